@@ -0,0 +1,85 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func newHealthCommand(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "health",
+		Short: "Check the gRPC health of each service",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			services := []struct {
+				name string
+				addr string
+			}{
+				{"user-service", opts.userAddr},
+				{"order-service", opts.orderAddr},
+			}
+
+			healthy := true
+			rows := make([][]string, 0, len(services))
+			result := make(map[string]interface{}, len(services))
+
+			for _, svc := range services {
+				status := checkHealth(opts, svc.addr)
+				if status != grpc_health_v1.HealthCheckResponse_SERVING.String() {
+					healthy = false
+				}
+				rows = append(rows, []string{svc.name, svc.addr, status})
+				result[svc.name] = status
+			}
+
+			msg, err := structpb.NewStruct(result)
+			if err != nil {
+				return err
+			}
+			if err := newPrinter(cmd.OutOrStdout(), opts.output).print(msg, []string{"SERVICE", "ADDRESS", "STATUS"}, rows); err != nil {
+				return err
+			}
+
+			if !healthy {
+				return fmt.Errorf("one or more services are unhealthy")
+			}
+			return nil
+		},
+	}
+}
+
+// checkHealth returns the serving status of addr, or the error text if the check failed
+func checkHealth(opts *options, addr string) string {
+	ctx, cancel := opts.context()
+	defer cancel()
+
+	conn, err := opts.dial(ctx, addr)
+	if err != nil {
+		return err.Error()
+	}
+	defer conn.Close()
+
+	resp, err := grpc_health_v1.NewHealthClient(conn).Check(ctx, &grpc_health_v1.HealthCheckRequest{})
+	if err != nil {
+		return err.Error()
+	}
+	return resp.GetStatus().String()
+}
@@ -0,0 +1,146 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/config"
+	"github.com/kevindiu/monorepo-go-example/internal/db"
+	"github.com/kevindiu/monorepo-go-example/internal/tenant"
+	"github.com/kevindiu/monorepo-go-example/pkg/inventory/repository"
+	"github.com/spf13/cobra"
+)
+
+var stockHeaders = []string{"PRODUCT_ID", "AVAILABLE", "RESERVED", "UPDATED_AT"}
+
+func stockRow(s *repository.Stock) []string {
+	return []string{
+		s.ProductID,
+		strconv.Itoa(s.Available),
+		strconv.Itoa(s.Reserved),
+		s.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+func newInventoryCommand(opts *options) *cobra.Command {
+	var tenantID string
+
+	cmd := &cobra.Command{
+		Use:   "inventory",
+		Short: "Manage product stock",
+		Long: "Manage the stock that orders reserve. Products without stock are not tracked.\n" +
+			"Database settings are read from the DATABASE_* environment variables.",
+	}
+
+	cmd.PersistentFlags().StringVar(&tenantID, "tenant", tenant.DefaultID, "tenant the stock belongs to")
+
+	cmd.AddCommand(
+		newInventorySetCommand(opts, &tenantID),
+		newInventoryListCommand(opts, &tenantID),
+	)
+
+	return cmd
+}
+
+// withInventoryRepo connects to the database holding the inventory
+func withInventoryRepo(opts *options, tenantID string, fn func(ctx context.Context, repo repository.Repository) error) error {
+	if err := tenant.Validate(tenantID); err != nil {
+		return err
+	}
+
+	ctx, cancel := opts.context()
+	defer cancel()
+	ctx = tenant.WithID(ctx, tenantID)
+
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+
+	database, err := db.Connect(cfg.Database)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	return fn(ctx, repository.New(database))
+}
+
+func newInventorySetCommand(opts *options, tenantID *string) *cobra.Command {
+	return &cobra.Command{
+		Use:   "set <product-id> <available>",
+		Short: "Set the available stock of a product",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			available, err := strconv.Atoi(args[1])
+			if err != nil || available < 0 {
+				return fmt.Errorf("available must be a non-negative number, got %q", args[1])
+			}
+
+			return withInventoryRepo(opts, *tenantID, func(ctx context.Context, repo repository.Repository) error {
+				stock, err := repo.Set(ctx, args[0], available)
+				if err != nil {
+					return err
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "Set stock of %s to %d available, %d reserved\n",
+					stock.ProductID, stock.Available, stock.Reserved)
+				return nil
+			})
+		},
+	}
+}
+
+func newInventoryListCommand(opts *options, tenantID *string) *cobra.Command {
+	var limit int
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the stock of tracked products",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withInventoryRepo(opts, *tenantID, func(ctx context.Context, repo repository.Repository) error {
+				stocks, err := repo.List(ctx, limit, 0)
+				if err != nil {
+					return err
+				}
+
+				if opts.output == outputJSON {
+					enc := json.NewEncoder(cmd.OutOrStdout())
+					enc.SetIndent("", "  ")
+					return enc.Encode(stocks)
+				}
+
+				tw := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+				fmt.Fprintln(tw, strings.Join(stockHeaders, "\t"))
+				for _, s := range stocks {
+					fmt.Fprintln(tw, strings.Join(stockRow(s), "\t"))
+				}
+				return tw.Flush()
+			})
+		},
+	}
+
+	cmd.Flags().IntVar(&limit, "limit", 50, "maximum number of products to list")
+
+	return cmd
+}
@@ -0,0 +1,139 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/config"
+	"github.com/kevindiu/monorepo-go-example/internal/db"
+	"github.com/kevindiu/monorepo-go-example/internal/jobs"
+	"github.com/spf13/cobra"
+)
+
+var jobHeaders = []string{"ID", "QUEUE", "TYPE", "ATTEMPTS", "FINISHED_AT", "LAST_ERROR"}
+
+func jobRow(j *jobs.Job) []string {
+	finished := ""
+	if j.FinishedAt != nil {
+		finished = j.FinishedAt.Format(time.RFC3339)
+	}
+	return []string{
+		j.ID,
+		j.Queue,
+		j.Type,
+		strconv.Itoa(j.Attempts),
+		finished,
+		j.LastError,
+	}
+}
+
+func newJobsCommand(opts *options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "jobs",
+		Short: "Inspect and retry dead-lettered background jobs",
+		Long: "Inspect and retry dead-lettered background jobs.\n" +
+			"Database settings are read from the DATABASE_* environment variables.",
+	}
+
+	cmd.AddCommand(
+		newJobsDeadCommand(opts),
+		newJobsRetryCommand(opts),
+	)
+
+	return cmd
+}
+
+// withJobStore connects to the database holding the job queue
+func withJobStore(opts *options, fn func(ctx context.Context, store jobs.Store) error) error {
+	ctx, cancel := opts.context()
+	defer cancel()
+
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+
+	database, err := db.Connect(cfg.Database)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	return fn(ctx, jobs.NewStore(database))
+}
+
+func newJobsDeadCommand(opts *options) *cobra.Command {
+	var queue string
+	var limit int
+
+	cmd := &cobra.Command{
+		Use:   "dead",
+		Short: "List dead-lettered jobs",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withJobStore(opts, func(ctx context.Context, store jobs.Store) error {
+				dead, err := store.ListDead(ctx, queue, limit)
+				if err != nil {
+					return err
+				}
+
+				if opts.output == outputJSON {
+					enc := json.NewEncoder(cmd.OutOrStdout())
+					enc.SetIndent("", "  ")
+					return enc.Encode(dead)
+				}
+
+				tw := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+				fmt.Fprintln(tw, strings.Join(jobHeaders, "\t"))
+				for _, j := range dead {
+					fmt.Fprintln(tw, strings.Join(jobRow(j), "\t"))
+				}
+				return tw.Flush()
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&queue, "queue", "", "only list jobs from this queue")
+	cmd.Flags().IntVar(&limit, "limit", 50, "maximum number of jobs to list")
+
+	return cmd
+}
+
+func newJobsRetryCommand(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "retry <id>...",
+		Short: "Requeue dead-lettered jobs with a fresh set of attempts",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withJobStore(opts, func(ctx context.Context, store jobs.Store) error {
+				for _, id := range args {
+					if err := store.Requeue(ctx, id); err != nil {
+						return fmt.Errorf("failed to requeue job %s: %w", id, err)
+					}
+					fmt.Fprintf(cmd.OutOrStdout(), "Requeued job %s\n", id)
+				}
+				return nil
+			})
+		},
+	}
+}
@@ -0,0 +1,204 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/certs"
+	"github.com/kevindiu/monorepo-go-example/internal/config"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+)
+
+// options holds the global flags shared by every subcommand
+type options struct {
+	configFile string
+	userAddr   string
+	orderAddr  string
+	token      string
+	output     string
+	timeout    time.Duration
+	// tls connects over TLS when the CA or a client certificate is set
+	tls config.TLS
+}
+
+func main() {
+	if err := newRootCommand().Execute(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func newRootCommand() *cobra.Command {
+	opts := &options{}
+	v := viper.New()
+
+	cmd := &cobra.Command{
+		Use:           "adminctl",
+		Short:         "Administrative CLI for the user and order services",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return loadOptions(cmd, v, opts)
+		},
+	}
+
+	flags := cmd.PersistentFlags()
+	flags.StringVar(&opts.configFile, "config", "", "config file (default $HOME/.adminctl.yaml)")
+	flags.String("user-addr", "localhost:9091", "user service gRPC address")
+	flags.String("order-addr", "localhost:9092", "order service gRPC address")
+	flags.String("token", "", "bearer token sent with every request")
+	flags.StringP("output", "o", "table", "output format (table|json)")
+	flags.Duration("timeout", 10*time.Second, "per-command timeout")
+	flags.String("tls-ca", "", "CA certificate to verify the services with; enables TLS")
+	flags.String("tls-cert", "", "client certificate for services that require mutual TLS")
+	flags.String("tls-key", "", "key of the client certificate")
+	flags.String("tls-server-name", "", "name to verify service certificates against (default the host of the address)")
+
+	cmd.AddCommand(
+		newUsersCommand(opts),
+		newOrdersCommand(opts),
+		newMigrateCommand(opts),
+		newHealthCommand(opts),
+		newJobsCommand(opts),
+		newPromotionsCommand(opts),
+		newInventoryCommand(opts),
+		newRetentionCommand(opts),
+		newTokenCommand(),
+	)
+
+	return cmd
+}
+
+// loadOptions resolves flag values with precedence flag > env > config file > default
+func loadOptions(cmd *cobra.Command, v *viper.Viper, opts *options) error {
+	if opts.configFile != "" {
+		v.SetConfigFile(opts.configFile)
+	} else {
+		home, err := os.UserHomeDir()
+		if err == nil {
+			v.AddConfigPath(home)
+		}
+		v.SetConfigName(".adminctl")
+		v.SetConfigType("yaml")
+	}
+
+	v.SetEnvPrefix("ADMINCTL")
+	v.SetEnvKeyReplacer(strings.NewReplacer("-", "_"))
+	v.AutomaticEnv()
+
+	if err := v.BindPFlags(cmd.Flags()); err != nil {
+		return fmt.Errorf("failed to bind flags: %w", err)
+	}
+
+	if err := v.ReadInConfig(); err != nil {
+		if _, ok := err.(viper.ConfigFileNotFoundError); !ok || opts.configFile != "" {
+			return fmt.Errorf("failed to read config file: %w", err)
+		}
+	}
+
+	opts.userAddr = v.GetString("user-addr")
+	opts.orderAddr = v.GetString("order-addr")
+	opts.token = v.GetString("token")
+	opts.output = v.GetString("output")
+	opts.timeout = v.GetDuration("timeout")
+	opts.tls = config.TLS{
+		CAFile:     v.GetString("tls-ca"),
+		CertFile:   v.GetString("tls-cert"),
+		KeyFile:    v.GetString("tls-key"),
+		ServerName: v.GetString("tls-server-name"),
+	}
+	if (opts.tls.CertFile == "") != (opts.tls.KeyFile == "") {
+		return fmt.Errorf("--tls-cert and --tls-key must be set together")
+	}
+
+	switch opts.output {
+	case outputTable, outputJSON:
+	default:
+		return fmt.Errorf("unsupported output format %q", opts.output)
+	}
+
+	return nil
+}
+
+// dial opens a client connection that attaches the bearer token to every call
+func (o *options) dial(ctx context.Context, addr string) (*grpc.ClientConn, error) {
+	creds, err := o.credentials()
+	if err != nil {
+		return nil, err
+	}
+	conn, err := grpc.DialContext(
+		ctx,
+		addr,
+		grpc.WithTransportCredentials(creds),
+		grpc.WithUnaryInterceptor(o.authInterceptor()),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", addr, err)
+	}
+	return conn, nil
+}
+
+// credentials returns the transport credentials of service connections:
+// plaintext unless a CA or a client certificate is configured
+func (o *options) credentials() (credentials.TransportCredentials, error) {
+	switch {
+	case o.tls.CertFile != "":
+		store, err := certs.New(&o.tls, nil)
+		if err != nil {
+			return nil, err
+		}
+		return credentials.NewTLS(store.ClientConfig()), nil
+	case o.tls.CAFile != "":
+		ca, err := os.ReadFile(o.tls.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read TLS CA: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf("no certificates found in %s", o.tls.CAFile)
+		}
+		return credentials.NewTLS(&tls.Config{MinVersion: tls.VersionTLS12, RootCAs: pool, ServerName: o.tls.ServerName}), nil
+	default:
+		return insecure.NewCredentials(), nil
+	}
+}
+
+func (o *options) authInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, callOpts ...grpc.CallOption) error {
+		if o.token != "" {
+			ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+o.token)
+		}
+		return invoker(ctx, method, req, reply, cc, callOpts...)
+	}
+}
+
+// context returns a context bounded by the configured timeout
+func (o *options) context() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), o.timeout)
+}
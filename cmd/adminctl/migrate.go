@@ -0,0 +1,72 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+
+	"github.com/kevindiu/monorepo-go-example/internal/config"
+	"github.com/kevindiu/monorepo-go-example/internal/db"
+	"github.com/kevindiu/monorepo-go-example/internal/db/migrations"
+	"github.com/spf13/cobra"
+)
+
+func newMigrateCommand(opts *options) *cobra.Command {
+	var dir string
+
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Apply pending database migrations",
+		Long: "Apply pending database migrations. The migrations built into adminctl are applied\n" +
+			"unless --dir names a directory of NNN_name.sql files.\n" +
+			"Database settings are read from the DATABASE_* environment variables.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			fsys := fs.FS(migrations.FS)
+			if dir != "" {
+				fsys = os.DirFS(dir)
+			}
+			pending, err := db.LoadMigrations(fsys)
+			if err != nil {
+				return err
+			}
+
+			cfg, err := config.Load()
+			if err != nil {
+				return err
+			}
+
+			database, err := db.Connect(cfg.Database)
+			if err != nil {
+				return err
+			}
+			defer database.Close()
+
+			if err := database.Migrate(pending); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Migrations up to date (%d known)\n", len(pending))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&dir, "dir", "", "directory containing migration files instead of the built-in ones")
+
+	return cmd
+}
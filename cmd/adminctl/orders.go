@@ -0,0 +1,202 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	orderv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/order/v1"
+	"github.com/kevindiu/monorepo-go-example/internal/money"
+	"github.com/spf13/cobra"
+)
+
+var orderHeaders = []string{"ID", "USER_ID", "STATUS", "TOTAL", "ITEMS", "CREATED_AT"}
+
+func orderRow(o *orderv1.Order) []string {
+	return []string{
+		o.GetId(),
+		o.GetUserId(),
+		strings.ToLower(strings.TrimPrefix(o.GetStatus().String(), "ORDER_STATUS_")),
+		money.Format(o.GetTotalAmountMinor(), o.GetCurrency()),
+		strconv.Itoa(len(o.GetItems())),
+		formatTime(o.GetCreatedAt()),
+	}
+}
+
+// parseStatus accepts either the short ("shipped") or full ("ORDER_STATUS_SHIPPED") status name
+func parseStatus(s string) (orderv1.OrderStatus, error) {
+	name := strings.ToUpper(s)
+	if !strings.HasPrefix(name, "ORDER_STATUS_") {
+		name = "ORDER_STATUS_" + name
+	}
+	v, ok := orderv1.OrderStatus_value[name]
+	if !ok || v == int32(orderv1.OrderStatus_ORDER_STATUS_UNSPECIFIED) {
+		return orderv1.OrderStatus_ORDER_STATUS_UNSPECIFIED, fmt.Errorf("unknown order status %q", s)
+	}
+	return orderv1.OrderStatus(v), nil
+}
+
+func newOrdersCommand(opts *options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "orders",
+		Short: "Inspect and manage orders",
+	}
+
+	cmd.AddCommand(
+		newOrdersGetCommand(opts),
+		newOrdersListCommand(opts),
+		newOrdersSetStatusCommand(opts),
+		newOrdersCancelCommand(opts),
+	)
+
+	return cmd
+}
+
+func withOrderClient(opts *options, fn func(ctx context.Context, client orderv1.OrderServiceClient) error) error {
+	ctx, cancel := opts.context()
+	defer cancel()
+
+	conn, err := opts.dial(ctx, opts.orderAddr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	return fn(ctx, orderv1.NewOrderServiceClient(conn))
+}
+
+func newOrdersGetCommand(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "get ID",
+		Short: "Show an order and its items",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withOrderClient(opts, func(ctx context.Context, client orderv1.OrderServiceClient) error {
+				resp, err := client.GetOrder(ctx, &orderv1.GetOrderRequest{Id: args[0]})
+				if err != nil {
+					return fmt.Errorf("failed to get order: %w", err)
+				}
+
+				rows := make([][]string, 0, len(resp.GetOrder().GetItems()))
+				for _, item := range resp.GetOrder().GetItems() {
+					rows = append(rows, []string{
+						item.GetId(),
+						item.GetProductId(),
+						strconv.Itoa(int(item.GetQuantity())),
+						money.Format(item.GetPriceMinor(), resp.GetOrder().GetCurrency()),
+					})
+				}
+
+				p := newPrinter(cmd.OutOrStdout(), opts.output)
+				if opts.output == outputJSON {
+					return p.print(resp, nil, nil)
+				}
+				if err := p.print(resp, orderHeaders, [][]string{orderRow(resp.GetOrder())}); err != nil {
+					return err
+				}
+				fmt.Fprintln(cmd.OutOrStdout())
+				return p.print(resp, []string{"ITEM_ID", "PRODUCT_ID", "QUANTITY", "PRICE"}, rows)
+			})
+		},
+	}
+}
+
+func newOrdersListCommand(opts *options) *cobra.Command {
+	var userID, pageToken string
+	var pageSize int32
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List orders",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withOrderClient(opts, func(ctx context.Context, client orderv1.OrderServiceClient) error {
+				resp, err := client.ListOrders(ctx, &orderv1.ListOrdersRequest{
+					UserId:    userID,
+					PageSize:  pageSize,
+					PageToken: pageToken,
+				})
+				if err != nil {
+					return fmt.Errorf("failed to list orders: %w", err)
+				}
+
+				rows := make([][]string, 0, len(resp.GetOrders()))
+				for _, o := range resp.GetOrders() {
+					rows = append(rows, orderRow(o))
+				}
+				if err := newPrinter(cmd.OutOrStdout(), opts.output).print(resp, orderHeaders, rows); err != nil {
+					return err
+				}
+				if opts.output == outputTable && resp.GetNextPageToken() != "" {
+					fmt.Fprintf(cmd.OutOrStdout(), "\nNext page token: %s\n", resp.GetNextPageToken())
+				}
+				return nil
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&userID, "user-id", "", "only list orders for this user")
+	cmd.Flags().Int32Var(&pageSize, "page-size", 20, "number of orders per page")
+	cmd.Flags().StringVar(&pageToken, "page-token", "", "page token from a previous list")
+
+	return cmd
+}
+
+func newOrdersSetStatusCommand(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "set-status ID STATUS",
+		Short: "Force an order into the given status",
+		Long:  "Force an order into the given status (pending, confirmed, shipped, delivered, cancelled).",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			status, err := parseStatus(args[1])
+			if err != nil {
+				return err
+			}
+
+			return withOrderClient(opts, func(ctx context.Context, client orderv1.OrderServiceClient) error {
+				resp, err := client.UpdateOrderStatus(ctx, &orderv1.UpdateOrderStatusRequest{Id: args[0], Status: status})
+				if err != nil {
+					return fmt.Errorf("failed to update order status: %w", err)
+				}
+				return newPrinter(cmd.OutOrStdout(), opts.output).print(resp, orderHeaders, [][]string{orderRow(resp.GetOrder())})
+			})
+		},
+	}
+}
+
+func newOrdersCancelCommand(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "cancel ID",
+		Short: "Cancel an order",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withOrderClient(opts, func(ctx context.Context, client orderv1.OrderServiceClient) error {
+				resp, err := client.CancelOrder(ctx, &orderv1.CancelOrderRequest{Id: args[0]})
+				if err != nil {
+					return fmt.Errorf("failed to cancel order: %w", err)
+				}
+				return newPrinter(cmd.OutOrStdout(), opts.output).print(resp,
+					[]string{"ID", "CANCELLED"},
+					[][]string{{args[0], strconv.FormatBool(resp.GetSuccess())}},
+				)
+			})
+		},
+	}
+}
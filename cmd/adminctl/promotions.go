@@ -0,0 +1,204 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/config"
+	"github.com/kevindiu/monorepo-go-example/internal/db"
+	"github.com/kevindiu/monorepo-go-example/internal/tenant"
+	"github.com/kevindiu/monorepo-go-example/pkg/promotion"
+	"github.com/kevindiu/monorepo-go-example/pkg/promotion/repository"
+	"github.com/spf13/cobra"
+)
+
+var promotionHeaders = []string{"CODE", "TYPE", "VALUE", "MIN_TOTAL", "PER_USER", "EXPIRES_AT", "ACTIVE"}
+
+func promotionRow(p *repository.Promotion) []string {
+	expires := ""
+	if p.ExpiresAt != nil {
+		expires = p.ExpiresAt.Format(time.RFC3339)
+	}
+	perUser := "unlimited"
+	if p.MaxUsesPerUser > 0 {
+		perUser = strconv.Itoa(p.MaxUsesPerUser)
+	}
+	return []string{
+		p.Code,
+		p.Type,
+		strconv.FormatFloat(p.Value, 'f', 2, 64),
+		strconv.FormatFloat(p.MinTotal, 'f', 2, 64),
+		perUser,
+		expires,
+		strconv.FormatBool(p.Active),
+	}
+}
+
+func newPromotionsCommand(opts *options) *cobra.Command {
+	var tenantID string
+
+	cmd := &cobra.Command{
+		Use:   "promotions",
+		Short: "Manage coupon code promotions",
+		Long: "Manage coupon code promotions.\n" +
+			"Database settings are read from the DATABASE_* environment variables.",
+	}
+
+	cmd.PersistentFlags().StringVar(&tenantID, "tenant", tenant.DefaultID, "tenant the promotions belong to")
+
+	cmd.AddCommand(
+		newPromotionsCreateCommand(opts, &tenantID),
+		newPromotionsListCommand(opts, &tenantID),
+		newPromotionsSetActiveCommand(opts, &tenantID, "enable", true),
+		newPromotionsSetActiveCommand(opts, &tenantID, "disable", false),
+	)
+
+	return cmd
+}
+
+// withPromotionRepo connects to the database holding the promotions
+func withPromotionRepo(opts *options, tenantID string, fn func(ctx context.Context, repo repository.Repository) error) error {
+	if err := tenant.Validate(tenantID); err != nil {
+		return err
+	}
+
+	ctx, cancel := opts.context()
+	defer cancel()
+	ctx = tenant.WithID(ctx, tenantID)
+
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+
+	database, err := db.Connect(cfg.Database)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	return fn(ctx, repository.New(database))
+}
+
+func newPromotionsCreateCommand(opts *options, tenantID *string) *cobra.Command {
+	var p repository.Promotion
+	var percent, amount float64
+	var expires time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "create <code>",
+		Short: "Create a coupon code",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			switch {
+			case percent > 0 && amount > 0:
+				return fmt.Errorf("--percent and --amount are mutually exclusive")
+			case percent > 0 && percent <= 100:
+				p.Type, p.Value = repository.TypePercent, percent
+			case amount > 0:
+				p.Type, p.Value = repository.TypeFixed, amount
+			default:
+				return fmt.Errorf("one of --percent (up to 100) or --amount is required")
+			}
+
+			p.Code = promotion.NormalizeCode(args[0])
+			p.Active = true
+			if expires > 0 {
+				at := time.Now().Add(expires)
+				p.ExpiresAt = &at
+			}
+
+			return withPromotionRepo(opts, *tenantID, func(ctx context.Context, repo repository.Repository) error {
+				if err := repo.Create(ctx, &p); err != nil {
+					return err
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "Created promotion %s\n", p.Code)
+				return nil
+			})
+		},
+	}
+
+	cmd.Flags().Float64Var(&percent, "percent", 0, "percentage off the order")
+	cmd.Flags().Float64Var(&amount, "amount", 0, "fixed amount off the order")
+	cmd.Flags().StringVar(&p.Description, "description", "", "description shown to operators")
+	cmd.Flags().Float64Var(&p.MinTotal, "min-total", 0, "minimum order total the code applies to")
+	cmd.Flags().IntVar(&p.MaxUsesPerUser, "per-user", 0, "maximum redemptions per user (0 for unlimited)")
+	cmd.Flags().DurationVar(&expires, "expires-in", 0, "expire the code after this long (0 never expires)")
+
+	return cmd
+}
+
+func newPromotionsListCommand(opts *options, tenantID *string) *cobra.Command {
+	var limit int
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List coupon codes",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withPromotionRepo(opts, *tenantID, func(ctx context.Context, repo repository.Repository) error {
+				promotions, err := repo.List(ctx, limit, 0)
+				if err != nil {
+					return err
+				}
+
+				if opts.output == outputJSON {
+					enc := json.NewEncoder(cmd.OutOrStdout())
+					enc.SetIndent("", "  ")
+					return enc.Encode(promotions)
+				}
+
+				tw := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+				fmt.Fprintln(tw, strings.Join(promotionHeaders, "\t"))
+				for _, p := range promotions {
+					fmt.Fprintln(tw, strings.Join(promotionRow(p), "\t"))
+				}
+				return tw.Flush()
+			})
+		},
+	}
+
+	cmd.Flags().IntVar(&limit, "limit", 50, "maximum number of promotions to list")
+
+	return cmd
+}
+
+func newPromotionsSetActiveCommand(opts *options, tenantID *string, verb string, active bool) *cobra.Command {
+	return &cobra.Command{
+		Use:   verb + " <code>...",
+		Short: strings.ToUpper(verb[:1]) + verb[1:] + " coupon codes",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withPromotionRepo(opts, *tenantID, func(ctx context.Context, repo repository.Repository) error {
+				for _, code := range args {
+					code = promotion.NormalizeCode(code)
+					if err := repo.SetActive(ctx, code, active); err != nil {
+						return fmt.Errorf("failed to %s promotion %s: %w", verb, code, err)
+					}
+					fmt.Fprintf(cmd.OutOrStdout(), "%sd promotion %s\n", strings.ToUpper(verb[:1])+verb[1:], code)
+				}
+				return nil
+			})
+		},
+	}
+}
@@ -0,0 +1,73 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/kevindiu/monorepo-go-example/internal/config"
+	"github.com/kevindiu/monorepo-go-example/internal/crypto"
+	"github.com/kevindiu/monorepo-go-example/internal/db"
+	"github.com/kevindiu/monorepo-go-example/pkg/user/repository"
+	"github.com/spf13/cobra"
+)
+
+func newUsersReencryptCommand(opts *options) *cobra.Command {
+	var batchSize int
+
+	cmd := &cobra.Command{
+		Use:   "reencrypt",
+		Short: "Encrypt user emails stored in plaintext or under a retired key",
+		Long: "Encrypt user emails stored in plaintext or under a retired key with the current key.\n" +
+			"Run it after enabling encryption or rotating encryption.key_id; retired keys can be removed from\n" +
+			"encryption.secret_keys once it finishes. Database and encryption settings are read from the\n" +
+			"DATABASE_* and ENCRYPTION_* environment variables; raise --timeout for large tables.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := opts.context()
+			defer cancel()
+
+			cfg, err := config.Load()
+			if err != nil {
+				return err
+			}
+			if !cfg.Encryption.Enabled {
+				return fmt.Errorf("encryption is not enabled")
+			}
+			enc, err := crypto.New(cfg.Encryption)
+			if err != nil {
+				return err
+			}
+
+			database, err := db.Connect(cfg.Database)
+			if err != nil {
+				return err
+			}
+			defer database.Close()
+
+			rewritten, err := repository.Reencrypt(ctx, database, enc, batchSize)
+			if err != nil {
+				return fmt.Errorf("failed to re-encrypt users: %w", err)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Re-encrypted %d users\n", rewritten)
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVar(&batchSize, "batch-size", 500, "users read per batch")
+
+	return cmd
+}
@@ -0,0 +1,89 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/kevindiu/monorepo-go-example/internal/config"
+	"github.com/kevindiu/monorepo-go-example/internal/db"
+	"github.com/kevindiu/monorepo-go-example/internal/log"
+	"github.com/kevindiu/monorepo-go-example/internal/retention"
+	"github.com/spf13/cobra"
+)
+
+func newRetentionCommand(opts *options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "retention",
+		Short: "Inspect data retention policies",
+		Long: "Inspect data retention policies.\n" +
+			"Database and retention settings are read from the DATABASE_* and RETENTION_* environment variables.",
+	}
+
+	cmd.AddCommand(newRetentionPreviewCommand(opts))
+
+	return cmd
+}
+
+func newRetentionPreviewCommand(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "preview",
+		Short: "List the rows the next retention run would purge or anonymize",
+		Long: "List the rows the next retention run would purge or anonymize, up to one batch per policy.\n" +
+			"Nothing is changed.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := opts.context()
+			defer cancel()
+
+			cfg, err := config.Load()
+			if err != nil {
+				return err
+			}
+
+			database, err := db.Connect(cfg.Database)
+			if err != nil {
+				return err
+			}
+			defer database.Close()
+
+			retentionCfg := *cfg.Retention
+			retentionCfg.DryRun = true
+			results, err := retention.New(&retentionCfg, database, nil, nil, log.NewDefault()).Run(ctx)
+			if err != nil {
+				return err
+			}
+
+			if opts.output == outputJSON {
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				return enc.Encode(results)
+			}
+
+			tw := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+			fmt.Fprintln(tw, strings.Join([]string{"POLICY", "TENANT", "ID"}, "\t"))
+			for _, result := range results {
+				for _, rec := range result.Records {
+					fmt.Fprintln(tw, strings.Join([]string{result.Policy, rec.TenantID, rec.ID}, "\t"))
+				}
+			}
+			return tw.Flush()
+		},
+	}
+}
@@ -0,0 +1,70 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/auth"
+	"github.com/kevindiu/monorepo-go-example/internal/config"
+	"github.com/spf13/cobra"
+)
+
+func newTokenCommand() *cobra.Command {
+	var (
+		tenantID string
+		roles    []string
+		ttl      time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "token SUBJECT",
+		Short: "Issue a bearer token for a user or service",
+		Long: "Issue a bearer token for SUBJECT, signed with auth.secret, and print it.\n" +
+			"Auth settings are read from the AUTH_* environment variables. A token issued for a tenant\n" +
+			"can only act on that tenant.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load()
+			if err != nil {
+				return err
+			}
+			authenticator, err := auth.New(cfg.Auth)
+			if err != nil {
+				return err
+			}
+
+			claims := auth.Claims{Subject: args[0], TenantID: tenantID, Roles: roles}
+			if ttl > 0 {
+				claims.ExpiresAt = time.Now().Add(ttl).Unix()
+			}
+			token, err := authenticator.Issue(claims)
+			if err != nil {
+				return fmt.Errorf("failed to issue token: %w", err)
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), token)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&tenantID, "tenant", "", "tenant the token is bound to")
+	cmd.Flags().StringSliceVar(&roles, "role", nil, "role granted by the token (repeatable)")
+	cmd.Flags().DurationVar(&ttl, "ttl", 0, "token lifetime (default auth.token_ttl)")
+
+	return cmd
+}
@@ -0,0 +1,197 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package main
+
+import (
+	"context"
+	"fmt"
+
+	userv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/user/v1"
+	"github.com/spf13/cobra"
+)
+
+var userHeaders = []string{"ID", "EMAIL", "NAME", "CREATED_AT"}
+
+func userRow(u *userv1.User) []string {
+	return []string{u.GetId(), u.GetEmail(), u.GetName(), formatTime(u.GetCreatedAt())}
+}
+
+func newUsersCommand(opts *options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "users",
+		Short: "Manage users",
+	}
+
+	cmd.AddCommand(
+		newUsersCreateCommand(opts),
+		newUsersGetCommand(opts),
+		newUsersListCommand(opts),
+		newUsersDeleteCommand(opts),
+		newUsersUndeleteCommand(opts),
+		newUsersPurgeCommand(opts),
+		newUsersReencryptCommand(opts),
+	)
+
+	return cmd
+}
+
+func withUserClient(opts *options, fn func(ctx context.Context, client userv1.UserServiceClient) error) error {
+	ctx, cancel := opts.context()
+	defer cancel()
+
+	conn, err := opts.dial(ctx, opts.userAddr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	return fn(ctx, userv1.NewUserServiceClient(conn))
+}
+
+func newUsersCreateCommand(opts *options) *cobra.Command {
+	var email, name string
+
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create a user",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withUserClient(opts, func(ctx context.Context, client userv1.UserServiceClient) error {
+				resp, err := client.CreateUser(ctx, &userv1.CreateUserRequest{Email: email, Name: name})
+				if err != nil {
+					return fmt.Errorf("failed to create user: %w", err)
+				}
+				return newPrinter(cmd.OutOrStdout(), opts.output).print(resp, userHeaders, [][]string{userRow(resp.GetUser())})
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&email, "email", "", "user email")
+	cmd.Flags().StringVar(&name, "name", "", "user name")
+	cmd.MarkFlagRequired("email")
+	cmd.MarkFlagRequired("name")
+
+	return cmd
+}
+
+func newUsersGetCommand(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "get ID",
+		Short: "Show a user",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withUserClient(opts, func(ctx context.Context, client userv1.UserServiceClient) error {
+				resp, err := client.GetUser(ctx, &userv1.GetUserRequest{Id: args[0]})
+				if err != nil {
+					return fmt.Errorf("failed to get user: %w", err)
+				}
+				return newPrinter(cmd.OutOrStdout(), opts.output).print(resp, userHeaders, [][]string{userRow(resp.GetUser())})
+			})
+		},
+	}
+}
+
+func newUsersListCommand(opts *options) *cobra.Command {
+	var pageSize int32
+	var pageToken string
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List users",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withUserClient(opts, func(ctx context.Context, client userv1.UserServiceClient) error {
+				resp, err := client.ListUsers(ctx, &userv1.ListUsersRequest{PageSize: pageSize, PageToken: pageToken})
+				if err != nil {
+					return fmt.Errorf("failed to list users: %w", err)
+				}
+
+				rows := make([][]string, 0, len(resp.GetUsers()))
+				for _, u := range resp.GetUsers() {
+					rows = append(rows, userRow(u))
+				}
+				if err := newPrinter(cmd.OutOrStdout(), opts.output).print(resp, userHeaders, rows); err != nil {
+					return err
+				}
+				if opts.output == outputTable && resp.GetNextPageToken() != "" {
+					fmt.Fprintf(cmd.OutOrStdout(), "\nNext page token: %s\n", resp.GetNextPageToken())
+				}
+				return nil
+			})
+		},
+	}
+
+	cmd.Flags().Int32Var(&pageSize, "page-size", 20, "number of users per page")
+	cmd.Flags().StringVar(&pageToken, "page-token", "", "page token from a previous list")
+
+	return cmd
+}
+
+func newUsersDeleteCommand(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "delete ID",
+		Short: "Delete a user",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withUserClient(opts, func(ctx context.Context, client userv1.UserServiceClient) error {
+				resp, err := client.DeleteUser(ctx, &userv1.DeleteUserRequest{Id: args[0]})
+				if err != nil {
+					return fmt.Errorf("failed to delete user: %w", err)
+				}
+				return newPrinter(cmd.OutOrStdout(), opts.output).print(resp,
+					[]string{"ID", "DELETED"},
+					[][]string{{args[0], fmt.Sprintf("%t", resp.GetSuccess())}},
+				)
+			})
+		},
+	}
+}
+
+func newUsersUndeleteCommand(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "undelete ID",
+		Short: "Restore a deleted user",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withUserClient(opts, func(ctx context.Context, client userv1.UserServiceClient) error {
+				resp, err := client.UndeleteUser(ctx, &userv1.UndeleteUserRequest{Id: args[0]})
+				if err != nil {
+					return fmt.Errorf("failed to undelete user: %w", err)
+				}
+				return newPrinter(cmd.OutOrStdout(), opts.output).print(resp, userHeaders, [][]string{userRow(resp.GetUser())})
+			})
+		},
+	}
+}
+
+func newUsersPurgeCommand(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "purge ID",
+		Short: "Permanently remove a deleted user",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withUserClient(opts, func(ctx context.Context, client userv1.UserServiceClient) error {
+				resp, err := client.PurgeUser(ctx, &userv1.PurgeUserRequest{Id: args[0]})
+				if err != nil {
+					return fmt.Errorf("failed to purge user: %w", err)
+				}
+				return newPrinter(cmd.OutOrStdout(), opts.output).print(resp,
+					[]string{"ID", "PURGED"},
+					[][]string{{args[0], fmt.Sprintf("%t", resp.GetSuccess())}},
+				)
+			})
+		},
+	}
+}
@@ -18,25 +18,47 @@ package main
 
 import (
 	"context"
+	"database/sql"
+	"flag"
 	"fmt"
 	"net/http"
 	"os"
-	"os/signal"
-	"syscall"
-	"time"
 
+	"github.com/kevindiu/monorepo-go-example/internal/apikey"
+	"github.com/kevindiu/monorepo-go-example/internal/auth"
+	"github.com/kevindiu/monorepo-go-example/internal/certs"
 	"github.com/kevindiu/monorepo-go-example/internal/config"
+	"github.com/kevindiu/monorepo-go-example/internal/db"
+	"github.com/kevindiu/monorepo-go-example/internal/diagnostics"
+	"github.com/kevindiu/monorepo-go-example/internal/doctor"
+	"github.com/kevindiu/monorepo-go-example/internal/health"
 	"github.com/kevindiu/monorepo-go-example/internal/log"
+	"github.com/kevindiu/monorepo-go-example/internal/metrics"
+	"github.com/kevindiu/monorepo-go-example/internal/ratelimit"
+	"github.com/kevindiu/monorepo-go-example/internal/reporting"
+	"github.com/kevindiu/monorepo-go-example/internal/session"
+	"github.com/kevindiu/monorepo-go-example/internal/storage"
+	"github.com/kevindiu/monorepo-go-example/internal/tenant"
+	"github.com/kevindiu/monorepo-go-example/internal/tracing"
 	"github.com/kevindiu/monorepo-go-example/pkg/gateway"
+	"github.com/kevindiu/monorepo-go-example/pkg/server"
 )
 
 func main() {
+	configFile := config.Flag()
+	check := doctor.Flag()
+	migrate := config.MigrateFlag()
+	flag.Parse()
+
 	// Load configuration
-	cfg, err := config.Load()
+	cfg, err := config.Open(*configFile)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to load configuration: %v\n", err)
 		os.Exit(1)
 	}
+	if *migrate {
+		cfg.Database.MigrateOnStart = true
+	}
 
 	// Initialize logger
 	logCfg := &log.Config{
@@ -49,6 +71,36 @@ func main() {
 		os.Exit(1)
 	}
 	defer logger.Sync()
+	// Code without a request-scoped logger logs with this one
+	log.SetDefault(logger)
+
+	// Export traces when enabled
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Observability, "gateway")
+	if err != nil {
+		logger.Fatal("Failed to set up tracing", log.Error(err))
+	}
+	defer shutdownTracing(context.Background())
+
+	// Report internal errors and panics when configured
+	flushReports, err := reporting.Setup(cfg.Observability, "gateway")
+	if err != nil {
+		logger.Fatal("Failed to set up error reporting", log.Error(err))
+	}
+	defer flushReports()
+
+	// Authenticate API calls by bearer token when enabled
+	var authenticator *auth.Authenticator
+	if cfg.Auth.Enabled {
+		if authenticator, err = auth.New(cfg.Auth); err != nil {
+			logger.Fatal("Failed to set up authentication", log.Error(err))
+		}
+	}
+
+	// Secure gRPC connections with TLS when enabled
+	tlsStore, err := certs.FromConfig(cfg.TLS, logger)
+	if err != nil {
+		logger.Fatal("Failed to load TLS certificates", log.Error(err))
+	}
 
 	logger.Info("Starting gateway service",
 		log.String("version", "1.0.0"),
@@ -66,59 +118,183 @@ func main() {
 		orderServiceEndpoint = "localhost:9092"
 	}
 
+	// The webhook, search, audit, shipping and product services are optional
+	webhookServiceEndpoint := os.Getenv("WEBHOOK_SERVICE_ENDPOINT")
+	searchServiceEndpoint := os.Getenv("SEARCH_SERVICE_ENDPOINT")
+	auditServiceEndpoint := os.Getenv("AUDIT_SERVICE_ENDPOINT")
+	shippingServiceEndpoint := os.Getenv("SHIPPING_SERVICE_ENDPOINT")
+	productServiceEndpoint := os.Getenv("PRODUCT_SERVICE_ENDPOINT")
+
+	// Validate configuration and dependencies and exit in --check mode
+	if *check {
+		checks := []health.Checker{
+			doctor.Config(cfg),
+			doctor.Endpoint("user-service", userServiceEndpoint, true),
+			doctor.Endpoint("order-service", orderServiceEndpoint, true),
+		}
+		// The optional backends only warn
+		for name, endpoint := range map[string]string{
+			"webhook-service":  webhookServiceEndpoint,
+			"search-service":   searchServiceEndpoint,
+			"audit-service":    auditServiceEndpoint,
+			"shipping-service": shippingServiceEndpoint,
+			"product-service":  productServiceEndpoint,
+		} {
+			if endpoint != "" {
+				checks = append(checks, doctor.Endpoint(name, endpoint, false))
+			}
+		}
+		if cfg.Session.Enabled || cfg.APIKeys.Enabled {
+			checks = append(checks, doctor.Database(cfg.Database)...)
+		}
+		os.Exit(doctor.Run(context.Background(), os.Stdout, "gateway", checks))
+	}
+
+	// Probe the running server and exit in healthcheck mode
+	if flag.Arg(0) == doctor.HealthcheckCommand {
+		os.Exit(doctor.Healthcheck(os.Stdout, doctor.HTTPProbe(cfg.Server)))
+	}
+
 	logger.Info("Backend service endpoints",
 		log.String("user_service", userServiceEndpoint),
 		log.String("order_service", orderServiceEndpoint),
+		log.String("webhook_service", webhookServiceEndpoint),
+		log.String("search_service", searchServiceEndpoint),
+		log.String("audit_service", auditServiceEndpoint),
+		log.String("shipping_service", shippingServiceEndpoint),
+		log.String("product_service", productServiceEndpoint),
 	)
 
-	// Create gateway
-	gw, err := gateway.New(gateway.Config{
-		UserServiceEndpoint:  userServiceEndpoint,
-		OrderServiceEndpoint: orderServiceEndpoint,
-		Logger:               logger,
+	// Throttle decisions are served with the request metrics
+	m := metrics.New(cfg.Observability)
+
+	// Start components in dependency order. A component that fails to
+	// start stops those already started.
+	var (
+		files     http.Handler
+		sessions  *session.Manager
+		apiKeys   *apikey.Validator
+		limiter   *ratelimit.Limiter
+		checks    []health.Checker
+		sessionDB *sql.DB
+		gw        *gateway.Gateway
+	)
+	gatewayDeps := []string{"files"}
+	lc := server.NewLifecycle(logger)
+	// Serve signed storage URLs when objects live on the local filesystem
+	lc.Add(server.Component{
+		Name: "files",
+		Start: func(context.Context) error {
+			if cfg.Storage.Driver != "local" {
+				return nil
+			}
+			local, err := storage.NewLocal(cfg.Storage.LocalDir, cfg.Storage.BaseURL, cfg.Storage.SigningSecret)
+			if err != nil {
+				return err
+			}
+			files = local.Handler()
+			return nil
+		},
 	})
-	if err != nil {
-		logger.Fatal("Failed to create gateway", log.Error(err))
+	// Cookie sessions are kept in Postgres, cached in Redis when configured,
+	// and API keys in Postgres
+	if cfg.Session.Enabled || cfg.APIKeys.Enabled {
+		var database *db.DB
+		lc.Add(server.Component{
+			Name: "database",
+			Start: func(context.Context) (err error) {
+				database, err = db.ConnectAndMigrate(cfg.Database)
+				if err != nil {
+					return err
+				}
+				if cfg.Session.Enabled {
+					sessions = session.New(cfg.Session, database, logger)
+				}
+				if cfg.APIKeys.Enabled {
+					apiKeys = apikey.NewValidator(apikey.NewPostgresStore(database), cfg.APIKeys.CacheTTL)
+				}
+				checks = append(checks, health.Checker{Name: "database", Check: database.PingContext, Critical: true})
+				sessionDB = database.DB
+				return nil
+			},
+			Stop: func(context.Context) error { return database.Close() },
+		})
+		gatewayDeps = append(gatewayDeps, "database")
 	}
+	// Tenant and client IP quotas are shared through Redis when configured
+	if cfg.RateLimit.Enabled {
+		lc.Add(server.Component{
+			Name: "rate-limit",
+			Start: func(context.Context) (err error) {
+				limiter, err = ratelimit.New(cfg.RateLimit, m.Registry, logger)
+				return err
+			},
+			Stop: func(context.Context) error { return limiter.Close() },
+		})
+		gatewayDeps = append(gatewayDeps, "rate-limit")
+	}
+	lc.Add(server.Component{
+		Name:      "gateway",
+		DependsOn: gatewayDeps,
+		Start: func(ctx context.Context) (err error) {
+			gw, err = gateway.New(gateway.Config{
+				UserServiceEndpoint:     userServiceEndpoint,
+				OrderServiceEndpoint:    orderServiceEndpoint,
+				WebhookServiceEndpoint:  webhookServiceEndpoint,
+				SearchServiceEndpoint:   searchServiceEndpoint,
+				AuditServiceEndpoint:    auditServiceEndpoint,
+				ShippingServiceEndpoint: shippingServiceEndpoint,
+				ProductServiceEndpoint:  productServiceEndpoint,
+				Tenants:                 tenant.NewResolver(cfg.Tenancy),
+				Files:                   files,
+				Sessions:                sessions,
+				APIKeys:                 apiKeys,
+				Auth:                    authenticator,
+				GraphQLPlayground:       cfg.Server.Mode == "development",
+				BFF:                     cfg.BFF,
+				AccessLog:               &cfg.Log.Access,
+				CORS:                    cfg.CORS,
+				Keepalive:               cfg.Server.Keepalive,
+				Backends:                cfg.Gateway,
+				TLS:                     tlsStore,
+				Deprecation:             cfg.Deprecation,
+				RateLimiter:             limiter,
+				ClientIPLimiter:         ratelimit.ClientIP(limiter, cfg.RateLimit),
+				TrustForwardedFor:       cfg.RateLimit.TrustForwardedFor,
+				APIKeyLimiter:           ratelimit.APIKey(limiter, cfg.RateLimit),
+				Checks:                  checks,
+				Logger:                  logger,
+			})
+			if err != nil {
+				return err
+			}
+			// Initialize the backend connections
+			return gw.Start(ctx)
+		},
+		Stop: func(context.Context) error { return gw.Close() },
+	})
 
-	// Start gateway (initialize connections)
 	ctx := context.Background()
-	if err := gw.Start(ctx); err != nil {
-		logger.Fatal("Failed to start gateway", log.Error(err))
+	if err := lc.Start(ctx); err != nil {
+		logger.Fatal("Failed to start components", log.Error(err))
 	}
 
-	// Create HTTP server
-	httpAddr := fmt.Sprintf("%s:%d", cfg.Server.Host, cfg.Server.Port)
-	httpServer := &http.Server{
-		Addr:         httpAddr,
-		Handler:      gw.Handler(),
-		ReadTimeout:  15 * time.Second,
-		WriteTimeout: 15 * time.Second,
-		IdleTimeout:  60 * time.Second,
+	opts := []server.Option{
+		server.WithLifecycle(lc),
+		server.WithInstrumentation(m),
 	}
-
-	// Start HTTP server
-	go func() {
-		logger.Info("Starting HTTP server", log.String("address", httpAddr))
-		if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			logger.Fatal("Failed to serve HTTP", log.Error(err))
-		}
-	}()
-
-	// Wait for interrupt signal
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
-
-	logger.Info("Shutting down server...")
-
-	// Graceful shutdown
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
-
-	if err := httpServer.Shutdown(ctx); err != nil {
-		logger.Error("HTTP server forced to shutdown", log.Error(err))
+	opts = append(opts,
+		server.WithHTTPHandler(gw.Handler()),
+		server.WithAuth(authenticator),
+		server.WithTLS(tlsStore),
+		server.WithRuntimeStats(cfg.Observability.RuntimeStatsInterval, sessionDB),
+		server.WithChannelz(cfg.Observability.ChannelzEnabled),
+		server.WithLogLevelEndpoint(cfg.Log.LevelEndpoint),
+		server.WithConfigReload(*configFile, cfg, gw.Reload),
+		server.WithDiagnostics(cfg, diagnostics.Conns(gw.Conns())),
+	)
+	srv := server.New("gateway", cfg.Server, logger, opts...)
+	if err := srv.Run(ctx); err != nil {
+		logger.Error("Server failed", log.Error(err))
 	}
-
-	logger.Info("Server stopped")
 }
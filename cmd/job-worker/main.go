@@ -0,0 +1,203 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+
+	"github.com/kevindiu/monorepo-go-example/internal/config"
+	"github.com/kevindiu/monorepo-go-example/internal/crypto"
+	"github.com/kevindiu/monorepo-go-example/internal/db"
+	"github.com/kevindiu/monorepo-go-example/internal/doctor"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/internal/health"
+	"github.com/kevindiu/monorepo-go-example/internal/jobs"
+	"github.com/kevindiu/monorepo-go-example/internal/log"
+	"github.com/kevindiu/monorepo-go-example/internal/mail"
+	"github.com/kevindiu/monorepo-go-example/internal/metrics"
+	"github.com/kevindiu/monorepo-go-example/internal/reporting"
+	"github.com/kevindiu/monorepo-go-example/internal/storage"
+	"github.com/kevindiu/monorepo-go-example/internal/tracing"
+	"github.com/kevindiu/monorepo-go-example/pkg/server"
+	userjobs "github.com/kevindiu/monorepo-go-example/pkg/user/jobs"
+	"github.com/kevindiu/monorepo-go-example/pkg/user/repository"
+)
+
+func main() {
+	configFile := config.Flag()
+	check := doctor.Flag()
+	migrate := config.MigrateFlag()
+	flag.Parse()
+
+	// Load configuration
+	cfg, err := config.Open(*configFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load configuration: %v\n", err)
+		os.Exit(1)
+	}
+	if *migrate {
+		cfg.Database.MigrateOnStart = true
+	}
+
+	// Validate configuration and dependencies and exit in --check mode
+	if *check {
+		checks := []health.Checker{doctor.Config(cfg)}
+		checks = append(checks, doctor.Database(cfg.Database)...)
+		if cfg.Mail.Host != "" {
+			checks = append(checks, doctor.Endpoint("smtp", net.JoinHostPort(cfg.Mail.Host, strconv.Itoa(cfg.Mail.Port)), false))
+		}
+		os.Exit(doctor.Run(context.Background(), os.Stdout, "job-worker", checks))
+	}
+
+	// Probe the running server and exit in healthcheck mode
+	if flag.Arg(0) == doctor.HealthcheckCommand {
+		os.Exit(doctor.Healthcheck(os.Stdout, doctor.HTTPProbe(cfg.Server)))
+	}
+
+	// Initialize logger
+	logCfg := &log.Config{
+		Level:  cfg.Log.Level,
+		Format: cfg.Log.Format,
+	}
+	logger, err := log.New(logCfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to initialize logger: %v\n", err)
+		os.Exit(1)
+	}
+	defer logger.Sync()
+	// Code without a request-scoped logger logs with this one
+	log.SetDefault(logger)
+
+	// Export traces when enabled
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Observability, "job-worker")
+	if err != nil {
+		logger.Fatal("Failed to set up tracing", log.Error(err))
+	}
+	defer shutdownTracing(context.Background())
+
+	// Report internal errors and panics when configured
+	flushReports, err := reporting.Setup(cfg.Observability, "job-worker")
+	if err != nil {
+		logger.Fatal("Failed to set up error reporting", log.Error(err))
+	}
+	defer flushReports()
+
+	logger.Info("Starting job worker",
+		log.String("version", "1.0.0"),
+		log.Any("queues", cfg.Jobs.Queues),
+		log.Int("http_port", cfg.Server.Port),
+	)
+
+	// Pool metrics are served with the request metrics
+	m := metrics.New(cfg.Observability)
+
+	// Encrypt personal data at rest when enabled
+	var userRepoOpts []repository.Option
+	if cfg.Encryption.Enabled {
+		enc, err := crypto.New(cfg.Encryption)
+		if err != nil {
+			logger.Fatal("Failed to set up encryption", log.Error(err))
+		}
+		userRepoOpts = append(userRepoOpts, repository.WithEncryptor(enc))
+	}
+
+	// Start components in dependency order. A component that fails to
+	// start stops those already started.
+	var (
+		database *db.DB
+		store    storage.Storage
+		pool     *jobs.Pool
+	)
+	lc := server.NewLifecycle(logger)
+	lc.Add(
+		server.Component{
+			Name: "database",
+			Start: func(context.Context) (err error) {
+				database, err = db.ConnectAndMigrate(cfg.Database)
+				return err
+			},
+			Stop: func(context.Context) error { return database.Close() },
+		},
+		server.Component{
+			Name: "storage",
+			Start: func(context.Context) (err error) {
+				store, err = storage.New(cfg.Storage)
+				return err
+			},
+		},
+		server.Component{
+			Name:      "jobs",
+			DependsOn: []string{"database", "storage"},
+			Start: func(context.Context) (err error) {
+				pool, err = jobs.NewPool(jobs.Config{
+					Store:          jobs.NewStore(database),
+					Queues:         cfg.Jobs.Queues,
+					Concurrency:    cfg.Jobs.Concurrency,
+					PollInterval:   cfg.Jobs.PollInterval,
+					Lease:          cfg.Jobs.Lease,
+					InitialBackoff: cfg.Jobs.InitialBackoff,
+					MaxBackoff:     cfg.Jobs.MaxBackoff,
+					Logger:         logger,
+					Registerer:     m.Registry,
+				})
+				if err != nil {
+					return err
+				}
+
+				// Register job handlers
+				handlers := map[string]jobs.Handler{
+					mail.JobType:         mail.NewJobHandler(mail.NewSMTPSender(cfg.Mail)),
+					userjobs.ExportUsers: userjobs.NewExportHandler(repository.NewUserRepository(database, userRepoOpts...), store, database),
+				}
+				for jobType, handler := range handlers {
+					if err := pool.Register(jobType, handler); err != nil {
+						return errors.Wrapf(err, "failed to register %s handler", jobType)
+					}
+				}
+				return nil
+			},
+		},
+	)
+	if err := lc.Start(context.Background()); err != nil {
+		logger.Fatal("Failed to start components", log.Error(err))
+	}
+
+	// Serve metrics and health checks while the workers run
+	srv := server.New("job-worker", cfg.Server, logger,
+		server.WithLifecycle(lc),
+		server.WithHealth(),
+		server.WithHealthCheck(
+			health.Checker{Name: "database", Check: database.PingContext, Critical: true},
+			health.Checker{Name: "migrations", Check: health.Migrations(database, db.SchemaVersion), Critical: true},
+		),
+		server.WithInstrumentation(m),
+		server.WithRuntimeStats(cfg.Observability.RuntimeStatsInterval, database.DB),
+		server.WithChannelz(cfg.Observability.ChannelzEnabled),
+		server.WithLogLevelEndpoint(cfg.Log.LevelEndpoint),
+		server.WithConfigReload(*configFile, cfg),
+		server.WithDiagnostics(cfg),
+		server.WithWorker("jobs", pool.Run),
+	)
+	if err := srv.Run(context.Background()); err != nil {
+		logger.Error("Server failed", log.Error(err))
+	}
+}
@@ -0,0 +1,106 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/kevindiu/monorepo-go-example/internal/db"
+	"github.com/spf13/cobra"
+)
+
+// defaultDir is where create writes migrations when --dir is not set
+const defaultDir = "internal/db/migrations"
+
+// invalidName matches the runs of characters a migration name cannot hold
+var invalidName = regexp.MustCompile(`[^a-z0-9]+`)
+
+func newCreateCommand(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "create NAME",
+		Short: "Create empty up and down migration files with the next version",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := strings.Trim(invalidName.ReplaceAllString(strings.ToLower(args[0]), "_"), "_")
+			if name == "" {
+				return fmt.Errorf("invalid migration name %q", args[0])
+			}
+
+			dir := opts.dir
+			if dir == "" {
+				dir = defaultDir
+			}
+			existing, err := db.LoadMigrations(os.DirFS(dir))
+			if err != nil {
+				return err
+			}
+			version := 1
+			if len(existing) > 0 {
+				version = existing[len(existing)-1].Version + 1
+			}
+
+			title := strings.ReplaceAll(name, "_", " ")
+			title = strings.ToUpper(title[:1]) + title[1:]
+			files := []struct {
+				down   bool
+				header string
+			}{
+				{down: false, header: "-- Migration: " + title},
+				{down: true, header: "-- Migration: Undo: " + title},
+			}
+			for _, f := range files {
+				path := filepath.Join(dir, fileName(version, name, f.down))
+				content := fmt.Sprintf("%s\n-- Version: %s\n\n", f.header, versionLabel(version))
+				// O_EXCL keeps a concurrent create from overwriting a file
+				file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+				if err != nil {
+					return fmt.Errorf("failed to create migration: %w", err)
+				}
+				_, err = file.WriteString(content)
+				if cerr := file.Close(); err == nil {
+					err = cerr
+				}
+				if err != nil {
+					return fmt.Errorf("failed to write %s: %w", path, err)
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "Created %s\n", path)
+			}
+
+			if dir == defaultDir {
+				fmt.Fprintf(cmd.OutOrStdout(), "Set db.SchemaVersion to %d\n", version)
+			}
+			return nil
+		},
+	}
+}
+
+// versionLabel formats a version the way migration files are numbered
+func versionLabel(version int) string {
+	return fmt.Sprintf("%03d", version)
+}
+
+// fileName returns the file name of a migration, or of its down migration
+func fileName(version int, name string, down bool) string {
+	if down {
+		return versionLabel(version) + "_" + name + ".down.sql"
+	}
+	return versionLabel(version) + "_" + name + ".sql"
+}
@@ -0,0 +1,218 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Command migrate applies and rolls back database schema migrations,
+// separately from service startup
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/config"
+	"github.com/kevindiu/monorepo-go-example/internal/db"
+	"github.com/kevindiu/monorepo-go-example/internal/db/migrations"
+	"github.com/spf13/cobra"
+)
+
+// options holds the global flags shared by every subcommand
+type options struct {
+	configFile string
+	dir        string
+}
+
+func main() {
+	if err := newRootCommand().Execute(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func newRootCommand() *cobra.Command {
+	opts := &options{}
+
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Apply and roll back database schema migrations",
+		Long: "Apply and roll back database schema migrations. The migrations built into this binary are\n" +
+			"used unless --dir names a directory of NNN_name.sql and NNN_name.down.sql files. Database\n" +
+			"settings are read from --config and the DATABASE_* environment variables.",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+
+	flags := cmd.PersistentFlags()
+	flags.StringVar(&opts.configFile, "config", "", "YAML configuration file; environment variables override it")
+	flags.StringVar(&opts.dir, "dir", "", "directory containing migration files instead of the built-in ones")
+
+	cmd.AddCommand(
+		newUpCommand(opts),
+		newDownCommand(opts),
+		newStatusCommand(opts),
+		newForceCommand(opts),
+		newCreateCommand(opts),
+	)
+
+	return cmd
+}
+
+func newUpCommand(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "up",
+		Short: "Apply pending migrations",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			known, database, err := opts.open()
+			if err != nil {
+				return err
+			}
+			defer database.Close()
+
+			if err := database.Migrate(known); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Migrations up to date (%d known)\n", len(known))
+			return nil
+		},
+	}
+}
+
+func newDownCommand(opts *options) *cobra.Command {
+	var all bool
+
+	cmd := &cobra.Command{
+		Use:   "down [N]",
+		Short: "Roll back the latest N applied migrations (default 1)",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			steps := 1
+			if len(args) == 1 {
+				n, err := strconv.Atoi(args[0])
+				if err != nil || n < 1 {
+					return fmt.Errorf("invalid number of migrations %q", args[0])
+				}
+				steps = n
+			}
+
+			known, database, err := opts.open()
+			if err != nil {
+				return err
+			}
+			defer database.Close()
+
+			if all {
+				steps = len(known)
+			}
+			return database.MigrateDown(known, steps)
+		},
+	}
+
+	cmd.Flags().BoolVar(&all, "all", false, "roll back every applied migration")
+
+	return cmd
+}
+
+func newStatusCommand(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "status",
+		Short: "Show which migrations are applied",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			known, database, err := opts.open()
+			if err != nil {
+				return err
+			}
+			defer database.Close()
+
+			statuses, err := database.MigrationStatuses(cmd.Context(), known)
+			if err != nil {
+				return err
+			}
+
+			tw := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+			fmt.Fprintln(tw, "VERSION\tNAME\tSTATE\tAPPLIED AT")
+			for _, s := range statuses {
+				state, appliedAt := "pending", ""
+				switch {
+				case s.Dirty:
+					state = "dirty"
+				case s.Applied:
+					state = "applied"
+					appliedAt = s.AppliedAt.Format(time.RFC3339)
+				}
+				if s.Unknown {
+					state += " (unknown)"
+				}
+				fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", versionLabel(s.Version), s.Name, state, appliedAt)
+			}
+			return tw.Flush()
+		},
+	}
+}
+
+func newForceCommand(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "force VERSION",
+		Short: "Clear the dirty state after repairing the schema by hand",
+		Long: "Clear the dirty state after repairing the schema by hand. VERSION is the migration the\n" +
+			"schema is now at: the dirty migration if it completed, or the one before it if it did not.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			version, err := strconv.Atoi(args[0])
+			if err != nil {
+				return fmt.Errorf("invalid version %q", args[0])
+			}
+
+			_, database, err := opts.open()
+			if err != nil {
+				return err
+			}
+			defer database.Close()
+
+			if err := database.ForceMigration(cmd.Context(), version); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Schema recorded at migration %d\n", version)
+			return nil
+		},
+	}
+}
+
+// open loads the migrations and connects to the database
+func (o *options) open() ([]db.Migration, *db.DB, error) {
+	fsys := fs.FS(migrations.FS)
+	if o.dir != "" {
+		fsys = os.DirFS(o.dir)
+	}
+	known, err := db.LoadMigrations(fsys)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cfg, err := config.Open(o.configFile)
+	if err != nil {
+		return nil, nil, err
+	}
+	database, err := db.Connect(cfg.Database)
+	if err != nil {
+		return nil, nil, err
+	}
+	return known, database, nil
+}
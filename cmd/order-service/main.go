@@ -18,34 +18,86 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
-	"net"
-	"net/http"
 	"os"
-	"os/signal"
-	"syscall"
-	"time"
 
-	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	orderv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/order/v1"
+	orderv2 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/order/v2"
+	recommendationv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/recommendation/v1"
+	"github.com/kevindiu/monorepo-go-example/internal/audit"
+	"github.com/kevindiu/monorepo-go-example/internal/auth"
+	"github.com/kevindiu/monorepo-go-example/internal/breaker"
+	"github.com/kevindiu/monorepo-go-example/internal/cache"
+	"github.com/kevindiu/monorepo-go-example/internal/certs"
 	"github.com/kevindiu/monorepo-go-example/internal/config"
+	"github.com/kevindiu/monorepo-go-example/internal/currency"
 	"github.com/kevindiu/monorepo-go-example/internal/db"
+	"github.com/kevindiu/monorepo-go-example/internal/doctor"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/internal/events"
+	"github.com/kevindiu/monorepo-go-example/internal/health"
+	"github.com/kevindiu/monorepo-go-example/internal/idempotency"
+	"github.com/kevindiu/monorepo-go-example/internal/leader"
 	"github.com/kevindiu/monorepo-go-example/internal/log"
-	"github.com/kevindiu/monorepo-go-example/internal/middleware"
+	"github.com/kevindiu/monorepo-go-example/internal/metrics"
+	"github.com/kevindiu/monorepo-go-example/internal/pagination"
+	"github.com/kevindiu/monorepo-go-example/internal/ratelimit"
+	"github.com/kevindiu/monorepo-go-example/internal/reporting"
+	"github.com/kevindiu/monorepo-go-example/internal/retention"
+	"github.com/kevindiu/monorepo-go-example/internal/scheduler"
+	"github.com/kevindiu/monorepo-go-example/internal/tenant"
+	"github.com/kevindiu/monorepo-go-example/internal/tracing"
+	"github.com/kevindiu/monorepo-go-example/pkg/client"
+	"github.com/kevindiu/monorepo-go-example/pkg/order/jobs"
 	"github.com/kevindiu/monorepo-go-example/pkg/order/repository"
 	"github.com/kevindiu/monorepo-go-example/pkg/order/service"
+	"github.com/kevindiu/monorepo-go-example/pkg/order/watch"
+	"github.com/kevindiu/monorepo-go-example/pkg/promotion"
+	promotionrepo "github.com/kevindiu/monorepo-go-example/pkg/promotion/repository"
+	recommendationjobs "github.com/kevindiu/monorepo-go-example/pkg/recommendation/jobs"
+	recommendationrepo "github.com/kevindiu/monorepo-go-example/pkg/recommendation/repository"
+	recommendationservice "github.com/kevindiu/monorepo-go-example/pkg/recommendation/service"
+	"github.com/kevindiu/monorepo-go-example/pkg/server"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
-	"google.golang.org/grpc/reflection"
 )
 
 func main() {
+	configFile := config.Flag()
+	check := doctor.Flag()
+	migrate := config.MigrateFlag()
+	flag.Parse()
+
 	// Load configuration
-	cfg, err := config.Load()
+	cfg, err := config.Open(*configFile)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to load configuration: %v\n", err)
 		os.Exit(1)
 	}
+	if *migrate {
+		cfg.Database.MigrateOnStart = true
+	}
+
+	// Validate configuration and dependencies and exit in --check mode
+	if *check {
+		checks := []health.Checker{doctor.Config(cfg)}
+		checks = append(checks, doctor.Database(cfg.Database)...)
+		if cfg.Audit.Enabled {
+			checks = append(checks, doctor.Events(cfg.Events)...)
+		}
+		if cfg.UserValidation.Enabled {
+			checks = append(checks, doctor.Endpoint("user-service", cfg.UserValidation.UserServiceEndpoint, !cfg.UserValidation.FailOpen))
+		}
+		if cfg.Catalog.Enabled {
+			checks = append(checks, doctor.Endpoint("product-service", cfg.Catalog.ProductServiceEndpoint, true))
+		}
+		os.Exit(doctor.Run(context.Background(), os.Stdout, "order-service", checks))
+	}
+
+	// Probe the running server and exit in healthcheck mode
+	if flag.Arg(0) == doctor.HealthcheckCommand {
+		os.Exit(doctor.Healthcheck(os.Stdout, doctor.GRPCProbe(cfg.Server, cfg.TLS)))
+	}
 
 	// Initialize logger
 	logCfg := &log.Config{
@@ -58,114 +110,375 @@ func main() {
 		os.Exit(1)
 	}
 	defer logger.Sync()
+	// Code without a request-scoped logger logs with this one
+	log.SetDefault(logger)
 
-	logger.Info("Starting order service",
-		log.String("version", "1.0.0"),
-		log.Int("grpc_port", cfg.Server.GRPCPort),
-		log.Int("http_port", cfg.Server.Port),
-	)
+	// Export traces when enabled
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Observability, "order-service")
+	if err != nil {
+		logger.Fatal("Failed to set up tracing", log.Error(err))
+	}
+	defer shutdownTracing(context.Background())
 
-	// Connect to database
-	database, err := db.Connect(cfg.Database)
+	// Report internal errors and panics when configured
+	flushReports, err := reporting.Setup(cfg.Observability, "order-service")
 	if err != nil {
-		logger.Fatal("Failed to connect to database", log.Error(err))
+		logger.Fatal("Failed to set up error reporting", log.Error(err))
 	}
-	defer database.Close()
+	defer flushReports()
 
-	// Run migrations - skipping for now as migrations should be handled separately
-	logger.Info("Skipping automatic migrations - use migration tool separately")
+	// Authenticate API calls by bearer token when enabled
+	var authenticator *auth.Authenticator
+	if cfg.Auth.Enabled {
+		if authenticator, err = auth.New(cfg.Auth); err != nil {
+			logger.Fatal("Failed to set up authentication", log.Error(err))
+		}
+	}
 
-	// Initialize repository and service
-	orderRepo := repository.New(database)
-	orderService := service.New(orderRepo, logger)
+	// Secure gRPC connections with TLS when enabled
+	tlsStore, err := certs.FromConfig(cfg.TLS, logger)
+	if err != nil {
+		logger.Fatal("Failed to load TLS certificates", log.Error(err))
+	}
 
-	// Create gRPC server
-	grpcServer := grpc.NewServer(
-		grpc.ChainUnaryInterceptor(
-			middleware.UnaryLoggingInterceptor(logger),
-			middleware.UnaryRecoveryInterceptor(logger),
-		),
+	logger.Info("Starting order service",
+		log.String("version", "1.0.0"),
+		log.Int("grpc_port", cfg.Server.GRPCPort),
+		log.Int("http_port", cfg.Server.Port),
 	)
 
-	// Register service
-	orderv1.RegisterOrderServiceServer(grpcServer, orderService)
-	reflection.Register(grpcServer)
-
-	// Start gRPC server
-	grpcAddr := fmt.Sprintf("%s:%d", cfg.Server.Host, cfg.Server.GRPCPort)
-	grpcListener, err := net.Listen("tcp", grpcAddr)
-	if err != nil {
-		logger.Fatal("Failed to listen for gRPC", log.Error(err))
-	}
+	// Status changes committed by any replica are pushed to WatchOrder
+	// streams through Postgres notifications
+	watchHub := watch.NewHub()
 
-	go func() {
-		logger.Info("Starting gRPC server", log.String("address", grpcAddr))
-		if err := grpcServer.Serve(grpcListener); err != nil {
-			logger.Fatal("Failed to serve gRPC", log.Error(err))
-		}
-	}()
+	// Start components in dependency order. A component that fails to
+	// start stops those already started.
+	var (
+		database              *db.DB
+		converter             *currency.Converter
+		users                 *client.UserClient
+		products              *client.ProductClient
+		orderRepo             repository.Repository
+		orderCache            cache.Cache
+		idempotencyStore      *idempotency.PostgresStore
+		orderService          service.Service
+		orderServiceV2        service.ServiceV2
+		recommendationRepo    recommendationrepo.Repository
+		recommendationService recommendationservice.Service
+		recorder              *audit.Recorder
+		sched                 *scheduler.Scheduler
+		election              *leader.Election
+	)
+	instrumentation := metrics.New(cfg.Observability)
+	lc := server.NewLifecycle(logger)
 
-	// Create HTTP server with gRPC-Gateway
-	ctx := context.Background()
-	ctx, cancel := context.WithCancel(ctx)
-	defer cancel()
+	// New orders are checked against the user service unless it is run
+	// offline, authenticating as the order service when auth is enabled
+	orderDeps := []string{"database", "currency"}
+	if u := cfg.UserValidation; u.Enabled {
+		lc.Add(server.Component{
+			Name: "user-client",
+			Start: func(ctx context.Context) (err error) {
+				userClient := client.Config{
+					Address:   u.UserServiceEndpoint,
+					Timeout:   u.Timeout,
+					Keepalive: client.KeepaliveFrom(cfg.Server.Keepalive),
+					DialOptions: []grpc.DialOption{
+						grpc.WithChainUnaryInterceptor(tenant.UnaryClientInterceptor()),
+					},
+				}
+				if tlsStore != nil {
+					userClient.TLS = tlsStore.ClientConfig()
+				}
+				if authenticator != nil {
+					userClient.DialOptions = append(userClient.DialOptions,
+						grpc.WithPerRPCCredentials(authenticator.Credentials("order-service")))
+				}
+				users, err = client.NewUserClient(ctx, userClient)
+				return err
+			},
+			Stop: func(context.Context) error { return users.Close() },
+		})
+		orderDeps = append(orderDeps, "user-client")
+	} else {
+		logger.Info("User validation disabled; orders are created without checking their user")
+	}
 
-	mux := runtime.NewServeMux()
+	// Order items are priced with the product catalog when it is enabled
+	if c := cfg.Catalog; c.Enabled {
+		lc.Add(server.Component{
+			Name: "product-client",
+			Start: func(ctx context.Context) (err error) {
+				productClient := client.Config{
+					Address:   c.ProductServiceEndpoint,
+					Timeout:   c.Timeout,
+					Keepalive: client.KeepaliveFrom(cfg.Server.Keepalive),
+					DialOptions: []grpc.DialOption{
+						grpc.WithChainUnaryInterceptor(tenant.UnaryClientInterceptor()),
+					},
+				}
+				if tlsStore != nil {
+					productClient.TLS = tlsStore.ClientConfig()
+				}
+				if authenticator != nil {
+					productClient.DialOptions = append(productClient.DialOptions,
+						grpc.WithPerRPCCredentials(authenticator.Credentials("order-service")))
+				}
+				products, err = client.NewProductClient(ctx, productClient)
+				return err
+			},
+			Stop: func(context.Context) error { return products.Close() },
+		})
+		orderDeps = append(orderDeps, "product-client")
+	} else {
+		logger.Info("Catalog pricing disabled; orders are priced as clients send them")
+	}
 
-	// Register gateway
-	opts := []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
-	if err := orderv1.RegisterOrderServiceHandlerFromEndpoint(ctx, mux, grpcAddr, opts); err != nil {
-		logger.Fatal("Failed to register gateway", log.Error(err))
+	// Order lookups are served from the cache when it is enabled
+	if cfg.Cache.Enabled {
+		lc.Add(server.Component{
+			Name: "cache",
+			Start: func(context.Context) error {
+				orderCache = cache.New(cfg.Cache)
+				return nil
+			},
+			Stop: func(context.Context) error { return orderCache.Close() },
+		})
+		orderDeps = append(orderDeps, "cache")
 	}
 
-	// Add health check endpoints
-	handler := addHealthCheckEndpoints(mux, logger)
+	lc.Add(
+		server.Component{
+			Name: "database",
+			Start: func(context.Context) (err error) {
+				database, err = db.ConnectAndMigrate(cfg.Database)
+				return err
+			},
+			Stop: func(context.Context) error { return database.Close() },
+		},
+		server.Component{
+			Name: "currency",
+			Start: func(context.Context) (err error) {
+				if cfg.Currency.Provider != "" {
+					converter, err = currency.New(cfg.Currency, logger)
+				}
+				return err
+			},
+		},
+		server.Component{
+			Name:      "order",
+			DependsOn: orderDeps,
+			Start: func(context.Context) error {
+				orderRepo = repository.New(database, repository.WithReservationTTL(cfg.Inventory.ReservationTTL))
+				if orderCache != nil {
+					orderRepo = repository.NewCached(orderRepo, orderCache, cfg.Cache.OrderTTL, logger)
+				}
+				orderOpts := []service.Option{
+					service.WithCurrency(cfg.Currency.Base, converter),
+					service.WithPromotions(promotion.New(promotionrepo.New(database))),
+					service.WithPageTokens(pagination.New(cfg.Pagination.Secret)),
+					service.WithWatch(watchHub),
+				}
+				if users != nil {
+					orderOpts = append(orderOpts, service.WithUsers(service.NewUserClient(
+						users,
+						breaker.New(cfg.UserValidation.BreakerThreshold, cfg.UserValidation.BreakerCooldown),
+						cfg.UserValidation.FailOpen,
+						logger,
+					)))
+				}
+				if products != nil {
+					orderOpts = append(orderOpts, service.WithCatalog(service.NewProductClient(products)))
+				}
+				if cfg.Idempotency.Enabled {
+					idempotencyStore = idempotency.NewPostgresStore(database, cfg.Idempotency.TTL, cfg.Idempotency.LockTimeout)
+					orderOpts = append(orderOpts, service.WithIdempotency(idempotencyStore))
+				}
+				orderService = service.New(orderRepo, logger, orderOpts...)
+				orderServiceV2 = service.NewV2(orderRepo, logger, orderOpts...)
+				return nil
+			},
+		},
+		// Recommendations are aggregated from order history, so the order
+		// service serves them too
+		server.Component{
+			Name:      "recommendation",
+			DependsOn: []string{"database"},
+			Start: func(context.Context) error {
+				recommendationRepo = recommendationrepo.New(database)
+				recommendationService = recommendationservice.New(recommendationRepo, logger)
+				return nil
+			},
+		},
+	)
 
-	httpAddr := fmt.Sprintf("%s:%d", cfg.Server.Host, cfg.Server.Port)
-	httpServer := &http.Server{
-		Addr:         httpAddr,
-		Handler:      handler,
-		ReadTimeout:  15 * time.Second,
-		WriteTimeout: 15 * time.Second,
-		IdleTimeout:  60 * time.Second,
+	schedulerDeps := []string{"database", "order", "recommendation"}
+	if cfg.Audit.Enabled {
+		var auditBus events.Bus
+		lc.Add(
+			server.Component{
+				Name: "audit-events",
+				Start: func(context.Context) (err error) {
+					auditBus, err = events.New(cfg.Events, logger)
+					return err
+				},
+				Stop: func(context.Context) error { return auditBus.Close() },
+			},
+			// The recorder publishes to the bus, so it stops first
+			server.Component{
+				Name:      "audit-recorder",
+				DependsOn: []string{"audit-events"},
+				Start: func(context.Context) error {
+					recorder = audit.NewRecorder(auditBus, cfg.Audit.Topic, logger)
+					return nil
+				},
+				Stop: func(context.Context) error {
+					recorder.Close()
+					return nil
+				},
+			},
+		)
+		// Retention audits what it deletes
+		schedulerDeps = append(schedulerDeps, "audit-recorder")
 	}
 
-	go func() {
-		logger.Info("Starting HTTP server", log.String("address", httpAddr))
-		if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			logger.Fatal("Failed to serve HTTP", log.Error(err))
-		}
-	}()
+	// Run scheduled jobs
+	if cfg.Scheduler.Enabled {
+		lc.Add(server.Component{
+			Name:      "scheduler",
+			DependsOn: schedulerDeps,
+			Start: func(context.Context) error {
+				sched = scheduler.New(scheduler.Config{
+					Locker:  scheduler.NewPostgresLocker(database),
+					History: scheduler.NewHistory(database),
+					Logger:  logger,
+				})
+				if err := sched.Register(jobs.NewPendingOrderExpiry(
+					orderRepo,
+					cfg.Scheduler.OrderExpirySchedule,
+					cfg.Scheduler.PendingOrderTTL,
+					logger,
+				)); err != nil {
+					return errors.Wrap(err, "failed to register pending order expiry job")
+				}
+				if err := sched.Register(jobs.NewReservationExpiry(
+					orderRepo,
+					cfg.Scheduler.ReservationExpirySchedule,
+					logger,
+				)); err != nil {
+					return errors.Wrap(err, "failed to register reservation expiry job")
+				}
+				if err := sched.Register(recommendationjobs.NewRecommendationAggregation(
+					recommendationRepo,
+					cfg.Scheduler.RecommendationSchedule,
+					logger,
+				)); err != nil {
+					return errors.Wrap(err, "failed to register recommendation aggregation job")
+				}
+				if idempotencyStore != nil {
+					if err := sched.Register(idempotency.NewPurgeJob(
+						idempotencyStore,
+						cfg.Idempotency.PurgeSchedule,
+						logger,
+					)); err != nil {
+						return errors.Wrap(err, "failed to register idempotency key purge job")
+					}
+				}
+				if cfg.Retention.Enabled {
+					var auditor retention.Auditor
+					if recorder != nil {
+						auditor = recorder
+					}
+					var changed retention.OrdersChanged
+					if orderCache != nil {
+						changed = func(ctx context.Context, orders []retention.Record) error {
+							for _, o := range orders {
+								if err := repository.Invalidate(ctx, orderCache, o.TenantID, o.ID); err != nil {
+									return err
+								}
+							}
+							return nil
+						}
+					}
+					runner := retention.New(cfg.Retention, database, auditor, changed, logger)
+					if err := sched.Register(retention.NewJob(runner, cfg.Retention.Schedule)); err != nil {
+						return errors.Wrap(err, "failed to register data retention job")
+					}
+				}
 
-	// Wait for interrupt signal
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
+				// Only the leader replica runs the scheduler
+				var err error
+				election, err = leader.FromConfig(cfg.Leader, "order-service-scheduler", database, logger)
+				return err
+			},
+		})
+	}
 
-	logger.Info("Shutting down server...")
+	// gRPC method quotas are shared through Redis when configured
+	var limiter *ratelimit.Limiter
+	if cfg.RateLimit.Enabled && len(cfg.RateLimit.Methods) > 0 {
+		lc.Add(server.Component{
+			Name: "rate-limit",
+			Start: func(context.Context) (err error) {
+				limiter, err = ratelimit.NewMethods(cfg.RateLimit, instrumentation.Registry, logger)
+				return err
+			},
+			Stop: func(context.Context) error { return limiter.Close() },
+		})
+	}
 
-	// Graceful shutdown
-	ctx, cancel = context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
+	if err := lc.Start(context.Background()); err != nil {
+		logger.Fatal("Failed to start components", log.Error(err))
+	}
 
-	if err := httpServer.Shutdown(ctx); err != nil {
-		logger.Error("HTTP server forced to shutdown", log.Error(err))
+	opts := []server.Option{
+		server.WithAuth(authenticator),
+		server.WithTLS(tlsStore),
+		server.WithRateLimit(limiter),
+		server.WithLifecycle(lc),
+		server.WithHTTPGateway(
+			orderv1.RegisterOrderServiceHandlerFromEndpoint,
+			orderv2.RegisterOrderServiceHandlerFromEndpoint,
+			recommendationv1.RegisterRecommendationServiceHandlerFromEndpoint,
+		),
+		server.WithHealth(),
+		server.WithHealthCheck(
+			health.Checker{Name: "database", Check: database.PingContext, Critical: true},
+			health.Checker{Name: "migrations", Check: health.Migrations(database, db.SchemaVersion), Critical: true},
+		),
+		server.WithInstrumentation(instrumentation),
+		server.WithSLO(cfg.SLO),
+		server.WithRuntimeStats(cfg.Observability.RuntimeStatsInterval, database.DB),
+		server.WithChannelz(cfg.Observability.ChannelzEnabled),
+		server.WithLogLevelEndpoint(cfg.Log.LevelEndpoint),
+		server.WithConfigReload(*configFile, cfg),
+		server.WithDiagnostics(cfg),
+		server.WithWorker("order-watch", func(ctx context.Context) error {
+			return watch.Listen(ctx, cfg.Database.GetDSN(), watchHub, logger)
+		}),
+		// End WatchOrder streams so that the gRPC server can drain
+		server.WithDrainHook(watchHub.Close),
 	}
 
-	grpcServer.GracefulStop()
+	// Scope every call to its tenant, then audit state-changing calls
+	tenantResolver := tenant.NewResolver(cfg.Tenancy)
+	interceptors := []grpc.UnaryServerInterceptor{
+		tenant.UnaryServerInterceptor(tenantResolver),
+	}
+	if recorder != nil {
+		interceptors = append(interceptors, audit.UnaryServerInterceptor(recorder, "order-service"))
+	}
+	if sched != nil {
+		opts = append(opts, server.WithWorker("scheduler", election.Worker(sched.Run)))
+	}
 
-	logger.Info("Server stopped")
-}
+	opts = append(opts, server.WithGRPC(func(s *grpc.Server) {
+		orderv1.RegisterOrderServiceServer(s, orderService)
+		orderv2.RegisterOrderServiceServer(s, orderServiceV2)
+		recommendationv1.RegisterRecommendationServiceServer(s, recommendationService)
+	}, interceptors...), server.WithStreamInterceptors(tenant.StreamServerInterceptor(tenantResolver)))
 
-func addHealthCheckEndpoints(mux *runtime.ServeMux, logger *log.Logger) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if r.URL.Path == "/health" || r.URL.Path == "/ready" {
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusOK)
-			w.Write([]byte(`{"status":"ok"}`))
-			logger.Debug("Health check", log.String("path", r.URL.Path))
-			return
-		}
-		mux.ServeHTTP(w, r)
-	})
+	if err := server.New("order-service", cfg.Server, logger, opts...).Run(context.Background()); err != nil {
+		logger.Error("Server failed", log.Error(err))
+	}
 }
@@ -0,0 +1,185 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/kevindiu/monorepo-go-example/internal/config"
+	"github.com/kevindiu/monorepo-go-example/internal/db"
+	"github.com/kevindiu/monorepo-go-example/internal/doctor"
+	"github.com/kevindiu/monorepo-go-example/internal/events"
+	"github.com/kevindiu/monorepo-go-example/internal/health"
+	"github.com/kevindiu/monorepo-go-example/internal/leader"
+	"github.com/kevindiu/monorepo-go-example/internal/log"
+	"github.com/kevindiu/monorepo-go-example/internal/metrics"
+	"github.com/kevindiu/monorepo-go-example/internal/outbox"
+	"github.com/kevindiu/monorepo-go-example/internal/reporting"
+	"github.com/kevindiu/monorepo-go-example/internal/tracing"
+	"github.com/kevindiu/monorepo-go-example/pkg/server"
+)
+
+func main() {
+	configFile := config.Flag()
+	check := doctor.Flag()
+	migrate := config.MigrateFlag()
+	flag.Parse()
+
+	// Load configuration
+	cfg, err := config.Open(*configFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load configuration: %v\n", err)
+		os.Exit(1)
+	}
+	if *migrate {
+		cfg.Database.MigrateOnStart = true
+	}
+
+	// Validate configuration and dependencies and exit in --check mode
+	if *check {
+		checks := []health.Checker{doctor.Config(cfg)}
+		checks = append(checks, doctor.Database(cfg.Database)...)
+		if cfg.Outbox.Sink == outbox.SinkEvents {
+			checks = append(checks, doctor.Events(cfg.Events)...)
+		}
+		os.Exit(doctor.Run(context.Background(), os.Stdout, "outbox-relay", checks))
+	}
+
+	// Probe the running server and exit in healthcheck mode
+	if flag.Arg(0) == doctor.HealthcheckCommand {
+		os.Exit(doctor.Healthcheck(os.Stdout, doctor.HTTPProbe(cfg.Server)))
+	}
+
+	// Initialize logger
+	logCfg := &log.Config{
+		Level:  cfg.Log.Level,
+		Format: cfg.Log.Format,
+	}
+	logger, err := log.New(logCfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to initialize logger: %v\n", err)
+		os.Exit(1)
+	}
+	defer logger.Sync()
+	// Code without a request-scoped logger logs with this one
+	log.SetDefault(logger)
+
+	// Export traces when enabled
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Observability, "outbox-relay")
+	if err != nil {
+		logger.Fatal("Failed to set up tracing", log.Error(err))
+	}
+	defer shutdownTracing(context.Background())
+
+	// Report internal errors and panics when configured
+	flushReports, err := reporting.Setup(cfg.Observability, "outbox-relay")
+	if err != nil {
+		logger.Fatal("Failed to set up error reporting", log.Error(err))
+	}
+	defer flushReports()
+
+	logger.Info("Starting outbox relay",
+		log.String("version", "1.0.0"),
+		log.String("sink", cfg.Outbox.Sink),
+		log.String("events_driver", cfg.Events.Driver),
+		log.Int("http_port", cfg.Server.Port),
+	)
+
+	// Relay metrics are served with the request metrics
+	m := metrics.New(cfg.Observability)
+
+	// Start components in dependency order. A component that fails to
+	// start stops those already started.
+	var (
+		database *db.DB
+		sink     events.Publisher
+		relay    *outbox.Relay
+		election *leader.Election
+	)
+	lc := server.NewLifecycle(logger)
+	lc.Add(
+		server.Component{
+			Name: "database",
+			Start: func(context.Context) (err error) {
+				database, err = db.ConnectAndMigrate(cfg.Database)
+				return err
+			},
+			Stop: func(context.Context) error { return database.Close() },
+		},
+		server.Component{
+			Name: "sink",
+			Start: func(context.Context) (err error) {
+				sink, err = outbox.NewSink(cfg.Outbox, cfg.Events, logger)
+				return err
+			},
+			Stop: func(context.Context) error { return sink.Close() },
+		},
+		server.Component{
+			Name:      "relay",
+			DependsOn: []string{"database", "sink"},
+			Start: func(context.Context) (err error) {
+				relay, err = outbox.NewRelay(outbox.RelayConfig{
+					Store:        outbox.NewStore(database),
+					Publisher:    sink,
+					Logger:       logger,
+					PollInterval: cfg.Outbox.PollInterval,
+					BatchSize:    cfg.Outbox.BatchSize,
+					MaxAttempts:  cfg.Outbox.MaxAttempts,
+					Registerer:   m.Registry,
+				})
+				return err
+			},
+			// Publish what is left in the outbox before the sink closes
+			Stop: func(ctx context.Context) error { return relay.Flush(ctx) },
+		},
+		// Only the leader replica relays, so that events are published in order
+		server.Component{
+			Name:      "leader",
+			DependsOn: []string{"database"},
+			Start: func(context.Context) (err error) {
+				election, err = leader.FromConfig(cfg.Leader, "outbox-relay", database, logger)
+				return err
+			},
+		},
+	)
+	if err := lc.Start(context.Background()); err != nil {
+		logger.Fatal("Failed to start components", log.Error(err))
+	}
+
+	// Serve metrics and health checks while the relay runs
+	srv := server.New("outbox-relay", cfg.Server, logger,
+		server.WithLifecycle(lc),
+		server.WithHealth(),
+		server.WithHealthCheck(
+			health.Checker{Name: "database", Check: database.PingContext, Critical: true},
+			health.Checker{Name: "migrations", Check: health.Migrations(database, db.SchemaVersion), Critical: true},
+		),
+		server.WithInstrumentation(m),
+		server.WithRuntimeStats(cfg.Observability.RuntimeStatsInterval, database.DB),
+		server.WithChannelz(cfg.Observability.ChannelzEnabled),
+		server.WithLogLevelEndpoint(cfg.Log.LevelEndpoint),
+		server.WithConfigReload(*configFile, cfg),
+		server.WithDiagnostics(cfg),
+		server.WithWorker("relay", election.Worker(relay.Run)),
+	)
+	if err := srv.Run(context.Background()); err != nil {
+		logger.Error("Server failed", log.Error(err))
+	}
+}
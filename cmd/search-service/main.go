@@ -0,0 +1,209 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	searchv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/search/v1"
+	"github.com/kevindiu/monorepo-go-example/internal/auth"
+	"github.com/kevindiu/monorepo-go-example/internal/certs"
+	"github.com/kevindiu/monorepo-go-example/internal/config"
+	"github.com/kevindiu/monorepo-go-example/internal/doctor"
+	"github.com/kevindiu/monorepo-go-example/internal/events"
+	"github.com/kevindiu/monorepo-go-example/internal/health"
+	"github.com/kevindiu/monorepo-go-example/internal/log"
+	"github.com/kevindiu/monorepo-go-example/internal/metrics"
+	"github.com/kevindiu/monorepo-go-example/internal/ratelimit"
+	"github.com/kevindiu/monorepo-go-example/internal/reporting"
+	"github.com/kevindiu/monorepo-go-example/internal/tracing"
+	"github.com/kevindiu/monorepo-go-example/pkg/search/index"
+	"github.com/kevindiu/monorepo-go-example/pkg/search/indexer"
+	"github.com/kevindiu/monorepo-go-example/pkg/search/service"
+	"github.com/kevindiu/monorepo-go-example/pkg/server"
+	"google.golang.org/grpc"
+)
+
+func main() {
+	configFile := config.Flag()
+	check := doctor.Flag()
+	flag.Parse()
+
+	// Load configuration
+	cfg, err := config.Open(*configFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load configuration: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Validate configuration and dependencies and exit in --check mode
+	if *check {
+		checks := []health.Checker{doctor.Config(cfg)}
+		checks = append(checks, doctor.Events(cfg.Events)...)
+		if cfg.Search.Driver == "elasticsearch" {
+			checks = append(checks, doctor.Endpoint("elasticsearch", cfg.Search.ElasticsearchURL, true))
+		}
+		os.Exit(doctor.Run(context.Background(), os.Stdout, "search-service", checks))
+	}
+
+	// Probe the running server and exit in healthcheck mode
+	if flag.Arg(0) == doctor.HealthcheckCommand {
+		os.Exit(doctor.Healthcheck(os.Stdout, doctor.GRPCProbe(cfg.Server, cfg.TLS)))
+	}
+
+	// Initialize logger
+	logCfg := &log.Config{
+		Level:  cfg.Log.Level,
+		Format: cfg.Log.Format,
+	}
+	logger, err := log.New(logCfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to initialize logger: %v\n", err)
+		os.Exit(1)
+	}
+	defer logger.Sync()
+	// Code without a request-scoped logger logs with this one
+	log.SetDefault(logger)
+
+	// Export traces when enabled
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Observability, "search-service")
+	if err != nil {
+		logger.Fatal("Failed to set up tracing", log.Error(err))
+	}
+	defer shutdownTracing(context.Background())
+
+	// Report internal errors and panics when configured
+	flushReports, err := reporting.Setup(cfg.Observability, "search-service")
+	if err != nil {
+		logger.Fatal("Failed to set up error reporting", log.Error(err))
+	}
+	defer flushReports()
+
+	// Authenticate API calls by bearer token when enabled
+	var authenticator *auth.Authenticator
+	if cfg.Auth.Enabled {
+		if authenticator, err = auth.New(cfg.Auth); err != nil {
+			logger.Fatal("Failed to set up authentication", log.Error(err))
+		}
+	}
+
+	// Secure gRPC connections with TLS when enabled
+	tlsStore, err := certs.FromConfig(cfg.TLS, logger)
+	if err != nil {
+		logger.Fatal("Failed to load TLS certificates", log.Error(err))
+	}
+
+	logger.Info("Starting search service",
+		log.String("version", "1.0.0"),
+		log.Int("grpc_port", cfg.Server.GRPCPort),
+		log.Int("http_port", cfg.Server.Port),
+		log.String("search_driver", cfg.Search.Driver),
+	)
+
+	// Start components in dependency order. A component that fails to
+	// start stops those already started.
+	var (
+		idx           index.Index
+		bus           events.Bus
+		searchService service.Service
+		ix            *indexer.Indexer
+	)
+	instrumentation := metrics.New(cfg.Observability)
+	lc := server.NewLifecycle(logger)
+	lc.Add(
+		server.Component{
+			Name: "index",
+			Start: func(ctx context.Context) (err error) {
+				idx, err = index.New(ctx, cfg.Search)
+				return err
+			},
+			Stop: func(context.Context) error { return idx.Close() },
+		},
+		server.Component{
+			Name: "events",
+			Start: func(context.Context) (err error) {
+				bus, err = events.New(cfg.Events, logger)
+				return err
+			},
+			Stop: func(context.Context) error { return bus.Close() },
+		},
+		server.Component{
+			Name:      "search",
+			DependsOn: []string{"index"},
+			Start: func(context.Context) error {
+				searchService = service.New(idx, logger)
+				return nil
+			},
+		},
+		server.Component{
+			Name:      "indexer",
+			DependsOn: []string{"index", "events"},
+			Start: func(context.Context) (err error) {
+				ix, err = indexer.New(indexer.Config{
+					Index:      idx,
+					Subscriber: bus,
+					Topics:     cfg.Search.Topics,
+					Logger:     logger,
+				})
+				return err
+			},
+		},
+	)
+
+	// gRPC method quotas are shared through Redis when configured
+	var limiter *ratelimit.Limiter
+	if cfg.RateLimit.Enabled && len(cfg.RateLimit.Methods) > 0 {
+		lc.Add(server.Component{
+			Name: "rate-limit",
+			Start: func(context.Context) (err error) {
+				limiter, err = ratelimit.NewMethods(cfg.RateLimit, instrumentation.Registry, logger)
+				return err
+			},
+			Stop: func(context.Context) error { return limiter.Close() },
+		})
+	}
+
+	if err := lc.Start(context.Background()); err != nil {
+		logger.Fatal("Failed to start components", log.Error(err))
+	}
+
+	srv := server.New("search-service", cfg.Server, logger,
+		server.WithLifecycle(lc),
+		server.WithAuth(authenticator),
+		server.WithTLS(tlsStore),
+		server.WithRateLimit(limiter),
+		server.WithGRPC(func(s *grpc.Server) {
+			searchv1.RegisterSearchServiceServer(s, searchService)
+		}),
+		server.WithHTTPGateway(searchv1.RegisterSearchServiceHandlerFromEndpoint),
+		server.WithHealth(),
+		server.WithInstrumentation(instrumentation),
+		server.WithSLO(cfg.SLO),
+		server.WithRuntimeStats(cfg.Observability.RuntimeStatsInterval, nil),
+		server.WithChannelz(cfg.Observability.ChannelzEnabled),
+		server.WithLogLevelEndpoint(cfg.Log.LevelEndpoint),
+		server.WithConfigReload(*configFile, cfg),
+		server.WithDiagnostics(cfg),
+		server.WithWorker("indexer", ix.Run),
+	)
+	if err := srv.Run(context.Background()); err != nil {
+		logger.Error("Server failed", log.Error(err))
+	}
+}
@@ -18,34 +18,68 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
-	"net"
-	"net/http"
 	"os"
-	"os/signal"
-	"syscall"
-	"time"
 
-	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	apikeyv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/apikey/v1"
+	authv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/auth/v1"
 	userv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/user/v1"
+	userv2 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/user/v2"
+	"github.com/kevindiu/monorepo-go-example/internal/apikey"
+	"github.com/kevindiu/monorepo-go-example/internal/audit"
+	"github.com/kevindiu/monorepo-go-example/internal/auth"
+	"github.com/kevindiu/monorepo-go-example/internal/cache"
+	"github.com/kevindiu/monorepo-go-example/internal/certs"
 	"github.com/kevindiu/monorepo-go-example/internal/config"
+	"github.com/kevindiu/monorepo-go-example/internal/crypto"
 	"github.com/kevindiu/monorepo-go-example/internal/db"
+	"github.com/kevindiu/monorepo-go-example/internal/doctor"
+	"github.com/kevindiu/monorepo-go-example/internal/events"
+	"github.com/kevindiu/monorepo-go-example/internal/health"
+	"github.com/kevindiu/monorepo-go-example/internal/idempotency"
 	"github.com/kevindiu/monorepo-go-example/internal/log"
-	"github.com/kevindiu/monorepo-go-example/internal/middleware"
+	"github.com/kevindiu/monorepo-go-example/internal/metrics"
+	"github.com/kevindiu/monorepo-go-example/internal/pagination"
+	"github.com/kevindiu/monorepo-go-example/internal/password"
+	"github.com/kevindiu/monorepo-go-example/internal/ratelimit"
+	"github.com/kevindiu/monorepo-go-example/internal/reporting"
+	"github.com/kevindiu/monorepo-go-example/internal/tenant"
+	"github.com/kevindiu/monorepo-go-example/internal/tracing"
+	"github.com/kevindiu/monorepo-go-example/pkg/server"
+	"github.com/kevindiu/monorepo-go-example/pkg/user/handler"
 	"github.com/kevindiu/monorepo-go-example/pkg/user/repository"
 	"github.com/kevindiu/monorepo-go-example/pkg/user/service"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
-	"google.golang.org/grpc/reflection"
 )
 
 func main() {
+	configFile := config.Flag()
+	check := doctor.Flag()
+	migrate := config.MigrateFlag()
+	flag.Parse()
+
 	// Load configuration
-	cfg, err := config.Load()
+	cfg, err := config.Open(*configFile)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to load configuration: %v\n", err)
 		os.Exit(1)
 	}
+	if *migrate {
+		cfg.Database.MigrateOnStart = true
+	}
+
+	// Validate configuration and dependencies and exit in --check mode
+	if *check {
+		checks := []health.Checker{doctor.Config(cfg)}
+		checks = append(checks, doctor.Database(cfg.Database)...)
+		os.Exit(doctor.Run(context.Background(), os.Stdout, "user-service", checks))
+	}
+
+	// Probe the running server and exit in healthcheck mode
+	if flag.Arg(0) == doctor.HealthcheckCommand {
+		os.Exit(doctor.Healthcheck(os.Stdout, doctor.GRPCProbe(cfg.Server, cfg.TLS)))
+	}
 
 	// Initialize logger
 	logCfg := &log.Config{
@@ -58,122 +92,205 @@ func main() {
 		os.Exit(1)
 	}
 	defer logger.Sync()
+	// Code without a request-scoped logger logs with this one
+	log.SetDefault(logger)
 
-	logger.Info("Starting user service",
-		log.String("version", "1.0.0"),
-		log.Int("grpc_port", cfg.Server.GRPCPort),
-		log.Int("http_port", cfg.Server.Port),
-	)
-
-	// Connect to database
-	database, err := db.Connect(cfg.Database)
+	// Export traces when enabled
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Observability, "user-service")
 	if err != nil {
-		logger.Fatal("Failed to connect to database", log.Error(err))
+		logger.Fatal("Failed to set up tracing", log.Error(err))
 	}
-	defer database.Close()
+	defer shutdownTracing(context.Background())
 
-	// Run migrations - skipping for now as migrations should be handled separately
-	// In production, use a migration tool like golang-migrate
-	logger.Info("Skipping automatic migrations - use migration tool separately")
-
-	// Initialize repository and service
-	userRepo := repository.NewUserRepository(database)
-	userService := service.NewUserService(userRepo)
-	_ = userService // TODO: create gRPC handler wrapper
-
-	// TODO: User service needs a gRPC handler wrapper since the business logic
-	// service interface doesn't match the protobuf-generated gRPC interface.
-	// For now, the service is created but not registered.
-	// Create a handler in pkg/user/handler that wraps userService and implements userv1.UserServiceServer
-
-	// Create gRPC server
-	grpcServer := grpc.NewServer(
-		grpc.ChainUnaryInterceptor(
-			middleware.UnaryLoggingInterceptor(logger),
-			middleware.UnaryRecoveryInterceptor(logger),
-		),
-	)
-
-	// Register service - DISABLED until gRPC handler is implemented
-	// userv1.RegisterUserServiceServer(grpcServer, userService)
-	logger.Warn("User service gRPC handler not yet implemented - service will start but won't handle requests")
-	reflection.Register(grpcServer)
-
-	// Start gRPC server
-	grpcAddr := fmt.Sprintf("%s:%d", cfg.Server.Host, cfg.Server.GRPCPort)
-	grpcListener, err := net.Listen("tcp", grpcAddr)
+	// Report internal errors and panics when configured
+	flushReports, err := reporting.Setup(cfg.Observability, "user-service")
 	if err != nil {
-		logger.Fatal("Failed to listen for gRPC", log.Error(err))
+		logger.Fatal("Failed to set up error reporting", log.Error(err))
 	}
+	defer flushReports()
 
-	go func() {
-		logger.Info("Starting gRPC server", log.String("address", grpcAddr))
-		if err := grpcServer.Serve(grpcListener); err != nil {
-			logger.Fatal("Failed to serve gRPC", log.Error(err))
+	// Authenticate API calls by bearer token when enabled
+	var authenticator *auth.Authenticator
+	if cfg.Auth.Enabled {
+		if authenticator, err = auth.New(cfg.Auth); err != nil {
+			logger.Fatal("Failed to set up authentication", log.Error(err))
 		}
-	}()
-
-	// Create HTTP server with gRPC-Gateway
-	ctx := context.Background()
-	ctx, cancel := context.WithCancel(ctx)
-	defer cancel()
-
-	mux := runtime.NewServeMux()
-
-	// Register gateway
-	opts := []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
-	if err := userv1.RegisterUserServiceHandlerFromEndpoint(ctx, mux, grpcAddr, opts); err != nil {
-		logger.Fatal("Failed to register gateway", log.Error(err))
 	}
 
-	// Add health check endpoints
-	handler := addHealthCheckEndpoints(mux, logger)
-
-	httpAddr := fmt.Sprintf("%s:%d", cfg.Server.Host, cfg.Server.Port)
-	httpServer := &http.Server{
-		Addr:         httpAddr,
-		Handler:      handler,
-		ReadTimeout:  15 * time.Second,
-		WriteTimeout: 15 * time.Second,
-		IdleTimeout:  60 * time.Second,
+	// Secure gRPC connections with TLS when enabled
+	tlsStore, err := certs.FromConfig(cfg.TLS, logger)
+	if err != nil {
+		logger.Fatal("Failed to load TLS certificates", log.Error(err))
 	}
 
-	go func() {
-		logger.Info("Starting HTTP server", log.String("address", httpAddr))
-		if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			logger.Fatal("Failed to serve HTTP", log.Error(err))
+	logger.Info("Starting user service",
+		log.String("version", "1.0.0"),
+		log.Int("grpc_port", cfg.Server.GRPCPort),
+		log.Int("http_port", cfg.Server.Port),
+	)
+
+	// Encrypt personal data at rest when enabled
+	var userRepoOpts []repository.Option
+	var enc *crypto.Encryptor
+	if cfg.Encryption.Enabled {
+		var err error
+		enc, err = crypto.New(cfg.Encryption)
+		if err != nil {
+			logger.Fatal("Failed to set up encryption", log.Error(err))
 		}
-	}()
+		userRepoOpts = append(userRepoOpts, repository.WithEncryptor(enc))
+	}
 
-	// Wait for interrupt signal
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
+	passwords, err := password.New(cfg.Password)
+	if err != nil {
+		logger.Fatal("Failed to set up password hashing", log.Error(err))
+	}
 
-	logger.Info("Shutting down server...")
+	// Start components in dependency order. A component that fails to
+	// start stops those already started.
+	var (
+		database    *db.DB
+		userService service.UserService
+		authService service.AuthService
+		keyService  service.APIKeyService
+		recorder    *audit.Recorder
+	)
+	instrumentation := metrics.New(cfg.Observability)
+	lc := server.NewLifecycle(logger)
+	lc.Add(server.Component{
+		Name: "database",
+		Start: func(context.Context) (err error) {
+			database, err = db.ConnectAndMigrate(cfg.Database)
+			return err
+		},
+		Stop: func(context.Context) error { return database.Close() },
+	})
 
-	// Graceful shutdown
-	ctx, cancel = context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
+	// Lookups are served from the cache when it is enabled
+	var userCache cache.Cache
+	userDeps := []string{"database"}
+	if cfg.Cache.Enabled {
+		lc.Add(server.Component{
+			Name: "cache",
+			Start: func(context.Context) error {
+				userCache = cache.New(cfg.Cache)
+				return nil
+			},
+			Stop: func(context.Context) error { return userCache.Close() },
+		})
+		userDeps = append(userDeps, "cache")
+	}
+	lc.Add(server.Component{
+		Name:      "user",
+		DependsOn: userDeps,
+		Start: func(context.Context) error {
+			userRepo := repository.NewUserRepository(database, userRepoOpts...)
+			if userCache != nil {
+				userRepo = repository.NewCachedUserRepository(userRepo, userCache, cfg.Cache.TTL, enc, logger)
+			}
+			sessions := repository.NewSessionRepository(database)
+			userOpts := []service.Option{
+				service.WithPageTokens(pagination.New(cfg.Pagination.Secret)),
+				service.WithPasswords(passwords),
+				service.WithSessions(sessions),
+			}
+			if cfg.Idempotency.Enabled {
+				userOpts = append(userOpts, service.WithIdempotency(
+					idempotency.NewPostgresStore(database, cfg.Idempotency.TTL, cfg.Idempotency.LockTimeout),
+				))
+			}
+			userService = service.NewUserService(userRepo, userOpts...)
+			keyService = service.NewAPIKeyService(apikey.NewPostgresStore(database))
+			// Logins issue tokens, so they are only served with auth enabled
+			if authenticator != nil {
+				authService = service.NewAuthService(userService, sessions, authenticator, cfg.Auth.RefreshTokenTTL)
+			}
+			return nil
+		},
+	})
 
-	if err := httpServer.Shutdown(ctx); err != nil {
-		logger.Error("HTTP server forced to shutdown", log.Error(err))
+	if cfg.Audit.Enabled {
+		var auditBus events.Bus
+		lc.Add(
+			server.Component{
+				Name: "audit-events",
+				Start: func(context.Context) (err error) {
+					auditBus, err = events.New(cfg.Events, logger)
+					return err
+				},
+				Stop: func(context.Context) error { return auditBus.Close() },
+			},
+			// The recorder publishes to the bus, so it stops first
+			server.Component{
+				Name:      "audit-recorder",
+				DependsOn: []string{"audit-events"},
+				Start: func(context.Context) error {
+					recorder = audit.NewRecorder(auditBus, cfg.Audit.Topic, logger)
+					return nil
+				},
+				Stop: func(context.Context) error {
+					recorder.Close()
+					return nil
+				},
+			},
+		)
 	}
 
-	grpcServer.GracefulStop()
+	// gRPC method quotas are shared through Redis when configured
+	var limiter *ratelimit.Limiter
+	if cfg.RateLimit.Enabled && len(cfg.RateLimit.Methods) > 0 {
+		lc.Add(server.Component{
+			Name: "rate-limit",
+			Start: func(context.Context) (err error) {
+				limiter, err = ratelimit.NewMethods(cfg.RateLimit, instrumentation.Registry, logger)
+				return err
+			},
+			Stop: func(context.Context) error { return limiter.Close() },
+		})
+	}
 
-	logger.Info("Server stopped")
-}
+	if err := lc.Start(context.Background()); err != nil {
+		logger.Fatal("Failed to start components", log.Error(err))
+	}
 
-func addHealthCheckEndpoints(mux *runtime.ServeMux, logger *log.Logger) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if r.URL.Path == "/health" || r.URL.Path == "/ready" {
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusOK)
-			w.Write([]byte(`{"status":"ok"}`))
-			logger.Debug("Health check", log.String("path", r.URL.Path))
-			return
-		}
-		mux.ServeHTTP(w, r)
-	})
+	// Scope every call and stream to its tenant, then audit state-changing
+	// calls. ExportUsers would otherwise read the default tenant's users.
+	tenantResolver := tenant.NewResolver(cfg.Tenancy)
+	interceptors := []grpc.UnaryServerInterceptor{
+		tenant.UnaryServerInterceptor(tenantResolver),
+	}
+	if recorder != nil {
+		interceptors = append(interceptors, audit.UnaryServerInterceptor(recorder, "user-service"))
+	}
+	srv := server.New("user-service", cfg.Server, logger,
+		server.WithLifecycle(lc),
+		server.WithAuth(authenticator),
+		server.WithTLS(tlsStore),
+		server.WithRateLimit(limiter),
+		server.WithGRPC(func(s *grpc.Server) {
+			userv1.RegisterUserServiceServer(s, handler.New(userService))
+			userv2.RegisterUserServiceServer(s, handler.NewV2(userService))
+			apikeyv1.RegisterAPIKeyServiceServer(s, handler.NewAPIKey(keyService))
+			if authService != nil {
+				authv1.RegisterAuthServiceServer(s, handler.NewAuth(authService))
+			}
+		}, interceptors...),
+		server.WithStreamInterceptors(tenant.StreamServerInterceptor(tenantResolver)),
+		server.WithHTTPGateway(userv1.RegisterUserServiceHandlerFromEndpoint, userv2.RegisterUserServiceHandlerFromEndpoint, authv1.RegisterAuthServiceHandlerFromEndpoint, apikeyv1.RegisterAPIKeyServiceHandlerFromEndpoint),
+		server.WithHealth(),
+		server.WithHealthCheck(
+			health.Checker{Name: "database", Check: database.PingContext, Critical: true},
+			health.Checker{Name: "migrations", Check: health.Migrations(database, db.SchemaVersion), Critical: true},
+		),
+		server.WithInstrumentation(instrumentation),
+		server.WithSLO(cfg.SLO),
+		server.WithRuntimeStats(cfg.Observability.RuntimeStatsInterval, database.DB),
+		server.WithChannelz(cfg.Observability.ChannelzEnabled),
+		server.WithLogLevelEndpoint(cfg.Log.LevelEndpoint),
+		server.WithConfigReload(*configFile, cfg),
+		server.WithDiagnostics(cfg),
+	)
+	if err := srv.Run(context.Background()); err != nil {
+		logger.Error("Server failed", log.Error(err))
+	}
 }
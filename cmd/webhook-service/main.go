@@ -0,0 +1,252 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	webhookv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/webhook/v1"
+	"github.com/kevindiu/monorepo-go-example/internal/audit"
+	"github.com/kevindiu/monorepo-go-example/internal/auth"
+	"github.com/kevindiu/monorepo-go-example/internal/certs"
+	"github.com/kevindiu/monorepo-go-example/internal/config"
+	"github.com/kevindiu/monorepo-go-example/internal/db"
+	"github.com/kevindiu/monorepo-go-example/internal/doctor"
+	"github.com/kevindiu/monorepo-go-example/internal/events"
+	"github.com/kevindiu/monorepo-go-example/internal/health"
+	"github.com/kevindiu/monorepo-go-example/internal/log"
+	"github.com/kevindiu/monorepo-go-example/internal/metrics"
+	"github.com/kevindiu/monorepo-go-example/internal/ratelimit"
+	"github.com/kevindiu/monorepo-go-example/internal/reporting"
+	"github.com/kevindiu/monorepo-go-example/internal/tracing"
+	"github.com/kevindiu/monorepo-go-example/pkg/server"
+	"github.com/kevindiu/monorepo-go-example/pkg/webhook/dispatcher"
+	"github.com/kevindiu/monorepo-go-example/pkg/webhook/repository"
+	"github.com/kevindiu/monorepo-go-example/pkg/webhook/service"
+	"google.golang.org/grpc"
+)
+
+func main() {
+	configFile := config.Flag()
+	check := doctor.Flag()
+	migrate := config.MigrateFlag()
+	flag.Parse()
+
+	// Load configuration
+	cfg, err := config.Open(*configFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load configuration: %v\n", err)
+		os.Exit(1)
+	}
+	if *migrate {
+		cfg.Database.MigrateOnStart = true
+	}
+
+	// Validate configuration and dependencies and exit in --check mode
+	if *check {
+		checks := []health.Checker{doctor.Config(cfg)}
+		checks = append(checks, doctor.Database(cfg.Database)...)
+		checks = append(checks, doctor.Events(cfg.Events)...)
+		os.Exit(doctor.Run(context.Background(), os.Stdout, "webhook-service", checks))
+	}
+
+	// Probe the running server and exit in healthcheck mode
+	if flag.Arg(0) == doctor.HealthcheckCommand {
+		os.Exit(doctor.Healthcheck(os.Stdout, doctor.GRPCProbe(cfg.Server, cfg.TLS)))
+	}
+
+	// Initialize logger
+	logCfg := &log.Config{
+		Level:  cfg.Log.Level,
+		Format: cfg.Log.Format,
+	}
+	logger, err := log.New(logCfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to initialize logger: %v\n", err)
+		os.Exit(1)
+	}
+	defer logger.Sync()
+	// Code without a request-scoped logger logs with this one
+	log.SetDefault(logger)
+
+	// Export traces when enabled
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Observability, "webhook-service")
+	if err != nil {
+		logger.Fatal("Failed to set up tracing", log.Error(err))
+	}
+	defer shutdownTracing(context.Background())
+
+	// Report internal errors and panics when configured
+	flushReports, err := reporting.Setup(cfg.Observability, "webhook-service")
+	if err != nil {
+		logger.Fatal("Failed to set up error reporting", log.Error(err))
+	}
+	defer flushReports()
+
+	// Authenticate API calls by bearer token when enabled
+	var authenticator *auth.Authenticator
+	if cfg.Auth.Enabled {
+		if authenticator, err = auth.New(cfg.Auth); err != nil {
+			logger.Fatal("Failed to set up authentication", log.Error(err))
+		}
+	}
+
+	// Secure gRPC connections with TLS when enabled
+	tlsStore, err := certs.FromConfig(cfg.TLS, logger)
+	if err != nil {
+		logger.Fatal("Failed to load TLS certificates", log.Error(err))
+	}
+
+	logger.Info("Starting webhook service",
+		log.String("version", "1.0.0"),
+		log.Int("grpc_port", cfg.Server.GRPCPort),
+		log.Int("http_port", cfg.Server.Port),
+	)
+
+	// Start components in dependency order. A component that fails to
+	// start stops those already started.
+	var (
+		database       *db.DB
+		bus            events.Bus
+		webhookRepo    repository.Repository
+		webhookService service.Service
+		d              *dispatcher.Dispatcher
+		recorder       *audit.Recorder
+	)
+	instrumentation := metrics.New(cfg.Observability)
+	lc := server.NewLifecycle(logger)
+	lc.Add(
+		server.Component{
+			Name: "database",
+			Start: func(context.Context) (err error) {
+				database, err = db.ConnectAndMigrate(cfg.Database)
+				return err
+			},
+			Stop: func(context.Context) error { return database.Close() },
+		},
+		server.Component{
+			Name: "events",
+			Start: func(context.Context) (err error) {
+				bus, err = events.New(cfg.Events, logger)
+				return err
+			},
+			Stop: func(context.Context) error { return bus.Close() },
+		},
+		server.Component{
+			Name:      "webhook",
+			DependsOn: []string{"database"},
+			Start: func(context.Context) error {
+				webhookRepo = repository.New(database)
+				webhookService = service.New(webhookRepo, logger)
+				return nil
+			},
+		},
+		server.Component{
+			Name:      "dispatcher",
+			DependsOn: []string{"webhook", "events"},
+			Start: func(context.Context) (err error) {
+				d, err = dispatcher.New(dispatcher.Config{
+					Repository:     webhookRepo,
+					Subscriber:     bus,
+					Topics:         cfg.Webhook.Topics,
+					Logger:         logger,
+					PollInterval:   cfg.Webhook.PollInterval,
+					BatchSize:      cfg.Webhook.BatchSize,
+					MaxAttempts:    cfg.Webhook.MaxAttempts,
+					InitialBackoff: cfg.Webhook.InitialBackoff,
+					MaxBackoff:     cfg.Webhook.MaxBackoff,
+					Timeout:        cfg.Webhook.Timeout,
+				})
+				return err
+			},
+		},
+	)
+	if cfg.Audit.Enabled {
+		// The recorder publishes to the bus, so it stops first
+		lc.Add(server.Component{
+			Name:      "audit-recorder",
+			DependsOn: []string{"events"},
+			Start: func(context.Context) error {
+				recorder = audit.NewRecorder(bus, cfg.Audit.Topic, logger)
+				return nil
+			},
+			Stop: func(context.Context) error {
+				recorder.Close()
+				return nil
+			},
+		})
+	}
+
+	// gRPC method quotas are shared through Redis when configured
+	var limiter *ratelimit.Limiter
+	if cfg.RateLimit.Enabled && len(cfg.RateLimit.Methods) > 0 {
+		lc.Add(server.Component{
+			Name: "rate-limit",
+			Start: func(context.Context) (err error) {
+				limiter, err = ratelimit.NewMethods(cfg.RateLimit, instrumentation.Registry, logger)
+				return err
+			},
+			Stop: func(context.Context) error { return limiter.Close() },
+		})
+	}
+
+	if err := lc.Start(context.Background()); err != nil {
+		logger.Fatal("Failed to start components", log.Error(err))
+	}
+
+	opts := []server.Option{
+		server.WithAuth(authenticator),
+		server.WithTLS(tlsStore),
+		server.WithRateLimit(limiter),
+		server.WithLifecycle(lc),
+	}
+
+	// Audit state-changing calls
+	var interceptors []grpc.UnaryServerInterceptor
+	if recorder != nil {
+		interceptors = append(interceptors, audit.UnaryServerInterceptor(recorder, "webhook-service"))
+	}
+
+	opts = append(opts,
+		server.WithGRPC(func(s *grpc.Server) {
+			webhookv1.RegisterWebhookServiceServer(s, webhookService)
+		}, interceptors...),
+		server.WithHTTPGateway(webhookv1.RegisterWebhookServiceHandlerFromEndpoint),
+		server.WithHealth(),
+		server.WithHealthCheck(
+			health.Checker{Name: "database", Check: database.PingContext, Critical: true},
+			health.Checker{Name: "migrations", Check: health.Migrations(database, db.SchemaVersion), Critical: true},
+		),
+		server.WithInstrumentation(instrumentation),
+		server.WithSLO(cfg.SLO),
+		server.WithRuntimeStats(cfg.Observability.RuntimeStatsInterval, database.DB),
+		server.WithChannelz(cfg.Observability.ChannelzEnabled),
+		server.WithLogLevelEndpoint(cfg.Log.LevelEndpoint),
+		server.WithConfigReload(*configFile, cfg),
+		server.WithDiagnostics(cfg),
+		// The dispatcher stops after the servers so that the in-flight
+		// delivery batch finishes
+		server.WithWorker("dispatcher", d.Run),
+	)
+	srv := server.New("webhook-service", cfg.Server, logger, opts...)
+	if err := srv.Run(context.Background()); err != nil {
+		logger.Error("Server failed", log.Error(err))
+	}
+}
@@ -0,0 +1,177 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package apikey authenticates service-to-service callers at the gateway
+// by API key
+package apikey
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+)
+
+// Header is the HTTP header that carries the API key
+const Header = "X-API-Key"
+
+// keyPrefix starts every key, so that leaked keys are easy to recognize
+const keyPrefix = "ak_"
+
+// prefixLength is how much of a key is kept to tell keys apart
+const prefixLength = 10
+
+// Key is an issued API key. The key itself is never stored; Prefix is its
+// start, which identifies it to people without authenticating anything.
+type Key struct {
+	ID        string     `json:"id"`
+	TenantID  string     `json:"tenant_id"`
+	Name      string     `json:"name"`
+	Prefix    string     `json:"prefix"`
+	Scopes    []string   `json:"scopes"`
+	CreatedAt time.Time  `json:"created_at"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+}
+
+// Active reports whether the key authenticates requests at now
+func (k *Key) Active(now time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || now.Before(*k.ExpiresAt))
+}
+
+// Store persists API keys by the hash of the key. Revoke and Lookup return
+// an error with errors.CodeNotFound for unknown keys, and Revoke for keys
+// already revoked.
+type Store interface {
+	Create(ctx context.Context, k *Key, hash string) error
+	List(ctx context.Context, tenantID string) ([]*Key, error)
+	Revoke(ctx context.Context, tenantID, id string, at time.Time) error
+	Lookup(ctx context.Context, hash string) (*Key, error)
+}
+
+// Generate returns a new random key with its prefix and hash
+func Generate() (key, prefix, hash string, err error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", "", errors.Wrap(err, "failed to generate API key")
+	}
+	key = keyPrefix + base64.RawURLEncoding.EncodeToString(b)
+	return key, key[:prefixLength], Hash(key), nil
+}
+
+// Hash returns the hex SHA-256 hash a key is stored under
+func Hash(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// errInvalidKey is returned for every key that does not authenticate,
+// whatever the reason
+var errInvalidKey = errors.WithCode(errors.New("API key is invalid, expired or revoked"), errors.CodeUnauthorized)
+
+type cachedKey struct {
+	key     *Key
+	expires time.Time
+}
+
+// Validator resolves API keys to the keys they were issued as. Lookups are
+// remembered for a while, so a revoked key keeps working until its cache
+// entry expires.
+type Validator struct {
+	store Store
+	ttl   time.Duration
+	now   func() time.Time
+
+	mu    sync.Mutex
+	cache map[string]cachedKey
+}
+
+// NewValidator creates a validator of the keys in store, caching each
+// lookup for ttl; a zero ttl looks every key up
+func NewValidator(store Store, ttl time.Duration) *Validator {
+	return &Validator{
+		store: store,
+		ttl:   ttl,
+		now:   time.Now,
+		cache: make(map[string]cachedKey),
+	}
+}
+
+// Validate returns the active key for key. Unknown, expired and revoked
+// keys are unauthorized.
+func (v *Validator) Validate(ctx context.Context, key string) (*Key, error) {
+	if key == "" {
+		return nil, errors.WithCode(errors.New("API key is required"), errors.CodeUnauthorized)
+	}
+
+	now := v.now()
+	hash := Hash(key)
+	v.mu.Lock()
+	cached, ok := v.cache[hash]
+	if ok && !now.Before(cached.expires) {
+		delete(v.cache, hash)
+		ok = false
+	}
+	v.mu.Unlock()
+
+	k := cached.key
+	if !ok {
+		var err error
+		if k, err = v.store.Lookup(ctx, hash); err != nil {
+			if errors.GetCode(err) == errors.CodeNotFound {
+				return nil, errInvalidKey
+			}
+			return nil, err
+		}
+		// Only keys that exist are cached, so that made-up keys cannot
+		// grow the cache
+		if v.ttl > 0 {
+			v.mu.Lock()
+			v.cache[hash] = cachedKey{key: k, expires: now.Add(v.ttl)}
+			v.mu.Unlock()
+		}
+	}
+
+	if !k.Active(now) {
+		return nil, errInvalidKey
+	}
+	return k, nil
+}
+
+// Subject returns the subject of the tokens issued for a key, which cannot
+// be mistaken for a user ID
+func Subject(k *Key) string {
+	return "apikey:" + k.ID
+}
+
+type keyKey struct{}
+
+// WithKey returns a context carrying the key that authenticated a request
+func WithKey(ctx context.Context, k *Key) context.Context {
+	return context.WithValue(ctx, keyKey{}, k)
+}
+
+// FromContext returns the key that authenticated the request the context
+// belongs to, if any
+func FromContext(ctx context.Context) (*Key, bool) {
+	k, ok := ctx.Value(keyKey{}).(*Key)
+	return k, ok && k != nil
+}
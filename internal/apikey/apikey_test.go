@@ -0,0 +1,203 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package apikey
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/internal/tenant"
+)
+
+// mockStore keeps keys in memory
+type mockStore struct {
+	keys    map[string]*Key
+	lookups int
+}
+
+func newMockStore() *mockStore {
+	return &mockStore{keys: make(map[string]*Key)}
+}
+
+func (m *mockStore) Create(ctx context.Context, k *Key, hash string) error {
+	m.keys[hash] = k
+	return nil
+}
+
+func (m *mockStore) List(ctx context.Context, tenantID string) ([]*Key, error) {
+	var keys []*Key
+	for _, k := range m.keys {
+		if k.TenantID == tenantID {
+			keys = append(keys, k)
+		}
+	}
+	return keys, nil
+}
+
+func (m *mockStore) Revoke(ctx context.Context, tenantID, id string, at time.Time) error {
+	for _, k := range m.keys {
+		if k.TenantID == tenantID && k.ID == id && k.RevokedAt == nil {
+			k.RevokedAt = &at
+			return nil
+		}
+	}
+	return errors.WithCode(errors.New("API key not found"), errors.CodeNotFound)
+}
+
+func (m *mockStore) Lookup(ctx context.Context, hash string) (*Key, error) {
+	m.lookups++
+	k, ok := m.keys[hash]
+	if !ok {
+		return nil, errors.WithCode(errors.New("API key not found"), errors.CodeNotFound)
+	}
+	copied := *k
+	return &copied, nil
+}
+
+// addKey generates a key of the acme tenant with scopes and stores it
+func addKey(t *testing.T, store *mockStore, id string, scopes ...string) string {
+	t.Helper()
+	key, prefix, hash, err := Generate()
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	store.Create(context.Background(), &Key{ID: id, TenantID: "acme", Prefix: prefix, Scopes: scopes}, hash)
+	return key
+}
+
+func TestGenerate(t *testing.T) {
+	key, prefix, hash, err := Generate()
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if !strings.HasPrefix(key, keyPrefix) || !strings.HasPrefix(key, prefix) || len(prefix) != prefixLength {
+		t.Errorf("Generate() = %q with prefix %q", key, prefix)
+	}
+	if hash != Hash(key) || strings.Contains(hash, key) {
+		t.Errorf("Generate() hash = %q, want the hash of the key", hash)
+	}
+	if other, _, _, _ := Generate(); other == key {
+		t.Error("Generate() returned the same key twice")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	store := newMockStore()
+	v := NewValidator(store, time.Minute)
+	now := time.Now()
+	v.now = func() time.Time { return now }
+
+	valid := addKey(t, store, "key-1", "service")
+	expired := addKey(t, store, "key-2")
+	past := now.Add(-time.Second)
+	store.keys[Hash(expired)].ExpiresAt = &past
+
+	k, err := v.Validate(context.Background(), valid)
+	if err != nil || k.ID != "key-1" {
+		t.Fatalf("Validate() = %+v, %v; want key-1", k, err)
+	}
+
+	for name, key := range map[string]string{"empty": "", "unknown": "ak_bogus", "expired": expired} {
+		if _, err := v.Validate(context.Background(), key); errors.GetCode(err) != errors.CodeUnauthorized {
+			t.Errorf("Validate(%s) error = %v, want unauthorized", name, err)
+		}
+	}
+
+	// Lookups are cached until the TTL passes, revocations included
+	lookups := store.lookups
+	store.Revoke(context.Background(), "acme", "key-1", now)
+	if _, err := v.Validate(context.Background(), valid); err != nil || store.lookups != lookups {
+		t.Fatalf("Validate() = %v after %d lookups, want the cached key", err, store.lookups-lookups)
+	}
+	now = now.Add(time.Minute)
+	if _, err := v.Validate(context.Background(), valid); errors.GetCode(err) != errors.CodeUnauthorized {
+		t.Errorf("Validate() error = %v for a revoked key, want unauthorized", err)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	store := newMockStore()
+	key := addKey(t, store, "key-1", "orders.read", "service")
+	v := NewValidator(store, 0)
+
+	var got *Key
+	var gotTenant string
+	var gotScopes []string
+	handler := Middleware(v, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, _ = FromContext(r.Context())
+		gotTenant = r.Header.Get(tenant.Header)
+		gotScopes = Metadata(r.Context(), r).Get(ScopesMetadataKey)
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		name     string
+		header   map[string]string
+		wantCode int
+		wantKey  string
+	}{
+		{name: "no key", wantCode: http.StatusOK},
+		{name: "valid key", header: map[string]string{Header: key}, wantCode: http.StatusOK, wantKey: "key-1"},
+		{name: "invalid key", header: map[string]string{Header: "ak_bogus"}, wantCode: http.StatusUnauthorized},
+		{name: "bearer token wins", header: map[string]string{Header: "ak_bogus", "Authorization": "Bearer x"}, wantCode: http.StatusOK},
+		{name: "tenant mismatch", header: map[string]string{Header: key, tenant.Header: "other"}, wantCode: http.StatusForbidden},
+		{name: "spoofed scopes", header: map[string]string{"Grpc-Metadata-X-Api-Key-Scopes": "admin"}, wantCode: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotScopes = nil, nil
+			req := httptest.NewRequest(http.MethodGet, "/v1/orders", nil)
+			for k, v := range tt.header {
+				req.Header.Set(k, v)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantCode)
+			}
+			if rec.Code != http.StatusOK {
+				var body errors.HTTPBody
+				if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || body.Code == "" {
+					t.Errorf("error body = %s, want {code, message, details}", rec.Body.String())
+				}
+			}
+			if req.Header.Get("Grpc-Metadata-X-Api-Key-Scopes") != "" {
+				t.Error("spoofed scopes header forwarded")
+			}
+			if tt.wantKey == "" {
+				if got != nil || gotScopes != nil {
+					t.Errorf("key = %+v, scopes = %v; want none", got, gotScopes)
+				}
+				return
+			}
+			if got == nil || got.ID != tt.wantKey || gotTenant != "acme" {
+				t.Errorf("key = %+v, tenant = %q; want %s of acme", got, gotTenant, tt.wantKey)
+			}
+			if strings.Join(gotScopes, ",") != "orders.read,service" {
+				t.Errorf("scopes metadata = %v, want the key's scopes", gotScopes)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package apikey
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/internal/tenant"
+	"google.golang.org/grpc/metadata"
+)
+
+// Metadata keys identifying the API key of calls to the backends
+const (
+	IDMetadataKey     = "x-api-key-id"
+	ScopesMetadataKey = "x-api-key-scopes"
+)
+
+// Middleware authenticates requests carrying an X-API-Key header and puts
+// the key on the request context. Requests with an Authorization header are
+// left to bearer token authentication, and requests with neither pass
+// through unauthenticated. A key binds its request to the key's tenant.
+func Middleware(v *Validator, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Key metadata is only ever derived from the key
+		r.Header.Del(runtime.MetadataHeaderPrefix + IDMetadataKey)
+		r.Header.Del(runtime.MetadataHeaderPrefix + ScopesMetadataKey)
+
+		key := r.Header.Get(Header)
+		if key == "" || r.Header.Get("Authorization") != "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		k, err := v.Validate(r.Context(), key)
+		if err != nil {
+			errors.WriteHTTP(w, err)
+			return
+		}
+
+		if requested := r.Header.Get(tenant.Header); requested != "" && requested != k.TenantID {
+			errors.WriteHTTP(w, errors.WithCode(errors.New("API key does not belong to the requested tenant"), errors.CodeForbidden))
+			return
+		}
+		r.Header.Set(tenant.Header, k.TenantID)
+
+		next.ServeHTTP(w, r.WithContext(WithKey(r.Context(), k)))
+	})
+}
+
+// Metadata is a grpc-gateway metadata annotator that forwards the key and
+// scopes of an authenticated request to the backends
+func Metadata(ctx context.Context, r *http.Request) metadata.MD {
+	k, ok := FromContext(r.Context())
+	if !ok {
+		return nil
+	}
+	md := metadata.Pairs(IDMetadataKey, k.ID)
+	md.Append(ScopesMetadataKey, k.Scopes...)
+	return md
+}
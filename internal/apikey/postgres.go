@@ -0,0 +1,136 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package apikey
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/db"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/lib/pq"
+)
+
+// PostgresStore keeps API keys in the api_keys table
+type PostgresStore struct {
+	db *db.DB
+}
+
+// NewPostgresStore creates a Postgres-backed API key store
+func NewPostgresStore(database *db.DB) *PostgresStore {
+	return &PostgresStore{db: database}
+}
+
+const keyColumns = `id, tenant_id, name, prefix, scopes, created_at, expires_at, revoked_at`
+
+// scanKey scans a row of keyColumns
+func scanKey(row interface{ Scan(...interface{}) error }) (*Key, error) {
+	var (
+		k                    Key
+		expiresAt, revokedAt sql.NullTime
+	)
+	if err := row.Scan(&k.ID, &k.TenantID, &k.Name, &k.Prefix, pq.Array(&k.Scopes), &k.CreatedAt, &expiresAt, &revokedAt); err != nil {
+		return nil, err
+	}
+	if expiresAt.Valid {
+		k.ExpiresAt = &expiresAt.Time
+	}
+	if revokedAt.Valid {
+		k.RevokedAt = &revokedAt.Time
+	}
+	return &k, nil
+}
+
+// Create stores a new key under the hash of the key
+func (p *PostgresStore) Create(ctx context.Context, k *Key, hash string) error {
+	query := `
+		INSERT INTO api_keys (id, tenant_id, name, prefix, key_hash, scopes, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	_, err := p.db.ExecContext(ctx, query,
+		k.ID,
+		k.TenantID,
+		k.Name,
+		k.Prefix,
+		hash,
+		pq.Array(k.Scopes),
+		k.CreatedAt,
+		k.ExpiresAt,
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to create API key")
+	}
+	return nil
+}
+
+// List returns the keys of a tenant, newest first
+func (p *PostgresStore) List(ctx context.Context, tenantID string) ([]*Key, error) {
+	query := `SELECT ` + keyColumns + ` FROM api_keys WHERE tenant_id = $1 ORDER BY created_at DESC`
+
+	rows, err := p.db.QueryContext(ctx, query, tenantID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list API keys")
+	}
+	defer rows.Close()
+
+	var keys []*Key
+	for rows.Next() {
+		k, err := scanKey(rows)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to scan API key")
+		}
+		keys = append(keys, k)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "failed to list API keys")
+	}
+	return keys, nil
+}
+
+// Revoke marks a key of a tenant revoked
+func (p *PostgresStore) Revoke(ctx context.Context, tenantID, id string, at time.Time) error {
+	query := `UPDATE api_keys SET revoked_at = $3 WHERE tenant_id = $1 AND id = $2 AND revoked_at IS NULL`
+
+	result, err := p.db.ExecContext(ctx, query, tenantID, id, at)
+	if err != nil {
+		return errors.Wrap(err, "failed to revoke API key")
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "failed to get affected rows")
+	}
+	if rows == 0 {
+		return errors.WithCode(errors.New("API key not found"), errors.CodeNotFound)
+	}
+	return nil
+}
+
+// Lookup retrieves a key by the hash of the key
+func (p *PostgresStore) Lookup(ctx context.Context, hash string) (*Key, error) {
+	query := `SELECT ` + keyColumns + ` FROM api_keys WHERE key_hash = $1`
+
+	k, err := scanKey(p.db.QueryRowContext(ctx, query, hash))
+	if err == sql.ErrNoRows {
+		return nil, errors.WithCode(errors.New("API key not found"), errors.CodeNotFound)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to look up API key")
+	}
+	return k, nil
+}
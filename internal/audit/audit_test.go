@@ -0,0 +1,131 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package audit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	orderv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/order/v1"
+	"github.com/kevindiu/monorepo-go-example/internal/auth"
+	"github.com/kevindiu/monorepo-go-example/internal/events"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestHash(t *testing.T) {
+	e := NewEvent("order-service", "u1", "/order.v1.OrderService/CancelOrder", "order", "o1", OutcomeSuccess)
+	h := Hash("", e)
+
+	if Hash("", e) != h {
+		t.Error("Hash() is not deterministic")
+	}
+	if Hash("other", e) == h {
+		t.Error("Hash() ignores the previous hash")
+	}
+
+	tampered := *e
+	tampered.Actor = "u2"
+	if Hash("", &tampered) == h {
+		t.Error("Hash() ignores the actor")
+	}
+
+	// The same instant in another zone hashes identically
+	local := *e
+	local.OccurredAt = e.OccurredAt.In(time.FixedZone("JST", 9*3600))
+	if Hash("", &local) != h {
+		t.Error("Hash() depends on the time zone")
+	}
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	bus := events.NewMemoryBus()
+	defer bus.Close()
+
+	received := make(chan *Event, 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go bus.Subscribe(ctx, Topic, "test", func(ctx context.Context, event *events.Event) error {
+		var e Event
+		if err := event.Decode(&e); err != nil {
+			return err
+		}
+		received <- &e
+		return nil
+	})
+	// Give the subscription time to register
+	time.Sleep(10 * time.Millisecond)
+
+	recorder := NewRecorder(bus, Topic, nil)
+	interceptor := UnaryServerInterceptor(recorder, "order-service")
+
+	call := func(ctx context.Context, method string, req interface{}, resp interface{}, err error) {
+		t.Helper()
+		info := &grpc.UnaryServerInfo{FullMethod: method}
+		interceptor(ctx, req, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return resp, err
+		})
+	}
+
+	actorCtx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(ActorMetadataKey, "admin"))
+
+	call(actorCtx, "/order.v1.OrderService/GetOrder", &orderv1.GetOrderRequest{Id: "o1"}, &orderv1.GetOrderResponse{}, nil)
+	call(actorCtx, "/order.v1.OrderService/CreateOrder", &orderv1.CreateOrderRequest{UserId: "u1"},
+		&orderv1.CreateOrderResponse{Order: &orderv1.Order{Id: "o2"}}, nil)
+	call(WithActor(context.Background(), "u1"), "/order.v1.OrderService/CancelOrder", &orderv1.CancelOrderRequest{Id: "o3"},
+		(*orderv1.CancelOrderResponse)(nil), errors.New("boom"))
+	call(auth.WithClaims(context.Background(), &auth.Claims{Subject: "svc-1"}), "/order.v1.OrderService/UpdateOrderStatus",
+		&orderv1.UpdateOrderStatusRequest{Id: "o4"}, &orderv1.UpdateOrderStatusResponse{Order: &orderv1.Order{Id: "o4"}}, nil)
+
+	// Authenticated callers cannot name another actor, unless they are a
+	// trusted service, which is recorded along with its principal
+	spoofed := auth.WithClaims(actorCtx, &auth.Claims{Subject: "user-2"})
+	call(spoofed, "/order.v1.OrderService/CancelOrder", &orderv1.CancelOrderRequest{Id: "o5"}, &orderv1.CancelOrderResponse{}, nil)
+	relayed := auth.WithClaims(actorCtx, &auth.Claims{Subject: "shipping-service", Roles: []string{auth.RoleService}})
+	call(relayed, "/order.v1.OrderService/CancelOrder", &orderv1.CancelOrderRequest{Id: "o6"}, &orderv1.CancelOrderResponse{}, nil)
+
+	recorder.Close()
+
+	want := []struct {
+		actor, onBehalfOf, resourceID, outcome string
+	}{
+		{actor: "admin", resourceID: "o2", outcome: OutcomeSuccess},
+		{actor: "u1", resourceID: "o3", outcome: OutcomeFailure},
+		{actor: "svc-1", resourceID: "o4", outcome: OutcomeSuccess},
+		{actor: "user-2", resourceID: "o5", outcome: OutcomeSuccess},
+		{actor: "shipping-service", onBehalfOf: "admin", resourceID: "o6", outcome: OutcomeSuccess},
+	}
+
+	for i, w := range want {
+		select {
+		case e := <-received:
+			if e.Actor != w.actor || e.Metadata[OnBehalfOfKey] != w.onBehalfOf || e.ResourceID != w.resourceID || e.Outcome != w.outcome || e.ResourceType != "order" {
+				t.Errorf("event %d = %+v, want %+v", i, e, w)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("event %d was not published", i)
+		}
+	}
+
+	select {
+	case e := <-received:
+		t.Errorf("unexpected event %+v; reads must not be audited", e)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
@@ -0,0 +1,157 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package audit
+
+import (
+	"context"
+	"path"
+	"strings"
+
+	"github.com/kevindiu/monorepo-go-example/internal/auth"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// ActorMetadataKey is the incoming metadata key naming the principal a call
+// is made on behalf of. See Principal for when it is honored.
+const ActorMetadataKey = "x-actor-id"
+
+// OnBehalfOfKey is the event metadata key recording the principal a trusted
+// service acted on behalf of
+const OnBehalfOfKey = "on_behalf_of"
+
+// readPrefixes mark RPCs that do not change state and are not audited
+var readPrefixes = []string{"Get", "List", "Search", "Watch", "Check", "Export"}
+
+// UnaryServerInterceptor records an audit event for every state-changing RPC
+// handled by service
+func UnaryServerInterceptor(recorder *Recorder, service string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		method := path.Base(info.FullMethod)
+		if isRead(method) {
+			return handler(ctx, req)
+		}
+
+		resp, err := handler(ctx, req)
+
+		outcome := OutcomeSuccess
+		if err != nil {
+			outcome = OutcomeFailure
+		}
+
+		actor, onBehalfOf := Principal(ctx)
+		event := NewEvent(service, actor, info.FullMethod, resourceType(info.FullMethod), resourceID(req, resp), outcome)
+		if onBehalfOf != "" {
+			event.Metadata = map[string]string{OnBehalfOfKey: onBehalfOf}
+		}
+		if err != nil {
+			if event.Metadata == nil {
+				event.Metadata = map[string]string{}
+			}
+			event.Metadata["code"] = status.Code(err).String()
+		}
+		recorder.Record(event)
+
+		return resp, err
+	}
+}
+
+func isRead(method string) bool {
+	for _, prefix := range readPrefixes {
+		if strings.HasPrefix(method, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Principal returns the actor a call is attributed to, empty when unknown,
+// and the principal it was made on behalf of. Authenticated calls are
+// always attributed to the subject of their token. A caller holding
+// auth.RoleService may name the principal it acts for, with WithActor or
+// in ActorMetadataKey, and both are returned; other authenticated callers
+// cannot. Unauthenticated calls, made when auth is disabled, are
+// attributed to the actor they name.
+func Principal(ctx context.Context) (actor, onBehalfOf string) {
+	named := namedActor(ctx)
+	claims, ok := auth.FromContext(ctx)
+	if !ok || claims.Subject == "" {
+		return named, ""
+	}
+	if claims.HasRole(auth.RoleService) && named != claims.Subject {
+		return claims.Subject, named
+	}
+	return claims.Subject, ""
+}
+
+// namedActor returns the actor put on the context or sent in the incoming
+// metadata
+func namedActor(ctx context.Context) string {
+	if a := ActorFromContext(ctx); a != "" {
+		return a
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(ActorMetadataKey); len(values) > 0 {
+			return values[0]
+		}
+	}
+	return ""
+}
+
+// resourceType derives the resource from the service name, so
+// "/order.v1.OrderService/CancelOrder" becomes "order"
+func resourceType(fullMethod string) string {
+	svc := strings.TrimPrefix(path.Dir(fullMethod), "/")
+	if i := strings.Index(svc, "."); i > 0 {
+		return svc[:i]
+	}
+	return svc
+}
+
+type idGetter interface {
+	GetId() string
+}
+
+// resourceID takes the ID from the request, or from the created resource
+// in the response when the request has none
+func resourceID(req, resp interface{}) string {
+	if r, ok := req.(idGetter); ok && r.GetId() != "" {
+		return r.GetId()
+	}
+
+	// Create responses wrap the resource, e.g. CreateOrderResponse.Order
+	msg, ok := resp.(proto.Message)
+	if !ok || !msg.ProtoReflect().IsValid() {
+		return ""
+	}
+
+	var id string
+	msg.ProtoReflect().Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if fd.Kind() != protoreflect.MessageKind || fd.IsList() || fd.IsMap() {
+			return true
+		}
+		if r, ok := v.Message().Interface().(idGetter); ok && r.GetId() != "" {
+			id = r.GetId()
+			return false
+		}
+		return true
+	})
+	return id
+}
@@ -0,0 +1,108 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package audit
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/events"
+	"github.com/kevindiu/monorepo-go-example/internal/log"
+)
+
+// DefaultBufferSize is the number of audit events queued for publishing
+const DefaultBufferSize = 1024
+
+// Recorder publishes audit events to the event bus in the background so
+// that auditing never adds broker latency to a request
+type Recorder struct {
+	publisher events.Publisher
+	topic     string
+	logger    *log.Logger
+
+	queue chan *Event
+	wg    sync.WaitGroup
+	once  sync.Once
+}
+
+// NewRecorder creates a recorder publishing to topic and starts its
+// background publisher
+func NewRecorder(publisher events.Publisher, topic string, logger *log.Logger) *Recorder {
+	if topic == "" {
+		topic = Topic
+	}
+	if logger == nil {
+		logger = log.NewDefault()
+	}
+
+	r := &Recorder{
+		publisher: publisher,
+		topic:     topic,
+		logger:    logger.Named("audit"),
+		queue:     make(chan *Event, DefaultBufferSize),
+	}
+
+	r.wg.Add(1)
+	go r.run()
+
+	return r
+}
+
+// Record queues an event for publishing. Events are dropped with a warning
+// when the queue is full rather than blocking the caller.
+func (r *Recorder) Record(e *Event) {
+	select {
+	case r.queue <- e:
+	default:
+		r.logger.Warn("Audit queue full, dropping event",
+			log.String("action", e.Action),
+			log.String("actor", e.Actor),
+		)
+	}
+}
+
+// Close stops accepting events and flushes the queue
+func (r *Recorder) Close() {
+	r.once.Do(func() {
+		close(r.queue)
+		r.wg.Wait()
+	})
+}
+
+func (r *Recorder) run() {
+	defer r.wg.Done()
+
+	for e := range r.queue {
+		event, err := events.NewEvent(EventType, e.ResourceID, e)
+		if err != nil {
+			r.logger.Error("Failed to encode audit event", log.Error(err))
+			continue
+		}
+		// Reuse the audit ID so consumers can deduplicate redeliveries
+		event.ID = e.ID
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		if err := r.publisher.Publish(ctx, r.topic, event); err != nil {
+			r.logger.Error("Failed to publish audit event",
+				log.String("action", e.Action),
+				log.Error(err),
+			)
+		}
+		cancel()
+	}
+}
@@ -0,0 +1,269 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package auth issues and validates the JWT bearer tokens that authenticate
+// API calls, and puts the claims of an authenticated call on its context.
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/config"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+)
+
+// algorithm is the only signing algorithm tokens are issued and accepted with
+const algorithm = "HS256"
+
+// header is the encoded JOSE header of every issued token
+var header = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+// RoleAdmin is the role that operator endpoints require
+const RoleAdmin = "admin"
+
+// RoleCustomer is the role users are created with
+const RoleCustomer = "customer"
+
+// RoleService is the role of the tokens services call each other with. Only
+// its holders may name another principal they act on behalf of.
+const RoleService = "service"
+
+// Claims are the claims of a token. Times are seconds since the epoch.
+type Claims struct {
+	Subject   string   `json:"sub"`
+	TenantID  string   `json:"tenant_id,omitempty"`
+	Roles     []string `json:"roles,omitempty"`
+	Issuer    string   `json:"iss,omitempty"`
+	Audience  string   `json:"aud,omitempty"`
+	IssuedAt  int64    `json:"iat,omitempty"`
+	NotBefore int64    `json:"nbf,omitempty"`
+	ExpiresAt int64    `json:"exp"`
+	ID        string   `json:"jti,omitempty"`
+}
+
+// HasRole reports whether the claims grant role
+func (c *Claims) HasRole(role string) bool {
+	for _, r := range c.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// Authenticator issues and validates tokens
+type Authenticator struct {
+	keys          [][]byte
+	issuer        string
+	audience      string
+	ttl           time.Duration
+	leeway        time.Duration
+	publicPaths   []string
+	publicMethods []string
+	policies      []config.AuthPolicy
+	now           func() time.Time
+}
+
+// New creates an authenticator from the auth configuration
+func New(cfg *config.Auth) (*Authenticator, error) {
+	if cfg == nil || cfg.Secret == "" {
+		return nil, errors.New("auth secret is required")
+	}
+	if cfg.TokenTTL <= 0 {
+		return nil, errors.New("auth token TTL must be positive")
+	}
+
+	keys := [][]byte{[]byte(cfg.Secret)}
+	for _, secret := range cfg.PreviousSecrets {
+		keys = append(keys, []byte(secret))
+	}
+
+	return &Authenticator{
+		keys:          keys,
+		issuer:        cfg.Issuer,
+		audience:      cfg.Audience,
+		ttl:           cfg.TokenTTL,
+		leeway:        cfg.Leeway,
+		publicPaths:   cfg.PublicPaths,
+		publicMethods: cfg.PublicMethods,
+		policies:      cfg.Policies,
+		now:           time.Now,
+	}, nil
+}
+
+// TTL returns how long issued tokens are valid
+func (a *Authenticator) TTL() time.Duration {
+	return a.ttl
+}
+
+// Issue signs a token for claims. The issuer, audience, issue time, expiry
+// and ID are filled in unless claims sets them.
+func (a *Authenticator) Issue(claims Claims) (string, error) {
+	if claims.Subject == "" {
+		return "", errors.WithCode(errors.New("token subject is required"), errors.CodeInvalidInput)
+	}
+
+	now := a.now()
+	if claims.Issuer == "" {
+		claims.Issuer = a.issuer
+	}
+	if claims.Audience == "" {
+		claims.Audience = a.audience
+	}
+	if claims.IssuedAt == 0 {
+		claims.IssuedAt = now.Unix()
+	}
+	if claims.ExpiresAt == 0 {
+		claims.ExpiresAt = now.Add(a.ttl).Unix()
+	}
+	if claims.ID == "" {
+		id := make([]byte, 16)
+		if _, err := rand.Read(id); err != nil {
+			return "", errors.Wrap(err, "failed to generate token ID")
+		}
+		claims.ID = hex.EncodeToString(id)
+	}
+
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to encode token claims")
+	}
+
+	signed := header + "." + base64.RawURLEncoding.EncodeToString(payload)
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sign(a.keys[0], signed)), nil
+}
+
+// Validate checks the signature and the time, issuer and audience claims of
+// token and returns its claims. Invalid tokens return an error with
+// errors.CodeUnauthorized.
+func (a *Authenticator) Validate(token string) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, unauthorized("malformed token")
+	}
+
+	var hdr struct {
+		Alg string `json:"alg"`
+	}
+	if err := decodeSegment(parts[0], &hdr); err != nil {
+		return nil, unauthorized("malformed token header")
+	}
+	if hdr.Alg != algorithm {
+		return nil, unauthorized("unsupported token algorithm")
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || !a.verify(parts[0]+"."+parts[1], signature) {
+		return nil, unauthorized("invalid token signature")
+	}
+
+	var claims Claims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, unauthorized("malformed token claims")
+	}
+
+	now := a.now()
+	switch {
+	case claims.Subject == "":
+		return nil, unauthorized("token has no subject")
+	case claims.ExpiresAt == 0 || now.After(time.Unix(claims.ExpiresAt, 0).Add(a.leeway)):
+		return nil, unauthorized("token expired")
+	case claims.NotBefore != 0 && now.Add(a.leeway).Before(time.Unix(claims.NotBefore, 0)):
+		return nil, unauthorized("token not yet valid")
+	case a.issuer != "" && claims.Issuer != a.issuer:
+		return nil, unauthorized("token issuer not accepted")
+	case a.audience != "" && claims.Audience != a.audience:
+		return nil, unauthorized("token audience not accepted")
+	}
+
+	return &claims, nil
+}
+
+// PublicPath reports whether the HTTP route path is served without a token
+func (a *Authenticator) PublicPath(path string) bool {
+	return matchAny(a.publicPaths, path)
+}
+
+// PublicMethod reports whether the full gRPC method name is served without
+// a token
+func (a *Authenticator) PublicMethod(method string) bool {
+	return matchAny(a.publicMethods, method)
+}
+
+// verify reports whether signature signs signed with any of the keys
+func (a *Authenticator) verify(signed string, signature []byte) bool {
+	for _, key := range a.keys {
+		if hmac.Equal(signature, sign(key, signed)) {
+			return true
+		}
+	}
+	return false
+}
+
+type claimsKey struct{}
+
+// WithClaims returns a context carrying the claims of an authenticated call
+func WithClaims(ctx context.Context, claims *Claims) context.Context {
+	return context.WithValue(ctx, claimsKey{}, claims)
+}
+
+// FromContext returns the claims of the call the context belongs to, if it
+// was authenticated
+func FromContext(ctx context.Context) (*Claims, bool) {
+	claims, ok := ctx.Value(claimsKey{}).(*Claims)
+	return claims, ok && claims != nil
+}
+
+// matchAny reports whether s equals one of patterns, or starts with the
+// prefix of one ending in "*"
+func matchAny(patterns []string, s string) bool {
+	for _, pattern := range patterns {
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+			if strings.HasPrefix(s, prefix) {
+				return true
+			}
+		} else if s == pattern {
+			return true
+		}
+	}
+	return false
+}
+
+func sign(key []byte, signed string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(signed))
+	return mac.Sum(nil)
+}
+
+func decodeSegment(segment string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+func unauthorized(msg string) error {
+	return errors.WithCode(errors.New(msg), errors.CodeUnauthorized)
+}
@@ -0,0 +1,416 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package auth
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/config"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/internal/log"
+	"github.com/kevindiu/monorepo-go-example/internal/tenant"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const testSecret = "0123456789abcdef0123456789abcdef"
+
+func newTestAuthenticator(t *testing.T, mutate func(cfg *config.Auth)) *Authenticator {
+	t.Helper()
+	cfg := &config.Auth{
+		Secret:        testSecret,
+		Issuer:        "test",
+		Audience:      "api",
+		TokenTTL:      time.Minute,
+		Leeway:        time.Second,
+		PublicPaths:   []string{"/v1/public/*"},
+		PublicMethods: []string{"/grpc.health.v1.Health/*"},
+	}
+	if mutate != nil {
+		mutate(cfg)
+	}
+	a, err := New(cfg)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	return a
+}
+
+func TestIssueAndValidate(t *testing.T) {
+	a := newTestAuthenticator(t, nil)
+	token, err := a.Issue(Claims{Subject: "user-1", TenantID: "acme", Roles: []string{"admin"}})
+	if err != nil {
+		t.Fatalf("Issue() error = %v", err)
+	}
+
+	claims, err := a.Validate(token)
+	if err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if claims.Subject != "user-1" || claims.TenantID != "acme" || !claims.HasRole("admin") {
+		t.Errorf("Validate() claims = %+v", claims)
+	}
+	if claims.Issuer != "test" || claims.Audience != "api" || claims.ID == "" {
+		t.Errorf("Issue() did not fill in the registered claims: %+v", claims)
+	}
+
+	if _, err := a.Issue(Claims{}); err == nil {
+		t.Error("Issue() without a subject should fail")
+	}
+}
+
+func TestValidateRejects(t *testing.T) {
+	a := newTestAuthenticator(t, nil)
+	valid, _ := a.Issue(Claims{Subject: "user-1"})
+	parts := strings.Split(valid, ".")
+
+	other := newTestAuthenticator(t, func(cfg *config.Auth) { cfg.Secret = strings.Repeat("x", 32) })
+	foreign, _ := other.Issue(Claims{Subject: "user-1"})
+
+	otherAudience := newTestAuthenticator(t, func(cfg *config.Auth) { cfg.Audience = "admin" })
+	wrongAudience, _ := otherAudience.Issue(Claims{Subject: "user-1"})
+
+	expired, _ := a.Issue(Claims{Subject: "user-1", ExpiresAt: time.Now().Add(-time.Minute).Unix()})
+	notYetValid, _ := a.Issue(Claims{Subject: "user-1", NotBefore: time.Now().Add(time.Minute).Unix()})
+
+	tampered := parts[0] + "." + base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"admin","exp":9999999999}`)) + "." + parts[2]
+	unsigned := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none"}`)) + "." + parts[1] + "."
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{name: "empty", token: ""},
+		{name: "malformed", token: "not-a-token"},
+		{name: "other secret", token: foreign},
+		{name: "other audience", token: wrongAudience},
+		{name: "expired", token: expired},
+		{name: "not yet valid", token: notYetValid},
+		{name: "tampered claims", token: tampered},
+		{name: "unsigned", token: unsigned},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := a.Validate(tt.token)
+			if errors.GetCode(err) != errors.CodeUnauthorized {
+				t.Errorf("Validate() error = %v, want an unauthorized error", err)
+			}
+		})
+	}
+}
+
+func TestValidateAcceptsPreviousSecrets(t *testing.T) {
+	old := newTestAuthenticator(t, nil)
+	token, _ := old.Issue(Claims{Subject: "user-1"})
+
+	rotated := newTestAuthenticator(t, func(cfg *config.Auth) {
+		cfg.Secret = strings.Repeat("n", 32)
+		cfg.PreviousSecrets = []string{testSecret}
+	})
+	if _, err := rotated.Validate(token); err != nil {
+		t.Errorf("Validate() with a previous secret error = %v", err)
+	}
+}
+
+func TestPublic(t *testing.T) {
+	a := newTestAuthenticator(t, func(cfg *config.Auth) { cfg.PublicPaths = []string{"/v1/status", "/v1/public/*"} })
+
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{path: "/v1/status", want: true},
+		{path: "/v1/status/details", want: false},
+		{path: "/v1/public/products", want: true},
+		{path: "/v1/orders", want: false},
+	}
+	for _, tt := range tests {
+		if got := a.PublicPath(tt.path); got != tt.want {
+			t.Errorf("PublicPath(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+	if !a.PublicMethod("/grpc.health.v1.Health/Check") || a.PublicMethod("/order.v1.OrderService/GetOrder") {
+		t.Error("PublicMethod() did not match the configured methods")
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	a := newTestAuthenticator(t, nil)
+	token, _ := a.Issue(Claims{Subject: "user-1", TenantID: "acme"})
+
+	var gotClaims *Claims
+	var gotTenant string
+	handler := Middleware(a, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotClaims, _ = FromContext(r.Context())
+		gotTenant = r.Header.Get(tenant.Header)
+	}))
+
+	tests := []struct {
+		name          string
+		path          string
+		authorization string
+		tenant        string
+		wantCode      int
+	}{
+		{name: "valid token", path: "/v1/orders", authorization: "Bearer " + token, wantCode: http.StatusOK},
+		{name: "lowercase scheme", path: "/v1/orders", authorization: "bearer " + token, wantCode: http.StatusOK},
+		{name: "missing token", path: "/v1/orders", wantCode: http.StatusUnauthorized},
+		{name: "invalid token", path: "/v1/orders", authorization: "Bearer nope", wantCode: http.StatusUnauthorized},
+		{name: "basic auth", path: "/v1/orders", authorization: "Basic dXNlcjpwYXNz", wantCode: http.StatusUnauthorized},
+		{name: "other tenant", path: "/v1/orders", authorization: "Bearer " + token, tenant: "globex", wantCode: http.StatusForbidden},
+		{name: "public path", path: "/v1/public/products", wantCode: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotClaims, gotTenant = nil, ""
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			if tt.tenant != "" {
+				req.Header.Set(tenant.Header, tt.tenant)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantCode)
+			}
+			if tt.wantCode == http.StatusUnauthorized && rec.Header().Get("WWW-Authenticate") == "" {
+				t.Error("unauthorized response has no WWW-Authenticate header")
+			}
+			if tt.wantCode == http.StatusOK && tt.authorization != "" {
+				if gotClaims == nil || gotClaims.Subject != "user-1" || gotTenant != "acme" {
+					t.Errorf("claims = %+v, tenant = %q", gotClaims, gotTenant)
+				}
+			}
+		})
+	}
+}
+
+func TestRequireRole(t *testing.T) {
+	a := newTestAuthenticator(t, nil)
+	admin, _ := a.Issue(Claims{Subject: "ops", Roles: []string{RoleAdmin}})
+	user, _ := a.Issue(Claims{Subject: "user-1"})
+	handler := Middleware(a, RequireRole(RoleAdmin, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})))
+
+	tests := []struct {
+		name     string
+		token    string
+		wantCode int
+	}{
+		{name: "admin", token: admin, wantCode: http.StatusOK},
+		{name: "without role", token: user, wantCode: http.StatusForbidden},
+		{name: "anonymous", wantCode: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPut, "/admin/loglevel", nil)
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != tt.wantCode {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	a := newTestAuthenticator(t, nil)
+	token, _ := a.Issue(Claims{Subject: "user-1", TenantID: "acme"})
+	interceptor := UnaryServerInterceptor(a)
+
+	var gotTenant string
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		if values := md.Get(tenant.MetadataKey); len(values) > 0 {
+			gotTenant = values[0]
+		}
+		if _, ok := FromContext(ctx); !ok {
+			return nil, nil
+		}
+		return "ok", nil
+	}
+
+	tests := []struct {
+		name     string
+		method   string
+		md       metadata.MD
+		wantCode codes.Code
+	}{
+		{name: "valid token", method: "/order.v1.OrderService/GetOrder", md: metadata.Pairs(MetadataKey, "Bearer "+token), wantCode: codes.OK},
+		{name: "missing token", method: "/order.v1.OrderService/GetOrder", md: metadata.MD{}, wantCode: codes.Unauthenticated},
+		{name: "invalid token", method: "/order.v1.OrderService/GetOrder", md: metadata.Pairs(MetadataKey, "Bearer nope"), wantCode: codes.Unauthenticated},
+		{name: "other tenant", method: "/order.v1.OrderService/GetOrder", md: metadata.Pairs(MetadataKey, "Bearer "+token, tenant.MetadataKey, "globex"), wantCode: codes.PermissionDenied},
+		{name: "public method", method: "/grpc.health.v1.Health/Check", md: metadata.MD{}, wantCode: codes.OK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotTenant = ""
+			ctx := metadata.NewIncomingContext(context.Background(), tt.md)
+			resp, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: tt.method}, handler)
+			if code := status.Code(err); code != tt.wantCode {
+				t.Fatalf("code = %v, want %v (%v)", code, tt.wantCode, err)
+			}
+			if tt.name == "valid token" && (resp != "ok" || gotTenant != "acme") {
+				t.Errorf("resp = %v, tenant = %q; want claims on the context and the token's tenant", resp, gotTenant)
+			}
+		})
+	}
+}
+
+func TestAuthorize(t *testing.T) {
+	a := newTestAuthenticator(t, func(cfg *config.Auth) {
+		cfg.Policies = []config.AuthPolicy{
+			{Method: "/user.v1.UserService/*", Roles: []string{AnyRole}},
+			{Method: "/user.v1.UserService/DeleteUser", Roles: []string{RoleAdmin}},
+			{Method: "/user.v1.UserService/Export*", Roles: []string{RoleAdmin, RoleService}},
+		}
+	})
+	customer := &Claims{Subject: "user-1", Roles: []string{RoleCustomer}}
+	admin := &Claims{Subject: "user-2", Roles: []string{RoleAdmin}}
+	service := &Claims{Subject: "order-service", Roles: []string{RoleService}}
+
+	tests := []struct {
+		name     string
+		method   string
+		claims   *Claims
+		wantCode codes.Code
+	}{
+		{name: "wildcard any role", method: "/user.v1.UserService/GetUser", claims: customer, wantCode: codes.OK},
+		{name: "exact match denies", method: "/user.v1.UserService/DeleteUser", claims: customer, wantCode: codes.PermissionDenied},
+		{name: "exact match allows", method: "/user.v1.UserService/DeleteUser", claims: admin, wantCode: codes.OK},
+		{name: "longest prefix wins", method: "/user.v1.UserService/ExportUsers", claims: customer, wantCode: codes.PermissionDenied},
+		{name: "any listed role", method: "/user.v1.UserService/ExportUsers", claims: service, wantCode: codes.OK},
+		{name: "no claims", method: "/user.v1.UserService/DeleteUser", claims: nil, wantCode: codes.PermissionDenied},
+		{name: "uncovered method", method: "/order.v1.OrderService/GetOrder", claims: admin, wantCode: codes.PermissionDenied},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if code := status.Code(a.Authorize(tt.method, tt.claims)); code != tt.wantCode {
+				t.Errorf("code = %v, want %v", code, tt.wantCode)
+			}
+		})
+	}
+
+	closed := newTestAuthenticator(t, nil)
+	if code := status.Code(closed.Authorize("/order.v1.OrderService/GetOrder", customer)); code != codes.PermissionDenied {
+		t.Errorf("Authorize() without policies code = %v, want %v", code, codes.PermissionDenied)
+	}
+}
+
+func TestUnaryAuthorizationInterceptor(t *testing.T) {
+	a := newTestAuthenticator(t, func(cfg *config.Auth) {
+		cfg.Policies = []config.AuthPolicy{{Method: "/order.v1.OrderService/*", Roles: []string{RoleAdmin}}}
+	})
+	interceptor := UnaryAuthorizationInterceptor(a)
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) { return "ok", nil }
+
+	tests := []struct {
+		name     string
+		method   string
+		claims   *Claims
+		wantCode codes.Code
+	}{
+		{name: "allowed", method: "/order.v1.OrderService/GetOrder", claims: &Claims{Roles: []string{RoleAdmin}}, wantCode: codes.OK},
+		{name: "denied", method: "/order.v1.OrderService/GetOrder", claims: &Claims{Roles: []string{RoleCustomer}}, wantCode: codes.PermissionDenied},
+		{name: "public method", method: "/grpc.health.v1.Health/Check", wantCode: codes.OK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.claims != nil {
+				ctx = WithClaims(ctx, tt.claims)
+			}
+			_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: tt.method}, handler)
+			if code := status.Code(err); code != tt.wantCode {
+				t.Errorf("code = %v, want %v (%v)", code, tt.wantCode, err)
+			}
+		})
+	}
+}
+
+func TestUnaryServerInterceptorLogger(t *testing.T) {
+	a := newTestAuthenticator(t, nil)
+	token, _ := a.Issue(Claims{Subject: "user-1"})
+	core, logs := observer.New(zap.InfoLevel)
+	ctx := log.WithContext(context.Background(), &log.Logger{Logger: zap.New(core)})
+	ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(MetadataKey, "Bearer "+token))
+
+	_, err := UnaryServerInterceptor(a)(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/order.v1.OrderService/GetOrder"},
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			log.FromContext(ctx).Info("Getting order")
+			return nil, nil
+		})
+	if err != nil {
+		t.Fatalf("interceptor() error = %v", err)
+	}
+
+	entries := logs.All()
+	if len(entries) != 1 || entries[0].ContextMap()["user_id"] != "user-1" {
+		t.Errorf("logged %v, want one entry with user_id user-1", entries)
+	}
+}
+
+func TestCredentials(t *testing.T) {
+	a := newTestAuthenticator(t, nil)
+	now := time.Now()
+	a.now = func() time.Time { return now }
+	creds := a.Credentials("shipping-service")
+
+	first, err := creds.GetRequestMetadata(context.Background())
+	if err != nil {
+		t.Fatalf("GetRequestMetadata() error = %v", err)
+	}
+	token, ok := bearerToken(first[MetadataKey])
+	if !ok {
+		t.Fatalf("GetRequestMetadata() = %v, want a bearer token", first)
+	}
+	if claims, err := a.Validate(token); err != nil || claims.Subject != "shipping-service" || !claims.HasRole(RoleService) {
+		t.Fatalf("Validate() = %+v, %v", claims, err)
+	}
+
+	second, _ := creds.GetRequestMetadata(context.Background())
+	if second[MetadataKey] != first[MetadataKey] {
+		t.Error("token was reissued before half of its lifetime passed")
+	}
+
+	now = now.Add(a.TTL())
+	third, _ := creds.GetRequestMetadata(context.Background())
+	if third[MetadataKey] == first[MetadataKey] {
+		t.Error("token was not reissued after half of its lifetime passed")
+	}
+}
@@ -0,0 +1,212 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package auth
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/internal/log"
+	"github.com/kevindiu/monorepo-go-example/internal/tenant"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// MetadataKey is the gRPC metadata key carrying the bearer token
+const MetadataKey = "authorization"
+
+// UnaryServerInterceptor authenticates each call by the bearer token in its
+// metadata, except calls to public methods, and puts the token's claims on
+// the call context
+func UnaryServerInterceptor(a *Authenticator) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if a.PublicMethod(info.FullMethod) {
+			return handler(ctx, req)
+		}
+		ctx, err := authenticateIncoming(ctx, a)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor is the streaming counterpart of
+// UnaryServerInterceptor
+func StreamServerInterceptor(a *Authenticator) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if a.PublicMethod(info.FullMethod) {
+			return handler(srv, ss)
+		}
+		ctx, err := authenticateIncoming(ss.Context(), a)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// serverStream overrides the context of a stream
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context { return s.ctx }
+
+// authenticateIncoming validates the bearer token in the incoming metadata.
+// A token issued for a tenant binds the call to that tenant: the tenant
+// metadata is set to it, and calls naming another tenant are rejected.
+func authenticateIncoming(ctx context.Context, a *Authenticator) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	var value string
+	if values := md.Get(MetadataKey); len(values) > 0 {
+		value = values[0]
+	}
+
+	token, ok := bearerToken(value)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "bearer token required")
+	}
+	claims, err := a.Validate(token)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	if claims.TenantID != "" {
+		if requested := md.Get(tenant.MetadataKey); len(requested) > 0 && requested[0] != claims.TenantID {
+			return nil, status.Error(codes.PermissionDenied, "token does not belong to the requested tenant")
+		}
+		md = md.Copy()
+		md.Set(tenant.MetadataKey, claims.TenantID)
+		ctx = metadata.NewIncomingContext(ctx, md)
+	}
+
+	return withClaims(ctx, claims), nil
+}
+
+// withClaims puts claims on ctx and adds their subject to the context's
+// logger, so that the caller's logs name the user
+func withClaims(ctx context.Context, claims *Claims) context.Context {
+	ctx = WithClaims(ctx, claims)
+	return log.WithContext(ctx, log.FromContext(ctx).With(log.String("user_id", claims.Subject)))
+}
+
+// Middleware authenticates each HTTP request by the bearer token in its
+// Authorization header, except requests to public paths, and puts the
+// token's claims on the request context. A token issued for a tenant binds
+// the request to that tenant, like a session does.
+func Middleware(a *Authenticator, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if a.PublicPath(r.URL.Path) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		token, ok := bearerToken(r.Header.Get("Authorization"))
+		if !ok {
+			writeError(w, unauthorized("bearer token required"))
+			return
+		}
+		claims, err := a.Validate(token)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		if claims.TenantID != "" {
+			if requested := r.Header.Get(tenant.Header); requested != "" && requested != claims.TenantID {
+				writeError(w, errors.WithCode(errors.New("token does not belong to the requested tenant"), errors.CodeForbidden))
+				return
+			}
+			r.Header.Set(tenant.Header, claims.TenantID)
+		}
+
+		next.ServeHTTP(w, r.WithContext(withClaims(r.Context(), claims)))
+	})
+}
+
+// RequireRole rejects HTTP requests whose claims, put on the context by
+// Middleware, do not grant role
+func RequireRole(role string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := FromContext(r.Context())
+		if !ok || !claims.HasRole(role) {
+			writeError(w, errors.WithCode(errors.New("the "+role+" role is required"), errors.CodeForbidden))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Credentials returns per-call credentials that authenticate a service's
+// calls to other services as subject, with RoleService. Tokens are
+// reissued once half of their lifetime has passed.
+func (a *Authenticator) Credentials(subject string) credentials.PerRPCCredentials {
+	return &serviceCredentials{auth: a, subject: subject}
+}
+
+type serviceCredentials struct {
+	auth    *Authenticator
+	subject string
+
+	mu      sync.Mutex
+	token   string
+	renewAt time.Time
+}
+
+func (c *serviceCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if now := c.auth.now(); c.token == "" || !now.Before(c.renewAt) {
+		token, err := c.auth.Issue(Claims{Subject: c.subject, Roles: []string{RoleService}})
+		if err != nil {
+			return nil, err
+		}
+		c.token = token
+		c.renewAt = now.Add(c.auth.ttl / 2)
+	}
+	return map[string]string{MetadataKey: "Bearer " + c.token}, nil
+}
+
+func (c *serviceCredentials) RequireTransportSecurity() bool { return false }
+
+// bearerToken extracts the token of a "Bearer <token>" authorization value
+func bearerToken(value string) (string, bool) {
+	scheme, token, ok := strings.Cut(value, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return "", false
+	}
+	return token, true
+}
+
+// writeError writes err with errors.WriteHTTP, challenging the client for a
+// bearer token when it is unauthorized
+func writeError(w http.ResponseWriter, err error) {
+	if errors.GetCode(err) == errors.CodeUnauthorized {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="api"`)
+	}
+	errors.WriteHTTP(w, err)
+}
@@ -0,0 +1,93 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package auth
+
+import (
+	"context"
+	"strings"
+
+	"github.com/kevindiu/monorepo-go-example/internal/config"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// AnyRole in a policy grants every authenticated caller
+const AnyRole = "*"
+
+// Authorize checks that claims may call the full gRPC method name under the
+// configured policies. Methods no policy covers are denied, so without
+// policies only public methods can be called.
+func (a *Authenticator) Authorize(method string, claims *Claims) error {
+	policy, ok := a.policy(method)
+	if !ok {
+		return status.Errorf(codes.PermissionDenied, "%s is not allowed by any policy", method)
+	}
+	for _, role := range policy.Roles {
+		if role == AnyRole || (claims != nil && claims.HasRole(role)) {
+			return nil
+		}
+	}
+	return status.Errorf(codes.PermissionDenied, "%s requires one of the roles %s", method, strings.Join(policy.Roles, ", "))
+}
+
+// policy returns the most specific policy of method: the one naming it,
+// or else the one with the longest matching prefix
+func (a *Authenticator) policy(method string) (config.AuthPolicy, bool) {
+	var (
+		best   config.AuthPolicy
+		length = -1
+	)
+	for _, p := range a.policies {
+		if p.Method == method {
+			return p, true
+		}
+		if prefix, ok := strings.CutSuffix(p.Method, "*"); ok && strings.HasPrefix(method, prefix) && len(prefix) > length {
+			best, length = p, len(prefix)
+		}
+	}
+	return best, length >= 0
+}
+
+// UnaryAuthorizationInterceptor enforces the policies of the authenticator
+// on calls authenticated by UnaryServerInterceptor, which must run first.
+// Public methods are exempt.
+func UnaryAuthorizationInterceptor(a *Authenticator) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if !a.PublicMethod(info.FullMethod) {
+			claims, _ := FromContext(ctx)
+			if err := a.Authorize(info.FullMethod, claims); err != nil {
+				return nil, err
+			}
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamAuthorizationInterceptor is the streaming counterpart of
+// UnaryAuthorizationInterceptor
+func StreamAuthorizationInterceptor(a *Authenticator) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if !a.PublicMethod(info.FullMethod) {
+			claims, _ := FromContext(ss.Context())
+			if err := a.Authorize(info.FullMethod, claims); err != nil {
+				return err
+			}
+		}
+		return handler(srv, ss)
+	}
+}
@@ -0,0 +1,149 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package breaker stops calling a failing dependency for a while, so that
+// callers fail fast instead of piling up behind timeouts.
+package breaker
+
+import (
+	"sync"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+)
+
+// ErrOpen is returned by Do without calling fn while the breaker is open
+var ErrOpen = &errors.Error{Code: errors.CodeUnavailable, Message: "circuit breaker is open"}
+
+// State is the state of a breaker
+type State int
+
+const (
+	// Closed lets every call through
+	Closed State = iota
+	// Open rejects every call until the cooldown has passed
+	Open
+	// HalfOpen lets one trial call through; its outcome closes or reopens
+	// the breaker
+	HalfOpen
+)
+
+// String returns the name of the state
+func (s State) String() string {
+	switch s {
+	case Closed:
+		return "closed"
+	case Open:
+		return "open"
+	case HalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// Breaker opens after threshold consecutive failed calls and rejects calls
+// for cooldown, then lets a single trial call through. It is safe for
+// concurrent use.
+type Breaker struct {
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+	probing   bool
+}
+
+// New creates a breaker opening after threshold consecutive failures, at
+// least one, for cooldown
+func New(threshold int, cooldown time.Duration) *Breaker {
+	return &Breaker{
+		threshold: max(threshold, 1),
+		cooldown:  cooldown,
+		now:       time.Now,
+	}
+}
+
+// State returns the current state of the breaker
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state()
+}
+
+func (b *Breaker) state() State {
+	switch {
+	case b.failures < b.threshold:
+		return Closed
+	case b.now().Before(b.openUntil):
+		return Open
+	default:
+		return HalfOpen
+	}
+}
+
+// Do calls fn unless the breaker is open, in which case it returns ErrOpen.
+// An error from fn counts as a failure, so fn should only return errors
+// that say the dependency is unhealthy.
+func (b *Breaker) Do(fn func() error) error {
+	probe, err := b.allow()
+	if err != nil {
+		return err
+	}
+	err = fn()
+	b.record(probe, err == nil)
+	return err
+}
+
+// allow reports whether a call may proceed and whether it is the trial
+// call of a half-open breaker
+func (b *Breaker) allow() (bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state() {
+	case Open:
+		return false, ErrOpen
+	case HalfOpen:
+		if b.probing {
+			return false, ErrOpen
+		}
+		b.probing = true
+		return true, nil
+	default:
+		return false, nil
+	}
+}
+
+// record counts the outcome of a call
+func (b *Breaker) record(probe, ok bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if probe {
+		b.probing = false
+	}
+	if ok {
+		b.failures = 0
+		return
+	}
+	b.failures++
+	if b.failures >= b.threshold {
+		b.openUntil = b.now().Add(b.cooldown)
+	}
+}
@@ -0,0 +1,109 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package breaker
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// clock is a settable time source
+type clock struct{ t time.Time }
+
+func (c *clock) now() time.Time { return c.t }
+
+func TestBreaker(t *testing.T) {
+	failure := errors.New("unavailable")
+	fail := func() error { return failure }
+	succeed := func() error { return nil }
+
+	c := &clock{t: time.Unix(0, 0)}
+	b := New(3, time.Minute)
+	b.now = c.now
+
+	// Fewer failures than the threshold keep it closed, and a success
+	// resets the count
+	for i := 0; i < 2; i++ {
+		if err := b.Do(fail); err != failure {
+			t.Fatalf("Do() error = %v, want %v", err, failure)
+		}
+	}
+	if err := b.Do(succeed); err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		b.Do(fail)
+	}
+	if got := b.State(); got != Closed {
+		t.Fatalf("State() after 2 failures = %v, want closed", got)
+	}
+
+	// The third consecutive failure opens it
+	b.Do(fail)
+	if got := b.State(); got != Open {
+		t.Fatalf("State() after 3 failures = %v, want open", got)
+	}
+	called := false
+	if err := b.Do(func() error { called = true; return nil }); err != ErrOpen || called {
+		t.Fatalf("Do() while open = %v (called %t), want ErrOpen without calling", err, called)
+	}
+
+	// After the cooldown one trial call goes through; a failure reopens it
+	c.t = c.t.Add(time.Minute)
+	if got := b.State(); got != HalfOpen {
+		t.Fatalf("State() after cooldown = %v, want half-open", got)
+	}
+	if err := b.Do(fail); err != failure {
+		t.Fatalf("Do() trial error = %v, want %v", err, failure)
+	}
+	if got := b.State(); got != Open {
+		t.Fatalf("State() after failed trial = %v, want open", got)
+	}
+
+	// A successful trial closes it
+	c.t = c.t.Add(time.Minute)
+	if err := b.Do(succeed); err != nil {
+		t.Fatalf("Do() trial error = %v", err)
+	}
+	if got := b.State(); got != Closed {
+		t.Fatalf("State() after successful trial = %v, want closed", got)
+	}
+}
+
+func TestBreakerSingleTrial(t *testing.T) {
+	c := &clock{t: time.Unix(0, 0)}
+	b := New(1, time.Second)
+	b.now = c.now
+
+	b.Do(func() error { return errors.New("unavailable") })
+	c.t = c.t.Add(time.Second)
+
+	// While the trial call is in flight, other calls are rejected
+	err := b.Do(func() error {
+		if err := b.Do(func() error { return nil }); err != ErrOpen {
+			t.Errorf("Do() during trial = %v, want ErrOpen", err)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Do() trial error = %v", err)
+	}
+	if got := b.State(); got != Closed {
+		t.Errorf("State() = %v, want closed", got)
+	}
+}
@@ -0,0 +1,55 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package cache stores short-lived values in front of slower lookups, in
+// Redis so that replicas share entries and invalidations, or in process.
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/config"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrMiss is returned by Get for a key that is not cached
+var ErrMiss = &errors.Error{Code: errors.CodeNotFound, Message: "cache miss"}
+
+// Cache stores values under keys until they expire. Get returns ErrMiss on
+// a miss.
+type Cache interface {
+	Get(ctx context.Context, key string) ([]byte, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, keys ...string) error
+	Close() error
+}
+
+// New creates a cache from configuration: Redis when an address is
+// configured, an in-process cache otherwise
+func New(cfg *config.Cache) Cache {
+	if cfg.RedisAddr == "" {
+		return NewMemory(cfg.MaxEntries)
+	}
+
+	client := redis.NewClient(&redis.Options{
+		Addr:     cfg.RedisAddr,
+		Password: cfg.RedisPassword,
+		DB:       cfg.RedisDB,
+	})
+	return NewRedis(client, "cache:")
+}
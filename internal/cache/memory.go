@@ -0,0 +1,112 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// Memory is an in-process cache that evicts the least recently used entry
+// once it holds maxEntries
+type Memory struct {
+	mu         sync.Mutex
+	maxEntries int
+	entries    map[string]*list.Element
+	lru        *list.List
+	now        func() time.Time
+}
+
+type memoryEntry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time
+}
+
+// NewMemory creates an in-process cache holding at most maxEntries
+func NewMemory(maxEntries int) *Memory {
+	return &Memory{
+		maxEntries: maxEntries,
+		entries:    make(map[string]*list.Element),
+		lru:        list.New(),
+		now:        time.Now,
+	}
+}
+
+// Get returns the value cached under key
+func (m *Memory) Get(ctx context.Context, key string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	elem, ok := m.entries[key]
+	if !ok {
+		return nil, ErrMiss
+	}
+	entry := elem.Value.(*memoryEntry)
+	if !m.now().Before(entry.expiresAt) {
+		m.remove(elem)
+		return nil, ErrMiss
+	}
+	m.lru.MoveToFront(elem)
+	return entry.value, nil
+}
+
+// Set caches value under key for ttl
+func (m *Memory) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	expiresAt := m.now().Add(ttl)
+	if elem, ok := m.entries[key]; ok {
+		entry := elem.Value.(*memoryEntry)
+		entry.value = value
+		entry.expiresAt = expiresAt
+		m.lru.MoveToFront(elem)
+		return nil
+	}
+
+	m.entries[key] = m.lru.PushFront(&memoryEntry{key: key, value: value, expiresAt: expiresAt})
+	for m.maxEntries > 0 && m.lru.Len() > m.maxEntries {
+		m.remove(m.lru.Back())
+	}
+	return nil
+}
+
+// Delete removes keys
+func (m *Memory) Delete(ctx context.Context, keys ...string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, key := range keys {
+		if elem, ok := m.entries[key]; ok {
+			m.remove(elem)
+		}
+	}
+	return nil
+}
+
+// Close releases nothing
+func (m *Memory) Close() error {
+	return nil
+}
+
+func (m *Memory) remove(elem *list.Element) {
+	m.lru.Remove(elem)
+	delete(m.entries, elem.Value.(*memoryEntry).key)
+}
@@ -0,0 +1,55 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemory(t *testing.T) {
+	now := time.Now()
+	m := NewMemory(2)
+	m.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	m.Set(ctx, "a", []byte("1"), time.Minute)
+	m.Set(ctx, "b", []byte("2"), time.Second)
+	if v, err := m.Get(ctx, "a"); err != nil || string(v) != "1" {
+		t.Fatalf("Get(a) = %q, %v", v, err)
+	}
+
+	// b is now the least recently used entry
+	m.Set(ctx, "c", []byte("3"), time.Minute)
+	if _, err := m.Get(ctx, "b"); err != ErrMiss {
+		t.Errorf("Get(b) error = %v, want the least recently used entry evicted", err)
+	}
+
+	m.Delete(ctx, "c")
+	if _, err := m.Get(ctx, "c"); err != ErrMiss {
+		t.Errorf("Get(c) error = %v after Delete", err)
+	}
+
+	now = now.Add(time.Minute)
+	if _, err := m.Get(ctx, "a"); err != ErrMiss {
+		t.Errorf("Get(a) error = %v after expiry", err)
+	}
+	if len(m.entries) != 0 || m.lru.Len() != 0 {
+		t.Errorf("cache holds %d entries, want 0", len(m.entries))
+	}
+}
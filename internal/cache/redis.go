@@ -0,0 +1,77 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/redis/go-redis/v9"
+)
+
+// Redis keeps entries in Redis under a key prefix, expiring each with its
+// TTL, so that every replica sees the same entries and invalidations
+type Redis struct {
+	client redis.UniversalClient
+	prefix string
+}
+
+// NewRedis creates a Redis-backed cache
+func NewRedis(client redis.UniversalClient, prefix string) *Redis {
+	return &Redis{client: client, prefix: prefix}
+}
+
+// Get returns the value cached under key
+func (r *Redis) Get(ctx context.Context, key string) ([]byte, error) {
+	value, err := r.client.Get(ctx, r.prefix+key).Bytes()
+	if err == redis.Nil {
+		return nil, ErrMiss
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read cache")
+	}
+	return value, nil
+}
+
+// Set caches value under key for ttl
+func (r *Redis) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if err := r.client.Set(ctx, r.prefix+key, value, ttl).Err(); err != nil {
+		return errors.Wrap(err, "failed to write cache")
+	}
+	return nil
+}
+
+// Delete removes keys
+func (r *Redis) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	prefixed := make([]string, len(keys))
+	for i, key := range keys {
+		prefixed[i] = r.prefix + key
+	}
+	if err := r.client.Del(ctx, prefixed...).Err(); err != nil {
+		return errors.Wrap(err, "failed to invalidate cache")
+	}
+	return nil
+}
+
+// Close closes the Redis client
+func (r *Redis) Close() error {
+	return r.client.Close()
+}
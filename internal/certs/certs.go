@@ -0,0 +1,235 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package certs loads the TLS certificates of gRPC servers and clients and
+// reloads them when their files change, so that rotated certificates take
+// effect without a restart. Handshakes always use the latest certificates
+// that loaded successfully.
+package certs
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/config"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/internal/log"
+)
+
+// Client authentication modes of config.TLS.ClientAuth
+const (
+	// ClientAuthNone does not ask clients for a certificate
+	ClientAuthNone = "none"
+	// ClientAuthOptional verifies the certificates clients present, but
+	// accepts clients without one
+	ClientAuthOptional = "optional"
+	// ClientAuthRequire rejects clients without a verified certificate,
+	// which is mutual TLS
+	ClientAuthRequire = "require"
+)
+
+// clientAuthTypes maps the client authentication modes to their tls types
+var clientAuthTypes = map[string]tls.ClientAuthType{
+	"":                 tls.NoClientCert,
+	ClientAuthNone:     tls.NoClientCert,
+	ClientAuthOptional: tls.VerifyClientCertIfGiven,
+	ClientAuthRequire:  tls.RequireAndVerifyClientCert,
+}
+
+// nextProtos are the ALPN protocols of every handshake; gRPC runs on HTTP/2
+var nextProtos = []string{"h2"}
+
+// Store holds the certificate of a process and the CA pool its peers are
+// verified against
+type Store struct {
+	cfg        *config.TLS
+	clientAuth tls.ClientAuthType
+	logger     *log.Logger
+
+	mu       sync.RWMutex
+	cert     *tls.Certificate
+	pool     *x509.CertPool
+	contents []byte
+}
+
+// New loads the certificate, key and CA files of cfg
+func New(cfg *config.TLS, logger *log.Logger) (*Store, error) {
+	if logger == nil {
+		logger = log.NewDefault()
+	}
+	clientAuth, ok := clientAuthTypes[cfg.ClientAuth]
+	if !ok {
+		return nil, errors.Newf("unknown client auth mode %q", cfg.ClientAuth)
+	}
+
+	s := &Store{cfg: cfg, clientAuth: clientAuth, logger: logger}
+	if _, err := s.Reload(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// FromConfig returns a store for cfg, or nil when TLS is disabled
+func FromConfig(cfg *config.TLS, logger *log.Logger) (*Store, error) {
+	if cfg == nil || !cfg.Enabled {
+		return nil, nil
+	}
+	return New(cfg, logger)
+}
+
+// Reload loads the files again if any of them changed. It reports whether
+// the certificates were replaced; on error the previous ones stay in use.
+func (s *Store) Reload() (bool, error) {
+	certPEM, err := os.ReadFile(s.cfg.CertFile)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to read TLS certificate")
+	}
+	keyPEM, err := os.ReadFile(s.cfg.KeyFile)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to read TLS key")
+	}
+	var caPEM []byte
+	if s.cfg.CAFile != "" {
+		if caPEM, err = os.ReadFile(s.cfg.CAFile); err != nil {
+			return false, errors.Wrap(err, "failed to read TLS CA")
+		}
+	}
+
+	contents := bytes.Join([][]byte{certPEM, keyPEM, caPEM}, nil)
+	s.mu.RLock()
+	unchanged := bytes.Equal(contents, s.contents)
+	s.mu.RUnlock()
+	if unchanged {
+		return false, nil
+	}
+
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to load TLS key pair")
+	}
+	// A nil pool verifies peers against the system roots
+	var pool *x509.CertPool
+	if caPEM != nil {
+		pool = x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return false, errors.Newf("no certificates found in %s", s.cfg.CAFile)
+		}
+	}
+
+	s.mu.Lock()
+	s.cert = &cert
+	s.pool = pool
+	s.contents = contents
+	s.mu.Unlock()
+	return true, nil
+}
+
+// Run reloads the files every reload interval until ctx is cancelled. It
+// returns at once when the interval is zero.
+func (s *Store) Run(ctx context.Context) error {
+	if s.cfg.ReloadInterval <= 0 {
+		return nil
+	}
+	ticker := time.NewTicker(s.cfg.ReloadInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			reloaded, err := s.Reload()
+			switch {
+			case err != nil:
+				s.logger.Error("Failed to reload TLS certificates", log.Error(err))
+			case reloaded:
+				s.logger.Info("TLS certificates reloaded", log.String("cert_file", s.cfg.CertFile))
+			}
+		}
+	}
+}
+
+// current returns the certificate and CA pool in use
+func (s *Store) current() (*tls.Certificate, *x509.CertPool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.cert, s.pool
+}
+
+// ServerConfig returns the TLS config of a server. Each handshake uses the
+// current certificate and verifies client certificates against the
+// current CA pool, as the client auth mode requires.
+func (s *Store) ServerConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		NextProtos: nextProtos,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			cert, pool := s.current()
+			return &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				NextProtos:   nextProtos,
+				Certificates: []tls.Certificate{*cert},
+				ClientAuth:   s.clientAuth,
+				ClientCAs:    pool,
+			}, nil
+		},
+	}
+}
+
+// ClientConfig returns the TLS config of a client. It presents the current
+// certificate to servers that ask for one and verifies servers against the
+// current CA pool, under the configured server name or, when that is
+// empty, the host being dialed.
+func (s *Store) ClientConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: s.cfg.ServerName,
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			cert, _ := s.current()
+			return cert, nil
+		},
+		// RootCAs cannot change once gRPC has copied the config, so the
+		// server is verified by VerifyConnection against the current pool
+		// instead of by the default verification
+		InsecureSkipVerify: true,
+		VerifyConnection:   s.verifyServer,
+	}
+}
+
+// verifyServer verifies the certificate chain and name of a server
+func (s *Store) verifyServer(cs tls.ConnectionState) error {
+	if len(cs.PeerCertificates) == 0 {
+		return errors.New("server presented no certificate")
+	}
+	_, pool := s.current()
+	opts := x509.VerifyOptions{
+		DNSName:       cs.ServerName,
+		Roots:         pool,
+		Intermediates: x509.NewCertPool(),
+	}
+	for _, cert := range cs.PeerCertificates[1:] {
+		opts.Intermediates.AddCert(cert)
+	}
+	if _, err := cs.PeerCertificates[0].Verify(opts); err != nil {
+		return errors.Wrap(err, "failed to verify server certificate")
+	}
+	return nil
+}
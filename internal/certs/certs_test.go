@@ -0,0 +1,261 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package certs
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/config"
+)
+
+// authority issues test certificates
+type authority struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newAuthority(t *testing.T) *authority {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &authority{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+// issue returns a PEM certificate and key for name, usable by servers and
+// clients
+func (a *authority) issue(t *testing.T, name string, serial int64) (certPEM, keyPEM []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, a.cert, &key.PublicKey, a.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+// writeFiles writes a certificate, key and CA to dir and returns their
+// config
+func writeFiles(t *testing.T, dir string, certPEM, keyPEM, caPEM []byte) *config.TLS {
+	t.Helper()
+	cfg := &config.TLS{
+		Enabled:  true,
+		CertFile: filepath.Join(dir, "tls.crt"),
+		KeyFile:  filepath.Join(dir, "tls.key"),
+		CAFile:   filepath.Join(dir, "ca.crt"),
+	}
+	for path, data := range map[string][]byte{cfg.CertFile: certPEM, cfg.KeyFile: keyPEM, cfg.CAFile: caPEM} {
+		if err := os.WriteFile(path, data, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return cfg
+}
+
+// handshake connects client to server over loopback TCP and returns the
+// certificate the server presented, or the first handshake error
+func handshake(server, client *tls.Config) (*x509.Certificate, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	defer listener.Close()
+
+	serverErr := make(chan error, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			serverErr <- err
+			return
+		}
+		defer conn.Close()
+		serverErr <- tls.Server(conn, server).Handshake()
+	}()
+
+	raw, err := net.Dial("tcp", listener.Addr().String())
+	if err != nil {
+		return nil, err
+	}
+	conn := tls.Client(raw, client)
+	defer conn.Close()
+	clientErr := conn.Handshake()
+	if clientErr != nil {
+		// Unblock the server, which waits for the client's response
+		raw.Close()
+	}
+	if err := <-serverErr; err != nil {
+		return nil, err
+	}
+	if clientErr != nil {
+		return nil, clientErr
+	}
+	return conn.ConnectionState().PeerCertificates[0], nil
+}
+
+func TestMutualTLS(t *testing.T) {
+	ca := newAuthority(t)
+	serverCert, serverKey := ca.issue(t, "localhost", 2)
+	serverCfg := writeFiles(t, t.TempDir(), serverCert, serverKey, ca.pem)
+	serverCfg.ClientAuth = ClientAuthRequire
+	server, err := New(serverCfg, nil)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	clientCert, clientKey := ca.issue(t, "gateway", 3)
+	client, err := New(writeFiles(t, t.TempDir(), clientCert, clientKey, ca.pem), nil)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	clientConfig := client.ClientConfig()
+	clientConfig.ServerName = "localhost"
+	if _, err := handshake(server.ServerConfig(), clientConfig); err != nil {
+		t.Fatalf("handshake error = %v", err)
+	}
+
+	// The server name must match its certificate
+	clientConfig = client.ClientConfig()
+	clientConfig.ServerName = "order-service"
+	if _, err := handshake(server.ServerConfig(), clientConfig); err == nil {
+		t.Error("handshake with a mismatched server name succeeded")
+	}
+
+	// Clients without a certificate are rejected
+	anonymous := &tls.Config{ServerName: "localhost", RootCAs: x509.NewCertPool()}
+	anonymous.RootCAs.AddCert(ca.cert)
+	if _, err := handshake(server.ServerConfig(), anonymous); err == nil {
+		t.Error("handshake without a client certificate succeeded")
+	}
+
+	// Certificates of another CA are rejected
+	other := newAuthority(t)
+	otherCert, otherKey := other.issue(t, "gateway", 2)
+	stranger, err := New(writeFiles(t, t.TempDir(), otherCert, otherKey, ca.pem), nil)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	clientConfig = stranger.ClientConfig()
+	clientConfig.ServerName = "localhost"
+	if _, err := handshake(server.ServerConfig(), clientConfig); err == nil {
+		t.Error("handshake with an untrusted client certificate succeeded")
+	}
+}
+
+func TestReload(t *testing.T) {
+	ca := newAuthority(t)
+	certPEM, keyPEM := ca.issue(t, "localhost", 2)
+	cfg := writeFiles(t, t.TempDir(), certPEM, keyPEM, ca.pem)
+	store, err := New(cfg, nil)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	client := store.ClientConfig()
+	client.ServerName = "localhost"
+
+	if reloaded, err := store.Reload(); err != nil || reloaded {
+		t.Fatalf("Reload() of unchanged files = %v, %v, want false, nil", reloaded, err)
+	}
+
+	// A rotated certificate is served by the next handshake, through the
+	// config built before the rotation
+	serverConfig := store.ServerConfig()
+	rotatedCert, rotatedKey := ca.issue(t, "localhost", 3)
+	writeFiles(t, filepath.Dir(cfg.CertFile), rotatedCert, rotatedKey, ca.pem)
+	if reloaded, err := store.Reload(); err != nil || !reloaded {
+		t.Fatalf("Reload() of rotated files = %v, %v, want true, nil", reloaded, err)
+	}
+	peer, err := handshake(serverConfig, client)
+	if err != nil {
+		t.Fatalf("handshake error = %v", err)
+	}
+	if peer.SerialNumber.Int64() != 3 {
+		t.Errorf("served certificate serial = %v, want 3", peer.SerialNumber)
+	}
+
+	// A broken key pair keeps the previous certificate in use
+	if err := os.WriteFile(cfg.KeyFile, []byte("not a key"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Reload(); err == nil {
+		t.Fatal("Reload() of a broken key succeeded")
+	}
+	if _, err := handshake(serverConfig, client); err != nil {
+		t.Fatalf("handshake after a failed reload error = %v", err)
+	}
+}
+
+func TestFromConfig(t *testing.T) {
+	store, err := FromConfig(&config.TLS{Enabled: false, CertFile: "missing.crt"}, nil)
+	if store != nil || err != nil {
+		t.Errorf("FromConfig() of disabled TLS = %v, %v, want nil, nil", store, err)
+	}
+	if _, err := FromConfig(&config.TLS{Enabled: true, CertFile: "missing.crt", KeyFile: "missing.key"}, nil); err == nil {
+		t.Error("FromConfig() with missing files succeeded")
+	}
+	if _, err := New(&config.TLS{ClientAuth: "sometimes"}, nil); err == nil {
+		t.Error("New() with an unknown client auth mode succeeded")
+	}
+}
@@ -0,0 +1,266 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package channelz enables gRPC channelz and summarizes its channels,
+// subchannels and servers: their connectivity states, call counts and the
+// last warnings they logged. It helps diagnose backend connectivity, for
+// example why the gateway cannot reach a service.
+package channelz
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"google.golang.org/grpc"
+	channelzpb "google.golang.org/grpc/channelz/grpc_channelz_v1"
+	"google.golang.org/grpc/channelz/service"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// inspectorTarget is the target of the inspector's own channel, which is
+// left out of summaries
+const inspectorTarget = "passthrough:///channelz-inspector"
+
+// Register serves the channelz service on s, for tools such as grpcdebug
+func Register(s *grpc.Server) {
+	service.RegisterChannelzServiceToServer(s)
+}
+
+// Calls counts the calls made on a channel or handled by a server
+type Calls struct {
+	Started     int64      `json:"started"`
+	Succeeded   int64      `json:"succeeded"`
+	Failed      int64      `json:"failed"`
+	LastStarted *time.Time `json:"last_started,omitempty"`
+}
+
+// Event is a channel trace event of warning or error severity
+type Event struct {
+	Description string    `json:"description"`
+	Severity    string    `json:"severity"`
+	Timestamp   time.Time `json:"timestamp"`
+}
+
+// Channel is a client connection to a target
+type Channel struct {
+	ID          int64        `json:"id"`
+	Target      string       `json:"target"`
+	State       string       `json:"state"`
+	Calls       Calls        `json:"calls"`
+	LastError   *Event       `json:"last_error,omitempty"`
+	Subchannels []Subchannel `json:"subchannels"`
+}
+
+// Subchannel is a connection of a channel to one backend address
+type Subchannel struct {
+	ID        int64  `json:"id"`
+	State     string `json:"state"`
+	Calls     Calls  `json:"calls"`
+	LastError *Event `json:"last_error,omitempty"`
+	Sockets   int    `json:"sockets"`
+}
+
+// Server is a gRPC server
+type Server struct {
+	ID            int64 `json:"id"`
+	Calls         Calls `json:"calls"`
+	ListenSockets int   `json:"listen_sockets"`
+}
+
+// Summary is the state of every channel and server in the process
+type Summary struct {
+	Channels []Channel `json:"channels"`
+	Servers  []Server  `json:"servers"`
+}
+
+// Inspector queries channelz in process, through a channelz service on an
+// in-memory listener
+type Inspector struct {
+	server *grpc.Server
+	conn   *grpc.ClientConn
+	client channelzpb.ChannelzClient
+}
+
+// NewInspector starts an in-process channelz service and connects to it
+func NewInspector() (*Inspector, error) {
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	Register(server)
+	go server.Serve(listener)
+
+	conn, err := grpc.Dial(inspectorTarget,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+	)
+	if err != nil {
+		server.Stop()
+		return nil, errors.Wrap(err, "failed to connect to channelz")
+	}
+
+	return &Inspector{server: server, conn: conn, client: channelzpb.NewChannelzClient(conn)}, nil
+}
+
+// Close stops the in-process channelz service
+func (i *Inspector) Close() error {
+	err := i.conn.Close()
+	i.server.Stop()
+	return err
+}
+
+// Summary returns the state of every channel and server, except the
+// inspector's own
+func (i *Inspector) Summary(ctx context.Context) (*Summary, error) {
+	summary := &Summary{Channels: []Channel{}, Servers: []Server{}}
+
+	for start := int64(0); ; {
+		resp, err := i.client.GetTopChannels(ctx, &channelzpb.GetTopChannelsRequest{StartChannelId: start})
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to list channels")
+		}
+		for _, ch := range resp.GetChannel() {
+			start = ch.GetRef().GetChannelId() + 1
+			if ch.GetData().GetTarget() == inspectorTarget {
+				continue
+			}
+			channel, err := i.channel(ctx, ch)
+			if err != nil {
+				return nil, err
+			}
+			summary.Channels = append(summary.Channels, channel)
+		}
+		if resp.GetEnd() || len(resp.GetChannel()) == 0 {
+			break
+		}
+	}
+
+	for start := int64(0); ; {
+		resp, err := i.client.GetServers(ctx, &channelzpb.GetServersRequest{StartServerId: start})
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to list servers")
+		}
+		for _, s := range resp.GetServer() {
+			start = s.GetRef().GetServerId() + 1
+			if i.isOwn(s) {
+				continue
+			}
+			summary.Servers = append(summary.Servers, Server{
+				ID:            s.GetRef().GetServerId(),
+				Calls:         serverCalls(s.GetData()),
+				ListenSockets: len(s.GetListenSocket()),
+			})
+		}
+		if resp.GetEnd() || len(resp.GetServer()) == 0 {
+			break
+		}
+	}
+
+	return summary, nil
+}
+
+// Handler serves the summary as JSON
+func (i *Inspector) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		summary, err := i.Summary(r.Context())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(summary)
+	})
+}
+
+// channel summarizes a channel and its subchannels
+func (i *Inspector) channel(ctx context.Context, ch *channelzpb.Channel) (Channel, error) {
+	data := ch.GetData()
+	channel := Channel{
+		ID:          ch.GetRef().GetChannelId(),
+		Target:      data.GetTarget(),
+		State:       data.GetState().GetState().String(),
+		Calls:       channelCalls(data),
+		LastError:   lastError(data.GetTrace()),
+		Subchannels: []Subchannel{},
+	}
+
+	for _, ref := range ch.GetSubchannelRef() {
+		resp, err := i.client.GetSubchannel(ctx, &channelzpb.GetSubchannelRequest{SubchannelId: ref.GetSubchannelId()})
+		if err != nil {
+			// The subchannel closed since the channel was listed
+			continue
+		}
+		sub := resp.GetSubchannel()
+		channel.Subchannels = append(channel.Subchannels, Subchannel{
+			ID:        ref.GetSubchannelId(),
+			State:     sub.GetData().GetState().GetState().String(),
+			Calls:     channelCalls(sub.GetData()),
+			LastError: lastError(sub.GetData().GetTrace()),
+			Sockets:   len(sub.GetSocketRef()),
+		})
+	}
+
+	return channel, nil
+}
+
+// isOwn reports whether s is the inspector's in-memory server
+func (i *Inspector) isOwn(s *channelzpb.Server) bool {
+	for _, ref := range s.GetListenSocket() {
+		if ref.GetName() == "bufconn" {
+			return true
+		}
+	}
+	return false
+}
+
+func channelCalls(data *channelzpb.ChannelData) Calls {
+	return calls(data.GetCallsStarted(), data.GetCallsSucceeded(), data.GetCallsFailed(), data.GetLastCallStartedTimestamp().AsTime())
+}
+
+func serverCalls(data *channelzpb.ServerData) Calls {
+	return calls(data.GetCallsStarted(), data.GetCallsSucceeded(), data.GetCallsFailed(), data.GetLastCallStartedTimestamp().AsTime())
+}
+
+func calls(started, succeeded, failed int64, lastStarted time.Time) Calls {
+	c := Calls{Started: started, Succeeded: succeeded, Failed: failed}
+	if started > 0 {
+		c.LastStarted = &lastStarted
+	}
+	return c
+}
+
+// lastError returns the most recent trace event of warning or error
+// severity, such as a failed connection attempt
+func lastError(trace *channelzpb.ChannelTrace) *Event {
+	events := trace.GetEvents()
+	for i := len(events) - 1; i >= 0; i-- {
+		e := events[i]
+		switch e.GetSeverity() {
+		case channelzpb.ChannelTraceEvent_CT_WARNING, channelzpb.ChannelTraceEvent_CT_ERROR:
+			return &Event{
+				Description: e.GetDescription(),
+				Severity:    e.GetSeverity().String(),
+				Timestamp:   e.GetTimestamp().AsTime(),
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package channelz
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+func TestSummary(t *testing.T) {
+	inspector, err := NewInspector()
+	if err != nil {
+		t.Fatalf("NewInspector() error = %v", err)
+	}
+	defer inspector.Close()
+
+	// A server with channelz registered, and a channel to a dead backend
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := grpc.NewServer()
+	Register(server)
+	go server.Serve(listener)
+	defer server.Stop()
+
+	dead, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	target := dead.Addr().String()
+	dead.Close()
+
+	conn, err := grpc.Dial(target, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	conn.Connect()
+
+	// Wait for the failed connection attempt to be traced
+	var channel *Channel
+	for deadline := time.Now().Add(5 * time.Second); channel == nil; {
+		if time.Now().After(deadline) {
+			t.Fatal("the failed connection was not reported")
+		}
+		summary, err := inspector.Summary(context.Background())
+		if err != nil {
+			t.Fatalf("Summary() error = %v", err)
+		}
+		for i, ch := range summary.Channels {
+			if ch.Target == inspectorTarget {
+				t.Fatal("Summary() includes the inspector's own channel")
+			}
+			if ch.Target == target && len(ch.Subchannels) > 0 && ch.Subchannels[0].LastError != nil {
+				channel = &summary.Channels[i]
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if channel.Subchannels[0].State != "TRANSIENT_FAILURE" {
+		t.Errorf("subchannel state = %s, want TRANSIENT_FAILURE", channel.Subchannels[0].State)
+	}
+
+	rec := httptest.NewRecorder()
+	inspector.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/channelz", nil))
+	var summary Summary
+	if err := json.NewDecoder(rec.Body).Decode(&summary); err != nil {
+		t.Fatalf("failed to decode summary: %v", err)
+	}
+	listening := false
+	for _, s := range summary.Servers {
+		if s.ListenSockets > 0 {
+			listening = true
+		}
+	}
+	if !listening {
+		t.Errorf("servers = %+v, want the listening server", summary.Servers)
+	}
+}
@@ -17,9 +17,13 @@
 package config
 
 import (
+	"errors"
+	"flag"
 	"fmt"
+	"path"
 	"reflect"
 	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 	"gopkg.in/yaml.v3"
@@ -32,9 +36,60 @@ type GlobalConfig interface {
 
 // Config holds the entire application configuration
 type Config struct {
-	Server   *Server   `yaml:"server" mapstructure:"server"`
-	Database *Database `yaml:"database" mapstructure:"database"`
-	Log      *Log      `yaml:"log" mapstructure:"log"`
+	Server    *Server    `yaml:"server" mapstructure:"server"`
+	Database  *Database  `yaml:"database" mapstructure:"database"`
+	Log       *Log       `yaml:"log" mapstructure:"log"`
+	Events    *Events    `yaml:"events" mapstructure:"events"`
+	Outbox    *Outbox    `yaml:"outbox" mapstructure:"outbox"`
+	Scheduler *Scheduler `yaml:"scheduler" mapstructure:"scheduler"`
+	Webhook   *Webhook   `yaml:"webhook" mapstructure:"webhook"`
+	Search    *Search    `yaml:"search" mapstructure:"search"`
+	Audit     *Audit     `yaml:"audit" mapstructure:"audit"`
+	Shipping  *Shipping  `yaml:"shipping" mapstructure:"shipping"`
+	Tenancy   *Tenancy   `yaml:"tenancy" mapstructure:"tenancy"`
+	Jobs      *Jobs      `yaml:"jobs" mapstructure:"jobs"`
+	Mail      *Mail      `yaml:"mail" mapstructure:"mail"`
+	Storage   *Storage   `yaml:"storage" mapstructure:"storage"`
+	Session   *Session   `yaml:"session" mapstructure:"session"`
+	Currency  *Currency  `yaml:"currency" mapstructure:"currency"`
+	BFF       *BFF       `yaml:"bff" mapstructure:"bff"`
+	Retention *Retention `yaml:"retention" mapstructure:"retention"`
+	Leader    *Leader    `yaml:"leader" mapstructure:"leader"`
+	CORS      *CORS      `yaml:"cors" mapstructure:"cors"`
+	SLO       *SLO       `yaml:"slo" mapstructure:"slo"`
+	Cache     *Cache     `yaml:"cache" mapstructure:"cache"`
+	// Observability configures metrics and tracing for every service
+	Observability *Observability `yaml:"observability" mapstructure:"observability"`
+	// Deprecation announces the retirement of the v1 REST API
+	Deprecation *Deprecation `yaml:"deprecation" mapstructure:"deprecation"`
+	// Encryption configures application-level encryption of personal data
+	Encryption *Encryption `yaml:"encryption" mapstructure:"encryption"`
+	// RateLimit configures the request quotas of the gateway and gRPC servers
+	RateLimit *RateLimit `yaml:"rate_limit" mapstructure:"rate_limit"`
+	// Pagination configures the page tokens of list endpoints
+	Pagination *Pagination `yaml:"pagination" mapstructure:"pagination"`
+	// Auth configures bearer token authentication of API calls
+	Auth *Auth `yaml:"auth" mapstructure:"auth"`
+	// Gateway configures the gateway's connections to the backend services
+	Gateway *Gateway `yaml:"gateway" mapstructure:"gateway"`
+	// UserValidation configures the order service's check that the users
+	// orders are created for exist
+	UserValidation *UserValidation `yaml:"user_validation" mapstructure:"user_validation"`
+	// Catalog configures how the order service prices order items with the
+	// product service
+	Catalog *Catalog `yaml:"catalog" mapstructure:"catalog"`
+	// Inventory configures the stock reservations of orders
+	Inventory *Inventory `yaml:"inventory" mapstructure:"inventory"`
+	// Idempotency configures the idempotency keys of CreateOrder and
+	// CreateUser
+	Idempotency *Idempotency `yaml:"idempotency" mapstructure:"idempotency"`
+	// TLS secures the gRPC connections between the gateway and the
+	// services
+	TLS *TLS `yaml:"tls" mapstructure:"tls"`
+	// Password configures how user passwords are hashed
+	Password *Password `yaml:"password" mapstructure:"password"`
+	// APIKeys configures the gateway's authentication of API keys
+	APIKeys *APIKeys `yaml:"api_keys" mapstructure:"api_keys"`
 }
 
 // Server configuration
@@ -43,6 +98,64 @@ type Server struct {
 	Port     int    `yaml:"port" mapstructure:"port"`
 	GRPCPort int    `yaml:"grpc_port" mapstructure:"grpc_port"`
 	Mode     string `yaml:"mode" mapstructure:"mode"`
+	// GRPCSocket also serves gRPC on a unix domain socket at this path,
+	// for callers in the same pod or host
+	GRPCSocket string `yaml:"grpc_socket" mapstructure:"grpc_socket"`
+	// RequestTimeout bounds each unary gRPC call and HTTP request; zero
+	// leaves them unbounded. It can be changed while the server runs.
+	RequestTimeout time.Duration `yaml:"request_timeout" mapstructure:"request_timeout"`
+	// Keepalive tunes gRPC keepalive pings and connection ages; nil leaves
+	// the gRPC defaults
+	Keepalive *Keepalive `yaml:"keepalive" mapstructure:"keepalive"`
+}
+
+// Keepalive configures gRPC keepalive, so that idle connections survive
+// NAT and load balancer idle timeouts. The client fields apply to the
+// connections a service dials, the server fields to the connections it
+// accepts.
+type Keepalive struct {
+	// Time is how long a connection may be idle before either end pings
+	// it; zero leaves clients without pings and servers at two hours
+	Time time.Duration `yaml:"time" mapstructure:"time"`
+	// Timeout is how long a client waits for a ping ack before it closes
+	// the connection
+	Timeout time.Duration `yaml:"timeout" mapstructure:"timeout"`
+	// PermitWithoutStream pings connections with no active calls, which
+	// is what keeps otherwise idle connections open
+	PermitWithoutStream bool `yaml:"permit_without_stream" mapstructure:"permit_without_stream"`
+	// MinTime is the shortest ping interval a server allows; clients
+	// that ping more often are disconnected. Zero uses the gRPC default
+	// of five minutes.
+	MinTime time.Duration `yaml:"min_time" mapstructure:"min_time"`
+	// MaxConnectionIdle closes server connections without calls for this
+	// long; zero never closes them
+	MaxConnectionIdle time.Duration `yaml:"max_connection_idle" mapstructure:"max_connection_idle"`
+	// MaxConnectionAge closes server connections this old, so clients
+	// reconnect and spread across replicas; zero never closes them
+	MaxConnectionAge time.Duration `yaml:"max_connection_age" mapstructure:"max_connection_age"`
+	// MaxConnectionAgeGrace is how long calls may run on a connection
+	// closed for its age
+	MaxConnectionAgeGrace time.Duration `yaml:"max_connection_age_grace" mapstructure:"max_connection_age_grace"`
+}
+
+// TLS configures transport security of gRPC servers and of the
+// connections services make to each other. Every process presents the
+// certificate in CertFile, as a server and as a client, and verifies its
+// peers against the CAs in CAFile, or the system roots when it is empty.
+type TLS struct {
+	Enabled  bool   `yaml:"enabled" mapstructure:"enabled"`
+	CertFile string `yaml:"cert_file" mapstructure:"cert_file"`
+	KeyFile  string `yaml:"key_file" mapstructure:"key_file"`
+	CAFile   string `yaml:"ca_file" mapstructure:"ca_file"`
+	// ClientAuth is whether servers verify client certificates: none,
+	// optional (when one is presented) or require, for mutual TLS
+	ClientAuth string `yaml:"client_auth" mapstructure:"client_auth"`
+	// ServerName is the name clients verify server certificates against;
+	// empty uses the host of the endpoint
+	ServerName string `yaml:"server_name" mapstructure:"server_name"`
+	// ReloadInterval is how often the files are checked for rotated
+	// certificates; zero loads them once
+	ReloadInterval time.Duration `yaml:"reload_interval" mapstructure:"reload_interval"`
 }
 
 // Database configuration
@@ -53,12 +166,489 @@ type Database struct {
 	Password string `yaml:"password" mapstructure:"password"`
 	Name     string `yaml:"name" mapstructure:"name"`
 	SSLMode  string `yaml:"ssl_mode" mapstructure:"ssl_mode"`
+	// MigrateOnStart applies the embedded migrations when a service
+	// connects, for development setups without a separate migration step
+	MigrateOnStart bool `yaml:"migrate_on_start" mapstructure:"migrate_on_start"`
+	// MaxOpenConns bounds the connections of each process; MaxIdleConns of
+	// them are kept open while idle
+	MaxOpenConns int `yaml:"max_open_conns" mapstructure:"max_open_conns"`
+	MaxIdleConns int `yaml:"max_idle_conns" mapstructure:"max_idle_conns"`
+	// ConnMaxLifetime and ConnMaxIdleTime close connections that have
+	// been open, or idle, that long; zero keeps them
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime" mapstructure:"conn_max_lifetime"`
+	ConnMaxIdleTime time.Duration `yaml:"conn_max_idle_time" mapstructure:"conn_max_idle_time"`
+	// Driver is "pq" (lib/pq) or "pgx" (pgx through database/sql)
+	Driver string `yaml:"driver" mapstructure:"driver"`
+	// StatementCacheCapacity is how many prepared statements pgx caches
+	// per connection; zero prepares none, for poolers such as PgBouncer
+	// in transaction mode
+	StatementCacheCapacity int `yaml:"statement_cache_capacity" mapstructure:"statement_cache_capacity"`
 }
 
 // Log configuration
 type Log struct {
 	Level  string `yaml:"level" mapstructure:"level"`
 	Format string `yaml:"format" mapstructure:"format"`
+	// LevelEndpoint serves the log level on /admin/loglevel so that it can
+	// be changed without a restart or a config reload
+	LevelEndpoint bool `yaml:"level_endpoint" mapstructure:"level_endpoint"`
+	// Access configures the gateway's HTTP access log
+	Access AccessLog `yaml:"access" mapstructure:"access"`
+}
+
+// AccessLog configures the line the gateway logs for each HTTP request
+type AccessLog struct {
+	// SampleRate is the fraction of successful requests logged, from 0 to
+	// 1. Requests that fail or are slow are always logged.
+	SampleRate float64 `yaml:"sample_rate" mapstructure:"sample_rate"`
+	// SlowThreshold is the duration from which a request is slow; 0 treats
+	// no request as slow
+	SlowThreshold time.Duration `yaml:"slow_threshold" mapstructure:"slow_threshold"`
+}
+
+// Events configuration
+type Events struct {
+	Driver   string   `yaml:"driver" mapstructure:"driver"`
+	Brokers  []string `yaml:"brokers" mapstructure:"brokers"`
+	NATSURL  string   `yaml:"nats_url" mapstructure:"nats_url"`
+	ClientID string   `yaml:"client_id" mapstructure:"client_id"`
+}
+
+// Outbox configuration
+type Outbox struct {
+	PollInterval time.Duration `yaml:"poll_interval" mapstructure:"poll_interval"`
+	BatchSize    int           `yaml:"batch_size" mapstructure:"batch_size"`
+	MaxAttempts  int           `yaml:"max_attempts" mapstructure:"max_attempts"`
+	// Sink is where the relay publishes events: events (the configured
+	// event bus), log or webhook
+	Sink    string         `yaml:"sink" mapstructure:"sink"`
+	Webhook *OutboxWebhook `yaml:"webhook" mapstructure:"webhook"`
+}
+
+// OutboxWebhook configures the webhook outbox sink
+type OutboxWebhook struct {
+	URL string `yaml:"url" mapstructure:"url"`
+	// Secret signs request bodies when set
+	Secret  string        `yaml:"secret" mapstructure:"secret"`
+	Timeout time.Duration `yaml:"timeout" mapstructure:"timeout"`
+}
+
+// Scheduler configuration
+type Scheduler struct {
+	Enabled             bool          `yaml:"enabled" mapstructure:"enabled"`
+	OrderExpirySchedule string        `yaml:"order_expiry_schedule" mapstructure:"order_expiry_schedule"`
+	PendingOrderTTL     time.Duration `yaml:"pending_order_ttl" mapstructure:"pending_order_ttl"`
+	// RecommendationSchedule is when product recommendations are rebuilt
+	RecommendationSchedule string `yaml:"recommendation_schedule" mapstructure:"recommendation_schedule"`
+	// ReservationExpirySchedule is when pending orders whose stock
+	// reservation lapsed are cancelled
+	ReservationExpirySchedule string `yaml:"reservation_expiry_schedule" mapstructure:"reservation_expiry_schedule"`
+}
+
+// Webhook configuration
+type Webhook struct {
+	Topics         []string      `yaml:"topics" mapstructure:"topics"`
+	PollInterval   time.Duration `yaml:"poll_interval" mapstructure:"poll_interval"`
+	BatchSize      int           `yaml:"batch_size" mapstructure:"batch_size"`
+	MaxAttempts    int           `yaml:"max_attempts" mapstructure:"max_attempts"`
+	InitialBackoff time.Duration `yaml:"initial_backoff" mapstructure:"initial_backoff"`
+	MaxBackoff     time.Duration `yaml:"max_backoff" mapstructure:"max_backoff"`
+	Timeout        time.Duration `yaml:"timeout" mapstructure:"timeout"`
+}
+
+// Search configuration
+type Search struct {
+	Driver           string   `yaml:"driver" mapstructure:"driver"`
+	Path             string   `yaml:"path" mapstructure:"path"`
+	ElasticsearchURL string   `yaml:"elasticsearch_url" mapstructure:"elasticsearch_url"`
+	Index            string   `yaml:"index" mapstructure:"index"`
+	Topics           []string `yaml:"topics" mapstructure:"topics"`
+}
+
+// Audit configuration
+type Audit struct {
+	Enabled bool   `yaml:"enabled" mapstructure:"enabled"`
+	Topic   string `yaml:"topic" mapstructure:"topic"`
+}
+
+// Shipping configuration
+type Shipping struct {
+	OrderServiceEndpoint string   `yaml:"order_service_endpoint" mapstructure:"order_service_endpoint"`
+	Carriers             []string `yaml:"carriers" mapstructure:"carriers"`
+	DefaultCarrier       string   `yaml:"default_carrier" mapstructure:"default_carrier"`
+	CallbackSecret       string   `yaml:"callback_secret" mapstructure:"callback_secret"`
+}
+
+// Tenancy configuration
+type Tenancy struct {
+	Required      bool     `yaml:"required" mapstructure:"required"`
+	DefaultTenant string   `yaml:"default_tenant" mapstructure:"default_tenant"`
+	Tenants       []string `yaml:"tenants" mapstructure:"tenants"`
+}
+
+// Jobs configuration
+type Jobs struct {
+	Queues         []string      `yaml:"queues" mapstructure:"queues"`
+	Concurrency    int           `yaml:"concurrency" mapstructure:"concurrency"`
+	PollInterval   time.Duration `yaml:"poll_interval" mapstructure:"poll_interval"`
+	Lease          time.Duration `yaml:"lease" mapstructure:"lease"`
+	InitialBackoff time.Duration `yaml:"initial_backoff" mapstructure:"initial_backoff"`
+	MaxBackoff     time.Duration `yaml:"max_backoff" mapstructure:"max_backoff"`
+}
+
+// Mail configuration
+type Mail struct {
+	Host     string `yaml:"host" mapstructure:"host"`
+	Port     int    `yaml:"port" mapstructure:"port"`
+	Username string `yaml:"username" mapstructure:"username"`
+	Password string `yaml:"password" mapstructure:"password"`
+	From     string `yaml:"from" mapstructure:"from"`
+}
+
+// Storage configuration
+type Storage struct {
+	Driver          string `yaml:"driver" mapstructure:"driver"`
+	Bucket          string `yaml:"bucket" mapstructure:"bucket"`
+	Region          string `yaml:"region" mapstructure:"region"`
+	Endpoint        string `yaml:"endpoint" mapstructure:"endpoint"`
+	PathStyle       bool   `yaml:"path_style" mapstructure:"path_style"`
+	AccessKeyID     string `yaml:"access_key_id" mapstructure:"access_key_id"`
+	SecretAccessKey string `yaml:"secret_access_key" mapstructure:"secret_access_key"`
+	LocalDir        string `yaml:"local_dir" mapstructure:"local_dir"`
+	BaseURL         string `yaml:"base_url" mapstructure:"base_url"`
+	SigningSecret   string `yaml:"signing_secret" mapstructure:"signing_secret"`
+}
+
+// Session configuration
+type Session struct {
+	Enabled       bool          `yaml:"enabled" mapstructure:"enabled"`
+	RedisAddr     string        `yaml:"redis_addr" mapstructure:"redis_addr"`
+	RedisPassword string        `yaml:"redis_password" mapstructure:"redis_password"`
+	RedisDB       int           `yaml:"redis_db" mapstructure:"redis_db"`
+	IdleTimeout   time.Duration `yaml:"idle_timeout" mapstructure:"idle_timeout"`
+	MaxLifetime   time.Duration `yaml:"max_lifetime" mapstructure:"max_lifetime"`
+	CookieName    string        `yaml:"cookie_name" mapstructure:"cookie_name"`
+	CookieSecure  bool          `yaml:"cookie_secure" mapstructure:"cookie_secure"`
+}
+
+// Currency configuration
+type Currency struct {
+	Base                   string        `yaml:"base" mapstructure:"base"`
+	Provider               string        `yaml:"provider" mapstructure:"provider"`
+	OpenExchangeRatesAppID string        `yaml:"openexchangerates_app_id" mapstructure:"openexchangerates_app_id"`
+	RefreshInterval        time.Duration `yaml:"refresh_interval" mapstructure:"refresh_interval"`
+}
+
+// BFF configuration
+type BFF struct {
+	UserTimeout      time.Duration `yaml:"user_timeout" mapstructure:"user_timeout"`
+	OrdersTimeout    time.Duration `yaml:"orders_timeout" mapstructure:"orders_timeout"`
+	ShipmentsTimeout time.Duration `yaml:"shipments_timeout" mapstructure:"shipments_timeout"`
+	ProductsTimeout  time.Duration `yaml:"products_timeout" mapstructure:"products_timeout"`
+	RecentOrders     int           `yaml:"recent_orders" mapstructure:"recent_orders"`
+}
+
+// Gateway configures how the gateway connects to and calls the backend
+// services
+type Gateway struct {
+	// ConnectTimeout bounds each connection attempt; failed attempts are
+	// retried in the background with exponential backoff
+	ConnectTimeout    time.Duration `yaml:"connect_timeout" mapstructure:"connect_timeout"`
+	BackoffBaseDelay  time.Duration `yaml:"backoff_base_delay" mapstructure:"backoff_base_delay"`
+	BackoffMultiplier float64       `yaml:"backoff_multiplier" mapstructure:"backoff_multiplier"`
+	BackoffMaxDelay   time.Duration `yaml:"backoff_max_delay" mapstructure:"backoff_max_delay"`
+	// Keepalive pings idle backend connections; nil uses server.keepalive
+	Keepalive *Keepalive `yaml:"keepalive" mapstructure:"keepalive"`
+	// CallTimeout bounds unary backend calls; zero leaves them unbounded
+	CallTimeout time.Duration `yaml:"call_timeout" mapstructure:"call_timeout"`
+	// MethodTimeouts overrides CallTimeout by "package.Service/Method" or
+	// by "package.Service". Streaming methods are only bounded here.
+	MethodTimeouts map[string]time.Duration `yaml:"method_timeouts" mapstructure:"method_timeouts"`
+	// RetryAttempts is the total number of attempts of idempotent calls
+	// that fail with UNAVAILABLE, including the first; gRPC caps it at
+	// five and values below two disable retries
+	RetryAttempts       int           `yaml:"retry_attempts" mapstructure:"retry_attempts"`
+	RetryInitialBackoff time.Duration `yaml:"retry_initial_backoff" mapstructure:"retry_initial_backoff"`
+	RetryMaxBackoff     time.Duration `yaml:"retry_max_backoff" mapstructure:"retry_max_backoff"`
+}
+
+// UserValidation configures how the order service checks with the user
+// service that a user exists before creating an order for it. Disabling it
+// runs the order service offline, without the user service.
+type UserValidation struct {
+	Enabled             bool   `yaml:"enabled" mapstructure:"enabled"`
+	UserServiceEndpoint string `yaml:"user_service_endpoint" mapstructure:"user_service_endpoint"`
+	// Timeout bounds each lookup
+	Timeout time.Duration `yaml:"timeout" mapstructure:"timeout"`
+	// FailOpen creates orders unchecked while the user service is failing,
+	// rather than rejecting them
+	FailOpen bool `yaml:"fail_open" mapstructure:"fail_open"`
+	// BreakerThreshold consecutive failed lookups stop lookups for
+	// BreakerCooldown
+	BreakerThreshold int           `yaml:"breaker_threshold" mapstructure:"breaker_threshold"`
+	BreakerCooldown  time.Duration `yaml:"breaker_cooldown" mapstructure:"breaker_cooldown"`
+}
+
+// Catalog configures how the order service prices the items of new orders
+// with the product service. Disabling it trusts the prices clients send.
+type Catalog struct {
+	Enabled                bool   `yaml:"enabled" mapstructure:"enabled"`
+	ProductServiceEndpoint string `yaml:"product_service_endpoint" mapstructure:"product_service_endpoint"`
+	// Timeout bounds each lookup
+	Timeout time.Duration `yaml:"timeout" mapstructure:"timeout"`
+}
+
+// Inventory configures how long new orders hold the stock of their items.
+// A pending order is cancelled once its reservation lapses.
+type Inventory struct {
+	ReservationTTL time.Duration `yaml:"reservation_ttl" mapstructure:"reservation_ttl"`
+}
+
+// Idempotency configures how long idempotency keys are remembered.
+// LockTimeout bounds how long a request that never completed, say because
+// its replica crashed, holds its key.
+type Idempotency struct {
+	Enabled       bool          `yaml:"enabled" mapstructure:"enabled"`
+	TTL           time.Duration `yaml:"ttl" mapstructure:"ttl"`
+	LockTimeout   time.Duration `yaml:"lock_timeout" mapstructure:"lock_timeout"`
+	PurgeSchedule string        `yaml:"purge_schedule" mapstructure:"purge_schedule"`
+}
+
+// Retention configuration. A zero retention period disables its policy.
+type Retention struct {
+	Enabled             bool          `yaml:"enabled" mapstructure:"enabled"`
+	Schedule            string        `yaml:"schedule" mapstructure:"schedule"`
+	DryRun              bool          `yaml:"dry_run" mapstructure:"dry_run"`
+	BatchSize           int           `yaml:"batch_size" mapstructure:"batch_size"`
+	UserPurgeAfter      time.Duration `yaml:"user_purge_after" mapstructure:"user_purge_after"`
+	OrderAnonymizeAfter time.Duration `yaml:"order_anonymize_after" mapstructure:"order_anonymize_after"`
+}
+
+// Leader configures leader election for workers that must run on a single
+// replica. Disabled, every replica runs them.
+type Leader struct {
+	Enabled bool `yaml:"enabled" mapstructure:"enabled"`
+	// RetryInterval is how often a follower tries to take leadership
+	RetryInterval time.Duration `yaml:"retry_interval" mapstructure:"retry_interval"`
+	// CheckInterval is how often the leader verifies it still holds the lock
+	CheckInterval time.Duration `yaml:"check_interval" mapstructure:"check_interval"`
+}
+
+// CORS configures the cross-origin requests the gateway allows. It can be
+// changed while the gateway runs.
+type CORS struct {
+	// AllowedOrigins are origins, or patterns in which "*" matches any run
+	// of characters but "/", such as "https://*.example.com". "*" alone
+	// allows any origin, which AllowCredentials rules out.
+	AllowedOrigins []string `yaml:"allowed_origins" mapstructure:"allowed_origins"`
+	AllowedMethods []string `yaml:"allowed_methods" mapstructure:"allowed_methods"`
+	// AllowedHeaders are request headers allowed in addition to those the
+	// gateway reads itself
+	AllowedHeaders []string `yaml:"allowed_headers" mapstructure:"allowed_headers"`
+	// MaxAge is how long browsers may cache a preflight response
+	MaxAge           time.Duration `yaml:"max_age" mapstructure:"max_age"`
+	AllowCredentials bool          `yaml:"allow_credentials" mapstructure:"allow_credentials"`
+}
+
+// Deprecation configures the Deprecation and Sunset headers the gateway
+// sends on v1 REST responses, as RFC 3339 timestamps. Empty values leave
+// the header out; the successor-version link is always sent.
+type Deprecation struct {
+	// Since is when v1 was deprecated
+	Since string `yaml:"since" mapstructure:"since"`
+	// Sunset is when v1 stops being served
+	Sunset string `yaml:"sunset" mapstructure:"sunset"`
+}
+
+// Encryption configures envelope encryption of personal data at rest.
+// SecretKeys holds the local key encryption keys as "id:base64 key"
+// entries; KeyID names the one new data keys are wrapped with, while the
+// others stay readable until their values are encrypted again.
+type Encryption struct {
+	Enabled     bool          `yaml:"enabled" mapstructure:"enabled"`
+	Provider    string        `yaml:"provider" mapstructure:"provider"`
+	KeyID       string        `yaml:"key_id" mapstructure:"key_id"`
+	SecretKeys  []string      `yaml:"secret_keys" mapstructure:"secret_keys"`
+	IndexSecret string        `yaml:"index_secret" mapstructure:"index_secret"`
+	DataKeyTTL  time.Duration `yaml:"data_key_ttl" mapstructure:"data_key_ttl"`
+}
+
+// Password configures the hashing of user passwords. Algorithm is bcrypt
+// or argon2id and applies to new hashes; stored hashes of the other
+// algorithm still verify and are replaced on the next successful login.
+// Argon2Memory is in KiB.
+type Password struct {
+	Algorithm         string `yaml:"algorithm" mapstructure:"algorithm"`
+	MinLength         int    `yaml:"min_length" mapstructure:"min_length"`
+	BcryptCost        int    `yaml:"bcrypt_cost" mapstructure:"bcrypt_cost"`
+	Argon2Memory      uint32 `yaml:"argon2_memory" mapstructure:"argon2_memory"`
+	Argon2Iterations  uint32 `yaml:"argon2_iterations" mapstructure:"argon2_iterations"`
+	Argon2Parallelism uint8  `yaml:"argon2_parallelism" mapstructure:"argon2_parallelism"`
+}
+
+// APIKeys configures API keys. When enabled, the gateway authenticates
+// requests carrying an X-API-Key header with the keys in the database,
+// remembering each lookup for CacheTTL; a revoked key is refused at the
+// latest CacheTTL later.
+type APIKeys struct {
+	Enabled  bool          `yaml:"enabled" mapstructure:"enabled"`
+	CacheTTL time.Duration `yaml:"cache_ttl" mapstructure:"cache_ttl"`
+}
+
+// RateLimit configures token bucket quotas. The gateway allows every tenant
+// Requests per Window, in bursts of up to Burst (Requests when 0), unless
+// Quotas overrides it, every client IP address the ClientIP quota and every
+// API key the APIKey quota. The gRPC servers apply Methods, keyed by full
+// method name ("/order.v1.OrderService/CreateOrder") or by service
+// ("/order.v1.OrderService/") to share one bucket across its methods.
+// Buckets are shared through Redis when RedisAddr is set and kept per
+// replica otherwise.
+type RateLimit struct {
+	Enabled       bool             `yaml:"enabled" mapstructure:"enabled"`
+	RedisAddr     string           `yaml:"redis_addr" mapstructure:"redis_addr"`
+	RedisPassword string           `yaml:"redis_password" mapstructure:"redis_password"`
+	RedisDB       int              `yaml:"redis_db" mapstructure:"redis_db"`
+	Requests      int              `yaml:"requests" mapstructure:"requests"`
+	Window        time.Duration    `yaml:"window" mapstructure:"window"`
+	Burst         int              `yaml:"burst" mapstructure:"burst"`
+	Quotas        []RateLimitQuota `yaml:"quotas" mapstructure:"quotas"`
+	// ClientIP limits each client IP address; zero requests leaves
+	// addresses unlimited
+	ClientIP RateLimitBucket `yaml:"client_ip" mapstructure:"client_ip"`
+	// APIKey limits each API key; zero requests leaves keys unlimited
+	APIKey RateLimitBucket `yaml:"api_key" mapstructure:"api_key"`
+	// TrustForwardedFor takes the client IP address from the last
+	// X-Forwarded-For entry, which is only safe behind a proxy that sets it
+	TrustForwardedFor bool             `yaml:"trust_forwarded_for" mapstructure:"trust_forwarded_for"`
+	Methods           []RateLimitQuota `yaml:"methods" mapstructure:"methods"`
+}
+
+// RateLimitBucket allows Requests per Window, in bursts of up to Burst
+// (Requests when 0)
+type RateLimitBucket struct {
+	Requests int           `yaml:"requests" mapstructure:"requests"`
+	Window   time.Duration `yaml:"window" mapstructure:"window"`
+	Burst    int           `yaml:"burst" mapstructure:"burst"`
+}
+
+// RateLimitQuota overrides the quota of one key. A quota of zero requests
+// leaves the key unlimited.
+type RateLimitQuota struct {
+	Key      string        `yaml:"key" mapstructure:"key"`
+	Requests int           `yaml:"requests" mapstructure:"requests"`
+	Window   time.Duration `yaml:"window" mapstructure:"window"`
+	Burst    int           `yaml:"burst" mapstructure:"burst"`
+}
+
+// Pagination configures page tokens. Secret signs them and must be shared
+// by every replica of a service; when empty, each process signs with a
+// random key, so tokens only work on the replica that issued them.
+type Pagination struct {
+	Secret string `yaml:"secret" mapstructure:"secret"`
+}
+
+// Auth configures JWT bearer token authentication. Tokens are signed with
+// Secret; PreviousSecrets still verify, so secrets can be rotated without
+// logging everyone out. PublicPaths (gateway routes) and PublicMethods (full
+// gRPC method names) are served without a token; an entry ending in "*"
+// matches every path or method with that prefix. RefreshTokenTTL is how
+// long a login can be kept alive through the user service's AuthService.
+// Every other gRPC method needs a policy in Policies granting one of the
+// caller's roles; methods without one are denied.
+type Auth struct {
+	Enabled         bool          `yaml:"enabled" mapstructure:"enabled"`
+	Secret          string        `yaml:"secret" mapstructure:"secret"`
+	PreviousSecrets []string      `yaml:"previous_secrets" mapstructure:"previous_secrets"`
+	Issuer          string        `yaml:"issuer" mapstructure:"issuer"`
+	Audience        string        `yaml:"audience" mapstructure:"audience"`
+	TokenTTL        time.Duration `yaml:"token_ttl" mapstructure:"token_ttl"`
+	RefreshTokenTTL time.Duration `yaml:"refresh_token_ttl" mapstructure:"refresh_token_ttl"`
+	Leeway          time.Duration `yaml:"leeway" mapstructure:"leeway"`
+	PublicPaths     []string      `yaml:"public_paths" mapstructure:"public_paths"`
+	PublicMethods   []string      `yaml:"public_methods" mapstructure:"public_methods"`
+	Policies        []AuthPolicy  `yaml:"policies" mapstructure:"policies"`
+}
+
+// AuthPolicy grants the callers holding any of Roles access to Method, a
+// full gRPC method name or, ending in "*", every method with that prefix.
+// The most specific policy of a method applies. The role "*" grants every
+// authenticated caller.
+type AuthPolicy struct {
+	Method string   `yaml:"method" mapstructure:"method"`
+	Roles  []string `yaml:"roles" mapstructure:"roles"`
+}
+
+// SLO configures the service level objectives tracked for each RPC method.
+// Methods without an override in Methods share the default objectives.
+type SLO struct {
+	Enabled bool `yaml:"enabled" mapstructure:"enabled"`
+	// Availability is the target ratio of calls that do not fail with a
+	// server error
+	Availability float64 `yaml:"availability" mapstructure:"availability"`
+	// LatencyTarget is the target ratio of calls that complete within
+	// LatencyThreshold
+	LatencyThreshold time.Duration `yaml:"latency_threshold" mapstructure:"latency_threshold"`
+	LatencyTarget    float64       `yaml:"latency_target" mapstructure:"latency_target"`
+	// Windows are the rolling windows burn rates are computed over
+	Windows []time.Duration `yaml:"windows" mapstructure:"windows"`
+	Methods []SLOMethod     `yaml:"methods" mapstructure:"methods"`
+}
+
+// SLOMethod overrides the objectives of one RPC method, named by its full
+// gRPC method name. Zero fields keep the defaults.
+type SLOMethod struct {
+	Method           string        `yaml:"method" mapstructure:"method"`
+	Availability     float64       `yaml:"availability" mapstructure:"availability"`
+	LatencyThreshold time.Duration `yaml:"latency_threshold" mapstructure:"latency_threshold"`
+	LatencyTarget    float64       `yaml:"latency_target" mapstructure:"latency_target"`
+}
+
+// Cache configures the read-through cache in front of hot lookups. Entries
+// are kept in Redis when an address is configured, and in process
+// otherwise, where each replica caches on its own.
+type Cache struct {
+	Enabled       bool   `yaml:"enabled" mapstructure:"enabled"`
+	RedisAddr     string `yaml:"redis_addr" mapstructure:"redis_addr"`
+	RedisPassword string `yaml:"redis_password" mapstructure:"redis_password"`
+	RedisDB       int    `yaml:"redis_db" mapstructure:"redis_db"`
+	// TTL is how long users stay cached
+	TTL time.Duration `yaml:"ttl" mapstructure:"ttl"`
+	// OrderTTL is how long orders stay cached. Expiry jobs change orders
+	// without invalidating them, so it bounds how stale a cached order is.
+	OrderTTL time.Duration `yaml:"order_ttl" mapstructure:"order_ttl"`
+	// MaxEntries bounds the in-process cache
+	MaxEntries int `yaml:"max_entries" mapstructure:"max_entries"`
+}
+
+// Observability configuration
+type Observability struct {
+	MetricsEnabled   bool   `yaml:"metrics_enabled" mapstructure:"metrics_enabled"`
+	MetricsPath      string `yaml:"metrics_path" mapstructure:"metrics_path"`
+	MetricsNamespace string `yaml:"metrics_namespace" mapstructure:"metrics_namespace"`
+	// MetricsPort serves metrics on a separate port; zero serves them on
+	// server.port next to the API
+	MetricsPort int `yaml:"metrics_port" mapstructure:"metrics_port"`
+	// RuntimeMetrics adds the Go runtime and process collectors
+	RuntimeMetrics bool `yaml:"runtime_metrics" mapstructure:"runtime_metrics"`
+	// Traces are exported over OTLP/gRPC to TracingEndpoint; a ratio of
+	// new traces is sampled, and traces started by a caller follow its
+	// sampling decision
+	TracingEnabled     bool    `yaml:"tracing_enabled" mapstructure:"tracing_enabled"`
+	TracingEndpoint    string  `yaml:"tracing_endpoint" mapstructure:"tracing_endpoint"`
+	TracingInsecure    bool    `yaml:"tracing_insecure" mapstructure:"tracing_insecure"`
+	TracingSampleRatio float64 `yaml:"tracing_sample_ratio" mapstructure:"tracing_sample_ratio"`
+	// ChannelzEnabled serves a summary of the gRPC channels and servers
+	// on /debug/channelz
+	ChannelzEnabled bool `yaml:"channelz_enabled" mapstructure:"channelz_enabled"`
+	// RuntimeStatsInterval logs goroutine, memory, GC and database pool
+	// stats at this interval; zero disables the reports
+	RuntimeStatsInterval time.Duration `yaml:"runtime_stats_interval" mapstructure:"runtime_stats_interval"`
+	// ErrorReporter sends internal errors and panics to an error tracker:
+	// "" (none) or "sentry"
+	ErrorReporter     string  `yaml:"error_reporter" mapstructure:"error_reporter"`
+	SentryDSN         string  `yaml:"sentry_dsn" mapstructure:"sentry_dsn"`
+	SentryEnvironment string  `yaml:"sentry_environment" mapstructure:"sentry_environment"`
+	SentrySampleRate  float64 `yaml:"sentry_sample_rate" mapstructure:"sentry_sample_rate"`
 }
 
 // Bind binds environment variables to config struct
@@ -77,6 +667,221 @@ func (c *Config) Bind() error {
 	return v.Unmarshal(c)
 }
 
+// Validate reports every setting that is out of range or inconsistent
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...interface{}) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	if c.Server != nil {
+		check(validPort(c.Server.Port), "server.port %d is not a valid port", c.Server.Port)
+		check(validPort(c.Server.GRPCPort), "server.grpc_port %d is not a valid port", c.Server.GRPCPort)
+		check(c.Server.RequestTimeout >= 0, "server.request_timeout must not be negative")
+		if k := c.Server.Keepalive; k != nil {
+			check(k.Time >= 0 && k.Timeout >= 0 && k.MinTime >= 0 && k.MaxConnectionIdle >= 0 &&
+				k.MaxConnectionAge >= 0 && k.MaxConnectionAgeGrace >= 0, "server.keepalive durations must not be negative")
+			check(k.Time == 0 || (k.MinTime > 0 && k.MinTime <= k.Time),
+				"server.keepalive.min_time %v must not exceed server.keepalive.time %v", k.MinTime, k.Time)
+		}
+	}
+	if c.Database != nil {
+		check(c.Database.Host != "", "database.host is required")
+		check(validPort(c.Database.Port) && c.Database.Port != 0, "database.port %d is not a valid port", c.Database.Port)
+		check(c.Database.Name != "", "database.name is required")
+		check(c.Database.MaxOpenConns > 0, "database.max_open_conns must be positive")
+		check(c.Database.MaxIdleConns >= 0 && c.Database.MaxIdleConns <= c.Database.MaxOpenConns,
+			"database.max_idle_conns must be between 0 and database.max_open_conns")
+		check(c.Database.ConnMaxLifetime >= 0, "database.conn_max_lifetime must not be negative")
+		check(c.Database.ConnMaxIdleTime >= 0, "database.conn_max_idle_time must not be negative")
+		check(c.Database.Driver == "pq" || c.Database.Driver == "pgx", "database.driver %q must be pq or pgx", c.Database.Driver)
+		check(c.Database.StatementCacheCapacity >= 0, "database.statement_cache_capacity must not be negative")
+	}
+	if c.Log != nil {
+		check(validLogLevel(c.Log.Level), "log.level %q is not one of debug, info, warn, error", c.Log.Level)
+		check(c.Log.Access.SampleRate >= 0 && c.Log.Access.SampleRate <= 1, "log.access.sample_rate must be between 0 and 1")
+		check(c.Log.Access.SlowThreshold >= 0, "log.access.slow_threshold must not be negative")
+	}
+	if o := c.Observability; o != nil {
+		check(validPort(o.MetricsPort), "observability.metrics_port %d is not a valid port", o.MetricsPort)
+		check(o.TracingSampleRatio >= 0 && o.TracingSampleRatio <= 1,
+			"observability.tracing_sample_ratio %v is not between 0 and 1", o.TracingSampleRatio)
+		check(o.SentrySampleRate >= 0 && o.SentrySampleRate <= 1,
+			"observability.sentry_sample_rate %v is not between 0 and 1", o.SentrySampleRate)
+		check(o.ErrorReporter != "sentry" || o.SentryDSN != "",
+			"observability.sentry_dsn is required when observability.error_reporter is sentry")
+		check(o.RuntimeStatsInterval >= 0, "observability.runtime_stats_interval must not be negative")
+	}
+	if c.Leader != nil {
+		check(c.Leader.RetryInterval >= 0, "leader.retry_interval must not be negative")
+		check(c.Leader.CheckInterval >= 0, "leader.check_interval must not be negative")
+	}
+
+	if c.SLO != nil {
+		check(validRatio(c.SLO.Availability), "slo.availability %v is not between 0 and 1", c.SLO.Availability)
+		check(validRatio(c.SLO.LatencyTarget), "slo.latency_target %v is not between 0 and 1", c.SLO.LatencyTarget)
+		check(c.SLO.LatencyThreshold > 0, "slo.latency_threshold must be positive")
+		check(!c.SLO.Enabled || len(c.SLO.Windows) > 0, "slo.windows is required when slo is enabled")
+		for _, window := range c.SLO.Windows {
+			check(window > 0, "slo.windows %v must be positive", window)
+		}
+		for _, m := range c.SLO.Methods {
+			check(m.Method != "", "slo.methods entries require a method")
+			check(m.Availability == 0 || validRatio(m.Availability),
+				"slo.methods %s availability %v is not between 0 and 1", m.Method, m.Availability)
+			check(m.LatencyTarget == 0 || validRatio(m.LatencyTarget),
+				"slo.methods %s latency_target %v is not between 0 and 1", m.Method, m.LatencyTarget)
+		}
+	}
+	if g := c.Gateway; g != nil {
+		check(g.ConnectTimeout >= 0 && g.BackoffBaseDelay >= 0 && g.BackoffMaxDelay >= 0,
+			"gateway connection durations must not be negative")
+		check(g.BackoffMultiplier == 0 || g.BackoffMultiplier >= 1,
+			"gateway.backoff_multiplier %v must be at least 1", g.BackoffMultiplier)
+		check(g.CallTimeout >= 0, "gateway.call_timeout must not be negative")
+		for name, timeout := range g.MethodTimeouts {
+			check(timeout > 0, "gateway.method_timeouts %s must be positive", name)
+		}
+		check(g.RetryAttempts >= 0 && g.RetryAttempts <= 5,
+			"gateway.retry_attempts %d is not between 0 and 5", g.RetryAttempts)
+		check(g.RetryAttempts < 2 || (g.RetryInitialBackoff > 0 && g.RetryMaxBackoff >= g.RetryInitialBackoff),
+			"gateway.retry_initial_backoff must be positive and not exceed gateway.retry_max_backoff")
+		if k := g.Keepalive; k != nil {
+			check(k.Time >= 0 && k.Timeout >= 0, "gateway.keepalive durations must not be negative")
+		}
+	}
+	if u := c.UserValidation; u != nil && u.Enabled {
+		check(u.UserServiceEndpoint != "", "user_validation.user_service_endpoint is required when user_validation is enabled")
+		check(u.Timeout > 0, "user_validation.timeout must be positive")
+		check(u.BreakerThreshold > 0, "user_validation.breaker_threshold must be positive")
+		check(u.BreakerCooldown > 0, "user_validation.breaker_cooldown must be positive")
+	}
+	if c.Catalog != nil && c.Catalog.Enabled {
+		check(c.Catalog.ProductServiceEndpoint != "", "catalog.product_service_endpoint is required when catalog is enabled")
+		check(c.Catalog.Timeout > 0, "catalog.timeout must be positive")
+	}
+	if c.Inventory != nil {
+		check(c.Inventory.ReservationTTL > 0, "inventory.reservation_ttl must be positive")
+	}
+	if i := c.Idempotency; i != nil && i.Enabled {
+		check(i.TTL > 0, "idempotency.ttl must be positive")
+		check(i.LockTimeout > 0 && i.LockTimeout <= i.TTL, "idempotency.lock_timeout must be positive and not exceed idempotency.ttl")
+	}
+	if o := c.Outbox; o != nil {
+		check(o.Sink == "events" || o.Sink == "log" || o.Sink == "webhook",
+			"outbox.sink %q is not one of events, log, webhook", o.Sink)
+		if o.Sink == "webhook" {
+			check(o.Webhook != nil && o.Webhook.URL != "", "outbox.webhook.url is required when outbox.sink is webhook")
+			check(o.Webhook == nil || o.Webhook.Timeout > 0, "outbox.webhook.timeout must be positive")
+		}
+	}
+	if c.Cache != nil && c.Cache.Enabled {
+		check(c.Cache.TTL > 0, "cache.ttl must be positive")
+		check(c.Cache.OrderTTL > 0, "cache.order_ttl must be positive")
+		check(c.Cache.MaxEntries > 0, "cache.max_entries must be positive")
+	}
+	if c.Deprecation != nil {
+		check(validTimestamp(c.Deprecation.Since), "deprecation.since %q is not an RFC 3339 timestamp", c.Deprecation.Since)
+		check(validTimestamp(c.Deprecation.Sunset), "deprecation.sunset %q is not an RFC 3339 timestamp", c.Deprecation.Sunset)
+	}
+	if e := c.Encryption; e != nil && e.Enabled {
+		check(e.KeyID != "", "encryption.key_id is required when encryption is enabled")
+		check(e.IndexSecret != "", "encryption.index_secret is required when encryption is enabled")
+		check(e.Provider != "local" || len(e.SecretKeys) > 0,
+			"encryption.secret_keys is required when encryption.provider is local")
+		check(e.DataKeyTTL >= 0, "encryption.data_key_ttl must not be negative")
+	}
+	if r := c.RateLimit; r != nil && r.Enabled {
+		check(r.Requests >= 0 && r.Burst >= 0, "rate_limit.requests and rate_limit.burst must not be negative")
+		check(r.Requests == 0 || r.Window > 0, "rate_limit.window must be positive")
+		for _, q := range r.Quotas {
+			check(q.Key != "", "rate_limit.quotas entries require a key")
+			check(q.Requests >= 0 && q.Burst >= 0, "rate_limit.quotas %s requests and burst must not be negative", q.Key)
+			check(q.Requests == 0 || q.Window > 0, "rate_limit.quotas %s window must be positive", q.Key)
+		}
+		check(r.ClientIP.Requests >= 0 && r.ClientIP.Burst >= 0, "rate_limit.client_ip requests and burst must not be negative")
+		check(r.ClientIP.Requests == 0 || r.ClientIP.Window > 0, "rate_limit.client_ip.window must be positive")
+		check(r.APIKey.Requests >= 0 && r.APIKey.Burst >= 0, "rate_limit.api_key requests and burst must not be negative")
+		check(r.APIKey.Requests == 0 || r.APIKey.Window > 0, "rate_limit.api_key.window must be positive")
+		for _, q := range r.Methods {
+			check(strings.HasPrefix(q.Key, "/") && strings.Count(q.Key, "/") == 2,
+				"rate_limit.methods key %q is not a full method or service name", q.Key)
+			check(q.Requests >= 0 && q.Burst >= 0, "rate_limit.methods %s requests and burst must not be negative", q.Key)
+			check(q.Requests == 0 || q.Window > 0, "rate_limit.methods %s window must be positive", q.Key)
+		}
+	}
+	if a := c.Auth; a != nil && a.Enabled {
+		check(len(a.Secret) >= 32, "auth.secret must be at least 32 bytes when auth is enabled")
+		check(a.TokenTTL > 0, "auth.token_ttl must be positive")
+		check(a.RefreshTokenTTL > a.TokenTTL, "auth.refresh_token_ttl must be longer than auth.token_ttl")
+		check(a.Leeway >= 0, "auth.leeway must not be negative")
+		for _, p := range a.Policies {
+			check(strings.HasPrefix(p.Method, "/"), "auth.policies method %q is not a full method name or prefix", p.Method)
+			check(len(p.Roles) > 0, "auth.policies %s must grant at least one role", p.Method)
+		}
+	}
+	if p := c.Password; p != nil {
+		check(p.Algorithm == "bcrypt" || p.Algorithm == "argon2id",
+			"password.algorithm %q is not one of bcrypt, argon2id", p.Algorithm)
+		check(p.MinLength >= 8, "password.min_length must be at least 8")
+		check(p.Algorithm != "bcrypt" || (p.BcryptCost >= 10 && p.BcryptCost <= 31),
+			"password.bcrypt_cost must be between 10 and 31")
+		check(p.Algorithm != "argon2id" || (p.Argon2Memory >= 8*1024 && p.Argon2Iterations > 0 && p.Argon2Parallelism > 0),
+			"password.argon2_memory must be at least 8192 KiB, and argon2 iterations and parallelism positive")
+	}
+	if k := c.APIKeys; k != nil && k.Enabled {
+		check(k.CacheTTL >= 0, "api_keys.cache_ttl must not be negative")
+	}
+	if t := c.TLS; t != nil && t.Enabled {
+		check(t.CertFile != "" && t.KeyFile != "", "tls.cert_file and tls.key_file are required when tls is enabled")
+		check(t.ClientAuth == "none" || t.ClientAuth == "optional" || t.ClientAuth == "require",
+			"tls.client_auth %q is not one of none, optional, require", t.ClientAuth)
+		check(t.ClientAuth == "none" || t.CAFile != "", "tls.ca_file is required when tls.client_auth is not none")
+		check(t.ReloadInterval >= 0, "tls.reload_interval must not be negative")
+	}
+	if c.CORS != nil {
+		for _, origin := range c.CORS.AllowedOrigins {
+			_, err := path.Match(origin, "")
+			check(err == nil && (origin == "*" || strings.HasPrefix(origin, "http://") || strings.HasPrefix(origin, "https://")),
+				"cors.allowed_origins %q is not \"*\" or an http(s) origin or pattern", origin)
+			check(origin != "*" || !c.CORS.AllowCredentials, "cors.allow_credentials cannot be used with the \"*\" origin")
+		}
+		check(len(c.CORS.AllowedMethods) > 0, "cors.allowed_methods must not be empty")
+		for _, method := range c.CORS.AllowedMethods {
+			check(method != "" && method == strings.ToUpper(method), "cors.allowed_methods %q is not an upper-case method", method)
+		}
+		check(c.CORS.MaxAge >= 0, "cors.max_age must not be negative")
+	}
+
+	return errors.Join(errs...)
+}
+
+// validTimestamp reports whether an optional timestamp is empty or RFC 3339
+func validTimestamp(value string) bool {
+	_, err := time.Parse(time.RFC3339, value)
+	return value == "" || err == nil
+}
+
+func validPort(port int) bool {
+	return port >= 0 && port <= 65535
+}
+
+// validRatio reports whether an objective is strictly between 0 and 1; an
+// objective of 1 leaves no error budget to burn
+func validRatio(ratio float64) bool {
+	return ratio > 0 && ratio < 1
+}
+
+func validLogLevel(level string) bool {
+	switch level {
+	case "", "debug", "info", "warn", "error":
+		return true
+	}
+	return false
+}
+
 // GetDSN returns database connection string
 func (d *Database) GetDSN() string {
 	return fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
@@ -102,6 +907,26 @@ func Load() (*Config, error) {
 	return cfg, nil
 }
 
+// Flag registers the --config flag on the default flag set
+func Flag() *string {
+	return flag.String("config", "", "YAML configuration file; environment variables override it")
+}
+
+// MigrateFlag registers the --migrate flag on the default flag set. It
+// sets database.migrate_on_start for a single run.
+func MigrateFlag() *bool {
+	return flag.Bool("migrate", false, "apply pending database migrations on startup")
+}
+
+// Open loads configuration from filename, or from environment variables
+// alone when filename is empty
+func Open(filename string) (*Config, error) {
+	if filename == "" {
+		return Load()
+	}
+	return LoadFromFile(filename)
+}
+
 // LoadFromFile loads configuration from YAML file
 func LoadFromFile(filename string) (*Config, error) {
 	v := viper.New()
@@ -132,12 +957,68 @@ func (c *Config) ToYAML() (string, error) {
 	return string(data), nil
 }
 
+// sensitiveKeys are substrings of the keys whose values RedactedYAML hides
+var sensitiveKeys = []string{"password", "secret", "dsn", "token", "app_id"}
+
+// RedactedYAML converts config to YAML with credentials replaced, so that
+// it can be logged
+func (c *Config) RedactedYAML() (string, error) {
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal config to YAML: %w", err)
+	}
+	var tree map[string]interface{}
+	if err := yaml.Unmarshal(data, &tree); err != nil {
+		return "", fmt.Errorf("failed to unmarshal config: %w", err)
+	}
+
+	var redact func(node map[string]interface{})
+	redact = func(node map[string]interface{}) {
+		for key, value := range node {
+			if child, ok := value.(map[string]interface{}); ok {
+				redact(child)
+				continue
+			}
+			for _, sensitive := range sensitiveKeys {
+				if strings.Contains(key, sensitive) && value != "" {
+					node[key] = "REDACTED"
+				}
+			}
+		}
+	}
+	redact(tree)
+
+	data, err = yaml.Marshal(tree)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal config to YAML: %w", err)
+	}
+	return string(data), nil
+}
+
 func setDefaults(v *viper.Viper) {
 	// Server defaults
 	v.SetDefault("server.host", "0.0.0.0")
 	v.SetDefault("server.port", 8080)
 	v.SetDefault("server.grpc_port", 9090)
 	v.SetDefault("server.mode", "development")
+	v.SetDefault("server.grpc_socket", "")
+	v.SetDefault("server.request_timeout", "0s")
+	v.SetDefault("server.keepalive.time", "30s")
+	v.SetDefault("server.keepalive.timeout", "10s")
+	v.SetDefault("server.keepalive.permit_without_stream", true)
+	v.SetDefault("server.keepalive.min_time", "15s")
+	v.SetDefault("server.keepalive.max_connection_idle", "0s")
+	v.SetDefault("server.keepalive.max_connection_age", "0s")
+	v.SetDefault("server.keepalive.max_connection_age_grace", "0s")
+
+	// TLS defaults
+	v.SetDefault("tls.enabled", false)
+	v.SetDefault("tls.cert_file", "")
+	v.SetDefault("tls.key_file", "")
+	v.SetDefault("tls.ca_file", "")
+	v.SetDefault("tls.client_auth", "none")
+	v.SetDefault("tls.server_name", "")
+	v.SetDefault("tls.reload_interval", "1m")
 
 	// Database defaults
 	v.SetDefault("database.host", "localhost")
@@ -146,10 +1027,300 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("database.password", "postgres")
 	v.SetDefault("database.name", "monorepo")
 	v.SetDefault("database.ssl_mode", "disable")
+	v.SetDefault("database.migrate_on_start", false)
+	v.SetDefault("database.max_open_conns", 25)
+	v.SetDefault("database.max_idle_conns", 5)
+	v.SetDefault("database.conn_max_lifetime", "5m")
+	v.SetDefault("database.conn_max_idle_time", "1m")
+	v.SetDefault("database.driver", "pq")
+	v.SetDefault("database.statement_cache_capacity", 512)
 
 	// Log defaults
 	v.SetDefault("log.level", "info")
 	v.SetDefault("log.format", "json")
+	v.SetDefault("log.level_endpoint", false)
+	v.SetDefault("log.access.sample_rate", 1.0)
+	v.SetDefault("log.access.slow_threshold", "1s")
+
+	// Events defaults
+	v.SetDefault("events.driver", "memory")
+	v.SetDefault("events.brokers", []string{"localhost:9092"})
+	v.SetDefault("events.nats_url", "nats://localhost:4222")
+	v.SetDefault("events.client_id", "monorepo")
+
+	// Outbox defaults
+	v.SetDefault("outbox.poll_interval", "1s")
+	v.SetDefault("outbox.batch_size", 100)
+	v.SetDefault("outbox.max_attempts", 10)
+	v.SetDefault("outbox.sink", "events")
+	v.SetDefault("outbox.webhook.timeout", "10s")
+
+	// Scheduler defaults
+	v.SetDefault("scheduler.enabled", true)
+	v.SetDefault("scheduler.order_expiry_schedule", "*/5 * * * *")
+	v.SetDefault("scheduler.pending_order_ttl", "24h")
+	v.SetDefault("scheduler.recommendation_schedule", "0 3 * * *")
+	v.SetDefault("scheduler.reservation_expiry_schedule", "* * * * *")
+
+	// Webhook defaults
+	v.SetDefault("webhook.topics", []string{"orders", "users"})
+	v.SetDefault("webhook.poll_interval", "1s")
+	v.SetDefault("webhook.batch_size", 50)
+	v.SetDefault("webhook.max_attempts", 8)
+	v.SetDefault("webhook.initial_backoff", "10s")
+	v.SetDefault("webhook.max_backoff", "1h")
+	v.SetDefault("webhook.timeout", "10s")
+
+	// Search defaults
+	v.SetDefault("search.driver", "bleve")
+	v.SetDefault("search.path", "")
+	v.SetDefault("search.elasticsearch_url", "http://localhost:9200")
+	v.SetDefault("search.index", "monorepo")
+	v.SetDefault("search.topics", []string{"orders", "users"})
+
+	// Audit defaults
+	v.SetDefault("audit.enabled", false)
+	v.SetDefault("audit.topic", "audit")
+
+	// Shipping defaults
+	v.SetDefault("shipping.order_service_endpoint", "localhost:9092")
+	v.SetDefault("shipping.carriers", []string{"ups", "fedex", "dhl", "usps"})
+	v.SetDefault("shipping.default_carrier", "ups")
+	v.SetDefault("shipping.callback_secret", "")
+
+	// Tenancy defaults
+	v.SetDefault("tenancy.required", false)
+	v.SetDefault("tenancy.default_tenant", "default")
+	v.SetDefault("tenancy.tenants", []string{})
+
+	// Jobs defaults
+	v.SetDefault("jobs.queues", []string{"default", "email", "export"})
+	v.SetDefault("jobs.concurrency", 4)
+	v.SetDefault("jobs.poll_interval", "1s")
+	v.SetDefault("jobs.lease", "5m")
+	v.SetDefault("jobs.initial_backoff", "10s")
+	v.SetDefault("jobs.max_backoff", "1h")
+
+	// Mail defaults
+	v.SetDefault("mail.host", "localhost")
+	v.SetDefault("mail.port", 25)
+	v.SetDefault("mail.username", "")
+	v.SetDefault("mail.password", "")
+	v.SetDefault("mail.from", "no-reply@example.com")
+
+	// Storage defaults
+	v.SetDefault("storage.driver", "local")
+	v.SetDefault("storage.bucket", "")
+	v.SetDefault("storage.region", "us-east-1")
+	v.SetDefault("storage.endpoint", "")
+	v.SetDefault("storage.path_style", false)
+	v.SetDefault("storage.access_key_id", "")
+	v.SetDefault("storage.secret_access_key", "")
+	v.SetDefault("storage.local_dir", "data/storage")
+	v.SetDefault("storage.base_url", "http://localhost:8080/files")
+	v.SetDefault("storage.signing_secret", "")
+
+	// Session defaults
+	v.SetDefault("session.enabled", false)
+	v.SetDefault("session.redis_addr", "")
+	v.SetDefault("session.redis_password", "")
+	v.SetDefault("session.redis_db", 0)
+	v.SetDefault("session.idle_timeout", "24h")
+	v.SetDefault("session.max_lifetime", "720h")
+	v.SetDefault("session.cookie_name", "session")
+	v.SetDefault("session.cookie_secure", true)
+
+	// Currency defaults
+	v.SetDefault("currency.base", "USD")
+	v.SetDefault("currency.provider", "")
+	v.SetDefault("currency.openexchangerates_app_id", "")
+	v.SetDefault("currency.refresh_interval", "24h")
+
+	// BFF defaults
+	v.SetDefault("bff.user_timeout", "500ms")
+	v.SetDefault("bff.orders_timeout", "1s")
+	v.SetDefault("bff.shipments_timeout", "500ms")
+	v.SetDefault("bff.products_timeout", "500ms")
+	v.SetDefault("bff.recent_orders", 5)
+
+	// Gateway defaults
+	v.SetDefault("gateway.connect_timeout", "20s")
+	v.SetDefault("gateway.backoff_base_delay", "1s")
+	v.SetDefault("gateway.backoff_multiplier", 1.6)
+	v.SetDefault("gateway.backoff_max_delay", "30s")
+	v.SetDefault("gateway.call_timeout", "10s")
+	v.SetDefault("gateway.retry_attempts", 3)
+	v.SetDefault("gateway.retry_initial_backoff", "100ms")
+	v.SetDefault("gateway.retry_max_backoff", "1s")
+
+	// User validation defaults
+	v.SetDefault("user_validation.enabled", false)
+	v.SetDefault("user_validation.user_service_endpoint", "localhost:9091")
+	v.SetDefault("user_validation.timeout", "2s")
+	v.SetDefault("user_validation.fail_open", false)
+	v.SetDefault("user_validation.breaker_threshold", 5)
+	v.SetDefault("user_validation.breaker_cooldown", "30s")
+
+	// Catalog defaults
+	v.SetDefault("catalog.enabled", false)
+	v.SetDefault("catalog.product_service_endpoint", "localhost:9096")
+	v.SetDefault("catalog.timeout", "2s")
+
+	// Inventory defaults
+	v.SetDefault("inventory.reservation_ttl", "30m")
+
+	// Idempotency defaults
+	v.SetDefault("idempotency.enabled", true)
+	v.SetDefault("idempotency.ttl", "24h")
+	v.SetDefault("idempotency.lock_timeout", "1m")
+	v.SetDefault("idempotency.purge_schedule", "0 * * * *")
+
+	// Retention defaults: purge deleted users after 30 days and anonymize
+	// orders after 7 years
+	v.SetDefault("retention.enabled", false)
+	v.SetDefault("retention.schedule", "30 2 * * *")
+	v.SetDefault("retention.dry_run", false)
+	v.SetDefault("retention.batch_size", 500)
+	v.SetDefault("retention.user_purge_after", "720h")
+	v.SetDefault("retention.order_anonymize_after", "61368h")
+
+	// Leader election defaults
+	v.SetDefault("leader.enabled", true)
+	v.SetDefault("leader.retry_interval", "5s")
+	v.SetDefault("leader.check_interval", "5s")
+
+	// SLO defaults: 99.9% of calls succeed and 99% complete within 300ms,
+	// with burn rates over 5 minutes and 1 hour
+	v.SetDefault("slo.enabled", true)
+	v.SetDefault("slo.availability", 0.999)
+	v.SetDefault("slo.latency_threshold", "300ms")
+	v.SetDefault("slo.latency_target", 0.99)
+	v.SetDefault("slo.windows", []string{"5m", "1h"})
+	v.SetDefault("slo.methods", []SLOMethod{})
+
+	// CORS defaults
+	v.SetDefault("cors.allowed_origins", []string{"*"})
+	v.SetDefault("cors.allowed_methods", []string{"GET", "POST", "PUT", "PATCH", "DELETE"})
+	v.SetDefault("cors.allowed_headers", []string{})
+	v.SetDefault("cors.max_age", "10m")
+	v.SetDefault("cors.allow_credentials", false)
+
+	// Deprecation defaults
+	v.SetDefault("deprecation.since", "")
+	v.SetDefault("deprecation.sunset", "")
+
+	// Encryption defaults
+	v.SetDefault("encryption.enabled", false)
+	v.SetDefault("encryption.provider", "local")
+	v.SetDefault("encryption.key_id", "")
+	v.SetDefault("encryption.secret_keys", []string{})
+	v.SetDefault("encryption.index_secret", "")
+	v.SetDefault("encryption.data_key_ttl", "1h")
+
+	// Rate limit defaults: 100 requests per second per tenant
+	v.SetDefault("rate_limit.enabled", false)
+	v.SetDefault("rate_limit.redis_addr", "")
+	v.SetDefault("rate_limit.redis_password", "")
+	v.SetDefault("rate_limit.redis_db", 0)
+	v.SetDefault("rate_limit.requests", 100)
+	v.SetDefault("rate_limit.window", "1s")
+	v.SetDefault("rate_limit.burst", 0)
+	v.SetDefault("rate_limit.quotas", []RateLimitQuota{})
+	v.SetDefault("rate_limit.client_ip.requests", 0)
+	v.SetDefault("rate_limit.client_ip.window", "1s")
+	v.SetDefault("rate_limit.client_ip.burst", 0)
+	v.SetDefault("rate_limit.api_key.requests", 0)
+	v.SetDefault("rate_limit.api_key.window", "1s")
+	v.SetDefault("rate_limit.api_key.burst", 0)
+	v.SetDefault("rate_limit.trust_forwarded_for", false)
+	v.SetDefault("rate_limit.methods", []RateLimitQuota{})
+
+	// Auth defaults
+	v.SetDefault("auth.enabled", false)
+	v.SetDefault("auth.secret", "")
+	v.SetDefault("auth.previous_secrets", []string{})
+	v.SetDefault("auth.issuer", "monorepo-go-example")
+	v.SetDefault("auth.audience", "monorepo-go-example")
+	v.SetDefault("auth.token_ttl", "15m")
+	v.SetDefault("auth.refresh_token_ttl", "720h")
+	v.SetDefault("auth.leeway", "30s")
+	// Carrier callbacks authenticate with the callback secret rather than a
+	// token, and logins with a password or refresh token, so they must stay
+	// public when these lists are overridden
+	v.SetDefault("auth.public_paths", []string{"/v1/carriers/*", "/v1/auth:*"})
+	v.SetDefault("auth.public_methods", []string{
+		"/grpc.health.v1.Health/*",
+		"/shipping.v1.ShippingService/CarrierCallback",
+		"/auth.v1.AuthService/*",
+	})
+	// Operator methods need the admin role, and the methods services call on
+	// each other's behalf the service role; any authenticated caller may
+	// call the rest of the public API. Methods missing here are denied.
+	v.SetDefault("auth.policies", []AuthPolicy{
+		{Method: "/user.v1.UserService/DeleteUser", Roles: []string{"admin"}},
+		{Method: "/user.v1.UserService/UndeleteUser", Roles: []string{"admin"}},
+		{Method: "/user.v1.UserService/PurgeUser", Roles: []string{"admin"}},
+		{Method: "/user.v1.UserService/ExportUsers", Roles: []string{"admin", "service"}},
+		{Method: "/user.v2.UserService/DeleteUser", Roles: []string{"admin"}},
+		{Method: "/order.v1.OrderService/UpdateOrderStatus", Roles: []string{"admin", "service"}},
+		{Method: "/order.v1.OrderService/ImportOrders", Roles: []string{"admin", "service"}},
+		{Method: "/order.v2.OrderService/UpdateOrderStatus", Roles: []string{"admin", "service"}},
+		{Method: "/product.v1.ProductService/CreateProduct", Roles: []string{"admin"}},
+		{Method: "/product.v1.ProductService/UpdateProduct", Roles: []string{"admin"}},
+		{Method: "/audit.v1.AuditService/*", Roles: []string{"admin", "service"}},
+		{Method: "/webhook.v1.WebhookService/*", Roles: []string{"admin", "service"}},
+		{Method: "/apikey.v1.APIKeyService/*", Roles: []string{"admin"}},
+		{Method: "/grpc.reflection.*", Roles: []string{"admin"}},
+		{Method: "/user.v1.UserService/*", Roles: []string{"*"}},
+		{Method: "/user.v2.UserService/*", Roles: []string{"*"}},
+		{Method: "/order.v1.OrderService/*", Roles: []string{"*"}},
+		{Method: "/order.v2.OrderService/*", Roles: []string{"*"}},
+		{Method: "/product.v1.ProductService/*", Roles: []string{"*"}},
+		{Method: "/recommendation.v1.RecommendationService/*", Roles: []string{"*"}},
+		{Method: "/search.v1.SearchService/*", Roles: []string{"*"}},
+		{Method: "/shipping.v1.ShippingService/*", Roles: []string{"*"}},
+	})
+
+	// Password defaults, the argon2id parameters OWASP recommends
+	v.SetDefault("password.algorithm", "argon2id")
+	v.SetDefault("password.min_length", 12)
+	v.SetDefault("password.bcrypt_cost", 12)
+	v.SetDefault("password.argon2_memory", 19*1024)
+	v.SetDefault("password.argon2_iterations", 2)
+	v.SetDefault("password.argon2_parallelism", 1)
+
+	// API key defaults
+	v.SetDefault("api_keys.enabled", false)
+	v.SetDefault("api_keys.cache_ttl", "30s")
+
+	// Pagination defaults
+	v.SetDefault("pagination.secret", "")
+
+	// Cache defaults
+	v.SetDefault("cache.enabled", false)
+	v.SetDefault("cache.redis_addr", "")
+	v.SetDefault("cache.redis_password", "")
+	v.SetDefault("cache.redis_db", 0)
+	v.SetDefault("cache.ttl", "5m")
+	v.SetDefault("cache.order_ttl", "30s")
+	v.SetDefault("cache.max_entries", 10000)
+
+	// Observability defaults
+	v.SetDefault("observability.metrics_enabled", true)
+	v.SetDefault("observability.metrics_path", "/metrics")
+	v.SetDefault("observability.metrics_namespace", "")
+	v.SetDefault("observability.metrics_port", 0)
+	v.SetDefault("observability.runtime_metrics", true)
+	v.SetDefault("observability.tracing_enabled", false)
+	v.SetDefault("observability.tracing_endpoint", "localhost:4317")
+	v.SetDefault("observability.tracing_insecure", true)
+	v.SetDefault("observability.tracing_sample_ratio", 1.0)
+	v.SetDefault("observability.runtime_stats_interval", 0)
+	v.SetDefault("observability.channelz_enabled", false)
+	v.SetDefault("observability.error_reporter", "")
+	v.SetDefault("observability.sentry_dsn", "")
+	v.SetDefault("observability.sentry_environment", "")
+	v.SetDefault("observability.sentry_sample_rate", 1.0)
 }
 
 func bindEnvs(v *viper.Viper, prefix string, t reflect.Type) {
@@ -165,8 +1336,14 @@ func bindEnvs(v *viper.Viper, prefix string, t reflect.Type) {
 			key = prefix + "." + tag
 		}
 
-		if field.Type.Kind() == reflect.Struct {
-			bindEnvs(v, key, field.Type)
+		// Sections are pointers to structs; bind their fields, not the
+		// section itself, which would shadow them
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		if fieldType.Kind() == reflect.Struct {
+			bindEnvs(v, key, fieldType)
 		} else {
 			v.BindEnv(key)
 		}
@@ -17,7 +17,9 @@
 package config
 
 import (
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestBind(t *testing.T) {
@@ -55,6 +57,212 @@ func TestLoad(t *testing.T) {
 	if cfg.Server.Port != 8080 {
 		t.Errorf("Load() Server.Port = %v, want 8080", cfg.Server.Port)
 	}
+	if p := cfg.Auth.Policies; len(p) == 0 || p[0].Method != "/user.v1.UserService/DeleteUser" || p[0].Roles[0] != "admin" {
+		t.Errorf("Load() Auth.Policies = %v, want the default policies", p)
+	}
+}
+
+func TestLoadFromEnv(t *testing.T) {
+	t.Setenv("DATABASE_HOST", "postgres")
+	t.Setenv("SERVER_GRPC_SOCKET", "/var/run/user-service.sock")
+	t.Setenv("EVENTS_BROKERS", "kafka-0:9092,kafka-1:9092")
+	t.Setenv("DATABASE_MAX_OPEN_CONNS", "40")
+	t.Setenv("DATABASE_CONN_MAX_IDLE_TIME", "30s")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Database.Host != "postgres" {
+		t.Errorf("Database.Host = %q, want postgres", cfg.Database.Host)
+	}
+	if cfg.Server.GRPCSocket != "/var/run/user-service.sock" {
+		t.Errorf("Server.GRPCSocket = %q, want /var/run/user-service.sock", cfg.Server.GRPCSocket)
+	}
+	if len(cfg.Events.Brokers) != 2 {
+		t.Errorf("Events.Brokers = %v, want two brokers", cfg.Events.Brokers)
+	}
+	if cfg.Database.Port != 5432 {
+		t.Errorf("Database.Port = %d, want the default 5432", cfg.Database.Port)
+	}
+	if cfg.Database.MaxOpenConns != 40 || cfg.Database.ConnMaxIdleTime != 30*time.Second {
+		t.Errorf("Database pool = %d conns, %v idle time, want 40 and 30s", cfg.Database.MaxOpenConns, cfg.Database.ConnMaxIdleTime)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(cfg *Config)
+		wantErr bool
+	}{
+		{name: "defaults", mutate: func(cfg *Config) {}},
+		{name: "invalid port", mutate: func(cfg *Config) { cfg.Server.Port = 70000 }, wantErr: true},
+		{name: "missing database host", mutate: func(cfg *Config) { cfg.Database.Host = "" }, wantErr: true},
+		{name: "database pool", mutate: func(cfg *Config) {
+			cfg.Database.MaxOpenConns = 50
+			cfg.Database.MaxIdleConns = 50
+			cfg.Database.ConnMaxLifetime = 0
+		}},
+		{name: "database without connections", mutate: func(cfg *Config) { cfg.Database.MaxOpenConns = 0 }, wantErr: true},
+		{name: "database idle above open", mutate: func(cfg *Config) { cfg.Database.MaxIdleConns = 30 }, wantErr: true},
+		{name: "pgx driver", mutate: func(cfg *Config) { cfg.Database.Driver = "pgx" }},
+		{name: "unknown database driver", mutate: func(cfg *Config) { cfg.Database.Driver = "mysql" }, wantErr: true},
+		{name: "negative statement cache", mutate: func(cfg *Config) { cfg.Database.StatementCacheCapacity = -1 }, wantErr: true},
+		{name: "negative database idle time", mutate: func(cfg *Config) { cfg.Database.ConnMaxIdleTime = -time.Second }, wantErr: true},
+		{name: "separate metrics port", mutate: func(cfg *Config) { cfg.Observability.MetricsPort = 9090 }},
+		{name: "invalid metrics port", mutate: func(cfg *Config) { cfg.Observability.MetricsPort = -1 }, wantErr: true},
+		{name: "sample ratio out of range", mutate: func(cfg *Config) { cfg.Observability.TracingSampleRatio = 1.5 }, wantErr: true},
+		{name: "sentry without dsn", mutate: func(cfg *Config) { cfg.Observability.ErrorReporter = "sentry" }, wantErr: true},
+		{name: "keepalive pings below server minimum", mutate: func(cfg *Config) { cfg.Server.Keepalive.MinTime = time.Minute }, wantErr: true},
+		{name: "negative keepalive", mutate: func(cfg *Config) { cfg.Server.Keepalive.MaxConnectionAge = -time.Second }, wantErr: true},
+		{name: "gateway method timeout", mutate: func(cfg *Config) {
+			cfg.Gateway.MethodTimeouts = map[string]time.Duration{"order.v1.OrderService/ExportOrders": time.Minute}
+		}},
+		{name: "gateway zero method timeout", mutate: func(cfg *Config) {
+			cfg.Gateway.MethodTimeouts = map[string]time.Duration{"order.v1.OrderService": 0}
+		}, wantErr: true},
+		{name: "gateway too many retries", mutate: func(cfg *Config) { cfg.Gateway.RetryAttempts = 6 }, wantErr: true},
+		{name: "gateway backoff below one", mutate: func(cfg *Config) { cfg.Gateway.BackoffMultiplier = 0.5 }, wantErr: true},
+		{name: "user validation", mutate: func(cfg *Config) { cfg.UserValidation.Enabled = true }},
+		{name: "user validation without endpoint", mutate: func(cfg *Config) {
+			cfg.UserValidation.Enabled = true
+			cfg.UserValidation.UserServiceEndpoint = ""
+		}, wantErr: true},
+		{name: "user validation without breaker threshold", mutate: func(cfg *Config) {
+			cfg.UserValidation.Enabled = true
+			cfg.UserValidation.BreakerThreshold = 0
+		}, wantErr: true},
+		{name: "catalog", mutate: func(cfg *Config) { cfg.Catalog.Enabled = true }},
+		{name: "catalog without timeout", mutate: func(cfg *Config) {
+			cfg.Catalog.Enabled = true
+			cfg.Catalog.Timeout = 0
+		}, wantErr: true},
+		{name: "inventory without reservation ttl", mutate: func(cfg *Config) { cfg.Inventory.ReservationTTL = 0 }, wantErr: true},
+		{name: "idempotency lock timeout beyond ttl", mutate: func(cfg *Config) { cfg.Idempotency.LockTimeout = 48 * time.Hour }, wantErr: true},
+		{name: "unknown outbox sink", mutate: func(cfg *Config) { cfg.Outbox.Sink = "kafka" }, wantErr: true},
+		{name: "outbox webhook sink", mutate: func(cfg *Config) {
+			cfg.Outbox.Sink = "webhook"
+			cfg.Outbox.Webhook.URL = "https://hooks.example.com/orders"
+		}},
+		{name: "outbox webhook sink without url", mutate: func(cfg *Config) { cfg.Outbox.Sink = "webhook" }, wantErr: true},
+		{name: "cache without ttl", mutate: func(cfg *Config) { cfg.Cache.Enabled = true; cfg.Cache.TTL = 0 }, wantErr: true},
+		{name: "cache without order ttl", mutate: func(cfg *Config) { cfg.Cache.Enabled = true; cfg.Cache.OrderTTL = 0 }, wantErr: true},
+		{name: "deprecation dates", mutate: func(cfg *Config) { cfg.Deprecation.Sunset = "2027-01-01T00:00:00Z" }},
+		{name: "invalid sunset", mutate: func(cfg *Config) { cfg.Deprecation.Sunset = "next year" }, wantErr: true},
+		{name: "sampled access log", mutate: func(cfg *Config) { cfg.Log.Access.SampleRate = 0.1 }},
+		{name: "access log sample rate above one", mutate: func(cfg *Config) { cfg.Log.Access.SampleRate = 2 }, wantErr: true},
+		{name: "cors origin pattern with credentials", mutate: func(cfg *Config) {
+			cfg.CORS.AllowedOrigins = []string{"https://*.example.com"}
+			cfg.CORS.AllowCredentials = true
+		}},
+		{name: "cors any origin with credentials", mutate: func(cfg *Config) { cfg.CORS.AllowCredentials = true }, wantErr: true},
+		{name: "cors malformed origin pattern", mutate: func(cfg *Config) { cfg.CORS.AllowedOrigins = []string{"https://[.example.com"} }, wantErr: true},
+		{name: "cors without methods", mutate: func(cfg *Config) { cfg.CORS.AllowedMethods = nil }, wantErr: true},
+		{name: "cors negative max age", mutate: func(cfg *Config) { cfg.CORS.MaxAge = -time.Second }, wantErr: true},
+		{name: "rate limit quota", mutate: func(cfg *Config) {
+			cfg.RateLimit.Enabled = true
+			cfg.RateLimit.Quotas = []RateLimitQuota{{Key: "acme", Requests: 10, Window: time.Minute}}
+		}},
+		{name: "rate limit without window", mutate: func(cfg *Config) {
+			cfg.RateLimit.Enabled = true
+			cfg.RateLimit.Quotas = []RateLimitQuota{{Key: "acme", Requests: 10}}
+		}, wantErr: true},
+		{name: "rate limit client ip", mutate: func(cfg *Config) {
+			cfg.RateLimit.Enabled = true
+			cfg.RateLimit.ClientIP = RateLimitBucket{Requests: 20, Window: time.Second}
+		}},
+		{name: "rate limit client ip without window", mutate: func(cfg *Config) {
+			cfg.RateLimit.Enabled = true
+			cfg.RateLimit.ClientIP = RateLimitBucket{Requests: 20}
+		}, wantErr: true},
+		{name: "rate limit methods", mutate: func(cfg *Config) {
+			cfg.RateLimit.Enabled = true
+			cfg.RateLimit.Methods = []RateLimitQuota{
+				{Key: "/order.v1.OrderService/CreateOrder", Requests: 10, Window: time.Second},
+				{Key: "/search.v1.SearchService/", Requests: 50, Window: time.Second},
+			}
+		}},
+		{name: "rate limit method without service", mutate: func(cfg *Config) {
+			cfg.RateLimit.Enabled = true
+			cfg.RateLimit.Methods = []RateLimitQuota{{Key: "CreateOrder", Requests: 10, Window: time.Second}}
+		}, wantErr: true},
+		{name: "encryption without keys", mutate: func(cfg *Config) { cfg.Encryption.Enabled = true }, wantErr: true},
+		{name: "encryption", mutate: func(cfg *Config) {
+			cfg.Encryption = &Encryption{Enabled: true, Provider: "local", KeyID: "k1",
+				SecretKeys: []string{"k1:a2V5"}, IndexSecret: "aW5kZXg=", DataKeyTTL: time.Hour}
+		}},
+		{name: "auth without secret", mutate: func(cfg *Config) { cfg.Auth.Enabled = true }, wantErr: true},
+		{name: "auth", mutate: func(cfg *Config) {
+			cfg.Auth.Enabled = true
+			cfg.Auth.Secret = "0123456789abcdef0123456789abcdef"
+		}},
+		{name: "auth policy without roles", mutate: func(cfg *Config) {
+			cfg.Auth.Enabled = true
+			cfg.Auth.Secret = "0123456789abcdef0123456789abcdef"
+			cfg.Auth.Policies = []AuthPolicy{{Method: "/user.v1.UserService/*"}}
+		}, wantErr: true},
+		{name: "refresh tokens outlived by access tokens", mutate: func(cfg *Config) {
+			cfg.Auth.Enabled = true
+			cfg.Auth.Secret = "0123456789abcdef0123456789abcdef"
+			cfg.Auth.RefreshTokenTTL = cfg.Auth.TokenTTL
+		}, wantErr: true},
+		{name: "negative api key quota", mutate: func(cfg *Config) {
+			cfg.RateLimit.Enabled = true
+			cfg.RateLimit.APIKey.Requests = -1
+		}, wantErr: true},
+		{name: "unknown password algorithm", mutate: func(cfg *Config) { cfg.Password.Algorithm = "md5" }, wantErr: true},
+		{name: "weak bcrypt cost", mutate: func(cfg *Config) {
+			cfg.Password.Algorithm = "bcrypt"
+			cfg.Password.BcryptCost = 4
+		}, wantErr: true},
+		{name: "bcrypt passwords", mutate: func(cfg *Config) { cfg.Password.Algorithm = "bcrypt" }},
+		{name: "tls without certificate", mutate: func(cfg *Config) { cfg.TLS.Enabled = true }, wantErr: true},
+		{name: "mutual tls", mutate: func(cfg *Config) {
+			cfg.TLS = &TLS{Enabled: true, CertFile: "tls.crt", KeyFile: "tls.key", CAFile: "ca.crt", ClientAuth: "require"}
+		}},
+		{name: "mutual tls without ca", mutate: func(cfg *Config) {
+			cfg.TLS = &TLS{Enabled: true, CertFile: "tls.crt", KeyFile: "tls.key", ClientAuth: "require"}
+		}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := Load()
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			tt.mutate(cfg)
+			if err := cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRedactedYAML(t *testing.T) {
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	cfg.Session.RedisPassword = ""
+	cfg.Storage.SigningSecret = "signing-key"
+	cfg.Encryption.SecretKeys = []string{"k1:kek-material"}
+
+	out, err := cfg.RedactedYAML()
+	if err != nil {
+		t.Fatalf("RedactedYAML() error = %v", err)
+	}
+	for _, leaked := range []string{"password: postgres", "signing-key", "kek-material"} {
+		if strings.Contains(out, leaked) {
+			t.Errorf("RedactedYAML() leaked %q", leaked)
+		}
+	}
+	for _, kept := range []string{"host: localhost", "redis_password: \"\""} {
+		if !strings.Contains(out, kept) {
+			t.Errorf("RedactedYAML() is missing %q", kept)
+		}
+	}
 }
 
 func TestGetDSN(t *testing.T) {
@@ -0,0 +1,204 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package config
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultWatchInterval is how often a Watcher checks its file for changes
+const DefaultWatchInterval = 10 * time.Second
+
+// Reloader prepares a running component for a changed configuration. It
+// returns an error to reject the change, or a function that applies it.
+// Apply functions run only after every reloader has accepted the change.
+type Reloader func(old, new *Config) (apply func(), err error)
+
+// Watcher reloads a configuration file when it changes. A change is
+// applied only when the new configuration is valid and every reloader
+// accepts it; otherwise the running configuration is kept as a whole.
+type Watcher struct {
+	path     string
+	interval time.Duration
+
+	// OnChange is called with the keys that changed after a change is
+	// applied, and OnReject with the reason a change was rejected
+	OnChange func(changed []string)
+	OnReject func(err error)
+
+	mu        sync.Mutex
+	current   *Config
+	contents  []byte
+	reloaders []Reloader
+}
+
+// NewWatcher watches path, whose contents were loaded into current. Zero
+// interval uses DefaultWatchInterval.
+func NewWatcher(path string, current *Config, interval time.Duration) *Watcher {
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+	contents, _ := os.ReadFile(path)
+	return &Watcher{path: path, interval: interval, current: current, contents: contents}
+}
+
+// OnReload adds a reloader that is consulted on every change
+func (w *Watcher) OnReload(r Reloader) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.reloaders = append(w.reloaders, r)
+}
+
+// Current returns the configuration that is applied
+func (w *Watcher) Current() *Config {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.current
+}
+
+// Run checks the file every interval until ctx is cancelled
+func (w *Watcher) Run(ctx context.Context) error {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			changed, err := w.Reload()
+			switch {
+			case err != nil && w.OnReject != nil:
+				w.OnReject(err)
+			case len(changed) > 0 && w.OnChange != nil:
+				w.OnChange(changed)
+			}
+		}
+	}
+}
+
+// Reload loads the file if its contents changed and applies the new
+// configuration. It returns the keys that changed, or an error when the
+// change was rejected.
+func (w *Watcher) Reload() ([]string, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	contents, err := os.ReadFile(w.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+	if bytes.Equal(contents, w.contents) {
+		return nil, nil
+	}
+	// A rejected file is not retried until it changes again
+	w.contents = contents
+
+	next, err := LoadFromFile(w.path)
+	if err != nil {
+		return nil, err
+	}
+	if err := next.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	changed, err := diff(w.current, next)
+	if err != nil {
+		return nil, err
+	}
+	if len(changed) == 0 {
+		return nil, nil
+	}
+
+	applies := make([]func(), 0, len(w.reloaders))
+	for _, reload := range w.reloaders {
+		apply, err := reload(w.current, next)
+		if err != nil {
+			return nil, err
+		}
+		if apply != nil {
+			applies = append(applies, apply)
+		}
+	}
+	for _, apply := range applies {
+		apply()
+	}
+	w.current = next
+
+	return changed, nil
+}
+
+// diff returns the dotted keys whose values differ between a and b
+func diff(a, b *Config) ([]string, error) {
+	flatA, err := flatten(a)
+	if err != nil {
+		return nil, err
+	}
+	flatB, err := flatten(b)
+	if err != nil {
+		return nil, err
+	}
+
+	var changed []string
+	for key, value := range flatB {
+		if old, ok := flatA[key]; !ok || !reflect.DeepEqual(old, value) {
+			changed = append(changed, key)
+		}
+	}
+	for key := range flatA {
+		if _, ok := flatB[key]; !ok {
+			changed = append(changed, key)
+		}
+	}
+	sort.Strings(changed)
+	return changed, nil
+}
+
+// flatten maps the dotted YAML keys of cfg to their values
+func flatten(cfg *Config) (map[string]interface{}, error) {
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal config: %w", err)
+	}
+	var tree map[string]interface{}
+	if err := yaml.Unmarshal(data, &tree); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
+	}
+
+	flat := make(map[string]interface{})
+	var walk func(prefix string, node map[string]interface{})
+	walk = func(prefix string, node map[string]interface{}) {
+		for key, value := range node {
+			if child, ok := value.(map[string]interface{}); ok {
+				walk(prefix+key+".", child)
+				continue
+			}
+			flat[prefix+key] = value
+		}
+	}
+	walk("", tree)
+	return flat, nil
+}
@@ -0,0 +1,114 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeConfig(t *testing.T, path, contents string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+}
+
+func TestWatcherReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeConfig(t, path, "log:\n  level: info\n")
+
+	cfg, err := LoadFromFile(path)
+	if err != nil {
+		t.Fatalf("LoadFromFile() error = %v", err)
+	}
+	w := NewWatcher(path, cfg, 0)
+
+	var applied []string
+	w.OnReload(func(old, new *Config) (func(), error) {
+		return func() { applied = append(applied, new.Log.Level) }, nil
+	})
+
+	// Unchanged contents are not reloaded
+	if changed, err := w.Reload(); err != nil || changed != nil {
+		t.Fatalf("Reload() = %v, %v; want no change", changed, err)
+	}
+
+	writeConfig(t, path, "log:\n  level: debug\nserver:\n  request_timeout: 5s\n")
+	changed, err := w.Reload()
+	if err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	if want := []string{"log.level", "server.request_timeout"}; !reflect.DeepEqual(changed, want) {
+		t.Errorf("Reload() changed = %v, want %v", changed, want)
+	}
+	if !reflect.DeepEqual(applied, []string{"debug"}) {
+		t.Errorf("applied = %v, want [debug]", applied)
+	}
+	if w.Current().Log.Level != "debug" {
+		t.Errorf("Current().Log.Level = %q, want debug", w.Current().Log.Level)
+	}
+}
+
+func TestWatcherRejectsChanges(t *testing.T) {
+	tests := []struct {
+		name     string
+		contents string
+		reject   bool
+	}{
+		{name: "invalid value", contents: "log:\n  level: verbose\n"},
+		{name: "malformed yaml", contents: "log: [\n"},
+		{name: "rejected by a reloader", contents: "log:\n  level: warn\n", reject: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			writeConfig(t, path, "log:\n  level: info\n")
+			cfg, err := LoadFromFile(path)
+			if err != nil {
+				t.Fatalf("LoadFromFile() error = %v", err)
+			}
+			w := NewWatcher(path, cfg, 0)
+
+			applied := false
+			w.OnReload(func(old, new *Config) (func(), error) {
+				return func() { applied = true }, nil
+			})
+			w.OnReload(func(old, new *Config) (func(), error) {
+				if tt.reject {
+					return nil, errors.New("rejected")
+				}
+				return nil, nil
+			})
+
+			writeConfig(t, path, tt.contents)
+			if _, err := w.Reload(); err == nil {
+				t.Fatal("Reload() should reject the change")
+			}
+			if applied {
+				t.Error("a rejected change was partially applied")
+			}
+			if w.Current() != cfg {
+				t.Error("a rejected change replaced the current configuration")
+			}
+		})
+	}
+}
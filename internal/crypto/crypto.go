@@ -0,0 +1,241 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package crypto encrypts personal data for storage with envelope
+// encryption. Values are encrypted with data keys, which are in turn
+// wrapped by key encryption keys held by a KeyProvider, such as a KMS.
+// Blind indexes let encrypted values be looked up by equality.
+package crypto
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+)
+
+// prefix marks values encrypted by an Encryptor. It is followed by the key
+// ID, the wrapped data key and the sealed value, separated by colons.
+const prefix = "enc:v1:"
+
+// DataKeySize is the size of data keys and local key encryption keys
+const DataKeySize = 32
+
+// DefaultDataKeyTTL is how long a data key encrypts new values by default
+const DefaultDataKeyTTL = time.Hour
+
+// maxCachedKeys bounds the unwrapped data keys kept for decryption
+const maxCachedKeys = 1024
+
+// KeyProvider generates and unwraps data keys. Its methods mirror the data
+// key APIs of cloud KMSs, so that one can back it.
+type KeyProvider interface {
+	// KeyID returns the ID of the key encryption key new data keys are
+	// wrapped with
+	KeyID() string
+	// GenerateDataKey returns a new data key, in plaintext and wrapped by
+	// the key encryption key keyID
+	GenerateDataKey(ctx context.Context) (keyID string, plaintext, wrapped []byte, err error)
+	// Decrypt unwraps a data key wrapped by the key encryption key keyID
+	Decrypt(ctx context.Context, keyID string, wrapped []byte) ([]byte, error)
+}
+
+// dataKey is the data key new values are encrypted with
+type dataKey struct {
+	keyID   string
+	aead    cipher.AEAD
+	wrapped string
+	expires time.Time
+}
+
+// Encryptor encrypts and decrypts values, reusing each data key for up to
+// a TTL so that encrypting does not call the KeyProvider every time
+type Encryptor struct {
+	keys     KeyProvider
+	indexKey []byte
+	ttl      time.Duration
+
+	mu      sync.Mutex
+	current *dataKey
+	cache   map[string]cipher.AEAD
+}
+
+// NewEncryptor creates an encryptor. indexKey keys the blind indexes and
+// must be at least DataKeySize bytes; a ttl of zero uses
+// DefaultDataKeyTTL.
+func NewEncryptor(keys KeyProvider, indexKey []byte, ttl time.Duration) (*Encryptor, error) {
+	if len(indexKey) < DataKeySize {
+		return nil, errors.WithCode(errors.Newf("index key must be at least %d bytes", DataKeySize), errors.CodeInvalidInput)
+	}
+	if ttl <= 0 {
+		ttl = DefaultDataKeyTTL
+	}
+	return &Encryptor{
+		keys:     keys,
+		indexKey: indexKey,
+		ttl:      ttl,
+		cache:    make(map[string]cipher.AEAD),
+	}, nil
+}
+
+// Encrypt encrypts value. Equal values encrypt differently; use Index to
+// look them up.
+func (e *Encryptor) Encrypt(ctx context.Context, value string) (string, error) {
+	key, err := e.dataKey(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, key.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", errors.Wrap(err, "failed to generate nonce")
+	}
+	sealed := key.aead.Seal(nonce, nonce, []byte(value), nil)
+
+	return prefix + key.keyID + ":" + key.wrapped + ":" + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt decrypts a value returned by Encrypt. Values that are not
+// encrypted, such as those stored before encryption was enabled, are
+// returned unchanged.
+func (e *Encryptor) Decrypt(ctx context.Context, value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+
+	parts := strings.Split(strings.TrimPrefix(value, prefix), ":")
+	if len(parts) != 3 {
+		return "", errors.New("malformed encrypted value")
+	}
+	aead, err := e.unwrap(ctx, parts[0], parts[1])
+	if err != nil {
+		return "", err
+	}
+
+	sealed, err := base64.RawStdEncoding.DecodeString(parts[2])
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return "", errors.New("malformed encrypted value")
+	}
+	plaintext, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], nil)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to decrypt value")
+	}
+	return string(plaintext), nil
+}
+
+// Index returns the blind index of value: a keyed hash that is equal for
+// equal values and reveals nothing else about them
+func (e *Encryptor) Index(value string) string {
+	mac := hmac.New(sha256.New, e.indexKey)
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Stale reports whether value should be encrypted again: it is not
+// encrypted, or its data key is wrapped by a retired key encryption key
+func (e *Encryptor) Stale(value string) bool {
+	if !IsEncrypted(value) {
+		return true
+	}
+	keyID, _, _ := strings.Cut(strings.TrimPrefix(value, prefix), ":")
+	return keyID != e.keys.KeyID()
+}
+
+// IsEncrypted reports whether value was returned by Encrypt
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, prefix)
+}
+
+// dataKey returns the data key to encrypt with, generating a new one when
+// the current one has expired or the key encryption key was rotated
+func (e *Encryptor) dataKey(ctx context.Context) (*dataKey, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if k := e.current; k != nil && time.Now().Before(k.expires) && k.keyID == e.keys.KeyID() {
+		return k, nil
+	}
+
+	keyID, plaintext, wrapped, err := e.keys.GenerateDataKey(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate data key")
+	}
+	aead, err := newAEAD(plaintext)
+	if err != nil {
+		return nil, err
+	}
+	e.current = &dataKey{
+		keyID:   keyID,
+		aead:    aead,
+		wrapped: base64.RawStdEncoding.EncodeToString(wrapped),
+		expires: time.Now().Add(e.ttl),
+	}
+	return e.current, nil
+}
+
+// unwrap returns the cipher of a wrapped data key
+func (e *Encryptor) unwrap(ctx context.Context, keyID, wrapped string) (cipher.AEAD, error) {
+	cacheKey := keyID + ":" + wrapped
+
+	e.mu.Lock()
+	aead, ok := e.cache[cacheKey]
+	e.mu.Unlock()
+	if ok {
+		return aead, nil
+	}
+
+	raw, err := base64.RawStdEncoding.DecodeString(wrapped)
+	if err != nil {
+		return nil, errors.New("malformed encrypted value")
+	}
+	plaintext, err := e.keys.Decrypt(ctx, keyID, raw)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to unwrap data key")
+	}
+	if aead, err = newAEAD(plaintext); err != nil {
+		return nil, err
+	}
+
+	e.mu.Lock()
+	if len(e.cache) >= maxCachedKeys {
+		e.cache = make(map[string]cipher.AEAD)
+	}
+	e.cache[cacheKey] = aead
+	e.mu.Unlock()
+	return aead, nil
+}
+
+// newAEAD returns an AES-256-GCM cipher
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	if len(key) != DataKeySize {
+		return nil, errors.Newf("key must be %d bytes", DataKeySize)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create cipher")
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,157 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package crypto
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/config"
+)
+
+func newTestEncryptor(t *testing.T, current string, ids ...string) *Encryptor {
+	t.Helper()
+	keys := make(map[string][]byte, len(ids))
+	for i, id := range ids {
+		keys[id] = bytes.Repeat([]byte{byte(i + 1)}, DataKeySize)
+	}
+	provider, err := NewLocalKeys(keys, current)
+	if err != nil {
+		t.Fatalf("NewLocalKeys() error = %v", err)
+	}
+	enc, err := NewEncryptor(provider, bytes.Repeat([]byte{'i'}, DataKeySize), time.Hour)
+	if err != nil {
+		t.Fatalf("NewEncryptor() error = %v", err)
+	}
+	return enc
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	ctx := context.Background()
+	enc := newTestEncryptor(t, "k1", "k1")
+
+	tests := []string{"alice@example.com", "", "ユーザー@example.jp"}
+	for _, value := range tests {
+		sealed, err := enc.Encrypt(ctx, value)
+		if err != nil {
+			t.Fatalf("Encrypt(%q) error = %v", value, err)
+		}
+		if !IsEncrypted(sealed) || (value != "" && strings.Contains(sealed, value)) {
+			t.Errorf("Encrypt(%q) = %q, not encrypted", value, sealed)
+		}
+		got, err := enc.Decrypt(ctx, sealed)
+		if err != nil {
+			t.Fatalf("Decrypt() error = %v", err)
+		}
+		if got != value {
+			t.Errorf("Decrypt() = %q, want %q", got, value)
+		}
+	}
+
+	a, _ := enc.Encrypt(ctx, "alice@example.com")
+	b, _ := enc.Encrypt(ctx, "alice@example.com")
+	if a == b {
+		t.Error("Encrypt() is deterministic")
+	}
+
+	if got, err := enc.Decrypt(ctx, "legacy@example.com"); err != nil || got != "legacy@example.com" {
+		t.Errorf("Decrypt(plaintext) = %q, %v, want it unchanged", got, err)
+	}
+
+	tampered := a[:len(a)-2] + "AA"
+	if _, err := enc.Decrypt(ctx, tampered); err == nil {
+		t.Error("Decrypt() accepted a tampered value")
+	}
+}
+
+func TestKeyRotation(t *testing.T) {
+	ctx := context.Background()
+	old := newTestEncryptor(t, "k1", "k1")
+	sealed, err := old.Encrypt(ctx, "alice@example.com")
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+
+	rotated := newTestEncryptor(t, "k2", "k1", "k2")
+	if !rotated.Stale(sealed) {
+		t.Error("Stale() = false for a value under a retired key")
+	}
+	if got, err := rotated.Decrypt(ctx, sealed); err != nil || got != "alice@example.com" {
+		t.Errorf("Decrypt() = %q, %v after rotation", got, err)
+	}
+
+	resealed, err := rotated.Encrypt(ctx, "alice@example.com")
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+	if rotated.Stale(resealed) {
+		t.Error("Stale() = true for a value under the current key")
+	}
+	if !rotated.Stale("alice@example.com") {
+		t.Error("Stale() = false for a plaintext value")
+	}
+
+	retired := newTestEncryptor(t, "k2", "k2")
+	if _, err := retired.Decrypt(ctx, sealed); err == nil {
+		t.Error("Decrypt() succeeded without the key that wrapped its data key")
+	}
+}
+
+func TestIndex(t *testing.T) {
+	enc := newTestEncryptor(t, "k1", "k1")
+	rotated := newTestEncryptor(t, "k2", "k1", "k2")
+
+	if enc.Index("alice@example.com") != enc.Index("alice@example.com") {
+		t.Error("Index() is not deterministic")
+	}
+	if enc.Index("alice@example.com") == enc.Index("bob@example.com") {
+		t.Error("Index() collided for different values")
+	}
+	if enc.Index("alice@example.com") != rotated.Index("alice@example.com") {
+		t.Error("Index() changed with the key encryption key")
+	}
+}
+
+func TestNew(t *testing.T) {
+	key := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{1}, DataKeySize))
+	index := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{2}, DataKeySize))
+
+	tests := []struct {
+		name    string
+		cfg     config.Encryption
+		wantErr bool
+	}{
+		{name: "local", cfg: config.Encryption{Provider: "local", KeyID: "k1", SecretKeys: []string{"k1:" + key}, IndexSecret: index}},
+		{name: "unknown provider", cfg: config.Encryption{Provider: "vault", KeyID: "k1", SecretKeys: []string{"k1:" + key}, IndexSecret: index}, wantErr: true},
+		{name: "missing current key", cfg: config.Encryption{Provider: "local", KeyID: "k2", SecretKeys: []string{"k1:" + key}, IndexSecret: index}, wantErr: true},
+		{name: "short key", cfg: config.Encryption{Provider: "local", KeyID: "k1", SecretKeys: []string{"k1:a2V5"}, IndexSecret: index}, wantErr: true},
+		{name: "malformed entry", cfg: config.Encryption{Provider: "local", KeyID: "k1", SecretKeys: []string{key}, IndexSecret: index}, wantErr: true},
+		{name: "short index secret", cfg: config.Encryption{Provider: "local", KeyID: "k1", SecretKeys: []string{"k1:" + key}, IndexSecret: "aW5kZXg="}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := New(&tt.cfg); (err != nil) != tt.wantErr {
+				t.Errorf("New() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package crypto
+
+import (
+	"context"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"io"
+	"strings"
+
+	"github.com/kevindiu/monorepo-go-example/internal/config"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+)
+
+// ProviderLocal wraps data keys with key encryption keys from the config
+const ProviderLocal = "local"
+
+// New creates the encryptor configured by cfg
+func New(cfg *config.Encryption) (*Encryptor, error) {
+	indexKey, err := base64.StdEncoding.DecodeString(cfg.IndexSecret)
+	if err != nil {
+		return nil, errors.WithCode(errors.New("encryption.index_secret is not base64"), errors.CodeInvalidInput)
+	}
+
+	var keys KeyProvider
+	switch cfg.Provider {
+	case ProviderLocal, "":
+		if keys, err = localKeysFromConfig(cfg); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, errors.WithCode(errors.Newf("unsupported encryption provider %q", cfg.Provider), errors.CodeInvalidInput)
+	}
+	return NewEncryptor(keys, indexKey, cfg.DataKeyTTL)
+}
+
+// localKeysFromConfig parses the "id:base64 key" entries of
+// encryption.secret_keys
+func localKeysFromConfig(cfg *config.Encryption) (KeyProvider, error) {
+	keys := make(map[string][]byte, len(cfg.SecretKeys))
+	for _, entry := range cfg.SecretKeys {
+		id, encoded, ok := strings.Cut(entry, ":")
+		if !ok {
+			return nil, errors.WithCode(errors.New("encryption.secret_keys entries must be id:base64 key"), errors.CodeInvalidInput)
+		}
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, errors.WithCode(errors.Newf("encryption key %q is not base64", id), errors.CodeInvalidInput)
+		}
+		keys[id] = key
+	}
+	return NewLocalKeys(keys, cfg.KeyID)
+}
+
+// localKeys wraps data keys with AES-256-GCM key encryption keys held in
+// memory
+type localKeys struct {
+	keys    map[string]cipher.AEAD
+	current string
+}
+
+// NewLocalKeys creates a KeyProvider from key encryption keys by ID. New
+// data keys are wrapped by the key current; the others only unwrap data
+// keys, so that rotated keys stay readable until their values are
+// encrypted again.
+func NewLocalKeys(keys map[string][]byte, current string) (KeyProvider, error) {
+	if _, ok := keys[current]; !ok {
+		return nil, errors.WithCode(errors.Newf("encryption key %q is not configured", current), errors.CodeInvalidInput)
+	}
+	p := &localKeys{keys: make(map[string]cipher.AEAD, len(keys)), current: current}
+	for id, key := range keys {
+		if id == "" || strings.Contains(id, ":") {
+			return nil, errors.WithCode(errors.Newf("invalid encryption key ID %q", id), errors.CodeInvalidInput)
+		}
+		aead, err := newAEAD(key)
+		if err != nil {
+			return nil, errors.WithCode(errors.Wrapf(err, "invalid encryption key %q", id), errors.CodeInvalidInput)
+		}
+		p.keys[id] = aead
+	}
+	return p, nil
+}
+
+func (p *localKeys) KeyID() string {
+	return p.current
+}
+
+func (p *localKeys) GenerateDataKey(ctx context.Context) (string, []byte, []byte, error) {
+	plaintext := make([]byte, DataKeySize)
+	if _, err := io.ReadFull(rand.Reader, plaintext); err != nil {
+		return "", nil, nil, errors.Wrap(err, "failed to generate data key")
+	}
+
+	aead := p.keys[p.current]
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", nil, nil, errors.Wrap(err, "failed to generate nonce")
+	}
+	return p.current, plaintext, aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func (p *localKeys) Decrypt(ctx context.Context, keyID string, wrapped []byte) ([]byte, error) {
+	aead, ok := p.keys[keyID]
+	if !ok {
+		return nil, errors.Newf("encryption key %q is not configured", keyID)
+	}
+	if len(wrapped) < aead.NonceSize() {
+		return nil, errors.New("malformed wrapped data key")
+	}
+	return aead.Open(nil, wrapped[:aead.NonceSize()], wrapped[aead.NonceSize():], nil)
+}
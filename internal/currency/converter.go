@@ -0,0 +1,111 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package currency
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/config"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/internal/log"
+)
+
+// Converter converts amounts with rates from a provider, fetching them at
+// most once per refresh interval. When a refresh fails, the last rates keep
+// being served so a provider outage does not break conversions.
+type Converter struct {
+	provider  Provider
+	interval  time.Duration
+	logger    *log.Logger
+	now       func() time.Time
+	mu        sync.Mutex
+	rates     *Rates
+	fetchedAt time.Time
+}
+
+// New creates a converter for the configured provider
+func New(cfg *config.Currency, logger *log.Logger) (*Converter, error) {
+	var provider Provider
+	switch cfg.Provider {
+	case "ecb":
+		provider = NewECB("", nil)
+	case "openexchangerates":
+		provider = NewOpenExchangeRates("", cfg.OpenExchangeRatesAppID, nil)
+	default:
+		return nil, errors.Newf("unknown exchange rate provider %q", cfg.Provider)
+	}
+	return NewConverter(provider, cfg.RefreshInterval, logger), nil
+}
+
+// NewConverter creates a converter that refreshes rates every interval
+func NewConverter(provider Provider, interval time.Duration, logger *log.Logger) *Converter {
+	if interval <= 0 {
+		interval = 24 * time.Hour
+	}
+	if logger == nil {
+		logger = log.NewDefault()
+	}
+	return &Converter{
+		provider: provider,
+		interval: interval,
+		logger:   logger,
+		now:      time.Now,
+	}
+}
+
+// Rates returns the cached rates, refreshing them when they are stale
+func (c *Converter) Rates(ctx context.Context) (*Rates, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	if c.rates != nil && now.Sub(c.fetchedAt) < c.interval {
+		return c.rates, nil
+	}
+
+	rates, err := c.provider.Latest(ctx)
+	if err != nil {
+		if c.rates == nil {
+			return nil, err
+		}
+		c.logger.Warn("Failed to refresh exchange rates, serving cached rates",
+			log.Any("rates_date", c.rates.Date), log.Error(err))
+		// Back off for a fraction of the interval rather than retrying on
+		// every conversion
+		c.fetchedAt = now.Add(-c.interval + c.interval/24)
+		return c.rates, nil
+	}
+
+	c.rates = rates
+	c.fetchedAt = now
+	return rates, nil
+}
+
+// Convert converts an amount in minor units between currencies, rounded to
+// the minor unit of to
+func (c *Converter) Convert(ctx context.Context, amount int64, from, to string) (int64, error) {
+	if from == to {
+		return amount, nil
+	}
+	rates, err := c.Rates(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return rates.Convert(amount, from, to)
+}
@@ -0,0 +1,85 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package currency converts amounts between currencies using daily exchange
+// rates
+package currency
+
+import (
+	"context"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/internal/money"
+)
+
+// Rates are exchange rates from one base currency on a given day. Each rate
+// is the number of units of the currency that one unit of Base buys.
+type Rates struct {
+	Base  string
+	Date  time.Time
+	Rates map[string]float64
+}
+
+// Provider fetches the latest exchange rates
+type Provider interface {
+	Latest(ctx context.Context) (*Rates, error)
+}
+
+// Normalize returns a currency code in canonical upper case, rejecting
+// anything that is not an ISO 4217 code
+func Normalize(code string) (string, error) {
+	return money.Normalize(code)
+}
+
+// Rate returns how many units of to one unit of from buys, crossing through
+// the base currency when neither side is the base
+func (r *Rates) Rate(from, to string) (float64, error) {
+	if from == to {
+		return 1, nil
+	}
+
+	fromRate, err := r.rate(from)
+	if err != nil {
+		return 0, err
+	}
+	toRate, err := r.rate(to)
+	if err != nil {
+		return 0, err
+	}
+	return toRate / fromRate, nil
+}
+
+// Convert converts an amount in minor units between currencies, rounded to
+// the minor unit of to
+func (r *Rates) Convert(amount int64, from, to string) (int64, error) {
+	rate, err := r.Rate(from, to)
+	if err != nil {
+		return 0, err
+	}
+	return money.Convert(amount, from, to, rate)
+}
+
+func (r *Rates) rate(code string) (float64, error) {
+	if code == r.Base {
+		return 1, nil
+	}
+	rate, ok := r.Rates[code]
+	if !ok || rate <= 0 {
+		return 0, errors.WithCode(errors.Newf("no exchange rate for %s", code), errors.CodeInvalidInput)
+	}
+	return rate, nil
+}
@@ -0,0 +1,199 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package currency
+
+import (
+	"context"
+	stderrors "errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		code    string
+		want    string
+		wantErr bool
+	}{
+		{code: "USD", want: "USD"},
+		{code: " eur ", want: "EUR"},
+		{code: "EURO", wantErr: true},
+		{code: "U1D", wantErr: true},
+		{code: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			got, err := Normalize(tt.code)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Normalize(%q) error = %v, wantErr %v", tt.code, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Normalize(%q) = %q, want %q", tt.code, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRatesConvert(t *testing.T) {
+	rates := &Rates{Base: "EUR", Rates: map[string]float64{"USD": 1.25, "JPY": 150}}
+
+	tests := []struct {
+		name     string
+		amount   int64
+		from, to string
+		want     int64
+		wantErr  bool
+	}{
+		{name: "same currency", amount: 1000, from: "USD", to: "USD", want: 1000},
+		{name: "from base", amount: 1000, from: "EUR", to: "USD", want: 1250},
+		{name: "to base", amount: 1250, from: "USD", to: "EUR", want: 1000},
+		{name: "cross rate", amount: 1000, from: "USD", to: "JPY", want: 1200},
+		{name: "rounds to cents", amount: 1, from: "JPY", to: "USD", want: 1},
+		{name: "unknown currency", amount: 1000, from: "EUR", to: "GBP", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := rates.Convert(tt.amount, tt.from, tt.to)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Convert() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Convert() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestECB(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<gesmes:Envelope xmlns:gesmes="http://www.gesmes.org/xml/2002-08-01" xmlns="http://www.ecb.int/vocabulary/2002-08-01/eurofxref">
+	<gesmes:subject>Reference rates</gesmes:subject>
+	<Cube>
+		<Cube time="2025-01-02">
+			<Cube currency="USD" rate="1.0321"/>
+			<Cube currency="JPY" rate="162.65"/>
+		</Cube>
+	</Cube>
+</gesmes:Envelope>`))
+	}))
+	defer srv.Close()
+
+	rates, err := NewECB(srv.URL, nil).Latest(context.Background())
+	if err != nil {
+		t.Fatalf("Latest() error = %v", err)
+	}
+	if rates.Base != "EUR" || rates.Rates["USD"] != 1.0321 || rates.Rates["JPY"] != 162.65 {
+		t.Errorf("Latest() = %+v", rates)
+	}
+	if want := time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC); !rates.Date.Equal(want) {
+		t.Errorf("Date = %v, want %v", rates.Date, want)
+	}
+}
+
+func TestOpenExchangeRates(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("app_id") != "key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"timestamp": 1735776000, "base": "USD", "rates": {"EUR": 0.9689, "GBP": 0.8}}`))
+	}))
+	defer srv.Close()
+
+	rates, err := NewOpenExchangeRates(srv.URL, "key", nil).Latest(context.Background())
+	if err != nil {
+		t.Fatalf("Latest() error = %v", err)
+	}
+	if rates.Base != "USD" || rates.Rates["EUR"] != 0.9689 {
+		t.Errorf("Latest() = %+v", rates)
+	}
+
+	if _, err := NewOpenExchangeRates(srv.URL, "wrong", nil).Latest(context.Background()); err == nil {
+		t.Error("Latest() with a rejected app ID should fail")
+	}
+}
+
+// flakyProvider counts fetches and fails on demand
+type flakyProvider struct {
+	calls int
+	err   error
+}
+
+func (f *flakyProvider) Latest(ctx context.Context) (*Rates, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &Rates{Base: "USD", Rates: map[string]float64{"EUR": 0.5}}, nil
+}
+
+func TestConverterCachesRates(t *testing.T) {
+	provider := &flakyProvider{}
+	c := NewConverter(provider, 24*time.Hour, nil)
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	c.now = func() time.Time { return now }
+
+	// No rates yet and the provider is down
+	provider.err = stderrors.New("unavailable")
+	if _, err := c.Convert(context.Background(), 1000, "USD", "EUR"); err == nil {
+		t.Fatal("Convert() without any rates should fail")
+	}
+
+	provider.err = nil
+	for i := 0; i < 3; i++ {
+		got, err := c.Convert(context.Background(), 1000, "USD", "EUR")
+		if err != nil || got != 500 {
+			t.Fatalf("Convert() = %v, %v", got, err)
+		}
+	}
+	if provider.calls != 2 {
+		t.Errorf("provider called %d times, want 2", provider.calls)
+	}
+
+	// Stale rates are refreshed; a failed refresh keeps serving them
+	now = now.Add(25 * time.Hour)
+	provider.err = stderrors.New("unavailable")
+	if got, err := c.Convert(context.Background(), 1000, "USD", "EUR"); err != nil || got != 500 {
+		t.Fatalf("Convert() during outage = %v, %v", got, err)
+	}
+	if provider.calls != 3 {
+		t.Errorf("provider called %d times, want 3", provider.calls)
+	}
+
+	// The failed refresh is not retried on every conversion
+	c.Convert(context.Background(), 1000, "USD", "EUR")
+	if provider.calls != 3 {
+		t.Errorf("provider called %d times after an outage, want 3", provider.calls)
+	}
+}
+
+func TestSameCurrencySkipsProvider(t *testing.T) {
+	provider := &flakyProvider{err: stderrors.New("unavailable")}
+	c := NewConverter(provider, 0, nil)
+
+	if got, err := c.Convert(context.Background(), 1234, "USD", "USD"); err != nil || got != 1234 {
+		t.Errorf("Convert() = %v, %v", got, err)
+	}
+	if provider.calls != 0 {
+		t.Error("provider was called for a same-currency conversion")
+	}
+}
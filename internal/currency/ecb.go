@@ -0,0 +1,111 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package currency
+
+import (
+	"context"
+	"encoding/xml"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+)
+
+// ECBURL is the European Central Bank's daily reference rate feed
+const ECBURL = "https://www.ecb.europa.eu/stats/eurofxref/eurofxref-daily.xml"
+
+// ECB fetches the European Central Bank's euro reference rates, published
+// once per working day
+type ECB struct {
+	url    string
+	client *http.Client
+}
+
+// NewECB creates an ECB provider. An empty url uses ECBURL.
+func NewECB(url string, client *http.Client) *ECB {
+	if url == "" {
+		url = ECBURL
+	}
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &ECB{url: url, client: client}
+}
+
+// ecbEnvelope is the shape of the ECB feed
+type ecbEnvelope struct {
+	Cube struct {
+		Cube struct {
+			Time  string `xml:"time,attr"`
+			Rates []struct {
+				Currency string `xml:"currency,attr"`
+				Rate     string `xml:"rate,attr"`
+			} `xml:"Cube"`
+		} `xml:"Cube"`
+	} `xml:"Cube"`
+}
+
+// Latest fetches the most recent reference rates
+func (e *ECB) Latest(ctx context.Context) (*Rates, error) {
+	var env ecbEnvelope
+	if err := fetch(ctx, e.client, e.url, func(resp *http.Response) error {
+		return xml.NewDecoder(resp.Body).Decode(&env)
+	}); err != nil {
+		return nil, err
+	}
+
+	date, err := time.Parse("2006-01-02", env.Cube.Cube.Time)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid ECB rate date")
+	}
+
+	rates := &Rates{Base: "EUR", Date: date, Rates: make(map[string]float64)}
+	for _, r := range env.Cube.Cube.Rates {
+		rate, err := strconv.ParseFloat(r.Rate, 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid ECB rate for %s", r.Currency)
+		}
+		rates.Rates[r.Currency] = rate
+	}
+	if len(rates.Rates) == 0 {
+		return nil, errors.New("ECB feed contained no rates")
+	}
+	return rates, nil
+}
+
+// fetch performs a GET request and decodes a successful response
+func fetch(ctx context.Context, client *http.Client, url string, decode func(*http.Response) error) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to create rates request")
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return errors.WithCode(errors.Wrap(err, "failed to fetch exchange rates"), errors.CodeUnavailable)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return errors.WithCode(errors.Newf("exchange rate provider returned %s", resp.Status), errors.CodeUnavailable)
+	}
+	if err := decode(resp); err != nil {
+		return errors.Wrap(err, "failed to decode exchange rates")
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package currency
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+)
+
+// OpenExchangeRatesURL is the Open Exchange Rates latest rates endpoint
+const OpenExchangeRatesURL = "https://openexchangerates.org/api/latest.json"
+
+// OpenExchangeRates fetches rates from openexchangerates.org. Free plans
+// only serve US dollar based rates.
+type OpenExchangeRates struct {
+	url    string
+	appID  string
+	client *http.Client
+}
+
+// NewOpenExchangeRates creates an Open Exchange Rates provider. An empty
+// endpoint uses OpenExchangeRatesURL.
+func NewOpenExchangeRates(endpoint, appID string, client *http.Client) *OpenExchangeRates {
+	if endpoint == "" {
+		endpoint = OpenExchangeRatesURL
+	}
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &OpenExchangeRates{url: endpoint, appID: appID, client: client}
+}
+
+// Latest fetches the most recent rates
+func (o *OpenExchangeRates) Latest(ctx context.Context) (*Rates, error) {
+	if o.appID == "" {
+		return nil, errors.New("open exchange rates app ID is required")
+	}
+
+	var body struct {
+		Timestamp int64              `json:"timestamp"`
+		Base      string             `json:"base"`
+		Rates     map[string]float64 `json:"rates"`
+	}
+	endpoint := o.url + "?" + url.Values{"app_id": {o.appID}}.Encode()
+	if err := fetch(ctx, o.client, endpoint, func(resp *http.Response) error {
+		return json.NewDecoder(resp.Body).Decode(&body)
+	}); err != nil {
+		return nil, err
+	}
+
+	if body.Base == "" || len(body.Rates) == 0 {
+		return nil, errors.New("open exchange rates response contained no rates")
+	}
+	return &Rates{
+		Base:  body.Base,
+		Date:  time.Unix(body.Timestamp, 0).UTC(),
+		Rates: body.Rates,
+	}, nil
+}
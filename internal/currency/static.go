@@ -0,0 +1,39 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package currency
+
+import (
+	"context"
+	"time"
+)
+
+// Static is a provider with fixed rates, for tests and offline development
+type Static struct {
+	rates Rates
+}
+
+// NewStatic creates a provider that always returns the given rates
+func NewStatic(base string, rates map[string]float64) *Static {
+	return &Static{rates: Rates{Base: base, Rates: rates}}
+}
+
+// Latest returns the fixed rates, dated today
+func (s *Static) Latest(ctx context.Context) (*Rates, error) {
+	rates := s.rates
+	rates.Date = time.Now().UTC().Truncate(24 * time.Hour)
+	return &rates, nil
+}
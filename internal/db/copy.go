@@ -0,0 +1,107 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package db
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/lib/pq"
+)
+
+// maxParams is the most bind parameters PostgreSQL accepts in one statement
+const maxParams = 65535
+
+// CopyIn bulk inserts rows into table with COPY FROM STDIN, which is much
+// faster than an INSERT per row. Each row holds a value per column. It
+// must run in a transaction, which a rejected row aborts. With the pgx
+// driver the rows are inserted with multi-row INSERTs instead.
+func (db *DB) CopyIn(ctx context.Context, tx Executor, table string, columns []string, rows [][]interface{}) error {
+	if db.driver == DriverPGX {
+		return insertRows(ctx, tx, table, columns, rows)
+	}
+
+	stmt, err := tx.PrepareContext(ctx, pq.CopyIn(table, columns...))
+	if err != nil {
+		return errors.Wrapf(err, "failed to prepare copy into %s", table)
+	}
+	defer stmt.Close()
+
+	for _, row := range rows {
+		if _, err := stmt.ExecContext(ctx, row...); err != nil {
+			return errors.Wrapf(err, "failed to copy into %s", table)
+		}
+	}
+	// Flush the buffered rows; constraint violations surface here
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		return errors.Wrapf(err, "failed to copy into %s", table)
+	}
+	return nil
+}
+
+// insertRows inserts rows with as few INSERT statements as the parameter
+// limit allows
+func insertRows(ctx context.Context, tx Executor, table string, columns []string, rows [][]interface{}) error {
+	batch := maxParams / len(columns)
+	for start := 0; start < len(rows); start += batch {
+		end := start + batch
+		if end > len(rows) {
+			end = len(rows)
+		}
+		args := make([]interface{}, 0, (end-start)*len(columns))
+		for _, row := range rows[start:end] {
+			args = append(args, row...)
+		}
+		if _, err := tx.ExecContext(ctx, insertQuery(table, columns, end-start), args...); err != nil {
+			return errors.Wrapf(err, "failed to insert into %s", table)
+		}
+	}
+	return nil
+}
+
+// insertQuery returns an INSERT of n rows into table
+func insertQuery(table string, columns []string, n int) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "INSERT INTO %s (%s) VALUES ", pq.QuoteIdentifier(table), quoteColumns(columns))
+	param := 1
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteByte('(')
+		for j := range columns {
+			if j > 0 {
+				b.WriteString(", ")
+			}
+			fmt.Fprintf(&b, "$%d", param)
+			param++
+		}
+		b.WriteByte(')')
+	}
+	return b.String()
+}
+
+// quoteColumns returns columns as a quoted, comma separated list
+func quoteColumns(columns []string) string {
+	quoted := make([]string, len(columns))
+	for i, column := range columns {
+		quoted[i] = pq.QuoteIdentifier(column)
+	}
+	return strings.Join(quoted, ", ")
+}
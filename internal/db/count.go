@@ -0,0 +1,76 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+)
+
+// CountCap is the number of rows Count counts exactly; larger results are
+// estimated
+const CountCap = 1000
+
+// Count returns the number of rows of SELECT 1 FROM from, where from is a
+// FROM clause with its WHERE conditions, and whether the number is exact.
+// Up to CountCap rows are counted exactly, and the count stops scanning at
+// the cap. Larger results are the query planner's estimate instead, which
+// comes from table statistics (pg_class.reltuples and column histograms)
+// and scans nothing.
+func (db *DB) Count(ctx context.Context, from string, args ...interface{}) (int64, bool, error) {
+	var n int64
+	query := fmt.Sprintf("SELECT COUNT(*) FROM (SELECT 1 FROM %s LIMIT %d) capped", from, CountCap+1)
+	if err := db.Executor(ctx).QueryRowContext(ctx, query, args...).Scan(&n); err != nil {
+		return 0, false, errors.Wrap(err, "failed to count rows")
+	}
+	if n <= CountCap {
+		return n, true, nil
+	}
+
+	var plan []byte
+	if err := db.Executor(ctx).QueryRowContext(ctx, "EXPLAIN (FORMAT JSON) SELECT 1 FROM "+from, args...).Scan(&plan); err != nil {
+		return 0, false, errors.Wrap(err, "failed to estimate row count")
+	}
+	estimate, err := planRows(plan)
+	if err != nil {
+		return 0, false, err
+	}
+	// Statistics may lag behind, but the capped count saw more rows
+	if estimate <= CountCap {
+		estimate = CountCap + 1
+	}
+	return estimate, false, nil
+}
+
+// planRows returns the row estimate of an EXPLAIN (FORMAT JSON) plan
+func planRows(plan []byte) (int64, error) {
+	var explain []struct {
+		Plan struct {
+			Rows float64 `json:"Plan Rows"`
+		} `json:"Plan"`
+	}
+	if err := json.Unmarshal(plan, &explain); err != nil {
+		return 0, errors.Wrap(err, "failed to parse query plan")
+	}
+	if len(explain) == 0 {
+		return 0, errors.New("query plan is empty")
+	}
+	return int64(explain[0].Plan.Rows), nil
+}
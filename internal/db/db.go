@@ -19,122 +19,120 @@ package db
 import (
 	"context"
 	"database/sql"
-	"fmt"
-	"time"
 
+	"github.com/XSAM/otelsql"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
 	"github.com/kevindiu/monorepo-go-example/internal/config"
 	"github.com/kevindiu/monorepo-go-example/internal/errors"
 	_ "github.com/lib/pq"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+)
+
+// Drivers selectable with config.Database.Driver
+const (
+	DriverPQ  = "pq"
+	DriverPGX = "pgx"
 )
 
 // DB wraps database connection
 type DB struct {
 	*sql.DB
+	driver string
+	// pgxConfig is the name the pgx connection config is registered under
+	pgxConfig string
 }
 
 // Connect establishes database connection
 func Connect(cfg *config.Database) (*DB, error) {
-	db, err := sql.Open("postgres", cfg.GetDSN())
+	driverName, dsn := "postgres", cfg.GetDSN()
+	var pgxConfig string
+	if cfg.Driver == DriverPGX {
+		connConfig, err := pgx.ParseConfig(dsn)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse database connection config")
+		}
+		connConfig.StatementCacheCapacity = cfg.StatementCacheCapacity
+		if cfg.StatementCacheCapacity == 0 {
+			// Describe each query instead of preparing it, so no statement
+			// outlives the session a pooler hands out
+			connConfig.DefaultQueryExecMode = pgx.QueryExecModeExec
+		}
+		pgxConfig = stdlib.RegisterConnConfig(connConfig)
+		driverName, dsn = "pgx", pgxConfig
+	}
+
+	// Queries are traced as children of the calling request's span
+	db, err := otelsql.Open(driverName, dsn,
+		otelsql.WithAttributes(semconv.DBSystemPostgreSQL),
+		otelsql.WithSpanOptions(otelsql.SpanOptions{OmitConnResetSession: true, OmitRows: true}),
+	)
 	if err != nil {
+		unregister(pgxConfig)
 		return nil, errors.Wrap(err, "failed to open database connection")
 	}
 
 	// Set connection pool settings
-	db.SetMaxOpenConns(25)
-	db.SetMaxIdleConns(5)
-	db.SetConnMaxLifetime(5 * time.Minute)
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
 
 	// Test connection
 	if err := db.Ping(); err != nil {
+		db.Close()
+		unregister(pgxConfig)
 		return nil, errors.Wrap(err, "failed to ping database")
 	}
 
-	return &DB{DB: db}, nil
+	driver := cfg.Driver
+	if driver == "" {
+		driver = DriverPQ
+	}
+	return &DB{DB: db, driver: driver, pgxConfig: pgxConfig}, nil
+}
+
+// unregister drops a registered pgx connection config, if any
+func unregister(pgxConfig string) {
+	if pgxConfig != "" {
+		stdlib.UnregisterConnConfig(pgxConfig)
+	}
 }
 
 // Close closes database connection
 func (db *DB) Close() error {
+	defer unregister(db.pgxConfig)
 	if db.DB != nil {
 		return db.DB.Close()
 	}
 	return nil
 }
 
-// BeginTx starts a new transaction
-func (db *DB) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
+// BeginTx starts a new transaction, or joins the one a TxManager stashed
+// in ctx; a joined transaction ignores opts and is committed or rolled back
+// by its TxManager only
+func (db *DB) BeginTx(ctx context.Context, opts *sql.TxOptions) (*Tx, error) {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return &Tx{Tx: tx, joined: true}, nil
+	}
 	tx, err := db.DB.BeginTx(ctx, opts)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to begin transaction")
 	}
-	return tx, nil
-}
-
-// Migration represents a database migration
-type Migration struct {
-	Version int
-	Name    string
-	SQL     string
+	return &Tx{Tx: tx}, nil
 }
 
-// Migrate runs database migrations
-func (db *DB) Migrate(migrations []Migration) error {
-	// Create migrations table if not exists
-	createTable := `
-		CREATE TABLE IF NOT EXISTS migrations (
-			version INTEGER PRIMARY KEY,
-			name TEXT NOT NULL,
-			applied_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
-		);
-	`
-	if _, err := db.Exec(createTable); err != nil {
-		return errors.Wrap(err, "failed to create migrations table")
-	}
+// SchemaVersion is the latest migration in internal/db/migrations, which this
+// build expects to have been applied
+const SchemaVersion = 28
 
-	// Get applied migrations
-	applied := make(map[int]bool)
-	rows, err := db.Query("SELECT version FROM migrations")
+// AppliedVersion returns the latest applied migration, or 0 when none has
+// been applied
+func (db *DB) AppliedVersion(ctx context.Context) (int, error) {
+	var version int
+	err := db.QueryRowContext(ctx, "SELECT COALESCE(MAX(version), 0) FROM migrations").Scan(&version)
 	if err != nil {
-		return errors.Wrap(err, "failed to query applied migrations")
+		return 0, errors.Wrap(err, "failed to query schema version")
 	}
-	defer rows.Close()
-
-	for rows.Next() {
-		var version int
-		if err := rows.Scan(&version); err != nil {
-			return errors.Wrap(err, "failed to scan migration version")
-		}
-		applied[version] = true
-	}
-
-	// Run unapplied migrations
-	for _, migration := range migrations {
-		if applied[migration.Version] {
-			continue
-		}
-
-		tx, err := db.BeginTx(context.Background(), nil)
-		if err != nil {
-			return errors.Wrap(err, "failed to start migration transaction")
-		}
-
-		// Execute migration SQL
-		if _, err := tx.Exec(migration.SQL); err != nil {
-			tx.Rollback()
-			return errors.Wrapf(err, "failed to execute migration %d: %s", migration.Version, migration.Name)
-		}
-
-		// Record migration
-		if _, err := tx.Exec("INSERT INTO migrations (version, name) VALUES ($1, $2)", migration.Version, migration.Name); err != nil {
-			tx.Rollback()
-			return errors.Wrapf(err, "failed to record migration %d", migration.Version)
-		}
-
-		if err := tx.Commit(); err != nil {
-			return errors.Wrapf(err, "failed to commit migration %d", migration.Version)
-		}
-
-		fmt.Printf("Applied migration %d: %s\n", migration.Version, migration.Name)
-	}
-
-	return nil
+	return version, nil
 }
@@ -0,0 +1,167 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/lib/pq"
+)
+
+func TestSchemaVersionMatchesMigrations(t *testing.T) {
+	paths, err := filepath.Glob("migrations/*.sql")
+	if err != nil || len(paths) == 0 {
+		t.Fatalf("no migrations found: %v", err)
+	}
+
+	latest := 0
+	for _, path := range paths {
+		prefix, _, _ := strings.Cut(filepath.Base(path), "_")
+		version, err := strconv.Atoi(prefix)
+		if err != nil {
+			t.Fatalf("migration %s has no version prefix", path)
+		}
+		if version > latest {
+			latest = version
+		}
+	}
+
+	if SchemaVersion != latest {
+		t.Errorf("SchemaVersion = %d, latest migration is %d", SchemaVersion, latest)
+	}
+}
+
+func TestPlanRows(t *testing.T) {
+	tests := []struct {
+		name    string
+		plan    string
+		want    int64
+		wantErr bool
+	}{
+		{
+			name: "seq scan",
+			plan: `[{"Plan": {"Node Type": "Seq Scan", "Relation Name": "orders", "Plan Rows": 48213, "Plan Width": 0}}]`,
+			want: 48213,
+		},
+		{name: "empty", plan: `[]`, wantErr: true},
+		{name: "malformed", plan: `{`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := planRows([]byte(tt.plan))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("planRows() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("planRows() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsUniqueViolation(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "pq", err: &pq.Error{Code: "23505"}, want: true},
+		{name: "pgx", err: &pgconn.PgError{Code: "23505"}, want: true},
+		{name: "wrapped", err: errors.Wrap(&pgconn.PgError{Code: "23505"}, "failed to insert"), want: true},
+		{name: "foreign key", err: &pq.Error{Code: "23503"}},
+		{name: "other", err: fmt.Errorf("connection refused")},
+		{name: "nil"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsUniqueViolation(tt.err); got != tt.want {
+				t.Errorf("IsUniqueViolation() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInsertQuery(t *testing.T) {
+	got := insertQuery("order_items", []string{"id", "quantity"}, 2)
+	want := `INSERT INTO "order_items" ("id", "quantity") VALUES ($1, $2), ($3, $4)`
+	if got != want {
+		t.Errorf("insertQuery() = %q, want %q", got, want)
+	}
+}
+
+func TestBeginTxJoinsContextTransaction(t *testing.T) {
+	stashed := &sql.Tx{}
+	ctx := context.WithValue(context.Background(), txKey{}, stashed)
+	database := &DB{}
+
+	tx, err := database.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatalf("BeginTx() error = %v", err)
+	}
+	if tx.Tx != stashed {
+		t.Error("BeginTx() did not join the transaction in the context")
+	}
+	// The TxManager owns the transaction, so these must not reach it
+	if err := tx.Commit(); err != nil {
+		t.Errorf("Commit() error = %v", err)
+	}
+	if err := tx.Rollback(); err != nil {
+		t.Errorf("Rollback() error = %v", err)
+	}
+	if got := database.Executor(ctx); got != stashed {
+		t.Errorf("Executor() = %v, want the context transaction", got)
+	}
+}
+
+func TestAfterCommit(t *testing.T) {
+	calls := 0
+	hook := func() error {
+		calls++
+		return nil
+	}
+
+	if InTransaction(context.Background()) {
+		t.Error("InTransaction() of a bare context = true")
+	}
+	if err := AfterCommit(context.Background(), hook); err != nil || calls != 1 {
+		t.Errorf("AfterCommit() outside a transaction = %v, %d calls, want it run at once", err, calls)
+	}
+
+	hooks := &commitHooks{}
+	ctx := context.WithValue(context.WithValue(context.Background(), txKey{}, &sql.Tx{}), hooksKey{}, hooks)
+	if !InTransaction(ctx) {
+		t.Error("InTransaction() of a transaction context = false")
+	}
+	AfterCommit(ctx, hook)
+	AfterCommit(ctx, func() error { return errors.New("cache down") })
+	if calls != 1 {
+		t.Fatalf("AfterCommit() in a transaction ran before the commit")
+	}
+	if err := hooks.run(); err == nil || calls != 2 {
+		t.Errorf("run() = %v, %d calls, want the hook error after both ran", err, calls)
+	}
+}
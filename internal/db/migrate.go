@@ -0,0 +1,436 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/config"
+	"github.com/kevindiu/monorepo-go-example/internal/db/migrations"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+)
+
+// Migration represents a database migration. Down undoes it, and is empty
+// when the migration cannot be rolled back.
+type Migration struct {
+	Version int
+	Name    string
+	SQL     string
+	Down    string
+}
+
+// MigrationStatus is the state of a migration in the database
+type MigrationStatus struct {
+	Version   int
+	Name      string
+	Applied   bool
+	AppliedAt time.Time
+	// Dirty marks a migration that started but never finished, for example
+	// because the process running it died
+	Dirty bool
+	// Unknown marks an applied migration missing from the migrations the
+	// status was computed for
+	Unknown bool
+}
+
+// migrationLockKey is the advisory lock that serializes migrations, so that
+// replicas starting together apply each migration once
+const migrationLockKey = 0x6d6967726174
+
+// LoadMigrations reads the NNN_name.sql files at the root of fsys, ordered
+// by version. A NNN_name.down.sql file holds the Down of the migration with
+// the same version.
+func LoadMigrations(fsys fs.FS) ([]Migration, error) {
+	paths, err := fs.Glob(fsys, "*.sql")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list migrations")
+	}
+
+	ups := make(map[int]*Migration, len(paths))
+	downs := make(map[int]string)
+	upFiles := make(map[int]string, len(paths))
+	downFiles := make(map[int]string)
+	for _, p := range paths {
+		base := strings.TrimSuffix(path.Base(p), ".sql")
+		base, down := strings.CutSuffix(base, ".down")
+		prefix, name, ok := strings.Cut(base, "_")
+		if !ok {
+			return nil, errors.Newf("invalid migration file name %q", p)
+		}
+
+		version, err := strconv.Atoi(prefix)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid migration version in %q", p)
+		}
+		files := upFiles
+		if down {
+			files = downFiles
+		}
+		if other, ok := files[version]; ok {
+			return nil, errors.Newf("migrations %q and %q share version %d", other, p, version)
+		}
+		files[version] = p
+
+		data, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read migration %q", p)
+		}
+
+		if down {
+			downs[version] = string(data)
+			continue
+		}
+		ups[version] = &Migration{Version: version, Name: name, SQL: string(data)}
+	}
+
+	result := make([]Migration, 0, len(ups))
+	for version, sql := range downs {
+		m, ok := ups[version]
+		if !ok {
+			return nil, errors.Newf("down migration %q has no up migration", downFiles[version])
+		}
+		m.Down = sql
+	}
+	for _, m := range ups {
+		result = append(result, *m)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Version < result[j].Version
+	})
+
+	return result, nil
+}
+
+// MigrateFS applies the pending migrations in fsys
+func (db *DB) MigrateFS(fsys fs.FS) error {
+	pending, err := LoadMigrations(fsys)
+	if err != nil {
+		return err
+	}
+	return db.Migrate(pending)
+}
+
+// ConnectAndMigrate connects like Connect, then applies the embedded
+// migrations when cfg.MigrateOnStart is set. It is meant for development,
+// where no separate migration step runs before the services start.
+func ConnectAndMigrate(cfg *config.Database) (*DB, error) {
+	db, err := Connect(cfg)
+	if err != nil || !cfg.MigrateOnStart {
+		return db, err
+	}
+
+	if err := db.MigrateFS(migrations.FS); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// Migrate runs database migrations. It refuses to run while a migration is
+// dirty.
+func (db *DB) Migrate(migrations []Migration) error {
+	return db.withMigrationLock(context.Background(), func(conn *sql.Conn) error {
+		applied, err := appliedMigrations(conn)
+		if err != nil {
+			return err
+		}
+
+		// Run unapplied migrations
+		for _, migration := range migrations {
+			if applied[migration.Version] {
+				continue
+			}
+
+			migration := migration
+			err := runMarked(conn, migration.Version,
+				"INSERT INTO migrations (version, name, dirty) VALUES ($1, $2, TRUE)",
+				[]interface{}{migration.Version, migration.Name},
+				func(tx *sql.Tx) error {
+					if _, err := tx.Exec(migration.SQL); err != nil {
+						return errors.Wrapf(err, "failed to execute migration %d: %s", migration.Version, migration.Name)
+					}
+					_, err := tx.Exec("UPDATE migrations SET dirty = FALSE, applied_at = CURRENT_TIMESTAMP WHERE version = $1", migration.Version)
+					return errors.Wrapf(err, "failed to record migration %d", migration.Version)
+				},
+				"DELETE FROM migrations WHERE version = $1",
+			)
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("Applied migration %d: %s\n", migration.Version, migration.Name)
+		}
+
+		return nil
+	})
+}
+
+// MigrateDown rolls back the latest steps applied migrations, newest
+// first. Every one of them must be among migrations and have a Down.
+func (db *DB) MigrateDown(migrations []Migration, steps int) error {
+	known := make(map[int]Migration, len(migrations))
+	for _, m := range migrations {
+		known[m.Version] = m
+	}
+
+	return db.withMigrationLock(context.Background(), func(conn *sql.Conn) error {
+		applied, err := appliedMigrations(conn)
+		if err != nil {
+			return err
+		}
+		versions := make([]int, 0, len(applied))
+		for version := range applied {
+			versions = append(versions, version)
+		}
+		sort.Sort(sort.Reverse(sort.IntSlice(versions)))
+		if steps < len(versions) {
+			versions = versions[:steps]
+		}
+
+		// Check every step before rolling any back
+		for _, version := range versions {
+			m, ok := known[version]
+			if !ok {
+				return errors.Newf("applied migration %d is unknown to this build", version)
+			}
+			if m.Down == "" {
+				return errors.Newf("migration %d: %s has no down migration", m.Version, m.Name)
+			}
+		}
+
+		for _, version := range versions {
+			migration := known[version]
+			err := runMarked(conn, migration.Version,
+				"UPDATE migrations SET dirty = TRUE WHERE version = $1",
+				[]interface{}{migration.Version},
+				func(tx *sql.Tx) error {
+					if _, err := tx.Exec(migration.Down); err != nil {
+						return errors.Wrapf(err, "failed to roll back migration %d: %s", migration.Version, migration.Name)
+					}
+					_, err := tx.Exec("DELETE FROM migrations WHERE version = $1", migration.Version)
+					return errors.Wrapf(err, "failed to record rollback of migration %d", migration.Version)
+				},
+				"UPDATE migrations SET dirty = FALSE WHERE version = $1",
+			)
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("Rolled back migration %d: %s\n", migration.Version, migration.Name)
+		}
+
+		return nil
+	})
+}
+
+// MigrationStatuses returns the state of each of migrations, followed by
+// the applied migrations that are not among them
+func (db *DB) MigrationStatuses(ctx context.Context, migrations []Migration) ([]MigrationStatus, error) {
+	if err := db.ensureMigrationsTable(ctx); err != nil {
+		return nil, err
+	}
+
+	rows, err := db.QueryContext(ctx, "SELECT version, name, applied_at, dirty FROM migrations ORDER BY version")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query applied migrations")
+	}
+	defer rows.Close()
+
+	recorded := make(map[int]MigrationStatus)
+	var order []int
+	for rows.Next() {
+		var s MigrationStatus
+		var appliedAt sql.NullTime
+		if err := rows.Scan(&s.Version, &s.Name, &appliedAt, &s.Dirty); err != nil {
+			return nil, errors.Wrap(err, "failed to scan migration")
+		}
+		s.Applied = !s.Dirty
+		s.AppliedAt = appliedAt.Time
+		recorded[s.Version] = s
+		order = append(order, s.Version)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "error iterating migrations")
+	}
+
+	statuses := make([]MigrationStatus, 0, len(migrations))
+	for _, m := range migrations {
+		s, ok := recorded[m.Version]
+		if !ok {
+			s = MigrationStatus{Version: m.Version}
+		}
+		s.Name = m.Name
+		statuses = append(statuses, s)
+		delete(recorded, m.Version)
+	}
+	for _, version := range order {
+		if s, ok := recorded[version]; ok {
+			s.Unknown = true
+			statuses = append(statuses, s)
+		}
+	}
+	return statuses, nil
+}
+
+// ForceMigration clears the dirty state once the schema has been repaired
+// by hand. version is the migration the schema is now at: the dirty one
+// when it completed, or the one before it when it did not.
+func (db *DB) ForceMigration(ctx context.Context, version int) error {
+	return db.withMigrationLock(ctx, func(conn *sql.Conn) error {
+		dirty, err := dirtyVersion(conn)
+		if err != nil {
+			return err
+		}
+		if dirty == 0 {
+			return errors.New("no migration is dirty")
+		}
+
+		var previous int
+		if err := conn.QueryRowContext(ctx,
+			"SELECT COALESCE(MAX(version), 0) FROM migrations WHERE version < $1 AND NOT dirty", dirty,
+		).Scan(&previous); err != nil {
+			return errors.Wrap(err, "failed to query applied migrations")
+		}
+
+		switch version {
+		case dirty:
+			_, err = conn.ExecContext(ctx, "UPDATE migrations SET dirty = FALSE WHERE version = $1", dirty)
+		case previous:
+			_, err = conn.ExecContext(ctx, "DELETE FROM migrations WHERE version = $1", dirty)
+		default:
+			return errors.Newf("migration %d is dirty; force it to %d if it completed or %d if it did not", dirty, dirty, previous)
+		}
+		return errors.Wrapf(err, "failed to force migration %d", dirty)
+	})
+}
+
+// withMigrationLock runs fn on a connection that holds the migration lock,
+// after making sure the migrations table exists
+func (db *DB) withMigrationLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to get a connection for migrations")
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", migrationLockKey); err != nil {
+		return errors.Wrap(err, "failed to take the migration lock")
+	}
+	defer conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", migrationLockKey)
+
+	if err := db.ensureMigrationsTable(ctx); err != nil {
+		return err
+	}
+	return fn(conn)
+}
+
+// ensureMigrationsTable creates the migrations table, and adds the dirty
+// column to tables created before it existed
+func (db *DB) ensureMigrationsTable(ctx context.Context) error {
+	createTable := `
+		CREATE TABLE IF NOT EXISTS migrations (
+			version INTEGER PRIMARY KEY,
+			name TEXT NOT NULL,
+			applied_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		);
+		ALTER TABLE migrations ADD COLUMN IF NOT EXISTS dirty BOOLEAN NOT NULL DEFAULT FALSE;
+	`
+	if _, err := db.ExecContext(ctx, createTable); err != nil {
+		return errors.Wrap(err, "failed to create migrations table")
+	}
+	return nil
+}
+
+// appliedMigrations returns the versions of the applied migrations, or an
+// error when one of them is dirty
+func appliedMigrations(conn *sql.Conn) (map[int]bool, error) {
+	dirty, err := dirtyVersion(conn)
+	if err != nil {
+		return nil, err
+	}
+	if dirty != 0 {
+		return nil, errors.Newf("migration %d is dirty; repair the schema, then run migrate force", dirty)
+	}
+
+	rows, err := conn.QueryContext(context.Background(), "SELECT version FROM migrations")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query applied migrations")
+	}
+	defer rows.Close()
+
+	applied := make(map[int]bool)
+	for rows.Next() {
+		var version int
+		if err := rows.Scan(&version); err != nil {
+			return nil, errors.Wrap(err, "failed to scan migration version")
+		}
+		applied[version] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "error iterating migrations")
+	}
+	return applied, nil
+}
+
+// dirtyVersion returns the dirty migration, or 0 when none is
+func dirtyVersion(conn *sql.Conn) (int, error) {
+	var version int
+	err := conn.QueryRowContext(context.Background(),
+		"SELECT COALESCE(MAX(version), 0) FROM migrations WHERE dirty",
+	).Scan(&version)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to query dirty migrations")
+	}
+	return version, nil
+}
+
+// runMarked marks a migration dirty with mark, then runs fn in a
+// transaction. The mark is committed first, so that it survives the
+// process dying while fn runs. When fn fails its transaction rolls back,
+// leaving the schema untouched, and unmark removes the mark with version
+// as its argument.
+func runMarked(conn *sql.Conn, version int, mark string, args []interface{}, fn func(tx *sql.Tx) error, unmark string) error {
+	ctx := context.Background()
+	if _, err := conn.ExecContext(ctx, mark, args...); err != nil {
+		return errors.Wrapf(err, "failed to mark migration %d dirty", version)
+	}
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to start migration transaction")
+	}
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		conn.ExecContext(ctx, unmark, version)
+		return err
+	}
+
+	// A failed commit may still have been applied, so the mark stays
+	if err := tx.Commit(); err != nil {
+		return errors.Wrapf(err, "failed to commit migration %d", version)
+	}
+	return nil
+}
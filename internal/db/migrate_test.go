@@ -0,0 +1,130 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package db
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/kevindiu/monorepo-go-example/internal/db/migrations"
+)
+
+func TestLoadMigrations(t *testing.T) {
+	tests := []struct {
+		name     string
+		fsys     fstest.MapFS
+		versions []int
+		wantErr  bool
+	}{
+		{
+			name: "ordered by version",
+			fsys: fstest.MapFS{
+				"010_add_index.sql":   {Data: []byte("CREATE INDEX")},
+				"002_create_b.sql":    {Data: []byte("CREATE TABLE b")},
+				"001_create_a.sql":    {Data: []byte("CREATE TABLE a")},
+				"README.md":           {Data: []byte("not a migration")},
+				"nested/003_skip.sql": {Data: []byte("ignored")},
+			},
+			versions: []int{1, 2, 10},
+		},
+		{name: "empty", fsys: fstest.MapFS{}},
+		{name: "missing name", fsys: fstest.MapFS{"001.sql": {}}, wantErr: true},
+		{name: "invalid version", fsys: fstest.MapFS{"one_create_a.sql": {}}, wantErr: true},
+		{
+			name: "down migrations",
+			fsys: fstest.MapFS{
+				"001_create_a.sql":      {Data: []byte("CREATE TABLE a")},
+				"001_create_a.down.sql": {Data: []byte("DROP TABLE a")},
+				"002_create_b.sql":      {Data: []byte("CREATE TABLE b")},
+			},
+			versions: []int{1, 2},
+		},
+		{name: "down without up", fsys: fstest.MapFS{"003_create_c.down.sql": {}}, wantErr: true},
+		{
+			name:    "duplicate down",
+			fsys:    fstest.MapFS{"001_create_a.sql": {}, "001_create_a.down.sql": {}, "1_a.down.sql": {}},
+			wantErr: true,
+		},
+		{
+			name:    "duplicate version",
+			fsys:    fstest.MapFS{"001_create_a.sql": {}, "1_create_b.sql": {}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := LoadMigrations(tt.fsys)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadMigrations() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(got) != len(tt.versions) {
+				t.Fatalf("LoadMigrations() returned %d migrations, want %d", len(got), len(tt.versions))
+			}
+			for i, m := range got {
+				if m.Version != tt.versions[i] {
+					t.Errorf("migration %d version = %d, want %d", i, m.Version, tt.versions[i])
+				}
+			}
+		})
+	}
+}
+
+func TestLoadMigrationsPairsDowns(t *testing.T) {
+	got, err := LoadMigrations(fstest.MapFS{
+		"001_create_a.sql":      {Data: []byte("CREATE TABLE a")},
+		"001_create_a.down.sql": {Data: []byte("DROP TABLE a")},
+		"002_create_b.sql":      {Data: []byte("CREATE TABLE b")},
+	})
+	if err != nil {
+		t.Fatalf("LoadMigrations() error = %v", err)
+	}
+
+	want := []Migration{
+		{Version: 1, Name: "create_a", SQL: "CREATE TABLE a", Down: "DROP TABLE a"},
+		{Version: 2, Name: "create_b", SQL: "CREATE TABLE b"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("LoadMigrations() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("migration %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestEmbeddedMigrations(t *testing.T) {
+	got, err := LoadMigrations(migrations.FS)
+	if err != nil {
+		t.Fatalf("LoadMigrations() error = %v", err)
+	}
+	if len(got) == 0 {
+		t.Fatal("no migrations are embedded")
+	}
+	if latest := got[len(got)-1].Version; latest != SchemaVersion {
+		t.Fatalf("latest embedded migration = %d, want SchemaVersion %d", latest, SchemaVersion)
+	}
+	if got[0].Name != "create_users_table" || got[0].SQL == "" {
+		t.Errorf("first migration = %d %q", got[0].Version, got[0].Name)
+	}
+	for _, m := range got {
+		if m.Down == "" {
+			t.Errorf("migration %d: %s has no down migration", m.Version, m.Name)
+		}
+	}
+}
@@ -0,0 +1,5 @@
+-- Migration: Drop users table
+-- Version: 001
+
+DROP TABLE IF EXISTS users;
+DROP FUNCTION IF EXISTS update_updated_at_column();
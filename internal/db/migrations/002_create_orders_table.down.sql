@@ -0,0 +1,5 @@
+-- Migration: Drop orders and order_items tables
+-- Version: 002
+
+DROP TABLE IF EXISTS order_items;
+DROP TABLE IF EXISTS orders;
@@ -0,0 +1,4 @@
+-- Migration: Drop outbox_events table
+-- Version: 003
+
+DROP TABLE IF EXISTS outbox_events;
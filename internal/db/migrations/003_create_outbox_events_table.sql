@@ -0,0 +1,20 @@
+-- Migration: Create outbox events table
+-- Version: 003
+
+CREATE TABLE IF NOT EXISTS outbox_events (
+    id UUID PRIMARY KEY,
+    topic VARCHAR(255) NOT NULL,
+    event_type VARCHAR(255) NOT NULL,
+    event_key VARCHAR(255) NOT NULL DEFAULT '',
+    payload JSONB NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    next_attempt_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    published_at TIMESTAMP WITH TIME ZONE
+);
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_outbox_events_pending ON outbox_events(next_attempt_at)
+    WHERE published_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_outbox_events_created_at ON outbox_events(created_at);
@@ -0,0 +1,4 @@
+-- Migration: Drop sagas table
+-- Version: 004
+
+DROP TABLE IF EXISTS sagas;
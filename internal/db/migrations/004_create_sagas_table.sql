@@ -0,0 +1,27 @@
+-- Migration: Create sagas table
+-- Version: 004
+
+CREATE TABLE IF NOT EXISTS sagas (
+    id UUID PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    status VARCHAR(50) NOT NULL,
+    step INTEGER NOT NULL DEFAULT 0,
+    data JSONB NOT NULL DEFAULT '{}',
+    error TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_sagas_incomplete ON sagas(status)
+    WHERE status IN ('running', 'compensating');
+
+-- Create trigger for updated_at
+CREATE TRIGGER update_sagas_updated_at
+    BEFORE UPDATE ON sagas
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Add constraints for saga status
+ALTER TABLE sagas ADD CONSTRAINT check_saga_status
+CHECK (status IN ('running', 'completed', 'compensating', 'compensated'));
@@ -0,0 +1,4 @@
+-- Migration: Drop job_runs table
+-- Version: 005
+
+DROP TABLE IF EXISTS job_runs;
@@ -0,0 +1,19 @@
+-- Migration: Create job runs table
+-- Version: 005
+
+CREATE TABLE IF NOT EXISTS job_runs (
+    id UUID PRIMARY KEY,
+    job_name VARCHAR(255) NOT NULL,
+    instance VARCHAR(255) NOT NULL,
+    status VARCHAR(50) NOT NULL,
+    error TEXT,
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    finished_at TIMESTAMP WITH TIME ZONE
+);
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_job_runs_job_name_started_at ON job_runs(job_name, started_at DESC);
+
+-- Add constraints for run status
+ALTER TABLE job_runs ADD CONSTRAINT check_job_run_status
+CHECK (status IN ('running', 'succeeded', 'failed'));
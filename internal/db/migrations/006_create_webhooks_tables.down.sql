@@ -0,0 +1,5 @@
+-- Migration: Drop webhook tables
+-- Version: 006
+
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS webhook_endpoints;
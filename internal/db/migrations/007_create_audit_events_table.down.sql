@@ -0,0 +1,5 @@
+-- Migration: Drop audit_events table
+-- Version: 007
+
+DROP TABLE IF EXISTS audit_events;
+DROP FUNCTION IF EXISTS reject_audit_event_change();
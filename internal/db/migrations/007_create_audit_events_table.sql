@@ -0,0 +1,36 @@
+-- Migration: Create audit events table
+-- Version: 007
+
+CREATE TABLE IF NOT EXISTS audit_events (
+    seq BIGSERIAL PRIMARY KEY,
+    id UUID NOT NULL UNIQUE,
+    service VARCHAR(255) NOT NULL,
+    actor VARCHAR(255) NOT NULL,
+    action VARCHAR(255) NOT NULL,
+    resource_type VARCHAR(255) NOT NULL DEFAULT '',
+    resource_id VARCHAR(255) NOT NULL DEFAULT '',
+    outcome VARCHAR(50) NOT NULL,
+    metadata JSONB,
+    occurred_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    prev_hash CHAR(64) NOT NULL,
+    hash CHAR(64) NOT NULL,
+    recorded_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_audit_events_actor ON audit_events(actor, occurred_at DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_events_resource ON audit_events(resource_type, resource_id, occurred_at DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_events_occurred_at ON audit_events(occurred_at DESC);
+
+-- Audit events are append-only
+CREATE OR REPLACE FUNCTION reject_audit_event_change()
+RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_events is append-only';
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER audit_events_append_only
+    BEFORE UPDATE OR DELETE ON audit_events
+    FOR EACH ROW
+    EXECUTE FUNCTION reject_audit_event_change();
@@ -0,0 +1,5 @@
+-- Migration: Drop shipment tables
+-- Version: 008
+
+DROP TABLE IF EXISTS shipment_events;
+DROP TABLE IF EXISTS shipments;
@@ -0,0 +1,43 @@
+-- Migration: Create shipment tables
+-- Version: 008
+
+CREATE TABLE IF NOT EXISTS shipments (
+    id UUID PRIMARY KEY,
+    order_id UUID NOT NULL UNIQUE REFERENCES orders(id) ON DELETE CASCADE,
+    carrier VARCHAR(50) NOT NULL,
+    tracking_number VARCHAR(255) NOT NULL,
+    status VARCHAR(50) NOT NULL DEFAULT 'label_created',
+    delivered_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (carrier, tracking_number)
+);
+
+CREATE TABLE IF NOT EXISTS shipment_events (
+    id UUID PRIMARY KEY,
+    shipment_id UUID NOT NULL REFERENCES shipments(id) ON DELETE CASCADE,
+    status VARCHAR(50) NOT NULL,
+    location VARCHAR(255),
+    description TEXT,
+    occurred_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_shipments_status ON shipments(status);
+CREATE INDEX IF NOT EXISTS idx_shipments_created_at ON shipments(created_at);
+CREATE INDEX IF NOT EXISTS idx_shipment_events_shipment_id_occurred_at
+    ON shipment_events(shipment_id, occurred_at);
+
+-- Create trigger for updated_at
+CREATE TRIGGER update_shipments_updated_at
+    BEFORE UPDATE ON shipments
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Add constraints for shipment status
+ALTER TABLE shipments ADD CONSTRAINT check_shipment_status
+CHECK (status IN ('label_created', 'in_transit', 'out_for_delivery', 'delivered', 'exception'));
+
+ALTER TABLE shipment_events ADD CONSTRAINT check_shipment_event_status
+CHECK (status IN ('label_created', 'in_transit', 'out_for_delivery', 'delivered', 'exception'));
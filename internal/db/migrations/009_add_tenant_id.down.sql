@@ -0,0 +1,23 @@
+-- Migration: Remove tenant scoping from users and orders
+-- Version: 009
+
+-- Fails when two tenants share an email, which cannot be unscoped
+DROP INDEX IF EXISTS idx_orders_tenant_created_at;
+DROP INDEX IF EXISTS idx_orders_tenant_user_id;
+DROP INDEX IF EXISTS idx_users_tenant_created_at;
+
+ALTER TABLE orders DROP CONSTRAINT IF EXISTS orders_tenant_user_fkey;
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_tenant_id_id_key;
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_tenant_email_key;
+
+ALTER TABLE orders ADD CONSTRAINT orders_user_id_fkey
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+ALTER TABLE users ADD CONSTRAINT users_email_key UNIQUE (email);
+
+ALTER TABLE orders DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE users DROP COLUMN IF EXISTS tenant_id;
+
+CREATE INDEX IF NOT EXISTS idx_users_email ON users(email);
+CREATE INDEX IF NOT EXISTS idx_users_created_at ON users(created_at);
+CREATE INDEX IF NOT EXISTS idx_orders_user_id ON orders(user_id);
+CREATE INDEX IF NOT EXISTS idx_orders_created_at ON orders(created_at);
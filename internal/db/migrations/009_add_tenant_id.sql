@@ -0,0 +1,24 @@
+-- Migration: Scope users and orders by tenant
+-- Version: 009
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS tenant_id VARCHAR(63) NOT NULL DEFAULT 'default';
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS tenant_id VARCHAR(63) NOT NULL DEFAULT 'default';
+
+-- Emails are unique within a tenant rather than globally
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_email_key;
+ALTER TABLE users ADD CONSTRAINT users_tenant_email_key UNIQUE (tenant_id, email);
+
+-- Orders may only reference users of the same tenant
+ALTER TABLE users ADD CONSTRAINT users_tenant_id_id_key UNIQUE (tenant_id, id);
+ALTER TABLE orders DROP CONSTRAINT IF EXISTS orders_user_id_fkey;
+ALTER TABLE orders ADD CONSTRAINT orders_tenant_user_fkey
+    FOREIGN KEY (tenant_id, user_id) REFERENCES users(tenant_id, id) ON DELETE CASCADE;
+
+-- Replace the global indexes with tenant-scoped ones
+DROP INDEX IF EXISTS idx_users_email;
+DROP INDEX IF EXISTS idx_users_created_at;
+DROP INDEX IF EXISTS idx_orders_user_id;
+DROP INDEX IF EXISTS idx_orders_created_at;
+CREATE INDEX IF NOT EXISTS idx_users_tenant_created_at ON users(tenant_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_orders_tenant_user_id ON orders(tenant_id, user_id);
+CREATE INDEX IF NOT EXISTS idx_orders_tenant_created_at ON orders(tenant_id, created_at);
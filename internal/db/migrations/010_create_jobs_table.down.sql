@@ -0,0 +1,4 @@
+-- Migration: Drop jobs table
+-- Version: 010
+
+DROP TABLE IF EXISTS jobs;
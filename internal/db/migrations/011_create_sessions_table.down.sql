@@ -0,0 +1,4 @@
+-- Migration: Drop sessions table
+-- Version: 011
+
+DROP TABLE IF EXISTS sessions;
@@ -0,0 +1,5 @@
+-- Migration: Drop promotion tables
+-- Version: 012
+
+DROP TABLE IF EXISTS order_discounts;
+DROP TABLE IF EXISTS promotions;
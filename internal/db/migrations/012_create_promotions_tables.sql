@@ -0,0 +1,41 @@
+-- Migration: Create promotions and order discount tables
+-- Version: 012
+
+CREATE TABLE IF NOT EXISTS promotions (
+    id UUID PRIMARY KEY,
+    tenant_id VARCHAR(63) NOT NULL DEFAULT 'default',
+    code VARCHAR(64) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    discount_type VARCHAR(20) NOT NULL CHECK (discount_type IN ('percent', 'fixed')),
+    value DECIMAL(10,2) NOT NULL CHECK (value > 0),
+    min_total DECIMAL(10,2) NOT NULL DEFAULT 0.00 CHECK (min_total >= 0),
+    max_uses_per_user INTEGER NOT NULL DEFAULT 0 CHECK (max_uses_per_user >= 0),
+    expires_at TIMESTAMP WITH TIME ZONE,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (tenant_id, code),
+    UNIQUE (tenant_id, id)
+);
+
+-- Discounts applied to an order, one row per redeemed promotion
+CREATE TABLE IF NOT EXISTS order_discounts (
+    id UUID PRIMARY KEY,
+    tenant_id VARCHAR(63) NOT NULL DEFAULT 'default',
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    promotion_id UUID NOT NULL,
+    code VARCHAR(64) NOT NULL,
+    amount DECIMAL(10,2) NOT NULL CHECK (amount >= 0),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (tenant_id, promotion_id) REFERENCES promotions(tenant_id, id),
+    UNIQUE (order_id, promotion_id)
+);
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_order_discounts_promotion ON order_discounts(tenant_id, promotion_id);
+
+-- Create triggers for updated_at
+CREATE TRIGGER update_promotions_updated_at
+    BEFORE UPDATE ON promotions
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
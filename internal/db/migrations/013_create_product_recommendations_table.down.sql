@@ -0,0 +1,4 @@
+-- Migration: Drop product_recommendations table
+-- Version: 013
+
+DROP TABLE IF EXISTS product_recommendations;
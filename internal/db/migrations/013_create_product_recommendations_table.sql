@@ -0,0 +1,16 @@
+-- Migration: Create product recommendations table
+-- Version: 013
+
+-- Rebuilt by the recommendation aggregation job; one row per product pair
+CREATE TABLE IF NOT EXISTS product_recommendations (
+    tenant_id VARCHAR(63) NOT NULL DEFAULT 'default',
+    product_id VARCHAR(255) NOT NULL,
+    recommended_product_id VARCHAR(255) NOT NULL,
+    score INTEGER NOT NULL,
+    computed_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY (tenant_id, product_id, recommended_product_id)
+);
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_product_recommendations_score
+    ON product_recommendations(tenant_id, product_id, score DESC);
@@ -0,0 +1,16 @@
+-- Migration: Remove soft deletion and anonymization
+-- Version: 014
+
+-- Fails while anonymized orders or soft-deleted users with a reused email
+-- remain; purge them first
+DROP INDEX IF EXISTS idx_orders_created_at_active;
+ALTER TABLE orders DROP CONSTRAINT IF EXISTS orders_tenant_user_fkey;
+ALTER TABLE orders ADD CONSTRAINT orders_tenant_user_fkey
+    FOREIGN KEY (tenant_id, user_id) REFERENCES users(tenant_id, id) ON DELETE CASCADE;
+ALTER TABLE orders ALTER COLUMN user_id SET NOT NULL;
+ALTER TABLE orders DROP COLUMN IF EXISTS anonymized_at;
+
+DROP INDEX IF EXISTS idx_users_deleted_at;
+DROP INDEX IF EXISTS idx_users_tenant_email_active;
+ALTER TABLE users ADD CONSTRAINT users_tenant_email_key UNIQUE (tenant_id, email);
+ALTER TABLE users DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,22 @@
+-- Migration: Add soft deletion and anonymization for data retention
+-- Version: 014
+
+-- Deleted users are kept until the retention job purges them
+ALTER TABLE users ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
+
+-- Emails are only unique among users that have not been deleted
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_tenant_email_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_tenant_email_active
+    ON users(tenant_id, email) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_users_deleted_at
+    ON users(deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- Anonymized orders are detached from their user, so orders outlive the
+-- users the retention job purges
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS anonymized_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE orders ALTER COLUMN user_id DROP NOT NULL;
+ALTER TABLE orders DROP CONSTRAINT IF EXISTS orders_tenant_user_fkey;
+ALTER TABLE orders ADD CONSTRAINT orders_tenant_user_fkey
+    FOREIGN KEY (tenant_id, user_id) REFERENCES users(tenant_id, id);
+CREATE INDEX IF NOT EXISTS idx_orders_created_at_active
+    ON orders(created_at) WHERE anonymized_at IS NULL;
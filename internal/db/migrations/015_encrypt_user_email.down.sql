@@ -0,0 +1,10 @@
+-- Migration: Stop indexing encrypted user emails
+-- Version: 015
+
+-- Emails encrypted since then stay encrypted, and the rollback fails when
+-- one no longer fits in 255 characters
+DROP INDEX IF EXISTS idx_users_tenant_email_active;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_tenant_email_active
+    ON users(tenant_id, email) WHERE deleted_at IS NULL;
+ALTER TABLE users DROP COLUMN IF EXISTS email_index;
+ALTER TABLE users ALTER COLUMN email TYPE VARCHAR(255);
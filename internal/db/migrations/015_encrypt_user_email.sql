@@ -0,0 +1,14 @@
+-- Migration: Encrypt user emails at rest
+-- Version: 015
+
+-- Encrypted emails are longer than the plaintext they replace
+ALTER TABLE users ALTER COLUMN email TYPE TEXT;
+
+-- email_index holds the blind index encrypted emails are looked up by. It
+-- is NULL for emails still stored in plaintext.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_index VARCHAR(64);
+
+-- Emails stay unique among active users whether encrypted or not
+DROP INDEX IF EXISTS idx_users_tenant_email_active;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_tenant_email_active
+    ON users(tenant_id, COALESCE(email_index, email)) WHERE deleted_at IS NULL;
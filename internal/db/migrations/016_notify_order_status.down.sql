@@ -0,0 +1,5 @@
+-- Migration: Stop notifying listeners of order status changes
+-- Version: 016
+
+DROP TRIGGER IF EXISTS orders_notify_status ON orders;
+DROP FUNCTION IF EXISTS notify_order_status();
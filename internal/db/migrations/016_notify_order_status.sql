@@ -0,0 +1,23 @@
+-- Migration: Notify listeners of order status changes
+-- Version: 016
+
+-- Every committed status change, whatever made it, is announced on the
+-- order_status channel so that the order service can push it to the
+-- clients watching the order
+CREATE OR REPLACE FUNCTION notify_order_status() RETURNS TRIGGER AS $$
+BEGIN
+    PERFORM pg_notify('order_status', json_build_object(
+        'tenant_id', NEW.tenant_id,
+        'id', NEW.id,
+        'status', NEW.status
+    )::text);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS orders_notify_status ON orders;
+CREATE TRIGGER orders_notify_status
+    AFTER UPDATE OF status ON orders
+    FOR EACH ROW
+    WHEN (OLD.status IS DISTINCT FROM NEW.status)
+    EXECUTE FUNCTION notify_order_status();
@@ -0,0 +1,15 @@
+-- Migration: Store order amounts as decimals without a currency
+-- Version: 017
+
+ALTER TABLE order_discounts ALTER COLUMN amount_minor TYPE DECIMAL(10,2) USING amount_minor / 100.0;
+ALTER TABLE order_discounts RENAME COLUMN amount_minor TO amount;
+
+ALTER TABLE order_items ALTER COLUMN price_minor TYPE DECIMAL(10,2) USING price_minor / 100.0;
+ALTER TABLE order_items RENAME COLUMN price_minor TO price;
+
+ALTER TABLE orders ALTER COLUMN total_minor DROP DEFAULT;
+ALTER TABLE orders ALTER COLUMN total_minor TYPE DECIMAL(10,2) USING total_minor / 100.0;
+ALTER TABLE orders ALTER COLUMN total_minor SET DEFAULT 0.00;
+ALTER TABLE orders RENAME COLUMN total_minor TO total_amount;
+
+ALTER TABLE orders DROP COLUMN IF EXISTS currency;
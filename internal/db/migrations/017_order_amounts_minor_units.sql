@@ -0,0 +1,21 @@
+-- Migration: Store order amounts as integer minor units with a currency
+-- Version: 017
+
+-- Amounts were DECIMAL(10,2) in the order service's base currency. They
+-- become integer minor units (cents), and orders record their currency.
+-- Existing orders are taken to be in USD; if the base currency is another
+-- one, update orders.currency after migrating. Currencies without two
+-- decimal places need their amounts rescaled too.
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT 'USD';
+ALTER TABLE orders ALTER COLUMN currency DROP DEFAULT;
+
+ALTER TABLE orders RENAME COLUMN total_amount TO total_minor;
+ALTER TABLE orders ALTER COLUMN total_minor DROP DEFAULT;
+ALTER TABLE orders ALTER COLUMN total_minor TYPE BIGINT USING round(total_minor * 100)::BIGINT;
+ALTER TABLE orders ALTER COLUMN total_minor SET DEFAULT 0;
+
+ALTER TABLE order_items RENAME COLUMN price TO price_minor;
+ALTER TABLE order_items ALTER COLUMN price_minor TYPE BIGINT USING round(price_minor * 100)::BIGINT;
+
+ALTER TABLE order_discounts RENAME COLUMN amount TO amount_minor;
+ALTER TABLE order_discounts ALTER COLUMN amount_minor TYPE BIGINT USING round(amount_minor * 100)::BIGINT;
@@ -0,0 +1,4 @@
+-- Migration: Drop products table
+-- Version: 018
+
+DROP TABLE IF EXISTS products;
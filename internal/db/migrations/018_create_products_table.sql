@@ -0,0 +1,25 @@
+-- Migration: Create products table
+-- Version: 018
+
+CREATE TABLE IF NOT EXISTS products (
+    id UUID PRIMARY KEY,
+    tenant_id VARCHAR(63) NOT NULL DEFAULT 'default',
+    sku VARCHAR(64) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    price_minor BIGINT NOT NULL CHECK (price_minor > 0),
+    currency CHAR(3) NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (tenant_id, sku)
+);
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_products_tenant_created_at ON products(tenant_id, created_at DESC);
+
+-- Create trigger for updated_at
+CREATE TRIGGER update_products_updated_at
+    BEFORE UPDATE ON products
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
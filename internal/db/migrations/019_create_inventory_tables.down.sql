@@ -0,0 +1,5 @@
+-- Migration: Drop inventory and reservation tables
+-- Version: 019
+
+DROP TABLE IF EXISTS inventory_reservations;
+DROP TABLE IF EXISTS inventory;
@@ -0,0 +1,48 @@
+-- Migration: Create inventory and reservation tables
+-- Version: 019
+
+-- Stock of a product. Products without a row are not tracked and can
+-- always be ordered. available excludes the units reserved by orders.
+CREATE TABLE IF NOT EXISTS inventory (
+    tenant_id VARCHAR(63) NOT NULL DEFAULT 'default',
+    product_id VARCHAR(255) NOT NULL,
+    available INTEGER NOT NULL CHECK (available >= 0),
+    reserved INTEGER NOT NULL DEFAULT 0 CHECK (reserved >= 0),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (tenant_id, product_id)
+);
+
+-- Stock held for an order item, one row per item of a tracked product
+CREATE TABLE IF NOT EXISTS inventory_reservations (
+    id UUID PRIMARY KEY,
+    tenant_id VARCHAR(63) NOT NULL DEFAULT 'default',
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    order_item_id UUID NOT NULL UNIQUE,
+    product_id VARCHAR(255) NOT NULL,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    status VARCHAR(20) NOT NULL DEFAULT 'reserved',
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_inventory_reservations_order_id ON inventory_reservations(order_id);
+CREATE INDEX IF NOT EXISTS idx_inventory_reservations_expires_at
+    ON inventory_reservations(expires_at) WHERE status = 'reserved';
+
+-- Create triggers for updated_at
+CREATE TRIGGER update_inventory_updated_at
+    BEFORE UPDATE ON inventory
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TRIGGER update_inventory_reservations_updated_at
+    BEFORE UPDATE ON inventory_reservations
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Add constraints for reservation status
+ALTER TABLE inventory_reservations ADD CONSTRAINT check_inventory_reservation_status
+CHECK (status IN ('reserved', 'committed', 'released'));
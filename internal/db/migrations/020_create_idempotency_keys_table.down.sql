@@ -0,0 +1,4 @@
+-- Migration: Drop idempotency keys table
+-- Version: 020
+
+DROP TABLE IF EXISTS idempotency_keys;
@@ -0,0 +1,18 @@
+-- Migration: Create idempotency keys table
+-- Version: 020
+
+-- A create request made with an idempotency key. response is set once the
+-- request completed; retries with the same key get it back.
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    tenant_id VARCHAR(63) NOT NULL DEFAULT 'default',
+    scope VARCHAR(255) NOT NULL,
+    key VARCHAR(255) NOT NULL,
+    request_hash BYTEA NOT NULL,
+    response BYTEA,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    PRIMARY KEY (tenant_id, scope, key)
+);
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_created_at ON idempotency_keys(created_at);
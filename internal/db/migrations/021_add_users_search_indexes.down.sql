@@ -0,0 +1,6 @@
+-- Migration: Drop trigram indexes for user search
+-- Version: 021
+
+DROP INDEX IF EXISTS idx_users_email_trgm;
+DROP INDEX IF EXISTS idx_users_name_trgm;
+DROP EXTENSION IF EXISTS pg_trgm;
@@ -0,0 +1,12 @@
+-- Migration: Add trigram indexes for user search
+-- Version: 021
+
+-- pg_trgm ships with Postgres as a contrib extension
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- Trigram indexes serve the ILIKE '%term%' matches of SearchUsers. Emails
+-- encrypted at rest are ciphertext and only match by their blind index.
+CREATE INDEX IF NOT EXISTS idx_users_name_trgm
+    ON users USING gin (name gin_trgm_ops) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_users_email_trgm
+    ON users USING gin (email gin_trgm_ops) WHERE deleted_at IS NULL AND email_index IS NULL;
@@ -0,0 +1,4 @@
+-- Migration: Drop order status history table
+-- Version: 022
+
+DROP TABLE IF EXISTS order_status_history;
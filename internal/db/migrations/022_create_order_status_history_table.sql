@@ -0,0 +1,18 @@
+-- Migration: Create order status history table
+-- Version: 022
+
+-- One row per status an order took, written in the transaction that set
+-- it. from_status is NULL for the status the order was created with.
+-- Orders created before this migration have no creation row.
+CREATE TABLE IF NOT EXISTS order_status_history (
+    seq BIGSERIAL PRIMARY KEY,
+    tenant_id VARCHAR(63) NOT NULL DEFAULT 'default',
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    from_status VARCHAR(50),
+    to_status VARCHAR(50) NOT NULL,
+    actor VARCHAR(255) NOT NULL,
+    changed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_order_status_history_order_id ON order_status_history(order_id, seq);
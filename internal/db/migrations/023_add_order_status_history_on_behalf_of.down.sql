@@ -0,0 +1,4 @@
+-- Migration: Drop the principal status changes were made on behalf of
+-- Version: 023
+
+ALTER TABLE order_status_history DROP COLUMN IF EXISTS on_behalf_of;
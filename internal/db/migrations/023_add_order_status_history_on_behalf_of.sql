@@ -0,0 +1,7 @@
+-- Migration: Add the principal a status change was made on behalf of
+-- Version: 023
+
+-- actor is the authenticated subject that made the change. on_behalf_of is
+-- the principal a trusted service named when it made the change for
+-- someone else, and NULL otherwise.
+ALTER TABLE order_status_history ADD COLUMN IF NOT EXISTS on_behalf_of VARCHAR(255);
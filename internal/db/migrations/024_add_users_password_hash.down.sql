@@ -0,0 +1,4 @@
+-- Migration: Drop user password hashes
+-- Version: 024
+
+ALTER TABLE users DROP COLUMN IF EXISTS password_hash;
@@ -0,0 +1,6 @@
+-- Migration: Add user password hashes
+-- Version: 024
+
+-- password_hash is a bcrypt or argon2id hash in its self-describing
+-- string format, and NULL for users without a password.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS password_hash TEXT;
@@ -0,0 +1,4 @@
+-- Migration: Drop the sessions of issued refresh tokens
+-- Version: 025
+
+DROP TABLE IF EXISTS auth_sessions;
@@ -0,0 +1,22 @@
+-- Migration: Create the sessions of issued refresh tokens
+-- Version: 025
+
+-- Each login opens a session holding the hash of its current refresh
+-- token. Refreshing replaces the hash and keeps the previous one, so that a
+-- replayed refresh token is recognized and revokes the session.
+CREATE TABLE IF NOT EXISTS auth_sessions (
+    id UUID PRIMARY KEY,
+    tenant_id VARCHAR(63) NOT NULL DEFAULT 'default',
+    user_id UUID NOT NULL,
+    token_hash VARCHAR(64) NOT NULL,
+    previous_token_hash VARCHAR(64),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    refreshed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    FOREIGN KEY (tenant_id, user_id) REFERENCES users(tenant_id, id) ON DELETE CASCADE
+);
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_auth_sessions_user ON auth_sessions(tenant_id, user_id);
+CREATE INDEX IF NOT EXISTS idx_auth_sessions_expires_at ON auth_sessions(expires_at);
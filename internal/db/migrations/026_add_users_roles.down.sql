@@ -0,0 +1,4 @@
+-- Migration: Drop user roles
+-- Version: 026
+
+ALTER TABLE users DROP COLUMN IF EXISTS roles;
@@ -0,0 +1,6 @@
+-- Migration: Add user roles
+-- Version: 026
+
+-- roles are granted to the access tokens the user service issues for the
+-- user, and checked against the authorization policies of each method
+ALTER TABLE users ADD COLUMN IF NOT EXISTS roles TEXT[] NOT NULL DEFAULT '{customer}';
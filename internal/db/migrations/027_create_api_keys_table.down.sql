@@ -0,0 +1,4 @@
+-- Migration: Drop the API keys of service-to-service callers
+-- Version: 027
+
+DROP TABLE IF EXISTS api_keys;
@@ -0,0 +1,4 @@
+-- Migration: Drop browser session roles
+-- Version: 028
+
+ALTER TABLE sessions DROP COLUMN IF EXISTS roles;
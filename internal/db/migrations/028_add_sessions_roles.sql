@@ -0,0 +1,6 @@
+-- Migration: Add browser session roles
+-- Version: 028
+
+-- roles are the user's roles at login, granted to the tokens the gateway
+-- issues for the session
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS roles TEXT[] NOT NULL DEFAULT '{}';
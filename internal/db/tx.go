@@ -0,0 +1,158 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+)
+
+// Executor runs queries; *sql.DB, *sql.Tx, *Tx and *DB satisfy it
+type Executor interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+}
+
+type txKey struct{}
+
+type hooksKey struct{}
+
+// commitHooks are the functions AfterCommit deferred to the commit of a
+// TxManager's transaction
+type commitHooks struct {
+	mu  sync.Mutex
+	fns []func() error
+}
+
+// run calls every hook and returns the first error
+func (h *commitHooks) run() error {
+	h.mu.Lock()
+	fns := h.fns
+	h.fns = nil
+	h.mu.Unlock()
+
+	var first error
+	for _, fn := range fns {
+		if err := fn(); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// Tx is a transaction begun by DB.BeginTx
+type Tx struct {
+	*sql.Tx
+	// joined is set when the transaction belongs to a TxManager
+	joined bool
+}
+
+// Commit commits the transaction, unless it was joined
+func (tx *Tx) Commit() error {
+	if tx.joined {
+		return nil
+	}
+	return tx.Tx.Commit()
+}
+
+// Rollback aborts the transaction, unless it was joined. A joined
+// transaction is rolled back once the TxManager's function fails.
+func (tx *Tx) Rollback() error {
+	if tx.joined {
+		return nil
+	}
+	return tx.Tx.Rollback()
+}
+
+// Executor returns the transaction a TxManager stashed in ctx, or the
+// connection pool outside of one
+func (db *DB) Executor(ctx context.Context) Executor {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return tx
+	}
+	return db.DB
+}
+
+// InTransaction reports whether ctx carries a TxManager's transaction,
+// whose writes other connections see only once it commits
+func InTransaction(ctx context.Context) bool {
+	_, ok := ctx.Value(txKey{}).(*sql.Tx)
+	return ok
+}
+
+// AfterCommit calls fn once the TxManager's transaction in ctx commits, or
+// at once outside of one. fn is never called when the transaction rolls
+// back.
+func AfterCommit(ctx context.Context, fn func() error) error {
+	hooks, ok := ctx.Value(hooksKey{}).(*commitHooks)
+	if !ok || !InTransaction(ctx) {
+		return fn()
+	}
+	hooks.mu.Lock()
+	hooks.fns = append(hooks.fns, fn)
+	hooks.mu.Unlock()
+	return nil
+}
+
+// TxManager runs functions in a transaction shared by every repository
+// they call
+type TxManager struct {
+	db *DB
+}
+
+// NewTxManager creates a TxManager
+func NewTxManager(db *DB) *TxManager {
+	return &TxManager{db: db}
+}
+
+// WithinTransaction calls fn with a context carrying a transaction, which
+// is committed when fn returns nil and rolled back otherwise. Repository
+// calls made with that context join the transaction, and so does a nested
+// WithinTransaction. Functions passed to AfterCommit run after the commit,
+// and the first of their errors is returned.
+func (m *TxManager) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := m.db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to begin transaction")
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	hooks := &commitHooks{}
+	txCtx := context.WithValue(context.WithValue(ctx, txKey{}, tx), hooksKey{}, hooks)
+	if err := fn(txCtx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(err, "failed to commit transaction")
+	}
+	return hooks.run()
+}
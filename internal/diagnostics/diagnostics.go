@@ -0,0 +1,127 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package diagnostics dumps the state of a running service to its log on
+// SIGUSR2, for debugging a hung process without attaching a debugger.
+package diagnostics
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"runtime/pprof"
+	"sort"
+
+	"github.com/kevindiu/monorepo-go-example/internal/config"
+	"github.com/kevindiu/monorepo-go-example/internal/log"
+	"google.golang.org/grpc"
+)
+
+// Section is a named part of a dump
+type Section struct {
+	Name  string
+	Write func(w io.Writer) error
+}
+
+// Dumper writes its sections to the log
+type Dumper struct {
+	logger   *log.Logger
+	sections []Section
+}
+
+// New creates a dumper of sections
+func New(logger *log.Logger, sections ...Section) *Dumper {
+	if logger == nil {
+		logger = log.NewDefault()
+	}
+	return &Dumper{logger: logger, sections: sections}
+}
+
+// Dump logs every section as its own entry. A failing section is logged
+// with its error and does not stop the others.
+func (d *Dumper) Dump() {
+	d.logger.Info("Diagnostics dump started", log.Int("sections", len(d.sections)))
+	for _, section := range d.sections {
+		var buf bytes.Buffer
+		if err := section.Write(&buf); err != nil {
+			d.logger.Error("Diagnostics section failed", log.String("section", section.Name), log.Error(err))
+			continue
+		}
+		d.logger.Info("Diagnostics", log.String("section", section.Name), log.String("dump", buf.String()))
+	}
+	d.logger.Info("Diagnostics dump finished")
+}
+
+// Run dumps on every SIGUSR2 until ctx is cancelled. On platforms without
+// SIGUSR2 it only waits for ctx.
+func (d *Dumper) Run(ctx context.Context) error {
+	signals := make(chan os.Signal, 1)
+	if len(dumpSignals) > 0 {
+		signal.Notify(signals, dumpSignals...)
+		defer signal.Stop(signals)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-signals:
+			d.Dump()
+		}
+	}
+}
+
+// Goroutines dumps the stack of every goroutine
+func Goroutines() Section {
+	return Section{Name: "goroutines", Write: func(w io.Writer) error {
+		return pprof.Lookup("goroutine").WriteTo(w, 2)
+	}}
+}
+
+// Config dumps the configuration returned by current, with credentials
+// redacted
+func Config(current func() *config.Config) Section {
+	return Section{Name: "config", Write: func(w io.Writer) error {
+		out, err := current().RedactedYAML()
+		if err != nil {
+			return err
+		}
+		_, err = io.WriteString(w, out)
+		return err
+	}}
+}
+
+// Conns dumps the state of backend connections by name
+func Conns(conns map[string]*grpc.ClientConn) Section {
+	return Section{Name: "connections", Write: func(w io.Writer) error {
+		names := make([]string, 0, len(conns))
+		for name := range conns {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			conn := conns[name]
+			if _, err := fmt.Fprintf(w, "%s %s %s\n", name, conn.Target(), conn.GetState()); err != nil {
+				return err
+			}
+		}
+		return nil
+	}}
+}
@@ -0,0 +1,106 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package diagnostics
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kevindiu/monorepo-go-example/internal/config"
+	"github.com/kevindiu/monorepo-go-example/internal/log"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestDump(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	failing := Section{Name: "broken", Write: func(io.Writer) error { return errors.New("boom") }}
+	d := New(&log.Logger{Logger: zap.New(core)},
+		Goroutines(),
+		failing,
+		Config(func() *config.Config { return cfg }),
+	)
+	d.Dump()
+
+	dumps := make(map[string]string)
+	for _, entry := range logs.FilterMessage("Diagnostics").All() {
+		fields := entry.ContextMap()
+		dumps[fields["section"].(string)] = fields["dump"].(string)
+	}
+	if !strings.Contains(dumps["goroutines"], "TestDump") {
+		t.Error("goroutine dump does not include the test goroutine")
+	}
+	if !strings.Contains(dumps["config"], "grpc_port: 9090") || strings.Contains(dumps["config"], "password: postgres") {
+		t.Errorf("config dump is not the redacted config:\n%s", dumps["config"])
+	}
+	if logs.FilterMessage("Diagnostics section failed").Len() != 1 {
+		t.Error("failing section was not reported")
+	}
+	if logs.FilterMessage("Diagnostics dump finished").Len() != 1 {
+		t.Error("a failing section stopped the dump")
+	}
+}
+
+func TestErrors(t *testing.T) {
+	e := NewErrors()
+
+	interceptor := e.UnaryServerInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/test/Method"}
+	for _, err := range []error{nil, status.Error(codes.NotFound, "missing"), status.Error(codes.NotFound, "missing")} {
+		err := err
+		interceptor(context.Background(), nil, info, func(context.Context, interface{}) (interface{}, error) {
+			return nil, err
+		})
+	}
+
+	handler := e.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	for _, path := range []string{"/ok", "/fail"} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	var first strings.Builder
+	if err := e.Section().Write(&first); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	want := "grpc NotFound total=2 since_last_dump=2\nhttp 502 total=1 since_last_dump=1\n"
+	if first.String() != want {
+		t.Errorf("first dump = %q, want %q", first.String(), want)
+	}
+
+	var second strings.Builder
+	e.Section().Write(&second)
+	if !strings.Contains(second.String(), "grpc NotFound total=2 since_last_dump=0") {
+		t.Errorf("second dump = %q, want the recent counters reset", second.String())
+	}
+}
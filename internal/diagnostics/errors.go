@@ -0,0 +1,114 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package diagnostics
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// Errors counts failed requests by kind, in total and since the previous
+// dump
+type Errors struct {
+	mu     sync.Mutex
+	total  map[string]int64
+	recent map[string]int64
+}
+
+// NewErrors creates an empty error counter
+func NewErrors() *Errors {
+	return &Errors{total: make(map[string]int64), recent: make(map[string]int64)}
+}
+
+// Record counts one error of kind
+func (e *Errors) Record(kind string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.total[kind]++
+	e.recent[kind]++
+}
+
+// Section dumps the counters and starts a new recent window
+func (e *Errors) Section() Section {
+	return Section{Name: "errors", Write: func(w io.Writer) error {
+		e.mu.Lock()
+		defer e.mu.Unlock()
+
+		kinds := make([]string, 0, len(e.total))
+		for kind := range e.total {
+			kinds = append(kinds, kind)
+		}
+		sort.Strings(kinds)
+
+		for _, kind := range kinds {
+			if _, err := fmt.Fprintf(w, "%s total=%d since_last_dump=%d\n", kind, e.total[kind], e.recent[kind]); err != nil {
+				return err
+			}
+		}
+		e.recent = make(map[string]int64)
+		return nil
+	}}
+}
+
+// UnaryServerInterceptor counts failed gRPC calls by status code
+func (e *Errors) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		resp, err := handler(ctx, req)
+		if err != nil {
+			e.Record("grpc " + status.Code(err).String())
+		}
+		return resp, err
+	}
+}
+
+// Middleware counts HTTP responses with a 5xx status
+func (e *Errors) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec := &statusRecorder{ResponseWriter: w, code: http.StatusOK}
+		next.ServeHTTP(rec, r)
+		if rec.code >= http.StatusInternalServerError {
+			e.Record(fmt.Sprintf("http %d", rec.code))
+		}
+	})
+}
+
+// statusRecorder captures the status code written to a response
+type statusRecorder struct {
+	http.ResponseWriter
+	code        int
+	wroteHeader bool
+}
+
+func (r *statusRecorder) WriteHeader(code int) {
+	if !r.wroteHeader {
+		r.code = code
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(code)
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
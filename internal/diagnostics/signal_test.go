@@ -0,0 +1,62 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build !windows
+
+package diagnostics
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/log"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestRunDumpsOnSignal(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	d := New(&log.Logger{Logger: zap.New(core)})
+
+	// Keep SIGUSR2 from killing the test binary before Run subscribes
+	guard := make(chan os.Signal, 1)
+	signal.Notify(guard, syscall.SIGUSR2)
+	defer signal.Stop(guard)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		d.Run(ctx)
+		close(done)
+	}()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for logs.FilterMessage("Diagnostics dump finished").Len() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("no dump after SIGUSR2")
+		}
+		// Run may not have subscribed yet, so signal until it dumps
+		syscall.Kill(syscall.Getpid(), syscall.SIGUSR2)
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	cancel()
+	<-done
+}
@@ -0,0 +1,209 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package doctor implements the --check mode of the binaries. It validates
+// the configuration and the service's dependencies once, prints a report
+// and exits non-zero on failure, for init containers and pre-deploy checks.
+package doctor
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/config"
+	"github.com/kevindiu/monorepo-go-example/internal/db"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/internal/events"
+	"github.com/kevindiu/monorepo-go-example/internal/health"
+)
+
+// Flag registers the --check flag on the default flag set
+func Flag() *bool {
+	return flag.Bool("check", false, "validate configuration and dependencies, print a report and exit")
+}
+
+// Run runs the checks in order, writes a report to w and returns the exit
+// code: 1 when a critical check failed, 0 otherwise
+func Run(ctx context.Context, w io.Writer, service string, checkers []health.Checker) int {
+	fmt.Fprintf(w, "%s self-check\n", service)
+
+	failed := 0
+	for _, c := range checkers {
+		report := health.Run(ctx, []health.Checker{c})
+		result := report.Checks[c.Name]
+
+		status := "ok"
+		switch {
+		case result.Status == health.CheckPassing:
+		case c.Critical:
+			status = "FAIL"
+			failed++
+		default:
+			status = "WARN"
+		}
+
+		line := fmt.Sprintf("  %-5s %s (%dms)", status, c.Name, result.DurationMS)
+		if result.Error != "" {
+			line += ": " + result.Error
+		}
+		fmt.Fprintln(w, line)
+	}
+
+	if failed > 0 {
+		fmt.Fprintf(w, "%d critical check(s) failed\n", failed)
+		return 1
+	}
+	fmt.Fprintln(w, "all critical checks passed")
+	return 0
+}
+
+// Config checks that cfg is valid
+func Config(cfg *config.Config) health.Checker {
+	return health.Checker{
+		Name:     "config",
+		Critical: true,
+		Check: func(ctx context.Context) error {
+			return cfg.Validate()
+		},
+	}
+}
+
+// Database checks that the database accepts connections and that its
+// schema has been migrated to the version this build expects
+func Database(cfg *config.Database) []health.Checker {
+	var (
+		mu       sync.Mutex
+		database *db.DB
+	)
+	connect := func(ctx context.Context) (*db.DB, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if database != nil {
+			return database, nil
+		}
+
+		// Connect does not take a context, so abandon it at the deadline
+		type result struct {
+			db  *db.DB
+			err error
+		}
+		done := make(chan result, 1)
+		go func() {
+			d, err := db.Connect(cfg)
+			done <- result{db: d, err: err}
+		}()
+		select {
+		case r := <-done:
+			database = r.db
+			return r.db, r.err
+		case <-ctx.Done():
+			return nil, errors.Wrap(ctx.Err(), "timed out connecting to database")
+		}
+	}
+
+	return []health.Checker{
+		{
+			Name:     "database",
+			Critical: true,
+			Timeout:  10 * time.Second,
+			Check: func(ctx context.Context) error {
+				_, err := connect(ctx)
+				return err
+			},
+		},
+		{
+			Name:     "migrations",
+			Critical: true,
+			Timeout:  10 * time.Second,
+			Check: func(ctx context.Context) error {
+				d, err := connect(ctx)
+				if err != nil {
+					return errors.Wrap(err, "database unavailable")
+				}
+				defer d.Close()
+				return health.Migrations(d, db.SchemaVersion)(ctx)
+			},
+		},
+	}
+}
+
+// Endpoint checks that the host of a downstream endpoint resolves. addr is
+// a host:port pair, a URL, or uds:///path for a unix socket, which must
+// exist.
+func Endpoint(name, addr string, critical bool) health.Checker {
+	return health.Checker{
+		Name:     name,
+		Critical: critical,
+		Check: func(ctx context.Context) error {
+			if path, ok := strings.CutPrefix(addr, "uds://"); ok {
+				if _, err := os.Stat(path); err != nil {
+					return errors.Wrapf(err, "socket %s is not available", path)
+				}
+				return nil
+			}
+
+			host, err := hostOf(addr)
+			if err != nil {
+				return err
+			}
+			if _, err := net.DefaultResolver.LookupHost(ctx, host); err != nil {
+				return errors.Wrapf(err, "failed to resolve %s", host)
+			}
+			return nil
+		},
+	}
+}
+
+// Events checks that the brokers of the configured event bus resolve
+func Events(cfg *config.Events) []health.Checker {
+	switch cfg.Driver {
+	case events.DriverKafka:
+		checkers := make([]health.Checker, 0, len(cfg.Brokers))
+		for _, broker := range cfg.Brokers {
+			checkers = append(checkers, Endpoint("kafka "+broker, broker, true))
+		}
+		return checkers
+	case events.DriverNATS:
+		return []health.Checker{Endpoint("nats", cfg.NATSURL, true)}
+	default:
+		return nil
+	}
+}
+
+// hostOf returns the host of a host:port pair or a URL
+func hostOf(addr string) (string, error) {
+	if strings.Contains(addr, "://") {
+		u, err := url.Parse(addr)
+		if err != nil {
+			return "", errors.Wrapf(err, "invalid endpoint %q", addr)
+		}
+		return u.Hostname(), nil
+	}
+
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return "", errors.Wrapf(err, "invalid endpoint %q", addr)
+	}
+	return host, nil
+}
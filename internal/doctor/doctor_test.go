@@ -0,0 +1,121 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package doctor
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kevindiu/monorepo-go-example/internal/config"
+	"github.com/kevindiu/monorepo-go-example/internal/health"
+)
+
+func TestRun(t *testing.T) {
+	pass := func(ctx context.Context) error { return nil }
+	fail := func(ctx context.Context) error { return errors.New("connection refused") }
+
+	tests := []struct {
+		name     string
+		checkers []health.Checker
+		wantCode int
+		wantLine string
+	}{
+		{
+			name:     "all pass",
+			checkers: []health.Checker{{Name: "config", Check: pass, Critical: true}},
+			wantCode: 0,
+			wantLine: "ok    config",
+		},
+		{
+			name:     "non-critical fails",
+			checkers: []health.Checker{{Name: "order-service", Check: fail}},
+			wantCode: 0,
+			wantLine: "WARN  order-service",
+		},
+		{
+			name:     "critical fails",
+			checkers: []health.Checker{{Name: "config", Check: pass, Critical: true}, {Name: "database", Check: fail, Critical: true}},
+			wantCode: 1,
+			wantLine: "FAIL  database",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			if code := Run(context.Background(), &out, "test-service", tt.checkers); code != tt.wantCode {
+				t.Errorf("Run() = %d, want %d", code, tt.wantCode)
+			}
+			if !strings.Contains(out.String(), tt.wantLine) {
+				t.Errorf("report %q does not contain %q", out.String(), tt.wantLine)
+			}
+		})
+	}
+}
+
+func TestEndpoint(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "user-service.sock")
+	if err := os.WriteFile(socket, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		addr    string
+		wantErr bool
+	}{
+		{name: "host and port", addr: "localhost:9091"},
+		{name: "url", addr: "nats://localhost:4222"},
+		{name: "missing port", addr: "localhost", wantErr: true},
+		{name: "unresolvable", addr: "no-such-host.invalid:9091", wantErr: true},
+		{name: "unix socket", addr: "uds://" + socket},
+		{name: "missing unix socket", addr: "uds://" + socket + ".missing", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Endpoint("backend", tt.addr, true).Check(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Endpoint(%q) error = %v, wantErr %v", tt.addr, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEvents(t *testing.T) {
+	tests := []struct {
+		cfg  config.Events
+		want int
+	}{
+		{cfg: config.Events{Driver: "memory"}, want: 0},
+		{cfg: config.Events{Driver: "kafka", Brokers: []string{"a:9092", "b:9092"}}, want: 2},
+		{cfg: config.Events{Driver: "nats", NATSURL: "nats://localhost:4222"}, want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.cfg.Driver, func(t *testing.T) {
+			if got := len(Events(&tt.cfg)); got != tt.want {
+				t.Errorf("Events() returned %d checks, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/certs"
+	"github.com/kevindiu/monorepo-go-example/internal/config"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// HealthcheckCommand is the subcommand that probes a running service
+const HealthcheckCommand = "healthcheck"
+
+// HealthcheckTimeout bounds a probe
+const HealthcheckTimeout = 3 * time.Second
+
+// Probe checks that the service running on this host is healthy
+type Probe func(ctx context.Context) error
+
+// Healthcheck runs probe, writes the outcome to w and returns the exit
+// code: 0 when the service is healthy, 1 otherwise. It backs container
+// HEALTHCHECKs and exec probes, so images need no curl or grpcurl.
+func Healthcheck(w io.Writer, probe Probe) int {
+	ctx, cancel := context.WithTimeout(context.Background(), HealthcheckTimeout)
+	defer cancel()
+
+	if err := probe(ctx); err != nil {
+		fmt.Fprintf(w, "unhealthy: %v\n", err)
+		return 1
+	}
+	fmt.Fprintln(w, "healthy")
+	return 0
+}
+
+// GRPCProbe checks the gRPC health service of the local server, over its
+// unix socket when it has one. With TLS enabled it connects with the
+// server's certificate and verifies the server as localhost, unless a
+// server name is configured.
+func GRPCProbe(cfg *config.Server, tlsCfg *config.TLS) Probe {
+	return func(ctx context.Context) error {
+		target := localAddr(cfg.Host, cfg.GRPCPort)
+		if cfg.GRPCSocket != "" {
+			target = "unix:" + cfg.GRPCSocket
+		}
+
+		creds := insecure.NewCredentials()
+		store, err := certs.FromConfig(tlsCfg, nil)
+		if err != nil {
+			return err
+		}
+		if store != nil {
+			tlsConfig := store.ClientConfig()
+			if tlsConfig.ServerName == "" {
+				tlsConfig.ServerName = "localhost"
+			}
+			creds = credentials.NewTLS(tlsConfig)
+		}
+
+		conn, err := grpc.DialContext(ctx, target, grpc.WithTransportCredentials(creds))
+		if err != nil {
+			return errors.Wrap(err, "failed to connect")
+		}
+		defer conn.Close()
+
+		resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+		if err != nil {
+			return errors.Wrap(err, "health check failed")
+		}
+		if resp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+			return errors.Newf("status %s", resp.GetStatus())
+		}
+		return nil
+	}
+}
+
+// HTTPProbe checks the /ready endpoint of the local HTTP server
+func HTTPProbe(cfg *config.Server) Probe {
+	return func(ctx context.Context) error {
+		url := "http://" + localAddr(cfg.Host, cfg.Port) + "/ready"
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return errors.Wrap(err, "readiness probe failed")
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return errors.Newf("%s responded %d", url, resp.StatusCode)
+		}
+		return nil
+	}
+}
+
+// localAddr returns the address to reach a server listening on host:port
+// from the same host
+func localAddr(host string, port int) string {
+	if host == "" || host == "0.0.0.0" || host == "::" {
+		host = "127.0.0.1"
+	}
+	return net.JoinHostPort(host, strconv.Itoa(port))
+}
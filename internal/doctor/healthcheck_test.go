@@ -0,0 +1,98 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package doctor
+
+import (
+	"bytes"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/kevindiu/monorepo-go-example/internal/config"
+	"google.golang.org/grpc"
+	healthgrpc "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func serverConfig(t *testing.T, addr string) *config.Server {
+	t.Helper()
+	_, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, _ := strconv.Atoi(port)
+	return &config.Server{Host: "0.0.0.0", Port: p, GRPCPort: p}
+}
+
+func TestHTTPProbe(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		wantCode int
+	}{
+		{name: "ready", status: http.StatusOK, wantCode: 0},
+		{name: "not ready", status: http.StatusServiceUnavailable, wantCode: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/ready" {
+					t.Errorf("probed %s, want /ready", r.URL.Path)
+				}
+				w.WriteHeader(tt.status)
+			}))
+			defer srv.Close()
+
+			var out bytes.Buffer
+			if code := Healthcheck(&out, HTTPProbe(serverConfig(t, srv.Listener.Addr().String()))); code != tt.wantCode {
+				t.Errorf("Healthcheck() = %d, want %d\n%s", code, tt.wantCode, out.String())
+			}
+		})
+	}
+}
+
+func TestGRPCProbe(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	hs := healthgrpc.NewServer()
+	s := grpc.NewServer()
+	healthpb.RegisterHealthServer(s, hs)
+	go s.Serve(lis)
+	defer s.Stop()
+
+	cfg := serverConfig(t, lis.Addr().String())
+	var out bytes.Buffer
+
+	if code := Healthcheck(&out, GRPCProbe(cfg, nil)); code != 0 {
+		t.Errorf("Healthcheck() = %d for a serving server\n%s", code, out.String())
+	}
+
+	hs.SetServingStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+	if code := Healthcheck(&out, GRPCProbe(cfg, nil)); code != 1 {
+		t.Errorf("Healthcheck() = %d for a not serving server, want 1", code)
+	}
+
+	s.Stop()
+	if code := Healthcheck(&out, GRPCProbe(cfg, nil)); code != 1 {
+		t.Errorf("Healthcheck() = %d for a stopped server, want 1", code)
+	}
+}
@@ -19,6 +19,7 @@ package errors
 import (
 	"fmt"
 	"runtime"
+	"sync/atomic"
 )
 
 // Error represents a custom error with additional context
@@ -27,6 +28,16 @@ type Error struct {
 	Message string `json:"message"`
 	Cause   error  `json:"cause,omitempty"`
 	Stack   string `json:"stack,omitempty"`
+	// Violations names the request fields that make a CodeInvalidInput
+	// error, for clients to point at
+	Violations []FieldViolation `json:"violations,omitempty"`
+}
+
+// FieldViolation describes why one field of a request is invalid. Field is
+// a path such as "items[0].quantity".
+type FieldViolation struct {
+	Field       string `json:"field"`
+	Description string `json:"description"`
 }
 
 // Error implements the error interface
@@ -82,21 +93,52 @@ func Wrapf(err error, format string, args ...interface{}) error {
 	}
 }
 
+// InvalidField creates a CodeInvalidInput error for a request whose field
+// is invalid, with message as both the error message and the violation
+func InvalidField(field, message string) error {
+	return &Error{
+		Code:       CodeInvalidInput,
+		Message:    message,
+		Stack:      getStack(),
+		Violations: []FieldViolation{{Field: field, Description: message}},
+	}
+}
+
 // WithCode adds an error code to an error
 func WithCode(err error, code string) error {
 	if err == nil {
 		return nil
 	}
 
-	if e, ok := err.(*Error); ok {
+	e, ok := err.(*Error)
+	if ok {
 		e.Code = code
-		return e
+	} else {
+		e = &Error{
+			Code:    code,
+			Message: err.Error(),
+			Stack:   getStack(),
+		}
 	}
 
-	return &Error{
-		Code:    code,
-		Message: err.Error(),
-		Stack:   getStack(),
+	if code == CodeInternal {
+		report(e)
+	}
+	return e
+}
+
+// reporter is called with every error marked CodeInternal
+var reporter atomic.Value
+
+// SetReporter registers fn to be called whenever WithCode marks an error as
+// CodeInternal; nil stops reporting
+func SetReporter(fn func(err *Error)) {
+	reporter.Store(fn)
+}
+
+func report(err *Error) {
+	if fn, _ := reporter.Load().(func(err *Error)); fn != nil {
+		fn(err)
 	}
 }
 
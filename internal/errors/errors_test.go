@@ -80,6 +80,22 @@ func TestWithCode(t *testing.T) {
 	}
 }
 
+func TestWithCodeReportsInternal(t *testing.T) {
+	var reported []*Error
+	SetReporter(func(err *Error) { reported = append(reported, err) })
+	defer SetReporter(nil)
+
+	WithCode(New("not found"), CodeNotFound)
+	WithCode(errors.New("connection reset"), CodeInternal)
+
+	if len(reported) != 1 {
+		t.Fatalf("reported %d errors, want 1", len(reported))
+	}
+	if reported[0].Code != CodeInternal || reported[0].Message != "connection reset" {
+		t.Errorf("reported %+v, want the internal error", reported[0])
+	}
+}
+
 func TestGetCode(t *testing.T) {
 	tests := []struct {
 		name string
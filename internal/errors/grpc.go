@@ -0,0 +1,112 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package errors
+
+import (
+	"context"
+	stderrors "errors"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/runtime/protoiface"
+)
+
+// internalMessage replaces the message of errors that are not meant for
+// clients
+const internalMessage = "internal server error"
+
+// Domain is the google.rpc.ErrorInfo domain of the error codes
+const Domain = "monorepo-go-example"
+
+// grpcCodes maps error codes to the gRPC codes clients receive
+var grpcCodes = map[string]codes.Code{
+	CodeNotFound:     codes.NotFound,
+	CodeInvalidInput: codes.InvalidArgument,
+	CodeUnauthorized: codes.Unauthenticated,
+	CodeForbidden:    codes.PermissionDenied,
+	CodeConflict:     codes.AlreadyExists,
+	CodeUnavailable:  codes.Unavailable,
+}
+
+// ToGRPCStatus converts err to the status returned to gRPC clients. An
+// error with a code, directly or wrapped, gets the matching gRPC code and
+// its own message, without its causes or stack trace, with a
+// google.rpc.ErrorInfo detail whose reason is the code and a
+// google.rpc.BadRequest detail listing its field violations. gRPC statuses
+// and context errors keep their codes. Anything else, including
+// CodeInternal, becomes Internal with a generic message, so that internal
+// details such as SQL errors never reach clients.
+func ToGRPCStatus(err error) *status.Status {
+	if err == nil {
+		return status.New(codes.OK, "")
+	}
+
+	if e := coded(err); e != nil {
+		code, ok := grpcCodes[e.Code]
+		if !ok {
+			return internalStatus()
+		}
+		st := status.New(code, e.Message)
+		info := &errdetails.ErrorInfo{Reason: e.Code, Domain: Domain}
+		if len(e.Violations) == 0 {
+			return withDetails(st, info)
+		}
+		badRequest := &errdetails.BadRequest{}
+		for _, v := range e.Violations {
+			badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       v.Field,
+				Description: v.Description,
+			})
+		}
+		return withDetails(st, info, badRequest)
+	}
+	if st, ok := status.FromError(err); ok {
+		return st
+	}
+	switch {
+	case stderrors.Is(err, context.DeadlineExceeded):
+		return status.New(codes.DeadlineExceeded, context.DeadlineExceeded.Error())
+	case stderrors.Is(err, context.Canceled):
+		return status.New(codes.Canceled, context.Canceled.Error())
+	}
+	return internalStatus()
+}
+
+// internalStatus returns the generic Internal status that hides an error
+func internalStatus() *status.Status {
+	return withDetails(status.New(codes.Internal, internalMessage), &errdetails.ErrorInfo{Reason: CodeInternal, Domain: Domain})
+}
+
+// withDetails attaches details to st, leaving st without them should they
+// fail to encode
+func withDetails(st *status.Status, details ...protoiface.MessageV1) *status.Status {
+	if detailed, err := st.WithDetails(details...); err == nil {
+		return detailed
+	}
+	return st
+}
+
+// coded returns the outermost error in err's chain that has a code
+func coded(err error) *Error {
+	for ; err != nil; err = stderrors.Unwrap(err) {
+		if e, ok := err.(*Error); ok && e.Code != "" {
+			return e
+		}
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package errors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestToGRPCStatus(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		wantCode    codes.Code
+		wantMessage string
+	}{
+		{name: "nil", wantCode: codes.OK},
+		{name: "not found", err: WithCode(New("user not found"), CodeNotFound), wantCode: codes.NotFound, wantMessage: "user not found"},
+		{name: "invalid input", err: WithCode(New("email is required"), CodeInvalidInput), wantCode: codes.InvalidArgument, wantMessage: "email is required"},
+		{name: "conflict", err: WithCode(New("email is already taken"), CodeConflict), wantCode: codes.AlreadyExists, wantMessage: "email is already taken"},
+		{name: "unauthorized", err: ErrUnauthorized, wantCode: codes.Unauthenticated, wantMessage: "unauthorized"},
+		{name: "forbidden", err: ErrForbidden, wantCode: codes.PermissionDenied, wantMessage: "forbidden"},
+		{name: "unavailable", err: ErrUnavailable, wantCode: codes.Unavailable, wantMessage: "service unavailable"},
+		{
+			name:        "code hides its cause",
+			err:         WithCode(Wrap(errors.New("pq: duplicate key"), "user exists"), CodeConflict),
+			wantCode:    codes.AlreadyExists,
+			wantMessage: "user exists",
+		},
+		{
+			name:        "wrapped code",
+			err:         Wrap(fmt.Errorf("lookup: %w", WithCode(New("order not found"), CodeNotFound)), "failed to cancel order"),
+			wantCode:    codes.NotFound,
+			wantMessage: "order not found",
+		},
+		{name: "internal", err: WithCode(New("disk full"), CodeInternal), wantCode: codes.Internal, wantMessage: "internal server error"},
+		{name: "uncoded", err: Wrap(errors.New("pq: connection refused"), "failed to get user"), wantCode: codes.Internal, wantMessage: "internal server error"},
+		{name: "status", err: status.Error(codes.FailedPrecondition, "not pending"), wantCode: codes.FailedPrecondition, wantMessage: "not pending"},
+		{name: "deadline", err: Wrap(context.DeadlineExceeded, "failed to list orders"), wantCode: codes.DeadlineExceeded, wantMessage: "context deadline exceeded"},
+		{name: "canceled", err: context.Canceled, wantCode: codes.Canceled, wantMessage: "context canceled"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := ToGRPCStatus(tt.err)
+			if st.Code() != tt.wantCode || st.Message() != tt.wantMessage {
+				t.Errorf("ToGRPCStatus() = %v %q, want %v %q", st.Code(), st.Message(), tt.wantCode, tt.wantMessage)
+			}
+			if strings.Contains(st.Message(), "goroutine") {
+				t.Errorf("ToGRPCStatus() leaked a stack trace: %q", st.Message())
+			}
+		})
+	}
+}
+
+func TestToGRPCStatusDetails(t *testing.T) {
+	tests := []struct {
+		name           string
+		err            error
+		wantReason     string
+		wantViolations []string
+	}{
+		{name: "coded", err: WithCode(New("order not found"), CodeNotFound), wantReason: CodeNotFound},
+		{
+			name:           "invalid field",
+			err:            Wrap(InvalidField("items[0].quantity", "quantity must be positive"), "failed to create order"),
+			wantReason:     CodeInvalidInput,
+			wantViolations: []string{"items[0].quantity: quantity must be positive"},
+		},
+		{name: "internal", err: errors.New("pq: connection refused"), wantReason: CodeInternal},
+		{name: "status", err: status.Error(codes.FailedPrecondition, "not pending")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var reason string
+			var violations []string
+			for _, d := range ToGRPCStatus(tt.err).Details() {
+				switch d := d.(type) {
+				case *errdetails.ErrorInfo:
+					if d.GetDomain() != Domain {
+						t.Errorf("ErrorInfo domain = %q, want %q", d.GetDomain(), Domain)
+					}
+					reason = d.GetReason()
+				case *errdetails.BadRequest:
+					for _, v := range d.GetFieldViolations() {
+						violations = append(violations, v.GetField()+": "+v.GetDescription())
+					}
+				}
+			}
+			if reason != tt.wantReason {
+				t.Errorf("ErrorInfo reason = %q, want %q", reason, tt.wantReason)
+			}
+			if strings.Join(violations, ",") != strings.Join(tt.wantViolations, ",") {
+				t.Errorf("field violations = %v, want %v", violations, tt.wantViolations)
+			}
+		})
+	}
+}
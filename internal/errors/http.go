@@ -0,0 +1,76 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package errors
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/genproto/googleapis/rpc/code"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// HTTPBody is the JSON body of every error response sent over HTTP, for
+// backend errors and those raised in front of the backends alike
+type HTTPBody struct {
+	// Code is the reason of the error's google.rpc.ErrorInfo, such as
+	// INVALID_INPUT, or else the name of its gRPC code, such as
+	// FAILED_PRECONDITION
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	// Details are the status details in their protobuf JSON form, each
+	// with an "@type"
+	Details []json.RawMessage `json:"details"`
+}
+
+// NewHTTPBody renders st as an error body
+func NewHTTPBody(st *status.Status) HTTPBody {
+	body := HTTPBody{
+		Code:    code.Code_name[int32(st.Code())],
+		Message: st.Message(),
+		Details: []json.RawMessage{},
+	}
+	for _, d := range st.Proto().GetDetails() {
+		if b, err := protojson.Marshal(d); err == nil {
+			body.Details = append(body.Details, b)
+		}
+	}
+	for _, d := range st.Details() {
+		if info, ok := d.(*errdetails.ErrorInfo); ok && info.GetReason() != "" {
+			body.Code = info.GetReason()
+		}
+	}
+	return body
+}
+
+// WriteHTTP writes err, converted with ToGRPCStatus, as an error response
+// with the HTTP status of its gRPC code
+func WriteHTTP(w http.ResponseWriter, err error) error {
+	st := ToGRPCStatus(err)
+	return WriteHTTPStatus(w, runtime.HTTPStatusFromCode(st.Code()), st)
+}
+
+// WriteHTTPStatus writes st as an error response with httpStatus, for the
+// errors whose HTTP status differs from that of their gRPC code
+func WriteHTTPStatus(w http.ResponseWriter, httpStatus int, st *status.Status) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(httpStatus)
+	return json.NewEncoder(w).Encode(NewHTTPBody(st))
+}
@@ -0,0 +1,84 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package errors
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestWriteHTTP(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		wantStatus  int
+		wantCode    string
+		wantMessage string
+		wantDetails int
+	}{
+		{name: "coded", err: WithCode(New("unknown tenant"), CodeForbidden),
+			wantStatus: http.StatusForbidden, wantCode: CodeForbidden, wantMessage: "unknown tenant", wantDetails: 1},
+		{name: "invalid field", err: InvalidField("email", "email is required"),
+			wantStatus: http.StatusBadRequest, wantCode: CodeInvalidInput, wantMessage: "email is required", wantDetails: 2},
+		{name: "grpc status", err: status.Error(codes.ResourceExhausted, "rate limit exceeded"),
+			wantStatus: http.StatusTooManyRequests, wantCode: "RESOURCE_EXHAUSTED", wantMessage: "rate limit exceeded"},
+		{name: "internal", err: errors.New("pq: connection refused"),
+			wantStatus: http.StatusInternalServerError, wantCode: CodeInternal, wantMessage: "internal server error", wantDetails: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			if err := WriteHTTP(w, tt.err); err != nil {
+				t.Fatalf("WriteHTTP() error = %v", err)
+			}
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			if got := w.Header().Get("Content-Type"); got != "application/json" {
+				t.Errorf("Content-Type = %q, want application/json", got)
+			}
+			var body HTTPBody
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("decode body: %v", err)
+			}
+			if body.Code != tt.wantCode || body.Message != tt.wantMessage || len(body.Details) != tt.wantDetails {
+				t.Errorf("body = %+v, want code %s, message %q and %d details", body, tt.wantCode, tt.wantMessage, tt.wantDetails)
+			}
+		})
+	}
+}
+
+func TestWriteHTTPStatus(t *testing.T) {
+	w := httptest.NewRecorder()
+	if err := WriteHTTPStatus(w, http.StatusMethodNotAllowed, status.New(codes.Unimplemented, "method not allowed")); err != nil {
+		t.Fatalf("WriteHTTPStatus() error = %v", err)
+	}
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusMethodNotAllowed)
+	}
+	if got := w.Body.String(); got != `{"code":"UNIMPLEMENTED","message":"method not allowed","details":[]}`+"\n" {
+		t.Errorf("body = %s", got)
+	}
+}
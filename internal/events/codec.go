@@ -0,0 +1,52 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package events
+
+import (
+	"encoding/json"
+	"hash/fnv"
+
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+)
+
+// Marshal encodes an event envelope for transport
+func Marshal(e *Event) ([]byte, error) {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal event")
+	}
+	return data, nil
+}
+
+// Unmarshal decodes an event envelope received from a transport
+func Unmarshal(data []byte) (*Event, error) {
+	var e Event
+	if err := json.Unmarshal(data, &e); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal event")
+	}
+	return &e, nil
+}
+
+// partition maps a key onto one of n partitions
+func partition(key string, n int) int {
+	if n <= 1 {
+		return 0
+	}
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return int(h.Sum32() % uint32(n))
+}
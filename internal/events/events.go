@@ -0,0 +1,112 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kevindiu/monorepo-go-example/internal/config"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/internal/log"
+)
+
+// Supported drivers
+const (
+	DriverMemory = "memory"
+	DriverKafka  = "kafka"
+	DriverNATS   = "nats"
+)
+
+// Event is a domain event exchanged between services
+type Event struct {
+	ID         string            `json:"id"`
+	Type       string            `json:"type"`
+	Key        string            `json:"key,omitempty"`
+	Payload    json.RawMessage   `json:"payload"`
+	Headers    map[string]string `json:"headers,omitempty"`
+	OccurredAt time.Time         `json:"occurred_at"`
+}
+
+// NewEvent creates an event with a JSON-encoded payload. The key is used to
+// partition events so that all events sharing a key are delivered in order.
+func NewEvent(eventType, key string, payload interface{}) (*Event, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal event payload")
+	}
+
+	return &Event{
+		ID:         uuid.New().String(),
+		Type:       eventType,
+		Key:        key,
+		Payload:    data,
+		OccurredAt: time.Now().UTC(),
+	}, nil
+}
+
+// Decode unmarshals the event payload into v
+func (e *Event) Decode(v interface{}) error {
+	if err := json.Unmarshal(e.Payload, v); err != nil {
+		return errors.Wrap(err, "failed to unmarshal event payload")
+	}
+	return nil
+}
+
+// Handler processes a received event. Returning an error signals that the
+// event was not handled and should be redelivered where the driver supports it.
+type Handler func(ctx context.Context, event *Event) error
+
+// Publisher publishes events to a topic
+type Publisher interface {
+	Publish(ctx context.Context, topic string, events ...*Event) error
+	Close() error
+}
+
+// Subscriber consumes events from a topic. Subscribers sharing a group
+// split the topic's events between them; each group receives every event.
+// Subscribe blocks until ctx is cancelled or the subscription fails.
+type Subscriber interface {
+	Subscribe(ctx context.Context, topic, group string, handler Handler) error
+	Close() error
+}
+
+// Bus is both a Publisher and a Subscriber
+type Bus interface {
+	Publisher
+	Subscriber
+}
+
+// New creates a bus for the configured driver
+func New(cfg *config.Events, logger *log.Logger) (Bus, error) {
+	if logger == nil {
+		logger = log.NewDefault()
+	}
+
+	switch cfg.Driver {
+	case DriverMemory, "":
+		return NewMemoryBus(), nil
+	case DriverKafka:
+		return NewKafkaBus(cfg.Brokers, cfg.ClientID, logger), nil
+	case DriverNATS:
+		return NewNATSBus(cfg.NATSURL, cfg.ClientID, logger)
+	default:
+		return nil, errors.WithCode(errors.Newf("unsupported events driver %q", cfg.Driver), errors.CodeInvalidInput)
+	}
+}
@@ -0,0 +1,175 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package events
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/config"
+)
+
+func TestNewEventRoundTrip(t *testing.T) {
+	type payload struct {
+		OrderID string `json:"order_id"`
+	}
+
+	event, err := NewEvent("order.created", "order-1", payload{OrderID: "order-1"})
+	if err != nil {
+		t.Fatalf("NewEvent() error = %v", err)
+	}
+	if event.ID == "" {
+		t.Error("NewEvent() did not assign an ID")
+	}
+
+	data, err := Marshal(event)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	decoded, err := Unmarshal(data)
+	if err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	var got payload
+	if err := decoded.Decode(&got); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if got.OrderID != "order-1" {
+		t.Errorf("Decode() OrderID = %v, want order-1", got.OrderID)
+	}
+	if decoded.Type != event.Type || decoded.Key != event.Key {
+		t.Errorf("Unmarshal() = %+v, want %+v", decoded, event)
+	}
+}
+
+func TestPartitionIsStable(t *testing.T) {
+	for _, key := range []string{"", "a", "order-1", "user-42"} {
+		if partition(key, 4) != partition(key, 4) {
+			t.Errorf("partition(%q) is not deterministic", key)
+		}
+		if p := partition(key, 4); p < 0 || p >= 4 {
+			t.Errorf("partition(%q, 4) = %d, out of range", key, p)
+		}
+	}
+	if partition("anything", 1) != 0 {
+		t.Error("partition() with one partition should always be 0")
+	}
+}
+
+func TestMemoryBusGroups(t *testing.T) {
+	bus := NewMemoryBus()
+	defer bus.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var mu sync.Mutex
+	received := map[string]int{}
+	var wg sync.WaitGroup
+	wg.Add(4)
+
+	subscribe := func(name, group string) {
+		go bus.Subscribe(ctx, "orders", group, func(ctx context.Context, e *Event) error {
+			mu.Lock()
+			received[name]++
+			mu.Unlock()
+			wg.Done()
+			return nil
+		})
+	}
+	subscribe("a1", "a")
+	subscribe("a2", "a")
+	subscribe("b1", "b")
+
+	waitForSubscribers(t, bus, "orders", 3)
+
+	e1, _ := NewEvent("order.created", "order-1", nil)
+	e2, _ := NewEvent("order.created", "order-1", nil)
+	if err := bus.Publish(ctx, "orders", e1, e2); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+
+	waitTimeout(t, &wg)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if received["b1"] != 2 {
+		t.Errorf("group b received %d events, want 2", received["b1"])
+	}
+	if received["a1"]+received["a2"] != 2 {
+		t.Errorf("group a received %d events, want 2", received["a1"]+received["a2"])
+	}
+	if received["a1"] != 0 && received["a2"] != 0 {
+		t.Error("events with the same key were split across group members")
+	}
+}
+
+func TestMemoryBusClosed(t *testing.T) {
+	bus := NewMemoryBus()
+	bus.Close()
+
+	if err := bus.Publish(context.Background(), "orders"); err == nil {
+		t.Error("Publish() on closed bus should fail")
+	}
+}
+
+func TestNewUnsupportedDriver(t *testing.T) {
+	if _, err := New(&config.Events{Driver: "carrier-pigeon"}, nil); err == nil {
+		t.Error("New() with unknown driver should fail")
+	}
+
+	bus, err := New(&config.Events{Driver: DriverMemory}, nil)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	bus.Close()
+}
+
+func waitForSubscribers(t *testing.T, bus *MemoryBus, topic string, n int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		bus.mu.RLock()
+		count := 0
+		for _, subs := range bus.topics[topic] {
+			count += len(subs)
+		}
+		bus.mu.RUnlock()
+		if count == n {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for %d subscribers", n)
+}
+
+func waitTimeout(t *testing.T, wg *sync.WaitGroup) {
+	t.Helper()
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for events")
+	}
+}
@@ -0,0 +1,148 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package events
+
+import (
+	"context"
+	"sync"
+
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/internal/log"
+	"github.com/segmentio/kafka-go"
+)
+
+// headerEventType carries the event type so consumers can filter without decoding
+const headerEventType = "event-type"
+
+// KafkaBus publishes and consumes events through Kafka. Event keys are used as
+// message keys, so all events for a key land on the same partition.
+type KafkaBus struct {
+	brokers  []string
+	clientID string
+	logger   *log.Logger
+
+	mu      sync.Mutex
+	writers map[string]*kafka.Writer
+}
+
+// NewKafkaBus creates a Kafka-backed bus
+func NewKafkaBus(brokers []string, clientID string, logger *log.Logger) *KafkaBus {
+	return &KafkaBus{
+		brokers:  brokers,
+		clientID: clientID,
+		logger:   logger.Named("events.kafka"),
+		writers:  make(map[string]*kafka.Writer),
+	}
+}
+
+func (b *KafkaBus) writer(topic string) *kafka.Writer {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	w, ok := b.writers[topic]
+	if !ok {
+		w = &kafka.Writer{
+			Addr:                   kafka.TCP(b.brokers...),
+			Topic:                  topic,
+			Balancer:               &kafka.Hash{},
+			RequiredAcks:           kafka.RequireAll,
+			AllowAutoTopicCreation: true,
+		}
+		b.writers[topic] = w
+	}
+	return w
+}
+
+// Publish writes events to topic, waiting for all in-sync replicas to acknowledge
+func (b *KafkaBus) Publish(ctx context.Context, topic string, events ...*Event) error {
+	msgs := make([]kafka.Message, 0, len(events))
+	for _, event := range events {
+		data, err := Marshal(event)
+		if err != nil {
+			return err
+		}
+		msgs = append(msgs, kafka.Message{
+			Key:   []byte(event.Key),
+			Value: data,
+			Headers: []kafka.Header{
+				{Key: headerEventType, Value: []byte(event.Type)},
+			},
+		})
+	}
+
+	if err := b.writer(topic).WriteMessages(ctx, msgs...); err != nil {
+		return errors.WithCode(errors.Wrapf(err, "failed to publish to kafka topic %s", topic), errors.CodeUnavailable)
+	}
+	return nil
+}
+
+// Subscribe consumes topic as part of the consumer group. Offsets are only
+// committed after the handler succeeds, giving at-least-once delivery; a
+// handler error ends the subscription so the caller can retry from the last
+// committed offset.
+func (b *KafkaBus) Subscribe(ctx context.Context, topic, group string, handler Handler) error {
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers: b.brokers,
+		GroupID: group,
+		Topic:   topic,
+		Dialer:  &kafka.Dialer{ClientID: b.clientID},
+	})
+	defer reader.Close()
+
+	for {
+		msg, err := reader.FetchMessage(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return errors.WithCode(errors.Wrapf(err, "failed to fetch from kafka topic %s", topic), errors.CodeUnavailable)
+		}
+
+		event, err := Unmarshal(msg.Value)
+		if err != nil {
+			// Poison messages are skipped so they don't block the partition
+			b.logger.Error("Dropping undecodable event",
+				log.String("topic", topic),
+				log.Int64("offset", msg.Offset),
+				log.Error(err),
+			)
+		} else if err := handler(ctx, event); err != nil {
+			// Stop without committing so the group resumes from this message
+			return errors.Wrapf(err, "failed to handle event %s from kafka topic %s", event.ID, topic)
+		}
+
+		if err := reader.CommitMessages(ctx, msg); err != nil && ctx.Err() == nil {
+			b.logger.Error("Failed to commit offset", log.String("topic", topic), log.Error(err))
+		}
+	}
+}
+
+// Close flushes pending writes. Consumers leave their groups when their
+// Subscribe context is cancelled.
+func (b *KafkaBus) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var firstErr error
+	for _, w := range b.writers {
+		if err := w.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return errors.Wrap(firstErr, "failed to close kafka bus")
+}
@@ -0,0 +1,145 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package events
+
+import (
+	"context"
+	"sync"
+
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+)
+
+// memoryBufferSize is the number of events buffered per subscriber
+const memoryBufferSize = 64
+
+// MemoryBus is an in-process bus intended for tests and single-binary setups
+type MemoryBus struct {
+	mu     sync.RWMutex
+	topics map[string]map[string][]*memorySubscription
+	closed bool
+}
+
+type memorySubscription struct {
+	ch   chan *Event
+	done chan struct{}
+}
+
+// NewMemoryBus creates an in-memory bus
+func NewMemoryBus() *MemoryBus {
+	return &MemoryBus{
+		topics: make(map[string]map[string][]*memorySubscription),
+	}
+}
+
+// Publish delivers each event to one subscriber per group, chosen by the
+// event key so that events with the same key go to the same subscriber
+func (b *MemoryBus) Publish(ctx context.Context, topic string, events ...*Event) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if b.closed {
+		return errors.WithCode(errors.New("event bus is closed"), errors.CodeUnavailable)
+	}
+
+	for _, event := range events {
+		for _, subs := range b.topics[topic] {
+			if len(subs) == 0 {
+				continue
+			}
+			sub := subs[partition(event.Key, len(subs))]
+			select {
+			case sub.ch <- event:
+			case <-sub.done:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+
+	return nil
+}
+
+// Subscribe registers handler for topic within group and blocks until ctx is cancelled
+func (b *MemoryBus) Subscribe(ctx context.Context, topic, group string, handler Handler) error {
+	sub := &memorySubscription{
+		ch:   make(chan *Event, memoryBufferSize),
+		done: make(chan struct{}),
+	}
+
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return errors.WithCode(errors.New("event bus is closed"), errors.CodeUnavailable)
+	}
+	if b.topics[topic] == nil {
+		b.topics[topic] = make(map[string][]*memorySubscription)
+	}
+	b.topics[topic][group] = append(b.topics[topic][group], sub)
+	b.mu.Unlock()
+
+	defer b.unsubscribe(topic, group, sub)
+	// Release publishers blocked on this subscription before unsubscribing
+	defer close(sub.done)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-sub.ch:
+			if !ok {
+				return nil
+			}
+			// There is no broker to redeliver to, so failed events are dropped
+			_ = handler(ctx, event)
+		}
+	}
+}
+
+func (b *MemoryBus) unsubscribe(topic, group string, sub *memorySubscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	subs := b.topics[topic][group]
+	for i, s := range subs {
+		if s == sub {
+			b.topics[topic][group] = append(subs[:i], subs[i+1:]...)
+			return
+		}
+	}
+}
+
+// Close stops all subscriptions
+func (b *MemoryBus) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return nil
+	}
+	b.closed = true
+
+	for _, groups := range b.topics {
+		for _, subs := range groups {
+			for _, sub := range subs {
+				close(sub.ch)
+			}
+		}
+	}
+	b.topics = make(map[string]map[string][]*memorySubscription)
+
+	return nil
+}
@@ -0,0 +1,108 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package events
+
+import (
+	"context"
+
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/internal/log"
+	"github.com/nats-io/nats.go"
+)
+
+// headerEventKey carries the partitioning key, which NATS has no native notion of
+const headerEventKey = "event-key"
+
+// NATSBus publishes and consumes events through NATS subjects. Consumer
+// groups map onto NATS queue groups.
+type NATSBus struct {
+	conn   *nats.Conn
+	logger *log.Logger
+}
+
+// NewNATSBus connects to the NATS server at url
+func NewNATSBus(url, clientID string, logger *log.Logger) (*NATSBus, error) {
+	conn, err := nats.Connect(url, nats.Name(clientID))
+	if err != nil {
+		return nil, errors.WithCode(errors.Wrap(err, "failed to connect to nats"), errors.CodeUnavailable)
+	}
+
+	return &NATSBus{
+		conn:   conn,
+		logger: logger.Named("events.nats"),
+	}, nil
+}
+
+// Publish sends events to the subject named by topic
+func (b *NATSBus) Publish(ctx context.Context, topic string, events ...*Event) error {
+	for _, event := range events {
+		data, err := Marshal(event)
+		if err != nil {
+			return err
+		}
+
+		msg := nats.NewMsg(topic)
+		msg.Data = data
+		msg.Header.Set(headerEventType, event.Type)
+		msg.Header.Set(headerEventKey, event.Key)
+
+		if err := b.conn.PublishMsg(msg); err != nil {
+			return errors.WithCode(errors.Wrapf(err, "failed to publish to nats subject %s", topic), errors.CodeUnavailable)
+		}
+	}
+
+	if err := b.conn.FlushWithContext(ctx); err != nil {
+		return errors.WithCode(errors.Wrap(err, "failed to flush nats connection"), errors.CodeUnavailable)
+	}
+	return nil
+}
+
+// Subscribe joins the queue group for topic and blocks until ctx is cancelled
+func (b *NATSBus) Subscribe(ctx context.Context, topic, group string, handler Handler) error {
+	sub, err := b.conn.QueueSubscribe(topic, group, func(msg *nats.Msg) {
+		event, err := Unmarshal(msg.Data)
+		if err != nil {
+			b.logger.Error("Dropping undecodable event", log.String("subject", topic), log.Error(err))
+			return
+		}
+		if err := handler(ctx, event); err != nil {
+			b.logger.Error("Event handler failed",
+				log.String("subject", topic),
+				log.String("event_id", event.ID),
+				log.Error(err),
+			)
+		}
+	})
+	if err != nil {
+		return errors.WithCode(errors.Wrapf(err, "failed to subscribe to nats subject %s", topic), errors.CodeUnavailable)
+	}
+
+	<-ctx.Done()
+
+	if err := sub.Drain(); err != nil && err != nats.ErrConnectionClosed {
+		return errors.Wrap(err, "failed to drain nats subscription")
+	}
+	return nil
+}
+
+// Close drains all subscriptions and closes the connection
+func (b *NATSBus) Close() error {
+	if err := b.conn.Drain(); err != nil && err != nats.ErrConnectionClosed {
+		return errors.Wrap(err, "failed to close nats connection")
+	}
+	return nil
+}
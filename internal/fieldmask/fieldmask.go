@@ -0,0 +1,99 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package fieldmask applies read masks to responses so clients receive only
+// the fields they ask for. An empty mask selects every field.
+package fieldmask
+
+import (
+	"strings"
+
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// Validate returns an invalid input error if mask names a field m does not
+// have
+func Validate(m proto.Message, mask *fieldmaskpb.FieldMask) error {
+	if mask == nil || mask.IsValid(m) {
+		return nil
+	}
+	return errors.WithCode(errors.Newf("invalid read_mask %q", strings.Join(mask.GetPaths(), ",")), errors.CodeInvalidInput)
+}
+
+// Includes reports whether mask selects path or a field below it
+func Includes(mask *fieldmaskpb.FieldMask, path string) bool {
+	if len(mask.GetPaths()) == 0 {
+		return true
+	}
+	for _, p := range mask.GetPaths() {
+		if p == path || strings.HasPrefix(p, path+".") || strings.HasPrefix(path, p+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// Trim clears the fields of m that mask does not select
+func Trim(m proto.Message, mask *fieldmaskpb.FieldMask) {
+	if len(mask.GetPaths()) == 0 {
+		return
+	}
+	trim(m.ProtoReflect(), build(mask.GetPaths()))
+}
+
+// tree is a parsed mask; a nil subtree selects the whole field
+type tree map[string]tree
+
+func build(paths []string) tree {
+	root := tree{}
+	for _, path := range paths {
+		node := root
+		parts := strings.Split(path, ".")
+		for i, part := range parts {
+			sub, seen := node[part]
+			if seen && sub == nil {
+				// an ancestor already selects the whole field
+				break
+			}
+			if i == len(parts)-1 {
+				node[part] = nil
+				break
+			}
+			if sub == nil {
+				sub = tree{}
+				node[part] = sub
+			}
+			node = sub
+		}
+	}
+	return root
+}
+
+func trim(m protoreflect.Message, t tree) {
+	m.Range(func(fd protoreflect.FieldDescriptor, _ protoreflect.Value) bool {
+		sub, ok := t[string(fd.Name())]
+		switch {
+		case !ok:
+			m.Clear(fd)
+		case sub != nil && fd.Message() != nil && !fd.IsList() && !fd.IsMap():
+			trim(m.Mutable(fd).Message(), sub)
+		}
+		return true
+	})
+}
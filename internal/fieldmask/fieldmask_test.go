@@ -0,0 +1,124 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package fieldmask
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/sourcecontextpb"
+	"google.golang.org/protobuf/types/known/typepb"
+)
+
+func newType() *typepb.Type {
+	return &typepb.Type{
+		Name:          "order",
+		Fields:        []*typepb.Field{{Name: "id"}},
+		Oneofs:        []string{"kind"},
+		SourceContext: &sourcecontextpb.SourceContext{FileName: "order.proto"},
+		Syntax:        typepb.Syntax_SYNTAX_PROTO3,
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		paths   []string
+		wantErr bool
+	}{
+		{name: "nil mask"},
+		{name: "top level", paths: []string{"name", "fields"}},
+		{name: "nested", paths: []string{"source_context.file_name"}},
+		{name: "unknown field", paths: []string{"items"}, wantErr: true},
+		{name: "below repeated", paths: []string{"fields.name"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mask *fieldmaskpb.FieldMask
+			if tt.paths != nil {
+				mask = &fieldmaskpb.FieldMask{Paths: tt.paths}
+			}
+			if err := Validate(&typepb.Type{}, mask); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestIncludes(t *testing.T) {
+	tests := []struct {
+		name  string
+		paths []string
+		path  string
+		want  bool
+	}{
+		{name: "empty mask", path: "fields", want: true},
+		{name: "named", paths: []string{"name", "fields"}, path: "fields", want: true},
+		{name: "not named", paths: []string{"name"}, path: "fields"},
+		{name: "child named", paths: []string{"source_context.file_name"}, path: "source_context", want: true},
+		{name: "parent named", paths: []string{"source_context"}, path: "source_context.file_name", want: true},
+		{name: "shared prefix", paths: []string{"fields_extra"}, path: "fields"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Includes(&fieldmaskpb.FieldMask{Paths: tt.paths}, tt.path); got != tt.want {
+				t.Errorf("Includes(%v, %q) = %v, want %v", tt.paths, tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTrim(t *testing.T) {
+	tests := []struct {
+		name  string
+		paths []string
+		want  *typepb.Type
+	}{
+		{name: "empty mask", want: newType()},
+		{
+			name:  "top level",
+			paths: []string{"name", "syntax"},
+			want:  &typepb.Type{Name: "order", Syntax: typepb.Syntax_SYNTAX_PROTO3},
+		},
+		{
+			name:  "nested",
+			paths: []string{"source_context.file_name"},
+			want:  &typepb.Type{SourceContext: &sourcecontextpb.SourceContext{FileName: "order.proto"}},
+		},
+		{
+			name:  "parent wins over child",
+			paths: []string{"source_context.file_name", "source_context", "fields"},
+			want: &typepb.Type{
+				Fields:        []*typepb.Field{{Name: "id"}},
+				SourceContext: &sourcecontextpb.SourceContext{FileName: "order.proto"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := newType()
+			Trim(got, &fieldmaskpb.FieldMask{Paths: tt.paths})
+			if !proto.Equal(got, tt.want) {
+				t.Errorf("Trim() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,206 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package health runs dependency checks for readiness probes and the gRPC
+// health service.
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/db"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// DefaultTimeout bounds a check that does not set its own timeout
+const DefaultTimeout = 2 * time.Second
+
+// Probe paths. LivePath reports that the process is alive and ReadyPath
+// runs the health checks; /health and /ready remain as aliases.
+const (
+	LivePath  = "/health/live"
+	ReadyPath = "/health/ready"
+)
+
+// Report statuses
+const (
+	StatusOK          = "ok"
+	StatusDegraded    = "degraded"
+	StatusUnavailable = "unavailable"
+)
+
+// Check statuses
+const (
+	CheckPassing = "passing"
+	CheckFailing = "failing"
+)
+
+// Check reports whether a dependency is usable
+type Check func(ctx context.Context) error
+
+// Checker is a named dependency check
+type Checker struct {
+	Name  string
+	Check Check
+	// Critical checks make the service unavailable when they fail; other
+	// failures only degrade it
+	Critical bool
+	// Timeout bounds the check; zero uses DefaultTimeout
+	Timeout time.Duration
+}
+
+// Result is the outcome of a single check
+type Result struct {
+	Status     string `json:"status"`
+	Critical   bool   `json:"critical"`
+	Error      string `json:"error,omitempty"`
+	DurationMS int64  `json:"duration_ms"`
+}
+
+// Report is the outcome of a set of checks
+type Report struct {
+	Status string            `json:"status"`
+	Checks map[string]Result `json:"checks,omitempty"`
+}
+
+// Ready reports whether every critical check passed
+func (r Report) Ready() bool {
+	return r.Status != StatusUnavailable
+}
+
+// Run runs the checks concurrently, each under its own timeout
+func Run(ctx context.Context, checkers []Checker) Report {
+	report := Report{Status: StatusOK, Checks: make(map[string]Result, len(checkers))}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, c := range checkers {
+		c := c
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result := run(ctx, c)
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[c.Name] = result
+			switch {
+			case result.Status == CheckPassing:
+			case c.Critical:
+				report.Status = StatusUnavailable
+			case report.Status == StatusOK:
+				report.Status = StatusDegraded
+			}
+		}()
+	}
+	wg.Wait()
+
+	return report
+}
+
+// run runs a single check
+func run(ctx context.Context, c Checker) Result {
+	timeout := c.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := c.Check(ctx)
+	result := Result{
+		Status:     CheckPassing,
+		Critical:   c.Critical,
+		DurationMS: time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		result.Status = CheckFailing
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// Handler serves the checks as a readiness probe. It responds with the JSON
+// report, and 503 when a critical check fails.
+func Handler(checkers []Checker) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		report := Run(r.Context(), checkers)
+
+		w.Header().Set("Content-Type", "application/json")
+		if !report.Ready() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		} else {
+			w.WriteHeader(http.StatusOK)
+		}
+		json.NewEncoder(w).Encode(report)
+	})
+}
+
+// GRPC checks a downstream server with the standard gRPC health service.
+// An empty service checks the server as a whole.
+func GRPC(conn grpc.ClientConnInterface, service string) Check {
+	client := grpc_health_v1.NewHealthClient(conn)
+	return func(ctx context.Context) error {
+		resp, err := client.Check(ctx, &grpc_health_v1.HealthCheckRequest{Service: service})
+		if err != nil {
+			return errors.Wrap(err, "health check failed")
+		}
+		if resp.Status != grpc_health_v1.HealthCheckResponse_SERVING {
+			return errors.Newf("status %s", resp.Status)
+		}
+		return nil
+	}
+}
+
+// Conn checks a downstream server over a connection that reconnects in
+// the background. A failed or closed connection fails at once with its
+// state, instead of waiting out the check timeout; otherwise the server is
+// checked with the gRPC health service, connecting an idle connection.
+func Conn(conn *grpc.ClientConn, service string) Check {
+	check := GRPC(conn, service)
+	return func(ctx context.Context) error {
+		switch state := conn.GetState(); state {
+		case connectivity.TransientFailure, connectivity.Shutdown:
+			return errors.Newf("connection is %s", state)
+		case connectivity.Idle:
+			conn.Connect()
+		}
+		return check(ctx)
+	}
+}
+
+// Migrations checks that the database schema has been migrated to at least
+// version
+func Migrations(database *db.DB, version int) Check {
+	return func(ctx context.Context) error {
+		applied, err := database.AppliedVersion(ctx)
+		if err != nil {
+			return err
+		}
+		if applied < version {
+			return errors.Newf("schema version %d, want %d", applied, version)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,178 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/credentials/insecure"
+	healthgrpc "google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func pass(ctx context.Context) error { return nil }
+
+func fail(ctx context.Context) error { return errors.New("connection refused") }
+
+func hang(ctx context.Context) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		checkers   []Checker
+		wantCode   int
+		wantStatus string
+		wantFailed []string
+	}{
+		{name: "no checks", wantCode: http.StatusOK, wantStatus: StatusOK},
+		{
+			name:       "all pass",
+			checkers:   []Checker{{Name: "database", Check: pass, Critical: true}, {Name: "search", Check: pass}},
+			wantCode:   http.StatusOK,
+			wantStatus: StatusOK,
+		},
+		{
+			name:       "non-critical fails",
+			checkers:   []Checker{{Name: "database", Check: pass, Critical: true}, {Name: "search", Check: fail}},
+			wantCode:   http.StatusOK,
+			wantStatus: StatusDegraded,
+			wantFailed: []string{"search"},
+		},
+		{
+			name:       "critical fails",
+			checkers:   []Checker{{Name: "database", Check: fail, Critical: true}, {Name: "search", Check: fail}},
+			wantCode:   http.StatusServiceUnavailable,
+			wantStatus: StatusUnavailable,
+			wantFailed: []string{"database", "search"},
+		},
+		{
+			name:       "critical times out",
+			checkers:   []Checker{{Name: "database", Check: hang, Critical: true, Timeout: 10 * time.Millisecond}},
+			wantCode:   http.StatusServiceUnavailable,
+			wantStatus: StatusUnavailable,
+			wantFailed: []string{"database"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			Handler(tt.checkers).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ready", nil))
+			if rec.Code != tt.wantCode {
+				t.Errorf("code = %d, want %d", rec.Code, tt.wantCode)
+			}
+
+			var report Report
+			if err := json.NewDecoder(rec.Body).Decode(&report); err != nil {
+				t.Fatalf("failed to decode report: %v", err)
+			}
+			if report.Status != tt.wantStatus {
+				t.Errorf("status = %q, want %q", report.Status, tt.wantStatus)
+			}
+			if len(report.Checks) != len(tt.checkers) {
+				t.Errorf("report has %d checks, want %d", len(report.Checks), len(tt.checkers))
+			}
+			for _, name := range tt.wantFailed {
+				if result := report.Checks[name]; result.Status != CheckFailing || result.Error == "" {
+					t.Errorf("check %s = %+v, want failing with an error", name, result)
+				}
+			}
+		})
+	}
+}
+
+func TestGRPC(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	healthServer := healthgrpc.NewServer()
+	server := grpc.NewServer()
+	grpc_health_v1.RegisterHealthServer(server, healthServer)
+	go server.Serve(listener)
+	defer server.Stop()
+
+	conn, err := grpc.Dial(listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	defer conn.Close()
+
+	check := GRPC(conn, "")
+	if err := check(context.Background()); err != nil {
+		t.Errorf("check() error = %v while serving", err)
+	}
+
+	healthServer.SetServingStatus("", grpc_health_v1.HealthCheckResponse_NOT_SERVING)
+	if err := check(context.Background()); err == nil {
+		t.Error("check() should fail while not serving")
+	}
+}
+
+func TestConn(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	server := grpc.NewServer()
+	grpc_health_v1.RegisterHealthServer(server, healthgrpc.NewServer())
+	go server.Serve(listener)
+
+	conn, err := grpc.Dial(listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	defer conn.Close()
+
+	check := Conn(conn, "")
+	if err := check(context.Background()); err != nil {
+		t.Errorf("check() error = %v while serving", err)
+	}
+
+	server.Stop()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	for state := conn.GetState(); state != connectivity.TransientFailure; state = conn.GetState() {
+		// A lost connection goes idle until something reconnects it
+		if state == connectivity.Idle {
+			conn.Connect()
+		}
+		if !conn.WaitForStateChange(ctx, state) {
+			t.Fatal("connection did not notice the server stopped")
+		}
+	}
+
+	start := time.Now()
+	if err := check(context.Background()); err == nil {
+		t.Error("check() should fail while the connection is down")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("check() took %v, want it to fail at once", elapsed)
+	}
+}
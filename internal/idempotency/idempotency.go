@@ -0,0 +1,166 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package idempotency lets clients retry creates safely. A request carrying
+// an idempotency key runs once per key; retries with the same key get the
+// original response back instead of creating a duplicate.
+package idempotency
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"net/textproto"
+
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+)
+
+// MetadataKey is the gRPC metadata key carrying the idempotency key
+const MetadataKey = "x-idempotency-key"
+
+// Header is the HTTP header carrying the idempotency key
+const Header = "X-Idempotency-Key"
+
+// MaxKeyLength is the longest accepted idempotency key
+const MaxKeyLength = 255
+
+// Store records the requests made under each key and their responses
+type Store interface {
+	// Begin claims key within scope for a request with hash. When the key
+	// already completed a request with the same hash, done is true, its
+	// response is returned and the caller must not run the request again.
+	Begin(ctx context.Context, scope, key string, hash []byte) (response []byte, done bool, err error)
+	// Complete stores the response of the request that claimed key
+	Complete(ctx context.Context, scope, key string, response []byte) error
+	// Abort releases a key whose request failed, so that it can be retried
+	Abort(ctx context.Context, scope, key string) error
+}
+
+type keyKey struct{}
+
+// WithKey returns a context carrying an idempotency key, for services whose
+// methods do not take the request message
+func WithKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, keyKey{}, key)
+}
+
+// Key returns the idempotency key of a request: explicit, typically the
+// request's idempotency_key field, or else the key set by WithKey, or else
+// the key in the incoming metadata
+func Key(ctx context.Context, explicit string) string {
+	if explicit != "" {
+		return explicit
+	}
+	if key, ok := ctx.Value(keyKey{}).(string); ok && key != "" {
+		return key
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(MetadataKey); len(values) > 0 {
+			return values[0]
+		}
+	}
+	return ""
+}
+
+// HeaderMatcher reports whether an HTTP header is the idempotency key
+// header, and the metadata key it is forwarded as
+func HeaderMatcher(key string) (string, bool) {
+	if textproto.CanonicalMIMEHeaderKey(key) == textproto.CanonicalMIMEHeaderKey(Header) {
+		return MetadataKey, true
+	}
+	return "", false
+}
+
+// Do runs fn once for key within scope. A retry with the same key and an
+// equal req returns the response fn produced the first time; a retry with a
+// different req is rejected. fn runs unguarded when key is empty or store
+// is nil. Failed calls are not recorded, so they can be retried.
+//
+// Protobuf requests and responses are encoded with proto, anything else
+// with JSON.
+func Do[T any](ctx context.Context, store Store, scope, key string, req interface{}, fn func(context.Context) (T, error)) (T, error) {
+	var zero T
+	if key == "" || store == nil {
+		return fn(ctx)
+	}
+	if len(key) > MaxKeyLength {
+		return zero, errors.WithCode(errors.Newf("idempotency key must be at most %d characters", MaxKeyLength), errors.CodeInvalidInput)
+	}
+
+	data, err := encode(req)
+	if err != nil {
+		return zero, err
+	}
+	hash := sha256.Sum256(data)
+
+	stored, done, err := store.Begin(ctx, scope, key, hash[:])
+	if err != nil {
+		return zero, err
+	}
+	if done {
+		return decode[T](stored)
+	}
+
+	resp, err := fn(ctx)
+	if err != nil {
+		// Use a fresh context so a cancelled request still releases its key
+		if abortErr := store.Abort(context.WithoutCancel(ctx), scope, key); abortErr != nil {
+			return zero, errors.Wrap(abortErr, "failed to release idempotency key")
+		}
+		return zero, err
+	}
+
+	data, err = encode(resp)
+	if err != nil {
+		return zero, err
+	}
+	if err := store.Complete(context.WithoutCancel(ctx), scope, key, data); err != nil {
+		return zero, err
+	}
+	return resp, nil
+}
+
+func encode(v interface{}) ([]byte, error) {
+	if msg, ok := v.(proto.Message); ok {
+		data, err := proto.MarshalOptions{Deterministic: true}.Marshal(msg)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to encode idempotency record")
+		}
+		return data, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode idempotency record")
+	}
+	return data, nil
+}
+
+func decode[T any](data []byte) (T, error) {
+	var v T
+	if msg, ok := any(v).(proto.Message); ok {
+		msg = msg.ProtoReflect().Type().New().Interface()
+		if err := proto.Unmarshal(data, msg); err != nil {
+			return v, errors.Wrap(err, "failed to decode stored response")
+		}
+		return msg.(T), nil
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return v, errors.Wrap(err, "failed to decode stored response")
+	}
+	return v, nil
+}
@@ -0,0 +1,138 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package idempotency
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	orderv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/order/v1"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestDo(t *testing.T) {
+	const scope = "order.v1.OrderService/CreateOrder"
+	req := &orderv1.CreateOrderRequest{UserId: "user-1"}
+
+	tests := []struct {
+		name     string
+		key      string
+		store    bool
+		calls    []*orderv1.CreateOrderRequest
+		failures int
+		wantRuns int
+		wantCode string
+	}{
+		{name: "without key", calls: []*orderv1.CreateOrderRequest{req, req}, store: true, wantRuns: 2},
+		{name: "without store", key: "k", calls: []*orderv1.CreateOrderRequest{req, req}, wantRuns: 2},
+		{name: "retry", key: "k", store: true, calls: []*orderv1.CreateOrderRequest{req, req, req}, wantRuns: 1},
+		{name: "retry after failure", key: "k", store: true, calls: []*orderv1.CreateOrderRequest{req, req}, failures: 1, wantRuns: 2},
+		{
+			name:     "different request",
+			key:      "k",
+			store:    true,
+			calls:    []*orderv1.CreateOrderRequest{req, {UserId: "user-2"}},
+			wantRuns: 1,
+			wantCode: errors.CodeInvalidInput,
+		},
+		{name: "key too long", key: strings.Repeat("k", MaxKeyLength+1), store: true, calls: []*orderv1.CreateOrderRequest{req}, wantCode: errors.CodeInvalidInput},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var store Store
+			if tt.store {
+				store = NewMemoryStore()
+			}
+
+			runs := 0
+			var first *orderv1.CreateOrderResponse
+			var lastErr error
+			for _, call := range tt.calls {
+				resp, err := Do(context.Background(), store, scope, tt.key, call, func(context.Context) (*orderv1.CreateOrderResponse, error) {
+					runs++
+					if runs <= tt.failures {
+						return nil, errors.New("transient failure")
+					}
+					return &orderv1.CreateOrderResponse{Order: &orderv1.Order{Id: strings.Repeat("o", runs)}}, nil
+				})
+				lastErr = err
+				if err != nil || resp == nil {
+					continue
+				}
+				if first == nil {
+					first = resp
+				} else if tt.wantRuns == 1 && resp.GetOrder().GetId() != first.GetOrder().GetId() {
+					t.Errorf("retry returned order %q, want the original %q", resp.GetOrder().GetId(), first.GetOrder().GetId())
+				}
+			}
+
+			if runs != tt.wantRuns {
+				t.Errorf("fn ran %d times, want %d", runs, tt.wantRuns)
+			}
+			if tt.wantCode != "" && errors.GetCode(lastErr) != tt.wantCode {
+				t.Errorf("last error = %v, want code %v", lastErr, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestDoJSON(t *testing.T) {
+	type user struct {
+		ID    string
+		Email string
+	}
+	store := NewMemoryStore()
+	req := map[string]string{"email": "a@example.com"}
+
+	runs := 0
+	create := func(context.Context) (*user, error) {
+		runs++
+		return &user{ID: "user-1", Email: "a@example.com"}, nil
+	}
+	for i := 0; i < 2; i++ {
+		got, err := Do(context.Background(), store, "CreateUser", "k", req, create)
+		if err != nil {
+			t.Fatalf("Do() error = %v", err)
+		}
+		if got.ID != "user-1" {
+			t.Errorf("Do() = %+v, want user-1", got)
+		}
+	}
+	if runs != 1 {
+		t.Errorf("fn ran %d times, want 1", runs)
+	}
+}
+
+func TestKey(t *testing.T) {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(MetadataKey, "from-metadata"))
+
+	if got := Key(ctx, "explicit"); got != "explicit" {
+		t.Errorf("Key() = %q, want the explicit key", got)
+	}
+	if got := Key(ctx, ""); got != "from-metadata" {
+		t.Errorf("Key() = %q, want the metadata key", got)
+	}
+	if got := Key(WithKey(ctx, "from-context"), ""); got != "from-context" {
+		t.Errorf("Key() = %q, want the context key", got)
+	}
+	if got := Key(context.Background(), ""); got != "" {
+		t.Errorf("Key() = %q, want none", got)
+	}
+}
@@ -0,0 +1,47 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package idempotency
+
+import (
+	"context"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/log"
+	"github.com/kevindiu/monorepo-go-example/internal/scheduler"
+)
+
+// PurgeJob is the name of the expired idempotency key purge job
+const PurgeJob = "idempotency-key-purge"
+
+// NewPurgeJob returns a job that deletes expired idempotency keys
+func NewPurgeJob(store *PostgresStore, schedule string, logger *log.Logger) scheduler.Job {
+	return scheduler.Job{
+		Name:     PurgeJob,
+		Schedule: schedule,
+		Timeout:  time.Minute,
+		Run: func(ctx context.Context) error {
+			purged, err := store.Purge(ctx, time.Now())
+			if err != nil {
+				return err
+			}
+			if purged > 0 {
+				logger.Info("Purged expired idempotency keys", log.Int64("count", purged))
+			}
+			return nil
+		},
+	}
+}
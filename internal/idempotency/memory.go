@@ -0,0 +1,91 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"sync"
+
+	"github.com/kevindiu/monorepo-go-example/internal/tenant"
+)
+
+// MemoryStore is an in-process store intended for tests and single-replica
+// setups. Keys are never forgotten.
+type MemoryStore struct {
+	mu      sync.Mutex
+	entries map[string]*memoryEntry
+}
+
+type memoryEntry struct {
+	hash     []byte
+	response []byte
+	done     bool
+}
+
+// NewMemoryStore creates an in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{entries: make(map[string]*memoryEntry)}
+}
+
+func memoryID(ctx context.Context, scope, key string) string {
+	return tenant.ID(ctx) + "\x00" + scope + "\x00" + key
+}
+
+// Begin implements Store
+func (s *MemoryStore) Begin(ctx context.Context, scope, key string, hash []byte) ([]byte, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := memoryID(ctx, scope, key)
+	entry, ok := s.entries[id]
+	if !ok {
+		s.entries[id] = &memoryEntry{hash: hash}
+		return nil, false, nil
+	}
+	if !bytes.Equal(entry.hash, hash) {
+		return nil, false, differentRequest()
+	}
+	if !entry.done {
+		return nil, false, inProgress()
+	}
+	return entry.response, true, nil
+}
+
+// Complete implements Store
+func (s *MemoryStore) Complete(ctx context.Context, scope, key string, response []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if entry, ok := s.entries[memoryID(ctx, scope, key)]; ok {
+		entry.response = response
+		entry.done = true
+	}
+	return nil
+}
+
+// Abort implements Store
+func (s *MemoryStore) Abort(ctx context.Context, scope, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := memoryID(ctx, scope, key)
+	if entry, ok := s.entries[id]; ok && !entry.done {
+		delete(s.entries, id)
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/db"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/internal/tenant"
+)
+
+// Default store settings
+const (
+	DefaultTTL         = 24 * time.Hour
+	DefaultLockTimeout = time.Minute
+)
+
+// PostgresStore keeps idempotency keys in the idempotency_keys table,
+// scoped to the tenant on the context
+type PostgresStore struct {
+	db          *db.DB
+	ttl         time.Duration
+	lockTimeout time.Duration
+}
+
+// NewPostgresStore creates a store. Keys are remembered for ttl; a request
+// that has not completed after lockTimeout, say because its replica
+// crashed, no longer holds its key.
+func NewPostgresStore(database *db.DB, ttl, lockTimeout time.Duration) *PostgresStore {
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	if lockTimeout <= 0 {
+		lockTimeout = DefaultLockTimeout
+	}
+	return &PostgresStore{db: database, ttl: ttl, lockTimeout: lockTimeout}
+}
+
+// Begin claims key by inserting it, or by taking over an expired or
+// abandoned row. Otherwise the existing row decides the outcome.
+func (s *PostgresStore) Begin(ctx context.Context, scope, key string, hash []byte) ([]byte, bool, error) {
+	query := `
+		INSERT INTO idempotency_keys (tenant_id, scope, key, request_hash, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (tenant_id, scope, key) DO UPDATE
+		SET request_hash = EXCLUDED.request_hash, response = NULL,
+			created_at = EXCLUDED.created_at, completed_at = NULL
+		WHERE idempotency_keys.created_at < $6
+			OR (idempotency_keys.completed_at IS NULL AND idempotency_keys.created_at < $7)
+		RETURNING true
+	`
+
+	now := time.Now()
+	tenantID := tenant.ID(ctx)
+	var claimed bool
+	err := s.db.QueryRowContext(ctx, query, tenantID, scope, key, hash, now, now.Add(-s.ttl), now.Add(-s.lockTimeout)).Scan(&claimed)
+	if err == nil {
+		return nil, false, nil
+	}
+	if err != sql.ErrNoRows {
+		return nil, false, errors.Wrap(err, "failed to claim idempotency key")
+	}
+
+	var storedHash, response []byte
+	var completedAt sql.NullTime
+	err = s.db.QueryRowContext(ctx, `
+		SELECT request_hash, response, completed_at
+		FROM idempotency_keys
+		WHERE tenant_id = $1 AND scope = $2 AND key = $3
+	`, tenantID, scope, key).Scan(&storedHash, &response, &completedAt)
+	if err == sql.ErrNoRows {
+		// The request holding the key failed between the two statements
+		return nil, false, inProgress()
+	}
+	if err != nil {
+		return nil, false, errors.Wrap(err, "failed to get idempotency key")
+	}
+
+	if !bytes.Equal(storedHash, hash) {
+		return nil, false, differentRequest()
+	}
+	if !completedAt.Valid {
+		return nil, false, inProgress()
+	}
+	return response, true, nil
+}
+
+// Complete stores the response of a claimed key
+func (s *PostgresStore) Complete(ctx context.Context, scope, key string, response []byte) error {
+	query := `
+		UPDATE idempotency_keys
+		SET response = $4, completed_at = $5
+		WHERE tenant_id = $1 AND scope = $2 AND key = $3
+	`
+	if _, err := s.db.ExecContext(ctx, query, tenant.ID(ctx), scope, key, response, time.Now()); err != nil {
+		return errors.Wrap(err, "failed to store idempotent response")
+	}
+	return nil
+}
+
+// Abort deletes a claimed key that has not completed
+func (s *PostgresStore) Abort(ctx context.Context, scope, key string) error {
+	query := `
+		DELETE FROM idempotency_keys
+		WHERE tenant_id = $1 AND scope = $2 AND key = $3 AND completed_at IS NULL
+	`
+	if _, err := s.db.ExecContext(ctx, query, tenant.ID(ctx), scope, key); err != nil {
+		return errors.Wrap(err, "failed to release idempotency key")
+	}
+	return nil
+}
+
+// Purge deletes the keys of every tenant that expired before now, and
+// returns how many it deleted
+func (s *PostgresStore) Purge(ctx context.Context, now time.Time) (int64, error) {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE created_at < $1`, now.Add(-s.ttl))
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to purge idempotency keys")
+	}
+	return result.RowsAffected()
+}
+
+func differentRequest() error {
+	return errors.WithCode(errors.New("idempotency key was already used for a different request"), errors.CodeInvalidInput)
+}
+
+func inProgress() error {
+	return errors.WithCode(errors.New("a request with this idempotency key is in progress"), errors.CodeConflict)
+}
@@ -0,0 +1,194 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package jobs is a Postgres-backed queue for deferred work such as sending
+// email or generating exports. Jobs are enqueued, optionally inside the
+// caller's transaction, and executed by a worker Pool with priorities,
+// scheduled execution, retries with backoff and a dead-letter state for
+// jobs that exhaust their attempts.
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	stderrors "errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+)
+
+// Job statuses
+const (
+	StatusPending   = "pending"
+	StatusRunning   = "running"
+	StatusSucceeded = "succeeded"
+	StatusDead      = "dead"
+)
+
+// Enqueue defaults
+const (
+	DefaultQueue       = "default"
+	DefaultMaxAttempts = 5
+)
+
+// Job is a unit of deferred work
+type Job struct {
+	ID          string
+	Queue       string
+	Type        string
+	Payload     json.RawMessage
+	Priority    int
+	Status      string
+	Attempts    int
+	MaxAttempts int
+	LastError   string
+	RunAt       time.Time
+	CreatedAt   time.Time
+	FinishedAt  *time.Time
+}
+
+// Decode unmarshals the job payload into v
+func (j *Job) Decode(v interface{}) error {
+	if err := json.Unmarshal(j.Payload, v); err != nil {
+		return Permanent(errors.Wrapf(err, "failed to decode %s payload", j.Type))
+	}
+	return nil
+}
+
+// Handler executes a job. Returning an error retries the job with backoff
+// until it runs out of attempts; wrap the error with Permanent to
+// dead-letter it immediately.
+type Handler func(ctx context.Context, job *Job) error
+
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent marks err as not worth retrying
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// IsPermanent reports whether err was marked with Permanent
+func IsPermanent(err error) bool {
+	var p *permanentError
+	return stderrors.As(err, &p)
+}
+
+// Option configures an enqueued job
+type Option func(*Job)
+
+// WithQueue places the job on queue instead of DefaultQueue
+func WithQueue(queue string) Option {
+	return func(j *Job) {
+		j.Queue = queue
+	}
+}
+
+// WithPriority sets the job priority; higher priorities run first
+func WithPriority(priority int) Option {
+	return func(j *Job) {
+		j.Priority = priority
+	}
+}
+
+// WithRunAt schedules the job to run no earlier than t
+func WithRunAt(t time.Time) Option {
+	return func(j *Job) {
+		j.RunAt = t
+	}
+}
+
+// WithDelay schedules the job to run after d
+func WithDelay(d time.Duration) Option {
+	return func(j *Job) {
+		j.RunAt = time.Now().Add(d)
+	}
+}
+
+// WithMaxAttempts sets how many times the job is tried before it is
+// dead-lettered
+func WithMaxAttempts(n int) Option {
+	return func(j *Job) {
+		j.MaxAttempts = n
+	}
+}
+
+// Execer is satisfied by *sql.DB, *sql.Tx and *db.DB so jobs can be
+// enqueued inside the caller's transaction
+type Execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// Enqueue adds a job of jobType with payload marshalled as JSON, using exec
+// so the job is only visible once the caller's transaction commits
+func Enqueue(ctx context.Context, exec Execer, jobType string, payload interface{}, opts ...Option) (*Job, error) {
+	if jobType == "" {
+		return nil, errors.WithCode(errors.New("job type is required"), errors.CodeInvalidInput)
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal job payload")
+	}
+
+	now := time.Now()
+	job := &Job{
+		ID:          uuid.New().String(),
+		Queue:       DefaultQueue,
+		Type:        jobType,
+		Payload:     data,
+		Status:      StatusPending,
+		MaxAttempts: DefaultMaxAttempts,
+		RunAt:       now,
+		CreatedAt:   now,
+	}
+	for _, opt := range opts {
+		opt(job)
+	}
+	if job.MaxAttempts <= 0 {
+		job.MaxAttempts = 1
+	}
+
+	query := `
+		INSERT INTO jobs (id, queue, job_type, payload, priority, status, max_attempts, run_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $9)
+	`
+	if _, err := exec.ExecContext(ctx, query,
+		job.ID,
+		job.Queue,
+		job.Type,
+		[]byte(job.Payload),
+		job.Priority,
+		job.Status,
+		job.MaxAttempts,
+		job.RunAt,
+		job.CreatedAt,
+	); err != nil {
+		return nil, errors.Wrap(err, "failed to enqueue job")
+	}
+
+	return job, nil
+}
@@ -0,0 +1,291 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/internal/log"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Default pool settings
+const (
+	DefaultConcurrency    = 4
+	DefaultPollInterval   = time.Second
+	DefaultLease          = 5 * time.Minute
+	DefaultInitialBackoff = 10 * time.Second
+	DefaultMaxBackoff     = time.Hour
+)
+
+// Config holds pool configuration
+type Config struct {
+	Store Store
+	// Queues the pool takes jobs from; empty means DefaultQueue
+	Queues       []string
+	Concurrency  int
+	PollInterval time.Duration
+	// Lease is how long a job may run before it is considered abandoned and
+	// handed to another worker. Handlers are cancelled when it runs out.
+	Lease          time.Duration
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	Logger         *log.Logger
+	// Registerer receives the pool metrics; nil disables registration
+	Registerer prometheus.Registerer
+}
+
+// Pool runs jobs with a fixed number of workers
+type Pool struct {
+	store          Store
+	queues         []string
+	concurrency    int
+	pollInterval   time.Duration
+	lease          time.Duration
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	logger         *log.Logger
+	metrics        *metrics
+
+	mu       sync.RWMutex
+	handlers map[string]Handler
+}
+
+// NewPool creates a new worker pool
+func NewPool(cfg Config) (*Pool, error) {
+	if cfg.Store == nil {
+		return nil, errors.WithCode(errors.New("job store is required"), errors.CodeInvalidInput)
+	}
+	if len(cfg.Queues) == 0 {
+		cfg.Queues = []string{DefaultQueue}
+	}
+	if cfg.Concurrency <= 0 {
+		cfg.Concurrency = DefaultConcurrency
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = DefaultPollInterval
+	}
+	if cfg.Lease <= 0 {
+		cfg.Lease = DefaultLease
+	}
+	if cfg.InitialBackoff <= 0 {
+		cfg.InitialBackoff = DefaultInitialBackoff
+	}
+	if cfg.MaxBackoff <= 0 {
+		cfg.MaxBackoff = DefaultMaxBackoff
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = log.NewDefault()
+	}
+
+	m := newMetrics()
+	if cfg.Registerer != nil {
+		if err := m.register(cfg.Registerer); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Pool{
+		store:          cfg.Store,
+		queues:         cfg.Queues,
+		concurrency:    cfg.Concurrency,
+		pollInterval:   cfg.PollInterval,
+		lease:          cfg.Lease,
+		initialBackoff: cfg.InitialBackoff,
+		maxBackoff:     cfg.MaxBackoff,
+		logger:         cfg.Logger.Named("jobs"),
+		metrics:        m,
+		handlers:       make(map[string]Handler),
+	}, nil
+}
+
+// Register sets the handler for jobs of jobType
+func (p *Pool) Register(jobType string, handler Handler) error {
+	if jobType == "" || handler == nil {
+		return errors.WithCode(errors.New("job type and handler are required"), errors.CodeInvalidInput)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, ok := p.handlers[jobType]; ok {
+		return errors.WithCode(errors.Newf("handler for %s already registered", jobType), errors.CodeConflict)
+	}
+	p.handlers[jobType] = handler
+	return nil
+}
+
+// Run processes jobs until ctx is cancelled and every in-flight job has
+// finished
+func (p *Pool) Run(ctx context.Context) error {
+	p.logger.Info("Starting job workers",
+		log.Any("queues", p.queues),
+		log.Int("concurrency", p.concurrency),
+	)
+
+	var wg sync.WaitGroup
+	for i := 0; i < p.concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p.work(ctx)
+		}()
+	}
+	wg.Wait()
+
+	p.logger.Info("Job workers stopped")
+	return nil
+}
+
+// work takes jobs back to back, waiting a poll interval when none is due
+func (p *Pool) work(ctx context.Context) {
+	for {
+		ran, err := p.RunOnce(ctx)
+		if err != nil && ctx.Err() == nil {
+			p.logger.Error("Job worker iteration failed", log.Error(err))
+		}
+		if ran && err == nil {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(p.pollInterval):
+		}
+	}
+}
+
+// RunOnce runs a single due job and reports whether there was one
+func (p *Pool) RunOnce(ctx context.Context) (bool, error) {
+	job, err := p.store.Dequeue(ctx, p.queues, p.lease)
+	if err != nil || job == nil {
+		return false, err
+	}
+
+	// Outcomes are recorded even when the pool is shutting down
+	recordCtx := context.Background()
+
+	if job.Attempts > job.MaxAttempts {
+		// The previous worker died holding the job on its last attempt
+		p.metrics.deadLettered.WithLabelValues(job.Type).Inc()
+		return true, p.store.Bury(recordCtx, job.ID, errors.New("job lease expired on its last attempt"))
+	}
+
+	start := time.Now()
+	runErr := p.execute(ctx, job)
+	p.metrics.duration.WithLabelValues(job.Type).Observe(time.Since(start).Seconds())
+
+	if runErr == nil {
+		p.metrics.processed.WithLabelValues(job.Type, "succeeded").Inc()
+		return true, p.store.Complete(recordCtx, job.ID)
+	}
+
+	if IsPermanent(runErr) || job.Attempts >= job.MaxAttempts {
+		p.metrics.processed.WithLabelValues(job.Type, "dead").Inc()
+		p.metrics.deadLettered.WithLabelValues(job.Type).Inc()
+		p.logger.Error("Job dead-lettered",
+			log.String("job_id", job.ID),
+			log.String("job_type", job.Type),
+			log.Int("attempt", job.Attempts),
+			log.Error(runErr),
+		)
+		return true, p.store.Bury(recordCtx, job.ID, runErr)
+	}
+
+	p.metrics.processed.WithLabelValues(job.Type, "retried").Inc()
+	p.logger.Warn("Job failed, retrying",
+		log.String("job_id", job.ID),
+		log.String("job_type", job.Type),
+		log.Int("attempt", job.Attempts),
+		log.Error(runErr),
+	)
+	return true, p.store.Retry(recordCtx, job.ID, runErr, time.Now().Add(p.backoff(job.Attempts)))
+}
+
+// execute runs the job's handler within its lease, converting panics into
+// errors
+func (p *Pool) execute(ctx context.Context, job *Job) (err error) {
+	p.mu.RLock()
+	handler, ok := p.handlers[job.Type]
+	p.mu.RUnlock()
+	if !ok {
+		return Permanent(errors.Newf("no handler registered for %s", job.Type))
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, p.lease)
+	defer cancel()
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panicked: %v", r)
+		}
+	}()
+
+	return handler(ctx, job)
+}
+
+// backoff returns the delay before retrying after the given attempt,
+// doubling from the initial backoff with up to 20% jitter
+func (p *Pool) backoff(attempt int) time.Duration {
+	d := p.initialBackoff
+	for i := 1; i < attempt && d < p.maxBackoff; i++ {
+		d *= 2
+	}
+	if d > p.maxBackoff {
+		d = p.maxBackoff
+	}
+	return d + time.Duration(rand.Int63n(int64(d)/5+1))
+}
+
+type metrics struct {
+	processed    *prometheus.CounterVec
+	deadLettered *prometheus.CounterVec
+	duration     *prometheus.HistogramVec
+}
+
+func newMetrics() *metrics {
+	return &metrics{
+		processed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "jobs_processed_total",
+			Help: "Number of job runs by outcome.",
+		}, []string{"type", "outcome"}),
+		deadLettered: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "jobs_dead_lettered_total",
+			Help: "Number of jobs moved to the dead-letter state.",
+		}, []string{"type"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "jobs_duration_seconds",
+			Help:    "Job handler run time.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"type"}),
+	}
+}
+
+func (m *metrics) register(reg prometheus.Registerer) error {
+	for _, c := range []prometheus.Collector{m.processed, m.deadLettered, m.duration} {
+		if err := reg.Register(c); err != nil {
+			return errors.Wrap(err, "failed to register job metrics")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,273 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// mockStore is an in-memory implementation of Store for testing
+type mockStore struct {
+	jobs map[string]*Job
+	seq  int
+}
+
+func newMockStore() *mockStore {
+	return &mockStore{jobs: make(map[string]*Job)}
+}
+
+func (m *mockStore) Enqueue(ctx context.Context, jobType string, payload interface{}, opts ...Option) (*Job, error) {
+	m.seq++
+	job := &Job{
+		ID:          fmt.Sprintf("job-%d", m.seq),
+		Queue:       DefaultQueue,
+		Type:        jobType,
+		Payload:     []byte(`{}`),
+		Status:      StatusPending,
+		MaxAttempts: DefaultMaxAttempts,
+		RunAt:       time.Now(),
+	}
+	for _, opt := range opts {
+		opt(job)
+	}
+	m.jobs[job.ID] = job
+	return job, nil
+}
+
+func (m *mockStore) Dequeue(ctx context.Context, queues []string, lease time.Duration) (*Job, error) {
+	var next *Job
+	for _, job := range m.jobs {
+		if job.Status != StatusPending || job.RunAt.After(time.Now()) || !contains(queues, job.Queue) {
+			continue
+		}
+		if next == nil || job.Priority > next.Priority {
+			next = job
+		}
+	}
+	if next == nil {
+		return nil, nil
+	}
+	next.Status = StatusRunning
+	next.Attempts++
+	return next, nil
+}
+
+func (m *mockStore) Complete(ctx context.Context, id string) error {
+	m.jobs[id].Status = StatusSucceeded
+	return nil
+}
+
+func (m *mockStore) Retry(ctx context.Context, id string, cause error, runAt time.Time) error {
+	m.jobs[id].Status = StatusPending
+	m.jobs[id].LastError = cause.Error()
+	m.jobs[id].RunAt = runAt
+	return nil
+}
+
+func (m *mockStore) Bury(ctx context.Context, id string, cause error) error {
+	m.jobs[id].Status = StatusDead
+	m.jobs[id].LastError = cause.Error()
+	return nil
+}
+
+func (m *mockStore) ListDead(ctx context.Context, queue string, limit int) ([]*Job, error) {
+	return nil, nil
+}
+
+func (m *mockStore) Requeue(ctx context.Context, id string) error {
+	m.jobs[id].Status = StatusPending
+	m.jobs[id].Attempts = 0
+	return nil
+}
+
+func (m *mockStore) Stats(ctx context.Context) (*Stats, error) {
+	return &Stats{}, nil
+}
+
+func contains(values []string, v string) bool {
+	for _, s := range values {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
+
+func newTestPool(t *testing.T, store Store) *Pool {
+	t.Helper()
+	p, err := NewPool(Config{Store: store, Queues: []string{DefaultQueue, "email"}})
+	if err != nil {
+		t.Fatalf("NewPool() error = %v", err)
+	}
+	return p
+}
+
+func TestRunOnceOutcomes(t *testing.T) {
+	tests := []struct {
+		name        string
+		handler     Handler
+		maxAttempts int
+		wantStatus  string
+	}{
+		{
+			name:       "success",
+			handler:    func(ctx context.Context, job *Job) error { return nil },
+			wantStatus: StatusSucceeded,
+		},
+		{
+			name:       "retryable failure",
+			handler:    func(ctx context.Context, job *Job) error { return errors.New("smtp unavailable") },
+			wantStatus: StatusPending,
+		},
+		{
+			name:        "last attempt",
+			handler:     func(ctx context.Context, job *Job) error { return errors.New("smtp unavailable") },
+			maxAttempts: 1,
+			wantStatus:  StatusDead,
+		},
+		{
+			name:       "permanent failure",
+			handler:    func(ctx context.Context, job *Job) error { return Permanent(errors.New("bad address")) },
+			wantStatus: StatusDead,
+		},
+		{
+			name:       "panic",
+			handler:    func(ctx context.Context, job *Job) error { panic("boom") },
+			wantStatus: StatusPending,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newMockStore()
+			p := newTestPool(t, store)
+			p.Register("email.send", tt.handler)
+
+			opts := []Option{WithQueue("email")}
+			if tt.maxAttempts > 0 {
+				opts = append(opts, WithMaxAttempts(tt.maxAttempts))
+			}
+			job, _ := store.Enqueue(context.Background(), "email.send", nil, opts...)
+
+			ran, err := p.RunOnce(context.Background())
+			if err != nil || !ran {
+				t.Fatalf("RunOnce() = %v, %v, want true, nil", ran, err)
+			}
+			if job.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s", job.Status, tt.wantStatus)
+			}
+			if tt.wantStatus == StatusPending && !job.RunAt.After(time.Now()) {
+				t.Error("retried job was not delayed")
+			}
+		})
+	}
+}
+
+func TestRunOnceUnknownType(t *testing.T) {
+	store := newMockStore()
+	p := newTestPool(t, store)
+	job, _ := store.Enqueue(context.Background(), "report.generate", nil)
+
+	if _, err := p.RunOnce(context.Background()); err != nil {
+		t.Fatalf("RunOnce() error = %v", err)
+	}
+	if job.Status != StatusDead {
+		t.Errorf("status = %s, want %s", job.Status, StatusDead)
+	}
+}
+
+func TestRunOncePriorityAndSchedule(t *testing.T) {
+	store := newMockStore()
+	p := newTestPool(t, store)
+
+	var order []string
+	p.Register("task", func(ctx context.Context, job *Job) error {
+		order = append(order, job.ID)
+		return nil
+	})
+
+	low, _ := store.Enqueue(context.Background(), "task", nil)
+	high, _ := store.Enqueue(context.Background(), "task", nil, WithPriority(10))
+	store.Enqueue(context.Background(), "task", nil, WithPriority(100), WithDelay(time.Hour))
+	store.Enqueue(context.Background(), "task", nil, WithQueue("export"))
+
+	for {
+		ran, err := p.RunOnce(context.Background())
+		if err != nil {
+			t.Fatalf("RunOnce() error = %v", err)
+		}
+		if !ran {
+			break
+		}
+	}
+
+	if len(order) != 2 || order[0] != high.ID || order[1] != low.ID {
+		t.Errorf("ran %v, want [%s %s]", order, high.ID, low.ID)
+	}
+}
+
+func TestRegisterDuplicate(t *testing.T) {
+	p := newTestPool(t, newMockStore())
+	noop := func(ctx context.Context, job *Job) error { return nil }
+
+	if err := p.Register("task", noop); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	if err := p.Register("task", noop); err == nil {
+		t.Error("Register() accepted a duplicate job type")
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	p, _ := NewPool(Config{Store: newMockStore(), InitialBackoff: time.Second, MaxBackoff: 10 * time.Second})
+
+	tests := []struct {
+		attempt int
+		min     time.Duration
+	}{
+		{attempt: 1, min: time.Second},
+		{attempt: 2, min: 2 * time.Second},
+		{attempt: 3, min: 4 * time.Second},
+		{attempt: 10, min: 10 * time.Second},
+	}
+
+	for _, tt := range tests {
+		got := p.backoff(tt.attempt)
+		if got < tt.min || got > tt.min+tt.min/5 {
+			t.Errorf("backoff(%d) = %v, want within 20%% above %v", tt.attempt, got, tt.min)
+		}
+	}
+}
+
+func TestPermanent(t *testing.T) {
+	cause := errors.New("bad address")
+	err := Permanent(cause)
+
+	if !IsPermanent(err) || !errors.Is(err, cause) {
+		t.Errorf("Permanent(%v) lost its cause or marker", cause)
+	}
+	if IsPermanent(cause) {
+		t.Error("IsPermanent() = true for a plain error")
+	}
+	if Permanent(nil) != nil {
+		t.Error("Permanent(nil) != nil")
+	}
+}
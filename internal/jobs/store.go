@@ -0,0 +1,234 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/db"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/lib/pq"
+)
+
+// Stats describes the queue backlog
+type Stats struct {
+	Pending int64
+	Running int64
+	Dead    int64
+}
+
+// Store persists jobs
+type Store interface {
+	Enqueue(ctx context.Context, jobType string, payload interface{}, opts ...Option) (*Job, error)
+	// Dequeue leases the next due job from queues until lease expires, or
+	// returns nil when none is due
+	Dequeue(ctx context.Context, queues []string, lease time.Duration) (*Job, error)
+	Complete(ctx context.Context, id string) error
+	Retry(ctx context.Context, id string, cause error, runAt time.Time) error
+	Bury(ctx context.Context, id string, cause error) error
+	ListDead(ctx context.Context, queue string, limit int) ([]*Job, error)
+	Requeue(ctx context.Context, id string) error
+	Stats(ctx context.Context) (*Stats, error)
+}
+
+type store struct {
+	db *db.DB
+}
+
+// NewStore creates a Postgres-backed job store
+func NewStore(database *db.DB) Store {
+	return &store{db: database}
+}
+
+// Enqueue adds a job outside any transaction
+func (s *store) Enqueue(ctx context.Context, jobType string, payload interface{}, opts ...Option) (*Job, error) {
+	return Enqueue(ctx, s.db, jobType, payload, opts...)
+}
+
+// Dequeue marks the highest priority due job running. Jobs whose lease
+// expired, because their worker died, are due again. Rows are locked with
+// SKIP LOCKED so workers never lease the same job twice.
+func (s *store) Dequeue(ctx context.Context, queues []string, lease time.Duration) (*Job, error) {
+	query := `
+		UPDATE jobs
+		SET status = 'running', attempts = attempts + 1, locked_until = $1, updated_at = $2
+		WHERE id = (
+			SELECT id FROM jobs
+			WHERE queue = ANY($3)
+				AND ((status = 'pending' AND run_at <= $2) OR (status = 'running' AND locked_until < $2))
+			ORDER BY priority DESC, run_at ASC
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, queue, job_type, payload, priority, status, attempts, max_attempts,
+			COALESCE(last_error, ''), run_at, created_at, finished_at
+	`
+
+	now := time.Now()
+	job, err := scanJob(s.db.QueryRowContext(ctx, query, now.Add(lease), now, pq.Array(queues)))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to dequeue job")
+	}
+	return job, nil
+}
+
+// Complete marks a job succeeded
+func (s *store) Complete(ctx context.Context, id string) error {
+	query := `
+		UPDATE jobs
+		SET status = 'succeeded', locked_until = NULL, finished_at = $1, updated_at = $1
+		WHERE id = $2
+	`
+	if _, err := s.db.ExecContext(ctx, query, time.Now(), id); err != nil {
+		return errors.Wrap(err, "failed to complete job")
+	}
+	return nil
+}
+
+// Retry returns a failed job to the queue to run again at runAt
+func (s *store) Retry(ctx context.Context, id string, cause error, runAt time.Time) error {
+	query := `
+		UPDATE jobs
+		SET status = 'pending', locked_until = NULL, last_error = $1, run_at = $2, updated_at = $3
+		WHERE id = $4
+	`
+	if _, err := s.db.ExecContext(ctx, query, cause.Error(), runAt, time.Now(), id); err != nil {
+		return errors.Wrap(err, "failed to retry job")
+	}
+	return nil
+}
+
+// Bury moves a job to the dead-letter state
+func (s *store) Bury(ctx context.Context, id string, cause error) error {
+	query := `
+		UPDATE jobs
+		SET status = 'dead', locked_until = NULL, last_error = $1, finished_at = $2, updated_at = $2
+		WHERE id = $3
+	`
+	if _, err := s.db.ExecContext(ctx, query, cause.Error(), time.Now(), id); err != nil {
+		return errors.Wrap(err, "failed to bury job")
+	}
+	return nil
+}
+
+// ListDead returns the most recently dead-lettered jobs, optionally
+// restricted to queue
+func (s *store) ListDead(ctx context.Context, queue string, limit int) ([]*Job, error) {
+	query := `
+		SELECT id, queue, job_type, payload, priority, status, attempts, max_attempts,
+			COALESCE(last_error, ''), run_at, created_at, finished_at
+		FROM jobs
+		WHERE status = 'dead' AND ($1 = '' OR queue = $1)
+		ORDER BY finished_at DESC
+		LIMIT $2
+	`
+
+	rows, err := s.db.QueryContext(ctx, query, queue, limit)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list dead jobs")
+	}
+	defer rows.Close()
+
+	var jobs []*Job
+	for rows.Next() {
+		job, err := scanJob(rows)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to scan job")
+		}
+		jobs = append(jobs, job)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "error iterating jobs")
+	}
+
+	return jobs, nil
+}
+
+// Requeue gives a dead job a fresh set of attempts
+func (s *store) Requeue(ctx context.Context, id string) error {
+	query := `
+		UPDATE jobs
+		SET status = 'pending', attempts = 0, run_at = $1, finished_at = NULL, updated_at = $1
+		WHERE id = $2 AND status = 'dead'
+	`
+
+	result, err := s.db.ExecContext(ctx, query, time.Now(), id)
+	if err != nil {
+		return errors.Wrap(err, "failed to requeue job")
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "failed to get affected rows")
+	}
+	if rows == 0 {
+		return errors.WithCode(errors.New("dead job not found"), errors.CodeNotFound)
+	}
+
+	return nil
+}
+
+// Stats counts jobs by state
+func (s *store) Stats(ctx context.Context) (*Stats, error) {
+	query := `
+		SELECT
+			COUNT(*) FILTER (WHERE status = 'pending'),
+			COUNT(*) FILTER (WHERE status = 'running'),
+			COUNT(*) FILTER (WHERE status = 'dead')
+		FROM jobs
+		WHERE status <> 'succeeded'
+	`
+
+	var stats Stats
+	if err := s.db.QueryRowContext(ctx, query).Scan(&stats.Pending, &stats.Running, &stats.Dead); err != nil {
+		return nil, errors.Wrap(err, "failed to get job stats")
+	}
+	return &stats, nil
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanJob(row scanner) (*Job, error) {
+	var job Job
+	var payload []byte
+	if err := row.Scan(
+		&job.ID,
+		&job.Queue,
+		&job.Type,
+		&payload,
+		&job.Priority,
+		&job.Status,
+		&job.Attempts,
+		&job.MaxAttempts,
+		&job.LastError,
+		&job.RunAt,
+		&job.CreatedAt,
+		&job.FinishedAt,
+	); err != nil {
+		return nil, err
+	}
+	job.Payload = payload
+	return &job, nil
+}
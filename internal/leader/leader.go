@@ -0,0 +1,184 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package leader elects a single replica to run singleton workers, such as
+// the scheduler and the outbox relay.
+package leader
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/config"
+	"github.com/kevindiu/monorepo-go-example/internal/db"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/internal/log"
+)
+
+// Default intervals
+const (
+	DefaultRetryInterval = 5 * time.Second
+	DefaultCheckInterval = 5 * time.Second
+)
+
+// Lock grants leadership of a name to one holder at a time
+type Lock interface {
+	// TryAcquire attempts to take leadership without blocking. When acquired
+	// is true, the caller must release the lease when done.
+	TryAcquire(ctx context.Context, name string) (lease Lease, acquired bool, err error)
+}
+
+// Lease is held leadership
+type Lease interface {
+	// Check returns an error once leadership has been lost
+	Check(ctx context.Context) error
+	// Release gives up leadership
+	Release()
+}
+
+// Config configures an Election
+type Config struct {
+	// Name identifies the election; replicas campaigning for the same name
+	// compete for the same leadership
+	Name          string
+	Lock          Lock
+	RetryInterval time.Duration
+	CheckInterval time.Duration
+	Logger        *log.Logger
+}
+
+// Election runs a function on whichever replica holds leadership
+type Election struct {
+	name          string
+	lock          Lock
+	retryInterval time.Duration
+	checkInterval time.Duration
+	logger        *log.Logger
+	leader        atomic.Bool
+}
+
+// New creates an election
+func New(cfg Config) (*Election, error) {
+	if cfg.Name == "" {
+		return nil, errors.New("election name is required")
+	}
+	if cfg.Lock == nil {
+		return nil, errors.New("election lock is required")
+	}
+	if cfg.RetryInterval <= 0 {
+		cfg.RetryInterval = DefaultRetryInterval
+	}
+	if cfg.CheckInterval <= 0 {
+		cfg.CheckInterval = DefaultCheckInterval
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = log.NewDefault()
+	}
+
+	return &Election{
+		name:          cfg.Name,
+		lock:          cfg.Lock,
+		retryInterval: cfg.RetryInterval,
+		checkInterval: cfg.CheckInterval,
+		logger:        cfg.Logger.With(log.String("election", cfg.Name)),
+	}, nil
+}
+
+// FromConfig creates an election for name. Enabled, leadership is a Postgres
+// advisory lock; disabled, this replica always leads.
+func FromConfig(cfg *config.Leader, name string, database *db.DB, logger *log.Logger) (*Election, error) {
+	var lock Lock = NewLocalLock()
+	if cfg.Enabled {
+		lock = NewPostgresLock(database)
+	}
+	return New(Config{
+		Name:          name,
+		Lock:          lock,
+		RetryInterval: cfg.RetryInterval,
+		CheckInterval: cfg.CheckInterval,
+		Logger:        logger,
+	})
+}
+
+// IsLeader reports whether this replica currently leads
+func (e *Election) IsLeader() bool {
+	return e.leader.Load()
+}
+
+// Run campaigns for leadership until ctx is done, calling fn whenever this
+// replica becomes leader. fn's context is cancelled when leadership is lost;
+// Run then campaigns again.
+func (e *Election) Run(ctx context.Context, fn func(ctx context.Context) error) error {
+	for {
+		lease, acquired, err := e.lock.TryAcquire(ctx, e.name)
+		switch {
+		case err != nil && ctx.Err() == nil:
+			e.logger.Warn("Failed to campaign for leadership", log.Error(err))
+		case acquired:
+			e.lead(ctx, lease, fn)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(e.retryInterval):
+		}
+	}
+}
+
+// Worker returns fn gated behind the election, for use as a server worker
+func (e *Election) Worker(fn func(ctx context.Context) error) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		return e.Run(ctx, fn)
+	}
+}
+
+// lead runs fn while lease is held
+func (e *Election) lead(ctx context.Context, lease Lease, fn func(ctx context.Context) error) {
+	defer lease.Release()
+
+	e.leader.Store(true)
+	defer e.leader.Store(false)
+	e.logger.Info("Became leader")
+
+	leadCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() { done <- fn(leadCtx) }()
+
+	ticker := time.NewTicker(e.checkInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case err := <-done:
+			if err != nil && ctx.Err() == nil {
+				e.logger.Error("Leader worker failed", log.Error(err))
+			}
+			e.logger.Info("Gave up leadership")
+			return
+		case <-ticker.C:
+			if err := lease.Check(ctx); err != nil && ctx.Err() == nil {
+				e.logger.Warn("Lost leadership", log.Error(err))
+				cancel()
+				<-done
+				return
+			}
+		}
+	}
+}
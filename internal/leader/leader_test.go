@@ -0,0 +1,176 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package leader
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/log"
+)
+
+// flakyLock hands out leases that can be revoked
+type flakyLock struct {
+	mu   sync.Mutex
+	lost bool
+}
+
+func (l *flakyLock) TryAcquire(ctx context.Context, name string) (Lease, bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.lost = false
+	return &flakyLease{lock: l}, true, nil
+}
+
+func (l *flakyLock) revoke() {
+	l.mu.Lock()
+	l.lost = true
+	l.mu.Unlock()
+}
+
+type flakyLease struct {
+	lock *flakyLock
+}
+
+func (l *flakyLease) Check(ctx context.Context) error {
+	l.lock.mu.Lock()
+	defer l.lock.mu.Unlock()
+	if l.lock.lost {
+		return errors.New("lock lost")
+	}
+	return nil
+}
+
+func (l *flakyLease) Release() {}
+
+func newElection(t *testing.T, name string, lock Lock) *Election {
+	t.Helper()
+	e, err := New(Config{
+		Name:          name,
+		Lock:          lock,
+		RetryInterval: 5 * time.Millisecond,
+		CheckInterval: 5 * time.Millisecond,
+		Logger:        log.NewDefault(),
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	return e
+}
+
+func TestNewValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+	}{
+		{name: "missing name", cfg: Config{Lock: NewLocalLock()}},
+		{name: "missing lock", cfg: Config{Name: "scheduler"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := New(tt.cfg); err == nil {
+				t.Error("New() should fail")
+			}
+		})
+	}
+}
+
+func TestOnlyOneReplicaLeads(t *testing.T) {
+	lock := NewLocalLock()
+	var running, maxRunning int32
+
+	worker := func(ctx context.Context) error {
+		n := atomic.AddInt32(&running, 1)
+		for {
+			old := atomic.LoadInt32(&maxRunning)
+			if n <= old || atomic.CompareAndSwapInt32(&maxRunning, old, n) {
+				break
+			}
+		}
+		<-ctx.Done()
+		atomic.AddInt32(&running, -1)
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	elections := make([]*Election, 3)
+	for i := range elections {
+		elections[i] = newElection(t, "scheduler", lock)
+		wg.Add(1)
+		go func(e *Election) {
+			defer wg.Done()
+			e.Run(ctx, worker)
+		}(elections[i])
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	leaders := 0
+	for _, e := range elections {
+		if e.IsLeader() {
+			leaders++
+		}
+	}
+	cancel()
+	wg.Wait()
+
+	if leaders != 1 {
+		t.Errorf("%d replicas lead, want 1", leaders)
+	}
+	if maxRunning != 1 {
+		t.Errorf("worker ran on %d replicas at once, want 1", maxRunning)
+	}
+}
+
+func TestLostLeadershipStopsWorker(t *testing.T) {
+	lock := &flakyLock{}
+	e := newElection(t, "relay", lock)
+
+	var starts int32
+	stopped := make(chan struct{}, 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go e.Run(ctx, func(ctx context.Context) error {
+		atomic.AddInt32(&starts, 1)
+		<-ctx.Done()
+		stopped <- struct{}{}
+		return nil
+	})
+
+	time.Sleep(20 * time.Millisecond)
+	lock.revoke()
+
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("worker was not stopped after leadership was lost")
+	}
+
+	// The replica campaigns again and resumes the worker
+	deadline := time.Now().Add(time.Second)
+	for atomic.LoadInt32(&starts) < 2 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if atomic.LoadInt32(&starts) < 2 {
+		t.Error("worker was not restarted after leadership was regained")
+	}
+}
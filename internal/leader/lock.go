@@ -0,0 +1,122 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package leader
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+
+	"github.com/kevindiu/monorepo-go-example/internal/db"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/internal/scheduler"
+)
+
+// PostgresLock holds leadership as a session-level advisory lock. The lock
+// is released as soon as the leader's connection drops, so a crashed leader
+// is replaced within a retry interval.
+type PostgresLock struct {
+	db *db.DB
+}
+
+// NewPostgresLock creates an advisory-lock based leadership lock
+func NewPostgresLock(database *db.DB) *PostgresLock {
+	return &PostgresLock{db: database}
+}
+
+// TryAcquire attempts to take the advisory lock for name
+func (l *PostgresLock) TryAcquire(ctx context.Context, name string) (Lease, bool, error) {
+	// Advisory locks belong to a session, so pin a connection while leading
+	conn, err := l.db.Conn(ctx)
+	if err != nil {
+		return nil, false, errors.Wrap(err, "failed to get database connection")
+	}
+
+	// Prefix the name so that it cannot collide with scheduler job locks
+	key := scheduler.LockKey("leader:" + name)
+
+	var acquired bool
+	if err := conn.QueryRowContext(ctx, `SELECT pg_try_advisory_lock($1)`, key).Scan(&acquired); err != nil {
+		conn.Close()
+		return nil, false, errors.Wrap(err, "failed to acquire advisory lock")
+	}
+	if !acquired {
+		conn.Close()
+		return nil, false, nil
+	}
+
+	return &postgresLease{conn: conn, key: key}, true, nil
+}
+
+type postgresLease struct {
+	conn *sql.Conn
+	key  int64
+}
+
+// Check verifies that the pinned connection, and with it the lock, is alive
+func (l *postgresLease) Check(ctx context.Context) error {
+	if err := l.conn.PingContext(ctx); err != nil {
+		return errors.Wrap(err, "leader connection lost")
+	}
+	return nil
+}
+
+// Release unlocks and returns the pinned connection
+func (l *postgresLease) Release() {
+	// Use a fresh context so unlocking still happens after cancellation
+	l.conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, l.key)
+	l.conn.Close()
+}
+
+// LocalLock only elects a single holder within one process
+type LocalLock struct {
+	mu   sync.Mutex
+	held map[string]bool
+}
+
+// NewLocalLock creates an in-process leadership lock
+func NewLocalLock() *LocalLock {
+	return &LocalLock{held: make(map[string]bool)}
+}
+
+// TryAcquire attempts to take leadership of name within the process
+func (l *LocalLock) TryAcquire(ctx context.Context, name string) (Lease, bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.held[name] {
+		return nil, false, nil
+	}
+	l.held[name] = true
+
+	return &localLease{lock: l, name: name}, true, nil
+}
+
+type localLease struct {
+	lock *LocalLock
+	name string
+}
+
+// Check always succeeds; in-process leadership cannot be lost
+func (l *localLease) Check(ctx context.Context) error { return nil }
+
+// Release gives up leadership of the name
+func (l *localLease) Release() {
+	l.lock.mu.Lock()
+	delete(l.lock.held, l.name)
+	l.lock.mu.Unlock()
+}
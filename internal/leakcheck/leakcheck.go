@@ -0,0 +1,152 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package leakcheck verifies that a component released everything it
+// started: goroutines and database connections. The server runs it after
+// shutdown in development and test mode, so that leaks fail tests instead
+// of surfacing in production.
+package leakcheck
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+)
+
+// pollInterval is how often Goroutines looks again for goroutines that are
+// still exiting
+const pollInterval = 10 * time.Millisecond
+
+// ignored are goroutines owned by the runtime, the test harness or HTTP
+// clients rather than by the component being checked
+var ignored = []string{
+	"os/signal.signal_recv",
+	"os/signal.loop",
+	"runtime.ensureSigM",
+	"testing.tRunner",
+	"testing.(*T).Run",
+	"net/http.(*persistConn).readLoop",
+	"net/http.(*persistConn).writeLoop",
+}
+
+// Snapshot identifies the goroutines running at one point in time
+type Snapshot map[uint64]struct{}
+
+// Take records the goroutines running now
+func Take() Snapshot {
+	snap := make(Snapshot)
+	for id := range stacks() {
+		snap[id] = struct{}{}
+	}
+	return snap
+}
+
+// Goroutines waits until ctx is done for the goroutines started since base
+// to exit and returns the stacks of those still running
+func Goroutines(ctx context.Context, base Snapshot) []string {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		leaked := leakedSince(base)
+		if len(leaked) == 0 {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return leaked
+		case <-ticker.C:
+		}
+	}
+}
+
+// Conns reports databases that still have connections checked out, which
+// means rows or transactions were never closed
+func Conns(dbs map[string]*sql.DB) error {
+	var leaks []string
+	for name, db := range dbs {
+		if inUse := db.Stats().InUse; inUse > 0 {
+			leaks = append(leaks, name+": "+strconv.Itoa(inUse))
+		}
+	}
+	if len(leaks) > 0 {
+		return errors.Newf("database connections still in use: %s", strings.Join(leaks, ", "))
+	}
+	return nil
+}
+
+// leakedSince returns the stacks of goroutines missing from base that are
+// not ignored
+func leakedSince(base Snapshot) []string {
+	var leaked []string
+	for id, stack := range stacks() {
+		if _, ok := base[id]; ok || isIgnored(stack) {
+			continue
+		}
+		leaked = append(leaked, stack)
+	}
+	return leaked
+}
+
+func isIgnored(stack string) bool {
+	for _, fn := range ignored {
+		if strings.Contains(stack, fn+"(") {
+			return true
+		}
+	}
+	return false
+}
+
+// stacks returns the stack of every goroutine but the caller's by ID
+func stacks() map[uint64]string {
+	buf := make([]byte, 64<<10)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			buf = buf[:n]
+			break
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+
+	// The first goroutine is the caller
+	blocks := bytes.Split(buf, []byte("\n\n"))
+	result := make(map[uint64]string, len(blocks))
+	for _, block := range blocks[1:] {
+		id, ok := goroutineID(block)
+		if ok {
+			result[id] = string(block)
+		}
+	}
+	return result
+}
+
+// goroutineID parses the ID from a "goroutine 42 [chan receive]:" header
+func goroutineID(block []byte) (uint64, bool) {
+	header, _, _ := bytes.Cut(block, []byte("\n"))
+	fields := bytes.Fields(header)
+	if len(fields) < 2 || string(fields[0]) != "goroutine" {
+		return 0, false
+	}
+	id, err := strconv.ParseUint(string(fields[1]), 10, 64)
+	return id, err == nil
+}
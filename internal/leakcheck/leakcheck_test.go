@@ -0,0 +1,73 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package leakcheck
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGoroutines(t *testing.T) {
+	base := Take()
+
+	stop := make(chan struct{})
+	exited := make(chan struct{})
+	go func() {
+		defer close(exited)
+		<-stop
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	leaked := Goroutines(ctx, base)
+	found := false
+	for _, stack := range leaked {
+		found = found || strings.Contains(stack, "TestGoroutines")
+	}
+	if !found {
+		t.Fatalf("Goroutines() = %v, want the blocked goroutine", leaked)
+	}
+
+	close(stop)
+	<-exited
+	ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if leaked := Goroutines(ctx, base); len(leaked) != 0 {
+		t.Errorf("Goroutines() = %v after the goroutine exited", leaked)
+	}
+}
+
+func TestGoroutineID(t *testing.T) {
+	tests := []struct {
+		block  string
+		wantID uint64
+		wantOK bool
+	}{
+		{block: "goroutine 42 [chan receive]:\nmain.main()", wantID: 42, wantOK: true},
+		{block: "goroutine x [running]:", wantOK: false},
+		{block: "", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		id, ok := goroutineID([]byte(tt.block))
+		if id != tt.wantID || ok != tt.wantOK {
+			t.Errorf("goroutineID(%q) = %d, %v, want %d, %v", tt.block, id, ok, tt.wantID, tt.wantOK)
+		}
+	}
+}
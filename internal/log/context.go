@@ -0,0 +1,75 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package log
+
+import (
+	"context"
+	"sync"
+)
+
+var (
+	mu            sync.RWMutex
+	defaultLogger *Logger
+)
+
+type contextKey struct{}
+
+// WithContext returns a copy of ctx carrying logger, so that code handling
+// a request logs with the request's fields
+func WithContext(ctx context.Context, logger *Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, logger)
+}
+
+// FromContext returns the logger carried by ctx, or the default logger
+// when it carries none
+func FromContext(ctx context.Context) *Logger {
+	return FromContextOr(ctx, Default())
+}
+
+// FromContextOr returns the logger carried by ctx, or fallback when it
+// carries none
+func FromContextOr(ctx context.Context, fallback *Logger) *Logger {
+	if l, ok := ctx.Value(contextKey{}).(*Logger); ok {
+		return l
+	}
+	return fallback
+}
+
+// Default returns the logger installed by SetDefault, or one created by
+// NewDefault when none is installed
+func Default() *Logger {
+	mu.RLock()
+	l := defaultLogger
+	mu.RUnlock()
+	if l != nil {
+		return l
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if defaultLogger == nil {
+		defaultLogger = NewDefault()
+	}
+	return defaultLogger
+}
+
+// SetDefault installs l as the logger of contexts that carry none
+func SetDefault(l *Logger) {
+	mu.Lock()
+	defaultLogger = l
+	mu.Unlock()
+}
@@ -17,12 +17,19 @@
 package log
 
 import (
+	"fmt"
+	"net/http"
+
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 )
 
 // Logger wraps zap logger
 type Logger struct {
 	*zap.Logger
+	// level is shared by the loggers derived with With and Named, so that
+	// SetLevel applies to all of them
+	level *zap.AtomicLevel
 }
 
 // Config represents logger configuration
@@ -68,23 +75,67 @@ func New(cfg *Config) (*Logger, error) {
 		return nil, err
 	}
 
-	return &Logger{Logger: logger}, nil
+	return &Logger{Logger: logger, level: &zapConfig.Level}, nil
 }
 
 // NewDefault creates a default logger
 func NewDefault() *Logger {
-	logger, _ := zap.NewProduction()
-	return &Logger{Logger: logger}
+	zapConfig := zap.NewProductionConfig()
+	logger, _ := zapConfig.Build()
+	return &Logger{Logger: logger, level: &zapConfig.Level}
 }
 
 // With creates a child logger with additional fields
 func (l *Logger) With(fields ...zap.Field) *Logger {
-	return &Logger{Logger: l.Logger.With(fields...)}
+	return &Logger{Logger: l.Logger.With(fields...), level: l.level}
 }
 
 // Named creates a named logger
 func (l *Logger) Named(name string) *Logger {
-	return &Logger{Logger: l.Logger.Named(name)}
+	return &Logger{Logger: l.Logger.Named(name), level: l.level}
+}
+
+// Level returns the current minimum level, or an empty string when the
+// logger was not created by New or NewDefault
+func (l *Logger) Level() string {
+	if l.level == nil {
+		return ""
+	}
+	return l.level.Level().String()
+}
+
+// SetLevel changes the minimum level of the logger and of every logger
+// derived from it, without rebuilding them
+func (l *Logger) SetLevel(level string) error {
+	if l.level == nil {
+		return fmt.Errorf("logger level cannot be changed")
+	}
+	lvl, err := zapcore.ParseLevel(level)
+	if err != nil {
+		return err
+	}
+	l.level.SetLevel(lvl)
+	return nil
+}
+
+// AtomicLevel returns the level shared by the logger and every logger
+// derived from it
+func (l *Logger) AtomicLevel() (zap.AtomicLevel, bool) {
+	if l.level == nil {
+		return zap.AtomicLevel{}, false
+	}
+	return *l.level, true
+}
+
+// LevelHandler serves the logger's level over HTTP: GET returns it as
+// {"level":"info"} and PUT with the same body changes it
+func (l *Logger) LevelHandler() http.Handler {
+	if l.level == nil {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "logger level cannot be changed", http.StatusNotImplemented)
+		})
+	}
+	return l.level
 }
 
 // String creates a string field for structured logging
@@ -17,6 +17,10 @@
 package log
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 )
 
@@ -113,6 +117,76 @@ func TestLoggerMethods(t *testing.T) {
 	}
 }
 
+func TestSetLevel(t *testing.T) {
+	logger, err := New(&Config{Level: "info", Format: "json"})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	child := logger.Named("child").With(String("key", "value"))
+
+	if err := logger.SetLevel("debug"); err != nil {
+		t.Fatalf("SetLevel() error = %v", err)
+	}
+	if got := child.Level(); got != "debug" {
+		t.Errorf("child Level() = %q, want debug", got)
+	}
+	if !child.Core().Enabled(-1) {
+		t.Error("child logger should log at debug level")
+	}
+
+	if err := logger.SetLevel("verbose"); err == nil {
+		t.Error("SetLevel(verbose) should fail")
+	}
+	if got := logger.Level(); got != "debug" {
+		t.Errorf("Level() = %q after invalid SetLevel, want debug", got)
+	}
+
+	if err := (&Logger{}).SetLevel("info"); err == nil {
+		t.Error("SetLevel() should fail on a logger without an atomic level")
+	}
+}
+
+func TestLevelHandler(t *testing.T) {
+	logger, err := New(&Config{Level: "info", Format: "json"})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	child := logger.Named("child")
+	handler := logger.LevelHandler()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/", strings.NewReader(`{"level":"debug"}`)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("PUT status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	if got := child.Level(); got != "debug" {
+		t.Errorf("child Level() = %q after PUT, want debug", got)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/", strings.NewReader(`{"level":"verbose"}`)))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("PUT invalid level status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if !strings.Contains(rec.Body.String(), `"debug"`) {
+		t.Errorf("GET body = %q, want the debug level", rec.Body.String())
+	}
+
+	level, ok := child.AtomicLevel()
+	if !ok || level.Level().String() != "debug" {
+		t.Errorf("AtomicLevel() = %v, %v", level, ok)
+	}
+
+	rec = httptest.NewRecorder()
+	(&Logger{}).LevelHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusNotImplemented {
+		t.Errorf("status without an atomic level = %d, want %d", rec.Code, http.StatusNotImplemented)
+	}
+}
+
 func TestFieldConstructors(t *testing.T) {
 	tests := []struct {
 		name string
@@ -163,3 +237,27 @@ func TestFieldConstructors(t *testing.T) {
 		})
 	}
 }
+
+func TestContext(t *testing.T) {
+	fallback := NewDefault()
+	SetDefault(fallback)
+	defer SetDefault(nil)
+	scoped := fallback.With(String("request_id", "req-1"))
+
+	if got := FromContext(context.Background()); got != fallback {
+		t.Error("FromContext() without a logger did not return the default logger")
+	}
+	if got := FromContext(WithContext(context.Background(), scoped)); got != scoped {
+		t.Error("FromContext() did not return the context's logger")
+	}
+
+	other := NewDefault()
+	if got := FromContextOr(context.Background(), other); got != other {
+		t.Error("FromContextOr() without a logger did not return the fallback")
+	}
+
+	SetDefault(nil)
+	if Default() == nil {
+		t.Error("Default() without an installed logger = nil")
+	}
+}
@@ -0,0 +1,53 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package mail
+
+import (
+	"context"
+
+	"github.com/kevindiu/monorepo-go-example/internal/jobs"
+)
+
+// JobType is the job type of deferred email
+const JobType = "email.send"
+
+// Queue is the job queue email is sent from
+const Queue = "email"
+
+// Enqueue defers sending msg to the job workers. Use the caller's
+// transaction as exec so the email is only sent if it commits.
+func Enqueue(ctx context.Context, exec jobs.Execer, msg *Message, opts ...jobs.Option) (*jobs.Job, error) {
+	if err := msg.Validate(); err != nil {
+		return nil, err
+	}
+	return jobs.Enqueue(ctx, exec, JobType, msg, append([]jobs.Option{jobs.WithQueue(Queue)}, opts...)...)
+}
+
+// NewJobHandler returns the job handler that sends deferred email with
+// sender. Invalid messages are dead-lettered rather than retried.
+func NewJobHandler(sender Sender) jobs.Handler {
+	return func(ctx context.Context, job *jobs.Job) error {
+		var msg Message
+		if err := job.Decode(&msg); err != nil {
+			return err
+		}
+		if err := msg.Validate(); err != nil {
+			return jobs.Permanent(err)
+		}
+		return sender.Send(ctx, &msg)
+	}
+}
@@ -0,0 +1,114 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package mail sends email over SMTP, either directly or deferred through
+// the job queue.
+package mail
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	netmail "net/mail"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/config"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+)
+
+// Message is a plain text email
+type Message struct {
+	To      []string `json:"to"`
+	Subject string   `json:"subject"`
+	Body    string   `json:"body"`
+}
+
+// Validate checks that the message has valid recipients and a subject that
+// cannot inject headers
+func (m *Message) Validate() error {
+	if len(m.To) == 0 {
+		return errors.WithCode(errors.New("at least one recipient is required"), errors.CodeInvalidInput)
+	}
+	for _, to := range m.To {
+		if _, err := netmail.ParseAddress(to); err != nil {
+			return errors.WithCode(errors.Newf("invalid recipient %q", to), errors.CodeInvalidInput)
+		}
+	}
+	if m.Subject == "" {
+		return errors.WithCode(errors.New("subject is required"), errors.CodeInvalidInput)
+	}
+	if strings.ContainsAny(m.Subject, "\r\n") {
+		return errors.WithCode(errors.New("subject must be a single line"), errors.CodeInvalidInput)
+	}
+	return nil
+}
+
+// Sender delivers email
+type Sender interface {
+	Send(ctx context.Context, msg *Message) error
+}
+
+// SMTPSender delivers email through an SMTP relay
+type SMTPSender struct {
+	addr string
+	from string
+	auth smtp.Auth
+}
+
+// NewSMTPSender creates a sender for the configured relay. Authentication
+// is only used when a username is set.
+func NewSMTPSender(cfg *config.Mail) *SMTPSender {
+	s := &SMTPSender{
+		addr: fmt.Sprintf("%s:%d", cfg.Host, cfg.Port),
+		from: cfg.From,
+	}
+	if cfg.Username != "" {
+		s.auth = smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.Host)
+	}
+	return s
+}
+
+// Send delivers msg. net/smtp does not take a context, so cancellation is
+// only checked before sending.
+func (s *SMTPSender) Send(ctx context.Context, msg *Message) error {
+	if err := msg.Validate(); err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if err := smtp.SendMail(s.addr, s.auth, s.from, msg.To, s.render(msg, time.Now())); err != nil {
+		return errors.WithCode(errors.Wrap(err, "failed to send email"), errors.CodeUnavailable)
+	}
+	return nil
+}
+
+// render builds the RFC 5322 message
+func (s *SMTPSender) render(msg *Message, date time.Time) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", s.from)
+	fmt.Fprintf(&buf, "To: %s\r\n", strings.Join(msg.To, ", "))
+	fmt.Fprintf(&buf, "Subject: %s\r\n", msg.Subject)
+	fmt.Fprintf(&buf, "Date: %s\r\n", date.Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	buf.WriteString("\r\n")
+	buf.WriteString(strings.ReplaceAll(strings.ReplaceAll(msg.Body, "\r\n", "\n"), "\n", "\r\n"))
+	return buf.Bytes()
+}
@@ -0,0 +1,118 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package mail
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/config"
+	"github.com/kevindiu/monorepo-go-example/internal/jobs"
+)
+
+// mockSender records sent messages
+type mockSender struct {
+	sent []*Message
+	err  error
+}
+
+func (m *mockSender) Send(ctx context.Context, msg *Message) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.sent = append(m.sent, msg)
+	return nil
+}
+
+func TestMessageValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		msg     Message
+		wantErr bool
+	}{
+		{name: "valid", msg: Message{To: []string{"a@example.com"}, Subject: "Hi", Body: "Hello"}},
+		{name: "no recipients", msg: Message{Subject: "Hi"}, wantErr: true},
+		{name: "bad recipient", msg: Message{To: []string{"not an address"}, Subject: "Hi"}, wantErr: true},
+		{name: "no subject", msg: Message{To: []string{"a@example.com"}}, wantErr: true},
+		{name: "header injection", msg: Message{To: []string{"a@example.com"}, Subject: "Hi\r\nBcc: x@example.com"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.msg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRender(t *testing.T) {
+	s := NewSMTPSender(&config.Mail{Host: "localhost", Port: 25, From: "no-reply@example.com"})
+	msg := &Message{To: []string{"a@example.com", "b@example.com"}, Subject: "Order shipped", Body: "line 1\nline 2"}
+
+	got := string(s.render(msg, time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)))
+
+	for _, want := range []string{
+		"From: no-reply@example.com\r\n",
+		"To: a@example.com, b@example.com\r\n",
+		"Subject: Order shipped\r\n",
+		"\r\n\r\nline 1\r\nline 2",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("render() = %q, missing %q", got, want)
+		}
+	}
+}
+
+func TestJobHandler(t *testing.T) {
+	valid, _ := json.Marshal(&Message{To: []string{"a@example.com"}, Subject: "Hi"})
+	invalid, _ := json.Marshal(&Message{Subject: "Hi"})
+
+	tests := []struct {
+		name          string
+		payload       []byte
+		sendErr       error
+		wantErr       bool
+		wantPermanent bool
+	}{
+		{name: "sent", payload: valid},
+		{name: "relay down", payload: valid, sendErr: errors.New("connection refused"), wantErr: true},
+		{name: "invalid message", payload: invalid, wantErr: true, wantPermanent: true},
+		{name: "malformed payload", payload: []byte(`[`), wantErr: true, wantPermanent: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sender := &mockSender{err: tt.sendErr}
+			err := NewJobHandler(sender)(context.Background(), &jobs.Job{Type: JobType, Payload: tt.payload})
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("handler error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if jobs.IsPermanent(err) != tt.wantPermanent {
+				t.Errorf("IsPermanent() = %v, want %v", jobs.IsPermanent(err), tt.wantPermanent)
+			}
+			if !tt.wantErr && len(sender.sent) != 1 {
+				t.Errorf("sent %d messages, want 1", len(sender.sent))
+			}
+		})
+	}
+}
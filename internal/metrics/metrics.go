@@ -0,0 +1,217 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package metrics instruments gRPC and HTTP servers with Prometheus metrics
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kevindiu/monorepo-go-example/internal/config"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// DefaultPath is the HTTP path metrics are served on
+const DefaultPath = "/metrics"
+
+// Metrics holds a service's metrics registry and its request metrics
+type Metrics struct {
+	// Registry gathers the request metrics, the runtime collectors when
+	// enabled, and anything else registered by the service
+	Registry *prometheus.Registry
+	// Path is the HTTP path the registry is served on
+	Path string
+	// Port serves the registry on its own listener when not zero, so that
+	// metrics can be scraped without exposing the API port
+	Port int
+	// Namespace prefixes the metric names
+	Namespace string
+	enabled   bool
+
+	grpcHandled  *prometheus.CounterVec
+	grpcDuration *prometheus.HistogramVec
+	grpcInFlight *prometheus.GaugeVec
+	httpRequests *prometheus.CounterVec
+	httpDuration *prometheus.HistogramVec
+	httpInFlight *prometheus.GaugeVec
+}
+
+// New creates the metrics of a service from the observability config
+func New(cfg *config.Observability) *Metrics {
+	m := &Metrics{
+		Registry:  prometheus.NewRegistry(),
+		Path:      cfg.MetricsPath,
+		Port:      cfg.MetricsPort,
+		Namespace: cfg.MetricsNamespace,
+		enabled:   cfg.MetricsEnabled,
+		grpcHandled: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: cfg.MetricsNamespace,
+			Name:      "grpc_server_handled_total",
+			Help:      "Number of gRPC calls completed, by method and status code.",
+		}, []string{"method", "code"}),
+		grpcDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: cfg.MetricsNamespace,
+			Name:      "grpc_server_handling_seconds",
+			Help:      "gRPC call handling time.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method"}),
+		grpcInFlight: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: cfg.MetricsNamespace,
+			Name:      "grpc_server_in_flight_requests",
+			Help:      "Number of gRPC calls being handled, by method.",
+		}, []string{"method"}),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: cfg.MetricsNamespace,
+			Name:      "http_requests_total",
+			Help:      "Number of HTTP requests completed, by method, route and status code.",
+		}, []string{"method", "route", "code"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: cfg.MetricsNamespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "HTTP request handling time.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route"}),
+		httpInFlight: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: cfg.MetricsNamespace,
+			Name:      "http_requests_in_flight",
+			Help:      "Number of HTTP requests being handled, by method and route.",
+		}, []string{"method", "route"}),
+	}
+	if m.Path == "" {
+		m.Path = DefaultPath
+	}
+
+	m.Registry.MustRegister(
+		m.grpcHandled, m.grpcDuration, m.grpcInFlight,
+		m.httpRequests, m.httpDuration, m.httpInFlight,
+	)
+	if cfg.RuntimeMetrics {
+		m.Registry.MustRegister(
+			collectors.NewGoCollector(),
+			collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		)
+	}
+
+	return m
+}
+
+// Enabled reports whether metrics should be collected and served
+func (m *Metrics) Enabled() bool {
+	return m != nil && m.enabled
+}
+
+// UnaryServerInterceptor records the outcome and duration of gRPC calls
+func (m *Metrics) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		inFlight := m.grpcInFlight.WithLabelValues(info.FullMethod)
+		inFlight.Inc()
+		defer inFlight.Dec()
+
+		start := time.Now()
+		resp, err := handler(ctx, req)
+
+		m.grpcDuration.WithLabelValues(info.FullMethod).Observe(time.Since(start).Seconds())
+		m.grpcHandled.WithLabelValues(info.FullMethod, status.Code(err).String()).Inc()
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor records the outcome and duration of gRPC streams.
+// A stream is in flight, and its duration measured, until the handler
+// returns.
+func (m *Metrics) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		inFlight := m.grpcInFlight.WithLabelValues(info.FullMethod)
+		inFlight.Inc()
+		defer inFlight.Dec()
+
+		start := time.Now()
+		err := handler(srv, ss)
+
+		m.grpcDuration.WithLabelValues(info.FullMethod).Observe(time.Since(start).Seconds())
+		m.grpcHandled.WithLabelValues(info.FullMethod, status.Code(err).String()).Inc()
+		return err
+	}
+}
+
+// Middleware records the outcome and duration of HTTP requests
+func (m *Metrics) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route := Route(r.URL.Path)
+		inFlight := m.httpInFlight.WithLabelValues(r.Method, route)
+		inFlight.Inc()
+		defer inFlight.Dec()
+
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, code: http.StatusOK}
+		next.ServeHTTP(rec, r)
+
+		m.httpDuration.WithLabelValues(r.Method, route).Observe(time.Since(start).Seconds())
+		m.httpRequests.WithLabelValues(r.Method, route, strconv.Itoa(rec.code)).Inc()
+	})
+}
+
+// Route collapses the ID segments of path into "{id}" so that every order
+// or user shares one route label
+func Route(path string) string {
+	segments := strings.Split(path, "/")
+	for i, s := range segments {
+		if isID(s) {
+			segments[i] = "{id}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// isID reports whether a path segment is a UUID or a number
+func isID(segment string) bool {
+	if segment == "" {
+		return false
+	}
+	if _, err := uuid.Parse(segment); err == nil {
+		return true
+	}
+	_, err := strconv.ParseUint(segment, 10, 64)
+	return err == nil
+}
+
+// statusRecorder captures the status code written to a response
+type statusRecorder struct {
+	http.ResponseWriter
+	code        int
+	wroteHeader bool
+}
+
+func (r *statusRecorder) WriteHeader(code int) {
+	if !r.wroteHeader {
+		r.code = code
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(code)
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
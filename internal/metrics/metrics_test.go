@@ -0,0 +1,163 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kevindiu/monorepo-go-example/internal/config"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRoute(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{path: "/v1/orders", want: "/v1/orders"},
+		{path: "/v1/orders/3f1c8a52-58b4-4a43-9d0e-1a2b3c4d5e6f", want: "/v1/orders/{id}"},
+		{path: "/v1/users/42/orders", want: "/v1/users/{id}/orders"},
+		{path: "/v1/products/sku-1/recommendations", want: "/v1/products/sku-1/recommendations"},
+		{path: "/", want: "/"},
+	}
+
+	for _, tt := range tests {
+		if got := Route(tt.path); got != tt.want {
+			t.Errorf("Route(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	m := New(&config.Observability{MetricsEnabled: true})
+	interceptor := m.UnaryServerInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/order.v1.OrderService/GetOrder"}
+
+	ok := func(ctx context.Context, req interface{}) (interface{}, error) { return "ok", nil }
+	notFound := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, status.Error(codes.NotFound, "order not found")
+	}
+	interceptor(context.Background(), nil, info, ok)
+	interceptor(context.Background(), nil, info, notFound)
+	interceptor(context.Background(), nil, info, notFound)
+
+	if got := testutil.ToFloat64(m.grpcHandled.WithLabelValues(info.FullMethod, "OK")); got != 1 {
+		t.Errorf("OK calls = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(m.grpcHandled.WithLabelValues(info.FullMethod, "NotFound")); got != 2 {
+		t.Errorf("NotFound calls = %v, want 2", got)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	m := New(&config.Observability{MetricsEnabled: true})
+	handler := m.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/42") {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+
+	for _, path := range []string{"/v1/orders/7", "/v1/orders/8", "/v1/orders/42"} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	if got := testutil.ToFloat64(m.httpRequests.WithLabelValues(http.MethodGet, "/v1/orders/{id}", "200")); got != 2 {
+		t.Errorf("200 responses = %v, want 2", got)
+	}
+	if got := testutil.ToFloat64(m.httpRequests.WithLabelValues(http.MethodGet, "/v1/orders/{id}", "404")); got != 1 {
+		t.Errorf("404 responses = %v, want 1", got)
+	}
+}
+
+func TestRuntimeCollectors(t *testing.T) {
+	tests := []struct {
+		name    string
+		runtime bool
+		want    bool
+	}{
+		{name: "enabled", runtime: true, want: true},
+		{name: "disabled", runtime: false, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := New(&config.Observability{MetricsEnabled: true, RuntimeMetrics: tt.runtime})
+			families, err := m.Registry.Gather()
+			if err != nil {
+				t.Fatalf("Gather() error = %v", err)
+			}
+			found := false
+			for _, f := range families {
+				if f.GetName() == "go_goroutines" {
+					found = true
+				}
+			}
+			if found != tt.want {
+				t.Errorf("go_goroutines registered = %v, want %v", found, tt.want)
+			}
+		})
+	}
+}
+
+func TestStreamServerInterceptor(t *testing.T) {
+	m := New(&config.Observability{MetricsEnabled: true})
+	interceptor := m.StreamServerInterceptor()
+	info := &grpc.StreamServerInfo{FullMethod: "/order.v1.OrderService/WatchOrder", IsServerStream: true}
+
+	var inFlight float64
+	handler := func(srv interface{}, stream grpc.ServerStream) error {
+		inFlight = testutil.ToFloat64(m.grpcInFlight.WithLabelValues(info.FullMethod))
+		return status.Error(codes.Canceled, "client went away")
+	}
+	interceptor(nil, nil, info, handler)
+
+	if inFlight != 1 {
+		t.Errorf("in-flight streams during the call = %v, want 1", inFlight)
+	}
+	if got := testutil.ToFloat64(m.grpcInFlight.WithLabelValues(info.FullMethod)); got != 0 {
+		t.Errorf("in-flight streams after the call = %v, want 0", got)
+	}
+	if got := testutil.ToFloat64(m.grpcHandled.WithLabelValues(info.FullMethod, "Canceled")); got != 1 {
+		t.Errorf("Canceled streams = %v, want 1", got)
+	}
+}
+
+func TestInFlight(t *testing.T) {
+	m := New(&config.Observability{MetricsEnabled: true})
+
+	var during float64
+	handler := m.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		during = testutil.ToFloat64(m.httpInFlight.WithLabelValues(http.MethodGet, "/v1/orders/{id}"))
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/v1/orders/7", nil))
+
+	if during != 1 {
+		t.Errorf("in-flight requests during the call = %v, want 1", during)
+	}
+	if got := testutil.ToFloat64(m.httpInFlight.WithLabelValues(http.MethodGet, "/v1/orders/{id}")); got != 0 {
+		t.Errorf("in-flight requests after the call = %v, want 0", got)
+	}
+}
@@ -18,18 +18,27 @@ package middleware
 
 import (
 	"context"
+	"fmt"
+	"runtime/debug"
+	"strings"
 	"time"
 
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
 	"github.com/kevindiu/monorepo-go-example/internal/log"
+	"github.com/kevindiu/monorepo-go-example/internal/reporting"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
 )
 
 // LoggingInterceptor logs gRPC calls
 func LoggingInterceptor(logger *zap.Logger) grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		logger := withRequestID(ctx, logger)
 		start := time.Now()
 
 		logger.Info("gRPC call started",
@@ -60,15 +69,84 @@ func LoggingInterceptor(logger *zap.Logger) grpc.UnaryServerInterceptor {
 	}
 }
 
-// RecoveryInterceptor recovers from panics in gRPC handlers
+// PayloadLoggingInterceptor logs the request and response messages of gRPC
+// calls at debug level. Payloads hold personal data, so it is meant for
+// development only.
+func PayloadLoggingInterceptor(logger *zap.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if !logger.Core().Enabled(zap.DebugLevel) {
+			return handler(ctx, req)
+		}
+
+		logger.Debug("gRPC request",
+			zap.String("method", info.FullMethod),
+			zap.String("payload", payload(req)),
+		)
+		resp, err := handler(ctx, req)
+		if err == nil {
+			logger.Debug("gRPC response",
+				zap.String("method", info.FullMethod),
+				zap.String("payload", payload(resp)),
+			)
+		}
+		return resp, err
+	}
+}
+
+// payload formats a message as single-line JSON, with secrets cleared
+func payload(msg interface{}) string {
+	m, ok := msg.(proto.Message)
+	if !ok {
+		return fmt.Sprint(msg)
+	}
+	m = proto.Clone(m)
+	clearSecrets(m.ProtoReflect())
+	b, err := protojson.Marshal(m)
+	if err != nil {
+		return err.Error()
+	}
+	return string(b)
+}
+
+// clearSecrets clears the secret fields of m and its nested messages
+func clearSecrets(m protoreflect.Message) {
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case fd.Message() == nil && isSecret(string(fd.Name())):
+			m.Clear(fd)
+		case fd.Message() == nil || fd.IsMap():
+		case fd.IsList():
+			for i := 0; i < v.List().Len(); i++ {
+				clearSecrets(v.List().Get(i).Message())
+			}
+		default:
+			clearSecrets(v.Message())
+		}
+		return true
+	})
+}
+
+// isSecret reports whether a field holds a password, a webhook secret, the
+// plaintext key of CreateAPIKeyResponse or an issued token
+func isSecret(name string) bool {
+	switch name {
+	case "key", "access_token", "refresh_token":
+		return true
+	}
+	return strings.Contains(name, "password") || strings.Contains(name, "secret")
+}
+
+// RecoveryInterceptor recovers from panics in gRPC handlers and reports
+// them to the default error reporter
 func RecoveryInterceptor(logger *zap.Logger) grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
 		defer func() {
 			if r := recover(); r != nil {
-				logger.Error("gRPC handler panicked",
+				withRequestID(ctx, logger).Error("gRPC handler panicked",
 					zap.String("method", info.FullMethod),
 					zap.Any("panic", r),
 				)
+				reporting.Default().CapturePanic(ctx, r, debug.Stack(), map[string]string{"method": info.FullMethod})
 				err = status.Errorf(codes.Internal, "internal server error")
 			}
 		}()
@@ -77,6 +155,69 @@ func RecoveryInterceptor(logger *zap.Logger) grpc.UnaryServerInterceptor {
 	}
 }
 
+// StreamRecoveryInterceptor is the streaming counterpart of
+// UnaryRecoveryInterceptor
+func StreamRecoveryInterceptor(logger *log.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				logger.Error("gRPC handler panicked",
+					log.String("method", info.FullMethod),
+					log.Any("panic", r),
+				)
+				reporting.Default().CapturePanic(ss.Context(), r, debug.Stack(), map[string]string{"method": info.FullMethod})
+				err = status.Errorf(codes.Internal, "internal server error")
+			}
+		}()
+
+		return handler(srv, ss)
+	}
+}
+
+// ErrorInterceptor converts the errors handlers return to gRPC statuses
+// with errors.ToGRPCStatus, so that clients see NotFound rather than
+// Unknown. Errors hidden behind a generic Internal status are logged in
+// full first.
+func ErrorInterceptor(logger *zap.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		resp, err := handler(ctx, req)
+		if err != nil {
+			err = toStatusError(withRequestID(ctx, logger), info.FullMethod, err)
+		}
+		return resp, err
+	}
+}
+
+// StreamErrorInterceptor is the streaming counterpart of ErrorInterceptor
+func StreamErrorInterceptor(logger *log.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := handler(srv, ss); err != nil {
+			return toStatusError(withRequestID(ss.Context(), logger.Logger), info.FullMethod, err)
+		}
+		return nil
+	}
+}
+
+// toStatusError converts err to a gRPC status error
+func toStatusError(logger *zap.Logger, method string, err error) error {
+	st := errors.ToGRPCStatus(err)
+	if st.Code() == codes.Internal && st.Message() != err.Error() {
+		logger.Error("gRPC call failed with an internal error",
+			zap.String("method", method),
+			zap.Error(err),
+		)
+	}
+	return st.Err()
+}
+
+// withRequestID adds the request ID of ctx to logger, when there is one
+func withRequestID(ctx context.Context, logger *zap.Logger) *zap.Logger {
+	if id := RequestIDFromContext(ctx); id != "" {
+		return logger.With(zap.String("request_id", id))
+	}
+	return logger
+}
+
 // ValidationInterceptor validates incoming requests
 func ValidationInterceptor() grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
@@ -91,6 +232,16 @@ func UnaryLoggingInterceptor(logger *log.Logger) grpc.UnaryServerInterceptor {
 	return LoggingInterceptor(logger.Logger)
 }
 
+// UnaryPayloadLoggingInterceptor is a wrapper around PayloadLoggingInterceptor that accepts log.Logger
+func UnaryPayloadLoggingInterceptor(logger *log.Logger) grpc.UnaryServerInterceptor {
+	return PayloadLoggingInterceptor(logger.Logger)
+}
+
+// UnaryErrorInterceptor is a wrapper around ErrorInterceptor that accepts log.Logger
+func UnaryErrorInterceptor(logger *log.Logger) grpc.UnaryServerInterceptor {
+	return ErrorInterceptor(logger.Logger)
+}
+
 // UnaryRecoveryInterceptor is a wrapper around RecoveryInterceptor that accepts log.Logger
 func UnaryRecoveryInterceptor(logger *log.Logger) grpc.UnaryServerInterceptor {
 	return RecoveryInterceptor(logger.Logger)
@@ -0,0 +1,105 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package middleware
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	apikeyv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/apikey/v1"
+	authv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/auth/v1"
+	userv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/user/v1"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/internal/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestUnaryErrorInterceptor(t *testing.T) {
+	interceptor := UnaryErrorInterceptor(log.NewDefault())
+	info := &grpc.UnaryServerInfo{FullMethod: "/user.v1.UserService/GetUser"}
+
+	tests := []struct {
+		name     string
+		err      error
+		wantCode codes.Code
+	}{
+		{name: "ok", wantCode: codes.OK},
+		{name: "not found", err: errors.WithCode(errors.New("user not found"), errors.CodeNotFound), wantCode: codes.NotFound},
+		{name: "uncoded", err: errors.New("pq: connection refused"), wantCode: codes.Internal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := interceptor(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+				return nil, tt.err
+			})
+			if got := status.Code(err); got != tt.wantCode {
+				t.Errorf("code = %v, want %v", got, tt.wantCode)
+			}
+			if _, ok := status.FromError(err); !ok {
+				t.Errorf("error %v is not a gRPC status", err)
+			}
+		})
+	}
+}
+
+func TestPayloadClearsSecrets(t *testing.T) {
+	req := &userv1.ChangePasswordRequest{Id: "user-1", CurrentPassword: "old secret pw", NewPassword: "new secret pw"}
+
+	got := payload(req)
+	if strings.Contains(got, "secret") {
+		t.Errorf("payload() = %s, want passwords cleared", got)
+	}
+	if !strings.Contains(got, "user-1") {
+		t.Errorf("payload() = %s, want the other fields kept", got)
+	}
+	if req.GetNewPassword() == "" {
+		t.Error("payload() cleared the password of the request itself")
+	}
+}
+
+func TestPayloadClearsAPIKeys(t *testing.T) {
+	resp := &apikeyv1.CreateAPIKeyResponse{
+		ApiKey: &apikeyv1.APIKey{Id: "key-1", Prefix: "ak_1234"},
+		Key:    "ak_1234secretsecret",
+	}
+
+	got := payload(resp)
+	if strings.Contains(got, "secret") {
+		t.Errorf("payload() = %s, want the key cleared", got)
+	}
+	if !strings.Contains(got, "key-1") || !strings.Contains(got, "ak_1234") {
+		t.Errorf("payload() = %s, want the key's metadata kept", got)
+	}
+}
+
+func TestPayloadClearsTokens(t *testing.T) {
+	resp := &authv1.LoginResponse{
+		Tokens: &authv1.Tokens{AccessToken: "eyJsecret", RefreshToken: "rt_secret", TokenType: "Bearer"},
+	}
+
+	got := payload(resp)
+	if strings.Contains(got, "secret") {
+		t.Errorf("payload() = %s, want the tokens cleared", got)
+	}
+	if !strings.Contains(got, "Bearer") {
+		t.Errorf("payload() = %s, want the other fields kept", got)
+	}
+}
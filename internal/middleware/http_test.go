@@ -0,0 +1,92 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestRequestID(t *testing.T) {
+	tests := []struct {
+		name     string
+		incoming string
+		wantSame bool
+	}{
+		{name: "caller ID kept", incoming: "support-1234", wantSame: true},
+		{name: "missing ID generated"},
+		{name: "unsafe ID replaced", incoming: "bad id\n"},
+		{name: "long ID replaced", incoming: strings.Repeat("a", maxRequestIDLength+1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var seen string
+			handler := RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				seen = RequestIDFromContext(r.Context())
+				http.Error(w, "failed", http.StatusBadGateway)
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/v1/orders", nil)
+			if tt.incoming != "" {
+				req.Header.Set(RequestIDHeader, tt.incoming)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			got := rec.Header().Get(RequestIDHeader)
+			if got == "" || got != seen {
+				t.Errorf("response ID = %q, handler saw %q", got, seen)
+			}
+			if (got == tt.incoming) != tt.wantSame {
+				t.Errorf("response ID = %q, incoming %q, want kept = %v", got, tt.incoming, tt.wantSame)
+			}
+		})
+	}
+}
+
+func TestTraceResponse(t *testing.T) {
+	prev := otel.GetTextMapPropagator()
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	defer otel.SetTextMapPropagator(prev)
+
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+	}))
+
+	rec := httptest.NewRecorder()
+	handler := TraceResponse(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx))
+
+	want := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	if got := rec.Header().Get("traceparent"); got != want {
+		t.Errorf("traceparent = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,135 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package middleware
+
+import (
+	"context"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/internal/ratelimit"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// RetryAfterMetadataKey carries the seconds until a throttled gRPC call
+// may be retried
+const RetryAfterMetadataKey = "retry-after"
+
+// RateLimit throttles requests by the key returned for each, answering 429
+// Too Many Requests with a Retry-After header once its quota is spent. A
+// nil limiter leaves requests unlimited.
+func RateLimit(limiter *ratelimit.Limiter, key func(r *http.Request) string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if limiter == nil {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			d := limiter.Allow(r.Context(), key(r))
+			if d.Limit > 0 {
+				w.Header().Set("X-RateLimit-Limit", strconv.Itoa(d.Limit))
+				w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(d.Remaining))
+			}
+			if !d.Allowed {
+				w.Header().Set("Retry-After", retryAfter(d.RetryAfter))
+				errors.WriteHTTP(w, status.Error(codes.ResourceExhausted, "rate limit exceeded"))
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// ClientIP returns the IP address of the client of r. With
+// trustForwardedFor it is the last X-Forwarded-For entry, the one added by
+// the proxy in front of the server; clients can forge the others.
+func ClientIP(r *http.Request, trustForwardedFor bool) string {
+	if trustForwardedFor {
+		if forwarded := r.Header.Values("X-Forwarded-For"); len(forwarded) > 0 {
+			entries := strings.Split(forwarded[len(forwarded)-1], ",")
+			if ip := strings.TrimSpace(entries[len(entries)-1]); ip != "" {
+				return ip
+			}
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// UnaryRateLimitInterceptor throttles calls by method with limiter, failing
+// them with ResourceExhausted and retry-after header metadata once the
+// quota of the method, or else of its service, is spent. A nil limiter
+// leaves calls unlimited.
+func UnaryRateLimitInterceptor(limiter *ratelimit.Limiter) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := allowMethod(ctx, limiter, info.FullMethod, func(md metadata.MD) error {
+			return grpc.SetHeader(ctx, md)
+		}); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamRateLimitInterceptor throttles streams like
+// UnaryRateLimitInterceptor throttles unary calls
+func StreamRateLimitInterceptor(limiter *ratelimit.Limiter) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := allowMethod(ss.Context(), limiter, info.FullMethod, ss.SetHeader); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+// allowMethod takes a token for fullMethod, or for its service when only
+// the service has a quota, and returns a ResourceExhausted error after
+// setting the retry-after header when throttled
+func allowMethod(ctx context.Context, limiter *ratelimit.Limiter, fullMethod string, setHeader func(metadata.MD) error) error {
+	if limiter == nil {
+		return nil
+	}
+	key := fullMethod
+	if !limiter.Overrides(key) {
+		key = fullMethod[:strings.LastIndex(fullMethod, "/")+1]
+		if !limiter.Overrides(key) {
+			return nil
+		}
+	}
+	d := limiter.Allow(ctx, key)
+	if d.Allowed {
+		return nil
+	}
+	// The header is best effort; the status still tells clients to back off
+	_ = setHeader(metadata.Pairs(RetryAfterMetadataKey, retryAfter(d.RetryAfter)))
+	return status.Errorf(codes.ResourceExhausted, "rate limit exceeded for %s", key)
+}
+
+// retryAfter formats a wait as whole seconds, rounded up
+func retryAfter(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
@@ -0,0 +1,165 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/config"
+	"github.com/kevindiu/monorepo-go-example/internal/ratelimit"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// headerStream records the headers set by interceptors
+type headerStream struct {
+	grpc.ServerTransportStream
+	header metadata.MD
+}
+
+func (s *headerStream) SetHeader(md metadata.MD) error {
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+func TestRateLimit(t *testing.T) {
+	limiter, err := ratelimit.New(&config.RateLimit{Requests: 1, Window: time.Minute}, nil, nil)
+	if err != nil {
+		t.Fatalf("ratelimit.New() error = %v", err)
+	}
+	defer limiter.Close()
+	h := RateLimit(limiter, func(r *http.Request) string {
+		return ClientIP(r, false)
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	tests := []struct {
+		remoteAddr string
+		wantStatus int
+	}{
+		{remoteAddr: "10.0.0.1:1234", wantStatus: http.StatusOK},
+		{remoteAddr: "10.0.0.1:5678", wantStatus: http.StatusTooManyRequests},
+		{remoteAddr: "10.0.0.2:1234", wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodGet, "/v1/orders", nil)
+		r.RemoteAddr = tt.remoteAddr
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+
+		if w.Code != tt.wantStatus {
+			t.Errorf("%s: status = %d, want %d", tt.remoteAddr, w.Code, tt.wantStatus)
+		}
+		if tt.wantStatus == http.StatusTooManyRequests && w.Header().Get("Retry-After") != "60" {
+			t.Errorf("%s: Retry-After = %q, want 60", tt.remoteAddr, w.Header().Get("Retry-After"))
+		}
+	}
+}
+
+func TestClientIP(t *testing.T) {
+	tests := []struct {
+		name              string
+		remoteAddr        string
+		forwardedFor      []string
+		trustForwardedFor bool
+		want              string
+	}{
+		{name: "remote address", remoteAddr: "10.0.0.1:1234", want: "10.0.0.1"},
+		{name: "ipv6", remoteAddr: "[::1]:1234", want: "::1"},
+		{name: "untrusted forwarded for", remoteAddr: "10.0.0.1:1234", forwardedFor: []string{"203.0.113.7"}, want: "10.0.0.1"},
+		{name: "forwarded for", remoteAddr: "10.0.0.1:1234", forwardedFor: []string{"203.0.113.7"}, trustForwardedFor: true, want: "203.0.113.7"},
+		{
+			name:              "forged forwarded for",
+			remoteAddr:        "10.0.0.1:1234",
+			forwardedFor:      []string{"198.51.100.1", "192.0.2.1, 203.0.113.7"},
+			trustForwardedFor: true,
+			want:              "203.0.113.7",
+		},
+		{name: "no forwarded for", remoteAddr: "10.0.0.1:1234", trustForwardedFor: true, want: "10.0.0.1"},
+	}
+
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.RemoteAddr = tt.remoteAddr
+		for _, v := range tt.forwardedFor {
+			r.Header.Add("X-Forwarded-For", v)
+		}
+		if got := ClientIP(r, tt.trustForwardedFor); got != tt.want {
+			t.Errorf("%s: ClientIP() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestUnaryRateLimitInterceptor(t *testing.T) {
+	limiter, err := ratelimit.NewMethods(&config.RateLimit{
+		Enabled: true,
+		Methods: []config.RateLimitQuota{
+			{Key: "/order.v1.OrderService/CreateOrder", Requests: 1, Window: time.Minute},
+			{Key: "/search.v1.SearchService/", Requests: 1, Window: 30 * time.Second},
+		},
+	}, nil, nil)
+	if err != nil {
+		t.Fatalf("ratelimit.NewMethods() error = %v", err)
+	}
+	defer limiter.Close()
+	interceptor := UnaryRateLimitInterceptor(limiter)
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) { return "ok", nil }
+
+	tests := []struct {
+		method         string
+		wantCode       codes.Code
+		wantRetryAfter string
+	}{
+		{method: "/order.v1.OrderService/CreateOrder", wantCode: codes.OK},
+		{method: "/order.v1.OrderService/CreateOrder", wantCode: codes.ResourceExhausted, wantRetryAfter: "60"},
+		{method: "/order.v1.OrderService/GetOrder", wantCode: codes.OK},
+		{method: "/order.v1.OrderService/GetOrder", wantCode: codes.OK},
+		{method: "/search.v1.SearchService/SearchOrders", wantCode: codes.OK},
+		{method: "/search.v1.SearchService/SearchUsers", wantCode: codes.ResourceExhausted, wantRetryAfter: "30"},
+	}
+
+	for i, tt := range tests {
+		stream := &headerStream{}
+		ctx := grpc.NewContextWithServerTransportStream(context.Background(), stream)
+		_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: tt.method}, handler)
+		if got := status.Code(err); got != tt.wantCode {
+			t.Errorf("#%d %s: code = %v, want %v", i+1, tt.method, got, tt.wantCode)
+		}
+		var got string
+		if v := stream.header.Get(RetryAfterMetadataKey); len(v) > 0 {
+			got = v[0]
+		}
+		if got != tt.wantRetryAfter {
+			t.Errorf("#%d %s: retry-after = %q, want %q", i+1, tt.method, got, tt.wantRetryAfter)
+		}
+	}
+}
+
+func TestUnaryRateLimitInterceptorWithoutLimiter(t *testing.T) {
+	interceptor := UnaryRateLimitInterceptor(nil)
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) { return "ok", nil }
+	info := &grpc.UnaryServerInfo{FullMethod: "/order.v1.OrderService/CreateOrder"}
+	if _, err := interceptor(context.Background(), nil, info, handler); err != nil {
+		t.Errorf("interceptor() error = %v, want nil", err)
+	}
+}
@@ -0,0 +1,105 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/kevindiu/monorepo-go-example/internal/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// RequestIDMetadataKey carries the ID of a request between services, in
+// the call metadata and in the response header
+const RequestIDMetadataKey = "x-request-id"
+
+// RequestIDMetadata returns the request ID assigned by RequestID as gRPC
+// metadata, for grpc-gateway's runtime.WithMetadata
+func RequestIDMetadata(_ context.Context, r *http.Request) metadata.MD {
+	id := RequestIDFromContext(r.Context())
+	if id == "" {
+		return nil
+	}
+	return metadata.Pairs(RequestIDMetadataKey, id)
+}
+
+// UnaryRequestIDInterceptor assigns each call an ID, the caller's
+// x-request-id when it is usable or a new UUID otherwise, and returns it in
+// the x-request-id response header. The call's context carries the ID for
+// RequestIDFromContext and a logger with request_id and method fields for
+// log.FromContext.
+func UnaryRequestIDInterceptor(logger *log.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, id := incomingRequestID(ctx, logger, info.FullMethod)
+		// The header is best effort; the ID is still logged
+		_ = grpc.SetHeader(ctx, metadata.Pairs(RequestIDMetadataKey, id))
+		return handler(ctx, req)
+	}
+}
+
+// StreamRequestIDInterceptor is the streaming counterpart of
+// UnaryRequestIDInterceptor
+func StreamRequestIDInterceptor(logger *log.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, id := incomingRequestID(ss.Context(), logger, info.FullMethod)
+		_ = ss.SetHeader(metadata.Pairs(RequestIDMetadataKey, id))
+		return handler(srv, &requestIDStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// UnaryClientRequestIDInterceptor sends the ID of the request being
+// handled with outgoing calls, so that the services they reach log it too.
+// Calls that already carry an ID are left untouched.
+func UnaryClientRequestIDInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if id := RequestIDFromContext(ctx); id != "" {
+			md, _ := metadata.FromOutgoingContext(ctx)
+			if len(md.Get(RequestIDMetadataKey)) == 0 {
+				ctx = metadata.AppendToOutgoingContext(ctx, RequestIDMetadataKey, id)
+			}
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+// requestIDStream overrides the context of a stream
+type requestIDStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *requestIDStream) Context() context.Context { return s.ctx }
+
+// incomingRequestID scopes ctx and a logger of the call to method to the
+// request ID in the incoming metadata, assigning a new one when it is
+// missing or unusable
+func incomingRequestID(ctx context.Context, logger *log.Logger, method string) (context.Context, string) {
+	var id string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(RequestIDMetadataKey); len(values) > 0 {
+			id = values[0]
+		}
+	}
+	if !validRequestID(id) {
+		id = uuid.New().String()
+	}
+	ctx = context.WithValue(ctx, requestIDKey{}, id)
+	return log.WithContext(ctx, logger.With(log.String("request_id", id), log.String("method", method))), id
+}
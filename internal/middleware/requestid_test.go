@@ -0,0 +1,124 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kevindiu/monorepo-go-example/internal/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestUnaryRequestIDInterceptor(t *testing.T) {
+	interceptor := UnaryRequestIDInterceptor(log.NewDefault())
+	info := &grpc.UnaryServerInfo{FullMethod: "/order.v1.OrderService/GetOrder"}
+
+	tests := []struct {
+		name     string
+		incoming string
+		wantKept bool
+	}{
+		{name: "caller id", incoming: "req-1", wantKept: true},
+		{name: "no id"},
+		{name: "unusable id", incoming: "bad id"},
+		{name: "too long", incoming: strings.Repeat("a", maxRequestIDLength+1)},
+	}
+
+	for _, tt := range tests {
+		ctx := context.Background()
+		if tt.incoming != "" {
+			ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(RequestIDMetadataKey, tt.incoming))
+		}
+		stream := &headerStream{}
+		ctx = grpc.NewContextWithServerTransportStream(ctx, stream)
+
+		var gotID string
+		var gotLogger bool
+		_, err := interceptor(ctx, nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			gotID = RequestIDFromContext(ctx)
+			gotLogger = log.FromContext(ctx) != log.Default()
+			return nil, nil
+		})
+		if err != nil {
+			t.Fatalf("%s: interceptor() error = %v", tt.name, err)
+		}
+		if gotID == "" || (gotID == tt.incoming) != tt.wantKept {
+			t.Errorf("%s: request ID = %q, want caller's %q kept %v", tt.name, gotID, tt.incoming, tt.wantKept)
+		}
+		if !gotLogger {
+			t.Errorf("%s: context carries no logger", tt.name)
+		}
+		if got := stream.header.Get(RequestIDMetadataKey); len(got) != 1 || got[0] != gotID {
+			t.Errorf("%s: response header = %v, want [%s]", tt.name, got, gotID)
+		}
+	}
+}
+
+func TestUnaryClientRequestIDInterceptor(t *testing.T) {
+	interceptor := UnaryClientRequestIDInterceptor()
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		wantSent []string
+	}{
+		{name: "no id", ctx: context.Background()},
+		{
+			name:     "request id",
+			ctx:      context.WithValue(context.Background(), requestIDKey{}, "req-1"),
+			wantSent: []string{"req-1"},
+		},
+		{
+			name: "explicit id",
+			ctx: metadata.AppendToOutgoingContext(
+				context.WithValue(context.Background(), requestIDKey{}, "req-1"), RequestIDMetadataKey, "req-2"),
+			wantSent: []string{"req-2"},
+		},
+	}
+
+	for _, tt := range tests {
+		var sent []string
+		invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			md, _ := metadata.FromOutgoingContext(ctx)
+			sent = md.Get(RequestIDMetadataKey)
+			return nil
+		}
+		if err := interceptor(tt.ctx, "/user.v1.UserService/GetUser", nil, nil, nil, invoker); err != nil {
+			t.Fatalf("%s: interceptor() error = %v", tt.name, err)
+		}
+		if strings.Join(sent, ",") != strings.Join(tt.wantSent, ",") {
+			t.Errorf("%s: sent %v, want %v", tt.name, sent, tt.wantSent)
+		}
+	}
+}
+
+func TestRequestIDMetadata(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/v1/orders", nil)
+	if md := RequestIDMetadata(context.Background(), r); md != nil {
+		t.Errorf("RequestIDMetadata() without an ID = %v, want nil", md)
+	}
+
+	r = r.WithContext(context.WithValue(r.Context(), requestIDKey{}, "req-1"))
+	if got := RequestIDMetadata(context.Background(), r).Get(RequestIDMetadataKey); len(got) != 1 || got[0] != "req-1" {
+		t.Errorf("RequestIDMetadata() = %v, want [req-1]", got)
+	}
+}
@@ -0,0 +1,202 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package money represents amounts exactly, as integers in the minor units
+// of their currency (cents for USD, yen for JPY)
+package money
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	moneypb "google.golang.org/genproto/googleapis/type/money"
+)
+
+// codePattern matches ISO 4217 currency codes
+var codePattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// exponents lists the ISO 4217 currencies whose minor unit is not a
+// hundredth of the major unit
+var exponents = map[string]int{
+	"BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "ISK": 0, "JPY": 0, "KMF": 0,
+	"KRW": 0, "PYG": 0, "RWF": 0, "UGX": 0, "UYI": 0, "VND": 0, "VUV": 0,
+	"XAF": 0, "XOF": 0, "XPF": 0,
+	"BHD": 3, "IQD": 3, "JOD": 3, "KWD": 3, "LYD": 3, "OMR": 3, "TND": 3,
+}
+
+// Normalize returns a currency code in canonical upper case, rejecting
+// anything that is not an ISO 4217 code
+func Normalize(code string) (string, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if !codePattern.MatchString(code) {
+		return "", errors.WithCode(errors.Newf("invalid currency code %q", code), errors.CodeInvalidInput)
+	}
+	return code, nil
+}
+
+// Exponent returns the number of decimal places of a currency's minor unit
+func Exponent(code string) int {
+	if e, ok := exponents[code]; ok {
+		return e
+	}
+	return 2
+}
+
+// scale returns how many minor units make a major unit of code
+func scale(code string) int64 {
+	s := int64(1)
+	for i := 0; i < Exponent(code); i++ {
+		s *= 10
+	}
+	return s
+}
+
+// FromMajor converts an amount in major units, as carried by float fields,
+// to minor units, rounding half away from zero
+func FromMajor(amount float64, code string) (int64, error) {
+	minor := math.Round(amount * float64(scale(code)))
+	if math.IsNaN(minor) || minor >= math.MaxInt64 || minor <= math.MinInt64 {
+		return 0, errors.WithCode(errors.Newf("amount %v is out of range", amount), errors.CodeInvalidInput)
+	}
+	return int64(minor), nil
+}
+
+// ToMajor converts minor units to major units, for float fields and
+// display only
+func ToMajor(minor int64, code string) float64 {
+	return float64(minor) / float64(scale(code))
+}
+
+// Format formats minor units as a decimal amount in major units, such as
+// "12.50" for 1250 USD
+func Format(minor int64, code string) string {
+	e := Exponent(code)
+	if e == 0 {
+		return strconv.FormatInt(minor, 10)
+	}
+	sign := ""
+	if minor < 0 {
+		sign = "-"
+	}
+	digits := strconv.FormatUint(absUint(minor), 10)
+	if len(digits) <= e {
+		digits = strings.Repeat("0", e-len(digits)+1) + digits
+	}
+	return sign + digits[:len(digits)-e] + "." + digits[len(digits)-e:]
+}
+
+// absUint returns the absolute value of n, which fits a uint64 even for
+// math.MinInt64
+func absUint(n int64) uint64 {
+	if n < 0 {
+		return uint64(-(n + 1)) + 1
+	}
+	return uint64(n)
+}
+
+// Parse parses a decimal amount in major units, such as "12.5", to minor
+// units. Amounts finer than the currency's minor unit are rejected rather
+// than rounded.
+func Parse(s, code string) (int64, error) {
+	invalid := errors.WithCode(errors.Newf("amount %q is not a valid %s amount", s, code), errors.CodeInvalidInput)
+
+	s = strings.TrimSpace(s)
+	sign := ""
+	if strings.HasPrefix(s, "-") {
+		sign = "-"
+	}
+	whole, frac, _ := strings.Cut(strings.TrimPrefix(s, "-"), ".")
+	e := Exponent(code)
+	if whole == "" || len(frac) > e || strings.ContainsAny(whole+frac, "+-") {
+		return 0, invalid
+	}
+	frac += strings.Repeat("0", e-len(frac))
+
+	minor, err := strconv.ParseInt(sign+whole+frac, 10, 64)
+	if err != nil {
+		return 0, invalid
+	}
+	return minor, nil
+}
+
+// Multiply returns minor times quantity, failing rather than overflowing
+func Multiply(minor int64, quantity int64) (int64, error) {
+	if minor == 0 || quantity == 0 {
+		return 0, nil
+	}
+	product := minor * quantity
+	if product/quantity != minor || (minor == -1 && quantity == math.MinInt64) || (quantity == -1 && minor == math.MinInt64) {
+		return 0, errors.WithCode(errors.New("amount is out of range"), errors.CodeInvalidInput)
+	}
+	return product, nil
+}
+
+// Percent returns percent of minor, rounded half away from zero
+func Percent(minor int64, percent float64) int64 {
+	return int64(math.Round(float64(minor) * percent / 100))
+}
+
+// Convert converts minor units of from to minor units of to at rate, the
+// number of major units of to that one major unit of from buys
+func Convert(minor int64, from, to string, rate float64) (int64, error) {
+	converted := math.Round(float64(minor) * rate * float64(scale(to)) / float64(scale(from)))
+	if math.IsNaN(converted) || converted >= math.MaxInt64 || converted <= math.MinInt64 {
+		return 0, errors.WithCode(errors.New("converted amount is out of range"), errors.CodeInvalidInput)
+	}
+	return int64(converted), nil
+}
+
+// ToProto converts minor units of code to a google.type.Money
+func ToProto(minor int64, code string) *moneypb.Money {
+	s := scale(code)
+	return &moneypb.Money{
+		CurrencyCode: code,
+		Units:        minor / s,
+		Nanos:        int32(minor % s * (1e9 / s)),
+	}
+}
+
+// FromProto converts a google.type.Money to minor units of code. A missing
+// currency code is taken to be code; another currency, or an amount finer
+// than the minor unit, is rejected.
+func FromProto(m *moneypb.Money, code string) (int64, error) {
+	if m == nil {
+		return 0, nil
+	}
+	if m.GetCurrencyCode() != "" && !strings.EqualFold(m.GetCurrencyCode(), code) {
+		return 0, errors.WithCode(errors.Newf("amounts must be in %s", code), errors.CodeInvalidInput)
+	}
+	units, nanos := m.GetUnits(), int64(m.GetNanos())
+	if nanos <= -1e9 || nanos >= 1e9 || (units > 0 && nanos < 0) || (units < 0 && nanos > 0) {
+		return 0, errors.WithCode(errors.New("invalid money nanos"), errors.CodeInvalidInput)
+	}
+	s := scale(code)
+	if nanos%(1e9/s) != 0 {
+		return 0, errors.WithCode(errors.Newf("amount is finer than the %s minor unit", code), errors.CodeInvalidInput)
+	}
+	minor, err := Multiply(units, s)
+	if err != nil {
+		return 0, err
+	}
+	sum := minor + nanos/(1e9/s)
+	if (nanos > 0 && sum < minor) || (nanos < 0 && sum > minor) {
+		return 0, errors.WithCode(errors.New("amount is out of range"), errors.CodeInvalidInput)
+	}
+	return sum, nil
+}
@@ -0,0 +1,171 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package money
+
+import (
+	"math"
+	"testing"
+
+	moneypb "google.golang.org/genproto/googleapis/type/money"
+)
+
+func TestFromMajor(t *testing.T) {
+	tests := []struct {
+		amount  float64
+		code    string
+		want    int64
+		wantErr bool
+	}{
+		{amount: 12.5, code: "USD", want: 1250},
+		{amount: 0.1 + 0.2, code: "USD", want: 30},
+		{amount: 1.005, code: "USD", want: 100},
+		{amount: -2.345, code: "EUR", want: -235},
+		{amount: 1500, code: "JPY", want: 1500},
+		{amount: 1.2345, code: "KWD", want: 1235},
+		{amount: math.NaN(), code: "USD", wantErr: true},
+		{amount: math.Inf(1), code: "USD", wantErr: true},
+		{amount: 1e18, code: "USD", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := FromMajor(tt.amount, tt.code)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("FromMajor(%v, %s) error = %v, wantErr %v", tt.amount, tt.code, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("FromMajor(%v, %s) = %d, want %d", tt.amount, tt.code, got, tt.want)
+		}
+	}
+}
+
+func TestFormatAndParse(t *testing.T) {
+	tests := []struct {
+		minor int64
+		code  string
+		want  string
+	}{
+		{minor: 1250, code: "USD", want: "12.50"},
+		{minor: 5, code: "USD", want: "0.05"},
+		{minor: -5, code: "USD", want: "-0.05"},
+		{minor: 0, code: "EUR", want: "0.00"},
+		{minor: 1500, code: "JPY", want: "1500"},
+		{minor: 1235, code: "KWD", want: "1.235"},
+		{minor: math.MinInt64, code: "USD", want: "-92233720368547758.08"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := Format(tt.minor, tt.code); got != tt.want {
+				t.Errorf("Format(%d, %s) = %q, want %q", tt.minor, tt.code, got, tt.want)
+			}
+			got, err := Parse(tt.want, tt.code)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.want, err)
+			}
+			if got != tt.minor {
+				t.Errorf("Parse(%q) = %d, want %d", tt.want, got, tt.minor)
+			}
+		})
+	}
+
+	if got, err := Parse("12.5", "USD"); err != nil || got != 1250 {
+		t.Errorf("Parse(12.5) = %d, %v, want 1250", got, err)
+	}
+	for _, s := range []string{"", "abc", "1.234", "1.5e2", "--1", "1.-5", "1.5", "99999999999999999999"} {
+		code := "USD"
+		if s == "1.5" {
+			code = "JPY"
+		}
+		if _, err := Parse(s, code); err == nil {
+			t.Errorf("Parse(%q, %s) should return an error", s, code)
+		}
+	}
+}
+
+func TestMultiply(t *testing.T) {
+	if got, err := Multiply(1999, 3); err != nil || got != 5997 {
+		t.Errorf("Multiply(1999, 3) = %d, %v, want 5997", got, err)
+	}
+	if _, err := Multiply(math.MaxInt64/2, 3); err == nil {
+		t.Error("Multiply() should fail on overflow")
+	}
+}
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		minor    int64
+		from, to string
+		rate     float64
+		want     int64
+	}{
+		{minor: 1000, from: "USD", to: "EUR", rate: 0.9, want: 900},
+		{minor: 1000, from: "USD", to: "JPY", rate: 150.5, want: 1505},
+		{minor: 1505, from: "JPY", to: "USD", rate: 1 / 150.5, want: 1000},
+		{minor: 1000, from: "USD", to: "KWD", rate: 0.3075, want: 3075},
+	}
+
+	for _, tt := range tests {
+		got, err := Convert(tt.minor, tt.from, tt.to, tt.rate)
+		if err != nil {
+			t.Fatalf("Convert() error = %v", err)
+		}
+		if got != tt.want {
+			t.Errorf("Convert(%d %s to %s) = %d, want %d", tt.minor, tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestProto(t *testing.T) {
+	tests := []struct {
+		minor int64
+		code  string
+		want  *moneypb.Money
+	}{
+		{minor: 1250, code: "USD", want: &moneypb.Money{CurrencyCode: "USD", Units: 12, Nanos: 500000000}},
+		{minor: -1250, code: "USD", want: &moneypb.Money{CurrencyCode: "USD", Units: -12, Nanos: -500000000}},
+		{minor: 1500, code: "JPY", want: &moneypb.Money{CurrencyCode: "JPY", Units: 1500}},
+		{minor: 1235, code: "KWD", want: &moneypb.Money{CurrencyCode: "KWD", Units: 1, Nanos: 235000000}},
+	}
+
+	for _, tt := range tests {
+		m := ToProto(tt.minor, tt.code)
+		if m.Units != tt.want.Units || m.Nanos != tt.want.Nanos || m.CurrencyCode != tt.want.CurrencyCode {
+			t.Errorf("ToProto(%d, %s) = %v, want %v", tt.minor, tt.code, m, tt.want)
+		}
+		got, err := FromProto(m, tt.code)
+		if err != nil {
+			t.Fatalf("FromProto(%v) error = %v", m, err)
+		}
+		if got != tt.minor {
+			t.Errorf("FromProto(%v) = %d, want %d", m, got, tt.minor)
+		}
+	}
+
+	invalid := []*moneypb.Money{
+		{CurrencyCode: "EUR", Units: 1},
+		{CurrencyCode: "USD", Units: 1, Nanos: 5},
+		{CurrencyCode: "USD", Units: 1, Nanos: -500000000},
+		{CurrencyCode: "USD", Nanos: 1e9},
+		{CurrencyCode: "USD", Units: math.MaxInt64},
+	}
+	for _, m := range invalid {
+		if _, err := FromProto(m, "USD"); err == nil {
+			t.Errorf("FromProto(%v) should return an error", m)
+		}
+	}
+}
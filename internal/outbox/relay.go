@@ -0,0 +1,263 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package outbox
+
+import (
+	"context"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/internal/events"
+	"github.com/kevindiu/monorepo-go-example/internal/log"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Default relay settings
+const (
+	DefaultPollInterval = time.Second
+	DefaultBatchSize    = 100
+	DefaultMaxAttempts  = 10
+
+	maxBackoff = 5 * time.Minute
+)
+
+// RelayConfig holds relay configuration
+type RelayConfig struct {
+	Store        Store
+	Publisher    events.Publisher
+	Logger       *log.Logger
+	PollInterval time.Duration
+	BatchSize    int
+	MaxAttempts  int
+	// Registerer receives the relay metrics; nil disables registration
+	Registerer prometheus.Registerer
+}
+
+// Relay publishes outbox messages to the event bus. A message is only marked
+// sent after the publisher acknowledges it, so delivery is at-least-once and
+// consumers must tolerate duplicates.
+type Relay struct {
+	store        Store
+	publisher    events.Publisher
+	logger       *log.Logger
+	pollInterval time.Duration
+	batchSize    int
+	maxAttempts  int
+	metrics      *metrics
+}
+
+// NewRelay creates a new relay
+func NewRelay(cfg RelayConfig) (*Relay, error) {
+	if cfg.Store == nil {
+		return nil, errors.WithCode(errors.New("outbox store is required"), errors.CodeInvalidInput)
+	}
+	if cfg.Publisher == nil {
+		return nil, errors.WithCode(errors.New("event publisher is required"), errors.CodeInvalidInput)
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = log.NewDefault()
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = DefaultPollInterval
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = DefaultBatchSize
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = DefaultMaxAttempts
+	}
+
+	m := newMetrics()
+	if cfg.Registerer != nil {
+		if err := m.register(cfg.Registerer); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Relay{
+		store:        cfg.Store,
+		publisher:    cfg.Publisher,
+		logger:       cfg.Logger.Named("outbox"),
+		pollInterval: cfg.PollInterval,
+		batchSize:    cfg.BatchSize,
+		maxAttempts:  cfg.MaxAttempts,
+		metrics:      m,
+	}, nil
+}
+
+// Run polls the outbox until ctx is cancelled
+func (r *Relay) Run(ctx context.Context) error {
+	r.logger.Info("Starting outbox relay",
+		log.Any("poll_interval", r.pollInterval),
+		log.Int("batch_size", r.batchSize),
+	)
+
+	ticker := time.NewTicker(r.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := r.Flush(ctx); err != nil && ctx.Err() == nil {
+			r.logger.Error("Outbox relay iteration failed", log.Error(err))
+		}
+
+		r.updateBacklog(ctx)
+
+		select {
+		case <-ctx.Done():
+			r.logger.Info("Outbox relay stopped")
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Flush relays full batches back to back until the outbox holds less than
+// a batch of pending events. Run calls it on every tick; it is also used to
+// publish what is left before shutdown.
+func (r *Relay) Flush(ctx context.Context) error {
+	for {
+		n, err := r.RelayOnce(ctx)
+		if err != nil {
+			return err
+		}
+		if n < r.batchSize {
+			return nil
+		}
+	}
+}
+
+// RelayOnce publishes a single batch and returns the number of claimed messages
+func (r *Relay) RelayOnce(ctx context.Context) (int, error) {
+	claim, err := r.store.Claim(ctx, r.batchSize, r.maxAttempts)
+	if err != nil {
+		return 0, err
+	}
+	defer claim.Rollback()
+
+	messages := claim.Messages()
+	for _, msg := range messages {
+		if err := r.publisher.Publish(ctx, msg.Topic, msg.Event); err != nil {
+			r.metrics.failed.WithLabelValues(msg.Topic).Inc()
+			retryAt := time.Now().Add(backoff(msg.Attempts + 1))
+
+			if msg.Attempts+1 >= r.maxAttempts {
+				r.metrics.deadLettered.WithLabelValues(msg.Topic).Inc()
+				r.logger.Error("Outbox event exhausted retries",
+					log.String("event_id", msg.ID),
+					log.String("topic", msg.Topic),
+					log.Error(err),
+				)
+			} else {
+				r.logger.Warn("Failed to publish outbox event",
+					log.String("event_id", msg.ID),
+					log.String("topic", msg.Topic),
+					log.Int("attempt", msg.Attempts+1),
+					log.Error(err),
+				)
+			}
+
+			if err := claim.MarkFailed(ctx, msg.ID, err, retryAt); err != nil {
+				return 0, err
+			}
+			continue
+		}
+
+		if err := claim.MarkSent(ctx, msg.ID); err != nil {
+			return 0, err
+		}
+		r.metrics.published.WithLabelValues(msg.Topic).Inc()
+	}
+
+	if err := claim.Commit(); err != nil {
+		return 0, err
+	}
+
+	return len(messages), nil
+}
+
+func (r *Relay) updateBacklog(ctx context.Context) {
+	stats, err := r.store.Stats(ctx, r.maxAttempts)
+	if err != nil {
+		if ctx.Err() == nil {
+			r.logger.Warn("Failed to get outbox stats", log.Error(err))
+		}
+		return
+	}
+
+	r.metrics.backlog.Set(float64(stats.Pending))
+	r.metrics.dead.Set(float64(stats.Dead))
+	r.metrics.oldestAge.Set(stats.OldestPending.Seconds())
+}
+
+// backoff returns the delay before the given attempt, doubling from one second
+func backoff(attempt int) time.Duration {
+	if attempt <= 1 {
+		return time.Second
+	}
+	d := time.Second << uint(attempt-1)
+	if d <= 0 || d > maxBackoff {
+		return maxBackoff
+	}
+	return d
+}
+
+type metrics struct {
+	published    *prometheus.CounterVec
+	failed       *prometheus.CounterVec
+	deadLettered *prometheus.CounterVec
+	backlog      prometheus.Gauge
+	dead         prometheus.Gauge
+	oldestAge    prometheus.Gauge
+}
+
+func newMetrics() *metrics {
+	return &metrics{
+		published: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "outbox_events_published_total",
+			Help: "Number of outbox events published to the event bus.",
+		}, []string{"topic"}),
+		failed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "outbox_events_failed_total",
+			Help: "Number of failed outbox publish attempts.",
+		}, []string{"topic"}),
+		deadLettered: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "outbox_events_dead_lettered_total",
+			Help: "Number of outbox events that exhausted their retries.",
+		}, []string{"topic"}),
+		backlog: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "outbox_backlog_size",
+			Help: "Number of outbox events waiting to be published.",
+		}),
+		dead: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "outbox_dead_events",
+			Help: "Number of outbox events that will no longer be retried.",
+		}),
+		oldestAge: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "outbox_oldest_pending_age_seconds",
+			Help: "Age of the oldest unpublished outbox event.",
+		}),
+	}
+}
+
+func (m *metrics) register(reg prometheus.Registerer) error {
+	for _, c := range []prometheus.Collector{m.published, m.failed, m.deadLettered, m.backlog, m.dead, m.oldestAge} {
+		if err := reg.Register(c); err != nil {
+			return errors.Wrap(err, "failed to register outbox metrics")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,201 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package outbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/events"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// mockStore is an in-memory implementation of Store for testing
+type mockStore struct {
+	messages  []*Message
+	sent      map[string]bool
+	failed    map[string]int
+	committed bool
+}
+
+func newMockStore(messages ...*Message) *mockStore {
+	return &mockStore{
+		messages: messages,
+		sent:     make(map[string]bool),
+		failed:   make(map[string]int),
+	}
+}
+
+func (m *mockStore) Claim(ctx context.Context, limit, maxAttempts int) (Claim, error) {
+	var batch []*Message
+	for _, msg := range m.messages {
+		if !m.sent[msg.ID] && len(batch) < limit {
+			batch = append(batch, msg)
+		}
+	}
+	return &mockClaim{store: m, messages: batch}, nil
+}
+
+func (m *mockStore) Stats(ctx context.Context, maxAttempts int) (*Stats, error) {
+	return &Stats{Pending: int64(len(m.messages) - len(m.sent))}, nil
+}
+
+type mockClaim struct {
+	store    *mockStore
+	messages []*Message
+}
+
+func (c *mockClaim) Messages() []*Message { return c.messages }
+
+func (c *mockClaim) MarkSent(ctx context.Context, id string) error {
+	c.store.sent[id] = true
+	return nil
+}
+
+func (c *mockClaim) MarkFailed(ctx context.Context, id string, cause error, retryAt time.Time) error {
+	c.store.failed[id]++
+	return nil
+}
+
+func (c *mockClaim) Commit() error {
+	c.store.committed = true
+	return nil
+}
+
+func (c *mockClaim) Rollback() error { return nil }
+
+// mockPublisher records published events and fails for selected topics
+type mockPublisher struct {
+	published []*events.Event
+	failTopic string
+}
+
+func (p *mockPublisher) Publish(ctx context.Context, topic string, evts ...*events.Event) error {
+	if topic == p.failTopic {
+		return errors.New("broker unavailable")
+	}
+	p.published = append(p.published, evts...)
+	return nil
+}
+
+func (p *mockPublisher) Close() error { return nil }
+
+func newMessage(t *testing.T, id, topic string) *Message {
+	t.Helper()
+	event, err := events.NewEvent("order.created", id, map[string]string{"id": id})
+	if err != nil {
+		t.Fatalf("NewEvent() error = %v", err)
+	}
+	return &Message{ID: id, Topic: topic, Event: event}
+}
+
+func TestNewRelayValidation(t *testing.T) {
+	if _, err := NewRelay(RelayConfig{Publisher: &mockPublisher{}}); err == nil {
+		t.Error("NewRelay() without store should fail")
+	}
+	if _, err := NewRelay(RelayConfig{Store: newMockStore()}); err == nil {
+		t.Error("NewRelay() without publisher should fail")
+	}
+}
+
+func TestRelayOnce(t *testing.T) {
+	store := newMockStore(
+		newMessage(t, "1", "orders"),
+		newMessage(t, "2", "broken"),
+		newMessage(t, "3", "orders"),
+	)
+	pub := &mockPublisher{failTopic: "broken"}
+
+	relay, err := NewRelay(RelayConfig{
+		Store:      store,
+		Publisher:  pub,
+		Registerer: prometheus.NewRegistry(),
+	})
+	if err != nil {
+		t.Fatalf("NewRelay() error = %v", err)
+	}
+
+	n, err := relay.RelayOnce(context.Background())
+	if err != nil {
+		t.Fatalf("RelayOnce() error = %v", err)
+	}
+	if n != 3 {
+		t.Errorf("RelayOnce() claimed %d messages, want 3", n)
+	}
+	if len(pub.published) != 2 {
+		t.Errorf("published %d events, want 2", len(pub.published))
+	}
+	if !store.sent["1"] || !store.sent["3"] {
+		t.Error("successfully published messages were not marked sent")
+	}
+	if store.sent["2"] || store.failed["2"] != 1 {
+		t.Error("failed message should be marked failed, not sent")
+	}
+	if !store.committed {
+		t.Error("RelayOnce() did not commit the claim")
+	}
+}
+
+func TestFlush(t *testing.T) {
+	store := newMockStore(
+		newMessage(t, "1", "orders"),
+		newMessage(t, "2", "orders"),
+		newMessage(t, "3", "orders"),
+		newMessage(t, "4", "orders"),
+		newMessage(t, "5", "orders"),
+	)
+	pub := &mockPublisher{}
+
+	relay, err := NewRelay(RelayConfig{
+		Store:      store,
+		Publisher:  pub,
+		BatchSize:  2,
+		Registerer: prometheus.NewRegistry(),
+	})
+	if err != nil {
+		t.Fatalf("NewRelay() error = %v", err)
+	}
+
+	if err := relay.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+	if len(pub.published) != 5 {
+		t.Errorf("Flush() published %d events, want 5", len(pub.published))
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{attempt: 0, want: time.Second},
+		{attempt: 1, want: time.Second},
+		{attempt: 2, want: 2 * time.Second},
+		{attempt: 4, want: 8 * time.Second},
+		{attempt: 20, want: maxBackoff},
+		{attempt: 100, want: maxBackoff},
+	}
+
+	for _, tt := range tests {
+		if got := backoff(tt.attempt); got != tt.want {
+			t.Errorf("backoff(%d) = %v, want %v", tt.attempt, got, tt.want)
+		}
+	}
+}
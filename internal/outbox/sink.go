@@ -0,0 +1,173 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/config"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/internal/events"
+	"github.com/kevindiu/monorepo-go-example/internal/log"
+	"github.com/kevindiu/monorepo-go-example/internal/signing"
+)
+
+// Supported sinks
+const (
+	SinkEvents  = "events"
+	SinkLog     = "log"
+	SinkWebhook = "webhook"
+)
+
+// Headers set on every webhook sink request
+const (
+	HeaderSignature = "X-Outbox-Signature"
+	HeaderTimestamp = "X-Outbox-Timestamp"
+	HeaderTopic     = "X-Outbox-Topic"
+	HeaderEventType = "X-Outbox-Event"
+	HeaderEventID   = "X-Outbox-Event-Id"
+)
+
+// NewSink creates the publisher for the configured sink. The events sink
+// publishes to the event bus of the events configuration.
+func NewSink(cfg *config.Outbox, eventsCfg *config.Events, logger *log.Logger) (events.Publisher, error) {
+	switch cfg.Sink {
+	case SinkEvents, "":
+		return events.New(eventsCfg, logger)
+	case SinkLog:
+		return NewLogSink(logger), nil
+	case SinkWebhook:
+		if cfg.Webhook == nil || cfg.Webhook.URL == "" {
+			return nil, errors.WithCode(errors.New("outbox webhook url is required"), errors.CodeInvalidInput)
+		}
+		return NewWebhookSink(cfg.Webhook.URL, cfg.Webhook.Secret, &http.Client{Timeout: cfg.Webhook.Timeout}), nil
+	default:
+		return nil, errors.WithCode(errors.Newf("unsupported outbox sink %q", cfg.Sink), errors.CodeInvalidInput)
+	}
+}
+
+// LogSink writes events to the log instead of publishing them. It is meant
+// for development and for inspecting the outbox without a broker.
+type LogSink struct {
+	logger *log.Logger
+}
+
+// NewLogSink creates a log sink
+func NewLogSink(logger *log.Logger) *LogSink {
+	if logger == nil {
+		logger = log.NewDefault()
+	}
+	return &LogSink{logger: logger.Named("outbox-sink")}
+}
+
+// Publish logs each event
+func (s *LogSink) Publish(ctx context.Context, topic string, evts ...*events.Event) error {
+	for _, event := range evts {
+		s.logger.Info("Outbox event",
+			log.String("topic", topic),
+			log.String("event_id", event.ID),
+			log.String("type", event.Type),
+			log.String("key", event.Key),
+			log.String("payload", string(event.Payload)),
+		)
+	}
+	return nil
+}
+
+// Close implements events.Publisher
+func (s *LogSink) Close() error {
+	return nil
+}
+
+// WebhookSink POSTs each event envelope as JSON to a URL. Any 2xx response
+// acknowledges the event; receivers should deduplicate on the event ID
+// header, as events are redelivered until acknowledged.
+type WebhookSink struct {
+	url    string
+	secret string
+	client *http.Client
+}
+
+// NewWebhookSink creates a webhook sink. Bodies are signed with secret
+// when it is set.
+func NewWebhookSink(url, secret string, client *http.Client) *WebhookSink {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &WebhookSink{url: url, secret: secret, client: client}
+}
+
+// Publish delivers events one at a time, stopping at the first failure
+func (s *WebhookSink) Publish(ctx context.Context, topic string, evts ...*events.Event) error {
+	for _, event := range evts {
+		if err := s.post(ctx, topic, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *WebhookSink) post(ctx context.Context, topic string, event *events.Event) error {
+	body, err := events.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "failed to create webhook request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderTopic, topic)
+	req.Header.Set(HeaderEventType, event.Type)
+	req.Header.Set(HeaderEventID, event.ID)
+	if s.secret != "" {
+		ts := time.Now().Unix()
+		req.Header.Set(HeaderTimestamp, strconv.FormatInt(ts, 10))
+		req.Header.Set(HeaderSignature, Sign(s.secret, ts, body))
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return errors.WithCode(errors.Wrap(err, "failed to deliver outbox webhook"), errors.CodeUnavailable)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return errors.WithCode(errors.Newf("outbox webhook returned status %d", resp.StatusCode), errors.CodeUnavailable)
+	}
+	return nil
+}
+
+// Close implements events.Publisher
+func (s *WebhookSink) Close() error {
+	s.client.CloseIdleConnections()
+	return nil
+}
+
+// Sign computes the signature header value for a webhook sink body, as
+// outgoing webhooks are signed. The timestamp is part of the signed content
+// so receivers can reject replays.
+func Sign(secret string, timestamp int64, body []byte) string {
+	return signing.Sign(secret, timestamp, body)
+}
@@ -0,0 +1,117 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/kevindiu/monorepo-go-example/internal/config"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/internal/events"
+)
+
+func TestWebhookSink(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		secret  string
+		wantErr bool
+	}{
+		{name: "acknowledged", status: http.StatusNoContent},
+		{name: "signed", status: http.StatusOK, secret: "s3cret"},
+		{name: "rejected", status: http.StatusInternalServerError, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event, err := events.NewEvent("order.created", "order-1", map[string]string{"id": "order-1"})
+			if err != nil {
+				t.Fatalf("NewEvent() error = %v", err)
+			}
+
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				if got := r.Header.Get(HeaderEventID); got != event.ID {
+					t.Errorf("%s = %q, want %q", HeaderEventID, got, event.ID)
+				}
+				if got := r.Header.Get(HeaderTopic); got != "orders" {
+					t.Errorf("%s = %q, want orders", HeaderTopic, got)
+				}
+				if tt.secret != "" {
+					ts, _ := strconv.ParseInt(r.Header.Get(HeaderTimestamp), 10, 64)
+					if got, want := r.Header.Get(HeaderSignature), Sign(tt.secret, ts, body); got != want {
+						t.Errorf("%s = %q, want %q", HeaderSignature, got, want)
+					}
+				} else if r.Header.Get(HeaderSignature) != "" {
+					t.Errorf("unexpected %s header without a secret", HeaderSignature)
+				}
+				received, err := events.Unmarshal(body)
+				if err != nil || received.ID != event.ID {
+					t.Errorf("body does not decode to the published event: %v", err)
+				}
+				w.WriteHeader(tt.status)
+			}))
+			defer srv.Close()
+
+			sink := NewWebhookSink(srv.URL, tt.secret, srv.Client())
+			err = sink.Publish(context.Background(), "orders", event)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Publish() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && errors.GetCode(err) != errors.CodeUnavailable {
+				t.Errorf("Publish() code = %v, want unavailable", errors.GetCode(err))
+			}
+		})
+	}
+}
+
+func TestNewSink(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     *config.Outbox
+		want    string
+		wantErr bool
+	}{
+		{name: "events", cfg: &config.Outbox{Sink: SinkEvents}, want: "*events.MemoryBus"},
+		{name: "log", cfg: &config.Outbox{Sink: SinkLog}, want: "*outbox.LogSink"},
+		{name: "webhook", cfg: &config.Outbox{Sink: SinkWebhook, Webhook: &config.OutboxWebhook{URL: "http://localhost"}}, want: "*outbox.WebhookSink"},
+		{name: "webhook without url", cfg: &config.Outbox{Sink: SinkWebhook}, wantErr: true},
+		{name: "unknown", cfg: &config.Outbox{Sink: "carrier-pigeon"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink, err := NewSink(tt.cfg, &config.Events{Driver: events.DriverMemory}, nil)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewSink() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			defer sink.Close()
+			if got := fmt.Sprintf("%T", sink); got != tt.want {
+				t.Errorf("NewSink() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,215 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/db"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/internal/events"
+)
+
+// Message is an outbox row waiting to be published
+type Message struct {
+	ID        string
+	Topic     string
+	Event     *events.Event
+	Attempts  int
+	CreatedAt time.Time
+}
+
+// Stats describes the current outbox backlog
+type Stats struct {
+	Pending       int64
+	Dead          int64
+	OldestPending time.Duration
+}
+
+// Execer is satisfied by *sql.DB, *sql.Tx and *db.DB so events can be
+// enqueued inside the caller's transaction
+type Execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// Claim is a batch of messages locked for publishing. Outcomes recorded
+// through MarkSent and MarkFailed take effect on Commit.
+type Claim interface {
+	Messages() []*Message
+	MarkSent(ctx context.Context, id string) error
+	MarkFailed(ctx context.Context, id string, cause error, retryAt time.Time) error
+	Commit() error
+	Rollback() error
+}
+
+// Store persists outbox messages
+type Store interface {
+	Claim(ctx context.Context, limit, maxAttempts int) (Claim, error)
+	Stats(ctx context.Context, maxAttempts int) (*Stats, error)
+}
+
+// Enqueue writes events to the outbox using exec, which should be the
+// transaction that performs the corresponding state change
+func Enqueue(ctx context.Context, exec Execer, topic string, evts ...*events.Event) error {
+	query := `
+		INSERT INTO outbox_events (id, topic, event_type, event_key, payload, created_at, next_attempt_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $6)
+	`
+
+	for _, event := range evts {
+		payload, err := events.Marshal(event)
+		if err != nil {
+			return err
+		}
+
+		if _, err := exec.ExecContext(ctx, query,
+			event.ID,
+			topic,
+			event.Type,
+			event.Key,
+			payload,
+			event.OccurredAt,
+		); err != nil {
+			return errors.Wrap(err, "failed to enqueue outbox event")
+		}
+	}
+
+	return nil
+}
+
+type store struct {
+	db *db.DB
+}
+
+// NewStore creates a Postgres-backed outbox store
+func NewStore(database *db.DB) Store {
+	return &store{db: database}
+}
+
+// Claim locks up to limit due messages. Rows are locked with SKIP LOCKED so
+// several relays can run concurrently without publishing the same row twice.
+func (s *store) Claim(ctx context.Context, limit, maxAttempts int) (Claim, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT id, topic, payload, attempts, created_at
+		FROM outbox_events
+		WHERE published_at IS NULL AND attempts < $1 AND next_attempt_at <= $2
+		ORDER BY created_at ASC
+		LIMIT $3
+		FOR UPDATE SKIP LOCKED
+	`
+
+	rows, err := tx.QueryContext(ctx, query, maxAttempts, time.Now(), limit)
+	if err != nil {
+		tx.Rollback()
+		return nil, errors.Wrap(err, "failed to claim outbox events")
+	}
+	defer rows.Close()
+
+	var messages []*Message
+	for rows.Next() {
+		var msg Message
+		var payload []byte
+		if err := rows.Scan(&msg.ID, &msg.Topic, &payload, &msg.Attempts, &msg.CreatedAt); err != nil {
+			tx.Rollback()
+			return nil, errors.Wrap(err, "failed to scan outbox event")
+		}
+
+		msg.Event, err = events.Unmarshal(payload)
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+		messages = append(messages, &msg)
+	}
+
+	if err := rows.Err(); err != nil {
+		tx.Rollback()
+		return nil, errors.Wrap(err, "error iterating outbox events")
+	}
+
+	return &claim{tx: tx, messages: messages}, nil
+}
+
+// Stats reports the pending and dead-lettered backlog
+func (s *store) Stats(ctx context.Context, maxAttempts int) (*Stats, error) {
+	query := `
+		SELECT
+			COUNT(*) FILTER (WHERE attempts < $1),
+			COUNT(*) FILTER (WHERE attempts >= $1),
+			MIN(created_at) FILTER (WHERE attempts < $1)
+		FROM outbox_events
+		WHERE published_at IS NULL
+	`
+
+	var stats Stats
+	var oldest sql.NullTime
+	if err := s.db.QueryRowContext(ctx, query, maxAttempts).Scan(&stats.Pending, &stats.Dead, &oldest); err != nil {
+		return nil, errors.Wrap(err, "failed to get outbox stats")
+	}
+	if oldest.Valid {
+		stats.OldestPending = time.Since(oldest.Time)
+	}
+
+	return &stats, nil
+}
+
+type claim struct {
+	tx       *db.Tx
+	messages []*Message
+}
+
+func (c *claim) Messages() []*Message {
+	return c.messages
+}
+
+func (c *claim) MarkSent(ctx context.Context, id string) error {
+	query := `UPDATE outbox_events SET published_at = $1, attempts = attempts + 1 WHERE id = $2`
+	if _, err := c.tx.ExecContext(ctx, query, time.Now(), id); err != nil {
+		return errors.Wrap(err, "failed to mark outbox event sent")
+	}
+	return nil
+}
+
+func (c *claim) MarkFailed(ctx context.Context, id string, cause error, retryAt time.Time) error {
+	query := `
+		UPDATE outbox_events
+		SET attempts = attempts + 1, last_error = $1, next_attempt_at = $2
+		WHERE id = $3
+	`
+	if _, err := c.tx.ExecContext(ctx, query, cause.Error(), retryAt, id); err != nil {
+		return errors.Wrap(err, "failed to mark outbox event failed")
+	}
+	return nil
+}
+
+func (c *claim) Commit() error {
+	if err := c.tx.Commit(); err != nil {
+		return errors.Wrap(err, "failed to commit outbox claim")
+	}
+	return nil
+}
+
+func (c *claim) Rollback() error {
+	return c.tx.Rollback()
+}
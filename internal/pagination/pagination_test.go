@@ -0,0 +1,63 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pagination
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTokens(t *testing.T) {
+	tokens := New("page-secret")
+	cursor := Cursor{CreatedAt: time.Unix(1760000000, 123456789), Value: -4200, ID: "9b2f6c1e-0d1a-4f7e-8a52-3c7d9e0f1a2b"}
+	token := tokens.Encode("orders", cursor)
+
+	got, err := tokens.Decode("orders", token)
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if !got.CreatedAt.Equal(cursor.CreatedAt) || got.Value != cursor.Value || got.ID != cursor.ID {
+		t.Errorf("Decode() = %+v, want %+v", got, cursor)
+	}
+
+	if got, err := tokens.Decode("orders", ""); got != nil || err != nil {
+		t.Errorf("Decode(empty) = %v, %v, want the start of the listing", got, err)
+	}
+
+	tampered := []byte(token)
+	tampered[2] ^= 1
+	tests := []struct {
+		name   string
+		tokens *Tokens
+		scope  string
+		token  string
+	}{
+		{name: "offset", tokens: tokens, scope: "orders", token: "20"},
+		{name: "tampered", tokens: tokens, scope: "orders", token: string(tampered)},
+		{name: "other scope", tokens: tokens, scope: "orders:user-1", token: token},
+		{name: "other secret", tokens: New("other-secret"), scope: "orders", token: token},
+		{name: "not base64", tokens: tokens, scope: "orders", token: "!!!"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.tokens.Decode(tt.scope, tt.token); err == nil {
+				t.Error("Decode() accepted an invalid token")
+			}
+		})
+	}
+}
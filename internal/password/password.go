@@ -0,0 +1,190 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package password hashes and verifies user passwords with bcrypt or
+// argon2id. Hashes are self-describing, so a hash verifies under the
+// algorithm and parameters it was made with after the configuration
+// changes, and NeedsRehash tells when it should be replaced.
+package password
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/kevindiu/monorepo-go-example/internal/config"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Hashing algorithms of config.Password.Algorithm
+const (
+	AlgorithmBcrypt   = "bcrypt"
+	AlgorithmArgon2id = "argon2id"
+)
+
+// maxLength bounds passwords; bcrypt ignores bytes past 72 and argon2id
+// hashes of huge inputs are a cheap way to burn CPU
+const maxLength = 72
+
+const (
+	argon2SaltLength = 16
+	argon2KeyLength  = 32
+)
+
+// ErrMismatch is returned by Verify for a wrong password
+var ErrMismatch = &errors.Error{Code: errors.CodeUnauthorized, Message: "invalid credentials"}
+
+// Hasher hashes passwords with the configured algorithm
+type Hasher struct {
+	cfg config.Password
+
+	dummyOnce sync.Once
+	dummy     string
+}
+
+// New creates a hasher for cfg
+func New(cfg *config.Password) (*Hasher, error) {
+	switch cfg.Algorithm {
+	case AlgorithmBcrypt, AlgorithmArgon2id:
+	default:
+		return nil, errors.Newf("unknown password algorithm %q", cfg.Algorithm)
+	}
+	return &Hasher{cfg: *cfg}, nil
+}
+
+// Validate checks that password is acceptable as a new password
+func (h *Hasher) Validate(password string) error {
+	if n := utf8.RuneCountInString(password); n < h.cfg.MinLength {
+		return errors.InvalidField("password", fmt.Sprintf("password must have at least %d characters", h.cfg.MinLength))
+	}
+	if len(password) > maxLength {
+		return errors.InvalidField("password", fmt.Sprintf("password must have at most %d bytes", maxLength))
+	}
+	return nil
+}
+
+// Hash returns the hash of password to store
+func (h *Hasher) Hash(password string) (string, error) {
+	if h.cfg.Algorithm == AlgorithmBcrypt {
+		hash, err := bcrypt.GenerateFromPassword([]byte(password), h.cfg.BcryptCost)
+		if err != nil {
+			return "", errors.Wrap(err, "failed to hash password")
+		}
+		return string(hash), nil
+	}
+
+	salt := make([]byte, argon2SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", errors.Wrap(err, "failed to generate salt")
+	}
+	key := argon2.IDKey([]byte(password), salt, h.cfg.Argon2Iterations, h.cfg.Argon2Memory, h.cfg.Argon2Parallelism, argon2KeyLength)
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, h.cfg.Argon2Memory, h.cfg.Argon2Iterations, h.cfg.Argon2Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// Verify checks password against a hash made by Hash with any algorithm.
+// It returns ErrMismatch when the password is wrong.
+func (h *Hasher) Verify(hash, password string) error {
+	if strings.HasPrefix(hash, "$argon2id$") {
+		params, salt, key, err := parseArgon2(hash)
+		if err != nil {
+			return err
+		}
+		got := argon2.IDKey([]byte(password), salt, params.iterations, params.memory, params.parallelism, uint32(len(key)))
+		if subtle.ConstantTimeCompare(got, key) != 1 {
+			return ErrMismatch
+		}
+		return nil
+	}
+
+	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+	if err == bcrypt.ErrMismatchedHashAndPassword {
+		return ErrMismatch
+	}
+	if err != nil {
+		return errors.Wrap(err, "failed to verify password")
+	}
+	return nil
+}
+
+// Reject verifies password against a dummy hash made with the configured
+// settings and returns ErrMismatch. Logins for unknown users call it, so
+// they take as long as wrong passwords and do not tell which users exist.
+func (h *Hasher) Reject(password string) error {
+	h.dummyOnce.Do(func() {
+		h.dummy, _ = h.Hash("dummy password")
+	})
+	if h.dummy != "" {
+		h.Verify(h.dummy, password)
+	}
+	return ErrMismatch
+}
+
+// NeedsRehash reports whether hash was made with another algorithm or
+// weaker parameters than configured
+func (h *Hasher) NeedsRehash(hash string) bool {
+	if h.cfg.Algorithm == AlgorithmBcrypt {
+		cost, err := bcrypt.Cost([]byte(hash))
+		return err != nil || cost < h.cfg.BcryptCost
+	}
+	params, _, _, err := parseArgon2(hash)
+	return err != nil ||
+		params.memory < h.cfg.Argon2Memory ||
+		params.iterations < h.cfg.Argon2Iterations ||
+		params.parallelism < h.cfg.Argon2Parallelism
+}
+
+// argon2Params are the cost parameters recorded in an argon2id hash
+type argon2Params struct {
+	memory      uint32
+	iterations  uint32
+	parallelism uint8
+}
+
+// parseArgon2 splits an argon2id hash in the PHC string format
+func parseArgon2(hash string) (argon2Params, []byte, []byte, error) {
+	var params argon2Params
+	invalid := errors.New("invalid argon2id hash")
+
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || parts[1] != AlgorithmArgon2id {
+		return params, nil, nil, invalid
+	}
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return params, nil, nil, invalid
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.memory, &params.iterations, &params.parallelism); err != nil {
+		return params, nil, nil, invalid
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return params, nil, nil, invalid
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return params, nil, nil, invalid
+	}
+	return params, salt, key, nil
+}
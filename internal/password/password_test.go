@@ -0,0 +1,136 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package password
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kevindiu/monorepo-go-example/internal/config"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+)
+
+func testConfig(algorithm string) *config.Password {
+	return &config.Password{
+		Algorithm:         algorithm,
+		MinLength:         12,
+		BcryptCost:        10,
+		Argon2Memory:      8 * 1024,
+		Argon2Iterations:  1,
+		Argon2Parallelism: 1,
+	}
+}
+
+func TestHashVerify(t *testing.T) {
+	for _, algorithm := range []string{AlgorithmBcrypt, AlgorithmArgon2id} {
+		t.Run(algorithm, func(t *testing.T) {
+			h, err := New(testConfig(algorithm))
+			if err != nil {
+				t.Fatal(err)
+			}
+			hash, err := h.Hash("correct horse battery")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strings.Contains(hash, "correct horse") {
+				t.Fatalf("hash contains the password: %s", hash)
+			}
+			if err := h.Verify(hash, "correct horse battery"); err != nil {
+				t.Errorf("Verify() = %v, want nil", err)
+			}
+			if err := h.Verify(hash, "wrong horse battery"); errors.GetCode(err) != errors.CodeUnauthorized {
+				t.Errorf("Verify() wrong password = %v, want %s", err, errors.CodeUnauthorized)
+			}
+			if h.NeedsRehash(hash) {
+				t.Error("NeedsRehash() = true for a fresh hash")
+			}
+		})
+	}
+}
+
+func TestReject(t *testing.T) {
+	for _, algorithm := range []string{AlgorithmBcrypt, AlgorithmArgon2id} {
+		t.Run(algorithm, func(t *testing.T) {
+			h, err := New(testConfig(algorithm))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, pw := range []string{"correct horse battery", "dummy password"} {
+				if err := h.Reject(pw); err != ErrMismatch {
+					t.Errorf("Reject(%q) = %v, want ErrMismatch", pw, err)
+				}
+			}
+			if h.NeedsRehash(h.dummy) {
+				t.Errorf("dummy hash %q does not use the configured settings", h.dummy)
+			}
+		})
+	}
+}
+
+func TestVerifyAcrossAlgorithms(t *testing.T) {
+	bcryptHasher, _ := New(testConfig(AlgorithmBcrypt))
+	argonHasher, _ := New(testConfig(AlgorithmArgon2id))
+
+	hash, err := bcryptHasher.Hash("correct horse battery")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := argonHasher.Verify(hash, "correct horse battery"); err != nil {
+		t.Errorf("Verify() bcrypt hash with argon2id config = %v", err)
+	}
+	if !argonHasher.NeedsRehash(hash) {
+		t.Error("NeedsRehash() = false for a hash of another algorithm")
+	}
+
+	stronger := testConfig(AlgorithmArgon2id)
+	stronger.Argon2Iterations = 3
+	strongerHasher, _ := New(stronger)
+	hash, _ = argonHasher.Hash("correct horse battery")
+	if !strongerHasher.NeedsRehash(hash) {
+		t.Error("NeedsRehash() = false for weaker argon2id parameters")
+	}
+}
+
+func TestVerifyMalformed(t *testing.T) {
+	h, _ := New(testConfig(AlgorithmArgon2id))
+	for _, hash := range []string{"", "plain", "$argon2id$v=19$m=x$salt$key", "$argon2id$v=19$m=8192,t=1,p=1$!!$!!"} {
+		if err := h.Verify(hash, "correct horse battery"); err == nil || err == ErrMismatch {
+			t.Errorf("Verify(%q) = %v, want a malformed hash error", hash, err)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	h, _ := New(testConfig(AlgorithmBcrypt))
+	tests := map[string]bool{
+		"short":                 false,
+		"twelve chars":          true,
+		strings.Repeat("a", 73): false,
+		strings.Repeat("é", 12): true,
+	}
+	for password, ok := range tests {
+		if err := h.Validate(password); (err == nil) != ok {
+			t.Errorf("Validate(%q) = %v, want ok=%v", password, err, ok)
+		}
+	}
+}
+
+func TestNewUnknownAlgorithm(t *testing.T) {
+	if _, err := New(&config.Password{Algorithm: "md5"}); err == nil {
+		t.Error("New() with md5 should fail")
+	}
+}
@@ -0,0 +1,82 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often idle buckets are dropped from a Local store
+const sweepInterval = time.Minute
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	// full is when the bucket will have refilled, after which it is
+	// indistinguishable from a new one
+	full time.Time
+}
+
+// Local keeps buckets in process. Each replica enforces its quotas on its
+// own, so a quota is multiplied by the number of replicas.
+type Local struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	now       func() time.Time
+	lastSweep time.Time
+}
+
+// NewLocal creates an in-process store
+func NewLocal() *Local {
+	return &Local{buckets: make(map[string]*bucket), now: time.Now}
+}
+
+// Take takes a token from the bucket of key
+func (l *Local) Take(ctx context.Context, key string, quota Quota) (Decision, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: quota.capacity(), updated: now}
+		l.buckets[key] = b
+	}
+
+	tokens, d := take(b.tokens, now.Sub(b.updated), quota)
+	b.tokens = tokens
+	b.updated = now
+	b.full = now.Add(time.Duration((quota.capacity() - tokens) / quota.rate() * float64(time.Second)))
+	return d, nil
+}
+
+// sweep drops buckets that have refilled
+func (l *Local) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < sweepInterval {
+		return
+	}
+	l.lastSweep = now
+	for key, b := range l.buckets {
+		if now.After(b.full) {
+			delete(l.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package ratelimit
+
+import (
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Decision labels
+const (
+	decisionAllowed   = "allowed"
+	decisionThrottled = "throttled"
+	decisionUnlimited = "unlimited"
+	decisionError     = "error"
+)
+
+type metrics struct {
+	decisions *prometheus.CounterVec
+	fallbacks prometheus.Counter
+}
+
+func newMetrics() *metrics {
+	return &metrics{
+		decisions: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "ratelimit_decisions_total",
+			Help: "Number of rate limit decisions by outcome.",
+		}, []string{"decision"}),
+		fallbacks: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "ratelimit_fallbacks_total",
+			Help: "Number of rate limit decisions made by the local store while Redis was unavailable.",
+		}),
+	}
+}
+
+func (m *metrics) register(reg prometheus.Registerer) error {
+	for _, c := range []prometheus.Collector{m.decisions, m.fallbacks} {
+		if err := reg.Register(c); err != nil {
+			return errors.Wrap(err, "failed to register rate limit metrics")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,233 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package ratelimit throttles requests with token buckets kept in Redis,
+// so that every replica draws from the same quota, or in process.
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/config"
+	"github.com/kevindiu/monorepo-go-example/internal/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/redis/go-redis/v9"
+)
+
+// redisTimeout bounds each step of a call to Redis
+const redisTimeout = 100 * time.Millisecond
+
+// Quota allows Requests per Window on average, with bursts of up to Burst
+// requests
+type Quota struct {
+	Requests int
+	Window   time.Duration
+	Burst    int
+}
+
+// rate returns how many tokens the bucket regains per second
+func (q Quota) rate() float64 {
+	return float64(q.Requests) / q.Window.Seconds()
+}
+
+// capacity returns the bucket size, which defaults to Requests
+func (q Quota) capacity() float64 {
+	if q.Burst > 0 {
+		return float64(q.Burst)
+	}
+	return float64(q.Requests)
+}
+
+// Decision is the outcome of taking a token from a bucket
+type Decision struct {
+	Allowed bool
+	// Limit is the bucket size
+	Limit int
+	// Remaining is the number of whole tokens left
+	Remaining int
+	// RetryAfter is how long until a token is available when not allowed
+	RetryAfter time.Duration
+}
+
+// Store keeps token buckets by key
+type Store interface {
+	// Take takes a token from the bucket of key, creating it full
+	Take(ctx context.Context, key string, quota Quota) (Decision, error)
+}
+
+// take takes a token from a bucket holding tokens as of elapsed ago and
+// returns the tokens left and the decision
+func take(tokens float64, elapsed time.Duration, quota Quota) (float64, Decision) {
+	capacity := quota.capacity()
+	tokens = math.Min(capacity, tokens+elapsed.Seconds()*quota.rate())
+
+	d := Decision{Limit: int(capacity)}
+	if tokens >= 1 {
+		tokens--
+		d.Allowed = true
+	} else {
+		d.RetryAfter = time.Duration((1 - tokens) / quota.rate() * float64(time.Second))
+	}
+	d.Remaining = int(tokens)
+	return tokens, d
+}
+
+// Limiter applies a quota to each key, the default quota unless the config
+// overrides it for the key
+type Limiter struct {
+	store   Store
+	prefix  string
+	quota   Quota
+	quotas  map[string]Quota
+	metrics *metrics
+	logger  *log.Logger
+	close   func() error
+}
+
+// New creates a limiter from configuration. Buckets are kept in Redis when
+// an address is configured, falling back to in-process buckets while Redis
+// is unavailable, and in process otherwise. Throttle decisions are counted
+// on reg when it is not nil.
+func New(cfg *config.RateLimit, reg prometheus.Registerer, logger *log.Logger) (*Limiter, error) {
+	if logger == nil {
+		logger = log.NewDefault()
+	}
+	m := newMetrics()
+	if reg != nil {
+		if err := m.register(reg); err != nil {
+			return nil, err
+		}
+	}
+
+	local := NewLocal()
+	var store Store = local
+	closeStore := func() error { return nil }
+	if cfg.RedisAddr != "" {
+		// Fail fast so that requests fall back to the local buckets
+		// instead of waiting out an outage
+		client := redis.NewClient(&redis.Options{
+			Addr:         cfg.RedisAddr,
+			Password:     cfg.RedisPassword,
+			DB:           cfg.RedisDB,
+			DialTimeout:  redisTimeout,
+			ReadTimeout:  redisTimeout,
+			WriteTimeout: redisTimeout,
+			MaxRetries:   -1, // 0 means the default of 3 retries
+		})
+		store = newFallback(NewRedis(client, "ratelimit:"), local, m, logger)
+		closeStore = client.Close
+	}
+
+	l := &Limiter{
+		store:   store,
+		metrics: m,
+		logger:  logger,
+		close:   closeStore,
+	}
+	return l.Scoped("", Quota{Requests: cfg.Requests, Window: cfg.Window, Burst: cfg.Burst}, cfg.Quotas), nil
+}
+
+// NewMethods creates a limiter of the gRPC method quotas of cfg, keyed by
+// full method or service name. It returns nil when rate limiting is
+// disabled or no method has a quota.
+func NewMethods(cfg *config.RateLimit, reg prometheus.Registerer, logger *log.Logger) (*Limiter, error) {
+	if !cfg.Enabled || len(cfg.Methods) == 0 {
+		return nil, nil
+	}
+	l, err := New(cfg, reg, logger)
+	if err != nil {
+		return nil, err
+	}
+	return l.Scoped("method:", Quota{}, cfg.Methods), nil
+}
+
+// ClientIP returns a limiter of the client IP quota of cfg drawing from
+// l's store, or nil when client addresses are unlimited
+func ClientIP(l *Limiter, cfg *config.RateLimit) *Limiter {
+	q := cfg.ClientIP
+	if l == nil || q.Requests <= 0 {
+		return nil
+	}
+	return l.Scoped("ip:", Quota{Requests: q.Requests, Window: q.Window, Burst: q.Burst}, nil)
+}
+
+// APIKey returns a limiter of the API key quota of cfg drawing from l's
+// store, or nil when keys are unlimited
+func APIKey(l *Limiter, cfg *config.RateLimit) *Limiter {
+	q := cfg.APIKey
+	if l == nil || q.Requests <= 0 {
+		return nil
+	}
+	return l.Scoped("apikey:", Quota{Requests: q.Requests, Window: q.Window, Burst: q.Burst}, nil)
+}
+
+// Scoped returns a limiter sharing l's store and metrics that keeps its
+// buckets under prefix, apart from l's, and applies quota to every key
+// unless quotas overrides it. Closing either limiter closes the store.
+func (l *Limiter) Scoped(prefix string, quota Quota, quotas []config.RateLimitQuota) *Limiter {
+	scoped := &Limiter{
+		store:   l.store,
+		prefix:  l.prefix + prefix,
+		quota:   quota,
+		quotas:  make(map[string]Quota, len(quotas)),
+		metrics: l.metrics,
+		logger:  l.logger,
+		close:   l.close,
+	}
+	for _, q := range quotas {
+		scoped.quotas[q.Key] = Quota{Requests: q.Requests, Window: q.Window, Burst: q.Burst}
+	}
+	return scoped
+}
+
+// Overrides reports whether the config sets a quota for key
+func (l *Limiter) Overrides(key string) bool {
+	_, ok := l.quotas[key]
+	return ok
+}
+
+// Allow takes a token for key. When the store fails the request is allowed
+// rather than failing the caller.
+func (l *Limiter) Allow(ctx context.Context, key string) Decision {
+	quota, ok := l.quotas[key]
+	if !ok {
+		quota = l.quota
+	}
+	if quota.Requests <= 0 {
+		l.metrics.decisions.WithLabelValues(decisionUnlimited).Inc()
+		return Decision{Allowed: true}
+	}
+
+	d, err := l.store.Take(ctx, l.prefix+key, quota)
+	if err != nil {
+		l.logger.Warn("Rate limit store failed", log.String("key", key), log.Error(err))
+		l.metrics.decisions.WithLabelValues(decisionError).Inc()
+		return Decision{Allowed: true, Limit: int(quota.capacity())}
+	}
+	if d.Allowed {
+		l.metrics.decisions.WithLabelValues(decisionAllowed).Inc()
+	} else {
+		l.metrics.decisions.WithLabelValues(decisionThrottled).Inc()
+	}
+	return d
+}
+
+// Close releases the store's connections
+func (l *Limiter) Close() error {
+	return l.close()
+}
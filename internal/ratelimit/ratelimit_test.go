@@ -0,0 +1,216 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/config"
+	"github.com/kevindiu/monorepo-go-example/internal/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// failingStore fails every Take, counting the calls
+type failingStore struct {
+	calls int
+}
+
+func (s *failingStore) Take(ctx context.Context, key string, quota Quota) (Decision, error) {
+	s.calls++
+	return Decision{}, errors.New("connection refused")
+}
+
+func TestLocal(t *testing.T) {
+	now := time.Unix(0, 0)
+	store := NewLocal()
+	store.now = func() time.Time { return now }
+	quota := Quota{Requests: 2, Window: time.Second, Burst: 3}
+
+	tests := []struct {
+		name        string
+		advance     time.Duration
+		wantAllowed bool
+		wantLeft    int
+	}{
+		{name: "first", wantAllowed: true, wantLeft: 2},
+		{name: "second", wantAllowed: true, wantLeft: 1},
+		{name: "burst", wantAllowed: true, wantLeft: 0},
+		{name: "exhausted", wantAllowed: false, wantLeft: 0},
+		{name: "refilled one", advance: 500 * time.Millisecond, wantAllowed: true, wantLeft: 0},
+		{name: "refilled to burst", advance: time.Hour, wantAllowed: true, wantLeft: 2},
+	}
+
+	for _, tt := range tests {
+		now = now.Add(tt.advance)
+		d, err := store.Take(context.Background(), "acme", quota)
+		if err != nil {
+			t.Fatalf("%s: Take() error = %v", tt.name, err)
+		}
+		if d.Allowed != tt.wantAllowed || d.Remaining != tt.wantLeft {
+			t.Errorf("%s: Take() = allowed %v remaining %d, want %v %d", tt.name, d.Allowed, d.Remaining, tt.wantAllowed, tt.wantLeft)
+		}
+		if !d.Allowed && d.RetryAfter != 500*time.Millisecond {
+			t.Errorf("%s: RetryAfter = %v, want 500ms", tt.name, d.RetryAfter)
+		}
+	}
+
+	if d, _ := store.Take(context.Background(), "globex", quota); !d.Allowed {
+		t.Error("Take() shared a bucket between keys")
+	}
+}
+
+func TestFallback(t *testing.T) {
+	m := newMetrics()
+	primary := &failingStore{}
+	store := newFallback(primary, NewLocal(), m, log.NewDefault())
+	quota := Quota{Requests: 1, Window: time.Minute}
+
+	if d, err := store.Take(context.Background(), "acme", quota); err != nil || !d.Allowed {
+		t.Fatalf("Take() = %+v, %v, want the local store to allow", d, err)
+	}
+	if d, err := store.Take(context.Background(), "acme", quota); err != nil || d.Allowed {
+		t.Fatalf("Take() = %+v, %v, want the local store to throttle", d, err)
+	}
+	if got := testutil.ToFloat64(m.fallbacks); got != 2 {
+		t.Errorf("fallbacks = %v, want 2", got)
+	}
+
+	// Once the breaker opens, requests skip the failing primary
+	for i := 0; i < 2*breakerThreshold; i++ {
+		store.Take(context.Background(), "acme", quota)
+	}
+	if primary.calls != breakerThreshold {
+		t.Errorf("primary called %d times, want %d before the breaker opens", primary.calls, breakerThreshold)
+	}
+}
+
+func TestLimiter(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	l, err := New(&config.RateLimit{
+		Requests: 1,
+		Window:   time.Minute,
+		Quotas: []config.RateLimitQuota{
+			{Key: "acme", Requests: 2, Window: time.Minute},
+			{Key: "internal"},
+		},
+	}, reg, nil)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer l.Close()
+
+	tests := []struct {
+		key         string
+		wantAllowed []bool
+	}{
+		{key: "default", wantAllowed: []bool{true, false}},
+		{key: "acme", wantAllowed: []bool{true, true, false}},
+		{key: "internal", wantAllowed: []bool{true, true, true}},
+	}
+
+	for _, tt := range tests {
+		for i, want := range tt.wantAllowed {
+			if got := l.Allow(context.Background(), tt.key).Allowed; got != want {
+				t.Errorf("Allow(%s) #%d = %v, want %v", tt.key, i+1, got, want)
+			}
+		}
+	}
+
+	for decision, want := range map[string]float64{decisionAllowed: 3, decisionThrottled: 2, decisionUnlimited: 3} {
+		if got := testutil.ToFloat64(l.metrics.decisions.WithLabelValues(decision)); got != want {
+			t.Errorf("decisions{%s} = %v, want %v", decision, got, want)
+		}
+	}
+}
+
+func TestScoped(t *testing.T) {
+	l, err := New(&config.RateLimit{Requests: 1, Window: time.Minute}, nil, nil)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer l.Close()
+	ips := ClientIP(l, &config.RateLimit{ClientIP: config.RateLimitBucket{Requests: 2, Window: time.Minute}})
+
+	// The same key draws from separate buckets in each scope
+	for i, want := range []bool{true, false} {
+		if got := l.Allow(context.Background(), "10.0.0.1").Allowed; got != want {
+			t.Errorf("Allow() #%d = %v, want %v", i+1, got, want)
+		}
+	}
+	for i, want := range []bool{true, true, false} {
+		if got := ips.Allow(context.Background(), "10.0.0.1").Allowed; got != want {
+			t.Errorf("ClientIP().Allow() #%d = %v, want %v", i+1, got, want)
+		}
+	}
+
+	if ClientIP(l, &config.RateLimit{}) != nil {
+		t.Error("ClientIP() without a quota = limiter, want nil")
+	}
+	if APIKey(l, &config.RateLimit{}) != nil {
+		t.Error("APIKey() without a quota = limiter, want nil")
+	}
+}
+
+func TestNewMethods(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      *config.RateLimit
+		wantNil  bool
+		key      string
+		wantOver bool
+	}{
+		{name: "disabled", cfg: &config.RateLimit{Methods: []config.RateLimitQuota{{Key: "/a.A/B"}}}, wantNil: true},
+		{name: "no methods", cfg: &config.RateLimit{Enabled: true, Requests: 10, Window: time.Second}, wantNil: true},
+		{
+			name:     "method",
+			cfg:      &config.RateLimit{Enabled: true, Methods: []config.RateLimitQuota{{Key: "/a.A/B", Requests: 1, Window: time.Second}}},
+			key:      "/a.A/B",
+			wantOver: true,
+		},
+		{
+			name: "other method",
+			cfg:  &config.RateLimit{Enabled: true, Methods: []config.RateLimitQuota{{Key: "/a.A/B", Requests: 1, Window: time.Second}}},
+			key:  "/a.A/C",
+		},
+	}
+
+	for _, tt := range tests {
+		l, err := NewMethods(tt.cfg, nil, nil)
+		if err != nil {
+			t.Fatalf("%s: NewMethods() error = %v", tt.name, err)
+		}
+		if (l == nil) != tt.wantNil {
+			t.Fatalf("%s: NewMethods() = %v, want nil %v", tt.name, l, tt.wantNil)
+		}
+		if l == nil {
+			continue
+		}
+		if got := l.Overrides(tt.key); got != tt.wantOver {
+			t.Errorf("%s: Overrides(%s) = %v, want %v", tt.name, tt.key, got, tt.wantOver)
+		}
+		// Methods without a quota are unlimited rather than given the
+		// tenant default
+		if !tt.wantOver && l.Allow(context.Background(), tt.key).Limit != 0 {
+			t.Errorf("%s: Allow(%s) was limited", tt.name, tt.key)
+		}
+		l.Close()
+	}
+}
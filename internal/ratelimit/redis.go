@@ -0,0 +1,145 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/breaker"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/internal/log"
+	"github.com/redis/go-redis/v9"
+)
+
+// takeScript refills and takes a token from a bucket stored as a hash of
+// its tokens and last update, in milliseconds of Redis time, so replicas
+// with skewed clocks agree. Buckets expire once they would have refilled.
+var takeScript = redis.NewScript(`
+local capacity = tonumber(ARGV[1])
+local rate = tonumber(ARGV[2])
+local t = redis.call("TIME")
+local now = t[1] * 1000 + math.floor(t[2] / 1000)
+
+local state = redis.call("HMGET", KEYS[1], "tokens", "updated")
+local tokens = tonumber(state[1]) or capacity
+local updated = tonumber(state[2]) or now
+
+tokens = math.min(capacity, tokens + math.max(0, now - updated) / 1000 * rate)
+local allowed = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+end
+
+redis.call("HSET", KEYS[1], "tokens", tostring(tokens), "updated", now)
+redis.call("PEXPIRE", KEYS[1], math.ceil((capacity - tokens) / rate * 1000) + 1000)
+return {allowed, tostring(tokens)}
+`)
+
+// Redis keeps buckets in Redis under a key prefix, so that every replica
+// draws from the same quota
+type Redis struct {
+	client redis.UniversalClient
+	prefix string
+}
+
+// NewRedis creates a Redis-backed store
+func NewRedis(client redis.UniversalClient, prefix string) *Redis {
+	return &Redis{client: client, prefix: prefix}
+}
+
+// Take takes a token from the bucket of key
+func (r *Redis) Take(ctx context.Context, key string, quota Quota) (Decision, error) {
+	capacity, rate := quota.capacity(), quota.rate()
+	res, err := takeScript.Run(ctx, r.client, []string{r.prefix + key},
+		strconv.FormatFloat(capacity, 'f', -1, 64), strconv.FormatFloat(rate, 'f', -1, 64)).Slice()
+	if err != nil {
+		return Decision{}, errors.Wrap(err, "failed to take rate limit token")
+	}
+	if len(res) != 2 {
+		return Decision{}, errors.New("unexpected rate limit script result")
+	}
+	allowed, _ := res[0].(int64)
+	tokensStr, _ := res[1].(string)
+	tokens, err := strconv.ParseFloat(tokensStr, 64)
+	if err != nil {
+		return Decision{}, errors.Wrap(err, "unexpected rate limit script result")
+	}
+
+	d := Decision{Allowed: allowed == 1, Limit: int(capacity), Remaining: int(tokens)}
+	if !d.Allowed {
+		d.RetryAfter = time.Duration(math.Ceil((1 - tokens) / rate * float64(time.Second)))
+	}
+	return d, nil
+}
+
+// Once the primary store has failed breakerThreshold times in a row, it is
+// skipped for breakerCooldown, so that an outage does not hold up every
+// request behind a failing call
+const (
+	breakerThreshold = 3
+	breakerCooldown  = 5 * time.Second
+)
+
+// fallback takes tokens from a primary store, such as Redis, and from a
+// local store while the primary fails, so that an outage loosens quotas to
+// per replica instead of failing or unthrottling requests
+type fallback struct {
+	primary Store
+	local   Store
+	breaker *breaker.Breaker
+	metrics *metrics
+	logger  *log.Logger
+	failing atomic.Bool
+}
+
+// newFallback creates a store that falls back from primary to local
+func newFallback(primary, local Store, m *metrics, logger *log.Logger) *fallback {
+	return &fallback{
+		primary: primary,
+		local:   local,
+		breaker: breaker.New(breakerThreshold, breakerCooldown),
+		metrics: m,
+		logger:  logger,
+	}
+}
+
+// Take takes a token from the primary store, or the local one when the
+// primary fails or its breaker is open
+func (f *fallback) Take(ctx context.Context, key string, quota Quota) (Decision, error) {
+	var d Decision
+	err := f.breaker.Do(func() (err error) {
+		d, err = f.primary.Take(ctx, key, quota)
+		return err
+	})
+	if err == nil {
+		if f.failing.Swap(false) {
+			f.logger.Info("Rate limit store recovered")
+		}
+		return d, nil
+	}
+
+	if !f.failing.Swap(true) {
+		f.logger.Warn("Rate limit store unavailable, limiting per replica", log.Error(err))
+	}
+	f.metrics.fallbacks.Inc()
+	return f.local.Take(ctx, key, quota)
+}
@@ -0,0 +1,109 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package reporting sends internal errors and recovered panics to an error
+// tracker. Reports go to the default reporter, which discards them until
+// Setup installs one.
+package reporting
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/config"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+)
+
+// Reporter drivers
+const (
+	DriverNone   = ""
+	DriverSentry = "sentry"
+)
+
+// Reporter sends errors to an error tracker
+type Reporter interface {
+	// CaptureError reports err with tags describing where it happened
+	CaptureError(ctx context.Context, err error, tags map[string]string)
+	// CapturePanic reports a value recovered from a panic and the stack of
+	// the panicking goroutine
+	CapturePanic(ctx context.Context, recovered interface{}, stack []byte, tags map[string]string)
+	// Flush waits up to timeout for buffered reports to be sent
+	Flush(timeout time.Duration) bool
+}
+
+var (
+	mu       sync.RWMutex
+	reporter Reporter = Nop{}
+)
+
+// Default returns the default reporter
+func Default() Reporter {
+	mu.RLock()
+	defer mu.RUnlock()
+	return reporter
+}
+
+// SetDefault installs r as the default reporter and reports every error the
+// errors package marks CodeInternal to it
+func SetDefault(r Reporter) {
+	mu.Lock()
+	reporter = r
+	mu.Unlock()
+
+	errors.SetReporter(func(err *errors.Error) {
+		r.CaptureError(context.Background(), err, nil)
+	})
+}
+
+// Setup installs the reporter configured for service as the default and
+// returns a function that flushes it. With no reporter configured, reports
+// are discarded.
+func Setup(cfg *config.Observability, service string) (func(), error) {
+	var r Reporter
+	switch cfg.ErrorReporter {
+	case DriverNone:
+		return func() {}, nil
+	case DriverSentry:
+		s, err := NewSentry(SentryConfig{
+			DSN:         cfg.SentryDSN,
+			Environment: cfg.SentryEnvironment,
+			SampleRate:  cfg.SentrySampleRate,
+			Service:     service,
+		})
+		if err != nil {
+			return nil, err
+		}
+		r = s
+	default:
+		return nil, errors.WithCode(errors.Newf("unknown error reporter %q", cfg.ErrorReporter), errors.CodeInvalidInput)
+	}
+
+	SetDefault(r)
+	return func() { r.Flush(2 * time.Second) }, nil
+}
+
+// Nop discards reports
+type Nop struct{}
+
+// CaptureError discards err
+func (Nop) CaptureError(context.Context, error, map[string]string) {}
+
+// CapturePanic discards the panic
+func (Nop) CapturePanic(context.Context, interface{}, []byte, map[string]string) {}
+
+// Flush returns at once
+func (Nop) Flush(timeout time.Duration) bool { return true }
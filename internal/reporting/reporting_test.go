@@ -0,0 +1,122 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package reporting
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/kevindiu/monorepo-go-example/internal/config"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+)
+
+// mockTransport records the events a Sentry client sends
+type mockTransport struct {
+	mu     sync.Mutex
+	events []*sentry.Event
+}
+
+func (t *mockTransport) Configure(sentry.ClientOptions) {}
+
+func (t *mockTransport) SendEvent(event *sentry.Event) {
+	t.mu.Lock()
+	t.events = append(t.events, event)
+	t.mu.Unlock()
+}
+
+func (t *mockTransport) Flush(time.Duration) bool { return true }
+
+func newTestSentry(t *testing.T) (*Sentry, *mockTransport) {
+	t.Helper()
+	transport := &mockTransport{}
+	s, err := NewSentry(SentryConfig{
+		DSN:        "https://key@sentry.example.com/1",
+		SampleRate: 1,
+		Service:    "order-service",
+		Transport:  transport,
+	})
+	if err != nil {
+		t.Fatalf("NewSentry() error = %v", err)
+	}
+	return s, transport
+}
+
+func TestSentry(t *testing.T) {
+	s, transport := newTestSentry(t)
+
+	s.CaptureError(context.Background(), errors.New("connection reset"), map[string]string{"method": "/order.v1.OrderService/GetOrder"})
+	s.CapturePanic(context.Background(), "nil map", []byte("goroutine 1"), nil)
+
+	if len(transport.events) != 2 {
+		t.Fatalf("sent %d events, want 2", len(transport.events))
+	}
+	errEvent, panicEvent := transport.events[0], transport.events[1]
+	if errEvent.Tags["service"] != "order-service" || errEvent.Tags["method"] != "/order.v1.OrderService/GetOrder" {
+		t.Errorf("error event tags = %v", errEvent.Tags)
+	}
+	if len(errEvent.Exception) == 0 || errEvent.Exception[len(errEvent.Exception)-1].Value != "connection reset" {
+		t.Errorf("error event exception = %+v", errEvent.Exception)
+	}
+	if panicEvent.Level != sentry.LevelFatal || panicEvent.Message != "panic: nil map" {
+		t.Errorf("panic event = level %s, message %q", panicEvent.Level, panicEvent.Message)
+	}
+	if panicEvent.Extra["stack"] != "goroutine 1" {
+		t.Errorf("panic event stack = %v", panicEvent.Extra["stack"])
+	}
+}
+
+func TestSetDefaultReportsInternalErrors(t *testing.T) {
+	s, transport := newTestSentry(t)
+	SetDefault(s)
+	defer SetDefault(Nop{})
+
+	errors.WithCode(errors.New("missing order"), errors.CodeNotFound)
+	errors.WithCode(errors.New("connection reset"), errors.CodeInternal)
+
+	if len(transport.events) != 1 {
+		t.Errorf("sent %d events, want only the internal error", len(transport.events))
+	}
+}
+
+func TestSetup(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     config.Observability
+		wantErr bool
+	}{
+		{name: "none", cfg: config.Observability{}},
+		{name: "sentry", cfg: config.Observability{ErrorReporter: DriverSentry, SentryDSN: "https://key@sentry.example.com/1"}},
+		{name: "invalid dsn", cfg: config.Observability{ErrorReporter: DriverSentry, SentryDSN: "not a dsn"}, wantErr: true},
+		{name: "unknown driver", cfg: config.Observability{ErrorReporter: "rollbar"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer SetDefault(Nop{})
+			flush, err := Setup(&tt.cfg, "order-service")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Setup() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil {
+				flush()
+			}
+		})
+	}
+}
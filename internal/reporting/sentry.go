@@ -0,0 +1,89 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package reporting
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+)
+
+// SentryConfig configures a Sentry reporter
+type SentryConfig struct {
+	DSN         string
+	Environment string
+	// SampleRate is the fraction of errors sent
+	SampleRate float64
+	// Service tags every report
+	Service string
+	// Transport overrides how events are sent, for tests
+	Transport sentry.Transport
+}
+
+// Sentry reports to Sentry
+type Sentry struct {
+	hub *sentry.Hub
+}
+
+// NewSentry creates a Sentry reporter
+func NewSentry(cfg SentryConfig) (*Sentry, error) {
+	client, err := sentry.NewClient(sentry.ClientOptions{
+		Dsn:         cfg.DSN,
+		Environment: cfg.Environment,
+		SampleRate:  cfg.SampleRate,
+		ServerName:  cfg.Service,
+		Transport:   cfg.Transport,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create sentry client")
+	}
+
+	scope := sentry.NewScope()
+	scope.SetTag("service", cfg.Service)
+	return &Sentry{hub: sentry.NewHub(client, scope)}, nil
+}
+
+// CaptureError reports err
+func (s *Sentry) CaptureError(ctx context.Context, err error, tags map[string]string) {
+	s.hub.WithScope(func(scope *sentry.Scope) {
+		scope.SetTags(tags)
+		s.hub.CaptureException(err)
+	})
+}
+
+// CapturePanic reports a recovered panic. The stack is attached as is,
+// since the panicking frames are gone by the time the event is built.
+func (s *Sentry) CapturePanic(ctx context.Context, recovered interface{}, stack []byte, tags map[string]string) {
+	s.hub.WithScope(func(scope *sentry.Scope) {
+		scope.SetTags(tags)
+		scope.SetLevel(sentry.LevelFatal)
+		scope.SetExtra("stack", string(stack))
+		if err, ok := recovered.(error); ok {
+			s.hub.CaptureException(err)
+			return
+		}
+		s.hub.CaptureMessage(fmt.Sprintf("panic: %v", recovered))
+	})
+}
+
+// Flush waits up to timeout for buffered events to be sent
+func (s *Sentry) Flush(timeout time.Duration) bool {
+	return s.hub.Flush(timeout)
+}
@@ -0,0 +1,218 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package retention
+
+import (
+	"context"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/config"
+	"github.com/kevindiu/monorepo-go-example/internal/db"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/internal/log"
+)
+
+// OrdersChanged is called with the orders a policy changed once the change
+// is committed, so that cached copies of them can be invalidated
+type OrdersChanged func(ctx context.Context, orders []Record) error
+
+// New creates a runner enforcing the configured policies. Policies with a
+// zero retention period are disabled. changed may be nil.
+func New(cfg *config.Retention, database *db.DB, auditor Auditor, changed OrdersChanged, logger *log.Logger) *Runner {
+	var policies []Policy
+	if cfg.UserPurgeAfter > 0 {
+		policies = append(policies, NewUserPurge(database, cfg.UserPurgeAfter, changed))
+	}
+	if cfg.OrderAnonymizeAfter > 0 {
+		policies = append(policies, NewOrderAnonymization(database, cfg.OrderAnonymizeAfter, changed))
+	}
+	return NewRunner(Config{
+		Policies:  policies,
+		Auditor:   auditor,
+		BatchSize: cfg.BatchSize,
+		DryRun:    cfg.DryRun,
+		Logger:    logger,
+	})
+}
+
+// userPurge deletes users that were soft-deleted longer ago than the
+// retention period. Their orders are kept but detached from them.
+type userPurge struct {
+	db      *db.DB
+	after   time.Duration
+	changed OrdersChanged
+}
+
+// NewUserPurge creates a policy purging users soft-deleted more than after
+// ago. changed, if not nil, is told which orders were detached.
+func NewUserPurge(database *db.DB, after time.Duration, changed OrdersChanged) Policy {
+	return &userPurge{db: database, after: after, changed: changed}
+}
+
+func (p *userPurge) Name() string             { return "user-purge" }
+func (p *userPurge) Action() string           { return "retention.purge" }
+func (p *userPurge) ResourceType() string     { return "user" }
+func (p *userPurge) Retention() time.Duration { return p.after }
+
+// Eligible returns users soft-deleted before cutoff
+func (p *userPurge) Eligible(ctx context.Context, cutoff time.Time, limit int) ([]Record, error) {
+	query := `
+		SELECT tenant_id, id
+		FROM users
+		WHERE deleted_at IS NOT NULL AND deleted_at < $1
+		ORDER BY deleted_at
+		LIMIT $2
+	`
+	return queryRecords(ctx, p.db, query, cutoff, limit)
+}
+
+// Apply anonymizes the orders of each user and deletes the user, together
+// with its sessions, in one transaction
+func (p *userPurge) Apply(ctx context.Context, records []Record) ([]Record, error) {
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	var purged, orders []Record
+	for _, rec := range records {
+		detached, err := queryRecords(ctx, tx, `
+			UPDATE orders
+			SET user_id = NULL, anonymized_at = COALESCE(anonymized_at, $3)
+			WHERE tenant_id = $1 AND user_id = $2
+			RETURNING tenant_id, id
+		`, rec.TenantID, rec.ID, now)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to anonymize orders of purged user")
+		}
+		orders = append(orders, detached...)
+
+		result, err := tx.ExecContext(ctx,
+			`DELETE FROM users WHERE tenant_id = $1 AND id = $2 AND deleted_at IS NOT NULL`,
+			rec.TenantID, rec.ID,
+		)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to purge user")
+		}
+		if n, err := result.RowsAffected(); err == nil && n > 0 {
+			purged = append(purged, rec)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, errors.Wrap(err, "failed to commit user purge")
+	}
+	return purged, notify(ctx, p.changed, orders)
+}
+
+// orderAnonymization detaches orders older than the retention period from
+// the users who placed them
+type orderAnonymization struct {
+	db      *db.DB
+	after   time.Duration
+	changed OrdersChanged
+}
+
+// NewOrderAnonymization creates a policy anonymizing orders created more
+// than after ago. changed, if not nil, is told which orders were
+// anonymized.
+func NewOrderAnonymization(database *db.DB, after time.Duration, changed OrdersChanged) Policy {
+	return &orderAnonymization{db: database, after: after, changed: changed}
+}
+
+func (p *orderAnonymization) Name() string             { return "order-anonymization" }
+func (p *orderAnonymization) Action() string           { return "retention.anonymize" }
+func (p *orderAnonymization) ResourceType() string     { return "order" }
+func (p *orderAnonymization) Retention() time.Duration { return p.after }
+
+// Eligible returns orders created before cutoff that are not yet anonymized
+func (p *orderAnonymization) Eligible(ctx context.Context, cutoff time.Time, limit int) ([]Record, error) {
+	query := `
+		SELECT tenant_id, id
+		FROM orders
+		WHERE anonymized_at IS NULL AND created_at < $1
+		ORDER BY created_at
+		LIMIT $2
+	`
+	return queryRecords(ctx, p.db, query, cutoff, limit)
+}
+
+// Apply clears the user of each order
+func (p *orderAnonymization) Apply(ctx context.Context, records []Record) ([]Record, error) {
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	var anonymized []Record
+	for _, rec := range records {
+		result, err := tx.ExecContext(ctx, `
+			UPDATE orders
+			SET user_id = NULL, anonymized_at = $3
+			WHERE tenant_id = $1 AND id = $2 AND anonymized_at IS NULL
+		`, rec.TenantID, rec.ID, now)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to anonymize order")
+		}
+		if n, err := result.RowsAffected(); err == nil && n > 0 {
+			anonymized = append(anonymized, rec)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, errors.Wrap(err, "failed to commit order anonymization")
+	}
+	return anonymized, notify(ctx, p.changed, anonymized)
+}
+
+// notify tells changed about committed order changes. The records stay
+// applied when it fails.
+func notify(ctx context.Context, changed OrdersChanged, orders []Record) error {
+	if changed == nil || len(orders) == 0 {
+		return nil
+	}
+	if err := changed(ctx, orders); err != nil {
+		return errors.Wrap(err, "failed to report changed orders")
+	}
+	return nil
+}
+
+// queryRecords runs a query selecting tenant IDs and IDs
+func queryRecords(ctx context.Context, database db.Executor, query string, args ...interface{}) ([]Record, error) {
+	rows, err := database.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to select rows past retention")
+	}
+	defer rows.Close()
+
+	var records []Record
+	for rows.Next() {
+		var rec Record
+		if err := rows.Scan(&rec.TenantID, &rec.ID); err != nil {
+			return nil, errors.Wrap(err, "failed to scan row past retention")
+		}
+		records = append(records, rec)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "error iterating rows past retention")
+	}
+	return records, nil
+}
@@ -0,0 +1,196 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package retention enforces data retention policies: rows past their
+// retention period are purged or anonymized, and every affected row is
+// recorded in the audit log
+package retention
+
+import (
+	"context"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/audit"
+	"github.com/kevindiu/monorepo-go-example/internal/log"
+	"github.com/kevindiu/monorepo-go-example/internal/scheduler"
+)
+
+// JobName is the name of the scheduled retention job
+const JobName = "data-retention"
+
+// Actor is the audit actor of retention actions
+const Actor = "retention"
+
+// DefaultBatchSize is the number of rows a policy processes per batch
+const DefaultBatchSize = 500
+
+// Record identifies a row affected by a policy
+type Record struct {
+	TenantID string
+	ID       string
+}
+
+// Policy is a retention rule for one entity
+type Policy interface {
+	// Name identifies the policy in logs and reports
+	Name() string
+	// Action is the audit action recorded for each affected row
+	Action() string
+	// ResourceType is the audit resource type of affected rows
+	ResourceType() string
+	// Retention is how long rows are kept before the policy applies
+	Retention() time.Duration
+	// Eligible returns up to limit rows the policy applies to as of cutoff
+	Eligible(ctx context.Context, cutoff time.Time, limit int) ([]Record, error)
+	// Apply purges or anonymizes records, returning those it changed, even
+	// when it fails after committing them
+	Apply(ctx context.Context, records []Record) ([]Record, error)
+}
+
+// Auditor records audit events; *audit.Recorder implements it
+type Auditor interface {
+	Record(e *audit.Event)
+}
+
+// Config holds retention runner configuration
+type Config struct {
+	Policies  []Policy
+	Auditor   Auditor
+	BatchSize int
+	// DryRun reports eligible rows without changing or auditing them
+	DryRun bool
+	Logger *log.Logger
+}
+
+// Result is the outcome of running one policy
+type Result struct {
+	Policy  string
+	Records []Record
+	DryRun  bool
+}
+
+// Runner applies retention policies
+type Runner struct {
+	policies  []Policy
+	auditor   Auditor
+	batchSize int
+	dryRun    bool
+	logger    *log.Logger
+}
+
+// NewRunner creates a retention runner
+func NewRunner(cfg Config) *Runner {
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = DefaultBatchSize
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = log.NewDefault()
+	}
+	return &Runner{
+		policies:  cfg.Policies,
+		auditor:   cfg.Auditor,
+		batchSize: cfg.BatchSize,
+		dryRun:    cfg.DryRun,
+		logger:    cfg.Logger.Named("retention"),
+	}
+}
+
+// Run applies every policy to the rows past its retention period. A dry run
+// reports at most one batch per policy.
+func (r *Runner) Run(ctx context.Context) ([]*Result, error) {
+	now := time.Now()
+	results := make([]*Result, 0, len(r.policies))
+	for _, p := range r.policies {
+		result, err := r.run(ctx, p, now.Add(-p.Retention()))
+		if err != nil {
+			return results, err
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// run applies p batch by batch until no eligible rows remain
+func (r *Runner) run(ctx context.Context, p Policy, cutoff time.Time) (*Result, error) {
+	result := &Result{Policy: p.Name(), DryRun: r.dryRun}
+	for {
+		eligible, err := p.Eligible(ctx, cutoff, r.batchSize)
+		if err != nil {
+			return result, err
+		}
+		if r.dryRun {
+			result.Records = eligible
+			if len(eligible) > 0 {
+				r.logger.Info("Retention dry run",
+					log.String("policy", p.Name()),
+					log.Int("eligible", len(eligible)),
+					log.Any("cutoff", cutoff),
+				)
+			}
+			return result, nil
+		}
+		if len(eligible) == 0 {
+			return result, nil
+		}
+
+		applied, err := p.Apply(ctx, eligible)
+		for _, rec := range applied {
+			r.audit(p, rec)
+		}
+		result.Records = append(result.Records, applied...)
+		if err != nil {
+			return result, err
+		}
+		if len(applied) > 0 {
+			r.logger.Info("Applied retention policy",
+				log.String("policy", p.Name()),
+				log.Int("count", len(applied)),
+			)
+		}
+
+		// A short batch was the last one; a batch of which nothing was
+		// applied would otherwise be selected again forever
+		if len(eligible) < r.batchSize || len(applied) == 0 {
+			return result, nil
+		}
+	}
+}
+
+// audit records the action taken on rec
+func (r *Runner) audit(p Policy, rec Record) {
+	if r.auditor == nil {
+		return
+	}
+	event := audit.NewEvent(Actor, Actor, p.Action(), p.ResourceType(), rec.ID, audit.OutcomeSuccess)
+	event.Metadata = map[string]string{
+		"tenant_id": rec.TenantID,
+		"policy":    p.Name(),
+	}
+	r.auditor.Record(event)
+}
+
+// NewJob returns a scheduled job that runs the runner
+func NewJob(runner *Runner, schedule string) scheduler.Job {
+	return scheduler.Job{
+		Name:     JobName,
+		Schedule: schedule,
+		Timeout:  time.Hour,
+		Run: func(ctx context.Context) error {
+			_, err := runner.Run(ctx)
+			return err
+		},
+	}
+}
@@ -0,0 +1,162 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package retention
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/audit"
+)
+
+// mockPolicy keeps eligible rows in memory
+type mockPolicy struct {
+	rows    []Record
+	applied int
+	// skip makes Apply leave rows unchanged, as when they were restored
+	skip bool
+	// err is returned by Apply after the rows are changed
+	err error
+}
+
+func newMockPolicy(n int) *mockPolicy {
+	p := &mockPolicy{}
+	for i := 0; i < n; i++ {
+		p.rows = append(p.rows, Record{TenantID: "default", ID: fmt.Sprintf("row-%d", i)})
+	}
+	return p
+}
+
+func (p *mockPolicy) Name() string             { return "mock" }
+func (p *mockPolicy) Action() string           { return "retention.purge" }
+func (p *mockPolicy) ResourceType() string     { return "user" }
+func (p *mockPolicy) Retention() time.Duration { return time.Hour }
+
+func (p *mockPolicy) Eligible(ctx context.Context, cutoff time.Time, limit int) ([]Record, error) {
+	if len(p.rows) < limit {
+		limit = len(p.rows)
+	}
+	return append([]Record(nil), p.rows[:limit]...), nil
+}
+
+func (p *mockPolicy) Apply(ctx context.Context, records []Record) ([]Record, error) {
+	p.applied++
+	if p.skip {
+		return nil, nil
+	}
+	p.rows = p.rows[len(records):]
+	return records, p.err
+}
+
+// mockAuditor collects audit events
+type mockAuditor struct {
+	events []*audit.Event
+}
+
+func (a *mockAuditor) Record(e *audit.Event) {
+	a.events = append(a.events, e)
+}
+
+func TestRunnerRun(t *testing.T) {
+	tests := []struct {
+		name        string
+		rows        int
+		dryRun      bool
+		skip        bool
+		wantRecords int
+		wantApplied int
+		wantAudited int
+		wantLeft    int
+	}{
+		{name: "applies in batches", rows: 5, wantRecords: 5, wantApplied: 3, wantAudited: 5},
+		{name: "exact batch multiple", rows: 4, wantRecords: 4, wantApplied: 2, wantAudited: 4},
+		{name: "nothing eligible", rows: 0},
+		{name: "dry run changes nothing", rows: 5, dryRun: true, wantRecords: 2, wantLeft: 5},
+		{name: "stops when nothing applies", rows: 5, skip: true, wantApplied: 1, wantLeft: 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := newMockPolicy(tt.rows)
+			policy.skip = tt.skip
+			auditor := &mockAuditor{}
+			runner := NewRunner(Config{
+				Policies:  []Policy{policy},
+				Auditor:   auditor,
+				BatchSize: 2,
+				DryRun:    tt.dryRun,
+			})
+
+			results, err := runner.Run(context.Background())
+			if err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+			if len(results) != 1 || len(results[0].Records) != tt.wantRecords {
+				t.Fatalf("Run() results = %+v, want %d records", results, tt.wantRecords)
+			}
+			if results[0].DryRun != tt.dryRun {
+				t.Errorf("DryRun = %v, want %v", results[0].DryRun, tt.dryRun)
+			}
+			if policy.applied != tt.wantApplied {
+				t.Errorf("Apply called %d times, want %d", policy.applied, tt.wantApplied)
+			}
+			if len(auditor.events) != tt.wantAudited {
+				t.Errorf("audited %d rows, want %d", len(auditor.events), tt.wantAudited)
+			}
+			if len(policy.rows) != tt.wantLeft {
+				t.Errorf("%d rows left, want %d", len(policy.rows), tt.wantLeft)
+			}
+		})
+	}
+}
+
+func TestRunnerAuditsAffectedRows(t *testing.T) {
+	auditor := &mockAuditor{}
+	runner := NewRunner(Config{Policies: []Policy{newMockPolicy(1)}, Auditor: auditor})
+
+	if _, err := runner.Run(context.Background()); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if len(auditor.events) != 1 {
+		t.Fatalf("audited %d rows, want 1", len(auditor.events))
+	}
+
+	e := auditor.events[0]
+	if e.Actor != Actor || e.Action != "retention.purge" || e.ResourceType != "user" || e.ResourceID != "row-0" {
+		t.Errorf("audit event = %+v", e)
+	}
+	if e.Metadata["tenant_id"] != "default" || e.Metadata["policy"] != "mock" {
+		t.Errorf("audit metadata = %v", e.Metadata)
+	}
+}
+
+func TestRunnerAuditsRowsOfFailedApply(t *testing.T) {
+	auditor := &mockAuditor{}
+	policy := newMockPolicy(2)
+	policy.err = errors.New("cache down")
+	runner := NewRunner(Config{Policies: []Policy{policy}, Auditor: auditor})
+
+	if _, err := runner.Run(context.Background()); err == nil {
+		t.Fatal("Run() should fail when Apply fails")
+	}
+	if len(auditor.events) != 2 {
+		t.Errorf("audited %d rows, want the 2 rows Apply changed", len(auditor.events))
+	}
+}
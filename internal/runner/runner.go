@@ -0,0 +1,199 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package runner coordinates the startup and shutdown of a process's
+// components. Components start in dependency order and stop in reverse,
+// each under its own deadline, so a server drains its in-flight requests
+// before the database it depends on is closed, and one stuck component
+// cannot keep the rest from stopping.
+package runner
+
+import (
+	"context"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/internal/log"
+)
+
+// DefaultStopTimeout bounds a Stop that does not set its own timeout
+const DefaultStopTimeout = 10 * time.Second
+
+// Component is a part of a process, such as a database, a repository or a
+// server built on them. It starts after the components it depends on and
+// stops before them.
+type Component struct {
+	Name string
+	// DependsOn names the components that must start first
+	DependsOn []string
+	// Start initializes the component; nil has nothing to start
+	Start func(ctx context.Context) error
+	// Stop releases the component, draining the work it has in flight;
+	// nil has nothing to release
+	Stop func(ctx context.Context) error
+	// StopTimeout bounds Stop; zero uses DefaultStopTimeout
+	StopTimeout time.Duration
+}
+
+// Run starts the components and blocks until ctx is cancelled or SIGINT or
+// SIGTERM arrives, then stops them. It logs through the logger of ctx and
+// returns the start error, or an error naming the components that did not
+// stop cleanly.
+func Run(ctx context.Context, components ...Component) error {
+	logger := log.FromContext(ctx)
+	ctx, stop := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	started, err := Start(ctx, logger, components)
+	if err != nil {
+		return err
+	}
+	<-ctx.Done()
+
+	logger.Info("Shutting down")
+	if failed := Shutdown(logger, Reverse(started)); len(failed) > 0 {
+		return errors.Newf("components did not stop cleanly: %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// Start starts the components, each after its dependencies, and returns
+// them in the order they started. When one fails, those already started
+// are stopped in reverse order and the error names the failed component.
+func Start(ctx context.Context, logger *log.Logger, components []Component) ([]Component, error) {
+	order, err := Order(components)
+	if err != nil {
+		return nil, err
+	}
+
+	started := make([]Component, 0, len(order))
+	for _, c := range order {
+		if c.Start != nil {
+			start := time.Now()
+			if err := c.Start(ctx); err != nil {
+				Shutdown(logger, Reverse(started))
+				return nil, errors.Wrapf(err, "failed to start %s", c.Name)
+			}
+			logger.Info("Component started", log.String("component", c.Name), log.Any("duration", time.Since(start)))
+		}
+		started = append(started, c)
+	}
+	return started, nil
+}
+
+// Shutdown stops the components in the order given, each under its own
+// deadline. A component that fails or overruns is logged and abandoned,
+// and Shutdown returns the names of those components.
+func Shutdown(logger *log.Logger, components []Component) []string {
+	var failed []string
+	for _, c := range components {
+		if c.Stop == nil {
+			continue
+		}
+		timeout := c.StopTimeout
+		if timeout <= 0 {
+			timeout = DefaultStopTimeout
+		}
+
+		start := time.Now()
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+
+		done := make(chan error, 1)
+		go func(c Component) { done <- c.Stop(ctx) }(c)
+
+		var err error
+		select {
+		case err = <-done:
+		case <-ctx.Done():
+			err = ctx.Err()
+		}
+		cancel()
+
+		stageLogger := logger.With(log.String("stage", c.Name), log.Any("duration", time.Since(start)))
+		if err != nil {
+			stageLogger.Warn("Shutdown stage did not complete cleanly", log.Error(err))
+			failed = append(failed, c.Name)
+			continue
+		}
+		stageLogger.Info("Shutdown stage complete")
+	}
+	return failed
+}
+
+// Reverse returns the components in reverse order, the order in which
+// started components stop
+func Reverse(components []Component) []Component {
+	reversed := make([]Component, len(components))
+	for i, c := range components {
+		reversed[len(components)-1-i] = c
+	}
+	return reversed
+}
+
+// Order sorts the components so that each follows its dependencies,
+// keeping the order they were given in otherwise
+func Order(components []Component) ([]Component, error) {
+	byName := make(map[string]Component, len(components))
+	for _, c := range components {
+		if c.Name == "" {
+			return nil, errors.New("component name is required")
+		}
+		if _, ok := byName[c.Name]; ok {
+			return nil, errors.Newf("component %s is added twice", c.Name)
+		}
+		byName[c.Name] = c
+	}
+
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := make(map[string]int, len(components))
+	order := make([]Component, 0, len(components))
+
+	var visit func(c Component, path []string) error
+	visit = func(c Component, path []string) error {
+		switch state[c.Name] {
+		case visited:
+			return nil
+		case visiting:
+			return errors.Newf("dependency cycle: %s", strings.Join(append(path, c.Name), " -> "))
+		}
+		state[c.Name] = visiting
+		for _, dep := range c.DependsOn {
+			d, ok := byName[dep]
+			if !ok {
+				return errors.Newf("component %s depends on unknown component %s", c.Name, dep)
+			}
+			if err := visit(d, append(path, c.Name)); err != nil {
+				return err
+			}
+		}
+		state[c.Name] = visited
+		order = append(order, c)
+		return nil
+	}
+
+	for _, c := range components {
+		if err := visit(c, nil); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
@@ -0,0 +1,155 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package runner
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recorder records component starts and stops
+type recorder struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (r *recorder) record(event string) {
+	r.mu.Lock()
+	r.events = append(r.events, event)
+	r.mu.Unlock()
+}
+
+func (r *recorder) component(name string, deps ...string) Component {
+	return Component{
+		Name:      name,
+		DependsOn: deps,
+		Start: func(context.Context) error {
+			r.record("start " + name)
+			return nil
+		},
+		Stop: func(context.Context) error {
+			r.record("stop " + name)
+			return nil
+		},
+	}
+}
+
+func TestRun(t *testing.T) {
+	r := &recorder{}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- Run(ctx, r.component("server", "database"), r.component("database"))
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	// The server drains before the database it uses closes
+	want := []string{"start database", "start server", "stop server", "stop database"}
+	if !reflect.DeepEqual(r.events, want) {
+		t.Errorf("events = %v, want %v", r.events, want)
+	}
+}
+
+func TestRunReportsStuckComponents(t *testing.T) {
+	r := &recorder{}
+	stuck := Component{
+		Name:        "server",
+		DependsOn:   []string{"database"},
+		StopTimeout: 10 * time.Millisecond,
+		Stop: func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := Run(ctx, stuck, r.component("database"))
+	if err == nil || !strings.Contains(err.Error(), "server") {
+		t.Fatalf("Run() error = %v, want the stuck component named", err)
+	}
+	// A stuck component does not keep the rest from stopping
+	if want := []string{"start database", "stop database"}; !reflect.DeepEqual(r.events, want) {
+		t.Errorf("events = %v, want %v", r.events, want)
+	}
+}
+
+func TestOrder(t *testing.T) {
+	tests := []struct {
+		name       string
+		components []Component
+		want       []string
+		wantErr    string
+	}{
+		{
+			name: "dependency order",
+			components: []Component{
+				{Name: "service", DependsOn: []string{"repository"}},
+				{Name: "repository", DependsOn: []string{"database"}},
+				{Name: "database"},
+				{Name: "events"},
+			},
+			want: []string{"database", "repository", "service", "events"},
+		},
+		{
+			name:       "unknown dependency",
+			components: []Component{{Name: "service", DependsOn: []string{"database"}}},
+			wantErr:    "unknown component database",
+		},
+		{
+			name:       "cycle",
+			components: []Component{{Name: "a", DependsOn: []string{"b"}}, {Name: "b", DependsOn: []string{"a"}}},
+			wantErr:    "dependency cycle: a -> b -> a",
+		},
+		{
+			name:       "unnamed",
+			components: []Component{{}},
+			wantErr:    "name is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order, err := Order(tt.components)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Order() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Order() error = %v", err)
+			}
+			var names []string
+			for _, c := range order {
+				names = append(names, c.Name)
+			}
+			if !reflect.DeepEqual(names, tt.want) {
+				t.Errorf("Order() = %v, want %v", names, tt.want)
+			}
+		})
+	}
+}
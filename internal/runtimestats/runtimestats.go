@@ -0,0 +1,164 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package runtimestats periodically logs goroutine, memory, GC and database
+// pool statistics, so that slowdowns can be investigated from the logs.
+package runtimestats
+
+import (
+	"context"
+	"database/sql"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+)
+
+// Config configures a Reporter
+type Config struct {
+	// Interval between reports; zero disables logging
+	Interval time.Duration
+	// Registerer exports the pool stats of watched databases; nil disables
+	// exporting
+	Registerer prometheus.Registerer
+	Logger     *log.Logger
+}
+
+// Reporter logs runtime and database pool statistics every interval
+type Reporter struct {
+	interval   time.Duration
+	registerer prometheus.Registerer
+	logger     *log.Logger
+
+	mu  sync.Mutex
+	dbs []watchedDB
+}
+
+type watchedDB struct {
+	name string
+	db   *sql.DB
+	last sql.DBStats
+}
+
+// New creates a reporter
+func New(cfg Config) *Reporter {
+	if cfg.Logger == nil {
+		cfg.Logger = log.NewDefault()
+	}
+	return &Reporter{
+		interval:   cfg.Interval,
+		registerer: cfg.Registerer,
+		logger:     cfg.Logger,
+	}
+}
+
+// WatchDB adds a database's connection pool to the reports and exports its
+// stats as go_sql_* metrics labelled with name
+func (r *Reporter) WatchDB(name string, db *sql.DB) error {
+	if r.registerer != nil {
+		if err := r.registerer.Register(collectors.NewDBStatsCollector(db, name)); err != nil {
+			return err
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.dbs = append(r.dbs, watchedDB{name: name, db: db, last: db.Stats()})
+	return nil
+}
+
+// Run logs a report every interval until ctx is done. It returns at once
+// when reporting is disabled.
+func (r *Reporter) Run(ctx context.Context) error {
+	if r.interval <= 0 {
+		return nil
+	}
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	var last runtime.MemStats
+	runtime.ReadMemStats(&last)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			last = r.report(last)
+		}
+	}
+}
+
+// report logs the stats accumulated since last and returns the current
+// memory stats
+func (r *Reporter) report(last runtime.MemStats) runtime.MemStats {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	r.logger.Info("Runtime stats",
+		log.Int("goroutines", runtime.NumGoroutine()),
+		log.Any("heap_alloc_bytes", mem.HeapAlloc),
+		log.Any("heap_inuse_bytes", mem.HeapInuse),
+		log.Any("sys_bytes", mem.Sys),
+		log.Any("gc_cycles", mem.NumGC-last.NumGC),
+		log.Any("gc_pause_total", time.Duration(mem.PauseTotalNs-last.PauseTotalNs)),
+		log.Any("gc_pause_max", maxPause(&mem, last.NumGC)),
+	)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.dbs {
+		w := &r.dbs[i]
+		stats := w.db.Stats()
+		r.logger.Info("Database pool stats",
+			log.String("db", w.name),
+			log.Int("open", stats.OpenConnections),
+			log.Int("in_use", stats.InUse),
+			log.Int("idle", stats.Idle),
+			log.Int("max_open", stats.MaxOpenConnections),
+			log.Int64("wait_count", stats.WaitCount-w.last.WaitCount),
+			log.Any("wait_duration", stats.WaitDuration-w.last.WaitDuration),
+			log.Int64("max_idle_closed", stats.MaxIdleClosed-w.last.MaxIdleClosed),
+			log.Int64("max_idle_time_closed", stats.MaxIdleTimeClosed-w.last.MaxIdleTimeClosed),
+			log.Int64("max_lifetime_closed", stats.MaxLifetimeClosed-w.last.MaxLifetimeClosed),
+		)
+		w.last = stats
+	}
+
+	return mem
+}
+
+// maxPause returns the longest GC pause since the lastNumGC-th cycle. The
+// runtime keeps only the most recent 256 pauses.
+func maxPause(mem *runtime.MemStats, lastNumGC uint32) time.Duration {
+	cycles := mem.NumGC - lastNumGC
+	if cycles > uint32(len(mem.PauseNs)) {
+		cycles = uint32(len(mem.PauseNs))
+	}
+
+	var longest uint64
+	for i := uint32(0); i < cycles; i++ {
+		pause := mem.PauseNs[(mem.NumGC-i+255)%256]
+		if pause > longest {
+			longest = pause
+		}
+	}
+	return time.Duration(longest)
+}
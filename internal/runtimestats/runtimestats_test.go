@@ -0,0 +1,126 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package runtimestats
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// nopConnector opens a database that never connects, which is enough for
+// pool stats
+type nopConnector struct{}
+
+func (nopConnector) Connect(context.Context) (driver.Conn, error) {
+	return nil, errors.New("not connected")
+}
+
+func (nopConnector) Driver() driver.Driver { return nil }
+
+func TestReport(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	registry := prometheus.NewRegistry()
+	r := New(Config{Interval: time.Minute, Registerer: registry, Logger: &log.Logger{Logger: zap.New(core)}})
+
+	db := sql.OpenDB(nopConnector{})
+	defer db.Close()
+	if err := r.WatchDB("primary", db); err != nil {
+		t.Fatalf("WatchDB() error = %v", err)
+	}
+
+	var last runtime.MemStats
+	runtime.ReadMemStats(&last)
+	runtime.GC()
+	r.report(last)
+
+	entries := logs.All()
+	if len(entries) != 2 {
+		t.Fatalf("report() logged %d entries, want 2", len(entries))
+	}
+	runtimeFields := entries[0].ContextMap()
+	if runtimeFields["goroutines"] == nil || runtimeFields["gc_cycles"] == uint32(0) {
+		t.Errorf("runtime stats = %v, want goroutines and at least one GC cycle", runtimeFields)
+	}
+	if db := entries[1].ContextMap()["db"]; db != "primary" {
+		t.Errorf("pool stats db = %v, want primary", db)
+	}
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Gather() error = %v", err)
+	}
+	exported := false
+	for _, f := range families {
+		if strings.HasPrefix(f.GetName(), "go_sql_") {
+			exported = true
+		}
+	}
+	if !exported {
+		t.Error("WatchDB() did not export pool stats")
+	}
+}
+
+func TestRunDisabled(t *testing.T) {
+	done := make(chan error, 1)
+	go func() { done <- New(Config{}).Run(context.Background()) }()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Run() error = %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Error("Run() should return at once when disabled")
+	}
+}
+
+func TestMaxPause(t *testing.T) {
+	var mem runtime.MemStats
+	mem.NumGC = 3
+	mem.PauseNs[0] = 100
+	mem.PauseNs[1] = 300
+	mem.PauseNs[2] = 200
+
+	tests := []struct {
+		name      string
+		lastNumGC uint32
+		want      time.Duration
+	}{
+		{name: "no cycles", lastNumGC: 3, want: 0},
+		{name: "latest cycle", lastNumGC: 2, want: 200},
+		{name: "all cycles", lastNumGC: 0, want: 300},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := maxPause(&mem, tt.lastNumGC); got != tt.want {
+				t.Errorf("maxPause() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,223 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package saga
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/internal/log"
+)
+
+// Status represents the lifecycle state of a saga
+type Status string
+
+// Saga statuses
+const (
+	StatusRunning      Status = "running"
+	StatusCompleted    Status = "completed"
+	StatusCompensating Status = "compensating"
+	StatusCompensated  Status = "compensated"
+)
+
+// State is the persisted progress of a saga instance. Step is the index of
+// the next step to run while running, or the next step to compensate while
+// compensating.
+type State struct {
+	ID        string
+	Name      string
+	Status    Status
+	Step      int
+	Data      map[string]string
+	Error     string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Done reports whether the saga has reached a terminal status
+func (s *State) Done() bool {
+	return s.Status == StatusCompleted || s.Status == StatusCompensated
+}
+
+// Action performs or undoes a step. Data is shared by all steps of a saga
+// and persisted after every step, so actions record anything their
+// compensation will need (e.g. a reservation ID) in it.
+//
+// A crash can cause the step in flight to run again on resume, so actions
+// and compensations must be idempotent.
+type Action func(ctx context.Context, data map[string]string) error
+
+// Step is a single unit of work with its compensating action
+type Step struct {
+	Name       string
+	Action     Action
+	Compensate Action
+}
+
+// Definition describes an ordered list of steps
+type Definition struct {
+	Name  string
+	Steps []Step
+}
+
+// Orchestrator executes sagas and persists their state
+type Orchestrator struct {
+	store       Store
+	logger      *log.Logger
+	definitions map[string]*Definition
+}
+
+// New creates an orchestrator that can run the given definitions
+func New(store Store, logger *log.Logger, definitions ...*Definition) *Orchestrator {
+	if logger == nil {
+		logger = log.NewDefault()
+	}
+
+	defs := make(map[string]*Definition, len(definitions))
+	for _, def := range definitions {
+		defs[def.Name] = def
+	}
+
+	return &Orchestrator{
+		store:       store,
+		logger:      logger.Named("saga"),
+		definitions: defs,
+	}
+}
+
+// Start creates and runs a new saga. If a step fails, completed steps are
+// compensated in reverse order and the step error is returned.
+func (o *Orchestrator) Start(ctx context.Context, name string, data map[string]string) (*State, error) {
+	def, ok := o.definitions[name]
+	if !ok {
+		return nil, errors.WithCode(errors.Newf("unknown saga %q", name), errors.CodeInvalidInput)
+	}
+
+	if data == nil {
+		data = make(map[string]string)
+	}
+
+	state := &State{
+		ID:     uuid.New().String(),
+		Name:   name,
+		Status: StatusRunning,
+		Data:   data,
+	}
+	if err := o.store.Save(ctx, state); err != nil {
+		return nil, err
+	}
+
+	return state, o.execute(ctx, def, state)
+}
+
+// Resume continues every saga left incomplete, e.g. by a crash
+func (o *Orchestrator) Resume(ctx context.Context) error {
+	states, err := o.store.ListIncomplete(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, state := range states {
+		def, ok := o.definitions[state.Name]
+		if !ok {
+			o.logger.Warn("Skipping saga with unknown definition",
+				log.String("saga_id", state.ID),
+				log.String("name", state.Name),
+			)
+			continue
+		}
+
+		o.logger.Info("Resuming saga",
+			log.String("saga_id", state.ID),
+			log.String("name", state.Name),
+			log.String("status", string(state.Status)),
+			log.Int("step", state.Step),
+		)
+
+		if err := o.execute(ctx, def, state); err != nil {
+			o.logger.Error("Resumed saga failed", log.String("saga_id", state.ID), log.Error(err))
+		}
+	}
+
+	return nil
+}
+
+func (o *Orchestrator) execute(ctx context.Context, def *Definition, state *State) error {
+	var stepErr error
+
+	for state.Status == StatusRunning && state.Step < len(def.Steps) {
+		step := def.Steps[state.Step]
+
+		if err := step.Action(ctx, state.Data); err != nil {
+			o.logger.Warn("Saga step failed, compensating",
+				log.String("saga_id", state.ID),
+				log.String("step", step.Name),
+				log.Error(err),
+			)
+			stepErr = errors.Wrapf(err, "saga %s step %s failed", def.Name, step.Name)
+			state.Status = StatusCompensating
+			state.Error = err.Error()
+			state.Step--
+		} else {
+			state.Step++
+		}
+
+		if err := o.store.Save(ctx, state); err != nil {
+			return err
+		}
+	}
+
+	if state.Status == StatusRunning {
+		state.Status = StatusCompleted
+		return o.store.Save(ctx, state)
+	}
+
+	for state.Status == StatusCompensating && state.Step >= 0 {
+		step := def.Steps[state.Step]
+
+		if step.Compensate != nil {
+			if err := step.Compensate(ctx, state.Data); err != nil {
+				// Leave the saga compensating so Resume retries from this step
+				o.logger.Error("Saga compensation failed",
+					log.String("saga_id", state.ID),
+					log.String("step", step.Name),
+					log.Error(err),
+				)
+				return errors.Wrapf(err, "saga %s compensation %s failed", def.Name, step.Name)
+			}
+		}
+
+		state.Step--
+		if err := o.store.Save(ctx, state); err != nil {
+			return err
+		}
+	}
+
+	if state.Status == StatusCompensating {
+		state.Status = StatusCompensated
+		if err := o.store.Save(ctx, state); err != nil {
+			return err
+		}
+	}
+
+	if stepErr == nil && state.Error != "" {
+		stepErr = errors.Newf("saga %s was compensated: %s", def.Name, state.Error)
+	}
+	return stepErr
+}
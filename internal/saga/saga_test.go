@@ -0,0 +1,165 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package saga
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// mockStore is an in-memory implementation of Store for testing
+type mockStore struct {
+	states map[string]*State
+}
+
+func newMockStore() *mockStore {
+	return &mockStore{states: make(map[string]*State)}
+}
+
+func (m *mockStore) Save(ctx context.Context, state *State) error {
+	copied := *state
+	copied.Data = make(map[string]string, len(state.Data))
+	for k, v := range state.Data {
+		copied.Data[k] = v
+	}
+	m.states[state.ID] = &copied
+	return nil
+}
+
+func (m *mockStore) Get(ctx context.Context, id string) (*State, error) {
+	state, ok := m.states[id]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return state, nil
+}
+
+func (m *mockStore) ListIncomplete(ctx context.Context) ([]*State, error) {
+	var states []*State
+	for _, state := range m.states {
+		if !state.Done() {
+			states = append(states, state)
+		}
+	}
+	return states, nil
+}
+
+// recorder builds steps that log their execution order
+type recorder struct {
+	calls []string
+	fail  map[string]bool
+}
+
+func (r *recorder) step(name string) Step {
+	return Step{
+		Name: name,
+		Action: func(ctx context.Context, data map[string]string) error {
+			r.calls = append(r.calls, name)
+			if r.fail[name] {
+				return errors.New(name + " failed")
+			}
+			data[name] = "done"
+			return nil
+		},
+		Compensate: func(ctx context.Context, data map[string]string) error {
+			r.calls = append(r.calls, "undo "+name)
+			return nil
+		},
+	}
+}
+
+func TestStartCompletes(t *testing.T) {
+	rec := &recorder{}
+	store := newMockStore()
+	o := New(store, nil, &Definition{
+		Name:  "test",
+		Steps: []Step{rec.step("a"), rec.step("b"), rec.step("c")},
+	})
+
+	state, err := o.Start(context.Background(), "test", nil)
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	if state.Status != StatusCompleted {
+		t.Errorf("Start() status = %v, want %v", state.Status, StatusCompleted)
+	}
+	if want := []string{"a", "b", "c"}; !reflect.DeepEqual(rec.calls, want) {
+		t.Errorf("calls = %v, want %v", rec.calls, want)
+	}
+	if saved := store.states[state.ID]; saved.Status != StatusCompleted || saved.Data["c"] != "done" {
+		t.Errorf("persisted state = %+v, want completed with data", saved)
+	}
+}
+
+func TestStartCompensatesInReverse(t *testing.T) {
+	rec := &recorder{fail: map[string]bool{"c": true}}
+	store := newMockStore()
+	o := New(store, nil, &Definition{
+		Name:  "test",
+		Steps: []Step{rec.step("a"), rec.step("b"), rec.step("c")},
+	})
+
+	state, err := o.Start(context.Background(), "test", nil)
+	if err == nil {
+		t.Fatal("Start() expected error from failing step")
+	}
+
+	if state.Status != StatusCompensated {
+		t.Errorf("Start() status = %v, want %v", state.Status, StatusCompensated)
+	}
+	if want := []string{"a", "b", "c", "undo b", "undo a"}; !reflect.DeepEqual(rec.calls, want) {
+		t.Errorf("calls = %v, want %v", rec.calls, want)
+	}
+}
+
+func TestResumeContinuesFromPersistedStep(t *testing.T) {
+	rec := &recorder{}
+	store := newMockStore()
+	store.states["1"] = &State{
+		ID:     "1",
+		Name:   "test",
+		Status: StatusRunning,
+		Step:   1,
+		Data:   map[string]string{"a": "done"},
+	}
+
+	o := New(store, nil, &Definition{
+		Name:  "test",
+		Steps: []Step{rec.step("a"), rec.step("b")},
+	})
+
+	if err := o.Resume(context.Background()); err != nil {
+		t.Fatalf("Resume() error = %v", err)
+	}
+
+	if want := []string{"b"}; !reflect.DeepEqual(rec.calls, want) {
+		t.Errorf("calls = %v, want %v", rec.calls, want)
+	}
+	if store.states["1"].Status != StatusCompleted {
+		t.Errorf("resumed status = %v, want %v", store.states["1"].Status, StatusCompleted)
+	}
+}
+
+func TestStartUnknownSaga(t *testing.T) {
+	o := New(newMockStore(), nil)
+	if _, err := o.Start(context.Background(), "missing", nil); err == nil {
+		t.Error("Start() with unknown saga should fail")
+	}
+}
@@ -0,0 +1,162 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package saga
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/db"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+)
+
+// Store persists saga state
+type Store interface {
+	Save(ctx context.Context, state *State) error
+	Get(ctx context.Context, id string) (*State, error)
+	ListIncomplete(ctx context.Context) ([]*State, error)
+}
+
+type store struct {
+	db *db.DB
+}
+
+// NewStore creates a Postgres-backed saga store
+func NewStore(database *db.DB) Store {
+	return &store{db: database}
+}
+
+// Save inserts or updates the saga state
+func (s *store) Save(ctx context.Context, state *State) error {
+	query := `
+		INSERT INTO sagas (id, name, status, step, data, error, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $7)
+		ON CONFLICT (id) DO UPDATE
+		SET status = EXCLUDED.status, step = EXCLUDED.step, data = EXCLUDED.data,
+			error = EXCLUDED.error, updated_at = EXCLUDED.updated_at
+	`
+
+	data, err := json.Marshal(state.Data)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal saga data")
+	}
+
+	now := time.Now()
+	if state.CreatedAt.IsZero() {
+		state.CreatedAt = now
+	}
+	state.UpdatedAt = now
+
+	if _, err := s.db.ExecContext(ctx, query,
+		state.ID,
+		state.Name,
+		string(state.Status),
+		state.Step,
+		data,
+		state.Error,
+		now,
+	); err != nil {
+		return errors.Wrap(err, "failed to save saga state")
+	}
+
+	return nil
+}
+
+// Get retrieves a saga by ID
+func (s *store) Get(ctx context.Context, id string) (*State, error) {
+	query := `
+		SELECT id, name, status, step, data, COALESCE(error, ''), created_at, updated_at
+		FROM sagas
+		WHERE id = $1
+	`
+
+	state, err := scanState(s.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, errors.WithCode(errors.New("saga not found"), errors.CodeNotFound)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get saga")
+	}
+
+	return state, nil
+}
+
+// ListIncomplete returns sagas that are still running or compensating
+func (s *store) ListIncomplete(ctx context.Context) ([]*State, error) {
+	query := `
+		SELECT id, name, status, step, data, COALESCE(error, ''), created_at, updated_at
+		FROM sagas
+		WHERE status IN ('running', 'compensating')
+		ORDER BY created_at ASC
+	`
+
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list incomplete sagas")
+	}
+	defer rows.Close()
+
+	var states []*State
+	for rows.Next() {
+		state, err := scanState(rows)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to scan saga")
+		}
+		states = append(states, state)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "error iterating sagas")
+	}
+
+	return states, nil
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanState(row scanner) (*State, error) {
+	var state State
+	var status string
+	var data []byte
+
+	if err := row.Scan(
+		&state.ID,
+		&state.Name,
+		&status,
+		&state.Step,
+		&data,
+		&state.Error,
+		&state.CreatedAt,
+		&state.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	state.Status = Status(status)
+	if err := json.Unmarshal(data, &state.Data); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal saga data")
+	}
+	if state.Data == nil {
+		state.Data = make(map[string]string)
+	}
+
+	return &state, nil
+}
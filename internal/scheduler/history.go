@@ -0,0 +1,154 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package scheduler
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kevindiu/monorepo-go-example/internal/db"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+)
+
+// Run statuses
+const (
+	RunStatusRunning   = "running"
+	RunStatusSucceeded = "succeeded"
+	RunStatusFailed    = "failed"
+)
+
+// Run is a recorded job execution
+type Run struct {
+	ID         string
+	JobName    string
+	Instance   string
+	Status     string
+	Error      string
+	StartedAt  time.Time
+	FinishedAt *time.Time
+}
+
+// History records job executions
+type History interface {
+	Start(ctx context.Context, jobName string) (string, error)
+	Finish(ctx context.Context, runID string, runErr error) error
+	List(ctx context.Context, jobName string, limit int) ([]*Run, error)
+}
+
+type history struct {
+	db       *db.DB
+	instance string
+}
+
+// NewHistory creates a Postgres-backed run history. Runs are tagged with the
+// host name so operators can tell which replica executed a job.
+func NewHistory(database *db.DB) History {
+	instance, err := os.Hostname()
+	if err != nil {
+		instance = "unknown"
+	}
+	return &history{db: database, instance: instance}
+}
+
+// Start records the beginning of a run and returns its ID
+func (h *history) Start(ctx context.Context, jobName string) (string, error) {
+	query := `
+		INSERT INTO job_runs (id, job_name, instance, status, started_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+
+	id := uuid.New().String()
+	if _, err := h.db.ExecContext(ctx, query, id, jobName, h.instance, RunStatusRunning, time.Now()); err != nil {
+		return "", errors.Wrap(err, "failed to record job run")
+	}
+	return id, nil
+}
+
+// Finish records the outcome of a run
+func (h *history) Finish(ctx context.Context, runID string, runErr error) error {
+	query := `
+		UPDATE job_runs
+		SET status = $1, error = $2, finished_at = $3
+		WHERE id = $4
+	`
+
+	status := RunStatusSucceeded
+	var errMsg *string
+	if runErr != nil {
+		status = RunStatusFailed
+		msg := runErr.Error()
+		errMsg = &msg
+	}
+
+	if _, err := h.db.ExecContext(ctx, query, status, errMsg, time.Now(), runID); err != nil {
+		return errors.Wrap(err, "failed to record job result")
+	}
+	return nil
+}
+
+// List returns the most recent runs of a job
+func (h *history) List(ctx context.Context, jobName string, limit int) ([]*Run, error) {
+	query := `
+		SELECT id, job_name, instance, status, COALESCE(error, ''), started_at, finished_at
+		FROM job_runs
+		WHERE job_name = $1
+		ORDER BY started_at DESC
+		LIMIT $2
+	`
+
+	rows, err := h.db.QueryContext(ctx, query, jobName, limit)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list job runs")
+	}
+	defer rows.Close()
+
+	var runs []*Run
+	for rows.Next() {
+		var run Run
+		if err := rows.Scan(
+			&run.ID,
+			&run.JobName,
+			&run.Instance,
+			&run.Status,
+			&run.Error,
+			&run.StartedAt,
+			&run.FinishedAt,
+		); err != nil {
+			return nil, errors.Wrap(err, "failed to scan job run")
+		}
+		runs = append(runs, &run)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "error iterating job runs")
+	}
+
+	return runs, nil
+}
+
+// nopHistory discards run history
+type nopHistory struct{}
+
+func (nopHistory) Start(ctx context.Context, jobName string) (string, error) { return "", nil }
+
+func (nopHistory) Finish(ctx context.Context, runID string, runErr error) error { return nil }
+
+func (nopHistory) List(ctx context.Context, jobName string, limit int) ([]*Run, error) {
+	return nil, nil
+}
@@ -0,0 +1,106 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package scheduler
+
+import (
+	"context"
+	"hash/fnv"
+	"sync"
+
+	"github.com/kevindiu/monorepo-go-example/internal/db"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+)
+
+// Locker provides mutual exclusion for job runs. TryLock never blocks; when
+// acquired is true the caller must call unlock once the run is finished.
+type Locker interface {
+	TryLock(ctx context.Context, name string) (acquired bool, unlock func(), err error)
+}
+
+// PostgresLocker uses session-level advisory locks, so a lock held by a
+// crashed replica is released as soon as its connection drops
+type PostgresLocker struct {
+	db *db.DB
+}
+
+// NewPostgresLocker creates an advisory-lock based locker
+func NewPostgresLocker(database *db.DB) *PostgresLocker {
+	return &PostgresLocker{db: database}
+}
+
+// TryLock attempts to take the advisory lock for name
+func (l *PostgresLocker) TryLock(ctx context.Context, name string) (bool, func(), error) {
+	// Advisory locks belong to a session, so pin a connection for the whole run
+	conn, err := l.db.Conn(ctx)
+	if err != nil {
+		return false, nil, errors.Wrap(err, "failed to get database connection")
+	}
+
+	key := LockKey(name)
+
+	var acquired bool
+	if err := conn.QueryRowContext(ctx, `SELECT pg_try_advisory_lock($1)`, key).Scan(&acquired); err != nil {
+		conn.Close()
+		return false, nil, errors.Wrap(err, "failed to acquire advisory lock")
+	}
+	if !acquired {
+		conn.Close()
+		return false, nil, nil
+	}
+
+	unlock := func() {
+		// Use a fresh context so unlocking still happens after cancellation
+		conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, key)
+		conn.Close()
+	}
+	return true, unlock, nil
+}
+
+// LockKey maps a lock name onto a Postgres advisory lock key
+func LockKey(name string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte(name))
+	return int64(h.Sum64())
+}
+
+// LocalLocker only guards against overlapping runs within one process
+type LocalLocker struct {
+	mu     sync.Mutex
+	locked map[string]bool
+}
+
+// NewLocalLocker creates an in-process locker
+func NewLocalLocker() *LocalLocker {
+	return &LocalLocker{locked: make(map[string]bool)}
+}
+
+// TryLock attempts to take the in-process lock for name
+func (l *LocalLocker) TryLock(ctx context.Context, name string) (bool, func(), error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.locked[name] {
+		return false, nil, nil
+	}
+	l.locked[name] = true
+
+	return true, func() {
+		l.mu.Lock()
+		delete(l.locked, name)
+		l.mu.Unlock()
+	}, nil
+}
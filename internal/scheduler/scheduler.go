@@ -0,0 +1,252 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/internal/log"
+	"github.com/robfig/cron/v3"
+)
+
+// Job is a unit of scheduled work
+type Job struct {
+	// Name identifies the job; it is also the lock key shared by all replicas
+	Name string
+	// Schedule is a standard five-field cron expression or a descriptor
+	// such as "@hourly" or "@every 10m"
+	Schedule string
+	// Timeout bounds a single run; zero means no timeout
+	Timeout time.Duration
+	Run     func(ctx context.Context) error
+}
+
+// Config holds scheduler configuration
+type Config struct {
+	Locker  Locker
+	History History
+	Logger  *log.Logger
+}
+
+// Scheduler runs registered jobs on their cron schedules. Before each run the
+// job's lock is acquired so that only one replica executes it.
+type Scheduler struct {
+	locker  Locker
+	history History
+	logger  *log.Logger
+
+	mu   sync.Mutex
+	jobs []*entry
+	wg   sync.WaitGroup
+}
+
+type entry struct {
+	job      Job
+	schedule cron.Schedule
+	next     time.Time
+}
+
+// New creates a new scheduler
+func New(cfg Config) *Scheduler {
+	if cfg.Logger == nil {
+		cfg.Logger = log.NewDefault()
+	}
+	if cfg.Locker == nil {
+		cfg.Locker = NewLocalLocker()
+	}
+	if cfg.History == nil {
+		cfg.History = nopHistory{}
+	}
+
+	return &Scheduler{
+		locker:  cfg.Locker,
+		history: cfg.History,
+		logger:  cfg.Logger.Named("scheduler"),
+	}
+}
+
+// Register adds a job to the scheduler
+func (s *Scheduler) Register(job Job) error {
+	if job.Name == "" {
+		return errors.WithCode(errors.New("job name is required"), errors.CodeInvalidInput)
+	}
+	if job.Run == nil {
+		return errors.WithCode(errors.Newf("job %s has no run function", job.Name), errors.CodeInvalidInput)
+	}
+
+	schedule, err := cron.ParseStandard(job.Schedule)
+	if err != nil {
+		return errors.WithCode(errors.Wrapf(err, "invalid schedule for job %s", job.Name), errors.CodeInvalidInput)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, e := range s.jobs {
+		if e.job.Name == job.Name {
+			return errors.WithCode(errors.Newf("job %s already registered", job.Name), errors.CodeConflict)
+		}
+	}
+	s.jobs = append(s.jobs, &entry{job: job, schedule: schedule})
+
+	return nil
+}
+
+// Run executes jobs as they come due until ctx is cancelled, then waits for
+// in-flight runs to finish
+func (s *Scheduler) Run(ctx context.Context) error {
+	s.mu.Lock()
+	now := time.Now()
+	for _, e := range s.jobs {
+		e.next = e.schedule.Next(now)
+		s.logger.Info("Scheduled job",
+			log.String("job", e.job.Name),
+			log.String("schedule", e.job.Schedule),
+			log.Any("next_run", e.next),
+		)
+	}
+	s.mu.Unlock()
+
+	defer s.wg.Wait()
+
+	for {
+		wait := s.untilNext(time.Now())
+		timer := time.NewTimer(wait)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		case now := <-timer.C:
+			s.dispatchDue(ctx, now)
+		}
+	}
+}
+
+// RunNow executes a job immediately, subject to the same locking and history
+// rules as a scheduled run
+func (s *Scheduler) RunNow(ctx context.Context, name string) error {
+	s.mu.Lock()
+	var job *Job
+	for _, e := range s.jobs {
+		if e.job.Name == name {
+			j := e.job
+			job = &j
+			break
+		}
+	}
+	s.mu.Unlock()
+
+	if job == nil {
+		return errors.WithCode(errors.Newf("job %s not found", name), errors.CodeNotFound)
+	}
+	return s.runJob(ctx, *job)
+}
+
+func (s *Scheduler) untilNext(now time.Time) time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// With no jobs registered there is nothing to wake up for
+	wait := time.Hour
+	for _, e := range s.jobs {
+		if d := e.next.Sub(now); d < wait {
+			wait = d
+		}
+	}
+	if wait < 0 {
+		wait = 0
+	}
+	return wait
+}
+
+func (s *Scheduler) dispatchDue(ctx context.Context, now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, e := range s.jobs {
+		if e.next.After(now) {
+			continue
+		}
+		e.next = e.schedule.Next(now)
+
+		job := e.job
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			if err := s.runJob(ctx, job); err != nil {
+				s.logger.Error("Job failed", log.String("job", job.Name), log.Error(err))
+			}
+		}()
+	}
+}
+
+func (s *Scheduler) runJob(ctx context.Context, job Job) error {
+	acquired, unlock, err := s.locker.TryLock(ctx, job.Name)
+	if err != nil {
+		return errors.Wrapf(err, "failed to acquire lock for job %s", job.Name)
+	}
+	if !acquired {
+		s.logger.Debug("Job is running elsewhere, skipping", log.String("job", job.Name))
+		return nil
+	}
+	defer unlock()
+
+	runID, err := s.history.Start(ctx, job.Name)
+	if err != nil {
+		// Losing history should not stop the job itself
+		s.logger.Warn("Failed to record job start", log.String("job", job.Name), log.Error(err))
+	}
+
+	runCtx := ctx
+	if job.Timeout > 0 {
+		var cancel context.CancelFunc
+		runCtx, cancel = context.WithTimeout(ctx, job.Timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	runErr := s.safeRun(runCtx, job)
+
+	if runID != "" {
+		if err := s.history.Finish(ctx, runID, runErr); err != nil {
+			s.logger.Warn("Failed to record job result", log.String("job", job.Name), log.Error(err))
+		}
+	}
+
+	if runErr != nil {
+		return runErr
+	}
+
+	s.logger.Info("Job completed",
+		log.String("job", job.Name),
+		log.Any("duration", time.Since(start)),
+	)
+	return nil
+}
+
+func (s *Scheduler) safeRun(ctx context.Context, job Job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.WithCode(errors.Newf("job %s panicked: %v", job.Name, r), errors.CodeInternal)
+		}
+	}()
+	return job.Run(ctx)
+}
@@ -0,0 +1,143 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// mockHistory records job outcomes in memory
+type mockHistory struct {
+	started  []string
+	finished map[string]error
+}
+
+func newMockHistory() *mockHistory {
+	return &mockHistory{finished: make(map[string]error)}
+}
+
+func (m *mockHistory) Start(ctx context.Context, jobName string) (string, error) {
+	m.started = append(m.started, jobName)
+	return jobName, nil
+}
+
+func (m *mockHistory) Finish(ctx context.Context, runID string, runErr error) error {
+	m.finished[runID] = runErr
+	return nil
+}
+
+func (m *mockHistory) List(ctx context.Context, jobName string, limit int) ([]*Run, error) {
+	return nil, nil
+}
+
+func TestRegister(t *testing.T) {
+	s := New(Config{})
+	noop := func(ctx context.Context) error { return nil }
+
+	tests := []struct {
+		name    string
+		job     Job
+		wantErr bool
+	}{
+		{name: "valid cron", job: Job{Name: "a", Schedule: "*/5 * * * *", Run: noop}},
+		{name: "descriptor", job: Job{Name: "b", Schedule: "@every 10m", Run: noop}},
+		{name: "duplicate", job: Job{Name: "a", Schedule: "@hourly", Run: noop}, wantErr: true},
+		{name: "invalid schedule", job: Job{Name: "c", Schedule: "not a cron", Run: noop}, wantErr: true},
+		{name: "missing name", job: Job{Schedule: "@hourly", Run: noop}, wantErr: true},
+		{name: "missing run", job: Job{Name: "d", Schedule: "@hourly"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := s.Register(tt.job); (err != nil) != tt.wantErr {
+				t.Errorf("Register() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRunNowRecordsHistory(t *testing.T) {
+	history := newMockHistory()
+	s := New(Config{History: history})
+
+	jobErr := errors.New("boom")
+	s.Register(Job{Name: "ok", Schedule: "@hourly", Run: func(ctx context.Context) error { return nil }})
+	s.Register(Job{Name: "fail", Schedule: "@hourly", Run: func(ctx context.Context) error { return jobErr }})
+	s.Register(Job{Name: "panic", Schedule: "@hourly", Run: func(ctx context.Context) error { panic("oops") }})
+
+	if err := s.RunNow(context.Background(), "ok"); err != nil {
+		t.Errorf("RunNow(ok) error = %v", err)
+	}
+	if err := s.RunNow(context.Background(), "fail"); !errors.Is(err, jobErr) {
+		t.Errorf("RunNow(fail) error = %v, want %v", err, jobErr)
+	}
+	if err := s.RunNow(context.Background(), "panic"); err == nil {
+		t.Error("RunNow(panic) should return an error")
+	}
+	if err := s.RunNow(context.Background(), "missing"); err == nil {
+		t.Error("RunNow(missing) should return an error")
+	}
+
+	if len(history.started) != 3 {
+		t.Errorf("history recorded %d starts, want 3", len(history.started))
+	}
+	if history.finished["ok"] != nil {
+		t.Errorf("history for ok = %v, want nil", history.finished["ok"])
+	}
+	if history.finished["fail"] == nil || history.finished["panic"] == nil {
+		t.Error("history should record failures")
+	}
+}
+
+func TestRunNowSkipsWhenLocked(t *testing.T) {
+	locker := NewLocalLocker()
+	history := newMockHistory()
+	s := New(Config{Locker: locker, History: history})
+
+	ran := false
+	s.Register(Job{Name: "job", Schedule: "@hourly", Run: func(ctx context.Context) error {
+		ran = true
+		return nil
+	}})
+
+	_, unlock, _ := locker.TryLock(context.Background(), "job")
+	if err := s.RunNow(context.Background(), "job"); err != nil {
+		t.Fatalf("RunNow() error = %v", err)
+	}
+	if ran || len(history.started) != 0 {
+		t.Error("job ran while another holder had the lock")
+	}
+
+	unlock()
+	if err := s.RunNow(context.Background(), "job"); err != nil {
+		t.Fatalf("RunNow() error = %v", err)
+	}
+	if !ran {
+		t.Error("job did not run after the lock was released")
+	}
+}
+
+func TestLockKeyIsStable(t *testing.T) {
+	if LockKey("order-expiry") != LockKey("order-expiry") {
+		t.Error("LockKey() is not deterministic")
+	}
+	if LockKey("order-expiry") == LockKey("token-cleanup") {
+		t.Error("LockKey() collided for different names")
+	}
+}
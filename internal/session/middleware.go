@@ -0,0 +1,79 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package session
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/internal/tenant"
+	"google.golang.org/grpc/metadata"
+)
+
+// Metadata keys identifying the session user on calls to the backends
+const (
+	UserIDMetadataKey    = "x-user-id"
+	SessionIDMetadataKey = "x-session-id"
+)
+
+// Middleware authenticates requests carrying a session cookie and puts the
+// session on the request context. Requests with an Authorization header are
+// left to bearer token authentication, and requests with neither pass
+// through unauthenticated. A session binds its request to the session's
+// tenant.
+func Middleware(m *Manager, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Identity metadata is only ever derived from the session
+		r.Header.Del(runtime.MetadataHeaderPrefix + UserIDMetadataKey)
+		r.Header.Del(runtime.MetadataHeaderPrefix + SessionIDMetadataKey)
+
+		token := m.Token(r)
+		if token == "" || r.Header.Get("Authorization") != "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		s, err := m.Validate(r.Context(), token)
+		if err != nil {
+			if errors.GetCode(err) == errors.CodeUnauthorized {
+				m.ClearCookie(w)
+			}
+			errors.WriteHTTP(w, err)
+			return
+		}
+
+		if requested := r.Header.Get(tenant.Header); requested != "" && requested != s.TenantID {
+			errors.WriteHTTP(w, errors.WithCode(errors.New("session does not belong to the requested tenant"), errors.CodeForbidden))
+			return
+		}
+		r.Header.Set(tenant.Header, s.TenantID)
+
+		next.ServeHTTP(w, r.WithContext(WithSession(r.Context(), s)))
+	})
+}
+
+// Metadata is a grpc-gateway metadata annotator that forwards the user and
+// session of an authenticated request to the backends
+func Metadata(ctx context.Context, r *http.Request) metadata.MD {
+	s, ok := FromContext(r.Context())
+	if !ok {
+		return nil
+	}
+	return metadata.Pairs(UserIDMetadataKey, s.UserID, SessionIDMetadataKey, s.ID)
+}
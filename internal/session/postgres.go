@@ -0,0 +1,123 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package session
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/db"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/lib/pq"
+)
+
+// PostgresStore keeps sessions in the sessions table
+type PostgresStore struct {
+	db *db.DB
+}
+
+// NewPostgresStore creates a Postgres-backed session store
+func NewPostgresStore(database *db.DB) *PostgresStore {
+	return &PostgresStore{db: database}
+}
+
+// Save creates or updates a session
+func (p *PostgresStore) Save(ctx context.Context, s *Session) error {
+	query := `
+		INSERT INTO sessions (id, tenant_id, user_id, roles, user_agent, ip, created_at, last_seen_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (id) DO UPDATE
+		SET last_seen_at = EXCLUDED.last_seen_at, expires_at = EXCLUDED.expires_at
+	`
+
+	_, err := p.db.ExecContext(ctx, query,
+		s.ID,
+		s.TenantID,
+		s.UserID,
+		pq.Array(s.Roles),
+		s.Device.UserAgent,
+		s.Device.IP,
+		s.CreatedAt,
+		s.LastSeenAt,
+		s.ExpiresAt,
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to save session")
+	}
+	return nil
+}
+
+// Get retrieves an unexpired session by ID
+func (p *PostgresStore) Get(ctx context.Context, id string) (*Session, error) {
+	query := `
+		SELECT id, tenant_id, user_id, roles, user_agent, ip, created_at, last_seen_at, expires_at
+		FROM sessions
+		WHERE id = $1 AND expires_at > $2
+	`
+
+	var s Session
+	err := p.db.QueryRowContext(ctx, query, id, time.Now()).Scan(
+		&s.ID,
+		&s.TenantID,
+		&s.UserID,
+		pq.Array(&s.Roles),
+		&s.Device.UserAgent,
+		&s.Device.IP,
+		&s.CreatedAt,
+		&s.LastSeenAt,
+		&s.ExpiresAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, errors.WithCode(errors.New("session not found"), errors.CodeNotFound)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get session")
+	}
+	return &s, nil
+}
+
+// Delete removes a session
+func (p *PostgresStore) Delete(ctx context.Context, id string) error {
+	if _, err := p.db.ExecContext(ctx, `DELETE FROM sessions WHERE id = $1`, id); err != nil {
+		return errors.Wrap(err, "failed to delete session")
+	}
+	return nil
+}
+
+// DeleteByUser removes every session of a user
+func (p *PostgresStore) DeleteByUser(ctx context.Context, tenantID, userID string) error {
+	query := `DELETE FROM sessions WHERE tenant_id = $1 AND user_id = $2`
+	if _, err := p.db.ExecContext(ctx, query, tenantID, userID); err != nil {
+		return errors.Wrap(err, "failed to delete user sessions")
+	}
+	return nil
+}
+
+// DeleteExpired removes sessions that expired before the given time
+func (p *PostgresStore) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	result, err := p.db.ExecContext(ctx, `DELETE FROM sessions WHERE expires_at < $1`, before)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to delete expired sessions")
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to get affected rows")
+	}
+	return rows, nil
+}
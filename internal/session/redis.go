@@ -0,0 +1,115 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisStore keeps sessions in Redis, expiring each key with its session.
+// A set per user indexes the user's sessions for RevokeAll.
+type RedisStore struct {
+	client redis.UniversalClient
+}
+
+// NewRedisStore creates a Redis-backed session store
+func NewRedisStore(client redis.UniversalClient) *RedisStore {
+	return &RedisStore{client: client}
+}
+
+// Save creates or updates a session
+func (r *RedisStore) Save(ctx context.Context, s *Session) error {
+	ttl := time.Until(s.ExpiresAt)
+	if ttl <= 0 {
+		return r.Delete(ctx, s.ID)
+	}
+
+	data, err := json.Marshal(s)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode session")
+	}
+
+	userKey := userKey(s.TenantID, s.UserID)
+	_, err = r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, sessionKey(s.ID), data, ttl)
+		pipe.SAdd(ctx, userKey, s.ID)
+		// The index lives as long as the user's longest session
+		pipe.ExpireGT(ctx, userKey, ttl)
+		pipe.ExpireNX(ctx, userKey, ttl)
+		return nil
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to save session")
+	}
+	return nil
+}
+
+// Get retrieves a session by ID
+func (r *RedisStore) Get(ctx context.Context, id string) (*Session, error) {
+	data, err := r.client.Get(ctx, sessionKey(id)).Bytes()
+	if err == redis.Nil {
+		return nil, errors.WithCode(errors.New("session not found"), errors.CodeNotFound)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get session")
+	}
+
+	var s Session
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, errors.Wrap(err, "failed to decode session")
+	}
+	return &s, nil
+}
+
+// Delete removes a session. Its entry in the user index is left to expire.
+func (r *RedisStore) Delete(ctx context.Context, id string) error {
+	if err := r.client.Del(ctx, sessionKey(id)).Err(); err != nil {
+		return errors.Wrap(err, "failed to delete session")
+	}
+	return nil
+}
+
+// DeleteByUser removes every session of a user
+func (r *RedisStore) DeleteByUser(ctx context.Context, tenantID, userID string) error {
+	key := userKey(tenantID, userID)
+	ids, err := r.client.SMembers(ctx, key).Result()
+	if err != nil {
+		return errors.Wrap(err, "failed to list user sessions")
+	}
+
+	keys := []string{key}
+	for _, id := range ids {
+		keys = append(keys, sessionKey(id))
+	}
+	if err := r.client.Del(ctx, keys...).Err(); err != nil {
+		return errors.Wrap(err, "failed to delete user sessions")
+	}
+	return nil
+}
+
+func sessionKey(id string) string {
+	return "session:" + id
+}
+
+func userKey(tenantID, userID string) string {
+	return "session:user:" + tenantID + ":" + userID
+}
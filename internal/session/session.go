@@ -0,0 +1,283 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package session manages cookie-based browser sessions
+package session
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"net/http"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/config"
+	"github.com/kevindiu/monorepo-go-example/internal/db"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/internal/log"
+	"github.com/kevindiu/monorepo-go-example/internal/tenant"
+	"github.com/redis/go-redis/v9"
+)
+
+// DefaultCookieName is the cookie that carries the session token
+const DefaultCookieName = "session"
+
+// touchInterval is how often a session's sliding expiry is persisted. Requests
+// inside the interval extend nothing, which keeps busy sessions from writing
+// to the store on every request.
+const touchInterval = time.Minute
+
+// Session is an authenticated browser session. ID is a hash of the token held
+// by the client, so it is safe to log and to keep in the store.
+type Session struct {
+	ID         string    `json:"id"`
+	UserID     string    `json:"user_id"`
+	TenantID   string    `json:"tenant_id"`
+	Roles      []string  `json:"roles"`
+	Device     Device    `json:"device"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+// Device describes the client a session was created from
+type Device struct {
+	UserAgent string `json:"user_agent"`
+	IP        string `json:"ip"`
+}
+
+// DeviceFromRequest returns the device metadata of an HTTP request
+func DeviceFromRequest(r *http.Request) Device {
+	return Device{UserAgent: r.UserAgent(), IP: r.RemoteAddr}
+}
+
+// Store persists sessions. Get returns an error with errors.CodeNotFound
+// for unknown or expired sessions.
+type Store interface {
+	Save(ctx context.Context, s *Session) error
+	Get(ctx context.Context, id string) (*Session, error)
+	Delete(ctx context.Context, id string) error
+	DeleteByUser(ctx context.Context, tenantID, userID string) error
+}
+
+// Config configures a Manager
+type Config struct {
+	Store Store
+	// IdleTimeout is how long a session lives without being used
+	IdleTimeout time.Duration
+	// MaxLifetime caps how far sliding expiry can extend a session
+	MaxLifetime  time.Duration
+	CookieName   string
+	CookieSecure bool
+}
+
+// Manager creates, validates and revokes sessions
+type Manager struct {
+	store        Store
+	idleTimeout  time.Duration
+	maxLifetime  time.Duration
+	cookieName   string
+	cookieSecure bool
+	now          func() time.Time
+}
+
+// NewManager creates a session manager
+func NewManager(cfg Config) *Manager {
+	if cfg.IdleTimeout <= 0 {
+		cfg.IdleTimeout = 24 * time.Hour
+	}
+	if cfg.MaxLifetime <= 0 {
+		cfg.MaxLifetime = 30 * 24 * time.Hour
+	}
+	if cfg.CookieName == "" {
+		cfg.CookieName = DefaultCookieName
+	}
+	return &Manager{
+		store:        cfg.Store,
+		idleTimeout:  cfg.IdleTimeout,
+		maxLifetime:  cfg.MaxLifetime,
+		cookieName:   cfg.CookieName,
+		cookieSecure: cfg.CookieSecure,
+		now:          time.Now,
+	}
+}
+
+// New creates a session manager from configuration. Sessions are kept in
+// Postgres, with Redis in front of it when an address is configured.
+func New(cfg *config.Session, database *db.DB, logger *log.Logger) *Manager {
+	var store Store = NewPostgresStore(database)
+	if cfg.RedisAddr != "" {
+		client := redis.NewClient(&redis.Options{
+			Addr:     cfg.RedisAddr,
+			Password: cfg.RedisPassword,
+			DB:       cfg.RedisDB,
+		})
+		store = NewTieredStore(NewRedisStore(client), store, logger)
+	}
+
+	return NewManager(Config{
+		Store:        store,
+		IdleTimeout:  cfg.IdleTimeout,
+		MaxLifetime:  cfg.MaxLifetime,
+		CookieName:   cfg.CookieName,
+		CookieSecure: cfg.CookieSecure,
+	})
+}
+
+// Create starts a session for a user of the tenant on the context and
+// returns it with the token to hand to the client. roles are the user's
+// roles, which the tokens issued for the session carry.
+func (m *Manager) Create(ctx context.Context, userID string, roles []string, device Device) (*Session, string, error) {
+	if userID == "" {
+		return nil, "", errors.WithCode(errors.New("user ID is required"), errors.CodeInvalidInput)
+	}
+
+	token, err := newToken()
+	if err != nil {
+		return nil, "", err
+	}
+
+	now := m.now()
+	s := &Session{
+		ID:         hashToken(token),
+		UserID:     userID,
+		TenantID:   tenant.ID(ctx),
+		Roles:      roles,
+		Device:     device,
+		CreatedAt:  now,
+		LastSeenAt: now,
+		ExpiresAt:  m.expiry(now, now),
+	}
+	if err := m.store.Save(ctx, s); err != nil {
+		return nil, "", err
+	}
+	return s, token, nil
+}
+
+// Validate returns the live session for a token and slides its expiry
+// forward. Unknown, expired and malformed tokens are unauthorized.
+func (m *Manager) Validate(ctx context.Context, token string) (*Session, error) {
+	if token == "" {
+		return nil, errors.WithCode(errors.New("session token is required"), errors.CodeUnauthorized)
+	}
+
+	s, err := m.store.Get(ctx, hashToken(token))
+	if err != nil {
+		if errors.GetCode(err) == errors.CodeNotFound {
+			return nil, errors.WithCode(errors.New("session is invalid or expired"), errors.CodeUnauthorized)
+		}
+		return nil, err
+	}
+
+	now := m.now()
+	if !now.Before(s.ExpiresAt) {
+		return nil, errors.WithCode(errors.New("session is invalid or expired"), errors.CodeUnauthorized)
+	}
+
+	if now.Sub(s.LastSeenAt) >= touchInterval {
+		s.LastSeenAt = now
+		s.ExpiresAt = m.expiry(s.CreatedAt, now)
+		if err := m.store.Save(ctx, s); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// Revoke ends the session for a token
+func (m *Manager) Revoke(ctx context.Context, token string) error {
+	return m.store.Delete(ctx, hashToken(token))
+}
+
+// RevokeAll ends every session of a user in the tenant on the context
+func (m *Manager) RevokeAll(ctx context.Context, userID string) error {
+	return m.store.DeleteByUser(ctx, tenant.ID(ctx), userID)
+}
+
+// SetCookie writes the session cookie for a newly created session
+func (m *Manager) SetCookie(w http.ResponseWriter, token string, s *Session) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     m.cookieName,
+		Value:    token,
+		Path:     "/",
+		Expires:  s.CreatedAt.Add(m.maxLifetime),
+		HttpOnly: true,
+		Secure:   m.cookieSecure,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// ClearCookie removes the session cookie from the client
+func (m *Manager) ClearCookie(w http.ResponseWriter) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     m.cookieName,
+		Value:    "",
+		Path:     "/",
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   m.cookieSecure,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// Token returns the session token carried by a request, if any
+func (m *Manager) Token(r *http.Request) string {
+	c, err := r.Cookie(m.cookieName)
+	if err != nil {
+		return ""
+	}
+	return c.Value
+}
+
+// expiry returns when a session last used at now expires, capped at the
+// maximum lifetime from its creation
+func (m *Manager) expiry(createdAt, now time.Time) time.Time {
+	expires := now.Add(m.idleTimeout)
+	if limit := createdAt.Add(m.maxLifetime); expires.After(limit) {
+		return limit
+	}
+	return expires
+}
+
+// newToken returns a random session token
+func newToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", errors.Wrap(err, "failed to generate session token")
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashToken returns the session ID for a token
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+type contextKey struct{}
+
+// WithSession returns a context carrying the session
+func WithSession(ctx context.Context, s *Session) context.Context {
+	return context.WithValue(ctx, contextKey{}, s)
+}
+
+// FromContext returns the session on the context, if any
+func FromContext(ctx context.Context) (*Session, bool) {
+	s, ok := ctx.Value(contextKey{}).(*Session)
+	return s, ok
+}
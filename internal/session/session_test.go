@@ -0,0 +1,309 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package session
+
+import (
+	"context"
+	stderrors "errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/internal/tenant"
+)
+
+// mockStore keeps sessions in memory
+type mockStore struct {
+	sessions map[string]Session
+	saves    int
+	err      error
+}
+
+func newMockStore() *mockStore {
+	return &mockStore{sessions: make(map[string]Session)}
+}
+
+func (m *mockStore) Save(ctx context.Context, s *Session) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.saves++
+	m.sessions[s.ID] = *s
+	return nil
+}
+
+func (m *mockStore) Get(ctx context.Context, id string) (*Session, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	s, ok := m.sessions[id]
+	if !ok {
+		return nil, errors.WithCode(errors.New("session not found"), errors.CodeNotFound)
+	}
+	return &s, nil
+}
+
+func (m *mockStore) Delete(ctx context.Context, id string) error {
+	if m.err != nil {
+		return m.err
+	}
+	delete(m.sessions, id)
+	return nil
+}
+
+func (m *mockStore) DeleteByUser(ctx context.Context, tenantID, userID string) error {
+	if m.err != nil {
+		return m.err
+	}
+	for id, s := range m.sessions {
+		if s.TenantID == tenantID && s.UserID == userID {
+			delete(m.sessions, id)
+		}
+	}
+	return nil
+}
+
+// newTestManager returns a manager with a controllable clock
+func newTestManager(store Store) (*Manager, *time.Time) {
+	m := NewManager(Config{Store: store, IdleTimeout: time.Hour, MaxLifetime: 3 * time.Hour})
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	m.now = func() time.Time { return now }
+	return m, &now
+}
+
+func TestCreateAndValidate(t *testing.T) {
+	store := newMockStore()
+	m, _ := newTestManager(store)
+	ctx := tenant.WithID(context.Background(), "acme")
+
+	s, token, err := m.Create(ctx, "user-1", []string{"customer"}, Device{UserAgent: "test", IP: "10.0.0.1"})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if s.ID == token {
+		t.Error("session ID should not be the raw token")
+	}
+	if s.TenantID != "acme" {
+		t.Errorf("TenantID = %q, want acme", s.TenantID)
+	}
+
+	got, err := m.Validate(ctx, token)
+	if err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if got.UserID != "user-1" || len(got.Roles) != 1 || got.Roles[0] != "customer" || got.Device.UserAgent != "test" {
+		t.Errorf("Validate() = %+v", got)
+	}
+
+	if _, _, err := m.Create(ctx, "", nil, Device{}); errors.GetCode(err) != errors.CodeInvalidInput {
+		t.Errorf("Create() without user error = %v, want invalid input", err)
+	}
+}
+
+func TestValidateRejects(t *testing.T) {
+	tests := []struct {
+		name    string
+		advance time.Duration
+		token   func(token string) string
+	}{
+		{name: "empty token", token: func(string) string { return "" }},
+		{name: "unknown token", token: func(string) string { return "bogus" }},
+		{name: "idle timeout", advance: time.Hour, token: func(token string) string { return token }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, now := newTestManager(newMockStore())
+			_, token, err := m.Create(context.Background(), "user-1", nil, Device{})
+			if err != nil {
+				t.Fatalf("Create() error = %v", err)
+			}
+
+			*now = now.Add(tt.advance)
+			if _, err := m.Validate(context.Background(), tt.token(token)); errors.GetCode(err) != errors.CodeUnauthorized {
+				t.Errorf("Validate() error = %v, want unauthorized", err)
+			}
+		})
+	}
+}
+
+func TestSlidingExpiry(t *testing.T) {
+	store := newMockStore()
+	m, now := newTestManager(store)
+	created := *now
+
+	_, token, _ := m.Create(context.Background(), "user-1", nil, Device{})
+
+	// Uses inside the touch interval do not write
+	*now = now.Add(30 * time.Second)
+	if _, err := m.Validate(context.Background(), token); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if store.saves != 1 {
+		t.Errorf("store saved %d times, want 1", store.saves)
+	}
+
+	// Regular use keeps the session alive past the idle timeout...
+	for i := 0; i < 3; i++ {
+		*now = now.Add(45 * time.Minute)
+		if _, err := m.Validate(context.Background(), token); err != nil {
+			t.Fatalf("Validate() after %v error = %v", now.Sub(created), err)
+		}
+	}
+
+	// ...but never past the maximum lifetime
+	*now = created.Add(3 * time.Hour)
+	if _, err := m.Validate(context.Background(), token); errors.GetCode(err) != errors.CodeUnauthorized {
+		t.Errorf("Validate() past max lifetime error = %v, want unauthorized", err)
+	}
+}
+
+func TestRevoke(t *testing.T) {
+	m, _ := newTestManager(newMockStore())
+	ctx := tenant.WithID(context.Background(), "acme")
+
+	_, first, _ := m.Create(ctx, "user-1", nil, Device{})
+	_, second, _ := m.Create(ctx, "user-1", nil, Device{})
+	_, other, _ := m.Create(ctx, "user-2", nil, Device{})
+
+	if err := m.Revoke(ctx, first); err != nil {
+		t.Fatalf("Revoke() error = %v", err)
+	}
+	if _, err := m.Validate(ctx, first); err == nil {
+		t.Error("revoked session is still valid")
+	}
+	if _, err := m.Validate(ctx, second); err != nil {
+		t.Errorf("other session of the user was revoked: %v", err)
+	}
+
+	if err := m.RevokeAll(ctx, "user-1"); err != nil {
+		t.Fatalf("RevokeAll() error = %v", err)
+	}
+	if _, err := m.Validate(ctx, second); err == nil {
+		t.Error("RevokeAll() left a session valid")
+	}
+	if _, err := m.Validate(ctx, other); err != nil {
+		t.Errorf("RevokeAll() revoked another user's session: %v", err)
+	}
+}
+
+func TestTieredStoreFallback(t *testing.T) {
+	cache := newMockStore()
+	durable := newMockStore()
+	store := NewTieredStore(cache, durable, nil)
+	s := &Session{ID: "s1", UserID: "user-1", ExpiresAt: time.Now().Add(time.Hour)}
+
+	// A cache outage does not fail writes or reads
+	cache.err = stderrors.New("connection refused")
+	if err := store.Save(context.Background(), s); err != nil {
+		t.Fatalf("Save() with cache down error = %v", err)
+	}
+	if _, err := store.Get(context.Background(), "s1"); err != nil {
+		t.Fatalf("Get() with cache down error = %v", err)
+	}
+
+	// Once the cache is back, a miss is refilled from the durable store
+	cache.err = nil
+	if _, err := store.Get(context.Background(), "s1"); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if _, ok := cache.sessions["s1"]; !ok {
+		t.Error("cache was not refilled")
+	}
+
+	// Revocation must reach the cache
+	cache.err = stderrors.New("connection refused")
+	if err := store.Delete(context.Background(), "s1"); err == nil {
+		t.Error("Delete() should fail when the cache cannot be cleared")
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	m, _ := newTestManager(newMockStore())
+	_, token, _ := m.Create(tenant.WithID(context.Background(), "acme"), "user-1", nil, Device{})
+
+	var got *Session
+	var gotTenant string
+	handler := Middleware(m, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, _ = FromContext(r.Context())
+		gotTenant = r.Header.Get(tenant.Header)
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		name     string
+		cookie   string
+		header   map[string]string
+		wantCode int
+		wantUser string
+	}{
+		{name: "no cookie", wantCode: http.StatusOK},
+		{name: "valid cookie", cookie: token, wantCode: http.StatusOK, wantUser: "user-1"},
+		{name: "invalid cookie", cookie: "bogus", wantCode: http.StatusUnauthorized},
+		{name: "bearer token wins", cookie: "bogus", header: map[string]string{"Authorization": "Bearer x"}, wantCode: http.StatusOK},
+		{name: "tenant mismatch", cookie: token, header: map[string]string{tenant.Header: "other"}, wantCode: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got = nil
+			req := httptest.NewRequest(http.MethodGet, "/v1/orders", nil)
+			if tt.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: DefaultCookieName, Value: tt.cookie})
+			}
+			for k, v := range tt.header {
+				req.Header.Set(k, v)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantCode)
+			}
+			if tt.wantUser == "" {
+				if got != nil {
+					t.Errorf("session = %+v, want none", got)
+				}
+				return
+			}
+			if got == nil || got.UserID != tt.wantUser {
+				t.Errorf("session = %+v, want user %s", got, tt.wantUser)
+			}
+			if gotTenant != "acme" {
+				t.Errorf("tenant header = %q, want acme", gotTenant)
+			}
+		})
+	}
+}
+
+func TestMiddlewareStripsSpoofedIdentity(t *testing.T) {
+	m, _ := newTestManager(newMockStore())
+	handler := Middleware(m, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if md := Metadata(r.Context(), r); md != nil {
+			t.Errorf("Metadata() = %v for an anonymous request", md)
+		}
+		if v := r.Header.Get("Grpc-Metadata-X-User-Id"); v != "" {
+			t.Errorf("spoofed user header forwarded: %q", v)
+		}
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/orders", nil)
+	req.Header.Set("Grpc-Metadata-X-User-Id", "admin")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+}
@@ -0,0 +1,91 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package session
+
+import (
+	"context"
+
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/internal/log"
+)
+
+// TieredStore serves sessions from a fast cache, such as Redis, in front of
+// a durable store, such as Postgres. Writes go to the durable store first, so
+// sessions survive losing the cache; reads fall back to the durable store on
+// a cache miss or failure and refill the cache.
+type TieredStore struct {
+	cache   Store
+	durable Store
+	logger  *log.Logger
+}
+
+// NewTieredStore creates a store that caches durable in cache
+func NewTieredStore(cache, durable Store, logger *log.Logger) *TieredStore {
+	if logger == nil {
+		logger = log.NewDefault()
+	}
+	return &TieredStore{cache: cache, durable: durable, logger: logger}
+}
+
+// Save writes a session to both stores. A cache failure is logged rather
+// than returned; reads recover from the durable copy.
+func (t *TieredStore) Save(ctx context.Context, s *Session) error {
+	if err := t.durable.Save(ctx, s); err != nil {
+		return err
+	}
+	if err := t.cache.Save(ctx, s); err != nil {
+		t.logger.Warn("Failed to cache session", log.String("session_id", s.ID), log.Error(err))
+	}
+	return nil
+}
+
+// Get reads a session from the cache, falling back to the durable store
+func (t *TieredStore) Get(ctx context.Context, id string) (*Session, error) {
+	s, err := t.cache.Get(ctx, id)
+	if err == nil {
+		return s, nil
+	}
+	if errors.GetCode(err) != errors.CodeNotFound {
+		t.logger.Warn("Session cache unavailable", log.String("session_id", id), log.Error(err))
+	}
+
+	s, err = t.durable.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := t.cache.Save(ctx, s); err != nil {
+		t.logger.Warn("Failed to cache session", log.String("session_id", id), log.Error(err))
+	}
+	return s, nil
+}
+
+// Delete removes a session from both stores. Unlike Save, a cache failure is
+// returned: the cached copy would otherwise keep a revoked session alive.
+func (t *TieredStore) Delete(ctx context.Context, id string) error {
+	if err := t.durable.Delete(ctx, id); err != nil {
+		return err
+	}
+	return t.cache.Delete(ctx, id)
+}
+
+// DeleteByUser removes every session of a user from both stores
+func (t *TieredStore) DeleteByUser(ctx context.Context, tenantID, userID string) error {
+	if err := t.durable.DeleteByUser(ctx, tenantID, userID); err != nil {
+		return err
+	}
+	return t.cache.DeleteByUser(ctx, tenantID, userID)
+}
@@ -0,0 +1,30 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package signing
+
+import "testing"
+
+func TestSign(t *testing.T) {
+	// echo -n '1700000000.{"id":1}' | openssl dgst -sha256 -hmac secret
+	want := "sha256=3dd1b9aef568d75f6790a84bd2e5dfa1f44409eef3cbdbd3f10b837376100c11"
+	if got := Sign("secret", 1700000000, []byte(`{"id":1}`)); got != want {
+		t.Errorf("Sign() = %q, want %q", got, want)
+	}
+	if Sign("secret", 1700000001, []byte(`{"id":1}`)) == want {
+		t.Error("Sign() ignored the timestamp")
+	}
+}
@@ -0,0 +1,279 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package slo tracks the success rate and latency of each RPC method
+// against its service level objectives over rolling windows, and exports
+// how fast each method burns its error budget.
+//
+// A burn rate of 1 spends the budget exactly over the objective's period;
+// alerting on a high burn rate over both a short and a long window catches
+// a method burning its budget fast without paging on a brief blip.
+package slo
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/config"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// bucketsPerWindow is how many buckets the shortest window spans
+const bucketsPerWindow = 10
+
+// Objective is the service level objective of a method
+type Objective struct {
+	// Availability is the target ratio of calls that do not fail with a
+	// server error
+	Availability float64
+	// LatencyTarget is the target ratio of calls that complete within
+	// LatencyThreshold
+	LatencyThreshold time.Duration
+	LatencyTarget    float64
+}
+
+// Config configures a Tracker
+type Config struct {
+	Default Objective
+	// Methods overrides the default objective by full gRPC method name
+	Methods map[string]Objective
+	Windows []time.Duration
+	// Namespace prefixes the metric names
+	Namespace string
+}
+
+// FromConfig builds the tracker config from the SLO config
+func FromConfig(cfg *config.SLO, namespace string) Config {
+	c := Config{
+		Default: Objective{
+			Availability:     cfg.Availability,
+			LatencyThreshold: cfg.LatencyThreshold,
+			LatencyTarget:    cfg.LatencyTarget,
+		},
+		Methods:   make(map[string]Objective, len(cfg.Methods)),
+		Windows:   cfg.Windows,
+		Namespace: namespace,
+	}
+	for _, m := range cfg.Methods {
+		objective := c.Default
+		if m.Availability > 0 {
+			objective.Availability = m.Availability
+		}
+		if m.LatencyThreshold > 0 {
+			objective.LatencyThreshold = m.LatencyThreshold
+		}
+		if m.LatencyTarget > 0 {
+			objective.LatencyTarget = m.LatencyTarget
+		}
+		c.Methods[m.Method] = objective
+	}
+	return c
+}
+
+// Tracker records calls per method and exports their SLO metrics. It is a
+// prometheus.Collector that computes the metrics when scraped.
+type Tracker struct {
+	cfg        Config
+	resolution time.Duration
+	buckets    int
+	now        func() time.Time
+
+	mu      sync.Mutex
+	methods map[string]*series
+
+	objective *prometheus.Desc
+	sli       *prometheus.Desc
+	burnRate  *prometheus.Desc
+}
+
+// New creates a tracker
+func New(cfg Config) *Tracker {
+	shortest, longest := cfg.Windows[0], cfg.Windows[0]
+	for _, w := range cfg.Windows {
+		if w < shortest {
+			shortest = w
+		}
+		if w > longest {
+			longest = w
+		}
+	}
+	resolution := shortest / bucketsPerWindow
+	if resolution < time.Second {
+		resolution = time.Second
+	}
+
+	name := func(n string) string {
+		return prometheus.BuildFQName(cfg.Namespace, "slo", n)
+	}
+	return &Tracker{
+		cfg:        cfg,
+		resolution: resolution,
+		buckets:    int(longest/resolution) + 1,
+		now:        time.Now,
+		methods:    make(map[string]*series),
+		objective: prometheus.NewDesc(name("objective_ratio"),
+			"Target ratio of good calls, by method and SLO.",
+			[]string{"method", "slo"}, nil),
+		sli: prometheus.NewDesc(name("good_ratio"),
+			"Ratio of good calls over the window, by method and SLO.",
+			[]string{"method", "slo", "window"}, nil),
+		burnRate: prometheus.NewDesc(name("burn_rate"),
+			"Rate the error budget is spent at over the window; 1 spends it exactly over the SLO period.",
+			[]string{"method", "slo", "window"}, nil),
+	}
+}
+
+// objectiveOf returns the objective of method
+func (t *Tracker) objectiveOf(method string) Objective {
+	if objective, ok := t.cfg.Methods[method]; ok {
+		return objective
+	}
+	return t.cfg.Default
+}
+
+// Record records a call to method that took d and returned code
+func (t *Tracker) Record(method string, d time.Duration, code codes.Code) {
+	slot := t.now().UnixNano() / int64(t.resolution)
+	objective := t.objectiveOf(method)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	s, ok := t.methods[method]
+	if !ok {
+		s = newSeries(t.buckets)
+		t.methods[method] = s
+	}
+	s.record(slot, serverError(code), d > objective.LatencyThreshold)
+}
+
+// UnaryServerInterceptor records the outcome and duration of gRPC calls
+func (t *Tracker) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		t.Record(info.FullMethod, time.Since(start), status.Code(err))
+		return resp, err
+	}
+}
+
+// Describe implements prometheus.Collector
+func (t *Tracker) Describe(ch chan<- *prometheus.Desc) {
+	ch <- t.objective
+	ch <- t.sli
+	ch <- t.burnRate
+}
+
+// Collect implements prometheus.Collector. Windows without calls are
+// omitted, since they have no ratio.
+func (t *Tracker) Collect(ch chan<- prometheus.Metric) {
+	slot := t.now().UnixNano() / int64(t.resolution)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for method, s := range t.methods {
+		objective := t.objectiveOf(method)
+		ch <- prometheus.MustNewConstMetric(t.objective, prometheus.GaugeValue, objective.Availability, method, "availability")
+		ch <- prometheus.MustNewConstMetric(t.objective, prometheus.GaugeValue, objective.LatencyTarget, method, "latency")
+
+		for _, window := range t.cfg.Windows {
+			total, errs, slow := s.sum(slot, int64(window/t.resolution))
+			if total == 0 {
+				continue
+			}
+			label := windowLabel(window)
+			t.collectSLO(ch, method, "availability", label, objective.Availability, total, errs)
+			t.collectSLO(ch, method, "latency", label, objective.LatencyTarget, total, slow)
+		}
+	}
+}
+
+// collectSLO sends the good ratio and burn rate of one SLO over a window
+func (t *Tracker) collectSLO(ch chan<- prometheus.Metric, method, slo, window string, target float64, total, bad int64) {
+	badRatio := float64(bad) / float64(total)
+	ch <- prometheus.MustNewConstMetric(t.sli, prometheus.GaugeValue, 1-badRatio, method, slo, window)
+	ch <- prometheus.MustNewConstMetric(t.burnRate, prometheus.GaugeValue, badRatio/(1-target), method, slo, window)
+}
+
+// windowLabel formats a window without zero units, as "5m" or "1h"
+func windowLabel(window time.Duration) string {
+	label := window.String()
+	if strings.HasSuffix(label, "m0s") {
+		label = strings.TrimSuffix(label, "0s")
+	}
+	if strings.HasSuffix(label, "h0m") {
+		label = strings.TrimSuffix(label, "0m")
+	}
+	return label
+}
+
+// serverError reports whether code is a failure of the service rather than
+// of the caller; only server errors spend the availability budget
+func serverError(code codes.Code) bool {
+	switch code {
+	case codes.Unknown, codes.DeadlineExceeded, codes.ResourceExhausted,
+		codes.Internal, codes.Unavailable, codes.DataLoss:
+		return true
+	}
+	return false
+}
+
+// bucket counts the calls recorded in one time slot
+type bucket struct {
+	slot   int64
+	total  int64
+	errors int64
+	slow   int64
+}
+
+// series is a ring of buckets covering the longest window
+type series struct {
+	buckets []bucket
+}
+
+func newSeries(n int) *series {
+	return &series{buckets: make([]bucket, n)}
+}
+
+func (s *series) record(slot int64, failed, slow bool) {
+	b := &s.buckets[slot%int64(len(s.buckets))]
+	if b.slot != slot {
+		*b = bucket{slot: slot}
+	}
+	b.total++
+	if failed {
+		b.errors++
+	}
+	if slow {
+		b.slow++
+	}
+}
+
+// sum totals the buckets of the last n slots up to slot
+func (s *series) sum(slot, n int64) (total, errors, slow int64) {
+	for _, b := range s.buckets {
+		if b.slot > slot-n && b.slot <= slot {
+			total += b.total
+			errors += b.errors
+			slow += b.slow
+		}
+	}
+	return total, errors, slow
+}
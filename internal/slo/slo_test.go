@@ -0,0 +1,146 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package slo
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/config"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/codes"
+)
+
+func newTestTracker(now *time.Time) *Tracker {
+	t := New(FromConfig(&config.SLO{
+		Availability:     0.99,
+		LatencyThreshold: 100 * time.Millisecond,
+		LatencyTarget:    0.9,
+		Windows:          []time.Duration{5 * time.Minute, time.Hour},
+		Methods: []config.SLOMethod{
+			{Method: "/order.v1.OrderService/CreateOrder", Availability: 0.999},
+		},
+	}, ""))
+	t.now = func() time.Time { return *now }
+	return t
+}
+
+func TestBurnRate(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	tracker := newTestTracker(&now)
+
+	const getUser = "/user.v1.UserService/GetUser"
+	const createOrder = "/order.v1.OrderService/CreateOrder"
+
+	// An hour ago CreateOrder failed constantly; it has since recovered
+	now = now.Add(-50 * time.Minute)
+	for i := 0; i < 100; i++ {
+		tracker.Record(createOrder, time.Millisecond, codes.Unavailable)
+	}
+	now = now.Add(50 * time.Minute)
+	for i := 0; i < 100; i++ {
+		tracker.Record(createOrder, time.Millisecond, codes.OK)
+	}
+
+	// GetUser is slightly slow, and caller errors do not count against it
+	for i := 0; i < 100; i++ {
+		latency := time.Millisecond
+		if i%5 == 0 {
+			latency = time.Second
+		}
+		code := codes.OK
+		if i%2 == 0 {
+			code = codes.NotFound
+		}
+		tracker.Record(getUser, latency, code)
+	}
+
+	tests := []struct {
+		method string
+		slo    string
+		window string
+		want   float64
+	}{
+		{method: createOrder, slo: "availability", window: "5m", want: 0},
+		{method: createOrder, slo: "availability", window: "1h", want: 0.5 / 0.001},
+		{method: getUser, slo: "availability", window: "5m", want: 0},
+		{method: getUser, slo: "latency", window: "5m", want: 0.2 / 0.1},
+	}
+
+	metrics := gather(t, tracker)
+	for _, tt := range tests {
+		key := tt.method + " " + tt.slo + " " + tt.window
+		got, ok := metrics[key]
+		if !ok {
+			t.Errorf("burn rate %s is missing", key)
+			continue
+		}
+		if diff := got - tt.want; diff > 1e-6 || diff < -1e-6 {
+			t.Errorf("burn rate %s = %v, want %v", key, got, tt.want)
+		}
+	}
+}
+
+func TestWindowsExpire(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	tracker := newTestTracker(&now)
+	tracker.Record("/m", time.Millisecond, codes.Internal)
+
+	now = now.Add(2 * time.Hour)
+	if metrics := gather(t, tracker); len(metrics) != 0 {
+		t.Errorf("expired calls still reported: %v", metrics)
+	}
+}
+
+func TestWindowLabel(t *testing.T) {
+	for window, want := range map[time.Duration]string{
+		5 * time.Minute:  "5m",
+		time.Hour:        "1h",
+		90 * time.Minute: "1h30m",
+		30 * time.Second: "30s",
+	} {
+		if got := windowLabel(window); got != want {
+			t.Errorf("windowLabel(%v) = %q, want %q", window, got, want)
+		}
+	}
+}
+
+// gather returns the burn rates keyed by "method slo window"
+func gather(t *testing.T, tracker *Tracker) map[string]float64 {
+	t.Helper()
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(tracker)
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Gather() error = %v", err)
+	}
+	rates := make(map[string]float64)
+	for _, family := range families {
+		if !strings.HasSuffix(family.GetName(), "slo_burn_rate") {
+			continue
+		}
+		for _, m := range family.GetMetric() {
+			labels := make(map[string]string)
+			for _, l := range m.GetLabel() {
+				labels[l.GetName()] = l.GetValue()
+			}
+			rates[labels["method"]+" "+labels["slo"]+" "+labels["window"]] = m.GetGauge().GetValue()
+		}
+	}
+	return rates
+}
@@ -0,0 +1,206 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/config"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+)
+
+// gcsEndpoint is the GCS XML API, which is S3-compatible for HMAC keys
+const gcsEndpoint = "https://storage.googleapis.com"
+
+// Bucket stores objects in an S3 or GCS bucket over their REST APIs
+type Bucket struct {
+	endpoint  *url.URL
+	bucket    string
+	pathStyle bool
+	signer    *signer
+	client    *http.Client
+	now       func() time.Time
+}
+
+// NewS3 creates a storage for an S3 bucket. Endpoint overrides the regional
+// AWS endpoint for S3-compatible services such as MinIO, which usually also
+// need path-style addressing.
+func NewS3(cfg *config.Storage) (*Bucket, error) {
+	endpoint := cfg.Endpoint
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", cfg.Region)
+	}
+	return newBucket(cfg, endpoint, newS3Signer(cfg.AccessKeyID, cfg.SecretAccessKey, cfg.Region))
+}
+
+// NewGCS creates a storage for a GCS bucket, authenticating with an HMAC
+// key of a service account
+func NewGCS(cfg *config.Storage) (*Bucket, error) {
+	endpoint := cfg.Endpoint
+	if endpoint == "" {
+		endpoint = gcsEndpoint
+	}
+	return newBucket(cfg, endpoint, newGCSSigner(cfg.AccessKeyID, cfg.SecretAccessKey))
+}
+
+func newBucket(cfg *config.Storage, endpoint string, s *signer) (*Bucket, error) {
+	if cfg.Bucket == "" {
+		return nil, errors.WithCode(errors.New("storage bucket is required"), errors.CodeInvalidInput)
+	}
+	if cfg.AccessKeyID == "" || cfg.SecretAccessKey == "" {
+		return nil, errors.WithCode(errors.New("storage access key and secret are required"), errors.CodeInvalidInput)
+	}
+
+	u, err := url.Parse(strings.TrimSuffix(endpoint, "/"))
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return nil, errors.WithCode(errors.Newf("invalid storage endpoint %q", endpoint), errors.CodeInvalidInput)
+	}
+
+	return &Bucket{
+		endpoint:  u,
+		bucket:    cfg.Bucket,
+		pathStyle: cfg.PathStyle,
+		signer:    s,
+		client:    &http.Client{Timeout: 5 * time.Minute},
+		now:       time.Now,
+	}, nil
+}
+
+// Put uploads the object. The body is buffered when r is not a
+// *bytes.Reader, because uploads need a known length.
+func (b *Bucket) Put(ctx context.Context, key string, r io.Reader, contentType string) error {
+	if err := validateKey(key); err != nil {
+		return err
+	}
+
+	body, err := readAll(r)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, b.objectURL(key).String(), body)
+	if err != nil {
+		return errors.Wrap(err, "failed to create upload request")
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := b.do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// Get downloads the object
+func (b *Bucket) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	if err := validateKey(key); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, b.objectURL(key).String(), nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create download request")
+	}
+
+	resp, err := b.do(req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// Delete removes the object
+func (b *Bucket) Delete(ctx context.Context, key string) error {
+	if err := validateKey(key); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, b.objectURL(key).String(), nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to create delete request")
+	}
+
+	resp, err := b.do(req)
+	if err != nil && errors.GetCode(err) != errors.CodeNotFound {
+		return err
+	}
+	if resp != nil {
+		resp.Body.Close()
+	}
+	return nil
+}
+
+// SignedURL returns a presigned URL for the object
+func (b *Bucket) SignedURL(ctx context.Context, key, method string, expiry time.Duration) (string, error) {
+	if err := validateSignedURL(key, method, expiry); err != nil {
+		return "", err
+	}
+
+	u := b.objectURL(key)
+	b.signer.presign(method, u, expiry, b.now())
+	return u.String(), nil
+}
+
+// objectURL addresses key in the bucket, either as a path under the
+// endpoint or on a bucket subdomain
+func (b *Bucket) objectURL(key string) *url.URL {
+	u := *b.endpoint
+	if b.pathStyle {
+		u.Path = u.Path + "/" + b.bucket + "/" + key
+	} else {
+		u.Host = b.bucket + "." + u.Host
+		u.Path = u.Path + "/" + key
+	}
+	return &u
+}
+
+// do signs and sends req, turning error responses into errors with codes
+func (b *Bucket) do(req *http.Request) (*http.Response, error) {
+	b.signer.sign(req, b.now())
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return nil, errors.WithCode(errors.Wrap(err, "storage request failed"), errors.CodeUnavailable)
+	}
+	if resp.StatusCode < 300 {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+
+	detail, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	err = errors.Newf("storage %s %s: %s: %s", req.Method, req.URL.Path, resp.Status, strings.TrimSpace(string(detail)))
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, errors.WithCode(err, errors.CodeNotFound)
+	case resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusUnauthorized:
+		return nil, errors.WithCode(err, errors.CodeForbidden)
+	case resp.StatusCode >= 500:
+		return nil, errors.WithCode(err, errors.CodeUnavailable)
+	default:
+		return nil, errors.WithCode(err, errors.CodeInternal)
+	}
+}
@@ -0,0 +1,184 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Local stores objects as files under a directory. Its signed URLs point at
+// baseURL, where Handler must be mounted to serve them.
+type Local struct {
+	dir     string
+	baseURL string
+	secret  []byte
+}
+
+// NewLocal creates a filesystem storage rooted at dir. secret signs the URLs
+// returned by SignedURL; without it SignedURL fails.
+func NewLocal(dir, baseURL, secret string) (*Local, error) {
+	if dir == "" {
+		return nil, errors.WithCode(errors.New("local storage directory is required"), errors.CodeInvalidInput)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, errors.Wrap(err, "failed to create storage directory")
+	}
+	return &Local{
+		dir:     dir,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		secret:  []byte(secret),
+	}, nil
+}
+
+// Put writes the object through a temporary file so readers never see a
+// partial object
+func (l *Local) Put(ctx context.Context, key string, r io.Reader, contentType string) error {
+	if err := validateKey(key); err != nil {
+		return err
+	}
+
+	path := l.path(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return errors.Wrap(err, "failed to create object directory")
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return errors.Wrap(err, "failed to create object")
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		return errors.Wrap(err, "failed to write object")
+	}
+	if err := tmp.Close(); err != nil {
+		return errors.Wrap(err, "failed to write object")
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return errors.Wrap(err, "failed to store object")
+	}
+	return nil
+}
+
+// Get opens the object file
+func (l *Local) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	if err := validateKey(key); err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(l.path(key))
+	if os.IsNotExist(err) {
+		return nil, errors.WithCode(errors.Newf("object %s not found", key), errors.CodeNotFound)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open object")
+	}
+	return f, nil
+}
+
+// Delete removes the object file
+func (l *Local) Delete(ctx context.Context, key string) error {
+	if err := validateKey(key); err != nil {
+		return err
+	}
+	if err := os.Remove(l.path(key)); err != nil && !os.IsNotExist(err) {
+		return errors.Wrap(err, "failed to delete object")
+	}
+	return nil
+}
+
+// SignedURL returns a URL under baseURL carrying an expiry and an HMAC of
+// the method, key and expiry
+func (l *Local) SignedURL(ctx context.Context, key, method string, expiry time.Duration) (string, error) {
+	if err := validateSignedURL(key, method, expiry); err != nil {
+		return "", err
+	}
+	if len(l.secret) == 0 {
+		return "", errors.WithCode(errors.New("local storage signing secret is not configured"), errors.CodeInternal)
+	}
+
+	expires := strconv.FormatInt(time.Now().Add(expiry).Unix(), 10)
+	q := url.Values{}
+	q.Set("expires", expires)
+	q.Set("signature", l.sign(method, key, expires))
+	return l.baseURL + "/" + (&url.URL{Path: key}).EscapedPath() + "?" + q.Encode(), nil
+}
+
+// Handler serves GET and PUT requests for signed URLs. Mount it at the
+// path of baseURL with that prefix stripped.
+func (l *Local) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := strings.TrimPrefix(r.URL.Path, "/")
+		expires := r.URL.Query().Get("expires")
+
+		unix, err := strconv.ParseInt(expires, 10, 64)
+		if err != nil || time.Now().Unix() > unix || len(l.secret) == 0 ||
+			!hmac.Equal([]byte(r.URL.Query().Get("signature")), []byte(l.sign(r.Method, key, expires))) {
+			errors.WriteHTTP(w, status.Error(codes.PermissionDenied, "invalid or expired signature"))
+			return
+		}
+
+		switch r.Method {
+		case http.MethodGet:
+			f, err := l.Get(r.Context(), key)
+			if err != nil {
+				if errors.GetCode(err) == errors.CodeNotFound {
+					errors.WriteHTTP(w, err)
+					return
+				}
+				errors.WriteHTTP(w, status.Error(codes.Internal, "failed to read object"))
+				return
+			}
+			defer f.Close()
+			io.Copy(w, f)
+		case http.MethodPut:
+			if err := l.Put(r.Context(), key, r.Body, r.Header.Get("Content-Type")); err != nil {
+				errors.WriteHTTP(w, status.Error(codes.Internal, "failed to store object"))
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		default:
+			errors.WriteHTTPStatus(w, http.StatusMethodNotAllowed, status.New(codes.Unimplemented, "method not allowed"))
+		}
+	})
+}
+
+func (l *Local) path(key string) string {
+	return filepath.Join(l.dir, filepath.FromSlash(key))
+}
+
+func (l *Local) sign(method, key, expires string) string {
+	mac := hmac.New(sha256.New, l.secret)
+	mac.Write([]byte(method + "\n" + key + "\n" + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
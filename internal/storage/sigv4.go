@@ -0,0 +1,219 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package storage
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// unsignedPayload skips hashing request bodies, which both S3 and GCS allow
+// over HTTPS
+const unsignedPayload = "UNSIGNED-PAYLOAD"
+
+const signingTimeFormat = "20060102T150405Z"
+
+// signer implements AWS Signature Version 4. GCS accepts the same scheme
+// with HMAC keys under its own names (GOOG4-HMAC-SHA256, x-goog-*), so the
+// vendor-specific strings are fields.
+type signer struct {
+	accessKey string
+	secretKey string
+	region    string
+	service   string
+	// scheme is "AWS4" or "GOOG4"; it names the algorithm and prefixes the
+	// signing key
+	scheme string
+	// vendor is "Amz" or "Goog"; it names the headers and query parameters
+	vendor string
+}
+
+func newS3Signer(accessKey, secretKey, region string) *signer {
+	return &signer{accessKey: accessKey, secretKey: secretKey, region: region, service: "s3", scheme: "AWS4", vendor: "Amz"}
+}
+
+func newGCSSigner(accessKey, secretKey string) *signer {
+	return &signer{accessKey: accessKey, secretKey: secretKey, region: "auto", service: "storage", scheme: "GOOG4", vendor: "Goog"}
+}
+
+func (s *signer) algorithm() string {
+	return s.scheme + "-HMAC-SHA256"
+}
+
+func (s *signer) headerPrefix() string {
+	return "x-" + strings.ToLower(s.vendor) + "-"
+}
+
+func (s *signer) queryPrefix() string {
+	return "X-" + s.vendor + "-"
+}
+
+func (s *signer) scope(t time.Time) string {
+	return t.Format("20060102") + "/" + s.region + "/" + s.service + "/" + s.terminator()
+}
+
+func (s *signer) terminator() string {
+	return strings.ToLower(s.scheme) + "_request"
+}
+
+// sign adds the date, payload hash and Authorization headers to req
+func (s *signer) sign(req *http.Request, now time.Time) {
+	now = now.UTC()
+	req.Header.Set(s.headerPrefix()+"date", now.Format(signingTimeFormat))
+	req.Header.Set(s.headerPrefix()+"content-sha256", unsignedPayload)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name := range req.Header {
+		lower := strings.ToLower(name)
+		if lower == "content-type" || strings.HasPrefix(lower, s.headerPrefix()) {
+			headers[lower] = strings.TrimSpace(req.Header.Get(name))
+		}
+	}
+
+	signedHeaders, canonicalHeaders := canonicalizeHeaders(headers)
+	canonical := strings.Join([]string{
+		req.Method,
+		canonicalURI(req.URL),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders,
+		signedHeaders,
+		unsignedPayload,
+	}, "\n")
+
+	req.Header.Set("Authorization", s.algorithm()+
+		" Credential="+s.accessKey+"/"+s.scope(now)+
+		", SignedHeaders="+signedHeaders+
+		", Signature="+s.signature(canonical, now))
+}
+
+// presign adds the signature query parameters for method on u to u
+func (s *signer) presign(method string, u *url.URL, expiry time.Duration, now time.Time) {
+	now = now.UTC()
+	p := s.queryPrefix()
+
+	q := u.Query()
+	q.Set(p+"Algorithm", s.algorithm())
+	q.Set(p+"Credential", s.accessKey+"/"+s.scope(now))
+	q.Set(p+"Date", now.Format(signingTimeFormat))
+	q.Set(p+"Expires", strconv.FormatInt(int64(expiry/time.Second), 10))
+	q.Set(p+"SignedHeaders", "host")
+
+	canonical := strings.Join([]string{
+		method,
+		canonicalURI(u),
+		canonicalQuery(q),
+		"host:" + u.Host + "\n",
+		"host",
+		unsignedPayload,
+	}, "\n")
+
+	q.Set(p+"Signature", s.signature(canonical, now))
+	u.RawQuery = canonicalQuery(q)
+}
+
+func (s *signer) signature(canonicalRequest string, now time.Time) string {
+	stringToSign := strings.Join([]string{
+		s.algorithm(),
+		now.Format(signingTimeFormat),
+		s.scope(now),
+		hashHex(canonicalRequest),
+	}, "\n")
+
+	key := hmacSHA256([]byte(s.scheme+s.secretKey), now.Format("20060102"))
+	key = hmacSHA256(key, s.region)
+	key = hmacSHA256(key, s.service)
+	key = hmacSHA256(key, s.terminator())
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+func canonicalizeHeaders(headers map[string]string) (signed, canonical string) {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, name := range names {
+		b.WriteString(name + ":" + headers[name] + "\n")
+	}
+	return strings.Join(names, ";"), b.String()
+}
+
+// canonicalURI encodes each path segment, keeping the separators
+func canonicalURI(u *url.URL) string {
+	if u.Path == "" {
+		return "/"
+	}
+	segments := strings.Split(u.Path, "/")
+	for i, segment := range segments {
+		segments[i] = uriEncode(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+func canonicalQuery(q url.Values) string {
+	keys := make([]string, 0, len(q))
+	for k := range q {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var parts []string
+	for _, k := range keys {
+		values := append([]string(nil), q[k]...)
+		sort.Strings(values)
+		for _, v := range values {
+			parts = append(parts, uriEncode(k)+"="+uriEncode(v))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// uriEncode percent-encodes everything except the RFC 3986 unreserved
+// characters, as the signature scheme requires
+func uriEncode(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if ('A' <= c && c <= 'Z') || ('a' <= c && c <= 'z') || ('0' <= c && c <= '9') ||
+			c == '-' || c == '_' || c == '.' || c == '~' {
+			b.WriteByte(c)
+			continue
+		}
+		b.WriteString("%" + strings.ToUpper(hex.EncodeToString([]byte{c})))
+	}
+	return b.String()
+}
+
+func hashHex(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,109 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package storage stores binary objects such as exports, avatars and
+// invoices behind one interface, backed by the local filesystem, Amazon S3
+// or Google Cloud Storage.
+package storage
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/config"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+)
+
+// Storage drivers
+const (
+	DriverLocal = "local"
+	DriverS3    = "s3"
+	DriverGCS   = "gcs"
+)
+
+// MaxSignedURLExpiry is the longest validity S3 and GCS accept for signed URLs
+const MaxSignedURLExpiry = 7 * 24 * time.Hour
+
+// Storage stores objects by key. Keys are slash-separated relative paths
+// such as "exports/acme/users.csv".
+type Storage interface {
+	// Put stores the contents of r under key, replacing any existing object
+	Put(ctx context.Context, key string, r io.Reader, contentType string) error
+	// Get opens the object stored under key. A missing object is reported
+	// with errors.CodeNotFound.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the object stored under key; deleting a missing object
+	// is not an error
+	Delete(ctx context.Context, key string) error
+	// SignedURL returns a URL that allows method (GET or PUT) on key without
+	// further credentials until expiry has passed
+	SignedURL(ctx context.Context, key, method string, expiry time.Duration) (string, error)
+}
+
+// New creates the storage configured by cfg
+func New(cfg *config.Storage) (Storage, error) {
+	switch cfg.Driver {
+	case DriverLocal, "":
+		return NewLocal(cfg.LocalDir, cfg.BaseURL, cfg.SigningSecret)
+	case DriverS3:
+		return NewS3(cfg)
+	case DriverGCS:
+		return NewGCS(cfg)
+	default:
+		return nil, errors.WithCode(errors.Newf("unsupported storage driver %q", cfg.Driver), errors.CodeInvalidInput)
+	}
+}
+
+// validateKey rejects keys that are empty, absolute or escape the storage
+// root
+func validateKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") || path.Clean(key) != key || strings.HasPrefix(key, "../") || key == ".." {
+		return errors.WithCode(errors.Newf("invalid object key %q", key), errors.CodeInvalidInput)
+	}
+	return nil
+}
+
+// validateSignedURL checks the arguments of SignedURL
+func validateSignedURL(key, method string, expiry time.Duration) error {
+	if err := validateKey(key); err != nil {
+		return err
+	}
+	if method != http.MethodGet && method != http.MethodPut {
+		return errors.WithCode(errors.Newf("unsupported signed URL method %q", method), errors.CodeInvalidInput)
+	}
+	if expiry <= 0 || expiry > MaxSignedURLExpiry {
+		return errors.WithCode(errors.Newf("signed URL expiry must be between 0 and %s", MaxSignedURLExpiry), errors.CodeInvalidInput)
+	}
+	return nil
+}
+
+// readAll buffers r so its length is known, which the S3 and GCS APIs
+// require for uploads
+func readAll(r io.Reader) (*bytes.Reader, error) {
+	if br, ok := r.(*bytes.Reader); ok {
+		return br, nil
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read object")
+	}
+	return bytes.NewReader(data), nil
+}
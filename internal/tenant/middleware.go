@@ -0,0 +1,120 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package tenant
+
+import (
+	"context"
+	"net/http"
+	"net/textproto"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryServerInterceptor scopes each call to the tenant named in its
+// metadata, rejecting calls whose tenant the resolver does not accept
+func UnaryServerInterceptor(resolver *Resolver) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := resolveIncoming(ctx, resolver)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor is the streaming counterpart of
+// UnaryServerInterceptor
+func StreamServerInterceptor(resolver *Resolver) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := resolveIncoming(ss.Context(), resolver)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// serverStream overrides the context of a stream
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context { return s.ctx }
+
+// resolveIncoming scopes ctx to the tenant named in the incoming metadata
+func resolveIncoming(ctx context.Context, resolver *Resolver) (context.Context, error) {
+	var requested string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(MetadataKey); len(values) > 0 {
+			requested = values[0]
+		}
+	}
+
+	id, err := resolver.Resolve(requested)
+	if err != nil {
+		code := codes.InvalidArgument
+		if errors.GetCode(err) == errors.CodeForbidden {
+			code = codes.PermissionDenied
+		}
+		return nil, status.Error(code, err.Error())
+	}
+	return WithID(ctx, id), nil
+}
+
+// UnaryClientInterceptor forwards the tenant a call's context is scoped to
+// as tenant metadata, so that calls between services act on the tenant of
+// the request that made them
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if id, ok := FromContext(ctx); ok {
+			ctx = metadata.AppendToOutgoingContext(ctx, MetadataKey, id)
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+// Middleware resolves the tenant of each HTTP request from the tenant
+// header, rewrites the header to the resolved tenant so it is forwarded to
+// the backends, and rejects requests whose tenant the resolver does not
+// accept
+func Middleware(resolver *Resolver, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, err := resolver.Resolve(r.Header.Get(Header))
+		if err != nil {
+			errors.WriteHTTP(w, err)
+			return
+		}
+
+		r.Header.Set(Header, id)
+		next.ServeHTTP(w, r.WithContext(WithID(r.Context(), id)))
+	})
+}
+
+// HeaderMatcher is a grpc-gateway header matcher that forwards the tenant
+// header as tenant metadata, deferring to the default matcher otherwise
+func HeaderMatcher(key string) (string, bool) {
+	if textproto.CanonicalMIMEHeaderKey(key) == textproto.CanonicalMIMEHeaderKey(Header) {
+		return MetadataKey, true
+	}
+	return runtime.DefaultHeaderMatcher(key)
+}
@@ -0,0 +1,119 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package tenant carries the tenant a request acts on from the edge down to
+// the repositories, which scope every query by it.
+package tenant
+
+import (
+	"context"
+	"regexp"
+
+	"github.com/kevindiu/monorepo-go-example/internal/config"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+)
+
+// DefaultID is the tenant used by single-tenant deployments and by requests
+// that do not name a tenant
+const DefaultID = "default"
+
+// MetadataKey is the gRPC metadata key carrying the tenant ID
+const MetadataKey = "x-tenant-id"
+
+// Header is the HTTP header carrying the tenant ID
+const Header = "X-Tenant-ID"
+
+// validID restricts tenant IDs to lowercase slugs
+var validID = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,62}$`)
+
+type tenantKey struct{}
+
+// WithID returns a context scoped to tenant id
+func WithID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, id)
+}
+
+// FromContext returns the tenant the context is scoped to, if any
+func FromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(tenantKey{}).(string)
+	return id, ok && id != ""
+}
+
+// ID returns the tenant the context is scoped to, or DefaultID when it is
+// not scoped. Background work such as event consumers therefore acts on the
+// default tenant unless it scopes its context explicitly.
+func ID(ctx context.Context) string {
+	if id, ok := FromContext(ctx); ok {
+		return id
+	}
+	return DefaultID
+}
+
+// Validate checks that id is a well-formed tenant ID
+func Validate(id string) error {
+	if !validID.MatchString(id) {
+		return errors.WithCode(errors.Newf("invalid tenant ID %q", id), errors.CodeInvalidInput)
+	}
+	return nil
+}
+
+// Resolver decides which tenant a request acts on
+type Resolver struct {
+	required      bool
+	defaultTenant string
+	allowed       map[string]bool
+}
+
+// NewResolver creates a resolver from the tenancy configuration. A nil
+// configuration resolves every request to DefaultID.
+func NewResolver(cfg *config.Tenancy) *Resolver {
+	r := &Resolver{defaultTenant: DefaultID}
+	if cfg == nil {
+		return r
+	}
+
+	r.required = cfg.Required
+	if cfg.DefaultTenant != "" {
+		r.defaultTenant = cfg.DefaultTenant
+	}
+	if len(cfg.Tenants) > 0 {
+		r.allowed = make(map[string]bool, len(cfg.Tenants))
+		for _, t := range cfg.Tenants {
+			r.allowed[t] = true
+		}
+	}
+	return r
+}
+
+// Resolve returns the tenant for a request that named id. An empty id falls
+// back to the default tenant unless a tenant is required; when a tenant list
+// is configured, only listed tenants are accepted.
+func (r *Resolver) Resolve(id string) (string, error) {
+	if id == "" {
+		if r.required {
+			return "", errors.WithCode(errors.New("tenant ID is required"), errors.CodeInvalidInput)
+		}
+		id = r.defaultTenant
+	}
+
+	if err := Validate(id); err != nil {
+		return "", err
+	}
+	if r.allowed != nil && !r.allowed[id] {
+		return "", errors.WithCode(errors.Newf("unknown tenant %q", id), errors.CodeForbidden)
+	}
+	return id, nil
+}
@@ -0,0 +1,246 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package tenant
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/kevindiu/monorepo-go-example/internal/config"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     *config.Tenancy
+		id      string
+		want    string
+		wantErr bool
+	}{
+		{name: "nil config defaults", id: "", want: DefaultID},
+		{name: "named tenant", cfg: &config.Tenancy{}, id: "acme", want: "acme"},
+		{name: "configured default", cfg: &config.Tenancy{DefaultTenant: "main"}, id: "", want: "main"},
+		{name: "required", cfg: &config.Tenancy{Required: true}, id: "", wantErr: true},
+		{name: "malformed", cfg: &config.Tenancy{}, id: "Acme Corp", wantErr: true},
+		{name: "allowed", cfg: &config.Tenancy{Tenants: []string{"acme"}}, id: "acme", want: "acme"},
+		{name: "not allowed", cfg: &config.Tenancy{Tenants: []string{"acme"}}, id: "globex", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewResolver(tt.cfg).Resolve(tt.id)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Resolve() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Resolve() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestID(t *testing.T) {
+	if got := ID(context.Background()); got != DefaultID {
+		t.Errorf("ID() = %q, want %q", got, DefaultID)
+	}
+	if got := ID(WithID(context.Background(), "acme")); got != "acme" {
+		t.Errorf("ID() = %q, want acme", got)
+	}
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	resolver := NewResolver(&config.Tenancy{Tenants: []string{"acme", DefaultID}})
+	info := &grpc.UnaryServerInfo{FullMethod: "/order.v1.OrderService/GetOrder"}
+
+	tests := []struct {
+		name     string
+		md       metadata.MD
+		want     string
+		wantCode codes.Code
+	}{
+		{name: "no metadata", want: DefaultID},
+		{name: "tenant metadata", md: metadata.Pairs(MetadataKey, "acme"), want: "acme"},
+		{name: "unknown tenant", md: metadata.Pairs(MetadataKey, "globex"), wantCode: codes.PermissionDenied},
+		{name: "malformed tenant", md: metadata.Pairs(MetadataKey, "../acme"), wantCode: codes.InvalidArgument},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.md != nil {
+				ctx = metadata.NewIncomingContext(ctx, tt.md)
+			}
+
+			var got string
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				got, _ = FromContext(ctx)
+				return nil, nil
+			}
+
+			_, err := UnaryServerInterceptor(resolver)(ctx, nil, info, handler)
+			if code := status.Code(err); code != tt.wantCode {
+				t.Fatalf("code = %v, want %v", code, tt.wantCode)
+			}
+			if got != tt.want {
+				t.Errorf("tenant = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// contextStream is a server stream with a fixed context
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context { return s.ctx }
+
+func TestStreamServerInterceptor(t *testing.T) {
+	resolver := NewResolver(&config.Tenancy{Tenants: []string{"acme", DefaultID}})
+	info := &grpc.StreamServerInfo{FullMethod: "/order.v1.OrderService/ImportOrders", IsClientStream: true}
+
+	tests := []struct {
+		name     string
+		md       metadata.MD
+		want     string
+		wantCode codes.Code
+	}{
+		{name: "no metadata", want: DefaultID},
+		{name: "tenant metadata", md: metadata.Pairs(MetadataKey, "acme"), want: "acme"},
+		{name: "unknown tenant", md: metadata.Pairs(MetadataKey, "globex"), wantCode: codes.PermissionDenied},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.md != nil {
+				ctx = metadata.NewIncomingContext(ctx, tt.md)
+			}
+
+			var got string
+			handler := func(srv interface{}, ss grpc.ServerStream) error {
+				got, _ = FromContext(ss.Context())
+				return nil
+			}
+
+			err := StreamServerInterceptor(resolver)(nil, &contextStream{ctx: ctx}, info, handler)
+			if code := status.Code(err); code != tt.wantCode {
+				t.Fatalf("code = %v, want %v", code, tt.wantCode)
+			}
+			if got != tt.want {
+				t.Errorf("tenant = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnaryClientInterceptor(t *testing.T) {
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want []string
+	}{
+		{name: "unscoped", ctx: context.Background()},
+		{name: "scoped", ctx: WithID(context.Background(), "acme"), want: []string{"acme"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+				md, _ := metadata.FromOutgoingContext(ctx)
+				got = md.Get(MetadataKey)
+				return nil
+			}
+
+			if err := UnaryClientInterceptor()(tt.ctx, "/user.v1.UserService/GetUser", nil, nil, nil, invoker); err != nil {
+				t.Fatalf("interceptor error = %v", err)
+			}
+			if len(got) != len(tt.want) || (len(got) > 0 && got[0] != tt.want[0]) {
+				t.Errorf("tenant metadata = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	resolver := NewResolver(&config.Tenancy{Tenants: []string{"acme", DefaultID}})
+
+	tests := []struct {
+		name     string
+		header   string
+		want     string
+		wantCode int
+	}{
+		{name: "default tenant", want: DefaultID, wantCode: http.StatusOK},
+		{name: "named tenant", header: "acme", want: "acme", wantCode: http.StatusOK},
+		{name: "unknown tenant", header: "globex", wantCode: http.StatusForbidden},
+		{name: "malformed tenant", header: "ACME", wantCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got, forwarded string
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got, _ = FromContext(r.Context())
+				forwarded = r.Header.Get(Header)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/v1/orders", nil)
+			if tt.header != "" {
+				req.Header.Set(Header, tt.header)
+			}
+			rec := httptest.NewRecorder()
+			Middleware(resolver, next).ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantCode)
+			}
+			if rec.Code != http.StatusOK {
+				var body errors.HTTPBody
+				if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || body.Code == "" || body.Message == "" {
+					t.Errorf("error body = %s, want {code, message, details}", rec.Body.String())
+				}
+			}
+			if got != tt.want || forwarded != tt.want {
+				t.Errorf("tenant = %q, forwarded header = %q, want %q", got, forwarded, tt.want)
+			}
+		})
+	}
+}
+
+func TestHeaderMatcher(t *testing.T) {
+	if key, ok := HeaderMatcher("x-tenant-id"); !ok || key != MetadataKey {
+		t.Errorf("HeaderMatcher(x-tenant-id) = %q, %v, want %q, true", key, ok, MetadataKey)
+	}
+	if _, ok := HeaderMatcher("X-Unrelated"); ok {
+		t.Error("HeaderMatcher() forwarded an unrelated header")
+	}
+	if key, ok := HeaderMatcher("Authorization"); !ok || key != runtime.MetadataPrefix+"Authorization" {
+		t.Errorf("HeaderMatcher(Authorization) = %q, %v, want the default mapping", key, ok)
+	}
+}
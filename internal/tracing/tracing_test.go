@@ -0,0 +1,84 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/kevindiu/monorepo-go-example/internal/config"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestSetupDisabledPropagatesContext(t *testing.T) {
+	shutdown, err := Setup(context.Background(), &config.Observability{}, "test")
+	if err != nil {
+		t.Fatalf("Setup() error = %v", err)
+	}
+	if err := shutdown(context.Background()); err != nil {
+		t.Errorf("shutdown() error = %v", err)
+	}
+
+	header := http.Header{}
+	header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	ctx := otel.GetTextMapPropagator().Extract(context.Background(), propagation.HeaderCarrier(header))
+	if sc := trace.SpanContextFromContext(ctx); !sc.IsValid() || sc.TraceID().String() != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("extracted span context = %v, want the traceparent's trace", sc)
+	}
+}
+
+func TestSampler(t *testing.T) {
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	parent := func(flags trace.TraceFlags) context.Context {
+		return trace.ContextWithRemoteSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID:    traceID,
+			SpanID:     spanID,
+			TraceFlags: flags,
+			Remote:     true,
+		}))
+	}
+
+	tests := []struct {
+		name  string
+		ratio float64
+		ctx   context.Context
+		want  sdktrace.SamplingDecision
+	}{
+		{name: "root sampled", ratio: 1, ctx: context.Background(), want: sdktrace.RecordAndSample},
+		{name: "root dropped", ratio: 0, ctx: context.Background(), want: sdktrace.Drop},
+		{name: "follows sampled parent", ratio: 0, ctx: parent(trace.FlagsSampled), want: sdktrace.RecordAndSample},
+		{name: "follows unsampled parent", ratio: 1, ctx: parent(0), want: sdktrace.Drop},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Sampler(tt.ratio).ShouldSample(sdktrace.SamplingParameters{
+				ParentContext: tt.ctx,
+				TraceID:       traceID,
+				Name:          "GET /v1/orders",
+			})
+			if result.Decision != tt.want {
+				t.Errorf("decision = %v, want %v", result.Decision, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package ingest
+
+import (
+	"context"
+
+	"github.com/kevindiu/monorepo-go-example/internal/audit"
+	"github.com/kevindiu/monorepo-go-example/internal/events"
+	"github.com/kevindiu/monorepo-go-example/internal/log"
+	"github.com/kevindiu/monorepo-go-example/pkg/audit/repository"
+)
+
+// Group is the consumer group used by audit ingestion
+const Group = "audit-service"
+
+// Consumer appends audit events received from the event bus
+type Consumer struct {
+	repo   repository.Repository
+	logger *log.Logger
+}
+
+// New creates a new consumer
+func New(repo repository.Repository, logger *log.Logger) *Consumer {
+	if logger == nil {
+		logger = log.NewDefault()
+	}
+	return &Consumer{
+		repo:   repo,
+		logger: logger.Named("audit-ingest"),
+	}
+}
+
+// Run consumes topic until ctx is cancelled
+func (c *Consumer) Run(ctx context.Context, subscriber events.Subscriber, topic string) error {
+	c.logger.Info("Consuming audit events", log.String("topic", topic))
+	return subscriber.Subscribe(ctx, topic, Group, c.Handle)
+}
+
+// Handle appends one audit event. Appends are idempotent on the event ID, so
+// redelivered events are not duplicated.
+func (c *Consumer) Handle(ctx context.Context, event *events.Event) error {
+	if event.Type != audit.EventType {
+		c.logger.Debug("Ignoring event", log.String("type", event.Type))
+		return nil
+	}
+
+	var e audit.Event
+	if err := event.Decode(&e); err != nil {
+		// A malformed event will never decode, so do not ask for redelivery
+		c.logger.Error("Dropping malformed audit event", log.String("event_id", event.ID), log.Error(err))
+		return nil
+	}
+
+	_, err := c.repo.Append(ctx, &e)
+	return err
+}
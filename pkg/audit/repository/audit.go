@@ -0,0 +1,295 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/audit"
+	"github.com/kevindiu/monorepo-go-example/internal/db"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+)
+
+// chainLockKey serializes appends so every event links to its predecessor
+const chainLockKey = 0x61756469745f6c67 // "audit_lg"
+
+// genesisHash is the previous hash of the first event in the chain
+var genesisHash = strings.Repeat("0", 64)
+
+// Entry is a stored audit event with its position in the hash chain
+type Entry struct {
+	Seq int64
+	audit.Event
+	PrevHash string
+	Hash     string
+}
+
+// Filter narrows down List
+type Filter struct {
+	Actor        string
+	Action       string
+	Service      string
+	ResourceType string
+	ResourceID   string
+	Since        time.Time
+	Until        time.Time
+}
+
+// Verification is the outcome of checking the hash chain
+type Verification struct {
+	Checked int64
+	// BrokenSeq is the first entry whose hash does not match; zero when the
+	// chain is intact
+	BrokenSeq int64
+}
+
+// Repository defines the audit repository interface
+type Repository interface {
+	Append(ctx context.Context, event *audit.Event) (*Entry, error)
+	List(ctx context.Context, filter Filter, limit, offset int) ([]*Entry, error)
+	Verify(ctx context.Context) (*Verification, error)
+}
+
+type repository struct {
+	db *db.DB
+}
+
+// New creates a new audit repository
+func New(database *db.DB) Repository {
+	return &repository{
+		db: database,
+	}
+}
+
+const entryColumns = `seq, id, service, actor, action, resource_type, resource_id, outcome,
+	metadata, occurred_at, prev_hash, hash`
+
+func scanEntry(s interface{ Scan(...interface{}) error }) (*Entry, error) {
+	var (
+		entry    Entry
+		metadata []byte
+	)
+	if err := s.Scan(
+		&entry.Seq,
+		&entry.ID,
+		&entry.Service,
+		&entry.Actor,
+		&entry.Action,
+		&entry.ResourceType,
+		&entry.ResourceID,
+		&entry.Outcome,
+		&metadata,
+		&entry.OccurredAt,
+		&entry.PrevHash,
+		&entry.Hash,
+	); err != nil {
+		return nil, err
+	}
+
+	if len(metadata) > 0 {
+		if err := json.Unmarshal(metadata, &entry.Metadata); err != nil {
+			return nil, errors.Wrap(err, "failed to decode audit metadata")
+		}
+	}
+	entry.OccurredAt = entry.OccurredAt.UTC()
+
+	return &entry, nil
+}
+
+// Append adds an event to the end of the hash chain. Appending an event whose
+// ID is already stored returns the stored entry, so redelivered events are
+// harmless.
+func (r *repository) Append(ctx context.Context, event *audit.Event) (*Entry, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1)`, chainLockKey); err != nil {
+		return nil, errors.Wrap(err, "failed to lock audit chain")
+	}
+
+	existing, err := scanEntry(tx.QueryRowContext(ctx, `SELECT `+entryColumns+` FROM audit_events WHERE id = $1`, event.ID))
+	if err == nil {
+		return existing, nil
+	}
+	if err != sql.ErrNoRows {
+		return nil, errors.Wrap(err, "failed to check audit event")
+	}
+
+	prevHash := genesisHash
+	err = tx.QueryRowContext(ctx, `SELECT hash FROM audit_events ORDER BY seq DESC LIMIT 1`).Scan(&prevHash)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, errors.Wrap(err, "failed to read audit chain head")
+	}
+
+	entry := &Entry{Event: *event, PrevHash: prevHash}
+	entry.OccurredAt = entry.OccurredAt.UTC().Truncate(time.Microsecond)
+	entry.Hash = audit.Hash(prevHash, &entry.Event)
+
+	var metadata []byte
+	if len(entry.Metadata) > 0 {
+		if metadata, err = json.Marshal(entry.Metadata); err != nil {
+			return nil, errors.Wrap(err, "failed to encode audit metadata")
+		}
+	}
+
+	query := `
+		INSERT INTO audit_events (id, service, actor, action, resource_type, resource_id, outcome, metadata, occurred_at, prev_hash, hash)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING seq
+	`
+	if err := tx.QueryRowContext(ctx, query,
+		entry.ID,
+		entry.Service,
+		entry.Actor,
+		entry.Action,
+		entry.ResourceType,
+		entry.ResourceID,
+		entry.Outcome,
+		metadata,
+		entry.OccurredAt,
+		entry.PrevHash,
+		entry.Hash,
+	).Scan(&entry.Seq); err != nil {
+		return nil, errors.Wrap(err, "failed to append audit event")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, errors.Wrap(err, "failed to commit transaction")
+	}
+
+	return entry, nil
+}
+
+// List retrieves audit events matching filter, newest first
+func (r *repository) List(ctx context.Context, filter Filter, limit, offset int) ([]*Entry, error) {
+	var since, until *time.Time
+	if !filter.Since.IsZero() {
+		since = &filter.Since
+	}
+	if !filter.Until.IsZero() {
+		until = &filter.Until
+	}
+
+	query := `
+		SELECT ` + entryColumns + `
+		FROM audit_events
+		WHERE ($1 = '' OR actor = $1)
+		  AND ($2 = '' OR action = $2)
+		  AND ($3 = '' OR service = $3)
+		  AND ($4 = '' OR resource_type = $4)
+		  AND ($5 = '' OR resource_id = $5)
+		  AND ($6::timestamptz IS NULL OR occurred_at >= $6)
+		  AND ($7::timestamptz IS NULL OR occurred_at < $7)
+		ORDER BY seq DESC
+		LIMIT $8 OFFSET $9
+	`
+
+	rows, err := r.db.Executor(ctx).QueryContext(ctx, query,
+		filter.Actor,
+		filter.Action,
+		filter.Service,
+		filter.ResourceType,
+		filter.ResourceID,
+		since,
+		until,
+		limit,
+		offset,
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list audit events")
+	}
+	defer rows.Close()
+
+	var entries []*Entry
+	for rows.Next() {
+		entry, err := scanEntry(rows)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to scan audit event")
+		}
+		entries = append(entries, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "error iterating audit events")
+	}
+
+	return entries, nil
+}
+
+// Verify walks the whole chain in order and recomputes every hash
+func (r *repository) Verify(ctx context.Context) (*Verification, error) {
+	rows, err := r.db.Executor(ctx).QueryContext(ctx, `SELECT `+entryColumns+` FROM audit_events ORDER BY seq ASC`)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read audit chain")
+	}
+	defer rows.Close()
+
+	v := newVerifier()
+	for rows.Next() {
+		entry, err := scanEntry(rows)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to scan audit event")
+		}
+		if !v.next(entry) {
+			return v.result, nil
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "error iterating audit events")
+	}
+
+	return v.result, nil
+}
+
+// VerifyChain checks that entries, in sequence order, form an unbroken chain
+func VerifyChain(entries []*Entry) *Verification {
+	v := newVerifier()
+	for _, entry := range entries {
+		if !v.next(entry) {
+			break
+		}
+	}
+	return v.result
+}
+
+type verifier struct {
+	prevHash string
+	result   *Verification
+}
+
+func newVerifier() *verifier {
+	return &verifier{prevHash: genesisHash, result: &Verification{}}
+}
+
+// next checks one entry and reports whether the chain is still intact
+func (v *verifier) next(entry *Entry) bool {
+	v.result.Checked++
+	if entry.PrevHash != v.prevHash || entry.Hash != audit.Hash(v.prevHash, &entry.Event) {
+		v.result.BrokenSeq = entry.Seq
+		return false
+	}
+	v.prevHash = entry.Hash
+	return true
+}
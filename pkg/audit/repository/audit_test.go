@@ -0,0 +1,66 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package repository
+
+import (
+	"testing"
+
+	"github.com/kevindiu/monorepo-go-example/internal/audit"
+)
+
+func buildChain(n int) []*Entry {
+	entries := make([]*Entry, n)
+	prevHash := genesisHash
+	for i := range entries {
+		e := audit.NewEvent("order-service", "u1", "/order.v1.OrderService/CreateOrder", "order", "", audit.OutcomeSuccess)
+		entries[i] = &Entry{Seq: int64(i + 1), Event: *e, PrevHash: prevHash, Hash: audit.Hash(prevHash, e)}
+		prevHash = entries[i].Hash
+	}
+	return entries
+}
+
+func TestVerifyChain(t *testing.T) {
+	tests := []struct {
+		name       string
+		tamper     func(entries []*Entry) []*Entry
+		wantBroken int64
+	}{
+		{name: "intact", tamper: func(e []*Entry) []*Entry { return e }},
+		{name: "modified field", tamper: func(e []*Entry) []*Entry {
+			e[2].Actor = "someone-else"
+			return e
+		}, wantBroken: 3},
+		{name: "removed entry", tamper: func(e []*Entry) []*Entry {
+			return append(e[:1], e[2:]...)
+		}, wantBroken: 3},
+		{name: "rehashed entry", tamper: func(e []*Entry) []*Entry {
+			// Recomputing one hash is not enough; the successor no longer links
+			e[1].Outcome = audit.OutcomeFailure
+			e[1].Hash = audit.Hash(e[1].PrevHash, &e[1].Event)
+			return e
+		}, wantBroken: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := VerifyChain(tt.tamper(buildChain(5)))
+			if result.BrokenSeq != tt.wantBroken {
+				t.Errorf("VerifyChain() broken at %d, want %d", result.BrokenSeq, tt.wantBroken)
+			}
+		})
+	}
+}
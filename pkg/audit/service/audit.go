@@ -0,0 +1,173 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package service
+
+import (
+	"context"
+	"strconv"
+
+	auditv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/audit/v1"
+	"github.com/kevindiu/monorepo-go-example/internal/audit"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/internal/log"
+	"github.com/kevindiu/monorepo-go-example/pkg/audit/repository"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Service defines the audit service interface
+type Service interface {
+	auditv1.AuditServiceServer
+}
+
+type service struct {
+	auditv1.UnimplementedAuditServiceServer
+	repo   repository.Repository
+	logger *log.Logger
+}
+
+// New creates a new audit service
+func New(repo repository.Repository, logger *log.Logger) Service {
+	return &service{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// RecordEvent appends an audit event
+func (s *service) RecordEvent(ctx context.Context, req *auditv1.RecordEventRequest) (*auditv1.RecordEventResponse, error) {
+	if req.GetService() == "" {
+		return nil, errors.WithCode(errors.New("service is required"), errors.CodeInvalidInput)
+	}
+	if req.GetAction() == "" {
+		return nil, errors.WithCode(errors.New("action is required"), errors.CodeInvalidInput)
+	}
+
+	outcome := req.GetOutcome()
+	if outcome == "" {
+		outcome = audit.OutcomeSuccess
+	}
+	if outcome != audit.OutcomeSuccess && outcome != audit.OutcomeFailure {
+		return nil, errors.WithCode(errors.Newf("invalid outcome %q", outcome), errors.CodeInvalidInput)
+	}
+
+	event := audit.NewEvent(req.GetService(), req.GetActor(), req.GetAction(), req.GetResourceType(), req.GetResourceId(), outcome)
+	event.Metadata = req.GetMetadata()
+	if req.GetOccurredAt() != nil {
+		event.OccurredAt = req.GetOccurredAt().AsTime()
+	}
+
+	entry, err := s.repo.Append(ctx, event)
+	if err != nil {
+		s.logger.Error("Failed to record audit event", log.Error(err))
+		return nil, err
+	}
+
+	return &auditv1.RecordEventResponse{Event: entryToProto(entry)}, nil
+}
+
+// ListEvents retrieves audit events, newest first
+func (s *service) ListEvents(ctx context.Context, req *auditv1.ListEventsRequest) (*auditv1.ListEventsResponse, error) {
+	pageSize := int(req.GetPageSize())
+	if pageSize <= 0 {
+		pageSize = 50
+	}
+	if pageSize > 500 {
+		pageSize = 500
+	}
+
+	offset := 0
+	if req.GetPageToken() != "" {
+		parsedOffset, err := strconv.Atoi(req.GetPageToken())
+		if err == nil && parsedOffset > 0 {
+			offset = parsedOffset
+		}
+	}
+
+	filter := repository.Filter{
+		Actor:        req.GetActor(),
+		Action:       req.GetAction(),
+		Service:      req.GetService(),
+		ResourceType: req.GetResourceType(),
+		ResourceID:   req.GetResourceId(),
+	}
+	if req.GetSince() != nil {
+		filter.Since = req.GetSince().AsTime()
+	}
+	if req.GetUntil() != nil {
+		filter.Until = req.GetUntil().AsTime()
+	}
+	if !filter.Since.IsZero() && !filter.Until.IsZero() && !filter.Until.After(filter.Since) {
+		return nil, errors.WithCode(errors.New("until must be later than since"), errors.CodeInvalidInput)
+	}
+
+	entries, err := s.repo.List(ctx, filter, pageSize, offset)
+	if err != nil {
+		s.logger.Error("Failed to list audit events", log.Error(err))
+		return nil, err
+	}
+
+	pbEvents := make([]*auditv1.AuditEvent, len(entries))
+	for i, entry := range entries {
+		pbEvents[i] = entryToProto(entry)
+	}
+
+	nextPageToken := ""
+	if len(entries) == pageSize {
+		nextPageToken = strconv.Itoa(offset + pageSize)
+	}
+
+	return &auditv1.ListEventsResponse{
+		Events:        pbEvents,
+		NextPageToken: nextPageToken,
+	}, nil
+}
+
+// VerifyChain recomputes the hash chain to detect tampering
+func (s *service) VerifyChain(ctx context.Context, req *auditv1.VerifyChainRequest) (*auditv1.VerifyChainResponse, error) {
+	result, err := s.repo.Verify(ctx)
+	if err != nil {
+		s.logger.Error("Failed to verify audit chain", log.Error(err))
+		return nil, err
+	}
+
+	if result.BrokenSeq != 0 {
+		s.logger.Warn("Audit chain is broken", log.Int64("seq", result.BrokenSeq))
+	}
+
+	return &auditv1.VerifyChainResponse{
+		Intact:    result.BrokenSeq == 0,
+		Checked:   result.Checked,
+		BrokenSeq: result.BrokenSeq,
+	}, nil
+}
+
+func entryToProto(entry *repository.Entry) *auditv1.AuditEvent {
+	return &auditv1.AuditEvent{
+		Id:           entry.ID,
+		Seq:          entry.Seq,
+		Service:      entry.Service,
+		Actor:        entry.Actor,
+		Action:       entry.Action,
+		ResourceType: entry.ResourceType,
+		ResourceId:   entry.ResourceID,
+		Outcome:      entry.Outcome,
+		Metadata:     entry.Metadata,
+		OccurredAt:   timestamppb.New(entry.OccurredAt),
+		PrevHash:     entry.PrevHash,
+		Hash:         entry.Hash,
+	}
+}
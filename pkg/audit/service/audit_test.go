@@ -0,0 +1,121 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	auditv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/audit/v1"
+	"github.com/kevindiu/monorepo-go-example/internal/audit"
+	"github.com/kevindiu/monorepo-go-example/internal/log"
+	"github.com/kevindiu/monorepo-go-example/pkg/audit/repository"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// mockRepository implements repository.Repository for testing
+type mockRepository struct {
+	entries    []*repository.Entry
+	lastFilter repository.Filter
+}
+
+func (m *mockRepository) Append(ctx context.Context, event *audit.Event) (*repository.Entry, error) {
+	entry := &repository.Entry{Seq: int64(len(m.entries) + 1), Event: *event}
+	m.entries = append(m.entries, entry)
+	return entry, nil
+}
+
+func (m *mockRepository) List(ctx context.Context, filter repository.Filter, limit, offset int) ([]*repository.Entry, error) {
+	m.lastFilter = filter
+	return m.entries, nil
+}
+
+func (m *mockRepository) Verify(ctx context.Context) (*repository.Verification, error) {
+	return &repository.Verification{Checked: int64(len(m.entries))}, nil
+}
+
+func TestRecordEvent(t *testing.T) {
+	svc := New(&mockRepository{}, log.NewDefault())
+
+	tests := []struct {
+		name    string
+		req     *auditv1.RecordEventRequest
+		wantErr bool
+	}{
+		{
+			name: "valid request",
+			req:  &auditv1.RecordEventRequest{Service: "billing", Actor: "u1", Action: "invoice.issue"},
+		},
+		{
+			name:    "missing service",
+			req:     &auditv1.RecordEventRequest{Action: "invoice.issue"},
+			wantErr: true,
+		},
+		{
+			name:    "missing action",
+			req:     &auditv1.RecordEventRequest{Service: "billing"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid outcome",
+			req:     &auditv1.RecordEventRequest{Service: "billing", Action: "invoice.issue", Outcome: "maybe"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := svc.RecordEvent(context.Background(), tt.req)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("RecordEvent() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && resp.GetEvent().GetOutcome() != audit.OutcomeSuccess {
+				t.Errorf("RecordEvent() outcome = %q, want default %q", resp.GetEvent().GetOutcome(), audit.OutcomeSuccess)
+			}
+		})
+	}
+}
+
+func TestListEventsFilters(t *testing.T) {
+	repo := &mockRepository{}
+	svc := New(repo, log.NewDefault())
+
+	since := time.Now().Add(-time.Hour)
+	_, err := svc.ListEvents(context.Background(), &auditv1.ListEventsRequest{
+		Actor:        "u1",
+		ResourceType: "order",
+		ResourceId:   "o1",
+		Since:        timestamppb.New(since),
+	})
+	if err != nil {
+		t.Fatalf("ListEvents() error = %v", err)
+	}
+
+	f := repo.lastFilter
+	if f.Actor != "u1" || f.ResourceType != "order" || f.ResourceID != "o1" || !f.Since.Equal(since) || !f.Until.IsZero() {
+		t.Errorf("ListEvents() filter = %+v", f)
+	}
+
+	_, err = svc.ListEvents(context.Background(), &auditv1.ListEventsRequest{
+		Since: timestamppb.New(since),
+		Until: timestamppb.New(since.Add(-time.Minute)),
+	})
+	if err == nil {
+		t.Error("ListEvents() accepted an inverted time range")
+	}
+}
@@ -0,0 +1,235 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package bff serves backend-for-frontend routes that compose several
+// backend calls into one payload shaped for mobile clients
+package bff
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	orderv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/order/v1"
+	recommendationv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/recommendation/v1"
+	shippingv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/shipping/v1"
+	userv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/user/v1"
+	"github.com/kevindiu/monorepo-go-example/internal/config"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/internal/log"
+	"github.com/kevindiu/monorepo-go-example/internal/session"
+	"github.com/kevindiu/monorepo-go-example/pkg/client"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Defaults for unset configuration
+const (
+	defaultUserTimeout      = 500 * time.Millisecond
+	defaultOrdersTimeout    = time.Second
+	defaultShipmentsTimeout = 500 * time.Millisecond
+	defaultProductsTimeout  = 500 * time.Millisecond
+	defaultRecentOrders     = 5
+)
+
+// Config holds BFF handler configuration
+type Config struct {
+	UserService  userv1.UserServiceClient
+	OrderService orderv1.OrderServiceClient
+	// RecommendationService adds related products to order details
+	RecommendationService recommendationv1.RecommendationServiceClient
+	// ShippingService adds shipments to order details; nil omits them
+	ShippingService shippingv1.ShippingServiceClient
+	// The timeouts bound each call to a backend; a call that runs out of
+	// time is reported as unavailable
+	UserTimeout      time.Duration
+	OrdersTimeout    time.Duration
+	ShipmentsTimeout time.Duration
+	ProductsTimeout  time.Duration
+	// RecentOrders is the number of orders on the dashboard
+	RecentOrders int
+	Logger       *log.Logger
+}
+
+// ConfigFrom fills a handler configuration from the bff config section
+func ConfigFrom(cfg *config.BFF, users userv1.UserServiceClient, orders orderv1.OrderServiceClient, logger *log.Logger) Config {
+	return Config{
+		UserService:      users,
+		OrderService:     orders,
+		UserTimeout:      cfg.UserTimeout,
+		OrdersTimeout:    cfg.OrdersTimeout,
+		ShipmentsTimeout: cfg.ShipmentsTimeout,
+		ProductsTimeout:  cfg.ProductsTimeout,
+		RecentOrders:     cfg.RecentOrders,
+		Logger:           logger,
+	}
+}
+
+// handler serves the BFF routes
+type handler struct {
+	cfg Config
+}
+
+// NewHandler creates an HTTP handler serving the BFF routes: those under
+// /v1/me/ on behalf of the session user, and GET /v1/orders/{id}/full.
+// Requests must have passed the gateway's tenant and session middleware.
+func NewHandler(cfg Config) http.Handler {
+	if cfg.UserTimeout <= 0 {
+		cfg.UserTimeout = defaultUserTimeout
+	}
+	if cfg.OrdersTimeout <= 0 {
+		cfg.OrdersTimeout = defaultOrdersTimeout
+	}
+	if cfg.ShipmentsTimeout <= 0 {
+		cfg.ShipmentsTimeout = defaultShipmentsTimeout
+	}
+	if cfg.ProductsTimeout <= 0 {
+		cfg.ProductsTimeout = defaultProductsTimeout
+	}
+	if cfg.RecentOrders <= 0 {
+		cfg.RecentOrders = defaultRecentOrders
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = log.NewDefault()
+	}
+
+	h := &handler{cfg: cfg}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/me/dashboard", h.dashboard)
+	mux.HandleFunc("/v1/orders/", h.orderDetails)
+	return mux
+}
+
+// User is the signed-in user
+type User struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+// Order is an order summary
+type Order struct {
+	ID          string    `json:"id"`
+	Status      string    `json:"status"`
+	TotalAmount float64   `json:"totalAmount"`
+	Currency    string    `json:"currency"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+// Dashboard is the home screen payload. Sections whose dependency failed
+// are left empty and named in Unavailable, so clients can render the rest.
+type Dashboard struct {
+	User         *User    `json:"user"`
+	RecentOrders []*Order `json:"recentOrders"`
+	Unavailable  []string `json:"unavailable,omitempty"`
+}
+
+// dependency is one backend call contributing to a composed payload
+type dependency struct {
+	name    string
+	timeout time.Duration
+	fetch   func(ctx context.Context) error
+}
+
+// dashboard serves GET /v1/me/dashboard
+func (h *handler) dashboard(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, codes.Unimplemented, "method not allowed")
+		return
+	}
+	s, ok := session.FromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, codes.Unauthenticated, "sign in required")
+		return
+	}
+
+	ctx := client.Forward(r.Context(), r)
+	dash := &Dashboard{RecentOrders: []*Order{}}
+	deps := []dependency{
+		{name: "user", timeout: h.cfg.UserTimeout, fetch: func(ctx context.Context) error {
+			resp, err := h.cfg.UserService.GetUser(ctx, &userv1.GetUserRequest{Id: s.UserID})
+			if err != nil {
+				return err
+			}
+			u := resp.GetUser()
+			dash.User = &User{ID: u.GetId(), Name: u.GetName(), Email: u.GetEmail()}
+			return nil
+		}},
+		{name: "orders", timeout: h.cfg.OrdersTimeout, fetch: func(ctx context.Context) error {
+			resp, err := h.cfg.OrderService.ListOrders(ctx, &orderv1.ListOrdersRequest{
+				UserId:   s.UserID,
+				PageSize: int32(h.cfg.RecentOrders),
+			})
+			if err != nil {
+				return err
+			}
+			for _, o := range resp.GetOrders() {
+				dash.RecentOrders = append(dash.RecentOrders, orderSummary(o))
+			}
+			return nil
+		}},
+	}
+	dash.Unavailable = h.fanOut(ctx, deps)
+
+	// A payload with every section missing is an outage, not a dashboard
+	code := http.StatusOK
+	if len(dash.Unavailable) == len(deps) {
+		code = http.StatusServiceUnavailable
+	}
+	writeJSON(w, code, dash)
+}
+
+// fanOut runs deps concurrently, each under its own timeout, and returns
+// the names of those that failed
+func (h *handler) fanOut(ctx context.Context, deps []dependency) []string {
+	failed := make([]bool, len(deps))
+	var wg sync.WaitGroup
+	for i, dep := range deps {
+		wg.Add(1)
+		go func(i int, dep dependency) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(ctx, dep.timeout)
+			defer cancel()
+
+			if err := dep.fetch(ctx); err != nil {
+				h.cfg.Logger.Warn("BFF dependency failed", log.String("dependency", dep.name), log.Error(err))
+				failed[i] = true
+			}
+		}(i, dep)
+	}
+	wg.Wait()
+
+	var names []string
+	for i, dep := range deps {
+		if failed[i] {
+			names = append(names, dep.name)
+		}
+	}
+	return names
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes an error in the body the gateway uses for every error
+func writeError(w http.ResponseWriter, httpStatus int, c codes.Code, msg string) {
+	errors.WriteHTTPStatus(w, httpStatus, status.New(c, msg))
+}
@@ -0,0 +1,126 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package bff
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	orderv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/order/v1"
+	userv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/user/v1"
+	"github.com/kevindiu/monorepo-go-example/internal/session"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// mockUserService returns a fixed user or error
+type mockUserService struct {
+	userv1.UserServiceClient
+	err error
+}
+
+func (m *mockUserService) GetUser(ctx context.Context, req *userv1.GetUserRequest, opts ...grpc.CallOption) (*userv1.GetUserResponse, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &userv1.GetUserResponse{User: &userv1.User{Id: req.GetId(), Name: "Alice"}}, nil
+}
+
+// mockOrderService returns one order, or blocks until the call times out
+type mockOrderService struct {
+	orderv1.OrderServiceClient
+	hang bool
+	req  *orderv1.ListOrdersRequest
+}
+
+func (m *mockOrderService) ListOrders(ctx context.Context, req *orderv1.ListOrdersRequest, opts ...grpc.CallOption) (*orderv1.ListOrdersResponse, error) {
+	m.req = req
+	if m.hang {
+		<-ctx.Done()
+		return nil, status.FromContextError(ctx.Err()).Err()
+	}
+	return &orderv1.ListOrdersResponse{Orders: []*orderv1.Order{
+		{Id: "o1", Status: orderv1.OrderStatus_ORDER_STATUS_SHIPPED, TotalAmount: 12.5, Currency: "USD"},
+	}}, nil
+}
+
+func TestDashboard(t *testing.T) {
+	tests := []struct {
+		name            string
+		session         bool
+		userErr         error
+		ordersHang      bool
+		wantCode        int
+		wantUnavailable []string
+		wantOrders      int
+	}{
+		{name: "composed", session: true, wantCode: http.StatusOK, wantOrders: 1},
+		{name: "orders time out", session: true, ordersHang: true, wantCode: http.StatusOK, wantUnavailable: []string{"orders"}},
+		{name: "user fails", session: true, userErr: status.Error(codes.Unavailable, "down"), wantCode: http.StatusOK, wantUnavailable: []string{"user"}, wantOrders: 1},
+		{name: "all fail", session: true, userErr: status.Error(codes.Unavailable, "down"), ordersHang: true, wantCode: http.StatusServiceUnavailable, wantUnavailable: []string{"user", "orders"}},
+		{name: "no session", wantCode: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orders := &mockOrderService{hang: tt.ordersHang}
+			h := NewHandler(Config{
+				UserService:   &mockUserService{err: tt.userErr},
+				OrderService:  orders,
+				OrdersTimeout: 20 * time.Millisecond,
+				RecentOrders:  3,
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/v1/me/dashboard", nil)
+			if tt.session {
+				req = req.WithContext(session.WithSession(req.Context(), &session.Session{UserID: "u1", TenantID: "default"}))
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d (%s)", rec.Code, tt.wantCode, rec.Body.String())
+			}
+			if !tt.session {
+				return
+			}
+
+			var dash Dashboard
+			if err := json.Unmarshal(rec.Body.Bytes(), &dash); err != nil {
+				t.Fatalf("failed to decode dashboard: %v", err)
+			}
+			if !reflect.DeepEqual(dash.Unavailable, tt.wantUnavailable) {
+				t.Errorf("unavailable = %v, want %v", dash.Unavailable, tt.wantUnavailable)
+			}
+			if len(dash.RecentOrders) != tt.wantOrders {
+				t.Errorf("got %d recent orders, want %d", len(dash.RecentOrders), tt.wantOrders)
+			}
+			if tt.userErr == nil && (dash.User == nil || dash.User.ID != "u1") {
+				t.Errorf("user = %+v, want session user u1", dash.User)
+			}
+			if orders.req.GetUserId() != "u1" || orders.req.GetPageSize() != 3 {
+				t.Errorf("ListOrders request = %v", orders.req)
+			}
+		})
+	}
+}
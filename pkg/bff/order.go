@@ -0,0 +1,232 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package bff
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	orderv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/order/v1"
+	recommendationv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/recommendation/v1"
+	shippingv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/shipping/v1"
+	userv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/user/v1"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/pkg/client"
+	"google.golang.org/grpc/codes"
+)
+
+// Limits of the product lookups of one order
+const (
+	// maxProductCalls bounds the concurrent product lookups
+	maxProductCalls = 4
+	// relatedProducts is the number of related products per product
+	relatedProducts = 3
+)
+
+// OrderItem is a line of an order
+type OrderItem struct {
+	ProductID   string  `json:"productId"`
+	ProductName string  `json:"productName"`
+	Quantity    int32   `json:"quantity"`
+	Price       float64 `json:"price"`
+}
+
+// Product is a product of an order with the products most often bought
+// with it
+type Product struct {
+	ID      string   `json:"id"`
+	Name    string   `json:"name"`
+	Related []string `json:"related"`
+}
+
+// Shipment is a shipment of an order
+type Shipment struct {
+	ID             string     `json:"id"`
+	Carrier        string     `json:"carrier"`
+	TrackingNumber string     `json:"trackingNumber"`
+	Status         string     `json:"status"`
+	DeliveredAt    *time.Time `json:"deliveredAt,omitempty"`
+}
+
+// OrderDetails is an order with its user, products and shipments, which
+// clients would otherwise fetch one call at a time. Sections whose
+// dependency failed are left empty and named in Unavailable.
+type OrderDetails struct {
+	Order
+	Items       []*OrderItem `json:"items"`
+	User        *User        `json:"user"`
+	Products    []*Product   `json:"products"`
+	Shipments   []*Shipment  `json:"shipments"`
+	Unavailable []string     `json:"unavailable,omitempty"`
+}
+
+// orderDetails serves GET /v1/orders/{id}/full. The order is fetched
+// first, then the sections that depend on it concurrently.
+func (h *handler) orderDetails(w http.ResponseWriter, r *http.Request) {
+	id, ok := orderDetailsID(r.URL.Path)
+	if !ok {
+		writeError(w, http.StatusNotFound, codes.NotFound, "not found")
+		return
+	}
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, codes.Unimplemented, "method not allowed")
+		return
+	}
+
+	ctx := client.Forward(r.Context(), r)
+	orderCtx, cancel := context.WithTimeout(ctx, h.cfg.OrdersTimeout)
+	resp, err := h.cfg.OrderService.GetOrder(orderCtx, &orderv1.GetOrderRequest{Id: id, Currency: r.URL.Query().Get("currency")})
+	cancel()
+	if err != nil {
+		errors.WriteHTTP(w, err)
+		return
+	}
+
+	o := resp.GetOrder()
+	details := &OrderDetails{
+		Order:     *orderSummary(o),
+		Items:     []*OrderItem{},
+		Products:  []*Product{},
+		Shipments: []*Shipment{},
+	}
+	byProduct := make(map[string]*Product)
+	for _, item := range o.GetItems() {
+		details.Items = append(details.Items, &OrderItem{
+			ProductID:   item.GetProductId(),
+			ProductName: item.GetProductName(),
+			Quantity:    item.GetQuantity(),
+			Price:       item.GetPrice(),
+		})
+		if _, ok := byProduct[item.GetProductId()]; !ok {
+			p := &Product{ID: item.GetProductId(), Name: item.GetProductName(), Related: []string{}}
+			byProduct[p.ID] = p
+			details.Products = append(details.Products, p)
+		}
+	}
+
+	deps := []dependency{
+		{name: "user", timeout: h.cfg.UserTimeout, fetch: func(ctx context.Context) error {
+			resp, err := h.cfg.UserService.GetUser(ctx, &userv1.GetUserRequest{Id: o.GetUserId()})
+			if err != nil {
+				return err
+			}
+			u := resp.GetUser()
+			details.User = &User{ID: u.GetId(), Name: u.GetName(), Email: u.GetEmail()}
+			return nil
+		}},
+	}
+	if h.cfg.RecommendationService != nil && len(details.Products) > 0 {
+		deps = append(deps, dependency{name: "products", timeout: h.cfg.ProductsTimeout, fetch: func(ctx context.Context) error {
+			return h.relatedProducts(ctx, details.Products)
+		}})
+	}
+	if h.cfg.ShippingService != nil {
+		deps = append(deps, dependency{name: "shipments", timeout: h.cfg.ShipmentsTimeout, fetch: func(ctx context.Context) error {
+			resp, err := h.cfg.ShippingService.ListShipments(ctx, &shippingv1.ListShipmentsRequest{OrderId: o.GetId()})
+			if err != nil {
+				return err
+			}
+			for _, s := range resp.GetShipments() {
+				details.Shipments = append(details.Shipments, shipment(s))
+			}
+			return nil
+		}})
+	}
+	details.Unavailable = h.fanOut(ctx, deps)
+
+	writeJSON(w, http.StatusOK, details)
+}
+
+// IsOrderDetails reports whether path is an order details route
+func IsOrderDetails(path string) bool {
+	_, ok := orderDetailsID(path)
+	return ok
+}
+
+// orderDetailsID returns the order ID of a /v1/orders/{id}/full path
+func orderDetailsID(path string) (string, bool) {
+	rest, ok := strings.CutPrefix(path, "/v1/orders/")
+	if !ok {
+		return "", false
+	}
+	id, ok := strings.CutSuffix(rest, "/full")
+	return id, ok && id != "" && !strings.Contains(id, "/")
+}
+
+// relatedProducts looks up the related products of each product, at most
+// maxProductCalls at a time, and returns the first error
+func (h *handler) relatedProducts(ctx context.Context, products []*Product) error {
+	sem := make(chan struct{}, maxProductCalls)
+	errs := make([]error, len(products))
+	var wg sync.WaitGroup
+	for i, p := range products {
+		wg.Add(1)
+		go func(i int, p *Product) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			resp, err := h.cfg.RecommendationService.GetRecommendations(ctx, &recommendationv1.GetRecommendationsRequest{
+				ProductId: p.ID,
+				Limit:     relatedProducts,
+			})
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			for _, rec := range resp.GetRecommendations() {
+				p.Related = append(p.Related, rec.GetProductId())
+			}
+		}(i, p)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// orderSummary converts an order to its summary
+func orderSummary(o *orderv1.Order) *Order {
+	return &Order{
+		ID:          o.GetId(),
+		Status:      strings.ToLower(strings.TrimPrefix(o.GetStatus().String(), "ORDER_STATUS_")),
+		TotalAmount: o.GetTotalAmount(),
+		Currency:    o.GetCurrency(),
+		CreatedAt:   o.GetCreatedAt().AsTime(),
+	}
+}
+
+func shipment(s *shippingv1.Shipment) *Shipment {
+	out := &Shipment{
+		ID:             s.GetId(),
+		Carrier:        s.GetCarrier(),
+		TrackingNumber: s.GetTrackingNumber(),
+		Status:         strings.ToLower(strings.TrimPrefix(s.GetStatus().String(), "SHIPMENT_STATUS_")),
+	}
+	if s.GetDeliveredAt() != nil {
+		delivered := s.GetDeliveredAt().AsTime()
+		out.DeliveredAt = &delivered
+	}
+	return out
+}
@@ -0,0 +1,186 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package bff
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	orderv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/order/v1"
+	recommendationv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/recommendation/v1"
+	shippingv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/shipping/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// mockOrderLookup returns an order with a repeated product, or an error
+type mockOrderLookup struct {
+	orderv1.OrderServiceClient
+	err error
+	req *orderv1.GetOrderRequest
+}
+
+func (m *mockOrderLookup) GetOrder(ctx context.Context, req *orderv1.GetOrderRequest, opts ...grpc.CallOption) (*orderv1.GetOrderResponse, error) {
+	m.req = req
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &orderv1.GetOrderResponse{Order: &orderv1.Order{
+		Id:     req.GetId(),
+		UserId: "u1",
+		Status: orderv1.OrderStatus_ORDER_STATUS_SHIPPED,
+		Items: []*orderv1.OrderItem{
+			{ProductId: "p1", ProductName: "Mug", Quantity: 1, Price: 8},
+			{ProductId: "p2", ProductName: "Tea", Quantity: 2, Price: 4},
+			{ProductId: "p1", ProductName: "Mug", Quantity: 1, Price: 8},
+		},
+	}}, nil
+}
+
+// mockRecommendationService relates every product to "r1" and counts calls
+type mockRecommendationService struct {
+	recommendationv1.RecommendationServiceClient
+	hang  bool
+	calls atomic.Int32
+}
+
+func (m *mockRecommendationService) GetRecommendations(ctx context.Context, req *recommendationv1.GetRecommendationsRequest, opts ...grpc.CallOption) (*recommendationv1.GetRecommendationsResponse, error) {
+	m.calls.Add(1)
+	if m.hang {
+		<-ctx.Done()
+		return nil, status.FromContextError(ctx.Err()).Err()
+	}
+	return &recommendationv1.GetRecommendationsResponse{Recommendations: []*recommendationv1.Recommendation{{ProductId: "r1"}}}, nil
+}
+
+// mockShippingService returns one shipment, or blocks until the call times out
+type mockShippingService struct {
+	shippingv1.ShippingServiceClient
+	hang bool
+}
+
+func (m *mockShippingService) ListShipments(ctx context.Context, req *shippingv1.ListShipmentsRequest, opts ...grpc.CallOption) (*shippingv1.ListShipmentsResponse, error) {
+	if m.hang {
+		<-ctx.Done()
+		return nil, status.FromContextError(ctx.Err()).Err()
+	}
+	return &shippingv1.ListShipmentsResponse{Shipments: []*shippingv1.Shipment{
+		{Id: "s1", OrderId: req.GetOrderId(), Carrier: "ups", Status: shippingv1.ShipmentStatus_SHIPMENT_STATUS_IN_TRANSIT},
+	}}, nil
+}
+
+func TestOrderDetails(t *testing.T) {
+	tests := []struct {
+		name            string
+		method          string
+		path            string
+		orderErr        error
+		productsHang    bool
+		shipmentsHang   bool
+		wantCode        int
+		wantUnavailable []string
+		wantShipments   int
+	}{
+		{name: "composed", path: "/v1/orders/o1/full?currency=EUR", wantCode: http.StatusOK, wantShipments: 1},
+		{name: "shipments time out", path: "/v1/orders/o1/full", shipmentsHang: true, wantCode: http.StatusOK, wantUnavailable: []string{"shipments"}},
+		{name: "products time out", path: "/v1/orders/o1/full", productsHang: true, wantCode: http.StatusOK, wantUnavailable: []string{"products"}, wantShipments: 1},
+		{name: "order not found", path: "/v1/orders/o1/full", orderErr: status.Error(codes.NotFound, "order not found"), wantCode: http.StatusNotFound},
+		{name: "unknown path", path: "/v1/orders/o1/items", wantCode: http.StatusNotFound},
+		{name: "method not allowed", method: http.MethodPost, path: "/v1/orders/o1/full", wantCode: http.StatusMethodNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orders := &mockOrderLookup{err: tt.orderErr}
+			recommendations := &mockRecommendationService{hang: tt.productsHang}
+			h := NewHandler(Config{
+				UserService:           &mockUserService{},
+				OrderService:          orders,
+				RecommendationService: recommendations,
+				ShippingService:       &mockShippingService{hang: tt.shipmentsHang},
+				ProductsTimeout:       20 * time.Millisecond,
+				ShipmentsTimeout:      20 * time.Millisecond,
+			})
+
+			method := tt.method
+			if method == "" {
+				method = http.MethodGet
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(method, tt.path, nil))
+
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d (%s)", rec.Code, tt.wantCode, rec.Body.String())
+			}
+			if rec.Code != http.StatusOK {
+				return
+			}
+
+			var details OrderDetails
+			if err := json.Unmarshal(rec.Body.Bytes(), &details); err != nil {
+				t.Fatalf("failed to decode order details: %v", err)
+			}
+			if !reflect.DeepEqual(details.Unavailable, tt.wantUnavailable) {
+				t.Errorf("unavailable = %v, want %v", details.Unavailable, tt.wantUnavailable)
+			}
+			if details.ID != "o1" || len(details.Items) != 3 {
+				t.Errorf("order = %+v, want o1 with 3 items", details)
+			}
+			if details.User == nil || details.User.ID != "u1" {
+				t.Errorf("user = %+v, want order user u1", details.User)
+			}
+			if len(details.Products) != 2 || recommendations.calls.Load() != 2 {
+				t.Errorf("got %d products from %d lookups, want 2 of each", len(details.Products), recommendations.calls.Load())
+			}
+			if !tt.productsHang && !reflect.DeepEqual(details.Products[0].Related, []string{"r1"}) {
+				t.Errorf("related = %v, want [r1]", details.Products[0].Related)
+			}
+			if len(details.Shipments) != tt.wantShipments {
+				t.Errorf("got %d shipments, want %d", len(details.Shipments), tt.wantShipments)
+			}
+			if tt.name == "composed" && orders.req.GetCurrency() != "EUR" {
+				t.Errorf("GetOrder currency = %q, want EUR", orders.req.GetCurrency())
+			}
+		})
+	}
+}
+
+func TestIsOrderDetails(t *testing.T) {
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{path: "/v1/orders/o1/full", want: true},
+		{path: "/v1/orders/o1", want: false},
+		{path: "/v1/orders//full", want: false},
+		{path: "/v1/orders/a/b/full", want: false},
+		{path: "/v1/users/o1/full", want: false},
+	}
+
+	for _, tt := range tests {
+		if got := IsOrderDetails(tt.path); got != tt.want {
+			t.Errorf("IsOrderDetails(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/kevindiu/monorepo-go-example/internal/audit"
+	"github.com/kevindiu/monorepo-go-example/internal/idempotency"
+	"github.com/kevindiu/monorepo-go-example/internal/middleware"
+	"github.com/kevindiu/monorepo-go-example/internal/session"
+	"github.com/kevindiu/monorepo-go-example/internal/tenant"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// authorizationKey is the metadata key carrying the bearer token
+const authorizationKey = "authorization"
+
+type tokenKey struct{}
+
+// WithToken returns a context whose calls authenticate with token instead
+// of the client's configured token
+func WithToken(ctx context.Context, token string) context.Context {
+	return context.WithValue(ctx, tokenKey{}, token)
+}
+
+// WithActor returns a context whose calls are made on behalf of actor. The
+// audit log records actor only when the client's token holds
+// auth.RoleService; see audit.Principal.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, audit.ActorMetadataKey, actor)
+}
+
+// WithTenant returns a context whose calls act on tenant id
+func WithTenant(ctx context.Context, id string) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, tenant.MetadataKey, id)
+}
+
+// Forward returns a context whose calls carry what the gateway forwards
+// from an HTTP request: its resolved tenant, session user, bearer token,
+// idempotency key and request ID
+func Forward(ctx context.Context, r *http.Request) context.Context {
+	md := metadata.Pairs(tenant.MetadataKey, tenant.ID(ctx))
+	if auth := r.Header.Get("Authorization"); auth != "" {
+		md.Set(authorizationKey, auth)
+	}
+	if key := r.Header.Get(idempotency.Header); key != "" {
+		md.Set(idempotency.MetadataKey, key)
+	}
+	if id := middleware.RequestIDFromContext(r.Context()); id != "" {
+		md.Set(middleware.RequestIDMetadataKey, id)
+	}
+	return metadata.NewOutgoingContext(ctx, metadata.Join(md, session.Metadata(ctx, r)))
+}
+
+// authInterceptor sends the call's token, falling back to token, as a
+// bearer token. Calls that already carry an authorization header, such as
+// those forwarded by the gateway, are left untouched.
+func authInterceptor(token string) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		callToken := token
+		if t, ok := ctx.Value(tokenKey{}).(string); ok {
+			callToken = t
+		}
+
+		md, _ := metadata.FromOutgoingContext(ctx)
+		if callToken != "" && len(md.Get(authorizationKey)) == 0 {
+			ctx = metadata.AppendToOutgoingContext(ctx, authorizationKey, "Bearer "+callToken)
+		}
+
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
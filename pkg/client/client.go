@@ -0,0 +1,156 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package client provides typed, connection-managed gRPC clients for the
+// user and order services.
+//
+//	users, err := client.NewUserClient(ctx, client.Config{
+//		Address: "user-service:9090",
+//		Token:   token,
+//		Retry:   client.DefaultRetryPolicy,
+//	})
+//	if err != nil {
+//		return err
+//	}
+//	defer users.Close()
+//
+//	resp, err := users.GetUser(ctx, &userv1.GetUserRequest{Id: id})
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"strings"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/config"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/internal/middleware"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/keepalive"
+)
+
+// Config holds client configuration
+type Config struct {
+	// Address is the host:port of the service, or uds:///path/to/socket
+	// for a service listening on a unix domain socket
+	Address string
+	// Token is sent as a bearer token on every call unless the call context
+	// carries its own token (see WithToken)
+	Token string
+	// TLS enables transport security; connections are plaintext when nil
+	TLS *tls.Config
+	// Timeout bounds calls whose context has no deadline, including retries.
+	// Zero leaves such calls unbounded.
+	Timeout time.Duration
+	// Retry controls retries of failed calls; the zero value disables them
+	Retry RetryPolicy
+	// Block makes the constructor wait until the connection is ready
+	Block bool
+	// Keepalive pings idle connections so that NAT and load balancers do
+	// not drop them; nil leaves the gRPC defaults (see KeepaliveFrom)
+	Keepalive *keepalive.ClientParameters
+	// DialOptions are appended to the options built from this config
+	DialOptions []grpc.DialOption
+}
+
+// Dial opens a connection configured with the client interceptors. Most
+// callers should use NewUserClient or NewOrderClient instead.
+func Dial(ctx context.Context, cfg Config) (*grpc.ClientConn, error) {
+	if cfg.Address == "" {
+		return nil, errors.WithCode(errors.New("address is required"), errors.CodeInvalidInput)
+	}
+
+	creds := insecure.NewCredentials()
+	if cfg.TLS != nil {
+		creds = credentials.NewTLS(cfg.TLS)
+	}
+
+	opts := []grpc.DialOption{
+		grpc.WithTransportCredentials(creds),
+		// Propagates the caller's trace and records a span per call
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+		grpc.WithChainUnaryInterceptor(
+			timeoutInterceptor(cfg.Timeout),
+			authInterceptor(cfg.Token),
+			middleware.UnaryClientRequestIDInterceptor(),
+			retryInterceptor(cfg.Retry),
+		),
+	}
+	if cfg.Block {
+		opts = append(opts, grpc.WithBlock())
+	}
+	if cfg.Keepalive != nil {
+		opts = append(opts, grpc.WithKeepaliveParams(*cfg.Keepalive))
+	}
+	opts = append(opts, cfg.DialOptions...)
+
+	conn, err := grpc.DialContext(ctx, Target(cfg.Address), opts...)
+	if err != nil {
+		return nil, errors.WithCode(errors.Wrapf(err, "failed to connect to %s", cfg.Address), errors.CodeUnavailable)
+	}
+
+	return conn, nil
+}
+
+// KeepaliveFrom converts keepalive configuration to client parameters. It
+// returns nil, which leaves the gRPC defaults, when cfg is nil or does not
+// enable pings.
+func KeepaliveFrom(cfg *config.Keepalive) *keepalive.ClientParameters {
+	if cfg == nil || cfg.Time <= 0 {
+		return nil
+	}
+	return &keepalive.ClientParameters{
+		Time:                cfg.Time,
+		Timeout:             cfg.Timeout,
+		PermitWithoutStream: cfg.PermitWithoutStream,
+	}
+}
+
+// UDSScheme prefixes the address of a service listening on a unix domain
+// socket
+const UDSScheme = "uds://"
+
+// Target converts an address to a gRPC dial target: uds:///path/to/socket
+// becomes unix:///path/to/socket, and other addresses are unchanged
+func Target(address string) string {
+	path, ok := strings.CutPrefix(address, UDSScheme)
+	switch {
+	case !ok:
+		return address
+	case strings.HasPrefix(path, "/"):
+		return "unix://" + path
+	default:
+		return "unix:" + path
+	}
+}
+
+// timeoutInterceptor applies a default deadline to calls without one
+func timeoutInterceptor(timeout time.Duration) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if timeout > 0 {
+			if _, ok := ctx.Deadline(); !ok {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, timeout)
+				defer cancel()
+			}
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
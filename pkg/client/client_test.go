@@ -0,0 +1,245 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package client
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/audit"
+	"github.com/kevindiu/monorepo-go-example/internal/config"
+	"github.com/kevindiu/monorepo-go-example/internal/tenant"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// fakeInvoker fails with the queued errors before succeeding
+type fakeInvoker struct {
+	errs  []error
+	calls int
+	ctx   context.Context
+}
+
+func (f *fakeInvoker) invoke(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+	f.ctx = ctx
+	f.calls++
+	if len(f.errs) > 0 {
+		err := f.errs[0]
+		f.errs = f.errs[1:]
+		return err
+	}
+	return nil
+}
+
+func TestRetryInterceptor(t *testing.T) {
+	unavailable := status.Error(codes.Unavailable, "connection refused")
+	notFound := status.Error(codes.NotFound, "order not found")
+	policy := RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond}
+
+	tests := []struct {
+		name      string
+		policy    RetryPolicy
+		errs      []error
+		wantCalls int
+		wantCode  codes.Code
+	}{
+		{name: "success", policy: policy, wantCalls: 1, wantCode: codes.OK},
+		{name: "recovers", policy: policy, errs: []error{unavailable, unavailable}, wantCalls: 3, wantCode: codes.OK},
+		{name: "gives up", policy: policy, errs: []error{unavailable, unavailable, unavailable}, wantCalls: 3, wantCode: codes.Unavailable},
+		{name: "not retryable", policy: policy, errs: []error{notFound}, wantCalls: 1, wantCode: codes.NotFound},
+		{name: "disabled", errs: []error{unavailable}, wantCalls: 1, wantCode: codes.Unavailable},
+		{
+			name:      "custom codes",
+			policy:    RetryPolicy{MaxAttempts: 2, Codes: []codes.Code{codes.NotFound}},
+			errs:      []error{notFound},
+			wantCalls: 2,
+			wantCode:  codes.OK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			invoker := &fakeInvoker{errs: tt.errs}
+			err := retryInterceptor(tt.policy)(context.Background(), "/order.v1.OrderService/GetOrder", nil, nil, nil, invoker.invoke)
+			if status.Code(err) != tt.wantCode {
+				t.Errorf("retryInterceptor() code = %v, want %v", status.Code(err), tt.wantCode)
+			}
+			if invoker.calls != tt.wantCalls {
+				t.Errorf("retryInterceptor() made %d calls, want %d", invoker.calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestRetryInterceptorStopsWhenContextDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	invoker := &fakeInvoker{errs: []error{status.Error(codes.Unavailable, "down")}}
+	policy := RetryPolicy{MaxAttempts: 5, InitialBackoff: time.Hour}
+	if err := retryInterceptor(policy)(ctx, "/m", nil, nil, nil, invoker.invoke); err == nil {
+		t.Fatal("retryInterceptor() should return the last error")
+	}
+	if invoker.calls != 1 {
+		t.Errorf("retryInterceptor() made %d calls after cancellation, want 1", invoker.calls)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	p := RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: 300 * time.Millisecond}
+
+	tests := []struct {
+		retry int
+		max   time.Duration
+	}{
+		{retry: 1, max: 100 * time.Millisecond},
+		{retry: 2, max: 200 * time.Millisecond},
+		{retry: 3, max: 300 * time.Millisecond},
+		{retry: 10, max: 300 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		got := p.backoff(tt.retry)
+		if got > tt.max || got < tt.max*4/5 {
+			t.Errorf("backoff(%d) = %v, want within 20%% below %v", tt.retry, got, tt.max)
+		}
+	}
+}
+
+func TestAuthInterceptor(t *testing.T) {
+	forwarded := metadata.AppendToOutgoingContext(context.Background(), authorizationKey, "Bearer forwarded")
+
+	tests := []struct {
+		name  string
+		token string
+		ctx   context.Context
+		want  string
+	}{
+		{name: "configured token", token: "t1", ctx: context.Background(), want: "Bearer t1"},
+		{name: "per-call token", token: "t1", ctx: WithToken(context.Background(), "t2"), want: "Bearer t2"},
+		{name: "forwarded header wins", token: "t1", ctx: forwarded, want: "Bearer forwarded"},
+		{name: "no token", ctx: context.Background()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			invoker := &fakeInvoker{}
+			if err := authInterceptor(tt.token)(tt.ctx, "/m", nil, nil, nil, invoker.invoke); err != nil {
+				t.Fatalf("authInterceptor() error = %v", err)
+			}
+
+			md, _ := metadata.FromOutgoingContext(invoker.ctx)
+			values := md.Get(authorizationKey)
+			if tt.want == "" {
+				if len(values) != 0 {
+					t.Errorf("authorization = %v, want none", values)
+				}
+				return
+			}
+			if len(values) != 1 || values[0] != tt.want {
+				t.Errorf("authorization = %v, want [%s]", values, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithActor(t *testing.T) {
+	md, _ := metadata.FromOutgoingContext(WithActor(context.Background(), "admin"))
+	if got := md.Get(audit.ActorMetadataKey); len(got) != 1 || got[0] != "admin" {
+		t.Errorf("actor metadata = %v, want [admin]", got)
+	}
+}
+
+func TestWithTenant(t *testing.T) {
+	md, _ := metadata.FromOutgoingContext(WithTenant(context.Background(), "acme"))
+	if got := md.Get(tenant.MetadataKey); len(got) != 1 || got[0] != "acme" {
+		t.Errorf("tenant metadata = %v, want [acme]", got)
+	}
+}
+
+func TestTimeoutInterceptor(t *testing.T) {
+	invoker := &fakeInvoker{}
+	if err := timeoutInterceptor(time.Minute)(context.Background(), "/m", nil, nil, nil, invoker.invoke); err != nil {
+		t.Fatalf("timeoutInterceptor() error = %v", err)
+	}
+	if _, ok := invoker.ctx.Deadline(); !ok {
+		t.Error("timeoutInterceptor() did not set a deadline")
+	}
+
+	deadline := time.Now().Add(time.Hour)
+	ctx, cancel := context.WithDeadline(context.Background(), deadline)
+	defer cancel()
+	if err := timeoutInterceptor(time.Minute)(ctx, "/m", nil, nil, nil, invoker.invoke); err != nil {
+		t.Fatalf("timeoutInterceptor() error = %v", err)
+	}
+	if got, _ := invoker.ctx.Deadline(); !got.Equal(deadline) {
+		t.Errorf("timeoutInterceptor() deadline = %v, want caller's %v", got, deadline)
+	}
+}
+
+func TestDialRequiresAddress(t *testing.T) {
+	if _, err := Dial(context.Background(), Config{}); err == nil {
+		t.Error("Dial() should require an address")
+	}
+}
+
+func TestTarget(t *testing.T) {
+	tests := []struct {
+		address string
+		want    string
+	}{
+		{address: "user-service:9090", want: "user-service:9090"},
+		{address: "uds:///var/run/user-service.sock", want: "unix:///var/run/user-service.sock"},
+		{address: "uds://user-service.sock", want: "unix:user-service.sock"},
+		{address: "unix:///var/run/user-service.sock", want: "unix:///var/run/user-service.sock"},
+	}
+
+	for _, tt := range tests {
+		if got := Target(tt.address); got != tt.want {
+			t.Errorf("Target(%q) = %q, want %q", tt.address, got, tt.want)
+		}
+	}
+}
+
+func TestKeepaliveFrom(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  *config.Keepalive
+		want *keepalive.ClientParameters
+	}{
+		{name: "nil", cfg: nil, want: nil},
+		{name: "pings disabled", cfg: &config.Keepalive{Timeout: time.Second}, want: nil},
+		{
+			name: "pings",
+			cfg:  &config.Keepalive{Time: 30 * time.Second, Timeout: 10 * time.Second, PermitWithoutStream: true, MinTime: time.Second},
+			want: &keepalive.ClientParameters{Time: 30 * time.Second, Timeout: 10 * time.Second, PermitWithoutStream: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := KeepaliveFrom(tt.cfg); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("KeepaliveFrom() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package client
+
+import (
+	"context"
+
+	orderv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/order/v1"
+	"google.golang.org/grpc"
+)
+
+// OrderClient is a client for the order service. It owns its connection and
+// must be closed when no longer needed.
+type OrderClient struct {
+	orderv1.OrderServiceClient
+	conn *grpc.ClientConn
+}
+
+// NewOrderClient connects to the order service
+func NewOrderClient(ctx context.Context, cfg Config) (*OrderClient, error) {
+	conn, err := Dial(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &OrderClient{
+		OrderServiceClient: orderv1.NewOrderServiceClient(conn),
+		conn:               conn,
+	}, nil
+}
+
+// Conn returns the underlying connection
+func (c *OrderClient) Conn() *grpc.ClientConn {
+	return c.conn
+}
+
+// Close closes the connection
+func (c *OrderClient) Close() error {
+	return c.conn.Close()
+}
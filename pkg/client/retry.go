@@ -0,0 +1,97 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package client
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RetryPolicy controls how failed calls are retried
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first;
+	// values below two disable retries
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry; later retries
+	// double it up to MaxBackoff. Delays are jittered by up to 20%.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	// Codes lists the retryable status codes; it defaults to Unavailable,
+	// which gRPC returns when the request never reached the server
+	Codes []codes.Code
+}
+
+// DefaultRetryPolicy retries unavailable services three times over about a second
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:    4,
+	InitialBackoff: 100 * time.Millisecond,
+	MaxBackoff:     time.Second,
+}
+
+// retryable reports whether err should be retried under p
+func (p RetryPolicy) retryable(err error) bool {
+	code := status.Code(err)
+	if len(p.Codes) == 0 {
+		return code == codes.Unavailable
+	}
+	for _, c := range p.Codes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+// backoff returns the delay before the given retry, starting at one
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	delay := p.InitialBackoff
+	for i := 1; i < retry && (p.MaxBackoff <= 0 || delay < p.MaxBackoff); i++ {
+		delay *= 2
+	}
+	if p.MaxBackoff > 0 && delay > p.MaxBackoff {
+		delay = p.MaxBackoff
+	}
+	if delay <= 0 {
+		return 0
+	}
+	jitter := time.Duration(rand.Int63n(int64(delay)/5 + 1))
+	return delay - jitter
+}
+
+// retryInterceptor retries calls that fail with a retryable code until the
+// attempts run out or the call context is done
+func retryInterceptor(p RetryPolicy) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		for attempt := 1; attempt < p.MaxAttempts && err != nil && p.retryable(err); attempt++ {
+			timer := time.NewTimer(p.backoff(attempt))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return err
+			case <-timer.C:
+			}
+			err = invoker(ctx, method, req, reply, cc, opts...)
+		}
+		return err
+	}
+}
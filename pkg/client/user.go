@@ -0,0 +1,54 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package client
+
+import (
+	"context"
+
+	userv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/user/v1"
+	"google.golang.org/grpc"
+)
+
+// UserClient is a client for the user service. It owns its connection and
+// must be closed when no longer needed.
+type UserClient struct {
+	userv1.UserServiceClient
+	conn *grpc.ClientConn
+}
+
+// NewUserClient connects to the user service
+func NewUserClient(ctx context.Context, cfg Config) (*UserClient, error) {
+	conn, err := Dial(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &UserClient{
+		UserServiceClient: userv1.NewUserServiceClient(conn),
+		conn:              conn,
+	}, nil
+}
+
+// Conn returns the underlying connection
+func (c *UserClient) Conn() *grpc.ClientConn {
+	return c.conn
+}
+
+// Close closes the connection
+func (c *UserClient) Close() error {
+	return c.conn.Close()
+}
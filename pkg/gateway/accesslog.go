@@ -0,0 +1,119 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package gateway
+
+import (
+	"math/rand"
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+
+	"github.com/kevindiu/monorepo-go-example/internal/log"
+	"github.com/kevindiu/monorepo-go-example/internal/middleware"
+)
+
+// responseRecorder captures the status code and size of a response
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int64
+}
+
+func (r *responseRecorder) WriteHeader(code int) {
+	if r.status == 0 {
+		r.status = code
+	}
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	n, err := r.ResponseWriter.Write(b)
+	r.bytes += int64(n)
+	return n, err
+}
+
+// Flush lets streaming handlers that check for http.Flusher flush through
+// the recorder
+func (r *responseRecorder) Flush() {
+	http.NewResponseController(r.ResponseWriter).Flush()
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer
+func (r *responseRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// loggingMiddleware passes handlers a logger scoped to the request and
+// logs one access line per request once it completes. Successful requests
+// are sampled by log.access.sample_rate; failed and slow ones always are
+// logged. A request whose handler panics is logged as a 500, which is how
+// recoveryMiddleware answers it.
+func (g *Gateway) loggingMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		logger := g.logger.With(log.String("request_id", middleware.RequestIDFromContext(r.Context())))
+		if span := trace.SpanContextFromContext(r.Context()); span.HasTraceID() {
+			logger = logger.With(log.String("trace_id", span.TraceID().String()))
+		}
+
+		start := time.Now()
+		rec := &responseRecorder{ResponseWriter: w}
+		completed := false
+		// Deferred, so the line is also logged while a panic unwinds
+		defer func() {
+			status := rec.status
+			if !completed {
+				status = http.StatusInternalServerError
+			} else if status == 0 {
+				status = http.StatusOK
+			}
+			g.logAccess(logger, r, status, rec.bytes, time.Since(start))
+		}()
+		next.ServeHTTP(rec, r.WithContext(log.WithContext(r.Context(), logger)))
+		completed = true
+	})
+}
+
+// logAccess logs the access line of a request, unless it is sampled out
+func (g *Gateway) logAccess(logger *log.Logger, r *http.Request, status int, bytes int64, duration time.Duration) {
+	slow := g.accessLog.SlowThreshold > 0 && duration >= g.accessLog.SlowThreshold
+	if status < http.StatusBadRequest && !slow && rand.Float64() >= g.accessLog.SampleRate {
+		return
+	}
+
+	fields := []zap.Field{
+		log.String("method", r.Method),
+		log.String("path", r.URL.Path),
+		log.Int("status", status),
+		log.Int64("bytes", bytes),
+		log.Duration("duration", duration),
+		log.String("remote_addr", r.RemoteAddr),
+		log.String("user_agent", r.UserAgent()),
+	}
+	switch {
+	case status >= http.StatusInternalServerError:
+		logger.Error("Request", fields...)
+	case status >= http.StatusBadRequest || slow:
+		logger.Warn("Request", fields...)
+	default:
+		logger.Info("Request", fields...)
+	}
+}
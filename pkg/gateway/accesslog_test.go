@@ -0,0 +1,104 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package gateway
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/kevindiu/monorepo-go-example/internal/config"
+	"github.com/kevindiu/monorepo-go-example/internal/log"
+)
+
+func TestLoggingMiddleware(t *testing.T) {
+	tests := []struct {
+		name      string
+		accessLog *config.AccessLog
+		status    int
+		body      string
+		delay     time.Duration
+		wantLevel zapcore.Level
+		wantLog   bool
+	}{
+		{name: "every request", status: http.StatusCreated, body: "created", wantLevel: zap.InfoLevel, wantLog: true},
+		{name: "no explicit status", body: "ok", wantLevel: zap.InfoLevel, wantLog: true},
+		{name: "sampled out", accessLog: &config.AccessLog{}, status: http.StatusOK},
+		{name: "client error", accessLog: &config.AccessLog{}, status: http.StatusNotFound,
+			body: "missing", wantLevel: zap.WarnLevel, wantLog: true},
+		{name: "server error", accessLog: &config.AccessLog{}, status: http.StatusBadGateway,
+			wantLevel: zap.ErrorLevel, wantLog: true},
+		{name: "slow request", accessLog: &config.AccessLog{SlowThreshold: time.Millisecond}, status: http.StatusOK,
+			delay: 5 * time.Millisecond, wantLevel: zap.WarnLevel, wantLog: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			core, logs := observer.New(zap.InfoLevel)
+			g, err := New(Config{AccessLog: tt.accessLog, Logger: &log.Logger{Logger: zap.New(core)}})
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+			h := g.loggingMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				time.Sleep(tt.delay)
+				if tt.status != 0 {
+					w.WriteHeader(tt.status)
+				}
+				w.Write([]byte(tt.body))
+			}))
+
+			h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/v1/orders", nil))
+
+			entries := logs.FilterMessage("Request").AllUntimed()
+			if !tt.wantLog {
+				if len(entries) != 0 {
+					t.Fatalf("logged %d access lines, want none", len(entries))
+				}
+				return
+			}
+			if len(entries) != 1 {
+				t.Fatalf("logged %d access lines, want 1", len(entries))
+			}
+			entry := entries[0]
+			if entry.Level != tt.wantLevel {
+				t.Errorf("level = %v, want %v", entry.Level, tt.wantLevel)
+			}
+			fields := entry.ContextMap()
+			wantStatus := tt.status
+			if wantStatus == 0 {
+				wantStatus = http.StatusOK
+			}
+			if got := fields["status"]; got != int64(wantStatus) {
+				t.Errorf("status = %v, want %d", got, wantStatus)
+			}
+			if got := fields["bytes"]; got != int64(len(tt.body)) {
+				t.Errorf("bytes = %v, want %d", got, len(tt.body))
+			}
+			if got := fields["path"]; got != "/v1/orders" {
+				t.Errorf("path = %v, want /v1/orders", got)
+			}
+			if _, ok := fields["duration"]; !ok {
+				t.Error("access line has no duration")
+			}
+		})
+	}
+}
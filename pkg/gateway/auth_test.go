@@ -0,0 +1,106 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package gateway
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/apikey"
+	"github.com/kevindiu/monorepo-go-example/internal/auth"
+	"github.com/kevindiu/monorepo-go-example/internal/config"
+	"github.com/kevindiu/monorepo-go-example/internal/session"
+)
+
+func TestAuthMiddleware(t *testing.T) {
+	authenticator, err := auth.New(&config.Auth{
+		Secret:      "0123456789abcdef0123456789abcdef",
+		TokenTTL:    time.Minute,
+		PublicPaths: []string{"/v1/public/*"},
+	})
+	if err != nil {
+		t.Fatalf("auth.New() error = %v", err)
+	}
+	g, err := New(Config{Auth: authenticator})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	var (
+		subject string
+		roles   []string
+	)
+	h := g.authMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		subject, roles = "", nil
+		if claims, ok := auth.FromContext(r.Context()); ok {
+			subject, roles = claims.Subject, claims.Roles
+		}
+	}))
+
+	token, _ := authenticator.Issue(auth.Claims{Subject: "user-1"})
+	sess := &session.Session{ID: "s1", UserID: "user-2", TenantID: "default", Roles: []string{"admin"}}
+	key := &apikey.Key{ID: "key-1", TenantID: "default", Scopes: []string{"service"}}
+
+	tests := []struct {
+		name        string
+		path        string
+		token       string
+		session     *session.Session
+		key         *apikey.Key
+		wantStatus  int
+		wantSubject string
+	}{
+		{name: "bearer token", path: "/v1/orders", token: token, wantStatus: http.StatusOK, wantSubject: "user-1"},
+		{name: "session", path: "/v1/orders", session: sess, wantStatus: http.StatusOK, wantSubject: "user-2"},
+		{name: "api key", path: "/v1/orders", key: key, wantStatus: http.StatusOK, wantSubject: "apikey:key-1"},
+		{name: "anonymous", path: "/v1/orders", wantStatus: http.StatusUnauthorized},
+		{name: "public path", path: "/v1/public/products", wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			subject = ""
+			r := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.token != "" {
+				r.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			if tt.session != nil {
+				r = r.WithContext(session.WithSession(r.Context(), tt.session))
+			}
+			if tt.key != nil {
+				r = r.WithContext(apikey.WithKey(r.Context(), tt.key))
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			if subject != tt.wantSubject {
+				t.Errorf("subject = %q, want %q", subject, tt.wantSubject)
+			}
+			if tt.key != nil && (len(roles) != 1 || roles[0] != "service") {
+				t.Errorf("roles = %v, want the key's scopes", roles)
+			}
+			if tt.session != nil && (len(roles) != 1 || roles[0] != "admin") {
+				t.Errorf("roles = %v, want the session user's roles", roles)
+			}
+		})
+	}
+}
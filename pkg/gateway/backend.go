@@ -0,0 +1,215 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	apikeyv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/apikey/v1"
+	auditv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/audit/v1"
+	authv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/auth/v1"
+	orderv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/order/v1"
+	orderv2 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/order/v2"
+	productv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/product/v1"
+	recommendationv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/recommendation/v1"
+	searchv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/search/v1"
+	shippingv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/shipping/v1"
+	userv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/user/v1"
+	userv2 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/user/v2"
+	webhookv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/webhook/v1"
+	"github.com/kevindiu/monorepo-go-example/internal/config"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/internal/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/backoff"
+	"google.golang.org/grpc/connectivity"
+)
+
+// backendServices are the services the gateway calls on its backends
+var backendServices = []grpc.ServiceDesc{
+	userv1.UserService_ServiceDesc,
+	userv2.UserService_ServiceDesc,
+	authv1.AuthService_ServiceDesc,
+	apikeyv1.APIKeyService_ServiceDesc,
+	orderv1.OrderService_ServiceDesc,
+	orderv2.OrderService_ServiceDesc,
+	recommendationv1.RecommendationService_ServiceDesc,
+	webhookv1.WebhookService_ServiceDesc,
+	searchv1.SearchService_ServiceDesc,
+	auditv1.AuditService_ServiceDesc,
+	shippingv1.ShippingService_ServiceDesc,
+	productv1.ProductService_ServiceDesc,
+}
+
+// defaultConnectTimeout bounds connection attempts when the config leaves
+// it unset, matching the gRPC default
+const defaultConnectTimeout = 20 * time.Second
+
+// idempotentPrefixes name the methods that are safe to retry, as they only
+// read
+var idempotentPrefixes = []string{"Get", "List", "Search", "Verify"}
+
+// serviceConfig is the JSON gRPC service config applied to backend
+// connections
+type serviceConfig struct {
+	MethodConfig []methodConfig `json:"methodConfig"`
+}
+
+type methodConfig struct {
+	Name        []methodName `json:"name"`
+	Timeout     string       `json:"timeout,omitempty"`
+	RetryPolicy *retryPolicy `json:"retryPolicy,omitempty"`
+}
+
+type methodName struct {
+	Service string `json:"service"`
+	Method  string `json:"method"`
+}
+
+type retryPolicy struct {
+	MaxAttempts          int      `json:"maxAttempts"`
+	InitialBackoff       string   `json:"initialBackoff"`
+	MaxBackoff           string   `json:"maxBackoff"`
+	BackoffMultiplier    float64  `json:"backoffMultiplier"`
+	RetryableStatusCodes []string `json:"retryableStatusCodes"`
+}
+
+// buildServiceConfig returns the service config for the given services.
+// Unary methods are bounded by their method timeout, then their service
+// timeout, then the call timeout; streaming methods only by the first two.
+// Idempotent unary methods are retried when the backend is unavailable.
+func buildServiceConfig(cfg *config.Gateway, services ...grpc.ServiceDesc) (string, error) {
+	known := make(map[string]bool)
+	var sc serviceConfig
+	add := func(service, method string, streaming bool) {
+		known[service] = true
+		known[service+"/"+method] = true
+
+		timeout, ok := cfg.MethodTimeouts[service+"/"+method]
+		if !ok {
+			timeout, ok = cfg.MethodTimeouts[service]
+		}
+		if !ok && !streaming {
+			timeout = cfg.CallTimeout
+		}
+
+		mc := methodConfig{
+			Name:    []methodName{{Service: service, Method: method}},
+			Timeout: durationString(timeout),
+		}
+		if !streaming && cfg.RetryAttempts >= 2 && idempotent(method) {
+			mc.RetryPolicy = &retryPolicy{
+				MaxAttempts:          cfg.RetryAttempts,
+				InitialBackoff:       durationString(cfg.RetryInitialBackoff),
+				MaxBackoff:           durationString(cfg.RetryMaxBackoff),
+				BackoffMultiplier:    2,
+				RetryableStatusCodes: []string{"UNAVAILABLE"},
+			}
+		}
+		if mc.Timeout != "" || mc.RetryPolicy != nil {
+			sc.MethodConfig = append(sc.MethodConfig, mc)
+		}
+	}
+	for _, svc := range services {
+		for _, m := range svc.Methods {
+			add(svc.ServiceName, m.MethodName, false)
+		}
+		for _, s := range svc.Streams {
+			add(svc.ServiceName, s.StreamName, true)
+		}
+	}
+
+	for name := range cfg.MethodTimeouts {
+		if !known[name] {
+			return "", errors.Newf("gateway.method_timeouts: unknown method or service %q", name)
+		}
+	}
+
+	b, err := json.Marshal(sc)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to encode service config")
+	}
+	return string(b), nil
+}
+
+// idempotent reports whether a method only reads
+func idempotent(method string) bool {
+	for _, prefix := range idempotentPrefixes {
+		if strings.HasPrefix(method, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// durationString formats d as a service config duration, or returns ""
+// for zero
+func durationString(d time.Duration) string {
+	if d <= 0 {
+		return ""
+	}
+	return strconv.FormatFloat(d.Seconds(), 'f', -1, 64) + "s"
+}
+
+// dialOptions returns the backoff and service config options of backend
+// connections
+func dialOptions(cfg *config.Gateway, serviceConfig string) []grpc.DialOption {
+	bc := backoff.DefaultConfig
+	if cfg.BackoffBaseDelay > 0 {
+		bc.BaseDelay = cfg.BackoffBaseDelay
+	}
+	if cfg.BackoffMultiplier > 0 {
+		bc.Multiplier = cfg.BackoffMultiplier
+	}
+	if cfg.BackoffMaxDelay > 0 {
+		bc.MaxDelay = cfg.BackoffMaxDelay
+	}
+	connectTimeout := cfg.ConnectTimeout
+	if connectTimeout <= 0 {
+		connectTimeout = defaultConnectTimeout
+	}
+	return []grpc.DialOption{
+		grpc.WithDefaultServiceConfig(serviceConfig),
+		grpc.WithConnectParams(grpc.ConnectParams{
+			Backoff:           bc,
+			MinConnectTimeout: connectTimeout,
+		}),
+	}
+}
+
+// watch logs the state changes of a backend connection until ctx is done.
+// A lost connection goes idle; it is reconnected at once rather than on
+// the next call.
+func (g *Gateway) watch(ctx context.Context, name string, conn *grpc.ClientConn) {
+	logger := g.logger.With(log.String("backend", name))
+	state := conn.GetState()
+	for conn.WaitForStateChange(ctx, state) {
+		state = conn.GetState()
+		switch state {
+		case connectivity.Idle:
+			conn.Connect()
+		case connectivity.Ready:
+			logger.Info("Backend connection ready")
+		case connectivity.TransientFailure:
+			logger.Warn("Backend connection failed, reconnecting")
+		}
+	}
+}
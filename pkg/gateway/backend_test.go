@@ -0,0 +1,162 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/config"
+	"github.com/kevindiu/monorepo-go-example/internal/health"
+	"github.com/kevindiu/monorepo-go-example/pkg/client"
+)
+
+func TestBuildServiceConfig(t *testing.T) {
+	cfg := &config.Gateway{
+		CallTimeout: 10 * time.Second,
+		MethodTimeouts: map[string]time.Duration{
+			"order.v1.OrderService/ExportOrders": 5 * time.Minute,
+			"user.v1.UserService":                2 * time.Second,
+			"user.v1.UserService/ListUsers":      1500 * time.Millisecond,
+		},
+		RetryAttempts:       3,
+		RetryInitialBackoff: 100 * time.Millisecond,
+		RetryMaxBackoff:     time.Second,
+	}
+	raw, err := buildServiceConfig(cfg, backendServices...)
+	if err != nil {
+		t.Fatalf("buildServiceConfig() error = %v", err)
+	}
+	var sc serviceConfig
+	if err := json.Unmarshal([]byte(raw), &sc); err != nil {
+		t.Fatalf("service config is not JSON: %v", err)
+	}
+	methods := make(map[string]methodConfig)
+	for _, mc := range sc.MethodConfig {
+		methods[mc.Name[0].Service+"/"+mc.Name[0].Method] = mc
+	}
+
+	tests := []struct {
+		method      string
+		wantTimeout string
+		wantRetry   bool
+	}{
+		{method: "order.v1.OrderService/GetOrder", wantTimeout: "10s", wantRetry: true},
+		{method: "order.v1.OrderService/CreateOrder", wantTimeout: "10s"},
+		{method: "order.v1.OrderService/ExportOrders", wantTimeout: "300s"},
+		{method: "order.v1.OrderService/WatchOrder"},
+		{method: "user.v1.UserService/GetUser", wantTimeout: "2s", wantRetry: true},
+		{method: "user.v1.UserService/ListUsers", wantTimeout: "1.5s", wantRetry: true},
+		{method: "user.v2.UserService/GetUser", wantTimeout: "10s", wantRetry: true},
+		{method: "search.v1.SearchService/Search", wantTimeout: "10s", wantRetry: true},
+		{method: "audit.v1.AuditService/VerifyChain", wantTimeout: "10s", wantRetry: true},
+		{method: "webhook.v1.WebhookService/Redeliver", wantTimeout: "10s"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			mc := methods[tt.method]
+			if mc.Timeout != tt.wantTimeout {
+				t.Errorf("timeout = %q, want %q", mc.Timeout, tt.wantTimeout)
+			}
+			if (mc.RetryPolicy != nil) != tt.wantRetry {
+				t.Errorf("retry policy = %+v, want retry %v", mc.RetryPolicy, tt.wantRetry)
+			}
+		})
+	}
+
+	// gRPC must accept the config
+	conn, err := client.Dial(context.Background(), client.Config{
+		Address:     "localhost:1",
+		DialOptions: dialOptions(cfg, raw),
+	})
+	if err != nil {
+		t.Fatalf("Dial() with service config error = %v", err)
+	}
+	conn.Close()
+}
+
+func TestBuildServiceConfigUnknownMethod(t *testing.T) {
+	for _, name := range []string{"order.v1.OrderService/Missing", "missing.v1.Service"} {
+		cfg := &config.Gateway{MethodTimeouts: map[string]time.Duration{name: time.Second}}
+		if _, err := buildServiceConfig(cfg, backendServices...); err == nil {
+			t.Errorf("buildServiceConfig() should reject %s", name)
+		}
+	}
+}
+
+func TestStartDoesNotWaitForBackends(t *testing.T) {
+	g, err := New(Config{
+		UserServiceEndpoint:  "localhost:1",
+		OrderServiceEndpoint: "localhost:1",
+		Backends:             &config.Gateway{ConnectTimeout: time.Second},
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := g.Start(ctx); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if len(g.Conns()) != 3 {
+		t.Errorf("Start() opened %d connections, want 3", len(g.Conns()))
+	}
+	if err := g.Close(); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+}
+
+func TestReadyReportsBackends(t *testing.T) {
+	g, err := New(Config{
+		UserServiceEndpoint:  "localhost:1",
+		OrderServiceEndpoint: "localhost:1",
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if err := g.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer g.Close()
+	h := g.Handler()
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, health.LivePath, nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("%s = %d, want %d", health.LivePath, w.Code, http.StatusOK)
+	}
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, health.ReadyPath, nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("%s = %d, want %d", health.ReadyPath, w.Code, http.StatusServiceUnavailable)
+	}
+	var report health.Report
+	if err := json.NewDecoder(w.Body).Decode(&report); err != nil {
+		t.Fatalf("failed to decode report: %v", err)
+	}
+	for _, name := range []string{"user-service", "order-service", "recommendation-service"} {
+		if report.Checks[name].Status != health.CheckFailing {
+			t.Errorf("check %s = %+v, want failing", name, report.Checks[name])
+		}
+	}
+}
@@ -0,0 +1,267 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package gateway
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"connectrpc.com/connect"
+	orderv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/order/v1"
+	"github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/order/v1/orderv1connect"
+	userv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/user/v1"
+	"github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/user/v1/userv1connect"
+	"github.com/kevindiu/monorepo-go-example/pkg/client"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// connectHeaders are the request headers Connect and gRPC-Web clients send
+const connectHeaders = "Connect-Protocol-Version, Connect-Timeout-Ms, X-Grpc-Web, X-User-Agent, Grpc-Timeout"
+
+// connectHandler serves the user and order services over the Connect
+// protocol, and gRPC-Web, at /<package>.<Service>/<Method>. Calls are
+// forwarded to the backends over the same connections as the REST routes,
+// so they pass the same backend interceptors.
+type connectHandler struct {
+	mux   *http.ServeMux
+	paths []string
+}
+
+func newConnectHandler(users userv1.UserServiceClient, orders orderv1.OrderServiceClient) *connectHandler {
+	h := &connectHandler{mux: http.NewServeMux()}
+	h.mount(userv1connect.NewUserServiceHandler(&connectUsers{client: users}))
+	h.mount(orderv1connect.NewOrderServiceHandler(&connectOrders{client: orders}))
+	return h
+}
+
+func (h *connectHandler) mount(path string, handler http.Handler) {
+	h.mux.Handle(path, handler)
+	h.paths = append(h.paths, path)
+}
+
+// serves reports whether path names a procedure of a mounted service
+func (h *connectHandler) serves(path string) bool {
+	for _, p := range h.paths {
+		if strings.HasPrefix(path, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// ServeHTTP forwards the request's tenant, session user and bearer token
+// with each call. Order watches outlive the server's write timeout, so it
+// is lifted for them.
+func (h *connectHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == orderv1connect.OrderServiceWatchOrderProcedure {
+		http.NewResponseController(w).SetWriteDeadline(time.Time{})
+	}
+	h.mux.ServeHTTP(w, r.WithContext(client.Forward(r.Context(), r)))
+}
+
+// unary forwards a Connect request to a gRPC client method
+func unary[Req, Res any](ctx context.Context, req *connect.Request[Req], call func(context.Context, *Req, ...grpc.CallOption) (*Res, error)) (*connect.Response[Res], error) {
+	res, err := call(ctx, req.Msg)
+	if err != nil {
+		return nil, connectError(err)
+	}
+	return connect.NewResponse(res), nil
+}
+
+// connectError converts a backend status to a Connect error; Connect codes
+// share the values of gRPC codes
+func connectError(err error) error {
+	st := status.Convert(err)
+	return connect.NewError(connect.Code(st.Code()), errors.New(st.Message()))
+}
+
+// connectUsers serves UserService over Connect
+type connectUsers struct {
+	client userv1.UserServiceClient
+}
+
+func (h *connectUsers) CreateUser(ctx context.Context, req *connect.Request[userv1.CreateUserRequest]) (*connect.Response[userv1.CreateUserResponse], error) {
+	return unary(ctx, req, h.client.CreateUser)
+}
+
+func (h *connectUsers) GetUser(ctx context.Context, req *connect.Request[userv1.GetUserRequest]) (*connect.Response[userv1.GetUserResponse], error) {
+	return unary(ctx, req, h.client.GetUser)
+}
+
+func (h *connectUsers) GetUserByEmail(ctx context.Context, req *connect.Request[userv1.GetUserByEmailRequest]) (*connect.Response[userv1.GetUserByEmailResponse], error) {
+	return unary(ctx, req, h.client.GetUserByEmail)
+}
+
+func (h *connectUsers) BatchGetUsers(ctx context.Context, req *connect.Request[userv1.BatchGetUsersRequest]) (*connect.Response[userv1.BatchGetUsersResponse], error) {
+	return unary(ctx, req, h.client.BatchGetUsers)
+}
+
+func (h *connectUsers) ListUsers(ctx context.Context, req *connect.Request[userv1.ListUsersRequest]) (*connect.Response[userv1.ListUsersResponse], error) {
+	return unary(ctx, req, h.client.ListUsers)
+}
+
+func (h *connectUsers) SearchUsers(ctx context.Context, req *connect.Request[userv1.SearchUsersRequest]) (*connect.Response[userv1.SearchUsersResponse], error) {
+	return unary(ctx, req, h.client.SearchUsers)
+}
+
+func (h *connectUsers) UpdateUser(ctx context.Context, req *connect.Request[userv1.UpdateUserRequest]) (*connect.Response[userv1.UpdateUserResponse], error) {
+	return unary(ctx, req, h.client.UpdateUser)
+}
+
+func (h *connectUsers) DeleteUser(ctx context.Context, req *connect.Request[userv1.DeleteUserRequest]) (*connect.Response[userv1.DeleteUserResponse], error) {
+	return unary(ctx, req, h.client.DeleteUser)
+}
+
+func (h *connectUsers) UndeleteUser(ctx context.Context, req *connect.Request[userv1.UndeleteUserRequest]) (*connect.Response[userv1.UndeleteUserResponse], error) {
+	return unary(ctx, req, h.client.UndeleteUser)
+}
+
+func (h *connectUsers) PurgeUser(ctx context.Context, req *connect.Request[userv1.PurgeUserRequest]) (*connect.Response[userv1.PurgeUserResponse], error) {
+	return unary(ctx, req, h.client.PurgeUser)
+}
+
+func (h *connectUsers) ChangePassword(ctx context.Context, req *connect.Request[userv1.ChangePasswordRequest]) (*connect.Response[userv1.ChangePasswordResponse], error) {
+	return unary(ctx, req, h.client.ChangePassword)
+}
+
+// ExportUsers relays the backend's batches to the client
+func (h *connectUsers) ExportUsers(ctx context.Context, req *connect.Request[userv1.ExportUsersRequest], stream *connect.ServerStream[userv1.ExportUsersResponse]) error {
+	upstream, err := h.client.ExportUsers(ctx, req.Msg)
+	if err != nil {
+		return connectError(err)
+	}
+
+	for {
+		res, err := upstream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return connectError(err)
+		}
+		if err := stream.Send(res); err != nil {
+			return err
+		}
+	}
+}
+
+// connectOrders serves OrderService over Connect
+type connectOrders struct {
+	client orderv1.OrderServiceClient
+}
+
+func (h *connectOrders) CreateOrder(ctx context.Context, req *connect.Request[orderv1.CreateOrderRequest]) (*connect.Response[orderv1.CreateOrderResponse], error) {
+	return unary(ctx, req, h.client.CreateOrder)
+}
+
+func (h *connectOrders) GetOrder(ctx context.Context, req *connect.Request[orderv1.GetOrderRequest]) (*connect.Response[orderv1.GetOrderResponse], error) {
+	return unary(ctx, req, h.client.GetOrder)
+}
+
+func (h *connectOrders) ListOrders(ctx context.Context, req *connect.Request[orderv1.ListOrdersRequest]) (*connect.Response[orderv1.ListOrdersResponse], error) {
+	return unary(ctx, req, h.client.ListOrders)
+}
+
+func (h *connectOrders) UpdateOrderStatus(ctx context.Context, req *connect.Request[orderv1.UpdateOrderStatusRequest]) (*connect.Response[orderv1.UpdateOrderStatusResponse], error) {
+	return unary(ctx, req, h.client.UpdateOrderStatus)
+}
+
+func (h *connectOrders) GetOrderHistory(ctx context.Context, req *connect.Request[orderv1.GetOrderHistoryRequest]) (*connect.Response[orderv1.GetOrderHistoryResponse], error) {
+	return unary(ctx, req, h.client.GetOrderHistory)
+}
+
+func (h *connectOrders) UpdateOrder(ctx context.Context, req *connect.Request[orderv1.UpdateOrderRequest]) (*connect.Response[orderv1.UpdateOrderResponse], error) {
+	return unary(ctx, req, h.client.UpdateOrder)
+}
+
+func (h *connectOrders) CancelOrder(ctx context.Context, req *connect.Request[orderv1.CancelOrderRequest]) (*connect.Response[orderv1.CancelOrderResponse], error) {
+	return unary(ctx, req, h.client.CancelOrder)
+}
+
+// ImportOrders relays the client's batches to the backend stream. A failed
+// send ends the stream; CloseAndRecv returns its status.
+func (h *connectOrders) ImportOrders(ctx context.Context, stream *connect.ClientStream[orderv1.ImportOrdersRequest]) (*connect.Response[orderv1.ImportOrdersResponse], error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	upstream, err := h.client.ImportOrders(ctx)
+	if err != nil {
+		return nil, connectError(err)
+	}
+
+	for stream.Receive() {
+		if err := upstream.Send(stream.Msg()); err != nil {
+			break
+		}
+	}
+	if err := stream.Err(); err != nil {
+		// Abandon the backend stream rather than import part of the upload
+		return nil, err
+	}
+
+	res, err := upstream.CloseAndRecv()
+	if err != nil {
+		return nil, connectError(err)
+	}
+	return connect.NewResponse(res), nil
+}
+
+// ExportOrders relays the backend's batches to the client
+func (h *connectOrders) ExportOrders(ctx context.Context, req *connect.Request[orderv1.ExportOrdersRequest], stream *connect.ServerStream[orderv1.ExportOrdersResponse]) error {
+	upstream, err := h.client.ExportOrders(ctx, req.Msg)
+	if err != nil {
+		return connectError(err)
+	}
+
+	for {
+		res, err := upstream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return connectError(err)
+		}
+		if err := stream.Send(res); err != nil {
+			return err
+		}
+	}
+}
+
+// WatchOrder relays the backend's order updates to the client
+func (h *connectOrders) WatchOrder(ctx context.Context, req *connect.Request[orderv1.WatchOrderRequest], stream *connect.ServerStream[orderv1.WatchOrderResponse]) error {
+	upstream, err := h.client.WatchOrder(ctx, req.Msg)
+	if err != nil {
+		return connectError(err)
+	}
+
+	for {
+		res, err := upstream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return connectError(err)
+		}
+		if err := stream.Send(res); err != nil {
+			return err
+		}
+	}
+}
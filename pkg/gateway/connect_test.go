@@ -0,0 +1,180 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package gateway
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"connectrpc.com/connect"
+	orderv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/order/v1"
+	"github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/order/v1/orderv1connect"
+	"github.com/kevindiu/monorepo-go-example/internal/idempotency"
+	"github.com/kevindiu/monorepo-go-example/internal/middleware"
+	"github.com/kevindiu/monorepo-go-example/internal/tenant"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// mockConnectOrders serves one order and records the forwarded metadata
+type mockConnectOrders struct {
+	orderv1.OrderServiceClient
+	md metadata.MD
+}
+
+func (m *mockConnectOrders) GetOrder(ctx context.Context, req *orderv1.GetOrderRequest, opts ...grpc.CallOption) (*orderv1.GetOrderResponse, error) {
+	m.md, _ = metadata.FromOutgoingContext(ctx)
+	if req.GetId() != "o1" {
+		return nil, status.Error(codes.NotFound, "order not found")
+	}
+	return &orderv1.GetOrderResponse{Order: &orderv1.Order{Id: "o1", TotalAmount: 12}}, nil
+}
+
+// mockWatchClient replays order updates, then ends the stream
+type mockWatchClient struct {
+	grpc.ClientStream
+	updates []*orderv1.WatchOrderResponse
+}
+
+func (m *mockWatchClient) Recv() (*orderv1.WatchOrderResponse, error) {
+	if len(m.updates) == 0 {
+		return nil, io.EOF
+	}
+	update := m.updates[0]
+	m.updates = m.updates[1:]
+	return update, nil
+}
+
+func (m *mockConnectOrders) WatchOrder(ctx context.Context, req *orderv1.WatchOrderRequest, opts ...grpc.CallOption) (orderv1.OrderService_WatchOrderClient, error) {
+	if req.GetId() != "o1" {
+		return nil, status.Error(codes.NotFound, "order not found")
+	}
+	return &mockWatchClient{updates: []*orderv1.WatchOrderResponse{
+		{Order: &orderv1.Order{Id: "o1", Status: orderv1.OrderStatus_ORDER_STATUS_SHIPPED}},
+		{Order: &orderv1.Order{Id: "o1", Status: orderv1.OrderStatus_ORDER_STATUS_DELIVERED}},
+	}}, nil
+}
+
+func TestConnectOrders(t *testing.T) {
+	orders := &mockConnectOrders{}
+	h := newConnectHandler(nil, orders)
+	srv := httptest.NewServer(middleware.RequestID(tenant.Middleware(tenant.NewResolver(nil), h)))
+	defer srv.Close()
+
+	tests := []struct {
+		name     string
+		opts     []connect.ClientOption
+		id       string
+		wantCode connect.Code
+	}{
+		{name: "connect json", opts: []connect.ClientOption{connect.WithProtoJSON()}, id: "o1"},
+		{name: "connect proto", id: "o1"},
+		{name: "grpc-web", opts: []connect.ClientOption{connect.WithGRPCWeb()}, id: "o1"},
+		{name: "backend error", id: "missing", wantCode: connect.CodeNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := orderv1connect.NewOrderServiceClient(srv.Client(), srv.URL, tt.opts...)
+			req := connect.NewRequest(&orderv1.GetOrderRequest{Id: tt.id})
+			req.Header().Set("Authorization", "Bearer token")
+			req.Header().Set(idempotency.Header, "retry-1")
+			req.Header().Set(middleware.RequestIDHeader, "req-1")
+
+			resp, err := c.GetOrder(context.Background(), req)
+			if tt.wantCode != 0 {
+				if connect.CodeOf(err) != tt.wantCode {
+					t.Fatalf("GetOrder() error = %v, want code %v", err, tt.wantCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetOrder() error = %v", err)
+			}
+			if resp.Msg.GetOrder().GetTotalAmount() != 12 {
+				t.Errorf("GetOrder() order = %v", resp.Msg.GetOrder())
+			}
+			if got := orders.md.Get(tenant.MetadataKey); len(got) != 1 || got[0] != tenant.DefaultID {
+				t.Errorf("tenant metadata = %v, want [%s]", got, tenant.DefaultID)
+			}
+			if got := orders.md.Get("authorization"); len(got) != 1 || got[0] != "Bearer token" {
+				t.Errorf("authorization metadata = %v, want [Bearer token]", got)
+			}
+			if got := orders.md.Get(idempotency.MetadataKey); len(got) != 1 || got[0] != "retry-1" {
+				t.Errorf("idempotency metadata = %v, want [retry-1]", got)
+			}
+			if got := orders.md.Get(middleware.RequestIDMetadataKey); len(got) != 1 || got[0] != "req-1" {
+				t.Errorf("request ID metadata = %v, want [req-1]", got)
+			}
+		})
+	}
+}
+
+func TestConnectWatchOrder(t *testing.T) {
+	h := newConnectHandler(nil, &mockConnectOrders{})
+	srv := httptest.NewServer(tenant.Middleware(tenant.NewResolver(nil), h))
+	defer srv.Close()
+	c := orderv1connect.NewOrderServiceClient(srv.Client(), srv.URL)
+
+	stream, err := c.WatchOrder(context.Background(), connect.NewRequest(&orderv1.WatchOrderRequest{Id: "o1"}))
+	if err != nil {
+		t.Fatalf("WatchOrder() error = %v", err)
+	}
+	var statuses []orderv1.OrderStatus
+	for stream.Receive() {
+		statuses = append(statuses, stream.Msg().GetOrder().GetStatus())
+	}
+	if err := stream.Err(); err != nil {
+		t.Fatalf("WatchOrder() stream error = %v", err)
+	}
+	if len(statuses) != 2 || statuses[1] != orderv1.OrderStatus_ORDER_STATUS_DELIVERED {
+		t.Errorf("WatchOrder() statuses = %v, want shipped then delivered", statuses)
+	}
+
+	stream, err = c.WatchOrder(context.Background(), connect.NewRequest(&orderv1.WatchOrderRequest{Id: "missing"}))
+	if err != nil {
+		t.Fatalf("WatchOrder(missing) error = %v", err)
+	}
+	for stream.Receive() {
+	}
+	if connect.CodeOf(stream.Err()) != connect.CodeNotFound {
+		t.Errorf("WatchOrder(missing) error = %v, want not found", stream.Err())
+	}
+}
+
+func TestConnectServes(t *testing.T) {
+	h := newConnectHandler(nil, nil)
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{path: orderv1connect.OrderServiceGetOrderProcedure, want: true},
+		{path: "/user.v1.UserService/GetUser", want: true},
+		{path: "/v1/orders"},
+		{path: "/webhook.v1.WebhookService/ListEndpoints"},
+	}
+
+	for _, tt := range tests {
+		if got := h.serves(tt.path); got != tt.want {
+			t.Errorf("serves(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
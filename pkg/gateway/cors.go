@@ -0,0 +1,167 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package gateway
+
+import (
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/kevindiu/monorepo-go-example/internal/apikey"
+	"github.com/kevindiu/monorepo-go-example/internal/config"
+	"github.com/kevindiu/monorepo-go-example/internal/idempotency"
+	"github.com/kevindiu/monorepo-go-example/internal/middleware"
+	"github.com/kevindiu/monorepo-go-example/internal/tenant"
+	"google.golang.org/grpc/codes"
+)
+
+// corsExposedHeaders are the response headers browsers let scripts read
+const corsExposedHeaders = middleware.RequestIDHeader + ", traceparent, Grpc-Status, Grpc-Message, Deprecation, Sunset, Link, Retry-After, X-RateLimit-Limit, X-RateLimit-Remaining"
+
+// gatewayHeaders are the request headers the gateway reads itself, so they
+// are always allowed cross-origin
+var gatewayHeaders = append([]string{
+	"Content-Type", "Authorization", apikey.Header, tenant.Header, idempotency.Header, middleware.RequestIDHeader,
+}, strings.Split(connectHeaders, ", ")...)
+
+// corsPolicy is a CORS configuration compiled for matching requests
+type corsPolicy struct {
+	// anyOrigin allows every origin
+	anyOrigin bool
+	// origins are the allowed origins and origin patterns
+	origins     []string
+	methods     map[string]bool
+	headers     map[string]bool
+	credentials bool
+	// allowMethods, allowHeaders and maxAge are the preflight response
+	// header values
+	allowMethods string
+	allowHeaders string
+	maxAge       string
+}
+
+// newCORSPolicy compiles cfg; nil allows any origin with the default
+// methods
+func newCORSPolicy(cfg *config.CORS) *corsPolicy {
+	if cfg == nil {
+		cfg = &config.CORS{
+			AllowedOrigins: []string{"*"},
+			AllowedMethods: []string{"GET", "POST", "PUT", "PATCH", "DELETE"},
+		}
+	}
+	p := &corsPolicy{
+		methods:     make(map[string]bool),
+		headers:     make(map[string]bool),
+		credentials: cfg.AllowCredentials,
+	}
+	for _, origin := range cfg.AllowedOrigins {
+		if origin == "*" {
+			p.anyOrigin = true
+			continue
+		}
+		p.origins = append(p.origins, origin)
+	}
+	for _, method := range cfg.AllowedMethods {
+		p.methods[method] = true
+	}
+	headers := append(append([]string{}, gatewayHeaders...), cfg.AllowedHeaders...)
+	for _, header := range headers {
+		p.headers[strings.ToLower(header)] = true
+	}
+	p.allowMethods = strings.Join(cfg.AllowedMethods, ", ")
+	p.allowHeaders = strings.Join(headers, ", ")
+	if cfg.MaxAge > 0 {
+		p.maxAge = strconv.Itoa(int(cfg.MaxAge.Seconds()))
+	}
+	return p
+}
+
+// allowOrigin returns the Access-Control-Allow-Origin value for origin, or
+// an empty string when it is not allowed. "*" never admits credentialed
+// requests; those need the origin to be listed.
+func (p *corsPolicy) allowOrigin(origin string) string {
+	if origin == "" {
+		return ""
+	}
+	if p.anyOrigin && !p.credentials {
+		return "*"
+	}
+	for _, pattern := range p.origins {
+		if ok, _ := path.Match(pattern, origin); ok {
+			return origin
+		}
+	}
+	return ""
+}
+
+// allowRequestHeaders reports whether every header in the comma-separated
+// Access-Control-Request-Headers value is allowed
+func (p *corsPolicy) allowRequestHeaders(requested string) bool {
+	for _, header := range strings.Split(requested, ",") {
+		header = strings.ToLower(strings.TrimSpace(header))
+		if header != "" && !p.headers[header] {
+			return false
+		}
+	}
+	return true
+}
+
+// corsMiddleware answers preflight requests and adds the CORS headers to
+// responses for allowed origins. Preflights from a disallowed origin, or
+// asking for a disallowed method or header, are rejected with 403.
+func (g *Gateway) corsMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p := g.cors.Load().(*corsPolicy)
+		origin := r.Header.Get("Origin")
+		allowed := p.allowOrigin(origin)
+		if allowed != "*" {
+			w.Header().Add("Vary", "Origin")
+		}
+
+		requestMethod := r.Header.Get("Access-Control-Request-Method")
+		if r.Method == http.MethodOptions && origin != "" && requestMethod != "" {
+			w.Header().Add("Vary", "Access-Control-Request-Method")
+			w.Header().Add("Vary", "Access-Control-Request-Headers")
+			if allowed == "" || !p.methods[requestMethod] ||
+				!p.allowRequestHeaders(r.Header.Get("Access-Control-Request-Headers")) {
+				writeError(w, codes.PermissionDenied, "CORS preflight not allowed")
+				return
+			}
+			w.Header().Set("Access-Control-Allow-Origin", allowed)
+			w.Header().Set("Access-Control-Allow-Methods", p.allowMethods)
+			w.Header().Set("Access-Control-Allow-Headers", p.allowHeaders)
+			if p.maxAge != "" {
+				w.Header().Set("Access-Control-Max-Age", p.maxAge)
+			}
+			if p.credentials {
+				w.Header().Set("Access-Control-Allow-Credentials", "true")
+			}
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		if allowed != "" {
+			w.Header().Set("Access-Control-Allow-Origin", allowed)
+			w.Header().Set("Access-Control-Expose-Headers", corsExposedHeaders)
+			if p.credentials {
+				w.Header().Set("Access-Control-Allow-Credentials", "true")
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,164 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package gateway
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/config"
+	"github.com/kevindiu/monorepo-go-example/internal/tenant"
+)
+
+func TestCORSMiddleware(t *testing.T) {
+	g, err := New(Config{CORS: &config.CORS{
+		AllowedOrigins:   []string{"https://app.example.com", "https://*.example.org"},
+		AllowedMethods:   []string{"GET", "POST"},
+		AllowedHeaders:   []string{"X-Client-Version"},
+		MaxAge:           10 * time.Minute,
+		AllowCredentials: true,
+	}})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	h := g.corsMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}))
+
+	tests := []struct {
+		name        string
+		method      string
+		origin      string
+		reqMethod   string
+		reqHeaders  string
+		wantStatus  int
+		wantAllowed string
+	}{
+		{name: "allowed origin", method: http.MethodGet, origin: "https://app.example.com",
+			wantStatus: http.StatusTeapot, wantAllowed: "https://app.example.com"},
+		{name: "origin pattern", method: http.MethodPost, origin: "https://shop.example.org",
+			wantStatus: http.StatusTeapot, wantAllowed: "https://shop.example.org"},
+		{name: "pattern does not span hosts", method: http.MethodGet, origin: "https://evil.com/.example.org",
+			wantStatus: http.StatusTeapot},
+		{name: "disallowed origin", method: http.MethodGet, origin: "https://evil.com", wantStatus: http.StatusTeapot},
+		{name: "same origin", method: http.MethodGet, wantStatus: http.StatusTeapot},
+		{name: "preflight", method: http.MethodOptions, origin: "https://app.example.com", reqMethod: "POST",
+			reqHeaders: "content-type, " + tenant.Header + ", x-client-version",
+			wantStatus: http.StatusNoContent, wantAllowed: "https://app.example.com"},
+		{name: "preflight from disallowed origin", method: http.MethodOptions, origin: "https://evil.com",
+			reqMethod: "POST", wantStatus: http.StatusForbidden},
+		{name: "preflight for disallowed method", method: http.MethodOptions, origin: "https://app.example.com",
+			reqMethod: "DELETE", wantStatus: http.StatusForbidden},
+		{name: "preflight for disallowed header", method: http.MethodOptions, origin: "https://app.example.com",
+			reqMethod: "GET", reqHeaders: "X-Secret", wantStatus: http.StatusForbidden},
+		{name: "options without preflight", method: http.MethodOptions, origin: "https://app.example.com",
+			wantStatus: http.StatusTeapot, wantAllowed: "https://app.example.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(tt.method, "/v1/orders", nil)
+			if tt.origin != "" {
+				r.Header.Set("Origin", tt.origin)
+			}
+			if tt.reqMethod != "" {
+				r.Header.Set("Access-Control-Request-Method", tt.reqMethod)
+			}
+			if tt.reqHeaders != "" {
+				r.Header.Set("Access-Control-Request-Headers", tt.reqHeaders)
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			if got := w.Header().Get("Access-Control-Allow-Origin"); got != tt.wantAllowed {
+				t.Errorf("Access-Control-Allow-Origin = %q, want %q", got, tt.wantAllowed)
+			}
+			wantCredentials := ""
+			if tt.wantAllowed != "" {
+				wantCredentials = "true"
+			}
+			if got := w.Header().Get("Access-Control-Allow-Credentials"); got != wantCredentials {
+				t.Errorf("Access-Control-Allow-Credentials = %q, want %q", got, wantCredentials)
+			}
+			if got := w.Header().Get("Vary"); got != "Origin" && tt.reqMethod == "" {
+				t.Errorf("Vary = %q, want Origin", got)
+			}
+			if tt.wantStatus == http.StatusNoContent {
+				if got := w.Header().Get("Access-Control-Allow-Methods"); got != "GET, POST" {
+					t.Errorf("Access-Control-Allow-Methods = %q, want %q", got, "GET, POST")
+				}
+				if got := w.Header().Get("Access-Control-Max-Age"); got != "600" {
+					t.Errorf("Access-Control-Max-Age = %q, want %q", got, "600")
+				}
+			}
+		})
+	}
+}
+
+func TestCORSAnyOriginWithCredentials(t *testing.T) {
+	// Config validation rejects this; the policy must not reflect every
+	// origin with credentials if it gets through anyway
+	p := newCORSPolicy(&config.CORS{
+		AllowedOrigins:   []string{"*", "https://app.example.com"},
+		AllowedMethods:   []string{"GET"},
+		AllowCredentials: true,
+	})
+
+	if got := p.allowOrigin("https://evil.com"); got != "" {
+		t.Errorf("allowOrigin() = %q for an unlisted origin, want none", got)
+	}
+	if got := p.allowOrigin("https://app.example.com"); got != "https://app.example.com" {
+		t.Errorf("allowOrigin() = %q for a listed origin, want it reflected", got)
+	}
+}
+
+func TestCORSReload(t *testing.T) {
+	g, err := New(Config{})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	h := g.corsMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	get := func() string {
+		r := httptest.NewRequest(http.MethodGet, "/v1/orders", nil)
+		r.Header.Set("Origin", "https://app.example.com")
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w.Header().Get("Access-Control-Allow-Origin")
+	}
+
+	if got := get(); got != "*" {
+		t.Fatalf("Access-Control-Allow-Origin = %q, want %q", got, "*")
+	}
+
+	apply, err := g.Reload(&config.Config{}, &config.Config{CORS: &config.CORS{
+		AllowedOrigins: []string{"https://admin.example.com"},
+		AllowedMethods: []string{"GET"},
+	}})
+	if err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	apply()
+
+	if got := get(); got != "" {
+		t.Errorf("Access-Control-Allow-Origin after reload = %q, want none", got)
+	}
+}
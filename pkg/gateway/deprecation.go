@@ -0,0 +1,98 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package gateway
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/config"
+)
+
+// successors maps the deprecated v1 REST resources to their v2 successors
+var successors = []struct {
+	prefix    string
+	successor string
+}{
+	{prefix: "/v1/orders", successor: "/v2/orders"},
+	{prefix: "/v1/users", successor: "/v2/users"},
+}
+
+// deprecation holds the header values announcing the retirement of v1
+type deprecation struct {
+	// since is the Deprecation header value (RFC 9745), empty when unset
+	since string
+	// sunset is the Sunset header value (RFC 8594), empty when unset
+	sunset string
+}
+
+// newDeprecation parses the deprecation dates; nil sends only the
+// successor-version links
+func newDeprecation(cfg *config.Deprecation) (*deprecation, error) {
+	d := &deprecation{}
+	if cfg == nil {
+		return d, nil
+	}
+	if cfg.Since != "" {
+		since, err := time.Parse(time.RFC3339, cfg.Since)
+		if err != nil {
+			return nil, fmt.Errorf("invalid deprecation date: %w", err)
+		}
+		d.since = "@" + strconv.FormatInt(since.Unix(), 10)
+	}
+	if cfg.Sunset != "" {
+		sunset, err := time.Parse(time.RFC3339, cfg.Sunset)
+		if err != nil {
+			return nil, fmt.Errorf("invalid sunset date: %w", err)
+		}
+		d.sunset = sunset.UTC().Format(http.TimeFormat)
+	}
+	return d, nil
+}
+
+// successor returns the v2 resource replacing the v1 resource at path, or
+// an empty string when path has none
+func successor(path string) string {
+	for _, s := range successors {
+		rest, ok := strings.CutPrefix(path, s.prefix)
+		if ok && (rest == "" || rest[0] == '/' || rest[0] == ':') {
+			return s.successor
+		}
+	}
+	return ""
+}
+
+// deprecationMiddleware marks responses of the v1 resources that have a v2
+// successor as deprecated, so clients can find and migrate to v2 before
+// the sunset
+func (g *Gateway) deprecationMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s := successor(r.URL.Path); s != "" {
+			if g.deprecation.since != "" {
+				w.Header().Set("Deprecation", g.deprecation.since)
+			}
+			if g.deprecation.sunset != "" {
+				w.Header().Set("Sunset", g.deprecation.sunset)
+			}
+			w.Header().Add("Link", "<"+s+`>; rel="successor-version"`)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,73 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package gateway
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kevindiu/monorepo-go-example/internal/config"
+)
+
+func TestDeprecationMiddleware(t *testing.T) {
+	g, err := New(Config{Deprecation: &config.Deprecation{
+		Since:  "2026-07-01T00:00:00Z",
+		Sunset: "2027-01-01T00:00:00Z",
+	}})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	h := g.deprecationMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	tests := []struct {
+		path     string
+		wantLink string
+	}{
+		{path: "/v1/orders", wantLink: `</v2/orders>; rel="successor-version"`},
+		{path: "/v1/orders/o1", wantLink: `</v2/orders>; rel="successor-version"`},
+		{path: "/v1/users/u1", wantLink: `</v2/users>; rel="successor-version"`},
+		{path: "/v2/orders/o1"},
+		{path: "/v1/webhooks"},
+		{path: "/v1/orders-archive"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if got := w.Header().Get("Link"); got != tt.wantLink {
+				t.Errorf("Link = %q, want %q", got, tt.wantLink)
+			}
+			wantSince, wantSunset := "", ""
+			if tt.wantLink != "" {
+				wantSince, wantSunset = "@1782864000", "Fri, 01 Jan 2027 00:00:00 GMT"
+			}
+			if got := w.Header().Get("Deprecation"); got != wantSince {
+				t.Errorf("Deprecation = %q, want %q", got, wantSince)
+			}
+			if got := w.Header().Get("Sunset"); got != wantSunset {
+				t.Errorf("Sunset = %q, want %q", got, wantSunset)
+			}
+		})
+	}
+
+	if _, err := New(Config{Deprecation: &config.Deprecation{Sunset: "soon"}}); err == nil {
+		t.Error("New() should reject an invalid sunset date")
+	}
+}
@@ -0,0 +1,73 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package gateway
+
+import (
+	"context"
+	stderrors "errors"
+	"net/http"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/internal/log"
+	"github.com/kevindiu/monorepo-go-example/internal/middleware"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errorHandler writes backend errors as {code, message, details} with the
+// HTTP status of their gRPC code. The backend's response headers are
+// forwarded like grpc-gateway does, and retry-after as Retry-After.
+func errorHandler(logger *log.Logger) runtime.ErrorHandlerFunc {
+	return func(ctx context.Context, mux *runtime.ServeMux, _ runtime.Marshaler, w http.ResponseWriter, r *http.Request, err error) {
+		httpStatus := 0
+		var statusErr *runtime.HTTPStatusError
+		if stderrors.As(err, &statusErr) {
+			err, httpStatus = statusErr.Err, statusErr.HTTPStatus
+		}
+		st := status.Convert(err)
+		if httpStatus == 0 {
+			httpStatus = runtime.HTTPStatusFromCode(st.Code())
+		}
+
+		w.Header().Del("Trailer")
+		w.Header().Del("Transfer-Encoding")
+		if st.Code() == codes.Unauthenticated {
+			w.Header().Set("WWW-Authenticate", st.Message())
+		}
+		if md, ok := runtime.ServerMetadataFromContext(ctx); ok {
+			for key, values := range md.HeaderMD {
+				for _, v := range values {
+					w.Header().Add(runtime.MetadataHeaderPrefix+key, v)
+				}
+			}
+			if values := md.HeaderMD.Get(middleware.RetryAfterMetadataKey); len(values) > 0 {
+				w.Header().Set("Retry-After", values[0])
+			}
+		}
+
+		if err := errors.WriteHTTPStatus(w, httpStatus, st); err != nil {
+			logger.Debug("Failed to write error response", log.Error(err))
+		}
+	}
+}
+
+// writeError writes an error the gateway raises itself in the same body as
+// backend errors
+func writeError(w http.ResponseWriter, c codes.Code, message string) {
+	errors.WriteHTTPStatus(w, runtime.HTTPStatusFromCode(c), status.New(c, message))
+}
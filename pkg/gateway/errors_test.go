@@ -0,0 +1,125 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/internal/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestErrorHandler(t *testing.T) {
+	handler := errorHandler(log.NewDefault())
+
+	tests := []struct {
+		name           string
+		err            error
+		md             metadata.MD
+		wantStatus     int
+		wantCode       string
+		wantMessage    string
+		wantDetails    []string
+		wantRetryAfter string
+	}{
+		{
+			name:        "invalid field",
+			err:         errors.ToGRPCStatus(errors.InvalidField("user_id", "user_id is required")).Err(),
+			wantStatus:  http.StatusBadRequest,
+			wantCode:    errors.CodeInvalidInput,
+			wantMessage: "user_id is required",
+			wantDetails: []string{"google.rpc.ErrorInfo", "google.rpc.BadRequest"},
+		},
+		{
+			name:        "not found",
+			err:         errors.ToGRPCStatus(errors.WithCode(errors.New("order not found"), errors.CodeNotFound)).Err(),
+			wantStatus:  http.StatusNotFound,
+			wantCode:    errors.CodeNotFound,
+			wantMessage: "order not found",
+			wantDetails: []string{"google.rpc.ErrorInfo"},
+		},
+		{
+			name:        "status without details",
+			err:         status.Error(codes.FailedPrecondition, "order is not pending"),
+			wantStatus:  http.StatusBadRequest,
+			wantCode:    "FAILED_PRECONDITION",
+			wantMessage: "order is not pending",
+		},
+		{
+			name:           "throttled",
+			err:            status.Error(codes.ResourceExhausted, "rate limit exceeded"),
+			md:             metadata.Pairs("retry-after", "30"),
+			wantStatus:     http.StatusTooManyRequests,
+			wantCode:       "RESOURCE_EXHAUSTED",
+			wantMessage:    "rate limit exceeded",
+			wantRetryAfter: "30",
+		},
+		{
+			name:        "routing error",
+			err:         &runtime.HTTPStatusError{HTTPStatus: http.StatusMethodNotAllowed, Err: status.Error(codes.Unimplemented, "method not allowed")},
+			wantStatus:  http.StatusMethodNotAllowed,
+			wantCode:    "UNIMPLEMENTED",
+			wantMessage: "method not allowed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := runtime.NewServerMetadataContext(context.Background(), runtime.ServerMetadata{HeaderMD: tt.md})
+			r := httptest.NewRequest(http.MethodGet, "/v1/orders/o1", nil)
+			w := httptest.NewRecorder()
+			handler(ctx, runtime.NewServeMux(), &runtime.JSONPb{}, w, r, tt.err)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			if got := w.Header().Get("Retry-After"); got != tt.wantRetryAfter {
+				t.Errorf("Retry-After = %q, want %q", got, tt.wantRetryAfter)
+			}
+
+			var body struct {
+				Code    string `json:"code"`
+				Message string `json:"message"`
+				Details []struct {
+					Type string `json:"@type"`
+				} `json:"details"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("body %s: %v", w.Body.String(), err)
+			}
+			if body.Code != tt.wantCode || body.Message != tt.wantMessage {
+				t.Errorf("body = %s %q, want %s %q", body.Code, body.Message, tt.wantCode, tt.wantMessage)
+			}
+			var types []string
+			for _, d := range body.Details {
+				types = append(types, strings.TrimPrefix(d.Type, "type.googleapis.com/"))
+			}
+			if strings.Join(types, ",") != strings.Join(tt.wantDetails, ",") {
+				t.Errorf("details = %v, want %v", types, tt.wantDetails)
+			}
+		})
+	}
+}
@@ -0,0 +1,290 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package gateway
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/csv"
+	"encoding/xml"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	orderv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/order/v1"
+	"github.com/kevindiu/monorepo-go-example/internal/log"
+	"github.com/kevindiu/monorepo-go-example/pkg/client"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// exportPath is the route of order downloads
+const exportPath = "/v1/orders:export"
+
+// exportColumns are the columns of an order download
+var exportColumns = []interface{}{"id", "user_id", "status", "total_amount", "currency", "created_at", "updated_at"}
+
+// exportFormat is a download format of the export route
+type exportFormat struct {
+	contentType string
+	newWriter   func(w io.Writer) (exportWriter, error)
+}
+
+// exportFormats are the download formats by their format parameter
+var exportFormats = map[string]exportFormat{
+	"csv":  {contentType: "text/csv; charset=utf-8", newWriter: newCSVExport},
+	"xlsx": {contentType: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", newWriter: newXLSXExport},
+}
+
+// exportWriter writes the rows of a download. Cells are strings or
+// float64s.
+type exportWriter interface {
+	write(row []interface{}) error
+	// flush writes out the rows buffered so far
+	flush() error
+	// close ends the download
+	close() error
+}
+
+// exportOrders serves GET /v1/orders:export. It takes the user_id and
+// currency filters of ListOrders and a format of csv, the default, or
+// xlsx, and streams ExportOrders as a download, flushing after every
+// batch. A failure after the download started cuts it short, so that the
+// client does not take it for complete.
+func (g *Gateway) exportOrders(w http.ResponseWriter, r *http.Request) {
+	_, marshaler := runtime.MarshalerForRequest(g.mux, r)
+	fail := func(err error) {
+		runtime.HTTPError(r.Context(), g.mux, marshaler, w, r, err)
+	}
+
+	if r.Method != http.MethodGet {
+		fail(&runtime.HTTPStatusError{
+			HTTPStatus: http.StatusMethodNotAllowed,
+			Err:        status.Error(codes.Unimplemented, "method not allowed"),
+		})
+		return
+	}
+	query := r.URL.Query()
+	name := query.Get("format")
+	if name == "" {
+		name = "csv"
+	}
+	format, ok := exportFormats[name]
+	if !ok {
+		fail(status.Errorf(codes.InvalidArgument, "format %q is not csv or xlsx", name))
+		return
+	}
+
+	ctx, cancel := context.WithCancel(client.Forward(r.Context(), r))
+	defer cancel()
+	stream, err := g.orders.ExportOrders(ctx, &orderv1.ExportOrdersRequest{
+		UserId:   query.Get("user_id"),
+		Currency: query.Get("currency"),
+	})
+	if err != nil {
+		fail(err)
+		return
+	}
+
+	// Wait for the first batch, so that a rejected request still gets an
+	// error status
+	batch, err := stream.Recv()
+	if err == io.EOF {
+		batch, err = nil, nil
+	}
+	if err != nil {
+		fail(err)
+		return
+	}
+
+	w.Header().Set("Content-Type", format.contentType)
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{
+		"filename": "orders-" + time.Now().UTC().Format("20060102") + "." + name,
+	}))
+	out, err := format.newWriter(w)
+	if err == nil {
+		err = out.write(exportColumns)
+	}
+	for err == nil && batch != nil {
+		for _, order := range batch.GetOrders() {
+			if err = out.write(exportRow(order)); err != nil {
+				break
+			}
+		}
+		if err == nil {
+			err = out.flush()
+		}
+		if err == nil {
+			// Not every ResponseWriter can flush; the rows then arrive
+			// when the handler returns
+			http.NewResponseController(w).Flush()
+			batch, err = stream.Recv()
+		}
+	}
+	if err == io.EOF {
+		err = nil
+	}
+	if err == nil {
+		err = out.close()
+	}
+	if err != nil {
+		g.logger.Error("Order export failed", log.Error(err))
+		panic(http.ErrAbortHandler)
+	}
+}
+
+// exportRow returns the cells of order, in the order of exportColumns
+func exportRow(order *orderv1.Order) []interface{} {
+	return []interface{}{
+		order.GetId(),
+		order.GetUserId(),
+		strings.ToLower(strings.TrimPrefix(order.GetStatus().String(), "ORDER_STATUS_")),
+		order.GetTotalAmount(),
+		order.GetCurrency(),
+		order.GetCreatedAt().AsTime().Format(time.RFC3339),
+		order.GetUpdatedAt().AsTime().Format(time.RFC3339),
+	}
+}
+
+// csvExport writes a download as CSV, in the column layout CSV uploads
+// accept where they share columns
+type csvExport struct {
+	w *csv.Writer
+}
+
+func newCSVExport(w io.Writer) (exportWriter, error) {
+	return &csvExport{w: csv.NewWriter(w)}, nil
+}
+
+func (e *csvExport) write(row []interface{}) error {
+	record := make([]string, len(row))
+	for i, cell := range row {
+		switch v := cell.(type) {
+		case float64:
+			record[i] = strconv.FormatFloat(v, 'f', -1, 64)
+		default:
+			record[i] = v.(string)
+		}
+	}
+	return e.w.Write(record)
+}
+
+func (e *csvExport) flush() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+func (e *csvExport) close() error {
+	return e.flush()
+}
+
+// XLSX package parts written ahead of the sheet
+var xlsxParts = []struct {
+	name    string
+	content string
+}{
+	{
+		name: "[Content_Types].xml",
+		content: xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+			`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+			`<Default Extension="xml" ContentType="application/xml"/>` +
+			`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+			`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+			`</Types>`,
+	},
+	{
+		name: "_rels/.rels",
+		content: xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+			`</Relationships>`,
+	},
+	{
+		name: "xl/workbook.xml",
+		content: xml.Header + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+			`<sheets><sheet name="Orders" sheetId="1" r:id="rId1"/></sheets>` +
+			`</workbook>`,
+	},
+	{
+		name: "xl/_rels/workbook.xml.rels",
+		content: xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+			`</Relationships>`,
+	},
+}
+
+// xlsxExport writes a download as a single-sheet XLSX workbook. The sheet
+// is the last part of the package, so its rows are compressed and sent as
+// they are written.
+type xlsxExport struct {
+	zw    *zip.Writer
+	sheet io.Writer
+}
+
+func newXLSXExport(w io.Writer) (exportWriter, error) {
+	zw := zip.NewWriter(w)
+	for _, part := range xlsxParts {
+		f, err := zw.Create(part.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.WriteString(f, part.content); err != nil {
+			return nil, err
+		}
+	}
+
+	sheet, err := zw.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.WriteString(sheet, xml.Header+`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`); err != nil {
+		return nil, err
+	}
+	return &xlsxExport{zw: zw, sheet: sheet}, nil
+}
+
+func (e *xlsxExport) write(row []interface{}) error {
+	var b strings.Builder
+	b.WriteString("<row>")
+	for _, cell := range row {
+		switch v := cell.(type) {
+		case float64:
+			b.WriteString("<c><v>" + strconv.FormatFloat(v, 'f', -1, 64) + "</v></c>")
+		default:
+			b.WriteString(`<c t="inlineStr"><is><t>`)
+			xml.EscapeText(&b, []byte(v.(string)))
+			b.WriteString("</t></is></c>")
+		}
+	}
+	b.WriteString("</row>")
+	_, err := io.WriteString(e.sheet, b.String())
+	return err
+}
+
+func (e *xlsxExport) flush() error {
+	return e.zw.Flush()
+}
+
+func (e *xlsxExport) close() error {
+	if _, err := io.WriteString(e.sheet, "</sheetData></worksheet>"); err != nil {
+		return err
+	}
+	return e.zw.Close()
+}
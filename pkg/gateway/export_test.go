@@ -0,0 +1,180 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package gateway
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	orderv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/order/v1"
+	"github.com/kevindiu/monorepo-go-example/internal/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// mockExportClient streams its batches from ExportOrders
+type mockExportClient struct {
+	orderv1.OrderServiceClient
+	batches [][]*orderv1.Order
+	err     error
+}
+
+func (m *mockExportClient) ExportOrders(ctx context.Context, req *orderv1.ExportOrdersRequest, opts ...grpc.CallOption) (orderv1.OrderService_ExportOrdersClient, error) {
+	return &mockExportStream{batches: m.batches, err: m.err}, nil
+}
+
+// mockExportStream returns its batches, then err or io.EOF
+type mockExportStream struct {
+	grpc.ClientStream
+	batches [][]*orderv1.Order
+	err     error
+}
+
+func (s *mockExportStream) Recv() (*orderv1.ExportOrdersResponse, error) {
+	if len(s.batches) == 0 {
+		if s.err != nil {
+			return nil, s.err
+		}
+		return nil, io.EOF
+	}
+	batch := s.batches[0]
+	s.batches = s.batches[1:]
+	return &orderv1.ExportOrdersResponse{Orders: batch}, nil
+}
+
+func exportOrder(id string, total float64) *orderv1.Order {
+	at := timestamppb.New(time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC))
+	return &orderv1.Order{
+		Id:          id,
+		UserId:      "u1",
+		Status:      orderv1.OrderStatus_ORDER_STATUS_SHIPPED,
+		TotalAmount: total,
+		Currency:    "USD",
+		CreatedAt:   at,
+		UpdatedAt:   at,
+	}
+}
+
+func TestExportOrders(t *testing.T) {
+	batches := [][]*orderv1.Order{
+		{exportOrder("o1", 12.5), exportOrder("o2", 3)},
+		{exportOrder("o<3>", 7)},
+	}
+
+	tests := []struct {
+		name      string
+		method    string
+		query     string
+		client    *mockExportClient
+		wantCode  int
+		wantType  string
+		wantRows  int
+		wantAbort bool
+	}{
+		{name: "csv", client: &mockExportClient{batches: batches}, wantCode: http.StatusOK, wantType: "text/csv; charset=utf-8", wantRows: 4},
+		{name: "empty csv", client: &mockExportClient{}, wantCode: http.StatusOK, wantType: "text/csv; charset=utf-8", wantRows: 1},
+		{name: "xlsx", query: "format=xlsx", client: &mockExportClient{batches: batches}, wantCode: http.StatusOK, wantType: exportFormats["xlsx"].contentType},
+		{name: "unknown format", query: "format=pdf", client: &mockExportClient{}, wantCode: http.StatusBadRequest},
+		{name: "rejected", client: &mockExportClient{err: status.Error(codes.InvalidArgument, "bad currency")}, wantCode: http.StatusBadRequest},
+		{name: "failure after first batch", client: &mockExportClient{batches: batches[:1], err: status.Error(codes.Unavailable, "gone")}, wantAbort: true},
+		{name: "method not allowed", method: http.MethodPost, client: &mockExportClient{}, wantCode: http.StatusMethodNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := &Gateway{mux: runtime.NewServeMux(), orders: tt.client, logger: log.NewDefault()}
+			method := tt.method
+			if method == "" {
+				method = http.MethodGet
+			}
+			req := httptest.NewRequest(method, exportPath+"?"+tt.query, nil)
+			w := httptest.NewRecorder()
+
+			aborted := func() (aborted bool) {
+				defer func() {
+					if r := recover(); r != nil {
+						aborted = r == http.ErrAbortHandler
+					}
+				}()
+				g.exportOrders(w, req)
+				return false
+			}()
+			if aborted != tt.wantAbort {
+				t.Fatalf("aborted = %v, want %v", aborted, tt.wantAbort)
+			}
+			if tt.wantAbort {
+				return
+			}
+
+			if w.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantCode, w.Body.String())
+			}
+			if tt.wantType == "" {
+				return
+			}
+			if got := w.Header().Get("Content-Type"); got != tt.wantType {
+				t.Errorf("Content-Type = %q, want %q", got, tt.wantType)
+			}
+			if got := w.Header().Get("Content-Disposition"); !strings.HasPrefix(got, "attachment; filename=orders-") {
+				t.Errorf("Content-Disposition = %q", got)
+			}
+
+			if tt.wantRows > 0 {
+				rows, err := csv.NewReader(w.Body).ReadAll()
+				if err != nil {
+					t.Fatalf("invalid CSV: %v", err)
+				}
+				if len(rows) != tt.wantRows {
+					t.Fatalf("CSV has %d rows, want %d", len(rows), tt.wantRows)
+				}
+				if tt.wantRows > 1 && strings.Join(rows[1], ",") != "o1,u1,shipped,12.5,USD,2026-01-02T03:04:05Z,2026-01-02T03:04:05Z" {
+					t.Errorf("first row = %v", rows[1])
+				}
+				return
+			}
+
+			zr, err := zip.NewReader(bytes.NewReader(w.Body.Bytes()), int64(w.Body.Len()))
+			if err != nil {
+				t.Fatalf("invalid XLSX: %v", err)
+			}
+			for _, f := range zr.File {
+				if f.Name != "xl/worksheets/sheet1.xml" {
+					continue
+				}
+				rc, _ := f.Open()
+				sheet, _ := io.ReadAll(rc)
+				rc.Close()
+				if !strings.Contains(string(sheet), "<c><v>12.5</v></c>") || !strings.Contains(string(sheet), "o&lt;3&gt;") {
+					t.Errorf("sheet = %s", sheet)
+				}
+				return
+			}
+			t.Error("XLSX has no sheet")
+		})
+	}
+}
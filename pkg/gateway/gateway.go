@@ -18,30 +18,152 @@ package gateway
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"strings"
+	"sync/atomic"
 
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	apikeyv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/apikey/v1"
+	auditv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/audit/v1"
+	authv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/auth/v1"
 	orderv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/order/v1"
+	orderv2 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/order/v2"
+	productv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/product/v1"
+	recommendationv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/recommendation/v1"
+	searchv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/search/v1"
+	shippingv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/shipping/v1"
 	userv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/user/v1"
+	userv2 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/user/v2"
+	webhookv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/webhook/v1"
+	"github.com/kevindiu/monorepo-go-example/internal/apikey"
+	"github.com/kevindiu/monorepo-go-example/internal/auth"
+	"github.com/kevindiu/monorepo-go-example/internal/certs"
+	"github.com/kevindiu/monorepo-go-example/internal/config"
+	"github.com/kevindiu/monorepo-go-example/internal/health"
+	"github.com/kevindiu/monorepo-go-example/internal/idempotency"
 	"github.com/kevindiu/monorepo-go-example/internal/log"
+	"github.com/kevindiu/monorepo-go-example/internal/middleware"
+	"github.com/kevindiu/monorepo-go-example/internal/ratelimit"
+	"github.com/kevindiu/monorepo-go-example/internal/session"
+	"github.com/kevindiu/monorepo-go-example/internal/tenant"
+	"github.com/kevindiu/monorepo-go-example/pkg/bff"
+	"github.com/kevindiu/monorepo-go-example/pkg/client"
+	"github.com/kevindiu/monorepo-go-example/pkg/graphql"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/codes"
 )
 
 // Gateway represents the API gateway
 type Gateway struct {
-	userServiceEndpoint  string
-	orderServiceEndpoint string
-	logger               *log.Logger
-	mux                  *runtime.ServeMux
+	userServiceEndpoint     string
+	orderServiceEndpoint    string
+	webhookServiceEndpoint  string
+	searchServiceEndpoint   string
+	auditServiceEndpoint    string
+	shippingServiceEndpoint string
+	productServiceEndpoint  string
+	tenants                 *tenant.Resolver
+	files                   http.Handler
+	sessions                *session.Manager
+	apiKeys                 *apikey.Validator
+	auth                    *auth.Authenticator
+	playground              bool
+	bffConfig               *config.BFF
+	keepalive               *config.Keepalive
+	backends                *config.Gateway
+	certs                   *certs.Store
+	serviceConfig           string
+	stopWatch               context.CancelFunc
+	cors                    atomic.Value // *corsPolicy
+	accessLog               config.AccessLog
+	checks                  []health.Checker
+	logger                  *log.Logger
+	mux                     *runtime.ServeMux
+	conns                   map[string]*grpc.ClientConn
+	graphql                 http.Handler
+	bff                     http.Handler
+	connectAPI              *connectHandler
+	deprecation             *deprecation
+	openAPI                 *openAPISpecs
+	limiter                 *ratelimit.Limiter
+	ipLimiter               *ratelimit.Limiter
+	keyLimiter              *ratelimit.Limiter
+	trustForwardedFor       bool
+	orders                  orderv1.OrderServiceClient
 }
 
 // Config holds gateway configuration
 type Config struct {
 	UserServiceEndpoint  string
 	OrderServiceEndpoint string
-	Logger               *log.Logger
+	// The webhook, search, audit, shipping and product services are
+	// optional; their routes are only served when an endpoint is set
+	WebhookServiceEndpoint  string
+	SearchServiceEndpoint   string
+	AuditServiceEndpoint    string
+	ShippingServiceEndpoint string
+	ProductServiceEndpoint  string
+	// Tenants resolves the tenant of each request; nil resolves every
+	// request to the default tenant
+	Tenants *tenant.Resolver
+	// Files serves signed object storage URLs under /files/; it is only
+	// set when objects are stored on the local filesystem
+	Files http.Handler
+	// Sessions authenticates browser requests by session cookie; nil
+	// disables cookie sessions
+	Sessions *session.Manager
+	// APIKeys authenticates service-to-service requests by X-API-Key
+	// header; nil disables API keys
+	APIKeys *apikey.Validator
+	// Auth requires a bearer token on every route but its public paths;
+	// nil leaves requests unauthenticated
+	Auth *auth.Authenticator
+	// GraphQLPlayground serves a query editor on GET /graphql
+	GraphQLPlayground bool
+	// BFF tunes the mobile BFF routes; nil uses their defaults
+	BFF *config.BFF
+	// AccessLog samples the per-request access log; nil logs every request
+	AccessLog *config.AccessLog
+	// CORS sets the allowed cross-origin requests; nil allows any origin
+	CORS *config.CORS
+	// Keepalive pings idle backend connections; nil leaves the gRPC
+	// defaults. Backends.Keepalive takes precedence when set.
+	Keepalive *config.Keepalive
+	// Backends tunes connection backoff, call timeouts and retries of the
+	// backend connections; nil uses the gRPC defaults without timeouts or
+	// retries
+	Backends *config.Gateway
+	// TLS secures the backend connections, presenting its certificate to
+	// backends that require one; nil connects in plaintext
+	TLS *certs.Store
+	// Deprecation dates the v1 REST routes superseded by v2; nil only
+	// links them to their successors
+	Deprecation *config.Deprecation
+	// RateLimiter throttles requests by tenant; nil leaves them unlimited
+	RateLimiter *ratelimit.Limiter
+	// ClientIPLimiter throttles requests by client IP address, taken from
+	// X-Forwarded-For when TrustForwardedFor is set; nil leaves them
+	// unlimited
+	ClientIPLimiter   *ratelimit.Limiter
+	TrustForwardedFor bool
+	// APIKeyLimiter throttles requests authenticated by API key by key;
+	// nil leaves keys unlimited
+	APIKeyLimiter *ratelimit.Limiter
+	// Checks are added to the readiness probe alongside the backend
+	// health checks
+	Checks []health.Checker
+	Logger *log.Logger
+}
+
+// headerMatcher forwards the idempotency key header as metadata, deferring
+// to the tenant header matcher otherwise
+func headerMatcher(key string) (string, bool) {
+	if md, ok := idempotency.HeaderMatcher(key); ok {
+		return md, true
+	}
+	return tenant.HeaderMatcher(key)
 }
 
 // New creates a new gateway
@@ -49,106 +171,363 @@ func New(cfg Config) (*Gateway, error) {
 	if cfg.Logger == nil {
 		cfg.Logger = log.NewDefault()
 	}
+	if cfg.Tenants == nil {
+		cfg.Tenants = tenant.NewResolver(nil)
+	}
+	if cfg.BFF == nil {
+		cfg.BFF = &config.BFF{}
+	}
+	if cfg.Backends == nil {
+		cfg.Backends = &config.Gateway{}
+	}
+	if cfg.Backends.Keepalive != nil {
+		cfg.Keepalive = cfg.Backends.Keepalive
+	}
+
+	// Create gRPC-Gateway mux, forwarding the tenant and idempotency key
+	// headers, the session user, the API key and its scopes and the
+	// request ID to the backends, and
+	// rendering their errors as JSON envelopes
+	mux := runtime.NewServeMux(
+		runtime.WithIncomingHeaderMatcher(headerMatcher),
+		runtime.WithMetadata(session.Metadata),
+		runtime.WithMetadata(apikey.Metadata),
+		runtime.WithMetadata(middleware.RequestIDMetadata),
+		runtime.WithErrorHandler(errorHandler(cfg.Logger)),
+	)
 
-	// Create gRPC-Gateway mux
-	mux := runtime.NewServeMux()
+	dep, err := newDeprecation(cfg.Deprecation)
+	if err != nil {
+		return nil, err
+	}
+	serviceConfig, err := buildServiceConfig(cfg.Backends, backendServices...)
+	if err != nil {
+		return nil, err
+	}
+	specs, err := loadOpenAPI()
+	if err != nil {
+		return nil, err
+	}
 
 	gw := &Gateway{
-		userServiceEndpoint:  cfg.UserServiceEndpoint,
-		orderServiceEndpoint: cfg.OrderServiceEndpoint,
-		logger:               cfg.Logger,
-		mux:                  mux,
+		userServiceEndpoint:     cfg.UserServiceEndpoint,
+		orderServiceEndpoint:    cfg.OrderServiceEndpoint,
+		webhookServiceEndpoint:  cfg.WebhookServiceEndpoint,
+		searchServiceEndpoint:   cfg.SearchServiceEndpoint,
+		auditServiceEndpoint:    cfg.AuditServiceEndpoint,
+		shippingServiceEndpoint: cfg.ShippingServiceEndpoint,
+		productServiceEndpoint:  cfg.ProductServiceEndpoint,
+		tenants:                 cfg.Tenants,
+		files:                   cfg.Files,
+		sessions:                cfg.Sessions,
+		apiKeys:                 cfg.APIKeys,
+		auth:                    cfg.Auth,
+		playground:              cfg.GraphQLPlayground,
+		bffConfig:               cfg.BFF,
+		keepalive:               cfg.Keepalive,
+		backends:                cfg.Backends,
+		certs:                   cfg.TLS,
+		serviceConfig:           serviceConfig,
+		deprecation:             dep,
+		openAPI:                 specs,
+		limiter:                 cfg.RateLimiter,
+		ipLimiter:               cfg.ClientIPLimiter,
+		keyLimiter:              cfg.APIKeyLimiter,
+		trustForwardedFor:       cfg.TrustForwardedFor,
+		checks:                  cfg.Checks,
+		logger:                  cfg.Logger,
+		mux:                     mux,
+		conns:                   make(map[string]*grpc.ClientConn),
+	}
+	gw.accessLog = config.AccessLog{SampleRate: 1}
+	if cfg.AccessLog != nil {
+		gw.accessLog = *cfg.AccessLog
 	}
+	gw.cors.Store(newCORSPolicy(cfg.CORS))
 
 	return gw, nil
 }
 
-// Start initializes connections to backend services and registers handlers
+// registerFunc registers a backend's HTTP handlers on the mux
+type registerFunc func(ctx context.Context, mux *runtime.ServeMux, conn *grpc.ClientConn) error
+
+// registerAll registers the handlers of every API version a backend serves
+func registerAll(fns ...registerFunc) registerFunc {
+	return func(ctx context.Context, mux *runtime.ServeMux, conn *grpc.ClientConn) error {
+		for _, fn := range fns {
+			if err := fn(ctx, mux, conn); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// Start initializes connections to backend services and registers
+// handlers. It does not wait for the backends: connections are made and
+// remade in the background, and the readiness probe reports the backends
+// that are down.
 func (g *Gateway) Start(ctx context.Context) error {
-	// Connect to user service
-	g.logger.Info("Connecting to user service", log.String("endpoint", g.userServiceEndpoint))
-	userConn, err := grpc.DialContext(
-		ctx,
-		g.userServiceEndpoint,
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
-		grpc.WithBlock(),
-	)
-	if err != nil {
-		return fmt.Errorf("failed to connect to user service: %w", err)
+	watchCtx, cancel := context.WithCancel(context.Background())
+	g.stopWatch = cancel
+
+	backends := []struct {
+		name     string
+		endpoint string
+		optional bool
+		register registerFunc
+	}{
+		{name: "user", endpoint: g.userServiceEndpoint, register: registerAll(userv1.RegisterUserServiceHandler, userv2.RegisterUserServiceHandler, authv1.RegisterAuthServiceHandler, apikeyv1.RegisterAPIKeyServiceHandler)},
+		{name: "order", endpoint: g.orderServiceEndpoint, register: registerAll(orderv1.RegisterOrderServiceHandler, orderv2.RegisterOrderServiceHandler)},
+		// Recommendations are served by the order service
+		{name: "recommendation", endpoint: g.orderServiceEndpoint, register: recommendationv1.RegisterRecommendationServiceHandler},
+		{name: "webhook", endpoint: g.webhookServiceEndpoint, optional: true, register: webhookv1.RegisterWebhookServiceHandler},
+		{name: "search", endpoint: g.searchServiceEndpoint, optional: true, register: searchv1.RegisterSearchServiceHandler},
+		{name: "audit", endpoint: g.auditServiceEndpoint, optional: true, register: auditv1.RegisterAuditServiceHandler},
+		{name: "shipping", endpoint: g.shippingServiceEndpoint, optional: true, register: shippingv1.RegisterShippingServiceHandler},
+		{name: "product", endpoint: g.productServiceEndpoint, optional: true, register: productv1.RegisterProductServiceHandler},
 	}
 
-	// Register user service handler
-	if err := userv1.RegisterUserServiceHandler(ctx, g.mux, userConn); err != nil {
-		return fmt.Errorf("failed to register user service handler: %w", err)
+	for _, b := range backends {
+		if b.optional && b.endpoint == "" {
+			continue
+		}
+		if err := g.connect(ctx, b.name, b.endpoint, b.register); err != nil {
+			g.Close()
+			return err
+		}
+		go g.watch(watchCtx, b.name, g.conns[b.name])
+		// Optional backends only degrade readiness
+		g.checks = append(g.checks, health.Checker{
+			Name:     b.name + "-service",
+			Check:    health.Conn(g.conns[b.name], ""),
+			Critical: !b.optional,
+		})
 	}
 
-	// Connect to order service
-	g.logger.Info("Connecting to order service", log.String("endpoint", g.orderServiceEndpoint))
-	orderConn, err := grpc.DialContext(
-		ctx,
-		g.orderServiceEndpoint,
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
-		grpc.WithBlock(),
-	)
+	// The graph and the BFF routes compose the user, order, recommendation
+	// and shipping services over the same connections as their REST routes
+	users := userv1.NewUserServiceClient(g.conns["user"])
+	g.orders = orderv1.NewOrderServiceClient(g.conns["order"])
+	g.graphql = graphql.NewHandler(graphql.Config{
+		UserService:  users,
+		OrderService: g.orders,
+		Playground:   g.playground,
+	})
+	bffConfig := bff.ConfigFrom(g.bffConfig, users, g.orders, g.logger)
+	bffConfig.RecommendationService = recommendationv1.NewRecommendationServiceClient(g.conns["recommendation"])
+	if conn, ok := g.conns["shipping"]; ok {
+		bffConfig.ShippingService = shippingv1.NewShippingServiceClient(conn)
+	}
+	g.bff = bff.NewHandler(bffConfig)
+	g.connectAPI = newConnectHandler(users, g.orders)
+
+	g.logger.Info("Gateway initialized successfully")
+	return nil
+}
+
+// connect dials a backend service and registers its handlers
+func (g *Gateway) connect(ctx context.Context, name, endpoint string, register registerFunc) error {
+	g.logger.Info("Connecting to "+name+" service", log.String("endpoint", endpoint))
+	clientConfig := client.Config{
+		Address:     endpoint,
+		Keepalive:   client.KeepaliveFrom(g.keepalive),
+		DialOptions: dialOptions(g.backends, g.serviceConfig),
+	}
+	if g.certs != nil {
+		clientConfig.TLS = g.certs.ClientConfig()
+	}
+	conn, err := client.Dial(ctx, clientConfig)
 	if err != nil {
-		return fmt.Errorf("failed to connect to order service: %w", err)
+		return fmt.Errorf("failed to connect to %s service: %w", name, err)
 	}
+	conn.Connect()
 
-	// Register order service handler
-	if err := orderv1.RegisterOrderServiceHandler(ctx, g.mux, orderConn); err != nil {
-		return fmt.Errorf("failed to register order service handler: %w", err)
+	if err := register(ctx, g.mux, conn); err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to register %s service handler: %w", name, err)
 	}
+	g.conns[name] = conn
 
-	g.logger.Info("Gateway initialized successfully")
 	return nil
 }
 
+// Close stops watching the backend connections and closes them
+func (g *Gateway) Close() error {
+	if g.stopWatch != nil {
+		g.stopWatch()
+	}
+	var errs []error
+	for name, conn := range g.conns {
+		if err := conn.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to close %s service connection: %w", name, err))
+		}
+	}
+	g.conns = make(map[string]*grpc.ClientConn)
+	return errors.Join(errs...)
+}
+
+// Conns returns the backend connections opened by Start, by backend name
+func (g *Gateway) Conns() map[string]*grpc.ClientConn {
+	conns := make(map[string]*grpc.ClientConn, len(g.conns))
+	for name, conn := range g.conns {
+		conns[name] = conn
+	}
+	return conns
+}
+
 // Handler returns the HTTP handler
 func (g *Gateway) Handler() http.Handler {
 	// Wrap the mux with middleware
-	handler := g.loggingMiddleware(g.mux)
+	handler := tenant.Middleware(g.tenants, g.rateLimitMiddleware(g.composedMiddleware(g.deprecationMiddleware(g.mux))))
+	handler = g.authMiddleware(handler)
+	handler = g.apiKeyMiddleware(handler)
+	handler = g.sessionMiddleware(handler)
+	handler = g.filesMiddleware(handler)
+	handler = g.docsMiddleware(handler)
+	handler = g.loggingMiddleware(handler)
 	handler = g.corsMiddleware(handler)
 	handler = g.healthCheckMiddleware(handler)
+	// Every response, errors included, carries IDs callers can quote
+	handler = middleware.RequestID(handler)
+	handler = middleware.TraceResponse(handler)
+	// Panics anywhere in the chain are answered with a JSON 500
+	handler = g.recoveryMiddleware(handler)
 	return handler
 }
 
-// healthCheckMiddleware adds health check endpoints
+// healthCheckMiddleware adds health check endpoints. /health/ready checks
+// the backends connected by Start, failing at once for those whose
+// connection is down.
 func (g *Gateway) healthCheckMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if r.URL.Path == "/health" || r.URL.Path == "/ready" {
+		switch r.URL.Path {
+		case "/health", health.LivePath:
 			w.Header().Set("Content-Type", "application/json")
 			w.WriteHeader(http.StatusOK)
 			w.Write([]byte(`{"status":"ok"}`))
+		case "/ready", health.ReadyPath:
+			health.Handler(g.checks).ServeHTTP(w, r)
+		default:
+			next.ServeHTTP(w, r)
+		}
+	})
+}
+
+// composedMiddleware serves the GraphQL endpoint and the BFF routes, which
+// compose several backends, the order upload and download routes, which
+// stream to and from their backend, and the Connect procedures, once Start
+// has connected them
+func (g *Gateway) composedMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == importPath && g.orders != nil:
+			g.importOrders(w, r)
+		case r.URL.Path == exportPath && g.orders != nil:
+			g.exportOrders(w, r)
+		case r.URL.Path == "/graphql" && g.graphql != nil:
+			g.graphql.ServeHTTP(w, r)
+		case (strings.HasPrefix(r.URL.Path, "/v1/me/") || bff.IsOrderDetails(r.URL.Path)) && g.bff != nil:
+			g.bff.ServeHTTP(w, r)
+		case g.connectAPI != nil && g.connectAPI.serves(r.URL.Path):
+			g.connectAPI.ServeHTTP(w, r)
+		default:
+			next.ServeHTTP(w, r)
+		}
+	})
+}
+
+// sessionMiddleware authenticates cookie sessions and serves logout
+func (g *Gateway) sessionMiddleware(next http.Handler) http.Handler {
+	if g.sessions == nil {
+		return next
+	}
+	next = session.Middleware(g.sessions, next)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/auth/logout" && r.Method == http.MethodPost {
+			if token := g.sessions.Token(r); token != "" {
+				if err := g.sessions.Revoke(r.Context(), token); err != nil {
+					g.logger.Error("Failed to revoke session", log.Error(err))
+					writeError(w, codes.Internal, "failed to revoke session")
+					return
+				}
+			}
+			g.sessions.ClearCookie(w)
+			w.WriteHeader(http.StatusNoContent)
 			return
 		}
 		next.ServeHTTP(w, r)
 	})
 }
 
-// loggingMiddleware logs incoming requests
-func (g *Gateway) loggingMiddleware(next http.Handler) http.Handler {
+// apiKeyMiddleware authenticates requests by API key
+func (g *Gateway) apiKeyMiddleware(next http.Handler) http.Handler {
+	if g.apiKeys == nil {
+		return next
+	}
+	return apikey.Middleware(g.apiKeys, next)
+}
+
+// authMiddleware requires a bearer token on every route but the public
+// ones. Requests authenticated by a session cookie or an API key get a
+// token issued for the session user or the key, so the backends see the
+// same credentials either way. A key's scopes are the roles of its token.
+func (g *Gateway) authMiddleware(next http.Handler) http.Handler {
+	if g.auth == nil {
+		return next
+	}
+	next = auth.Middleware(g.auth, next)
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		g.logger.Info("Request",
-			log.String("method", r.Method),
-			log.String("path", r.URL.Path),
-			log.String("remote_addr", r.RemoteAddr),
-		)
+		if r.Header.Get("Authorization") == "" {
+			var claims *auth.Claims
+			if s, ok := session.FromContext(r.Context()); ok {
+				claims = &auth.Claims{Subject: s.UserID, TenantID: s.TenantID, Roles: s.Roles}
+			} else if k, ok := apikey.FromContext(r.Context()); ok {
+				claims = &auth.Claims{Subject: apikey.Subject(k), TenantID: k.TenantID, Roles: k.Scopes}
+			}
+			if claims != nil {
+				token, err := g.auth.Issue(*claims)
+				if err != nil {
+					g.logger.Error("Failed to issue token", log.Error(err))
+					writeError(w, codes.Internal, "failed to issue token")
+					return
+				}
+				r.Header.Set("Authorization", "Bearer "+token)
+			}
+		}
 		next.ServeHTTP(w, r)
 	})
 }
 
-// corsMiddleware handles CORS
-func (g *Gateway) corsMiddleware(next http.Handler) http.Handler {
+// filesMiddleware serves signed storage URLs. Signed URLs carry their own
+// authorization, so they bypass tenant resolution.
+func (g *Gateway) filesMiddleware(next http.Handler) http.Handler {
+	if g.files == nil {
+		return next
+	}
+	files := http.StripPrefix("/files", g.files)
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
-
-		if r.Method == "OPTIONS" {
-			w.WriteHeader(http.StatusOK)
+		if strings.HasPrefix(r.URL.Path, "/files/") {
+			files.ServeHTTP(w, r)
 			return
 		}
-
 		next.ServeHTTP(w, r)
 	})
 }
+
+// Reload applies changed CORS settings; it is a config.Reloader
+func (g *Gateway) Reload(old, new *config.Config) (func(), error) {
+	if new.CORS == nil {
+		return nil, nil
+	}
+	cfg := new.CORS
+	policy := newCORSPolicy(cfg)
+	return func() {
+		g.cors.Store(policy)
+		g.logger.Info("CORS settings changed",
+			log.String("allowed_origins", strings.Join(cfg.AllowedOrigins, ",")),
+			log.String("allowed_methods", strings.Join(cfg.AllowedMethods, ",")),
+			log.Any("allow_credentials", cfg.AllowCredentials),
+		)
+	}, nil
+}
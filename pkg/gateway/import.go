@@ -0,0 +1,342 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package gateway
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	orderv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/order/v1"
+	"github.com/kevindiu/monorepo-go-example/pkg/client"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// importPath is the route of order uploads
+const importPath = "/v1/orders:import"
+
+// Upload limits
+const (
+	// importBatchSize is the number of orders sent per ImportOrders message
+	importBatchSize = 500
+	// maxImportLine is the longest NDJSON line accepted
+	maxImportLine = 1 << 20
+)
+
+// importOrders serves POST /v1/orders:import. The body is CSV or NDJSON,
+// as its Content-Type says, and is streamed to ImportOrders in batches
+// while it is parsed. Rows that cannot be parsed are reported alongside
+// the orders the order service rejected.
+func (g *Gateway) importOrders(w http.ResponseWriter, r *http.Request) {
+	_, marshaler := runtime.MarshalerForRequest(g.mux, r)
+	fail := func(err error) {
+		runtime.HTTPError(r.Context(), g.mux, marshaler, w, r, err)
+	}
+
+	if r.Method != http.MethodPost {
+		fail(&runtime.HTTPStatusError{
+			HTTPStatus: http.StatusMethodNotAllowed,
+			Err:        status.Error(codes.Unimplemented, "method not allowed"),
+		})
+		return
+	}
+	decoder, err := newOrderDecoder(r)
+	if err != nil {
+		fail(err)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(client.Forward(r.Context(), r))
+	defer cancel()
+	stream, err := g.orders.ImportOrders(ctx)
+	if err != nil {
+		fail(err)
+		return
+	}
+
+	// A failed send ends the stream; CloseAndRecv returns its status
+	var rowErrors []*orderv1.ImportOrderError
+	var sendErr error
+	batch := &orderv1.ImportOrdersRequest{}
+	for sendErr == nil {
+		order, err := decoder.next()
+		if err == io.EOF {
+			if len(batch.Orders) > 0 {
+				sendErr = stream.Send(batch)
+			}
+			break
+		}
+		var rowErr *rowError
+		if errors.As(err, &rowErr) {
+			rowErrors = append(rowErrors, &orderv1.ImportOrderError{Row: rowErr.row, Ref: rowErr.ref, Message: rowErr.message})
+			continue
+		}
+		if err != nil {
+			// Abandon the stream rather than import what was read of an
+			// upload that was cut short
+			cancel()
+			fail(status.Errorf(codes.InvalidArgument, "failed to read the upload: %v", err))
+			return
+		}
+
+		batch.Orders = append(batch.Orders, order)
+		if len(batch.Orders) == importBatchSize {
+			sendErr = stream.Send(batch)
+			batch = &orderv1.ImportOrdersRequest{}
+		}
+	}
+
+	resp, err := stream.CloseAndRecv()
+	if err != nil {
+		fail(err)
+		return
+	}
+	resp.Errors = append(resp.Errors, rowErrors...)
+	sort.SliceStable(resp.Errors, func(i, j int) bool { return resp.Errors[i].GetRow() < resp.Errors[j].GetRow() })
+
+	body, err := marshaler.Marshal(resp)
+	if err != nil {
+		fail(err)
+		return
+	}
+	w.Header().Set("Content-Type", marshaler.ContentType(resp))
+	w.Write(body)
+}
+
+// orderDecoder reads the orders of an upload. next returns a *rowError
+// for an order that cannot be parsed, and io.EOF after the last order.
+type orderDecoder interface {
+	next() (*orderv1.ImportOrder, error)
+}
+
+// rowError is an order of an upload that cannot be parsed
+type rowError struct {
+	row     int32
+	ref     string
+	message string
+}
+
+func (e *rowError) Error() string {
+	return fmt.Sprintf("row %d: %s", e.row, e.message)
+}
+
+// newOrderDecoder returns the decoder for the upload's Content-Type
+func newOrderDecoder(r *http.Request) (orderDecoder, error) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	switch mediaType {
+	case "text/csv":
+		return newCSVDecoder(r.Body)
+	case "application/x-ndjson":
+		s := bufio.NewScanner(r.Body)
+		s.Buffer(nil, maxImportLine)
+		return &ndjsonDecoder{s: s}, nil
+	default:
+		return nil, &runtime.HTTPStatusError{
+			HTTPStatus: http.StatusUnsupportedMediaType,
+			Err:        status.Error(codes.InvalidArgument, "uploads must be text/csv or application/x-ndjson"),
+		}
+	}
+}
+
+// ndjsonDecoder reads one order per line, in the JSON form of ImportOrder
+type ndjsonDecoder struct {
+	s    *bufio.Scanner
+	line int32
+}
+
+func (d *ndjsonDecoder) next() (*orderv1.ImportOrder, error) {
+	for d.s.Scan() {
+		d.line++
+		line := bytes.TrimSpace(d.s.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		order := &orderv1.ImportOrder{}
+		if err := protojson.Unmarshal(line, order); err != nil {
+			return nil, &rowError{row: d.line, message: "invalid order: " + err.Error()}
+		}
+		if order.Row == 0 {
+			order.Row = d.line
+		}
+		return order, nil
+	}
+	if err := d.s.Err(); err != nil {
+		return nil, err
+	}
+	return nil, io.EOF
+}
+
+// csvColumns are the columns of a CSV upload; the others are optional
+var csvColumns = []string{"user_id", "product_id", "quantity", "price"}
+
+// csvDecoder reads one order item per row, under a header row naming the
+// columns: ref, user_id, product_id, product_name, quantity, price, status
+// and created_at. Consecutive rows with the same ref are the items of one
+// order, whose user, status and creation time are taken from its first
+// row. Rows without a ref are orders of their own.
+type csvDecoder struct {
+	r        *csv.Reader
+	columns  map[string]int
+	buffered *csvRow
+}
+
+// csvRow is a row of a CSV upload
+type csvRow struct {
+	line   int32
+	fields []string
+	err    error
+}
+
+func newCSVDecoder(body io.Reader) (*csvDecoder, error) {
+	r := csv.NewReader(body)
+	header, err := r.Read()
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to read the CSV header: %v", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range csvColumns {
+		if _, ok := columns[name]; !ok {
+			return nil, status.Errorf(codes.InvalidArgument, "the CSV header has no %s column", name)
+		}
+	}
+	return &csvDecoder{r: r, columns: columns}, nil
+}
+
+func (d *csvDecoder) next() (*orderv1.ImportOrder, error) {
+	row, err := d.read()
+	if err != nil {
+		return nil, err
+	}
+
+	order := &orderv1.ImportOrder{Ref: d.field(row, "ref"), Row: row.line}
+	addErr := d.add(order, row)
+	for order.Ref != "" {
+		next, err := d.read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if d.field(next, "ref") != order.Ref {
+			d.buffered = next
+			break
+		}
+		if addErr == nil {
+			addErr = d.add(order, next)
+		}
+	}
+	if addErr != nil {
+		return nil, addErr
+	}
+	return order, nil
+}
+
+// read returns the next row. Malformed rows are returned with their error,
+// so that they fail only their order.
+func (d *csvDecoder) read() (*csvRow, error) {
+	if row := d.buffered; row != nil {
+		d.buffered = nil
+		return row, nil
+	}
+
+	fields, err := d.r.Read()
+	var parseErr *csv.ParseError
+	switch {
+	case err == nil:
+		line, _ := d.r.FieldPos(0)
+		return &csvRow{line: int32(line), fields: fields}, nil
+	case errors.As(err, &parseErr):
+		return &csvRow{line: int32(parseErr.StartLine), fields: fields, err: parseErr.Err}, nil
+	default:
+		return nil, err
+	}
+}
+
+// field returns the named column of row, or "" when there is none
+func (d *csvDecoder) field(row *csvRow, name string) string {
+	i, ok := d.columns[name]
+	if !ok || i >= len(row.fields) {
+		return ""
+	}
+	return strings.TrimSpace(row.fields[i])
+}
+
+// add adds the item on row to order, filling in the order from its first
+// row
+func (d *csvDecoder) add(order *orderv1.ImportOrder, row *csvRow) error {
+	fail := func(format string, args ...interface{}) error {
+		return &rowError{row: row.line, ref: order.Ref, message: fmt.Sprintf(format, args...)}
+	}
+	if row.err != nil {
+		return fail("%v", row.err)
+	}
+
+	if len(order.Items) == 0 {
+		order.UserId = d.field(row, "user_id")
+		if s := d.field(row, "status"); s != "" {
+			value, ok := orderv1.OrderStatus_value["ORDER_STATUS_"+strings.ToUpper(s)]
+			if !ok {
+				return fail("unknown status %q", s)
+			}
+			order.Status = orderv1.OrderStatus(value)
+		}
+		if s := d.field(row, "created_at"); s != "" {
+			createdAt, err := time.Parse(time.RFC3339, s)
+			if err != nil {
+				return fail("created_at %q is not an RFC 3339 time", s)
+			}
+			order.CreatedAt = timestamppb.New(createdAt)
+		}
+	} else if userID := d.field(row, "user_id"); userID != order.UserId {
+		return fail("user_id %q differs from the order's first row", userID)
+	}
+
+	quantity, err := strconv.ParseInt(d.field(row, "quantity"), 10, 32)
+	if err != nil {
+		return fail("quantity %q is not an integer", d.field(row, "quantity"))
+	}
+	price, err := strconv.ParseFloat(d.field(row, "price"), 64)
+	if err != nil {
+		return fail("price %q is not a number", d.field(row, "price"))
+	}
+	order.Items = append(order.Items, &orderv1.OrderItem{
+		ProductId:   d.field(row, "product_id"),
+		ProductName: d.field(row, "product_name"),
+		Quantity:    int32(quantity),
+		Price:       price,
+	})
+	return nil
+}
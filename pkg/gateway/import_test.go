@@ -0,0 +1,152 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	orderv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/order/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// mockImportClient accepts every order streamed to ImportOrders
+type mockImportClient struct {
+	orderv1.OrderServiceClient
+	orders []*orderv1.ImportOrder
+}
+
+func (m *mockImportClient) ImportOrders(ctx context.Context, opts ...grpc.CallOption) (orderv1.OrderService_ImportOrdersClient, error) {
+	return &mockImportStream{client: m}, nil
+}
+
+// mockImportStream collects the orders sent on it into its client
+type mockImportStream struct {
+	grpc.ClientStream
+	client *mockImportClient
+}
+
+func (s *mockImportStream) Send(req *orderv1.ImportOrdersRequest) error {
+	s.client.orders = append(s.client.orders, req.GetOrders()...)
+	return nil
+}
+
+func (s *mockImportStream) CloseAndRecv() (*orderv1.ImportOrdersResponse, error) {
+	return &orderv1.ImportOrdersResponse{Imported: int32(len(s.client.orders))}, nil
+}
+
+func TestImportOrders(t *testing.T) {
+	tests := []struct {
+		name          string
+		method        string
+		contentType   string
+		body          string
+		wantCode      int
+		wantOrders    []string // ref:user:item count of each order sent
+		wantErrorRows []int32
+	}{
+		{
+			name:        "csv groups rows by ref",
+			contentType: "text/csv",
+			body: "ref,user_id,product_id,product_name,quantity,price,status\n" +
+				"a,u1,p1,Mug,1,8,delivered\n" +
+				"a,u1,p2,Tea,2,4,\n" +
+				",u2,p1,Mug,1,8,\n" +
+				"b,u3,p1,Mug,one,8,\n" +
+				"b,u3,p2,Tea,1,4,\n" +
+				"c,u4,p1,Mug,1,8,lost\n",
+			wantCode:      http.StatusOK,
+			wantOrders:    []string{"a:u1:2", ":u2:1"},
+			wantErrorRows: []int32{5, 7},
+		},
+		{
+			name:          "csv row with a different user",
+			contentType:   "text/csv; charset=utf-8",
+			body:          "ref,user_id,product_id,quantity,price\na,u1,p1,1,8\na,u2,p2,1,4\n",
+			wantCode:      http.StatusOK,
+			wantErrorRows: []int32{3},
+		},
+		{
+			name:        "csv header without required columns",
+			contentType: "text/csv",
+			body:        "ref,user_id\na,u1\n",
+			wantCode:    http.StatusBadRequest,
+		},
+		{
+			name:        "ndjson",
+			contentType: "application/x-ndjson",
+			body: `{"ref":"a","userId":"u1","items":[{"productId":"p1","quantity":1,"price":8}]}` + "\n\n" +
+				`{"ref":"b",` + "\n" +
+				`{"ref":"c","user_id":"u2","items":[{"product_id":"p1","quantity":1,"price":8}]}` + "\n",
+			wantCode:      http.StatusOK,
+			wantOrders:    []string{"a:u1:1", "c:u2:1"},
+			wantErrorRows: []int32{3},
+		},
+		{name: "unsupported content type", contentType: "application/json", body: "{}", wantCode: http.StatusUnsupportedMediaType},
+		{name: "method not allowed", method: http.MethodGet, contentType: "text/csv", wantCode: http.StatusMethodNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orders := &mockImportClient{}
+			g := &Gateway{mux: runtime.NewServeMux(), orders: orders}
+
+			method := tt.method
+			if method == "" {
+				method = http.MethodPost
+			}
+			req := httptest.NewRequest(method, importPath, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			rec := httptest.NewRecorder()
+			g.composedMiddleware(http.NotFoundHandler()).ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d (%s)", rec.Code, tt.wantCode, rec.Body.String())
+			}
+			if rec.Code != http.StatusOK {
+				return
+			}
+
+			var got []string
+			for _, o := range orders.orders {
+				got = append(got, fmt.Sprintf("%s:%s:%d", o.GetRef(), o.GetUserId(), len(o.GetItems())))
+			}
+			if !reflect.DeepEqual(got, tt.wantOrders) {
+				t.Errorf("orders sent = %v, want %v", got, tt.wantOrders)
+			}
+
+			resp := &orderv1.ImportOrdersResponse{}
+			if err := protojson.Unmarshal(rec.Body.Bytes(), resp); err != nil {
+				t.Fatalf("failed to decode the response: %v", err)
+			}
+			var rows []int32
+			for _, e := range resp.GetErrors() {
+				rows = append(rows, e.GetRow())
+			}
+			if !reflect.DeepEqual(rows, tt.wantErrorRows) {
+				t.Errorf("error rows = %v, want %v", rows, tt.wantErrorRows)
+			}
+		})
+	}
+}
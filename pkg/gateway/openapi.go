@@ -0,0 +1,135 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package gateway
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	swaggerfiles "github.com/swaggo/files/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// The OpenAPI specs are generated from the protos by make proto
+//
+//go:embed openapi/openapi.yaml openapi/api.swagger.json
+var openAPIFiles embed.FS
+
+// Paths of the API documentation
+const (
+	openAPIPath   = "/openapi.json"
+	openAPIV2Path = "/openapi/v2.json"
+	docsPath      = "/docs"
+)
+
+// docsPage renders the OpenAPI v3 spec with Swagger UI
+const docsPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>API Documentation</title>
+  <link rel="stylesheet" href="` + docsPath + `/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="` + docsPath + `/swagger-ui-bundle.js"></script>
+  <script>
+    window.onload = () => {
+      window.ui = SwaggerUIBundle({url: "` + openAPIPath + `", dom_id: "#swagger-ui"});
+    };
+  </script>
+</body>
+</html>
+`
+
+// swaggerUIAssets are the files of the docs page served under docsPath.
+// They come from the swagger-ui-dist release embedded in the pinned
+// github.com/swaggo/files/v2 module, so the page loads no third-party
+// scripts.
+var swaggerUIAssets = map[string]bool{"swagger-ui.css": true, "swagger-ui-bundle.js": true}
+
+var swaggerUI = http.StripPrefix(docsPath+"/", http.FileServer(http.FS(swaggerfiles.FS)))
+
+// openAPISpecs are the OpenAPI v3 and v2 specs of the REST routes as JSON
+type openAPISpecs struct {
+	v3 []byte
+	v2 []byte
+}
+
+// loadOpenAPI reads the embedded specs. The v3 spec is generated as YAML
+// and served as JSON; the v2 spec gets the v3 spec's info, as the v2
+// generator takes it from whichever proto comes first.
+func loadOpenAPI() (*openAPISpecs, error) {
+	raw, err := openAPIFiles.ReadFile("openapi/openapi.yaml")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read OpenAPI v3 spec: %w", err)
+	}
+	var v3 map[string]interface{}
+	if err := yaml.Unmarshal(raw, &v3); err != nil {
+		return nil, fmt.Errorf("failed to parse OpenAPI v3 spec: %w", err)
+	}
+
+	raw, err = openAPIFiles.ReadFile("openapi/api.swagger.json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read OpenAPI v2 spec: %w", err)
+	}
+	var v2 map[string]interface{}
+	if err := json.Unmarshal(raw, &v2); err != nil {
+		return nil, fmt.Errorf("failed to parse OpenAPI v2 spec: %w", err)
+	}
+	v2["info"] = v3["info"]
+
+	specs := &openAPISpecs{}
+	if specs.v3, err = json.Marshal(v3); err != nil {
+		return nil, fmt.Errorf("failed to encode OpenAPI v3 spec: %w", err)
+	}
+	if specs.v2, err = json.Marshal(v2); err != nil {
+		return nil, fmt.Errorf("failed to encode OpenAPI v2 spec: %w", err)
+	}
+	return specs, nil
+}
+
+// docsMiddleware serves the OpenAPI specs and Swagger UI. Like the health
+// probes, they need no tenant or credentials.
+func (g *Gateway) docsMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			next.ServeHTTP(w, r)
+			return
+		}
+		switch r.URL.Path {
+		case openAPIPath:
+			w.Header().Set("Content-Type", "application/json")
+			w.Write(g.openAPI.v3)
+		case openAPIV2Path:
+			w.Header().Set("Content-Type", "application/json")
+			w.Write(g.openAPI.v2)
+		case docsPath:
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			w.Write([]byte(docsPage))
+		default:
+			if name, ok := strings.CutPrefix(r.URL.Path, docsPath+"/"); ok && swaggerUIAssets[name] {
+				swaggerUI.ServeHTTP(w, r)
+				return
+			}
+			next.ServeHTTP(w, r)
+		}
+	})
+}
@@ -0,0 +1,95 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package gateway
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDocsMiddleware(t *testing.T) {
+	g, err := New(Config{})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	h := g.docsMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}))
+
+	specs := map[string]string{openAPIPath: "openapi", openAPIV2Path: "swagger"}
+	var titles []string
+	for path, versionField := range specs {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+
+		var spec struct {
+			Info  struct{ Title string }
+			Paths map[string]interface{}
+		}
+		if w.Code != http.StatusOK || w.Header().Get("Content-Type") != "application/json" {
+			t.Fatalf("GET %s = %d %s, want JSON", path, w.Code, w.Header().Get("Content-Type"))
+		}
+		var raw map[string]interface{}
+		if err := json.Unmarshal(w.Body.Bytes(), &raw); err != nil || raw[versionField] == nil {
+			t.Fatalf("GET %s: %v, want a spec with %q", path, err, versionField)
+		}
+		json.Unmarshal(w.Body.Bytes(), &spec)
+		if spec.Paths["/v1/users"] == nil || spec.Paths["/v2/orders"] == nil {
+			t.Errorf("GET %s: paths lack the REST routes", path)
+		}
+		titles = append(titles, spec.Info.Title)
+	}
+	if titles[0] == "" || titles[0] != titles[1] {
+		t.Errorf("titles = %q, want the same title on both specs", titles)
+	}
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, docsPath, nil))
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `url: "`+openAPIPath+`"`) {
+		t.Errorf("GET %s = %d, want Swagger UI loading %s", docsPath, w.Code, openAPIPath)
+	}
+
+	if strings.Contains(w.Body.String(), "https://") {
+		t.Errorf("GET %s loads remote assets: %s", docsPath, w.Body.String())
+	}
+
+	assets := map[string]string{
+		docsPath + "/swagger-ui.css":       "text/css; charset=utf-8",
+		docsPath + "/swagger-ui-bundle.js": "text/javascript; charset=utf-8",
+	}
+	for path, contentType := range assets {
+		w = httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		if w.Code != http.StatusOK || w.Header().Get("Content-Type") != contentType || w.Body.Len() == 0 {
+			t.Errorf("GET %s = %d %s, want the embedded asset", path, w.Code, w.Header().Get("Content-Type"))
+		}
+	}
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, docsPath+"/index.html", nil))
+	if w.Code != http.StatusTeapot {
+		t.Errorf("GET %s/index.html = %d, want it passed on", docsPath, w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, openAPIPath, nil))
+	if w.Code != http.StatusTeapot {
+		t.Errorf("POST %s = %d, want it passed on", openAPIPath, w.Code)
+	}
+}
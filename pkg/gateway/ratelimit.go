@@ -0,0 +1,51 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package gateway
+
+import (
+	"net/http"
+
+	"github.com/kevindiu/monorepo-go-example/internal/apikey"
+	"github.com/kevindiu/monorepo-go-example/internal/middleware"
+	"github.com/kevindiu/monorepo-go-example/internal/tenant"
+)
+
+// rateLimitMiddleware throttles requests by client IP address, then by API
+// key for requests authenticated by one, and then by tenant, answering 429
+// Too Many Requests with a Retry-After header once any quota is spent
+func (g *Gateway) rateLimitMiddleware(next http.Handler) http.Handler {
+	byTenant := middleware.RateLimit(g.limiter, func(r *http.Request) string {
+		return tenant.ID(r.Context())
+	})
+	byAPIKey := middleware.RateLimit(g.keyLimiter, func(r *http.Request) string {
+		k, _ := apikey.FromContext(r.Context())
+		return k.ID
+	})
+	byClientIP := middleware.RateLimit(g.ipLimiter, func(r *http.Request) string {
+		return middleware.ClientIP(r, g.trustForwardedFor)
+	})
+
+	limited := byTenant(next)
+	keyed := byAPIKey(limited)
+	return byClientIP(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := apikey.FromContext(r.Context()); ok {
+			keyed.ServeHTTP(w, r)
+			return
+		}
+		limited.ServeHTTP(w, r)
+	}))
+}
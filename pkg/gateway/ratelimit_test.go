@@ -0,0 +1,147 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package gateway
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/apikey"
+	"github.com/kevindiu/monorepo-go-example/internal/config"
+	"github.com/kevindiu/monorepo-go-example/internal/ratelimit"
+	"github.com/kevindiu/monorepo-go-example/internal/tenant"
+)
+
+func TestRateLimitMiddleware(t *testing.T) {
+	limiter, err := ratelimit.New(&config.RateLimit{Requests: 1, Window: time.Minute}, nil, nil)
+	if err != nil {
+		t.Fatalf("ratelimit.New() error = %v", err)
+	}
+	g, err := New(Config{
+		RateLimiter: limiter,
+		Tenants:     tenant.NewResolver(&config.Tenancy{Tenants: []string{"acme", "globex"}}),
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	h := tenant.Middleware(g.tenants, g.rateLimitMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})))
+
+	tests := []struct {
+		tenant     string
+		wantStatus int
+	}{
+		{tenant: "acme", wantStatus: http.StatusOK},
+		{tenant: "acme", wantStatus: http.StatusTooManyRequests},
+		{tenant: "globex", wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodGet, "/v1/orders", nil)
+		r.Header.Set(tenant.Header, tt.tenant)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+
+		if w.Code != tt.wantStatus {
+			t.Errorf("%s: status = %d, want %d", tt.tenant, w.Code, tt.wantStatus)
+		}
+		if got := w.Header().Get("X-RateLimit-Limit"); got != "1" {
+			t.Errorf("%s: X-RateLimit-Limit = %q, want 1", tt.tenant, got)
+		}
+		if tt.wantStatus == http.StatusTooManyRequests && w.Header().Get("Retry-After") != "60" {
+			t.Errorf("%s: Retry-After = %q, want 60", tt.tenant, w.Header().Get("Retry-After"))
+		}
+	}
+}
+
+func TestRateLimitMiddlewareByClientIP(t *testing.T) {
+	cfg := &config.RateLimit{Requests: 10, Window: time.Minute, ClientIP: config.RateLimitBucket{Requests: 1, Window: time.Minute}}
+	limiter, err := ratelimit.New(cfg, nil, nil)
+	if err != nil {
+		t.Fatalf("ratelimit.New() error = %v", err)
+	}
+	g, err := New(Config{
+		RateLimiter:       limiter,
+		ClientIPLimiter:   ratelimit.ClientIP(limiter, cfg),
+		TrustForwardedFor: true,
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	h := tenant.Middleware(g.tenants, g.rateLimitMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})))
+
+	tests := []struct {
+		forwardedFor string
+		wantStatus   int
+	}{
+		{forwardedFor: "203.0.113.7", wantStatus: http.StatusOK},
+		{forwardedFor: "203.0.113.7", wantStatus: http.StatusTooManyRequests},
+		{forwardedFor: "203.0.113.8", wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodGet, "/v1/orders", nil)
+		r.Header.Set("X-Forwarded-For", tt.forwardedFor)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+
+		if w.Code != tt.wantStatus {
+			t.Errorf("%s: status = %d, want %d", tt.forwardedFor, w.Code, tt.wantStatus)
+		}
+	}
+}
+
+func TestRateLimitMiddlewareByAPIKey(t *testing.T) {
+	cfg := &config.RateLimit{Requests: 10, Window: time.Minute, APIKey: config.RateLimitBucket{Requests: 1, Window: time.Minute}}
+	limiter, err := ratelimit.New(cfg, nil, nil)
+	if err != nil {
+		t.Fatalf("ratelimit.New() error = %v", err)
+	}
+	g, err := New(Config{
+		RateLimiter:   limiter,
+		APIKeyLimiter: ratelimit.APIKey(limiter, cfg),
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	h := tenant.Middleware(g.tenants, g.rateLimitMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})))
+
+	tests := []struct {
+		name       string
+		key        string
+		wantStatus int
+	}{
+		{name: "key-1", key: "key-1", wantStatus: http.StatusOK},
+		{name: "key-1 again", key: "key-1", wantStatus: http.StatusTooManyRequests},
+		{name: "key-2", key: "key-2", wantStatus: http.StatusOK},
+		{name: "no key", wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodGet, "/v1/orders", nil)
+		if tt.key != "" {
+			r = r.WithContext(apikey.WithKey(r.Context(), &apikey.Key{ID: tt.key, TenantID: "default"}))
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+
+		if w.Code != tt.wantStatus {
+			t.Errorf("%s: status = %d, want %d", tt.name, w.Code, tt.wantStatus)
+		}
+	}
+}
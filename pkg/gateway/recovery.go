@@ -0,0 +1,66 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package gateway
+
+import (
+	"net/http"
+	"runtime/debug"
+
+	"google.golang.org/grpc/codes"
+
+	"github.com/kevindiu/monorepo-go-example/internal/log"
+	"github.com/kevindiu/monorepo-go-example/internal/middleware"
+	"github.com/kevindiu/monorepo-go-example/internal/reporting"
+)
+
+// recoveryMiddleware recovers from panics in the handlers it wraps, like
+// middleware.RecoveryInterceptor does for gRPC handlers. The panic is
+// logged with its stack and reported, and the caller gets a JSON 500. If
+// the response had already started, it is aborted instead, so the client
+// sees a broken response rather than a truncated one passing as complete.
+func (g *Gateway) recoveryMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec := &responseRecorder{ResponseWriter: w}
+		defer func() {
+			p := recover()
+			if p == nil {
+				return
+			}
+			// The handler asked for the response to be aborted
+			if p == http.ErrAbortHandler {
+				panic(p)
+			}
+			stack := debug.Stack()
+			// The request ID is assigned inside this middleware, so it is
+			// read back from the response
+			g.logger.Error("HTTP handler panicked",
+				log.String("request_id", w.Header().Get(middleware.RequestIDHeader)),
+				log.String("method", r.Method),
+				log.String("path", r.URL.Path),
+				log.Any("panic", p),
+				log.String("stack", string(stack)),
+			)
+			reporting.Default().CapturePanic(r.Context(), p, stack, map[string]string{"method": r.Method, "path": r.URL.Path})
+			if rec.status != 0 {
+				panic(http.ErrAbortHandler)
+			}
+			writeError(rec, codes.Internal, "internal server error")
+		}()
+
+		next.ServeHTTP(rec, r)
+	})
+}
@@ -0,0 +1,129 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package gateway
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/internal/log"
+	"github.com/kevindiu/monorepo-go-example/internal/middleware"
+)
+
+func TestRecoveryMiddleware(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	g, err := New(Config{Logger: &log.Logger{Logger: zap.New(core)}})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	h := g.recoveryMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/export" {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte("id,total\n"))
+		}
+		panic("boom")
+	}))
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/orders", nil))
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusInternalServerError)
+	}
+	if got := w.Header().Get("Content-Type"); got != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", got)
+	}
+	var body errors.HTTPBody
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode body: %v", err)
+	}
+	if body.Code != "INTERNAL" || body.Message != "internal server error" {
+		t.Errorf("body = %+v, want INTERNAL internal server error", body)
+	}
+	entries := logs.FilterMessage("HTTP handler panicked").AllUntimed()
+	if len(entries) != 1 {
+		t.Fatalf("logged %d panics, want 1", len(entries))
+	}
+	if stack, _ := entries[0].ContextMap()["stack"].(string); !strings.Contains(stack, "recoveryMiddleware") {
+		t.Errorf("stack = %q, want the panicking goroutine's stack", stack)
+	}
+
+	// A response already under way is aborted rather than passed off as
+	// complete
+	func() {
+		defer func() {
+			if p := recover(); p != http.ErrAbortHandler {
+				t.Errorf("recovered %v, want http.ErrAbortHandler", p)
+			}
+		}()
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/v1/export", nil))
+	}()
+	if n := logs.FilterMessage("HTTP handler panicked").Len(); n != 2 {
+		t.Errorf("logged %d panics, want 2", n)
+	}
+}
+
+func TestHandlerRecovers(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	g, err := New(Config{Logger: &log.Logger{Logger: zap.New(core)}})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if err := g.mux.HandlePath(http.MethodGet, "/v1/panic", func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+		panic("boom")
+	}); err != nil {
+		t.Fatalf("HandlePath() error = %v", err)
+	}
+
+	w := httptest.NewRecorder()
+	g.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/panic", nil))
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusInternalServerError)
+	}
+	if w.Header().Get(middleware.RequestIDHeader) == "" {
+		t.Error("500 response carries no request ID")
+	}
+	access := logs.FilterMessage("Request").AllUntimed()
+	if len(access) != 1 || access[0].ContextMap()["status"] != int64(http.StatusInternalServerError) {
+		t.Errorf("access lines = %v, want one with status 500", access)
+	}
+}
+
+func TestRecoveryMiddlewareAbort(t *testing.T) {
+	g, err := New(Config{})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	h := g.recoveryMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic(http.ErrAbortHandler)
+	}))
+
+	defer func() {
+		if p := recover(); p != http.ErrAbortHandler {
+			t.Errorf("recovered %v, want http.ErrAbortHandler", p)
+		}
+	}()
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/v1/orders", nil))
+}
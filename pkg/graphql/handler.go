@@ -0,0 +1,78 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package graphql
+
+import (
+	"net/http"
+
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/lru"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/99designs/gqlgen/graphql/playground"
+	orderv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/order/v1"
+	userv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/user/v1"
+	"github.com/kevindiu/monorepo-go-example/pkg/client"
+	"github.com/kevindiu/monorepo-go-example/pkg/graphql/generated"
+)
+
+// DefaultComplexityLimit bounds the cost of a single query
+const DefaultComplexityLimit = 500
+
+// Config holds GraphQL handler configuration
+type Config struct {
+	UserService  userv1.UserServiceClient
+	OrderService orderv1.OrderServiceClient
+	// ComplexityLimit rejects queries costing more; zero uses
+	// DefaultComplexityLimit
+	ComplexityLimit int
+	// Playground serves an interactive query editor on GET requests
+	// without a query, and enables introspection
+	Playground bool
+}
+
+// NewHandler creates an HTTP handler serving the graph. Requests must have
+// passed tenant resolution; their tenant, session and bearer token are
+// forwarded to the backends.
+func NewHandler(cfg Config) http.Handler {
+	if cfg.ComplexityLimit <= 0 {
+		cfg.ComplexityLimit = DefaultComplexityLimit
+	}
+
+	srv := handler.New(generated.NewExecutableSchema(generated.Config{
+		Resolvers: &Resolver{UserService: cfg.UserService, OrderService: cfg.OrderService},
+	}))
+	srv.AddTransport(transport.GET{})
+	srv.AddTransport(transport.POST{})
+	srv.SetQueryCache(lru.New(1000))
+	srv.Use(extension.FixedComplexityLimit(cfg.ComplexityLimit))
+	if cfg.Playground {
+		srv.Use(extension.Introspection{})
+	}
+
+	editor := playground.Handler("GraphQL", "/graphql")
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if cfg.Playground && r.Method == http.MethodGet && r.URL.Query().Get("query") == "" {
+			editor.ServeHTTP(w, r)
+			return
+		}
+
+		ctx := client.Forward(r.Context(), r)
+		ctx = WithLoaders(ctx, NewLoaders(cfg.UserService, cfg.OrderService))
+		srv.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
@@ -0,0 +1,162 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	orderv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/order/v1"
+	userv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/user/v1"
+	"github.com/kevindiu/monorepo-go-example/internal/tenant"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// mockUserService serves users from memory and counts lookups
+type mockUserService struct {
+	userv1.UserServiceClient
+	mu      sync.Mutex
+	users   map[string]*userv1.User
+	lookups map[string]int
+	batches int
+	tenants []string
+}
+
+func (m *mockUserService) BatchGetUsers(ctx context.Context, req *userv1.BatchGetUsersRequest, opts ...grpc.CallOption) (*userv1.BatchGetUsersResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.batches++
+	md, _ := metadata.FromOutgoingContext(ctx)
+	m.tenants = append(m.tenants, md.Get(tenant.MetadataKey)...)
+
+	resp := &userv1.BatchGetUsersResponse{}
+	for _, id := range req.GetIds() {
+		m.lookups[id]++
+		if u, ok := m.users[id]; ok {
+			resp.Users = append(resp.Users, u)
+		}
+	}
+	return resp, nil
+}
+
+// mockOrderService serves orders from memory; listed orders omit items
+type mockOrderService struct {
+	orderv1.OrderServiceClient
+	orders []*orderv1.Order
+}
+
+func (m *mockOrderService) ListOrders(ctx context.Context, req *orderv1.ListOrdersRequest, opts ...grpc.CallOption) (*orderv1.ListOrdersResponse, error) {
+	resp := &orderv1.ListOrdersResponse{}
+	for _, o := range m.orders {
+		if req.GetUserId() == "" || o.GetUserId() == req.GetUserId() {
+			resp.Orders = append(resp.Orders, &orderv1.Order{Id: o.GetId(), UserId: o.GetUserId(), Status: o.GetStatus()})
+		}
+	}
+	return resp, nil
+}
+
+func (m *mockOrderService) GetOrder(ctx context.Context, req *orderv1.GetOrderRequest, opts ...grpc.CallOption) (*orderv1.GetOrderResponse, error) {
+	for _, o := range m.orders {
+		if o.GetId() == req.GetId() {
+			return &orderv1.GetOrderResponse{Order: o}, nil
+		}
+	}
+	return nil, status.Error(codes.NotFound, "order not found")
+}
+
+func TestHandlerComposesOrdersAndUsers(t *testing.T) {
+	users := &mockUserService{
+		users: map[string]*userv1.User{
+			"u1": {Id: "u1", Name: "Alice"},
+			"u2": {Id: "u2", Name: "Bob"},
+		},
+		lookups: make(map[string]int),
+	}
+	orders := &mockOrderService{orders: []*orderv1.Order{
+		{Id: "o1", UserId: "u1", Status: orderv1.OrderStatus_ORDER_STATUS_PENDING, Items: []*orderv1.OrderItem{{Id: "i1", ProductId: "p1", Quantity: 2}}},
+		{Id: "o2", UserId: "u1", Status: orderv1.OrderStatus_ORDER_STATUS_SHIPPED},
+		{Id: "o3", UserId: "u2", Status: orderv1.OrderStatus_ORDER_STATUS_CONFIRMED},
+		{Id: "o4", UserId: "deleted", Status: orderv1.OrderStatus_ORDER_STATUS_CANCELLED},
+	}}
+	h := NewHandler(Config{UserService: users, OrderService: orders})
+
+	body := `{"query":"{ orders { orders { id status user { name } items { quantity product { id } } } } }"}`
+	req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req = req.WithContext(tenant.WithID(req.Context(), "acme"))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	var resp struct {
+		Data struct {
+			Orders struct {
+				Orders []struct {
+					ID     string
+					Status string
+					User   *struct{ Name string }
+					Items  []struct {
+						Quantity int
+						Product  struct{ ID string }
+					}
+				}
+			}
+		}
+		Errors []json.RawMessage
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to decode response %q: %v", rec.Body.String(), err)
+	}
+	if len(resp.Errors) > 0 {
+		t.Fatalf("query returned errors: %s", resp.Errors)
+	}
+
+	got := resp.Data.Orders.Orders
+	if len(got) != 4 {
+		t.Fatalf("got %d orders, want 4", len(got))
+	}
+	if got[0].User == nil || got[0].User.Name != "Alice" || got[2].User == nil || got[2].User.Name != "Bob" {
+		t.Errorf("orders resolved to the wrong users: %+v", got)
+	}
+	if got[3].User != nil {
+		t.Errorf("order of a missing user resolved user %+v, want null", got[3].User)
+	}
+	if got[0].Status != "PENDING" || len(got[0].Items) != 1 || got[0].Items[0].Product.ID != "p1" {
+		t.Errorf("order o1 = %+v", got[0])
+	}
+
+	for id, n := range users.lookups {
+		if n != 1 {
+			t.Errorf("user %s looked up %d times, want 1", id, n)
+		}
+	}
+	if users.batches != 1 {
+		t.Errorf("users fetched in %d calls, want 1", users.batches)
+	}
+	for _, id := range users.tenants {
+		if id != "acme" {
+			t.Errorf("backend call carried tenant %q, want acme", id)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package graphql
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Loader batching defaults
+const (
+	defaultLoaderWait     = 2 * time.Millisecond
+	defaultLoaderMaxBatch = 100
+)
+
+// BatchFunc fetches the values of keys. It returns one value and one error
+// per key, in key order.
+type BatchFunc[K comparable, V any] func(ctx context.Context, keys []K) ([]V, []error)
+
+// Loader coalesces the loads issued by concurrently resolving fields into
+// batches and caches their results for the lifetime of the loader, which is
+// one request
+type Loader[K comparable, V any] struct {
+	fetch    BatchFunc[K, V]
+	wait     time.Duration
+	maxBatch int
+
+	mu    sync.Mutex
+	cache map[K]*result[V]
+	batch *batch[K, V]
+}
+
+// result is the eventual outcome of loading one key
+type result[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+}
+
+// batch is a set of keys waiting to be fetched together
+type batch[K comparable, V any] struct {
+	keys    []K
+	results []*result[V]
+	full    chan struct{}
+}
+
+// NewLoader creates a loader that waits up to wait for more keys before
+// fetching at most maxBatch keys at once
+func NewLoader[K comparable, V any](fetch BatchFunc[K, V], wait time.Duration, maxBatch int) *Loader[K, V] {
+	if wait <= 0 {
+		wait = defaultLoaderWait
+	}
+	if maxBatch <= 0 {
+		maxBatch = defaultLoaderMaxBatch
+	}
+	return &Loader[K, V]{
+		fetch:    fetch,
+		wait:     wait,
+		maxBatch: maxBatch,
+		cache:    make(map[K]*result[V]),
+	}
+}
+
+// Load returns the value of key, fetching it in a batch with other keys
+// loaded around the same time
+func (l *Loader[K, V]) Load(ctx context.Context, key K) (V, error) {
+	l.mu.Lock()
+	res, ok := l.cache[key]
+	if !ok {
+		res = &result[V]{done: make(chan struct{})}
+		l.cache[key] = res
+		l.enqueue(ctx, key, res)
+	}
+	l.mu.Unlock()
+
+	select {
+	case <-res.done:
+		return res.value, res.err
+	case <-ctx.Done():
+		var zero V
+		return zero, ctx.Err()
+	}
+}
+
+// enqueue adds key to the pending batch, starting a new one if needed.
+// It must be called with l.mu held.
+func (l *Loader[K, V]) enqueue(ctx context.Context, key K, res *result[V]) {
+	if l.batch == nil {
+		l.batch = &batch[K, V]{full: make(chan struct{})}
+		go l.dispatch(ctx, l.batch)
+	}
+
+	b := l.batch
+	b.keys = append(b.keys, key)
+	b.results = append(b.results, res)
+	if len(b.keys) >= l.maxBatch {
+		l.batch = nil
+		close(b.full)
+	}
+}
+
+// dispatch fetches b once it is full or the wait has elapsed
+func (l *Loader[K, V]) dispatch(ctx context.Context, b *batch[K, V]) {
+	timer := time.NewTimer(l.wait)
+	defer timer.Stop()
+
+	select {
+	case <-b.full:
+	case <-timer.C:
+		l.mu.Lock()
+		if l.batch == b {
+			l.batch = nil
+		}
+		l.mu.Unlock()
+	}
+
+	values, errs := l.fetch(ctx, b.keys)
+	for i, res := range b.results {
+		if i < len(values) {
+			res.value = values[i]
+		}
+		if i < len(errs) {
+			res.err = errs[i]
+		}
+		close(res.done)
+	}
+}
@@ -0,0 +1,102 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package graphql
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLoaderBatchesConcurrentLoads(t *testing.T) {
+	var mu sync.Mutex
+	var batches [][]int
+	l := NewLoader(func(ctx context.Context, keys []int) ([]int, []error) {
+		mu.Lock()
+		batches = append(batches, keys)
+		mu.Unlock()
+		values := make([]int, len(keys))
+		for i, k := range keys {
+			values[i] = k * 10
+		}
+		return values, make([]error, len(keys))
+	}, 10*time.Millisecond, 3)
+
+	keys := []int{1, 2, 3, 4, 1}
+	got := make([]int, len(keys))
+	var wg sync.WaitGroup
+	for i, k := range keys {
+		wg.Add(1)
+		go func(i, k int) {
+			defer wg.Done()
+			v, err := l.Load(context.Background(), k)
+			if err != nil {
+				t.Errorf("Load(%d) error = %v", k, err)
+			}
+			got[i] = v
+		}(i, k)
+	}
+	wg.Wait()
+
+	for i, k := range keys {
+		if got[i] != k*10 {
+			t.Errorf("Load(%d) = %d, want %d", k, got[i], k*10)
+		}
+	}
+
+	// Four distinct keys with a batch size of three make two batches
+	fetched := 0
+	for _, b := range batches {
+		if len(b) > 3 {
+			t.Errorf("batch of %d keys exceeds max batch 3", len(b))
+		}
+		fetched += len(b)
+	}
+	if len(batches) != 2 || fetched != 4 {
+		t.Errorf("fetched %d keys in %d batches, want 4 keys in 2 batches", fetched, len(batches))
+	}
+}
+
+func TestLoaderCachesResults(t *testing.T) {
+	calls := 0
+	fetchErr := errors.New("boom")
+	l := NewLoader(func(ctx context.Context, keys []string) ([]string, []error) {
+		calls++
+		errs := make([]error, len(keys))
+		for i, k := range keys {
+			if k == "bad" {
+				errs[i] = fetchErr
+			}
+		}
+		return keys, errs
+	}, time.Millisecond, 0)
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		if v, err := l.Load(ctx, "a"); err != nil || v != "a" {
+			t.Errorf("Load(a) = %q, %v", v, err)
+		}
+		if _, err := l.Load(ctx, "bad"); !errors.Is(err, fetchErr) {
+			t.Errorf("Load(bad) error = %v, want %v", err, fetchErr)
+		}
+	}
+	if calls != 2 {
+		t.Errorf("fetch called %d times, want 2", calls)
+	}
+}
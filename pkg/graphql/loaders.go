@@ -0,0 +1,123 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package graphql
+
+import (
+	"context"
+	"sync"
+
+	orderv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/order/v1"
+	userv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/user/v1"
+	"github.com/kevindiu/monorepo-go-example/pkg/graphql/model"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxConcurrentFetches bounds the backend calls a single batch makes at once.
+// Backends without a batch lookup RPC get one call per key.
+const maxConcurrentFetches = 10
+
+// Loaders batches the lookups of one GraphQL request against the backends
+type Loaders struct {
+	Users  *Loader[string, *model.User]
+	Orders *Loader[string, *model.Order]
+}
+
+type loadersKey struct{}
+
+// NewLoaders creates loaders backed by the user and order services
+func NewLoaders(users userv1.UserServiceClient, orders orderv1.OrderServiceClient) *Loaders {
+	return &Loaders{
+		Users: NewLoader(func(ctx context.Context, ids []string) ([]*model.User, []error) {
+			return fetchUsers(ctx, users, ids)
+		}, 0, 0),
+		Orders: NewLoader(func(ctx context.Context, ids []string) ([]*model.Order, []error) {
+			return fetchAll(ctx, ids, func(ctx context.Context, id string) (*model.Order, error) {
+				resp, err := orders.GetOrder(ctx, &orderv1.GetOrderRequest{Id: id})
+				if err != nil {
+					return nil, err
+				}
+				return model.OrderFromProto(resp.GetOrder(), true), nil
+			})
+		}, 0, 0),
+	}
+}
+
+// WithLoaders returns a context carrying loaders
+func WithLoaders(ctx context.Context, l *Loaders) context.Context {
+	return context.WithValue(ctx, loadersKey{}, l)
+}
+
+// LoadersFromContext returns the loaders of the request
+func LoadersFromContext(ctx context.Context) *Loaders {
+	l, _ := ctx.Value(loadersKey{}).(*Loaders)
+	return l
+}
+
+// fetchUsers fetches a batch of users in one BatchGetUsers call. Users the
+// backend does not know resolve to nil.
+func fetchUsers(ctx context.Context, users userv1.UserServiceClient, ids []string) ([]*model.User, []error) {
+	values := make([]*model.User, len(ids))
+	errs := make([]error, len(ids))
+
+	resp, err := users.BatchGetUsers(ctx, &userv1.BatchGetUsersRequest{Ids: ids})
+	if err != nil {
+		for i := range errs {
+			errs[i] = err
+		}
+		return values, errs
+	}
+
+	byID := make(map[string]*userv1.User, len(resp.GetUsers()))
+	for _, u := range resp.GetUsers() {
+		byID[u.GetId()] = u
+	}
+	for i, id := range ids {
+		if u, ok := byID[id]; ok {
+			values[i] = model.UserFromProto(u)
+		}
+	}
+	return values, errs
+}
+
+// fetchAll fetches every key concurrently. Keys the backend does not know
+// resolve to nil rather than an error, so a dangling reference renders as
+// null instead of failing the query.
+func fetchAll[V any](ctx context.Context, keys []string, fetch func(ctx context.Context, key string) (*V, error)) ([]*V, []error) {
+	values := make([]*V, len(keys))
+	errs := make([]error, len(keys))
+
+	sem := make(chan struct{}, maxConcurrentFetches)
+	var wg sync.WaitGroup
+	for i, key := range keys {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, key string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			v, err := fetch(ctx, key)
+			if status.Code(err) == codes.NotFound {
+				return
+			}
+			values[i], errs[i] = v, err
+		}(i, key)
+	}
+	wg.Wait()
+
+	return values, errs
+}
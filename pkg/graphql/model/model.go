@@ -0,0 +1,153 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package model holds the types the GraphQL schema is bound to
+package model
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	orderv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/order/v1"
+	userv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/user/v1"
+)
+
+// User is a user of the user service
+type User struct {
+	ID        string
+	Email     string
+	Name      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// UserPage is a page of users
+type UserPage struct {
+	Users         []*User
+	NextPageToken *string
+}
+
+// Order is an order of the order service. Orders from list calls carry no
+// items; ItemsLoaded tells the resolver to fetch them.
+type Order struct {
+	ID          string
+	UserID      string
+	Status      OrderStatus
+	TotalAmount float64
+	Currency    string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	Items       []*OrderItem
+	Discounts   []*OrderDiscount
+	ItemsLoaded bool
+}
+
+// OrderPage is a page of orders
+type OrderPage struct {
+	Orders        []*Order
+	NextPageToken *string
+}
+
+// OrderItem is a line of an order
+type OrderItem struct {
+	ID        string
+	ProductID string
+	Quantity  int
+	Price     float64
+}
+
+// OrderDiscount is a coupon discount applied to an order
+type OrderDiscount struct {
+	Code   string
+	Amount float64
+}
+
+// Product is a product referenced by order items
+type Product struct {
+	ID string
+}
+
+// OrderStatus is the status of an order
+type OrderStatus string
+
+// MarshalGQL writes the status as a GraphQL enum value
+func (s OrderStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(string(s)))
+}
+
+// UnmarshalGQL reads the status from a GraphQL enum value
+func (s *OrderStatus) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("order status must be a string")
+	}
+	if _, ok := orderv1.OrderStatus_value["ORDER_STATUS_"+str]; !ok {
+		return fmt.Errorf("%s is not a valid OrderStatus", str)
+	}
+	*s = OrderStatus(str)
+	return nil
+}
+
+// UserFromProto converts a user service user
+func UserFromProto(u *userv1.User) *User {
+	return &User{
+		ID:        u.GetId(),
+		Email:     u.GetEmail(),
+		Name:      u.GetName(),
+		CreatedAt: u.GetCreatedAt().AsTime(),
+		UpdatedAt: u.GetUpdatedAt().AsTime(),
+	}
+}
+
+// OrderFromProto converts an order service order. withItems reports whether
+// the response carried the order's items.
+func OrderFromProto(o *orderv1.Order, withItems bool) *Order {
+	order := &Order{
+		ID:          o.GetId(),
+		UserID:      o.GetUserId(),
+		Status:      OrderStatus(strings.TrimPrefix(o.GetStatus().String(), "ORDER_STATUS_")),
+		TotalAmount: o.GetTotalAmount(),
+		Currency:    o.GetCurrency(),
+		CreatedAt:   o.GetCreatedAt().AsTime(),
+		UpdatedAt:   o.GetUpdatedAt().AsTime(),
+		Items:       make([]*OrderItem, len(o.GetItems())),
+		Discounts:   make([]*OrderDiscount, len(o.GetDiscounts())),
+		ItemsLoaded: withItems,
+	}
+	for i, item := range o.GetItems() {
+		order.Items[i] = &OrderItem{
+			ID:        item.GetId(),
+			ProductID: item.GetProductId(),
+			Quantity:  int(item.GetQuantity()),
+			Price:     item.GetPrice(),
+		}
+	}
+	for i, d := range o.GetDiscounts() {
+		order.Discounts[i] = &OrderDiscount{Code: d.GetCode(), Amount: d.GetAmount()}
+	}
+	return order
+}
+
+// StringPtr returns nil for an empty page token and a pointer to it otherwise
+func StringPtr(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
@@ -0,0 +1,81 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package graphql serves a GraphQL graph composed from the user and order
+// services
+package graphql
+
+//go:generate go run github.com/99designs/gqlgen generate
+
+import (
+	"context"
+
+	orderv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/order/v1"
+	userv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/user/v1"
+	"github.com/kevindiu/monorepo-go-example/pkg/graphql/model"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"google.golang.org/grpc/status"
+)
+
+// Resolver resolves the graph against the backend services
+type Resolver struct {
+	UserService  userv1.UserServiceClient
+	OrderService orderv1.OrderServiceClient
+}
+
+// listOrders lists a page of orders. The listed orders carry no items, so
+// their items resolve through the order loader.
+func (r *Resolver) listOrders(ctx context.Context, userID string, pageSize int, pageToken string) ([]*model.Order, string, error) {
+	resp, err := r.OrderService.ListOrders(ctx, &orderv1.ListOrdersRequest{
+		UserId:    userID,
+		PageSize:  int32(pageSize),
+		PageToken: pageToken,
+	})
+	if err != nil {
+		return nil, "", backendError(err)
+	}
+
+	orders := make([]*model.Order, len(resp.GetOrders()))
+	for i, o := range resp.GetOrders() {
+		orders[i] = model.OrderFromProto(o, false)
+	}
+	return orders, resp.GetNextPageToken(), nil
+}
+
+// backendError converts a backend gRPC error into a GraphQL error carrying
+// the gRPC code as its "code" extension
+func backendError(err error) error {
+	if err == nil {
+		return nil
+	}
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+	return &gqlerror.Error{
+		Message:    st.Message(),
+		Extensions: map[string]interface{}{"code": st.Code().String()},
+	}
+}
+
+// deref returns the value of an optional argument, or its zero value
+func deref[T any](v *T) T {
+	if v == nil {
+		var zero T
+		return zero
+	}
+	return *v
+}
@@ -0,0 +1,100 @@
+package graphql
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.40
+
+import (
+	"context"
+
+	userv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/user/v1"
+	"github.com/kevindiu/monorepo-go-example/pkg/graphql/generated"
+	"github.com/kevindiu/monorepo-go-example/pkg/graphql/model"
+)
+
+// User is the resolver for the user field.
+func (r *orderResolver) User(ctx context.Context, obj *model.Order) (*model.User, error) {
+	user, err := LoadersFromContext(ctx).Users.Load(ctx, obj.UserID)
+	return user, backendError(err)
+}
+
+// Items is the resolver for the items field.
+func (r *orderResolver) Items(ctx context.Context, obj *model.Order) ([]*model.OrderItem, error) {
+	if obj.ItemsLoaded {
+		return obj.Items, nil
+	}
+	order, err := LoadersFromContext(ctx).Orders.Load(ctx, obj.ID)
+	if err != nil || order == nil {
+		return nil, backendError(err)
+	}
+	return order.Items, nil
+}
+
+// Product is the resolver for the product field.
+func (r *orderItemResolver) Product(ctx context.Context, obj *model.OrderItem) (*model.Product, error) {
+	return &model.Product{ID: obj.ProductID}, nil
+}
+
+// User is the resolver for the user field.
+func (r *queryResolver) User(ctx context.Context, id string) (*model.User, error) {
+	user, err := LoadersFromContext(ctx).Users.Load(ctx, id)
+	return user, backendError(err)
+}
+
+// Users is the resolver for the users field.
+func (r *queryResolver) Users(ctx context.Context, pageSize *int, pageToken *string) (*model.UserPage, error) {
+	resp, err := r.UserService.ListUsers(ctx, &userv1.ListUsersRequest{
+		PageSize:  int32(deref(pageSize)),
+		PageToken: deref(pageToken),
+	})
+	if err != nil {
+		return nil, backendError(err)
+	}
+
+	page := &model.UserPage{
+		Users:         make([]*model.User, len(resp.GetUsers())),
+		NextPageToken: model.StringPtr(resp.GetNextPageToken()),
+	}
+	for i, u := range resp.GetUsers() {
+		page.Users[i] = model.UserFromProto(u)
+	}
+	return page, nil
+}
+
+// Order is the resolver for the order field.
+func (r *queryResolver) Order(ctx context.Context, id string) (*model.Order, error) {
+	order, err := LoadersFromContext(ctx).Orders.Load(ctx, id)
+	return order, backendError(err)
+}
+
+// Orders is the resolver for the orders field.
+func (r *queryResolver) Orders(ctx context.Context, userID *string, pageSize *int, pageToken *string) (*model.OrderPage, error) {
+	orders, next, err := r.listOrders(ctx, deref(userID), deref(pageSize), deref(pageToken))
+	if err != nil {
+		return nil, err
+	}
+	return &model.OrderPage{Orders: orders, NextPageToken: model.StringPtr(next)}, nil
+}
+
+// Orders is the resolver for the orders field.
+func (r *userResolver) Orders(ctx context.Context, obj *model.User, pageSize *int) ([]*model.Order, error) {
+	orders, _, err := r.listOrders(ctx, obj.ID, deref(pageSize), "")
+	return orders, err
+}
+
+// Order returns generated.OrderResolver implementation.
+func (r *Resolver) Order() generated.OrderResolver { return &orderResolver{r} }
+
+// OrderItem returns generated.OrderItemResolver implementation.
+func (r *Resolver) OrderItem() generated.OrderItemResolver { return &orderItemResolver{r} }
+
+// Query returns generated.QueryResolver implementation.
+func (r *Resolver) Query() generated.QueryResolver { return &queryResolver{r} }
+
+// User returns generated.UserResolver implementation.
+func (r *Resolver) User() generated.UserResolver { return &userResolver{r} }
+
+type orderResolver struct{ *Resolver }
+type orderItemResolver struct{ *Resolver }
+type queryResolver struct{ *Resolver }
+type userResolver struct{ *Resolver }
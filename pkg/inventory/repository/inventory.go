@@ -0,0 +1,328 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kevindiu/monorepo-go-example/internal/db"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/internal/tenant"
+	"github.com/lib/pq"
+)
+
+// Reservation statuses
+const (
+	StatusReserved  = "reserved"
+	StatusCommitted = "committed"
+	StatusReleased  = "released"
+)
+
+// Stock is the inventory of a product. Available excludes the units
+// reserved by orders that are not confirmed yet.
+type Stock struct {
+	TenantID  string
+	ProductID string
+	Available int
+	Reserved  int
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Item is an order item to reserve stock for
+type Item struct {
+	OrderItemID string
+	ProductID   string
+	Quantity    int32
+}
+
+// Repository defines the inventory repository interface. Every operation is
+// scoped to the tenant on the context. Products without stock are not
+// tracked, and orders for them reserve nothing.
+type Repository interface {
+	Get(ctx context.Context, productID string) (*Stock, error)
+	List(ctx context.Context, limit, offset int) ([]*Stock, error)
+	Set(ctx context.Context, productID string, available int) (*Stock, error)
+}
+
+type repository struct {
+	db *db.DB
+}
+
+// New creates a new inventory repository
+func New(database *db.DB) Repository {
+	return &repository{db: database}
+}
+
+const stockColumns = `tenant_id, product_id, available, reserved, created_at, updated_at`
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanStock(s scanner) (*Stock, error) {
+	var stock Stock
+	err := s.Scan(
+		&stock.TenantID,
+		&stock.ProductID,
+		&stock.Available,
+		&stock.Reserved,
+		&stock.CreatedAt,
+		&stock.UpdatedAt,
+	)
+	return &stock, err
+}
+
+// Get retrieves the stock of a product
+func (r *repository) Get(ctx context.Context, productID string) (*Stock, error) {
+	query := `SELECT ` + stockColumns + ` FROM inventory WHERE tenant_id = $1 AND product_id = $2`
+
+	stock, err := scanStock(r.db.Executor(ctx).QueryRowContext(ctx, query, tenant.ID(ctx), productID))
+	if err == sql.ErrNoRows {
+		return nil, errors.WithCode(errors.New("product is not tracked"), errors.CodeNotFound)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get stock")
+	}
+	return stock, nil
+}
+
+// List retrieves the stock of tracked products, by product ID
+func (r *repository) List(ctx context.Context, limit, offset int) ([]*Stock, error) {
+	query := `
+		SELECT ` + stockColumns + `
+		FROM inventory
+		WHERE tenant_id = $1
+		ORDER BY product_id
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.db.Executor(ctx).QueryContext(ctx, query, tenant.ID(ctx), limit, offset)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list stock")
+	}
+	defer rows.Close()
+
+	var stocks []*Stock
+	for rows.Next() {
+		stock, err := scanStock(rows)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to scan stock")
+		}
+		stocks = append(stocks, stock)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "error iterating stock")
+	}
+
+	return stocks, nil
+}
+
+// Set sets the available stock of a product, tracking it from now on.
+// Units reserved by orders are kept.
+func (r *repository) Set(ctx context.Context, productID string, available int) (*Stock, error) {
+	if available < 0 {
+		return nil, errors.WithCode(errors.New("available stock cannot be negative"), errors.CodeInvalidInput)
+	}
+
+	query := `
+		INSERT INTO inventory (tenant_id, product_id, available, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $4)
+		ON CONFLICT (tenant_id, product_id) DO UPDATE SET available = EXCLUDED.available
+		RETURNING ` + stockColumns
+
+	stock, err := scanStock(r.db.Executor(ctx).QueryRowContext(ctx, query, tenant.ID(ctx), productID, available, time.Now()))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to set stock")
+	}
+	return stock, nil
+}
+
+// Reserve holds stock for the items of an order until expiresAt. Call it
+// inside the transaction that creates the items, so that an order is only
+// placed when its stock is held. Items of untracked products are skipped.
+func Reserve(ctx context.Context, tx db.Executor, orderID string, items []Item, expiresAt time.Time) error {
+	// Lock stock in product order, so concurrent orders cannot deadlock
+	sorted := append([]Item(nil), items...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].ProductID < sorted[j].ProductID })
+
+	tenantID := tenant.ID(ctx)
+	now := time.Now()
+	for _, item := range sorted {
+		var available int
+		err := tx.QueryRowContext(ctx,
+			`SELECT available FROM inventory WHERE tenant_id = $1 AND product_id = $2 FOR UPDATE`,
+			tenantID, item.ProductID,
+		).Scan(&available)
+		if err == sql.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			return errors.Wrap(err, "failed to lock stock")
+		}
+		if available < int(item.Quantity) {
+			return errors.WithCode(errors.Newf("insufficient stock for product %s", item.ProductID), errors.CodeInvalidInput)
+		}
+
+		_, err = tx.ExecContext(ctx,
+			`UPDATE inventory SET available = available - $1, reserved = reserved + $1 WHERE tenant_id = $2 AND product_id = $3`,
+			item.Quantity, tenantID, item.ProductID,
+		)
+		if err != nil {
+			return errors.Wrap(err, "failed to reserve stock")
+		}
+
+		query := `
+			INSERT INTO inventory_reservations (id, tenant_id, order_id, order_item_id, product_id, quantity, status, expires_at, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $9)
+		`
+		_, err = tx.ExecContext(ctx, query,
+			uuid.New().String(),
+			tenantID,
+			orderID,
+			item.OrderItemID,
+			item.ProductID,
+			item.Quantity,
+			StatusReserved,
+			expiresAt,
+			now,
+		)
+		if err != nil {
+			return errors.Wrap(err, "failed to record reservation")
+		}
+	}
+
+	return nil
+}
+
+// Commit takes the stock reserved for an order out of the inventory for
+// good, once the order is confirmed
+func Commit(ctx context.Context, tx db.Executor, orderID string) error {
+	reservations, err := lockReservations(ctx, tx, orderID, nil)
+	if err != nil {
+		return err
+	}
+
+	var ids []string
+	for _, res := range reservations {
+		if res.status != StatusReserved {
+			continue
+		}
+		_, err := tx.ExecContext(ctx,
+			`UPDATE inventory SET reserved = reserved - $1 WHERE tenant_id = $2 AND product_id = $3`,
+			res.quantity, res.tenantID, res.productID,
+		)
+		if err != nil {
+			return errors.Wrap(err, "failed to commit stock")
+		}
+		ids = append(ids, res.id)
+	}
+
+	return setStatus(ctx, tx, ids, StatusCommitted)
+}
+
+// Release returns the stock held for an order to the inventory, whether it
+// is still reserved or was committed. With itemIDs, only the stock of those
+// order items is released. Orders are not scoped to the tenant on the
+// context, so that maintenance jobs can release stock in every tenant.
+func Release(ctx context.Context, tx db.Executor, orderID string, itemIDs []string) error {
+	reservations, err := lockReservations(ctx, tx, orderID, itemIDs)
+	if err != nil {
+		return err
+	}
+
+	ids := make([]string, len(reservations))
+	for i, res := range reservations {
+		held := 0
+		if res.status == StatusReserved {
+			held = res.quantity
+		}
+		_, err := tx.ExecContext(ctx,
+			`UPDATE inventory SET available = available + $1, reserved = reserved - $2 WHERE tenant_id = $3 AND product_id = $4`,
+			res.quantity, held, res.tenantID, res.productID,
+		)
+		if err != nil {
+			return errors.Wrap(err, "failed to release stock")
+		}
+		ids[i] = res.id
+	}
+
+	return setStatus(ctx, tx, ids, StatusReleased)
+}
+
+// reservation is stock held for an order item
+type reservation struct {
+	id        string
+	tenantID  string
+	productID string
+	quantity  int
+	status    string
+}
+
+// lockReservations locks the reservations of an order that were not
+// released, optionally only those of itemIDs, in product order
+func lockReservations(ctx context.Context, tx db.Executor, orderID string, itemIDs []string) ([]*reservation, error) {
+	query := `
+		SELECT id, tenant_id, product_id, quantity, status
+		FROM inventory_reservations
+		WHERE order_id = $1 AND status <> $2
+		  AND ($3::uuid[] IS NULL OR order_item_id = ANY($3::uuid[]))
+		ORDER BY product_id
+		FOR UPDATE
+	`
+
+	rows, err := tx.QueryContext(ctx, query, orderID, StatusReleased, pq.Array(itemIDs))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to lock reservations")
+	}
+	defer rows.Close()
+
+	var reservations []*reservation
+	for rows.Next() {
+		var res reservation
+		if err := rows.Scan(&res.id, &res.tenantID, &res.productID, &res.quantity, &res.status); err != nil {
+			return nil, errors.Wrap(err, "failed to scan reservation")
+		}
+		reservations = append(reservations, &res)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "error iterating reservations")
+	}
+
+	return reservations, nil
+}
+
+func setStatus(ctx context.Context, tx db.Executor, ids []string, status string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	_, err := tx.ExecContext(ctx,
+		`UPDATE inventory_reservations SET status = $1 WHERE id = ANY($2::uuid[])`,
+		status, pq.Array(ids),
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to update reservations")
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package events defines the order domain events published on the event bus
+package events
+
+import "time"
+
+// Topic is the event bus topic carrying order events
+const Topic = "orders"
+
+// Order event types
+const (
+	TypeOrderCreated       = "order.created"
+	TypeOrderStatusChanged = "order.status_changed"
+	TypeOrderDeleted       = "order.deleted"
+)
+
+// Item is an order line in an event payload
+type Item struct {
+	ProductID   string `json:"product_id"`
+	ProductName string `json:"product_name,omitempty"`
+	Quantity    int32  `json:"quantity"`
+	// PriceMinor is the unit price in the minor units of the order currency
+	PriceMinor int64 `json:"price_minor"`
+}
+
+// Order is the payload of every order event. Deleted events only carry the ID.
+type Order struct {
+	ID     string `json:"id"`
+	UserID string `json:"user_id,omitempty"`
+	Status string `json:"status,omitempty"`
+	// TotalMinor is the total in the minor units of Currency
+	TotalMinor int64     `json:"total_minor,omitempty"`
+	Currency   string    `json:"currency,omitempty"`
+	Items      []Item    `json:"items,omitempty"`
+	CreatedAt  time.Time `json:"created_at,omitempty"`
+	UpdatedAt  time.Time `json:"updated_at,omitempty"`
+}
@@ -0,0 +1,48 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/log"
+	"github.com/kevindiu/monorepo-go-example/internal/scheduler"
+	"github.com/kevindiu/monorepo-go-example/pkg/order/repository"
+)
+
+// PendingOrderExpiry is the name of the pending order expiry job
+const PendingOrderExpiry = "pending-order-expiry"
+
+// NewPendingOrderExpiry returns a job that cancels orders left pending for longer than ttl
+func NewPendingOrderExpiry(repo repository.Repository, schedule string, ttl time.Duration, logger *log.Logger) scheduler.Job {
+	return scheduler.Job{
+		Name:     PendingOrderExpiry,
+		Schedule: schedule,
+		Timeout:  time.Minute,
+		Run: func(ctx context.Context) error {
+			expired, err := repo.ExpirePending(ctx, time.Now().Add(-ttl))
+			if err != nil {
+				return err
+			}
+			if len(expired) > 0 {
+				logger.Info("Expired pending orders", log.Int("count", len(expired)), log.Any("ttl", ttl))
+			}
+			return nil
+		},
+	}
+}
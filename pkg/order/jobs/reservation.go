@@ -0,0 +1,49 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/log"
+	"github.com/kevindiu/monorepo-go-example/internal/scheduler"
+	"github.com/kevindiu/monorepo-go-example/pkg/order/repository"
+)
+
+// ReservationExpiry is the name of the stock reservation expiry job
+const ReservationExpiry = "reservation-expiry"
+
+// NewReservationExpiry returns a job that cancels pending orders whose stock
+// reservation lapsed, returning their stock to the inventory
+func NewReservationExpiry(repo repository.Repository, schedule string, logger *log.Logger) scheduler.Job {
+	return scheduler.Job{
+		Name:     ReservationExpiry,
+		Schedule: schedule,
+		Timeout:  time.Minute,
+		Run: func(ctx context.Context) error {
+			expired, err := repo.ExpireReservations(ctx, time.Now())
+			if err != nil {
+				return err
+			}
+			if len(expired) > 0 {
+				logger.Info("Cancelled orders with lapsed reservations", log.Int("count", len(expired)))
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,208 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/cache"
+	"github.com/kevindiu/monorepo-go-example/internal/db"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/internal/log"
+	"github.com/kevindiu/monorepo-go-example/internal/tenant"
+)
+
+// cachedOrder is a cache entry of GetByID: an order with its items
+type cachedOrder struct {
+	Order *Order
+	Items []*OrderItem
+}
+
+// cachedRepository serves Get and GetByID from a cache in front of a
+// repository. A cache failure falls through to the repository, so the
+// cache can be lost without failing lookups.
+type cachedRepository struct {
+	Repository
+	cache  cache.Cache
+	ttl    time.Duration
+	logger *log.Logger
+}
+
+// NewCached caches order lookups of repo in c for ttl. UpdateStatus,
+// UpdateItems, Delete, ExpirePending and ExpireReservations invalidate the
+// entries of the orders they change. Writers outside the repository, such
+// as data retention, call Invalidate. Inside a db.TxManager transaction,
+// lookups bypass the cache and invalidation waits for the commit, so
+// uncommitted orders are never cached.
+func NewCached(repo Repository, c cache.Cache, ttl time.Duration, logger *log.Logger) Repository {
+	if logger == nil {
+		logger = log.NewDefault()
+	}
+	return &cachedRepository{Repository: repo, cache: c, ttl: ttl, logger: logger}
+}
+
+// Get retrieves an order without its items, from the cache when possible
+func (r *cachedRepository) Get(ctx context.Context, id string) (*Order, error) {
+	entry, err := r.lookup(ctx, orderKey(ctx, id), func() (*cachedOrder, error) {
+		order, err := r.Repository.Get(ctx, id)
+		return &cachedOrder{Order: order}, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entry.Order, nil
+}
+
+// GetByID retrieves an order with its items, from the cache when possible
+func (r *cachedRepository) GetByID(ctx context.Context, id string) (*Order, []*OrderItem, error) {
+	entry, err := r.lookup(ctx, orderItemsKey(ctx, id), func() (*cachedOrder, error) {
+		order, items, err := r.Repository.GetByID(ctx, id)
+		return &cachedOrder{Order: order, Items: items}, err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return entry.Order, entry.Items, nil
+}
+
+// UpdateStatus updates the order status and invalidates its entries
+func (r *cachedRepository) UpdateStatus(ctx context.Context, id, status string) error {
+	if err := r.Repository.UpdateStatus(ctx, id, status); err != nil {
+		return err
+	}
+	return r.invalidate(ctx, id)
+}
+
+// UpdateItems changes the items of an order and invalidates its entries
+func (r *cachedRepository) UpdateItems(ctx context.Context, id string, add []*OrderItem, removeIDs []string) error {
+	if err := r.Repository.UpdateItems(ctx, id, add, removeIDs); err != nil {
+		return err
+	}
+	return r.invalidate(ctx, id)
+}
+
+// Delete deletes an order and invalidates its entries
+func (r *cachedRepository) Delete(ctx context.Context, id string) error {
+	if err := r.Repository.Delete(ctx, id); err != nil {
+		return err
+	}
+	return r.invalidate(ctx, id)
+}
+
+// ExpirePending cancels stale pending orders and invalidates their entries
+func (r *cachedRepository) ExpirePending(ctx context.Context, createdBefore time.Time) ([]Expired, error) {
+	expired, err := r.Repository.ExpirePending(ctx, createdBefore)
+	if err != nil {
+		return nil, err
+	}
+	return expired, r.invalidateExpired(ctx, expired)
+}
+
+// ExpireReservations cancels orders with lapsed reservations and
+// invalidates their entries
+func (r *cachedRepository) ExpireReservations(ctx context.Context, now time.Time) ([]Expired, error) {
+	expired, err := r.Repository.ExpireReservations(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+	return expired, r.invalidateExpired(ctx, expired)
+}
+
+// invalidateExpired removes the entries of orders cancelled across tenants
+func (r *cachedRepository) invalidateExpired(ctx context.Context, expired []Expired) error {
+	return db.AfterCommit(ctx, func() error {
+		for _, e := range expired {
+			if err := Invalidate(ctx, r.cache, e.TenantID, e.ID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// lookup returns the entry cached under key, or loads and caches it
+func (r *cachedRepository) lookup(ctx context.Context, key string, load func() (*cachedOrder, error)) (*cachedOrder, error) {
+	// A transaction may read orders it changed, which must not be served
+	// from or written to the cache before they commit
+	if db.InTransaction(ctx) {
+		return load()
+	}
+
+	data, err := r.cache.Get(ctx, key)
+	if err == nil {
+		var entry cachedOrder
+		if err := json.Unmarshal(data, &entry); err == nil && entry.Order != nil {
+			return &entry, nil
+		}
+		r.logger.Warn("Discarding undecodable cached order", log.String("key", key))
+	} else if errors.GetCode(err) != errors.CodeNotFound {
+		r.logger.Warn("Order cache unavailable", log.String("key", key), log.Error(err))
+	}
+
+	entry, err := load()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err = json.Marshal(entry)
+	if err == nil {
+		err = r.cache.Set(ctx, key, data, r.ttl)
+	}
+	if err != nil {
+		r.logger.Warn("Failed to cache order", log.String("key", key), log.Error(err))
+	}
+	return entry, nil
+}
+
+// invalidate removes the entries of an order once its change commits.
+// Unlike a failed fill, a failed invalidation is returned: the cache would
+// otherwise serve the old order until it expires.
+func (r *cachedRepository) invalidate(ctx context.Context, id string) error {
+	return db.AfterCommit(ctx, func() error {
+		return Invalidate(ctx, r.cache, tenant.ID(ctx), id)
+	})
+}
+
+// Invalidate removes the entries cached by NewCached in c for orders of a
+// tenant, for code that changes orders without going through the cached
+// repository
+func Invalidate(ctx context.Context, c cache.Cache, tenantID string, ids ...string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	ctx = tenant.WithID(ctx, tenantID)
+	keys := make([]string, 0, 2*len(ids))
+	for _, id := range ids {
+		keys = append(keys, orderKey(ctx, id), orderItemsKey(ctx, id))
+	}
+	if err := c.Delete(ctx, keys...); err != nil {
+		return errors.Wrap(err, "failed to invalidate cached order")
+	}
+	return nil
+}
+
+// orderKey and orderItemsKey scope entries to the tenant on the context,
+// like the queries they cache
+func orderKey(ctx context.Context, id string) string {
+	return "order:" + tenant.ID(ctx) + ":id:" + id
+}
+
+func orderItemsKey(ctx context.Context, id string) string {
+	return "order:" + tenant.ID(ctx) + ":items:" + id
+}
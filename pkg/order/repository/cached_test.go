@@ -0,0 +1,208 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/cache"
+	"github.com/kevindiu/monorepo-go-example/internal/log"
+	"github.com/kevindiu/monorepo-go-example/internal/tenant"
+)
+
+// fakeRepository keeps orders in memory and counts lookups
+type fakeRepository struct {
+	Repository
+	orders  map[string]*Order
+	items   map[string][]*OrderItem
+	lookups int
+}
+
+func (f *fakeRepository) Get(ctx context.Context, id string) (*Order, error) {
+	f.lookups++
+	if o, ok := f.orders[id]; ok {
+		copied := *o
+		return &copied, nil
+	}
+	return nil, errors.New("order not found")
+}
+
+func (f *fakeRepository) GetByID(ctx context.Context, id string) (*Order, []*OrderItem, error) {
+	order, err := f.Get(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	return order, f.items[id], nil
+}
+
+func (f *fakeRepository) UpdateStatus(ctx context.Context, id, status string) error {
+	f.orders[id].Status = status
+	return nil
+}
+
+func (f *fakeRepository) UpdateItems(ctx context.Context, id string, add []*OrderItem, removeIDs []string) error {
+	f.items[id] = append(f.items[id], add...)
+	return nil
+}
+
+func (f *fakeRepository) Delete(ctx context.Context, id string) error {
+	delete(f.orders, id)
+	return nil
+}
+
+func (f *fakeRepository) ExpirePending(ctx context.Context, createdBefore time.Time) ([]Expired, error) {
+	var expired []Expired
+	for id, o := range f.orders {
+		if o.Status == "pending" {
+			o.Status = "cancelled"
+			expired = append(expired, Expired{TenantID: "acme", ID: id})
+		}
+	}
+	return expired, nil
+}
+
+// failingCache fails every operation
+type failingCache struct{ cache.Cache }
+
+func (failingCache) Get(context.Context, string) ([]byte, error) { return nil, errors.New("down") }
+
+func (failingCache) Set(context.Context, string, []byte, time.Duration) error {
+	return errors.New("down")
+}
+
+func (failingCache) Delete(context.Context, ...string) error { return errors.New("down") }
+
+func newFakeRepository() *fakeRepository {
+	return &fakeRepository{
+		orders: map[string]*Order{"o1": {ID: "o1", UserID: "u1", Status: "pending", TotalAmount: 1500, Currency: "USD"}},
+		items:  map[string][]*OrderItem{"o1": {{ID: "i1", OrderID: "o1", ProductID: "p1", Quantity: 1, Price: 1500}}},
+	}
+}
+
+func TestCachedRepositoryLookups(t *testing.T) {
+	fake := newFakeRepository()
+	repo := NewCached(fake, cache.NewMemory(100), time.Minute, log.NewDefault())
+	ctx := tenant.WithID(context.Background(), "acme")
+
+	for i := 0; i < 3; i++ {
+		if _, err := repo.Get(ctx, "o1"); err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		order, items, err := repo.GetByID(ctx, "o1")
+		if err != nil {
+			t.Fatalf("GetByID() error = %v", err)
+		}
+		if order.TotalAmount != 1500 || len(items) != 1 || items[0].ProductID != "p1" {
+			t.Errorf("GetByID() = %+v, %v, want the order with its item", order, items)
+		}
+	}
+	if fake.lookups != 2 {
+		t.Errorf("repository lookups = %d, want 2", fake.lookups)
+	}
+
+	// Entries are scoped to the tenant
+	if _, err := repo.Get(tenant.WithID(context.Background(), "globex"), "o1"); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if fake.lookups != 3 {
+		t.Errorf("repository lookups = %d, want another tenant to miss", fake.lookups)
+	}
+
+	// Misses are not cached
+	for i := 0; i < 2; i++ {
+		if _, err := repo.Get(ctx, "missing"); err == nil {
+			t.Fatal("Get() should fail for a missing order")
+		}
+	}
+	if fake.lookups != 5 {
+		t.Errorf("repository lookups = %d, want misses to reach the repository", fake.lookups)
+	}
+}
+
+func TestCachedRepositoryInvalidates(t *testing.T) {
+	fake := newFakeRepository()
+	repo := NewCached(fake, cache.NewMemory(100), time.Minute, log.NewDefault())
+	ctx := context.Background()
+
+	repo.Get(ctx, "o1")
+	repo.GetByID(ctx, "o1")
+
+	if err := repo.UpdateStatus(ctx, "o1", "confirmed"); err != nil {
+		t.Fatalf("UpdateStatus() error = %v", err)
+	}
+	if o, err := repo.Get(ctx, "o1"); err != nil || o.Status != "confirmed" {
+		t.Errorf("Get() = %v, %v after status update, want the new status", o, err)
+	}
+
+	if err := repo.UpdateItems(ctx, "o1", []*OrderItem{{ID: "i2", OrderID: "o1", ProductID: "p2", Quantity: 1}}, nil); err != nil {
+		t.Fatalf("UpdateItems() error = %v", err)
+	}
+	if _, items, err := repo.GetByID(ctx, "o1"); err != nil || len(items) != 2 {
+		t.Errorf("GetByID() = %v, %v after item update, want 2 items", items, err)
+	}
+
+	if err := repo.Delete(ctx, "o1"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := repo.Get(ctx, "o1"); err == nil {
+		t.Error("Get() served a deleted order")
+	}
+	if _, _, err := repo.GetByID(ctx, "o1"); err == nil {
+		t.Error("GetByID() served a deleted order")
+	}
+}
+
+func TestCachedRepositoryInvalidatesExpired(t *testing.T) {
+	fake := newFakeRepository()
+	c := cache.NewMemory(100)
+	repo := NewCached(fake, c, time.Minute, log.NewDefault())
+	ctx := tenant.WithID(context.Background(), "acme")
+
+	repo.Get(ctx, "o1")
+	// Expiry runs outside any tenant, but invalidates the entries of the
+	// tenant each order belongs to
+	if _, err := repo.ExpirePending(context.Background(), time.Now()); err != nil {
+		t.Fatalf("ExpirePending() error = %v", err)
+	}
+	if o, err := repo.Get(ctx, "o1"); err != nil || o.Status != "cancelled" {
+		t.Errorf("Get() = %v, %v after expiry, want the cancelled order", o, err)
+	}
+
+	// Writers outside the repository invalidate through Invalidate
+	fake.orders["o1"].UserID = ""
+	if err := Invalidate(context.Background(), c, "acme", "o1"); err != nil {
+		t.Fatalf("Invalidate() error = %v", err)
+	}
+	if o, err := repo.Get(ctx, "o1"); err != nil || o.UserID != "" {
+		t.Errorf("Get() = %v, %v after Invalidate, want the anonymized order", o, err)
+	}
+}
+
+func TestCachedRepositoryCacheFailure(t *testing.T) {
+	repo := NewCached(newFakeRepository(), failingCache{}, time.Minute, log.NewDefault())
+	ctx := context.Background()
+
+	if _, _, err := repo.GetByID(ctx, "o1"); err != nil {
+		t.Errorf("GetByID() error = %v, want lookups to fall through to the repository", err)
+	}
+	if err := repo.UpdateStatus(ctx, "o1", "confirmed"); err == nil {
+		t.Error("UpdateStatus() should fail when the cache cannot be invalidated")
+	}
+}
@@ -0,0 +1,109 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package repository
+
+import (
+	"context"
+
+	"github.com/kevindiu/monorepo-go-example/internal/db"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/internal/events"
+	"github.com/kevindiu/monorepo-go-example/internal/outbox"
+	orderevents "github.com/kevindiu/monorepo-go-example/pkg/order/events"
+)
+
+// enqueue writes an order event to the outbox in tx, so that the event is
+// published by the outbox relay if and only if the change commits
+func enqueue(ctx context.Context, tx db.Executor, eventType string, payload *orderevents.Order) error {
+	event, err := events.NewEvent(eventType, payload.ID, payload)
+	if err != nil {
+		return err
+	}
+	return outbox.Enqueue(ctx, tx, orderevents.Topic, event)
+}
+
+// enqueueChanged enqueues an event carrying the order as written by tx
+func enqueueChanged(ctx context.Context, tx db.Executor, eventType, id string) error {
+	query := `
+		SELECT id, COALESCE(user_id::text, ''), status, total_minor, currency, created_at, updated_at
+		FROM orders
+		WHERE id = $1
+	`
+
+	var order Order
+	if err := tx.QueryRowContext(ctx, query, id).Scan(
+		&order.ID,
+		&order.UserID,
+		&order.Status,
+		&order.TotalAmount,
+		&order.Currency,
+		&order.CreatedAt,
+		&order.UpdatedAt,
+	); err != nil {
+		return errors.Wrap(err, "failed to read order for event")
+	}
+
+	itemQuery := `
+		SELECT product_id, quantity, price_minor
+		FROM order_items
+		WHERE order_id = $1
+		ORDER BY created_at ASC
+	`
+
+	rows, err := tx.QueryContext(ctx, itemQuery, id)
+	if err != nil {
+		return errors.Wrap(err, "failed to read order items for event")
+	}
+	defer rows.Close()
+
+	var items []*OrderItem
+	for rows.Next() {
+		var item OrderItem
+		if err := rows.Scan(&item.ProductID, &item.Quantity, &item.Price); err != nil {
+			return errors.Wrap(err, "failed to scan order item")
+		}
+		items = append(items, &item)
+	}
+	if err := rows.Err(); err != nil {
+		return errors.Wrap(err, "error iterating order items")
+	}
+
+	return enqueue(ctx, tx, eventType, eventPayload(&order, items))
+}
+
+// eventPayload converts an order and its items into an event payload
+func eventPayload(order *Order, items []*OrderItem) *orderevents.Order {
+	payload := &orderevents.Order{
+		ID:         order.ID,
+		UserID:     order.UserID,
+		Status:     order.Status,
+		TotalMinor: order.TotalAmount,
+		Currency:   order.Currency,
+		Items:      make([]orderevents.Item, len(items)),
+		CreatedAt:  order.CreatedAt,
+		UpdatedAt:  order.UpdatedAt,
+	}
+	for i, item := range items {
+		payload.Items[i] = orderevents.Item{
+			ProductID:   item.ProductID,
+			ProductName: item.ProductName,
+			Quantity:    item.Quantity,
+			PriceMinor:  item.Price,
+		}
+	}
+	return payload
+}
@@ -0,0 +1,116 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/audit"
+	"github.com/kevindiu/monorepo-go-example/internal/db"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/internal/tenant"
+)
+
+// SystemActor is recorded for status changes made without an
+// authenticated caller, such as the expiry jobs
+const SystemActor = "system"
+
+// StatusChange is an entry in the status history of an order
+type StatusChange struct {
+	OrderID string
+	// FromStatus is empty for the status the order was created with
+	FromStatus string
+	ToStatus   string
+	Actor      string
+	// OnBehalfOf is the principal a trusted service made the change for,
+	// empty when the actor acted for itself
+	OnBehalfOf string
+	ChangedAt  time.Time
+}
+
+// historyColumns are the columns Import copies history rows into
+var historyColumns = []string{"tenant_id", "order_id", "to_status", "actor", "on_behalf_of", "changed_at"}
+
+// actor returns who made a change and on whose behalf, attributed like
+// audited calls (see audit.Principal), or SystemActor when no one is known
+func actor(ctx context.Context) (string, string) {
+	by, onBehalfOf := audit.Principal(ctx)
+	if by == "" {
+		return SystemActor, ""
+	}
+	return by, onBehalfOf
+}
+
+// recordStatus appends a status change of an order to its history in tx;
+// from is empty when the order is created
+func recordStatus(ctx context.Context, tx db.Executor, tenantID, id, from, to string, at time.Time) error {
+	query := `
+		INSERT INTO order_status_history (tenant_id, order_id, from_status, to_status, actor, on_behalf_of, changed_at)
+		VALUES ($1, $2, NULLIF($3, ''), $4, $5, NULLIF($6, ''), $7)
+	`
+	by, onBehalfOf := actor(ctx)
+	if _, err := tx.ExecContext(ctx, query, tenantID, id, from, to, by, onBehalfOf, at); err != nil {
+		return errors.Wrap(err, "failed to record order status change")
+	}
+	return nil
+}
+
+// History returns the status changes of an order, oldest first
+func (r *repository) History(ctx context.Context, id string) ([]*StatusChange, error) {
+	query := `
+		SELECT order_id, COALESCE(from_status, ''), to_status, actor, COALESCE(on_behalf_of, ''), changed_at
+		FROM order_status_history
+		WHERE tenant_id = $1 AND order_id = $2
+		ORDER BY seq
+	`
+	rows, err := r.db.Executor(ctx).QueryContext(ctx, query, tenant.ID(ctx), id)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query order history")
+	}
+	defer rows.Close()
+
+	var changes []*StatusChange
+	for rows.Next() {
+		var c StatusChange
+		if err := rows.Scan(&c.OrderID, &c.FromStatus, &c.ToStatus, &c.Actor, &c.OnBehalfOf, &c.ChangedAt); err != nil {
+			return nil, errors.Wrap(err, "failed to scan order status change")
+		}
+		changes = append(changes, &c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "failed to query order history")
+	}
+	if len(changes) > 0 {
+		return changes, nil
+	}
+
+	// Orders created before the history was kept may have none
+	var exists bool
+	err = r.db.Executor(ctx).QueryRowContext(ctx,
+		`SELECT true FROM orders WHERE tenant_id = $1 AND id = $2`,
+		tenant.ID(ctx), id,
+	).Scan(&exists)
+	if err == sql.ErrNoRows {
+		return nil, errors.WithCode(errors.New("order not found"), errors.CodeNotFound)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get order")
+	}
+	return changes, nil
+}
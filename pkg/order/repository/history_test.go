@@ -0,0 +1,55 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kevindiu/monorepo-go-example/internal/audit"
+	"github.com/kevindiu/monorepo-go-example/internal/auth"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestActor(t *testing.T) {
+	named := metadata.NewIncomingContext(context.Background(), metadata.Pairs(audit.ActorMetadataKey, "admin-1"))
+	tests := []struct {
+		name       string
+		ctx        context.Context
+		want       string
+		onBehalfOf string
+	}{
+		{name: "authenticated", ctx: auth.WithClaims(context.Background(), &auth.Claims{Subject: "user-1"}), want: "user-1"},
+		{name: "no subject", ctx: auth.WithClaims(context.Background(), &auth.Claims{}), want: SystemActor},
+		{name: "background job", ctx: context.Background(), want: SystemActor},
+		{name: "named by a user", ctx: auth.WithClaims(named, &auth.Claims{Subject: "user-1"}), want: "user-1"},
+		{
+			name:       "named by a service",
+			ctx:        auth.WithClaims(named, &auth.Claims{Subject: "shipping-service", Roles: []string{auth.RoleService}}),
+			want:       "shipping-service",
+			onBehalfOf: "admin-1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, onBehalfOf := actor(tt.ctx); got != tt.want || onBehalfOf != tt.onBehalfOf {
+				t.Errorf("actor() = %q, %q, want %q, %q", got, onBehalfOf, tt.want, tt.onBehalfOf)
+			}
+		})
+	}
+}
@@ -0,0 +1,211 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build integration
+
+package repository
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/internal/tenant"
+	inventoryrepo "github.com/kevindiu/monorepo-go-example/pkg/inventory/repository"
+)
+
+func TestOrderRepository_InventoryReservations(t *testing.T) {
+	database := setupTestDB(t)
+	repo := New(database, WithReservationTTL(time.Hour))
+	stock := inventoryrepo.New(database)
+
+	tenantID := "tenant-inv-" + uuid.New().String()[:8]
+	ctx := tenant.WithID(context.Background(), tenantID)
+	userID := createUser(t, database, tenantID)
+	t.Cleanup(func() { database.Exec(`DELETE FROM inventory WHERE tenant_id = $1`, tenantID) })
+
+	if _, err := stock.Set(ctx, "prod-tracked", 5); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+
+	// create places an order for quantity units of the tracked product and
+	// one of an untracked product
+	create := func(t *testing.T, quantity int32) (*Order, error) {
+		t.Helper()
+		order := &Order{UserID: userID, Status: "pending", TotalAmount: 100, Currency: "USD"}
+		items := []*OrderItem{
+			{ProductID: "prod-tracked", ProductName: "Tracked widget", Quantity: quantity, Price: 10},
+			{ProductID: "prod-untracked", ProductName: "Untracked widget", Quantity: 100, Price: 10},
+		}
+		return order, repo.Create(ctx, order, items)
+	}
+	wantStock := func(t *testing.T, available, reserved int) {
+		t.Helper()
+		got, err := stock.Get(ctx, "prod-tracked")
+		if err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		if got.Available != available || got.Reserved != reserved {
+			t.Errorf("stock = %d available, %d reserved, want %d and %d", got.Available, got.Reserved, available, reserved)
+		}
+	}
+
+	t.Run("insufficient stock", func(t *testing.T) {
+		if _, err := create(t, 6); errors.GetCode(err) != errors.CodeInvalidInput {
+			t.Errorf("Create() beyond stock error = %v, want invalid input", err)
+		}
+		wantStock(t, 5, 0)
+	})
+
+	t.Run("cancel releases", func(t *testing.T) {
+		order, err := create(t, 3)
+		if err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		wantStock(t, 2, 3)
+
+		if err := repo.UpdateStatus(ctx, order.ID, "cancelled"); err != nil {
+			t.Fatalf("UpdateStatus() error = %v", err)
+		}
+		wantStock(t, 5, 0)
+	})
+
+	t.Run("confirm commits", func(t *testing.T) {
+		order, err := create(t, 2)
+		if err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		if err := repo.UpdateStatus(ctx, order.ID, "confirmed"); err != nil {
+			t.Fatalf("UpdateStatus() error = %v", err)
+		}
+		wantStock(t, 3, 0)
+
+		// Cancelling a confirmed order restocks it
+		if err := repo.UpdateStatus(ctx, order.ID, "cancelled"); err != nil {
+			t.Fatalf("UpdateStatus() error = %v", err)
+		}
+		wantStock(t, 5, 0)
+	})
+
+	t.Run("concurrent confirm and cancel", func(t *testing.T) {
+		// Whichever update takes the row lock first, the order ends up
+		// cancelled with its stock back: a cancel after the confirmation
+		// restocks it, and a confirmation after the cancel is rejected
+		for i := 0; i < 5; i++ {
+			order, err := create(t, 2)
+			if err != nil {
+				t.Fatalf("Create() error = %v", err)
+			}
+
+			var wg sync.WaitGroup
+			var confirmErr, cancelErr error
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				confirmErr = repo.UpdateStatus(ctx, order.ID, "confirmed")
+			}()
+			go func() {
+				defer wg.Done()
+				cancelErr = repo.UpdateStatus(ctx, order.ID, "cancelled")
+			}()
+			wg.Wait()
+
+			if cancelErr != nil {
+				t.Fatalf("UpdateStatus(cancelled) error = %v", cancelErr)
+			}
+			if confirmErr != nil && errors.GetCode(confirmErr) != errors.CodeInvalidInput {
+				t.Fatalf("UpdateStatus(confirmed) error = %v, want nil or invalid input", confirmErr)
+			}
+			got, err := repo.Get(ctx, order.ID)
+			if err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+			if got.Status != "cancelled" {
+				t.Errorf("status after concurrent updates = %q, want cancelled", got.Status)
+			}
+			wantStock(t, 5, 0)
+		}
+	})
+
+	t.Run("update items", func(t *testing.T) {
+		order, err := create(t, 1)
+		if err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		_, items, err := repo.GetByID(ctx, order.ID)
+		if err != nil {
+			t.Fatalf("GetByID() error = %v", err)
+		}
+		var trackedID string
+		for _, item := range items {
+			if item.ProductID == "prod-tracked" {
+				trackedID = item.ID
+				if item.ProductName != "Tracked widget" {
+					t.Errorf("GetByID() item name = %q, want the name it was created with", item.ProductName)
+				}
+			}
+		}
+
+		add := []*OrderItem{{ProductID: "prod-tracked", ProductName: "Tracked widget XL", Quantity: 4, Price: 10}}
+		if err := repo.UpdateItems(ctx, order.ID, add, []string{trackedID}); err != nil {
+			t.Fatalf("UpdateItems() error = %v", err)
+		}
+		wantStock(t, 1, 4)
+
+		_, items, err = repo.GetByID(ctx, order.ID)
+		if err != nil {
+			t.Fatalf("GetByID() error = %v", err)
+		}
+		names := make(map[string]string)
+		for _, item := range items {
+			names[item.ProductID] = item.ProductName
+		}
+		if len(items) != 2 || names["prod-tracked"] != "Tracked widget XL" || names["prod-untracked"] != "Untracked widget" {
+			t.Errorf("GetByID() after UpdateItems() items = %v, want the added and the kept item with their names", names)
+		}
+
+		if err := repo.UpdateStatus(ctx, order.ID, "cancelled"); err != nil {
+			t.Fatalf("UpdateStatus() error = %v", err)
+		}
+		wantStock(t, 5, 0)
+	})
+
+	t.Run("expire reservations", func(t *testing.T) {
+		order, err := create(t, 5)
+		if err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		if _, err := repo.ExpireReservations(context.Background(), time.Now()); err != nil {
+			t.Fatalf("ExpireReservations() error = %v", err)
+		}
+		wantStock(t, 0, 5)
+
+		if _, err := repo.ExpireReservations(context.Background(), time.Now().Add(2*time.Hour)); err != nil {
+			t.Fatalf("ExpireReservations() error = %v", err)
+		}
+		got, err := repo.Get(ctx, order.ID)
+		if err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		if got.Status != "cancelled" {
+			t.Errorf("ExpireReservations() left the order %q", got.Status)
+		}
+		wantStock(t, 5, 0)
+	})
+}
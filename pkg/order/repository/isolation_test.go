@@ -0,0 +1,218 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build integration
+
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kevindiu/monorepo-go-example/internal/auth"
+	"github.com/kevindiu/monorepo-go-example/internal/config"
+	"github.com/kevindiu/monorepo-go-example/internal/db"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/internal/tenant"
+)
+
+// These tests run against the database configured through the usual
+// DATABASE_* environment variables, migrated to at least version 009:
+//
+//	make test-integration
+
+func setupTestDB(t *testing.T) *db.DB {
+	t.Helper()
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("failed to load configuration: %v", err)
+	}
+	database, err := db.Connect(cfg.Database)
+	if err != nil {
+		t.Fatalf("failed to connect to database: %v", err)
+	}
+	t.Cleanup(func() { database.Close() })
+	return database
+}
+
+// createUser inserts a user owned by tenantID and removes it, with its
+// orders, when the test ends
+func createUser(t *testing.T, database *db.DB, tenantID string) string {
+	t.Helper()
+
+	id := uuid.New().String()
+	_, err := database.Exec(
+		`INSERT INTO users (id, tenant_id, email, name) VALUES ($1, $2, $3, $4)`,
+		id, tenantID, id+"@example.com", "Test User",
+	)
+	if err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+	t.Cleanup(func() { database.Exec(`DELETE FROM users WHERE id = $1`, id) })
+	return id
+}
+
+func TestOrderRepository_TenantIsolation(t *testing.T) {
+	database := setupTestDB(t)
+	repo := New(database)
+
+	idA := "tenant-a-" + uuid.New().String()[:8]
+	idB := "tenant-b-" + uuid.New().String()[:8]
+	tenantA := tenant.WithID(context.Background(), idA)
+	tenantB := tenant.WithID(context.Background(), idB)
+	userA := createUser(t, database, idA)
+	userB := createUser(t, database, idB)
+
+	order := &Order{UserID: userA, Status: "pending", TotalAmount: 4200, Currency: "USD"}
+	if err := repo.Create(tenantA, order, nil); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	t.Run("create for another tenant's user", func(t *testing.T) {
+		if err := repo.Create(tenantB, &Order{UserID: userA, Status: "pending"}, nil); err == nil {
+			t.Error("Create() accepted a user of another tenant")
+		}
+	})
+
+	t.Run("import", func(t *testing.T) {
+		imported := &ImportedOrder{
+			Order: &Order{UserID: userA, Status: "delivered", TotalAmount: 800, Currency: "USD"},
+			Items: []*OrderItem{{ProductID: "prod-1", ProductName: "Mug", Quantity: 1, Price: 800}},
+		}
+		if err := repo.Import(tenantB, []*ImportedOrder{imported}); err == nil {
+			t.Error("Import() accepted a user of another tenant")
+		}
+		if err := repo.Import(tenantA, []*ImportedOrder{imported}); err != nil {
+			t.Fatalf("Import() error = %v", err)
+		}
+		got, items, err := repo.GetByID(tenantA, imported.Order.ID)
+		if err != nil {
+			t.Fatalf("GetByID() error = %v", err)
+		}
+		if got.Status != "delivered" || len(items) != 1 {
+			t.Errorf("imported order is %q with %d items, want delivered with 1", got.Status, len(items))
+		}
+	})
+
+	t.Run("get by ID", func(t *testing.T) {
+		if _, _, err := repo.GetByID(tenantB, order.ID); errors.GetCode(err) != errors.CodeNotFound {
+			t.Errorf("GetByID() across tenants error = %v, want not found", err)
+		}
+	})
+
+	t.Run("get by user ID", func(t *testing.T) {
+		orders, err := repo.ListAfter(tenantB, Filter{UserID: userA}, nil, 100)
+		if err != nil {
+			t.Fatalf("ListAfter() error = %v", err)
+		}
+		if len(orders) != 0 {
+			t.Errorf("ListAfter() across tenants returned %d orders", len(orders))
+		}
+	})
+
+	t.Run("list", func(t *testing.T) {
+		if err := repo.Create(tenantB, &Order{UserID: userB, Status: "pending"}, nil); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		orders, err := repo.ListAfter(tenantB, Filter{}, nil, 100)
+		if err != nil {
+			t.Fatalf("ListAfter() error = %v", err)
+		}
+		for _, o := range orders {
+			if o.TenantID != idB {
+				t.Errorf("ListAfter() returned order %s of tenant %s", o.ID, o.TenantID)
+			}
+		}
+	})
+
+	t.Run("update status", func(t *testing.T) {
+		if err := repo.UpdateStatus(tenantB, order.ID, "cancelled"); errors.GetCode(err) != errors.CodeNotFound {
+			t.Errorf("UpdateStatus() across tenants error = %v, want not found", err)
+		}
+		got, _, err := repo.GetByID(tenantA, order.ID)
+		if err != nil {
+			t.Fatalf("GetByID() error = %v", err)
+		}
+		if got.Status != "pending" {
+			t.Errorf("UpdateStatus() across tenants changed the status to %q", got.Status)
+		}
+	})
+
+	t.Run("update items", func(t *testing.T) {
+		add := []*OrderItem{{ProductID: "prod-1", Quantity: 2, Price: 300}}
+		if err := repo.UpdateItems(tenantB, order.ID, add, nil); errors.GetCode(err) != errors.CodeNotFound {
+			t.Errorf("UpdateItems() across tenants error = %v, want not found", err)
+		}
+		if err := repo.UpdateItems(tenantA, order.ID, add, nil); err != nil {
+			t.Fatalf("UpdateItems() error = %v", err)
+		}
+		got, items, err := repo.GetByID(tenantA, order.ID)
+		if err != nil {
+			t.Fatalf("GetByID() error = %v", err)
+		}
+		if len(items) != 1 || got.TotalAmount != 600 {
+			t.Errorf("UpdateItems() left %d items totalling %d, want 1 totalling 600", len(items), got.TotalAmount)
+		}
+	})
+
+	t.Run("history", func(t *testing.T) {
+		if _, err := repo.History(tenantB, order.ID); errors.GetCode(err) != errors.CodeNotFound {
+			t.Errorf("History() across tenants error = %v, want not found", err)
+		}
+		caller := auth.WithClaims(tenantA, &auth.Claims{Subject: "admin-1"})
+		if err := repo.UpdateStatus(caller, order.ID, "confirmed"); err != nil {
+			t.Fatalf("UpdateStatus() error = %v", err)
+		}
+		changes, err := repo.History(tenantA, order.ID)
+		if err != nil {
+			t.Fatalf("History() error = %v", err)
+		}
+		if len(changes) != 2 {
+			t.Fatalf("History() returned %d changes, want creation and confirmation", len(changes))
+		}
+		if c := changes[0]; c.FromStatus != "" || c.ToStatus != "pending" || c.Actor != SystemActor {
+			t.Errorf("creation = %+v", c)
+		}
+		if c := changes[1]; c.FromStatus != "pending" || c.ToStatus != "confirmed" || c.Actor != "admin-1" {
+			t.Errorf("confirmation = %+v", c)
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		if err := repo.Delete(tenantB, order.ID); errors.GetCode(err) != errors.CodeNotFound {
+			t.Errorf("Delete() across tenants error = %v, want not found", err)
+		}
+		if _, _, err := repo.GetByID(tenantA, order.ID); err != nil {
+			t.Errorf("Delete() across tenants removed the order: %v", err)
+		}
+	})
+
+	t.Run("expire pending spans tenants", func(t *testing.T) {
+		if _, err := repo.ExpirePending(context.Background(), time.Now().Add(time.Minute)); err != nil {
+			t.Fatalf("ExpirePending() error = %v", err)
+		}
+		got, _, err := repo.GetByID(tenantA, order.ID)
+		if err != nil {
+			t.Fatalf("GetByID() error = %v", err)
+		}
+		if got.Status != "cancelled" {
+			t.Errorf("ExpirePending() left the order %q", got.Status)
+		}
+	})
+}
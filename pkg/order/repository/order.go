@@ -24,50 +24,121 @@ import (
 	"github.com/google/uuid"
 	"github.com/kevindiu/monorepo-go-example/internal/db"
 	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/internal/pagination"
+	"github.com/kevindiu/monorepo-go-example/internal/tenant"
+	inventoryrepo "github.com/kevindiu/monorepo-go-example/pkg/inventory/repository"
+	orderevents "github.com/kevindiu/monorepo-go-example/pkg/order/events"
+	promotionrepo "github.com/kevindiu/monorepo-go-example/pkg/promotion/repository"
+	"github.com/lib/pq"
 )
 
 // Order represents an order entity
 type Order struct {
-	ID          string
-	UserID      string
-	Status      string
-	TotalAmount float64
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
+	ID       string
+	TenantID string
+	UserID   string
+	Status   string
+	// TotalAmount is in the minor units of Currency
+	TotalAmount int64
+	// Currency is the ISO 4217 code of every amount of the order
+	Currency string
+	// Discounts are the promotions applied to the order; TotalAmount is net
+	// of them
+	Discounts []*Discount
+	CreatedAt time.Time
+	UpdatedAt time.Time
 }
 
 // OrderItem represents an order item entity
 type OrderItem struct {
-	ID        string
-	OrderID   string
-	ProductID string
-	Quantity  int32
-	Price     float64
+	ID          string
+	OrderID     string
+	ProductID   string
+	ProductName string
+	Quantity    int32
+	// Price is the unit price in the minor units of the order currency
+	Price     int64
+	CreatedAt time.Time
+}
+
+// ImportedOrder is an order with its items, as inserted by Import
+type ImportedOrder struct {
+	Order *Order
+	Items []*OrderItem
+}
+
+// Discount represents a promotion redeemed on an order
+type Discount struct {
+	ID          string
+	OrderID     string
+	PromotionID string
+	Code        string
+	// Amount is in the minor units of the order currency
+	Amount    int64
 	CreatedAt time.Time
 }
 
-// Repository defines the order repository interface
+// DefaultReservationTTL is how long the stock of a pending order is held
+// unless WithReservationTTL says otherwise
+const DefaultReservationTTL = 30 * time.Minute
+
+// Expired identifies an order cancelled by ExpirePending or
+// ExpireReservations
+type Expired struct {
+	TenantID string
+	ID       string
+}
+
+// Repository defines the order repository interface. Every operation except
+// ExpirePending and ExpireReservations, which are maintenance across all
+// tenants, is scoped to the tenant on the context.
 type Repository interface {
 	Create(ctx context.Context, order *Order, items []*OrderItem) error
+	Get(ctx context.Context, id string) (*Order, error)
 	GetByID(ctx context.Context, id string) (*Order, []*OrderItem, error)
-	GetByUserID(ctx context.Context, userID string, limit, offset int) ([]*Order, error)
-	List(ctx context.Context, limit, offset int) ([]*Order, error)
+	ListItems(ctx context.Context, orderIDs []string) (map[string][]*OrderItem, error)
+	ListAfter(ctx context.Context, filter Filter, after *pagination.Cursor, limit int) ([]*Order, error)
+	Count(ctx context.Context, filter Filter) (int64, bool, error)
 	UpdateStatus(ctx context.Context, id, status string) error
+	History(ctx context.Context, id string) ([]*StatusChange, error)
+	UpdateItems(ctx context.Context, id string, add []*OrderItem, removeIDs []string) error
 	Delete(ctx context.Context, id string) error
+	ExpirePending(ctx context.Context, createdBefore time.Time) ([]Expired, error)
+	ExpireReservations(ctx context.Context, now time.Time) ([]Expired, error)
+	Import(ctx context.Context, orders []*ImportedOrder) error
 }
 
 type repository struct {
-	db *db.DB
+	db             *db.DB
+	reservationTTL time.Duration
+}
+
+// Option configures an order repository
+type Option func(*repository)
+
+// WithReservationTTL holds the stock of pending orders for ttl. Pending
+// orders are cancelled by ExpireReservations once their stock is no longer
+// held.
+func WithReservationTTL(ttl time.Duration) Option {
+	return func(r *repository) {
+		r.reservationTTL = ttl
+	}
 }
 
 // New creates a new order repository
-func New(database *db.DB) Repository {
-	return &repository{
-		db: database,
+func New(database *db.DB, opts ...Option) Repository {
+	r := &repository{
+		db:             database,
+		reservationTTL: DefaultReservationTTL,
 	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
 }
 
-// Create creates a new order with items
+// Create creates a new order with items, reserving their stock, and writes
+// an order.created event to the outbox in the same transaction
 func (r *repository) Create(ctx context.Context, order *Order, items []*OrderItem) error {
 	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
@@ -77,19 +148,22 @@ func (r *repository) Create(ctx context.Context, order *Order, items []*OrderIte
 
 	// Insert order
 	query := `
-		INSERT INTO orders (id, user_id, status, total_amount, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		INSERT INTO orders (id, tenant_id, user_id, status, total_minor, currency, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 	`
 	now := time.Now()
 	order.ID = uuid.New().String()
+	order.TenantID = tenant.ID(ctx)
 	order.CreatedAt = now
 	order.UpdatedAt = now
 
 	_, err = tx.ExecContext(ctx, query,
 		order.ID,
+		order.TenantID,
 		order.UserID,
 		order.Status,
 		order.TotalAmount,
+		order.Currency,
 		order.CreatedAt,
 		order.UpdatedAt,
 	)
@@ -99,8 +173,8 @@ func (r *repository) Create(ctx context.Context, order *Order, items []*OrderIte
 
 	// Insert order items
 	itemQuery := `
-		INSERT INTO order_items (id, order_id, product_id, quantity, price, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		INSERT INTO order_items (id, order_id, product_id, product_name, quantity, price_minor, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
 	`
 	for _, item := range items {
 		item.ID = uuid.New().String()
@@ -111,6 +185,7 @@ func (r *repository) Create(ctx context.Context, order *Order, items []*OrderIte
 			item.ID,
 			item.OrderID,
 			item.ProductID,
+			item.ProductName,
 			item.Quantity,
 			item.Price,
 			item.CreatedAt,
@@ -120,6 +195,126 @@ func (r *repository) Create(ctx context.Context, order *Order, items []*OrderIte
 		}
 	}
 
+	if err := inventoryrepo.Reserve(ctx, tx, order.ID, reservationItems(items), now.Add(r.reservationTTL)); err != nil {
+		return err
+	}
+
+	// Redeem promotions, enforcing their usage limits under the promotion
+	// row lock
+	discountQuery := `
+		INSERT INTO order_discounts (id, tenant_id, order_id, promotion_id, code, amount_minor, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+	for _, d := range order.Discounts {
+		if err := promotionrepo.Reserve(ctx, tx, d.PromotionID, order.UserID); err != nil {
+			return err
+		}
+
+		d.ID = uuid.New().String()
+		d.OrderID = order.ID
+		d.CreatedAt = now
+
+		_, err = tx.ExecContext(ctx, discountQuery,
+			d.ID,
+			order.TenantID,
+			d.OrderID,
+			d.PromotionID,
+			d.Code,
+			d.Amount,
+			d.CreatedAt,
+		)
+		if err != nil {
+			return errors.Wrap(err, "failed to create order discount")
+		}
+	}
+
+	if err := recordStatus(ctx, tx, order.TenantID, order.ID, "", order.Status, now); err != nil {
+		return err
+	}
+
+	if err := enqueue(ctx, tx, orderevents.TypeOrderCreated, eventPayload(order, items)); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(err, "failed to commit transaction")
+	}
+
+	return nil
+}
+
+// Import bulk inserts orders with their items using COPY, in one
+// transaction: either every order is inserted or none is. Orders keep
+// their status, and their creation time when it is set.
+func (r *repository) Import(ctx context.Context, orders []*ImportedOrder) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	tenantID := tenant.ID(ctx)
+	orderRows := make([][]interface{}, 0, len(orders))
+	historyRows := make([][]interface{}, 0, len(orders))
+	var itemRows [][]interface{}
+	importer, onBehalfOf := actor(ctx)
+	importedFor := sql.NullString{String: onBehalfOf, Valid: onBehalfOf != ""}
+	for _, o := range orders {
+		order := o.Order
+		order.ID = uuid.New().String()
+		order.TenantID = tenantID
+		if order.CreatedAt.IsZero() {
+			order.CreatedAt = now
+		}
+		order.UpdatedAt = now
+		orderRows = append(orderRows, []interface{}{
+			order.ID,
+			order.TenantID,
+			order.UserID,
+			order.Status,
+			order.TotalAmount,
+			order.Currency,
+			order.CreatedAt,
+			order.UpdatedAt,
+		})
+		historyRows = append(historyRows, []interface{}{
+			order.TenantID,
+			order.ID,
+			order.Status,
+			importer,
+			importedFor,
+			order.CreatedAt,
+		})
+
+		for _, item := range o.Items {
+			item.ID = uuid.New().String()
+			item.OrderID = order.ID
+			item.CreatedAt = now
+			itemRows = append(itemRows, []interface{}{
+				item.ID,
+				item.OrderID,
+				item.ProductID,
+				item.ProductName,
+				item.Quantity,
+				item.Price,
+				item.CreatedAt,
+			})
+		}
+	}
+
+	orderColumns := []string{"id", "tenant_id", "user_id", "status", "total_minor", "currency", "created_at", "updated_at"}
+	if err := r.db.CopyIn(ctx, tx, "orders", orderColumns, orderRows); err != nil {
+		return err
+	}
+	itemColumns := []string{"id", "order_id", "product_id", "product_name", "quantity", "price_minor", "created_at"}
+	if err := r.db.CopyIn(ctx, tx, "order_items", itemColumns, itemRows); err != nil {
+		return err
+	}
+	if err := r.db.CopyIn(ctx, tx, "order_status_history", historyColumns, historyRows); err != nil {
+		return err
+	}
+
 	if err := tx.Commit(); err != nil {
 		return errors.Wrap(err, "failed to commit transaction")
 	}
@@ -127,116 +322,161 @@ func (r *repository) Create(ctx context.Context, order *Order, items []*OrderIte
 	return nil
 }
 
-// GetByID retrieves an order by ID with its items
-func (r *repository) GetByID(ctx context.Context, id string) (*Order, []*OrderItem, error) {
+// Get retrieves an order by ID with its discounts but not its items
+func (r *repository) Get(ctx context.Context, id string) (*Order, error) {
 	query := `
-		SELECT id, user_id, status, total_amount, created_at, updated_at
+		SELECT id, tenant_id, COALESCE(user_id::text, ''), status, total_minor, currency, created_at, updated_at
 		FROM orders
-		WHERE id = $1
+		WHERE tenant_id = $1 AND id = $2
 	`
 
 	var order Order
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
+	err := r.db.Executor(ctx).QueryRowContext(ctx, query, tenant.ID(ctx), id).Scan(
 		&order.ID,
+		&order.TenantID,
 		&order.UserID,
 		&order.Status,
 		&order.TotalAmount,
+		&order.Currency,
 		&order.CreatedAt,
 		&order.UpdatedAt,
 	)
 	if err == sql.ErrNoRows {
-		return nil, nil, errors.WithCode(errors.New("order not found"), errors.CodeNotFound)
+		return nil, errors.WithCode(errors.New("order not found"), errors.CodeNotFound)
 	}
 	if err != nil {
-		return nil, nil, errors.Wrap(err, "failed to get order")
+		return nil, errors.Wrap(err, "failed to get order")
 	}
 
-	// Get order items
-	itemQuery := `
-		SELECT id, order_id, product_id, quantity, price, created_at
-		FROM order_items
-		WHERE order_id = $1
-		ORDER BY created_at ASC
+	discounts, err := r.getDiscounts(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	order.Discounts = discounts
+
+	return &order, nil
+}
+
+// GetByID retrieves an order by ID with its items
+func (r *repository) GetByID(ctx context.Context, id string) (*Order, []*OrderItem, error) {
+	order, err := r.Get(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	items, err := r.ListItems(ctx, []string{id})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return order, items[id], nil
+}
+
+// ListItems retrieves the items of several orders in one query, keyed by
+// order ID. Orders outside the caller's tenant are skipped.
+func (r *repository) ListItems(ctx context.Context, orderIDs []string) (map[string][]*OrderItem, error) {
+	query := `
+		SELECT i.id, i.order_id, i.product_id, i.product_name, i.quantity, i.price_minor, i.created_at
+		FROM order_items i
+		JOIN orders o ON o.id = i.order_id
+		WHERE o.tenant_id = $1 AND i.order_id = ANY($2::uuid[])
+		ORDER BY i.created_at ASC
 	`
 
-	rows, err := r.db.QueryContext(ctx, itemQuery, id)
+	rows, err := r.db.Executor(ctx).QueryContext(ctx, query, tenant.ID(ctx), pq.Array(orderIDs))
 	if err != nil {
-		return nil, nil, errors.Wrap(err, "failed to get order items")
+		return nil, errors.Wrap(err, "failed to get order items")
 	}
 	defer rows.Close()
 
-	var items []*OrderItem
+	items := make(map[string][]*OrderItem, len(orderIDs))
 	for rows.Next() {
 		var item OrderItem
 		if err := rows.Scan(
 			&item.ID,
 			&item.OrderID,
 			&item.ProductID,
+			&item.ProductName,
 			&item.Quantity,
 			&item.Price,
 			&item.CreatedAt,
 		); err != nil {
-			return nil, nil, errors.Wrap(err, "failed to scan order item")
+			return nil, errors.Wrap(err, "failed to scan order item")
 		}
-		items = append(items, &item)
+		items[item.OrderID] = append(items[item.OrderID], &item)
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, nil, errors.Wrap(err, "error iterating order items")
+		return nil, errors.Wrap(err, "error iterating order items")
 	}
 
-	return &order, items, nil
+	return items, nil
 }
 
-// GetByUserID retrieves orders by user ID
-func (r *repository) GetByUserID(ctx context.Context, userID string, limit, offset int) ([]*Order, error) {
+// getDiscounts retrieves the discounts applied to an order
+func (r *repository) getDiscounts(ctx context.Context, orderID string) ([]*Discount, error) {
 	query := `
-		SELECT id, user_id, status, total_amount, created_at, updated_at
-		FROM orders
-		WHERE user_id = $1
-		ORDER BY created_at DESC
-		LIMIT $2 OFFSET $3
+		SELECT id, order_id, promotion_id, code, amount_minor, created_at
+		FROM order_discounts
+		WHERE tenant_id = $1 AND order_id = $2
+		ORDER BY created_at ASC, code ASC
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, userID, limit, offset)
+	rows, err := r.db.Executor(ctx).QueryContext(ctx, query, tenant.ID(ctx), orderID)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to list user orders")
+		return nil, errors.Wrap(err, "failed to get order discounts")
 	}
 	defer rows.Close()
 
-	var orders []*Order
+	var discounts []*Discount
 	for rows.Next() {
-		var order Order
+		var d Discount
 		if err := rows.Scan(
-			&order.ID,
-			&order.UserID,
-			&order.Status,
-			&order.TotalAmount,
-			&order.CreatedAt,
-			&order.UpdatedAt,
+			&d.ID,
+			&d.OrderID,
+			&d.PromotionID,
+			&d.Code,
+			&d.Amount,
+			&d.CreatedAt,
 		); err != nil {
-			return nil, errors.Wrap(err, "failed to scan order")
+			return nil, errors.Wrap(err, "failed to scan order discount")
 		}
-		orders = append(orders, &order)
+		discounts = append(discounts, &d)
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, errors.Wrap(err, "error iterating orders")
+		return nil, errors.Wrap(err, "error iterating order discounts")
 	}
 
-	return orders, nil
+	return discounts, nil
 }
 
-// List retrieves all orders with pagination
-func (r *repository) List(ctx context.Context, limit, offset int) ([]*Order, error) {
+// ListAfter retrieves up to limit orders selected by filter, in its
+// ordering, starting after the after cursor, or from the first when it is
+// nil. Unlike an offset, the cursor costs the same on every page and does
+// not skip or repeat orders created between pages.
+func (r *repository) ListAfter(ctx context.Context, filter Filter, after *pagination.Cursor, limit int) ([]*Order, error) {
+	sort, err := filter.sort()
+	if err != nil {
+		return nil, err
+	}
+	q := filter.where(ctx)
+	if after != nil {
+		q.after(sort, after)
+	}
+	dir := "ASC"
+	if sort.desc {
+		dir = "DESC"
+	}
+
 	query := `
-		SELECT id, user_id, status, total_amount, created_at, updated_at
+		SELECT id, tenant_id, COALESCE(user_id::text, ''), status, total_minor, currency, created_at, updated_at
 		FROM orders
-		ORDER BY created_at DESC
-		LIMIT $1 OFFSET $2
-	`
+		WHERE ` + q.String() + `
+		ORDER BY ` + sort.column + ` ` + dir + `, id ` + dir + `
+		LIMIT ` + q.arg(limit)
 
-	rows, err := r.db.QueryContext(ctx, query, limit, offset)
+	rows, err := r.db.Executor(ctx).QueryContext(ctx, query, q.args...)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to list orders")
 	}
@@ -247,9 +487,11 @@ func (r *repository) List(ctx context.Context, limit, offset int) ([]*Order, err
 		var order Order
 		if err := rows.Scan(
 			&order.ID,
+			&order.TenantID,
 			&order.UserID,
 			&order.Status,
 			&order.TotalAmount,
+			&order.Currency,
 			&order.CreatedAt,
 			&order.UpdatedAt,
 		); err != nil {
@@ -265,32 +507,277 @@ func (r *repository) List(ctx context.Context, limit, offset int) ([]*Order, err
 	return orders, nil
 }
 
-// UpdateStatus updates the order status
+// Count returns the number of orders selected by filter, and whether the
+// number is exact rather than estimated (see db.Count)
+func (r *repository) Count(ctx context.Context, filter Filter) (int64, bool, error) {
+	q := filter.where(ctx)
+	return r.db.Count(ctx, "orders WHERE "+q.String(), q.args...)
+}
+
+// UpdateStatus updates the order status. The order row is locked first and
+// the change checked against the status it holds, so that concurrent
+// updates cannot make a transition the state machine forbids; setting the
+// status an order already has does nothing. Confirming an order commits
+// its reserved stock, and cancelling it returns its stock to the
+// inventory. The change is appended to the order's status history, and an
+// order.status_changed event is written to the outbox, in the same
+// transaction.
 func (r *repository) UpdateStatus(ctx context.Context, id, status string) error {
-	query := `
-		UPDATE orders
-		SET status = $1, updated_at = $2
-		WHERE id = $3
-	`
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	var previous string
+	err = tx.QueryRowContext(ctx,
+		`SELECT status FROM orders WHERE tenant_id = $1 AND id = $2 FOR UPDATE`,
+		tenant.ID(ctx), id,
+	).Scan(&previous)
+	if err == sql.ErrNoRows {
+		return errors.WithCode(errors.New("order not found"), errors.CodeNotFound)
+	}
+	if err != nil {
+		return errors.Wrap(err, "failed to lock order")
+	}
+	if previous == status {
+		return nil
+	}
+	if err := CheckTransition(previous, status); err != nil {
+		return err
+	}
 
-	result, err := r.db.ExecContext(ctx, query, status, time.Now(), id)
+	now := time.Now()
+	_, err = tx.ExecContext(ctx,
+		`UPDATE orders SET status = $1, updated_at = $2 WHERE tenant_id = $3 AND id = $4`,
+		status, now, tenant.ID(ctx), id,
+	)
 	if err != nil {
 		return errors.Wrap(err, "failed to update order status")
 	}
 
-	rows, err := result.RowsAffected()
+	if err := recordStatus(ctx, tx, tenant.ID(ctx), id, previous, status, now); err != nil {
+		return err
+	}
+
+	switch status {
+	case "confirmed":
+		err = inventoryrepo.Commit(ctx, tx, id)
+	case "cancelled":
+		err = inventoryrepo.Release(ctx, tx, id, nil)
+	}
 	if err != nil {
-		return errors.Wrap(err, "failed to get affected rows")
+		return err
 	}
 
-	if rows == 0 {
+	if err := enqueueChanged(ctx, tx, orderevents.TypeOrderStatusChanged, id); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(err, "failed to commit transaction")
+	}
+
+	return nil
+}
+
+// UpdateItems adds and removes items of a pending order and recalculates
+// its total, net of its discounts, in one transaction. The order row is
+// locked first, so that the order cannot be confirmed part way through.
+// Stock of removed items is released and stock of added items reserved.
+func (r *repository) UpdateItems(ctx context.Context, id string, add []*OrderItem, removeIDs []string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	var status string
+	var createdAt time.Time
+	err = tx.QueryRowContext(ctx,
+		`SELECT status, created_at FROM orders WHERE tenant_id = $1 AND id = $2 FOR UPDATE`,
+		tenant.ID(ctx), id,
+	).Scan(&status, &createdAt)
+	if err == sql.ErrNoRows {
 		return errors.WithCode(errors.New("order not found"), errors.CodeNotFound)
 	}
+	if err != nil {
+		return errors.Wrap(err, "failed to lock order")
+	}
+	if status != "pending" {
+		return errors.WithCode(errors.Newf("cannot modify %s order", status), errors.CodeInvalidInput)
+	}
+
+	if len(removeIDs) > 0 {
+		if err := inventoryrepo.Release(ctx, tx, id, removeIDs); err != nil {
+			return err
+		}
+
+		result, err := tx.ExecContext(ctx,
+			`DELETE FROM order_items WHERE order_id = $1 AND id = ANY($2::uuid[])`,
+			id, pq.Array(removeIDs),
+		)
+		if err != nil {
+			return errors.Wrap(err, "failed to delete order items")
+		}
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return errors.Wrap(err, "failed to get affected rows")
+		}
+		if rows != int64(len(removeIDs)) {
+			return errors.WithCode(errors.New("order item not found"), errors.CodeNotFound)
+		}
+	}
+
+	now := time.Now()
+	itemQuery := `
+		INSERT INTO order_items (id, order_id, product_id, product_name, quantity, price_minor, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+	for _, item := range add {
+		item.ID = uuid.New().String()
+		item.OrderID = id
+		item.CreatedAt = now
+
+		_, err = tx.ExecContext(ctx, itemQuery,
+			item.ID,
+			item.OrderID,
+			item.ProductID,
+			item.ProductName,
+			item.Quantity,
+			item.Price,
+			item.CreatedAt,
+		)
+		if err != nil {
+			return errors.Wrap(err, "failed to create order item")
+		}
+	}
+
+	// Added items are held as long as the rest of the order
+	if err := inventoryrepo.Reserve(ctx, tx, id, reservationItems(add), createdAt.Add(r.reservationTTL)); err != nil {
+		return err
+	}
+
+	// Discounts keep the amounts they were redeemed for; the total never
+	// drops below zero
+	var items int
+	query := `
+		UPDATE orders
+		SET total_minor = GREATEST(
+				(SELECT COALESCE(SUM(price_minor * quantity), 0) FROM order_items WHERE order_id = $1) -
+				(SELECT COALESCE(SUM(amount_minor), 0) FROM order_discounts WHERE order_id = $1),
+				0),
+			updated_at = $2
+		WHERE id = $1
+		RETURNING (SELECT COUNT(*) FROM order_items WHERE order_id = $1)
+	`
+	if err := tx.QueryRowContext(ctx, query, id, now).Scan(&items); err != nil {
+		return errors.Wrap(err, "failed to update order total")
+	}
+	if items == 0 {
+		return errors.WithCode(errors.New("order must keep at least one item"), errors.CodeInvalidInput)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(err, "failed to commit transaction")
+	}
 
 	return nil
 }
 
-// Delete deletes an order and its items
+// ExpirePending cancels pending orders created before the given time in
+// every tenant, returning their stock to the inventory, and returns the
+// cancelled orders
+func (r *repository) ExpirePending(ctx context.Context, createdBefore time.Time) ([]Expired, error) {
+	query := `
+		UPDATE orders
+		SET status = 'cancelled', updated_at = $1
+		WHERE status = 'pending' AND created_at < $2
+		RETURNING id, tenant_id
+	`
+	return r.cancel(ctx, "failed to expire pending orders", query, time.Now(), createdBefore)
+}
+
+// ExpireReservations cancels pending orders in every tenant whose stock
+// reservation lapsed before now, returning their stock to the inventory,
+// and returns the cancelled orders
+func (r *repository) ExpireReservations(ctx context.Context, now time.Time) ([]Expired, error) {
+	query := `
+		UPDATE orders
+		SET status = 'cancelled', updated_at = $1
+		WHERE status = 'pending' AND id IN (
+			SELECT order_id FROM inventory_reservations
+			WHERE status = 'reserved' AND expires_at < $1
+		)
+		RETURNING id, tenant_id
+	`
+	return r.cancel(ctx, "failed to expire order reservations", query, now)
+}
+
+// cancel runs an UPDATE that cancels pending orders and returns their IDs
+// and tenants, and releases the stock of the cancelled orders, records
+// their status change and enqueues their order.status_changed events in
+// the same transaction
+func (r *repository) cancel(ctx context.Context, msg, query string, args ...interface{}) ([]Expired, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, msg)
+	}
+	var expired []Expired
+	for rows.Next() {
+		var e Expired
+		if err := rows.Scan(&e.ID, &e.TenantID); err != nil {
+			rows.Close()
+			return nil, errors.Wrap(err, "failed to scan order id")
+		}
+		expired = append(expired, e)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, msg)
+	}
+
+	now := time.Now()
+	for _, e := range expired {
+		if err := inventoryrepo.Release(ctx, tx, e.ID, nil); err != nil {
+			return nil, err
+		}
+		if err := recordStatus(ctx, tx, e.TenantID, e.ID, "pending", "cancelled", now); err != nil {
+			return nil, err
+		}
+		if err := enqueueChanged(ctx, tx, orderevents.TypeOrderStatusChanged, e.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, errors.Wrap(err, "failed to commit transaction")
+	}
+
+	return expired, nil
+}
+
+// reservationItems returns the stock to reserve for order items
+func reservationItems(items []*OrderItem) []inventoryrepo.Item {
+	reserve := make([]inventoryrepo.Item, len(items))
+	for i, item := range items {
+		reserve[i] = inventoryrepo.Item{
+			OrderItemID: item.ID,
+			ProductID:   item.ProductID,
+			Quantity:    item.Quantity,
+		}
+	}
+	return reserve
+}
+
+// Delete deletes an order and its items, returning their stock to the
+// inventory and enqueueing an order.deleted event
 func (r *repository) Delete(ctx context.Context, id string) error {
 	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
@@ -298,16 +785,25 @@ func (r *repository) Delete(ctx context.Context, id string) error {
 	}
 	defer tx.Rollback()
 
+	// The transaction rolls back when the order is not the tenant's
+	if err := inventoryrepo.Release(ctx, tx, id, nil); err != nil {
+		return err
+	}
+
 	// Delete order items first (foreign key constraint)
-	itemQuery := `DELETE FROM order_items WHERE order_id = $1`
-	_, err = tx.ExecContext(ctx, itemQuery, id)
+	tenantID := tenant.ID(ctx)
+	itemQuery := `
+		DELETE FROM order_items
+		WHERE order_id IN (SELECT id FROM orders WHERE tenant_id = $1 AND id = $2)
+	`
+	_, err = tx.ExecContext(ctx, itemQuery, tenantID, id)
 	if err != nil {
 		return errors.Wrap(err, "failed to delete order items")
 	}
 
 	// Delete order
-	orderQuery := `DELETE FROM orders WHERE id = $1`
-	result, err := tx.ExecContext(ctx, orderQuery, id)
+	orderQuery := `DELETE FROM orders WHERE tenant_id = $1 AND id = $2`
+	result, err := tx.ExecContext(ctx, orderQuery, tenantID, id)
 	if err != nil {
 		return errors.Wrap(err, "failed to delete order")
 	}
@@ -321,6 +817,10 @@ func (r *repository) Delete(ctx context.Context, id string) error {
 		return errors.WithCode(errors.New("order not found"), errors.CodeNotFound)
 	}
 
+	if err := enqueue(ctx, tx, orderevents.TypeOrderDeleted, &orderevents.Order{ID: id}); err != nil {
+		return err
+	}
+
 	if err := tx.Commit(); err != nil {
 		return errors.Wrap(err, "failed to commit transaction")
 	}
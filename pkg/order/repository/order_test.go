@@ -107,7 +107,7 @@ func TestOrderValidation(t *testing.T) {
 				ID:          uuid.New().String(),
 				UserID:      uuid.New().String(),
 				Status:      "pending",
-				TotalAmount: 100.50,
+				TotalAmount: 10050,
 				CreatedAt:   time.Now(),
 				UpdatedAt:   time.Now(),
 			},
@@ -119,7 +119,7 @@ func TestOrderValidation(t *testing.T) {
 				ID:          uuid.New().String(),
 				UserID:      "",
 				Status:      "pending",
-				TotalAmount: 100.50,
+				TotalAmount: 10050,
 			},
 			valid: false,
 		},
@@ -129,7 +129,7 @@ func TestOrderValidation(t *testing.T) {
 				ID:          uuid.New().String(),
 				UserID:      uuid.New().String(),
 				Status:      "pending",
-				TotalAmount: -10.00,
+				TotalAmount: -1000,
 			},
 			valid: false,
 		},
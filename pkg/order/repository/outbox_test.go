@@ -0,0 +1,94 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build integration
+
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/kevindiu/monorepo-go-example/internal/tenant"
+	orderevents "github.com/kevindiu/monorepo-go-example/pkg/order/events"
+)
+
+func TestOrderRepository_OutboxEvents(t *testing.T) {
+	database := setupTestDB(t)
+	repo := New(database)
+
+	tenantID := "tenant-outbox-" + uuid.New().String()[:8]
+	ctx := tenant.WithID(context.Background(), tenantID)
+	userID := createUser(t, database, tenantID)
+
+	// eventTypes returns the types of the outbox events keyed by an order,
+	// oldest first
+	eventTypes := func(t *testing.T, orderID string) []string {
+		t.Helper()
+		rows, err := database.Query(`
+			SELECT event_type FROM outbox_events
+			WHERE topic = $1 AND event_key = $2
+			ORDER BY created_at ASC
+		`, orderevents.Topic, orderID)
+		if err != nil {
+			t.Fatalf("query outbox error = %v", err)
+		}
+		defer rows.Close()
+
+		var types []string
+		for rows.Next() {
+			var eventType string
+			if err := rows.Scan(&eventType); err != nil {
+				t.Fatalf("scan outbox error = %v", err)
+			}
+			types = append(types, eventType)
+		}
+		return types
+	}
+
+	order := &Order{UserID: userID, Status: "pending", TotalAmount: 100, Currency: "USD"}
+	items := []*OrderItem{{ProductID: "prod-1", Quantity: 1, Price: 100}}
+	if err := repo.Create(ctx, order, items); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	t.Cleanup(func() { database.Exec(`DELETE FROM outbox_events WHERE event_key = $1`, order.ID) })
+
+	if err := repo.UpdateStatus(ctx, order.ID, "confirmed"); err != nil {
+		t.Fatalf("UpdateStatus() error = %v", err)
+	}
+
+	// A failed change must not leave an event behind
+	if err := repo.UpdateStatus(tenant.WithID(context.Background(), "other-tenant"), order.ID, "cancelled"); err == nil {
+		t.Fatal("UpdateStatus() from another tenant succeeded")
+	}
+
+	if err := repo.Delete(ctx, order.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+
+	got := eventTypes(t, order.ID)
+	want := []string{orderevents.TypeOrderCreated, orderevents.TypeOrderStatusChanged, orderevents.TypeOrderDeleted}
+	if len(got) != len(want) {
+		t.Fatalf("outbox events = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("outbox events = %v, want %v", got, want)
+			break
+		}
+	}
+}
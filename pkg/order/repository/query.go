@@ -0,0 +1,158 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/internal/pagination"
+	"github.com/kevindiu/monorepo-go-example/internal/tenant"
+)
+
+// Orderings of ListAfter. Ascending orderings name just the column, as in
+// an AIP-132 order_by.
+const (
+	OrderByCreatedAtDesc = "created_at desc"
+	OrderByCreatedAt     = "created_at"
+	OrderByTotalDesc     = "total_amount_minor desc"
+	OrderByTotal         = "total_amount_minor"
+)
+
+// ordering is the sort of a listing: a column, then ID as tiebreaker
+type ordering struct {
+	column string
+	desc   bool
+}
+
+// orderings maps every ordering ListAfter accepts to its sort
+var orderings = map[string]ordering{
+	OrderByCreatedAtDesc: {column: "created_at", desc: true},
+	OrderByCreatedAt:     {column: "created_at"},
+	OrderByTotalDesc:     {column: "total_minor", desc: true},
+	OrderByTotal:         {column: "total_minor"},
+}
+
+// ValidOrderBy reports whether ListAfter accepts orderBy
+func ValidOrderBy(orderBy string) bool {
+	_, ok := orderings[orderBy]
+	return ok
+}
+
+// Filter selects the orders ListAfter and Count return. Zero fields do not
+// filter.
+type Filter struct {
+	UserID string
+	Status string
+	// CreatedAfter is inclusive and CreatedBefore exclusive
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+	// MinTotal and MaxTotal bound TotalAmount, inclusive
+	MinTotal int64
+	MaxTotal int64
+	// OrderBy is one of the OrderBy constants; empty is newest first
+	OrderBy string
+}
+
+// sort returns the ordering of the listing f selects
+func (f Filter) sort() (ordering, error) {
+	if f.OrderBy == "" {
+		return orderings[OrderByCreatedAtDesc], nil
+	}
+	o, ok := orderings[f.OrderBy]
+	if !ok {
+		return ordering{}, errors.WithCode(errors.Newf("cannot order orders by %q", f.OrderBy), errors.CodeInvalidInput)
+	}
+	return o, nil
+}
+
+// where returns the conditions selecting the orders of f in the tenant on
+// ctx
+func (f Filter) where(ctx context.Context) *query {
+	q := &query{}
+	q.where("tenant_id = %s", tenant.ID(ctx))
+	if f.UserID != "" {
+		q.where("user_id = %s", f.UserID)
+	}
+	if f.Status != "" {
+		q.where("status = %s", f.Status)
+	}
+	if !f.CreatedAfter.IsZero() {
+		q.where("created_at >= %s", f.CreatedAfter)
+	}
+	if !f.CreatedBefore.IsZero() {
+		q.where("created_at < %s", f.CreatedBefore)
+	}
+	if f.MinTotal != 0 {
+		q.where("total_minor >= %s", f.MinTotal)
+	}
+	if f.MaxTotal != 0 {
+		q.where("total_minor <= %s", f.MaxTotal)
+	}
+	return q
+}
+
+// CursorAfter returns the cursor resuming a listing after order, whatever
+// the listing is ordered by
+func CursorAfter(order *Order) *pagination.Cursor {
+	return &pagination.Cursor{CreatedAt: order.CreatedAt, Value: order.TotalAmount, ID: order.ID}
+}
+
+// query composes the conditions of a WHERE clause, numbering their
+// placeholders in the order arguments are added
+type query struct {
+	conds []string
+	args  []interface{}
+}
+
+// arg adds an argument and returns its placeholder
+func (q *query) arg(v interface{}) string {
+	q.args = append(q.args, v)
+	return "$" + strconv.Itoa(len(q.args))
+}
+
+// where adds a condition. Each %s verb of format is replaced by the
+// placeholder of the matching argument.
+func (q *query) where(format string, args ...interface{}) {
+	placeholders := make([]interface{}, len(args))
+	for i, v := range args {
+		placeholders[i] = q.arg(v)
+	}
+	q.conds = append(q.conds, fmt.Sprintf(format, placeholders...))
+}
+
+// after adds the condition seeking past cursor in rows sorted by o
+func (q *query) after(o ordering, cursor *pagination.Cursor) {
+	var key interface{} = cursor.CreatedAt
+	if o.column != "created_at" {
+		key = cursor.Value
+	}
+	op := ">"
+	if o.desc {
+		op = "<"
+	}
+	q.where("("+o.column+", id) "+op+" (%s, %s)", key, cursor.ID)
+}
+
+// String returns the conditions joined into a WHERE clause body
+func (q *query) String() string {
+	return strings.Join(q.conds, " AND ")
+}
@@ -0,0 +1,92 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package repository
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/internal/pagination"
+	"github.com/kevindiu/monorepo-go-example/internal/tenant"
+)
+
+func TestFilterWhere(t *testing.T) {
+	ctx := tenant.WithID(context.Background(), "acme")
+	since := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	cursor := &pagination.Cursor{CreatedAt: since, Value: 1500, ID: "o1"}
+
+	tests := []struct {
+		name      string
+		filter    Filter
+		after     *pagination.Cursor
+		wantWhere string
+		wantArgs  []interface{}
+	}{
+		{name: "tenant only", wantWhere: "tenant_id = $1", wantArgs: []interface{}{"acme"}},
+		{
+			name:      "every filter",
+			filter:    Filter{UserID: "u1", Status: "pending", CreatedAfter: since, CreatedBefore: since.Add(time.Hour), MinTotal: 100, MaxTotal: 900},
+			wantWhere: "tenant_id = $1 AND user_id = $2 AND status = $3 AND created_at >= $4 AND created_at < $5 AND total_minor >= $6 AND total_minor <= $7",
+			wantArgs:  []interface{}{"acme", "u1", "pending", since, since.Add(time.Hour), int64(100), int64(900)},
+		},
+		{
+			name:      "newest first after cursor",
+			filter:    Filter{UserID: "u1"},
+			after:     cursor,
+			wantWhere: "tenant_id = $1 AND user_id = $2 AND (created_at, id) < ($3, $4)",
+			wantArgs:  []interface{}{"acme", "u1", since, "o1"},
+		},
+		{
+			name:      "cheapest first after cursor",
+			filter:    Filter{OrderBy: OrderByTotal},
+			after:     cursor,
+			wantWhere: "tenant_id = $1 AND (total_minor, id) > ($2, $3)",
+			wantArgs:  []interface{}{"acme", int64(1500), "o1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sort, err := tt.filter.sort()
+			if err != nil {
+				t.Fatalf("sort() error = %v", err)
+			}
+			q := tt.filter.where(ctx)
+			if tt.after != nil {
+				q.after(sort, tt.after)
+			}
+			if got := q.String(); got != tt.wantWhere {
+				t.Errorf("where = %q, want %q", got, tt.wantWhere)
+			}
+			if fmt.Sprint(q.args) != fmt.Sprint(tt.wantArgs) {
+				t.Errorf("args = %v, want %v", q.args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestFilterSort(t *testing.T) {
+	if _, err := (Filter{OrderBy: "user_id"}).sort(); errors.GetCode(err) != errors.CodeInvalidInput {
+		t.Errorf("sort() error = %v, want invalid input", err)
+	}
+	if o, err := (Filter{}).sort(); err != nil || o.column != "created_at" || !o.desc {
+		t.Errorf("sort() = %+v, %v, want newest first", o, err)
+	}
+}
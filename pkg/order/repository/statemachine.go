@@ -0,0 +1,52 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package repository
+
+import (
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+)
+
+// transitions lists the statuses an order may move to from each status.
+// Orders move forward one step at a time and can be cancelled until they
+// ship. Delivered and cancelled orders are final.
+var transitions = map[string][]string{
+	"pending":   {"confirmed", "cancelled"},
+	"confirmed": {"shipped", "cancelled"},
+	"shipped":   {"delivered"},
+	"delivered": nil,
+	"cancelled": nil,
+}
+
+// CanTransition reports whether an order may move from one status to
+// another
+func CanTransition(from, to string) bool {
+	for _, next := range transitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckTransition returns an invalid input error naming the transition
+// when an order may not move from one status to another
+func CheckTransition(from, to string) error {
+	if CanTransition(from, to) {
+		return nil
+	}
+	return errors.WithCode(errors.Newf("cannot change order status from %s to %s", from, to), errors.CodeInvalidInput)
+}
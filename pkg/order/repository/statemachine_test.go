@@ -0,0 +1,53 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package repository
+
+import "testing"
+
+func TestCanTransition(t *testing.T) {
+	tests := []struct {
+		from string
+		to   string
+		want bool
+	}{
+		{"pending", "confirmed", true},
+		{"pending", "cancelled", true},
+		{"pending", "shipped", false},
+		{"pending", "delivered", false},
+		{"pending", "pending", false},
+		{"confirmed", "shipped", true},
+		{"confirmed", "cancelled", true},
+		{"confirmed", "pending", false},
+		{"confirmed", "delivered", false},
+		{"shipped", "delivered", true},
+		{"shipped", "cancelled", false},
+		{"shipped", "confirmed", false},
+		{"delivered", "pending", false},
+		{"delivered", "cancelled", false},
+		{"cancelled", "pending", false},
+		{"cancelled", "confirmed", false},
+		{"unknown", "confirmed", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.from+" to "+tt.to, func(t *testing.T) {
+			if got := CanTransition(tt.from, tt.to); got != tt.want {
+				t.Errorf("CanTransition(%q, %q) = %v, want %v", tt.from, tt.to, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package saga
+
+import (
+	"context"
+
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/internal/saga"
+	"github.com/kevindiu/monorepo-go-example/internal/tenant"
+	"github.com/kevindiu/monorepo-go-example/pkg/order/repository"
+)
+
+// FulfillmentSaga is the name of the order fulfillment saga
+const FulfillmentSaga = "order_fulfillment"
+
+// Keys stored in the saga data
+const (
+	keyOrderID         = "order_id"
+	keyTenantID        = "tenant_id"
+	keyReservationID   = "reservation_id"
+	keyAuthorizationID = "authorization_id"
+)
+
+// Inventory reserves stock for orders
+type Inventory interface {
+	Reserve(ctx context.Context, orderID string, items []*repository.OrderItem) (string, error)
+	Release(ctx context.Context, reservationID string) error
+}
+
+// Payments authorizes and voids payments for orders
+type Payments interface {
+	// Authorize authorizes amount, in the minor units of currency
+	Authorize(ctx context.Context, orderID string, amount int64, currency string) (string, error)
+	Void(ctx context.Context, authorizationID string) error
+}
+
+// NewFulfillment defines the order fulfillment saga:
+//
+//	validate_order    -> cancel the order
+//	reserve_inventory -> release the reservation
+//	authorize_payment -> void the authorization
+//	confirm_order
+//
+// Any failure cancels the order after releasing whatever was acquired.
+func NewFulfillment(repo repository.Repository, inventory Inventory, payments Payments) *saga.Definition {
+	return &saga.Definition{
+		Name: FulfillmentSaga,
+		Steps: []saga.Step{
+			{
+				Name: "validate_order",
+				Action: func(ctx context.Context, data map[string]string) error {
+					order, err := repo.Get(scoped(ctx, data), data[keyOrderID])
+					if err != nil {
+						return err
+					}
+					if order.Status != "pending" {
+						return errors.WithCode(errors.Newf("order is %s, not pending", order.Status), errors.CodeConflict)
+					}
+					return nil
+				},
+				Compensate: func(ctx context.Context, data map[string]string) error {
+					return repo.UpdateStatus(scoped(ctx, data), data[keyOrderID], "cancelled")
+				},
+			},
+			{
+				Name: "reserve_inventory",
+				Action: func(ctx context.Context, data map[string]string) error {
+					if data[keyReservationID] != "" {
+						return nil
+					}
+					_, items, err := repo.GetByID(scoped(ctx, data), data[keyOrderID])
+					if err != nil {
+						return err
+					}
+					reservationID, err := inventory.Reserve(ctx, data[keyOrderID], items)
+					if err != nil {
+						return err
+					}
+					data[keyReservationID] = reservationID
+					return nil
+				},
+				Compensate: func(ctx context.Context, data map[string]string) error {
+					if data[keyReservationID] == "" {
+						return nil
+					}
+					return inventory.Release(ctx, data[keyReservationID])
+				},
+			},
+			{
+				Name: "authorize_payment",
+				Action: func(ctx context.Context, data map[string]string) error {
+					if data[keyAuthorizationID] != "" {
+						return nil
+					}
+					order, err := repo.Get(scoped(ctx, data), data[keyOrderID])
+					if err != nil {
+						return err
+					}
+					authorizationID, err := payments.Authorize(ctx, order.ID, order.TotalAmount, order.Currency)
+					if err != nil {
+						return err
+					}
+					data[keyAuthorizationID] = authorizationID
+					return nil
+				},
+				Compensate: func(ctx context.Context, data map[string]string) error {
+					if data[keyAuthorizationID] == "" {
+						return nil
+					}
+					return payments.Void(ctx, data[keyAuthorizationID])
+				},
+			},
+			{
+				Name: "confirm_order",
+				Action: func(ctx context.Context, data map[string]string) error {
+					return repo.UpdateStatus(scoped(ctx, data), data[keyOrderID], "confirmed")
+				},
+			},
+		},
+	}
+}
+
+// StartFulfillment runs the fulfillment saga for an order
+func StartFulfillment(ctx context.Context, o *saga.Orchestrator, orderID string) (*saga.State, error) {
+	if orderID == "" {
+		return nil, errors.WithCode(errors.New("order_id is required"), errors.CodeInvalidInput)
+	}
+	return o.Start(ctx, FulfillmentSaga, map[string]string{
+		keyOrderID:  orderID,
+		keyTenantID: tenant.ID(ctx),
+	})
+}
+
+// scoped returns ctx scoped to the tenant the saga was started for, so
+// resumed sagas act on the same tenant as the original request
+func scoped(ctx context.Context, data map[string]string) context.Context {
+	return tenant.WithID(ctx, data[keyTenantID])
+}
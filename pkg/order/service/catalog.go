@@ -0,0 +1,138 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package service
+
+import (
+	"context"
+
+	orderv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/order/v1"
+	productv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/product/v1"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/internal/log"
+)
+
+// maxProductBatch is the most products the product service returns per
+// lookup
+const maxProductBatch = 100
+
+// Product is the catalog entry of an order item
+type Product struct {
+	ID         string
+	Name       string
+	PriceMinor int64
+	Currency   string
+	Active     bool
+}
+
+// Catalog looks up the products orders are placed for
+type Catalog interface {
+	// Products returns the products with the given IDs, keyed by ID. IDs
+	// that are not in the catalog are left out.
+	Products(ctx context.Context, ids []string) (map[string]*Product, error)
+}
+
+// ProductClient looks products up with the product service
+type ProductClient struct {
+	client productv1.ProductServiceClient
+}
+
+// NewProductClient looks products up through client
+func NewProductClient(client productv1.ProductServiceClient) *ProductClient {
+	return &ProductClient{client: client}
+}
+
+// Products returns the products with the given IDs, keyed by ID
+func (c *ProductClient) Products(ctx context.Context, ids []string) (map[string]*Product, error) {
+	products := make(map[string]*Product, len(ids))
+	for start := 0; start < len(ids); start += maxProductBatch {
+		batch := ids[start:min(start+maxProductBatch, len(ids))]
+		resp, err := c.client.BatchGetProducts(ctx, &productv1.BatchGetProductsRequest{Ids: batch})
+		if err != nil {
+			return nil, errors.WithCode(errors.Wrap(err, "failed to look up products"), errors.CodeUnavailable)
+		}
+		for _, p := range resp.GetProducts() {
+			products[p.GetId()] = &Product{
+				ID:         p.GetId(),
+				Name:       p.GetName(),
+				PriceMinor: p.GetPriceMinor(),
+				Currency:   p.GetCurrency(),
+				Active:     p.GetActive(),
+			}
+		}
+	}
+	return products, nil
+}
+
+// WithCatalog prices the items of new orders, and items added to orders,
+// with the products in catalog instead of the prices clients send. Without
+// it, client prices are trusted.
+func WithCatalog(catalog Catalog) Option {
+	return func(s *service) {
+		s.catalog = catalog
+	}
+}
+
+// catalogPrices looks up the products of items in the catalog and returns
+// them priced in minor units of code, keyed by ID. Products priced in
+// another currency are converted when conversion is enabled. It returns
+// nil without a catalog.
+func (s *service) catalogPrices(ctx context.Context, items []*orderv1.OrderItem, code string) (map[string]*Product, error) {
+	if s.catalog == nil {
+		return nil, nil
+	}
+
+	seen := make(map[string]bool, len(items))
+	ids := make([]string, 0, len(items))
+	for _, item := range items {
+		// orderItems reports items without a product
+		if id := item.GetProductId(); id != "" && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	found, err := s.catalog.Products(ctx, ids)
+	if err != nil {
+		s.loggerFor(ctx).Error("Failed to look up products", log.Error(err))
+		return nil, err
+	}
+
+	products := make(map[string]*Product, len(ids))
+	for _, id := range ids {
+		p, ok := found[id]
+		if !ok {
+			return nil, errors.WithCode(errors.Newf("product %s does not exist", id), errors.CodeInvalidInput)
+		}
+		if !p.Active {
+			return nil, errors.WithCode(errors.Newf("product %s is not available", id), errors.CodeInvalidInput)
+		}
+
+		priced := *p
+		if priced.Currency != code {
+			if s.converter == nil {
+				return nil, errors.WithCode(errors.Newf("product %s is priced in %s, not %s", id, p.Currency, code), errors.CodeInvalidInput)
+			}
+			if priced.PriceMinor, err = s.converter.Convert(ctx, p.PriceMinor, p.Currency, code); err != nil {
+				s.loggerFor(ctx).Error("Failed to convert product price", log.Error(err))
+				return nil, err
+			}
+			priced.Currency = code
+		}
+		products[id] = &priced
+	}
+	return products, nil
+}
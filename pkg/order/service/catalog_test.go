@@ -0,0 +1,166 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	orderv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/order/v1"
+	productv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/product/v1"
+	"github.com/kevindiu/monorepo-go-example/internal/currency"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/internal/log"
+	"github.com/kevindiu/monorepo-go-example/pkg/order/repository"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeProductService answers BatchGetProducts from products, or with err,
+// counting calls
+type fakeProductService struct {
+	productv1.ProductServiceClient
+	products map[string]*productv1.Product
+	err      error
+	calls    int
+}
+
+func (f *fakeProductService) BatchGetProducts(ctx context.Context, req *productv1.BatchGetProductsRequest, opts ...grpc.CallOption) (*productv1.BatchGetProductsResponse, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	resp := &productv1.BatchGetProductsResponse{}
+	for _, id := range req.GetIds() {
+		if p, ok := f.products[id]; ok {
+			resp.Products = append(resp.Products, p)
+		}
+	}
+	return resp, nil
+}
+
+func TestProductClientProducts(t *testing.T) {
+	fake := &fakeProductService{products: make(map[string]*productv1.Product)}
+	var ids []string
+	for i := 0; i < maxProductBatch+1; i++ {
+		id := fmt.Sprintf("prod-%d", i)
+		fake.products[id] = &productv1.Product{Id: id, PriceMinor: int64(i + 1), Currency: "USD", Active: true}
+		ids = append(ids, id)
+	}
+	ids = append(ids, "prod-missing")
+
+	got, err := NewProductClient(fake).Products(context.Background(), ids)
+	if err != nil {
+		t.Fatalf("Products() error = %v", err)
+	}
+	if len(got) != maxProductBatch+1 {
+		t.Errorf("Products() returned %d products, want %d", len(got), maxProductBatch+1)
+	}
+	if fake.calls != 2 {
+		t.Errorf("Products() made %d calls, want 2", fake.calls)
+	}
+}
+
+func TestCreateOrderUsesCatalogPrices(t *testing.T) {
+	products := map[string]*productv1.Product{
+		"prod-1":   {Id: "prod-1", Name: "Widget", PriceMinor: 1250, Currency: "USD", Active: true},
+		"prod-eur": {Id: "prod-eur", Name: "Euro widget", PriceMinor: 900, Currency: "EUR", Active: true},
+		"prod-old": {Id: "prod-old", Name: "Retired widget", PriceMinor: 100, Currency: "USD"},
+	}
+	converter := currency.NewConverter(currency.NewStatic("USD", map[string]float64{"EUR": 0.9}), 0, nil)
+
+	tests := []struct {
+		name      string
+		productID string
+		converter *currency.Converter
+		err       error
+		wantCode  string
+		wantPrice int64
+		wantName  string
+	}{
+		{name: "catalog price replaces client price", productID: "prod-1", wantPrice: 1250, wantName: "Widget"},
+		{name: "unknown product", productID: "prod-2", wantCode: errors.CodeInvalidInput},
+		{name: "inactive product", productID: "prod-old", wantCode: errors.CodeInvalidInput},
+		{name: "other currency without conversion", productID: "prod-eur", wantCode: errors.CodeInvalidInput},
+		{name: "other currency converted", productID: "prod-eur", converter: converter, wantPrice: 1000, wantName: "Euro widget"},
+		{name: "product service down", productID: "prod-1", err: status.Error(codes.Unavailable, "connection refused"), wantCode: errors.CodeUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMockRepository()
+			catalog := NewProductClient(&fakeProductService{products: products, err: tt.err})
+			svc := New(repo, log.NewDefault(), WithCurrency("USD", tt.converter), WithCatalog(catalog))
+
+			resp, err := svc.CreateOrder(context.Background(), &orderv1.CreateOrderRequest{
+				UserId: "user-1",
+				Items:  []*orderv1.OrderItem{{ProductId: tt.productID, ProductName: "Cheap widget", Quantity: 2, PriceMinor: 1}},
+			})
+			if errors.GetCode(err) != tt.wantCode {
+				t.Fatalf("CreateOrder() error = %v, want code %q", err, tt.wantCode)
+			}
+			if tt.wantCode != "" {
+				if len(repo.orders) != 0 {
+					t.Errorf("CreateOrder() stored %d orders, want 0", len(repo.orders))
+				}
+				return
+			}
+
+			if got := resp.GetOrder().GetTotalAmountMinor(); got != 2*tt.wantPrice {
+				t.Errorf("CreateOrder() total = %d, want %d", got, 2*tt.wantPrice)
+			}
+			items := repo.orderItems[resp.GetOrder().GetId()]
+			if len(items) != 1 || items[0].Price != tt.wantPrice || items[0].ProductName != tt.wantName {
+				t.Errorf("CreateOrder() stored items %+v, want %s at %d", items, tt.wantName, tt.wantPrice)
+			}
+		})
+	}
+}
+
+func TestUpdateOrderUsesCatalogPrices(t *testing.T) {
+	repo := newMockRepository()
+	orderID := "11111111-1111-1111-1111-111111111111"
+	repo.orders[orderID] = &repository.Order{ID: orderID, UserID: "user-1", Status: "pending", Currency: "USD"}
+	repo.orderItems[orderID] = []*repository.OrderItem{{ID: "item-1", ProductID: "prod-1", Quantity: 1, Price: 1250}}
+
+	catalog := NewProductClient(&fakeProductService{products: map[string]*productv1.Product{
+		"prod-1": {Id: "prod-1", Name: "Widget", PriceMinor: 1250, Currency: "USD", Active: true},
+	}})
+	svc := New(repo, log.NewDefault(), WithCatalog(catalog))
+
+	_, err := svc.UpdateOrder(context.Background(), &orderv1.UpdateOrderRequest{
+		Id:       orderID,
+		AddItems: []*orderv1.OrderItem{{ProductId: "prod-1", Quantity: 1, PriceMinor: 1}},
+	})
+	if err != nil {
+		t.Fatalf("UpdateOrder() error = %v", err)
+	}
+	items := repo.orderItems[orderID]
+	if len(items) != 2 || items[1].Price != 1250 {
+		t.Errorf("UpdateOrder() stored items %+v, want the added item at 1250", items)
+	}
+
+	_, err = svc.UpdateOrder(context.Background(), &orderv1.UpdateOrderRequest{
+		Id:       orderID,
+		AddItems: []*orderv1.OrderItem{{ProductId: "prod-2", Quantity: 1, PriceMinor: 1}},
+	})
+	if errors.GetCode(err) != errors.CodeInvalidInput {
+		t.Errorf("UpdateOrder() with unknown product error = %v, want invalid input", err)
+	}
+}
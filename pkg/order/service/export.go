@@ -0,0 +1,67 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package service
+
+import (
+	orderv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/order/v1"
+	"github.com/kevindiu/monorepo-go-example/internal/log"
+	"github.com/kevindiu/monorepo-go-example/internal/pagination"
+	"github.com/kevindiu/monorepo-go-example/pkg/order/repository"
+)
+
+// exportBatchSize is the number of orders ExportOrders reads and sends at
+// a time
+const exportBatchSize = 500
+
+// ExportOrders streams the orders of a user, or of every user, newest
+// first. Orders are read by keyset cursor, so every batch costs the same
+// and orders created while the export runs do not shift it.
+func (s *service) ExportOrders(req *orderv1.ExportOrdersRequest, stream orderv1.OrderService_ExportOrdersServer) error {
+	ctx := stream.Context()
+	s.loggerFor(ctx).Info("Exporting orders", log.String("user_id", req.GetUserId()))
+
+	target, rates, err := s.targetCurrency(ctx, req.GetCurrency())
+	if err != nil {
+		return err
+	}
+
+	filter := repository.Filter{UserID: req.GetUserId()}
+	var after *pagination.Cursor
+	for {
+		orders, err := s.repo.ListAfter(ctx, filter, after, exportBatchSize)
+		if err != nil {
+			s.loggerFor(ctx).Error("Failed to list orders", log.Error(err))
+			return err
+		}
+		if len(orders) == 0 {
+			return nil
+		}
+
+		pbOrders, err := s.ordersToProto(ctx, orders, nil, target, rates)
+		if err != nil {
+			return err
+		}
+		if err := stream.Send(&orderv1.ExportOrdersResponse{Orders: pbOrders}); err != nil {
+			return err
+		}
+
+		if len(orders) < exportBatchSize {
+			return nil
+		}
+		after = repository.CursorAfter(orders[len(orders)-1])
+	}
+}
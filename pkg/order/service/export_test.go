@@ -0,0 +1,101 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	orderv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/order/v1"
+	"github.com/kevindiu/monorepo-go-example/internal/log"
+	"github.com/kevindiu/monorepo-go-example/pkg/order/repository"
+	"google.golang.org/grpc"
+)
+
+// mockExportStream collects the batches sent by ExportOrders
+type mockExportStream struct {
+	grpc.ServerStream
+	batches []*orderv1.ExportOrdersResponse
+}
+
+func (m *mockExportStream) Context() context.Context { return context.Background() }
+
+func (m *mockExportStream) Send(resp *orderv1.ExportOrdersResponse) error {
+	m.batches = append(m.batches, resp)
+	return nil
+}
+
+func TestExportOrders(t *testing.T) {
+	repo := newMockRepository()
+	created := time.Now()
+	total := exportBatchSize + 1
+	for i := 0; i < total; i++ {
+		id := fmt.Sprintf("o%04d", i)
+		userID := "u1"
+		if i%2 == 1 {
+			userID = "u2"
+		}
+		at := created.Add(time.Duration(i) * time.Second)
+		repo.orders[id] = &repository.Order{ID: id, UserID: userID, Status: "pending", CreatedAt: at, UpdatedAt: at}
+	}
+	svc := New(repo, log.NewDefault())
+
+	tests := []struct {
+		name        string
+		userID      string
+		wantOrders  int
+		wantBatches int
+	}{
+		{name: "every user", wantOrders: total, wantBatches: 2},
+		{name: "one user", userID: "u1", wantOrders: total/2 + 1, wantBatches: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stream := &mockExportStream{}
+			if err := svc.ExportOrders(&orderv1.ExportOrdersRequest{UserId: tt.userID}, stream); err != nil {
+				t.Fatalf("ExportOrders() error = %v", err)
+			}
+			if len(stream.batches) != tt.wantBatches {
+				t.Errorf("ExportOrders() sent %d batches, want %d", len(stream.batches), tt.wantBatches)
+			}
+
+			seen := make(map[string]bool)
+			last := ""
+			for _, batch := range stream.batches {
+				for _, o := range batch.GetOrders() {
+					if seen[o.GetId()] {
+						t.Fatalf("order %s exported twice", o.GetId())
+					}
+					if last != "" && o.GetId() > last {
+						t.Fatalf("order %s exported after %s, want newest first", o.GetId(), last)
+					}
+					if tt.userID != "" && o.GetUserId() != tt.userID {
+						t.Fatalf("order %s of user %s exported", o.GetId(), o.GetUserId())
+					}
+					seen[o.GetId()] = true
+					last = o.GetId()
+				}
+			}
+			if len(seen) != tt.wantOrders {
+				t.Errorf("ExportOrders() exported %d orders, want %d", len(seen), tt.wantOrders)
+			}
+		})
+	}
+}
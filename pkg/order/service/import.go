@@ -0,0 +1,159 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package service
+
+import (
+	"context"
+	"io"
+
+	orderv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/order/v1"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/internal/log"
+	"github.com/kevindiu/monorepo-go-example/pkg/order/repository"
+	"google.golang.org/grpc/status"
+)
+
+// DefaultImportChunkSize is the number of orders ImportOrders inserts per
+// transaction
+const DefaultImportChunkSize = 1000
+
+// WithImportChunkSize sets the number of orders ImportOrders inserts per
+// transaction
+func WithImportChunkSize(n int) Option {
+	return func(s *service) {
+		if n > 0 {
+			s.importChunkSize = n
+		}
+	}
+}
+
+// pendingImport is a validated order waiting for its chunk to be inserted
+type pendingImport struct {
+	row   int32
+	ref   string
+	order *repository.ImportedOrder
+}
+
+// ImportOrders imports the streamed orders in chunks, one transaction per
+// chunk. Invalid orders are skipped, and when a chunk fails its orders are
+// retried one at a time, so that only the failing orders are left out. The
+// failures are reported in the response. Chunks inserted before the
+// stream breaks stay imported.
+func (s *service) ImportOrders(stream orderv1.OrderService_ImportOrdersServer) error {
+	ctx := stream.Context()
+	resp := &orderv1.ImportOrdersResponse{}
+	chunk := make([]*pendingImport, 0, s.importChunkSize)
+
+	var position int32
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		for _, o := range req.GetOrders() {
+			position++
+			row := o.GetRow()
+			if row == 0 {
+				row = position
+			}
+
+			order, err := importedOrder(o, s.currency)
+			if err != nil {
+				resp.Errors = append(resp.Errors, &orderv1.ImportOrderError{Row: row, Ref: o.GetRef(), Message: err.Error()})
+				continue
+			}
+			chunk = append(chunk, &pendingImport{row: row, ref: o.GetRef(), order: order})
+			if len(chunk) == s.importChunkSize {
+				if err := s.importChunk(ctx, chunk, resp); err != nil {
+					return err
+				}
+				chunk = chunk[:0]
+			}
+		}
+	}
+	if err := s.importChunk(ctx, chunk, resp); err != nil {
+		return err
+	}
+
+	s.loggerFor(ctx).Info("Imported orders",
+		log.Int("imported", int(resp.GetImported())),
+		log.Int("failed", len(resp.GetErrors())),
+	)
+	return stream.SendAndClose(resp)
+}
+
+// importChunk inserts a chunk of orders, falling back to one order at a
+// time when the chunk fails, and records the outcome in resp
+func (s *service) importChunk(ctx context.Context, chunk []*pendingImport, resp *orderv1.ImportOrdersResponse) error {
+	if len(chunk) == 0 {
+		return nil
+	}
+
+	orders := make([]*repository.ImportedOrder, len(chunk))
+	for i, p := range chunk {
+		orders[i] = p.order
+	}
+	err := s.repo.Import(ctx, orders)
+	if err == nil {
+		resp.Imported += int32(len(chunk))
+		return nil
+	}
+	s.loggerFor(ctx).Warn("Order import chunk failed, retrying orders one at a time",
+		log.Int("orders", len(chunk)),
+		log.Error(err),
+	)
+
+	for _, p := range chunk {
+		if ctx.Err() != nil {
+			return status.FromContextError(ctx.Err()).Err()
+		}
+		if err := s.repo.Import(ctx, []*repository.ImportedOrder{p.order}); err != nil {
+			resp.Errors = append(resp.Errors, &orderv1.ImportOrderError{Row: p.row, Ref: p.ref, Message: err.Error()})
+			continue
+		}
+		resp.Imported++
+	}
+	return nil
+}
+
+// importedOrder validates an order to import, priced in code, and converts
+// it
+func importedOrder(o *orderv1.ImportOrder, code string) (*repository.ImportedOrder, error) {
+	if o.GetUserId() == "" {
+		return nil, errors.WithCode(errors.New("user_id is required"), errors.CodeInvalidInput)
+	}
+	// Imported orders keep the prices they were placed at
+	items, total, err := orderItems("items", o.GetItems(), code, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	order := &repository.Order{
+		UserID:      o.GetUserId(),
+		Status:      statusFromProto(o.GetStatus()),
+		TotalAmount: total,
+		Currency:    code,
+	}
+	if o.GetCreatedAt() != nil {
+		order.CreatedAt = o.GetCreatedAt().AsTime()
+	}
+	return &repository.ImportedOrder{Order: order, Items: items}, nil
+}
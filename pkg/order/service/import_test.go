@@ -0,0 +1,140 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package service
+
+import (
+	"context"
+	"io"
+	"reflect"
+	"testing"
+
+	orderv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/order/v1"
+	"github.com/kevindiu/monorepo-go-example/internal/log"
+	"google.golang.org/grpc"
+)
+
+// mockImportStream replays requests to ImportOrders and keeps its response
+type mockImportStream struct {
+	grpc.ServerStream
+	reqs []*orderv1.ImportOrdersRequest
+	resp *orderv1.ImportOrdersResponse
+}
+
+func (m *mockImportStream) Context() context.Context { return context.Background() }
+
+func (m *mockImportStream) Recv() (*orderv1.ImportOrdersRequest, error) {
+	if len(m.reqs) == 0 {
+		return nil, io.EOF
+	}
+	req := m.reqs[0]
+	m.reqs = m.reqs[1:]
+	return req, nil
+}
+
+func (m *mockImportStream) SendAndClose(resp *orderv1.ImportOrdersResponse) error {
+	m.resp = resp
+	return nil
+}
+
+// importOrder returns a valid order to import for userID
+func importOrder(ref, userID string) *orderv1.ImportOrder {
+	return &orderv1.ImportOrder{
+		Ref:    ref,
+		UserId: userID,
+		Items:  []*orderv1.OrderItem{{ProductId: "prod-1", ProductName: "Mug", Quantity: 2, Price: 4.5}},
+	}
+}
+
+func TestImportOrders(t *testing.T) {
+	tests := []struct {
+		name         string
+		reqs         []*orderv1.ImportOrdersRequest
+		missingUsers map[string]bool
+		wantImported int32
+		wantImports  int
+		wantErrors   []*orderv1.ImportOrderError
+	}{
+		{
+			name: "chunked across requests",
+			reqs: []*orderv1.ImportOrdersRequest{
+				{Orders: []*orderv1.ImportOrder{importOrder("a", "u1"), importOrder("b", "u1"), importOrder("c", "u1")}},
+				{Orders: []*orderv1.ImportOrder{importOrder("d", "u1"), importOrder("e", "u1")}},
+			},
+			wantImported: 5,
+			wantImports:  3,
+		},
+		{
+			name: "invalid orders are skipped",
+			reqs: []*orderv1.ImportOrdersRequest{
+				{Orders: []*orderv1.ImportOrder{importOrder("a", ""), importOrder("b", "u1"), {Ref: "c", UserId: "u1"}}},
+			},
+			wantImported: 1,
+			wantImports:  1,
+			wantErrors: []*orderv1.ImportOrderError{
+				{Row: 1, Ref: "a", Message: "user_id is required"},
+				{Row: 3, Ref: "c", Message: "at least one item is required"},
+			},
+		},
+		{
+			name: "failed chunk is retried per order",
+			reqs: []*orderv1.ImportOrdersRequest{
+				{Orders: []*orderv1.ImportOrder{importOrder("a", "u1"), importOrder("b", "gone")}},
+			},
+			missingUsers: map[string]bool{"gone": true},
+			wantImported: 1,
+			wantImports:  3,
+			wantErrors:   []*orderv1.ImportOrderError{{Row: 2, Ref: "b", Message: "user does not exist"}},
+		},
+		{
+			name: "rows from the source file are echoed",
+			reqs: []*orderv1.ImportOrdersRequest{
+				{Orders: []*orderv1.ImportOrder{{Ref: "a", Row: 7}}},
+			},
+			wantErrors: []*orderv1.ImportOrderError{{Row: 7, Ref: "a", Message: "user_id is required"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMockRepository()
+			repo.missingUsers = tt.missingUsers
+			svc := New(repo, log.NewDefault(), WithImportChunkSize(2))
+
+			stream := &mockImportStream{reqs: tt.reqs}
+			if err := svc.ImportOrders(stream); err != nil {
+				t.Fatalf("ImportOrders() error = %v", err)
+			}
+
+			if stream.resp.GetImported() != tt.wantImported {
+				t.Errorf("imported = %d, want %d", stream.resp.GetImported(), tt.wantImported)
+			}
+			if repo.imports != tt.wantImports {
+				t.Errorf("Import() called %d times, want %d", repo.imports, tt.wantImports)
+			}
+			if len(repo.orders) != int(tt.wantImported) {
+				t.Errorf("repository holds %d orders, want %d", len(repo.orders), tt.wantImported)
+			}
+			var gotErrors []*orderv1.ImportOrderError
+			for _, e := range stream.resp.GetErrors() {
+				gotErrors = append(gotErrors, &orderv1.ImportOrderError{Row: e.GetRow(), Ref: e.GetRef(), Message: e.GetMessage()})
+			}
+			if !reflect.DeepEqual(gotErrors, tt.wantErrors) {
+				t.Errorf("errors = %v, want %v", gotErrors, tt.wantErrors)
+			}
+		})
+	}
+}
@@ -18,12 +18,24 @@ package service
 
 import (
 	"context"
-	"strconv"
+	"fmt"
+	"math"
+	"strings"
 
+	"github.com/google/uuid"
 	orderv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/order/v1"
+	"github.com/kevindiu/monorepo-go-example/internal/currency"
 	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/internal/fieldmask"
+	"github.com/kevindiu/monorepo-go-example/internal/idempotency"
 	"github.com/kevindiu/monorepo-go-example/internal/log"
+	"github.com/kevindiu/monorepo-go-example/internal/money"
+	"github.com/kevindiu/monorepo-go-example/internal/pagination"
 	"github.com/kevindiu/monorepo-go-example/pkg/order/repository"
+	"github.com/kevindiu/monorepo-go-example/pkg/order/watch"
+	"github.com/kevindiu/monorepo-go-example/pkg/promotion"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
@@ -70,51 +82,148 @@ type Service interface {
 
 type service struct {
 	orderv1.UnimplementedOrderServiceServer
-	repo   repository.Repository
-	logger *log.Logger
+	repo       repository.Repository
+	logger     *log.Logger
+	currency   string
+	converter  *currency.Converter
+	promotions *promotion.Engine
+	pageTokens *pagination.Tokens
+	watch      *watch.Hub
+	users      Users
+	catalog    Catalog
+	// idempotency remembers the responses of CreateOrder by idempotency key
+	idempotency idempotency.Store
+	// importChunkSize is the number of orders ImportOrders inserts per
+	// transaction
+	importChunkSize int
+}
+
+// Option configures the order service
+type Option func(*service)
+
+// WithCurrency sets the currency orders are priced in, and lets callers of
+// GetOrder and ListOrders request amounts converted to another currency
+// through conv. Without a converter, only the base currency is served.
+func WithCurrency(base string, conv *currency.Converter) Option {
+	return func(s *service) {
+		s.currency = strings.ToUpper(base)
+		s.converter = conv
+	}
+}
+
+// WithPromotions lets CreateOrder redeem coupon codes through engine
+func WithPromotions(engine *promotion.Engine) Option {
+	return func(s *service) {
+		s.promotions = engine
+	}
+}
+
+// WithPageTokens signs the page tokens of ListOrders with tokens, which
+// every replica must share. Without it, tokens are signed with a random
+// key and only work on the replica that issued them.
+func WithPageTokens(tokens *pagination.Tokens) Option {
+	return func(s *service) {
+		s.pageTokens = tokens
+	}
+}
+
+// WithWatch serves WatchOrder with the status changes announced on hub
+// (see watch.Listen). Without it, WatchOrder is unavailable.
+func WithWatch(hub *watch.Hub) Option {
+	return func(s *service) {
+		s.watch = hub
+	}
+}
+
+// WithIdempotency makes CreateOrder calls carrying an idempotency key
+// return the original order when retried
+func WithIdempotency(store idempotency.Store) Option {
+	return func(s *service) {
+		s.idempotency = store
+	}
 }
 
 // New creates a new order service
-func New(repo repository.Repository, logger *log.Logger) Service {
-	return &service{
-		repo:   repo,
-		logger: logger,
+func New(repo repository.Repository, logger *log.Logger, opts ...Option) Service {
+	return newService(repo, logger, opts...)
+}
+
+func newService(repo repository.Repository, logger *log.Logger, opts ...Option) *service {
+	s := &service{
+		repo:            repo,
+		logger:          logger,
+		currency:        "USD",
+		importChunkSize: DefaultImportChunkSize,
+	}
+	for _, opt := range opts {
+		opt(s)
 	}
+	if s.pageTokens == nil {
+		s.pageTokens = pagination.New("")
+	}
+	return s
+}
+
+// loggerFor returns the request-scoped logger of ctx, which carries the
+// call's method, request ID and user, or the service's logger outside a
+// gRPC call
+func (s *service) loggerFor(ctx context.Context) *log.Logger {
+	return log.FromContextOr(ctx, s.logger)
 }
 
-// CreateOrder creates a new order
+// createOrderScope scopes the idempotency keys of CreateOrder
+const createOrderScope = "order.v1.OrderService/CreateOrder"
+
+// CreateOrder creates a new order. A retry carrying the idempotency key of a
+// completed call returns the order that call created.
 func (s *service) CreateOrder(ctx context.Context, req *orderv1.CreateOrderRequest) (*orderv1.CreateOrderResponse, error) {
-	s.logger.Info("Creating order", log.String("user_id", req.GetUserId()))
+	key := idempotency.Key(ctx, req.GetIdempotencyKey())
+
+	// The key may arrive in the request or the metadata; either way the
+	// rest of the request identifies it
+	fingerprint := proto.Clone(req).(*orderv1.CreateOrderRequest)
+	fingerprint.IdempotencyKey = ""
+
+	return idempotency.Do(ctx, s.idempotency, createOrderScope, key, fingerprint, func(ctx context.Context) (*orderv1.CreateOrderResponse, error) {
+		return s.createOrder(ctx, req)
+	})
+}
+
+func (s *service) createOrder(ctx context.Context, req *orderv1.CreateOrderRequest) (*orderv1.CreateOrderResponse, error) {
+	s.loggerFor(ctx).Info("Creating order", log.String("user_id", req.GetUserId()))
 
 	// Validate request
 	if req.GetUserId() == "" {
-		return nil, errors.WithCode(errors.New("user_id is required"), errors.CodeInvalidInput)
+		return nil, errors.InvalidField("user_id", "user_id is required")
 	}
 
-	if len(req.GetItems()) == 0 {
-		return nil, errors.WithCode(errors.New("at least one item is required"), errors.CodeInvalidInput)
+	products, err := s.catalogPrices(ctx, req.GetItems(), s.currency)
+	if err != nil {
+		return nil, err
+	}
+	items, totalAmount, err := orderItems("items", req.GetItems(), s.currency, products)
+	if err != nil {
+		return nil, err
 	}
 
-	// Calculate total amount
-	var totalAmount float64
-	items := make([]*repository.OrderItem, len(req.GetItems()))
-	for i, item := range req.GetItems() {
-		if item.GetProductId() == "" {
-			return nil, errors.WithCode(errors.New("product_id is required"), errors.CodeInvalidInput)
+	if s.users != nil {
+		exists, err := s.users.Exists(ctx, req.GetUserId())
+		if err != nil {
+			s.loggerFor(ctx).Error("Failed to check user", log.Error(err))
+			return nil, err
 		}
-		if item.GetQuantity() <= 0 {
-			return nil, errors.WithCode(errors.New("quantity must be positive"), errors.CodeInvalidInput)
-		}
-		if item.GetPrice() <= 0 {
-			return nil, errors.WithCode(errors.New("price must be positive"), errors.CodeInvalidInput)
+		if !exists {
+			return nil, errors.WithCode(errors.Newf("user %s does not exist", req.GetUserId()), errors.CodeInvalidInput)
 		}
+	}
 
-		items[i] = &repository.OrderItem{
-			ProductID: item.GetProductId(),
-			Quantity:  item.GetQuantity(),
-			Price:     item.GetPrice(),
-		}
-		totalAmount += float64(item.GetQuantity()) * item.GetPrice()
+	// Apply coupons
+	discounts, err := s.applyCoupons(ctx, req.GetUserId(), totalAmount, req.GetCouponCodes())
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range discounts {
+		totalAmount -= d.Amount
 	}
 
 	// Create order
@@ -122,189 +231,567 @@ func (s *service) CreateOrder(ctx context.Context, req *orderv1.CreateOrderReque
 		UserID:      req.GetUserId(),
 		Status:      "pending",
 		TotalAmount: totalAmount,
+		Currency:    s.currency,
+		Discounts:   discounts,
 	}
 
 	if err := s.repo.Create(ctx, order, items); err != nil {
-		s.logger.Error("Failed to create order", log.Error(err))
+		s.loggerFor(ctx).Error("Failed to create order", log.Error(err))
 		return nil, err
 	}
 
-	s.logger.Info("Order created successfully", log.String("order_id", order.ID))
+	s.loggerFor(ctx).Info("Order created successfully", log.String("order_id", order.ID))
 
-	return &orderv1.CreateOrderResponse{
-		Order: &orderv1.Order{
-			Id:          order.ID,
-			UserId:      order.UserID,
-			Status:      statusToProto(order.Status),
-			TotalAmount: order.TotalAmount,
-			CreatedAt:   timestamppb.New(order.CreatedAt),
-			UpdatedAt:   timestamppb.New(order.UpdatedAt),
-		},
-	}, nil
+	return &orderv1.CreateOrderResponse{Order: orderToProto(order)}, nil
+}
+
+// orderToProto converts an order, without its items, to protobuf
+func orderToProto(order *repository.Order) *orderv1.Order {
+	return &orderv1.Order{
+		Id:               order.ID,
+		UserId:           order.UserID,
+		Status:           statusToProto(order.Status),
+		TotalAmount:      money.ToMajor(order.TotalAmount, order.Currency),
+		TotalAmountMinor: order.TotalAmount,
+		Currency:         order.Currency,
+		Discounts:        discountsToProto(order.Discounts, order.Currency),
+		CreatedAt:        timestamppb.New(order.CreatedAt),
+		UpdatedAt:        timestamppb.New(order.UpdatedAt),
+	}
+}
+
+// orderItems validates the items of an order, given in the request field
+// named field, and returns them with their total amount, in minor units of
+// code. Items of the given products are
+// priced and named after them.
+func orderItems(field string, pb []*orderv1.OrderItem, code string, products map[string]*Product) ([]*repository.OrderItem, int64, error) {
+	if len(pb) == 0 {
+		return nil, 0, errors.InvalidField(field, "at least one item is required")
+	}
+
+	var total int64
+	items := make([]*repository.OrderItem, len(pb))
+	for i, item := range pb {
+		if item.GetProductId() == "" {
+			return nil, 0, errors.InvalidField(fmt.Sprintf("%s[%d].product_id", field, i), "product_id is required")
+		}
+		if item.GetQuantity() <= 0 {
+			return nil, 0, errors.InvalidField(fmt.Sprintf("%s[%d].quantity", field, i), "quantity must be positive")
+		}
+		var price int64
+		name := item.GetProductName()
+		if p, ok := products[item.GetProductId()]; ok {
+			// Catalog prices replace the prices clients send
+			name, price = p.Name, p.PriceMinor
+		} else {
+			var err error
+			if price, err = itemPrice(item, code); err != nil {
+				return nil, 0, err
+			}
+		}
+		line, err := money.Multiply(price, int64(item.GetQuantity()))
+		if err != nil || total > math.MaxInt64-line {
+			return nil, 0, errors.WithCode(errors.New("order total is out of range"), errors.CodeInvalidInput)
+		}
+
+		items[i] = &repository.OrderItem{
+			ProductID:   item.GetProductId(),
+			ProductName: name,
+			Quantity:    item.GetQuantity(),
+			Price:       price,
+		}
+		total += line
+	}
+	return items, total, nil
+}
+
+// itemPrice returns the unit price of an item in minor units of code,
+// preferring price_minor over the float price of older clients
+func itemPrice(item *orderv1.OrderItem, code string) (int64, error) {
+	price := item.GetPriceMinor()
+	if price == 0 {
+		var err error
+		if price, err = money.FromMajor(item.GetPrice(), code); err != nil {
+			return 0, err
+		}
+	}
+	if price <= 0 {
+		return 0, errors.WithCode(errors.New("price must be positive"), errors.CodeInvalidInput)
+	}
+	return price, nil
+}
+
+// applyCoupons validates coupon codes and returns the discounts to record
+func (s *service) applyCoupons(ctx context.Context, userID string, subtotal int64, codes []string) ([]*repository.Discount, error) {
+	if len(codes) == 0 {
+		return nil, nil
+	}
+	if s.promotions == nil {
+		return nil, errors.WithCode(errors.New("coupons are not enabled"), errors.CodeInvalidInput)
+	}
+
+	applied, err := s.promotions.Apply(ctx, userID, s.currency, subtotal, codes)
+	if err != nil {
+		return nil, err
+	}
+
+	discounts := make([]*repository.Discount, len(applied))
+	for i, a := range applied {
+		discounts[i] = &repository.Discount{
+			PromotionID: a.PromotionID,
+			Code:        a.Code,
+			Amount:      a.Amount,
+		}
+	}
+	return discounts, nil
+}
+
+// discountsToProto converts order discounts in code to protobuf
+func discountsToProto(discounts []*repository.Discount, code string) []*orderv1.OrderDiscount {
+	if len(discounts) == 0 {
+		return nil
+	}
+	pb := make([]*orderv1.OrderDiscount, len(discounts))
+	for i, d := range discounts {
+		pb[i] = &orderv1.OrderDiscount{
+			Code:        d.Code,
+			Amount:      money.ToMajor(d.Amount, code),
+			AmountMinor: d.Amount,
+		}
+	}
+	return pb
+}
+
+// itemsToProto converts order items priced in code to protobuf
+func itemsToProto(items []*repository.OrderItem, code string) []*orderv1.OrderItem {
+	pb := make([]*orderv1.OrderItem, len(items))
+	for i, item := range items {
+		pb[i] = &orderv1.OrderItem{
+			Id:          item.ID,
+			ProductId:   item.ProductID,
+			ProductName: item.ProductName,
+			Quantity:    item.Quantity,
+			Price:       money.ToMajor(item.Price, code),
+			PriceMinor:  item.Price,
+		}
+	}
+	return pb
 }
 
 // GetOrder retrieves an order by ID
 func (s *service) GetOrder(ctx context.Context, req *orderv1.GetOrderRequest) (*orderv1.GetOrderResponse, error) {
-	s.logger.Info("Getting order", log.String("order_id", req.GetId()))
+	s.loggerFor(ctx).Info("Getting order", log.String("order_id", req.GetId()))
 
 	if req.GetId() == "" {
-		return nil, errors.WithCode(errors.New("id is required"), errors.CodeInvalidInput)
+		return nil, errors.InvalidField("id", "id is required")
 	}
 
-	order, items, err := s.repo.GetByID(ctx, req.GetId())
+	if err := fieldmask.Validate(&orderv1.Order{}, req.GetReadMask()); err != nil {
+		return nil, err
+	}
+
+	target, rates, err := s.targetCurrency(ctx, req.GetCurrency())
 	if err != nil {
-		s.logger.Error("Failed to get order", log.Error(err))
 		return nil, err
 	}
 
-	// Convert items
-	orderItems := make([]*orderv1.OrderItem, len(items))
-	for i, item := range items {
-		orderItems[i] = &orderv1.OrderItem{
-			Id:        item.ID,
-			ProductId: item.ProductID,
-			Quantity:  item.Quantity,
-			Price:     item.Price,
-		}
+	// Skip the items query when the mask leaves them out
+	var order *repository.Order
+	var items []*repository.OrderItem
+	if fieldmask.Includes(req.GetReadMask(), "items") {
+		order, items, err = s.repo.GetByID(ctx, req.GetId())
+	} else {
+		order, err = s.repo.Get(ctx, req.GetId())
+	}
+	if err != nil {
+		s.loggerFor(ctx).Error("Failed to get order", log.Error(err))
+		return nil, err
 	}
 
-	return &orderv1.GetOrderResponse{
-		Order: &orderv1.Order{
-			Id:          order.ID,
-			UserId:      order.UserID,
-			Status:      statusToProto(order.Status),
-			TotalAmount: order.TotalAmount,
-			CreatedAt:   timestamppb.New(order.CreatedAt),
-			UpdatedAt:   timestamppb.New(order.UpdatedAt),
-			Items:       orderItems,
-		},
-	}, nil
+	pbOrder := orderToProto(order)
+	pbOrder.Items = itemsToProto(items, order.Currency)
+	if err := s.convert(pbOrder, target, rates); err != nil {
+		return nil, err
+	}
+	fieldmask.Trim(pbOrder, req.GetReadMask())
+
+	return &orderv1.GetOrderResponse{Order: pbOrder}, nil
 }
 
 // ListOrders lists orders with pagination
 func (s *service) ListOrders(ctx context.Context, req *orderv1.ListOrdersRequest) (*orderv1.ListOrdersResponse, error) {
-	s.logger.Info("Listing orders", log.String("user_id", req.GetUserId()), log.Int32("page_size", req.GetPageSize()))
+	s.loggerFor(ctx).Info("Listing orders", log.String("user_id", req.GetUserId()), log.Int32("page_size", req.GetPageSize()))
 
-	pageSize := int(req.GetPageSize())
-	if pageSize <= 0 {
-		pageSize = 10
+	pageSize := normalizePageSize(req.GetPageSize())
+
+	filter, err := listFilter(req)
+	if err != nil {
+		return nil, err
 	}
-	if pageSize > 100 {
-		pageSize = 100
+
+	after, err := s.pageTokens.Decode(ordersScope(filter), req.GetPageToken())
+	if err != nil {
+		return nil, err
 	}
 
-	// Parse page token
-	offset := 0
-	if req.GetPageToken() != "" {
-		parsedOffset, err := strconv.Atoi(req.GetPageToken())
-		if err == nil && parsedOffset > 0 {
-			offset = parsedOffset
-		}
+	if err := fieldmask.Validate(&orderv1.Order{}, req.GetReadMask()); err != nil {
+		return nil, err
 	}
 
-	var orders []*repository.Order
-	var err error
+	target, rates, err := s.targetCurrency(ctx, req.GetCurrency())
+	if err != nil {
+		return nil, err
+	}
 
-	if req.GetUserId() != "" {
-		orders, err = s.repo.GetByUserID(ctx, req.GetUserId(), pageSize, offset)
-	} else {
-		orders, err = s.repo.List(ctx, pageSize, offset)
+	// Fetch one more order than the page holds to learn whether another
+	// page follows
+	orders, err := s.repo.ListAfter(ctx, filter, after, pageSize+1)
+	if err != nil {
+		s.loggerFor(ctx).Error("Failed to list orders", log.Error(err))
+		return nil, err
+	}
+	hasMore := len(orders) > pageSize
+	if hasMore {
+		orders = orders[:pageSize]
 	}
 
+	pbOrders, err := s.ordersToProto(ctx, orders, req.GetReadMask(), target, rates)
 	if err != nil {
-		s.logger.Error("Failed to list orders", log.Error(err))
 		return nil, err
 	}
 
-	// Convert to protobuf
+	resp := &orderv1.ListOrdersResponse{
+		Orders:  pbOrders,
+		HasMore: hasMore,
+	}
+	if hasMore {
+		resp.NextPageToken = s.nextPageToken(filter, orders)
+	}
+	if req.GetIncludeTotal() {
+		resp.TotalSize, resp.TotalSizeExact, err = s.repo.Count(ctx, filter)
+		if err != nil {
+			s.loggerFor(ctx).Error("Failed to count orders", log.Error(err))
+			return nil, err
+		}
+	}
+
+	return resp, nil
+}
+
+// listFilter returns the filter and ordering of a ListOrders request
+func listFilter(req *orderv1.ListOrdersRequest) (repository.Filter, error) {
+	filter := repository.Filter{
+		UserID:   req.GetUserId(),
+		MinTotal: req.GetMinTotalMinor(),
+		MaxTotal: req.GetMaxTotalMinor(),
+	}
+
+	if status := req.GetStatus(); status != orderv1.OrderStatus_ORDER_STATUS_UNSPECIFIED {
+		filter.Status = statusFromProto(status)
+		if statusToProto(filter.Status) != status {
+			return filter, errors.InvalidField("status", fmt.Sprintf("unknown status %d", status))
+		}
+	}
+
+	if ts := req.GetCreatedAfter(); ts != nil {
+		if err := ts.CheckValid(); err != nil {
+			return filter, errors.InvalidField("created_after", "created_after is not a valid timestamp")
+		}
+		filter.CreatedAfter = ts.AsTime()
+	}
+	if ts := req.GetCreatedBefore(); ts != nil {
+		if err := ts.CheckValid(); err != nil {
+			return filter, errors.InvalidField("created_before", "created_before is not a valid timestamp")
+		}
+		filter.CreatedBefore = ts.AsTime()
+	}
+	if !filter.CreatedAfter.IsZero() && !filter.CreatedBefore.IsZero() && !filter.CreatedAfter.Before(filter.CreatedBefore) {
+		return filter, errors.InvalidField("created_before", "created_before must be after created_after")
+	}
+
+	if filter.MinTotal < 0 {
+		return filter, errors.InvalidField("min_total_minor", "min_total_minor must not be negative")
+	}
+	if filter.MaxTotal < 0 {
+		return filter, errors.InvalidField("max_total_minor", "max_total_minor must not be negative")
+	}
+	if filter.MaxTotal != 0 && filter.MinTotal > filter.MaxTotal {
+		return filter, errors.InvalidField("max_total_minor", "max_total_minor must not be below min_total_minor")
+	}
+
+	// "created_at asc" and "created_at  ASC" mean "created_at"
+	orderBy := strings.Fields(strings.ToLower(req.GetOrderBy()))
+	if len(orderBy) == 2 && orderBy[1] == "asc" {
+		orderBy = orderBy[:1]
+	}
+	filter.OrderBy = strings.Join(orderBy, " ")
+	if filter.OrderBy != "" && !repository.ValidOrderBy(filter.OrderBy) {
+		return filter, errors.InvalidField("order_by", fmt.Sprintf("cannot order by %q", req.GetOrderBy()))
+	}
+
+	return filter, nil
+}
+
+// ordersScope names the listing that page tokens are valid for: the orders
+// of the filter's user, or every order when it has none, in the filter's
+// ordering
+func ordersScope(filter repository.Filter) string {
+	scope := "orders:" + filter.UserID
+	if filter.OrderBy != "" && filter.OrderBy != repository.OrderByCreatedAtDesc {
+		scope += ":" + filter.OrderBy
+	}
+	return scope
+}
+
+// nextPageToken returns the page token resuming after the last of orders
+func (s *service) nextPageToken(filter repository.Filter, orders []*repository.Order) string {
+	return s.pageTokens.Encode(ordersScope(filter), *repository.CursorAfter(orders[len(orders)-1]))
+}
+
+// ordersToProto converts a page of orders to protobuf, trimmed to mask.
+// Listed orders carry items only when the mask asks for them, fetched for
+// the whole page at once.
+func (s *service) ordersToProto(ctx context.Context, orders []*repository.Order, mask *fieldmaskpb.FieldMask, target string, rates *currency.Rates) ([]*orderv1.Order, error) {
+	var items map[string][]*repository.OrderItem
+	if len(mask.GetPaths()) > 0 && fieldmask.Includes(mask, "items") && len(orders) > 0 {
+		ids := make([]string, len(orders))
+		for i, order := range orders {
+			ids[i] = order.ID
+		}
+		var err error
+		items, err = s.repo.ListItems(ctx, ids)
+		if err != nil {
+			s.loggerFor(ctx).Error("Failed to list order items", log.Error(err))
+			return nil, err
+		}
+	}
+
 	pbOrders := make([]*orderv1.Order, len(orders))
 	for i, order := range orders {
-		pbOrders[i] = &orderv1.Order{
-			Id:          order.ID,
-			UserId:      order.UserID,
-			Status:      statusToProto(order.Status),
-			TotalAmount: order.TotalAmount,
-			CreatedAt:   timestamppb.New(order.CreatedAt),
-			UpdatedAt:   timestamppb.New(order.UpdatedAt),
+		pbOrders[i] = orderToProto(order)
+		if items != nil {
+			pbOrders[i].Items = itemsToProto(items[order.ID], order.Currency)
+		}
+		if err := s.convert(pbOrders[i], target, rates); err != nil {
+			return nil, err
 		}
+		fieldmask.Trim(pbOrders[i], mask)
 	}
+	return pbOrders, nil
+}
 
-	nextPageToken := ""
-	if len(orders) == pageSize {
-		nextPageToken = strconv.Itoa(offset + pageSize)
+// normalizePageSize applies the default and maximum page sizes
+func normalizePageSize(size int32) int {
+	pageSize := int(size)
+	if pageSize <= 0 {
+		pageSize = 10
 	}
-
-	return &orderv1.ListOrdersResponse{
-		Orders:        pbOrders,
-		NextPageToken: nextPageToken,
-	}, nil
+	if pageSize > 100 {
+		pageSize = 100
+	}
+	return pageSize
 }
 
 // UpdateOrderStatus updates the order status
 func (s *service) UpdateOrderStatus(ctx context.Context, req *orderv1.UpdateOrderStatusRequest) (*orderv1.UpdateOrderStatusResponse, error) {
-	s.logger.Info("Updating order status", log.String("order_id", req.GetId()), log.String("status", req.GetStatus().String()))
+	s.loggerFor(ctx).Info("Updating order status", log.String("order_id", req.GetId()), log.String("status", req.GetStatus().String()))
 
 	if req.GetId() == "" {
-		return nil, errors.WithCode(errors.New("id is required"), errors.CodeInvalidInput)
+		return nil, errors.InvalidField("id", "id is required")
 	}
 
 	if req.GetStatus() == orderv1.OrderStatus_ORDER_STATUS_UNSPECIFIED {
-		return nil, errors.WithCode(errors.New("status is required"), errors.CodeInvalidInput)
+		return nil, errors.InvalidField("status", "status is required")
 	}
 
 	// Convert status to string
 	status := statusFromProto(req.GetStatus())
 
+	current, err := s.repo.Get(ctx, req.GetId())
+	if err != nil {
+		s.loggerFor(ctx).Error("Failed to get order", log.Error(err))
+		return nil, err
+	}
+	// Fail fast on the status read; UpdateStatus checks again under the
+	// row lock, since the order may have changed since
+	if err := repository.CheckTransition(current.Status, status); err != nil {
+		return nil, err
+	}
+
 	if err := s.repo.UpdateStatus(ctx, req.GetId(), status); err != nil {
-		s.logger.Error("Failed to update order status", log.Error(err))
+		s.loggerFor(ctx).Error("Failed to update order status", log.Error(err))
 		return nil, err
 	}
 
 	// Get updated order
 	order, items, err := s.repo.GetByID(ctx, req.GetId())
 	if err != nil {
-		s.logger.Error("Failed to get updated order", log.Error(err))
+		s.loggerFor(ctx).Error("Failed to get updated order", log.Error(err))
 		return nil, err
 	}
 
-	// Convert items
-	orderItems := make([]*orderv1.OrderItem, len(items))
-	for i, item := range items {
-		orderItems[i] = &orderv1.OrderItem{
-			Id:        item.ID,
-			ProductId: item.ProductID,
-			Quantity:  item.Quantity,
-			Price:     item.Price,
+	s.loggerFor(ctx).Info("Order status updated successfully", log.String("order_id", order.ID))
+
+	pbOrder := orderToProto(order)
+	pbOrder.Items = itemsToProto(items, order.Currency)
+	return &orderv1.UpdateOrderStatusResponse{Order: pbOrder}, nil
+}
+
+// GetOrderHistory returns the status changes of an order, oldest first
+func (s *service) GetOrderHistory(ctx context.Context, req *orderv1.GetOrderHistoryRequest) (*orderv1.GetOrderHistoryResponse, error) {
+	if req.GetId() == "" {
+		return nil, errors.InvalidField("id", "id is required")
+	}
+
+	changes, err := s.repo.History(ctx, req.GetId())
+	if err != nil {
+		s.loggerFor(ctx).Error("Failed to get order history", log.Error(err))
+		return nil, err
+	}
+
+	pbChanges := make([]*orderv1.OrderStatusChange, len(changes))
+	for i, c := range changes {
+		pbChanges[i] = &orderv1.OrderStatusChange{
+			ToStatus:  statusToProto(c.ToStatus),
+			Actor:     c.Actor,
+			ChangedAt: timestamppb.New(c.ChangedAt),
+		}
+		if c.FromStatus != "" {
+			pbChanges[i].FromStatus = statusToProto(c.FromStatus)
 		}
 	}
+	return &orderv1.GetOrderHistoryResponse{Changes: pbChanges}, nil
+}
 
-	s.logger.Info("Order status updated successfully", log.String("order_id", order.ID))
+// UpdateOrder adds and removes items of a pending order
+func (s *service) UpdateOrder(ctx context.Context, req *orderv1.UpdateOrderRequest) (*orderv1.UpdateOrderResponse, error) {
+	s.loggerFor(ctx).Info("Updating order", log.String("order_id", req.GetId()),
+		log.Int("add_items", len(req.GetAddItems())), log.Int("remove_items", len(req.GetRemoveItemIds())))
 
-	return &orderv1.UpdateOrderStatusResponse{
-		Order: &orderv1.Order{
-			Id:          order.ID,
-			UserId:      order.UserID,
-			Status:      statusToProto(order.Status),
-			TotalAmount: order.TotalAmount,
-			CreatedAt:   timestamppb.New(order.CreatedAt),
-			UpdatedAt:   timestamppb.New(order.UpdatedAt),
-			Items:       orderItems,
-		},
-	}, nil
+	if req.GetId() == "" {
+		return nil, errors.InvalidField("id", "id is required")
+	}
+	if len(req.GetAddItems()) == 0 && len(req.GetRemoveItemIds()) == 0 {
+		return nil, errors.WithCode(errors.New("add_items or remove_item_ids is required"), errors.CodeInvalidInput)
+	}
+	for _, id := range req.GetRemoveItemIds() {
+		if _, err := uuid.Parse(id); err != nil {
+			return nil, errors.WithCode(errors.Newf("invalid item id %q", id), errors.CodeInvalidInput)
+		}
+	}
+
+	// Added items are priced in the currency of the order. The repository
+	// checks the status again under lock.
+	order, err := s.repo.Get(ctx, req.GetId())
+	if err != nil {
+		s.loggerFor(ctx).Error("Failed to get order", log.Error(err))
+		return nil, err
+	}
+	if order.Status != "pending" {
+		return nil, errors.WithCode(errors.Newf("cannot modify %s order", order.Status), errors.CodeInvalidInput)
+	}
+
+	var add []*repository.OrderItem
+	if len(req.GetAddItems()) > 0 {
+		products, err := s.catalogPrices(ctx, req.GetAddItems(), order.Currency)
+		if err != nil {
+			return nil, err
+		}
+		if add, _, err = orderItems("add_items", req.GetAddItems(), order.Currency, products); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.repo.UpdateItems(ctx, req.GetId(), add, req.GetRemoveItemIds()); err != nil {
+		s.loggerFor(ctx).Error("Failed to update order", log.Error(err))
+		return nil, err
+	}
+
+	order, items, err := s.repo.GetByID(ctx, req.GetId())
+	if err != nil {
+		s.loggerFor(ctx).Error("Failed to get updated order", log.Error(err))
+		return nil, err
+	}
+
+	s.loggerFor(ctx).Info("Order updated successfully", log.String("order_id", order.ID))
+
+	pbOrder := orderToProto(order)
+	pbOrder.Items = itemsToProto(items, order.Currency)
+	return &orderv1.UpdateOrderResponse{Order: pbOrder}, nil
+}
+
+// targetCurrency validates a requested currency and returns the rates to
+// convert to it with, which are nil when orders in the base currency need
+// no conversion. An empty target leaves orders in their own currency.
+func (s *service) targetCurrency(ctx context.Context, requested string) (string, *currency.Rates, error) {
+	if requested == "" {
+		return "", nil, nil
+	}
+
+	target, err := currency.Normalize(requested)
+	if err != nil {
+		return "", nil, err
+	}
+	if target == s.currency {
+		return target, nil, nil
+	}
+	if s.converter == nil {
+		return "", nil, errors.WithCode(errors.New("currency conversion is not enabled"), errors.CodeInvalidInput)
+	}
+
+	rates, err := s.converter.Rates(ctx)
+	if err != nil {
+		s.loggerFor(ctx).Error("Failed to get exchange rates", log.Error(err))
+		return "", nil, err
+	}
+	return target, rates, nil
+}
+
+// convert converts an order's amounts from its currency to target
+func (s *service) convert(order *orderv1.Order, target string, rates *currency.Rates) error {
+	from := order.Currency
+	if target == "" || target == from {
+		return nil
+	}
+	if rates == nil {
+		return errors.WithCode(errors.Newf("cannot convert orders in %s without exchange rates", from), errors.CodeInvalidInput)
+	}
+
+	total, err := rates.Convert(order.TotalAmountMinor, from, target)
+	if err != nil {
+		return err
+	}
+	for _, item := range order.Items {
+		if item.PriceMinor, err = rates.Convert(item.PriceMinor, from, target); err != nil {
+			return err
+		}
+		item.Price = money.ToMajor(item.PriceMinor, target)
+	}
+	for _, d := range order.Discounts {
+		if d.AmountMinor, err = rates.Convert(d.AmountMinor, from, target); err != nil {
+			return err
+		}
+		d.Amount = money.ToMajor(d.AmountMinor, target)
+	}
+
+	order.TotalAmountMinor = total
+	order.TotalAmount = money.ToMajor(total, target)
+	order.Currency = target
+	return nil
 }
 
 // CancelOrder cancels an order
 func (s *service) CancelOrder(ctx context.Context, req *orderv1.CancelOrderRequest) (*orderv1.CancelOrderResponse, error) {
-	s.logger.Info("Cancelling order", log.String("order_id", req.GetId()))
+	s.loggerFor(ctx).Info("Cancelling order", log.String("order_id", req.GetId()))
 
 	if req.GetId() == "" {
-		return nil, errors.WithCode(errors.New("id is required"), errors.CodeInvalidInput)
+		return nil, errors.InvalidField("id", "id is required")
 	}
 
 	// Get order to check status
-	order, _, err := s.repo.GetByID(ctx, req.GetId())
+	order, err := s.repo.Get(ctx, req.GetId())
 	if err != nil {
-		s.logger.Error("Failed to get order", log.Error(err))
+		s.loggerFor(ctx).Error("Failed to get order", log.Error(err))
 		return nil, err
 	}
 
@@ -312,18 +799,17 @@ func (s *service) CancelOrder(ctx context.Context, req *orderv1.CancelOrderReque
 	if order.Status == "cancelled" {
 		return nil, errors.WithCode(errors.New("order is already cancelled"), errors.CodeInvalidInput)
 	}
-
-	if order.Status == "delivered" {
-		return nil, errors.WithCode(errors.New("cannot cancel delivered order"), errors.CodeInvalidInput)
+	if err := repository.CheckTransition(order.Status, "cancelled"); err != nil {
+		return nil, err
 	}
 
 	// Update status to cancelled
 	if err := s.repo.UpdateStatus(ctx, req.GetId(), "cancelled"); err != nil {
-		s.logger.Error("Failed to cancel order", log.Error(err))
+		s.loggerFor(ctx).Error("Failed to cancel order", log.Error(err))
 		return nil, err
 	}
 
-	s.logger.Info("Order cancelled successfully", log.String("order_id", req.GetId()))
+	s.loggerFor(ctx).Info("Order cancelled successfully", log.String("order_id", req.GetId()))
 
 	return &orderv1.CancelOrderResponse{
 		Success: true,
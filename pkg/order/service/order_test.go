@@ -18,23 +18,45 @@ package service
 
 import (
 	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	orderv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/order/v1"
+	"github.com/kevindiu/monorepo-go-example/internal/currency"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/internal/idempotency"
 	"github.com/kevindiu/monorepo-go-example/internal/log"
+	"github.com/kevindiu/monorepo-go-example/internal/pagination"
 	"github.com/kevindiu/monorepo-go-example/pkg/order/repository"
+	"github.com/kevindiu/monorepo-go-example/pkg/promotion"
+	promotionrepo "github.com/kevindiu/monorepo-go-example/pkg/promotion/repository"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 // mockRepository implements repository.Repository for testing
 type mockRepository struct {
 	orders     map[string]*repository.Order
 	orderItems map[string][]*repository.OrderItem
+	// imports counts Import calls; Import rejects every batch holding an
+	// order of a user in missingUsers, like the users foreign key would
+	imports      int
+	missingUsers map[string]bool
+	// itemLoads counts the queries that read order items
+	itemLoads int
+	history   map[string][]*repository.StatusChange
 }
 
 func newMockRepository() *mockRepository {
 	return &mockRepository{
 		orders:     make(map[string]*repository.Order),
 		orderItems: make(map[string][]*repository.OrderItem),
+		history:    make(map[string][]*repository.StatusChange),
 	}
 }
 
@@ -44,31 +66,93 @@ func (m *mockRepository) Create(ctx context.Context, order *repository.Order, it
 	return nil
 }
 
+func (m *mockRepository) Get(ctx context.Context, id string) (*repository.Order, error) {
+	order, ok := m.orders[id]
+	if !ok {
+		return nil, errors.WithCode(errors.New("order not found"), errors.CodeNotFound)
+	}
+	return order, nil
+}
+
 func (m *mockRepository) GetByID(ctx context.Context, id string) (*repository.Order, []*repository.OrderItem, error) {
 	order, ok := m.orders[id]
 	if !ok {
 		return nil, nil, nil
 	}
+	m.itemLoads++
 	items := m.orderItems[id]
 	return order, items, nil
 }
 
-func (m *mockRepository) GetByUserID(ctx context.Context, userID string, limit, offset int) ([]*repository.Order, error) {
+func (m *mockRepository) ListItems(ctx context.Context, orderIDs []string) (map[string][]*repository.OrderItem, error) {
+	m.itemLoads++
+	items := make(map[string][]*repository.OrderItem)
+	for _, id := range orderIDs {
+		items[id] = m.orderItems[id]
+	}
+	return items, nil
+}
+
+func (m *mockRepository) ListAfter(ctx context.Context, filter repository.Filter, after *pagination.Cursor, limit int) ([]*repository.Order, error) {
+	var seek *repository.Order
+	if after != nil {
+		seek = &repository.Order{ID: after.ID, CreatedAt: after.CreatedAt, TotalAmount: after.Value}
+	}
 	orders := []*repository.Order{}
 	for _, order := range m.orders {
-		if order.UserID == userID {
-			orders = append(orders, order)
+		if !mockMatches(filter, order) || (seek != nil && !mockBefore(filter.OrderBy, seek, order)) {
+			continue
 		}
+		orders = append(orders, order)
+	}
+	sort.Slice(orders, func(i, j int) bool { return mockBefore(filter.OrderBy, orders[i], orders[j]) })
+	if limit < len(orders) {
+		orders = orders[:limit]
 	}
 	return orders, nil
 }
 
-func (m *mockRepository) List(ctx context.Context, limit, offset int) ([]*repository.Order, error) {
-	orders := []*repository.Order{}
+// mockMatches reports whether filter selects order
+func mockMatches(filter repository.Filter, order *repository.Order) bool {
+	return (filter.UserID == "" || order.UserID == filter.UserID) &&
+		(filter.Status == "" || order.Status == filter.Status) &&
+		(filter.CreatedAfter.IsZero() || !order.CreatedAt.Before(filter.CreatedAfter)) &&
+		(filter.CreatedBefore.IsZero() || order.CreatedAt.Before(filter.CreatedBefore)) &&
+		order.TotalAmount >= filter.MinTotal &&
+		(filter.MaxTotal == 0 || order.TotalAmount <= filter.MaxTotal)
+}
+
+// mockBefore reports whether a is listed before b in the orderBy ordering
+func mockBefore(orderBy string, a, b *repository.Order) bool {
+	var c int
+	switch {
+	case strings.HasPrefix(orderBy, repository.OrderByTotal) && a.TotalAmount != b.TotalAmount:
+		c = 1
+		if a.TotalAmount < b.TotalAmount {
+			c = -1
+		}
+	case !strings.HasPrefix(orderBy, repository.OrderByTotal) && !a.CreatedAt.Equal(b.CreatedAt):
+		c = 1
+		if a.CreatedAt.Before(b.CreatedAt) {
+			c = -1
+		}
+	default:
+		c = strings.Compare(a.ID, b.ID)
+	}
+	if orderBy == "" || strings.HasSuffix(orderBy, " desc") {
+		c = -c
+	}
+	return c < 0
+}
+
+func (m *mockRepository) Count(ctx context.Context, filter repository.Filter) (int64, bool, error) {
+	var n int64
 	for _, order := range m.orders {
-		orders = append(orders, order)
+		if mockMatches(filter, order) {
+			n++
+		}
 	}
-	return orders, nil
+	return n, true, nil
 }
 
 func (m *mockRepository) UpdateStatus(ctx context.Context, id, status string) error {
@@ -76,16 +160,110 @@ func (m *mockRepository) UpdateStatus(ctx context.Context, id, status string) er
 	if !ok {
 		return nil
 	}
+	if order.Status == status {
+		return nil
+	}
+	if err := repository.CheckTransition(order.Status, status); err != nil {
+		return err
+	}
+	m.history[id] = append(m.history[id], &repository.StatusChange{
+		OrderID:    id,
+		FromStatus: order.Status,
+		ToStatus:   status,
+		Actor:      repository.SystemActor,
+		ChangedAt:  time.Now(),
+	})
 	order.Status = status
 	return nil
 }
 
+func (m *mockRepository) History(ctx context.Context, id string) ([]*repository.StatusChange, error) {
+	if _, ok := m.orders[id]; !ok {
+		return nil, errors.WithCode(errors.New("order not found"), errors.CodeNotFound)
+	}
+	return m.history[id], nil
+}
+
+func (m *mockRepository) UpdateItems(ctx context.Context, id string, add []*repository.OrderItem, removeIDs []string) error {
+	order, ok := m.orders[id]
+	if !ok {
+		return errors.WithCode(errors.New("order not found"), errors.CodeNotFound)
+	}
+	if order.Status != "pending" {
+		return errors.WithCode(errors.Newf("cannot modify %s order", order.Status), errors.CodeInvalidInput)
+	}
+
+	remove := make(map[string]bool, len(removeIDs))
+	for _, itemID := range removeIDs {
+		remove[itemID] = true
+	}
+	var items []*repository.OrderItem
+	for _, item := range m.orderItems[id] {
+		if remove[item.ID] {
+			delete(remove, item.ID)
+			continue
+		}
+		items = append(items, item)
+	}
+	if len(remove) > 0 {
+		return errors.WithCode(errors.New("order item not found"), errors.CodeNotFound)
+	}
+	for _, item := range add {
+		item.ID = fmt.Sprintf("00000000-0000-0000-0000-%012d", len(items)+len(removeIDs)+100)
+		items = append(items, item)
+	}
+	if len(items) == 0 {
+		return errors.WithCode(errors.New("order must keep at least one item"), errors.CodeInvalidInput)
+	}
+
+	var total int64
+	for _, item := range items {
+		total += item.Price * int64(item.Quantity)
+	}
+	for _, d := range order.Discounts {
+		total -= d.Amount
+	}
+	order.TotalAmount = max(total, 0)
+	m.orderItems[id] = items
+	return nil
+}
+
 func (m *mockRepository) Delete(ctx context.Context, id string) error {
 	delete(m.orders, id)
 	delete(m.orderItems, id)
 	return nil
 }
 
+func (m *mockRepository) ExpirePending(ctx context.Context, createdBefore time.Time) ([]repository.Expired, error) {
+	var expired []repository.Expired
+	for _, order := range m.orders {
+		if order.Status == "pending" && order.CreatedAt.Before(createdBefore) {
+			order.Status = "cancelled"
+			expired = append(expired, repository.Expired{ID: order.ID})
+		}
+	}
+	return expired, nil
+}
+
+func (m *mockRepository) ExpireReservations(ctx context.Context, now time.Time) ([]repository.Expired, error) {
+	return nil, nil
+}
+
+func (m *mockRepository) Import(ctx context.Context, orders []*repository.ImportedOrder) error {
+	m.imports++
+	for _, o := range orders {
+		if m.missingUsers[o.Order.UserID] {
+			return errors.New("user does not exist")
+		}
+	}
+	for _, o := range orders {
+		o.Order.ID = strconv.Itoa(len(m.orders) + 1)
+		m.orders[o.Order.ID] = o.Order
+		m.orderItems[o.Order.ID] = o.Items
+	}
+	return nil
+}
+
 func TestNew(t *testing.T) {
 	repo := newMockRepository()
 	logger := log.NewDefault()
@@ -112,9 +290,10 @@ func TestCreateOrder(t *testing.T) {
 				UserId: "user-1",
 				Items: []*orderv1.OrderItem{
 					{
-						ProductId: "prod-1",
-						Quantity:  2,
-						Price:     10.50,
+						ProductId:   "prod-1",
+						ProductName: "Mug",
+						Quantity:    2,
+						Price:       10.50,
 					},
 				},
 			},
@@ -185,6 +364,166 @@ func TestCreateOrder(t *testing.T) {
 			if !tt.wantErr && resp.Order == nil {
 				t.Error("CreateOrder() returned nil order")
 			}
+			if !tt.wantErr {
+				for i, item := range resp.GetOrder().GetItems() {
+					if want := tt.req.Items[i].GetProductName(); item.GetProductName() != want {
+						t.Errorf("CreateOrder() item name = %q, want %q", item.GetProductName(), want)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestCreateOrderMinorUnits(t *testing.T) {
+	svc := New(newMockRepository(), log.NewDefault())
+
+	resp, err := svc.CreateOrder(context.Background(), &orderv1.CreateOrderRequest{
+		UserId: "user-1",
+		Items: []*orderv1.OrderItem{
+			{ProductId: "prod-1", Quantity: 3, Price: 0.1},
+			// price_minor wins over price
+			{ProductId: "prod-2", Quantity: 1, Price: 99, PriceMinor: 20},
+		},
+	})
+	if err != nil {
+		t.Fatalf("CreateOrder() error = %v", err)
+	}
+	order := resp.GetOrder()
+	if order.GetTotalAmountMinor() != 50 || order.GetTotalAmount() != 0.5 || order.GetCurrency() != "USD" {
+		t.Errorf("CreateOrder() total = %d (%v) %s, want 50 (0.5) USD",
+			order.GetTotalAmountMinor(), order.GetTotalAmount(), order.GetCurrency())
+	}
+}
+
+func TestUpdateOrder(t *testing.T) {
+	const (
+		itemA = "00000000-0000-0000-0000-00000000000a"
+		itemB = "00000000-0000-0000-0000-00000000000b"
+	)
+	newRepo := func(status string) *mockRepository {
+		repo := newMockRepository()
+		repo.orders["order-1"] = &repository.Order{
+			ID:          "order-1",
+			UserID:      "user-1",
+			Status:      status,
+			TotalAmount: 1300,
+			Currency:    "USD",
+			Discounts:   []*repository.Discount{{Code: "SAVE2", Amount: 200}},
+		}
+		repo.orderItems["order-1"] = []*repository.OrderItem{
+			{ID: itemA, ProductID: "prod-a", Quantity: 1, Price: 1000},
+			{ID: itemB, ProductID: "prod-b", Quantity: 1, Price: 500},
+		}
+		return repo
+	}
+
+	tests := []struct {
+		name      string
+		status    string
+		req       *orderv1.UpdateOrderRequest
+		wantCode  string
+		wantTotal int64
+		wantItems int
+	}{
+		{
+			name:   "add item",
+			status: "pending",
+			req: &orderv1.UpdateOrderRequest{
+				Id:       "order-1",
+				AddItems: []*orderv1.OrderItem{{ProductId: "prod-c", Quantity: 2, PriceMinor: 250}},
+			},
+			wantTotal: 1800,
+			wantItems: 3,
+		},
+		{
+			name:      "remove item",
+			status:    "pending",
+			req:       &orderv1.UpdateOrderRequest{Id: "order-1", RemoveItemIds: []string{itemA}},
+			wantTotal: 300,
+			wantItems: 1,
+		},
+		{
+			name:   "replace item",
+			status: "pending",
+			req: &orderv1.UpdateOrderRequest{
+				Id:            "order-1",
+				AddItems:      []*orderv1.OrderItem{{ProductId: "prod-c", Quantity: 1, Price: 1.5}},
+				RemoveItemIds: []string{itemA},
+			},
+			wantTotal: 450,
+			wantItems: 2,
+		},
+		{
+			name:     "confirmed order",
+			status:   "confirmed",
+			req:      &orderv1.UpdateOrderRequest{Id: "order-1", RemoveItemIds: []string{itemA}},
+			wantCode: errors.CodeInvalidInput,
+		},
+		{
+			name:     "remove every item",
+			status:   "pending",
+			req:      &orderv1.UpdateOrderRequest{Id: "order-1", RemoveItemIds: []string{itemA, itemB}},
+			wantCode: errors.CodeInvalidInput,
+		},
+		{
+			name:     "unknown item",
+			status:   "pending",
+			req:      &orderv1.UpdateOrderRequest{Id: "order-1", RemoveItemIds: []string{"00000000-0000-0000-0000-000000000001"}},
+			wantCode: errors.CodeNotFound,
+		},
+		{
+			name:     "invalid item id",
+			status:   "pending",
+			req:      &orderv1.UpdateOrderRequest{Id: "order-1", RemoveItemIds: []string{"item-a"}},
+			wantCode: errors.CodeInvalidInput,
+		},
+		{
+			name:   "invalid added item",
+			status: "pending",
+			req: &orderv1.UpdateOrderRequest{
+				Id:       "order-1",
+				AddItems: []*orderv1.OrderItem{{ProductId: "prod-c", Quantity: 0, PriceMinor: 250}},
+			},
+			wantCode: errors.CodeInvalidInput,
+		},
+		{
+			name:     "no changes",
+			status:   "pending",
+			req:      &orderv1.UpdateOrderRequest{Id: "order-1"},
+			wantCode: errors.CodeInvalidInput,
+		},
+		{
+			name:     "missing order",
+			status:   "pending",
+			req:      &orderv1.UpdateOrderRequest{Id: "order-2", RemoveItemIds: []string{itemA}},
+			wantCode: errors.CodeNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newRepo(tt.status)
+			svc := New(repo, log.NewDefault())
+
+			resp, err := svc.UpdateOrder(context.Background(), tt.req)
+			if tt.wantCode != "" {
+				if errors.GetCode(err) != tt.wantCode {
+					t.Fatalf("UpdateOrder() error = %v, want code %s", err, tt.wantCode)
+				}
+				if len(repo.orderItems["order-1"]) != 2 || repo.orders["order-1"].TotalAmount != 1300 {
+					t.Error("UpdateOrder() changed the order after failing")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("UpdateOrder() error = %v", err)
+			}
+			order := resp.GetOrder()
+			if order.GetTotalAmountMinor() != tt.wantTotal || len(order.GetItems()) != tt.wantItems {
+				t.Errorf("UpdateOrder() = total %d with %d items, want %d with %d",
+					order.GetTotalAmountMinor(), len(order.GetItems()), tt.wantTotal, tt.wantItems)
+			}
 		})
 	}
 }
@@ -221,3 +560,499 @@ func TestStatusConversion(t *testing.T) {
 		})
 	}
 }
+
+func TestGetOrderHistory(t *testing.T) {
+	repo := newMockRepository()
+	repo.orders["order-1"] = &repository.Order{ID: "order-1", Status: "pending", Currency: "USD"}
+	svc := New(repo, log.NewDefault())
+	ctx := context.Background()
+
+	for _, status := range []orderv1.OrderStatus{orderv1.OrderStatus_ORDER_STATUS_CONFIRMED, orderv1.OrderStatus_ORDER_STATUS_SHIPPED} {
+		if _, err := svc.UpdateOrderStatus(ctx, &orderv1.UpdateOrderStatusRequest{Id: "order-1", Status: status}); err != nil {
+			t.Fatalf("UpdateOrderStatus(%v) error = %v", status, err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		id       string
+		want     [][2]orderv1.OrderStatus
+		wantCode string
+	}{
+		{
+			name: "timeline",
+			id:   "order-1",
+			want: [][2]orderv1.OrderStatus{
+				{orderv1.OrderStatus_ORDER_STATUS_PENDING, orderv1.OrderStatus_ORDER_STATUS_CONFIRMED},
+				{orderv1.OrderStatus_ORDER_STATUS_CONFIRMED, orderv1.OrderStatus_ORDER_STATUS_SHIPPED},
+			},
+		},
+		{name: "missing id", wantCode: errors.CodeInvalidInput},
+		{name: "unknown order", id: "order-2", wantCode: errors.CodeNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := svc.GetOrderHistory(ctx, &orderv1.GetOrderHistoryRequest{Id: tt.id})
+			if tt.wantCode != "" {
+				if errors.GetCode(err) != tt.wantCode {
+					t.Fatalf("GetOrderHistory() error = %v, want code %s", err, tt.wantCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetOrderHistory() error = %v", err)
+			}
+			if len(resp.GetChanges()) != len(tt.want) {
+				t.Fatalf("GetOrderHistory() returned %d changes, want %d", len(resp.GetChanges()), len(tt.want))
+			}
+			for i, c := range resp.GetChanges() {
+				if c.GetFromStatus() != tt.want[i][0] || c.GetToStatus() != tt.want[i][1] {
+					t.Errorf("change %d = %v -> %v, want %v -> %v", i, c.GetFromStatus(), c.GetToStatus(), tt.want[i][0], tt.want[i][1])
+				}
+				if c.GetActor() != repository.SystemActor || c.GetChangedAt() == nil {
+					t.Errorf("change %d actor = %q, changed at %v", i, c.GetActor(), c.GetChangedAt())
+				}
+			}
+		})
+	}
+}
+
+func TestGetOrderCurrencyConversion(t *testing.T) {
+	repo := newMockRepository()
+	repo.orders["order-1"] = &repository.Order{ID: "order-1", UserID: "user-1", Status: "pending", TotalAmount: 2000, Currency: "USD"}
+	repo.orderItems["order-1"] = []*repository.OrderItem{{ID: "item-1", ProductID: "prod-1", Quantity: 2, Price: 1000}}
+
+	converter := currency.NewConverter(currency.NewStatic("USD", map[string]float64{"EUR": 0.9, "JPY": 150}), 0, nil)
+	svc := New(repo, log.NewDefault(), WithCurrency("usd", converter))
+
+	tests := []struct {
+		name         string
+		currency     string
+		wantCurrency string
+		wantTotal    float64
+		wantPrice    float64
+		wantErr      bool
+	}{
+		{name: "base currency", wantCurrency: "USD", wantTotal: 20, wantPrice: 10},
+		{name: "converted", currency: "eur", wantCurrency: "EUR", wantTotal: 18, wantPrice: 9},
+		{name: "explicit base", currency: "USD", wantCurrency: "USD", wantTotal: 20, wantPrice: 10},
+		{name: "unknown currency", currency: "XYZ", wantErr: true},
+		{name: "malformed currency", currency: "euro", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := svc.GetOrder(context.Background(), &orderv1.GetOrderRequest{Id: "order-1", Currency: tt.currency})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetOrder() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			order := resp.GetOrder()
+			if order.GetCurrency() != tt.wantCurrency || order.GetTotalAmount() != tt.wantTotal {
+				t.Errorf("GetOrder() = %v %s, want %v %s", order.GetTotalAmount(), order.GetCurrency(), tt.wantTotal, tt.wantCurrency)
+			}
+			if got := order.GetItems()[0].GetPrice(); got != tt.wantPrice {
+				t.Errorf("item price = %v, want %v", got, tt.wantPrice)
+			}
+		})
+	}
+
+	// The stored order is never modified by a conversion
+	if repo.orders["order-1"].TotalAmount != 2000 {
+		t.Error("conversion modified the stored order")
+	}
+}
+
+func TestGetOrderConversionDisabled(t *testing.T) {
+	repo := newMockRepository()
+	repo.orders["order-1"] = &repository.Order{ID: "order-1", Status: "pending", TotalAmount: 2000, Currency: "USD"}
+	svc := New(repo, log.NewDefault())
+
+	if _, err := svc.GetOrder(context.Background(), &orderv1.GetOrderRequest{Id: "order-1", Currency: "EUR"}); err == nil {
+		t.Error("GetOrder() should reject conversion without a converter")
+	}
+}
+
+// mockPromotions implements promotionrepo.Repository for testing
+type mockPromotions struct {
+	promotions map[string]*promotionrepo.Promotion
+}
+
+func (m *mockPromotions) Create(ctx context.Context, p *promotionrepo.Promotion) error { return nil }
+
+func (m *mockPromotions) GetByCode(ctx context.Context, code string) (*promotionrepo.Promotion, error) {
+	p, ok := m.promotions[code]
+	if !ok {
+		return nil, errors.WithCode(errors.New("promotion not found"), errors.CodeNotFound)
+	}
+	return p, nil
+}
+
+func (m *mockPromotions) List(ctx context.Context, limit, offset int) ([]*promotionrepo.Promotion, error) {
+	return nil, nil
+}
+
+func (m *mockPromotions) SetActive(ctx context.Context, code string, active bool) error { return nil }
+
+func (m *mockPromotions) CountRedemptions(ctx context.Context, promotionID, userID string) (int, error) {
+	return 0, nil
+}
+
+func TestCreateOrderWithCoupons(t *testing.T) {
+	repo := newMockRepository()
+	promotions := &mockPromotions{promotions: map[string]*promotionrepo.Promotion{
+		"SAVE10": {ID: "promo-1", Code: "SAVE10", Type: promotionrepo.TypePercent, Value: 10, Active: true},
+	}}
+	svc := New(repo, log.NewDefault(), WithPromotions(promotion.New(promotions)))
+
+	req := &orderv1.CreateOrderRequest{
+		UserId:      "user-1",
+		Items:       []*orderv1.OrderItem{{ProductId: "prod-1", Quantity: 3, Price: 9.99}},
+		CouponCodes: []string{"save10"},
+	}
+	resp, err := svc.CreateOrder(context.Background(), req)
+	if err != nil {
+		t.Fatalf("CreateOrder() error = %v", err)
+	}
+
+	order := resp.GetOrder()
+	if order.GetTotalAmount() != 26.97 {
+		t.Errorf("TotalAmount = %v, want 26.97", order.GetTotalAmount())
+	}
+	if len(order.GetDiscounts()) != 1 || order.GetDiscounts()[0].GetCode() != "SAVE10" || order.GetDiscounts()[0].GetAmount() != 3 {
+		t.Errorf("Discounts = %v, want SAVE10 3.00", order.GetDiscounts())
+	}
+
+	stored := repo.orders[""]
+	if len(stored.Discounts) != 1 || stored.Discounts[0].PromotionID != "promo-1" {
+		t.Errorf("stored discounts = %v, want promo-1", stored.Discounts)
+	}
+
+	req.CouponCodes = []string{"BOGUS"}
+	if _, err := svc.CreateOrder(context.Background(), req); err == nil {
+		t.Error("CreateOrder() with an invalid coupon should fail")
+	}
+
+	if _, err := New(repo, log.NewDefault()).CreateOrder(context.Background(), req); err == nil {
+		t.Error("CreateOrder() with coupons should fail when promotions are disabled")
+	}
+}
+
+// countingRepository counts the orders created through it
+type countingRepository struct {
+	*mockRepository
+	creates int
+}
+
+func (r *countingRepository) Create(ctx context.Context, order *repository.Order, items []*repository.OrderItem) error {
+	r.creates++
+	order.ID = strconv.Itoa(r.creates)
+	return r.mockRepository.Create(ctx, order, items)
+}
+
+func TestCreateOrderIdempotency(t *testing.T) {
+	repo := &countingRepository{mockRepository: newMockRepository()}
+	svc := New(repo, log.NewDefault(), WithIdempotency(idempotency.NewMemoryStore()))
+	items := []*orderv1.OrderItem{{ProductId: "prod-1", Quantity: 1, PriceMinor: 100}}
+
+	tests := []struct {
+		name        string
+		ctx         context.Context
+		req         *orderv1.CreateOrderRequest
+		wantOrderID string
+		wantCode    string
+	}{
+		{
+			name:        "first call",
+			ctx:         context.Background(),
+			req:         &orderv1.CreateOrderRequest{UserId: "user-1", Items: items, IdempotencyKey: "k1"},
+			wantOrderID: "1",
+		},
+		{
+			name:        "retry",
+			ctx:         context.Background(),
+			req:         &orderv1.CreateOrderRequest{UserId: "user-1", Items: items, IdempotencyKey: "k1"},
+			wantOrderID: "1",
+		},
+		{
+			name:        "retry with key in metadata",
+			ctx:         metadata.NewIncomingContext(context.Background(), metadata.Pairs(idempotency.MetadataKey, "k1")),
+			req:         &orderv1.CreateOrderRequest{UserId: "user-1", Items: items},
+			wantOrderID: "1",
+		},
+		{
+			name:     "key reused for another request",
+			ctx:      context.Background(),
+			req:      &orderv1.CreateOrderRequest{UserId: "user-2", Items: items, IdempotencyKey: "k1"},
+			wantCode: errors.CodeInvalidInput,
+		},
+		{
+			name:        "new key",
+			ctx:         context.Background(),
+			req:         &orderv1.CreateOrderRequest{UserId: "user-1", Items: items, IdempotencyKey: "k2"},
+			wantOrderID: "2",
+		},
+		{
+			name:        "without key",
+			ctx:         context.Background(),
+			req:         &orderv1.CreateOrderRequest{UserId: "user-1", Items: items},
+			wantOrderID: "3",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := svc.CreateOrder(tt.ctx, tt.req)
+			if tt.wantCode != "" {
+				if errors.GetCode(err) != tt.wantCode {
+					t.Fatalf("CreateOrder() error = %v, want code %s", err, tt.wantCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CreateOrder() error = %v", err)
+			}
+			if got := resp.GetOrder().GetId(); got != tt.wantOrderID {
+				t.Errorf("CreateOrder() order = %s, want %s", got, tt.wantOrderID)
+			}
+		})
+	}
+
+	if repo.creates != 3 {
+		t.Errorf("created %d orders, want 3", repo.creates)
+	}
+}
+
+func TestListOrdersPagination(t *testing.T) {
+	repo := newMockRepository()
+	created := time.Now()
+	for i, id := range []string{"o1", "o2", "o3", "o4", "o5"} {
+		at := created.Add(time.Duration(i) * time.Second)
+		repo.orders[id] = &repository.Order{ID: id, UserID: "user-1", Status: "pending", CreatedAt: at, UpdatedAt: at}
+	}
+	svc := New(repo, log.NewDefault())
+
+	first, err := svc.ListOrders(context.Background(), &orderv1.ListOrdersRequest{PageSize: 2})
+	if err != nil {
+		t.Fatalf("ListOrders() error = %v", err)
+	}
+	second, err := svc.ListOrders(context.Background(), &orderv1.ListOrdersRequest{PageSize: 2, PageToken: first.GetNextPageToken()})
+	if err != nil {
+		t.Fatalf("ListOrders() error = %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		req         *orderv1.ListOrdersRequest
+		wantOrders  []string
+		wantHasMore bool
+		wantTotal   int64
+		wantErr     bool
+	}{
+		{name: "first page", req: &orderv1.ListOrdersRequest{PageSize: 2}, wantOrders: []string{"o5", "o4"}, wantHasMore: true},
+		{name: "next page", req: &orderv1.ListOrdersRequest{PageSize: 2, PageToken: first.GetNextPageToken()}, wantOrders: []string{"o3", "o2"}, wantHasMore: true},
+		{name: "last page", req: &orderv1.ListOrdersRequest{PageSize: 2, PageToken: second.GetNextPageToken()}, wantOrders: []string{"o1"}},
+		{name: "with total", req: &orderv1.ListOrdersRequest{UserId: "user-1", PageSize: 5, IncludeTotal: true}, wantOrders: []string{"o5", "o4", "o3", "o2", "o1"}, wantTotal: 5},
+		{name: "offset token", req: &orderv1.ListOrdersRequest{PageSize: 2, PageToken: "2"}, wantErr: true},
+		{name: "token of another listing", req: &orderv1.ListOrdersRequest{UserId: "user-1", PageSize: 2, PageToken: first.GetNextPageToken()}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := svc.ListOrders(context.Background(), tt.req)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ListOrders() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			var got []string
+			for _, o := range resp.GetOrders() {
+				got = append(got, o.GetId())
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.wantOrders) {
+				t.Errorf("got orders %v, want %v", got, tt.wantOrders)
+			}
+			if resp.GetHasMore() != tt.wantHasMore || (resp.GetNextPageToken() != "") != tt.wantHasMore {
+				t.Errorf("has_more = %v, next_page_token = %q, want has_more %v", resp.GetHasMore(), resp.GetNextPageToken(), tt.wantHasMore)
+			}
+			if resp.GetTotalSize() != tt.wantTotal || resp.GetTotalSizeExact() != tt.req.GetIncludeTotal() {
+				t.Errorf("total_size = %d (exact %v), want %d", resp.GetTotalSize(), resp.GetTotalSizeExact(), tt.wantTotal)
+			}
+		})
+	}
+}
+
+func TestListOrdersFilter(t *testing.T) {
+	repo := newMockRepository()
+	created := time.Now().Truncate(time.Second)
+	for i, o := range []struct {
+		id     string
+		status string
+		total  int64
+	}{
+		{"o1", "pending", 500},
+		{"o2", "confirmed", 1500},
+		{"o3", "pending", 2500},
+		{"o4", "cancelled", 1500},
+		{"o5", "pending", 100},
+	} {
+		at := created.Add(time.Duration(i) * time.Second)
+		repo.orders[o.id] = &repository.Order{ID: o.id, UserID: "user-1", Status: o.status, TotalAmount: o.total, Currency: "USD", CreatedAt: at, UpdatedAt: at}
+	}
+	svc := New(repo, log.NewDefault())
+
+	byTotal, err := svc.ListOrders(context.Background(), &orderv1.ListOrdersRequest{PageSize: 2, OrderBy: "total_amount_minor desc"})
+	if err != nil {
+		t.Fatalf("ListOrders() error = %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		req        *orderv1.ListOrdersRequest
+		wantOrders []string
+		wantCode   string
+	}{
+		{name: "status", req: &orderv1.ListOrdersRequest{Status: orderv1.OrderStatus_ORDER_STATUS_PENDING}, wantOrders: []string{"o5", "o3", "o1"}},
+		{name: "created range", req: &orderv1.ListOrdersRequest{
+			CreatedAfter:  timestamppb.New(created.Add(time.Second)),
+			CreatedBefore: timestamppb.New(created.Add(3 * time.Second)),
+		}, wantOrders: []string{"o3", "o2"}},
+		{name: "total range", req: &orderv1.ListOrdersRequest{MinTotalMinor: 500, MaxTotalMinor: 1500}, wantOrders: []string{"o4", "o2", "o1"}},
+		{name: "oldest first", req: &orderv1.ListOrdersRequest{OrderBy: "created_at"}, wantOrders: []string{"o1", "o2", "o3", "o4", "o5"}},
+		{name: "explicit ascending", req: &orderv1.ListOrdersRequest{OrderBy: "CREATED_AT asc"}, wantOrders: []string{"o1", "o2", "o3", "o4", "o5"}},
+		{name: "cheapest first", req: &orderv1.ListOrdersRequest{OrderBy: "total_amount_minor", Status: orderv1.OrderStatus_ORDER_STATUS_PENDING}, wantOrders: []string{"o5", "o1", "o3"}},
+		{name: "by total next page", req: &orderv1.ListOrdersRequest{PageSize: 2, OrderBy: "total_amount_minor desc", PageToken: byTotal.GetNextPageToken()}, wantOrders: []string{"o2", "o1"}},
+		{name: "token of another ordering", req: &orderv1.ListOrdersRequest{PageSize: 2, PageToken: byTotal.GetNextPageToken()}, wantCode: errors.CodeInvalidInput},
+		{name: "unknown order_by", req: &orderv1.ListOrdersRequest{OrderBy: "user_id"}, wantCode: errors.CodeInvalidInput},
+		{name: "unknown status", req: &orderv1.ListOrdersRequest{Status: orderv1.OrderStatus(42)}, wantCode: errors.CodeInvalidInput},
+		{name: "empty created range", req: &orderv1.ListOrdersRequest{
+			CreatedAfter:  timestamppb.New(created),
+			CreatedBefore: timestamppb.New(created),
+		}, wantCode: errors.CodeInvalidInput},
+		{name: "min above max", req: &orderv1.ListOrdersRequest{MinTotalMinor: 2000, MaxTotalMinor: 1000}, wantCode: errors.CodeInvalidInput},
+		{name: "negative total", req: &orderv1.ListOrdersRequest{MinTotalMinor: -1}, wantCode: errors.CodeInvalidInput},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := svc.ListOrders(context.Background(), tt.req)
+			if tt.wantCode != "" {
+				if errors.GetCode(err) != tt.wantCode {
+					t.Fatalf("ListOrders() error = %v, want code %s", err, tt.wantCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ListOrders() error = %v", err)
+			}
+			var got []string
+			for _, o := range resp.GetOrders() {
+				got = append(got, o.GetId())
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.wantOrders) {
+				t.Errorf("got orders %v, want %v", got, tt.wantOrders)
+			}
+		})
+	}
+}
+
+func TestReadMask(t *testing.T) {
+	repo := newMockRepository()
+	created := time.Now()
+	repo.orders["o1"] = &repository.Order{ID: "o1", UserID: "u1", Status: "pending", TotalAmount: 2000, Currency: "USD", CreatedAt: created, UpdatedAt: created}
+	repo.orderItems["o1"] = []*repository.OrderItem{{ID: "i1", OrderID: "o1", ProductID: "p1", Quantity: 2, Price: 1000}}
+	svc := New(repo, log.NewDefault())
+
+	tests := []struct {
+		name          string
+		paths         []string
+		list          bool
+		wantErr       bool
+		wantItemLoads int
+		want          func(o *orderv1.Order) bool
+	}{
+		{
+			name:          "get without mask",
+			wantItemLoads: 1,
+			want:          func(o *orderv1.Order) bool { return len(o.GetItems()) == 1 && o.GetTotalAmount() == 20 },
+		},
+		{
+			name:  "get skips items",
+			paths: []string{"id", "status"},
+			want: func(o *orderv1.Order) bool {
+				return o.GetId() == "o1" && o.GetStatus() == orderv1.OrderStatus_ORDER_STATUS_PENDING &&
+					len(o.GetItems()) == 0 && o.GetTotalAmount() == 0 && o.GetCreatedAt() == nil
+			},
+		},
+		{
+			name:          "get only items",
+			paths:         []string{"items"},
+			wantItemLoads: 1,
+			want:          func(o *orderv1.Order) bool { return len(o.GetItems()) == 1 && o.GetId() == "" },
+		},
+		{
+			name:    "get unknown field",
+			paths:   []string{"shipping_address"},
+			wantErr: true,
+		},
+		{
+			name: "list without mask",
+			list: true,
+			want: func(o *orderv1.Order) bool { return len(o.GetItems()) == 0 && o.GetUserId() == "u1" },
+		},
+		{
+			name:          "list with items",
+			paths:         []string{"id", "items"},
+			list:          true,
+			wantItemLoads: 1,
+			want:          func(o *orderv1.Order) bool { return len(o.GetItems()) == 1 && o.GetUserId() == "" },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo.itemLoads = 0
+			var mask *fieldmaskpb.FieldMask
+			if tt.paths != nil {
+				mask = &fieldmaskpb.FieldMask{Paths: tt.paths}
+			}
+
+			var order *orderv1.Order
+			var err error
+			if tt.list {
+				var resp *orderv1.ListOrdersResponse
+				resp, err = svc.ListOrders(context.Background(), &orderv1.ListOrdersRequest{ReadMask: mask})
+				if err == nil {
+					if len(resp.GetOrders()) != 1 {
+						t.Fatalf("ListOrders() returned %d orders, want 1", len(resp.GetOrders()))
+					}
+					order = resp.GetOrders()[0]
+				}
+			} else {
+				var resp *orderv1.GetOrderResponse
+				resp, err = svc.GetOrder(context.Background(), &orderv1.GetOrderRequest{Id: "o1", ReadMask: mask})
+				order = resp.GetOrder()
+			}
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if errors.GetCode(err) != errors.CodeInvalidInput {
+					t.Errorf("error code = %v, want %v", errors.GetCode(err), errors.CodeInvalidInput)
+				}
+				return
+			}
+			if repo.itemLoads != tt.wantItemLoads {
+				t.Errorf("item queries = %d, want %d", repo.itemLoads, tt.wantItemLoads)
+			}
+			if !tt.want(order) {
+				t.Errorf("order = %v", order)
+			}
+		})
+	}
+}
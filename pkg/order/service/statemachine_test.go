@@ -0,0 +1,149 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	orderv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/order/v1"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/internal/log"
+	"github.com/kevindiu/monorepo-go-example/pkg/order/repository"
+)
+
+func TestUpdateOrderStatusTransitions(t *testing.T) {
+	tests := []struct {
+		name    string
+		from    string
+		to      orderv1.OrderStatus
+		wantErr string
+	}{
+		{name: "confirm", from: "pending", to: orderv1.OrderStatus_ORDER_STATUS_CONFIRMED},
+		{name: "ship", from: "confirmed", to: orderv1.OrderStatus_ORDER_STATUS_SHIPPED},
+		{name: "deliver", from: "shipped", to: orderv1.OrderStatus_ORDER_STATUS_DELIVERED},
+		{
+			name:    "back to pending",
+			from:    "delivered",
+			to:      orderv1.OrderStatus_ORDER_STATUS_PENDING,
+			wantErr: "from delivered to pending",
+		},
+		{
+			name:    "skip confirmation",
+			from:    "pending",
+			to:      orderv1.OrderStatus_ORDER_STATUS_SHIPPED,
+			wantErr: "from pending to shipped",
+		},
+		{
+			name:    "cancel shipped",
+			from:    "shipped",
+			to:      orderv1.OrderStatus_ORDER_STATUS_CANCELLED,
+			wantErr: "from shipped to cancelled",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMockRepository()
+			repo.orders["order-1"] = &repository.Order{ID: "order-1", Status: tt.from, Currency: "USD"}
+			svc := New(repo, log.NewDefault())
+
+			_, err := svc.UpdateOrderStatus(context.Background(), &orderv1.UpdateOrderStatusRequest{Id: "order-1", Status: tt.to})
+			if tt.wantErr != "" {
+				if errors.GetCode(err) != errors.CodeInvalidInput || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("UpdateOrderStatus() error = %v, want invalid input %q", err, tt.wantErr)
+				}
+				if repo.orders["order-1"].Status != tt.from {
+					t.Errorf("UpdateOrderStatus() changed the status to %q", repo.orders["order-1"].Status)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("UpdateOrderStatus() error = %v", err)
+			}
+			if got := repo.orders["order-1"].Status; got != statusFromProto(tt.to) {
+				t.Errorf("status = %q, want %q", got, statusFromProto(tt.to))
+			}
+		})
+	}
+}
+
+func TestCancelOrderTransitions(t *testing.T) {
+	tests := []struct {
+		from    string
+		wantErr bool
+	}{
+		{from: "pending"},
+		{from: "confirmed"},
+		{from: "shipped", wantErr: true},
+		{from: "delivered", wantErr: true},
+		{from: "cancelled", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.from, func(t *testing.T) {
+			repo := newMockRepository()
+			repo.orders["order-1"] = &repository.Order{ID: "order-1", Status: tt.from, Currency: "USD"}
+			svc := New(repo, log.NewDefault())
+
+			_, err := svc.CancelOrder(context.Background(), &orderv1.CancelOrderRequest{Id: "order-1"})
+			if tt.wantErr {
+				if errors.GetCode(err) != errors.CodeInvalidInput {
+					t.Errorf("CancelOrder() error = %v, want invalid input", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CancelOrder() error = %v", err)
+			}
+			if got := repo.orders["order-1"].Status; got != "cancelled" {
+				t.Errorf("status = %q, want cancelled", got)
+			}
+		})
+	}
+}
+
+// staleRepository returns the orders as they were when it was created, like
+// a cache or a read that raced with another update
+type staleRepository struct {
+	*mockRepository
+	snapshot map[string]repository.Order
+}
+
+func (r *staleRepository) Get(ctx context.Context, id string) (*repository.Order, error) {
+	order := r.snapshot[id]
+	return &order, nil
+}
+
+func TestUpdateOrderStatusStaleRead(t *testing.T) {
+	mock := newMockRepository()
+	mock.orders["order-1"] = &repository.Order{ID: "order-1", Status: "pending", Currency: "USD"}
+	repo := &staleRepository{mockRepository: mock, snapshot: map[string]repository.Order{"order-1": *mock.orders["order-1"]}}
+	svc := New(repo, log.NewDefault())
+
+	// Cancelled by another request after the snapshot was taken
+	mock.orders["order-1"].Status = "cancelled"
+
+	_, err := svc.UpdateOrderStatus(context.Background(), &orderv1.UpdateOrderStatusRequest{Id: "order-1", Status: orderv1.OrderStatus_ORDER_STATUS_CONFIRMED})
+	if errors.GetCode(err) != errors.CodeInvalidInput {
+		t.Fatalf("UpdateOrderStatus() of a stale pending order error = %v, want invalid input", err)
+	}
+	if got := mock.orders["order-1"].Status; got != "cancelled" {
+		t.Errorf("status = %q, want cancelled", got)
+	}
+}
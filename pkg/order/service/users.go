@@ -0,0 +1,83 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package service
+
+import (
+	"context"
+
+	userv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/user/v1"
+	"github.com/kevindiu/monorepo-go-example/internal/breaker"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/internal/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Users checks that the users orders are created for exist
+type Users interface {
+	// Exists reports whether the user exists. An error means the answer is
+	// unknown.
+	Exists(ctx context.Context, userID string) (bool, error)
+}
+
+// UserClient checks users with the user service. Lookups stop for a while
+// after repeated failures, so that orders fail fast while the user service
+// is down.
+type UserClient struct {
+	client   userv1.UserServiceClient
+	breaker  *breaker.Breaker
+	failOpen bool
+	logger   *log.Logger
+}
+
+// NewUserClient checks users through client, guarded by b. With failOpen,
+// users are assumed to exist while the user service is failing.
+func NewUserClient(client userv1.UserServiceClient, b *breaker.Breaker, failOpen bool, logger *log.Logger) *UserClient {
+	return &UserClient{client: client, breaker: b, failOpen: failOpen, logger: logger}
+}
+
+// Exists reports whether the user exists. Only failures to get an answer
+// count against the breaker; a missing user is an answer.
+func (c *UserClient) Exists(ctx context.Context, userID string) (bool, error) {
+	exists := true
+	err := c.breaker.Do(func() error {
+		_, err := c.client.GetUser(ctx, &userv1.GetUserRequest{Id: userID})
+		if status.Code(err) == codes.NotFound {
+			exists = false
+			return nil
+		}
+		return err
+	})
+	if err == nil {
+		return exists, nil
+	}
+
+	if c.failOpen {
+		c.logger.Warn("Skipping user check while the user service is failing",
+			log.String("user_id", userID), log.Error(err))
+		return true, nil
+	}
+	return false, errors.WithCode(errors.Wrap(err, "failed to check user"), errors.CodeUnavailable)
+}
+
+// WithUsers makes CreateOrder reject orders for users that users does not
+// know. Without it, any user ID is accepted.
+func WithUsers(users Users) Option {
+	return func(s *service) {
+		s.users = users
+	}
+}
@@ -0,0 +1,134 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	orderv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/order/v1"
+	userv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/user/v1"
+	"github.com/kevindiu/monorepo-go-example/internal/breaker"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/internal/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeUserService answers GetUser with err, counting calls
+type fakeUserService struct {
+	userv1.UserServiceClient
+	err   error
+	calls int
+}
+
+func (f *fakeUserService) GetUser(ctx context.Context, req *userv1.GetUserRequest, opts ...grpc.CallOption) (*userv1.GetUserResponse, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &userv1.GetUserResponse{User: &userv1.User{Id: req.GetId()}}, nil
+}
+
+func TestUserClientExists(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		failOpen bool
+		want     bool
+		wantCode string
+	}{
+		{name: "exists", want: true},
+		{name: "not found", err: status.Error(codes.NotFound, "user not found")},
+		{name: "unavailable", err: status.Error(codes.Unavailable, "connection refused"), wantCode: errors.CodeUnavailable},
+		{name: "unavailable fail open", err: status.Error(codes.Unavailable, "connection refused"), failOpen: true, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			users := NewUserClient(&fakeUserService{err: tt.err}, breaker.New(5, time.Minute), tt.failOpen, log.NewDefault())
+
+			got, err := users.Exists(context.Background(), "user-1")
+			if errors.GetCode(err) != tt.wantCode {
+				t.Fatalf("Exists() error = %v, want code %q", err, tt.wantCode)
+			}
+			if got != tt.want {
+				t.Errorf("Exists() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUserClientBreaker(t *testing.T) {
+	fake := &fakeUserService{err: status.Error(codes.Unavailable, "connection refused")}
+	users := NewUserClient(fake, breaker.New(2, time.Minute), false, log.NewDefault())
+
+	for i := 0; i < 4; i++ {
+		if _, err := users.Exists(context.Background(), "user-1"); errors.GetCode(err) != errors.CodeUnavailable {
+			t.Fatalf("Exists() error = %v, want unavailable", err)
+		}
+	}
+	if fake.calls != 2 {
+		t.Errorf("user service called %d times, want 2 before the breaker opened", fake.calls)
+	}
+
+	// Missing users are answers, not failures
+	fake.err = status.Error(codes.NotFound, "user not found")
+	users = NewUserClient(fake, breaker.New(2, time.Minute), false, log.NewDefault())
+	for i := 0; i < 4; i++ {
+		if _, err := users.Exists(context.Background(), "user-1"); err != nil {
+			t.Fatalf("Exists() error = %v", err)
+		}
+	}
+}
+
+func TestCreateOrderChecksUser(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantCode string
+	}{
+		{name: "existing user"},
+		{name: "missing user", err: status.Error(codes.NotFound, "user not found"), wantCode: errors.CodeInvalidInput},
+		{name: "user service down", err: status.Error(codes.Unavailable, "connection refused"), wantCode: errors.CodeUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMockRepository()
+			users := NewUserClient(&fakeUserService{err: tt.err}, breaker.New(5, time.Minute), false, log.NewDefault())
+			svc := New(repo, log.NewDefault(), WithUsers(users))
+
+			_, err := svc.CreateOrder(context.Background(), &orderv1.CreateOrderRequest{
+				UserId: "user-1",
+				Items:  []*orderv1.OrderItem{{ProductId: "prod-1", Quantity: 1, PriceMinor: 500}},
+			})
+			if errors.GetCode(err) != tt.wantCode {
+				t.Fatalf("CreateOrder() error = %v, want code %q", err, tt.wantCode)
+			}
+			wantOrders := 1
+			if tt.wantCode != "" {
+				wantOrders = 0
+			}
+			if len(repo.orders) != wantOrders {
+				t.Errorf("CreateOrder() stored %d orders, want %d", len(repo.orders), wantOrders)
+			}
+		})
+	}
+}
@@ -0,0 +1,229 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package service
+
+import (
+	"context"
+	"strings"
+
+	orderv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/order/v1"
+	orderv2 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/order/v2"
+	"github.com/kevindiu/monorepo-go-example/internal/fieldmask"
+	"github.com/kevindiu/monorepo-go-example/internal/log"
+	"github.com/kevindiu/monorepo-go-example/internal/money"
+	"github.com/kevindiu/monorepo-go-example/pkg/order/repository"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// ServiceV2 defines the v2 order service interface
+type ServiceV2 interface {
+	orderv2.OrderServiceServer
+}
+
+// serviceV2 serves the v2 API on top of the v1 service, translating
+// amounts to Money and field masks to their v1 paths
+type serviceV2 struct {
+	orderv2.UnimplementedOrderServiceServer
+	v1 *service
+}
+
+// NewV2 creates a new v2 order service
+func NewV2(repo repository.Repository, logger *log.Logger, opts ...Option) ServiceV2 {
+	return &serviceV2{v1: newService(repo, logger, opts...)}
+}
+
+// v1Fields maps the top-level fields of a v2 order to the v1 fields they
+// are built from
+var v1Fields = map[string][]string{
+	"id":          {"id"},
+	"user_id":     {"user_id"},
+	"items":       {"items", "currency"},
+	"total":       {"total_amount_minor", "currency"},
+	"status":      {"status"},
+	"create_time": {"created_at"},
+	"update_time": {"updated_at"},
+	"discounts":   {"discounts", "currency"},
+}
+
+// v1Mask translates a validated v2 read mask to the v1 mask that reads
+// everything it needs
+func v1Mask(mask *fieldmaskpb.FieldMask) *fieldmaskpb.FieldMask {
+	if len(mask.GetPaths()) == 0 {
+		return nil
+	}
+	seen := make(map[string]bool)
+	out := &fieldmaskpb.FieldMask{}
+	for _, path := range mask.GetPaths() {
+		field, _, _ := strings.Cut(path, ".")
+		for _, p := range v1Fields[field] {
+			if !seen[p] {
+				seen[p] = true
+				out.Paths = append(out.Paths, p)
+			}
+		}
+	}
+	return out
+}
+
+// orderToV2 converts a v1 order to v2
+func orderToV2(o *orderv1.Order) *orderv2.Order {
+	pb := &orderv2.Order{
+		Id:         o.GetId(),
+		UserId:     o.GetUserId(),
+		Total:      money.ToProto(o.GetTotalAmountMinor(), o.GetCurrency()),
+		Status:     orderv2.OrderStatus(o.GetStatus()),
+		CreateTime: o.GetCreatedAt(),
+		UpdateTime: o.GetUpdatedAt(),
+	}
+	for _, item := range o.GetItems() {
+		pb.Items = append(pb.Items, &orderv2.OrderItem{
+			Id:          item.GetId(),
+			ProductId:   item.GetProductId(),
+			ProductName: item.GetProductName(),
+			Quantity:    item.GetQuantity(),
+			Price:       money.ToProto(item.GetPriceMinor(), o.GetCurrency()),
+		})
+	}
+	for _, d := range o.GetDiscounts() {
+		pb.Discounts = append(pb.Discounts, &orderv2.OrderDiscount{
+			Code:   d.GetCode(),
+			Amount: money.ToProto(d.GetAmountMinor(), o.GetCurrency()),
+		})
+	}
+	return pb
+}
+
+// CreateOrder creates a new order
+func (s *serviceV2) CreateOrder(ctx context.Context, req *orderv2.CreateOrderRequest) (*orderv2.CreateOrderResponse, error) {
+	items := make([]*orderv1.OrderItem, len(req.GetItems()))
+	for i, item := range req.GetItems() {
+		price, err := money.FromProto(item.GetPrice(), s.v1.currency)
+		if err != nil {
+			return nil, err
+		}
+		items[i] = &orderv1.OrderItem{
+			ProductId:  item.GetProductId(),
+			Quantity:   item.GetQuantity(),
+			PriceMinor: price,
+		}
+	}
+
+	resp, err := s.v1.CreateOrder(ctx, &orderv1.CreateOrderRequest{
+		UserId:         req.GetUserId(),
+		Items:          items,
+		CouponCodes:    req.GetCouponCodes(),
+		IdempotencyKey: req.GetIdempotencyKey(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &orderv2.CreateOrderResponse{Order: orderToV2(resp.GetOrder())}, nil
+}
+
+// GetOrder retrieves an order by ID
+func (s *serviceV2) GetOrder(ctx context.Context, req *orderv2.GetOrderRequest) (*orderv2.GetOrderResponse, error) {
+	if err := fieldmask.Validate(&orderv2.Order{}, req.GetReadMask()); err != nil {
+		return nil, err
+	}
+
+	resp, err := s.v1.GetOrder(ctx, &orderv1.GetOrderRequest{
+		Id:       req.GetId(),
+		Currency: req.GetCurrency(),
+		ReadMask: v1Mask(req.GetReadMask()),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	order := orderToV2(resp.GetOrder())
+	fieldmask.Trim(order, req.GetReadMask())
+	return &orderv2.GetOrderResponse{Order: order}, nil
+}
+
+// ListOrders lists orders, newest first, by cursor
+func (s *serviceV2) ListOrders(ctx context.Context, req *orderv2.ListOrdersRequest) (*orderv2.ListOrdersResponse, error) {
+	s.v1.loggerFor(ctx).Info("Listing orders", log.String("user_id", req.GetUserId()), log.Int32("page_size", req.GetPageSize()))
+
+	pageSize := normalizePageSize(req.GetPageSize())
+
+	filter := repository.Filter{UserID: req.GetUserId()}
+	after, err := s.v1.pageTokens.Decode(ordersScope(filter), req.GetPageToken())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := fieldmask.Validate(&orderv2.Order{}, req.GetReadMask()); err != nil {
+		return nil, err
+	}
+
+	target, rates, err := s.v1.targetCurrency(ctx, req.GetCurrency())
+	if err != nil {
+		return nil, err
+	}
+
+	// Fetch one more order than the page holds to learn whether another
+	// page follows
+	orders, err := s.v1.repo.ListAfter(ctx, filter, after, pageSize+1)
+	if err != nil {
+		s.v1.loggerFor(ctx).Error("Failed to list orders", log.Error(err))
+		return nil, err
+	}
+	hasMore := len(orders) > pageSize
+	if hasMore {
+		orders = orders[:pageSize]
+	}
+
+	pbOrders, err := s.v1.ordersToProto(ctx, orders, v1Mask(req.GetReadMask()), target, rates)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &orderv2.ListOrdersResponse{Orders: make([]*orderv2.Order, len(pbOrders))}
+	for i, o := range pbOrders {
+		resp.Orders[i] = orderToV2(o)
+		fieldmask.Trim(resp.Orders[i], req.GetReadMask())
+	}
+	if hasMore {
+		resp.NextPageToken = s.v1.nextPageToken(filter, orders)
+	}
+	return resp, nil
+}
+
+// UpdateOrderStatus updates the order status
+func (s *serviceV2) UpdateOrderStatus(ctx context.Context, req *orderv2.UpdateOrderStatusRequest) (*orderv2.UpdateOrderStatusResponse, error) {
+	resp, err := s.v1.UpdateOrderStatus(ctx, &orderv1.UpdateOrderStatusRequest{
+		Id:     req.GetId(),
+		Status: orderv1.OrderStatus(req.GetStatus()),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &orderv2.UpdateOrderStatusResponse{Order: orderToV2(resp.GetOrder())}, nil
+}
+
+// CancelOrder cancels an order and returns it
+func (s *serviceV2) CancelOrder(ctx context.Context, req *orderv2.CancelOrderRequest) (*orderv2.CancelOrderResponse, error) {
+	if _, err := s.v1.CancelOrder(ctx, &orderv1.CancelOrderRequest{Id: req.GetId()}); err != nil {
+		return nil, err
+	}
+
+	resp, err := s.v1.GetOrder(ctx, &orderv1.GetOrderRequest{Id: req.GetId()})
+	if err != nil {
+		return nil, err
+	}
+	return &orderv2.CancelOrderResponse{Order: orderToV2(resp.GetOrder())}, nil
+}
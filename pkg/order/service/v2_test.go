@@ -0,0 +1,150 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	orderv2 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/order/v2"
+	"github.com/kevindiu/monorepo-go-example/internal/log"
+	"github.com/kevindiu/monorepo-go-example/pkg/order/repository"
+	"google.golang.org/genproto/googleapis/type/money"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+func TestListOrdersV2Cursor(t *testing.T) {
+	repo := newMockRepository()
+	created := time.Now()
+	for i := 0; i < 5; i++ {
+		id := fmt.Sprintf("o%d", i)
+		// o3 and o4 share a creation time, so the ID breaks the tie
+		at := created.Add(time.Duration(min(i, 3)) * time.Second)
+		repo.orders[id] = &repository.Order{ID: id, UserID: "u1", Status: "pending", TotalAmount: 1000, Currency: "USD", CreatedAt: at, UpdatedAt: at}
+	}
+	svc := NewV2(repo, log.NewDefault())
+
+	var got []string
+	token := ""
+	for pages := 0; ; pages++ {
+		if pages > 5 {
+			t.Fatal("ListOrders() did not reach the last page")
+		}
+		resp, err := svc.ListOrders(context.Background(), &orderv2.ListOrdersRequest{PageSize: 2, PageToken: token})
+		if err != nil {
+			t.Fatalf("ListOrders() error = %v", err)
+		}
+		for _, o := range resp.GetOrders() {
+			got = append(got, o.GetId())
+			if o.GetTotal().GetUnits() != 10 || o.GetTotal().GetCurrencyCode() != "USD" {
+				t.Errorf("order %s total = %v", o.GetId(), o.GetTotal())
+			}
+		}
+		if token = resp.GetNextPageToken(); token == "" {
+			break
+		}
+	}
+
+	want := []string{"o4", "o3", "o2", "o1", "o0"}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("ListOrders() walked %v, want %v", got, want)
+	}
+
+	if _, err := svc.ListOrders(context.Background(), &orderv2.ListOrdersRequest{PageToken: "not a cursor"}); err == nil {
+		t.Error("ListOrders() should reject an invalid page_token")
+	}
+}
+
+func TestGetOrderV2ReadMask(t *testing.T) {
+	repo := newMockRepository()
+	created := time.Now()
+	repo.orders["o1"] = &repository.Order{ID: "o1", UserID: "u1", Status: "shipped", TotalAmount: 2050, Currency: "USD", CreatedAt: created, UpdatedAt: created}
+	repo.orderItems["o1"] = []*repository.OrderItem{{ID: "i1", OrderID: "o1", ProductID: "p1", Quantity: 2, Price: 1025}}
+	svc := NewV2(repo, log.NewDefault())
+
+	tests := []struct {
+		name    string
+		paths   []string
+		wantErr bool
+		want    func(o *orderv2.Order) bool
+	}{
+		{
+			name: "without mask",
+			want: func(o *orderv2.Order) bool {
+				return o.GetStatus() == orderv2.OrderStatus_ORDER_STATUS_SHIPPED && o.GetCreateTime() != nil &&
+					o.GetItems()[0].GetPrice().GetNanos() == 250000000
+			},
+		},
+		{
+			name:  "total only",
+			paths: []string{"total"},
+			want: func(o *orderv2.Order) bool {
+				return o.GetId() == "" && len(o.GetItems()) == 0 &&
+					o.GetTotal().GetUnits() == 20 && o.GetTotal().GetCurrencyCode() == "USD"
+			},
+		},
+		{
+			name:    "v1 field",
+			paths:   []string{"total_amount"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mask *fieldmaskpb.FieldMask
+			if tt.paths != nil {
+				mask = &fieldmaskpb.FieldMask{Paths: tt.paths}
+			}
+			resp, err := svc.GetOrder(context.Background(), &orderv2.GetOrderRequest{Id: "o1", ReadMask: mask})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetOrder() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !tt.want(resp.GetOrder()) {
+				t.Errorf("GetOrder() = %v", resp.GetOrder())
+			}
+		})
+	}
+}
+
+func TestCreateOrderV2(t *testing.T) {
+	svc := NewV2(newMockRepository(), log.NewDefault())
+
+	resp, err := svc.CreateOrder(context.Background(), &orderv2.CreateOrderRequest{
+		UserId: "u1",
+		Items:  []*orderv2.CreateOrderItem{{ProductId: "p1", Quantity: 3, Price: &money.Money{CurrencyCode: "usd", Units: 1, Nanos: 500000000}}},
+	})
+	if err != nil {
+		t.Fatalf("CreateOrder() error = %v", err)
+	}
+	if total := resp.GetOrder().GetTotal(); total.GetUnits() != 4 || total.GetNanos() != 500000000 {
+		t.Errorf("CreateOrder() total = %v, want 4.5", total)
+	}
+
+	_, err = svc.CreateOrder(context.Background(), &orderv2.CreateOrderRequest{
+		UserId: "u1",
+		Items:  []*orderv2.CreateOrderItem{{ProductId: "p1", Quantity: 1, Price: &money.Money{CurrencyCode: "EUR", Units: 1}}},
+	})
+	if err == nil {
+		t.Error("CreateOrder() should reject prices in another currency")
+	}
+}
@@ -0,0 +1,87 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package service
+
+import (
+	orderv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/order/v1"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/internal/log"
+	"github.com/kevindiu/monorepo-go-example/internal/tenant"
+	"github.com/kevindiu/monorepo-go-example/pkg/order/repository"
+)
+
+// finalStatus reports whether an order's status can no longer change
+func finalStatus(status string) bool {
+	return status == "delivered" || status == "cancelled"
+}
+
+// WatchOrder sends the order, then sends it again after each status change
+// until it is delivered or cancelled. The subscription is made before the
+// first read, so no change between the two is missed.
+func (s *service) WatchOrder(req *orderv1.WatchOrderRequest, stream orderv1.OrderService_WatchOrderServer) error {
+	ctx := stream.Context()
+
+	if req.GetId() == "" {
+		return errors.WithCode(errors.New("id is required"), errors.CodeInvalidInput)
+	}
+	if s.watch == nil {
+		return errors.WithCode(errors.New("watching orders is not enabled"), errors.CodeUnavailable)
+	}
+
+	target, rates, err := s.targetCurrency(ctx, req.GetCurrency())
+	if err != nil {
+		return err
+	}
+
+	changes, stop := s.watch.Subscribe(tenant.ID(ctx), req.GetId())
+	defer stop()
+
+	s.loggerFor(ctx).Info("Watching order", log.String("order_id", req.GetId()))
+
+	var sent string
+	for {
+		order, err := s.repo.Get(ctx, req.GetId())
+		if err != nil {
+			return err
+		}
+
+		// Notifications can repeat, e.g. after a reconnection
+		if order.Status != sent {
+			pbOrders, err := s.ordersToProto(ctx, []*repository.Order{order}, nil, target, rates)
+			if err != nil {
+				return err
+			}
+			if err := stream.Send(&orderv1.WatchOrderResponse{Order: pbOrders[0]}); err != nil {
+				return err
+			}
+			sent = order.Status
+		}
+
+		if finalStatus(order.Status) {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case _, ok := <-changes:
+			if !ok {
+				return errors.WithCode(errors.New("server is shutting down; watch the order again"), errors.CodeUnavailable)
+			}
+		}
+	}
+}
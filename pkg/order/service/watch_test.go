@@ -0,0 +1,151 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	orderv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/order/v1"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/internal/log"
+	"github.com/kevindiu/monorepo-go-example/internal/tenant"
+	"github.com/kevindiu/monorepo-go-example/pkg/order/repository"
+	"github.com/kevindiu/monorepo-go-example/pkg/order/watch"
+	"google.golang.org/grpc"
+)
+
+// mockWatchStream collects the orders sent by WatchOrder
+type mockWatchStream struct {
+	grpc.ServerStream
+	ctx      context.Context
+	statuses []orderv1.OrderStatus
+}
+
+func (m *mockWatchStream) Context() context.Context { return m.ctx }
+
+func (m *mockWatchStream) Send(resp *orderv1.WatchOrderResponse) error {
+	m.statuses = append(m.statuses, resp.GetOrder().GetStatus())
+	return nil
+}
+
+// watchRepository plays back a sequence of statuses, one per read, and
+// announces every read as a change so that WatchOrder reads again
+type watchRepository struct {
+	*mockRepository
+	hub      *watch.Hub
+	statuses []string
+}
+
+func (r *watchRepository) Get(ctx context.Context, id string) (*repository.Order, error) {
+	order, err := r.mockRepository.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(r.statuses) > 0 {
+		order.Status, r.statuses = r.statuses[0], r.statuses[1:]
+	}
+	r.hub.Notify(tenant.ID(ctx), id)
+	return order, nil
+}
+
+func TestWatchOrder(t *testing.T) {
+	tests := []struct {
+		name         string
+		id           string
+		statuses     []string
+		closeHub     bool
+		wantStatuses []orderv1.OrderStatus
+		wantCode     string
+	}{
+		{
+			name:     "until delivered",
+			id:       "o1",
+			statuses: []string{"pending", "pending", "shipped", "delivered"},
+			wantStatuses: []orderv1.OrderStatus{
+				orderv1.OrderStatus_ORDER_STATUS_PENDING,
+				orderv1.OrderStatus_ORDER_STATUS_SHIPPED,
+				orderv1.OrderStatus_ORDER_STATUS_DELIVERED,
+			},
+		},
+		{
+			name:         "already cancelled",
+			id:           "o1",
+			statuses:     []string{"cancelled"},
+			wantStatuses: []orderv1.OrderStatus{orderv1.OrderStatus_ORDER_STATUS_CANCELLED},
+		},
+		{
+			name:         "shutting down",
+			id:           "o1",
+			statuses:     []string{"pending"},
+			closeHub:     true,
+			wantStatuses: []orderv1.OrderStatus{orderv1.OrderStatus_ORDER_STATUS_PENDING},
+			wantCode:     errors.CodeUnavailable,
+		},
+		{name: "missing id", wantCode: errors.CodeInvalidInput},
+		{name: "unknown order", id: "missing", wantCode: errors.CodeNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hub := watch.NewHub()
+			if tt.closeHub {
+				hub.Close()
+			}
+			repo := &watchRepository{mockRepository: newMockRepository(), hub: hub, statuses: tt.statuses}
+			repo.orders["o1"] = &repository.Order{ID: "o1", UserID: "u1", CreatedAt: time.Now(), UpdatedAt: time.Now()}
+			svc := New(repo, log.NewDefault(), WithWatch(hub))
+
+			stream := &mockWatchStream{ctx: context.Background()}
+			err := svc.WatchOrder(&orderv1.WatchOrderRequest{Id: tt.id}, stream)
+			if code := errors.GetCode(err); code != tt.wantCode {
+				t.Fatalf("WatchOrder() error = %v, want code %q", err, tt.wantCode)
+			}
+			if len(stream.statuses) != len(tt.wantStatuses) {
+				t.Fatalf("WatchOrder() sent %v, want %v", stream.statuses, tt.wantStatuses)
+			}
+			for i := range tt.wantStatuses {
+				if stream.statuses[i] != tt.wantStatuses[i] {
+					t.Errorf("WatchOrder() sent %v, want %v", stream.statuses, tt.wantStatuses)
+					break
+				}
+			}
+		})
+	}
+
+	t.Run("client gone", func(t *testing.T) {
+		cancelled, cancel := context.WithCancel(context.Background())
+		cancel()
+		repo := newMockRepository()
+		repo.orders["o1"] = &repository.Order{ID: "o1", Status: "pending"}
+		svc := New(repo, log.NewDefault(), WithWatch(watch.NewHub()))
+
+		stream := &mockWatchStream{ctx: cancelled}
+		if err := svc.WatchOrder(&orderv1.WatchOrderRequest{Id: "o1"}, stream); err != context.Canceled {
+			t.Errorf("WatchOrder() error = %v, want %v", err, context.Canceled)
+		}
+	})
+
+	t.Run("not enabled", func(t *testing.T) {
+		svc := New(newMockRepository(), log.NewDefault())
+		err := svc.WatchOrder(&orderv1.WatchOrderRequest{Id: "o1"}, &mockWatchStream{ctx: context.Background()})
+		if errors.GetCode(err) != errors.CodeUnavailable {
+			t.Errorf("WatchOrder() error = %v, want unavailable", err)
+		}
+	})
+}
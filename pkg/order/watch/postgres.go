@@ -0,0 +1,81 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package watch
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/internal/log"
+	"github.com/lib/pq"
+)
+
+// Channel is the Postgres notification channel that order status changes
+// are announced on by the orders_notify_status trigger
+const Channel = "order_status"
+
+// pingInterval is how often an idle listener checks its connection
+const pingInterval = 90 * time.Second
+
+// change is the payload of a notification on Channel
+type change struct {
+	TenantID string `json:"tenant_id"`
+	ID       string `json:"id"`
+}
+
+// Listen feeds hub with the status changes announced on Channel until ctx
+// is cancelled. Changes made while the connection is down are not
+// announced, so every watcher is notified once it is re-established.
+func Listen(ctx context.Context, dsn string, hub *Hub, logger *log.Logger) error {
+	listener := pq.NewListener(dsn, time.Second, time.Minute, func(event pq.ListenerEventType, err error) {
+		if err != nil {
+			logger.Warn("Order status listener connection failed", log.Error(err))
+		}
+	})
+	defer listener.Close()
+
+	if err := listener.Listen(Channel); err != nil {
+		return errors.Wrap(err, "failed to listen for order status changes")
+	}
+	logger.Info("Listening for order status changes", log.String("channel", Channel))
+
+	ping := time.NewTicker(pingInterval)
+	defer ping.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case n := <-listener.Notify:
+			// A nil notification follows a reconnection
+			if n == nil {
+				hub.NotifyAll()
+				continue
+			}
+			var c change
+			if err := json.Unmarshal([]byte(n.Extra), &c); err != nil {
+				logger.Warn("Ignoring malformed order status notification", log.Error(err))
+				continue
+			}
+			hub.Notify(c.TenantID, c.ID)
+		case <-ping.C:
+			go listener.Ping()
+		}
+	}
+}
@@ -0,0 +1,118 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package watch tells the clients watching an order when its status changes
+package watch
+
+import "sync"
+
+type key struct {
+	tenantID string
+	orderID  string
+}
+
+// Hub fans status change notifications out to the watchers of each order.
+// Notifications only say that an order changed; watchers read its new
+// state themselves, so a watcher that falls behind only misses
+// intermediate states, never the latest one.
+type Hub struct {
+	mu     sync.Mutex
+	subs   map[key]map[chan struct{}]struct{}
+	closed bool
+}
+
+// NewHub creates a hub with no watchers
+func NewHub() *Hub {
+	return &Hub{subs: make(map[key]map[chan struct{}]struct{})}
+}
+
+// Subscribe returns a channel that receives a value after status changes of
+// an order, and a function that stops the subscription. Changes the
+// watcher has not received yet are coalesced. The channel is closed when
+// the hub closes.
+func (h *Hub) Subscribe(tenantID, orderID string) (<-chan struct{}, func()) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	ch := make(chan struct{}, 1)
+	if h.closed {
+		close(ch)
+		return ch, func() {}
+	}
+
+	k := key{tenantID: tenantID, orderID: orderID}
+	if h.subs[k] == nil {
+		h.subs[k] = make(map[chan struct{}]struct{})
+	}
+	h.subs[k][ch] = struct{}{}
+
+	return ch, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		if _, ok := h.subs[k][ch]; !ok {
+			return
+		}
+		delete(h.subs[k], ch)
+		if len(h.subs[k]) == 0 {
+			delete(h.subs, k)
+		}
+	}
+}
+
+// Notify tells the watchers of an order that its status changed
+func (h *Hub) Notify(tenantID, orderID string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for ch := range h.subs[key{tenantID: tenantID, orderID: orderID}] {
+		signal(ch)
+	}
+}
+
+// NotifyAll tells every watcher to read its order again, for when changes
+// may have been missed
+func (h *Hub) NotifyAll() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, subs := range h.subs {
+		for ch := range subs {
+			signal(ch)
+		}
+	}
+}
+
+// Close ends every subscription. Subscriptions made afterwards end at once.
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return
+	}
+	h.closed = true
+	for k, subs := range h.subs {
+		for ch := range subs {
+			close(ch)
+		}
+		delete(h.subs, k)
+	}
+}
+
+// signal wakes a watcher unless a wake-up is already pending
+func signal(ch chan struct{}) {
+	select {
+	case ch <- struct{}{}:
+	default:
+	}
+}
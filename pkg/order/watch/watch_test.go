@@ -0,0 +1,80 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package watch
+
+import "testing"
+
+// pending reports whether ch has a notification waiting
+func pending(ch <-chan struct{}) bool {
+	select {
+	case _, ok := <-ch:
+		return ok
+	default:
+		return false
+	}
+}
+
+func TestHubNotify(t *testing.T) {
+	hub := NewHub()
+	order1, stop1 := hub.Subscribe("acme", "o1")
+	defer stop1()
+	order2, stop2 := hub.Subscribe("acme", "o2")
+	defer stop2()
+	otherTenant, stop3 := hub.Subscribe("globex", "o1")
+	defer stop3()
+
+	hub.Notify("acme", "o1")
+	hub.Notify("acme", "o1")
+
+	if !pending(order1) {
+		t.Error("watcher of the changed order was not notified")
+	}
+	if pending(order1) {
+		t.Error("unread notifications were not coalesced")
+	}
+	if pending(order2) || pending(otherTenant) {
+		t.Error("watchers of other orders were notified")
+	}
+
+	hub.NotifyAll()
+	if !pending(order1) || !pending(order2) || !pending(otherTenant) {
+		t.Error("NotifyAll() did not notify every watcher")
+	}
+}
+
+func TestHubStopAndClose(t *testing.T) {
+	hub := NewHub()
+	stopped, stop := hub.Subscribe("acme", "o1")
+	stop()
+	stop()
+	hub.Notify("acme", "o1")
+	if pending(stopped) {
+		t.Error("stopped watcher was notified")
+	}
+
+	open, _ := hub.Subscribe("acme", "o1")
+	hub.Close()
+	hub.Close()
+	if _, ok := <-open; ok {
+		t.Error("Close() did not close the subscription")
+	}
+
+	late, _ := hub.Subscribe("acme", "o2")
+	if _, ok := <-late; ok {
+		t.Error("subscription after Close() is open")
+	}
+}
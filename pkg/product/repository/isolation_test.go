@@ -0,0 +1,118 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build integration
+
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/kevindiu/monorepo-go-example/internal/config"
+	"github.com/kevindiu/monorepo-go-example/internal/db"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/internal/tenant"
+)
+
+// These tests run against the database configured through the usual
+// DATABASE_* environment variables, migrated to at least version 018:
+//
+//	make test-integration
+
+func setupTestDB(t *testing.T) *db.DB {
+	t.Helper()
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("failed to load configuration: %v", err)
+	}
+	database, err := db.Connect(cfg.Database)
+	if err != nil {
+		t.Fatalf("failed to connect to database: %v", err)
+	}
+	t.Cleanup(func() { database.Close() })
+	return database
+}
+
+func TestProductRepository_TenantIsolation(t *testing.T) {
+	database := setupTestDB(t)
+	repo := New(database)
+
+	tenantA := tenant.WithID(context.Background(), "tenant-a-"+uuid.New().String()[:8])
+	tenantB := tenant.WithID(context.Background(), "tenant-b-"+uuid.New().String()[:8])
+
+	// The same SKU may exist once per tenant
+	sku := "SKU-" + uuid.New().String()[:8]
+	productA := &Product{SKU: sku, Name: "A", PriceMinor: 1000, Currency: "USD", Active: true}
+	if err := repo.Create(tenantA, productA); err != nil {
+		t.Fatalf("Create(tenant A) error = %v", err)
+	}
+	productB := &Product{SKU: sku, Name: "B", PriceMinor: 2000, Currency: "USD", Active: true}
+	if err := repo.Create(tenantB, productB); err != nil {
+		t.Fatalf("Create(tenant B) with tenant A's sku error = %v", err)
+	}
+	t.Cleanup(func() {
+		database.Exec(`DELETE FROM products WHERE id IN ($1, $2)`, productA.ID, productB.ID)
+	})
+
+	t.Run("duplicate sku", func(t *testing.T) {
+		dup := &Product{SKU: sku, Name: "A2", PriceMinor: 1000, Currency: "USD"}
+		if err := repo.Create(tenantA, dup); errors.GetCode(err) != errors.CodeConflict {
+			t.Errorf("Create() with duplicate sku error = %v, want conflict", err)
+		}
+	})
+
+	t.Run("get by ID", func(t *testing.T) {
+		if _, err := repo.GetByID(tenantB, productA.ID); errors.GetCode(err) != errors.CodeNotFound {
+			t.Errorf("GetByID() across tenants error = %v, want not found", err)
+		}
+	})
+
+	t.Run("get many", func(t *testing.T) {
+		got, err := repo.GetMany(tenantB, []string{productA.ID, productB.ID, uuid.New().String()})
+		if err != nil {
+			t.Fatalf("GetMany() error = %v", err)
+		}
+		if len(got) != 1 || got[0].ID != productB.ID {
+			t.Errorf("GetMany() = %v, want only tenant B's product", got)
+		}
+	})
+
+	t.Run("update", func(t *testing.T) {
+		other := &Product{ID: productA.ID, Name: "taken", PriceMinor: 1, Currency: "USD"}
+		if err := repo.Update(tenantB, other); errors.GetCode(err) != errors.CodeNotFound {
+			t.Errorf("Update() across tenants error = %v, want not found", err)
+		}
+
+		update := &Product{ID: productA.ID, Name: "A", PriceMinor: 1500, Currency: "USD", Active: false}
+		if err := repo.Update(tenantA, update); err != nil {
+			t.Fatalf("Update() error = %v", err)
+		}
+		if update.SKU != sku || update.PriceMinor != 1500 || update.Active {
+			t.Errorf("Update() = %+v, want sku kept and new price and availability", update)
+		}
+
+		active, err := repo.List(tenantA, true, 10, 0)
+		if err != nil {
+			t.Fatalf("List() error = %v", err)
+		}
+		if len(active) != 0 {
+			t.Errorf("List(active only) = %d products, want 0", len(active))
+		}
+	})
+}
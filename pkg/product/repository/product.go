@@ -0,0 +1,209 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kevindiu/monorepo-go-example/internal/db"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/internal/tenant"
+	"github.com/lib/pq"
+)
+
+// Product represents a product in the catalog
+type Product struct {
+	ID          string
+	TenantID    string
+	SKU         string
+	Name        string
+	Description string
+	// PriceMinor is the unit price in minor units of Currency
+	PriceMinor int64
+	Currency   string
+	Active     bool
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// Repository defines the product repository interface. Every operation is
+// scoped to the tenant on the context.
+type Repository interface {
+	Create(ctx context.Context, p *Product) error
+	GetByID(ctx context.Context, id string) (*Product, error)
+	GetMany(ctx context.Context, ids []string) ([]*Product, error)
+	List(ctx context.Context, activeOnly bool, limit, offset int) ([]*Product, error)
+	Update(ctx context.Context, p *Product) error
+}
+
+type repository struct {
+	db *db.DB
+}
+
+// New creates a new product repository
+func New(database *db.DB) Repository {
+	return &repository{db: database}
+}
+
+const productColumns = `id, tenant_id, sku, name, description, price_minor, currency, active, created_at, updated_at`
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanProduct(s scanner) (*Product, error) {
+	var p Product
+	err := s.Scan(
+		&p.ID,
+		&p.TenantID,
+		&p.SKU,
+		&p.Name,
+		&p.Description,
+		&p.PriceMinor,
+		&p.Currency,
+		&p.Active,
+		&p.CreatedAt,
+		&p.UpdatedAt,
+	)
+	return &p, err
+}
+
+// Create creates a new product. SKUs are unique per tenant.
+func (r *repository) Create(ctx context.Context, p *Product) error {
+	query := `
+		INSERT INTO products (` + productColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`
+	now := time.Now()
+	p.ID = uuid.New().String()
+	p.TenantID = tenant.ID(ctx)
+	p.CreatedAt = now
+	p.UpdatedAt = now
+
+	_, err := r.db.Executor(ctx).ExecContext(ctx, query,
+		p.ID,
+		p.TenantID,
+		p.SKU,
+		p.Name,
+		p.Description,
+		p.PriceMinor,
+		p.Currency,
+		p.Active,
+		p.CreatedAt,
+		p.UpdatedAt,
+	)
+	if db.IsUniqueViolation(err) {
+		return errors.WithCode(errors.New("product with this sku already exists"), errors.CodeConflict)
+	}
+	if err != nil {
+		return errors.Wrap(err, "failed to create product")
+	}
+	return nil
+}
+
+// GetByID retrieves a product by ID
+func (r *repository) GetByID(ctx context.Context, id string) (*Product, error) {
+	query := `SELECT ` + productColumns + ` FROM products WHERE tenant_id = $1 AND id = $2`
+
+	p, err := scanProduct(r.db.Executor(ctx).QueryRowContext(ctx, query, tenant.ID(ctx), id))
+	if err == sql.ErrNoRows {
+		return nil, errors.WithCode(errors.New("product not found"), errors.CodeNotFound)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get product")
+	}
+	return p, nil
+}
+
+// GetMany retrieves the products with the given IDs. IDs that are not found
+// are left out, so the result may be shorter than ids.
+func (r *repository) GetMany(ctx context.Context, ids []string) ([]*Product, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	query := `SELECT ` + productColumns + ` FROM products WHERE tenant_id = $1 AND id = ANY($2::uuid[])`
+	return r.list(ctx, query, tenant.ID(ctx), pq.Array(ids))
+}
+
+// List retrieves products with pagination, newest first
+func (r *repository) List(ctx context.Context, activeOnly bool, limit, offset int) ([]*Product, error) {
+	query := `
+		SELECT ` + productColumns + `
+		FROM products
+		WHERE tenant_id = $1 AND (NOT $2 OR active)
+		ORDER BY created_at DESC, id
+		LIMIT $3 OFFSET $4
+	`
+	return r.list(ctx, query, tenant.ID(ctx), activeOnly, limit, offset)
+}
+
+func (r *repository) list(ctx context.Context, query string, args ...interface{}) ([]*Product, error) {
+	rows, err := r.db.Executor(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list products")
+	}
+	defer rows.Close()
+
+	var products []*Product
+	for rows.Next() {
+		p, err := scanProduct(rows)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to scan product")
+		}
+		products = append(products, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "error iterating products")
+	}
+
+	return products, nil
+}
+
+// Update replaces the name, description, price and availability of a
+// product
+func (r *repository) Update(ctx context.Context, p *Product) error {
+	query := `
+		UPDATE products
+		SET name = $1, description = $2, price_minor = $3, currency = $4, active = $5, updated_at = $6
+		WHERE tenant_id = $7 AND id = $8
+		RETURNING ` + productColumns
+
+	updated, err := scanProduct(r.db.Executor(ctx).QueryRowContext(ctx, query,
+		p.Name,
+		p.Description,
+		p.PriceMinor,
+		p.Currency,
+		p.Active,
+		time.Now(),
+		tenant.ID(ctx),
+		p.ID,
+	))
+	if err == sql.ErrNoRows {
+		return errors.WithCode(errors.New("product not found"), errors.CodeNotFound)
+	}
+	if err != nil {
+		return errors.Wrap(err, "failed to update product")
+	}
+
+	*p = *updated
+	return nil
+}
@@ -0,0 +1,248 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package service
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	productv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/product/v1"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/internal/log"
+	"github.com/kevindiu/monorepo-go-example/internal/money"
+	"github.com/kevindiu/monorepo-go-example/pkg/product/repository"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// maxBatchSize is the most products BatchGetProducts returns at once
+const maxBatchSize = 100
+
+// Service defines the product service interface
+type Service interface {
+	productv1.ProductServiceServer
+}
+
+type service struct {
+	productv1.UnimplementedProductServiceServer
+	repo     repository.Repository
+	currency string
+	logger   *log.Logger
+}
+
+// New creates a new product service. Products created without a currency
+// are priced in base.
+func New(repo repository.Repository, base string, logger *log.Logger) Service {
+	return &service{
+		repo:     repo,
+		currency: strings.ToUpper(base),
+		logger:   logger,
+	}
+}
+
+// loggerFor returns the logger of the call on ctx, or the service's logger
+// when there is none
+func (s *service) loggerFor(ctx context.Context) *log.Logger {
+	return log.FromContextOr(ctx, s.logger)
+}
+
+// CreateProduct adds a product to the catalog
+func (s *service) CreateProduct(ctx context.Context, req *productv1.CreateProductRequest) (*productv1.CreateProductResponse, error) {
+	s.loggerFor(ctx).Info("Creating product", log.String("sku", req.GetSku()))
+
+	if strings.TrimSpace(req.GetSku()) == "" {
+		return nil, errors.WithCode(errors.New("sku is required"), errors.CodeInvalidInput)
+	}
+
+	product := &repository.Product{
+		SKU:         strings.TrimSpace(req.GetSku()),
+		Name:        req.GetName(),
+		Description: req.GetDescription(),
+		PriceMinor:  req.GetPriceMinor(),
+		Currency:    req.GetCurrency(),
+		Active:      true,
+	}
+	if err := s.validate(product); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Create(ctx, product); err != nil {
+		s.loggerFor(ctx).Error("Failed to create product", log.Error(err))
+		return nil, err
+	}
+
+	s.loggerFor(ctx).Info("Product created successfully", log.String("product_id", product.ID))
+
+	return &productv1.CreateProductResponse{Product: productToProto(product)}, nil
+}
+
+// GetProduct retrieves a product by ID
+func (s *service) GetProduct(ctx context.Context, req *productv1.GetProductRequest) (*productv1.GetProductResponse, error) {
+	if _, err := uuid.Parse(req.GetId()); err != nil {
+		return nil, errors.WithCode(errors.New("product not found"), errors.CodeNotFound)
+	}
+
+	product, err := s.repo.GetByID(ctx, req.GetId())
+	if err != nil {
+		s.loggerFor(ctx).Error("Failed to get product", log.Error(err))
+		return nil, err
+	}
+
+	return &productv1.GetProductResponse{Product: productToProto(product)}, nil
+}
+
+// BatchGetProducts retrieves several products by ID. IDs that do not exist,
+// including ones that are not product IDs at all, are left out of the
+// response.
+func (s *service) BatchGetProducts(ctx context.Context, req *productv1.BatchGetProductsRequest) (*productv1.BatchGetProductsResponse, error) {
+	if len(req.GetIds()) > maxBatchSize {
+		return nil, errors.WithCode(errors.Newf("at most %d ids may be requested at once", maxBatchSize), errors.CodeInvalidInput)
+	}
+
+	seen := make(map[string]bool, len(req.GetIds()))
+	ids := make([]string, 0, len(req.GetIds()))
+	for _, id := range req.GetIds() {
+		if _, err := uuid.Parse(id); err != nil || seen[id] {
+			continue
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+
+	products, err := s.repo.GetMany(ctx, ids)
+	if err != nil {
+		s.loggerFor(ctx).Error("Failed to get products", log.Error(err))
+		return nil, err
+	}
+
+	resp := &productv1.BatchGetProductsResponse{
+		Products: make([]*productv1.Product, len(products)),
+	}
+	for i, product := range products {
+		resp.Products[i] = productToProto(product)
+	}
+	return resp, nil
+}
+
+// ListProducts lists products with pagination
+func (s *service) ListProducts(ctx context.Context, req *productv1.ListProductsRequest) (*productv1.ListProductsResponse, error) {
+	pageSize, offset := pagination(req.GetPageSize(), req.GetPageToken())
+
+	products, err := s.repo.List(ctx, req.GetActiveOnly(), pageSize, offset)
+	if err != nil {
+		s.loggerFor(ctx).Error("Failed to list products", log.Error(err))
+		return nil, err
+	}
+
+	pbProducts := make([]*productv1.Product, len(products))
+	for i, product := range products {
+		pbProducts[i] = productToProto(product)
+	}
+
+	resp := &productv1.ListProductsResponse{Products: pbProducts}
+	if len(products) == pageSize {
+		resp.NextPageToken = strconv.Itoa(offset + pageSize)
+	}
+	return resp, nil
+}
+
+// UpdateProduct replaces the name, description, price and availability of a
+// product. Orders already placed keep the price they were placed at.
+func (s *service) UpdateProduct(ctx context.Context, req *productv1.UpdateProductRequest) (*productv1.UpdateProductResponse, error) {
+	s.loggerFor(ctx).Info("Updating product", log.String("product_id", req.GetId()))
+
+	if _, err := uuid.Parse(req.GetId()); err != nil {
+		return nil, errors.WithCode(errors.New("product not found"), errors.CodeNotFound)
+	}
+
+	product := &repository.Product{
+		ID:          req.GetId(),
+		Name:        req.GetName(),
+		Description: req.GetDescription(),
+		PriceMinor:  req.GetPriceMinor(),
+		Currency:    req.GetCurrency(),
+		Active:      req.GetActive(),
+	}
+	if err := s.validate(product); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(ctx, product); err != nil {
+		s.loggerFor(ctx).Error("Failed to update product", log.Error(err))
+		return nil, err
+	}
+
+	return &productv1.UpdateProductResponse{Product: productToProto(product)}, nil
+}
+
+// validate checks the fields of a product that may be set and normalizes
+// its currency, defaulting it to the base currency
+func (s *service) validate(product *repository.Product) error {
+	if strings.TrimSpace(product.Name) == "" {
+		return errors.WithCode(errors.New("name is required"), errors.CodeInvalidInput)
+	}
+	if product.PriceMinor <= 0 {
+		return errors.WithCode(errors.New("price_minor must be positive"), errors.CodeInvalidInput)
+	}
+
+	code := product.Currency
+	if code == "" {
+		code = s.currency
+	}
+	code, err := money.Normalize(code)
+	if err != nil {
+		return err
+	}
+	product.Currency = code
+	return nil
+}
+
+// pagination normalizes the page size and decodes the offset page token
+func pagination(size int32, token string) (int, int) {
+	pageSize := int(size)
+	if pageSize <= 0 {
+		pageSize = 10
+	}
+	if pageSize > 100 {
+		pageSize = 100
+	}
+
+	offset := 0
+	if token != "" {
+		parsedOffset, err := strconv.Atoi(token)
+		if err == nil && parsedOffset > 0 {
+			offset = parsedOffset
+		}
+	}
+
+	return pageSize, offset
+}
+
+func productToProto(product *repository.Product) *productv1.Product {
+	return &productv1.Product{
+		Id:          product.ID,
+		Sku:         product.SKU,
+		Name:        product.Name,
+		Description: product.Description,
+		PriceMinor:  product.PriceMinor,
+		Currency:    product.Currency,
+		Active:      product.Active,
+		CreatedAt:   timestamppb.New(product.CreatedAt),
+		UpdatedAt:   timestamppb.New(product.UpdatedAt),
+	}
+}
@@ -0,0 +1,207 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	productv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/product/v1"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/internal/log"
+	"github.com/kevindiu/monorepo-go-example/pkg/product/repository"
+)
+
+// mockRepository implements repository.Repository for testing
+type mockRepository struct {
+	products map[string]*repository.Product
+}
+
+func newMockRepository() *mockRepository {
+	return &mockRepository{products: make(map[string]*repository.Product)}
+}
+
+func (m *mockRepository) Create(ctx context.Context, p *repository.Product) error {
+	for _, existing := range m.products {
+		if existing.SKU == p.SKU {
+			return errors.WithCode(errors.New("product with this sku already exists"), errors.CodeConflict)
+		}
+	}
+	p.ID = uuid.New().String()
+	m.products[p.ID] = p
+	return nil
+}
+
+func (m *mockRepository) GetByID(ctx context.Context, id string) (*repository.Product, error) {
+	p, ok := m.products[id]
+	if !ok {
+		return nil, errors.WithCode(errors.New("product not found"), errors.CodeNotFound)
+	}
+	return p, nil
+}
+
+func (m *mockRepository) GetMany(ctx context.Context, ids []string) ([]*repository.Product, error) {
+	var products []*repository.Product
+	for _, id := range ids {
+		if p, ok := m.products[id]; ok {
+			products = append(products, p)
+		}
+	}
+	return products, nil
+}
+
+func (m *mockRepository) List(ctx context.Context, activeOnly bool, limit, offset int) ([]*repository.Product, error) {
+	var products []*repository.Product
+	for _, p := range m.products {
+		if !activeOnly || p.Active {
+			products = append(products, p)
+		}
+	}
+	if offset >= len(products) {
+		return nil, nil
+	}
+	products = products[offset:]
+	if len(products) > limit {
+		products = products[:limit]
+	}
+	return products, nil
+}
+
+func (m *mockRepository) Update(ctx context.Context, p *repository.Product) error {
+	existing, ok := m.products[p.ID]
+	if !ok {
+		return errors.WithCode(errors.New("product not found"), errors.CodeNotFound)
+	}
+	p.SKU = existing.SKU
+	m.products[p.ID] = p
+	return nil
+}
+
+func TestCreateProduct(t *testing.T) {
+	tests := []struct {
+		name         string
+		req          *productv1.CreateProductRequest
+		wantCode     string
+		wantCurrency string
+	}{
+		{
+			name:         "base currency by default",
+			req:          &productv1.CreateProductRequest{Sku: "SKU-1", Name: "Widget", PriceMinor: 1999},
+			wantCurrency: "USD",
+		},
+		{
+			name:         "explicit currency",
+			req:          &productv1.CreateProductRequest{Sku: "SKU-2", Name: "Widget", PriceMinor: 1999, Currency: "eur"},
+			wantCurrency: "EUR",
+		},
+		{
+			name:     "missing sku",
+			req:      &productv1.CreateProductRequest{Name: "Widget", PriceMinor: 1999},
+			wantCode: errors.CodeInvalidInput,
+		},
+		{
+			name:     "missing name",
+			req:      &productv1.CreateProductRequest{Sku: "SKU-3", PriceMinor: 1999},
+			wantCode: errors.CodeInvalidInput,
+		},
+		{
+			name:     "free product",
+			req:      &productv1.CreateProductRequest{Sku: "SKU-4", Name: "Widget"},
+			wantCode: errors.CodeInvalidInput,
+		},
+		{
+			name:     "invalid currency",
+			req:      &productv1.CreateProductRequest{Sku: "SKU-5", Name: "Widget", PriceMinor: 1999, Currency: "dollars"},
+			wantCode: errors.CodeInvalidInput,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := New(newMockRepository(), "usd", log.NewDefault())
+
+			resp, err := svc.CreateProduct(context.Background(), tt.req)
+			if tt.wantCode != "" {
+				if errors.GetCode(err) != tt.wantCode {
+					t.Fatalf("CreateProduct() error = %v, want code %s", err, tt.wantCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CreateProduct() error = %v", err)
+			}
+			if got := resp.GetProduct(); got.GetCurrency() != tt.wantCurrency || !got.GetActive() {
+				t.Errorf("CreateProduct() = %v, want active product in %s", got, tt.wantCurrency)
+			}
+		})
+	}
+}
+
+func TestBatchGetProducts(t *testing.T) {
+	repo := newMockRepository()
+	svc := New(repo, "USD", log.NewDefault())
+
+	created, err := svc.CreateProduct(context.Background(), &productv1.CreateProductRequest{Sku: "SKU-1", Name: "Widget", PriceMinor: 500})
+	if err != nil {
+		t.Fatalf("CreateProduct() error = %v", err)
+	}
+	id := created.GetProduct().GetId()
+
+	resp, err := svc.BatchGetProducts(context.Background(), &productv1.BatchGetProductsRequest{
+		Ids: []string{id, id, uuid.New().String(), "not-a-product"},
+	})
+	if err != nil {
+		t.Fatalf("BatchGetProducts() error = %v", err)
+	}
+	if len(resp.GetProducts()) != 1 || resp.GetProducts()[0].GetPriceMinor() != 500 {
+		t.Errorf("BatchGetProducts() = %v, want the one existing product", resp.GetProducts())
+	}
+
+	tooMany := make([]string, maxBatchSize+1)
+	if _, err := svc.BatchGetProducts(context.Background(), &productv1.BatchGetProductsRequest{Ids: tooMany}); errors.GetCode(err) != errors.CodeInvalidInput {
+		t.Errorf("BatchGetProducts() with %d ids error = %v, want invalid input", len(tooMany), err)
+	}
+}
+
+func TestUpdateProduct(t *testing.T) {
+	repo := newMockRepository()
+	svc := New(repo, "USD", log.NewDefault())
+
+	created, err := svc.CreateProduct(context.Background(), &productv1.CreateProductRequest{Sku: "SKU-1", Name: "Widget", PriceMinor: 500})
+	if err != nil {
+		t.Fatalf("CreateProduct() error = %v", err)
+	}
+	id := created.GetProduct().GetId()
+
+	resp, err := svc.UpdateProduct(context.Background(), &productv1.UpdateProductRequest{
+		Id: id, Name: "Widget", PriceMinor: 750, Currency: "USD", Active: false,
+	})
+	if err != nil {
+		t.Fatalf("UpdateProduct() error = %v", err)
+	}
+	if got := resp.GetProduct(); got.GetSku() != "SKU-1" || got.GetPriceMinor() != 750 || got.GetActive() {
+		t.Errorf("UpdateProduct() = %v, want inactive SKU-1 at 750", got)
+	}
+
+	for _, id := range []string{"not-a-product", uuid.New().String()} {
+		_, err := svc.UpdateProduct(context.Background(), &productv1.UpdateProductRequest{Id: id, Name: "Widget", PriceMinor: 1})
+		if errors.GetCode(err) != errors.CodeNotFound {
+			t.Errorf("UpdateProduct(%q) error = %v, want not found", id, err)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package promotion validates coupon codes and computes their discounts
+package promotion
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/internal/money"
+	"github.com/kevindiu/monorepo-go-example/pkg/promotion/repository"
+)
+
+// Applied is a promotion applied to an order
+type Applied struct {
+	PromotionID string
+	Code        string
+	Description string
+	// Amount is in the minor units of the order currency
+	Amount int64
+}
+
+// Engine validates coupon codes against orders
+type Engine struct {
+	repo repository.Repository
+	now  func() time.Time
+}
+
+// New creates a promotion engine
+func New(repo repository.Repository) *Engine {
+	return &Engine{repo: repo, now: time.Now}
+}
+
+// NormalizeCode returns a coupon code in its stored form
+func NormalizeCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// Apply validates the coupon codes a user entered for an order with the
+// given subtotal, in minor units of currency, and returns the discount of
+// each. Discounts are taken in
+// order from what the previous ones left, so the total never goes negative.
+// Usage limits are checked here for a friendly error and enforced again when
+// the order is stored.
+func (e *Engine) Apply(ctx context.Context, userID, currency string, subtotal int64, codes []string) ([]*Applied, error) {
+	seen := make(map[string]bool, len(codes))
+	remaining := subtotal
+
+	var applied []*Applied
+	for _, raw := range codes {
+		code := NormalizeCode(raw)
+		if code == "" {
+			return nil, errors.WithCode(errors.New("coupon code is empty"), errors.CodeInvalidInput)
+		}
+		if seen[code] {
+			return nil, errors.WithCode(errors.Newf("coupon %s was given more than once", code), errors.CodeInvalidInput)
+		}
+		seen[code] = true
+
+		p, err := e.repo.GetByCode(ctx, code)
+		if err != nil {
+			if errors.GetCode(err) == errors.CodeNotFound {
+				return nil, errors.WithCode(errors.Newf("coupon %s is not valid", code), errors.CodeInvalidInput)
+			}
+			return nil, err
+		}
+		if err := e.check(ctx, p, userID, currency, subtotal); err != nil {
+			return nil, err
+		}
+
+		amount := Discount(p, remaining, currency)
+		remaining -= amount
+		applied = append(applied, &Applied{
+			PromotionID: p.ID,
+			Code:        p.Code,
+			Description: p.Description,
+			Amount:      amount,
+		})
+	}
+
+	return applied, nil
+}
+
+// check verifies a promotion's constraints for an order
+func (e *Engine) check(ctx context.Context, p *repository.Promotion, userID, currency string, subtotal int64) error {
+	if !p.Active {
+		return errors.WithCode(errors.Newf("coupon %s is not valid", p.Code), errors.CodeInvalidInput)
+	}
+	if p.ExpiresAt != nil && !e.now().Before(*p.ExpiresAt) {
+		return errors.WithCode(errors.Newf("coupon %s has expired", p.Code), errors.CodeInvalidInput)
+	}
+	if minTotal := fromMajor(p.MinTotal, currency); subtotal < minTotal {
+		return errors.WithCode(errors.Newf("coupon %s requires an order total of at least %s %s",
+			p.Code, money.Format(minTotal, currency), currency), errors.CodeInvalidInput)
+	}
+
+	if p.MaxUsesPerUser > 0 {
+		used, err := e.repo.CountRedemptions(ctx, p.ID, userID)
+		if err != nil {
+			return err
+		}
+		if used >= p.MaxUsesPerUser {
+			return errors.WithCode(errors.Newf("coupon %s has already been used", p.Code), errors.CodeInvalidInput)
+		}
+	}
+
+	return nil
+}
+
+// Discount returns the discount a promotion grants on an amount in minor
+// units of currency, rounded to the minor unit and never more than the
+// amount itself
+func Discount(p *repository.Promotion, amount int64, currency string) int64 {
+	var discount int64
+	switch p.Type {
+	case repository.TypePercent:
+		discount = money.Percent(amount, p.Value)
+	case repository.TypeFixed:
+		discount = fromMajor(p.Value, currency)
+	}
+	return max(0, min(discount, amount))
+}
+
+// fromMajor converts a promotion amount to minor units. Promotions store
+// amounts as DECIMAL(10,2), which always converts.
+func fromMajor(amount float64, currency string) int64 {
+	minor, _ := money.FromMajor(amount, currency)
+	return minor
+}
@@ -0,0 +1,156 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package promotion
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/pkg/promotion/repository"
+)
+
+// mockRepository implements repository.Repository for testing
+type mockRepository struct {
+	promotions  map[string]*repository.Promotion
+	redemptions map[string]int
+}
+
+func newMockRepository(promotions ...*repository.Promotion) *mockRepository {
+	m := &mockRepository{
+		promotions:  make(map[string]*repository.Promotion),
+		redemptions: make(map[string]int),
+	}
+	for _, p := range promotions {
+		p.ID = "promo-" + p.Code
+		m.promotions[p.Code] = p
+	}
+	return m
+}
+
+func (m *mockRepository) Create(ctx context.Context, p *repository.Promotion) error {
+	m.promotions[p.Code] = p
+	return nil
+}
+
+func (m *mockRepository) GetByCode(ctx context.Context, code string) (*repository.Promotion, error) {
+	p, ok := m.promotions[code]
+	if !ok {
+		return nil, errors.WithCode(errors.New("promotion not found"), errors.CodeNotFound)
+	}
+	return p, nil
+}
+
+func (m *mockRepository) List(ctx context.Context, limit, offset int) ([]*repository.Promotion, error) {
+	return nil, nil
+}
+
+func (m *mockRepository) SetActive(ctx context.Context, code string, active bool) error {
+	return nil
+}
+
+func (m *mockRepository) CountRedemptions(ctx context.Context, promotionID, userID string) (int, error) {
+	return m.redemptions[promotionID+"/"+userID], nil
+}
+
+func TestApply(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+
+	repo := newMockRepository(
+		&repository.Promotion{Code: "TENOFF", Type: repository.TypePercent, Value: 10, Active: true},
+		&repository.Promotion{Code: "FIVE", Type: repository.TypeFixed, Value: 5, Active: true, ExpiresAt: &future},
+		&repository.Promotion{Code: "BIG", Type: repository.TypeFixed, Value: 500, Active: true},
+		&repository.Promotion{Code: "OLD", Type: repository.TypeFixed, Value: 5, Active: true, ExpiresAt: &past},
+		&repository.Promotion{Code: "OFF", Type: repository.TypeFixed, Value: 5},
+		&repository.Promotion{Code: "MIN50", Type: repository.TypeFixed, Value: 5, Active: true, MinTotal: 50},
+		&repository.Promotion{Code: "ONCE", Type: repository.TypeFixed, Value: 5, Active: true, MaxUsesPerUser: 1},
+	)
+	repo.redemptions["promo-ONCE/user-used"] = 1
+
+	engine := New(repo)
+	engine.now = func() time.Time { return now }
+
+	tests := []struct {
+		name    string
+		userID  string
+		total   int64
+		codes   []string
+		want    []int64
+		wantErr bool
+	}{
+		{name: "no codes", userID: "user-1", total: 4000},
+		{name: "percent", userID: "user-1", total: 4000, codes: []string{"tenoff"}, want: []int64{400}},
+		{name: "stacked on the remainder", userID: "user-1", total: 4000, codes: []string{"FIVE", "TENOFF"}, want: []int64{500, 350}},
+		{name: "capped at the total", userID: "user-1", total: 4000, codes: []string{"BIG", "FIVE"}, want: []int64{4000, 0}},
+		{name: "minimum total met", userID: "user-1", total: 5000, codes: []string{"MIN50"}, want: []int64{500}},
+		{name: "minimum total not met", userID: "user-1", total: 4999, codes: []string{"MIN50"}, wantErr: true},
+		{name: "unknown code", userID: "user-1", total: 4000, codes: []string{"NOPE"}, wantErr: true},
+		{name: "expired", userID: "user-1", total: 4000, codes: []string{"OLD"}, wantErr: true},
+		{name: "inactive", userID: "user-1", total: 4000, codes: []string{"OFF"}, wantErr: true},
+		{name: "duplicate", userID: "user-1", total: 4000, codes: []string{"FIVE", " five "}, wantErr: true},
+		{name: "usage left", userID: "user-new", total: 4000, codes: []string{"ONCE"}, want: []int64{500}},
+		{name: "usage exhausted", userID: "user-used", total: 4000, codes: []string{"ONCE"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			applied, err := engine.Apply(context.Background(), tt.userID, "USD", tt.total, tt.codes)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Apply() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if errors.GetCode(err) != errors.CodeInvalidInput {
+					t.Errorf("Apply() error code = %s, want %s", errors.GetCode(err), errors.CodeInvalidInput)
+				}
+				return
+			}
+			if len(applied) != len(tt.want) {
+				t.Fatalf("Apply() returned %d discounts, want %d", len(applied), len(tt.want))
+			}
+			for i, a := range applied {
+				if a.Amount != tt.want[i] {
+					t.Errorf("discount %d (%s) = %v, want %v", i, a.Code, a.Amount, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestDiscount(t *testing.T) {
+	tests := []struct {
+		name   string
+		p      *repository.Promotion
+		amount int64
+		want   int64
+	}{
+		{name: "percent rounds to cents", p: &repository.Promotion{Type: repository.TypePercent, Value: 15}, amount: 999, want: 150},
+		{name: "fixed", p: &repository.Promotion{Type: repository.TypeFixed, Value: 7.5}, amount: 2000, want: 750},
+		{name: "fixed capped", p: &repository.Promotion{Type: repository.TypeFixed, Value: 30}, amount: 2000, want: 2000},
+		{name: "unknown type", p: &repository.Promotion{Type: "bogo", Value: 30}, amount: 2000, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Discount(tt.p, tt.amount, "USD"); got != tt.want {
+				t.Errorf("Discount() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,263 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kevindiu/monorepo-go-example/internal/db"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/internal/tenant"
+)
+
+// Discount types
+const (
+	TypePercent = "percent"
+	TypeFixed   = "fixed"
+)
+
+// Promotion represents a coupon code and the discount it grants
+type Promotion struct {
+	ID          string
+	TenantID    string
+	Code        string
+	Description string
+	Type        string
+	// Value is a percentage for percent promotions and an amount for fixed ones
+	Value    float64
+	MinTotal float64
+	// MaxUsesPerUser limits redemptions per user; zero means unlimited
+	MaxUsesPerUser int
+	ExpiresAt      *time.Time
+	Active         bool
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// Repository defines the promotion repository interface. Every operation is
+// scoped to the tenant on the context.
+type Repository interface {
+	Create(ctx context.Context, p *Promotion) error
+	GetByCode(ctx context.Context, code string) (*Promotion, error)
+	List(ctx context.Context, limit, offset int) ([]*Promotion, error)
+	SetActive(ctx context.Context, code string, active bool) error
+	CountRedemptions(ctx context.Context, promotionID, userID string) (int, error)
+}
+
+// querier runs queries on the database or inside a transaction
+type querier interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+type repository struct {
+	db *db.DB
+}
+
+// New creates a new promotion repository
+func New(database *db.DB) Repository {
+	return &repository{db: database}
+}
+
+const promotionColumns = `id, tenant_id, code, description, discount_type, value, min_total,
+	max_uses_per_user, expires_at, active, created_at, updated_at`
+
+// Create creates a new promotion
+func (r *repository) Create(ctx context.Context, p *Promotion) error {
+	query := `
+		INSERT INTO promotions (` + promotionColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+	`
+	now := time.Now()
+	p.ID = uuid.New().String()
+	p.TenantID = tenant.ID(ctx)
+	p.CreatedAt = now
+	p.UpdatedAt = now
+
+	_, err := r.db.Executor(ctx).ExecContext(ctx, query,
+		p.ID,
+		p.TenantID,
+		p.Code,
+		p.Description,
+		p.Type,
+		p.Value,
+		p.MinTotal,
+		p.MaxUsesPerUser,
+		p.ExpiresAt,
+		p.Active,
+		p.CreatedAt,
+		p.UpdatedAt,
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to create promotion")
+	}
+	return nil
+}
+
+// GetByCode retrieves a promotion by its coupon code
+func (r *repository) GetByCode(ctx context.Context, code string) (*Promotion, error) {
+	query := `
+		SELECT ` + promotionColumns + `
+		FROM promotions
+		WHERE tenant_id = $1 AND code = $2
+	`
+
+	p, err := scanPromotion(r.db.Executor(ctx).QueryRowContext(ctx, query, tenant.ID(ctx), code))
+	if err == sql.ErrNoRows {
+		return nil, errors.WithCode(errors.New("promotion not found"), errors.CodeNotFound)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get promotion")
+	}
+	return p, nil
+}
+
+// List retrieves promotions with pagination, newest first
+func (r *repository) List(ctx context.Context, limit, offset int) ([]*Promotion, error) {
+	query := `
+		SELECT ` + promotionColumns + `
+		FROM promotions
+		WHERE tenant_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.db.Executor(ctx).QueryContext(ctx, query, tenant.ID(ctx), limit, offset)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list promotions")
+	}
+	defer rows.Close()
+
+	var promotions []*Promotion
+	for rows.Next() {
+		p, err := scanPromotion(rows)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to scan promotion")
+		}
+		promotions = append(promotions, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "error iterating promotions")
+	}
+
+	return promotions, nil
+}
+
+// SetActive enables or disables a promotion
+func (r *repository) SetActive(ctx context.Context, code string, active bool) error {
+	query := `UPDATE promotions SET active = $1 WHERE tenant_id = $2 AND code = $3`
+
+	result, err := r.db.Executor(ctx).ExecContext(ctx, query, active, tenant.ID(ctx), code)
+	if err != nil {
+		return errors.Wrap(err, "failed to update promotion")
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "failed to get affected rows")
+	}
+
+	if rows == 0 {
+		return errors.WithCode(errors.New("promotion not found"), errors.CodeNotFound)
+	}
+
+	return nil
+}
+
+// CountRedemptions returns how many live orders of a user redeemed a
+// promotion. Cancelled orders give their redemption back.
+func (r *repository) CountRedemptions(ctx context.Context, promotionID, userID string) (int, error) {
+	return countRedemptions(ctx, r.db, promotionID, userID)
+}
+
+// Reserve locks a promotion and verifies the user has a redemption left. Call
+// it inside the transaction that records the discount, so concurrent orders
+// cannot redeem a promotion past its limit.
+func Reserve(ctx context.Context, tx db.Executor, promotionID, userID string) error {
+	query := `
+		SELECT max_uses_per_user
+		FROM promotions
+		WHERE tenant_id = $1 AND id = $2
+		FOR UPDATE
+	`
+
+	var limit int
+	err := tx.QueryRowContext(ctx, query, tenant.ID(ctx), promotionID).Scan(&limit)
+	if err == sql.ErrNoRows {
+		return errors.WithCode(errors.New("promotion not found"), errors.CodeNotFound)
+	}
+	if err != nil {
+		return errors.Wrap(err, "failed to lock promotion")
+	}
+	if limit == 0 {
+		return nil
+	}
+
+	used, err := countRedemptions(ctx, tx, promotionID, userID)
+	if err != nil {
+		return err
+	}
+	if used >= limit {
+		return errors.WithCode(errors.New("promotion usage limit reached"), errors.CodeInvalidInput)
+	}
+	return nil
+}
+
+func countRedemptions(ctx context.Context, q querier, promotionID, userID string) (int, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM order_discounts d
+		JOIN orders o ON o.id = d.order_id
+		WHERE d.tenant_id = $1 AND d.promotion_id = $2 AND o.user_id = $3 AND o.status <> 'cancelled'
+	`
+
+	var count int
+	if err := q.QueryRowContext(ctx, query, tenant.ID(ctx), promotionID, userID).Scan(&count); err != nil {
+		return 0, errors.Wrap(err, "failed to count promotion redemptions")
+	}
+	return count, nil
+}
+
+// scanner is implemented by *sql.Row and *sql.Rows
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanPromotion(s scanner) (*Promotion, error) {
+	var p Promotion
+	err := s.Scan(
+		&p.ID,
+		&p.TenantID,
+		&p.Code,
+		&p.Description,
+		&p.Type,
+		&p.Value,
+		&p.MinTotal,
+		&p.MaxUsesPerUser,
+		&p.ExpiresAt,
+		&p.Active,
+		&p.CreatedAt,
+		&p.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
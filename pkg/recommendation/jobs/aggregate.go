@@ -0,0 +1,50 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/log"
+	"github.com/kevindiu/monorepo-go-example/internal/scheduler"
+	"github.com/kevindiu/monorepo-go-example/pkg/recommendation/repository"
+)
+
+// RecommendationAggregation is the name of the recommendation aggregation job
+const RecommendationAggregation = "recommendation-aggregation"
+
+// MaxPerProduct is the number of recommendations kept for each product
+const MaxPerProduct = 20
+
+// NewRecommendationAggregation returns a job that rebuilds product
+// recommendations from order history
+func NewRecommendationAggregation(repo repository.Repository, schedule string, logger *log.Logger) scheduler.Job {
+	return scheduler.Job{
+		Name:     RecommendationAggregation,
+		Schedule: schedule,
+		Timeout:  30 * time.Minute,
+		Run: func(ctx context.Context) error {
+			stored, err := repo.Rebuild(ctx, MaxPerProduct)
+			if err != nil {
+				return err
+			}
+			logger.Info("Rebuilt product recommendations", log.Int64("count", stored))
+			return nil
+		},
+	}
+}
@@ -0,0 +1,146 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/db"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/internal/tenant"
+)
+
+// Recommendation is a product bought by users who also bought ProductID
+type Recommendation struct {
+	ProductID            string
+	RecommendedProductID string
+	// Score is the number of users who bought both products
+	Score      int
+	ComputedAt time.Time
+}
+
+// Repository defines the recommendation repository interface. List is
+// scoped to the tenant on the context; Rebuild aggregates every tenant.
+type Repository interface {
+	List(ctx context.Context, productID string, limit int) ([]*Recommendation, error)
+	Rebuild(ctx context.Context, perProduct int) (int64, error)
+}
+
+type repository struct {
+	db *db.DB
+}
+
+// New creates a new recommendation repository
+func New(database *db.DB) Repository {
+	return &repository{db: database}
+}
+
+// List retrieves the top recommendations for a product
+func (r *repository) List(ctx context.Context, productID string, limit int) ([]*Recommendation, error) {
+	query := `
+		SELECT product_id, recommended_product_id, score, computed_at
+		FROM product_recommendations
+		WHERE tenant_id = $1 AND product_id = $2
+		ORDER BY score DESC, recommended_product_id ASC
+		LIMIT $3
+	`
+
+	rows, err := r.db.Executor(ctx).QueryContext(ctx, query, tenant.ID(ctx), productID, limit)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list recommendations")
+	}
+	defer rows.Close()
+
+	var recs []*Recommendation
+	for rows.Next() {
+		var rec Recommendation
+		if err := rows.Scan(
+			&rec.ProductID,
+			&rec.RecommendedProductID,
+			&rec.Score,
+			&rec.ComputedAt,
+		); err != nil {
+			return nil, errors.Wrap(err, "failed to scan recommendation")
+		}
+		recs = append(recs, &rec)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "error iterating recommendations")
+	}
+
+	return recs, nil
+}
+
+// Rebuild recomputes every tenant's recommendations from order history,
+// keeping the perProduct best per product, and returns how many were
+// stored. Readers keep seeing the previous set until the rebuild commits.
+func (r *repository) Rebuild(ctx context.Context, perProduct int) (int64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM product_recommendations`); err != nil {
+		return 0, errors.Wrap(err, "failed to clear recommendations")
+	}
+
+	// A pair scores once per user who bought both products in any live
+	// orders, however many times they bought them
+	query := `
+		WITH purchases AS (
+			SELECT DISTINCT o.tenant_id, o.user_id, i.product_id
+			FROM orders o
+			JOIN order_items i ON i.order_id = o.id
+			WHERE o.status <> 'cancelled'
+		),
+		pairs AS (
+			SELECT a.tenant_id, a.product_id, b.product_id AS recommended_product_id,
+				COUNT(*) AS score,
+				ROW_NUMBER() OVER (
+					PARTITION BY a.tenant_id, a.product_id
+					ORDER BY COUNT(*) DESC, b.product_id ASC
+				) AS rank
+			FROM purchases a
+			JOIN purchases b
+				ON b.tenant_id = a.tenant_id AND b.user_id = a.user_id AND b.product_id <> a.product_id
+			GROUP BY a.tenant_id, a.product_id, b.product_id
+		)
+		INSERT INTO product_recommendations (tenant_id, product_id, recommended_product_id, score, computed_at)
+		SELECT tenant_id, product_id, recommended_product_id, score, $1
+		FROM pairs
+		WHERE rank <= $2
+	`
+
+	result, err := tx.ExecContext(ctx, query, time.Now(), perProduct)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to aggregate recommendations")
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to get affected rows")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, errors.Wrap(err, "failed to commit transaction")
+	}
+
+	return rows, nil
+}
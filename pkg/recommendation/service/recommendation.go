@@ -0,0 +1,86 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package service
+
+import (
+	"context"
+
+	recommendationv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/recommendation/v1"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/internal/log"
+	"github.com/kevindiu/monorepo-go-example/pkg/recommendation/jobs"
+	"github.com/kevindiu/monorepo-go-example/pkg/recommendation/repository"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// defaultLimit is the number of recommendations returned when none is requested
+const defaultLimit = 10
+
+// Service defines the recommendation service interface
+type Service interface {
+	recommendationv1.RecommendationServiceServer
+}
+
+type service struct {
+	recommendationv1.UnimplementedRecommendationServiceServer
+	repo   repository.Repository
+	logger *log.Logger
+}
+
+// New creates a new recommendation service
+func New(repo repository.Repository, logger *log.Logger) Service {
+	return &service{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// GetRecommendations lists the products most often bought with a product
+func (s *service) GetRecommendations(ctx context.Context, req *recommendationv1.GetRecommendationsRequest) (*recommendationv1.GetRecommendationsResponse, error) {
+	if req.GetProductId() == "" {
+		return nil, errors.WithCode(errors.New("product_id is required"), errors.CodeInvalidInput)
+	}
+
+	limit := int(req.GetLimit())
+	if limit <= 0 {
+		limit = defaultLimit
+	}
+	if limit > jobs.MaxPerProduct {
+		limit = jobs.MaxPerProduct
+	}
+
+	recs, err := s.repo.List(ctx, req.GetProductId(), limit)
+	if err != nil {
+		s.logger.Error("Failed to list recommendations", log.Error(err))
+		return nil, err
+	}
+
+	resp := &recommendationv1.GetRecommendationsResponse{
+		Recommendations: make([]*recommendationv1.Recommendation, len(recs)),
+	}
+	for i, rec := range recs {
+		resp.Recommendations[i] = &recommendationv1.Recommendation{
+			ProductId: rec.RecommendedProductID,
+			Score:     int32(rec.Score),
+		}
+	}
+	if len(recs) > 0 {
+		resp.ComputedAt = timestamppb.New(recs[0].ComputedAt)
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,97 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	recommendationv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/recommendation/v1"
+	"github.com/kevindiu/monorepo-go-example/internal/log"
+	"github.com/kevindiu/monorepo-go-example/pkg/recommendation/repository"
+)
+
+// mockRepository implements repository.Repository for testing
+type mockRepository struct {
+	recs      []*repository.Recommendation
+	lastLimit int
+}
+
+func (m *mockRepository) List(ctx context.Context, productID string, limit int) ([]*repository.Recommendation, error) {
+	m.lastLimit = limit
+	var recs []*repository.Recommendation
+	for _, rec := range m.recs {
+		if rec.ProductID == productID && len(recs) < limit {
+			recs = append(recs, rec)
+		}
+	}
+	return recs, nil
+}
+
+func (m *mockRepository) Rebuild(ctx context.Context, perProduct int) (int64, error) {
+	return 0, nil
+}
+
+func TestGetRecommendations(t *testing.T) {
+	computed := time.Date(2025, 3, 1, 3, 0, 0, 0, time.UTC)
+	repo := &mockRepository{recs: []*repository.Recommendation{
+		{ProductID: "prod-1", RecommendedProductID: "prod-2", Score: 5, ComputedAt: computed},
+		{ProductID: "prod-1", RecommendedProductID: "prod-3", Score: 2, ComputedAt: computed},
+	}}
+	svc := New(repo, log.NewDefault())
+
+	tests := []struct {
+		name      string
+		req       *recommendationv1.GetRecommendationsRequest
+		want      []string
+		wantLimit int
+		wantErr   bool
+	}{
+		{name: "missing product", req: &recommendationv1.GetRecommendationsRequest{}, wantErr: true},
+		{name: "default limit", req: &recommendationv1.GetRecommendationsRequest{ProductId: "prod-1"}, want: []string{"prod-2", "prod-3"}, wantLimit: defaultLimit},
+		{name: "explicit limit", req: &recommendationv1.GetRecommendationsRequest{ProductId: "prod-1", Limit: 1}, want: []string{"prod-2"}, wantLimit: 1},
+		{name: "limit capped", req: &recommendationv1.GetRecommendationsRequest{ProductId: "prod-1", Limit: 500}, want: []string{"prod-2", "prod-3"}, wantLimit: 20},
+		{name: "no history", req: &recommendationv1.GetRecommendationsRequest{ProductId: "prod-9"}, wantLimit: defaultLimit},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := svc.GetRecommendations(context.Background(), tt.req)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetRecommendations() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if repo.lastLimit != tt.wantLimit {
+				t.Errorf("repository limit = %d, want %d", repo.lastLimit, tt.wantLimit)
+			}
+			if len(resp.GetRecommendations()) != len(tt.want) {
+				t.Fatalf("got %d recommendations, want %d", len(resp.GetRecommendations()), len(tt.want))
+			}
+			for i, rec := range resp.GetRecommendations() {
+				if rec.GetProductId() != tt.want[i] {
+					t.Errorf("recommendation %d = %s, want %s", i, rec.GetProductId(), tt.want[i])
+				}
+			}
+			if len(tt.want) > 0 && !resp.GetComputedAt().AsTime().Equal(computed) {
+				t.Errorf("ComputedAt = %v, want %v", resp.GetComputedAt().AsTime(), computed)
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package index
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	"github.com/blevesearch/bleve/v2"
+	"github.com/blevesearch/bleve/v2/analysis/analyzer/keyword"
+	"github.com/blevesearch/bleve/v2/mapping"
+	"github.com/blevesearch/bleve/v2/search/query"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+)
+
+// BleveIndex is an embedded index for single-node deployments and tests
+type BleveIndex struct {
+	index bleve.Index
+}
+
+// NewBleveIndex opens the index at path, creating it if needed. An empty
+// path keeps the index in memory.
+func NewBleveIndex(path string) (*BleveIndex, error) {
+	var (
+		idx bleve.Index
+		err error
+	)
+
+	switch {
+	case path == "":
+		idx, err = bleve.NewMemOnly(newMapping())
+	case exists(path):
+		idx, err = bleve.Open(path)
+	default:
+		idx, err = bleve.New(path, newMapping())
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open bleve index")
+	}
+
+	return &BleveIndex{index: idx}, nil
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// newMapping indexes keyword fields verbatim and everything else as text
+func newMapping() mapping.IndexMapping {
+	doc := bleve.NewDocumentMapping()
+	for _, field := range KeywordFields {
+		fm := bleve.NewTextFieldMapping()
+		fm.Analyzer = keyword.Name
+		doc.AddFieldMappingsAt(field, fm)
+	}
+	doc.AddFieldMappingsAt(FieldCreatedAt, bleve.NewDateTimeFieldMapping())
+
+	m := bleve.NewIndexMapping()
+	m.DefaultMapping = doc
+	return m
+}
+
+// Index adds or replaces documents
+func (b *BleveIndex) Index(ctx context.Context, docs ...*Document) error {
+	batch := b.index.NewBatch()
+	for _, doc := range docs {
+		if err := batch.Index(doc.key(), doc.source()); err != nil {
+			return errors.Wrap(err, "failed to index document")
+		}
+	}
+
+	if err := b.index.Batch(batch); err != nil {
+		return errors.Wrap(err, "failed to index documents")
+	}
+	return nil
+}
+
+// Delete removes a document
+func (b *BleveIndex) Delete(ctx context.Context, docType, id string) error {
+	if err := b.index.Delete(docKey(docType, id)); err != nil {
+		return errors.Wrap(err, "failed to delete document")
+	}
+	return nil
+}
+
+// Search runs a query
+func (b *BleveIndex) Search(ctx context.Context, q *Query) (*Result, error) {
+	req := bleve.NewSearchRequestOptions(b.query(q), q.Size, q.From, false)
+	req.Fields = []string{"*"}
+	req.Highlight = bleve.NewHighlight()
+
+	res, err := b.index.SearchInContext(ctx, req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to search index")
+	}
+
+	result := &Result{Total: int64(res.Total)}
+	for _, match := range res.Hits {
+		docType, id, _ := strings.Cut(match.ID, ":")
+		result.Hits = append(result.Hits, &Hit{
+			ID:         id,
+			Type:       docType,
+			Score:      match.Score,
+			Fields:     match.Fields,
+			Highlights: match.Fragments,
+		})
+	}
+
+	return result, nil
+}
+
+func (b *BleveIndex) query(q *Query) query.Query {
+	var must []query.Query
+
+	if q.Text == "" {
+		must = append(must, bleve.NewMatchAllQuery())
+	} else {
+		must = append(must, bleve.NewMatchQuery(q.Text))
+	}
+
+	if len(q.Types) > 0 {
+		types := make([]query.Query, len(q.Types))
+		for i, t := range q.Types {
+			tq := bleve.NewTermQuery(t)
+			tq.SetField(FieldType)
+			types[i] = tq
+		}
+		must = append(must, bleve.NewDisjunctionQuery(types...))
+	}
+
+	for field, value := range q.Filters {
+		tq := bleve.NewTermQuery(value)
+		tq.SetField(field)
+		must = append(must, tq)
+	}
+
+	if !q.CreatedAfter.IsZero() || !q.CreatedBefore.IsZero() {
+		inclusive := true
+		dq := bleve.NewDateRangeInclusiveQuery(q.CreatedAfter, q.CreatedBefore, &inclusive, &inclusive)
+		dq.SetField(FieldCreatedAt)
+		must = append(must, dq)
+	}
+
+	return bleve.NewConjunctionQuery(must...)
+}
+
+// Close closes the index
+func (b *BleveIndex) Close() error {
+	return b.index.Close()
+}
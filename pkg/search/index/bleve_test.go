@@ -0,0 +1,122 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package index
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func newTestIndex(t *testing.T) *BleveIndex {
+	t.Helper()
+
+	idx, err := NewBleveIndex("")
+	if err != nil {
+		t.Fatalf("NewBleveIndex() error = %v", err)
+	}
+	t.Cleanup(func() { idx.Close() })
+
+	now := time.Now()
+	docs := []*Document{
+		{ID: "u1", Type: TypeUser, Fields: map[string]interface{}{
+			"name": "Alice Johnson", "email": "alice@example.com", FieldCreatedAt: now.Add(-48 * time.Hour),
+		}},
+		{ID: "u2", Type: TypeUser, Fields: map[string]interface{}{
+			"name": "Bob Smith", "email": "bob@example.com", FieldCreatedAt: now,
+		}},
+		{ID: "o1", Type: TypeOrder, Fields: map[string]interface{}{
+			"user_id": "u1", "status": "pending", "product_names": "Alice in Wonderland", FieldCreatedAt: now,
+		}},
+		{ID: "o2", Type: TypeOrder, Fields: map[string]interface{}{
+			"user_id": "u2", "status": "shipped", "product_names": "Mechanical keyboard", FieldCreatedAt: now,
+		}},
+	}
+	if err := idx.Index(context.Background(), docs...); err != nil {
+		t.Fatalf("Index() error = %v", err)
+	}
+	return idx
+}
+
+func TestBleveSearch(t *testing.T) {
+	idx := newTestIndex(t)
+	now := time.Now()
+
+	tests := []struct {
+		name  string
+		query *Query
+		want  []string
+	}{
+		{name: "match all", query: &Query{}, want: []string{"u1", "u2", "o1", "o2"}},
+		{name: "text across types", query: &Query{Text: "alice"}, want: []string{"u1", "o1"}},
+		{name: "type filter", query: &Query{Text: "alice", Types: []string{TypeOrder}}, want: []string{"o1"}},
+		{name: "keyword filter", query: &Query{Filters: map[string]string{"status": "shipped"}}, want: []string{"o2"}},
+		{name: "user filter", query: &Query{Types: []string{TypeOrder}, Filters: map[string]string{"user_id": "u1"}}, want: []string{"o1"}},
+		{name: "created range", query: &Query{Types: []string{TypeUser}, CreatedAfter: now.Add(-time.Hour)}, want: []string{"u2"}},
+		{name: "no match", query: &Query{Text: "nonexistent"}, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.query.Size = 10
+			res, err := idx.Search(context.Background(), tt.query)
+			if err != nil {
+				t.Fatalf("Search() error = %v", err)
+			}
+
+			got := map[string]bool{}
+			for _, hit := range res.Hits {
+				got[hit.ID] = true
+			}
+			if len(got) != len(tt.want) || res.Total != int64(len(tt.want)) {
+				t.Fatalf("Search() hits = %v (total %d), want %v", got, res.Total, tt.want)
+			}
+			for _, id := range tt.want {
+				if !got[id] {
+					t.Errorf("Search() missing hit %s, got %v", id, got)
+				}
+			}
+		})
+	}
+}
+
+func TestBleveSearchHighlightsAndDeletes(t *testing.T) {
+	idx := newTestIndex(t)
+	ctx := context.Background()
+
+	res, err := idx.Search(ctx, &Query{Text: "wonderland", Size: 10})
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if len(res.Hits) != 1 || res.Hits[0].Type != TypeOrder || res.Hits[0].ID != "o1" {
+		t.Fatalf("Search() hits = %+v, want order o1", res.Hits)
+	}
+	if len(res.Hits[0].Highlights["product_names"]) == 0 {
+		t.Errorf("Search() highlights = %v, want a product_names fragment", res.Hits[0].Highlights)
+	}
+
+	if err := idx.Delete(ctx, TypeOrder, "o1"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	res, err = idx.Search(ctx, &Query{Text: "wonderland", Size: 10})
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if len(res.Hits) != 0 {
+		t.Errorf("Search() after delete hits = %+v, want none", res.Hits)
+	}
+}
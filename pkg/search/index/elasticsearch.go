@@ -0,0 +1,255 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package index
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+)
+
+// ElasticsearchIndex stores documents in a single Elasticsearch index using
+// the REST API
+type ElasticsearchIndex struct {
+	baseURL string
+	index   string
+	client  *http.Client
+}
+
+// NewElasticsearchIndex connects to Elasticsearch and creates the index with
+// keyword mappings if it does not exist yet
+func NewElasticsearchIndex(ctx context.Context, baseURL, index string, client *http.Client) (*ElasticsearchIndex, error) {
+	if baseURL == "" || index == "" {
+		return nil, errors.WithCode(errors.New("elasticsearch url and index are required"), errors.CodeInvalidInput)
+	}
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	es := &ElasticsearchIndex{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		index:   index,
+		client:  client,
+	}
+
+	if err := es.ensureIndex(ctx); err != nil {
+		return nil, err
+	}
+	return es, nil
+}
+
+func (es *ElasticsearchIndex) ensureIndex(ctx context.Context) error {
+	status, _, err := es.do(ctx, http.MethodHead, "/"+es.index, nil)
+	if err != nil {
+		return err
+	}
+	if status == http.StatusOK {
+		return nil
+	}
+
+	properties := map[string]interface{}{
+		FieldCreatedAt: map[string]string{"type": "date"},
+	}
+	for _, field := range KeywordFields {
+		properties[field] = map[string]string{"type": "keyword"}
+	}
+
+	body := map[string]interface{}{
+		"mappings": map[string]interface{}{"properties": properties},
+	}
+	status, resp, err := es.do(ctx, http.MethodPut, "/"+es.index, body)
+	if err != nil {
+		return err
+	}
+	// Another replica may have created it in the meantime
+	if status >= 300 && !bytes.Contains(resp, []byte("resource_already_exists_exception")) {
+		return esError("failed to create index", status, resp)
+	}
+	return nil
+}
+
+// Index adds or replaces documents
+func (es *ElasticsearchIndex) Index(ctx context.Context, docs ...*Document) error {
+	for _, doc := range docs {
+		status, resp, err := es.do(ctx, http.MethodPut, "/"+es.index+"/_doc/"+url.PathEscape(doc.key()), doc.source())
+		if err != nil {
+			return err
+		}
+		if status >= 300 {
+			return esError("failed to index document", status, resp)
+		}
+	}
+	return nil
+}
+
+// Delete removes a document
+func (es *ElasticsearchIndex) Delete(ctx context.Context, docType, id string) error {
+	status, resp, err := es.do(ctx, http.MethodDelete, "/"+es.index+"/_doc/"+url.PathEscape(docKey(docType, id)), nil)
+	if err != nil {
+		return err
+	}
+	if status >= 300 && status != http.StatusNotFound {
+		return esError("failed to delete document", status, resp)
+	}
+	return nil
+}
+
+// Search runs a query
+func (es *ElasticsearchIndex) Search(ctx context.Context, q *Query) (*Result, error) {
+	status, resp, err := es.do(ctx, http.MethodPost, "/"+es.index+"/_search", searchBody(q))
+	if err != nil {
+		return nil, err
+	}
+	if status >= 300 {
+		return nil, esError("failed to search index", status, resp)
+	}
+
+	var parsed struct {
+		Hits struct {
+			Total struct {
+				Value int64 `json:"value"`
+			} `json:"total"`
+			Hits []struct {
+				ID        string                 `json:"_id"`
+				Score     float64                `json:"_score"`
+				Source    map[string]interface{} `json:"_source"`
+				Highlight map[string][]string    `json:"highlight"`
+			} `json:"hits"`
+		} `json:"hits"`
+	}
+	if err := json.Unmarshal(resp, &parsed); err != nil {
+		return nil, errors.Wrap(err, "failed to decode search response")
+	}
+
+	result := &Result{Total: parsed.Hits.Total.Value}
+	for _, h := range parsed.Hits.Hits {
+		docType, id, _ := strings.Cut(h.ID, ":")
+		result.Hits = append(result.Hits, &Hit{
+			ID:         id,
+			Type:       docType,
+			Score:      h.Score,
+			Fields:     h.Source,
+			Highlights: h.Highlight,
+		})
+	}
+
+	return result, nil
+}
+
+// searchBody builds the query DSL for q
+func searchBody(q *Query) map[string]interface{} {
+	var must interface{} = map[string]interface{}{"match_all": map[string]interface{}{}}
+	if q.Text != "" {
+		must = map[string]interface{}{
+			"multi_match": map[string]interface{}{
+				"query":   q.Text,
+				"fields":  []string{"*"},
+				"lenient": true,
+			},
+		}
+	}
+
+	var filter []interface{}
+	if len(q.Types) > 0 {
+		filter = append(filter, map[string]interface{}{
+			"terms": map[string]interface{}{FieldType: q.Types},
+		})
+	}
+	for field, value := range q.Filters {
+		filter = append(filter, map[string]interface{}{
+			"term": map[string]interface{}{field: value},
+		})
+	}
+	if !q.CreatedAfter.IsZero() || !q.CreatedBefore.IsZero() {
+		rng := map[string]interface{}{}
+		if !q.CreatedAfter.IsZero() {
+			rng["gte"] = q.CreatedAfter.Format(time.RFC3339Nano)
+		}
+		if !q.CreatedBefore.IsZero() {
+			rng["lte"] = q.CreatedBefore.Format(time.RFC3339Nano)
+		}
+		filter = append(filter, map[string]interface{}{
+			"range": map[string]interface{}{FieldCreatedAt: rng},
+		})
+	}
+
+	return map[string]interface{}{
+		"from":             q.From,
+		"size":             q.Size,
+		"track_total_hits": true,
+		"query": map[string]interface{}{
+			"bool": map[string]interface{}{
+				"must":   must,
+				"filter": filter,
+			},
+		},
+		"highlight": map[string]interface{}{
+			"fields": map[string]interface{}{"*": map[string]interface{}{}},
+		},
+	}
+}
+
+func (es *ElasticsearchIndex) do(ctx context.Context, method, path string, body interface{}) (int, []byte, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return 0, nil, errors.Wrap(err, "failed to encode elasticsearch request")
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, es.baseURL+path, reader)
+	if err != nil {
+		return 0, nil, errors.Wrap(err, "failed to build elasticsearch request")
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := es.client.Do(req)
+	if err != nil {
+		return 0, nil, errors.WithCode(errors.Wrap(err, "elasticsearch request failed"), errors.CodeUnavailable)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, nil, errors.Wrap(err, "failed to read elasticsearch response")
+	}
+	return resp.StatusCode, data, nil
+}
+
+func esError(message string, status int, body []byte) error {
+	if len(body) > 512 {
+		body = body[:512]
+	}
+	return errors.Newf("%s: elasticsearch responded with %d: %s", message, status, body)
+}
+
+// Close releases idle connections
+func (es *ElasticsearchIndex) Close() error {
+	es.client.CloseIdleConnections()
+	return nil
+}
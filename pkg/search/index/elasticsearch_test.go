@@ -0,0 +1,80 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package index
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestElasticsearchIndex(t *testing.T) {
+	var created bool
+	var searchBody map[string]interface{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodHead && r.URL.Path == "/docs":
+			w.WriteHeader(http.StatusNotFound)
+		case r.Method == http.MethodPut && r.URL.Path == "/docs":
+			created = true
+			w.Write([]byte(`{"acknowledged":true}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/docs/_search":
+			json.NewDecoder(r.Body).Decode(&searchBody)
+			w.Write([]byte(`{"hits":{"total":{"value":1},"hits":[
+				{"_id":"order:o1","_score":1.5,"_source":{"status":"pending"},
+				 "highlight":{"product_names":["<mark>Alice</mark> in Wonderland"]}}]}}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	es, err := NewElasticsearchIndex(context.Background(), server.URL, "docs", nil)
+	if err != nil {
+		t.Fatalf("NewElasticsearchIndex() error = %v", err)
+	}
+	if !created {
+		t.Error("NewElasticsearchIndex() did not create the missing index")
+	}
+
+	res, err := es.Search(context.Background(), &Query{
+		Text:    "alice",
+		Types:   []string{TypeOrder},
+		Filters: map[string]string{"status": "pending"},
+		Size:    10,
+	})
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+
+	if res.Total != 1 || len(res.Hits) != 1 {
+		t.Fatalf("Search() = %+v, want one hit", res)
+	}
+	hit := res.Hits[0]
+	if hit.ID != "o1" || hit.Type != TypeOrder || hit.Score != 1.5 || len(hit.Highlights["product_names"]) != 1 {
+		t.Errorf("Search() hit = %+v", hit)
+	}
+
+	filters := searchBody["query"].(map[string]interface{})["bool"].(map[string]interface{})["filter"].([]interface{})
+	if len(filters) != 2 {
+		t.Errorf("search filters = %v, want type and status filters", filters)
+	}
+}
@@ -0,0 +1,123 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package index
+
+import (
+	"context"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/config"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+)
+
+// Supported drivers
+const (
+	DriverBleve         = "bleve"
+	DriverElasticsearch = "elasticsearch"
+)
+
+// Document types
+const (
+	TypeUser  = "user"
+	TypeOrder = "order"
+)
+
+// Reserved document fields
+const (
+	FieldType      = "type"
+	FieldID        = "id"
+	FieldCreatedAt = "created_at"
+)
+
+// KeywordFields are indexed verbatim so they can be used as exact filters
+var KeywordFields = []string{FieldType, FieldID, "user_id", "status", "email", "product_ids"}
+
+// Document is a searchable representation of a resource
+type Document struct {
+	ID     string
+	Type   string
+	Fields map[string]interface{}
+}
+
+// key returns the index-wide document key
+func (d *Document) key() string {
+	return docKey(d.Type, d.ID)
+}
+
+// source returns the fields to index including the reserved ones
+func (d *Document) source() map[string]interface{} {
+	src := make(map[string]interface{}, len(d.Fields)+2)
+	for k, v := range d.Fields {
+		src[k] = v
+	}
+	src[FieldType] = d.Type
+	src[FieldID] = d.ID
+	return src
+}
+
+func docKey(docType, id string) string {
+	return docType + ":" + id
+}
+
+// Query describes a search
+type Query struct {
+	// Text is matched against every text field; empty matches all documents
+	Text string
+	// Types restricts the search to some document types
+	Types []string
+	// Filters are exact matches on keyword fields
+	Filters       map[string]string
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+	From          int
+	Size          int
+}
+
+// Hit is a single search result
+type Hit struct {
+	ID         string
+	Type       string
+	Score      float64
+	Fields     map[string]interface{}
+	Highlights map[string][]string
+}
+
+// Result is the outcome of a search, ordered by descending relevance
+type Result struct {
+	Total int64
+	Hits  []*Hit
+}
+
+// Index stores and searches documents
+type Index interface {
+	Index(ctx context.Context, docs ...*Document) error
+	Delete(ctx context.Context, docType, id string) error
+	Search(ctx context.Context, q *Query) (*Result, error)
+	Close() error
+}
+
+// New creates an index for the configured driver
+func New(ctx context.Context, cfg *config.Search) (Index, error) {
+	switch cfg.Driver {
+	case DriverBleve, "":
+		return NewBleveIndex(cfg.Path)
+	case DriverElasticsearch:
+		return NewElasticsearchIndex(ctx, cfg.ElasticsearchURL, cfg.Index, nil)
+	default:
+		return nil, errors.WithCode(errors.Newf("unsupported search driver %q", cfg.Driver), errors.CodeInvalidInput)
+	}
+}
@@ -0,0 +1,174 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package indexer
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/internal/events"
+	"github.com/kevindiu/monorepo-go-example/internal/log"
+	orderevents "github.com/kevindiu/monorepo-go-example/pkg/order/events"
+	"github.com/kevindiu/monorepo-go-example/pkg/search/index"
+	userevents "github.com/kevindiu/monorepo-go-example/pkg/user/events"
+)
+
+// DefaultGroup is the consumer group used by search indexers
+const DefaultGroup = "search-indexer"
+
+// Config holds indexer configuration
+type Config struct {
+	Index      index.Index
+	Subscriber events.Subscriber
+	Topics     []string
+	Group      string
+	Logger     *log.Logger
+}
+
+// Indexer keeps the search index up to date from user and order events
+type Indexer struct {
+	index      index.Index
+	subscriber events.Subscriber
+	topics     []string
+	group      string
+	logger     *log.Logger
+}
+
+// New creates a new indexer
+func New(cfg Config) (*Indexer, error) {
+	if cfg.Index == nil {
+		return nil, errors.WithCode(errors.New("search index is required"), errors.CodeInvalidInput)
+	}
+	if cfg.Subscriber == nil && len(cfg.Topics) > 0 {
+		return nil, errors.WithCode(errors.New("subscriber is required to consume topics"), errors.CodeInvalidInput)
+	}
+	if cfg.Group == "" {
+		cfg.Group = DefaultGroup
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = log.NewDefault()
+	}
+
+	return &Indexer{
+		index:      cfg.Index,
+		subscriber: cfg.Subscriber,
+		topics:     cfg.Topics,
+		group:      cfg.Group,
+		logger:     cfg.Logger.Named("indexer"),
+	}, nil
+}
+
+// Run consumes the configured topics until ctx is cancelled
+func (i *Indexer) Run(ctx context.Context) error {
+	i.logger.Info("Starting search indexer", log.Any("topics", i.topics))
+
+	var wg sync.WaitGroup
+	for _, topic := range i.topics {
+		topic := topic
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := i.subscriber.Subscribe(ctx, topic, i.group, i.Handle); err != nil && ctx.Err() == nil {
+				i.logger.Error("Search subscription failed", log.String("topic", topic), log.Error(err))
+			}
+		}()
+	}
+
+	wg.Wait()
+	return nil
+}
+
+// Handle applies a single event to the index. Unknown event types are
+// ignored so producers can add new events without breaking the indexer.
+func (i *Indexer) Handle(ctx context.Context, event *events.Event) error {
+	switch event.Type {
+	case userevents.TypeUserCreated, userevents.TypeUserUpdated:
+		var user userevents.User
+		if err := event.Decode(&user); err != nil {
+			return err
+		}
+		return i.index.Index(ctx, UserDocument(&user))
+
+	case userevents.TypeUserDeleted:
+		var user userevents.User
+		if err := event.Decode(&user); err != nil {
+			return err
+		}
+		return i.index.Delete(ctx, index.TypeUser, user.ID)
+
+	case orderevents.TypeOrderCreated, orderevents.TypeOrderStatusChanged:
+		var order orderevents.Order
+		if err := event.Decode(&order); err != nil {
+			return err
+		}
+		return i.index.Index(ctx, OrderDocument(&order))
+
+	case orderevents.TypeOrderDeleted:
+		var order orderevents.Order
+		if err := event.Decode(&order); err != nil {
+			return err
+		}
+		return i.index.Delete(ctx, index.TypeOrder, order.ID)
+
+	default:
+		i.logger.Debug("Ignoring event", log.String("type", event.Type))
+		return nil
+	}
+}
+
+// UserDocument converts a user event payload into a search document
+func UserDocument(user *userevents.User) *index.Document {
+	return &index.Document{
+		ID:   user.ID,
+		Type: index.TypeUser,
+		Fields: map[string]interface{}{
+			"name":               user.Name,
+			"email":              user.Email,
+			index.FieldCreatedAt: user.CreatedAt,
+			"updated_at":         user.UpdatedAt,
+		},
+	}
+}
+
+// OrderDocument converts an order event payload into a search document
+func OrderDocument(order *orderevents.Order) *index.Document {
+	productIDs := make([]string, 0, len(order.Items))
+	productNames := make([]string, 0, len(order.Items))
+	for _, item := range order.Items {
+		productIDs = append(productIDs, item.ProductID)
+		if item.ProductName != "" {
+			productNames = append(productNames, item.ProductName)
+		}
+	}
+
+	return &index.Document{
+		ID:   order.ID,
+		Type: index.TypeOrder,
+		Fields: map[string]interface{}{
+			"user_id":            order.UserID,
+			"status":             order.Status,
+			"total_minor":        order.TotalMinor,
+			"currency":           order.Currency,
+			"product_ids":        productIDs,
+			"product_names":      strings.Join(productNames, " "),
+			index.FieldCreatedAt: order.CreatedAt,
+			"updated_at":         order.UpdatedAt,
+		},
+	}
+}
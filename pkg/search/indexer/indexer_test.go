@@ -0,0 +1,87 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package indexer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kevindiu/monorepo-go-example/internal/events"
+	orderevents "github.com/kevindiu/monorepo-go-example/pkg/order/events"
+	"github.com/kevindiu/monorepo-go-example/pkg/search/index"
+	userevents "github.com/kevindiu/monorepo-go-example/pkg/user/events"
+)
+
+func TestHandle(t *testing.T) {
+	idx, err := index.NewBleveIndex("")
+	if err != nil {
+		t.Fatalf("NewBleveIndex() error = %v", err)
+	}
+	defer idx.Close()
+
+	ix, err := New(Config{Index: idx})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	handle := func(eventType string, payload interface{}) {
+		t.Helper()
+		event, err := events.NewEvent(eventType, "", payload)
+		if err != nil {
+			t.Fatalf("NewEvent() error = %v", err)
+		}
+		if err := ix.Handle(context.Background(), event); err != nil {
+			t.Fatalf("Handle(%s) error = %v", eventType, err)
+		}
+	}
+
+	handle(userevents.TypeUserCreated, userevents.User{ID: "u1", Name: "Carol Danvers", Email: "carol@example.com"})
+	handle(orderevents.TypeOrderCreated, orderevents.Order{
+		ID: "o1", UserID: "u1", Status: "pending",
+		Items: []orderevents.Item{{ProductID: "p1", ProductName: "Flight jacket", Quantity: 1, PriceMinor: 9900}},
+	})
+	handle(orderevents.TypeOrderStatusChanged, orderevents.Order{
+		ID: "o1", UserID: "u1", Status: "shipped",
+		Items: []orderevents.Item{{ProductID: "p1", ProductName: "Flight jacket", Quantity: 1, PriceMinor: 9900}},
+	})
+	handle("order.unknown", map[string]string{"id": "o1"})
+
+	search := func(q *index.Query) []*index.Hit {
+		t.Helper()
+		q.Size = 10
+		res, err := idx.Search(context.Background(), q)
+		if err != nil {
+			t.Fatalf("Search() error = %v", err)
+		}
+		return res.Hits
+	}
+
+	if hits := search(&index.Query{Text: "carol"}); len(hits) != 1 || hits[0].Type != index.TypeUser {
+		t.Errorf("search for user = %+v, want user u1", hits)
+	}
+	if hits := search(&index.Query{Filters: map[string]string{"status": "shipped", "product_ids": "p1"}}); len(hits) != 1 || hits[0].ID != "o1" {
+		t.Errorf("search for shipped order = %+v, want order o1", hits)
+	}
+	if hits := search(&index.Query{Filters: map[string]string{"status": "pending"}}); len(hits) != 0 {
+		t.Errorf("status change did not replace the order document: %+v", hits)
+	}
+
+	handle(userevents.TypeUserDeleted, userevents.User{ID: "u1"})
+	if hits := search(&index.Query{Text: "carol"}); len(hits) != 0 {
+		t.Errorf("deleted user is still searchable: %+v", hits)
+	}
+}
@@ -0,0 +1,212 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	searchv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/search/v1"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/internal/log"
+	"github.com/kevindiu/monorepo-go-example/pkg/search/index"
+)
+
+// typeToProto converts a document type to the proto enum
+func typeToProto(docType string) searchv1.DocumentType {
+	switch docType {
+	case index.TypeUser:
+		return searchv1.DocumentType_DOCUMENT_TYPE_USER
+	case index.TypeOrder:
+		return searchv1.DocumentType_DOCUMENT_TYPE_ORDER
+	default:
+		return searchv1.DocumentType_DOCUMENT_TYPE_UNSPECIFIED
+	}
+}
+
+// typeFromProto converts the proto enum to a document type
+func typeFromProto(docType searchv1.DocumentType) string {
+	switch docType {
+	case searchv1.DocumentType_DOCUMENT_TYPE_USER:
+		return index.TypeUser
+	case searchv1.DocumentType_DOCUMENT_TYPE_ORDER:
+		return index.TypeOrder
+	default:
+		return ""
+	}
+}
+
+// Service defines the search service interface
+type Service interface {
+	searchv1.SearchServiceServer
+}
+
+type service struct {
+	searchv1.UnimplementedSearchServiceServer
+	index  index.Index
+	logger *log.Logger
+}
+
+// New creates a new search service
+func New(idx index.Index, logger *log.Logger) Service {
+	return &service{
+		index:  idx,
+		logger: logger,
+	}
+}
+
+// Search returns documents ranked by relevance
+func (s *service) Search(ctx context.Context, req *searchv1.SearchRequest) (*searchv1.SearchResponse, error) {
+	s.logger.Info("Searching", log.String("query", req.GetQuery()), log.Int32("page_size", req.GetPageSize()))
+
+	q, err := buildQuery(req)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := s.index.Search(ctx, q)
+	if err != nil {
+		s.logger.Error("Failed to search", log.Error(err))
+		return nil, err
+	}
+
+	hits := make([]*searchv1.Hit, len(res.Hits))
+	for i, hit := range res.Hits {
+		hits[i] = hitToProto(hit)
+	}
+
+	nextPageToken := ""
+	if int64(q.From+q.Size) < res.Total {
+		nextPageToken = strconv.Itoa(q.From + q.Size)
+	}
+
+	return &searchv1.SearchResponse{
+		Hits:          hits,
+		Total:         res.Total,
+		NextPageToken: nextPageToken,
+	}, nil
+}
+
+// buildQuery validates a request and converts it into an index query
+func buildQuery(req *searchv1.SearchRequest) (*index.Query, error) {
+	pageSize := int(req.GetPageSize())
+	if pageSize <= 0 {
+		pageSize = 10
+	}
+	if pageSize > 100 {
+		pageSize = 100
+	}
+
+	offset := 0
+	if req.GetPageToken() != "" {
+		parsedOffset, err := strconv.Atoi(req.GetPageToken())
+		if err == nil && parsedOffset > 0 {
+			offset = parsedOffset
+		}
+	}
+
+	q := &index.Query{
+		Text: strings.TrimSpace(req.GetQuery()),
+		From: offset,
+		Size: pageSize,
+	}
+
+	for _, t := range req.GetTypes() {
+		docType := typeFromProto(t)
+		if docType == "" {
+			return nil, errors.WithCode(errors.Newf("unsupported document type %s", t), errors.CodeInvalidInput)
+		}
+		q.Types = append(q.Types, docType)
+	}
+
+	if len(req.GetFilters()) > 0 {
+		q.Filters = make(map[string]string, len(req.GetFilters()))
+		for field, value := range req.GetFilters() {
+			if !isFilterable(field) {
+				return nil, errors.WithCode(errors.Newf("field %q cannot be used as a filter", field), errors.CodeInvalidInput)
+			}
+			q.Filters[field] = value
+		}
+	}
+
+	if req.GetCreatedAfter() != nil {
+		q.CreatedAfter = req.GetCreatedAfter().AsTime()
+	}
+	if req.GetCreatedBefore() != nil {
+		q.CreatedBefore = req.GetCreatedBefore().AsTime()
+	}
+	if !q.CreatedAfter.IsZero() && !q.CreatedBefore.IsZero() && q.CreatedBefore.Before(q.CreatedAfter) {
+		return nil, errors.WithCode(errors.New("created_before must not be earlier than created_after"), errors.CodeInvalidInput)
+	}
+
+	return q, nil
+}
+
+// isFilterable reports whether field is indexed verbatim. The type field is
+// filtered through the types parameter instead.
+func isFilterable(field string) bool {
+	if field == index.FieldType {
+		return false
+	}
+	for _, f := range index.KeywordFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+func hitToProto(hit *index.Hit) *searchv1.Hit {
+	fields := make(map[string]string, len(hit.Fields))
+	for k, v := range hit.Fields {
+		if k == index.FieldType || k == index.FieldID {
+			continue
+		}
+		fields[k] = formatField(v)
+	}
+
+	highlights := make(map[string]*searchv1.Highlight, len(hit.Highlights))
+	for k, fragments := range hit.Highlights {
+		highlights[k] = &searchv1.Highlight{Fragments: fragments}
+	}
+
+	return &searchv1.Hit{
+		Id:         hit.ID,
+		Type:       typeToProto(hit.Type),
+		Score:      hit.Score,
+		Fields:     fields,
+		Highlights: highlights,
+	}
+}
+
+// formatField renders a stored field value; lists are comma separated
+func formatField(v interface{}) string {
+	switch val := v.(type) {
+	case string:
+		return val
+	case []interface{}:
+		parts := make([]string, len(val))
+		for i, p := range val {
+			parts[i] = formatField(p)
+		}
+		return strings.Join(parts, ",")
+	default:
+		return fmt.Sprint(val)
+	}
+}
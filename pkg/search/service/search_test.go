@@ -0,0 +1,135 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	searchv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/search/v1"
+	"github.com/kevindiu/monorepo-go-example/internal/log"
+	"github.com/kevindiu/monorepo-go-example/pkg/search/index"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func newTestService(t *testing.T) Service {
+	t.Helper()
+
+	idx, err := index.NewBleveIndex("")
+	if err != nil {
+		t.Fatalf("NewBleveIndex() error = %v", err)
+	}
+	t.Cleanup(func() { idx.Close() })
+
+	docs := []*index.Document{
+		{ID: "u1", Type: index.TypeUser, Fields: map[string]interface{}{"name": "Diana Prince", "email": "diana@example.com"}},
+		{ID: "o1", Type: index.TypeOrder, Fields: map[string]interface{}{"user_id": "u1", "status": "pending", "product_names": "Golden lasso", "product_ids": []string{"p1", "p2"}}},
+		{ID: "o2", Type: index.TypeOrder, Fields: map[string]interface{}{"user_id": "u1", "status": "shipped", "product_names": "Silver bracelets"}},
+	}
+	if err := idx.Index(context.Background(), docs...); err != nil {
+		t.Fatalf("Index() error = %v", err)
+	}
+
+	return New(idx, log.NewDefault())
+}
+
+func TestSearch(t *testing.T) {
+	svc := newTestService(t)
+
+	tests := []struct {
+		name      string
+		req       *searchv1.SearchRequest
+		wantTotal int64
+		wantErr   bool
+	}{
+		{name: "match all", req: &searchv1.SearchRequest{}, wantTotal: 3},
+		{name: "text", req: &searchv1.SearchRequest{Query: "lasso"}, wantTotal: 1},
+		{
+			name:      "orders only",
+			req:       &searchv1.SearchRequest{Types: []searchv1.DocumentType{searchv1.DocumentType_DOCUMENT_TYPE_ORDER}},
+			wantTotal: 2,
+		},
+		{
+			name:      "status filter",
+			req:       &searchv1.SearchRequest{Filters: map[string]string{"status": "shipped"}},
+			wantTotal: 1,
+		},
+		{
+			name:    "unsupported filter",
+			req:     &searchv1.SearchRequest{Filters: map[string]string{"name": "diana"}},
+			wantErr: true,
+		},
+		{
+			name:    "unspecified type",
+			req:     &searchv1.SearchRequest{Types: []searchv1.DocumentType{searchv1.DocumentType_DOCUMENT_TYPE_UNSPECIFIED}},
+			wantErr: true,
+		},
+		{
+			name: "inverted date range",
+			req: &searchv1.SearchRequest{
+				CreatedAfter:  timestamppb.New(time.Now()),
+				CreatedBefore: timestamppb.New(time.Now().Add(-time.Hour)),
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := svc.Search(context.Background(), tt.req)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Search() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && resp.GetTotal() != tt.wantTotal {
+				t.Errorf("Search() total = %d, want %d", resp.GetTotal(), tt.wantTotal)
+			}
+		})
+	}
+}
+
+func TestSearchHitFieldsAndPaging(t *testing.T) {
+	svc := newTestService(t)
+
+	resp, err := svc.Search(context.Background(), &searchv1.SearchRequest{Query: "lasso"})
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	hit := resp.GetHits()[0]
+	if hit.GetType() != searchv1.DocumentType_DOCUMENT_TYPE_ORDER || hit.GetFields()["product_ids"] != "p1,p2" {
+		t.Errorf("Search() hit = %v", hit)
+	}
+	if len(hit.GetHighlights()["product_names"].GetFragments()) == 0 {
+		t.Errorf("Search() hit has no highlight for product_names: %v", hit.GetHighlights())
+	}
+
+	resp, err = svc.Search(context.Background(), &searchv1.SearchRequest{PageSize: 2})
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if resp.GetNextPageToken() != "2" {
+		t.Errorf("Search() next_page_token = %q, want 2", resp.GetNextPageToken())
+	}
+
+	resp, err = svc.Search(context.Background(), &searchv1.SearchRequest{PageSize: 2, PageToken: "2"})
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if len(resp.GetHits()) != 1 || resp.GetNextPageToken() != "" {
+		t.Errorf("Search() last page = %d hits, token %q", len(resp.GetHits()), resp.GetNextPageToken())
+	}
+}
@@ -0,0 +1,125 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package server
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/health"
+	"github.com/kevindiu/monorepo-go-example/internal/log"
+	"google.golang.org/grpc"
+	healthgrpc "google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// DefaultHealthCheckInterval is how often health checks are run
+const DefaultHealthCheckInterval = 10 * time.Second
+
+// healthReporter serves the standard gRPC health service. Every registered
+// service, and the server as a whole under the empty name, reports SERVING
+// while all critical checks pass and NOT_SERVING otherwise.
+type healthReporter struct {
+	server   *healthgrpc.Server
+	services []string
+	checks   []health.Checker
+	interval time.Duration
+	logger   *log.Logger
+
+	mu      sync.Mutex
+	failing map[string]bool
+}
+
+// newHealthReporter registers the health service on grpcServer, reporting
+// for the services already registered on it
+func newHealthReporter(grpcServer *grpc.Server, checks []health.Checker, interval time.Duration, logger *log.Logger) *healthReporter {
+	h := &healthReporter{
+		server:   healthgrpc.NewServer(),
+		services: []string{""},
+		checks:   checks,
+		interval: interval,
+		logger:   logger,
+		failing:  make(map[string]bool),
+	}
+	for name := range grpcServer.GetServiceInfo() {
+		h.services = append(h.services, name)
+	}
+	grpc_health_v1.RegisterHealthServer(grpcServer, h.server)
+
+	h.set(grpc_health_v1.HealthCheckResponse_SERVING)
+	return h
+}
+
+// watch runs the health checks every interval until ctx is done
+func (h *healthReporter) watch(ctx context.Context) {
+	if len(h.checks) == 0 {
+		return
+	}
+
+	ticker := time.NewTicker(h.interval)
+	defer ticker.Stop()
+
+	for {
+		h.check(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// check runs every health check once and updates the serving status
+func (h *healthReporter) check(ctx context.Context) {
+	report := health.Run(ctx, h.checks)
+	if ctx.Err() != nil {
+		return
+	}
+
+	h.mu.Lock()
+	for name, result := range report.Checks {
+		failing := result.Status == health.CheckFailing
+		switch {
+		case failing && !h.failing[name]:
+			h.logger.Warn("Health check failed", log.String("check", name), log.String("error", result.Error))
+		case !failing && h.failing[name]:
+			h.logger.Info("Health check recovered", log.String("check", name))
+		}
+		h.failing[name] = failing
+	}
+	h.mu.Unlock()
+
+	if report.Ready() {
+		h.set(grpc_health_v1.HealthCheckResponse_SERVING)
+	} else {
+		h.set(grpc_health_v1.HealthCheckResponse_NOT_SERVING)
+	}
+}
+
+// set reports status for every service
+func (h *healthReporter) set(status grpc_health_v1.HealthCheckResponse_ServingStatus) {
+	for _, name := range h.services {
+		h.server.SetServingStatus(name, status)
+	}
+}
+
+// shutdown reports NOT_SERVING for every service from now on, so that
+// clients stop sending new calls while the server drains
+func (h *healthReporter) shutdown() {
+	h.server.Shutdown()
+}
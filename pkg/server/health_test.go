@@ -0,0 +1,95 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/health"
+	"github.com/kevindiu/monorepo-go-example/internal/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestHealthReporter(t *testing.T) {
+	dbErr := errors.New("connection refused")
+
+	tests := []struct {
+		name       string
+		checks     []health.Checker
+		shutdown   bool
+		wantStatus grpc_health_v1.HealthCheckResponse_ServingStatus
+	}{
+		{name: "no checks", wantStatus: grpc_health_v1.HealthCheckResponse_SERVING},
+		{
+			name:       "checks pass",
+			checks:     []health.Checker{{Name: "database", Critical: true, Check: func(ctx context.Context) error { return nil }}},
+			wantStatus: grpc_health_v1.HealthCheckResponse_SERVING,
+		},
+		{
+			name:       "check fails",
+			checks:     []health.Checker{{Name: "database", Critical: true, Check: func(ctx context.Context) error { return dbErr }}},
+			wantStatus: grpc_health_v1.HealthCheckResponse_NOT_SERVING,
+		},
+		{
+			name:       "non-critical check fails",
+			checks:     []health.Checker{{Name: "search", Check: func(ctx context.Context) error { return dbErr }}},
+			wantStatus: grpc_health_v1.HealthCheckResponse_SERVING,
+		},
+		{name: "shutdown", shutdown: true, wantStatus: grpc_health_v1.HealthCheckResponse_NOT_SERVING},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newHealthReporter(grpc.NewServer(), tt.checks, time.Second, log.NewDefault())
+			h.check(context.Background())
+			if tt.shutdown {
+				h.shutdown()
+			}
+
+			resp, err := h.server.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+			if err != nil {
+				t.Fatalf("Check() error = %v", err)
+			}
+			if resp.Status != tt.wantStatus {
+				t.Errorf("Check() status = %v, want %v", resp.Status, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestHealthReporterRecovers(t *testing.T) {
+	var dbErr error = errors.New("connection refused")
+	h := newHealthReporter(grpc.NewServer(), []health.Checker{
+		{Name: "database", Critical: true, Check: func(ctx context.Context) error { return dbErr }},
+	}, time.Second, log.NewDefault())
+
+	h.check(context.Background())
+	dbErr = nil
+	h.check(context.Background())
+
+	resp, err := h.server.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if resp.Status != grpc_health_v1.HealthCheckResponse_SERVING {
+		t.Errorf("Check() status = %v after recovery, want SERVING", resp.Status)
+	}
+}
@@ -0,0 +1,84 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package server
+
+import (
+	"context"
+
+	"github.com/kevindiu/monorepo-go-example/internal/log"
+	"github.com/kevindiu/monorepo-go-example/internal/runner"
+)
+
+// Component is a part of a service, such as a database, a repository or a
+// service built on them. It starts after the components it depends on and
+// stops before them.
+type Component = runner.Component
+
+// Lifecycle starts components in dependency order and stops them in
+// reverse. Components are initialized lazily by Start, so they may be added
+// in any order.
+type Lifecycle struct {
+	logger     *log.Logger
+	components []Component
+	started    []Component
+	running    bool
+}
+
+// NewLifecycle creates an empty lifecycle
+func NewLifecycle(logger *log.Logger) *Lifecycle {
+	if logger == nil {
+		logger = log.NewDefault()
+	}
+	return &Lifecycle{logger: logger}
+}
+
+// Add registers components. Names and dependencies are checked by Start.
+func (l *Lifecycle) Add(components ...Component) {
+	l.components = append(l.components, components...)
+}
+
+// Start starts the components, each after its dependencies. When one
+// fails, those already started are stopped in reverse order and the error
+// names the failed component. Start does nothing once it has succeeded.
+func (l *Lifecycle) Start(ctx context.Context) error {
+	if l.running {
+		return nil
+	}
+
+	started, err := runner.Start(ctx, l.logger, l.components)
+	if err != nil {
+		return err
+	}
+	l.started = started
+	l.running = true
+	return nil
+}
+
+// Stop stops the started components in reverse order of start, each under
+// its own deadline, and returns the names of those that failed
+func (l *Lifecycle) Stop() []string {
+	failed := runner.Shutdown(l.logger, l.stages())
+	l.started = nil
+	l.running = false
+	return failed
+}
+
+// stages returns the started components in reverse order of start, as
+// shutdown stages
+func (l *Lifecycle) stages() []stage {
+	return runner.Reverse(l.started)
+}
@@ -0,0 +1,162 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package server
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/log"
+)
+
+// recorder records component starts and stops
+type recorder struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (r *recorder) record(event string) {
+	r.mu.Lock()
+	r.events = append(r.events, event)
+	r.mu.Unlock()
+}
+
+func (r *recorder) component(name string, deps ...string) Component {
+	return Component{
+		Name:      name,
+		DependsOn: deps,
+		Start: func(context.Context) error {
+			r.record("start " + name)
+			return nil
+		},
+		Stop: func(context.Context) error {
+			r.record("stop " + name)
+			return nil
+		},
+	}
+}
+
+func TestLifecycleStart(t *testing.T) {
+	tests := []struct {
+		name       string
+		components func(r *recorder) []Component
+		wantEvents []string
+		wantErr    string
+	}{
+		{
+			name: "dependency order",
+			components: func(r *recorder) []Component {
+				return []Component{
+					r.component("service", "repository"),
+					r.component("repository", "database"),
+					r.component("database"),
+					r.component("events"),
+				}
+			},
+			wantEvents: []string{"start database", "start repository", "start service", "start events"},
+		},
+		{
+			name: "unknown dependency",
+			components: func(r *recorder) []Component {
+				return []Component{r.component("service", "database")}
+			},
+			wantErr: "unknown component database",
+		},
+		{
+			name: "cycle",
+			components: func(r *recorder) []Component {
+				return []Component{r.component("a", "b"), r.component("b", "a")}
+			},
+			wantErr: "dependency cycle: a -> b -> a",
+		},
+		{
+			name: "duplicate",
+			components: func(r *recorder) []Component {
+				return []Component{r.component("database"), r.component("database")}
+			},
+			wantErr: "added twice",
+		},
+		{
+			name: "failure stops started components",
+			components: func(r *recorder) []Component {
+				return []Component{
+					r.component("database"),
+					r.component("events"),
+					{Name: "service", DependsOn: []string{"database", "events"}, Start: func(context.Context) error {
+						return errors.New("boom")
+					}},
+				}
+			},
+			wantEvents: []string{"start database", "start events", "stop events", "stop database"},
+			wantErr:    "failed to start service",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &recorder{}
+			lc := NewLifecycle(log.NewDefault())
+			lc.Add(tt.components(r)...)
+
+			err := lc.Start(context.Background())
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("Start() error = %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("Start() error = %v, want %q", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(r.events, tt.wantEvents) {
+				t.Errorf("events = %v, want %v", r.events, tt.wantEvents)
+			}
+		})
+	}
+}
+
+func TestRunStopsLifecycleAfterHooks(t *testing.T) {
+	r := &recorder{}
+	lc := NewLifecycle(log.NewDefault())
+	lc.Add(r.component("repository", "database"), r.component("database"))
+
+	s := New("test", testConfig(), log.NewDefault(),
+		WithHealth(),
+		WithLifecycle(lc),
+		WithShutdownHook("flush", 0, func(context.Context) error {
+			r.record("hook flush")
+			return nil
+		}),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- s.Run(ctx) }()
+
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	want := []string{"start database", "start repository", "hook flush", "stop repository", "stop database"}
+	if !reflect.DeepEqual(r.events, want) {
+		t.Errorf("events = %v, want %v", r.events, want)
+	}
+}
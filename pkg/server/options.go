@@ -0,0 +1,286 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package server
+
+import (
+	"context"
+	"database/sql"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/kevindiu/monorepo-go-example/internal/auth"
+	"github.com/kevindiu/monorepo-go-example/internal/certs"
+	"github.com/kevindiu/monorepo-go-example/internal/config"
+	"github.com/kevindiu/monorepo-go-example/internal/diagnostics"
+	"github.com/kevindiu/monorepo-go-example/internal/health"
+	"github.com/kevindiu/monorepo-go-example/internal/metrics"
+	"github.com/kevindiu/monorepo-go-example/internal/ratelimit"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+)
+
+// Option configures a Server
+type Option func(*Server)
+
+// GatewayRegisterFunc registers grpc-gateway handlers that proxy to the
+// gRPC endpoint. The generated Register<Service>HandlerFromEndpoint
+// functions satisfy it.
+type GatewayRegisterFunc func(ctx context.Context, mux *runtime.ServeMux, endpoint string, opts []grpc.DialOption) error
+
+// WithGRPC serves gRPC on the configured gRPC port. register is called with
+// the server to register services; interceptors run in the order given.
+func WithGRPC(register func(*grpc.Server), interceptors ...grpc.UnaryServerInterceptor) Option {
+	return func(s *Server) {
+		s.registerGRPC = append(s.registerGRPC, register)
+		s.interceptors = append(s.interceptors, interceptors...)
+	}
+}
+
+// WithStreamInterceptors adds interceptors to streaming gRPC calls, which
+// the interceptors given to WithGRPC do not see. They run in the order
+// given.
+func WithStreamInterceptors(interceptors ...grpc.StreamServerInterceptor) Option {
+	return func(s *Server) {
+		s.streamInterceptors = append(s.streamInterceptors, interceptors...)
+	}
+}
+
+// WithHTTPGateway serves a grpc-gateway mux proxying to the gRPC server on
+// the HTTP port
+func WithHTTPGateway(register ...GatewayRegisterFunc) Option {
+	return func(s *Server) {
+		s.gateways = append(s.gateways, register...)
+	}
+}
+
+// WithHTTPHandler serves handler on the HTTP port, for binaries that build
+// their own handler instead of using WithHTTPGateway
+func WithHTTPHandler(handler http.Handler) Option {
+	return func(s *Server) {
+		s.handler = handler
+	}
+}
+
+// WithHealth serves /health/live and /health/ready on the HTTP port, with
+// /health and /ready as aliases. /health/live reports that the process is
+// alive; /health/ready runs the health checks.
+func WithHealth() Option {
+	return func(s *Server) {
+		s.health = true
+	}
+}
+
+// WithHealthCheck adds dependency checks to the readiness probe on /ready
+// and to the gRPC health service. While a critical check fails, /ready
+// responds 503 and every gRPC service reports NOT_SERVING.
+func WithHealthCheck(checkers ...health.Checker) Option {
+	return func(s *Server) {
+		s.healthChecks = append(s.healthChecks, checkers...)
+	}
+}
+
+// WithHealthCheckInterval sets how often the gRPC health service runs the
+// health checks
+func WithHealthCheckInterval(interval time.Duration) Option {
+	return func(s *Server) {
+		s.healthInterval = interval
+	}
+}
+
+// WithMetrics serves /metrics from gatherer on the HTTP port; nil uses the
+// default Prometheus registry
+func WithMetrics(gatherer prometheus.Gatherer) Option {
+	return func(s *Server) {
+		if gatherer == nil {
+			gatherer = prometheus.DefaultGatherer
+		}
+		s.metrics = gatherer
+	}
+}
+
+// WithInstrumentation records gRPC and HTTP request metrics in m and serves
+// its registry on m.Path, on m.Port when it is set. It does nothing when m
+// is disabled.
+func WithInstrumentation(m *metrics.Metrics) Option {
+	return func(s *Server) {
+		if !m.Enabled() {
+			return
+		}
+		s.instrumentation = m
+		s.metrics = m.Registry
+		s.metricsPath = m.Path
+		s.metricsPort = m.Port
+	}
+}
+
+// WithRuntimeStats logs runtime stats every interval, and the pool stats of
+// db when it is not nil. The pool stats are also exported with the request
+// metrics when instrumentation is enabled.
+func WithRuntimeStats(interval time.Duration, db *sql.DB) Option {
+	return func(s *Server) {
+		s.statsInterval = interval
+		s.statsDB = db
+	}
+}
+
+// WithDiagnostics dumps goroutine stacks, error counters, the redacted
+// configuration and sections to the log on SIGUSR2. The configuration is
+// the reloaded one when WithConfigReload is used; nil cfg omits it.
+func WithDiagnostics(cfg *config.Config, sections ...diagnostics.Section) Option {
+	return func(s *Server) {
+		s.diagnostics = true
+		s.diagConfig = cfg
+		s.diagSections = append(s.diagSections, sections...)
+	}
+}
+
+// WithAuth requires a valid bearer token on every gRPC call but those to
+// the authenticator's public methods, and enforces its authorization
+// policies on the token's roles. Authentication runs before the
+// interceptors passed to WithGRPC and WithStreamInterceptors, so they see
+// the caller's claims. It also guards the log level endpoint. A nil
+// authenticator leaves calls unauthenticated.
+func WithAuth(a *auth.Authenticator) Option {
+	return func(s *Server) {
+		s.auth = a
+	}
+}
+
+// WithTLS serves gRPC over TLS with the certificates of store, verifying
+// client certificates as its client auth mode requires, and reloads them
+// as a worker when they are rotated. The HTTP gateway proxies to the gRPC
+// server over TLS too. A nil store serves plaintext.
+func WithTLS(store *certs.Store) Option {
+	return func(s *Server) {
+		if store == nil {
+			return
+		}
+		s.certs = store
+		s.workers = append(s.workers, worker{name: "tls-reload", run: store.Run})
+	}
+}
+
+// WithRateLimit throttles gRPC calls by method with limiter, failing them
+// with ResourceExhausted once the quota of the method or its service is
+// spent. It runs before authentication so that throttled calls cost
+// little. A nil limiter leaves calls unlimited.
+func WithRateLimit(limiter *ratelimit.Limiter) Option {
+	return func(s *Server) {
+		s.limiter = limiter
+	}
+}
+
+// WithSLO tracks the success rate and latency of each gRPC method against
+// cfg and exports their error budget burn rates with the request metrics.
+// It does nothing unless cfg is enabled and instrumentation is enabled.
+func WithSLO(cfg *config.SLO) Option {
+	return func(s *Server) {
+		s.sloConfig = cfg
+	}
+}
+
+// WithChannelz serves a JSON summary of the process's gRPC channels,
+// subchannels and servers on ChannelzPath when enabled. It exposes backend
+// addresses and errors, so it should only be enabled where the HTTP port
+// is not public.
+func WithChannelz(enabled bool) Option {
+	return func(s *Server) {
+		s.channelzEnabled = enabled
+	}
+}
+
+// WithLogLevelEndpoint serves the logger's level on LogLevelPath when
+// enabled. GET returns the level and PUT {"level":"debug"} changes it.
+// With WithAuth the endpoint requires a token with the admin role.
+func WithLogLevelEndpoint(enabled bool) Option {
+	return func(s *Server) {
+		s.logLevelEndpoint = enabled
+	}
+}
+
+// WithWorker runs a background worker alongside the servers. Its context is
+// cancelled after the servers have stopped, and Run waits for it to return.
+func WithWorker(name string, run func(ctx context.Context) error) Option {
+	return func(s *Server) {
+		s.workers = append(s.workers, worker{name: name, run: run})
+	}
+}
+
+// WithShutdownTimeout bounds how long the HTTP and gRPC servers each wait
+// for in-flight requests during shutdown
+func WithShutdownTimeout(timeout time.Duration) Option {
+	return func(s *Server) {
+		s.shutdownTimeout = timeout
+	}
+}
+
+// WithWorkerShutdownTimeout bounds how long shutdown waits for workers to
+// return after they are cancelled
+func WithWorkerShutdownTimeout(timeout time.Duration) Option {
+	return func(s *Server) {
+		s.workerShutdownTimeout = timeout
+	}
+}
+
+// WithShutdownHook runs stop once the servers and workers have stopped.
+// Hooks run in reverse order of registration, each bounded by timeout; zero
+// uses DefaultHookTimeout.
+func WithShutdownHook(name string, timeout time.Duration, stop func(ctx context.Context) error) Option {
+	return func(s *Server) {
+		if timeout <= 0 {
+			timeout = DefaultHookTimeout
+		}
+		s.hooks = append(s.hooks, stage{Name: name, StopTimeout: timeout, Stop: stop})
+	}
+}
+
+// WithDrainHook runs drain as soon as shutdown begins, before the servers
+// drain. Use it to end long-lived streams, which would otherwise hold the
+// gRPC server until the shutdown timeout forces it to stop.
+func WithDrainHook(drain func()) Option {
+	return func(s *Server) {
+		s.drainHooks = append(s.drainHooks, drain)
+	}
+}
+
+// WithCloser closes c once the servers and workers have stopped, as a
+// shutdown hook
+func WithCloser(name string, c io.Closer) Option {
+	return WithShutdownHook(name, 0, func(context.Context) error {
+		return c.Close()
+	})
+}
+
+// WithLifecycle starts lc before serving, unless it is already running,
+// and stops its components after the shutdown hooks
+func WithLifecycle(lc *Lifecycle) Option {
+	return func(s *Server) {
+		s.lifecycle = lc
+	}
+}
+
+// WithLeakCheck verifies after shutdown that every stage stopped in time,
+// that the runtime stats database has no connections checked out and that
+// the goroutines started while running have exited. Run returns an error
+// describing any leak. It defaults to on in development and test mode.
+func WithLeakCheck(enabled bool) Option {
+	return func(s *Server) {
+		s.leakCheck = enabled
+	}
+}
@@ -0,0 +1,117 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package server
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/config"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/internal/log"
+	"google.golang.org/grpc"
+)
+
+// WithConfigReload watches the configuration file at path, which cfg was
+// loaded from, and applies changes without a restart. The log level and
+// the request timeout are reloaded by the server; reloaders reload the
+// settings of other components. Changes that are invalid or rejected by a
+// reloader are logged and not applied. It does nothing when path is empty.
+func WithConfigReload(path string, cfg *config.Config, reloaders ...config.Reloader) Option {
+	return func(s *Server) {
+		if path == "" {
+			return
+		}
+
+		watcher := config.NewWatcher(path, cfg, 0)
+		watcher.OnReload(s.reloadLogLevel)
+		watcher.OnReload(s.reloadRequestTimeout)
+		for _, r := range reloaders {
+			watcher.OnReload(r)
+		}
+		watcher.OnChange = func(changed []string) {
+			s.logger.Info("Configuration reloaded",
+				log.String("path", path),
+				log.String("changed", strings.Join(changed, ",")),
+			)
+		}
+		watcher.OnReject = func(err error) {
+			s.logger.Error("Rejected configuration change", log.String("path", path), log.Error(err))
+		}
+
+		s.watcher = watcher
+		s.workers = append(s.workers, worker{name: "config-watcher", run: watcher.Run})
+	}
+}
+
+// reloadLogLevel applies a changed log level
+func (s *Server) reloadLogLevel(old, new *config.Config) (func(), error) {
+	if new.Log == nil || new.Log.Level == "" || (old.Log != nil && old.Log.Level == new.Log.Level) {
+		return nil, nil
+	}
+	if s.logger.Level() == "" {
+		return nil, errors.New("log level cannot be changed without a restart")
+	}
+	return func() {
+		if err := s.logger.SetLevel(new.Log.Level); err != nil {
+			s.logger.Error("Failed to change log level", log.Error(err))
+			return
+		}
+		s.logger.Info("Log level changed", log.String("level", new.Log.Level))
+	}, nil
+}
+
+// reloadRequestTimeout applies a changed request timeout
+func (s *Server) reloadRequestTimeout(old, new *config.Config) (func(), error) {
+	if new.Server == nil {
+		return nil, nil
+	}
+	timeout := new.Server.RequestTimeout
+	if time.Duration(s.requestTimeout.Load()) == timeout {
+		return nil, nil
+	}
+	return func() {
+		s.requestTimeout.Store(int64(timeout))
+		s.logger.Info("Request timeout changed", log.Any("timeout", timeout))
+	}, nil
+}
+
+// timeoutInterceptor bounds each unary call by the current request timeout
+func (s *Server) timeoutInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if timeout := time.Duration(s.requestTimeout.Load()); timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		return handler(ctx, req)
+	}
+}
+
+// timeoutMiddleware bounds each HTTP request by the current request timeout
+func (s *Server) timeoutMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if timeout := time.Duration(s.requestTimeout.Load()); timeout > 0 {
+			ctx, cancel := context.WithTimeout(r.Context(), timeout)
+			defer cancel()
+			r = r.WithContext(ctx)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,89 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/config"
+	"github.com/kevindiu/monorepo-go-example/internal/log"
+	"google.golang.org/grpc"
+)
+
+func TestWithConfigReload(t *testing.T) {
+	if s := New("test", testConfig(), log.NewDefault(), WithConfigReload("", nil)); len(s.workers) != 0 {
+		t.Errorf("WithConfigReload without a path added %d workers", len(s.workers))
+	}
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("log:\n  level: info\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := config.LoadFromFile(path)
+	if err != nil {
+		t.Fatalf("LoadFromFile() error = %v", err)
+	}
+	logger, err := log.New(&log.Config{Level: "info"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := New("test", cfg.Server, logger, WithConfigReload(path, cfg))
+	if len(s.workers) != 1 || s.workers[0].name != "config-watcher" {
+		t.Fatalf("workers = %v, want the config watcher", s.workers)
+	}
+
+	next := *cfg
+	next.Log = &config.Log{Level: "debug"}
+	server := *cfg.Server
+	server.RequestTimeout = time.Second
+	next.Server = &server
+
+	for _, reload := range []config.Reloader{s.reloadLogLevel, s.reloadRequestTimeout} {
+		apply, err := reload(cfg, &next)
+		if err != nil {
+			t.Fatalf("reload error = %v", err)
+		}
+		apply()
+	}
+	if logger.Level() != "debug" {
+		t.Errorf("log level = %q, want debug", logger.Level())
+	}
+
+	var deadline time.Time
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		deadline, _ = ctx.Deadline()
+		return nil, nil
+	}
+	s.timeoutInterceptor()(context.Background(), nil, &grpc.UnaryServerInfo{}, handler)
+	if deadline.IsZero() || time.Until(deadline) > time.Second {
+		t.Errorf("gRPC deadline = %v, want within 1s", deadline)
+	}
+
+	deadline = time.Time{}
+	s.timeoutMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		deadline, _ = r.Context().Deadline()
+	})).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	if deadline.IsZero() {
+		t.Error("HTTP request has no deadline")
+	}
+}
@@ -0,0 +1,709 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package server runs a service's gRPC server, HTTP gateway and background
+// workers, and shuts them down in order on SIGINT or SIGTERM.
+package server
+
+import (
+	"context"
+	"database/sql"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/kevindiu/monorepo-go-example/internal/auth"
+	"github.com/kevindiu/monorepo-go-example/internal/certs"
+	"github.com/kevindiu/monorepo-go-example/internal/channelz"
+	"github.com/kevindiu/monorepo-go-example/internal/config"
+	"github.com/kevindiu/monorepo-go-example/internal/diagnostics"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/internal/health"
+	"github.com/kevindiu/monorepo-go-example/internal/leakcheck"
+	"github.com/kevindiu/monorepo-go-example/internal/log"
+	"github.com/kevindiu/monorepo-go-example/internal/metrics"
+	"github.com/kevindiu/monorepo-go-example/internal/middleware"
+	"github.com/kevindiu/monorepo-go-example/internal/ratelimit"
+	"github.com/kevindiu/monorepo-go-example/internal/runner"
+	"github.com/kevindiu/monorepo-go-example/internal/runtimestats"
+	"github.com/kevindiu/monorepo-go-example/internal/slo"
+	"github.com/kevindiu/monorepo-go-example/internal/tenant"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/reflection"
+)
+
+// ChannelzPath is the HTTP path of the channelz summary
+const ChannelzPath = "/debug/channelz"
+
+// leakCheckModes are the server modes that check for leaks after shutdown
+var leakCheckModes = map[string]bool{"development": true, "test": true}
+
+// debugModes are the server modes that expose debug surfaces: gRPC
+// reflection, request and response payload logs, and pprof. Security
+// scanners flag them in production.
+var debugModes = map[string]bool{"development": true}
+
+// PprofPath is the HTTP path prefix of the pprof profiles
+const PprofPath = "/debug/pprof/"
+
+// LogLevelPath is the HTTP path of the log level endpoint
+const LogLevelPath = "/admin/loglevel"
+
+// DefaultShutdownTimeout is how long shutdown waits for in-flight requests
+const DefaultShutdownTimeout = 30 * time.Second
+
+type worker struct {
+	name string
+	run  func(ctx context.Context) error
+}
+
+// Server runs a service until it receives a shutdown signal
+type Server struct {
+	name                  string
+	cfg                   *config.Server
+	logger                *log.Logger
+	registerGRPC          []func(*grpc.Server)
+	interceptors          []grpc.UnaryServerInterceptor
+	auth                  *auth.Authenticator
+	certs                 *certs.Store
+	limiter               *ratelimit.Limiter
+	streamInterceptors    []grpc.StreamServerInterceptor
+	gateways              []GatewayRegisterFunc
+	handler               http.Handler
+	health                bool
+	healthChecks          []health.Checker
+	healthInterval        time.Duration
+	metrics               prometheus.Gatherer
+	metricsPath           string
+	metricsPort           int
+	instrumentation       *metrics.Metrics
+	statsInterval         time.Duration
+	statsDB               *sql.DB
+	workers               []worker
+	shutdownTimeout       time.Duration
+	workerShutdownTimeout time.Duration
+	hooks                 []stage
+	drainHooks            []func()
+	watcher               *config.Watcher
+	diagnostics           bool
+	diagConfig            *config.Config
+	diagSections          []diagnostics.Section
+	errorCounts           *diagnostics.Errors
+	sloConfig             *config.SLO
+	channelzEnabled       bool
+	logLevelEndpoint      bool
+	channelz              *channelz.Inspector
+	slo                   *slo.Tracker
+	leakCheck             bool
+	debug                 bool
+	lifecycle             *Lifecycle
+	// requestTimeout is a time.Duration that the config watcher may change
+	// while the server runs
+	requestTimeout atomic.Int64
+}
+
+// New creates a server for the named service
+func New(name string, cfg *config.Server, logger *log.Logger, opts ...Option) *Server {
+	if logger == nil {
+		logger = log.NewDefault()
+	}
+
+	s := &Server{
+		name:                  name,
+		cfg:                   cfg,
+		logger:                logger,
+		metricsPath:           metrics.DefaultPath,
+		healthInterval:        DefaultHealthCheckInterval,
+		shutdownTimeout:       DefaultShutdownTimeout,
+		workerShutdownTimeout: DefaultWorkerShutdownTimeout,
+	}
+	if cfg != nil {
+		s.requestTimeout.Store(int64(cfg.RequestTimeout))
+		s.leakCheck = leakCheckModes[cfg.Mode]
+		s.debug = debugModes[cfg.Mode]
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.addRuntimeStats()
+	s.addDiagnostics()
+	s.addSLO()
+	s.addChannelz()
+
+	return s
+}
+
+// addChannelz starts the channelz inspector behind /debug/channelz when
+// enabled
+func (s *Server) addChannelz() {
+	if !s.channelzEnabled {
+		return
+	}
+	inspector, err := channelz.NewInspector()
+	if err != nil {
+		s.logger.Warn("Failed to start channelz inspector", log.Error(err))
+		return
+	}
+	s.channelz = inspector
+	s.hooks = append(s.hooks, stage{Name: "channelz", StopTimeout: DefaultHookTimeout, Stop: func(context.Context) error {
+		return inspector.Close()
+	}})
+}
+
+// addSLO tracks the SLOs of the gRPC methods when they are enabled and
+// request metrics are exported
+func (s *Server) addSLO() {
+	if s.sloConfig == nil || !s.sloConfig.Enabled || s.instrumentation == nil {
+		return
+	}
+	s.slo = slo.New(slo.FromConfig(s.sloConfig, s.instrumentation.Namespace))
+	if err := s.instrumentation.Registry.Register(s.slo); err != nil {
+		s.logger.Warn("Failed to export SLO metrics", log.Error(err))
+		s.slo = nil
+	}
+}
+
+// addDiagnostics runs the diagnostics dumper as a worker when enabled
+func (s *Server) addDiagnostics() {
+	if !s.diagnostics {
+		return
+	}
+
+	s.errorCounts = diagnostics.NewErrors()
+	sections := []diagnostics.Section{diagnostics.Goroutines(), s.errorCounts.Section()}
+	if s.diagConfig != nil {
+		current := func() *config.Config { return s.diagConfig }
+		if s.watcher != nil {
+			current = s.watcher.Current
+		}
+		sections = append(sections, diagnostics.Config(current))
+	}
+	sections = append(sections, s.diagSections...)
+
+	dumper := diagnostics.New(s.logger, sections...)
+	s.workers = append(s.workers, worker{name: "diagnostics", run: dumper.Run})
+}
+
+// addRuntimeStats runs the runtime stats reporter as a worker when stats
+// are reported or exported
+func (s *Server) addRuntimeStats() {
+	if s.statsInterval <= 0 && (s.statsDB == nil || s.instrumentation == nil) {
+		return
+	}
+
+	cfg := runtimestats.Config{Interval: s.statsInterval, Logger: s.logger}
+	if s.instrumentation != nil {
+		cfg.Registerer = s.instrumentation.Registry
+	}
+	reporter := runtimestats.New(cfg)
+	if s.statsDB != nil {
+		if err := reporter.WatchDB("database", s.statsDB); err != nil {
+			s.logger.Warn("Failed to export database pool stats", log.Error(err))
+		}
+	}
+	if s.statsInterval > 0 {
+		s.workers = append(s.workers, worker{name: "runtime-stats", run: reporter.Run})
+	}
+}
+
+// Run starts the servers and workers and blocks until ctx is cancelled, a
+// shutdown signal arrives or a server fails. Shutdown then proceeds in
+// stages, each under its own deadline: ingress stops (the gRPC health
+// service reports NOT_SERVING, the drain hooks end long-lived streams, the
+// HTTP server drains, the gRPC server drains), the workers are cancelled and awaited, and finally the shutdown
+// hooks and then the lifecycle's components release resources such as the
+// database. The lifecycle is started first if it is not yet running.
+func (s *Server) Run(ctx context.Context) error {
+	var baseline leakcheck.Snapshot
+	if s.leakCheck {
+		baseline = leakcheck.Take()
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	if s.lifecycle != nil {
+		if err := s.lifecycle.Start(ctx); err != nil {
+			runner.Shutdown(s.logger, s.hookStages())
+			return err
+		}
+	}
+
+	// One slot per listener, so that a failing listener never blocks
+	serveErr := make(chan error, 4)
+
+	grpcServer, healthReporter, err := s.startGRPC(serveErr)
+	if err != nil {
+		runner.Shutdown(s.logger, s.hookStages())
+		return err
+	}
+
+	healthCtx, cancelHealth := context.WithCancel(context.Background())
+	defer cancelHealth()
+	if healthReporter != nil {
+		go healthReporter.watch(healthCtx)
+	}
+
+	gatewayCtx, cancelGateway := context.WithCancel(context.Background())
+	defer cancelGateway()
+
+	httpServer, err := s.startHTTP(gatewayCtx, serveErr)
+	if err != nil {
+		if grpcServer != nil {
+			grpcServer.Stop()
+		}
+		runner.Shutdown(s.logger, s.hookStages())
+		return err
+	}
+
+	metricsServer, err := s.startMetrics(serveErr)
+	if err != nil {
+		if httpServer != nil {
+			httpServer.Close()
+		}
+		if grpcServer != nil {
+			grpcServer.Stop()
+		}
+		runner.Shutdown(s.logger, s.hookStages())
+		return err
+	}
+
+	workerCtx, cancelWorkers := context.WithCancel(context.Background())
+	defer cancelWorkers()
+
+	var wg sync.WaitGroup
+	for _, w := range s.workers {
+		w := w
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := w.run(workerCtx); err != nil && workerCtx.Err() == nil {
+				s.logger.Error("Worker failed", log.String("worker", w.name), log.Error(err))
+			}
+		}()
+	}
+
+	var runErr error
+	select {
+	case <-ctx.Done():
+	case runErr = <-serveErr:
+	}
+
+	s.logger.Info("Shutting down server...", log.String("service", s.name))
+
+	cancelHealth()
+	if healthReporter != nil {
+		healthReporter.shutdown()
+	}
+	for _, drain := range s.drainHooks {
+		drain()
+	}
+
+	var stages []stage
+	if httpServer != nil {
+		stages = append(stages, stage{Name: "http", StopTimeout: s.shutdownTimeout, Stop: func(ctx context.Context) error {
+			defer cancelGateway()
+			return httpServer.Shutdown(ctx)
+		}})
+	}
+	if grpcServer != nil {
+		stages = append(stages, stage{Name: "grpc", StopTimeout: s.shutdownTimeout, Stop: func(ctx context.Context) error {
+			return gracefulStop(ctx, grpcServer)
+		}})
+	}
+	if metricsServer != nil {
+		// Metrics stay scrapeable until the API listeners have drained
+		stages = append(stages, stage{Name: "metrics", StopTimeout: s.shutdownTimeout, Stop: metricsServer.Shutdown})
+	}
+	if len(s.workers) > 0 {
+		stages = append(stages, stage{Name: "workers", StopTimeout: s.workerShutdownTimeout, Stop: func(ctx context.Context) error {
+			cancelWorkers()
+			return wait(ctx, &wg)
+		}})
+	}
+	failed := runner.Shutdown(s.logger, append(stages, s.hookStages()...))
+
+	s.logger.Info("Server stopped", log.String("service", s.name))
+
+	if s.leakCheck {
+		stop()
+		if err := s.checkLeaks(baseline, failed); err != nil {
+			s.logger.Error("Shutdown left resources behind", log.Error(err))
+			if runErr == nil {
+				runErr = err
+			}
+		}
+	}
+	return runErr
+}
+
+// startGRPC listens and serves gRPC when any service is registered
+func (s *Server) startGRPC(serveErr chan<- error) (*grpc.Server, *healthReporter, error) {
+	if len(s.registerGRPC) == 0 {
+		return nil, nil, nil
+	}
+
+	// Request IDs are assigned first so that every interceptor logs them
+	interceptors := []grpc.UnaryServerInterceptor{middleware.UnaryRequestIDInterceptor(s.logger)}
+	if s.instrumentation != nil {
+		interceptors = append(interceptors, s.instrumentation.UnaryServerInterceptor())
+	}
+	if s.slo != nil {
+		interceptors = append(interceptors, s.slo.UnaryServerInterceptor())
+	}
+	if s.errorCounts != nil {
+		interceptors = append(interceptors, s.errorCounts.UnaryServerInterceptor())
+	}
+	interceptors = append(interceptors, middleware.UnaryLoggingInterceptor(s.logger))
+	if s.debug {
+		interceptors = append(interceptors, middleware.UnaryPayloadLoggingInterceptor(s.logger))
+	}
+	interceptors = append(interceptors, s.timeoutInterceptor())
+	// Errors become gRPC statuses inside the metrics, SLO and logging
+	// interceptors so that they record the codes clients receive
+	interceptors = append(interceptors, middleware.UnaryErrorInterceptor(s.logger))
+	streamInterceptors := []grpc.StreamServerInterceptor{middleware.StreamRequestIDInterceptor(s.logger)}
+	if s.instrumentation != nil {
+		streamInterceptors = append(streamInterceptors, s.instrumentation.StreamServerInterceptor())
+	}
+	streamInterceptors = append(streamInterceptors, middleware.StreamErrorInterceptor(s.logger))
+	if s.limiter != nil {
+		interceptors = append(interceptors, middleware.UnaryRateLimitInterceptor(s.limiter))
+		streamInterceptors = append(streamInterceptors, middleware.StreamRateLimitInterceptor(s.limiter))
+	}
+	if s.auth != nil {
+		interceptors = append(interceptors, auth.UnaryServerInterceptor(s.auth), auth.UnaryAuthorizationInterceptor(s.auth))
+		streamInterceptors = append(streamInterceptors, auth.StreamServerInterceptor(s.auth), auth.StreamAuthorizationInterceptor(s.auth))
+	}
+	interceptors = append(interceptors, s.interceptors...)
+	// Recovery runs innermost so that outer interceptors see panics as errors
+	interceptors = append(interceptors, middleware.UnaryRecoveryInterceptor(s.logger))
+	streamInterceptors = append(streamInterceptors, s.streamInterceptors...)
+	streamInterceptors = append(streamInterceptors, middleware.StreamRecoveryInterceptor(s.logger))
+
+	serverOpts := []grpc.ServerOption{
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(interceptors...),
+		grpc.ChainStreamInterceptor(streamInterceptors...),
+	}
+	if s.certs != nil {
+		serverOpts = append(serverOpts, grpc.Creds(credentials.NewTLS(s.certs.ServerConfig())))
+	}
+	grpcServer := grpc.NewServer(append(serverOpts, keepaliveOptions(s.cfg)...)...)
+	for _, register := range s.registerGRPC {
+		register(grpcServer)
+	}
+	healthReporter := newHealthReporter(grpcServer, s.healthChecks, s.healthInterval, s.logger)
+	if s.debug {
+		reflection.Register(grpcServer)
+	}
+	channelz.Register(grpcServer)
+
+	listener, err := net.Listen("tcp", s.cfg.GetGRPCAddr())
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to listen for gRPC")
+	}
+	listeners := []net.Listener{listener}
+	if s.cfg.GRPCSocket != "" {
+		socket, err := listenUnix(s.cfg.GRPCSocket)
+		if err != nil {
+			listener.Close()
+			return nil, nil, err
+		}
+		listeners = append(listeners, socket)
+	}
+
+	for _, l := range listeners {
+		l := l
+		go func() {
+			s.logger.Info("Starting gRPC server", log.String("address", l.Addr().String()))
+			if err := grpcServer.Serve(l); err != nil {
+				serveErr <- errors.Wrap(err, "failed to serve gRPC")
+			}
+		}()
+	}
+
+	return grpcServer, healthReporter, nil
+}
+
+// keepaliveOptions enforces the configured client ping policy and
+// connection ages
+func keepaliveOptions(cfg *config.Server) []grpc.ServerOption {
+	if cfg == nil || cfg.Keepalive == nil {
+		return nil
+	}
+	k := cfg.Keepalive
+	return []grpc.ServerOption{
+		grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+			MinTime:             k.MinTime,
+			PermitWithoutStream: k.PermitWithoutStream,
+		}),
+		grpc.KeepaliveParams(keepalive.ServerParameters{
+			MaxConnectionIdle:     k.MaxConnectionIdle,
+			MaxConnectionAge:      k.MaxConnectionAge,
+			MaxConnectionAgeGrace: k.MaxConnectionAgeGrace,
+			Time:                  k.Time,
+			Timeout:               k.Timeout,
+		}),
+	}
+}
+
+// listenUnix listens on a unix domain socket, replacing a socket left
+// behind by a previous process. The socket is removed when the listener
+// closes.
+func listenUnix(path string) (net.Listener, error) {
+	if info, err := os.Stat(path); err == nil && info.Mode()&os.ModeSocket != 0 {
+		if err := os.Remove(path); err != nil {
+			return nil, errors.Wrap(err, "failed to remove stale gRPC socket")
+		}
+	}
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to listen for gRPC on unix socket")
+	}
+	return listener, nil
+}
+
+// startHTTP listens and serves HTTP when there is anything to serve
+func (s *Server) startHTTP(ctx context.Context, serveErr chan<- error) (*http.Server, error) {
+	handler := s.handler
+	if len(s.gateways) > 0 {
+		// Forward the tenant header so the gRPC interceptor can scope the
+		// call, and the request ID so that both servers log the same one
+		mux := runtime.NewServeMux(
+			runtime.WithIncomingHeaderMatcher(tenant.HeaderMatcher),
+			runtime.WithMetadata(middleware.RequestIDMetadata),
+		)
+		opts := []grpc.DialOption{
+			grpc.WithTransportCredentials(s.loopbackCredentials()),
+			grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+		}
+		// Proxy over the unix socket when there is one, which skips TCP
+		endpoint := s.cfg.GetGRPCAddr()
+		if s.cfg.GRPCSocket != "" {
+			endpoint = "unix:" + s.cfg.GRPCSocket
+		}
+		for _, register := range s.gateways {
+			if err := register(ctx, mux, endpoint, opts); err != nil {
+				return nil, errors.Wrap(err, "failed to register gateway")
+			}
+		}
+		handler = mux
+	}
+
+	if handler == nil && !s.health && (s.metrics == nil || s.metricsPort != 0) && s.channelz == nil && !s.logLevelEndpoint {
+		return nil, nil
+	}
+
+	httpServer := &http.Server{
+		Addr:         s.cfg.GetServerAddr(),
+		Handler:      s.routes(handler),
+		ReadTimeout:  15 * time.Second,
+		WriteTimeout: 15 * time.Second,
+		IdleTimeout:  60 * time.Second,
+	}
+
+	listener, err := net.Listen("tcp", httpServer.Addr)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to listen for HTTP")
+	}
+
+	go func() {
+		s.logger.Info("Starting HTTP server", log.String("address", httpServer.Addr))
+		if err := httpServer.Serve(listener); err != nil && err != http.ErrServerClosed {
+			serveErr <- errors.Wrap(err, "failed to serve HTTP")
+		}
+	}()
+
+	return httpServer, nil
+}
+
+// loopbackCredentials returns the credentials the gateway proxies to the
+// gRPC server with. Over TLS it presents the server's own certificate and
+// verifies the server as localhost, unless a server name is configured.
+func (s *Server) loopbackCredentials() credentials.TransportCredentials {
+	if s.certs == nil {
+		return insecure.NewCredentials()
+	}
+	tlsConfig := s.certs.ClientConfig()
+	if tlsConfig.ServerName == "" {
+		tlsConfig.ServerName = "localhost"
+	}
+	return credentials.NewTLS(tlsConfig)
+}
+
+// startMetrics serves metrics on their own port when one is configured
+func (s *Server) startMetrics(serveErr chan<- error) (*http.Server, error) {
+	if s.metrics == nil || s.metricsPort == 0 {
+		return nil, nil
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(s.metricsPath, promhttp.HandlerFor(s.metrics, promhttp.HandlerOpts{}))
+	metricsServer := &http.Server{
+		Addr:         net.JoinHostPort(s.cfg.Host, strconv.Itoa(s.metricsPort)),
+		Handler:      mux,
+		ReadTimeout:  15 * time.Second,
+		WriteTimeout: 15 * time.Second,
+		IdleTimeout:  60 * time.Second,
+	}
+
+	listener, err := net.Listen("tcp", metricsServer.Addr)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to listen for metrics")
+	}
+
+	go func() {
+		s.logger.Info("Starting metrics server", log.String("address", metricsServer.Addr))
+		if err := metricsServer.Serve(listener); err != nil && err != http.ErrServerClosed {
+			serveErr <- errors.Wrap(err, "failed to serve metrics")
+		}
+	}()
+
+	return metricsServer, nil
+}
+
+// routes adds the health and metrics endpoints in front of handler. Metrics
+// are left out when they have a port of their own.
+func (s *Server) routes(handler http.Handler) http.Handler {
+	var metricsHandler http.Handler
+	if s.metrics != nil && s.metricsPort == 0 {
+		metricsHandler = promhttp.HandlerFor(s.metrics, promhttp.HandlerOpts{})
+	}
+	if handler != nil {
+		// Spans are named after the route so that IDs do not split them
+		handler = otelhttp.NewHandler(handler, s.name,
+			otelhttp.WithSpanNameFormatter(func(operation string, r *http.Request) string {
+				return r.Method + " " + metrics.Route(r.URL.Path)
+			}),
+		)
+	}
+	if handler != nil {
+		handler = s.timeoutMiddleware(handler)
+	}
+	if handler != nil && s.errorCounts != nil {
+		handler = s.errorCounts.Middleware(handler)
+	}
+	if handler != nil && s.instrumentation != nil {
+		handler = s.instrumentation.Middleware(handler)
+	}
+	if handler != nil {
+		handler = middleware.RequestID(handler)
+	}
+
+	ready := health.Handler(s.healthChecks)
+	var channelzHandler http.Handler
+	if s.channelz != nil {
+		channelzHandler = s.channelz.Handler()
+	}
+	var pprofHandler http.Handler
+	if s.debug {
+		pprofHandler = newPprofHandler()
+	}
+	var logLevelHandler http.Handler
+	if s.logLevelEndpoint {
+		logLevelHandler = s.logLevelHandler()
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case s.health && (r.URL.Path == "/health" || r.URL.Path == health.LivePath):
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"status":"ok"}`))
+			s.logger.Debug("Health check", log.String("path", r.URL.Path))
+		case s.health && (r.URL.Path == "/ready" || r.URL.Path == health.ReadyPath):
+			ready.ServeHTTP(w, r)
+		case metricsHandler != nil && r.URL.Path == s.metricsPath:
+			metricsHandler.ServeHTTP(w, r)
+		case channelzHandler != nil && r.URL.Path == ChannelzPath:
+			channelzHandler.ServeHTTP(w, r)
+		case logLevelHandler != nil && r.URL.Path == LogLevelPath:
+			logLevelHandler.ServeHTTP(w, r)
+		case pprofHandler != nil && strings.HasPrefix(r.URL.Path, PprofPath):
+			pprofHandler.ServeHTTP(w, r)
+		case handler != nil:
+			handler.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// logLevelHandler serves the logger's level, logs each change, and requires
+// an admin token when the server authenticates calls
+func (s *Server) logLevelHandler() http.Handler {
+	level := s.logger.LevelHandler()
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		previous := s.logger.Level()
+		level.ServeHTTP(w, r)
+		if current := s.logger.Level(); current != previous {
+			s.logger.Warn("Log level changed",
+				log.String("from", previous),
+				log.String("to", current),
+				log.String("remote_addr", r.RemoteAddr),
+			)
+		}
+	})
+	if s.auth != nil {
+		handler = auth.Middleware(s.auth, auth.RequireRole(auth.RoleAdmin, handler))
+	}
+	return handler
+}
+
+// newPprofHandler serves the pprof profiles under PprofPath
+func newPprofHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(PprofPath, pprof.Index)
+	mux.HandleFunc(PprofPath+"cmdline", pprof.Cmdline)
+	mux.HandleFunc(PprofPath+"profile", pprof.Profile)
+	mux.HandleFunc(PprofPath+"symbol", pprof.Symbol)
+	mux.HandleFunc(PprofPath+"trace", pprof.Trace)
+	return mux
+}
+
+// gracefulStop drains the gRPC server, forcing it to stop if ctx expires
+func gracefulStop(ctx context.Context, grpcServer *grpc.Server) error {
+	done := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		grpcServer.Stop()
+		return ctx.Err()
+	}
+}
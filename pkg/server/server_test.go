@@ -0,0 +1,586 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package server
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/auth"
+	"github.com/kevindiu/monorepo-go-example/internal/config"
+	"github.com/kevindiu/monorepo-go-example/internal/health"
+	"github.com/kevindiu/monorepo-go-example/internal/log"
+	"github.com/kevindiu/monorepo-go-example/internal/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/keepalive"
+)
+
+func testConfig() *config.Server {
+	return &config.Server{Host: "127.0.0.1", Port: 0, GRPCPort: 0, Mode: "test"}
+}
+
+func TestRoutes(t *testing.T) {
+	app := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+	failing := health.Checker{Name: "database", Critical: true, Check: func(ctx context.Context) error {
+		return errors.New("connection refused")
+	}}
+
+	authenticator, err := auth.New(&config.Auth{Secret: "0123456789abcdef0123456789abcdef", TokenTTL: time.Minute})
+	if err != nil {
+		t.Fatalf("auth.New() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		opts     []Option
+		path     string
+		wantCode int
+	}{
+		{name: "health", opts: []Option{WithHealth()}, path: "/health", wantCode: http.StatusOK},
+		{name: "ready", opts: []Option{WithHealth()}, path: "/ready", wantCode: http.StatusOK},
+		{name: "live", opts: []Option{WithHealth()}, path: health.LivePath, wantCode: http.StatusOK},
+		{name: "ready split", opts: []Option{WithHealth(), WithHealthCheck(failing)}, path: health.ReadyPath, wantCode: http.StatusServiceUnavailable},
+		{name: "ready check fails", opts: []Option{WithHealth(), WithHealthCheck(failing)}, path: "/ready", wantCode: http.StatusServiceUnavailable},
+		{name: "health ignores checks", opts: []Option{WithHealth(), WithHealthCheck(failing)}, path: "/health", wantCode: http.StatusOK},
+		{name: "health disabled", opts: []Option{WithHTTPHandler(app)}, path: "/health", wantCode: http.StatusTeapot},
+		{name: "metrics", opts: []Option{WithMetrics(prometheus.NewRegistry())}, path: "/metrics", wantCode: http.StatusOK},
+		{name: "custom metrics path", opts: []Option{WithInstrumentation(metrics.New(&config.Observability{MetricsEnabled: true, MetricsPath: "/internal/metrics"}))}, path: "/internal/metrics", wantCode: http.StatusOK},
+		{name: "metrics disabled", opts: []Option{WithHTTPHandler(app), WithInstrumentation(metrics.New(&config.Observability{}))}, path: "/metrics", wantCode: http.StatusTeapot},
+		{name: "metrics on own port", opts: []Option{WithHTTPHandler(app), WithInstrumentation(metrics.New(&config.Observability{MetricsEnabled: true, MetricsPort: 9090}))}, path: "/metrics", wantCode: http.StatusTeapot},
+		{name: "handler", opts: []Option{WithHealth(), WithHTTPHandler(app)}, path: "/v1/orders", wantCode: http.StatusTeapot},
+		{name: "no handler", opts: []Option{WithHealth()}, path: "/v1/orders", wantCode: http.StatusNotFound},
+		{name: "channelz", opts: []Option{WithChannelz(true)}, path: ChannelzPath, wantCode: http.StatusOK},
+		{name: "channelz disabled", opts: []Option{WithHealth(), WithChannelz(false)}, path: ChannelzPath, wantCode: http.StatusNotFound},
+		{name: "log level", opts: []Option{WithLogLevelEndpoint(true)}, path: LogLevelPath, wantCode: http.StatusOK},
+		{name: "log level with auth", opts: []Option{WithLogLevelEndpoint(true), WithAuth(authenticator)}, path: LogLevelPath, wantCode: http.StatusUnauthorized},
+		{name: "log level disabled", opts: []Option{WithHealth(), WithLogLevelEndpoint(false)}, path: LogLevelPath, wantCode: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := New("test", testConfig(), log.NewDefault(), tt.opts...)
+			rec := httptest.NewRecorder()
+			s.routes(s.handler).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if rec.Code != tt.wantCode {
+				t.Errorf("GET %s = %d, want %d", tt.path, rec.Code, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestDebugSurfaces(t *testing.T) {
+	tests := []struct {
+		mode     string
+		wantCode int
+	}{
+		{mode: "development", wantCode: http.StatusOK},
+		{mode: "production", wantCode: http.StatusNotFound},
+		{mode: "", wantCode: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			cfg := testConfig()
+			cfg.Mode = tt.mode
+			s := New("test", cfg, log.NewDefault(), WithHealth())
+			if s.debug != (tt.wantCode == http.StatusOK) {
+				t.Errorf("debug = %v in %q mode", s.debug, tt.mode)
+			}
+
+			rec := httptest.NewRecorder()
+			s.routes(s.handler).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, PprofPath, nil))
+			if rec.Code != tt.wantCode {
+				t.Errorf("GET %s = %d, want %d", PprofPath, rec.Code, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestRunStopsWorkersAfterServers(t *testing.T) {
+	var mu sync.Mutex
+	var stopped []string
+
+	worker := func(name string) func(ctx context.Context) error {
+		return func(ctx context.Context) error {
+			<-ctx.Done()
+			mu.Lock()
+			stopped = append(stopped, name)
+			mu.Unlock()
+			return nil
+		}
+	}
+
+	registered := false
+	s := New("test", testConfig(), log.NewDefault(),
+		WithGRPC(func(*grpc.Server) { registered = true }),
+		WithHealth(),
+		WithWorker("a", worker("a")),
+		WithWorker("b", worker("b")),
+		WithShutdownTimeout(time.Second),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- s.Run(ctx) }()
+
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run() did not return after cancellation")
+	}
+
+	if !registered {
+		t.Error("Run() did not register the gRPC services")
+	}
+	if len(stopped) != 2 {
+		t.Errorf("Run() returned before workers stopped, stopped = %v", stopped)
+	}
+}
+
+func TestRunFailsWhenPortTaken(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	defer listener.Close()
+
+	cfg := testConfig()
+	cfg.Port = listener.Addr().(*net.TCPAddr).Port
+
+	s := New("test", cfg, log.NewDefault(), WithHealth())
+	if err := s.Run(context.Background()); err == nil {
+		t.Error("Run() should fail when the HTTP port is in use")
+	}
+}
+
+func TestRunShutsDownInOrder(t *testing.T) {
+	var mu sync.Mutex
+	var order []string
+	record := func(name string) {
+		mu.Lock()
+		order = append(order, name)
+		mu.Unlock()
+	}
+	hook := func(name string) func(ctx context.Context) error {
+		return func(ctx context.Context) error {
+			record(name)
+			return nil
+		}
+	}
+
+	s := New("test", testConfig(), log.NewDefault(),
+		WithHealth(),
+		WithWorker("worker", func(ctx context.Context) error {
+			<-ctx.Done()
+			record("worker")
+			return nil
+		}),
+		WithShutdownHook("database", 0, hook("database")),
+		WithShutdownHook("events", 0, hook("events")),
+		WithDrainHook(func() { record("drain") }),
+		// A stuck hook is abandoned at its deadline
+		WithShutdownHook("stuck", 10*time.Millisecond, func(ctx context.Context) error {
+			select {}
+		}),
+		WithShutdownTimeout(time.Second),
+		// The stuck hook leaks its goroutine on purpose
+		WithLeakCheck(false),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- s.Run(ctx) }()
+
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run() did not return after cancellation")
+	}
+
+	want := []string{"drain", "worker", "events", "database"}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(order) != len(want) {
+		t.Fatalf("shutdown order = %v, want %v", order, want)
+	}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Fatalf("shutdown order = %v, want %v", order, want)
+		}
+	}
+}
+
+func TestRunServesMetricsOnOwnPort(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+
+	m := metrics.New(&config.Observability{MetricsEnabled: true, MetricsPath: "/metrics", MetricsPort: port})
+	s := New("test", testConfig(), log.NewDefault(), WithHealth(), WithInstrumentation(m))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- s.Run(ctx) }()
+
+	url := fmt.Sprintf("http://127.0.0.1:%d/metrics", port)
+	var resp *http.Response
+	for i := 0; i < 50; i++ {
+		if resp, err = http.Get(url); err == nil {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if err != nil {
+		t.Fatalf("GET %s error = %v", url, err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("GET %s = %d, want %d", url, resp.StatusCode, http.StatusOK)
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run() did not return after cancellation")
+	}
+}
+
+func TestRunReleasesResourcesWhenStartFails(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	defer listener.Close()
+
+	cfg := testConfig()
+	cfg.Port = listener.Addr().(*net.TCPAddr).Port
+
+	closed := false
+	s := New("test", cfg, log.NewDefault(),
+		WithHealth(),
+		WithShutdownHook("database", 0, func(ctx context.Context) error {
+			closed = true
+			return nil
+		}),
+	)
+	if err := s.Run(context.Background()); err == nil {
+		t.Fatal("Run() should fail when the HTTP port is in use")
+	}
+	if !closed {
+		t.Error("Run() did not run the shutdown hooks after a failed start")
+	}
+}
+
+func TestRuntimeStats(t *testing.T) {
+	m := metrics.New(&config.Observability{MetricsEnabled: true})
+	db := sql.OpenDB(nopConnector{})
+	defer db.Close()
+
+	tests := []struct {
+		name       string
+		opts       []Option
+		wantWorker bool
+	}{
+		{name: "disabled", opts: []Option{WithInstrumentation(m)}},
+		{name: "export only", opts: []Option{WithInstrumentation(m), WithRuntimeStats(0, db)}},
+		{name: "reports", opts: []Option{WithRuntimeStats(time.Minute, nil)}, wantWorker: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := New("test", testConfig(), log.NewDefault(), tt.opts...)
+			if got := len(s.workers) == 1; got != tt.wantWorker {
+				t.Errorf("runtime stats worker added = %v, want %v", got, tt.wantWorker)
+			}
+		})
+	}
+
+	rec := httptest.NewRecorder()
+	promhttp.HandlerFor(m.Registry, promhttp.HandlerOpts{}).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if !strings.Contains(rec.Body.String(), `go_sql_open_connections{db_name="database"}`) {
+		t.Error("database pool stats were not exported")
+	}
+}
+
+// nopConnector opens a database that never connects, which is enough for
+// pool stats
+type nopConnector struct{}
+
+func (nopConnector) Connect(context.Context) (driver.Conn, error) {
+	return nil, errors.New("not connected")
+}
+
+func (nopConnector) Driver() driver.Driver { return nil }
+
+// openConnector opens connections that do nothing, so that a test can hold
+// one checked out
+type openConnector struct{}
+
+func (openConnector) Connect(context.Context) (driver.Conn, error) { return openConn{}, nil }
+
+func (openConnector) Driver() driver.Driver { return nil }
+
+type openConn struct{}
+
+func (openConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+
+func (openConn) Close() error { return nil }
+
+func (openConn) Begin() (driver.Tx, error) { return nil, errors.New("not supported") }
+
+func TestRunChecksForLeaks(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	tests := []struct {
+		name    string
+		opts    func(db *sql.DB) []Option
+		wantErr string
+	}{
+		{
+			name: "clean",
+			opts: func(db *sql.DB) []Option {
+				return []Option{WithRuntimeStats(0, db), WithWorker("worker", func(ctx context.Context) error {
+					<-ctx.Done()
+					return nil
+				})}
+			},
+		},
+		{
+			name: "goroutine",
+			opts: func(*sql.DB) []Option {
+				return []Option{WithWorker("worker", func(ctx context.Context) error {
+					go func() { <-release }()
+					<-ctx.Done()
+					return nil
+				})}
+			},
+			wantErr: "goroutines leaked",
+		},
+		{
+			name: "connection",
+			opts: func(db *sql.DB) []Option {
+				return []Option{WithRuntimeStats(0, db), WithWorker("worker", func(ctx context.Context) error {
+					if _, err := db.Conn(ctx); err != nil {
+						return err
+					}
+					<-ctx.Done()
+					return nil
+				})}
+			},
+			wantErr: "database connections still in use",
+		},
+		{
+			name: "stuck stage",
+			opts: func(*sql.DB) []Option {
+				return []Option{WithShutdownHook("stuck", 10*time.Millisecond, func(ctx context.Context) error {
+					<-release
+					return nil
+				})}
+			},
+			wantErr: "stages did not stop: stuck",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := sql.OpenDB(openConnector{})
+			defer db.Close()
+
+			s := New("test", testConfig(), log.NewDefault(), append(tt.opts(db), WithHealth())...)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			done := make(chan error, 1)
+			go func() { done <- s.Run(ctx) }()
+			time.Sleep(50 * time.Millisecond)
+			cancel()
+
+			err := <-done
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Run() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Run() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDiagnostics(t *testing.T) {
+	if s := New("test", testConfig(), log.NewDefault()); s.errorCounts != nil {
+		t.Error("diagnostics should be disabled by default")
+	}
+
+	failing := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	s := New("test", testConfig(), log.NewDefault(), WithHTTPHandler(failing), WithDiagnostics(nil))
+	if len(s.workers) != 1 || s.workers[0].name != "diagnostics" {
+		t.Fatalf("workers = %v, want the diagnostics dumper", s.workers)
+	}
+
+	s.routes(s.handler).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/v1/orders", nil))
+	var dump strings.Builder
+	s.errorCounts.Section().Write(&dump)
+	if !strings.Contains(dump.String(), "http 500 total=1") {
+		t.Errorf("error counters = %q, want the failed request", dump.String())
+	}
+}
+
+func TestSLO(t *testing.T) {
+	cfg := &config.SLO{Enabled: true, Availability: 0.999, LatencyThreshold: time.Second, LatencyTarget: 0.99, Windows: []time.Duration{time.Hour}}
+	enabled := metrics.New(&config.Observability{MetricsEnabled: true})
+
+	tests := []struct {
+		name string
+		opts []Option
+		want bool
+	}{
+		{name: "enabled", opts: []Option{WithInstrumentation(enabled), WithSLO(cfg)}, want: true},
+		{name: "metrics disabled", opts: []Option{WithInstrumentation(metrics.New(&config.Observability{})), WithSLO(cfg)}},
+		{name: "slo disabled", opts: []Option{WithInstrumentation(metrics.New(&config.Observability{MetricsEnabled: true})), WithSLO(&config.SLO{})}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := New("test", testConfig(), log.NewDefault(), tt.opts...)
+			if got := s.slo != nil; got != tt.want {
+				t.Errorf("SLO tracked = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunServesOnUnixSocket(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "grpc.sock")
+	// A socket left behind by a previous process is replaced
+	stale, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stale.(*net.UnixListener).SetUnlinkOnClose(false)
+	stale.Close()
+
+	cfg := testConfig()
+	cfg.GRPCSocket = socket
+	s := New("test", cfg, log.NewDefault(), WithGRPC(func(*grpc.Server) {}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- s.Run(ctx) }()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	dialCtx, cancelDial := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelDial()
+	conn, err := grpc.DialContext(dialCtx, "unix://"+socket,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithBlock(),
+	)
+	if err != nil {
+		t.Fatalf("failed to dial the unix socket: %v", err)
+	}
+	defer conn.Close()
+
+	resp, err := healthpb.NewHealthClient(conn).Check(dialCtx, &healthpb.HealthCheckRequest{})
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if resp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("Check() = %v, want SERVING", resp.GetStatus())
+	}
+}
+
+func TestRunServesWithKeepalive(t *testing.T) {
+	if opts := keepaliveOptions(testConfig()); len(opts) != 0 {
+		t.Errorf("keepaliveOptions() without keepalive config = %d options, want none", len(opts))
+	}
+
+	socket := filepath.Join(t.TempDir(), "grpc.sock")
+	cfg := testConfig()
+	cfg.GRPCSocket = socket
+	cfg.Keepalive = &config.Keepalive{Time: 30 * time.Second, Timeout: 10 * time.Second, PermitWithoutStream: true, MinTime: 15 * time.Second}
+	if opts := keepaliveOptions(cfg); len(opts) != 2 {
+		t.Fatalf("keepaliveOptions() = %d options, want 2", len(opts))
+	}
+	s := New("test", cfg, log.NewDefault(), WithGRPC(func(*grpc.Server) {}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- s.Run(ctx) }()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	dialCtx, cancelDial := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelDial()
+	conn, err := grpc.DialContext(dialCtx, "unix://"+socket,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithKeepaliveParams(keepalive.ClientParameters{Time: 30 * time.Second, PermitWithoutStream: true}),
+		grpc.WithBlock(),
+	)
+	if err != nil {
+		t.Fatalf("failed to dial the server: %v", err)
+	}
+	defer conn.Close()
+
+	if _, err := healthpb.NewHealthClient(conn).Check(dialCtx, &healthpb.HealthCheckRequest{}); err != nil {
+		t.Errorf("Check() error = %v", err)
+	}
+}
@@ -0,0 +1,99 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package server
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/internal/leakcheck"
+	"github.com/kevindiu/monorepo-go-example/internal/runner"
+)
+
+// DefaultWorkerShutdownTimeout is how long shutdown waits for workers to
+// return after they are cancelled
+const DefaultWorkerShutdownTimeout = 30 * time.Second
+
+// DefaultHookTimeout bounds a shutdown hook that does not set its own timeout
+const DefaultHookTimeout = runner.DefaultStopTimeout
+
+// leakCheckTimeout is how long the leak check waits for goroutines to exit
+const leakCheckTimeout = 2 * time.Second
+
+// stage is a step of shutdown, run by runner.Shutdown under its own
+// deadline
+type stage = runner.Component
+
+// checkLeaks reports the stages that did not stop, database connections
+// still checked out and goroutines started by Run that are still running
+func (s *Server) checkLeaks(baseline leakcheck.Snapshot, failed []string) error {
+	var problems []string
+	if len(failed) > 0 {
+		problems = append(problems, "stages did not stop: "+strings.Join(failed, ", "))
+	}
+	if s.statsDB != nil {
+		if err := leakcheck.Conns(map[string]*sql.DB{"database": s.statsDB}); err != nil {
+			problems = append(problems, err.Error())
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), leakCheckTimeout)
+	defer cancel()
+	if leaked := leakcheck.Goroutines(ctx, baseline); len(leaked) > 0 {
+		problems = append(problems, fmt.Sprintf("%d goroutines leaked:\n%s", len(leaked), strings.Join(leaked, "\n\n")))
+	}
+
+	if len(problems) > 0 {
+		return errors.New(strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// hookStages returns the shutdown hooks in reverse order of registration,
+// so that resources are released after everything registered later that
+// may depend on them, followed by the lifecycle's components
+func (s *Server) hookStages() []stage {
+	stages := make([]stage, 0, len(s.hooks))
+	for i := len(s.hooks) - 1; i >= 0; i-- {
+		stages = append(stages, s.hooks[i])
+	}
+	if s.lifecycle != nil {
+		stages = append(stages, s.lifecycle.stages()...)
+	}
+	return stages
+}
+
+// wait waits for wg until ctx is done
+func wait(ctx context.Context, wg *sync.WaitGroup) error {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
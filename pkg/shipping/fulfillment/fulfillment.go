@@ -0,0 +1,84 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package fulfillment creates shipments for orders as they are shipped
+package fulfillment
+
+import (
+	"context"
+
+	shippingv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/shipping/v1"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/internal/events"
+	"github.com/kevindiu/monorepo-go-example/internal/log"
+	orderevents "github.com/kevindiu/monorepo-go-example/pkg/order/events"
+)
+
+// Group is the consumer group used by the fulfillment consumer
+const Group = "shipping-fulfillment"
+
+// Shipments creates shipments
+type Shipments interface {
+	CreateShipment(ctx context.Context, req *shippingv1.CreateShipmentRequest) (*shippingv1.CreateShipmentResponse, error)
+}
+
+// Consumer creates a shipment whenever an order moves to shipped
+type Consumer struct {
+	shipments Shipments
+	logger    *log.Logger
+}
+
+// New creates a new consumer
+func New(shipments Shipments, logger *log.Logger) *Consumer {
+	if logger == nil {
+		logger = log.NewDefault()
+	}
+	return &Consumer{
+		shipments: shipments,
+		logger:    logger.Named("fulfillment"),
+	}
+}
+
+// Run consumes order events until ctx is cancelled
+func (c *Consumer) Run(ctx context.Context, subscriber events.Subscriber) error {
+	c.logger.Info("Consuming order events", log.String("topic", orderevents.Topic))
+	return subscriber.Subscribe(ctx, orderevents.Topic, Group, c.Handle)
+}
+
+// Handle creates a shipment for a shipped order using the default carrier.
+// An order only ever has one shipment, so redelivered events are ignored.
+func (c *Consumer) Handle(ctx context.Context, event *events.Event) error {
+	if event.Type != orderevents.TypeOrderStatusChanged {
+		return nil
+	}
+
+	var order orderevents.Order
+	if err := event.Decode(&order); err != nil {
+		// A malformed event will never decode, so do not ask for redelivery
+		c.logger.Error("Dropping malformed order event", log.String("event_id", event.ID), log.Error(err))
+		return nil
+	}
+	if order.Status != "shipped" {
+		return nil
+	}
+
+	_, err := c.shipments.CreateShipment(ctx, &shippingv1.CreateShipmentRequest{OrderId: order.ID})
+	if errors.GetCode(err) == errors.CodeConflict {
+		c.logger.Debug("Order already has a shipment", log.String("order_id", order.ID))
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,76 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package fulfillment
+
+import (
+	"context"
+	"testing"
+
+	shippingv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/shipping/v1"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/internal/events"
+	orderevents "github.com/kevindiu/monorepo-go-example/pkg/order/events"
+)
+
+// mockShipments records created shipments
+type mockShipments struct {
+	created map[string]bool
+}
+
+func (m *mockShipments) CreateShipment(ctx context.Context, req *shippingv1.CreateShipmentRequest) (*shippingv1.CreateShipmentResponse, error) {
+	if m.created[req.GetOrderId()] {
+		return nil, errors.WithCode(errors.New("order already has a shipment"), errors.CodeConflict)
+	}
+	m.created[req.GetOrderId()] = true
+	return &shippingv1.CreateShipmentResponse{}, nil
+}
+
+func TestHandle(t *testing.T) {
+	shipments := &mockShipments{created: make(map[string]bool)}
+	c := New(shipments, nil)
+
+	tests := []struct {
+		name      string
+		eventType string
+		order     orderevents.Order
+		wantOrder string
+	}{
+		{name: "shipped", eventType: orderevents.TypeOrderStatusChanged, order: orderevents.Order{ID: "o1", Status: "shipped"}, wantOrder: "o1"},
+		{name: "redelivered", eventType: orderevents.TypeOrderStatusChanged, order: orderevents.Order{ID: "o1", Status: "shipped"}},
+		{name: "confirmed", eventType: orderevents.TypeOrderStatusChanged, order: orderevents.Order{ID: "o2", Status: "confirmed"}},
+		{name: "other event", eventType: orderevents.TypeOrderCreated, order: orderevents.Order{ID: "o3", Status: "shipped"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event, err := events.NewEvent(tt.eventType, tt.order.ID, tt.order)
+			if err != nil {
+				t.Fatalf("NewEvent() error = %v", err)
+			}
+			if err := c.Handle(context.Background(), event); err != nil {
+				t.Fatalf("Handle() error = %v", err)
+			}
+			if tt.wantOrder != "" && !shipments.created[tt.wantOrder] {
+				t.Errorf("Handle() did not create a shipment for %s", tt.wantOrder)
+			}
+		})
+	}
+
+	if len(shipments.created) != 1 {
+		t.Errorf("created %d shipments, want 1", len(shipments.created))
+	}
+}
@@ -0,0 +1,369 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kevindiu/monorepo-go-example/internal/db"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+)
+
+// Shipment statuses
+const (
+	StatusLabelCreated   = "label_created"
+	StatusInTransit      = "in_transit"
+	StatusOutForDelivery = "out_for_delivery"
+	StatusDelivered      = "delivered"
+	StatusException      = "exception"
+)
+
+// progress orders the statuses a shipment moves through
+var progress = map[string]int{
+	StatusLabelCreated:   1,
+	StatusInTransit:      2,
+	StatusOutForDelivery: 3,
+	StatusDelivered:      4,
+}
+
+// CanTransition reports whether a shipment in status from may move to status
+// to. Carriers deliver tracking updates out of order, so a shipment never
+// moves backwards; an exception can be raised and cleared at any point
+// before delivery, and delivered is final.
+func CanTransition(from, to string) bool {
+	if from == StatusDelivered {
+		return false
+	}
+	if to == StatusException {
+		return from != StatusException
+	}
+	toRank, ok := progress[to]
+	if !ok {
+		return false
+	}
+	if from == StatusException {
+		return toRank > progress[StatusLabelCreated]
+	}
+	return toRank > progress[from]
+}
+
+// Shipment represents the shipment of an order
+type Shipment struct {
+	ID             string
+	OrderID        string
+	Carrier        string
+	TrackingNumber string
+	Status         string
+	DeliveredAt    *time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// Event represents a tracking update reported by a carrier
+type Event struct {
+	ID          string
+	ShipmentID  string
+	Status      string
+	Location    string
+	Description string
+	OccurredAt  time.Time
+	CreatedAt   time.Time
+}
+
+// Filter narrows down List
+type Filter struct {
+	OrderID string
+	Status  string
+}
+
+// Repository defines the shipment repository interface
+type Repository interface {
+	Create(ctx context.Context, shipment *Shipment) error
+	GetByID(ctx context.Context, id string) (*Shipment, error)
+	GetByOrderID(ctx context.Context, orderID string) (*Shipment, error)
+	GetByTrackingNumber(ctx context.Context, carrier, trackingNumber string) (*Shipment, error)
+	List(ctx context.Context, filter Filter, limit, offset int) ([]*Shipment, error)
+	ListEvents(ctx context.Context, shipmentID string) ([]*Event, error)
+	RecordEvent(ctx context.Context, event *Event) (*Shipment, error)
+}
+
+type repository struct {
+	db *db.DB
+}
+
+// New creates a new shipment repository
+func New(database *db.DB) Repository {
+	return &repository{
+		db: database,
+	}
+}
+
+const shipmentColumns = `id, order_id, carrier, tracking_number, status, delivered_at, created_at, updated_at`
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanShipment(s scanner) (*Shipment, error) {
+	var shipment Shipment
+	err := s.Scan(
+		&shipment.ID,
+		&shipment.OrderID,
+		&shipment.Carrier,
+		&shipment.TrackingNumber,
+		&shipment.Status,
+		&shipment.DeliveredAt,
+		&shipment.CreatedAt,
+		&shipment.UpdatedAt,
+	)
+	return &shipment, err
+}
+
+// Create creates a shipment together with its label_created tracking event.
+// An order has at most one shipment, so creating a second one is a conflict.
+func (r *repository) Create(ctx context.Context, shipment *Shipment) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO shipments (id, order_id, carrier, tracking_number, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $6)
+		ON CONFLICT (order_id) DO NOTHING
+	`
+
+	now := time.Now()
+	shipment.ID = uuid.New().String()
+	shipment.Status = StatusLabelCreated
+	shipment.CreatedAt = now
+	shipment.UpdatedAt = now
+
+	result, err := tx.ExecContext(ctx, query,
+		shipment.ID,
+		shipment.OrderID,
+		shipment.Carrier,
+		shipment.TrackingNumber,
+		shipment.Status,
+		now,
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to create shipment")
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "failed to get affected rows")
+	}
+	if rows == 0 {
+		return errors.WithCode(errors.New("order already has a shipment"), errors.CodeConflict)
+	}
+
+	event := &Event{
+		ShipmentID:  shipment.ID,
+		Status:      StatusLabelCreated,
+		Description: "Shipping label created",
+		OccurredAt:  now,
+	}
+	if err := insertEvent(ctx, tx, event); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(err, "failed to commit transaction")
+	}
+
+	return nil
+}
+
+// GetByID retrieves a shipment by ID
+func (r *repository) GetByID(ctx context.Context, id string) (*Shipment, error) {
+	query := `SELECT ` + shipmentColumns + ` FROM shipments WHERE id = $1`
+	return r.get(ctx, query, id)
+}
+
+// GetByOrderID retrieves the shipment of an order
+func (r *repository) GetByOrderID(ctx context.Context, orderID string) (*Shipment, error) {
+	query := `SELECT ` + shipmentColumns + ` FROM shipments WHERE order_id = $1`
+	return r.get(ctx, query, orderID)
+}
+
+// GetByTrackingNumber retrieves a shipment by its carrier tracking number
+func (r *repository) GetByTrackingNumber(ctx context.Context, carrier, trackingNumber string) (*Shipment, error) {
+	query := `SELECT ` + shipmentColumns + ` FROM shipments WHERE carrier = $1 AND tracking_number = $2`
+	return r.get(ctx, query, carrier, trackingNumber)
+}
+
+func (r *repository) get(ctx context.Context, query string, args ...interface{}) (*Shipment, error) {
+	shipment, err := scanShipment(r.db.Executor(ctx).QueryRowContext(ctx, query, args...))
+	if err == sql.ErrNoRows {
+		return nil, errors.WithCode(errors.New("shipment not found"), errors.CodeNotFound)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get shipment")
+	}
+
+	return shipment, nil
+}
+
+// List retrieves shipments with pagination, newest first
+func (r *repository) List(ctx context.Context, filter Filter, limit, offset int) ([]*Shipment, error) {
+	query := `
+		SELECT ` + shipmentColumns + `
+		FROM shipments
+		WHERE ($1 = '' OR order_id::text = $1)
+		  AND ($2 = '' OR status = $2)
+		ORDER BY created_at DESC
+		LIMIT $3 OFFSET $4
+	`
+
+	rows, err := r.db.Executor(ctx).QueryContext(ctx, query, filter.OrderID, filter.Status, limit, offset)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list shipments")
+	}
+	defer rows.Close()
+
+	var shipments []*Shipment
+	for rows.Next() {
+		shipment, err := scanShipment(rows)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to scan shipment")
+		}
+		shipments = append(shipments, shipment)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "error iterating shipments")
+	}
+
+	return shipments, nil
+}
+
+// ListEvents retrieves the tracking history of a shipment, oldest first
+func (r *repository) ListEvents(ctx context.Context, shipmentID string) ([]*Event, error) {
+	query := `
+		SELECT id, shipment_id, status, COALESCE(location, ''), COALESCE(description, ''), occurred_at, created_at
+		FROM shipment_events
+		WHERE shipment_id = $1
+		ORDER BY occurred_at ASC
+	`
+
+	rows, err := r.db.Executor(ctx).QueryContext(ctx, query, shipmentID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list shipment events")
+	}
+	defer rows.Close()
+
+	var events []*Event
+	for rows.Next() {
+		var event Event
+		if err := rows.Scan(
+			&event.ID,
+			&event.ShipmentID,
+			&event.Status,
+			&event.Location,
+			&event.Description,
+			&event.OccurredAt,
+			&event.CreatedAt,
+		); err != nil {
+			return nil, errors.Wrap(err, "failed to scan shipment event")
+		}
+		events = append(events, &event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "error iterating shipment events")
+	}
+
+	return events, nil
+}
+
+// RecordEvent appends a tracking event and advances the shipment status when
+// CanTransition allows it. The event is kept in the history either way.
+func (r *repository) RecordEvent(ctx context.Context, event *Event) (*Shipment, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	// Lock the shipment so concurrent callbacks apply in turn
+	query := `SELECT ` + shipmentColumns + ` FROM shipments WHERE id = $1 FOR UPDATE`
+	shipment, err := scanShipment(tx.QueryRowContext(ctx, query, event.ShipmentID))
+	if err == sql.ErrNoRows {
+		return nil, errors.WithCode(errors.New("shipment not found"), errors.CodeNotFound)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get shipment")
+	}
+
+	if err := insertEvent(ctx, tx, event); err != nil {
+		return nil, err
+	}
+
+	if CanTransition(shipment.Status, event.Status) {
+		shipment.Status = event.Status
+		shipment.UpdatedAt = time.Now()
+		if event.Status == StatusDelivered {
+			deliveredAt := event.OccurredAt
+			shipment.DeliveredAt = &deliveredAt
+		}
+
+		update := `
+			UPDATE shipments
+			SET status = $1, delivered_at = $2, updated_at = $3
+			WHERE id = $4
+		`
+		if _, err := tx.ExecContext(ctx, update, shipment.Status, shipment.DeliveredAt, shipment.UpdatedAt, shipment.ID); err != nil {
+			return nil, errors.Wrap(err, "failed to update shipment status")
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, errors.Wrap(err, "failed to commit transaction")
+	}
+
+	return shipment, nil
+}
+
+func insertEvent(ctx context.Context, tx db.Executor, event *Event) error {
+	query := `
+		INSERT INTO shipment_events (id, shipment_id, status, location, description, occurred_at, created_at)
+		VALUES ($1, $2, $3, NULLIF($4, ''), NULLIF($5, ''), $6, $7)
+	`
+
+	event.ID = uuid.New().String()
+	event.CreatedAt = time.Now()
+
+	if _, err := tx.ExecContext(ctx, query,
+		event.ID,
+		event.ShipmentID,
+		event.Status,
+		event.Location,
+		event.Description,
+		event.OccurredAt,
+		event.CreatedAt,
+	); err != nil {
+		return errors.Wrap(err, "failed to record shipment event")
+	}
+
+	return nil
+}
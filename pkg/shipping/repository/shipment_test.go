@@ -0,0 +1,47 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package repository
+
+import "testing"
+
+func TestCanTransition(t *testing.T) {
+	tests := []struct {
+		name string
+		from string
+		to   string
+		want bool
+	}{
+		{name: "forward", from: StatusLabelCreated, to: StatusInTransit, want: true},
+		{name: "skip ahead", from: StatusInTransit, to: StatusDelivered, want: true},
+		{name: "backwards", from: StatusOutForDelivery, to: StatusInTransit, want: false},
+		{name: "same status", from: StatusInTransit, to: StatusInTransit, want: false},
+		{name: "raise exception", from: StatusInTransit, to: StatusException, want: true},
+		{name: "repeat exception", from: StatusException, to: StatusException, want: false},
+		{name: "clear exception", from: StatusException, to: StatusInTransit, want: true},
+		{name: "exception to label", from: StatusException, to: StatusLabelCreated, want: false},
+		{name: "delivered is final", from: StatusDelivered, to: StatusException, want: false},
+		{name: "unknown status", from: StatusInTransit, to: "lost", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CanTransition(tt.from, tt.to); got != tt.want {
+				t.Errorf("CanTransition(%q, %q) = %v, want %v", tt.from, tt.to, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package service
+
+import (
+	"context"
+
+	orderv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/order/v1"
+)
+
+type orderClient struct {
+	client orderv1.OrderServiceClient
+}
+
+// NewOrderClient returns Orders backed by the order service
+func NewOrderClient(client orderv1.OrderServiceClient) Orders {
+	return &orderClient{client: client}
+}
+
+// MarkDelivered moves an order to delivered unless it already is
+func (c *orderClient) MarkDelivered(ctx context.Context, orderID string) error {
+	resp, err := c.client.GetOrder(ctx, &orderv1.GetOrderRequest{Id: orderID})
+	if err != nil {
+		return err
+	}
+	if resp.GetOrder().GetStatus() == orderv1.OrderStatus_ORDER_STATUS_DELIVERED {
+		return nil
+	}
+
+	_, err = c.client.UpdateOrderStatus(ctx, &orderv1.UpdateOrderStatusRequest{
+		Id:     orderID,
+		Status: orderv1.OrderStatus_ORDER_STATUS_DELIVERED,
+	})
+	return err
+}
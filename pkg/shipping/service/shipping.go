@@ -0,0 +1,398 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+
+	shippingv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/shipping/v1"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/internal/log"
+	"github.com/kevindiu/monorepo-go-example/pkg/shipping/repository"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// statusToProto converts string status to proto enum
+func statusToProto(status string) shippingv1.ShipmentStatus {
+	switch status {
+	case repository.StatusLabelCreated:
+		return shippingv1.ShipmentStatus_SHIPMENT_STATUS_LABEL_CREATED
+	case repository.StatusInTransit:
+		return shippingv1.ShipmentStatus_SHIPMENT_STATUS_IN_TRANSIT
+	case repository.StatusOutForDelivery:
+		return shippingv1.ShipmentStatus_SHIPMENT_STATUS_OUT_FOR_DELIVERY
+	case repository.StatusDelivered:
+		return shippingv1.ShipmentStatus_SHIPMENT_STATUS_DELIVERED
+	case repository.StatusException:
+		return shippingv1.ShipmentStatus_SHIPMENT_STATUS_EXCEPTION
+	default:
+		return shippingv1.ShipmentStatus_SHIPMENT_STATUS_UNSPECIFIED
+	}
+}
+
+// statusFromProto converts proto enum to string status; unspecified maps to
+// an empty filter
+func statusFromProto(status shippingv1.ShipmentStatus) string {
+	switch status {
+	case shippingv1.ShipmentStatus_SHIPMENT_STATUS_LABEL_CREATED:
+		return repository.StatusLabelCreated
+	case shippingv1.ShipmentStatus_SHIPMENT_STATUS_IN_TRANSIT:
+		return repository.StatusInTransit
+	case shippingv1.ShipmentStatus_SHIPMENT_STATUS_OUT_FOR_DELIVERY:
+		return repository.StatusOutForDelivery
+	case shippingv1.ShipmentStatus_SHIPMENT_STATUS_DELIVERED:
+		return repository.StatusDelivered
+	case shippingv1.ShipmentStatus_SHIPMENT_STATUS_EXCEPTION:
+		return repository.StatusException
+	default:
+		return ""
+	}
+}
+
+// Orders advances orders as their shipments progress
+type Orders interface {
+	MarkDelivered(ctx context.Context, orderID string) error
+}
+
+// Service defines the shipping service interface
+type Service interface {
+	shippingv1.ShippingServiceServer
+}
+
+// Config holds shipping service configuration
+type Config struct {
+	Repository repository.Repository
+	Orders     Orders
+	// Carriers lists the accepted carrier codes; DefaultCarrier is used when
+	// a shipment is created without one
+	Carriers       []string
+	DefaultCarrier string
+	// CallbackSecret authenticates carrier callbacks. Callbacks are rejected
+	// while it is empty.
+	CallbackSecret string
+	Logger         *log.Logger
+}
+
+type service struct {
+	shippingv1.UnimplementedShippingServiceServer
+	repo           repository.Repository
+	orders         Orders
+	carriers       map[string]bool
+	defaultCarrier string
+	callbackSecret string
+	logger         *log.Logger
+}
+
+// New creates a new shipping service
+func New(cfg Config) (Service, error) {
+	if cfg.Repository == nil {
+		return nil, errors.WithCode(errors.New("shipment repository is required"), errors.CodeInvalidInput)
+	}
+	if cfg.Orders == nil {
+		return nil, errors.WithCode(errors.New("orders client is required"), errors.CodeInvalidInput)
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = log.NewDefault()
+	}
+
+	carriers := make(map[string]bool, len(cfg.Carriers))
+	for _, carrier := range cfg.Carriers {
+		carriers[strings.ToLower(carrier)] = true
+	}
+
+	defaultCarrier := strings.ToLower(cfg.DefaultCarrier)
+	if defaultCarrier != "" && !carriers[defaultCarrier] {
+		return nil, errors.WithCode(errors.Newf("default carrier %q is not a supported carrier", cfg.DefaultCarrier), errors.CodeInvalidInput)
+	}
+
+	return &service{
+		repo:           cfg.Repository,
+		orders:         cfg.Orders,
+		carriers:       carriers,
+		defaultCarrier: defaultCarrier,
+		callbackSecret: cfg.CallbackSecret,
+		logger:         cfg.Logger,
+	}, nil
+}
+
+// loggerFor returns the logger the interceptors attached to ctx, so that
+// shipment logs carry the call's request ID and user
+func (s *service) loggerFor(ctx context.Context) *log.Logger {
+	return log.FromContextOr(ctx, s.logger)
+}
+
+// CreateShipment creates a shipment for an order
+func (s *service) CreateShipment(ctx context.Context, req *shippingv1.CreateShipmentRequest) (*shippingv1.CreateShipmentResponse, error) {
+	s.loggerFor(ctx).Info("Creating shipment", log.String("order_id", req.GetOrderId()))
+
+	if req.GetOrderId() == "" {
+		return nil, errors.WithCode(errors.New("order_id is required"), errors.CodeInvalidInput)
+	}
+
+	carrier, err := s.carrier(req.GetCarrier())
+	if err != nil {
+		return nil, err
+	}
+
+	trackingNumber := req.GetTrackingNumber()
+	if trackingNumber == "" {
+		if trackingNumber, err = generateTrackingNumber(carrier); err != nil {
+			return nil, err
+		}
+	}
+
+	shipment := &repository.Shipment{
+		OrderID:        req.GetOrderId(),
+		Carrier:        carrier,
+		TrackingNumber: trackingNumber,
+	}
+
+	if err := s.repo.Create(ctx, shipment); err != nil {
+		s.loggerFor(ctx).Error("Failed to create shipment", log.Error(err))
+		return nil, err
+	}
+
+	s.loggerFor(ctx).Info("Shipment created successfully",
+		log.String("shipment_id", shipment.ID),
+		log.String("tracking_number", shipment.TrackingNumber),
+	)
+
+	return &shippingv1.CreateShipmentResponse{Shipment: shipmentToProto(shipment)}, nil
+}
+
+// GetShipment retrieves a shipment and its tracking history by ID
+func (s *service) GetShipment(ctx context.Context, req *shippingv1.GetShipmentRequest) (*shippingv1.GetShipmentResponse, error) {
+	if req.GetId() == "" {
+		return nil, errors.WithCode(errors.New("id is required"), errors.CodeInvalidInput)
+	}
+
+	shipment, err := s.repo.GetByID(ctx, req.GetId())
+	if err != nil {
+		s.loggerFor(ctx).Error("Failed to get shipment", log.Error(err))
+		return nil, err
+	}
+
+	events, err := s.repo.ListEvents(ctx, shipment.ID)
+	if err != nil {
+		s.loggerFor(ctx).Error("Failed to get shipment events", log.Error(err))
+		return nil, err
+	}
+
+	pb := shipmentToProto(shipment)
+	pb.Events = make([]*shippingv1.TrackingEvent, len(events))
+	for i, event := range events {
+		pb.Events[i] = eventToProto(event)
+	}
+
+	return &shippingv1.GetShipmentResponse{Shipment: pb}, nil
+}
+
+// ListShipments lists shipments with pagination
+func (s *service) ListShipments(ctx context.Context, req *shippingv1.ListShipmentsRequest) (*shippingv1.ListShipmentsResponse, error) {
+	pageSize, offset := pagination(req.GetPageSize(), req.GetPageToken())
+
+	filter := repository.Filter{
+		OrderID: req.GetOrderId(),
+		Status:  statusFromProto(req.GetStatus()),
+	}
+
+	shipments, err := s.repo.List(ctx, filter, pageSize, offset)
+	if err != nil {
+		s.loggerFor(ctx).Error("Failed to list shipments", log.Error(err))
+		return nil, err
+	}
+
+	pbShipments := make([]*shippingv1.Shipment, len(shipments))
+	for i, shipment := range shipments {
+		pbShipments[i] = shipmentToProto(shipment)
+	}
+
+	return &shippingv1.ListShipmentsResponse{
+		Shipments:     pbShipments,
+		NextPageToken: nextPageToken(len(shipments), pageSize, offset),
+	}, nil
+}
+
+// CarrierCallback records a carrier tracking update. When the update marks
+// the shipment delivered, the order is advanced to delivered as well; a
+// failure to do so is returned so that the carrier retries the callback.
+func (s *service) CarrierCallback(ctx context.Context, req *shippingv1.CarrierCallbackRequest) (*shippingv1.CarrierCallbackResponse, error) {
+	if err := s.authenticateCarrier(ctx); err != nil {
+		return nil, err
+	}
+
+	carrier := strings.ToLower(req.GetCarrier())
+	if !s.carriers[carrier] {
+		return nil, errors.WithCode(errors.Newf("unsupported carrier %q", req.GetCarrier()), errors.CodeInvalidInput)
+	}
+	if req.GetTrackingNumber() == "" {
+		return nil, errors.WithCode(errors.New("tracking_number is required"), errors.CodeInvalidInput)
+	}
+
+	status := statusFromProto(req.GetStatus())
+	if status == "" {
+		return nil, errors.WithCode(errors.New("status is required"), errors.CodeInvalidInput)
+	}
+
+	s.loggerFor(ctx).Info("Received carrier callback",
+		log.String("carrier", carrier),
+		log.String("tracking_number", req.GetTrackingNumber()),
+		log.String("status", status),
+	)
+
+	shipment, err := s.repo.GetByTrackingNumber(ctx, carrier, req.GetTrackingNumber())
+	if err != nil {
+		s.loggerFor(ctx).Error("Failed to get shipment", log.Error(err))
+		return nil, err
+	}
+
+	occurredAt := time.Now()
+	if req.GetOccurredAt() != nil {
+		occurredAt = req.GetOccurredAt().AsTime()
+	}
+
+	shipment, err = s.repo.RecordEvent(ctx, &repository.Event{
+		ShipmentID:  shipment.ID,
+		Status:      status,
+		Location:    req.GetLocation(),
+		Description: req.GetDescription(),
+		OccurredAt:  occurredAt,
+	})
+	if err != nil {
+		s.loggerFor(ctx).Error("Failed to record shipment event", log.Error(err))
+		return nil, err
+	}
+
+	// Redelivered callbacks retry the order update, which is idempotent
+	if status == repository.StatusDelivered && shipment.Status == repository.StatusDelivered {
+		if err := s.orders.MarkDelivered(ctx, shipment.OrderID); err != nil {
+			s.loggerFor(ctx).Error("Failed to mark order delivered", log.String("order_id", shipment.OrderID), log.Error(err))
+			return nil, err
+		}
+		s.loggerFor(ctx).Info("Order delivered", log.String("order_id", shipment.OrderID))
+	}
+
+	return &shippingv1.CarrierCallbackResponse{Shipment: shipmentToProto(shipment)}, nil
+}
+
+// carrier normalizes a requested carrier, falling back to the default
+func (s *service) carrier(requested string) (string, error) {
+	carrier := strings.ToLower(requested)
+	if carrier == "" {
+		carrier = s.defaultCarrier
+	}
+	if carrier == "" {
+		return "", errors.WithCode(errors.New("carrier is required"), errors.CodeInvalidInput)
+	}
+	if !s.carriers[carrier] {
+		return "", errors.WithCode(errors.Newf("unsupported carrier %q", requested), errors.CodeInvalidInput)
+	}
+	return carrier, nil
+}
+
+// authenticateCarrier checks the bearer token of a carrier callback against
+// the callback secret
+func (s *service) authenticateCarrier(ctx context.Context) error {
+	if s.callbackSecret == "" {
+		return errors.WithCode(errors.New("carrier callbacks are not configured"), errors.CodeUnauthorized)
+	}
+
+	var token string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get("authorization"); len(values) > 0 {
+			token = strings.TrimPrefix(values[0], "Bearer ")
+		}
+	}
+
+	if subtle.ConstantTimeCompare([]byte(token), []byte(s.callbackSecret)) != 1 {
+		return errors.WithCode(errors.New("invalid carrier credentials"), errors.CodeUnauthorized)
+	}
+
+	return nil
+}
+
+// generateTrackingNumber returns a random tracking number prefixed with the
+// carrier code, for carriers that do not issue their own
+func generateTrackingNumber(carrier string) (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", errors.Wrap(err, "failed to generate tracking number")
+	}
+	return strings.ToUpper(carrier + hex.EncodeToString(b)), nil
+}
+
+// pagination normalizes the page size and decodes the offset page token
+func pagination(size int32, token string) (int, int) {
+	pageSize := int(size)
+	if pageSize <= 0 {
+		pageSize = 10
+	}
+	if pageSize > 100 {
+		pageSize = 100
+	}
+
+	offset := 0
+	if token != "" {
+		parsedOffset, err := strconv.Atoi(token)
+		if err == nil && parsedOffset > 0 {
+			offset = parsedOffset
+		}
+	}
+
+	return pageSize, offset
+}
+
+func nextPageToken(count, pageSize, offset int) string {
+	if count == pageSize {
+		return strconv.Itoa(offset + pageSize)
+	}
+	return ""
+}
+
+func shipmentToProto(shipment *repository.Shipment) *shippingv1.Shipment {
+	pb := &shippingv1.Shipment{
+		Id:             shipment.ID,
+		OrderId:        shipment.OrderID,
+		Carrier:        shipment.Carrier,
+		TrackingNumber: shipment.TrackingNumber,
+		Status:         statusToProto(shipment.Status),
+		CreatedAt:      timestamppb.New(shipment.CreatedAt),
+		UpdatedAt:      timestamppb.New(shipment.UpdatedAt),
+	}
+	if shipment.DeliveredAt != nil {
+		pb.DeliveredAt = timestamppb.New(*shipment.DeliveredAt)
+	}
+	return pb
+}
+
+func eventToProto(event *repository.Event) *shippingv1.TrackingEvent {
+	return &shippingv1.TrackingEvent{
+		Id:          event.ID,
+		ShipmentId:  event.ShipmentID,
+		Status:      statusToProto(event.Status),
+		Location:    event.Location,
+		Description: event.Description,
+		OccurredAt:  timestamppb.New(event.OccurredAt),
+	}
+}
@@ -0,0 +1,362 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	shippingv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/shipping/v1"
+	"github.com/kevindiu/monorepo-go-example/internal/auth"
+	"github.com/kevindiu/monorepo-go-example/internal/config"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/internal/log"
+	"github.com/kevindiu/monorepo-go-example/pkg/shipping/repository"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// mockRepository implements repository.Repository for testing
+type mockRepository struct {
+	shipments map[string]*repository.Shipment
+	events    []*repository.Event
+}
+
+func newMockRepository() *mockRepository {
+	return &mockRepository{shipments: make(map[string]*repository.Shipment)}
+}
+
+func (m *mockRepository) Create(ctx context.Context, shipment *repository.Shipment) error {
+	for _, existing := range m.shipments {
+		if existing.OrderID == shipment.OrderID {
+			return errors.WithCode(errors.New("order already has a shipment"), errors.CodeConflict)
+		}
+	}
+	shipment.ID = "shipment-" + shipment.OrderID
+	shipment.Status = repository.StatusLabelCreated
+	m.shipments[shipment.ID] = shipment
+	return nil
+}
+
+func (m *mockRepository) GetByID(ctx context.Context, id string) (*repository.Shipment, error) {
+	shipment, ok := m.shipments[id]
+	if !ok {
+		return nil, errors.WithCode(errors.New("shipment not found"), errors.CodeNotFound)
+	}
+	return shipment, nil
+}
+
+func (m *mockRepository) GetByOrderID(ctx context.Context, orderID string) (*repository.Shipment, error) {
+	return m.GetByID(ctx, "shipment-"+orderID)
+}
+
+func (m *mockRepository) GetByTrackingNumber(ctx context.Context, carrier, trackingNumber string) (*repository.Shipment, error) {
+	for _, shipment := range m.shipments {
+		if shipment.Carrier == carrier && shipment.TrackingNumber == trackingNumber {
+			return shipment, nil
+		}
+	}
+	return nil, errors.WithCode(errors.New("shipment not found"), errors.CodeNotFound)
+}
+
+func (m *mockRepository) List(ctx context.Context, filter repository.Filter, limit, offset int) ([]*repository.Shipment, error) {
+	shipments := []*repository.Shipment{}
+	for _, shipment := range m.shipments {
+		if filter.OrderID == "" || shipment.OrderID == filter.OrderID {
+			shipments = append(shipments, shipment)
+		}
+	}
+	return shipments, nil
+}
+
+func (m *mockRepository) ListEvents(ctx context.Context, shipmentID string) ([]*repository.Event, error) {
+	var events []*repository.Event
+	for _, event := range m.events {
+		if event.ShipmentID == shipmentID {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+func (m *mockRepository) RecordEvent(ctx context.Context, event *repository.Event) (*repository.Shipment, error) {
+	shipment, err := m.GetByID(ctx, event.ShipmentID)
+	if err != nil {
+		return nil, err
+	}
+	m.events = append(m.events, event)
+	if repository.CanTransition(shipment.Status, event.Status) {
+		shipment.Status = event.Status
+	}
+	return shipment, nil
+}
+
+// mockOrders records delivered orders
+type mockOrders struct {
+	delivered []string
+	err       error
+}
+
+func (m *mockOrders) MarkDelivered(ctx context.Context, orderID string) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.delivered = append(m.delivered, orderID)
+	return nil
+}
+
+func newTestService(t *testing.T, repo *mockRepository, orders *mockOrders) Service {
+	t.Helper()
+	svc, err := New(Config{
+		Repository:     repo,
+		Orders:         orders,
+		Carriers:       []string{"ups", "dhl"},
+		DefaultCarrier: "ups",
+		CallbackSecret: "carrier-secret",
+		Logger:         log.NewDefault(),
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	return svc
+}
+
+func carrierContext(token string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+token))
+}
+
+func TestNew(t *testing.T) {
+	_, err := New(Config{
+		Repository:     newMockRepository(),
+		Orders:         &mockOrders{},
+		Carriers:       []string{"ups"},
+		DefaultCarrier: "fedex",
+	})
+	if err == nil {
+		t.Error("New() should reject a default carrier that is not supported")
+	}
+}
+
+func TestCreateShipment(t *testing.T) {
+	svc := newTestService(t, newMockRepository(), &mockOrders{})
+
+	tests := []struct {
+		name        string
+		req         *shippingv1.CreateShipmentRequest
+		wantCarrier string
+		wantErr     bool
+	}{
+		{
+			name:        "default carrier",
+			req:         &shippingv1.CreateShipmentRequest{OrderId: "order-1"},
+			wantCarrier: "ups",
+		},
+		{
+			name:        "explicit carrier and tracking",
+			req:         &shippingv1.CreateShipmentRequest{OrderId: "order-2", Carrier: "DHL", TrackingNumber: "JD0123"},
+			wantCarrier: "dhl",
+		},
+		{
+			name:    "duplicate order",
+			req:     &shippingv1.CreateShipmentRequest{OrderId: "order-1"},
+			wantErr: true,
+		},
+		{
+			name:    "unsupported carrier",
+			req:     &shippingv1.CreateShipmentRequest{OrderId: "order-3", Carrier: "pigeon"},
+			wantErr: true,
+		},
+		{
+			name:    "missing order id",
+			req:     &shippingv1.CreateShipmentRequest{},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := svc.CreateShipment(context.Background(), tt.req)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CreateShipment() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			shipment := resp.GetShipment()
+			if shipment.GetCarrier() != tt.wantCarrier {
+				t.Errorf("CreateShipment() carrier = %q, want %q", shipment.GetCarrier(), tt.wantCarrier)
+			}
+			if shipment.GetTrackingNumber() == "" {
+				t.Error("CreateShipment() did not assign a tracking number")
+			}
+			if tt.req.GetTrackingNumber() == "" && !strings.HasPrefix(shipment.GetTrackingNumber(), strings.ToUpper(tt.wantCarrier)) {
+				t.Errorf("CreateShipment() tracking number = %q, want carrier prefix", shipment.GetTrackingNumber())
+			}
+		})
+	}
+}
+
+func TestCarrierCallback(t *testing.T) {
+	repo := newMockRepository()
+	repo.shipments["s1"] = &repository.Shipment{ID: "s1", OrderID: "o1", Carrier: "ups", TrackingNumber: "UPS1", Status: repository.StatusLabelCreated}
+	orders := &mockOrders{}
+	svc := newTestService(t, repo, orders)
+
+	tests := []struct {
+		name       string
+		ctx        context.Context
+		req        *shippingv1.CarrierCallbackRequest
+		wantStatus shippingv1.ShipmentStatus
+		wantErr    bool
+	}{
+		{
+			name:    "missing credentials",
+			ctx:     context.Background(),
+			req:     &shippingv1.CarrierCallbackRequest{Carrier: "ups", TrackingNumber: "UPS1", Status: shippingv1.ShipmentStatus_SHIPMENT_STATUS_IN_TRANSIT},
+			wantErr: true,
+		},
+		{
+			name:    "wrong secret",
+			ctx:     carrierContext("guess"),
+			req:     &shippingv1.CarrierCallbackRequest{Carrier: "ups", TrackingNumber: "UPS1", Status: shippingv1.ShipmentStatus_SHIPMENT_STATUS_IN_TRANSIT},
+			wantErr: true,
+		},
+		{
+			name:    "unknown tracking number",
+			ctx:     carrierContext("carrier-secret"),
+			req:     &shippingv1.CarrierCallbackRequest{Carrier: "ups", TrackingNumber: "UPS2", Status: shippingv1.ShipmentStatus_SHIPMENT_STATUS_IN_TRANSIT},
+			wantErr: true,
+		},
+		{
+			name:    "missing status",
+			ctx:     carrierContext("carrier-secret"),
+			req:     &shippingv1.CarrierCallbackRequest{Carrier: "ups", TrackingNumber: "UPS1"},
+			wantErr: true,
+		},
+		{
+			name:       "in transit",
+			ctx:        carrierContext("carrier-secret"),
+			req:        &shippingv1.CarrierCallbackRequest{Carrier: "UPS", TrackingNumber: "UPS1", Status: shippingv1.ShipmentStatus_SHIPMENT_STATUS_IN_TRANSIT},
+			wantStatus: shippingv1.ShipmentStatus_SHIPMENT_STATUS_IN_TRANSIT,
+		},
+		{
+			name:       "delivered",
+			ctx:        carrierContext("carrier-secret"),
+			req:        &shippingv1.CarrierCallbackRequest{Carrier: "ups", TrackingNumber: "UPS1", Status: shippingv1.ShipmentStatus_SHIPMENT_STATUS_DELIVERED},
+			wantStatus: shippingv1.ShipmentStatus_SHIPMENT_STATUS_DELIVERED,
+		},
+		{
+			name:       "late update after delivery",
+			ctx:        carrierContext("carrier-secret"),
+			req:        &shippingv1.CarrierCallbackRequest{Carrier: "ups", TrackingNumber: "UPS1", Status: shippingv1.ShipmentStatus_SHIPMENT_STATUS_OUT_FOR_DELIVERY},
+			wantStatus: shippingv1.ShipmentStatus_SHIPMENT_STATUS_DELIVERED,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := svc.CarrierCallback(tt.ctx, tt.req)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CarrierCallback() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && resp.GetShipment().GetStatus() != tt.wantStatus {
+				t.Errorf("CarrierCallback() status = %v, want %v", resp.GetShipment().GetStatus(), tt.wantStatus)
+			}
+		})
+	}
+
+	if len(orders.delivered) != 1 || orders.delivered[0] != "o1" {
+		t.Errorf("delivered orders = %v, want [o1]", orders.delivered)
+	}
+}
+
+func TestCarrierCallbackRetriesOrderUpdate(t *testing.T) {
+	repo := newMockRepository()
+	repo.shipments["s1"] = &repository.Shipment{ID: "s1", OrderID: "o1", Carrier: "ups", TrackingNumber: "UPS1", Status: repository.StatusInTransit}
+	orders := &mockOrders{err: errors.WithCode(errors.New("order service down"), errors.CodeUnavailable)}
+	svc := newTestService(t, repo, orders)
+
+	req := &shippingv1.CarrierCallbackRequest{Carrier: "ups", TrackingNumber: "UPS1", Status: shippingv1.ShipmentStatus_SHIPMENT_STATUS_DELIVERED}
+	if _, err := svc.CarrierCallback(carrierContext("carrier-secret"), req); err == nil {
+		t.Fatal("CarrierCallback() should fail while the order cannot be updated")
+	}
+
+	// The carrier redelivers the callback once the order service is back
+	orders.err = nil
+	if _, err := svc.CarrierCallback(carrierContext("carrier-secret"), req); err != nil {
+		t.Fatalf("CarrierCallback() error = %v", err)
+	}
+	if len(orders.delivered) != 1 {
+		t.Errorf("delivered orders = %v, want [o1]", orders.delivered)
+	}
+}
+
+func TestCarrierCallbackDisabledWithoutSecret(t *testing.T) {
+	svc, err := New(Config{Repository: newMockRepository(), Orders: &mockOrders{}, Carriers: []string{"ups"}})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	req := &shippingv1.CarrierCallbackRequest{Carrier: "ups", TrackingNumber: "UPS1", Status: shippingv1.ShipmentStatus_SHIPMENT_STATUS_IN_TRANSIT}
+	_, err = svc.CarrierCallback(carrierContext(""), req)
+	if errors.GetCode(err) != errors.CodeUnauthorized {
+		t.Errorf("CarrierCallback() error = %v, want unauthorized", err)
+	}
+}
+
+func TestCarrierCallbackWithAuth(t *testing.T) {
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	cfg.Auth.Enabled = true
+	cfg.Auth.Secret = "0123456789abcdef0123456789abcdef"
+	authenticator, err := auth.New(cfg.Auth)
+	if err != nil {
+		t.Fatalf("auth.New() error = %v", err)
+	}
+	interceptor := auth.UnaryServerInterceptor(authenticator)
+
+	repo := newMockRepository()
+	repo.shipments["s1"] = &repository.Shipment{ID: "s1", OrderID: "o1", Carrier: "ups", TrackingNumber: "UPS1", Status: repository.StatusLabelCreated}
+	svc := newTestService(t, repo, &mockOrders{})
+
+	call := func(ctx context.Context, method string) error {
+		req := &shippingv1.CarrierCallbackRequest{Carrier: "ups", TrackingNumber: "UPS1", Status: shippingv1.ShipmentStatus_SHIPMENT_STATUS_IN_TRANSIT}
+		_, err := interceptor(ctx, req, &grpc.UnaryServerInfo{FullMethod: method}, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return svc.CarrierCallback(ctx, req.(*shippingv1.CarrierCallbackRequest))
+		})
+		return err
+	}
+
+	// The callback secret is not a token, but the default public methods
+	// let it through to the carrier check
+	if err := call(carrierContext("carrier-secret"), "/shipping.v1.ShippingService/CarrierCallback"); err != nil {
+		t.Fatalf("CarrierCallback() with auth enabled error = %v", err)
+	}
+	if err := call(carrierContext("guess"), "/shipping.v1.ShippingService/CarrierCallback"); errors.GetCode(err) != errors.CodeUnauthorized {
+		t.Errorf("CarrierCallback() with a wrong secret error = %v, want unauthorized", err)
+	}
+	if !authenticator.PublicPath("/v1/carriers/ups/callbacks") {
+		t.Error("carrier callback route is not public by default")
+	}
+	// Other methods still require a token
+	if err := call(carrierContext("carrier-secret"), "/shipping.v1.ShippingService/CreateShipment"); err == nil {
+		t.Error("call with the callback secret as a token succeeded")
+	}
+}
@@ -0,0 +1,39 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package events defines the user domain events published on the event bus
+package events
+
+import "time"
+
+// Topic is the event bus topic carrying user events
+const Topic = "users"
+
+// User event types
+const (
+	TypeUserCreated = "user.created"
+	TypeUserUpdated = "user.updated"
+	TypeUserDeleted = "user.deleted"
+)
+
+// User is the payload of every user event. Deleted events only carry the ID.
+type User struct {
+	ID        string    `json:"id"`
+	Email     string    `json:"email,omitempty"`
+	Name      string    `json:"name,omitempty"`
+	CreatedAt time.Time `json:"created_at,omitempty"`
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+}
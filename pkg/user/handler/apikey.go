@@ -0,0 +1,94 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package handler
+
+import (
+	"context"
+	"time"
+
+	apikeyv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/apikey/v1"
+	"github.com/kevindiu/monorepo-go-example/internal/apikey"
+	"github.com/kevindiu/monorepo-go-example/pkg/user/service"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// apiKeyHandler implements apikeyv1.APIKeyServiceServer
+type apiKeyHandler struct {
+	apikeyv1.UnimplementedAPIKeyServiceServer
+	keys service.APIKeyService
+}
+
+// NewAPIKey returns the gRPC handler of API key management
+func NewAPIKey(keys service.APIKeyService) apikeyv1.APIKeyServiceServer {
+	return &apiKeyHandler{keys: keys}
+}
+
+// optionalTimestamp converts an optional time to its message
+func optionalTimestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
+
+// apiKeyToProto converts an API key to its message
+func apiKeyToProto(k *apikey.Key) *apikeyv1.APIKey {
+	return &apikeyv1.APIKey{
+		Id:        k.ID,
+		Name:      k.Name,
+		Prefix:    k.Prefix,
+		Scopes:    k.Scopes,
+		CreatedAt: timestamppb.New(k.CreatedAt),
+		ExpiresAt: optionalTimestamp(k.ExpiresAt),
+		RevokedAt: optionalTimestamp(k.RevokedAt),
+	}
+}
+
+// CreateAPIKey issues a key
+func (h *apiKeyHandler) CreateAPIKey(ctx context.Context, req *apikeyv1.CreateAPIKeyRequest) (*apikeyv1.CreateAPIKeyResponse, error) {
+	var expiresAt *time.Time
+	if req.ExpiresAt != nil {
+		t := req.ExpiresAt.AsTime()
+		expiresAt = &t
+	}
+	k, key, err := h.keys.CreateAPIKey(ctx, req.GetName(), req.GetScopes(), expiresAt)
+	if err != nil {
+		return nil, err
+	}
+	return &apikeyv1.CreateAPIKeyResponse{ApiKey: apiKeyToProto(k), Key: key}, nil
+}
+
+// ListAPIKeys lists the keys of the tenant
+func (h *apiKeyHandler) ListAPIKeys(ctx context.Context, req *apikeyv1.ListAPIKeysRequest) (*apikeyv1.ListAPIKeysResponse, error) {
+	keys, err := h.keys.ListAPIKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resp := &apikeyv1.ListAPIKeysResponse{ApiKeys: make([]*apikeyv1.APIKey, 0, len(keys))}
+	for _, k := range keys {
+		resp.ApiKeys = append(resp.ApiKeys, apiKeyToProto(k))
+	}
+	return resp, nil
+}
+
+// RevokeAPIKey revokes a key
+func (h *apiKeyHandler) RevokeAPIKey(ctx context.Context, req *apikeyv1.RevokeAPIKeyRequest) (*apikeyv1.RevokeAPIKeyResponse, error) {
+	if err := h.keys.RevokeAPIKey(ctx, req.GetId()); err != nil {
+		return nil, err
+	}
+	return &apikeyv1.RevokeAPIKeyResponse{Success: true}, nil
+}
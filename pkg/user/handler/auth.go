@@ -0,0 +1,71 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package handler
+
+import (
+	"context"
+
+	authv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/auth/v1"
+	"github.com/kevindiu/monorepo-go-example/pkg/user/service"
+)
+
+// authHandler implements authv1.AuthServiceServer
+type authHandler struct {
+	authv1.UnimplementedAuthServiceServer
+	auth service.AuthService
+}
+
+// NewAuth returns the gRPC handler of logins
+func NewAuth(auth service.AuthService) authv1.AuthServiceServer {
+	return &authHandler{auth: auth}
+}
+
+// tokensToProto converts issued tokens to their message
+func tokensToProto(t *service.Tokens) *authv1.Tokens {
+	return &authv1.Tokens{
+		AccessToken:  t.AccessToken,
+		RefreshToken: t.RefreshToken,
+		ExpiresIn:    int32(t.ExpiresIn.Seconds()),
+		TokenType:    "Bearer",
+	}
+}
+
+// Login signs a user in with its email and password
+func (h *authHandler) Login(ctx context.Context, req *authv1.LoginRequest) (*authv1.LoginResponse, error) {
+	user, tokens, err := h.auth.Login(ctx, req.GetEmail(), req.GetPassword())
+	if err != nil {
+		return nil, err
+	}
+	return &authv1.LoginResponse{UserId: user.ID, Tokens: tokensToProto(tokens)}, nil
+}
+
+// Refresh exchanges a refresh token for new tokens
+func (h *authHandler) Refresh(ctx context.Context, req *authv1.RefreshRequest) (*authv1.RefreshResponse, error) {
+	tokens, err := h.auth.Refresh(ctx, req.GetRefreshToken())
+	if err != nil {
+		return nil, err
+	}
+	return &authv1.RefreshResponse{Tokens: tokensToProto(tokens)}, nil
+}
+
+// Logout revokes the session of a refresh token
+func (h *authHandler) Logout(ctx context.Context, req *authv1.LogoutRequest) (*authv1.LogoutResponse, error) {
+	if err := h.auth.Logout(ctx, req.GetRefreshToken()); err != nil {
+		return nil, err
+	}
+	return &authv1.LogoutResponse{Success: true}, nil
+}
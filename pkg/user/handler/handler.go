@@ -0,0 +1,129 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package handler serves the user service over gRPC, adapting
+// service.UserService to the generated v1 and v2 server interfaces
+package handler
+
+import (
+	"context"
+
+	userv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/user/v1"
+	"github.com/kevindiu/monorepo-go-example/internal/fieldmask"
+	"github.com/kevindiu/monorepo-go-example/pkg/user/repository"
+	"github.com/kevindiu/monorepo-go-example/pkg/user/service"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// handler implements userv1.UserServiceServer
+type handler struct {
+	userv1.UnimplementedUserServiceServer
+	users service.UserService
+}
+
+// New returns the v1 gRPC handler of users
+func New(users service.UserService) userv1.UserServiceServer {
+	return &handler{users: users}
+}
+
+// userToProto converts a user to its v1 message
+func userToProto(u *repository.User) *userv1.User {
+	return &userv1.User{
+		Id:        u.ID,
+		Email:     u.Email,
+		Name:      u.Name,
+		CreatedAt: timestamppb.New(u.CreatedAt),
+		UpdatedAt: timestamppb.New(u.UpdatedAt),
+		Roles:     u.Roles,
+	}
+}
+
+// usersToProto converts users to their v1 messages
+func usersToProto(users []*repository.User) []*userv1.User {
+	pb := make([]*userv1.User, len(users))
+	for i, u := range users {
+		pb[i] = userToProto(u)
+	}
+	return pb
+}
+
+// CreateUser creates a user
+func (h *handler) CreateUser(ctx context.Context, req *userv1.CreateUserRequest) (*userv1.CreateUserResponse, error) {
+	user, err := h.users.CreateUser(ctx, req.GetEmail(), req.GetName())
+	if err != nil {
+		return nil, err
+	}
+	return &userv1.CreateUserResponse{User: userToProto(user)}, nil
+}
+
+// GetUser retrieves a user by ID
+func (h *handler) GetUser(ctx context.Context, req *userv1.GetUserRequest) (*userv1.GetUserResponse, error) {
+	user, err := h.users.GetUser(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	return &userv1.GetUserResponse{User: userToProto(user)}, nil
+}
+
+// ListUsers lists users, newest first, a page at a time, trimmed to
+// read_mask
+func (h *handler) ListUsers(ctx context.Context, req *userv1.ListUsersRequest) (*userv1.ListUsersResponse, error) {
+	if err := fieldmask.Validate(&userv1.User{}, req.GetReadMask()); err != nil {
+		return nil, err
+	}
+	users, next, err := h.users.ListUsers(ctx, int(req.GetPageSize()), req.GetPageToken())
+	if err != nil {
+		return nil, err
+	}
+	resp := &userv1.ListUsersResponse{Users: usersToProto(users), NextPageToken: next}
+	for _, u := range resp.Users {
+		fieldmask.Trim(u, req.GetReadMask())
+	}
+	return resp, nil
+}
+
+// UpdateUser changes the fields of a user named by update_mask
+func (h *handler) UpdateUser(ctx context.Context, req *userv1.UpdateUserRequest) (*userv1.UpdateUserResponse, error) {
+	user, err := h.users.UpdateUser(ctx, req.GetId(), req.GetEmail(), req.GetName(), req.GetRoles(), req.GetUpdateMask())
+	if err != nil {
+		return nil, err
+	}
+	return &userv1.UpdateUserResponse{User: userToProto(user)}, nil
+}
+
+// DeleteUser soft-deletes a user
+func (h *handler) DeleteUser(ctx context.Context, req *userv1.DeleteUserRequest) (*userv1.DeleteUserResponse, error) {
+	if err := h.users.DeleteUser(ctx, req.GetId()); err != nil {
+		return nil, err
+	}
+	return &userv1.DeleteUserResponse{Success: true}, nil
+}
+
+// ChangePassword replaces the password of a user after checking its
+// current one
+func (h *handler) ChangePassword(ctx context.Context, req *userv1.ChangePasswordRequest) (*userv1.ChangePasswordResponse, error) {
+	if err := h.users.ChangePassword(ctx, req.GetId(), req.GetCurrentPassword(), req.GetNewPassword()); err != nil {
+		return nil, err
+	}
+	return &userv1.ChangePasswordResponse{Success: true}, nil
+}
+
+// ExportUsers streams every user in ID order, a batch per response
+func (h *handler) ExportUsers(req *userv1.ExportUsersRequest, stream userv1.UserService_ExportUsersServer) error {
+	return h.users.ExportUsers(stream.Context(), int(req.GetBatchSize()), req.GetIncludePii(), func(users []*repository.User) error {
+		return stream.Send(&userv1.ExportUsersResponse{Users: usersToProto(users)})
+	})
+}
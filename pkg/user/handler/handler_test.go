@@ -0,0 +1,383 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package handler
+
+import (
+	"context"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	authv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/auth/v1"
+	userv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/user/v1"
+	userv2 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/user/v2"
+	"github.com/kevindiu/monorepo-go-example/internal/audit"
+	"github.com/kevindiu/monorepo-go-example/internal/config"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/internal/events"
+	"github.com/kevindiu/monorepo-go-example/internal/tenant"
+	"github.com/kevindiu/monorepo-go-example/pkg/user/repository"
+	"github.com/kevindiu/monorepo-go-example/pkg/user/service"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// stubUsers serves one stored user; methods not overridden panic through
+// the nil embedded interface
+type stubUsers struct {
+	service.UserService
+	user    *repository.User
+	deleted string
+	// changed records the arguments of ChangePassword
+	changed []string
+	// exportBatch and exportPII record the options of ExportUsers
+	exportBatch int
+	exportPII   bool
+}
+
+func (s *stubUsers) CreateUser(ctx context.Context, email, name string) (*repository.User, error) {
+	return &repository.User{ID: "u2", Email: email, Name: name}, nil
+}
+
+func (s *stubUsers) GetUser(ctx context.Context, id string) (*repository.User, error) {
+	if id != s.user.ID {
+		return nil, errors.WithCode(errors.New("user not found"), errors.CodeNotFound)
+	}
+	return s.user, nil
+}
+
+func (s *stubUsers) ListUsers(ctx context.Context, pageSize int, pageToken string) ([]*repository.User, string, error) {
+	return []*repository.User{s.user}, "next", nil
+}
+
+func (s *stubUsers) UpdateUser(ctx context.Context, id, email, name string, roles []string, mask *fieldmaskpb.FieldMask) (*repository.User, error) {
+	u := *s.user
+	u.Name = name
+	return &u, nil
+}
+
+func (s *stubUsers) DeleteUser(ctx context.Context, id string) error {
+	s.deleted = id
+	return nil
+}
+
+func (s *stubUsers) ChangePassword(ctx context.Context, id, currentPassword, newPassword string) error {
+	s.changed = []string{id, currentPassword, newPassword}
+	return nil
+}
+
+// ExportUsers sends the stored user in two batches
+func (s *stubUsers) ExportUsers(ctx context.Context, batchSize int, includePII bool, fn func([]*repository.User) error) error {
+	s.exportBatch, s.exportPII = batchSize, includePII
+	for i := 0; i < 2; i++ {
+		if err := fn([]*repository.User{s.user}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// exportStream collects the batches sent by ExportUsers
+type exportStream struct {
+	grpc.ServerStream
+	batches []*userv1.ExportUsersResponse
+}
+
+func (s *exportStream) Context() context.Context { return context.Background() }
+
+func (s *exportStream) Send(resp *userv1.ExportUsersResponse) error {
+	s.batches = append(s.batches, resp)
+	return nil
+}
+
+func newStub() *stubUsers {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	return &stubUsers{user: &repository.User{ID: "u1", Email: "a@example.com", Name: "Ann", CreatedAt: now, UpdatedAt: now}}
+}
+
+func TestHandler(t *testing.T) {
+	ctx := context.Background()
+	users := newStub()
+	h := New(users)
+
+	created, err := h.CreateUser(ctx, &userv1.CreateUserRequest{Email: "b@example.com", Name: "Bob"})
+	if err != nil {
+		t.Fatalf("CreateUser() error = %v", err)
+	}
+	if created.GetUser().GetEmail() != "b@example.com" {
+		t.Errorf("CreateUser() = %v, want b@example.com", created.GetUser())
+	}
+
+	got, err := h.GetUser(ctx, &userv1.GetUserRequest{Id: "u1"})
+	if err != nil {
+		t.Fatalf("GetUser() error = %v", err)
+	}
+	u := got.GetUser()
+	if u.GetId() != "u1" || !u.GetCreatedAt().AsTime().Equal(users.user.CreatedAt) {
+		t.Errorf("GetUser() = %v", u)
+	}
+	if _, err := h.GetUser(ctx, &userv1.GetUserRequest{Id: "missing"}); errors.GetCode(err) != errors.CodeNotFound {
+		t.Errorf("GetUser(missing) code = %v, want %v", errors.GetCode(err), errors.CodeNotFound)
+	}
+
+	list, err := h.ListUsers(ctx, &userv1.ListUsersRequest{PageSize: 1})
+	if err != nil {
+		t.Fatalf("ListUsers() error = %v", err)
+	}
+	if len(list.GetUsers()) != 1 || list.GetNextPageToken() != "next" {
+		t.Errorf("ListUsers() = %v", list)
+	}
+
+	list, err = h.ListUsers(ctx, &userv1.ListUsersRequest{ReadMask: &fieldmaskpb.FieldMask{Paths: []string{"id", "name"}}})
+	if err != nil {
+		t.Fatalf("ListUsers(read_mask) error = %v", err)
+	}
+	if u := list.GetUsers()[0]; u.GetId() != "u1" || u.GetEmail() != "" || u.GetCreatedAt() != nil {
+		t.Errorf("ListUsers(read_mask) = %v, want only id and name", u)
+	}
+	if _, err := h.ListUsers(ctx, &userv1.ListUsersRequest{ReadMask: &fieldmaskpb.FieldMask{Paths: []string{"role"}}}); errors.GetCode(err) != errors.CodeInvalidInput {
+		t.Errorf("ListUsers(bad mask) code = %v, want %v", errors.GetCode(err), errors.CodeInvalidInput)
+	}
+
+	deleted, err := h.DeleteUser(ctx, &userv1.DeleteUserRequest{Id: "u1"})
+	if err != nil || !deleted.GetSuccess() || users.deleted != "u1" {
+		t.Errorf("DeleteUser() = %v, %v; deleted %q", deleted, err, users.deleted)
+	}
+
+	changed, err := h.ChangePassword(ctx, &userv1.ChangePasswordRequest{Id: "u1", CurrentPassword: "old", NewPassword: "new"})
+	if err != nil || !changed.GetSuccess() || strings.Join(users.changed, ",") != "u1,old,new" {
+		t.Errorf("ChangePassword() = %v, %v; changed %v", changed, err, users.changed)
+	}
+}
+
+func TestHandlerExportUsers(t *testing.T) {
+	users := newStub()
+	stream := &exportStream{}
+	if err := New(users).ExportUsers(&userv1.ExportUsersRequest{BatchSize: 50, IncludePii: true}, stream); err != nil {
+		t.Fatalf("ExportUsers() error = %v", err)
+	}
+	if users.exportBatch != 50 || !users.exportPII {
+		t.Errorf("ExportUsers() passed batch size %d, include PII %v", users.exportBatch, users.exportPII)
+	}
+	if len(stream.batches) != 2 || stream.batches[1].GetUsers()[0].GetEmail() != "a@example.com" {
+		t.Errorf("ExportUsers() sent %v, want two batches of the user", stream.batches)
+	}
+}
+
+// serve serves users over gRPC with the interceptors cmd/user-service
+// installs, auditing to recorder when it is not nil
+func serve(t *testing.T, users service.UserService, recorder *audit.Recorder) *grpc.ClientConn {
+	t.Helper()
+	resolver := tenant.NewResolver(&config.Tenancy{Tenants: []string{"acme", tenant.DefaultID}})
+	interceptors := []grpc.UnaryServerInterceptor{tenant.UnaryServerInterceptor(resolver)}
+	if recorder != nil {
+		interceptors = append(interceptors, audit.UnaryServerInterceptor(recorder, "user-service"))
+	}
+	s := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(interceptors...),
+		grpc.ChainStreamInterceptor(tenant.StreamServerInterceptor(resolver)),
+	)
+	userv1.RegisterUserServiceServer(s, New(users))
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	go s.Serve(listener)
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.Dial(listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// tenantUsers exports only the users of the tenant on the context, as the
+// repository does
+type tenantUsers struct {
+	service.UserService
+	byTenant map[string][]*repository.User
+}
+
+func (s *tenantUsers) ExportUsers(ctx context.Context, batchSize int, includePII bool, fn func([]*repository.User) error) error {
+	return fn(s.byTenant[tenant.ID(ctx)])
+}
+
+func TestHandlerExportUsersTenant(t *testing.T) {
+	users := &tenantUsers{byTenant: map[string][]*repository.User{
+		tenant.DefaultID: {{ID: "u1", Email: "default@example.com"}},
+		"acme":           {{ID: "u2", Email: "acme@example.com"}},
+	}}
+
+	conn := serve(t, users, nil)
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), tenant.MetadataKey, "acme")
+	stream, err := userv1.NewUserServiceClient(conn).ExportUsers(ctx, &userv1.ExportUsersRequest{IncludePii: true})
+	if err != nil {
+		t.Fatalf("ExportUsers() error = %v", err)
+	}
+	var got []string
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Recv() error = %v", err)
+		}
+		for _, u := range resp.GetUsers() {
+			got = append(got, u.GetId())
+		}
+	}
+	if len(got) != 1 || got[0] != "u2" {
+		t.Errorf("ExportUsers() for acme sent users %v, want only u2", got)
+	}
+}
+
+func TestHandlerAudit(t *testing.T) {
+	bus := events.NewMemoryBus()
+	defer bus.Close()
+	received := make(chan *audit.Event, 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go bus.Subscribe(ctx, audit.Topic, "test", func(ctx context.Context, event *events.Event) error {
+		var e audit.Event
+		if err := event.Decode(&e); err != nil {
+			return err
+		}
+		received <- &e
+		return nil
+	})
+	// Give the subscription time to register
+	time.Sleep(10 * time.Millisecond)
+
+	recorder := audit.NewRecorder(bus, audit.Topic, nil)
+	client := userv1.NewUserServiceClient(serve(t, newStub(), recorder))
+	ctx = metadata.AppendToOutgoingContext(ctx, audit.ActorMetadataKey, "admin")
+
+	if _, err := client.CreateUser(ctx, &userv1.CreateUserRequest{Email: "a@example.com", Name: "Ann"}); err != nil {
+		t.Fatalf("CreateUser() error = %v", err)
+	}
+	if _, err := client.GetUser(ctx, &userv1.GetUserRequest{Id: "u1"}); err != nil {
+		t.Fatalf("GetUser() error = %v", err)
+	}
+	if _, err := client.DeleteUser(ctx, &userv1.DeleteUserRequest{Id: "u1"}); err != nil {
+		t.Fatalf("DeleteUser() error = %v", err)
+	}
+	recorder.Close()
+
+	want := []struct{ action, resourceID string }{
+		{action: "/user.v1.UserService/CreateUser", resourceID: "u2"},
+		{action: "/user.v1.UserService/DeleteUser", resourceID: "u1"},
+	}
+	for _, w := range want {
+		select {
+		case e := <-received:
+			if e.Action != w.action || e.Actor != "admin" || e.ResourceType != "user" || e.ResourceID != w.resourceID || e.Outcome != audit.OutcomeSuccess {
+				t.Errorf("audit event = %+v, want %s of %s by admin", e, w.action, w.resourceID)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("%s was not audited", w.action)
+		}
+	}
+	select {
+	case e := <-received:
+		t.Errorf("unexpected audit event %+v; reads must not be audited", e)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestHandlerV2(t *testing.T) {
+	ctx := context.Background()
+	users := newStub()
+	h := NewV2(users)
+
+	got, err := h.GetUser(ctx, &userv2.GetUserRequest{Id: "u1", ReadMask: &fieldmaskpb.FieldMask{Paths: []string{"id", "name"}}})
+	if err != nil {
+		t.Fatalf("GetUser() error = %v", err)
+	}
+	if u := got.GetUser(); u.GetName() != "Ann" || u.GetEmail() != "" || u.GetCreateTime() != nil {
+		t.Errorf("GetUser() = %v, want only id and name", u)
+	}
+	if _, err := h.GetUser(ctx, &userv2.GetUserRequest{Id: "u1", ReadMask: &fieldmaskpb.FieldMask{Paths: []string{"role"}}}); errors.GetCode(err) != errors.CodeInvalidInput {
+		t.Errorf("GetUser(bad mask) code = %v, want %v", errors.GetCode(err), errors.CodeInvalidInput)
+	}
+
+	list, err := h.ListUsers(ctx, &userv2.ListUsersRequest{})
+	if err != nil {
+		t.Fatalf("ListUsers() error = %v", err)
+	}
+	if len(list.GetUsers()) != 1 || list.GetUsers()[0].GetCreateTime() == nil || list.GetNextPageToken() != "next" {
+		t.Errorf("ListUsers() = %v", list)
+	}
+
+	updated, err := h.UpdateUser(ctx, &userv2.UpdateUserRequest{User: &userv2.User{Id: "u1", Name: "Anna"}, UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"name"}}})
+	if err != nil {
+		t.Fatalf("UpdateUser() error = %v", err)
+	}
+	if updated.GetUser().GetName() != "Anna" {
+		t.Errorf("UpdateUser() name = %q, want Anna", updated.GetUser().GetName())
+	}
+
+	if _, err := h.DeleteUser(ctx, &userv2.DeleteUserRequest{Id: "u1"}); err != nil || users.deleted != "u1" {
+		t.Errorf("DeleteUser() error = %v, deleted %q", err, users.deleted)
+	}
+}
+
+// stubAuth issues fixed tokens
+type stubAuth struct {
+	service.AuthService
+	loggedOut string
+}
+
+func (s *stubAuth) Login(ctx context.Context, email, password string) (*repository.User, *service.Tokens, error) {
+	if password != "pw" {
+		return nil, nil, errors.WithCode(errors.New("invalid credentials"), errors.CodeUnauthorized)
+	}
+	return &repository.User{ID: "u1", Email: email}, &service.Tokens{AccessToken: "access", RefreshToken: "refresh", ExpiresIn: 15 * time.Minute}, nil
+}
+
+func (s *stubAuth) Logout(ctx context.Context, refreshToken string) error {
+	s.loggedOut = refreshToken
+	return nil
+}
+
+func TestAuthHandler(t *testing.T) {
+	ctx := context.Background()
+	stub := &stubAuth{}
+	h := NewAuth(stub)
+
+	resp, err := h.Login(ctx, &authv1.LoginRequest{Email: "a@example.com", Password: "pw"})
+	if err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+	if tokens := resp.GetTokens(); resp.GetUserId() != "u1" || tokens.GetExpiresIn() != 900 || tokens.GetTokenType() != "Bearer" {
+		t.Errorf("Login() = %v", resp)
+	}
+	if _, err := h.Login(ctx, &authv1.LoginRequest{Email: "a@example.com", Password: "wrong"}); errors.GetCode(err) != errors.CodeUnauthorized {
+		t.Errorf("Login(wrong password) code = %v, want %v", errors.GetCode(err), errors.CodeUnauthorized)
+	}
+
+	if _, err := h.Logout(ctx, &authv1.LogoutRequest{RefreshToken: "refresh"}); err != nil || stub.loggedOut != "refresh" {
+		t.Errorf("Logout() error = %v, logged out %q", err, stub.loggedOut)
+	}
+}
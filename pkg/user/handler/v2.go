@@ -0,0 +1,109 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package handler
+
+import (
+	"context"
+
+	userv2 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/user/v2"
+	"github.com/kevindiu/monorepo-go-example/internal/fieldmask"
+	"github.com/kevindiu/monorepo-go-example/pkg/user/repository"
+	"github.com/kevindiu/monorepo-go-example/pkg/user/service"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// handlerV2 implements userv2.UserServiceServer
+type handlerV2 struct {
+	userv2.UnimplementedUserServiceServer
+	users service.UserService
+}
+
+// NewV2 returns the v2 gRPC handler of users
+func NewV2(users service.UserService) userv2.UserServiceServer {
+	return &handlerV2{users: users}
+}
+
+// userToV2 converts a user to its v2 message
+func userToV2(u *repository.User) *userv2.User {
+	return &userv2.User{
+		Id:         u.ID,
+		Email:      u.Email,
+		Name:       u.Name,
+		CreateTime: timestamppb.New(u.CreatedAt),
+		UpdateTime: timestamppb.New(u.UpdatedAt),
+		Roles:      u.Roles,
+	}
+}
+
+// CreateUser creates a user
+func (h *handlerV2) CreateUser(ctx context.Context, req *userv2.CreateUserRequest) (*userv2.CreateUserResponse, error) {
+	user, err := h.users.CreateUser(ctx, req.GetUser().GetEmail(), req.GetUser().GetName())
+	if err != nil {
+		return nil, err
+	}
+	return &userv2.CreateUserResponse{User: userToV2(user)}, nil
+}
+
+// GetUser retrieves a user by ID, trimmed to read_mask
+func (h *handlerV2) GetUser(ctx context.Context, req *userv2.GetUserRequest) (*userv2.GetUserResponse, error) {
+	if err := fieldmask.Validate(&userv2.User{}, req.GetReadMask()); err != nil {
+		return nil, err
+	}
+	user, err := h.users.GetUser(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	pb := userToV2(user)
+	fieldmask.Trim(pb, req.GetReadMask())
+	return &userv2.GetUserResponse{User: pb}, nil
+}
+
+// ListUsers lists users, newest first, by cursor, trimmed to read_mask
+func (h *handlerV2) ListUsers(ctx context.Context, req *userv2.ListUsersRequest) (*userv2.ListUsersResponse, error) {
+	if err := fieldmask.Validate(&userv2.User{}, req.GetReadMask()); err != nil {
+		return nil, err
+	}
+	users, next, err := h.users.ListUsers(ctx, int(req.GetPageSize()), req.GetPageToken())
+	if err != nil {
+		return nil, err
+	}
+	resp := &userv2.ListUsersResponse{Users: make([]*userv2.User, len(users)), NextPageToken: next}
+	for i, u := range users {
+		resp.Users[i] = userToV2(u)
+		fieldmask.Trim(resp.Users[i], req.GetReadMask())
+	}
+	return resp, nil
+}
+
+// UpdateUser changes the fields of a user named by update_mask, or its
+// email and name when the mask is empty
+func (h *handlerV2) UpdateUser(ctx context.Context, req *userv2.UpdateUserRequest) (*userv2.UpdateUserResponse, error) {
+	u := req.GetUser()
+	user, err := h.users.UpdateUser(ctx, u.GetId(), u.GetEmail(), u.GetName(), u.GetRoles(), req.GetUpdateMask())
+	if err != nil {
+		return nil, err
+	}
+	return &userv2.UpdateUserResponse{User: userToV2(user)}, nil
+}
+
+// DeleteUser soft-deletes a user
+func (h *handlerV2) DeleteUser(ctx context.Context, req *userv2.DeleteUserRequest) (*userv2.DeleteUserResponse, error) {
+	if err := h.users.DeleteUser(ctx, req.GetId()); err != nil {
+		return nil, err
+	}
+	return &userv2.DeleteUserResponse{}, nil
+}
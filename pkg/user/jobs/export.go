@@ -0,0 +1,130 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package jobs
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"net/http"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/internal/jobs"
+	"github.com/kevindiu/monorepo-go-example/internal/mail"
+	"github.com/kevindiu/monorepo-go-example/internal/storage"
+	"github.com/kevindiu/monorepo-go-example/internal/tenant"
+	"github.com/kevindiu/monorepo-go-example/pkg/user/repository"
+)
+
+// ExportUsers is the job type of user exports
+const ExportUsers = "user.export"
+
+// ExportQueue is the job queue exports run from
+const ExportQueue = "export"
+
+// exportPageSize is the number of users read per query
+const exportPageSize = 500
+
+// exportLinkExpiry is how long the emailed download link stays valid
+const exportLinkExpiry = 24 * time.Hour
+
+// ExportRequest is the payload of a user export job
+type ExportRequest struct {
+	TenantID string `json:"tenant_id"`
+	// NotifyEmail, when set, is emailed the location of the finished export
+	NotifyEmail string `json:"notify_email,omitempty"`
+}
+
+// EnqueueExport defers a CSV export of the users of the context's tenant
+func EnqueueExport(ctx context.Context, exec jobs.Execer, notifyEmail string) (*jobs.Job, error) {
+	req := &ExportRequest{TenantID: tenant.ID(ctx), NotifyEmail: notifyEmail}
+	return jobs.Enqueue(ctx, exec, ExportUsers, req, jobs.WithQueue(ExportQueue))
+}
+
+// NewExportHandler returns the job handler that writes a tenant's users as
+// CSV to store. When notify is set, the requester is emailed a link to the
+// export through it.
+func NewExportHandler(repo repository.UserRepository, store storage.Storage, notify jobs.Execer) jobs.Handler {
+	return func(ctx context.Context, job *jobs.Job) error {
+		var req ExportRequest
+		if err := job.Decode(&req); err != nil {
+			return err
+		}
+		ctx = tenant.WithID(ctx, req.TenantID)
+
+		key := ExportKey(tenant.ID(ctx), job.ID)
+		if err := exportUsers(ctx, repo, store, key); err != nil {
+			return err
+		}
+
+		if notify == nil || req.NotifyEmail == "" {
+			return nil
+		}
+		link, err := store.SignedURL(ctx, key, http.MethodGet, exportLinkExpiry)
+		if err != nil {
+			return err
+		}
+		_, err = mail.Enqueue(ctx, notify, &mail.Message{
+			To:      []string{req.NotifyEmail},
+			Subject: "Your user export is ready",
+			Body:    "Your user export finished. Download it within 24 hours from:\n\n" + link,
+		})
+		return err
+	}
+}
+
+// ExportKey returns the storage key of the export produced by a job
+func ExportKey(tenantID, jobID string) string {
+	return "exports/" + tenantID + "/users-" + jobID + ".csv"
+}
+
+// exportUsers writes every user to key. The export is built in memory and
+// stored in one Put, so a retried job never leaves a partial export behind.
+func exportUsers(ctx context.Context, repo repository.UserRepository, store storage.Storage, key string) error {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write([]string{"id", "email", "name", "created_at", "updated_at"})
+
+	// Walk the table by ID so late pages cost the same as the first
+	for after := ""; ; {
+		users, err := repo.ListAfter(ctx, after, exportPageSize)
+		if err != nil {
+			return err
+		}
+		for _, u := range users {
+			w.Write([]string{
+				u.ID,
+				u.Email,
+				u.Name,
+				u.CreatedAt.Format(time.RFC3339),
+				u.UpdatedAt.Format(time.RFC3339),
+			})
+		}
+		if len(users) < exportPageSize {
+			break
+		}
+		after = users[len(users)-1].ID
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return errors.Wrap(err, "failed to write export")
+	}
+
+	return store.Put(ctx, key, bytes.NewReader(buf.Bytes()), "text/csv")
+}
@@ -0,0 +1,139 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/kevindiu/monorepo-go-example/internal/jobs"
+	"github.com/kevindiu/monorepo-go-example/internal/mail"
+	"github.com/kevindiu/monorepo-go-example/internal/storage"
+	"github.com/kevindiu/monorepo-go-example/internal/tenant"
+	"github.com/kevindiu/monorepo-go-example/pkg/user/repository"
+)
+
+// mockUserRepository serves users per tenant
+type mockUserRepository struct {
+	repository.UserRepository
+	users map[string][]*repository.User
+}
+
+func (m *mockUserRepository) ListAfter(ctx context.Context, afterID string, limit int) ([]*repository.User, error) {
+	var users []*repository.User
+	for _, u := range m.users[tenant.ID(ctx)] {
+		if u.ID > afterID && len(users) < limit {
+			users = append(users, u)
+		}
+	}
+	return users, nil
+}
+
+// mockExecer records enqueued job types
+type mockExecer struct {
+	jobTypes []string
+}
+
+func (m *mockExecer) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	m.jobTypes = append(m.jobTypes, args[2].(string))
+	return nil, nil
+}
+
+func TestExportHandler(t *testing.T) {
+	repo := &mockUserRepository{users: map[string][]*repository.User{
+		"acme":   {{ID: "u1", Email: "a@acme.test", Name: "A"}, {ID: "u2", Email: "b@acme.test", Name: "B"}},
+		"globex": {{ID: "u3", Email: "c@globex.test", Name: "C"}},
+	}}
+	store, err := storage.NewLocal(t.TempDir(), "http://files.test/files", "secret")
+	if err != nil {
+		t.Fatalf("NewLocal() error = %v", err)
+	}
+	notify := &mockExecer{}
+
+	payload, _ := json.Marshal(&ExportRequest{TenantID: "acme", NotifyEmail: "ops@acme.test"})
+	job := &jobs.Job{ID: "job-1", Type: ExportUsers, Payload: payload}
+
+	if err := NewExportHandler(repo, store, notify)(context.Background(), job); err != nil {
+		t.Fatalf("handler error = %v", err)
+	}
+
+	r, err := store.Get(context.Background(), ExportKey("acme", "job-1"))
+	if err != nil {
+		t.Fatalf("export not written: %v", err)
+	}
+	data, _ := io.ReadAll(r)
+	r.Close()
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 3 {
+		t.Errorf("export has %d lines, want header and 2 users", len(lines))
+	}
+	if strings.Contains(string(data), "globex") {
+		t.Error("export contains another tenant's users")
+	}
+
+	if len(notify.jobTypes) != 1 || notify.jobTypes[0] != mail.JobType {
+		t.Errorf("enqueued %v, want [%s]", notify.jobTypes, mail.JobType)
+	}
+}
+
+func TestExportWalksEveryPage(t *testing.T) {
+	users := make([]*repository.User, 2*exportPageSize+1)
+	for i := range users {
+		users[i] = &repository.User{ID: fmt.Sprintf("u%05d", i)}
+	}
+	repo := &mockUserRepository{users: map[string][]*repository.User{"acme": users}}
+	store, err := storage.NewLocal(t.TempDir(), "http://files.test/files", "secret")
+	if err != nil {
+		t.Fatalf("NewLocal() error = %v", err)
+	}
+
+	ctx := tenant.WithID(context.Background(), "acme")
+	if err := exportUsers(ctx, repo, store, "users.csv"); err != nil {
+		t.Fatalf("exportUsers() error = %v", err)
+	}
+
+	r, err := store.Get(ctx, "users.csv")
+	if err != nil {
+		t.Fatalf("export not written: %v", err)
+	}
+	data, _ := io.ReadAll(r)
+	r.Close()
+	if lines := strings.Count(string(data), "\n"); lines != len(users)+1 {
+		t.Errorf("export has %d lines, want header and %d users", lines, len(users))
+	}
+}
+
+func TestEnqueueExport(t *testing.T) {
+	exec := &mockExecer{}
+	job, err := EnqueueExport(tenant.WithID(context.Background(), "acme"), exec, "")
+	if err != nil {
+		t.Fatalf("EnqueueExport() error = %v", err)
+	}
+	if job.Queue != ExportQueue {
+		t.Errorf("queue = %s, want %s", job.Queue, ExportQueue)
+	}
+
+	var req ExportRequest
+	if err := job.Decode(&req); err != nil || req.TenantID != "acme" {
+		t.Errorf("payload tenant = %q (%v), want acme", req.TenantID, err)
+	}
+}
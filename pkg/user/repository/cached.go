@@ -0,0 +1,197 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package repository
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/cache"
+	"github.com/kevindiu/monorepo-go-example/internal/crypto"
+	"github.com/kevindiu/monorepo-go-example/internal/db"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/internal/log"
+	"github.com/kevindiu/monorepo-go-example/internal/tenant"
+)
+
+// cachedUserRepository serves GetByID and GetByEmail from a cache in front
+// of a repository. A cache failure falls through to the repository, so the
+// cache can be lost without failing lookups.
+type cachedUserRepository struct {
+	UserRepository
+	cache  cache.Cache
+	ttl    time.Duration
+	enc    *crypto.Encryptor
+	logger *log.Logger
+}
+
+// NewCachedUserRepository caches user lookups of repo in c for ttl. Update
+// and Delete invalidate the user's entries, after the commit when they run
+// in a db.TxManager transaction, inside which lookups bypass the cache. With enc, which should be the
+// encryptor of repo, emails are cached encrypted, as they are stored, and
+// entries are keyed by their blind index; without it, keys carry a hash
+// of the email. Emails never appear in keys.
+func NewCachedUserRepository(repo UserRepository, c cache.Cache, ttl time.Duration, enc *crypto.Encryptor, logger *log.Logger) UserRepository {
+	if logger == nil {
+		logger = log.NewDefault()
+	}
+	return &cachedUserRepository{UserRepository: repo, cache: c, ttl: ttl, enc: enc, logger: logger}
+}
+
+// GetByID retrieves a user by ID, from the cache when possible
+func (r *cachedUserRepository) GetByID(ctx context.Context, id string) (*User, error) {
+	return r.lookup(ctx, r.idKey(ctx, id), func() (*User, error) {
+		return r.UserRepository.GetByID(ctx, id)
+	})
+}
+
+// GetByEmail retrieves a user by email, from the cache when possible
+func (r *cachedUserRepository) GetByEmail(ctx context.Context, email string) (*User, error) {
+	return r.lookup(ctx, r.emailKey(ctx, email), func() (*User, error) {
+		return r.UserRepository.GetByEmail(ctx, email)
+	})
+}
+
+// Update updates a user and invalidates its entries, including the one
+// under its previous email
+func (r *cachedUserRepository) Update(ctx context.Context, user *User, fields ...string) (*User, error) {
+	previous, err := r.UserRepository.GetByID(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := r.UserRepository.Update(ctx, user, fields...)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.invalidate(ctx, previous, updated); err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// Delete deletes a user and invalidates its entries
+func (r *cachedUserRepository) Delete(ctx context.Context, id string) error {
+	previous, err := r.UserRepository.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := r.UserRepository.Delete(ctx, id); err != nil {
+		return err
+	}
+	return r.invalidate(ctx, previous)
+}
+
+// lookup returns the user cached under key, or loads and caches it
+func (r *cachedUserRepository) lookup(ctx context.Context, key string, load func() (*User, error)) (*User, error) {
+	// A transaction may read users it changed, which must not be served
+	// from or written to the cache before they commit
+	if db.InTransaction(ctx) {
+		return load()
+	}
+
+	data, err := r.cache.Get(ctx, key)
+	if err == nil {
+		if user, err := r.decode(ctx, data); err == nil {
+			return user, nil
+		}
+		r.logger.Warn("Discarding undecodable cached user", log.String("key", key))
+	} else if errors.GetCode(err) != errors.CodeNotFound {
+		r.logger.Warn("User cache unavailable", log.String("key", key), log.Error(err))
+	}
+
+	user, err := load()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err = r.encode(ctx, user)
+	if err == nil {
+		err = r.cache.Set(ctx, key, data, r.ttl)
+	}
+	if err != nil {
+		r.logger.Warn("Failed to cache user", log.String("key", key), log.Error(err))
+	}
+	return user, nil
+}
+
+// encode returns the cache entry of user, with its email encrypted when
+// emails are encrypted at rest
+func (r *cachedUserRepository) encode(ctx context.Context, user *User) ([]byte, error) {
+	entry := *user
+	if r.enc != nil {
+		sealed, err := r.enc.Encrypt(ctx, user.Email)
+		if err != nil {
+			return nil, err
+		}
+		entry.Email = sealed
+	}
+	return json.Marshal(&entry)
+}
+
+// decode returns the user of a cache entry written by encode
+func (r *cachedUserRepository) decode(ctx context.Context, data []byte) (*User, error) {
+	var user User
+	if err := json.Unmarshal(data, &user); err != nil {
+		return nil, err
+	}
+	if r.enc != nil {
+		email, err := r.enc.Decrypt(ctx, user.Email)
+		if err != nil {
+			return nil, err
+		}
+		user.Email = email
+	}
+	return &user, nil
+}
+
+// invalidate removes the entries of users once their change commits.
+// Unlike a failed fill, a failed invalidation is returned: the cache would
+// otherwise serve the old user until it expires.
+func (r *cachedUserRepository) invalidate(ctx context.Context, users ...*User) error {
+	var keys []string
+	for _, user := range users {
+		keys = append(keys, r.idKey(ctx, user.ID), r.emailKey(ctx, user.Email))
+	}
+	return db.AfterCommit(ctx, func() error {
+		if err := r.cache.Delete(ctx, keys...); err != nil {
+			return errors.Wrap(err, "failed to invalidate cached user")
+		}
+		return nil
+	})
+}
+
+// idKey and emailKey scope entries to the tenant on the context, like the
+// queries they cache
+func (r *cachedUserRepository) idKey(ctx context.Context, id string) string {
+	return "user:" + tenant.ID(ctx) + ":id:" + id
+}
+
+func (r *cachedUserRepository) emailKey(ctx context.Context, email string) string {
+	var index string
+	if r.enc != nil {
+		index = r.enc.Index(email)
+	} else {
+		sum := sha256.Sum256([]byte(email))
+		index = hex.EncodeToString(sum[:])
+	}
+	return "user:" + tenant.ID(ctx) + ":email:" + index
+}
@@ -0,0 +1,210 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package repository
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/cache"
+	"github.com/kevindiu/monorepo-go-example/internal/crypto"
+	"github.com/kevindiu/monorepo-go-example/internal/log"
+	"github.com/kevindiu/monorepo-go-example/internal/tenant"
+)
+
+// fakeUserRepository keeps users in memory and counts lookups
+type fakeUserRepository struct {
+	UserRepository
+	users   map[string]*User
+	lookups int
+}
+
+func (f *fakeUserRepository) GetByID(ctx context.Context, id string) (*User, error) {
+	f.lookups++
+	if u, ok := f.users[id]; ok {
+		copied := *u
+		return &copied, nil
+	}
+	return nil, errors.New("user not found")
+}
+
+func (f *fakeUserRepository) GetByEmail(ctx context.Context, email string) (*User, error) {
+	f.lookups++
+	for _, u := range f.users {
+		if u.Email == email {
+			copied := *u
+			return &copied, nil
+		}
+	}
+	return nil, errors.New("user not found")
+}
+
+func (f *fakeUserRepository) Update(ctx context.Context, user *User, fields ...string) (*User, error) {
+	copied := *user
+	f.users[user.ID] = &copied
+	return user, nil
+}
+
+func (f *fakeUserRepository) Delete(ctx context.Context, id string) error {
+	delete(f.users, id)
+	return nil
+}
+
+// failingCache fails every operation
+type failingCache struct{ cache.Cache }
+
+func (failingCache) Get(context.Context, string) ([]byte, error) { return nil, errors.New("down") }
+
+func (failingCache) Set(context.Context, string, []byte, time.Duration) error {
+	return errors.New("down")
+}
+
+func (failingCache) Delete(context.Context, ...string) error { return errors.New("down") }
+
+// recordingCache records the entries written to a cache
+type recordingCache struct {
+	cache.Cache
+	entries map[string][]byte
+}
+
+func (c *recordingCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	c.entries[key] = value
+	return c.Cache.Set(ctx, key, value, ttl)
+}
+
+func TestCachedUserRepositoryKeepsEmailsOut(t *testing.T) {
+	keys, err := crypto.NewLocalKeys(map[string][]byte{"k1": bytes.Repeat([]byte{1}, crypto.DataKeySize)}, "k1")
+	if err != nil {
+		t.Fatalf("NewLocalKeys() error = %v", err)
+	}
+	enc, err := crypto.NewEncryptor(keys, bytes.Repeat([]byte{'i'}, crypto.DataKeySize), time.Hour)
+	if err != nil {
+		t.Fatalf("NewEncryptor() error = %v", err)
+	}
+
+	for name, enc := range map[string]*crypto.Encryptor{"encrypted": enc, "plaintext": nil} {
+		t.Run(name, func(t *testing.T) {
+			fake := &fakeUserRepository{users: map[string]*User{"u1": {ID: "u1", Email: "a@example.com", Name: "A"}}}
+			recorder := &recordingCache{Cache: cache.NewMemory(100), entries: map[string][]byte{}}
+			repo := NewCachedUserRepository(fake, recorder, time.Minute, enc, log.NewDefault())
+			ctx := context.Background()
+
+			for i := 0; i < 2; i++ {
+				if u, err := repo.GetByEmail(ctx, "a@example.com"); err != nil || u.Email != "a@example.com" {
+					t.Fatalf("GetByEmail() = %v, %v, want the user with its email", u, err)
+				}
+				if u, err := repo.GetByID(ctx, "u1"); err != nil || u.Email != "a@example.com" {
+					t.Fatalf("GetByID() = %v, %v, want the user with its email", u, err)
+				}
+			}
+			if fake.lookups != 2 {
+				t.Errorf("repository lookups = %d, want 2", fake.lookups)
+			}
+
+			for key, value := range recorder.entries {
+				if strings.Contains(key, "a@example.com") {
+					t.Errorf("cache key %q holds the email", key)
+				}
+				if enc != nil && bytes.Contains(value, []byte("a@example.com")) {
+					t.Errorf("cache entry %q holds the plaintext email", key)
+				}
+			}
+		})
+	}
+}
+
+func TestCachedUserRepositoryLookups(t *testing.T) {
+	fake := &fakeUserRepository{users: map[string]*User{"u1": {ID: "u1", Email: "a@example.com", Name: "A"}}}
+	repo := NewCachedUserRepository(fake, cache.NewMemory(100), time.Minute, nil, log.NewDefault())
+	ctx := tenant.WithID(context.Background(), "acme")
+
+	for i := 0; i < 3; i++ {
+		if _, err := repo.GetByID(ctx, "u1"); err != nil {
+			t.Fatalf("GetByID() error = %v", err)
+		}
+		if _, err := repo.GetByEmail(ctx, "a@example.com"); err != nil {
+			t.Fatalf("GetByEmail() error = %v", err)
+		}
+	}
+	if fake.lookups != 2 {
+		t.Errorf("repository lookups = %d, want 2", fake.lookups)
+	}
+
+	// Entries are scoped to the tenant
+	if _, err := repo.GetByID(tenant.WithID(context.Background(), "globex"), "u1"); err != nil {
+		t.Fatalf("GetByID() error = %v", err)
+	}
+	if fake.lookups != 3 {
+		t.Errorf("repository lookups = %d, want another tenant to miss", fake.lookups)
+	}
+
+	// Misses are not cached
+	for i := 0; i < 2; i++ {
+		if _, err := repo.GetByID(ctx, "missing"); err == nil {
+			t.Fatal("GetByID() should fail for a missing user")
+		}
+	}
+	if fake.lookups != 5 {
+		t.Errorf("repository lookups = %d, want misses to reach the repository", fake.lookups)
+	}
+}
+
+func TestCachedUserRepositoryInvalidates(t *testing.T) {
+	fake := &fakeUserRepository{users: map[string]*User{"u1": {ID: "u1", Email: "a@example.com", Name: "A"}}}
+	repo := NewCachedUserRepository(fake, cache.NewMemory(100), time.Minute, nil, log.NewDefault())
+	ctx := context.Background()
+
+	repo.GetByID(ctx, "u1")
+	repo.GetByEmail(ctx, "a@example.com")
+
+	if _, err := repo.Update(ctx, &User{ID: "u1", Email: "b@example.com", Name: "B"}); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if u, err := repo.GetByID(ctx, "u1"); err != nil || u.Name != "B" {
+		t.Errorf("GetByID() = %v, %v after update, want the updated user", u, err)
+	}
+	if _, err := repo.GetByEmail(ctx, "a@example.com"); err == nil {
+		t.Error("GetByEmail() served the previous email after update")
+	}
+
+	if err := repo.Delete(ctx, "u1"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := repo.GetByID(ctx, "u1"); err == nil {
+		t.Error("GetByID() served a deleted user")
+	}
+	if _, err := repo.GetByEmail(ctx, "b@example.com"); err == nil {
+		t.Error("GetByEmail() served a deleted user")
+	}
+}
+
+func TestCachedUserRepositoryCacheFailure(t *testing.T) {
+	fake := &fakeUserRepository{users: map[string]*User{"u1": {ID: "u1", Email: "a@example.com"}}}
+	repo := NewCachedUserRepository(fake, failingCache{}, time.Minute, nil, log.NewDefault())
+	ctx := context.Background()
+
+	if _, err := repo.GetByID(ctx, "u1"); err != nil {
+		t.Errorf("GetByID() error = %v, want lookups to fall through to the repository", err)
+	}
+	if _, err := repo.Update(ctx, &User{ID: "u1", Email: "a@example.com"}); err == nil {
+		t.Error("Update() should fail when the cache cannot be invalidated")
+	}
+}
@@ -0,0 +1,45 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package repository
+
+import (
+	"context"
+
+	"github.com/kevindiu/monorepo-go-example/internal/db"
+	"github.com/kevindiu/monorepo-go-example/internal/events"
+	"github.com/kevindiu/monorepo-go-example/internal/outbox"
+	userevents "github.com/kevindiu/monorepo-go-example/pkg/user/events"
+)
+
+// enqueue writes a user event to the outbox in tx, so that the event is
+// published by the outbox relay if and only if the change commits. Only
+// the ID is sent for deleted users.
+func enqueue(ctx context.Context, tx db.Executor, eventType string, user *User) error {
+	payload := userevents.User{ID: user.ID}
+	if eventType != userevents.TypeUserDeleted {
+		payload.Email = user.Email
+		payload.Name = user.Name
+		payload.CreatedAt = user.CreatedAt
+		payload.UpdatedAt = user.UpdatedAt
+	}
+
+	event, err := events.NewEvent(eventType, user.ID, payload)
+	if err != nil {
+		return err
+	}
+	return outbox.Enqueue(ctx, tx, userevents.Topic, event)
+}
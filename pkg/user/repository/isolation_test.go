@@ -0,0 +1,185 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build integration
+
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/kevindiu/monorepo-go-example/internal/config"
+	"github.com/kevindiu/monorepo-go-example/internal/db"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/internal/tenant"
+)
+
+// These tests run against the database configured through the usual
+// DATABASE_* environment variables, migrated to at least version 009:
+//
+//	make test-integration
+
+func setupTestDB(t *testing.T) *db.DB {
+	t.Helper()
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("failed to load configuration: %v", err)
+	}
+	database, err := db.Connect(cfg.Database)
+	if err != nil {
+		t.Fatalf("failed to connect to database: %v", err)
+	}
+	t.Cleanup(func() { database.Close() })
+	return database
+}
+
+func TestUserRepository_TenantIsolation(t *testing.T) {
+	repo := NewUserRepository(setupTestDB(t))
+
+	tenantA := tenant.WithID(context.Background(), "tenant-a-"+uuid.New().String()[:8])
+	tenantB := tenant.WithID(context.Background(), "tenant-b-"+uuid.New().String()[:8])
+
+	// The same email may exist once per tenant
+	email := uuid.New().String() + "@example.com"
+	userA, err := repo.Create(tenantA, &User{ID: uuid.New().String(), Email: email, Name: "A"})
+	if err != nil {
+		t.Fatalf("Create(tenant A) error = %v", err)
+	}
+	t.Cleanup(func() { repo.Delete(tenantA, userA.ID) })
+
+	userB, err := repo.Create(tenantB, &User{ID: uuid.New().String(), Email: email, Name: "B"})
+	if err != nil {
+		t.Fatalf("Create(tenant B) with tenant A's email error = %v", err)
+	}
+	t.Cleanup(func() { repo.Delete(tenantB, userB.ID) })
+
+	t.Run("get by ID", func(t *testing.T) {
+		if _, err := repo.GetByID(tenantB, userA.ID); errors.GetCode(err) != errors.CodeNotFound {
+			t.Errorf("GetByID() across tenants error = %v, want not found", err)
+		}
+	})
+
+	t.Run("get by email", func(t *testing.T) {
+		got, err := repo.GetByEmail(tenantB, email)
+		if err != nil {
+			t.Fatalf("GetByEmail() error = %v", err)
+		}
+		if got.ID != userB.ID {
+			t.Errorf("GetByEmail() returned user %s of tenant %s, want %s", got.ID, got.TenantID, userB.ID)
+		}
+	})
+
+	t.Run("get many", func(t *testing.T) {
+		got, err := repo.GetMany(tenantB, []string{userA.ID, userB.ID, uuid.New().String()})
+		if err != nil {
+			t.Fatalf("GetMany() error = %v", err)
+		}
+		if len(got) != 1 || got[0].ID != userB.ID {
+			t.Errorf("GetMany() = %v, want only tenant B's user", got)
+		}
+	})
+
+	t.Run("search", func(t *testing.T) {
+		got, err := repo.Search(tenantB, email[:8], 0, 10)
+		if err != nil {
+			t.Fatalf("Search() error = %v", err)
+		}
+		if len(got) != 1 || got[0].ID != userB.ID {
+			t.Errorf("Search() = %v, want only tenant B's user", got)
+		}
+	})
+
+	t.Run("list", func(t *testing.T) {
+		users, err := repo.List(tenantB, nil, 100)
+		if err != nil {
+			t.Fatalf("List() error = %v", err)
+		}
+		for _, u := range users {
+			if u.ID == userA.ID {
+				t.Error("List() returned a user of another tenant")
+			}
+		}
+	})
+
+	t.Run("list after", func(t *testing.T) {
+		users, err := repo.ListAfter(tenantB, "", 100)
+		if err != nil {
+			t.Fatalf("ListAfter() error = %v", err)
+		}
+		for _, u := range users {
+			if u.ID == userA.ID {
+				t.Error("ListAfter() returned a user of another tenant")
+			}
+		}
+	})
+
+	t.Run("update", func(t *testing.T) {
+		_, err := repo.Update(tenantB, &User{ID: userA.ID, Email: email, Name: "hijacked"})
+		if errors.GetCode(err) != errors.CodeNotFound {
+			t.Errorf("Update() across tenants error = %v, want not found", err)
+		}
+		got, err := repo.GetByID(tenantA, userA.ID)
+		if err != nil {
+			t.Fatalf("GetByID() error = %v", err)
+		}
+		if got.Name != "A" {
+			t.Errorf("Update() across tenants changed the name to %q", got.Name)
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		if err := repo.Delete(tenantB, userA.ID); errors.GetCode(err) != errors.CodeNotFound {
+			t.Errorf("Delete() across tenants error = %v, want not found", err)
+		}
+		if _, err := repo.GetByID(tenantA, userA.ID); err != nil {
+			t.Errorf("Delete() across tenants removed the user: %v", err)
+		}
+	})
+
+	t.Run("undelete and purge", func(t *testing.T) {
+		if err := repo.Purge(tenantA, userA.ID); errors.GetCode(err) != errors.CodeInvalidInput {
+			t.Errorf("Purge() of a live user error = %v, want invalid input", err)
+		}
+		if err := repo.Delete(tenantA, userA.ID); err != nil {
+			t.Fatalf("Delete() error = %v", err)
+		}
+		if _, err := repo.Undelete(tenantB, userA.ID); errors.GetCode(err) != errors.CodeNotFound {
+			t.Errorf("Undelete() across tenants error = %v, want not found", err)
+		}
+		if _, err := repo.Undelete(tenantA, userA.ID); err != nil {
+			t.Fatalf("Undelete() error = %v", err)
+		}
+		if _, err := repo.GetByID(tenantA, userA.ID); err != nil {
+			t.Errorf("GetByID() after Undelete() error = %v", err)
+		}
+
+		if err := repo.Delete(tenantA, userA.ID); err != nil {
+			t.Fatalf("Delete() error = %v", err)
+		}
+		if err := repo.Purge(tenantB, userA.ID); errors.GetCode(err) != errors.CodeNotFound {
+			t.Errorf("Purge() across tenants error = %v, want not found", err)
+		}
+		if err := repo.Purge(tenantA, userA.ID); err != nil {
+			t.Fatalf("Purge() error = %v", err)
+		}
+		if _, err := repo.Undelete(tenantA, userA.ID); errors.GetCode(err) != errors.CodeNotFound {
+			t.Errorf("Undelete() after Purge() error = %v, want not found", err)
+		}
+	})
+}
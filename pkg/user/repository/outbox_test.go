@@ -0,0 +1,93 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build integration
+
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/kevindiu/monorepo-go-example/internal/events"
+	"github.com/kevindiu/monorepo-go-example/internal/tenant"
+	userevents "github.com/kevindiu/monorepo-go-example/pkg/user/events"
+)
+
+func TestUserRepository_OutboxEvents(t *testing.T) {
+	database := setupTestDB(t)
+	repo := NewUserRepository(database)
+	ctx := tenant.WithID(context.Background(), "tenant-outbox-"+uuid.New().String()[:8])
+
+	user, err := repo.Create(ctx, &User{ID: uuid.New().String(), Email: uuid.New().String() + "@example.com", Name: "Outbox"})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	t.Cleanup(func() {
+		database.Exec(`DELETE FROM outbox_events WHERE event_key = $1`, user.ID)
+		database.Exec(`DELETE FROM users WHERE id = $1`, user.ID)
+	})
+
+	user.Name = "Renamed"
+	if _, err := repo.Update(ctx, user); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if err := repo.Delete(ctx, user.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+
+	rows, err := database.Query(`
+		SELECT payload FROM outbox_events
+		WHERE topic = $1 AND event_key = $2
+		ORDER BY created_at ASC
+	`, userevents.Topic, user.ID)
+	if err != nil {
+		t.Fatalf("query outbox error = %v", err)
+	}
+	defer rows.Close()
+
+	var got []*events.Event
+	for rows.Next() {
+		var payload []byte
+		if err := rows.Scan(&payload); err != nil {
+			t.Fatalf("scan outbox error = %v", err)
+		}
+		event, err := events.Unmarshal(payload)
+		if err != nil {
+			t.Fatalf("Unmarshal() error = %v", err)
+		}
+		got = append(got, event)
+	}
+
+	want := []string{userevents.TypeUserCreated, userevents.TypeUserUpdated, userevents.TypeUserDeleted}
+	if len(got) != len(want) {
+		t.Fatalf("got %d outbox events, want %d", len(got), len(want))
+	}
+	for i, event := range got {
+		if event.Type != want[i] {
+			t.Errorf("event %d type = %s, want %s", i, event.Type, want[i])
+		}
+	}
+
+	var updated userevents.User
+	if err := got[1].Decode(&updated); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if updated.Name != "Renamed" {
+		t.Errorf("user.updated name = %q, want Renamed", updated.Name)
+	}
+}
@@ -0,0 +1,155 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/db"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/internal/tenant"
+)
+
+// Session is a login of a user, kept alive with rotating refresh tokens.
+// Only hashes of the tokens are stored.
+type Session struct {
+	ID                string
+	TenantID          string
+	UserID            string
+	TokenHash         string
+	PreviousTokenHash string
+	CreatedAt         time.Time
+	RefreshedAt       time.Time
+	ExpiresAt         time.Time
+	RevokedAt         *time.Time
+}
+
+// SessionRepository persists the sessions of issued refresh tokens. Every
+// operation is scoped to the tenant on the context.
+type SessionRepository interface {
+	CreateSession(ctx context.Context, session *Session) error
+	GetSession(ctx context.Context, id string) (*Session, error)
+	RotateSession(ctx context.Context, id, tokenHash, newTokenHash string) error
+	RevokeSession(ctx context.Context, id string) error
+	RevokeUserSessions(ctx context.Context, userID string) error
+}
+
+type sessionRepository struct {
+	db *db.DB
+}
+
+// NewSessionRepository creates a new session repository
+func NewSessionRepository(database *db.DB) SessionRepository {
+	return &sessionRepository{db: database}
+}
+
+// CreateSession stores a new session
+func (r *sessionRepository) CreateSession(ctx context.Context, session *Session) error {
+	query := `
+		INSERT INTO auth_sessions (id, tenant_id, user_id, token_hash, created_at, refreshed_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	session.TenantID = tenant.ID(ctx)
+	_, err := r.db.Executor(ctx).ExecContext(ctx, query,
+		session.ID, session.TenantID, session.UserID, session.TokenHash,
+		session.CreatedAt, session.RefreshedAt, session.ExpiresAt,
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to create session")
+	}
+	return nil
+}
+
+// GetSession retrieves a session by ID, including revoked and expired ones
+func (r *sessionRepository) GetSession(ctx context.Context, id string) (*Session, error) {
+	query := `
+		SELECT id, tenant_id, user_id, token_hash, previous_token_hash, created_at, refreshed_at, expires_at, revoked_at
+		FROM auth_sessions
+		WHERE tenant_id = $1 AND id = $2
+	`
+
+	var (
+		session  Session
+		previous sql.NullString
+		revoked  sql.NullTime
+	)
+	err := r.db.Executor(ctx).QueryRowContext(ctx, query, tenant.ID(ctx), id).Scan(
+		&session.ID, &session.TenantID, &session.UserID, &session.TokenHash, &previous,
+		&session.CreatedAt, &session.RefreshedAt, &session.ExpiresAt, &revoked,
+	)
+	if err == sql.ErrNoRows {
+		return nil, errors.WithCode(errors.New("session not found"), errors.CodeNotFound)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get session")
+	}
+
+	session.PreviousTokenHash = previous.String
+	if revoked.Valid {
+		session.RevokedAt = &revoked.Time
+	}
+	return &session, nil
+}
+
+// RotateSession replaces the refresh token hash of an active session,
+// keeping the replaced one as its previous hash. It fails with a conflict
+// when tokenHash is no longer current, e.g. because a concurrent refresh
+// rotated it first.
+func (r *sessionRepository) RotateSession(ctx context.Context, id, tokenHash, newTokenHash string) error {
+	query := `
+		UPDATE auth_sessions
+		SET token_hash = $4, previous_token_hash = token_hash, refreshed_at = $5
+		WHERE tenant_id = $1 AND id = $2 AND token_hash = $3 AND revoked_at IS NULL
+	`
+
+	result, err := r.db.Executor(ctx).ExecContext(ctx, query, tenant.ID(ctx), id, tokenHash, newTokenHash, time.Now())
+	if err != nil {
+		return errors.Wrap(err, "failed to rotate session")
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "failed to get rows affected")
+	}
+	if rowsAffected == 0 {
+		return errors.WithCode(errors.New("session was rotated concurrently"), errors.CodeConflict)
+	}
+	return nil
+}
+
+// RevokeSession revokes a session. Revoking a revoked session does
+// nothing.
+func (r *sessionRepository) RevokeSession(ctx context.Context, id string) error {
+	query := `UPDATE auth_sessions SET revoked_at = COALESCE(revoked_at, $3) WHERE tenant_id = $1 AND id = $2`
+
+	if _, err := r.db.Executor(ctx).ExecContext(ctx, query, tenant.ID(ctx), id, time.Now()); err != nil {
+		return errors.Wrap(err, "failed to revoke session")
+	}
+	return nil
+}
+
+// RevokeUserSessions revokes every active session of a user
+func (r *sessionRepository) RevokeUserSessions(ctx context.Context, userID string) error {
+	query := `UPDATE auth_sessions SET revoked_at = $3 WHERE tenant_id = $1 AND user_id = $2 AND revoked_at IS NULL`
+
+	if _, err := r.db.Executor(ctx).ExecContext(ctx, query, tenant.ID(ctx), userID, time.Now()); err != nil {
+		return errors.Wrap(err, "failed to revoke user sessions")
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build integration
+
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kevindiu/monorepo-go-example/internal/cache"
+	"github.com/kevindiu/monorepo-go-example/internal/db"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/internal/tenant"
+)
+
+func TestUserRepository_WithinTransaction(t *testing.T) {
+	database := setupTestDB(t)
+	repo := NewUserRepository(database)
+	txm := db.NewTxManager(database)
+	ctx := tenant.WithID(context.Background(), "tenant-tx-"+uuid.New().String()[:8])
+
+	t.Run("rollback", func(t *testing.T) {
+		var id string
+		errAbort := errors.New("abort")
+		err := txm.WithinTransaction(ctx, func(ctx context.Context) error {
+			user, err := repo.Create(ctx, &User{ID: uuid.New().String(), Email: uuid.New().String() + "@example.com", Name: "Rolled back"})
+			if err != nil {
+				return err
+			}
+			id = user.ID
+			// Reads in the transaction see its writes
+			if _, err := repo.GetByID(ctx, id); err != nil {
+				t.Errorf("GetByID() in transaction error = %v", err)
+			}
+			return errAbort
+		})
+		if err != errAbort {
+			t.Fatalf("WithinTransaction() error = %v, want %v", err, errAbort)
+		}
+		if _, err := repo.GetByID(ctx, id); errors.GetCode(err) != errors.CodeNotFound {
+			t.Errorf("GetByID() after rollback error = %v, want not found", err)
+		}
+	})
+
+	t.Run("commit", func(t *testing.T) {
+		var user *User
+		err := txm.WithinTransaction(ctx, func(ctx context.Context) error {
+			var err error
+			user, err = repo.Create(ctx, &User{ID: uuid.New().String(), Email: uuid.New().String() + "@example.com", Name: "Committed"})
+			if err != nil {
+				return err
+			}
+			user.Name = "Renamed"
+			_, err = repo.Update(ctx, user, FieldName)
+			return err
+		})
+		if err != nil {
+			t.Fatalf("WithinTransaction() error = %v", err)
+		}
+		t.Cleanup(func() { repo.Delete(ctx, user.ID) })
+
+		got, err := repo.GetByID(ctx, user.ID)
+		if err != nil {
+			t.Fatalf("GetByID() after commit error = %v", err)
+		}
+		if got.Name != "Renamed" {
+			t.Errorf("Name = %q, want %q", got.Name, "Renamed")
+		}
+	})
+
+	t.Run("cached", func(t *testing.T) {
+		cached := NewCachedUserRepository(repo, cache.NewMemory(100), time.Minute, nil, nil)
+
+		// A rolled back user must not be left in the cache
+		var id string
+		txm.WithinTransaction(ctx, func(ctx context.Context) error {
+			user, err := cached.Create(ctx, &User{ID: uuid.New().String(), Email: uuid.New().String() + "@example.com", Name: "Rolled back"})
+			if err != nil {
+				return err
+			}
+			id = user.ID
+			cached.GetByID(ctx, id)
+			return errors.New("abort")
+		})
+		if _, err := cached.GetByID(ctx, id); errors.GetCode(err) != errors.CodeNotFound {
+			t.Errorf("GetByID() after rollback error = %v, want not found", err)
+		}
+
+		// An update committed in a transaction replaces the cached user
+		user, err := cached.Create(ctx, &User{ID: uuid.New().String(), Email: uuid.New().String() + "@example.com", Name: "Cached"})
+		if err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		t.Cleanup(func() { repo.Delete(ctx, user.ID) })
+		cached.GetByID(ctx, user.ID)
+		err = txm.WithinTransaction(ctx, func(ctx context.Context) error {
+			user.Name = "Renamed"
+			_, err := cached.Update(ctx, user, FieldName)
+			return err
+		})
+		if err != nil {
+			t.Fatalf("WithinTransaction() error = %v", err)
+		}
+		if got, err := cached.GetByID(ctx, user.ID); err != nil || got.Name != "Renamed" {
+			t.Errorf("GetByID() after commit = %v, %v, want the renamed user", got, err)
+		}
+	})
+}
@@ -19,70 +19,154 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"fmt"
+	"strings"
 	"time"
 
+	"github.com/kevindiu/monorepo-go-example/internal/crypto"
 	"github.com/kevindiu/monorepo-go-example/internal/db"
 	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/internal/pagination"
+	"github.com/kevindiu/monorepo-go-example/internal/tenant"
+	userevents "github.com/kevindiu/monorepo-go-example/pkg/user/events"
+	"github.com/lib/pq"
 )
 
 // User represents a user entity
 type User struct {
 	ID        string    `db:"id" json:"id"`
+	TenantID  string    `db:"tenant_id" json:"tenant_id"`
 	Email     string    `db:"email" json:"email"`
 	Name      string    `db:"name" json:"name"`
 	CreatedAt time.Time `db:"created_at" json:"created_at"`
 	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+	// Roles are granted to the user's access tokens
+	Roles []string `db:"roles" json:"roles"`
 }
 
-// UserRepository interface defines user data operations
+// UserRepository interface defines user data operations. Every operation
+// is scoped to the tenant on the context.
 type UserRepository interface {
 	Create(ctx context.Context, user *User) (*User, error)
 	GetByID(ctx context.Context, id string) (*User, error)
 	GetByEmail(ctx context.Context, email string) (*User, error)
-	List(ctx context.Context, limit, offset int) ([]*User, error)
-	Update(ctx context.Context, user *User) (*User, error)
+	GetMany(ctx context.Context, ids []string) ([]*User, error)
+	List(ctx context.Context, after *pagination.Cursor, limit int) ([]*User, error)
+	Search(ctx context.Context, query string, offset, limit int) ([]*User, error)
+	ListAfter(ctx context.Context, afterID string, limit int) ([]*User, error)
+	Update(ctx context.Context, user *User, fields ...string) (*User, error)
 	Delete(ctx context.Context, id string) error
+	Undelete(ctx context.Context, id string) (*User, error)
+	Purge(ctx context.Context, id string) error
+	SetPasswordHash(ctx context.Context, id, hash string) error
+	GetPasswordHash(ctx context.Context, id string) (string, error)
 }
 
 type userRepository struct {
-	db *db.DB
+	db  *db.DB
+	enc *crypto.Encryptor
+}
+
+// Option configures a user repository
+type Option func(*userRepository)
+
+// WithEncryptor encrypts emails at rest with enc. Emails are looked up by
+// their blind index; rows stored before encryption was enabled are still
+// read and found by their plaintext email until Reencrypt rewrites them.
+func WithEncryptor(enc *crypto.Encryptor) Option {
+	return func(r *userRepository) {
+		r.enc = enc
+	}
 }
 
 // NewUserRepository creates a new user repository
-func NewUserRepository(database *db.DB) UserRepository {
-	return &userRepository{db: database}
+func NewUserRepository(database *db.DB, opts ...Option) UserRepository {
+	r := &userRepository{db: database}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// seal returns the email to store and its blind index, which is nil when
+// encryption is disabled
+func (r *userRepository) seal(ctx context.Context, email string) (string, *string, error) {
+	if r.enc == nil {
+		return email, nil, nil
+	}
+	sealed, err := r.enc.Encrypt(ctx, email)
+	if err != nil {
+		return "", nil, errors.Wrap(err, "failed to encrypt email")
+	}
+	index := r.enc.Index(email)
+	return sealed, &index, nil
+}
+
+// open decrypts the email of a user read from the database
+func (r *userRepository) open(ctx context.Context, user *User) error {
+	if r.enc == nil {
+		return nil
+	}
+	email, err := r.enc.Decrypt(ctx, user.Email)
+	if err != nil {
+		return errors.Wrap(err, "failed to decrypt email")
+	}
+	user.Email = email
+	return nil
 }
 
-// Create creates a new user
+// Create creates a new user and writes a user.created event to the outbox
+// in the same transaction
 func (r *userRepository) Create(ctx context.Context, user *User) (*User, error) {
 	query := `
-		INSERT INTO users (id, email, name, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5)
-		RETURNING id, email, name, created_at, updated_at
+		INSERT INTO users (id, tenant_id, email, email_index, name, roles, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, tenant_id, email, name, created_at, updated_at, roles
 	`
 
 	now := time.Now()
+	user.TenantID = tenant.ID(ctx)
 	user.CreatedAt = now
 	user.UpdatedAt = now
 
-	row := r.db.QueryRowContext(ctx, query, user.ID, user.Email, user.Name, user.CreatedAt, user.UpdatedAt)
+	email, index, err := r.seal(ctx, user.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	row := tx.QueryRowContext(ctx, query, user.ID, user.TenantID, email, index, user.Name, pq.Array(user.Roles), user.CreatedAt, user.UpdatedAt)
 
 	var created User
-	err := row.Scan(&created.ID, &created.Email, &created.Name, &created.CreatedAt, &created.UpdatedAt)
+	err = row.Scan(&created.ID, &created.TenantID, &created.Email, &created.Name, &created.CreatedAt, &created.UpdatedAt, pq.Array(&created.Roles))
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create user")
 	}
+	created.Email = user.Email
+
+	if err := enqueue(ctx, tx, userevents.TypeUserCreated, &created); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, errors.Wrap(err, "failed to commit transaction")
+	}
 
 	return &created, nil
 }
 
 // GetByID retrieves a user by ID
 func (r *userRepository) GetByID(ctx context.Context, id string) (*User, error) {
-	query := `SELECT id, email, name, created_at, updated_at FROM users WHERE id = $1`
+	query := `SELECT id, tenant_id, email, name, created_at, updated_at, roles FROM users WHERE tenant_id = $1 AND id = $2 AND deleted_at IS NULL`
 
 	var user User
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&user.ID, &user.Email, &user.Name, &user.CreatedAt, &user.UpdatedAt,
+	err := r.db.Executor(ctx).QueryRowContext(ctx, query, tenant.ID(ctx), id).Scan(
+		&user.ID, &user.TenantID, &user.Email, &user.Name, &user.CreatedAt, &user.UpdatedAt, pq.Array(&user.Roles),
 	)
 
 	if err == sql.ErrNoRows {
@@ -92,16 +176,29 @@ func (r *userRepository) GetByID(ctx context.Context, id string) (*User, error)
 		return nil, errors.Wrap(err, "failed to get user by ID")
 	}
 
+	if err := r.open(ctx, &user); err != nil {
+		return nil, err
+	}
 	return &user, nil
 }
 
-// GetByEmail retrieves a user by email
+// GetByEmail retrieves a user by email. Encrypted emails are matched by
+// their blind index and plaintext ones directly.
 func (r *userRepository) GetByEmail(ctx context.Context, email string) (*User, error) {
-	query := `SELECT id, email, name, created_at, updated_at FROM users WHERE email = $1`
+	query := `
+		SELECT id, tenant_id, email, name, created_at, updated_at, roles FROM users
+		WHERE tenant_id = $1 AND (email_index = $2 OR (email_index IS NULL AND email = $3)) AND deleted_at IS NULL
+	`
+
+	var index *string
+	if r.enc != nil {
+		i := r.enc.Index(email)
+		index = &i
+	}
 
 	var user User
-	err := r.db.QueryRowContext(ctx, query, email).Scan(
-		&user.ID, &user.Email, &user.Name, &user.CreatedAt, &user.UpdatedAt,
+	err := r.db.Executor(ctx).QueryRowContext(ctx, query, tenant.ID(ctx), index, email).Scan(
+		&user.ID, &user.TenantID, &user.Email, &user.Name, &user.CreatedAt, &user.UpdatedAt, pq.Array(&user.Roles),
 	)
 
 	if err == sql.ErrNoRows {
@@ -111,19 +208,70 @@ func (r *userRepository) GetByEmail(ctx context.Context, email string) (*User, e
 		return nil, errors.Wrap(err, "failed to get user by email")
 	}
 
+	if err := r.open(ctx, &user); err != nil {
+		return nil, err
+	}
 	return &user, nil
 }
 
-// List retrieves users with pagination
-func (r *userRepository) List(ctx context.Context, limit, offset int) ([]*User, error) {
+// GetMany retrieves the users with the given IDs in one query. IDs that do
+// not exist are left out.
+func (r *userRepository) GetMany(ctx context.Context, ids []string) ([]*User, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	query := `
+		SELECT id, tenant_id, email, name, created_at, updated_at, roles
+		FROM users
+		WHERE tenant_id = $1 AND id = ANY($2::uuid[]) AND deleted_at IS NULL
+	`
+
+	rows, err := r.db.Executor(ctx).QueryContext(ctx, query, tenant.ID(ctx), pq.Array(ids))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get users")
+	}
+	defer rows.Close()
+
+	var users []*User
+	for rows.Next() {
+		var user User
+		err := rows.Scan(&user.ID, &user.TenantID, &user.Email, &user.Name, &user.CreatedAt, &user.UpdatedAt, pq.Array(&user.Roles))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to scan user")
+		}
+		if err := r.open(ctx, &user); err != nil {
+			return nil, err
+		}
+		users = append(users, &user)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "error iterating users")
+	}
+
+	return users, nil
+}
+
+// List retrieves up to limit users, newest first, starting after the after
+// cursor, or from the newest when it is nil
+func (r *userRepository) List(ctx context.Context, after *pagination.Cursor, limit int) ([]*User, error) {
+	conds := "tenant_id = $1 AND deleted_at IS NULL"
+	args := []interface{}{tenant.ID(ctx), limit}
+	if after != nil {
+		conds += " AND (created_at, id) < ($3, $4)"
+		args = append(args, after.CreatedAt, after.ID)
+	}
+
 	query := `
-		SELECT id, email, name, created_at, updated_at 
-		FROM users 
-		ORDER BY created_at DESC 
-		LIMIT $1 OFFSET $2
+		SELECT id, tenant_id, email, name, created_at, updated_at, roles
+		FROM users
+		WHERE ` + conds + `
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, limit, offset)
+	rows, err := r.db.Executor(ctx).QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to list users")
 	}
@@ -132,46 +280,217 @@ func (r *userRepository) List(ctx context.Context, limit, offset int) ([]*User,
 	var users []*User
 	for rows.Next() {
 		var user User
-		err := rows.Scan(&user.ID, &user.Email, &user.Name, &user.CreatedAt, &user.UpdatedAt)
+		err := rows.Scan(&user.ID, &user.TenantID, &user.Email, &user.Name, &user.CreatedAt, &user.UpdatedAt, pq.Array(&user.Roles))
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to scan user")
 		}
+		if err := r.open(ctx, &user); err != nil {
+			return nil, err
+		}
 		users = append(users, &user)
 	}
 
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "error iterating users")
+	}
+
 	return users, nil
 }
 
-// Update updates an existing user
-func (r *userRepository) Update(ctx context.Context, user *User) (*User, error) {
+// likeEscaper escapes the wildcards of a LIKE pattern
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// Search retrieves up to limit users whose name or email contains query,
+// case-insensitively, best matches first, skipping the first offset.
+// Matches are ranked by trigram word similarity. Encrypted emails only
+// match a query that is the whole email.
+func (r *userRepository) Search(ctx context.Context, query string, offset, limit int) ([]*User, error) {
+	var index *string
+	if r.enc != nil {
+		i := r.enc.Index(query)
+		index = &i
+	}
+
+	sqlQuery := `
+		SELECT id, tenant_id, email, name, created_at, updated_at, roles
+		FROM (
+			SELECT id, tenant_id, email, name, created_at, updated_at, roles,
+				GREATEST(
+					word_similarity($2, name),
+					CASE WHEN email_index IS NULL THEN word_similarity($2, email) ELSE 0 END,
+					CASE WHEN email_index = $4 THEN 1 ELSE 0 END
+				) AS rank
+			FROM users
+			WHERE tenant_id = $1 AND deleted_at IS NULL
+			  AND (name ILIKE $3 OR (email_index IS NULL AND email ILIKE $3) OR email_index = $4)
+		) matches
+		ORDER BY rank DESC, name, id
+		LIMIT $5 OFFSET $6
+	`
+
+	pattern := "%" + likeEscaper.Replace(query) + "%"
+	rows, err := r.db.Executor(ctx).QueryContext(ctx, sqlQuery, tenant.ID(ctx), query, pattern, index, limit, offset)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to search users")
+	}
+	defer rows.Close()
+
+	var users []*User
+	for rows.Next() {
+		var user User
+		err := rows.Scan(&user.ID, &user.TenantID, &user.Email, &user.Name, &user.CreatedAt, &user.UpdatedAt, pq.Array(&user.Roles))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to scan user")
+		}
+		if err := r.open(ctx, &user); err != nil {
+			return nil, err
+		}
+		users = append(users, &user)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "error iterating users")
+	}
+
+	return users, nil
+}
+
+// ListAfter retrieves up to limit users ordered by ID, starting after
+// afterID. Callers walking the whole table, rather than serving pages,
+// should use it.
+func (r *userRepository) ListAfter(ctx context.Context, afterID string, limit int) ([]*User, error) {
 	query := `
-		UPDATE users 
-		SET email = $2, name = $3, updated_at = $4
-		WHERE id = $1
-		RETURNING id, email, name, created_at, updated_at
+		SELECT id, tenant_id, email, name, created_at, updated_at, roles
+		FROM users
+		WHERE tenant_id = $1 AND deleted_at IS NULL
+		  AND id > COALESCE(NULLIF($2, '')::uuid, '00000000-0000-0000-0000-000000000000')
+		ORDER BY id
+		LIMIT $3
 	`
 
+	rows, err := r.db.Executor(ctx).QueryContext(ctx, query, tenant.ID(ctx), afterID, limit)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list users")
+	}
+	defer rows.Close()
+
+	var users []*User
+	for rows.Next() {
+		var user User
+		err := rows.Scan(&user.ID, &user.TenantID, &user.Email, &user.Name, &user.CreatedAt, &user.UpdatedAt, pq.Array(&user.Roles))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to scan user")
+		}
+		if err := r.open(ctx, &user); err != nil {
+			return nil, err
+		}
+		users = append(users, &user)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "error iterating users")
+	}
+
+	return users, nil
+}
+
+// Fields of a user that Update can change
+const (
+	FieldEmail = "email"
+	FieldName  = "name"
+	FieldRoles = "roles"
+)
+
+// Update changes the given fields of an existing user, or its email and
+// name when none are given, and writes a user.updated event to the outbox
+// in the same transaction
+func (r *userRepository) Update(ctx context.Context, user *User, fields ...string) (*User, error) {
+	if len(fields) == 0 {
+		fields = []string{FieldEmail, FieldName}
+	}
+
 	user.UpdatedAt = time.Now()
 
-	row := r.db.QueryRowContext(ctx, query, user.ID, user.Email, user.Name, user.UpdatedAt)
+	// Only the named columns are set, so a name change leaves the email
+	// as stored
+	args := []interface{}{tenant.ID(ctx), user.ID, user.UpdatedAt}
+	set := []string{"updated_at = $3"}
+	seen := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		if seen[field] {
+			continue
+		}
+		seen[field] = true
+		switch field {
+		case FieldEmail:
+			email, index, err := r.seal(ctx, user.Email)
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, email, index)
+			set = append(set, fmt.Sprintf("email = $%d, email_index = $%d", len(args)-1, len(args)))
+		case FieldName:
+			args = append(args, user.Name)
+			set = append(set, fmt.Sprintf("name = $%d", len(args)))
+		case FieldRoles:
+			args = append(args, pq.Array(user.Roles))
+			set = append(set, fmt.Sprintf("roles = $%d", len(args)))
+		default:
+			return nil, errors.WithCode(errors.Newf("unknown user field %q", field), errors.CodeInvalidInput)
+		}
+	}
+
+	query := `
+		UPDATE users
+		SET ` + strings.Join(set, ", ") + `
+		WHERE tenant_id = $1 AND id = $2 AND deleted_at IS NULL
+		RETURNING id, tenant_id, email, name, created_at, updated_at, roles
+	`
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	row := tx.QueryRowContext(ctx, query, args...)
 
 	var updated User
-	err := row.Scan(&updated.ID, &updated.Email, &updated.Name, &updated.CreatedAt, &updated.UpdatedAt)
+	err = row.Scan(&updated.ID, &updated.TenantID, &updated.Email, &updated.Name, &updated.CreatedAt, &updated.UpdatedAt, pq.Array(&updated.Roles))
 	if err == sql.ErrNoRows {
 		return nil, errors.WithCode(errors.New("user not found"), errors.CodeNotFound)
 	}
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to update user")
 	}
+	if err := r.open(ctx, &updated); err != nil {
+		return nil, err
+	}
+
+	if err := enqueue(ctx, tx, userevents.TypeUserUpdated, &updated); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, errors.Wrap(err, "failed to commit transaction")
+	}
 
 	return &updated, nil
 }
 
-// Delete deletes a user by ID
+// Delete soft-deletes a user by ID and writes a user.deleted event to the
+// outbox in the same transaction. The row is purged by the retention job
+// once the retention period has passed.
 func (r *userRepository) Delete(ctx context.Context, id string) error {
-	query := `DELETE FROM users WHERE id = $1`
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to begin transaction")
+	}
+	defer tx.Rollback()
 
-	result, err := r.db.ExecContext(ctx, query, id)
+	query := `UPDATE users SET deleted_at = $3 WHERE tenant_id = $1 AND id = $2 AND deleted_at IS NULL`
+
+	result, err := tx.ExecContext(ctx, query, tenant.ID(ctx), id, time.Now())
 	if err != nil {
 		return errors.Wrap(err, "failed to delete user")
 	}
@@ -185,5 +504,201 @@ func (r *userRepository) Delete(ctx context.Context, id string) error {
 		return errors.WithCode(errors.New("user not found"), errors.CodeNotFound)
 	}
 
+	if err := enqueue(ctx, tx, userevents.TypeUserDeleted, &User{ID: id}); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(err, "failed to commit transaction")
+	}
+
+	return nil
+}
+
+// Undelete restores a soft-deleted user and writes a user.updated event to
+// the outbox. It fails with a conflict when another user has taken the
+// email in the meantime.
+func (r *userRepository) Undelete(ctx context.Context, id string) (*User, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE users
+		SET deleted_at = NULL, updated_at = $3
+		WHERE tenant_id = $1 AND id = $2 AND deleted_at IS NOT NULL
+		RETURNING id, tenant_id, email, name, created_at, updated_at, roles
+	`
+
+	var user User
+	err = tx.QueryRowContext(ctx, query, tenant.ID(ctx), id, time.Now()).Scan(
+		&user.ID, &user.TenantID, &user.Email, &user.Name, &user.CreatedAt, &user.UpdatedAt, pq.Array(&user.Roles),
+	)
+	if err == sql.ErrNoRows {
+		return nil, errors.WithCode(errors.New("deleted user not found"), errors.CodeNotFound)
+	}
+	if db.IsUniqueViolation(err) {
+		return nil, errors.WithCode(errors.New("user with this email already exists"), errors.CodeConflict)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to undelete user")
+	}
+
+	if err := r.open(ctx, &user); err != nil {
+		return nil, err
+	}
+
+	if err := enqueue(ctx, tx, userevents.TypeUserUpdated, &user); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, errors.Wrap(err, "failed to commit transaction")
+	}
+
+	return &user, nil
+}
+
+// Purge permanently deletes a soft-deleted user, like the retention job
+// does once the retention period has passed. Its orders are kept but
+// detached from it, in the same transaction.
+func (r *userRepository) Purge(ctx context.Context, id string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	tenantID := tenant.ID(ctx)
+	var deletedAt sql.NullTime
+	err = tx.QueryRowContext(ctx,
+		`SELECT deleted_at FROM users WHERE tenant_id = $1 AND id = $2 FOR UPDATE`,
+		tenantID, id,
+	).Scan(&deletedAt)
+	if err == sql.ErrNoRows {
+		return errors.WithCode(errors.New("user not found"), errors.CodeNotFound)
+	}
+	if err != nil {
+		return errors.Wrap(err, "failed to lock user")
+	}
+	if !deletedAt.Valid {
+		return errors.WithCode(errors.New("user must be deleted before it is purged"), errors.CodeInvalidInput)
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE orders
+		SET user_id = NULL, anonymized_at = COALESCE(anonymized_at, $3)
+		WHERE tenant_id = $1 AND user_id = $2
+	`, tenantID, id, time.Now()); err != nil {
+		return errors.Wrap(err, "failed to anonymize orders of purged user")
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM users WHERE tenant_id = $1 AND id = $2`, tenantID, id); err != nil {
+		return errors.Wrap(err, "failed to purge user")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(err, "failed to commit user purge")
+	}
 	return nil
 }
+
+// SetPasswordHash stores the password hash of a user. Hashes live outside
+// User, so that they are never cached, published in events or returned.
+func (r *userRepository) SetPasswordHash(ctx context.Context, id, hash string) error {
+	query := `UPDATE users SET password_hash = $3 WHERE tenant_id = $1 AND id = $2 AND deleted_at IS NULL`
+
+	result, err := r.db.Executor(ctx).ExecContext(ctx, query, tenant.ID(ctx), id, hash)
+	if err != nil {
+		return errors.Wrap(err, "failed to set password hash")
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "failed to get rows affected")
+	}
+	if rowsAffected == 0 {
+		return errors.WithCode(errors.New("user not found"), errors.CodeNotFound)
+	}
+	return nil
+}
+
+// GetPasswordHash retrieves the password hash of a user, which is empty
+// when the user has no password
+func (r *userRepository) GetPasswordHash(ctx context.Context, id string) (string, error) {
+	query := `SELECT password_hash FROM users WHERE tenant_id = $1 AND id = $2 AND deleted_at IS NULL`
+
+	var hash sql.NullString
+	err := r.db.Executor(ctx).QueryRowContext(ctx, query, tenant.ID(ctx), id).Scan(&hash)
+	if err == sql.ErrNoRows {
+		return "", errors.WithCode(errors.New("user not found"), errors.CodeNotFound)
+	}
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get password hash")
+	}
+	return hash.String, nil
+}
+
+// Reencrypt encrypts the emails of every tenant's users that are stored in
+// plaintext or under a retired key encryption key, batchSize rows at a
+// time, and returns how many it rewrote. Rows changed while it runs are
+// left for the next run.
+func Reencrypt(ctx context.Context, database *db.DB, enc *crypto.Encryptor, batchSize int) (int, error) {
+	query := `
+		SELECT id, tenant_id, email
+		FROM users
+		WHERE id > COALESCE(NULLIF($1, '')::uuid, '00000000-0000-0000-0000-000000000000')
+		ORDER BY id
+		LIMIT $2
+	`
+	update := `UPDATE users SET email = $3, email_index = $4 WHERE tenant_id = $1 AND id = $2 AND email = $5`
+
+	rewritten := 0
+	afterID := ""
+	for {
+		rows, err := database.QueryContext(ctx, query, afterID, batchSize)
+		if err != nil {
+			return rewritten, errors.Wrap(err, "failed to list users")
+		}
+		var batch []*User
+		for rows.Next() {
+			var user User
+			if err := rows.Scan(&user.ID, &user.TenantID, &user.Email); err != nil {
+				rows.Close()
+				return rewritten, errors.Wrap(err, "failed to scan user")
+			}
+			batch = append(batch, &user)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return rewritten, errors.Wrap(err, "error iterating users")
+		}
+
+		for _, user := range batch {
+			if !enc.Stale(user.Email) {
+				continue
+			}
+			email, err := enc.Decrypt(ctx, user.Email)
+			if err != nil {
+				return rewritten, errors.Wrapf(err, "failed to decrypt email of user %s", user.ID)
+			}
+			sealed, err := enc.Encrypt(ctx, email)
+			if err != nil {
+				return rewritten, errors.Wrap(err, "failed to encrypt email")
+			}
+			result, err := database.ExecContext(ctx, update, user.TenantID, user.ID, sealed, enc.Index(email), user.Email)
+			if err != nil {
+				return rewritten, errors.Wrapf(err, "failed to update user %s", user.ID)
+			}
+			if n, _ := result.RowsAffected(); n > 0 {
+				rewritten++
+			}
+		}
+
+		if len(batch) < batchSize {
+			return rewritten, nil
+		}
+		afterID = batch[len(batch)-1].ID
+	}
+}
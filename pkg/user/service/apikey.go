@@ -0,0 +1,134 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kevindiu/monorepo-go-example/internal/apikey"
+	"github.com/kevindiu/monorepo-go-example/internal/auth"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/internal/log"
+	"github.com/kevindiu/monorepo-go-example/internal/tenant"
+)
+
+// scopePattern is the form of API key scopes, such as "service" or
+// "orders.read"
+var scopePattern = regexp.MustCompile(`^[a-z][a-z0-9._:-]{0,63}$`)
+
+// APIKeyService manages the API keys of the tenant on the context
+type APIKeyService interface {
+	// CreateAPIKey returns the new key and the key itself, which is not
+	// stored and cannot be retrieved later
+	CreateAPIKey(ctx context.Context, name string, scopes []string, expiresAt *time.Time) (*apikey.Key, string, error)
+	ListAPIKeys(ctx context.Context) ([]*apikey.Key, error)
+	RevokeAPIKey(ctx context.Context, id string) error
+}
+
+type apiKeyService struct {
+	keys apikey.Store
+	now  func() time.Time
+}
+
+// NewAPIKeyService creates an API key service storing keys in keys
+func NewAPIKeyService(keys apikey.Store) APIKeyService {
+	return &apiKeyService{keys: keys, now: time.Now}
+}
+
+// requireAdmin fails authenticated calls without the admin role
+func requireAdmin(ctx context.Context) error {
+	if claims, ok := auth.FromContext(ctx); ok && !claims.HasRole(auth.RoleAdmin) {
+		return errors.WithCode(errors.New("managing API keys requires the admin role"), errors.CodeForbidden)
+	}
+	return nil
+}
+
+// CreateAPIKey issues a key granting scopes, which expires at expiresAt
+// unless it is nil
+func (s *apiKeyService) CreateAPIKey(ctx context.Context, name string, scopes []string, expiresAt *time.Time) (*apikey.Key, string, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, "", err
+	}
+	if name == "" {
+		return nil, "", errors.InvalidField("name", "name is required")
+	}
+	if len(scopes) == 0 {
+		return nil, "", errors.InvalidField("scopes", "at least one scope is required")
+	}
+	for _, scope := range scopes {
+		if !scopePattern.MatchString(scope) {
+			return nil, "", errors.InvalidField("scopes", fmt.Sprintf("%q is not a valid scope", scope))
+		}
+	}
+	now := s.now()
+	if expiresAt != nil && !expiresAt.After(now) {
+		return nil, "", errors.InvalidField("expires_at", "expires_at must be in the future")
+	}
+	scopes = slices.Clone(scopes)
+	slices.Sort(scopes)
+	scopes = slices.Compact(scopes)
+
+	key, prefix, hash, err := apikey.Generate()
+	if err != nil {
+		return nil, "", err
+	}
+	k := &apikey.Key{
+		ID:        uuid.New().String(),
+		TenantID:  tenant.ID(ctx),
+		Name:      name,
+		Prefix:    prefix,
+		Scopes:    scopes,
+		CreatedAt: now,
+		ExpiresAt: expiresAt,
+	}
+	if err := s.keys.Create(ctx, k, hash); err != nil {
+		return nil, "", err
+	}
+
+	log.FromContext(ctx).Info("API key created", log.String("api_key_id", k.ID), log.Any("scopes", scopes))
+	return k, key, nil
+}
+
+// ListAPIKeys lists the keys of the tenant
+func (s *apiKeyService) ListAPIKeys(ctx context.Context) ([]*apikey.Key, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	return s.keys.List(ctx, tenant.ID(ctx))
+}
+
+// RevokeAPIKey revokes a key of the tenant. The gateway may accept it for
+// as long as it caches keys.
+func (s *apiKeyService) RevokeAPIKey(ctx context.Context, id string) error {
+	if err := requireAdmin(ctx); err != nil {
+		return err
+	}
+	if _, err := uuid.Parse(id); err != nil {
+		return errors.InvalidField("id", "API key ID must be a UUID")
+	}
+	if err := s.keys.Revoke(ctx, tenant.ID(ctx), id, s.now()); err != nil {
+		return err
+	}
+
+	log.FromContext(ctx).Info("API key revoked", log.String("api_key_id", id))
+	return nil
+}
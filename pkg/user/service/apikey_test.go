@@ -0,0 +1,125 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/apikey"
+	"github.com/kevindiu/monorepo-go-example/internal/auth"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/internal/tenant"
+)
+
+// mockAPIKeyStore is a mock implementation of apikey.Store
+type mockAPIKeyStore struct {
+	keys map[string]*apikey.Key
+}
+
+func (m *mockAPIKeyStore) Create(ctx context.Context, k *apikey.Key, hash string) error {
+	m.keys[hash] = k
+	return nil
+}
+
+func (m *mockAPIKeyStore) List(ctx context.Context, tenantID string) ([]*apikey.Key, error) {
+	var keys []*apikey.Key
+	for _, k := range m.keys {
+		if k.TenantID == tenantID {
+			keys = append(keys, k)
+		}
+	}
+	return keys, nil
+}
+
+func (m *mockAPIKeyStore) Revoke(ctx context.Context, tenantID, id string, at time.Time) error {
+	for _, k := range m.keys {
+		if k.TenantID == tenantID && k.ID == id && k.RevokedAt == nil {
+			k.RevokedAt = &at
+			return nil
+		}
+	}
+	return errors.WithCode(errors.New("API key not found"), errors.CodeNotFound)
+}
+
+func (m *mockAPIKeyStore) Lookup(ctx context.Context, hash string) (*apikey.Key, error) {
+	k, ok := m.keys[hash]
+	if !ok {
+		return nil, errors.WithCode(errors.New("API key not found"), errors.CodeNotFound)
+	}
+	return k, nil
+}
+
+func TestAPIKeyLifecycle(t *testing.T) {
+	store := &mockAPIKeyStore{keys: make(map[string]*apikey.Key)}
+	s := NewAPIKeyService(store)
+	ctx := auth.WithClaims(tenant.WithID(context.Background(), "acme"), &auth.Claims{Subject: "admin-1", Roles: []string{auth.RoleAdmin}})
+
+	k, key, err := s.CreateAPIKey(ctx, "billing", []string{"service", "orders.read", "service"}, nil)
+	if err != nil {
+		t.Fatalf("CreateAPIKey() error = %v", err)
+	}
+	if k.TenantID != "acme" || len(k.Scopes) != 2 || k.Scopes[0] != "orders.read" {
+		t.Errorf("CreateAPIKey() = %+v, want acme's key with sorted, unique scopes", k)
+	}
+	if stored, err := store.Lookup(ctx, apikey.Hash(key)); err != nil || stored.ID != k.ID {
+		t.Fatalf("stored key = %+v, %v; want the key under its hash", stored, err)
+	}
+
+	keys, err := s.ListAPIKeys(ctx)
+	if err != nil || len(keys) != 1 {
+		t.Fatalf("ListAPIKeys() = %v, %v; want the key", keys, err)
+	}
+	if err := s.RevokeAPIKey(ctx, k.ID); err != nil {
+		t.Fatalf("RevokeAPIKey() error = %v", err)
+	}
+	if err := s.RevokeAPIKey(ctx, k.ID); errors.GetCode(err) != errors.CodeNotFound {
+		t.Errorf("RevokeAPIKey() twice error = %v, want not found", err)
+	}
+}
+
+func TestCreateAPIKeyRejects(t *testing.T) {
+	s := NewAPIKeyService(&mockAPIKeyStore{keys: make(map[string]*apikey.Key)})
+	admin := auth.WithClaims(context.Background(), &auth.Claims{Subject: "admin-1", Roles: []string{auth.RoleAdmin}})
+	customer := auth.WithClaims(context.Background(), &auth.Claims{Subject: "user-1", Roles: []string{auth.RoleCustomer}})
+	past := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		keyName   string
+		scopes    []string
+		expiresAt *time.Time
+		wantCode  string
+	}{
+		{name: "not an admin", ctx: customer, keyName: "billing", scopes: []string{"service"}, wantCode: errors.CodeForbidden},
+		{name: "no name", ctx: admin, scopes: []string{"service"}, wantCode: errors.CodeInvalidInput},
+		{name: "no scopes", ctx: admin, keyName: "billing", wantCode: errors.CodeInvalidInput},
+		{name: "invalid scope", ctx: admin, keyName: "billing", scopes: []string{"Service Role"}, wantCode: errors.CodeInvalidInput},
+		{name: "expired", ctx: admin, keyName: "billing", scopes: []string{"service"}, expiresAt: &past, wantCode: errors.CodeInvalidInput},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := s.CreateAPIKey(tt.ctx, tt.keyName, tt.scopes, tt.expiresAt)
+			if code := errors.GetCode(err); code != tt.wantCode {
+				t.Errorf("CreateAPIKey() error = %v, want code %s", err, tt.wantCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,237 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kevindiu/monorepo-go-example/internal/auth"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/internal/log"
+	"github.com/kevindiu/monorepo-go-example/internal/tenant"
+	"github.com/kevindiu/monorepo-go-example/pkg/user/repository"
+)
+
+// Tokens are the credentials issued for a session
+type Tokens struct {
+	AccessToken  string
+	RefreshToken string
+	// ExpiresIn is how long AccessToken is valid
+	ExpiresIn time.Duration
+}
+
+// AuthService signs users in and issues their tokens
+type AuthService interface {
+	Login(ctx context.Context, email, password string) (*repository.User, *Tokens, error)
+	Refresh(ctx context.Context, refreshToken string) (*Tokens, error)
+	Logout(ctx context.Context, refreshToken string) error
+}
+
+type authService struct {
+	users         UserService
+	sessions      repository.SessionRepository
+	authenticator *auth.Authenticator
+	refreshTTL    time.Duration
+	now           func() time.Time
+}
+
+// NewAuthService creates an auth service that checks passwords with users,
+// which must have been created WithPasswords, and issues access tokens
+// with authenticator. A session, and with it its refresh tokens, lasts
+// refreshTTL from its login.
+func NewAuthService(users UserService, sessions repository.SessionRepository, authenticator *auth.Authenticator, refreshTTL time.Duration) AuthService {
+	return &authService{
+		users:         users,
+		sessions:      sessions,
+		authenticator: authenticator,
+		refreshTTL:    refreshTTL,
+		now:           time.Now,
+	}
+}
+
+// errInvalidRefreshToken is returned for every refresh token that cannot
+// be used, whatever the reason, so that callers learn nothing about others'
+// sessions
+var errInvalidRefreshToken = errors.WithCode(errors.New("invalid refresh token"), errors.CodeUnauthorized)
+
+// Login verifies the password of the user with email and opens a session
+func (s *authService) Login(ctx context.Context, email, password string) (*repository.User, *Tokens, error) {
+	user, err := s.users.VerifyPassword(ctx, email, password)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	refreshToken, session, err := s.newSession(user.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := s.sessions.CreateSession(ctx, session); err != nil {
+		return nil, nil, err
+	}
+
+	tokens, err := s.issue(ctx, user, refreshToken)
+	if err != nil {
+		return nil, nil, err
+	}
+	log.FromContext(ctx).Info("User logged in", log.String("user_id", user.ID), log.String("session_id", session.ID))
+	return user, tokens, nil
+}
+
+// Refresh exchanges a refresh token for a new access token and a new
+// refresh token, which replaces it. Presenting a replaced refresh token
+// again means it leaked, so the session is revoked.
+func (s *authService) Refresh(ctx context.Context, refreshToken string) (*Tokens, error) {
+	session, err := s.session(ctx, refreshToken)
+	if err != nil {
+		return nil, err
+	}
+
+	hash := hashToken(refreshToken)
+	if session.PreviousTokenHash != "" && equalHash(hash, session.PreviousTokenHash) {
+		if err := s.sessions.RevokeSession(ctx, session.ID); err != nil {
+			return nil, err
+		}
+		log.FromContext(ctx).Warn("Replayed refresh token, session revoked",
+			log.String("user_id", session.UserID), log.String("session_id", session.ID))
+		return nil, errInvalidRefreshToken
+	}
+	if !equalHash(hash, session.TokenHash) {
+		return nil, errInvalidRefreshToken
+	}
+
+	// Users deleted since their login may not refresh, and changes to
+	// their roles apply from their next refresh
+	user, err := s.users.GetUser(ctx, session.UserID)
+	if err != nil {
+		if errors.GetCode(err) == errors.CodeNotFound {
+			return nil, errInvalidRefreshToken
+		}
+		return nil, err
+	}
+
+	next, err := newRefreshToken(session.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.sessions.RotateSession(ctx, session.ID, hash, hashToken(next)); err != nil {
+		if errors.GetCode(err) == errors.CodeConflict {
+			return nil, errInvalidRefreshToken
+		}
+		return nil, err
+	}
+	return s.issue(ctx, user, next)
+}
+
+// Logout revokes the session of a refresh token. Access tokens issued for
+// it stay valid until they expire.
+func (s *authService) Logout(ctx context.Context, refreshToken string) error {
+	session, err := s.session(ctx, refreshToken)
+	if err != nil {
+		return err
+	}
+	if !equalHash(hashToken(refreshToken), session.TokenHash) {
+		return errInvalidRefreshToken
+	}
+
+	if err := s.sessions.RevokeSession(ctx, session.ID); err != nil {
+		return err
+	}
+	log.FromContext(ctx).Info("User logged out", log.String("user_id", session.UserID), log.String("session_id", session.ID))
+	return nil
+}
+
+// session returns the active session a refresh token belongs to, without
+// checking that the token is its current one
+func (s *authService) session(ctx context.Context, refreshToken string) (*repository.Session, error) {
+	id, _, ok := strings.Cut(refreshToken, ".")
+	if !ok {
+		return nil, errInvalidRefreshToken
+	}
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, errInvalidRefreshToken
+	}
+
+	session, err := s.sessions.GetSession(ctx, id)
+	if errors.GetCode(err) == errors.CodeNotFound {
+		return nil, errInvalidRefreshToken
+	}
+	if err != nil {
+		return nil, err
+	}
+	if session.RevokedAt != nil || !s.now().Before(session.ExpiresAt) {
+		return nil, errInvalidRefreshToken
+	}
+	return session, nil
+}
+
+// newSession returns a new session of a user and its first refresh token
+func (s *authService) newSession(userID string) (string, *repository.Session, error) {
+	id := uuid.New().String()
+	refreshToken, err := newRefreshToken(id)
+	if err != nil {
+		return "", nil, err
+	}
+
+	now := s.now()
+	return refreshToken, &repository.Session{
+		ID:          id,
+		UserID:      userID,
+		TokenHash:   hashToken(refreshToken),
+		CreatedAt:   now,
+		RefreshedAt: now,
+		ExpiresAt:   now.Add(s.refreshTTL),
+	}, nil
+}
+
+// issue returns the tokens of a user with a new access token granting its
+// roles
+func (s *authService) issue(ctx context.Context, user *repository.User, refreshToken string) (*Tokens, error) {
+	accessToken, err := s.authenticator.Issue(auth.Claims{Subject: user.ID, TenantID: tenant.ID(ctx), Roles: user.Roles})
+	if err != nil {
+		return nil, err
+	}
+	return &Tokens{AccessToken: accessToken, RefreshToken: refreshToken, ExpiresIn: s.authenticator.TTL()}, nil
+}
+
+// newRefreshToken returns a random refresh token of a session. Tokens are
+// prefixed with their session ID, which locates the session to check them
+// against.
+func newRefreshToken(sessionID string) (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", errors.Wrap(err, "failed to generate refresh token")
+	}
+	return sessionID + "." + base64.RawURLEncoding.EncodeToString(secret), nil
+}
+
+// hashToken returns the hash a refresh token is stored as
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func equalHash(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
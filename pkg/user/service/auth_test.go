@@ -0,0 +1,222 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/auth"
+	"github.com/kevindiu/monorepo-go-example/internal/config"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/pkg/user/repository"
+)
+
+// mockSessionRepository is a mock implementation of
+// repository.SessionRepository
+type mockSessionRepository struct {
+	sessions map[string]*repository.Session
+}
+
+func (m *mockSessionRepository) CreateSession(ctx context.Context, session *repository.Session) error {
+	stored := *session
+	m.sessions[session.ID] = &stored
+	return nil
+}
+
+func (m *mockSessionRepository) GetSession(ctx context.Context, id string) (*repository.Session, error) {
+	session, ok := m.sessions[id]
+	if !ok {
+		return nil, errors.WithCode(errors.New("session not found"), errors.CodeNotFound)
+	}
+	copied := *session
+	return &copied, nil
+}
+
+func (m *mockSessionRepository) RotateSession(ctx context.Context, id, tokenHash, newTokenHash string) error {
+	session, ok := m.sessions[id]
+	if !ok || session.TokenHash != tokenHash || session.RevokedAt != nil {
+		return errors.WithCode(errors.New("session was rotated concurrently"), errors.CodeConflict)
+	}
+	session.PreviousTokenHash, session.TokenHash = session.TokenHash, newTokenHash
+	return nil
+}
+
+func (m *mockSessionRepository) RevokeSession(ctx context.Context, id string) error {
+	if session, ok := m.sessions[id]; ok && session.RevokedAt == nil {
+		now := time.Now()
+		session.RevokedAt = &now
+	}
+	return nil
+}
+
+func (m *mockSessionRepository) RevokeUserSessions(ctx context.Context, userID string) error {
+	for _, session := range m.sessions {
+		if session.UserID == userID && session.RevokedAt == nil {
+			now := time.Now()
+			session.RevokedAt = &now
+		}
+	}
+	return nil
+}
+
+// newTestAuthService returns an auth service for a user with the password
+// "correct horse battery"
+func newTestAuthService(t *testing.T) (*authService, *repository.User, *mockUserRepository) {
+	t.Helper()
+	repo := newMockUserRepository()
+	sessions := &mockSessionRepository{sessions: make(map[string]*repository.Session)}
+	users := NewUserService(repo, WithPasswords(testHasher(t, 10)), WithSessions(sessions))
+	ctx := context.Background()
+	user, err := users.CreateUser(ctx, "test@example.com", "Test User")
+	if err != nil {
+		t.Fatalf("CreateUser() error = %v", err)
+	}
+	if err := users.SetPassword(ctx, user.ID, "correct horse battery"); err != nil {
+		t.Fatalf("SetPassword() error = %v", err)
+	}
+
+	authenticator, err := auth.New(&config.Auth{Secret: "0123456789abcdef0123456789abcdef", TokenTTL: time.Minute})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewAuthService(users, sessions, authenticator, time.Hour).(*authService), user, repo
+}
+
+func TestLogin(t *testing.T) {
+	s, user, _ := newTestAuthService(t)
+	ctx := context.Background()
+
+	if _, _, err := s.Login(ctx, user.Email, "wrong horse battery"); errors.GetCode(err) != errors.CodeUnauthorized {
+		t.Errorf("Login() wrong password error = %v, want unauthorized", err)
+	}
+
+	got, tokens, err := s.Login(ctx, user.Email, "correct horse battery")
+	if err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+	if got.ID != user.ID || tokens.ExpiresIn != time.Minute {
+		t.Errorf("Login() = %v, %+v", got, tokens)
+	}
+	claims, err := s.authenticator.Validate(tokens.AccessToken)
+	if err != nil || claims.Subject != user.ID || !claims.HasRole(auth.RoleCustomer) {
+		t.Errorf("access token claims = %+v, %v, want subject %s with the customer role", claims, err, user.ID)
+	}
+}
+
+func TestRefreshRotates(t *testing.T) {
+	s, user, _ := newTestAuthService(t)
+	ctx := context.Background()
+	_, first, err := s.Login(ctx, user.Email, "correct horse battery")
+	if err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+
+	second, err := s.Refresh(ctx, first.RefreshToken)
+	if err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+	if second.RefreshToken == first.RefreshToken {
+		t.Fatal("Refresh() kept the refresh token")
+	}
+	if _, err := s.Refresh(ctx, second.RefreshToken); err != nil {
+		t.Fatalf("Refresh() with the rotated token error = %v", err)
+	}
+}
+
+func TestRefreshReplayRevokes(t *testing.T) {
+	s, user, _ := newTestAuthService(t)
+	ctx := context.Background()
+	_, first, _ := s.Login(ctx, user.Email, "correct horse battery")
+	second, err := s.Refresh(ctx, first.RefreshToken)
+	if err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+
+	// A replayed token revokes the session, including the token that
+	// replaced it
+	if _, err := s.Refresh(ctx, first.RefreshToken); errors.GetCode(err) != errors.CodeUnauthorized {
+		t.Fatalf("Refresh() replayed token error = %v, want unauthorized", err)
+	}
+	if _, err := s.Refresh(ctx, second.RefreshToken); errors.GetCode(err) != errors.CodeUnauthorized {
+		t.Errorf("Refresh() after replay error = %v, want unauthorized", err)
+	}
+}
+
+func TestRefreshInvalid(t *testing.T) {
+	s, user, repo := newTestAuthService(t)
+	ctx := context.Background()
+	_, tokens, _ := s.Login(ctx, user.Email, "correct horse battery")
+
+	for name, token := range map[string]string{
+		"empty":        "",
+		"malformed":    "not-a-token",
+		"unknown":      "6ba7b810-9dad-11d1-80b4-00c04fd430c8.secret",
+		"wrong secret": tokens.RefreshToken[:37] + "secret",
+	} {
+		if _, err := s.Refresh(ctx, token); errors.GetCode(err) != errors.CodeUnauthorized {
+			t.Errorf("Refresh(%s) error = %v, want unauthorized", name, err)
+		}
+	}
+
+	s.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+	if _, err := s.Refresh(ctx, tokens.RefreshToken); errors.GetCode(err) != errors.CodeUnauthorized {
+		t.Errorf("Refresh() expired session error = %v, want unauthorized", err)
+	}
+
+	s.now = time.Now
+	repo.Delete(ctx, user.ID)
+	if _, err := s.Refresh(ctx, tokens.RefreshToken); errors.GetCode(err) != errors.CodeUnauthorized {
+		t.Errorf("Refresh() deleted user error = %v, want unauthorized", err)
+	}
+}
+
+func TestLogout(t *testing.T) {
+	s, user, _ := newTestAuthService(t)
+	ctx := context.Background()
+	_, tokens, _ := s.Login(ctx, user.Email, "correct horse battery")
+
+	if err := s.Logout(ctx, tokens.RefreshToken); err != nil {
+		t.Fatalf("Logout() error = %v", err)
+	}
+	if _, err := s.Refresh(ctx, tokens.RefreshToken); errors.GetCode(err) != errors.CodeUnauthorized {
+		t.Errorf("Refresh() after logout error = %v, want unauthorized", err)
+	}
+	if err := s.Logout(ctx, tokens.RefreshToken); errors.GetCode(err) != errors.CodeUnauthorized {
+		t.Errorf("Logout() twice error = %v, want unauthorized", err)
+	}
+}
+
+func TestChangePasswordRevokesSessions(t *testing.T) {
+	s, user, _ := newTestAuthService(t)
+	ctx := context.Background()
+	_, first, _ := s.Login(ctx, user.Email, "correct horse battery")
+	_, second, _ := s.Login(ctx, user.Email, "correct horse battery")
+
+	if err := s.users.ChangePassword(ctx, user.ID, "correct horse battery", "staple battery horse"); err != nil {
+		t.Fatalf("ChangePassword() error = %v", err)
+	}
+	for _, tokens := range []*Tokens{first, second} {
+		if _, err := s.Refresh(ctx, tokens.RefreshToken); errors.GetCode(err) != errors.CodeUnauthorized {
+			t.Errorf("Refresh() after password change error = %v, want unauthorized", err)
+		}
+	}
+	if _, _, err := s.Login(ctx, user.Email, "staple battery horse"); err != nil {
+		t.Errorf("Login() with the new password error = %v", err)
+	}
+}
@@ -19,38 +19,130 @@ package service
 import (
 	"context"
 	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
+	"github.com/kevindiu/monorepo-go-example/internal/auth"
 	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/internal/idempotency"
+	"github.com/kevindiu/monorepo-go-example/internal/log"
+	"github.com/kevindiu/monorepo-go-example/internal/pagination"
+	"github.com/kevindiu/monorepo-go-example/internal/password"
 	"github.com/kevindiu/monorepo-go-example/pkg/user/repository"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 )
 
 // UserService interface defines user business logic operations
 type UserService interface {
 	CreateUser(ctx context.Context, email, name string) (*repository.User, error)
 	GetUser(ctx context.Context, id string) (*repository.User, error)
+	GetUserByEmail(ctx context.Context, email string) (*repository.User, error)
+	GetUsers(ctx context.Context, ids []string) ([]*repository.User, error)
 	ListUsers(ctx context.Context, pageSize int, pageToken string) ([]*repository.User, string, error)
-	UpdateUser(ctx context.Context, id, email, name string) (*repository.User, error)
+	SearchUsers(ctx context.Context, query string, pageSize int, pageToken string) ([]*repository.User, string, error)
+	UpdateUser(ctx context.Context, id, email, name string, roles []string, mask *fieldmaskpb.FieldMask) (*repository.User, error)
 	DeleteUser(ctx context.Context, id string) error
+	UndeleteUser(ctx context.Context, id string) (*repository.User, error)
+	PurgeUser(ctx context.Context, id string) error
+	ExportUsers(ctx context.Context, batchSize int, includePII bool, fn func([]*repository.User) error) error
+	SetPassword(ctx context.Context, id, newPassword string) error
+	VerifyPassword(ctx context.Context, email, pw string) (*repository.User, error)
+	ChangePassword(ctx context.Context, id, currentPassword, newPassword string) error
 }
 
 type userService struct {
-	repo repository.UserRepository
+	repo        repository.UserRepository
+	pageTokens  *pagination.Tokens
+	idempotency idempotency.Store
+	passwords   *password.Hasher
+	sessions    repository.SessionRepository
+}
+
+// Option configures the user service
+type Option func(*userService)
+
+// WithPageTokens signs the page tokens of ListUsers with tokens, which
+// every replica must share. Without it, tokens are signed with a random
+// key and only work on the replica that issued them.
+func WithPageTokens(tokens *pagination.Tokens) Option {
+	return func(s *userService) {
+		s.pageTokens = tokens
+	}
+}
+
+// WithIdempotency makes CreateUser calls carrying an idempotency key (see
+// idempotency.Key) return the original user when retried
+func WithIdempotency(store idempotency.Store) Option {
+	return func(s *userService) {
+		s.idempotency = store
+	}
+}
+
+// WithPasswords hashes user passwords with hasher. Without it, the
+// password operations fail as unavailable.
+func WithPasswords(hasher *password.Hasher) Option {
+	return func(s *userService) {
+		s.passwords = hasher
+	}
+}
+
+// WithSessions revokes the login sessions of a user when its password is
+// changed, so refresh tokens issued before the change stop working
+func WithSessions(sessions repository.SessionRepository) Option {
+	return func(s *userService) {
+		s.sessions = sessions
+	}
 }
 
 // NewUserService creates a new user service
-func NewUserService(repo repository.UserRepository) UserService {
-	return &userService{repo: repo}
+func NewUserService(repo repository.UserRepository, opts ...Option) UserService {
+	s := &userService{repo: repo}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.pageTokens == nil {
+		s.pageTokens = pagination.New("")
+	}
+	return s
 }
 
-// CreateUser creates a new user
+// createUserScope scopes the idempotency keys of CreateUser
+const createUserScope = "user.v1.UserService/CreateUser"
+
+// CreateUser creates a new user. A retry carrying the idempotency key of a
+// completed call returns the user that call created. Only the user's ID is
+// kept with the key, so the email stays encrypted at rest and a purge
+// leaves nothing behind.
 func (s *userService) CreateUser(ctx context.Context, email, name string) (*repository.User, error) {
+	req := map[string]string{"email": email, "name": name}
+	var created *repository.User
+	id, err := idempotency.Do(ctx, s.idempotency, createUserScope, idempotency.Key(ctx, ""), req, func(ctx context.Context) (string, error) {
+		user, err := s.createUser(ctx, email, name)
+		if err != nil {
+			return "", err
+		}
+		created = user
+		return user.ID, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if created != nil {
+		return created, nil
+	}
+	// A retry reloads the user the first call created
+	return s.repo.GetByID(ctx, id)
+}
+
+func (s *userService) createUser(ctx context.Context, email, name string) (*repository.User, error) {
 	// Validate input
 	if email == "" {
-		return nil, errors.WithCode(errors.New("email is required"), errors.CodeInvalidInput)
+		return nil, errors.InvalidField("email", "email is required")
 	}
 	if name == "" {
-		return nil, errors.WithCode(errors.New("name is required"), errors.CodeInvalidInput)
+		return nil, errors.InvalidField("name", "name is required")
 	}
 
 	// Check if user with email already exists
@@ -64,21 +156,63 @@ func (s *userService) CreateUser(ctx context.Context, email, name string) (*repo
 		ID:    uuid.New().String(),
 		Email: email,
 		Name:  name,
+		Roles: []string{auth.RoleCustomer},
 	}
 
-	return s.repo.Create(ctx, user)
+	created, err := s.repo.Create(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	// Emails are personal data and stay out of the logs
+	log.FromContext(ctx).Info("User created successfully", log.String("user_id", created.ID))
+	return created, nil
 }
 
 // GetUser retrieves a user by ID
 func (s *userService) GetUser(ctx context.Context, id string) (*repository.User, error) {
 	if id == "" {
-		return nil, errors.WithCode(errors.New("user ID is required"), errors.CodeInvalidInput)
+		return nil, errors.InvalidField("id", "user ID is required")
 	}
 
 	return s.repo.GetByID(ctx, id)
 }
 
-// ListUsers retrieves users with pagination
+// GetUserByEmail retrieves a user by email
+func (s *userService) GetUserByEmail(ctx context.Context, email string) (*repository.User, error) {
+	if email == "" {
+		return nil, errors.InvalidField("email", "email is required")
+	}
+
+	return s.repo.GetByEmail(ctx, email)
+}
+
+// maxBatchSize is the most users GetUsers returns at once
+const maxBatchSize = 100
+
+// GetUsers retrieves several users by ID in one round trip. IDs that do not
+// exist, including ones that are not user IDs at all, are left out.
+func (s *userService) GetUsers(ctx context.Context, ids []string) ([]*repository.User, error) {
+	if len(ids) > maxBatchSize {
+		return nil, errors.InvalidField("ids", fmt.Sprintf("at most %d ids may be requested at once", maxBatchSize))
+	}
+
+	seen := make(map[string]bool, len(ids))
+	valid := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if _, err := uuid.Parse(id); err != nil || seen[id] {
+			continue
+		}
+		seen[id] = true
+		valid = append(valid, id)
+	}
+
+	return s.repo.GetMany(ctx, valid)
+}
+
+// usersScope names the user listing page tokens are valid for
+const usersScope = "users"
+
+// ListUsers retrieves users, newest first, a page at a time
 func (s *userService) ListUsers(ctx context.Context, pageSize int, pageToken string) ([]*repository.User, string, error) {
 	if pageSize <= 0 {
 		pageSize = 10
@@ -87,37 +221,126 @@ func (s *userService) ListUsers(ctx context.Context, pageSize int, pageToken str
 		pageSize = 100
 	}
 
+	after, err := s.pageTokens.Decode(usersScope, pageToken)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Fetch one more user than the page holds to learn whether another
+	// page follows
+	users, err := s.repo.List(ctx, after, pageSize+1)
+	if err != nil {
+		return nil, "", err
+	}
+
+	nextPageToken := ""
+	if len(users) > pageSize {
+		users = users[:pageSize]
+		last := users[pageSize-1]
+		nextPageToken = s.pageTokens.Encode(usersScope, pagination.Cursor{CreatedAt: last.CreatedAt, ID: last.ID})
+	}
+
+	return users, nextPageToken, nil
+}
+
+// minSearchLength is the shortest query SearchUsers accepts; shorter ones
+// hold no trigram and cannot use the search indexes
+const minSearchLength = 3
+
+// searchScope names the search page tokens are valid for
+func searchScope(query string) string {
+	return "users:search:" + query
+}
+
+// SearchUsers retrieves the users whose name or email contains query, best
+// matches first, a page at a time
+func (s *userService) SearchUsers(ctx context.Context, query string, pageSize int, pageToken string) ([]*repository.User, string, error) {
+	query = strings.TrimSpace(query)
+	if utf8.RuneCountInString(query) < minSearchLength {
+		return nil, "", errors.InvalidField("query", fmt.Sprintf("query must have at least %d characters", minSearchLength))
+	}
+	if pageSize <= 0 {
+		pageSize = 10
+	}
+	if pageSize > 100 {
+		pageSize = 100
+	}
+
+	// Matches are ranked rather than ordered by a column, so tokens hold
+	// the offset of the next page, signed for this query
 	offset := 0
-	if pageToken != "" {
-		// In a real implementation, you'd decode the page token
-		// For simplicity, we'll just use a basic offset
-		fmt.Sscanf(pageToken, "%d", &offset)
+	after, err := s.pageTokens.Decode(searchScope(query), pageToken)
+	if err != nil {
+		return nil, "", err
+	}
+	if after != nil {
+		offset = int(after.Value)
 	}
 
-	users, err := s.repo.List(ctx, pageSize, offset)
+	users, err := s.repo.Search(ctx, query, offset, pageSize+1)
 	if err != nil {
 		return nil, "", err
 	}
 
-	// Generate next page token
 	nextPageToken := ""
-	if len(users) == pageSize {
-		nextPageToken = fmt.Sprintf("%d", offset+pageSize)
+	if len(users) > pageSize {
+		users = users[:pageSize]
+		nextPageToken = s.pageTokens.Encode(searchScope(query), pagination.Cursor{Value: int64(offset + pageSize)})
 	}
 
 	return users, nextPageToken, nil
 }
 
-// UpdateUser updates an existing user
-func (s *userService) UpdateUser(ctx context.Context, id, email, name string) (*repository.User, error) {
+// updatableFields are the update_mask paths of UpdateUser, and
+// defaultFields the ones an empty mask changes
+var (
+	updatableFields = []string{repository.FieldEmail, repository.FieldName, repository.FieldRoles}
+	defaultFields   = []string{repository.FieldEmail, repository.FieldName}
+)
+
+// userRoles are the roles users can be granted
+var userRoles = []string{auth.RoleAdmin, auth.RoleCustomer}
+
+// UpdateUser changes the fields of an existing user named by mask, or its
+// email and name when mask is empty. Fields outside the mask are left as
+// stored and need not be sent. Changing roles requires the admin role when
+// calls are authenticated.
+func (s *userService) UpdateUser(ctx context.Context, id, email, name string, roles []string, mask *fieldmaskpb.FieldMask) (*repository.User, error) {
 	if id == "" {
-		return nil, errors.WithCode(errors.New("user ID is required"), errors.CodeInvalidInput)
+		return nil, errors.InvalidField("id", "user ID is required")
 	}
-	if email == "" {
-		return nil, errors.WithCode(errors.New("email is required"), errors.CodeInvalidInput)
+	fields := mask.GetPaths()
+	if len(fields) == 0 {
+		fields = defaultFields
 	}
-	if name == "" {
-		return nil, errors.WithCode(errors.New("name is required"), errors.CodeInvalidInput)
+	for _, field := range fields {
+		if !slices.Contains(updatableFields, field) {
+			return nil, errors.InvalidField("update_mask", fmt.Sprintf("%q is not an updatable field", field))
+		}
+	}
+	updateEmail := slices.Contains(fields, repository.FieldEmail)
+	if updateEmail && email == "" {
+		return nil, errors.InvalidField("email", "email is required")
+	}
+	if slices.Contains(fields, repository.FieldName) && name == "" {
+		return nil, errors.InvalidField("name", "name is required")
+	}
+	updateRoles := slices.Contains(fields, repository.FieldRoles)
+	if updateRoles {
+		if claims, ok := auth.FromContext(ctx); ok && !claims.HasRole(auth.RoleAdmin) {
+			return nil, errors.WithCode(errors.New("changing roles requires the admin role"), errors.CodeForbidden)
+		}
+		if len(roles) == 0 {
+			return nil, errors.InvalidField("roles", "at least one role is required")
+		}
+		for _, role := range roles {
+			if !slices.Contains(userRoles, role) {
+				return nil, errors.InvalidField("roles", fmt.Sprintf("%q is not one of %s", role, strings.Join(userRoles, ", ")))
+			}
+		}
+		roles = slices.Clone(roles)
+		slices.Sort(roles)
+		roles = slices.Compact(roles)
 	}
 
 	// Check if user exists
@@ -127,7 +350,7 @@ func (s *userService) UpdateUser(ctx context.Context, id, email, name string) (*
 	}
 
 	// Check if email is taken by another user
-	if user.Email != email {
+	if updateEmail && user.Email != email {
 		existing, err := s.repo.GetByEmail(ctx, email)
 		if err == nil && existing != nil && existing.ID != id {
 			return nil, errors.WithCode(errors.New("email is already taken"), errors.CodeConflict)
@@ -135,17 +358,256 @@ func (s *userService) UpdateUser(ctx context.Context, id, email, name string) (*
 	}
 
 	// Update user
-	user.Email = email
-	user.Name = name
+	if updateEmail {
+		user.Email = email
+	}
+	if slices.Contains(fields, repository.FieldName) {
+		user.Name = name
+	}
+	if updateRoles {
+		user.Roles = roles
+	}
 
-	return s.repo.Update(ctx, user)
+	updated, err := s.repo.Update(ctx, user, fields...)
+	if err != nil {
+		return nil, err
+	}
+	if updateRoles {
+		log.FromContext(ctx).Info("User roles changed", log.String("user_id", id), log.Any("roles", roles))
+	}
+	return updated, nil
 }
 
-// DeleteUser deletes a user
+// DeleteUser soft-deletes a user
 func (s *userService) DeleteUser(ctx context.Context, id string) error {
 	if id == "" {
-		return errors.WithCode(errors.New("user ID is required"), errors.CodeInvalidInput)
+		return errors.InvalidField("id", "user ID is required")
+	}
+
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+	log.FromContext(ctx).Info("User deleted", log.String("user_id", id))
+	return nil
+}
+
+// UndeleteUser restores a soft-deleted user
+func (s *userService) UndeleteUser(ctx context.Context, id string) (*repository.User, error) {
+	if id == "" {
+		return nil, errors.InvalidField("id", "user ID is required")
+	}
+
+	return s.repo.Undelete(ctx, id)
+}
+
+// PurgeUser permanently removes a soft-deleted user. Authenticated callers
+// need the admin role.
+func (s *userService) PurgeUser(ctx context.Context, id string) error {
+	if claims, ok := auth.FromContext(ctx); ok && !claims.HasRole(auth.RoleAdmin) {
+		return errors.WithCode(errors.New("purging users requires the admin role"), errors.CodeForbidden)
+	}
+	if id == "" {
+		return errors.InvalidField("id", "user ID is required")
 	}
 
-	return s.repo.Delete(ctx, id)
+	if err := s.repo.Purge(ctx, id); err != nil {
+		return err
+	}
+	log.FromContext(ctx).Info("User purged", log.String("user_id", id))
+	return nil
+}
+
+// errNoPasswords is returned by the password operations of a service
+// created without WithPasswords
+var errNoPasswords = errors.WithCode(errors.New("passwords are not enabled"), errors.CodeUnavailable)
+
+// SetPassword sets the password of a user, replacing any previous one
+// without checking it. Authenticated callers must be the user or have the
+// admin role.
+func (s *userService) SetPassword(ctx context.Context, id, newPassword string) error {
+	if s.passwords == nil {
+		return errNoPasswords
+	}
+	if claims, ok := auth.FromContext(ctx); ok && claims.Subject != id && !claims.HasRole(auth.RoleAdmin) {
+		return errors.WithCode(errors.New("setting another user's password requires the admin role"), errors.CodeForbidden)
+	}
+	if id == "" {
+		return errors.InvalidField("id", "user ID is required")
+	}
+	if err := s.passwords.Validate(newPassword); err != nil {
+		return err
+	}
+
+	if err := s.storePassword(ctx, id, newPassword); err != nil {
+		return err
+	}
+	log.FromContext(ctx).Info("Password set", log.String("user_id", id))
+	return nil
+}
+
+// VerifyPassword returns the user with email when pw is its password. An
+// unknown email, a user without a password and a wrong password all fail
+// alike as unauthorized and take as long, since the first two are checked
+// against a dummy hash. Hashes made with weaker settings than configured
+// are replaced on success.
+func (s *userService) VerifyPassword(ctx context.Context, email, pw string) (*repository.User, error) {
+	if s.passwords == nil {
+		return nil, errNoPasswords
+	}
+	if email == "" {
+		return nil, errors.InvalidField("email", "email is required")
+	}
+
+	user, err := s.repo.GetByEmail(ctx, email)
+	if errors.GetCode(err) == errors.CodeNotFound || (err == nil && user == nil) {
+		return nil, s.passwords.Reject(pw)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkPassword(ctx, user.ID, pw); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// ChangePassword replaces the password of a user after checking its
+// current one and signs the user out of every session. Authenticated
+// callers must be the user.
+func (s *userService) ChangePassword(ctx context.Context, id, currentPassword, newPassword string) error {
+	if s.passwords == nil {
+		return errNoPasswords
+	}
+	if claims, ok := auth.FromContext(ctx); ok && claims.Subject != id {
+		return errors.WithCode(errors.New("users can only change their own password"), errors.CodeForbidden)
+	}
+	if id == "" {
+		return errors.InvalidField("id", "user ID is required")
+	}
+	if err := s.passwords.Validate(newPassword); err != nil {
+		return err
+	}
+	if newPassword == currentPassword {
+		return errors.InvalidField("new_password", "new password must differ from the current one")
+	}
+
+	if err := s.checkPassword(ctx, id, currentPassword); err != nil {
+		return err
+	}
+	// Sessions are revoked first, so a failure leaves the old password in
+	// place rather than sessions that outlive it
+	if s.sessions != nil {
+		if err := s.sessions.RevokeUserSessions(ctx, id); err != nil {
+			return err
+		}
+	}
+	if err := s.storePassword(ctx, id, newPassword); err != nil {
+		return err
+	}
+	log.FromContext(ctx).Info("Password changed", log.String("user_id", id))
+	return nil
+}
+
+// checkPassword verifies pw against the stored hash of a user and rehashes
+// it when the hash is outdated. Failing to rehash only logs, since the
+// password was right.
+func (s *userService) checkPassword(ctx context.Context, id, pw string) error {
+	hash, err := s.repo.GetPasswordHash(ctx, id)
+	if err != nil {
+		return err
+	}
+	if hash == "" {
+		return s.passwords.Reject(pw)
+	}
+	if err := s.passwords.Verify(hash, pw); err != nil {
+		return err
+	}
+
+	if s.passwords.NeedsRehash(hash) {
+		if err := s.storePassword(ctx, id, pw); err != nil {
+			log.FromContext(ctx).Warn("Failed to rehash password", log.String("user_id", id), log.Error(err))
+		}
+	}
+	return nil
+}
+
+// storePassword hashes pw and stores it as the password of a user
+func (s *userService) storePassword(ctx context.Context, id, pw string) error {
+	hash, err := s.passwords.Hash(pw)
+	if err != nil {
+		return err
+	}
+	return s.repo.SetPasswordHash(ctx, id, hash)
+}
+
+// Export batch sizes
+const (
+	defaultExportBatch = 500
+	maxExportBatch     = 1000
+)
+
+// ExportUsers calls fn with every user of the tenant in ID order, batchSize
+// users at a time. The table is walked by ID, so late batches cost the same
+// as the first. Emails and names are redacted (see Redact) unless
+// includePII is set, which requires the admin role when calls are
+// authenticated.
+func (s *userService) ExportUsers(ctx context.Context, batchSize int, includePII bool, fn func([]*repository.User) error) error {
+	if includePII {
+		if claims, ok := auth.FromContext(ctx); ok && !claims.HasRole(auth.RoleAdmin) {
+			return errors.WithCode(errors.New("exporting personal data requires the admin role"), errors.CodeForbidden)
+		}
+	}
+	if batchSize <= 0 {
+		batchSize = defaultExportBatch
+	}
+	if batchSize > maxExportBatch {
+		batchSize = maxExportBatch
+	}
+	log.FromContext(ctx).Info("Exporting users", log.Int("batch_size", batchSize), log.Any("include_pii", includePII))
+
+	for after := ""; ; {
+		users, err := s.repo.ListAfter(ctx, after, batchSize)
+		if err != nil {
+			return err
+		}
+		if len(users) == 0 {
+			return nil
+		}
+		after = users[len(users)-1].ID
+
+		if !includePII {
+			for i, u := range users {
+				users[i] = Redact(u)
+			}
+		}
+		if err := fn(users); err != nil {
+			return err
+		}
+		if len(users) < batchSize {
+			return nil
+		}
+	}
+}
+
+// Redact returns a copy of user with its personal data masked. The name
+// and the local part of the email keep their first character only; the
+// email domain is kept, so that exports can still be grouped by it.
+func Redact(user *repository.User) *repository.User {
+	redacted := *user
+	local, domain, found := strings.Cut(user.Email, "@")
+	redacted.Email = mask(local)
+	if found {
+		redacted.Email += "@" + domain
+	}
+	redacted.Name = mask(user.Name)
+	return &redacted
+}
+
+// mask keeps the first character of s and replaces the rest with "***"
+func mask(s string) string {
+	if s == "" {
+		return ""
+	}
+	r, _ := utf8.DecodeRuneInString(s)
+	return string(r) + "***"
 }
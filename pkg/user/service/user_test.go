@@ -18,24 +18,42 @@ package service
 
 import (
 	"context"
+	"fmt"
+	"sort"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/google/uuid"
+	"github.com/kevindiu/monorepo-go-example/internal/auth"
+	"github.com/kevindiu/monorepo-go-example/internal/config"
 	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/internal/idempotency"
+	"github.com/kevindiu/monorepo-go-example/internal/pagination"
+	"github.com/kevindiu/monorepo-go-example/internal/password"
 	"github.com/kevindiu/monorepo-go-example/pkg/user/repository"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 )
 
 // mockUserRepository is a mock implementation of repository.UserRepository
 type mockUserRepository struct {
-	users map[string]*repository.User
+	users     map[string]*repository.User
+	deleted   map[string]*repository.User
+	passwords map[string]string
 }
 
 func newMockUserRepository() *mockUserRepository {
 	return &mockUserRepository{
-		users: make(map[string]*repository.User),
+		users:     make(map[string]*repository.User),
+		deleted:   make(map[string]*repository.User),
+		passwords: make(map[string]string),
 	}
 }
 
 func (m *mockUserRepository) Create(ctx context.Context, user *repository.User) (*repository.User, error) {
+	if user.CreatedAt.IsZero() {
+		user.CreatedAt = time.Now()
+	}
 	m.users[user.ID] = user
 	return user, nil
 }
@@ -57,24 +75,135 @@ func (m *mockUserRepository) GetByEmail(ctx context.Context, email string) (*rep
 	return nil, nil
 }
 
-func (m *mockUserRepository) Update(ctx context.Context, user *repository.User) (*repository.User, error) {
-	if _, ok := m.users[user.ID]; !ok {
+func (m *mockUserRepository) GetMany(ctx context.Context, ids []string) ([]*repository.User, error) {
+	var users []*repository.User
+	for _, id := range ids {
+		if user, ok := m.users[id]; ok {
+			users = append(users, user)
+		}
+	}
+	return users, nil
+}
+
+func (m *mockUserRepository) Update(ctx context.Context, user *repository.User, fields ...string) (*repository.User, error) {
+	stored, ok := m.users[user.ID]
+	if !ok {
 		return nil, errors.WithCode(errors.New("user not found"), errors.CodeNotFound)
 	}
-	m.users[user.ID] = user
-	return user, nil
+	if len(fields) == 0 {
+		fields = []string{repository.FieldEmail, repository.FieldName}
+	}
+	// Like the database, only the named fields change
+	updated := *stored
+	for _, field := range fields {
+		switch field {
+		case repository.FieldEmail:
+			updated.Email = user.Email
+		case repository.FieldName:
+			updated.Name = user.Name
+		case repository.FieldRoles:
+			updated.Roles = user.Roles
+		}
+	}
+	m.users[user.ID] = &updated
+	return &updated, nil
 }
 
 func (m *mockUserRepository) Delete(ctx context.Context, id string) error {
+	if user, ok := m.users[id]; ok {
+		m.deleted[id] = user
+	}
 	delete(m.users, id)
 	return nil
 }
 
-func (m *mockUserRepository) List(ctx context.Context, limit, offset int) ([]*repository.User, error) {
+func (m *mockUserRepository) Undelete(ctx context.Context, id string) (*repository.User, error) {
+	user, ok := m.deleted[id]
+	if !ok {
+		return nil, errors.WithCode(errors.New("deleted user not found"), errors.CodeNotFound)
+	}
+	delete(m.deleted, id)
+	m.users[id] = user
+	return user, nil
+}
+
+func (m *mockUserRepository) Purge(ctx context.Context, id string) error {
+	if _, ok := m.users[id]; ok {
+		return errors.WithCode(errors.New("user must be deleted before it is purged"), errors.CodeInvalidInput)
+	}
+	if _, ok := m.deleted[id]; !ok {
+		return errors.WithCode(errors.New("user not found"), errors.CodeNotFound)
+	}
+	delete(m.deleted, id)
+	return nil
+}
+
+func (m *mockUserRepository) SetPasswordHash(ctx context.Context, id, hash string) error {
+	if _, ok := m.users[id]; !ok {
+		return errors.WithCode(errors.New("user not found"), errors.CodeNotFound)
+	}
+	m.passwords[id] = hash
+	return nil
+}
+
+func (m *mockUserRepository) GetPasswordHash(ctx context.Context, id string) (string, error) {
+	if _, ok := m.users[id]; !ok {
+		return "", errors.WithCode(errors.New("user not found"), errors.CodeNotFound)
+	}
+	return m.passwords[id], nil
+}
+
+func (m *mockUserRepository) List(ctx context.Context, after *pagination.Cursor, limit int) ([]*repository.User, error) {
 	users := make([]*repository.User, 0, len(m.users))
 	for _, user := range m.users {
+		if after != nil && !user.CreatedAt.Before(after.CreatedAt) &&
+			!(user.CreatedAt.Equal(after.CreatedAt) && user.ID < after.ID) {
+			continue
+		}
 		users = append(users, user)
 	}
+	sort.Slice(users, func(i, j int) bool {
+		if !users[i].CreatedAt.Equal(users[j].CreatedAt) {
+			return users[i].CreatedAt.After(users[j].CreatedAt)
+		}
+		return users[i].ID > users[j].ID
+	})
+	if limit < len(users) {
+		users = users[:limit]
+	}
+	return users, nil
+}
+
+func (m *mockUserRepository) Search(ctx context.Context, query string, offset, limit int) ([]*repository.User, error) {
+	query = strings.ToLower(query)
+	var users []*repository.User
+	for _, user := range m.users {
+		if strings.Contains(strings.ToLower(user.Name), query) || strings.Contains(strings.ToLower(user.Email), query) {
+			users = append(users, user)
+		}
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].Name < users[j].Name })
+	if offset > len(users) {
+		offset = len(users)
+	}
+	users = users[offset:]
+	if limit < len(users) {
+		users = users[:limit]
+	}
+	return users, nil
+}
+
+func (m *mockUserRepository) ListAfter(ctx context.Context, afterID string, limit int) ([]*repository.User, error) {
+	var users []*repository.User
+	for _, user := range m.users {
+		if user.ID > afterID {
+			users = append(users, user)
+		}
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].ID < users[j].ID })
+	if limit < len(users) {
+		users = users[:limit]
+	}
 	return users, nil
 }
 
@@ -133,6 +262,53 @@ func TestCreateUser(t *testing.T) {
 	}
 }
 
+// recordingStore records the responses saved with idempotency keys
+type recordingStore struct {
+	idempotency.Store
+	saved [][]byte
+}
+
+func (s *recordingStore) Complete(ctx context.Context, scope, key string, response []byte) error {
+	s.saved = append(s.saved, response)
+	return s.Store.Complete(ctx, scope, key, response)
+}
+
+func TestCreateUserIdempotency(t *testing.T) {
+	repo := newMockUserRepository()
+	store := &recordingStore{Store: idempotency.NewMemoryStore()}
+	svc := NewUserService(repo, WithIdempotency(store))
+	ctx := idempotency.WithKey(context.Background(), "signup-1")
+
+	first, err := svc.CreateUser(ctx, "retry@example.com", "Retry")
+	if err != nil {
+		t.Fatalf("CreateUser() error = %v", err)
+	}
+
+	// Without the key, the retry would fail as a duplicate email
+	retry, err := svc.CreateUser(ctx, "retry@example.com", "Retry")
+	if err != nil {
+		t.Fatalf("CreateUser() retry error = %v", err)
+	}
+	if retry.ID != first.ID {
+		t.Errorf("CreateUser() retry returned user %s, want %s", retry.ID, first.ID)
+	}
+	if retry.Email != "retry@example.com" {
+		t.Errorf("CreateUser() retry returned email %q, want the stored user's", retry.Email)
+	}
+	for _, saved := range store.saved {
+		if strings.Contains(string(saved), "retry@example.com") {
+			t.Errorf("idempotency response %q holds the email", saved)
+		}
+	}
+
+	if _, err := svc.CreateUser(ctx, "other@example.com", "Other"); errors.GetCode(err) != errors.CodeInvalidInput {
+		t.Errorf("CreateUser() with a reused key error = %v, want invalid input", err)
+	}
+	if len(repo.users) != 1 {
+		t.Errorf("created %d users, want 1", len(repo.users))
+	}
+}
+
 func TestGetUser(t *testing.T) {
 	repo := newMockUserRepository()
 	svc := NewUserService(repo)
@@ -179,6 +355,74 @@ func TestGetUser(t *testing.T) {
 	}
 }
 
+func TestGetUserByEmail(t *testing.T) {
+	svc := NewUserService(newMockUserRepository())
+	created, err := svc.CreateUser(context.Background(), "test@example.com", "Test User")
+	if err != nil {
+		t.Fatalf("CreateUser() error = %v", err)
+	}
+
+	user, err := svc.GetUserByEmail(context.Background(), "test@example.com")
+	if err != nil {
+		t.Fatalf("GetUserByEmail() error = %v", err)
+	}
+	if user.ID != created.ID {
+		t.Errorf("GetUserByEmail() = %s, want %s", user.ID, created.ID)
+	}
+
+	if _, err := svc.GetUserByEmail(context.Background(), ""); errors.GetCode(err) != errors.CodeInvalidInput {
+		t.Errorf("GetUserByEmail(\"\") error = %v, want invalid input", err)
+	}
+}
+
+func TestGetUsers(t *testing.T) {
+	svc := NewUserService(newMockUserRepository())
+	alice, err := svc.CreateUser(context.Background(), "alice@example.com", "Alice")
+	if err != nil {
+		t.Fatalf("CreateUser() error = %v", err)
+	}
+	bob, err := svc.CreateUser(context.Background(), "bob@example.com", "Bob")
+	if err != nil {
+		t.Fatalf("CreateUser() error = %v", err)
+	}
+
+	tooMany := make([]string, maxBatchSize+1)
+	for i := range tooMany {
+		tooMany[i] = uuid.New().String()
+	}
+
+	tests := []struct {
+		name     string
+		ids      []string
+		want     int
+		wantCode string
+	}{
+		{name: "found", ids: []string{alice.ID, bob.ID}, want: 2},
+		{name: "duplicates", ids: []string{alice.ID, alice.ID}, want: 1},
+		{name: "missing and malformed ids", ids: []string{alice.ID, uuid.New().String(), "not-a-uuid"}, want: 1},
+		{name: "empty", want: 0},
+		{name: "too many", ids: tooMany, wantCode: errors.CodeInvalidInput},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			users, err := svc.GetUsers(context.Background(), tt.ids)
+			if tt.wantCode != "" {
+				if errors.GetCode(err) != tt.wantCode {
+					t.Fatalf("GetUsers() error = %v, want code %s", err, tt.wantCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetUsers() error = %v", err)
+			}
+			if len(users) != tt.want {
+				t.Errorf("GetUsers() = %d users, want %d", len(users), tt.want)
+			}
+		})
+	}
+}
+
 func TestUpdateUser(t *testing.T) {
 	repo := newMockUserRepository()
 	svc := NewUserService(repo)
@@ -191,7 +435,7 @@ func TestUpdateUser(t *testing.T) {
 
 	updatedName := "Updated Name"
 	updatedEmail := "updated@example.com"
-	updatedUser, err := svc.UpdateUser(context.Background(), createdUser.ID, updatedEmail, updatedName)
+	updatedUser, err := svc.UpdateUser(context.Background(), createdUser.ID, updatedEmail, updatedName, nil, nil)
 	if err != nil {
 		t.Fatalf("UpdateUser() error = %v", err)
 	}
@@ -204,6 +448,99 @@ func TestUpdateUser(t *testing.T) {
 	}
 }
 
+func TestUpdateUserMask(t *testing.T) {
+	tests := []struct {
+		name      string
+		email     string
+		userName  string
+		paths     []string
+		wantEmail string
+		wantName  string
+		wantCode  string
+	}{
+		{name: "name only", userName: "Renamed", paths: []string{"name"}, wantEmail: "test@example.com", wantName: "Renamed"},
+		{name: "email only", email: "new@example.com", paths: []string{"email"}, wantEmail: "new@example.com", wantName: "Test User"},
+		{name: "both", email: "new@example.com", userName: "Renamed", paths: []string{"email", "name"}, wantEmail: "new@example.com", wantName: "Renamed"},
+		{name: "empty mask requires both", userName: "Renamed", wantCode: errors.CodeInvalidInput},
+		{name: "masked field required", paths: []string{"name"}, wantCode: errors.CodeInvalidInput},
+		{name: "unknown field", userName: "Renamed", paths: []string{"id"}, wantCode: errors.CodeInvalidInput},
+		{name: "email taken", email: "taken@example.com", paths: []string{"email"}, wantCode: errors.CodeConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := NewUserService(newMockUserRepository())
+			user, err := svc.CreateUser(context.Background(), "test@example.com", "Test User")
+			if err != nil {
+				t.Fatalf("CreateUser() error = %v", err)
+			}
+			if _, err := svc.CreateUser(context.Background(), "taken@example.com", "Other User"); err != nil {
+				t.Fatalf("CreateUser() error = %v", err)
+			}
+
+			var mask *fieldmaskpb.FieldMask
+			if tt.paths != nil {
+				mask = &fieldmaskpb.FieldMask{Paths: tt.paths}
+			}
+			updated, err := svc.UpdateUser(context.Background(), user.ID, tt.email, tt.userName, nil, mask)
+			if tt.wantCode != "" {
+				if errors.GetCode(err) != tt.wantCode {
+					t.Fatalf("UpdateUser() error = %v, want code %s", err, tt.wantCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("UpdateUser() error = %v", err)
+			}
+			if updated.Email != tt.wantEmail || updated.Name != tt.wantName {
+				t.Errorf("UpdateUser() = %s %q, want %s %q", updated.Email, updated.Name, tt.wantEmail, tt.wantName)
+			}
+		})
+	}
+}
+
+func TestUpdateUserRoles(t *testing.T) {
+	tests := []struct {
+		name       string
+		roles      []string
+		callerRole string
+		noClaims   bool
+		want       []string
+		wantCode   string
+	}{
+		{name: "admin", roles: []string{"customer", "admin", "admin"}, callerRole: auth.RoleAdmin, want: []string{"admin", "customer"}},
+		{name: "unauthenticated", roles: []string{"admin"}, noClaims: true, want: []string{"admin"}},
+		{name: "not admin", roles: []string{"admin"}, callerRole: auth.RoleCustomer, wantCode: errors.CodeForbidden},
+		{name: "unknown role", roles: []string{"root"}, callerRole: auth.RoleAdmin, wantCode: errors.CodeInvalidInput},
+		{name: "no roles", callerRole: auth.RoleAdmin, wantCode: errors.CodeInvalidInput},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := NewUserService(newMockUserRepository())
+			user, err := svc.CreateUser(context.Background(), "test@example.com", "Test User")
+			if err != nil {
+				t.Fatalf("CreateUser() error = %v", err)
+			}
+			if len(user.Roles) != 1 || user.Roles[0] != auth.RoleCustomer {
+				t.Fatalf("CreateUser() roles = %v, want [customer]", user.Roles)
+			}
+
+			ctx := context.Background()
+			if !tt.noClaims {
+				ctx = auth.WithClaims(ctx, &auth.Claims{Subject: "operator", Roles: []string{tt.callerRole}})
+			}
+			updated, err := svc.UpdateUser(ctx, user.ID, "", "", tt.roles, &fieldmaskpb.FieldMask{Paths: []string{"roles"}})
+			if errors.GetCode(err) != tt.wantCode {
+				t.Fatalf("UpdateUser() error = %v, want code %q", err, tt.wantCode)
+			}
+			if tt.wantCode == "" && strings.Join(updated.Roles, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("UpdateUser() roles = %v, want %v", updated.Roles, tt.want)
+			}
+		})
+	}
+}
+
 func TestDeleteUser(t *testing.T) {
 	repo := newMockUserRepository()
 	svc := NewUserService(repo)
@@ -226,26 +563,361 @@ func TestDeleteUser(t *testing.T) {
 	}
 }
 
-func TestListUsers(t *testing.T) {
+func TestUndeleteUser(t *testing.T) {
 	repo := newMockUserRepository()
 	svc := NewUserService(repo)
+	ctx := context.Background()
 
-	// Create some users
-	_, err := svc.CreateUser(context.Background(), "user1@example.com", "User 1")
+	createdUser, err := svc.CreateUser(ctx, "test@example.com", "Test User")
 	if err != nil {
 		t.Fatalf("CreateUser() error = %v", err)
 	}
-	_, err = svc.CreateUser(context.Background(), "user2@example.com", "User 2")
+
+	if _, err := svc.UndeleteUser(ctx, createdUser.ID); errors.GetCode(err) != errors.CodeNotFound {
+		t.Errorf("UndeleteUser() of a live user error = %v, want not found", err)
+	}
+
+	if err := svc.DeleteUser(ctx, createdUser.ID); err != nil {
+		t.Fatalf("DeleteUser() error = %v", err)
+	}
+	restored, err := svc.UndeleteUser(ctx, createdUser.ID)
 	if err != nil {
-		t.Fatalf("CreateUser() error = %v", err)
+		t.Fatalf("UndeleteUser() error = %v", err)
+	}
+	if restored.ID != createdUser.ID {
+		t.Errorf("UndeleteUser() restored %s, want %s", restored.ID, createdUser.ID)
+	}
+	if _, err := svc.GetUser(ctx, createdUser.ID); err != nil {
+		t.Errorf("GetUser() after undelete error = %v", err)
+	}
+
+	if _, err := svc.UndeleteUser(ctx, ""); errors.GetCode(err) != errors.CodeInvalidInput {
+		t.Errorf("UndeleteUser() without ID error = %v, want invalid input", err)
+	}
+}
+
+func TestPurgeUser(t *testing.T) {
+	tests := []struct {
+		name     string
+		roles    []string
+		noClaims bool
+		deleted  bool
+		wantCode string
+	}{
+		{name: "admin", roles: []string{auth.RoleAdmin}, deleted: true},
+		{name: "unauthenticated", noClaims: true, deleted: true},
+		{name: "not admin", roles: []string{"customer"}, deleted: true, wantCode: errors.CodeForbidden},
+		{name: "not deleted", roles: []string{auth.RoleAdmin}, wantCode: errors.CodeInvalidInput},
 	}
 
-	users, _, err := svc.ListUsers(context.Background(), 10, "")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMockUserRepository()
+			svc := NewUserService(repo)
+			ctx := context.Background()
+
+			user, err := svc.CreateUser(ctx, "test@example.com", "Test User")
+			if err != nil {
+				t.Fatalf("CreateUser() error = %v", err)
+			}
+			if tt.deleted {
+				if err := svc.DeleteUser(ctx, user.ID); err != nil {
+					t.Fatalf("DeleteUser() error = %v", err)
+				}
+			}
+
+			if !tt.noClaims {
+				ctx = auth.WithClaims(ctx, &auth.Claims{Subject: "operator", Roles: tt.roles})
+			}
+			err = svc.PurgeUser(ctx, user.ID)
+			if errors.GetCode(err) != tt.wantCode {
+				t.Fatalf("PurgeUser() error = %v, want code %q", err, tt.wantCode)
+			}
+			if tt.wantCode == "" {
+				if _, err := svc.UndeleteUser(ctx, user.ID); errors.GetCode(err) != errors.CodeNotFound {
+					t.Errorf("UndeleteUser() after purge error = %v, want not found", err)
+				}
+			}
+		})
+	}
+}
+
+// testHasher returns a cheap bcrypt hasher for tests
+func testHasher(t *testing.T, cost int) *password.Hasher {
+	t.Helper()
+	h, err := password.New(&config.Password{Algorithm: password.AlgorithmBcrypt, MinLength: 12, BcryptCost: cost})
 	if err != nil {
-		t.Fatalf("ListUsers() error = %v", err)
+		t.Fatal(err)
+	}
+	return h
+}
+
+func TestPasswords(t *testing.T) {
+	repo := newMockUserRepository()
+	svc := NewUserService(repo, WithPasswords(testHasher(t, 10)))
+	ctx := context.Background()
+
+	user, err := svc.CreateUser(ctx, "test@example.com", "Test User")
+	if err != nil {
+		t.Fatalf("CreateUser() error = %v", err)
+	}
+
+	if _, err := svc.VerifyPassword(ctx, user.Email, "correct horse battery"); errors.GetCode(err) != errors.CodeUnauthorized {
+		t.Errorf("VerifyPassword() without password error = %v, want unauthorized", err)
+	}
+	if err := svc.SetPassword(ctx, user.ID, "too short"); errors.GetCode(err) != errors.CodeInvalidInput {
+		t.Errorf("SetPassword() short password error = %v, want invalid input", err)
+	}
+	if err := svc.SetPassword(ctx, user.ID, "correct horse battery"); err != nil {
+		t.Fatalf("SetPassword() error = %v", err)
+	}
+	if strings.Contains(repo.passwords[user.ID], "horse") {
+		t.Fatal("SetPassword() stored the password in plaintext")
+	}
+
+	got, err := svc.VerifyPassword(ctx, user.Email, "correct horse battery")
+	if err != nil || got.ID != user.ID {
+		t.Fatalf("VerifyPassword() = %v, %v, want user %s", got, err, user.ID)
+	}
+	for _, email := range []string{user.Email, "nobody@example.com"} {
+		if _, err := svc.VerifyPassword(ctx, email, "wrong horse battery"); errors.GetCode(err) != errors.CodeUnauthorized {
+			t.Errorf("VerifyPassword(%s) wrong password error = %v, want unauthorized", email, err)
+		}
+	}
+
+	if err := svc.ChangePassword(ctx, user.ID, "wrong horse battery", "staple battery horse"); errors.GetCode(err) != errors.CodeUnauthorized {
+		t.Errorf("ChangePassword() wrong current password error = %v, want unauthorized", err)
+	}
+	if err := svc.ChangePassword(ctx, user.ID, "correct horse battery", "staple battery horse"); err != nil {
+		t.Fatalf("ChangePassword() error = %v", err)
+	}
+	if _, err := svc.VerifyPassword(ctx, user.Email, "staple battery horse"); err != nil {
+		t.Errorf("VerifyPassword() new password error = %v", err)
+	}
+}
+
+func TestPasswordsRehash(t *testing.T) {
+	repo := newMockUserRepository()
+	ctx := context.Background()
+	user, _ := repo.Create(ctx, &repository.User{ID: uuid.New().String(), Email: "test@example.com", Name: "Test User"})
+
+	if err := NewUserService(repo, WithPasswords(testHasher(t, 10))).SetPassword(ctx, user.ID, "correct horse battery"); err != nil {
+		t.Fatalf("SetPassword() error = %v", err)
+	}
+	old := repo.passwords[user.ID]
+
+	if _, err := NewUserService(repo, WithPasswords(testHasher(t, 11))).VerifyPassword(ctx, user.Email, "correct horse battery"); err != nil {
+		t.Fatalf("VerifyPassword() error = %v", err)
+	}
+	if repo.passwords[user.ID] == old {
+		t.Error("VerifyPassword() kept a hash weaker than configured")
+	}
+}
+
+func TestPasswordsAuthorization(t *testing.T) {
+	repo := newMockUserRepository()
+	svc := NewUserService(repo, WithPasswords(testHasher(t, 10)))
+	user, _ := svc.CreateUser(context.Background(), "test@example.com", "Test User")
+
+	tests := []struct {
+		name       string
+		claims     *auth.Claims
+		wantSet    string
+		wantChange string
+	}{
+		{name: "self", claims: &auth.Claims{Subject: user.ID}, wantChange: errors.CodeUnauthorized},
+		{name: "admin", claims: &auth.Claims{Subject: "admin", Roles: []string{auth.RoleAdmin}}, wantChange: errors.CodeForbidden},
+		{name: "other user", claims: &auth.Claims{Subject: "other"}, wantSet: errors.CodeForbidden, wantChange: errors.CodeForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := auth.WithClaims(context.Background(), tt.claims)
+			if err := svc.SetPassword(ctx, user.ID, "correct horse battery"); errors.GetCode(err) != tt.wantSet {
+				t.Errorf("SetPassword() error = %v, want code %q", err, tt.wantSet)
+			}
+			if err := svc.ChangePassword(ctx, user.ID, "wrong horse battery", "staple battery horse"); errors.GetCode(err) != tt.wantChange {
+				t.Errorf("ChangePassword() error = %v, want code %q", err, tt.wantChange)
+			}
+		})
+	}
+}
+
+func TestPasswordsDisabled(t *testing.T) {
+	svc := NewUserService(newMockUserRepository())
+	if err := svc.SetPassword(context.Background(), "u1", "correct horse battery"); errors.GetCode(err) != errors.CodeUnavailable {
+		t.Errorf("SetPassword() error = %v, want unavailable", err)
+	}
+}
+
+func TestListUsers(t *testing.T) {
+	repo := newMockUserRepository()
+	svc := NewUserService(repo)
+
+	// Create users a second apart, newest last
+	created := time.Now()
+	for i := 1; i <= 5; i++ {
+		id := fmt.Sprintf("u%d", i)
+		repo.users[id] = &repository.User{ID: id, Email: id + "@example.com", CreatedAt: created.Add(time.Duration(i) * time.Second)}
+	}
+
+	var got []string
+	token := ""
+	for pages := 0; ; pages++ {
+		if pages > 5 {
+			t.Fatal("ListUsers() did not reach the last page")
+		}
+		users, next, err := svc.ListUsers(context.Background(), 2, token)
+		if err != nil {
+			t.Fatalf("ListUsers() error = %v", err)
+		}
+		for _, u := range users {
+			got = append(got, u.ID)
+		}
+		if token = next; token == "" {
+			break
+		}
+	}
+
+	want := []string{"u5", "u4", "u3", "u2", "u1"}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("ListUsers() walked %v, want %v", got, want)
+	}
+
+	if _, _, err := svc.ListUsers(context.Background(), 2, "2"); errors.GetCode(err) != errors.CodeInvalidInput {
+		t.Errorf("ListUsers() with an offset token error = %v, want invalid input", err)
+	}
+}
+
+func TestSearchUsers(t *testing.T) {
+	repo := newMockUserRepository()
+	svc := NewUserService(repo)
+	for i, name := range []string{"Alice Smith", "Alicia Keys", "Bob Smithers", "Carol Alison"} {
+		id := fmt.Sprintf("u%d", i+1)
+		repo.users[id] = &repository.User{ID: id, Email: fmt.Sprintf("user%d@example.com", i+1), Name: name}
+	}
+
+	var got []string
+	token := ""
+	for pages := 0; ; pages++ {
+		if pages > 3 {
+			t.Fatal("SearchUsers() did not reach the last page")
+		}
+		users, next, err := svc.SearchUsers(context.Background(), " ali ", 2, token)
+		if err != nil {
+			t.Fatalf("SearchUsers() error = %v", err)
+		}
+		for _, u := range users {
+			got = append(got, u.Name)
+		}
+		if token = next; token == "" {
+			break
+		}
+	}
+	want := []string{"Alice Smith", "Alicia Keys", "Carol Alison"}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("SearchUsers() walked %v, want %v", got, want)
+	}
+
+	_, next, err := svc.SearchUsers(context.Background(), "smith", 1, "")
+	if err != nil || next == "" {
+		t.Fatalf("SearchUsers() = next %q, error %v, want another page", next, err)
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		token string
+	}{
+		{name: "too short", query: "al"},
+		{name: "blank", query: "    "},
+		{name: "token of another query", query: "alice", token: next},
+		{name: "offset token", query: "smith", token: "2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := svc.SearchUsers(context.Background(), tt.query, 10, tt.token); errors.GetCode(err) != errors.CodeInvalidInput {
+				t.Errorf("SearchUsers() error = %v, want invalid input", err)
+			}
+		})
+	}
+}
+
+func TestExportUsers(t *testing.T) {
+	repo := newMockUserRepository()
+	svc := NewUserService(repo)
+	for i := 0; i < 5; i++ {
+		id := fmt.Sprintf("u%d", i)
+		repo.users[id] = &repository.User{ID: id, Email: id + "@example.com", Name: "User " + id}
 	}
 
-	if len(users) != 2 {
-		t.Errorf("ListUsers() returned %d users, want 2", len(users))
+	tests := []struct {
+		name       string
+		claims     *auth.Claims
+		includePII bool
+		wantCode   string
+	}{
+		{name: "redacted", claims: &auth.Claims{Subject: "warehouse"}},
+		{name: "pii as admin", claims: &auth.Claims{Subject: "ops", Roles: []string{auth.RoleAdmin}}, includePII: true},
+		{name: "pii unauthenticated", includePII: true},
+		{name: "pii without admin", claims: &auth.Claims{Subject: "warehouse"}, includePII: true, wantCode: errors.CodeForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.claims != nil {
+				ctx = auth.WithClaims(ctx, tt.claims)
+			}
+
+			var batches [][]*repository.User
+			err := svc.ExportUsers(ctx, 2, tt.includePII, func(users []*repository.User) error {
+				batches = append(batches, users)
+				return nil
+			})
+			if errors.GetCode(err) != tt.wantCode {
+				t.Fatalf("ExportUsers() error = %v, want code %q", err, tt.wantCode)
+			}
+			if tt.wantCode != "" {
+				return
+			}
+
+			if len(batches) != 3 || len(batches[2]) != 1 {
+				t.Fatalf("ExportUsers() made %d batches, want 2, 2 and 1 users", len(batches))
+			}
+			var i int
+			for _, batch := range batches {
+				for _, u := range batch {
+					id := fmt.Sprintf("u%d", i)
+					wantEmail, wantName := id+"@example.com", "User "+id
+					if !tt.includePII {
+						wantEmail, wantName = "u***@example.com", "U***"
+					}
+					if u.ID != id || u.Email != wantEmail || u.Name != wantName {
+						t.Errorf("user %d = %+v, want %s <%s> %q", i, u, id, wantEmail, wantName)
+					}
+					i++
+				}
+			}
+			if repo.users["u0"].Email != "u0@example.com" {
+				t.Error("ExportUsers() redacted the stored user")
+			}
+		})
+	}
+}
+
+func TestRedact(t *testing.T) {
+	tests := []struct {
+		email, name         string
+		wantEmail, wantName string
+	}{
+		{"alice@example.com", "Alice Smith", "a***@example.com", "A***"},
+		{"émile@example.fr", "Émile", "é***@example.fr", "É***"},
+		{"no-domain", "", "n***", ""},
+	}
+	for _, tt := range tests {
+		got := Redact(&repository.User{ID: "u1", Email: tt.email, Name: tt.name})
+		if got.ID != "u1" || got.Email != tt.wantEmail || got.Name != tt.wantName {
+			t.Errorf("Redact(%q, %q) = %q, %q, want %q, %q", tt.email, tt.name, got.Email, got.Name, tt.wantEmail, tt.wantName)
+		}
 	}
 }
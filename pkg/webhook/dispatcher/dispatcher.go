@@ -0,0 +1,340 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package dispatcher
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/internal/events"
+	"github.com/kevindiu/monorepo-go-example/internal/log"
+	"github.com/kevindiu/monorepo-go-example/pkg/webhook/repository"
+)
+
+// Default dispatcher settings
+const (
+	DefaultGroup          = "webhook-dispatcher"
+	DefaultPollInterval   = time.Second
+	DefaultBatchSize      = 50
+	DefaultMaxAttempts    = 8
+	DefaultInitialBackoff = 10 * time.Second
+	DefaultMaxBackoff     = time.Hour
+	DefaultTimeout        = 10 * time.Second
+)
+
+// maxErrorBody bounds how much of a failed response is kept in the log
+const maxErrorBody = 512
+
+// Config holds dispatcher configuration
+type Config struct {
+	Repository repository.Repository
+	// Subscriber and Topics are optional; without them the dispatcher only
+	// delivers events passed to Enqueue
+	Subscriber events.Subscriber
+	Topics     []string
+	Group      string
+	Client     *http.Client
+	Logger     *log.Logger
+
+	PollInterval   time.Duration
+	BatchSize      int
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	Timeout        time.Duration
+}
+
+// Dispatcher fans events out to matching webhook endpoints and delivers them.
+// Deliveries are persisted before they are attempted, failed attempts are
+// retried with exponential backoff, and deliveries that exhaust their
+// attempts are dead-lettered for manual redelivery.
+type Dispatcher struct {
+	repo           repository.Repository
+	subscriber     events.Subscriber
+	topics         []string
+	group          string
+	client         *http.Client
+	logger         *log.Logger
+	pollInterval   time.Duration
+	batchSize      int
+	maxAttempts    int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	timeout        time.Duration
+}
+
+// New creates a new dispatcher
+func New(cfg Config) (*Dispatcher, error) {
+	if cfg.Repository == nil {
+		return nil, errors.WithCode(errors.New("webhook repository is required"), errors.CodeInvalidInput)
+	}
+	if cfg.Subscriber == nil && len(cfg.Topics) > 0 {
+		return nil, errors.WithCode(errors.New("subscriber is required to consume topics"), errors.CodeInvalidInput)
+	}
+	if cfg.Group == "" {
+		cfg.Group = DefaultGroup
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = log.NewDefault()
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = DefaultPollInterval
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = DefaultBatchSize
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = DefaultMaxAttempts
+	}
+	if cfg.InitialBackoff <= 0 {
+		cfg.InitialBackoff = DefaultInitialBackoff
+	}
+	if cfg.MaxBackoff <= 0 {
+		cfg.MaxBackoff = DefaultMaxBackoff
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultTimeout
+	}
+	if cfg.Client == nil {
+		cfg.Client = &http.Client{Timeout: cfg.Timeout}
+	}
+
+	return &Dispatcher{
+		repo:           cfg.Repository,
+		subscriber:     cfg.Subscriber,
+		topics:         cfg.Topics,
+		group:          cfg.Group,
+		client:         cfg.Client,
+		logger:         cfg.Logger.Named("webhook"),
+		pollInterval:   cfg.PollInterval,
+		batchSize:      cfg.BatchSize,
+		maxAttempts:    cfg.MaxAttempts,
+		initialBackoff: cfg.InitialBackoff,
+		maxBackoff:     cfg.MaxBackoff,
+		timeout:        cfg.Timeout,
+	}, nil
+}
+
+// Run consumes the configured topics and delivers due webhooks until ctx is
+// cancelled
+func (d *Dispatcher) Run(ctx context.Context) error {
+	d.logger.Info("Starting webhook dispatcher",
+		log.Any("topics", d.topics),
+		log.Any("poll_interval", d.pollInterval),
+		log.Int("max_attempts", d.maxAttempts),
+	)
+
+	var wg sync.WaitGroup
+	for _, topic := range d.topics {
+		topic := topic
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := d.subscriber.Subscribe(ctx, topic, d.group, d.Enqueue); err != nil && ctx.Err() == nil {
+				d.logger.Error("Webhook subscription failed", log.String("topic", topic), log.Error(err))
+			}
+		}()
+	}
+
+	ticker := time.NewTicker(d.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		// Keep draining while full batches are coming back
+		for ctx.Err() == nil {
+			n, err := d.DeliverOnce(ctx)
+			if err != nil {
+				d.logger.Error("Webhook delivery iteration failed", log.Error(err))
+				break
+			}
+			if n < d.batchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Enqueue records a pending delivery for every active endpoint subscribed to
+// the event type. It is the dispatcher's events.Handler.
+func (d *Dispatcher) Enqueue(ctx context.Context, event *events.Event) error {
+	endpoints, err := d.repo.ListActiveEndpoints(ctx)
+	if err != nil {
+		return err
+	}
+
+	var payload []byte
+	for _, endpoint := range endpoints {
+		if !endpoint.Matches(event.Type) {
+			continue
+		}
+
+		if payload == nil {
+			if payload, err = events.Marshal(event); err != nil {
+				return err
+			}
+		}
+
+		if err := d.repo.CreateDelivery(ctx, &repository.Delivery{
+			EndpointID: endpoint.ID,
+			EventID:    event.ID,
+			EventType:  event.Type,
+			Payload:    payload,
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DeliverOnce attempts one batch of due deliveries and returns how many were
+// attempted. Cancelling ctx stops the batch after the current attempt.
+func (d *Dispatcher) DeliverOnce(ctx context.Context) (int, error) {
+	// Lease the rows for longer than an attempt can take so that another
+	// dispatcher does not pick them up mid-request
+	deliveries, err := d.repo.ClaimDueDeliveries(ctx, d.batchSize, 2*d.timeout)
+	if err != nil {
+		return 0, err
+	}
+
+	endpoints := make(map[string]*repository.Endpoint)
+	attempted := 0
+	for _, delivery := range deliveries {
+		// Leave the rest of the batch to be retried once its lease expires
+		if ctx.Err() != nil {
+			break
+		}
+
+		endpoint, ok := endpoints[delivery.EndpointID]
+		if !ok {
+			endpoint, err = d.repo.GetEndpoint(ctx, delivery.EndpointID)
+			if err != nil && errors.GetCode(err) != errors.CodeNotFound {
+				return attempted, err
+			}
+			endpoints[delivery.EndpointID] = endpoint
+		}
+
+		// An attempt under way is finished and recorded even if ctx is
+		// cancelled meanwhile; it is bounded by the request timeout
+		attemptCtx := context.WithoutCancel(ctx)
+		d.attempt(attemptCtx, endpoint, delivery)
+		attempted++
+
+		if err := d.repo.UpdateDelivery(attemptCtx, delivery); err != nil {
+			return attempted, err
+		}
+	}
+
+	return attempted, nil
+}
+
+// attempt sends a delivery and records the outcome on it
+func (d *Dispatcher) attempt(ctx context.Context, endpoint *repository.Endpoint, delivery *repository.Delivery) {
+	if endpoint == nil || !endpoint.Active {
+		delivery.Status = repository.DeliveryStatusDead
+		delivery.LastError = "endpoint is no longer active"
+		return
+	}
+
+	delivery.Attempts++
+	code, err := d.send(ctx, endpoint, delivery)
+	delivery.ResponseCode = code
+
+	if err == nil {
+		delivery.Status = repository.DeliveryStatusSucceeded
+		delivery.LastError = ""
+		return
+	}
+
+	delivery.LastError = err.Error()
+	if delivery.Attempts >= d.maxAttempts {
+		delivery.Status = repository.DeliveryStatusDead
+		d.logger.Warn("Webhook delivery dead-lettered",
+			log.String("delivery_id", delivery.ID),
+			log.String("endpoint_id", endpoint.ID),
+			log.Int("attempts", delivery.Attempts),
+			log.Error(err),
+		)
+		return
+	}
+
+	delivery.NextAttemptAt = time.Now().Add(d.backoff(delivery.Attempts))
+	d.logger.Debug("Webhook delivery failed, will retry",
+		log.String("delivery_id", delivery.ID),
+		log.Int("attempts", delivery.Attempts),
+		log.Any("next_attempt_at", delivery.NextAttemptAt),
+		log.Error(err),
+	)
+}
+
+// send posts the event to the endpoint and returns the response status code
+func (d *Dispatcher) send(ctx context.Context, endpoint *repository.Endpoint, delivery *repository.Delivery) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, d.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to build webhook request")
+	}
+
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEventType, delivery.EventType)
+	req.Header.Set(HeaderDelivery, delivery.ID)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(endpoint.Secret, timestamp, delivery.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, errors.Wrap(err, "webhook request failed")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		io.Copy(io.Discard, resp.Body)
+		return resp.StatusCode, nil
+	}
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	return resp.StatusCode, errors.Newf("endpoint responded with %d: %s", resp.StatusCode, bytes.TrimSpace(body))
+}
+
+// backoff returns the delay before the next attempt, doubling from the
+// initial backoff up to the maximum
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	delay := d.initialBackoff
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= d.maxBackoff || delay <= 0 {
+			return d.maxBackoff
+		}
+	}
+	return delay
+}
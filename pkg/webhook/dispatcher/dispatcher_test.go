@@ -0,0 +1,299 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package dispatcher
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/internal/events"
+	"github.com/kevindiu/monorepo-go-example/pkg/webhook/repository"
+)
+
+// mockRepository implements repository.Repository in memory
+type mockRepository struct {
+	mu         sync.Mutex
+	endpoints  map[string]*repository.Endpoint
+	deliveries []*repository.Delivery
+}
+
+func newMockRepository(endpoints ...*repository.Endpoint) *mockRepository {
+	m := &mockRepository{endpoints: make(map[string]*repository.Endpoint)}
+	for _, e := range endpoints {
+		m.endpoints[e.ID] = e
+	}
+	return m
+}
+
+func (m *mockRepository) CreateEndpoint(ctx context.Context, endpoint *repository.Endpoint) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.endpoints[endpoint.ID] = endpoint
+	return nil
+}
+
+func (m *mockRepository) GetEndpoint(ctx context.Context, id string) (*repository.Endpoint, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, ok := m.endpoints[id]
+	if !ok {
+		return nil, errors.WithCode(errors.New("webhook endpoint not found"), errors.CodeNotFound)
+	}
+	return e, nil
+}
+
+func (m *mockRepository) ListEndpoints(ctx context.Context, limit, offset int) ([]*repository.Endpoint, error) {
+	return m.ListActiveEndpoints(ctx)
+}
+
+func (m *mockRepository) CountEndpoints(ctx context.Context) (int64, bool, error) {
+	return int64(len(m.endpoints)), true, nil
+}
+
+func (m *mockRepository) ListActiveEndpoints(ctx context.Context) ([]*repository.Endpoint, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var out []*repository.Endpoint
+	for _, e := range m.endpoints {
+		if e.Active {
+			out = append(out, e)
+		}
+	}
+	return out, nil
+}
+
+func (m *mockRepository) DeleteEndpoint(ctx context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.endpoints, id)
+	return nil
+}
+
+func (m *mockRepository) CreateDelivery(ctx context.Context, delivery *repository.Delivery) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, d := range m.deliveries {
+		if d.EndpointID == delivery.EndpointID && d.EventID == delivery.EventID {
+			return nil
+		}
+	}
+	delivery.ID = delivery.EndpointID + "/" + delivery.EventID
+	delivery.Status = repository.DeliveryStatusPending
+	delivery.NextAttemptAt = time.Now()
+	m.deliveries = append(m.deliveries, delivery)
+	return nil
+}
+
+func (m *mockRepository) GetDelivery(ctx context.Context, id string) (*repository.Delivery, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, d := range m.deliveries {
+		if d.ID == id {
+			return d, nil
+		}
+	}
+	return nil, errors.WithCode(errors.New("webhook delivery not found"), errors.CodeNotFound)
+}
+
+func (m *mockRepository) ListDeliveries(ctx context.Context, filter repository.DeliveryFilter, limit, offset int) ([]*repository.Delivery, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]*repository.Delivery(nil), m.deliveries...), nil
+}
+
+func (m *mockRepository) CountDeliveries(ctx context.Context, filter repository.DeliveryFilter) (int64, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return int64(len(m.deliveries)), true, nil
+}
+
+func (m *mockRepository) ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]*repository.Delivery, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	var out []*repository.Delivery
+	for _, d := range m.deliveries {
+		if d.Status == repository.DeliveryStatusPending && !d.NextAttemptAt.After(now) && len(out) < limit {
+			d.NextAttemptAt = now.Add(lease)
+			out = append(out, d)
+		}
+	}
+	return out, nil
+}
+
+func (m *mockRepository) UpdateDelivery(ctx context.Context, delivery *repository.Delivery) error {
+	return nil
+}
+
+func newTestEvent(t *testing.T, eventType string) *events.Event {
+	t.Helper()
+	event, err := events.NewEvent(eventType, "order-1", map[string]string{"id": "order-1"})
+	if err != nil {
+		t.Fatalf("NewEvent() error = %v", err)
+	}
+	return event
+}
+
+func TestEnqueueFiltersEndpoints(t *testing.T) {
+	repo := newMockRepository(
+		&repository.Endpoint{ID: "all", Active: true},
+		&repository.Endpoint{ID: "orders", Active: true, EventTypes: []string{"order.*"}},
+		&repository.Endpoint{ID: "users", Active: true, EventTypes: []string{"user.created"}},
+		&repository.Endpoint{ID: "inactive", Active: false},
+	)
+	d, err := New(Config{Repository: repo})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	event := newTestEvent(t, "order.created")
+	if err := d.Enqueue(context.Background(), event); err != nil {
+		t.Fatalf("Enqueue() error = %v", err)
+	}
+	// Redelivered events must not create duplicate deliveries
+	if err := d.Enqueue(context.Background(), event); err != nil {
+		t.Fatalf("Enqueue() error = %v", err)
+	}
+
+	got := map[string]bool{}
+	for _, delivery := range repo.deliveries {
+		got[delivery.EndpointID] = true
+	}
+	if len(repo.deliveries) != 2 || !got["all"] || !got["orders"] {
+		t.Errorf("deliveries went to %v, want all and orders", got)
+	}
+}
+
+func TestDeliverOnceSignsRequests(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		received *http.Request
+		body     []byte
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		received = r
+		body, _ = io.ReadAll(r.Body)
+	}))
+	defer server.Close()
+
+	repo := newMockRepository(&repository.Endpoint{ID: "e1", URL: server.URL, Secret: "s3cret", Active: true})
+	d, _ := New(Config{Repository: repo})
+
+	if err := d.Enqueue(context.Background(), newTestEvent(t, "order.created")); err != nil {
+		t.Fatalf("Enqueue() error = %v", err)
+	}
+	if n, err := d.DeliverOnce(context.Background()); err != nil || n != 1 {
+		t.Fatalf("DeliverOnce() = %d, %v; want 1, nil", n, err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if received == nil {
+		t.Fatal("endpoint did not receive a request")
+	}
+	if got := received.Header.Get(HeaderEventType); got != "order.created" {
+		t.Errorf("%s = %q, want order.created", HeaderEventType, got)
+	}
+	if err := Verify("s3cret", received.Header.Get(HeaderSignature), received.Header.Get(HeaderTimestamp), body, time.Minute); err != nil {
+		t.Errorf("Verify() error = %v", err)
+	}
+	if err := Verify("other", received.Header.Get(HeaderSignature), received.Header.Get(HeaderTimestamp), body, time.Minute); err == nil {
+		t.Error("Verify() accepted a signature made with a different secret")
+	}
+
+	delivery := repo.deliveries[0]
+	if delivery.Status != repository.DeliveryStatusSucceeded || delivery.Attempts != 1 || delivery.ResponseCode != http.StatusOK {
+		t.Errorf("delivery = %+v, want succeeded after one attempt", delivery)
+	}
+}
+
+func TestDeliverOnceRetriesAndDeadLetters(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	repo := newMockRepository(&repository.Endpoint{ID: "e1", URL: server.URL, Secret: "s", Active: true})
+	d, _ := New(Config{Repository: repo, MaxAttempts: 2, InitialBackoff: time.Minute})
+
+	if err := d.Enqueue(context.Background(), newTestEvent(t, "order.created")); err != nil {
+		t.Fatalf("Enqueue() error = %v", err)
+	}
+	delivery := repo.deliveries[0]
+
+	d.DeliverOnce(context.Background())
+	if delivery.Status != repository.DeliveryStatusPending || delivery.ResponseCode != http.StatusServiceUnavailable {
+		t.Fatalf("after first failure delivery = %+v, want pending with 503", delivery)
+	}
+	if wait := time.Until(delivery.NextAttemptAt); wait < 50*time.Second {
+		t.Errorf("next attempt in %v, want about one minute", wait)
+	}
+
+	// Make the retry due immediately
+	delivery.NextAttemptAt = time.Now()
+	d.DeliverOnce(context.Background())
+	if delivery.Status != repository.DeliveryStatusDead || delivery.Attempts != 2 {
+		t.Errorf("after max attempts delivery = %+v, want dead", delivery)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	d, _ := New(Config{Repository: newMockRepository(), InitialBackoff: time.Second, MaxBackoff: 10 * time.Second})
+
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{attempts: 1, want: time.Second},
+		{attempts: 2, want: 2 * time.Second},
+		{attempts: 4, want: 8 * time.Second},
+		{attempts: 5, want: 10 * time.Second},
+		{attempts: 100, want: 10 * time.Second},
+	}
+
+	for _, tt := range tests {
+		if got := d.backoff(tt.attempts); got != tt.want {
+			t.Errorf("backoff(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func TestVerifyRejectsStaleTimestamp(t *testing.T) {
+	body := []byte(`{"id":"1"}`)
+	old := time.Now().Add(-time.Hour).Unix()
+	sig := Sign("s", old, body)
+	ts := strconv.FormatInt(old, 10)
+
+	if err := Verify("s", sig, "not-a-number", body, 0); err == nil {
+		t.Error("Verify() accepted a malformed timestamp")
+	}
+	if err := Verify("s", sig, ts, body, 5*time.Minute); err == nil {
+		t.Error("Verify() accepted a stale timestamp")
+	}
+	if err := Verify("s", sig, ts, body, 0); err != nil {
+		t.Errorf("Verify() without tolerance error = %v", err)
+	}
+}
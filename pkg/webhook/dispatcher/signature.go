@@ -0,0 +1,67 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package dispatcher
+
+import (
+	"crypto/hmac"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/internal/signing"
+)
+
+// Headers set on every webhook request
+const (
+	HeaderSignature = "X-Webhook-Signature"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderEventType = "X-Webhook-Event"
+	HeaderDelivery  = "X-Webhook-Delivery"
+)
+
+// Sign computes the signature header value for a request body. The timestamp
+// is part of the signed content so receivers can reject replayed requests.
+func Sign(secret string, timestamp int64, body []byte) string {
+	return signing.Sign(secret, timestamp, body)
+}
+
+// Verify checks a signature produced by Sign. Requests whose timestamp is
+// further than tolerance from now are rejected; zero disables the check.
+func Verify(secret, signature, timestamp string, body []byte, tolerance time.Duration) error {
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return errors.WithCode(errors.New("invalid webhook timestamp"), errors.CodeUnauthorized)
+	}
+
+	if tolerance > 0 {
+		age := time.Since(time.Unix(ts, 0))
+		if age > tolerance || age < -tolerance {
+			return errors.WithCode(errors.New("webhook timestamp outside tolerance"), errors.CodeUnauthorized)
+		}
+	}
+
+	if !strings.HasPrefix(signature, signing.Prefix) {
+		return errors.WithCode(errors.New("unsupported webhook signature"), errors.CodeUnauthorized)
+	}
+
+	if !hmac.Equal([]byte(signature), []byte(Sign(secret, ts, body))) {
+		return errors.WithCode(errors.New("webhook signature mismatch"), errors.CodeUnauthorized)
+	}
+
+	return nil
+}
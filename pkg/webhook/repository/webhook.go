@@ -0,0 +1,409 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kevindiu/monorepo-go-example/internal/db"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/lib/pq"
+)
+
+// Delivery statuses
+const (
+	DeliveryStatusPending   = "pending"
+	DeliveryStatusSucceeded = "succeeded"
+	DeliveryStatusDead      = "dead"
+)
+
+// Endpoint represents a registered webhook receiver
+type Endpoint struct {
+	ID         string
+	URL        string
+	Secret     string
+	EventTypes []string
+	Active     bool
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// Matches reports whether the endpoint subscribes to eventType. An empty
+// filter or "*" matches everything and "order.*" matches any order event.
+func (e *Endpoint) Matches(eventType string) bool {
+	if len(e.EventTypes) == 0 {
+		return true
+	}
+	for _, filter := range e.EventTypes {
+		switch {
+		case filter == "*", filter == eventType:
+			return true
+		case strings.HasSuffix(filter, ".*") && strings.HasPrefix(eventType, strings.TrimSuffix(filter, "*")):
+			return true
+		}
+	}
+	return false
+}
+
+// Delivery represents an event queued for delivery to an endpoint
+type Delivery struct {
+	ID            string
+	EndpointID    string
+	EventID       string
+	EventType     string
+	Payload       []byte
+	Status        string
+	Attempts      int
+	ResponseCode  int
+	LastError     string
+	NextAttemptAt time.Time
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// DeliveryFilter narrows down ListDeliveries
+type DeliveryFilter struct {
+	EndpointID string
+	Status     string
+}
+
+// Repository defines the webhook repository interface
+type Repository interface {
+	CreateEndpoint(ctx context.Context, endpoint *Endpoint) error
+	GetEndpoint(ctx context.Context, id string) (*Endpoint, error)
+	ListEndpoints(ctx context.Context, limit, offset int) ([]*Endpoint, error)
+	CountEndpoints(ctx context.Context) (int64, bool, error)
+	ListActiveEndpoints(ctx context.Context) ([]*Endpoint, error)
+	DeleteEndpoint(ctx context.Context, id string) error
+
+	CreateDelivery(ctx context.Context, delivery *Delivery) error
+	GetDelivery(ctx context.Context, id string) (*Delivery, error)
+	ListDeliveries(ctx context.Context, filter DeliveryFilter, limit, offset int) ([]*Delivery, error)
+	CountDeliveries(ctx context.Context, filter DeliveryFilter) (int64, bool, error)
+	ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]*Delivery, error)
+	UpdateDelivery(ctx context.Context, delivery *Delivery) error
+}
+
+type repository struct {
+	db *db.DB
+}
+
+// New creates a new webhook repository
+func New(database *db.DB) Repository {
+	return &repository{
+		db: database,
+	}
+}
+
+const endpointColumns = `id, url, secret, event_types, active, created_at, updated_at`
+
+const deliveryColumns = `id, endpoint_id, event_id, event_type, payload, status, attempts,
+	response_code, COALESCE(last_error, ''), next_attempt_at, created_at, updated_at`
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanEndpoint(s scanner) (*Endpoint, error) {
+	var endpoint Endpoint
+	err := s.Scan(
+		&endpoint.ID,
+		&endpoint.URL,
+		&endpoint.Secret,
+		pq.Array(&endpoint.EventTypes),
+		&endpoint.Active,
+		&endpoint.CreatedAt,
+		&endpoint.UpdatedAt,
+	)
+	return &endpoint, err
+}
+
+func scanDelivery(s scanner) (*Delivery, error) {
+	var delivery Delivery
+	err := s.Scan(
+		&delivery.ID,
+		&delivery.EndpointID,
+		&delivery.EventID,
+		&delivery.EventType,
+		&delivery.Payload,
+		&delivery.Status,
+		&delivery.Attempts,
+		&delivery.ResponseCode,
+		&delivery.LastError,
+		&delivery.NextAttemptAt,
+		&delivery.CreatedAt,
+		&delivery.UpdatedAt,
+	)
+	return &delivery, err
+}
+
+// CreateEndpoint creates a new webhook endpoint
+func (r *repository) CreateEndpoint(ctx context.Context, endpoint *Endpoint) error {
+	query := `
+		INSERT INTO webhook_endpoints (id, url, secret, event_types, active, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	now := time.Now()
+	endpoint.ID = uuid.New().String()
+	endpoint.CreatedAt = now
+	endpoint.UpdatedAt = now
+
+	_, err := r.db.Executor(ctx).ExecContext(ctx, query,
+		endpoint.ID,
+		endpoint.URL,
+		endpoint.Secret,
+		pq.Array(endpoint.EventTypes),
+		endpoint.Active,
+		endpoint.CreatedAt,
+		endpoint.UpdatedAt,
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to create webhook endpoint")
+	}
+
+	return nil
+}
+
+// GetEndpoint retrieves a webhook endpoint by ID
+func (r *repository) GetEndpoint(ctx context.Context, id string) (*Endpoint, error) {
+	query := `SELECT ` + endpointColumns + ` FROM webhook_endpoints WHERE id = $1`
+
+	endpoint, err := scanEndpoint(r.db.Executor(ctx).QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, errors.WithCode(errors.New("webhook endpoint not found"), errors.CodeNotFound)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get webhook endpoint")
+	}
+
+	return endpoint, nil
+}
+
+// ListEndpoints retrieves webhook endpoints with pagination
+func (r *repository) ListEndpoints(ctx context.Context, limit, offset int) ([]*Endpoint, error) {
+	query := `
+		SELECT ` + endpointColumns + `
+		FROM webhook_endpoints
+		ORDER BY created_at DESC
+		LIMIT $1 OFFSET $2
+	`
+
+	return r.queryEndpoints(ctx, query, limit, offset)
+}
+
+// CountEndpoints returns the number of webhook endpoints and whether the
+// number is exact rather than estimated (see db.Count)
+func (r *repository) CountEndpoints(ctx context.Context) (int64, bool, error) {
+	return r.db.Count(ctx, "webhook_endpoints")
+}
+
+// ListActiveEndpoints retrieves every active webhook endpoint
+func (r *repository) ListActiveEndpoints(ctx context.Context) ([]*Endpoint, error) {
+	query := `SELECT ` + endpointColumns + ` FROM webhook_endpoints WHERE active = TRUE`
+
+	return r.queryEndpoints(ctx, query)
+}
+
+func (r *repository) queryEndpoints(ctx context.Context, query string, args ...interface{}) ([]*Endpoint, error) {
+	rows, err := r.db.Executor(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list webhook endpoints")
+	}
+	defer rows.Close()
+
+	var endpoints []*Endpoint
+	for rows.Next() {
+		endpoint, err := scanEndpoint(rows)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to scan webhook endpoint")
+		}
+		endpoints = append(endpoints, endpoint)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "error iterating webhook endpoints")
+	}
+
+	return endpoints, nil
+}
+
+// DeleteEndpoint deletes a webhook endpoint and its delivery log
+func (r *repository) DeleteEndpoint(ctx context.Context, id string) error {
+	result, err := r.db.Executor(ctx).ExecContext(ctx, `DELETE FROM webhook_endpoints WHERE id = $1`, id)
+	if err != nil {
+		return errors.Wrap(err, "failed to delete webhook endpoint")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "failed to get rows affected")
+	}
+
+	if rowsAffected == 0 {
+		return errors.WithCode(errors.New("webhook endpoint not found"), errors.CodeNotFound)
+	}
+
+	return nil
+}
+
+// CreateDelivery queues a delivery. Events are delivered to the bus
+// at-least-once, so a duplicate (endpoint, event) pair is ignored.
+func (r *repository) CreateDelivery(ctx context.Context, delivery *Delivery) error {
+	query := `
+		INSERT INTO webhook_deliveries (id, endpoint_id, event_id, event_type, payload, status, next_attempt_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $7, $7)
+		ON CONFLICT (endpoint_id, event_id) DO NOTHING
+	`
+
+	now := time.Now()
+	delivery.ID = uuid.New().String()
+	delivery.Status = DeliveryStatusPending
+	delivery.NextAttemptAt = now
+	delivery.CreatedAt = now
+	delivery.UpdatedAt = now
+
+	_, err := r.db.Executor(ctx).ExecContext(ctx, query,
+		delivery.ID,
+		delivery.EndpointID,
+		delivery.EventID,
+		delivery.EventType,
+		delivery.Payload,
+		delivery.Status,
+		now,
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to create webhook delivery")
+	}
+
+	return nil
+}
+
+// GetDelivery retrieves a delivery by ID
+func (r *repository) GetDelivery(ctx context.Context, id string) (*Delivery, error) {
+	query := `SELECT ` + deliveryColumns + ` FROM webhook_deliveries WHERE id = $1`
+
+	delivery, err := scanDelivery(r.db.Executor(ctx).QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, errors.WithCode(errors.New("webhook delivery not found"), errors.CodeNotFound)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get webhook delivery")
+	}
+
+	return delivery, nil
+}
+
+// ListDeliveries retrieves the delivery log, newest first
+func (r *repository) ListDeliveries(ctx context.Context, filter DeliveryFilter, limit, offset int) ([]*Delivery, error) {
+	query := `
+		SELECT ` + deliveryColumns + `
+		FROM webhook_deliveries
+		WHERE ($1 = '' OR endpoint_id::text = $1)
+		  AND ($2 = '' OR status = $2)
+		ORDER BY created_at DESC
+		LIMIT $3 OFFSET $4
+	`
+
+	return r.queryDeliveries(ctx, query, filter.EndpointID, filter.Status, limit, offset)
+}
+
+// CountDeliveries returns the number of deliveries matching filter and
+// whether the number is exact rather than estimated (see db.Count)
+func (r *repository) CountDeliveries(ctx context.Context, filter DeliveryFilter) (int64, bool, error) {
+	return r.db.Count(ctx, "webhook_deliveries WHERE ($1 = '' OR endpoint_id::text = $1) AND ($2 = '' OR status = $2)",
+		filter.EndpointID, filter.Status)
+}
+
+// ClaimDueDeliveries leases up to limit pending deliveries whose next attempt
+// is due. The lease pushes next_attempt_at forward so that other dispatchers
+// skip the rows while they are being sent.
+func (r *repository) ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]*Delivery, error) {
+	query := `
+		UPDATE webhook_deliveries
+		SET next_attempt_at = $1
+		WHERE id IN (
+			SELECT id FROM webhook_deliveries
+			WHERE status = 'pending' AND next_attempt_at <= $2
+			ORDER BY next_attempt_at
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + deliveryColumns
+
+	now := time.Now()
+	return r.queryDeliveries(ctx, query, now.Add(lease), now, limit)
+}
+
+func (r *repository) queryDeliveries(ctx context.Context, query string, args ...interface{}) ([]*Delivery, error) {
+	rows, err := r.db.Executor(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list webhook deliveries")
+	}
+	defer rows.Close()
+
+	var deliveries []*Delivery
+	for rows.Next() {
+		delivery, err := scanDelivery(rows)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to scan webhook delivery")
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "error iterating webhook deliveries")
+	}
+
+	return deliveries, nil
+}
+
+// UpdateDelivery records the outcome of a delivery attempt
+func (r *repository) UpdateDelivery(ctx context.Context, delivery *Delivery) error {
+	query := `
+		UPDATE webhook_deliveries
+		SET status = $1, attempts = $2, response_code = $3, last_error = NULLIF($4, ''), next_attempt_at = $5
+		WHERE id = $6
+	`
+
+	result, err := r.db.Executor(ctx).ExecContext(ctx, query,
+		delivery.Status,
+		delivery.Attempts,
+		delivery.ResponseCode,
+		delivery.LastError,
+		delivery.NextAttemptAt,
+		delivery.ID,
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to update webhook delivery")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "failed to get rows affected")
+	}
+
+	if rowsAffected == 0 {
+		return errors.WithCode(errors.New("webhook delivery not found"), errors.CodeNotFound)
+	}
+
+	return nil
+}
@@ -0,0 +1,45 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package repository
+
+import "testing"
+
+func TestEndpointMatches(t *testing.T) {
+	tests := []struct {
+		name       string
+		eventTypes []string
+		eventType  string
+		want       bool
+	}{
+		{name: "empty filter", eventType: "order.created", want: true},
+		{name: "wildcard", eventTypes: []string{"*"}, eventType: "user.deleted", want: true},
+		{name: "exact", eventTypes: []string{"order.created"}, eventType: "order.created", want: true},
+		{name: "exact mismatch", eventTypes: []string{"order.created"}, eventType: "order.cancelled", want: false},
+		{name: "prefix", eventTypes: []string{"order.*"}, eventType: "order.cancelled", want: true},
+		{name: "prefix mismatch", eventTypes: []string{"order.*"}, eventType: "orders.created", want: false},
+		{name: "any of several", eventTypes: []string{"user.created", "order.*"}, eventType: "order.shipped", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := &Endpoint{EventTypes: tt.eventTypes}
+			if got := e.Matches(tt.eventType); got != tt.want {
+				t.Errorf("Matches(%q) = %v, want %v", tt.eventType, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,343 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/url"
+	"strconv"
+	"time"
+
+	webhookv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/webhook/v1"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/internal/log"
+	"github.com/kevindiu/monorepo-go-example/pkg/webhook/repository"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// secretPrefix marks generated signing secrets
+const secretPrefix = "whsec_"
+
+// statusToProto converts string status to proto enum
+func statusToProto(status string) webhookv1.DeliveryStatus {
+	switch status {
+	case repository.DeliveryStatusPending:
+		return webhookv1.DeliveryStatus_DELIVERY_STATUS_PENDING
+	case repository.DeliveryStatusSucceeded:
+		return webhookv1.DeliveryStatus_DELIVERY_STATUS_SUCCEEDED
+	case repository.DeliveryStatusDead:
+		return webhookv1.DeliveryStatus_DELIVERY_STATUS_DEAD
+	default:
+		return webhookv1.DeliveryStatus_DELIVERY_STATUS_UNSPECIFIED
+	}
+}
+
+// statusFromProto converts proto enum to string status; unspecified maps to
+// an empty filter
+func statusFromProto(status webhookv1.DeliveryStatus) string {
+	switch status {
+	case webhookv1.DeliveryStatus_DELIVERY_STATUS_PENDING:
+		return repository.DeliveryStatusPending
+	case webhookv1.DeliveryStatus_DELIVERY_STATUS_SUCCEEDED:
+		return repository.DeliveryStatusSucceeded
+	case webhookv1.DeliveryStatus_DELIVERY_STATUS_DEAD:
+		return repository.DeliveryStatusDead
+	default:
+		return ""
+	}
+}
+
+// Service defines the webhook service interface
+type Service interface {
+	webhookv1.WebhookServiceServer
+}
+
+type service struct {
+	webhookv1.UnimplementedWebhookServiceServer
+	repo   repository.Repository
+	logger *log.Logger
+}
+
+// New creates a new webhook service
+func New(repo repository.Repository, logger *log.Logger) Service {
+	return &service{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// loggerFor returns the request-scoped logger of ctx, falling back to the
+// service's logger
+func (s *service) loggerFor(ctx context.Context) *log.Logger {
+	return log.FromContextOr(ctx, s.logger)
+}
+
+// CreateEndpoint registers a new webhook endpoint
+func (s *service) CreateEndpoint(ctx context.Context, req *webhookv1.CreateEndpointRequest) (*webhookv1.CreateEndpointResponse, error) {
+	s.loggerFor(ctx).Info("Creating webhook endpoint", log.String("url", req.GetUrl()))
+
+	if err := validateURL(req.GetUrl()); err != nil {
+		return nil, err
+	}
+
+	secret := req.GetSecret()
+	if secret == "" {
+		var err error
+		if secret, err = generateSecret(); err != nil {
+			return nil, err
+		}
+	}
+
+	endpoint := &repository.Endpoint{
+		URL:        req.GetUrl(),
+		Secret:     secret,
+		EventTypes: req.GetEventTypes(),
+		Active:     true,
+	}
+
+	if err := s.repo.CreateEndpoint(ctx, endpoint); err != nil {
+		s.loggerFor(ctx).Error("Failed to create webhook endpoint", log.Error(err))
+		return nil, err
+	}
+
+	s.loggerFor(ctx).Info("Webhook endpoint created successfully", log.String("endpoint_id", endpoint.ID))
+
+	// The secret is only ever returned here
+	pb := endpointToProto(endpoint)
+	pb.Secret = endpoint.Secret
+
+	return &webhookv1.CreateEndpointResponse{Endpoint: pb}, nil
+}
+
+// GetEndpoint retrieves a webhook endpoint by ID
+func (s *service) GetEndpoint(ctx context.Context, req *webhookv1.GetEndpointRequest) (*webhookv1.GetEndpointResponse, error) {
+	if req.GetId() == "" {
+		return nil, errors.WithCode(errors.New("id is required"), errors.CodeInvalidInput)
+	}
+
+	endpoint, err := s.repo.GetEndpoint(ctx, req.GetId())
+	if err != nil {
+		s.loggerFor(ctx).Error("Failed to get webhook endpoint", log.Error(err))
+		return nil, err
+	}
+
+	return &webhookv1.GetEndpointResponse{Endpoint: endpointToProto(endpoint)}, nil
+}
+
+// ListEndpoints lists webhook endpoints with pagination
+func (s *service) ListEndpoints(ctx context.Context, req *webhookv1.ListEndpointsRequest) (*webhookv1.ListEndpointsResponse, error) {
+	pageSize, offset := pagination(req.GetPageSize(), req.GetPageToken())
+
+	endpoints, err := s.repo.ListEndpoints(ctx, pageSize+1, offset)
+	if err != nil {
+		s.loggerFor(ctx).Error("Failed to list webhook endpoints", log.Error(err))
+		return nil, err
+	}
+	hasMore := len(endpoints) > pageSize
+	if hasMore {
+		endpoints = endpoints[:pageSize]
+	}
+
+	pbEndpoints := make([]*webhookv1.Endpoint, len(endpoints))
+	for i, endpoint := range endpoints {
+		pbEndpoints[i] = endpointToProto(endpoint)
+	}
+
+	resp := &webhookv1.ListEndpointsResponse{
+		Endpoints:     pbEndpoints,
+		NextPageToken: nextPageToken(hasMore, pageSize, offset),
+		HasMore:       hasMore,
+	}
+	if req.GetIncludeTotal() {
+		resp.TotalSize, resp.TotalSizeExact, err = s.repo.CountEndpoints(ctx)
+		if err != nil {
+			s.loggerFor(ctx).Error("Failed to count webhook endpoints", log.Error(err))
+			return nil, err
+		}
+	}
+
+	return resp, nil
+}
+
+// DeleteEndpoint removes a webhook endpoint
+func (s *service) DeleteEndpoint(ctx context.Context, req *webhookv1.DeleteEndpointRequest) (*webhookv1.DeleteEndpointResponse, error) {
+	s.loggerFor(ctx).Info("Deleting webhook endpoint", log.String("endpoint_id", req.GetId()))
+
+	if req.GetId() == "" {
+		return nil, errors.WithCode(errors.New("id is required"), errors.CodeInvalidInput)
+	}
+
+	if err := s.repo.DeleteEndpoint(ctx, req.GetId()); err != nil {
+		s.loggerFor(ctx).Error("Failed to delete webhook endpoint", log.Error(err))
+		return nil, err
+	}
+
+	return &webhookv1.DeleteEndpointResponse{Success: true}, nil
+}
+
+// ListDeliveries lists the delivery log, newest first
+func (s *service) ListDeliveries(ctx context.Context, req *webhookv1.ListDeliveriesRequest) (*webhookv1.ListDeliveriesResponse, error) {
+	pageSize, offset := pagination(req.GetPageSize(), req.GetPageToken())
+
+	filter := repository.DeliveryFilter{
+		EndpointID: req.GetEndpointId(),
+		Status:     statusFromProto(req.GetStatus()),
+	}
+
+	deliveries, err := s.repo.ListDeliveries(ctx, filter, pageSize+1, offset)
+	if err != nil {
+		s.loggerFor(ctx).Error("Failed to list webhook deliveries", log.Error(err))
+		return nil, err
+	}
+	hasMore := len(deliveries) > pageSize
+	if hasMore {
+		deliveries = deliveries[:pageSize]
+	}
+
+	pbDeliveries := make([]*webhookv1.Delivery, len(deliveries))
+	for i, delivery := range deliveries {
+		pbDeliveries[i] = deliveryToProto(delivery)
+	}
+
+	resp := &webhookv1.ListDeliveriesResponse{
+		Deliveries:    pbDeliveries,
+		NextPageToken: nextPageToken(hasMore, pageSize, offset),
+		HasMore:       hasMore,
+	}
+	if req.GetIncludeTotal() {
+		resp.TotalSize, resp.TotalSizeExact, err = s.repo.CountDeliveries(ctx, filter)
+		if err != nil {
+			s.loggerFor(ctx).Error("Failed to count webhook deliveries", log.Error(err))
+			return nil, err
+		}
+	}
+
+	return resp, nil
+}
+
+// Redeliver resets a delivery so the dispatcher attempts it again
+func (s *service) Redeliver(ctx context.Context, req *webhookv1.RedeliverRequest) (*webhookv1.RedeliverResponse, error) {
+	s.loggerFor(ctx).Info("Redelivering webhook", log.String("delivery_id", req.GetId()))
+
+	if req.GetId() == "" {
+		return nil, errors.WithCode(errors.New("id is required"), errors.CodeInvalidInput)
+	}
+
+	delivery, err := s.repo.GetDelivery(ctx, req.GetId())
+	if err != nil {
+		s.loggerFor(ctx).Error("Failed to get webhook delivery", log.Error(err))
+		return nil, err
+	}
+
+	if delivery.Status == repository.DeliveryStatusPending {
+		return nil, errors.WithCode(errors.New("delivery is already pending"), errors.CodeConflict)
+	}
+
+	// A redelivery gets a fresh set of attempts
+	delivery.Status = repository.DeliveryStatusPending
+	delivery.Attempts = 0
+	delivery.NextAttemptAt = time.Now()
+
+	if err := s.repo.UpdateDelivery(ctx, delivery); err != nil {
+		s.loggerFor(ctx).Error("Failed to reschedule webhook delivery", log.Error(err))
+		return nil, err
+	}
+
+	return &webhookv1.RedeliverResponse{Delivery: deliveryToProto(delivery)}, nil
+}
+
+// validateURL checks that a webhook URL is an absolute http(s) URL
+func validateURL(raw string) error {
+	if raw == "" {
+		return errors.WithCode(errors.New("url is required"), errors.CodeInvalidInput)
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return errors.WithCode(errors.New("url must be an absolute http or https URL"), errors.CodeInvalidInput)
+	}
+
+	return nil
+}
+
+// generateSecret returns a random signing secret
+func generateSecret() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", errors.Wrap(err, "failed to generate webhook secret")
+	}
+	return secretPrefix + hex.EncodeToString(b), nil
+}
+
+// pagination normalizes the page size and decodes the offset page token
+func pagination(size int32, token string) (int, int) {
+	pageSize := int(size)
+	if pageSize <= 0 {
+		pageSize = 10
+	}
+	if pageSize > 100 {
+		pageSize = 100
+	}
+
+	offset := 0
+	if token != "" {
+		parsedOffset, err := strconv.Atoi(token)
+		if err == nil && parsedOffset > 0 {
+			offset = parsedOffset
+		}
+	}
+
+	return pageSize, offset
+}
+
+// nextPageToken encodes the offset of the following page, if there is one.
+// Callers fetch one row beyond the page to learn hasMore.
+func nextPageToken(hasMore bool, pageSize, offset int) string {
+	if hasMore {
+		return strconv.Itoa(offset + pageSize)
+	}
+	return ""
+}
+
+func endpointToProto(endpoint *repository.Endpoint) *webhookv1.Endpoint {
+	return &webhookv1.Endpoint{
+		Id:         endpoint.ID,
+		Url:        endpoint.URL,
+		EventTypes: endpoint.EventTypes,
+		Active:     endpoint.Active,
+		CreatedAt:  timestamppb.New(endpoint.CreatedAt),
+		UpdatedAt:  timestamppb.New(endpoint.UpdatedAt),
+	}
+}
+
+func deliveryToProto(delivery *repository.Delivery) *webhookv1.Delivery {
+	return &webhookv1.Delivery{
+		Id:            delivery.ID,
+		EndpointId:    delivery.EndpointID,
+		EventId:       delivery.EventID,
+		EventType:     delivery.EventType,
+		Status:        statusToProto(delivery.Status),
+		Attempts:      int32(delivery.Attempts),
+		ResponseCode:  int32(delivery.ResponseCode),
+		LastError:     delivery.LastError,
+		NextAttemptAt: timestamppb.New(delivery.NextAttemptAt),
+		CreatedAt:     timestamppb.New(delivery.CreatedAt),
+		UpdatedAt:     timestamppb.New(delivery.UpdatedAt),
+	}
+}
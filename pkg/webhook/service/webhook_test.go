@@ -0,0 +1,281 @@
+//
+// Copyright (C) 2025 Kevin Diu <kevindiujp@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package service
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	webhookv1 "github.com/kevindiu/monorepo-go-example/apis/grpc/apis/proto/webhook/v1"
+	"github.com/kevindiu/monorepo-go-example/internal/errors"
+	"github.com/kevindiu/monorepo-go-example/internal/log"
+	"github.com/kevindiu/monorepo-go-example/pkg/webhook/repository"
+)
+
+// mockRepository implements repository.Repository for testing
+type mockRepository struct {
+	endpoints  map[string]*repository.Endpoint
+	deliveries map[string]*repository.Delivery
+}
+
+func newMockRepository() *mockRepository {
+	return &mockRepository{
+		endpoints:  make(map[string]*repository.Endpoint),
+		deliveries: make(map[string]*repository.Delivery),
+	}
+}
+
+func (m *mockRepository) CreateEndpoint(ctx context.Context, endpoint *repository.Endpoint) error {
+	endpoint.ID = "endpoint-1"
+	m.endpoints[endpoint.ID] = endpoint
+	return nil
+}
+
+func (m *mockRepository) GetEndpoint(ctx context.Context, id string) (*repository.Endpoint, error) {
+	endpoint, ok := m.endpoints[id]
+	if !ok {
+		return nil, errors.WithCode(errors.New("webhook endpoint not found"), errors.CodeNotFound)
+	}
+	return endpoint, nil
+}
+
+func (m *mockRepository) ListEndpoints(ctx context.Context, limit, offset int) ([]*repository.Endpoint, error) {
+	endpoints := []*repository.Endpoint{}
+	for _, endpoint := range m.endpoints {
+		endpoints = append(endpoints, endpoint)
+	}
+	return endpoints, nil
+}
+
+func (m *mockRepository) CountEndpoints(ctx context.Context) (int64, bool, error) {
+	return int64(len(m.endpoints)), true, nil
+}
+
+func (m *mockRepository) ListActiveEndpoints(ctx context.Context) ([]*repository.Endpoint, error) {
+	return m.ListEndpoints(ctx, 0, 0)
+}
+
+func (m *mockRepository) DeleteEndpoint(ctx context.Context, id string) error {
+	if _, ok := m.endpoints[id]; !ok {
+		return errors.WithCode(errors.New("webhook endpoint not found"), errors.CodeNotFound)
+	}
+	delete(m.endpoints, id)
+	return nil
+}
+
+func (m *mockRepository) CreateDelivery(ctx context.Context, delivery *repository.Delivery) error {
+	m.deliveries[delivery.ID] = delivery
+	return nil
+}
+
+func (m *mockRepository) GetDelivery(ctx context.Context, id string) (*repository.Delivery, error) {
+	delivery, ok := m.deliveries[id]
+	if !ok {
+		return nil, errors.WithCode(errors.New("webhook delivery not found"), errors.CodeNotFound)
+	}
+	return delivery, nil
+}
+
+func (m *mockRepository) ListDeliveries(ctx context.Context, filter repository.DeliveryFilter, limit, offset int) ([]*repository.Delivery, error) {
+	deliveries := m.filterDeliveries(filter)
+	if offset >= len(deliveries) {
+		return nil, nil
+	}
+	deliveries = deliveries[offset:]
+	if len(deliveries) > limit {
+		deliveries = deliveries[:limit]
+	}
+	return deliveries, nil
+}
+
+func (m *mockRepository) CountDeliveries(ctx context.Context, filter repository.DeliveryFilter) (int64, bool, error) {
+	return int64(len(m.filterDeliveries(filter))), true, nil
+}
+
+// filterDeliveries returns the deliveries matching filter sorted by ID
+func (m *mockRepository) filterDeliveries(filter repository.DeliveryFilter) []*repository.Delivery {
+	deliveries := []*repository.Delivery{}
+	for _, delivery := range m.deliveries {
+		if filter.Status == "" || delivery.Status == filter.Status {
+			deliveries = append(deliveries, delivery)
+		}
+	}
+	sort.Slice(deliveries, func(i, j int) bool { return deliveries[i].ID < deliveries[j].ID })
+	return deliveries
+}
+
+func (m *mockRepository) ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]*repository.Delivery, error) {
+	return nil, nil
+}
+
+func (m *mockRepository) UpdateDelivery(ctx context.Context, delivery *repository.Delivery) error {
+	m.deliveries[delivery.ID] = delivery
+	return nil
+}
+
+func TestCreateEndpoint(t *testing.T) {
+	svc := New(newMockRepository(), log.NewDefault())
+
+	tests := []struct {
+		name    string
+		req     *webhookv1.CreateEndpointRequest
+		wantErr bool
+	}{
+		{
+			name: "valid request",
+			req: &webhookv1.CreateEndpointRequest{
+				Url:        "https://example.com/hooks",
+				EventTypes: []string{"order.*"},
+			},
+		},
+		{
+			name:    "missing url",
+			req:     &webhookv1.CreateEndpointRequest{},
+			wantErr: true,
+		},
+		{
+			name:    "relative url",
+			req:     &webhookv1.CreateEndpointRequest{Url: "/hooks"},
+			wantErr: true,
+		},
+		{
+			name:    "unsupported scheme",
+			req:     &webhookv1.CreateEndpointRequest{Url: "ftp://example.com/hooks"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := svc.CreateEndpoint(context.Background(), tt.req)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CreateEndpoint() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !strings.HasPrefix(resp.GetEndpoint().GetSecret(), secretPrefix) {
+				t.Errorf("CreateEndpoint() secret = %q, want a generated secret", resp.GetEndpoint().GetSecret())
+			}
+		})
+	}
+}
+
+func TestGetEndpointHidesSecret(t *testing.T) {
+	repo := newMockRepository()
+	repo.endpoints["e1"] = &repository.Endpoint{ID: "e1", URL: "https://example.com", Secret: "s3cret", Active: true}
+	svc := New(repo, log.NewDefault())
+
+	resp, err := svc.GetEndpoint(context.Background(), &webhookv1.GetEndpointRequest{Id: "e1"})
+	if err != nil {
+		t.Fatalf("GetEndpoint() error = %v", err)
+	}
+	if resp.GetEndpoint().GetSecret() != "" {
+		t.Error("GetEndpoint() leaked the endpoint secret")
+	}
+}
+
+func TestRedeliver(t *testing.T) {
+	repo := newMockRepository()
+	repo.deliveries["dead"] = &repository.Delivery{ID: "dead", Status: repository.DeliveryStatusDead, Attempts: 8}
+	repo.deliveries["pending"] = &repository.Delivery{ID: "pending", Status: repository.DeliveryStatusPending}
+	svc := New(repo, log.NewDefault())
+
+	tests := []struct {
+		name    string
+		id      string
+		wantErr bool
+	}{
+		{name: "dead delivery", id: "dead"},
+		{name: "already pending", id: "pending", wantErr: true},
+		{name: "not found", id: "missing", wantErr: true},
+		{name: "missing id", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := svc.Redeliver(context.Background(), &webhookv1.RedeliverRequest{Id: tt.id})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Redeliver() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if resp.GetDelivery().GetStatus() != webhookv1.DeliveryStatus_DELIVERY_STATUS_PENDING || resp.GetDelivery().GetAttempts() != 0 {
+				t.Errorf("Redeliver() delivery = %v, want pending with no attempts", resp.GetDelivery())
+			}
+		})
+	}
+}
+
+func TestListDeliveriesPagination(t *testing.T) {
+	repo := newMockRepository()
+	for _, id := range []string{"a", "b", "c", "d", "e"} {
+		repo.deliveries[id] = &repository.Delivery{ID: id, Status: repository.DeliveryStatusSucceeded}
+	}
+	svc := New(repo, log.NewDefault())
+
+	tests := []struct {
+		name          string
+		req           *webhookv1.ListDeliveriesRequest
+		wantCount     int
+		wantHasMore   bool
+		wantNextToken string
+		wantTotal     int64
+	}{
+		{
+			name:          "first page",
+			req:           &webhookv1.ListDeliveriesRequest{PageSize: 2},
+			wantCount:     2,
+			wantHasMore:   true,
+			wantNextToken: "2",
+		},
+		{
+			name:      "exact last page",
+			req:       &webhookv1.ListDeliveriesRequest{PageSize: 2, PageToken: "3", IncludeTotal: true},
+			wantCount: 2,
+			wantTotal: 5,
+		},
+		{
+			name:      "page size equals total",
+			req:       &webhookv1.ListDeliveriesRequest{PageSize: 5},
+			wantCount: 5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := svc.ListDeliveries(context.Background(), tt.req)
+			if err != nil {
+				t.Fatalf("ListDeliveries() error = %v", err)
+			}
+			if len(resp.GetDeliveries()) != tt.wantCount {
+				t.Errorf("ListDeliveries() returned %d deliveries, want %d", len(resp.GetDeliveries()), tt.wantCount)
+			}
+			if resp.GetHasMore() != tt.wantHasMore || resp.GetNextPageToken() != tt.wantNextToken {
+				t.Errorf("ListDeliveries() has_more = %v, next_page_token = %q, want %v, %q",
+					resp.GetHasMore(), resp.GetNextPageToken(), tt.wantHasMore, tt.wantNextToken)
+			}
+			if resp.GetTotalSize() != tt.wantTotal {
+				t.Errorf("ListDeliveries() total_size = %d, want %d", resp.GetTotalSize(), tt.wantTotal)
+			}
+			if tt.req.GetIncludeTotal() && !resp.GetTotalSizeExact() {
+				t.Error("ListDeliveries() total_size_exact = false, want true")
+			}
+		})
+	}
+}